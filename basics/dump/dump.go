@@ -0,0 +1,198 @@
+package dump
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A small reflection based pretty-printer for example values.
+//
+// Printing a map with %v shows keys in sorted order, but everything is on one line and
+// nested structs lose their field names and types. Sprint instead writes one field per line,
+// with the type of every value, and map keys in a fixed (sorted) order, so the same value
+// always produces exactly the same text.
+//
+//	map[string]main.User{
+//		"1d02455e-...": main.User{
+//			UserId: string("1d02455e-..."),
+//			Name: string("John Doe"),
+//		},
+//	}
+
+const indent = "\t"
+
+// Sprint returns the multi-line representation of v.
+func Sprint(v any) string {
+	var sb strings.Builder
+	d := dumper{w: &sb, seen: make(map[uintptr]bool)}
+	d.value(reflect.ValueOf(v), 0)
+	return sb.String()
+}
+
+// Fprint writes the representation of v to w, followed by a newline.
+func Fprint(w io.Writer, v any) error {
+	_, err := fmt.Fprintln(w, Sprint(v))
+	return err
+}
+
+// Print writes the representation of v to standard output.
+func Print(v any) {
+	Fprint(os.Stdout, v)
+}
+
+type dumper struct {
+	w io.StringWriter
+	// pointers currently being printed, so self referencing values (ex. a cyclic linked list)
+	// print <cycle> instead of recursing forever
+	seen map[uintptr]bool
+}
+
+func (d *dumper) write(s ...string) {
+	for _, part := range s {
+		d.w.WriteString(part)
+	}
+}
+
+func (d *dumper) newline(depth int) {
+	d.write("\n", strings.Repeat(indent, depth))
+}
+
+func (d *dumper) value(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		d.write("nil")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			d.write("(", v.Type().String(), ")(nil)")
+			return
+		}
+		if d.seen[v.Pointer()] {
+			d.write("&", v.Type().Elem().String(), "{<cycle>}")
+			return
+		}
+		d.seen[v.Pointer()] = true
+		d.write("&")
+		d.value(v.Elem(), depth)
+		delete(d.seen, v.Pointer())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			d.write(v.Type().String(), "(nil)")
+			return
+		}
+		d.value(v.Elem(), depth)
+
+	case reflect.Struct:
+		d.write(v.Type().String(), "{")
+		if v.NumField() == 0 {
+			d.write("}")
+			return
+		}
+		for i := range v.NumField() {
+			d.newline(depth + 1)
+			d.write(v.Type().Field(i).Name, ": ")
+			d.value(v.Field(i), depth+1)
+			d.write(",")
+		}
+		d.newline(depth)
+		d.write("}")
+
+	case reflect.Map:
+		if v.IsNil() {
+			d.write(v.Type().String(), "(nil)")
+			return
+		}
+		d.write(v.Type().String(), "{")
+		if v.Len() == 0 {
+			d.write("}")
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return lessValue(keys[i], keys[j]) })
+		for _, k := range keys {
+			d.newline(depth + 1)
+			d.write(scalar(k), ": ")
+			d.value(v.MapIndex(k), depth+1)
+			d.write(",")
+		}
+		d.newline(depth)
+		d.write("}")
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			d.write(v.Type().String(), "(nil)")
+			return
+		}
+		d.write(v.Type().String(), "{")
+		if v.Len() == 0 {
+			d.write("}")
+			return
+		}
+		for i := range v.Len() {
+			d.newline(depth + 1)
+			d.value(v.Index(i), depth+1)
+			d.write(",")
+		}
+		d.newline(depth)
+		d.write("}")
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		// the address changes between runs, so only print whether it is set
+		if v.IsNil() {
+			d.write(v.Type().String(), "(nil)")
+		} else {
+			d.write(v.Type().String(), "(...)")
+		}
+
+	default:
+		d.write(v.Type().String(), "(", scalar(v), ")")
+	}
+}
+
+// scalar formats basic kinds without calling v.Interface(),
+// which panics for values read from unexported struct fields
+func scalar(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits())
+	default:
+		// composite map keys (ex. struct keys) are printed on one line
+		var sb strings.Builder
+		d := dumper{w: &sb, seen: make(map[uintptr]bool)}
+		d.value(v, 0)
+		return strings.Join(strings.Fields(sb.String()), " ")
+	}
+}
+
+// lessValue orders map keys: numbers numerically, everything else by its printed form
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	}
+	return scalar(a) < scalar(b)
+}
@@ -0,0 +1,103 @@
+package dump
+
+import (
+	"strings"
+	"testing"
+)
+
+type point struct {
+	X, Y int
+}
+
+type user struct {
+	Name  string
+	Tags  []string
+	Home  *point
+	notes map[string]int // unexported, still printed
+}
+
+type node struct {
+	Val  int
+	Next *node
+}
+
+func TestSprint(t *testing.T) {
+	var nilMap map[string]int
+	for _, c := range []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"nil", nil, "nil"},
+		{"int", 42, "int(42)"},
+		{"string", "hi\n", `string("hi\n")`},
+		{"float32", float32(0.1), "float32(0.1)"},
+		{"nil map", nilMap, "map[string]int(nil)"},
+		{"empty slice", []int{}, "[]int{}"},
+		{"nil pointer", (*point)(nil), "(*dump.point)(nil)"},
+		{"func", strings.ToUpper, "func(string) string(...)"},
+		{"struct", point{1, 2}, "dump.point{\n\tX: int(1),\n\tY: int(2),\n}"},
+		{"empty struct", struct{}{}, "struct {}{}"},
+		{"nested", user{Name: "ann", Tags: []string{"a"}, Home: &point{3, 4}, notes: map[string]int{"b": 2, "a": 1}}, `dump.user{
+	Name: string("ann"),
+	Tags: []string{
+		string("a"),
+	},
+	Home: &dump.point{
+		X: int(3),
+		Y: int(4),
+	},
+	notes: map[string]int{
+		"a": int(1),
+		"b": int(2),
+	},
+}`},
+		{"interface slice", []any{1, "x", nil}, "[]interface {}{\n\tint(1),\n\tstring(\"x\"),\n\tinterface {}(nil),\n}"},
+	} {
+		if got := Sprint(c.v); got != c.want {
+			t.Errorf("%s: Sprint =\n%s\nwant\n%s", c.name, got, c.want)
+		}
+	}
+}
+
+// Map keys come out sorted - numbers by value, not by their text - so output never changes between runs
+func TestSprintMapKeysSorted(t *testing.T) {
+	m := map[int]bool{10: true, 9: false, -1: true, 100: false}
+	want := "map[int]bool{\n\t-1: bool(true),\n\t9: bool(false),\n\t10: bool(true),\n\t100: bool(false),\n}"
+	for range 20 { // map iteration order is random, so one pass could pass by luck
+		if got := Sprint(m); got != want {
+			t.Fatalf("Sprint =\n%s\nwant\n%s", got, want)
+		}
+	}
+
+	structKeys := map[point]string{{2, 0}: "b", {1, 5}: "a"}
+	want = "map[dump.point]string{\n\tdump.point{ X: int(1), Y: int(5), }: string(\"a\"),\n\tdump.point{ X: int(2), Y: int(0), }: string(\"b\"),\n}"
+	if got := Sprint(structKeys); got != want {
+		t.Errorf("Sprint =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSprintCycle(t *testing.T) {
+	n := &node{Val: 1}
+	n.Next = &node{Val: 2, Next: n}
+	want := "&dump.node{\n\tVal: int(1),\n\tNext: &dump.node{\n\t\tVal: int(2),\n\t\tNext: &dump.node{<cycle>},\n\t},\n}"
+	if got := Sprint(n); got != want {
+		t.Errorf("Sprint =\n%s\nwant\n%s", got, want)
+	}
+
+	// the same pointer twice, side by side, is not a cycle
+	p := &point{1, 1}
+	if got := Sprint([]*point{p, p}); strings.Contains(got, "<cycle>") {
+		t.Errorf("Sprint of a repeated (not cyclic) pointer =\n%s", got)
+	}
+}
+
+func TestFprint(t *testing.T) {
+	var sb strings.Builder
+	if err := Fprint(&sb, []int{1}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[]int{\n\tint(1),\n}\n"; sb.String() != want {
+		t.Errorf("Fprint wrote %q, want %q", sb.String(), want)
+	}
+}
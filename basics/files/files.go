@@ -0,0 +1,201 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Files on disk with os and path/filepath, and a FileStore that keeps uploads in a directory.
+//
+// - os.ReadFile / os.WriteFile: a whole file at once - fine for small files
+// - os.Open (read) / os.Create (write, truncating) / os.OpenFile (flags and permissions): a *os.File to stream
+//   through, closed with defer - and for writes, Close's error is checked (errorsdeep/closeerrors.go)
+// - filepath.Join, Base, Ext, Rel: paths with the OS's separator (path, without file, is for URLs and slash paths)
+// - failures are *fs.PathError (operation, path, cause); errors.Is(err, fs.ErrNotExist) replaces the older
+//   os.IsNotExist(err), which doesn't see through wrapping
+
+// WriteFileAtomic writes data to path so that a reader sees the old file or the new one, never half of it:
+// the data goes to a temp file in the same directory (a rename across filesystems isn't atomic),
+// which is synced, closed, then renamed over path. On any error the temp file is removed
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close() // a second Close just returns an error, ignored here
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil { // CreateTemp makes it 0600
+		return err
+	}
+	if err := tmp.Sync(); err != nil { // on disk before the rename makes it visible
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// --- DiskStore: the FileStore for HandleFileUpload, on disk ---
+
+// An upload arrives as a temporary file (ex. from multipart/form-data parsing); Store(path) moves it into
+// the store's directory under its base name. It's written to a temp file of its own in the store, then
+// hard linked to the real name, so:
+// - a crash mid-copy leaves no half stored file under the real name
+// - two uploads of the same name at once each have their own temp file (os.CreateTemp picks a unique
+//   name), rather than writing into one another's
+// - a name already stored is never replaced: os.Link fails if the name exists, as one step - unlike
+//   checking with os.Stat and then renaming, which another upload can get in between.
+//   (os.Rename would replace the file silently; opening the real name with O_EXCL gets the same
+//   guarantee, but then a half written file is visible under it)
+
+var (
+	ErrBadName = errors.New("bad file name")
+	ErrExists  = errors.New("file already stored")
+)
+
+type DiskStore struct {
+	dir string
+}
+
+// NewDiskStore stores files in dir, creating it (and its parents) if needed
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+func (s *DiskStore) Dir() string { return s.dir }
+
+// Backend names the store in HandleFileUpload's errors
+func (s *DiskStore) Backend() string { return "disk" }
+
+// Store copies the file at path into the store. The name kept is path's base name, which must be
+// a plain name (not "..", not hidden) - it comes from outside, and mustn't pick where the file goes
+func (s *DiskStore) Store(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close() // opened for reading - nothing to lose in its Close
+	if info, err := in.Stat(); err != nil {
+		return err
+	} else if !info.Mode().IsRegular() { // a directory opens fine, and only fails once read
+		return fmt.Errorf("%s: not a regular file", path)
+	}
+	return s.StoreFrom(filepath.Base(path), in)
+}
+
+// StoreFrom stores what r reads under name - an upload streamed straight from the request, with no
+// temp file of its own. name is checked as in Store. If reading r fails (ex. the upload is over a size
+// limit, or the client went away), the partial copy is removed and nothing is stored
+func (s *DiskStore) StoreFrom(name string, r io.Reader) error {
+	dst, err := s.destination(name)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, "."+name+".*.partial") // hidden, so List skips it
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // once linked, the file is still there under dst
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("storing %s: %w", name, err)
+	}
+	if err := tmp.Chmod(0o644); err != nil { // CreateTemp makes it 0600
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Link(tmp.Name(), dst); err != nil {
+		if errors.Is(err, fs.ErrExist) { // stored by another upload since destination looked
+			return fmt.Errorf("%w: %s", ErrExists, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// destination is where name goes, if it's a plain name. A name already stored is ErrExists here, before
+// any copying - a quick answer, but only a hint: StoreFrom's os.Link is what makes sure
+func (s *DiskStore) destination(name string) (string, error) {
+	if !filepath.IsLocal(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("%w: %q", ErrBadName, name)
+	}
+	dst := filepath.Join(s.dir, name)
+	if _, err := os.Stat(dst); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrExists, name)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	return dst, nil
+}
+
+// Has reports whether name is stored
+func (s *DiskStore) Has(name string) bool {
+	info, err := os.Stat(filepath.Join(s.dir, name))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// List is the stored names, sorted (os.ReadDir sorts by name), without partial copies
+func (s *DiskStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// --- Walking a directory tree ---
+
+// GoFiles returns the .go files under root, as paths relative to it, in lexical order.
+// Hidden directories (.git) are skipped whole - returning fs.SkipDir from the callback for a directory
+// skips everything in it. WalkDir hands over fs.DirEntry values, from the directory listing,
+// so unlike the older filepath.Walk it doesn't stat every file
+func GoFiles(root string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err // a directory that couldn't be read - stop (returning nil would skip it and go on)
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".go" {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			found = append(found, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	slices.Sort(found) // WalkDir already goes in lexical order per directory; this makes the whole list sorted
+	return found, err
+}
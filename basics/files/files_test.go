@@ -0,0 +1,158 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func newStore(t *testing.T) *DiskStore {
+	t.Helper()
+	s, err := NewDiskStore(filepath.Join(t.TempDir(), "a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// entries is everything in the store's directory, partial copies included
+func entries(t *testing.T, s *DiskStore) []string {
+	t.Helper()
+	es, err := os.ReadDir(s.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range es {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestStoreFrom(t *testing.T) {
+	s := newStore(t)
+	if err := s.StoreFrom("report.txt", strings.NewReader("quarterly numbers")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir(), "report.txt"))
+	if err != nil || string(data) != "quarterly numbers" {
+		t.Errorf("report.txt = %q, %v", data, err)
+	}
+	info, _ := os.Stat(filepath.Join(s.Dir(), "report.txt"))
+	if info.Mode().Perm() != 0o644 {
+		t.Errorf("mode %v, want 0644", info.Mode().Perm())
+	}
+	if !s.Has("report.txt") || s.Has("missing.txt") {
+		t.Error("Has is wrong")
+	}
+
+	// Never replaced
+	if err := s.StoreFrom("report.txt", strings.NewReader("other")); !errors.Is(err, ErrExists) {
+		t.Errorf("StoreFrom of a stored name = %v, want ErrExists", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(s.Dir(), "report.txt")); string(data) != "quarterly numbers" {
+		t.Errorf("after a second StoreFrom: %q", data)
+	}
+}
+
+func TestStoreFromBadNames(t *testing.T) {
+	s := newStore(t)
+	for _, name := range []string{"", ".", "..", "../escape.txt", "/etc/passwd", ".env", "a/../../b"} {
+		if err := s.StoreFrom(name, strings.NewReader("x")); !errors.Is(err, ErrBadName) {
+			t.Errorf("StoreFrom(%q) = %v, want ErrBadName", name, err)
+		}
+	}
+	if got := entries(t, s); len(got) != 0 {
+		t.Errorf("files after bad names: %q", got)
+	}
+}
+
+// A read that fails halfway stores nothing, and leaves no partial copy
+func TestStoreFromReadError(t *testing.T) {
+	s := newStore(t)
+	errRead := errors.New("client went away")
+	r := io.MultiReader(bytes.NewReader(bytes.Repeat([]byte("a"), 100)), iotest.ErrReader(errRead))
+	if err := s.StoreFrom("cut.txt", r); !errors.Is(err, errRead) {
+		t.Fatalf("StoreFrom of a failing reader = %v, want %v", err, errRead)
+	}
+	if s.Has("cut.txt") {
+		t.Error("cut.txt was stored")
+	}
+	if got := entries(t, s); len(got) != 0 {
+		t.Errorf("left behind: %q", got)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := newStore(t)
+	src := filepath.Join(t.TempDir(), "notes.md")
+	os.WriteFile(src, []byte("# notes"), 0o600)
+	if err := s.Store(src); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(s.Dir(), "notes.md")); string(data) != "# notes" {
+		t.Errorf("notes.md = %q", data)
+	}
+	if err := s.Store(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Store of a missing file = %v", err)
+	}
+	if err := s.Store(t.TempDir()); err == nil {
+		t.Error("Store of a directory succeeded")
+	}
+}
+
+// Many StoreFroms of one name at once: exactly one wins, and what's stored is all of its data
+func TestStoreFromConcurrent(t *testing.T) {
+	s := newStore(t)
+	const n = 20
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			errs[i] = s.StoreFrom("same.txt", bytes.NewReader(bytes.Repeat([]byte{'a' + byte(i)}, 64<<10)))
+		})
+	}
+	wg.Wait()
+	stored := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			stored++
+		case !errors.Is(err, ErrExists):
+			t.Errorf("StoreFrom = %v, want nil or ErrExists", err)
+		}
+	}
+	if stored != 1 {
+		t.Errorf("%d of %d StoreFroms of one name succeeded, want 1", stored, n)
+	}
+	data, _ := os.ReadFile(filepath.Join(s.Dir(), "same.txt"))
+	if len(data) != 64<<10 || !bytes.Equal(data, bytes.Repeat(data[:1], len(data))) {
+		t.Errorf("same.txt is %d bytes, not one upload whole", len(data))
+	}
+	if got := entries(t, s); !slices.Equal(got, []string{"same.txt"}) {
+		t.Errorf("in the directory: %q, want only same.txt", got)
+	}
+}
+
+func TestList(t *testing.T) {
+	s := newStore(t)
+	for _, name := range []string{"c.txt", "a.txt", "b.txt"} {
+		s.StoreFrom(name, strings.NewReader(name))
+	}
+	os.WriteFile(filepath.Join(s.Dir(), ".x.partial"), nil, 0o644) // as if an upload were in progress
+	os.Mkdir(filepath.Join(s.Dir(), "sub"), 0o755)
+	names, err := s.List()
+	if want := []string{"a.txt", "b.txt", "c.txt"}; err != nil || !slices.Equal(names, want) {
+		t.Errorf("List = %q, %v, want %q", names, err, want)
+	}
+	if s.Has("sub") {
+		t.Error("Has(a directory) = true")
+	}
+}
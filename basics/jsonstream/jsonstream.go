@@ -0,0 +1,51 @@
+package jsonstream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// json.Marshal (and json.Encoder.Encode) of a slice needs the whole slice in memory,
+// and then builds the whole output in memory too, before the first byte is written.
+// For a large result set (every row of a table) that's two full copies of it.
+//
+// EncodeStream writes the array one element at a time as seq produces them,
+// so only one element (and one buffer) is held at once - the source can be a database cursor,
+// a paginated API, or a file being read, as long as it is an iter.Seq.
+//
+// The output is byte for byte what json.Marshal gives for a slice of the same elements
+// (each element is encoded by json.Marshal, so MarshalJSON methods, struct tags and HTML escaping all apply),
+// except that an empty seq is always [] - there is no nil slice to turn into null.
+//
+// Once writing has started it can't be taken back: if an element fails to encode (or w fails),
+// the output so far is an incomplete array. An HTTP handler has already sent its status by then,
+// so the client sees a truncated body rather than an error status.
+
+func EncodeStream[T any](w io.Writer, seq iter.Seq[T]) error {
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte('['); err != nil {
+		return err
+	}
+	i := 0
+	for v := range seq {
+		b, err := json.Marshal(v)
+		if err != nil {
+			bw.Flush() // still write out what came before, so w holds everything up to the bad element
+			return fmt.Errorf("jsonstream: element %d: %w", i, err)
+		}
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		// bufio.Writer keeps the first write error and returns it from every later call,
+		// so checking this Write covers the WriteByte above too
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+		i++
+	}
+	bw.WriteByte(']')
+	return bw.Flush()
+}
@@ -0,0 +1,130 @@
+package jsonstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type item struct {
+	Name  string         `json:"name"`
+	Tags  []string       `json:"tags,omitempty"`
+	Extra map[string]int `json:"extra,omitempty"`
+	When  time.Time      `json:"when"` // has a MarshalJSON method
+}
+
+func encode[T any](t *testing.T, values []T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := EncodeStream(&buf, slices.Values(values)); err != nil {
+		t.Fatalf("EncodeStream: %v", err)
+	}
+	return buf.String()
+}
+
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSameAsMarshal(t *testing.T) {
+	items := []item{
+		{Name: "<b>bold</b> & co"}, // HTML escaping, like Marshal
+		{Name: "gopher", Tags: []string{"go"}, Extra: map[string]int{"b": 2, "a": 1}, When: time.Unix(0, 0).UTC()},
+	}
+	many := make([]int, 10_000) // more than one bufio buffer of output
+	for i := range many {
+		many[i] = i * 7919
+	}
+	if got, want := encode(t, []int{1, 2, 3}), marshal(t, []int{1, 2, 3}); got != want {
+		t.Errorf("ints: %s, want %s", got, want)
+	}
+	if got, want := encode(t, items), marshal(t, items); got != want {
+		t.Errorf("structs: %s, want %s", got, want)
+	}
+	if got, want := encode(t, []string{"a b", "\xff"}), marshal(t, []string{"a b", "\xff"}); got != want {
+		t.Errorf("strings: %s, want %s", got, want)
+	}
+	if got, want := encode(t, many), marshal(t, many); got != want {
+		t.Errorf("10000 ints: output differs from json.Marshal (%d and %d bytes)", len(got), len(want))
+	}
+	// one exception: nothing to encode is [], where Marshal of a nil slice is null
+	if got := encode(t, []int(nil)); got != "[]" {
+		t.Errorf("empty: %s, want []", got)
+	}
+}
+
+func TestElementError(t *testing.T) {
+	var buf bytes.Buffer
+	err := EncodeStream(&buf, slices.Values([]any{1, 2, func() {}, 4}))
+	var unsupported *json.UnsupportedTypeError
+	if !errors.As(err, &unsupported) || !strings.Contains(err.Error(), "element 2") {
+		t.Errorf("err = %v, want an UnsupportedTypeError for element 2", err)
+	}
+	if buf.String() != "[1,2" {
+		t.Errorf("output %q, want the elements before the bad one: \"[1,2\"", buf.String())
+	}
+}
+
+type failingWriter struct{ after int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.after {
+		return w.after, errors.New("disk full")
+	}
+	w.after -= len(p)
+	return len(p), nil
+}
+
+func TestWriterError(t *testing.T) {
+	// the output is 40001 bytes: fails inside the stream (a full bufio buffer is flushed), and on the last flush
+	for _, after := range []int{0, 100, 39_000} {
+		err := EncodeStream(&failingWriter{after}, slices.Values(make([]int, 20_000)))
+		if err == nil || err.Error() != "disk full" {
+			t.Errorf("writer failing after %d bytes: err = %v, want disk full", after, err)
+		}
+	}
+}
+
+// Output reaches w while seq is still producing - the array is never built up whole
+func TestIncremental(t *testing.T) {
+	var buf bytes.Buffer
+	var seen []int // len(buf) each time seq is asked for its next element
+	seq := iter.Seq[string](func(yield func(string) bool) {
+		for range 100 {
+			seen = append(seen, buf.Len())
+			if !yield(strings.Repeat("x", 1000)) {
+				return
+			}
+		}
+	})
+	if err := EncodeStream(&buf, seq); err != nil {
+		t.Fatal(err)
+	}
+	if seen[len(seen)-1] == 0 {
+		t.Error("nothing was written before the last element was produced")
+	}
+	if want := 100*1003 + 1; buf.Len() != want { // 1000 x's, quotes and a comma each, and the brackets
+		t.Errorf("%d bytes written, want %d", buf.Len(), want)
+	}
+}
+
+func BenchmarkEncodeStream(b *testing.B) {
+	values := make([]item, 1000)
+	for i := range values {
+		values[i] = item{Name: "gopher", Tags: []string{"go", "json"}}
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		EncodeStream(&bytes.Buffer{}, slices.Values(values))
+	}
+}
@@ -4,15 +4,18 @@ package main
 // package declaration statement at the top of the function
 
 import (
+	"basics/dump"
+	"basics/orderedjson"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"math"
+	"os"
+	"slices"
 	"strings"
 	"time"
 )
 
-const fileUploadSuccessfulMsg string = "Successfully created"
-const fileUploadErrorMsg string = "An error occurred while attempting to create"
-
 // Ex. function
 
 func add(x, y int) int { // shorten all but the last func param if all are same
@@ -242,6 +245,10 @@ func rangeForLoopEx() {
 	for i := range pow {
 		fmt.Println(i)
 	}
+
+	// (sliceutil.go writes these loops with generic Map, Filter and Reduce)
+	// (the mapsslices module has the searching and editing loops from the standard slices package: Contains, Index, Sort, Delete)
+	// (fileformats.go writes records to CSV and XML files, and reads them back)
 }
 
 // Ex. Maps
@@ -272,6 +279,11 @@ func mapExample() {
 
 	fmt.Println("map contents", userLookupTable)
 
+	// %v puts everything on one line and drops the field names.
+	// dump prints one field per line with types, and map keys in sorted order
+	// (same output every run, so it can be compared against a saved copy)
+	dump.Print(userLookupTable)
+
 	// --- Ex. map operations ---
 
 	const newKey string = "orange"
@@ -294,6 +306,75 @@ func mapExample() {
 
 	elem, ok = dictionary["apple"]
 	fmt.Println("The value:", elem, "Present?", ok)
+
+	// (the mapsslices module does the loops over these maps with the maps package: Keys, Values, Clone, Equal)
+}
+
+// Ex. Maps as JSON
+
+// Iteration order over a map is random (different on every run),
+// so output built by ranging over a map changes between runs.
+// encoding/json sorts keys, but as strings - int keys come out as "1", "10", "2"
+func mapJSONExample() {
+	statusCodes := map[int]string{
+		2:  "two",
+		10: "ten",
+		1:  "one",
+	}
+
+	plain, _ := json.Marshal(statusCodes)
+	fmt.Println("encoding/json:", string(plain))
+
+	// orderedjson.Map has the same underlying type, so a conversion is enough (no copy of the entries)
+	ordered, _ := json.Marshal(orderedjson.Map[int, string](statusCodes))
+	fmt.Println("orderedjson:", string(ordered))
+
+	// Values can be orderedjson.Map too, so nested maps are ordered the same way
+	users := orderedjson.Map[string, orderedjson.Map[string, string]]{
+		userId2: {"Name": "Jack Eod", "UserId": userId2},
+		userId1: {"UserId": userId1, "Name": "John Doe"},
+	}
+	indented, _ := orderedjson.MarshalIndent(users)
+	fmt.Println(string(indented))
+
+	// Round trip - decoding gives back the same map (orderedjson_test.go checks it)
+	var decoded orderedjson.Map[int, string]
+	err := json.Unmarshal(ordered, &decoded)
+	fmt.Println("decoded:", decoded, "err:", err)
+
+	// A key type with MarshalText is written as its text - still sorted by its own order (Debug < Info < Warn)
+	counts := orderedjson.Map[logLevel, int]{levelWarn: 3, levelDebug: 10, levelInfo: 7}
+	byName, _ := json.Marshal(counts)
+	var levels orderedjson.Map[logLevel, int]
+	err = json.Unmarshal(byName, &levels)
+	fmt.Println("text keys:", string(byName), "| decoded:", levels, "err:", err)
+}
+
+// logLevel is an int key with names of its own in JSON
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+)
+
+var logLevelNames = []string{"debug", "info", "warn"}
+
+func (l logLevel) MarshalText() ([]byte, error) {
+	if l < 0 || int(l) >= len(logLevelNames) {
+		return nil, fmt.Errorf("no log level %d", int(l))
+	}
+	return []byte(logLevelNames[l]), nil
+}
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	i := slices.Index(logLevelNames, string(text))
+	if i < 0 {
+		return fmt.Errorf("no log level %q", text)
+	}
+	*l = logLevel(i)
+	return nil
 }
 
 // Ex. Functions as values in Go
@@ -314,24 +395,37 @@ func FunctionValuesEx(fn func(x, y string) (string, string)) {
 
 // Ex. public and private functions, file upload
 
-func HandleFileUpload(file string) string {
-	var resMes string
-	var uploadSuccess bool = storeFileInDb(file)
+// HandleFileUpload does not create its own storage (a database, disk, etc.).
+// It receives one through the FileStore interface - dependency injection.
+// main decides which implementation to use, and examples / tests can pass in
+// an in-memory or always-failing store without changing HandleFileUpload.
+// (see filestore.go for the implementations, files.go for one storing to a directory, and uploadhttp.go
+// for an HTTP handler in front of them)
+type FileStore interface {
+	Store(file string) error
+}
 
-	if !uploadSuccess {
-		resMes = fileUploadErrorMsg
-	} else {
-		resMes = fileUploadSuccessfulMsg
+// Where to log is injected the same way: a *slog.Logger, so main picks text or JSON, or a level, and
+// an example that isn't about logging passes discardLog (see logging.go)
+//
+// The result is an error, nil on success (see uploaderrors.go): ErrFileTooLarge before the store is
+// tried, or a *StorageError wrapping what the store returned - so a caller can tell the cases apart
+// with errors.Is and errors.As, and UploadMessage turns them into text for the user
+func HandleFileUpload(log *slog.Logger, store FileStore, file string) error {
+	// Only a file on disk has a size to check - an in-memory store's names aren't files
+	if info, err := os.Stat(file); err == nil && info.Size() > MaxUploadSize {
+		log.Warn("upload rejected", "file", file, "size", info.Size())
+		return fmt.Errorf("%s: %w (%d bytes, the limit is %d)", file, ErrFileTooLarge, info.Size(), MaxUploadSize)
 	}
-	return resMes
-}
 
-func storeFileInDb(file string) bool {
-	if file == "bad_file" {
-		// fmt.Println("Log: Error while attempting to store file")
-		return false
+	var err error = store.Store(file)
+
+	if err != nil {
+		log.Error("storing upload failed", "file", file, "err", err)
+		return &StorageError{Backend: backendName(store), File: file, Err: err}
 	}
-	return true
+	log.Info("stored upload", "file", file)
+	return nil
 }
 
 func main() {
@@ -344,8 +438,14 @@ func main() {
 	// fmt.Printf("Order after swapping: %s, %s\n", res1, res2)
 
 	// var file string = "bad_file"
-	// var fileUploadMessage string = HandleFileUpload(file)
-	// fmt.Println(fileUploadMessage)
+	// var err error = HandleFileUpload(slog.Default(), NewInMemoryStore(), file)
+	// fmt.Println(UploadMessage(err))
+
+	// fileStoreExample()
+
+	// semaphoreExample()
+
+	// snapshotExample()
 
 	// fmt.Println("hello", res1, "hi")
 
@@ -353,6 +453,40 @@ func main() {
 
 	// mapExample()
 
+	// mapJSONExample()
+
+	// jsonStreamExample()
+
+	// reverseLookupExample()
+
+	// singleflightExample()
+
+	// ttlCacheExample()
+
+	// sliceutilExample()
+
+	// batchesExample()
+
+	// fileFormatsExample()
+
+	// filesExample()
+
+	// diskStoreExample()
+
+	// uploadLoggingExample()
+
+	// checksumUploadExample()
+
+	// uploadErrorsExample()
+
+	// uploadStatusExample()
+
+	// uploadHTTPExample()
+
+	// shardMapExample()
+
+	// lookupTableBenchmarkExample()
+
 	// Pass in the swap function as function argument
 	FunctionValuesEx(swap)
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"basics/sliceutil"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Ex. Batches for a worker pool, with Chunk (and Zip and Window, see basics/sliceutil)
+
+// Handing workers one user at a time means one channel operation (and often a goroutine switch) per user.
+// Batches amortize that: a worker takes a whole []User, and a batch is also the natural unit for a bulk call
+// (one INSERT of 100 rows instead of 100 INSERTs). The pool is the one from concurrency/shutdown.go:
+// a jobs channel, n workers ranging over it, and the results channel closed after the last worker is done
+
+type batchResult struct {
+	worker int
+	users  int
+	names  string
+}
+
+func processBatches(users []User, batchSize, workers int) []batchResult {
+	jobs := make(chan []User)
+	results := make(chan batchResult)
+	go func() {
+		defer close(jobs)
+		for _, batch := range sliceutil.Chunk(users, batchSize) {
+			jobs <- batch
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for batch := range jobs {
+				names := sliceutil.Map(batch, func(u User) string { return u.Name })
+				results <- batchResult{worker: w, users: len(batch), names: strings.Join(names, ",")}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var out []batchResult
+	for r := range results {
+		out = append(out, r)
+	}
+	return out
+}
+
+func batchesExample() {
+	var users []User
+	for i := range 23 {
+		users = append(users, User{UserId: fmt.Sprintf("u%02d", i), Name: fmt.Sprintf("user%d", i)})
+	}
+
+	results := processBatches(users, 5, 3)
+	total := 0
+	for _, r := range results {
+		total += r.users
+		fmt.Printf("worker %d: %d users (%s)\n", r.worker, r.users, r.names)
+	}
+	fmt.Println("batches:", len(results), "| users processed:", total, "| all of them:", total == len(users))
+
+	// Zip: pairing two slices up by position
+	ids := sliceutil.Map(users[:3], func(u User) string { return u.UserId })
+	scores := []int{90, 75, 82, 60} // one more than ids - left out
+	for _, p := range sliceutil.Zip(ids, scores) {
+		fmt.Printf("%s=%d ", p.First, p.Second)
+	}
+	fmt.Println()
+
+	// Window: a moving average over scores
+	avgs := sliceutil.Map(sliceutil.Window(scores, 2), func(w []int) float64 { return float64(w[0]+w[1]) / 2 })
+	fmt.Println("moving average of 2:", avgs)
+
+	// sliceutil/sliceutil_test.go covers the edges of Zip, Chunk and Window; batches_test.go the pool
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Every user is in exactly one batch, whatever the batch size and number of workers
+func TestProcessBatches(t *testing.T) {
+	var users []User
+	var want []string
+	for i := range 23 {
+		users = append(users, User{UserId: fmt.Sprintf("u%02d", i), Name: fmt.Sprintf("user%d", i)})
+		want = append(want, users[i].Name)
+	}
+	slices.Sort(want)
+
+	for _, c := range []struct{ batchSize, workers, batches int }{
+		{5, 3, 5},
+		{23, 2, 1},
+		{100, 4, 1},
+		{1, 8, 23},
+	} {
+		results := processBatches(users, c.batchSize, c.workers)
+		if len(results) != c.batches {
+			t.Errorf("batches of %d: %d batches, want %d", c.batchSize, len(results), c.batches)
+		}
+		var got []string
+		for _, r := range results {
+			names := strings.Split(r.names, ",")
+			if r.users != len(names) || r.users > c.batchSize || r.worker < 0 || r.worker >= c.workers {
+				t.Errorf("batches of %d on %d workers: bad result %+v", c.batchSize, c.workers, r)
+			}
+			got = append(got, names...)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("batches of %d on %d workers processed %v, want each user once", c.batchSize, c.workers, got)
+		}
+	}
+	if results := processBatches(nil, 5, 3); len(results) != 0 {
+		t.Errorf("no users gave %v", results)
+	}
+}
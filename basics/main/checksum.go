@@ -0,0 +1,106 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"basics/files"
+)
+
+// Ex. Checking an upload's SHA-256 before storing it (see the cryptopkg notes for sha256 and hmac)
+
+var errChecksum = errors.New("checksum mismatch")
+
+// ChecksumStore is a FileStore in front of another one: it stores a file only if its SHA-256 is the
+// checksum the uploader sent with it (Expect), so a file changed or cut short on the way is rejected.
+// HandleFileUpload doesn't change - it gets a FileStore, the same as before
+type ChecksumStore struct {
+	next FileStore
+
+	mu   sync.Mutex
+	want map[string]string // file name -> hex sha256
+}
+
+func NewChecksumStore(next FileStore) *ChecksumStore {
+	return &ChecksumStore{next: next, want: map[string]string{}}
+}
+
+// Expect records the checksum sent for the upload named name
+func (s *ChecksumStore) Expect(name, sum string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.want[name] = sum
+}
+
+// Backend names the store in a StorageError: this one and the one behind it
+func (s *ChecksumStore) Backend() string { return "checksum+" + backendName(s.next) }
+
+func (s *ChecksumStore) Store(file string) error {
+	s.mu.Lock()
+	want, ok := s.want[filepath.Base(file)]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%s: no checksum sent", file)
+	}
+	got, err := sha256File(file)
+	if err != nil {
+		return err
+	}
+	// A checksum isn't a secret, so == is fine here - a signature (an HMAC) would need hmac.Equal
+	if got != want {
+		return fmt.Errorf("%s: %w: sha256 is %s, sent %s", file, errChecksum, got[:12], want[:min(12, len(want))])
+	}
+	return s.next.Store(file)
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+var _ FileStore = (*ChecksumStore)(nil)
+
+func checksumUploadExample() {
+	dir, err := os.MkdirTemp("", "notes-uploads-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	disk, err := files.NewDiskStore(filepath.Join(dir, "stored"))
+	if err != nil {
+		fmt.Println("store:", err)
+		return
+	}
+	store := NewChecksumStore(disk)
+
+	// The uploader computes the sum of what it sends
+	contents := []byte("quarterly numbers")
+	sum := sha256.Sum256(contents)
+	store.Expect("report.txt", hex.EncodeToString(sum[:]))
+	store.Expect("cut.txt", hex.EncodeToString(sum[:]))
+
+	// report.txt arrives whole; cut.txt, with the same sum sent, lost its end on the way
+	os.WriteFile(filepath.Join(dir, "report.txt"), contents, 0o644)
+	os.WriteFile(filepath.Join(dir, "cut.txt"), contents[:9], 0o644)
+	os.WriteFile(filepath.Join(dir, "nosum.txt"), contents, 0o644)
+	for _, name := range []string{"report.txt", "cut.txt", "nosum.txt"} {
+		err := HandleFileUpload(discardLog, store, filepath.Join(dir, name))
+		fmt.Printf("%-10s %v\n", name, err)
+	}
+	fmt.Println("stored:", disk.Has("report.txt"), disk.Has("cut.txt"), disk.Has("nosum.txt")) // (checksum_test.go checks each)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"basics/files"
+)
+
+func TestChecksumStore(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := files.NewDiskStore(filepath.Join(dir, "stored"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewChecksumStore(disk)
+
+	contents := []byte("quarterly numbers")
+	sum := sha256.Sum256(contents)
+	store.Expect("report.txt", hex.EncodeToString(sum[:]))
+	store.Expect("cut.txt", hex.EncodeToString(sum[:]))
+	store.Expect("short.txt", "abc") // a sum shorter than the 12 characters the error shows
+	for name, data := range map[string][]byte{
+		"report.txt": contents,
+		"cut.txt":    contents[:9],
+		"nosum.txt":  contents,
+		"short.txt":  contents,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		checksum bool // the error is errChecksum
+		stored   bool
+	}{
+		{"report.txt", false, true},
+		{"cut.txt", true, false},
+		{"short.txt", true, false},
+		{"nosum.txt", false, false},
+		{"missing.txt", false, false},
+	}
+	for _, tt := range tests {
+		err := HandleFileUpload(discardLog, store, filepath.Join(dir, tt.name))
+		if tt.stored != (err == nil) {
+			t.Errorf("%s: HandleFileUpload = %v, want stored %t", tt.name, err, tt.stored)
+		}
+		if got := errors.Is(err, errChecksum); got != tt.checksum {
+			t.Errorf("%s: errors.Is(%v, errChecksum) = %t, want %t", tt.name, err, got, tt.checksum)
+		}
+		if disk.Has(tt.name) != tt.stored {
+			t.Errorf("%s: in the store: %t, want %t", tt.name, disk.Has(tt.name), tt.stored)
+		}
+		var se *StorageError
+		if err != nil && (!errors.As(err, &se) || se.Backend != "checksum+disk") {
+			t.Errorf("%s: %v, want a *StorageError from checksum+disk", tt.name, err)
+		}
+	}
+
+	// Expect is per name: a new sum replaces the old one
+	cutSum := sha256.Sum256(contents[:9])
+	store.Expect("cut.txt", hex.EncodeToString(cutSum[:]))
+	if err := HandleFileUpload(discardLog, store, filepath.Join(dir, "cut.txt")); err != nil || !disk.Has("cut.txt") {
+		t.Errorf("cut.txt with its own sum: %v", err)
+	}
+}
+
+func TestSHA256File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc")
+	os.WriteFile(path, []byte("abc"), 0o644)
+	const want = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got, err := sha256File(path); err != nil || got != want {
+		t.Errorf("sha256File = %s, %v, want %s", got, err, want)
+	}
+	if _, err := sha256File(path + ".missing"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("sha256File of a missing file = %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Ex. The records from rangeForLoopEx as CSV and XML files
+
+// --- CSV (encoding/csv) ---
+
+// A csv.Writer quotes a field only when it needs to (a comma, a quote, a newline, or leading space):
+// "Doe, John" becomes "\"Doe, John\"", and a quote inside is doubled. The reader undoes it.
+// The first row is a header naming the columns - the reader finds columns by name, so a file with the
+// columns in another order (or with extra ones) still reads.
+
+var usersCSVHeader = []string{"user_id", "name"}
+
+func writeUsersCSV(w io.Writer, users []User) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(usersCSVHeader); err != nil {
+		return err
+	}
+	for _, u := range users {
+		if err := cw.Write([]string{u.UserId, u.Name}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()        // the writer is buffered - nothing may have reached w before this
+	return cw.Error() // Flush has no return value; a write error during it shows up here
+}
+
+// rowError is a row that parsed as CSV but isn't a valid User
+type rowError struct {
+	Line int
+	Err  error
+}
+
+func (e *rowError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+func (e *rowError) Unwrap() error { return e.Err }
+
+var errMissingColumn = errors.New("missing column")
+var errEmptyUserId = errors.New("empty user_id")
+
+// readUsersCSV returns the users up to the first bad row, and the error for it - the rows before it are still good.
+// A row with the wrong number of fields is a *csv.ParseError (with csv.ErrFieldCount inside), with its line
+func readUsersCSV(r io.Reader) ([]User, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read() // also sets FieldsPerRecord: every row must have as many fields as the header
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: empty file, no header", errMissingColumn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range usersCSVHeader {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("%w %q in header %v", errMissingColumn, name, header)
+		}
+	}
+
+	var users []User
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return users, nil
+		}
+		if err != nil {
+			return users, err
+		}
+		u := User{UserId: row[col["user_id"]], Name: row[col["name"]]}
+		if u.UserId == "" {
+			line, _ := cr.FieldPos(0)
+			return users, &rowError{Line: line, Err: errEmptyUserId}
+		}
+		users = append(users, u)
+	}
+}
+
+// --- XML (encoding/xml) ---
+
+// User has no xml tags (it's the plain struct from the slices notes), and xml.Marshal would write
+// <User><UserId>...</UserId><Name>...</Name></User>. The file format is its own type, with tags:
+//
+//	`xml:"users"`    the element name (XMLName sets it for the type itself)
+//	`xml:"id,attr"`  an attribute of the element, not a child element
+//	`xml:"user"`     on a slice: one <user> element per item
+//	`xml:",comment"` and `xml:",chardata"` for comments and bare text
+
+type xmlUser struct {
+	Id   string `xml:"id,attr"`
+	Name string `xml:"name"`
+}
+
+type xmlUsers struct {
+	XMLName xml.Name  `xml:"users"`
+	Count   int       `xml:"count,attr"`
+	Users   []xmlUser `xml:"user"`
+}
+
+func writeUsersXML(w io.Writer, users []User) error {
+	doc := xmlUsers{Count: len(users)}
+	for _, u := range users {
+		doc.Users = append(doc.Users, xmlUser{Id: u.UserId, Name: u.Name})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil { // <?xml version="1.0" encoding="UTF-8"?> - Marshal doesn't add it
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func readUsersXML(r io.Reader) ([]User, error) {
+	var doc xmlUsers
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err // a *xml.SyntaxError says on which line
+	}
+	users := make([]User, 0, len(doc.Users))
+	for i, u := range doc.Users {
+		if u.Id == "" {
+			return users, fmt.Errorf("user %d: %w", i+1, errEmptyUserId)
+		}
+		users = append(users, User{UserId: u.Id, Name: u.Name})
+	}
+	if doc.Count != len(users) {
+		return users, fmt.Errorf("count says %d users, found %d", doc.Count, len(users))
+	}
+	return users, nil
+}
+
+func fileFormatsExample() {
+	records := []User{
+		{UserId: userId1, Name: "John Doe"},
+		{UserId: userId2, Name: "Jack Eod"},
+	}
+	tricky := append(slices.Clone(records),
+		User{UserId: "3", Name: "Doe, John"},        // a comma
+		User{UserId: "4", Name: `Jack "JE" Eod`},    // quotes
+		User{UserId: "5", Name: "two\nlines"},       // a newline
+		User{UserId: "6", Name: "Zoë <&> Ångström"}, // not ASCII, and XML's special characters
+	)
+
+	var buf bytes.Buffer
+	writeUsersCSV(&buf, records)
+	fmt.Print("CSV:\n", buf.String())
+	buf.Reset()
+	writeUsersXML(&buf, records)
+	fmt.Print("XML:\n", buf.String(), "\n")
+
+	// Names that need quoting or escaping come back the same
+	buf.Reset()
+	writeUsersCSV(&buf, tricky)
+	fmt.Printf("tricky names as CSV:\n%s", buf.String())
+	back, err := readUsersCSV(&buf)
+	fmt.Printf("read back: %q %v\n", back, err)
+	buf.Reset()
+	writeUsersXML(&buf, tricky[len(tricky)-1:])
+	fmt.Print("escaped in XML:\n", buf.String(), "\n")
+
+	// The header decides the columns: reordered, extra columns and spaces are fine
+	back, err = readUsersCSV(strings.NewReader("name, email, user_id\nJohn Doe, john@example.com, " + userId1 + "\n"))
+	fmt.Println("columns by header:", back, err)
+
+	// Errors: the good rows before a bad one are returned with it
+	for _, in := range []string{
+		"user_id,name\n1,John Doe\n2\n3,Jack Eod\n", // a short row
+		"user_id,name\n1,John Doe\n,Nobody\n",       // an empty id
+		"id,name\n1,John Doe\n",                     // no user_id column
+	} {
+		users, err := readUsersCSV(strings.NewReader(in))
+		fmt.Printf("  %d good row(s), err: %v\n", len(users), err)
+	}
+	_, err = readUsersXML(strings.NewReader("<users count=\"1\">\n  <user id=\"1\"><name>John</user>\n</users>"))
+	fmt.Println("  XML, bad nesting:", err)
+	// fileformats_test.go checks the round trips, the error types and lines, and write errors
+}
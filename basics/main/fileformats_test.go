@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testRecords() []User {
+	return []User{
+		{UserId: userId1, Name: "John Doe"},
+		{UserId: userId2, Name: "Jack Eod"},
+		{UserId: "3", Name: "Doe, John"},        // a comma
+		{UserId: "4", Name: `Jack "JE" Eod`},    // quotes
+		{UserId: "5", Name: "two\nlines"},       // a newline
+		{UserId: "6", Name: "Zoë <&> Ångström"}, // not ASCII, and XML's special characters
+		{UserId: "7", Name: ""},                 // an empty name is allowed, an empty id isn't
+	}
+}
+
+func TestUsersCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeUsersCSV(&buf, testRecords()[2:5]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "user_id,name\n3,\"Doe, John\"\n4,\"Jack \"\"JE\"\" Eod\"\n5,\"two\nlines\"\n"; buf.String() != want {
+		t.Errorf("CSV:\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeUsersCSV(&buf, testRecords())
+	if back, err := readUsersCSV(&buf); err != nil || !slices.Equal(back, testRecords()) {
+		t.Errorf("CSV round trip: %q, %v", back, err)
+	}
+
+	buf.Reset()
+	writeUsersCSV(&buf, nil)
+	if buf.String() != "user_id,name\n" {
+		t.Errorf("no users: %q, want just the header", buf.String())
+	}
+	if back, err := readUsersCSV(&buf); err != nil || len(back) != 0 {
+		t.Errorf("reading just the header: %v, %v", back, err)
+	}
+}
+
+// The header decides the columns: reordered, extra columns, upper case and spaces are fine
+func TestReadUsersCSVByHeader(t *testing.T) {
+	back, err := readUsersCSV(strings.NewReader("Name, email, USER_ID\nJohn Doe, john@example.com, " + userId1 + "\n"))
+	if err != nil || len(back) != 1 || back[0] != (User{UserId: userId1, Name: "John Doe"}) {
+		t.Errorf("columns by header: %v, %v", back, err)
+	}
+}
+
+// The good rows before a bad one are returned with its error
+func TestReadUsersCSVErrors(t *testing.T) {
+	var parseErr *csv.ParseError
+	var rowErr *rowError
+	for _, tc := range []struct {
+		name, in string
+		good     int
+		check    func(error) bool
+	}{
+		{"short row", "user_id,name\n1,John Doe\n2\n3,Jack Eod\n", 1, func(err error) bool {
+			return errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) && parseErr.Line == 3
+		}},
+		{"long row", "user_id,name\n1,John Doe,extra\n", 0, func(err error) bool {
+			return errors.Is(err, csv.ErrFieldCount)
+		}},
+		{"bad quotes", "user_id,name\n1,John Doe\n2,\"Jack \"E\" Eod\"\n", 1, func(err error) bool {
+			return errors.As(err, &parseErr) && errors.Is(err, csv.ErrQuote)
+		}},
+		{"empty id", "user_id,name\n1,John Doe\n,Nobody\n", 1, func(err error) bool {
+			return errors.As(err, &rowErr) && errors.Is(err, errEmptyUserId) && rowErr.Line == 3
+		}},
+		{"no user_id column", "id,name\n1,John Doe\n", 0, func(err error) bool { return errors.Is(err, errMissingColumn) }},
+		{"empty file", "", 0, func(err error) bool { return errors.Is(err, errMissingColumn) }},
+	} {
+		users, err := readUsersCSV(strings.NewReader(tc.in))
+		if len(users) != tc.good || !tc.check(err) {
+			t.Errorf("%s: %d good rows, %v - want %d and the right error", tc.name, len(users), err, tc.good)
+		}
+	}
+}
+
+func TestUsersXML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeUsersXML(&buf, testRecords()[:1]); err != nil {
+		t.Fatal(err)
+	}
+	want := xml.Header + "<users count=\"1\">\n  <user id=\"" + userId1 + "\">\n    <name>John Doe</name>\n  </user>\n</users>"
+	if buf.String() != want {
+		t.Errorf("XML:\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeUsersXML(&buf, testRecords())
+	if !strings.Contains(buf.String(), "Zoë &lt;&amp;&gt; Ångström") {
+		t.Errorf("special characters not escaped:\n%s", buf.String())
+	}
+	if back, err := readUsersXML(&buf); err != nil || !slices.Equal(back, testRecords()) {
+		t.Errorf("XML round trip: %q, %v", back, err)
+	}
+
+	buf.Reset()
+	writeUsersXML(&buf, nil)
+	if back, err := readUsersXML(&buf); err != nil || len(back) != 0 {
+		t.Errorf("no users: %v, %v", back, err)
+	}
+}
+
+func TestReadUsersXMLErrors(t *testing.T) {
+	var syntaxErr *xml.SyntaxError
+	_, err := readUsersXML(strings.NewReader("<users count=\"1\">\n  <user id=\"1\"><name>John</user>\n</users>"))
+	if !errors.As(err, &syntaxErr) || syntaxErr.Line != 2 {
+		t.Errorf("bad nesting: %v, want a SyntaxError on line 2", err)
+	}
+
+	users, err := readUsersXML(strings.NewReader(`<users count="2"><user id="1"><name>John</name></user><user><name>Jack</name></user></users>`))
+	if !errors.Is(err, errEmptyUserId) || len(users) != 1 {
+		t.Errorf("missing id: %v, %v - want the good user kept and errEmptyUserId", users, err)
+	}
+
+	users, err = readUsersXML(strings.NewReader(`<users count="3"><user id="1"><name>John</name></user></users>`))
+	if err == nil || !strings.Contains(err.Error(), "count says 3") || len(users) != 1 {
+		t.Errorf("wrong count: %v, %v", users, err)
+	}
+}
+
+// failAfter is a writer that fails once n bytes have gone through it
+type failAfter struct{ n int }
+
+var errDiskFull = errors.New("disk full")
+
+func (w *failAfter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errDiskFull
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+// A write error is returned, not lost in the buffering - the CSV one only shows up after Flush
+func TestWriteUsersErrors(t *testing.T) {
+	for _, n := range []int{0, 10, 100} {
+		if err := writeUsersCSV(&failAfter{n}, testRecords()); !errors.Is(err, errDiskFull) {
+			t.Errorf("CSV, failing after %d bytes: %v", n, err)
+		}
+		if err := writeUsersXML(&failAfter{n}, testRecords()); !errors.Is(err, errDiskFull) {
+			t.Errorf("XML, failing after %d bytes: %v", n, err)
+		}
+	}
+}
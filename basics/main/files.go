@@ -0,0 +1,161 @@
+package main
+
+import (
+	"basics/files"
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Ex. Files on disk, and HandleFileUpload storing to a directory (see basics/files)
+
+// repoRoot is the nearest directory above the working directory with the notes' Readme.md,
+// or the working directory itself when run from outside the repo
+func repoRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "Readme.md")); err == nil {
+			return dir
+		}
+		if filepath.Dir(dir) == dir {
+			return wd
+		}
+	}
+}
+
+func filesExample() {
+	// A temp directory of our own - removed at the end, whatever happens
+	dir, err := os.MkdirTemp("", "notes-files-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// Whole files: WriteFile creates (or truncates) with the permissions given, ReadFile reads it all
+	notes := filepath.Join(dir, "notes.txt")
+	err = os.WriteFile(notes, []byte("slices\nmaps\nclosures\n"), 0o644)
+	data, rerr := os.ReadFile(notes)
+	fmt.Printf("WriteFile then ReadFile: %q, errs: %v %v\n", data, err, rerr)
+
+	// Streaming: os.Open, a deferred Close, and a Scanner over the *os.File (an io.Reader)
+	f, err := os.Open(notes)
+	if err != nil {
+		fmt.Println("open:", err)
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	var topics []string
+	for sc.Scan() {
+		topics = append(topics, sc.Text())
+	}
+	fmt.Println("read line by line:", topics, sc.Err())
+
+	// Appending needs OpenFile with flags
+	af, err := os.OpenFile(notes, os.O_APPEND|os.O_WRONLY, 0)
+	if err == nil {
+		fmt.Fprintln(af, "generics")
+		err = af.Close()
+	}
+	data, _ = os.ReadFile(notes)
+	fmt.Println("after appending:", strings.Count(string(data), "\n"), "lines, err:", err)
+
+	// A temp file: a unique name from the pattern (the * is replaced), in dir
+	tmp, _ := os.CreateTemp(dir, "upload-*.png")
+	fmt.Println("temp file:", filepath.Base(tmp.Name()), "| in dir:", filepath.Dir(tmp.Name()) == dir)
+	tmp.Close()
+
+	// Errors: a *fs.PathError, and checking for "doesn't exist"
+	_, err = os.ReadFile(filepath.Join(dir, "missing.txt"))
+	var pathErr *fs.PathError
+	fmt.Println("missing file:", err)
+	if errors.As(err, &pathErr) {
+		fmt.Printf("  PathError: Op %q, Path base %q, Err %v\n", pathErr.Op, filepath.Base(pathErr.Path), pathErr.Err)
+	}
+	wrapped := fmt.Errorf("loading config: %w", err)
+	fmt.Println("os.IsNotExist:", os.IsNotExist(err), "| wrapped:", os.IsNotExist(wrapped),
+		"| errors.Is(wrapped, fs.ErrNotExist):", errors.Is(wrapped, fs.ErrNotExist))
+	_, err = os.ReadFile(dir) // a directory, not a file
+	fmt.Println("reading a directory:", errors.As(err, &pathErr), pathErr.Op, pathErr.Err)
+
+	// Atomic replace: the old content or the new, never a half-written file
+	err = files.WriteFileAtomic(notes, []byte("rewritten\n"), 0o644)
+	data, _ = os.ReadFile(notes)
+	entries, _ := os.ReadDir(dir)
+	fmt.Printf("atomic rewrite: %q, err: %v | no temp file left: %t\n", data, err, len(entries) == 2)
+
+	// Paths: filepath for the OS's files, path for slash-separated paths (URLs)
+	fmt.Println("Join:", filepath.Join("basics", "main", "..", "files", "files.go"),
+		"| Ext:", filepath.Ext("files.go"), "| path.Join:", path.Join("/users", "42"))
+
+	// Walking the repo for .go files
+	root := repoRoot()
+	goFiles, err := files.GoFiles(root)
+	perTop := map[string]int{}
+	for _, p := range goFiles {
+		top, _, _ := strings.Cut(p, "/")
+		perTop[top]++
+	}
+	fmt.Println(".go files under", filepath.Base(root)+":", len(goFiles), "err:", err, "| in basics:", perTop["basics"],
+		"| has this file:", slices.ContainsFunc(goFiles, func(p string) bool { return strings.HasSuffix(p, "main/files.go") }))
+}
+
+func diskStoreExample() {
+	dir, err := os.MkdirTemp("", "notes-uploads-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := files.NewDiskStore(filepath.Join(dir, "stored", "2024")) // parent dirs created
+	if err != nil {
+		fmt.Println("store:", err)
+		return
+	}
+	var _ FileStore = store // the same interface as InMemoryStore: HandleFileUpload can't tell them apart
+
+	// An upload lands in a temp file first, then HandleFileUpload hands its path to the store
+	upload := filepath.Join(dir, "report.txt")
+	os.WriteFile(upload, []byte("quarterly numbers"), 0o644)
+	fmt.Println("report.txt:", HandleFileUpload(discardLog, store, upload), "| stored:", store.Has("report.txt"))
+	stored, _ := os.ReadFile(filepath.Join(store.Dir(), "report.txt"))
+	fmt.Printf("  content: %q\n", stored)
+
+	// The failures, each the store's error wrapped in a *StorageError - errors.Is finds the reason inside
+	for _, tc := range []struct {
+		name, path string
+		want       error
+	}{
+		{"again", upload, files.ErrExists},
+		{"missing upload", filepath.Join(dir, "never-uploaded.txt"), fs.ErrNotExist},
+		{"hidden name", filepath.Join(dir, ".env"), files.ErrBadName},
+		{"a directory", dir + string(filepath.Separator), nil},
+	} {
+		err := HandleFileUpload(discardLog, store, tc.path)
+		var storageErr *StorageError
+		fmt.Printf("  %-15s %v | ok: %t\n", tc.name, err,
+			errors.As(err, &storageErr) && (tc.want == nil || errors.Is(err, tc.want)))
+	}
+
+	// Several uploads, in parallel, with UploadAll from the semaphore example
+	var uploads []string
+	for _, name := range []string{"a.png", "b.png", "c.png", "d.png"} {
+		p := filepath.Join(dir, name)
+		os.WriteFile(p, []byte(name), 0o644)
+		uploads = append(uploads, p)
+	}
+	errs := UploadAll(discardLog, store, uploads, 2)
+	names, err := store.List()
+	fmt.Println("UploadAll:", errors.Join(errs...), "| stored:", names, err, "| no partial files:", len(names) == 5)
+}
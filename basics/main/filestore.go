@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// --- FileStore implementations ---
+
+// InMemoryStore keeps stored files in a map (the old storeFileInDb, but with state).
+// It rejects "bad_file" the same way storeFileInDb did, with ErrBadFile.
+// Mutex guarded, as uploads may be handled by several goroutines at once
+type InMemoryStore struct {
+	mu       sync.Mutex
+	files    map[string]bool
+	contents map[string][]byte // what StoreFrom read - Store only has a name
+}
+
+// Constructor function - the zero value InMemoryStore has a nil map, which panics on write
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{files: make(map[string]bool), contents: make(map[string][]byte)}
+}
+
+func (s *InMemoryStore) Store(file string) error {
+	if file == "bad_file" {
+		return ErrBadFile
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[file] = true
+	return nil
+}
+
+// StoreFrom stores what r reads as name (see uploadhttp.go). A read error stores nothing
+func (s *InMemoryStore) StoreFrom(name string, r io.Reader) error {
+	if name == "bad_file" {
+		return ErrBadFile
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name] = true
+	s.contents[name] = data
+	return nil
+}
+
+// Contents is what StoreFrom stored as name
+func (s *InMemoryStore) Contents(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.contents[name]
+	return data, ok
+}
+
+// Backend names the store in a StorageError
+func (s *InMemoryStore) Backend() string { return "memory" }
+
+func (s *InMemoryStore) Has(file string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.files[file]
+}
+
+// FailingStore always returns Err - for checking how callers handle a broken backend
+// (ex. the database being down)
+type FailingStore struct {
+	Err error
+}
+
+func (s FailingStore) Store(file string) error {
+	return s.Err
+}
+
+// mockStore records every call and returns a preset error,
+// so an example (or test) can check exactly how HandleFileUpload used its dependency
+type mockStore struct {
+	calls []string
+	err   error
+}
+
+func (m *mockStore) Store(file string) error {
+	m.calls = append(m.calls, file)
+	return m.err
+}
+
+// Compile time checks
+var _ FileStore = (*InMemoryStore)(nil)
+var _ FileStore = FailingStore{}
+var _ FileStore = (*mockStore)(nil)
+
+func fileStoreExample() {
+	memory := NewInMemoryStore()
+	fmt.Println("in-memory, report.txt:", HandleFileUpload(discardLog, memory, "report.txt"), "| stored:", memory.Has("report.txt"))
+	fmt.Println("in-memory, bad_file:", HandleFileUpload(discardLog, memory, "bad_file"), "| stored:", memory.Has("bad_file"))
+
+	down := FailingStore{Err: errors.New("database unavailable")}
+	fmt.Println("failing store:", HandleFileUpload(discardLog, down, "report.txt"))
+
+	// Checking the interaction, not just the result
+	mock := &mockStore{}
+	err := HandleFileUpload(discardLog, mock, "photo.png")
+	fmt.Printf("mock: %v, calls: %q, passed through unchanged: %t\n",
+		err, mock.calls, len(mock.calls) == 1 && mock.calls[0] == "photo.png")
+
+	// The store's error comes back wrapped, not replaced
+	diskFull := errors.New("disk full")
+	mock = &mockStore{err: diskFull}
+	err = HandleFileUpload(discardLog, mock, "photo.png")
+	fmt.Println("mock with error:", err, "| is the store's error:", errors.Is(err, diskFull))
+}
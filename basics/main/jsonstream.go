@@ -0,0 +1,149 @@
+package main
+
+import (
+	"basics/jsonstream"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Ex. Streaming a large JSON array (see basics/jsonstream)
+
+// userPage stands in for one query of a paginated repository (ex. SELECT ... ORDER BY id LIMIT n OFFSET k)
+func userPage(offset, limit, total int) []User {
+	var page []User
+	for i := offset; i < min(offset+limit, total); i++ {
+		page = append(page, User{UserId: "user-" + strconv.Itoa(i), Name: "Gopher #" + strconv.Itoa(i)})
+	}
+	return page
+}
+
+// allUsers pages through every user, fetching the next page only when the previous one is used up.
+// Returning early from the range loop (yield returning false) stops the paging
+func allUsers(total, pageSize int) iter.Seq[User] {
+	return func(yield func(User) bool) {
+		for offset := 0; offset < total; offset += pageSize {
+			for _, u := range userPage(offset, pageSize, total) {
+				if !yield(u) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// listUsersHandler is a "GET /users" endpoint streaming every user
+func listUsersHandler(total int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := jsonstream.EncodeStream(w, allUsers(total, 100)); err != nil {
+			// too late for an error status - the 200 and part of the body are already sent
+			fmt.Println("streaming users:", err)
+		}
+	}
+}
+
+// peakHeap runs f and returns roughly the most heap memory in use while it ran, above what was in use before.
+// A goroutine samples the heap size every millisecond (runtime/metrics reads it without stopping the world).
+// The heap also holds garbage until the next GC, so GC runs far more often than usual meanwhile -
+// that keeps the garbage small, and what's measured is close to what f really keeps alive
+func peakHeap(f func()) uint64 {
+	defer debug.SetGCPercent(debug.SetGCPercent(5))
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	read := func() uint64 {
+		metrics.Read(sample)
+		return sample[0].Value.Uint64()
+	}
+	// twice - right after one collection the metric can still include what it just freed
+	runtime.GC()
+	runtime.GC()
+	base, peak := read(), uint64(0)
+	done, sampled := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(sampled)
+		tick := time.NewTicker(time.Millisecond)
+		defer tick.Stop()
+		for {
+			peak = max(peak, read())
+			select {
+			case <-done:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+	f()
+	close(done)
+	<-sampled // the sampler is done with sample and peak - one last reading here
+	peak = max(peak, read())
+	return peak - min(base, peak)
+}
+
+func jsonStreamExample() {
+	// --- Same bytes as json.Marshal ---
+
+	type item struct {
+		Name  string         `json:"name"`
+		Tags  []string       `json:"tags,omitempty"`
+		Extra map[string]int `json:"extra,omitempty"`
+	}
+	same := func(name string, want any, got func(io.Writer) error) {
+		var buf bytes.Buffer
+		err := got(&buf)
+		expected, _ := json.Marshal(want)
+		out := buf.String()
+		if len(out) > 60 {
+			out = out[:57] + "..."
+		}
+		fmt.Printf("%-22s %-60s same as json.Marshal: %t (err: %v)\n", name+":", out, bytes.Equal(buf.Bytes(), expected), err)
+	}
+	ints := []int{1, 2, 3}
+	items := []item{{Name: "<b>bold</b> & co"}, {Name: "gopher", Tags: []string{"go"}, Extra: map[string]int{"b": 2, "a": 1}}}
+	same("ints", ints, func(w io.Writer) error { return jsonstream.EncodeStream(w, slices.Values(ints)) })
+	same("structs, escaping", items, func(w io.Writer) error { return jsonstream.EncodeStream(w, slices.Values(items)) })
+	same("empty", []int{}, func(w io.Writer) error { return jsonstream.EncodeStream(w, slices.Values([]int(nil))) })
+	same("250 users, 3 pages", slices.Collect(allUsers(250, 100)), func(w io.Writer) error {
+		return jsonstream.EncodeStream(w, allUsers(250, 100))
+	})
+
+	// An element that can't be encoded stops the stream with an error, after the elements before it
+	var partial bytes.Buffer
+	err := jsonstream.EncodeStream(&partial, slices.Values([]any{1, func() {}, 3}))
+	fmt.Printf("unencodable element: output %q, err: %v\n", partial.String(), err)
+
+	// --- The users endpoint ---
+
+	rec := httptest.NewRecorder()
+	listUsersHandler(5).ServeHTTP(rec, httptest.NewRequest("GET", "/users", nil))
+	fmt.Println("GET /users:", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+
+	// --- Memory ---
+
+	// Marshal needs the whole slice and the whole output at once, so its peak grows with the result.
+	// EncodeStream only ever holds one page of users and the bufio buffer, at any size
+	fmt.Printf("%-9s %16s %16s\n", "users", "json.Marshal", "EncodeStream")
+	var marshalPeaks, streamPeaks []uint64
+	for _, total := range []int{100_000, 400_000} {
+		marshalPeak := peakHeap(func() {
+			b, _ := json.Marshal(slices.Collect(allUsers(total, 100)))
+			io.Discard.Write(b)
+		})
+		streamPeak := peakHeap(func() {
+			jsonstream.EncodeStream(io.Discard, allUsers(total, 100))
+		})
+		marshalPeaks, streamPeaks = append(marshalPeaks, marshalPeak), append(streamPeaks, streamPeak)
+		fmt.Printf("%-9d %13.1f MB %13d KB\n", total, float64(marshalPeak)/1e6, streamPeak/1e3)
+	}
+	fmt.Println("4x the users - json.Marshal peak grew over 3x:", marshalPeaks[1] > 3*marshalPeaks[0],
+		"| EncodeStream stayed under 2 MB:", slices.Max(streamPeaks) < 2e6)
+}
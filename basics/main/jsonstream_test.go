@@ -0,0 +1,47 @@
+package main
+
+import (
+	"basics/jsonstream"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestListUsersHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	listUsersHandler(250).ServeHTTP(rec, httptest.NewRequest("GET", "/users", nil))
+	want, _ := json.Marshal(slices.Collect(allUsers(250, 100)))
+	if rec.Code != 200 || rec.Header().Get("Content-Type") != "application/json" || rec.Body.String() != string(want) {
+		t.Errorf("GET /users: %d %q, body of %d bytes, want 200 application/json and %d bytes",
+			rec.Code, rec.Header().Get("Content-Type"), rec.Body.Len(), len(want))
+	}
+}
+
+// json.Marshal's peak memory grows with the result, EncodeStream's doesn't
+func TestEncodeStreamPeakMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("encodes 800k users")
+	}
+	var marshalPeaks, streamPeaks []uint64
+	for _, total := range []int{100_000, 400_000} {
+		marshalPeaks = append(marshalPeaks, peakHeap(func() {
+			b, _ := json.Marshal(slices.Collect(allUsers(total, 100)))
+			io.Discard.Write(b)
+		}))
+		streamPeaks = append(streamPeaks, peakHeap(func() {
+			jsonstream.EncodeStream(io.Discard, allUsers(total, 100))
+		}))
+	}
+	if marshalPeaks[1] < 3*marshalPeaks[0] {
+		t.Errorf("json.Marshal peaks %d and %d bytes: 4x the users, want over 3x the memory", marshalPeaks[0], marshalPeaks[1])
+	}
+	// a page of 100 users, the bufio buffer, and whatever the sampling didn't get collected in time
+	if slices.Max(streamPeaks) > 2e6 {
+		t.Errorf("EncodeStream peaks %v bytes, want under 2 MB at any size", streamPeaks)
+	}
+	if slices.Max(streamPeaks)*10 > marshalPeaks[0] {
+		t.Errorf("EncodeStream peaks %v bytes, json.Marshal %v: want EncodeStream far below", streamPeaks, marshalPeaks)
+	}
+}
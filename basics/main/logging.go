@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Ex. Logging from HandleFileUpload (see the logging module for slog itself)
+
+// discardLog is for the examples where the log isn't the point - slog.DiscardHandler drops every record
+var discardLog = slog.New(slog.DiscardHandler)
+
+func uploadLoggingExample() {
+	// Text to stdout, without the time, so the lines are the same every run
+	noTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: noTime}))
+	HandleFileUpload(log, NewInMemoryStore(), "report.txt")
+	HandleFileUpload(log, FailingStore{Err: errors.New("database unavailable")}, "report.txt")
+
+	// The caller adds what HandleFileUpload doesn't know - whose upload it is - and every line has it
+	HandleFileUpload(log.With("user", userId1), NewInMemoryStore(), "bad_file")
+
+	// JSON into a buffer, at Warn: only the failure is kept, and its fields can be checked
+	var buf bytes.Buffer
+	warnOnly := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	errs := UploadAll(warnOnly.With("batch", 1), NewInMemoryStore(), []string{"a.txt", "bad_file", "b.txt"}, 2)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var rec struct {
+		Level string `json:"level"`
+		File  string `json:"file"`
+		Err   string `json:"err"`
+		Batch int    `json:"batch"`
+	}
+	err := json.Unmarshal([]byte(lines[0]), &rec)
+	fmt.Printf("UploadAll logged %d line(s) at Warn: %+v %v\n", len(lines), rec, err)
+	fmt.Println("| ok: only the failure, with its file, error and batch:", len(lines) == 1 && rec.Level == "ERROR" &&
+		rec.File == "bad_file" && rec.Err != "" && rec.Batch == 1 && errors.Is(errs[1], ErrBadFile))
+}
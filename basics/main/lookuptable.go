@@ -0,0 +1,146 @@
+package main
+
+import (
+	"basics/shardmap"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Ex. mapExample's userLookupTable, shared between goroutines - three ways (see basics/shardmap)
+// (concurrency/syncmap.go compares map+Mutex with sync.Map for an int cache; this adds the sharded map,
+// and varies the number of goroutines too)
+
+// userLookup is what the handlers of a server would need from the table
+type userLookup interface {
+	Get(id string) (User, bool)
+	Set(id string, u User)
+}
+
+// rwMutexTable is one map behind one RWMutex: readers share it, a writer has it to itself
+type rwMutexTable struct {
+	mu sync.RWMutex
+	m  map[string]User
+}
+
+func (t *rwMutexTable) Get(id string) (User, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	u, ok := t.m[id]
+	return u, ok
+}
+
+func (t *rwMutexTable) Set(id string, u User) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.m[id] = u
+}
+
+// syncMapTable is a sync.Map, with the type assertion every Load needs
+type syncMapTable struct{ m sync.Map }
+
+func (t *syncMapTable) Get(id string) (User, bool) {
+	v, ok := t.m.Load(id)
+	if !ok {
+		return User{}, false
+	}
+	return v.(User), true
+}
+
+func (t *syncMapTable) Set(id string, u User) { t.m.Store(id, u) }
+
+// *shardmap.Map[string, User] has Get and Set already - it is a userLookup as it is
+var _ userLookup = (*shardmap.Map[string, User])(nil)
+
+func shardMapExample() {
+	users := shardmap.New[string, User](10)
+	fmt.Println("shards:", users.Shards(), "(10 rounded up) | bytes per shard:", unsafe.Sizeof(struct {
+		mu sync.RWMutex
+		m  map[string]User
+	}{}), "+ padding to 64")
+
+	users.Set(userId1, User{UserId: userId1, Name: "John Doe"})
+	users.Set(userId2, User{UserId: userId2, Name: "Jack Eod"})
+	u, ok := users.Get(userId2)
+	fmt.Println("Get:", u, ok)
+
+	// 8 goroutines renaming users and counting logins at once: Update is a read-modify-write under one
+	// shard's lock, so no increment is lost
+	logins := shardmap.New[string, int](16)
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				id := "user-" + strconv.Itoa(i%100)
+				logins.Update(id, func(n int, _ bool) int { return n + 1 })
+				if i%10 == 0 {
+					users.Set(id, User{UserId: id, Name: "user " + strconv.Itoa(g)})
+				}
+			}
+		})
+	}
+	wg.Wait()
+	total := 0
+	for _, n := range logins.All() {
+		total += n
+	}
+	fmt.Println("logins:", total, "over", logins.Len(), "users | users:", users.Len())
+	users.Delete(userId1)
+	_, ok = users.Get(userId1)
+	fmt.Println("after Delete:", ok, users.Len())
+}
+
+// timeLookups runs random Gets and Sets on table over ids (all stored up front), writePercent of them Sets,
+// on goroutines goroutines - the time per operation, all goroutines together. A rough timing:
+// BenchmarkLookupTable in lookuptable_test.go is the careful one
+func timeLookups(table userLookup, ids []string, writePercent, goroutines int) time.Duration {
+	for _, id := range ids {
+		table.Set(id, User{UserId: id, Name: "user " + id})
+	}
+	const ops = 200_000
+	perGoroutine := ops / goroutines
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			rng := rand.New(rand.NewPCG(uint64(g), 0))
+			for range perGoroutine {
+				id := ids[rng.IntN(len(ids))]
+				if rng.IntN(100) < writePercent {
+					table.Set(id, User{UserId: id, Name: "renamed"})
+				} else {
+					table.Get(id)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return time.Since(start) / time.Duration(perGoroutine*goroutines)
+}
+
+func lookupTableBenchmarkExample() {
+	ids := make([]string, 10_000)
+	for i := range ids {
+		ids[i] = "user-" + strconv.Itoa(i)
+	}
+	fmt.Println("GOMAXPROCS:", runtime.GOMAXPROCS(0), "| ns per Get or Set, all goroutines together:")
+	fmt.Println("(go test -bench LookupTable basics/main measures the same, more carefully)")
+	fmt.Printf("%-11s %10s %13s %10s %12s\n", "writes", "goroutines", "map+RWMutex", "sync.Map", "shardmap(32)")
+	for _, writePercent := range []int{1, 10, 50} {
+		for _, goroutines := range []int{1, 16} {
+			rw := timeLookups(&rwMutexTable{m: map[string]User{}}, ids, writePercent, goroutines)
+			sm := timeLookups(&syncMapTable{}, ids, writePercent, goroutines)
+			sh := timeLookups(shardmap.New[string, User](32), ids, writePercent, goroutines)
+			fmt.Printf("%-11s %10d %13d %10d %12d\n", strconv.Itoa(writePercent)+"%", goroutines, rw.Nanoseconds(), sm.Nanoseconds(), sh.Nanoseconds())
+		}
+	}
+	// On one core only one goroutine runs at a time, so no lock is ever contended: this measures the cost
+	// of each structure itself, and map+RWMutex - the least work per operation - does well. The locks start
+	// to matter with many cores: the RWMutex's reader count is one cache line every core writes to, even for
+	// RLock, so it stops scaling at a handful of cores; shards spread that over 32 lines; sync.Map reads take
+	// no lock at all, but its writes cost the most of the three. Measure on the machine it runs on
+}
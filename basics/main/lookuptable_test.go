@@ -0,0 +1,97 @@
+package main
+
+import (
+	"basics/shardmap"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func lookupTables() []struct {
+	name  string
+	table func() userLookup
+} {
+	return []struct {
+		name  string
+		table func() userLookup
+	}{
+		{"rwmutex", func() userLookup { return &rwMutexTable{m: map[string]User{}} }},
+		{"syncmap", func() userLookup { return &syncMapTable{} }},
+		{"shardmap", func() userLookup { return shardmap.New[string, User](32) }},
+	}
+}
+
+func TestLookupTables(t *testing.T) {
+	for _, lt := range lookupTables() {
+		table := lt.table()
+		if _, ok := table.Get(userId1); ok {
+			t.Errorf("%s: Get on an empty table found %s", lt.name, userId1)
+		}
+		table.Set(userId1, User{UserId: userId1, Name: "John Doe"})
+		table.Set(userId1, User{UserId: userId1, Name: "John Q. Doe"})
+		if u, ok := table.Get(userId1); !ok || u.Name != "John Q. Doe" {
+			t.Errorf("%s: Get = %v, %t", lt.name, u, ok)
+		}
+
+		// Writers and readers at once: under -race, any unguarded access is reported
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Go(func() {
+				for i := range 500 {
+					id := "user-" + strconv.Itoa(i%50)
+					if i%5 == g%5 {
+						table.Set(id, User{UserId: id, Name: "user " + strconv.Itoa(g)})
+					} else {
+						table.Get(id)
+					}
+				}
+			})
+		}
+		wg.Wait()
+		for i := range 50 {
+			if u, ok := table.Get("user-" + strconv.Itoa(i)); !ok || u.UserId != "user-"+strconv.Itoa(i) {
+				t.Errorf("%s: user-%d = %v, %t", lt.name, i, u, ok)
+			}
+		}
+	}
+}
+
+// BenchmarkLookupTable runs random Gets and Sets over 10000 stored ids, some percent of them Sets, on 1 or
+// 16 goroutines. go test -bench LookupTable basics/main - the comment at lookupTableBenchmarkExample has
+// what to expect
+func BenchmarkLookupTable(b *testing.B) {
+	ids := make([]string, 10_000)
+	for i := range ids {
+		ids[i] = "user-" + strconv.Itoa(i)
+	}
+	for _, writePercent := range []int{1, 10, 50} {
+		for _, goroutines := range []int{1, 16} {
+			for _, lt := range lookupTables() {
+				b.Run(fmt.Sprintf("writes=%d%%/goroutines=%d/%s", writePercent, goroutines, lt.name), func(b *testing.B) {
+					table := lt.table()
+					for _, id := range ids {
+						table.Set(id, User{UserId: id, Name: "user " + id})
+					}
+					var seed atomic.Uint64
+					// RunParallel starts SetParallelism x GOMAXPROCS goroutines
+					b.SetParallelism(max(1, goroutines/runtime.GOMAXPROCS(0)))
+					b.RunParallel(func(pb *testing.PB) {
+						rng := rand.New(rand.NewPCG(seed.Add(1), 0))
+						for pb.Next() {
+							id := ids[rng.IntN(len(ids))]
+							if rng.IntN(100) < writePercent {
+								table.Set(id, User{UserId: id, Name: "renamed"})
+							} else {
+								table.Get(id)
+							}
+						}
+					})
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"basics/mapsx"
+	"errors"
+	"fmt"
+)
+
+// Ex. Reverse lookups (inverting a map)
+
+// A map only looks up one way, key -> value. Going the other way (which word has this definition?)
+// means either a linear search over every entry, or building a second map with the keys and values swapped.
+// The catch: keys are unique, values aren't - two words with the same definition collide on one key
+// of the inverted map, so something has to decide which word is kept (see basics/mapsx)
+func reverseLookupExample() {
+	dictionary := map[string]string{
+		"apple":      "round, edible fruit of an apple tree",
+		"orange":     "a round juicy citrus fruit with a tough bright reddish-yellow rind",
+		"car":        "a road vehicle with an engine and four wheels",
+		"automobile": "a road vehicle with an engine and four wheels", // a synonym - same value as "car"
+	}
+	const vehicle = "a road vehicle with an engine and four wheels"
+
+	// An error on any duplicate - the safe default when the values should be unique
+	_, err := mapsx.Invert(dictionary, mapsx.ErrorOnDuplicate)
+	fmt.Println("invert, error on duplicates:", err)
+	fmt.Println("  is ErrDuplicate:", errors.Is(err, mapsx.ErrDuplicate))
+
+	// Keep one of the words. KeepMin gives the same word on every run, whatever order the map is ranged in
+	byDefinition, _ := mapsx.Invert(dictionary, mapsx.KeepMin)
+	fmt.Println("invert, keep the smallest word:", byDefinition[vehicle])
+
+	// Or keep all of them
+	allWords := mapsx.InvertAll(dictionary)
+	fmt.Println("invert, keep every word:", allWords[vehicle])
+
+	// --- Entries / FromEntries ---
+
+	// Entries flattens a map into a slice (sorted here, so it prints the same every run)
+	for _, e := range mapsx.SortedEntries(map[int]string{3: "three", 1: "one", 2: "two"}) {
+		fmt.Printf("%d=%s ", e.Key, e.Value)
+	}
+	fmt.Println()
+
+	// A slice has an order, so "first" and "last" mean something when building a map from one
+	updates := []mapsx.Entry[string, string]{
+		{Key: "apple", Value: "first definition"},
+		{Key: "pear", Value: "a sweet fruit, narrow at the top"},
+		{Key: "apple", Value: "second definition"},
+	}
+	first, _ := mapsx.FromEntries(updates, mapsx.KeepFirst)
+	last, _ := mapsx.FromEntries(updates, mapsx.KeepLast)
+	_, err = mapsx.FromEntries(updates, mapsx.ErrorOnDuplicate)
+	fmt.Println("keep first:", first["apple"], "| keep last:", last["apple"], "| error:", err)
+}
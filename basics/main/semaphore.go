@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ex. Counting semaphore (a buffered channel)
+
+// A semaphore lets at most n goroutines into a section of code at once (ex. at most 3 uploads in flight,
+// so the storage backend isn't flooded). A buffered channel of capacity n is one already:
+// - acquire = send: succeeds while fewer than n values are in the buffer, blocks once it is full
+// - release = receive: frees one slot, and one blocked sender (if any) gets in
+// The values themselves carry nothing, so the element type is struct{} (zero size)
+
+type Semaphore chan struct{}
+
+// NewSemaphore panics if n < 1: an unbuffered channel would block every Acquire forever
+func NewSemaphore(n int) Semaphore {
+	if n < 1 {
+		panic("NewSemaphore: n must be at least 1")
+	}
+	return make(Semaphore, n)
+}
+
+func (s Semaphore) Acquire() { s <- struct{}{} }
+
+func (s Semaphore) Release() { <-s }
+
+// TryAcquire takes a slot only if one is free right now
+func (s Semaphore) TryAcquire() bool {
+	select {
+	case s <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// UploadAll uploads every file through HandleFileUpload, in parallel, with at most limit uploads at a time.
+// The errors are returned in the order of files - nil for each one stored - and each upload logs to log
+func UploadAll(log *slog.Logger, store FileStore, files []string, limit int) []error {
+	sem := NewSemaphore(limit)
+	errs := make([]error, len(files))
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Go(func() {
+			sem.Acquire()
+			defer sem.Release() // deferred, so the slot is freed even if the upload panics
+			errs[i] = HandleFileUpload(log, store, file)
+		})
+	}
+	wg.Wait()
+	return errs
+}
+
+// slowStore takes a while per file, and records the highest number of Store calls running at once
+type slowStore struct {
+	delay    time.Duration
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+	inner    *InMemoryStore
+}
+
+func (s *slowStore) Store(file string) error {
+	now := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	// raise maxSeen to now, unless another goroutine already raised it higher (compare-and-swap loop)
+	for {
+		seen := s.maxSeen.Load()
+		if now <= seen || s.maxSeen.CompareAndSwap(seen, now) {
+			break
+		}
+	}
+	time.Sleep(s.delay)
+	return s.inner.Store(file)
+}
+
+var _ FileStore = (*slowStore)(nil)
+
+func semaphoreExample() {
+	files := make([]string, 20)
+	for i := range files {
+		files[i] = fmt.Sprintf("file-%02d.txt", i)
+	}
+	files[7] = "bad_file" // rejected by the in-memory store
+
+	const limit = 3
+	store := &slowStore{delay: 10 * time.Millisecond, inner: NewInMemoryStore()}
+	start := time.Now()
+	errs := UploadAll(discardLog, store, files, limit)
+	took := time.Since(start)
+
+	// semaphore_test.go checks the ceiling holds (and is reached) with go test
+	fmt.Printf("limit %d, most uploads at once: %d\n", limit, store.maxSeen.Load())
+	fmt.Println("file-00:", errs[0], "| bad_file:", errs[7])
+	fmt.Println("took about 20/3 rounds of 10ms:", took.Round(10*time.Millisecond))
+
+	// Without the semaphore, all 20 run at once
+	unbounded := &slowStore{delay: 10 * time.Millisecond, inner: NewInMemoryStore()}
+	UploadAll(discardLog, unbounded, files, len(files))
+	fmt.Println("limit 20, most uploads at once:", unbounded.maxSeen.Load())
+
+	// TryAcquire - fail fast instead of waiting (ex. reply "busy, try again" instead of queueing)
+	sem := NewSemaphore(2)
+	fmt.Println("try acquire x3:", sem.TryAcquire(), sem.TryAcquire(), sem.TryAcquire())
+	sem.Release()
+	fmt.Println("after one release:", sem.TryAcquire())
+}
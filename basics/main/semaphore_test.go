@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUploadAllRespectsLimit(t *testing.T) {
+	files := make([]string, 12)
+	for i := range files {
+		files[i] = fmt.Sprintf("file-%02d.txt", i)
+	}
+	files[5] = "bad_file"
+
+	for _, limit := range []int{1, 3, len(files)} {
+		store := &slowStore{delay: 5 * time.Millisecond, inner: NewInMemoryStore()}
+		errs := UploadAll(discardLog, store, files, limit)
+		// never above the limit, and reached - otherwise the check could pass with no parallelism at all
+		if got := store.maxSeen.Load(); got != int32(limit) {
+			t.Errorf("limit %d: at most %d uploads at once, want exactly %d", limit, got, limit)
+		}
+		for i, f := range files {
+			if i == 5 {
+				if errs[i] == nil {
+					t.Errorf("limit %d: bad_file was accepted", limit)
+				}
+				continue
+			}
+			if errs[i] != nil || !store.inner.Has(f) {
+				t.Errorf("limit %d: %s: err %v, stored %t", limit, f, errs[i], store.inner.Has(f))
+			}
+		}
+	}
+}
+
+func TestTryAcquire(t *testing.T) {
+	sem := NewSemaphore(2)
+	if !sem.TryAcquire() || !sem.TryAcquire() {
+		t.Fatal("TryAcquire failed with free slots")
+	}
+	if sem.TryAcquire() {
+		t.Error("TryAcquire succeeded with every slot taken")
+	}
+	sem.Release()
+	if !sem.TryAcquire() {
+		t.Error("TryAcquire failed after a Release")
+	}
+}
+
+func TestNewSemaphoreRejectsZero(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewSemaphore(%d) did not panic", n)
+				}
+			}()
+			NewSemaphore(n)
+		}()
+	}
+}
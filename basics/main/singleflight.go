@@ -0,0 +1,116 @@
+package main
+
+import (
+	"basics/singleflight"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Ex. Deduplicating concurrent lookups (see basics/singleflight)
+
+var errUserNotFound = errors.New("user not found")
+
+// slowUserDB is the userLookupTable from mapExample behind a slow "query", counting how often it's queried
+type slowUserDB struct {
+	users   map[string]User
+	latency time.Duration
+	queries atomic.Int64
+}
+
+func (db *slowUserDB) fetch(id string) (User, error) {
+	db.queries.Add(1)
+	time.Sleep(db.latency)
+	u, ok := db.users[id]
+	if !ok {
+		return User{}, fmt.Errorf("%w: %s", errUserNotFound, id)
+	}
+	return u, nil
+}
+
+// userService looks users up through a singleflight.Group, so a burst of requests for one user is one query
+type userService struct {
+	db    *slowUserDB
+	group singleflight.Group[string, User]
+}
+
+func (s *userService) user(id string) (User, bool, error) {
+	return s.group.Do(id, func() (User, error) { return s.db.fetch(id) })
+}
+
+// lookupsAtOnce starts n goroutines asking for id together, and returns how many got a shared result
+func lookupsAtOnce(s *userService, id string, n int) (shared int, errs []error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	start := make(chan struct{}) // released all at once, so the lookups overlap
+	for range n {
+		wg.Go(func() {
+			<-start
+			_, sh, err := s.user(id)
+			mu.Lock()
+			defer mu.Unlock()
+			if sh {
+				shared++
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+	return shared, errs
+}
+
+func singleflightExample() {
+	db := &slowUserDB{
+		users: map[string]User{
+			userId1: {UserId: userId1, Name: "John Doe"},
+			userId2: {UserId: userId2, Name: "Jack Eod"},
+		},
+		latency: 50 * time.Millisecond,
+	}
+	s := &userService{db: db}
+
+	// 100 goroutines, one user - one query
+	shared, errs := lookupsAtOnce(s, userId1, 100)
+	fmt.Println("100 concurrent lookups of one user - queries:", db.queries.Load(), "| results shared:", shared, "| errors:", len(errs))
+
+	// Not a cache: the call is forgotten once it returns, so a later lookup queries again
+	u, sh, err := s.user(userId1)
+	fmt.Printf("a later lookup: %v, shared: %t, err: %v | queries: %d\n", u, sh, err, db.queries.Load())
+
+	// Different keys don't wait for each other
+	db.queries.Store(0)
+	var wg sync.WaitGroup
+	for _, id := range []string{userId1, userId2, userId1, userId2} {
+		wg.Go(func() { s.user(id) })
+	}
+	wg.Wait()
+	fmt.Println("lookups of 2 different users at once - queries:", db.queries.Load())
+
+	// An error is shared like a value: every waiter gets it
+	db.queries.Store(0)
+	_, errs = lookupsAtOnce(s, "no-such-user", 10)
+	fmt.Println("10 lookups of a missing user - queries:", db.queries.Load(), "| all got errUserNotFound:",
+		len(errs) == 10 && errors.Is(errs[0], errUserNotFound))
+
+	// A panic in the function reaches the caller that ran it - the others get ErrPanicked instead of waiting forever
+	var panicky singleflight.Group[string, int]
+	var waiterErr error
+	leaderStarted := make(chan struct{})
+	wg.Go(func() {
+		defer func() { fmt.Println("leader recovered:", recover()) }()
+		panicky.Do("k", func() (int, error) {
+			close(leaderStarted)
+			time.Sleep(20 * time.Millisecond) // give the waiter time to join
+			panic("lookup failed badly")
+		})
+	})
+	<-leaderStarted
+	wg.Go(func() { _, _, waiterErr = panicky.Do("k", func() (int, error) { return 1, nil }) })
+	wg.Wait()
+	fmt.Println("waiter got:", waiterErr, "| is ErrPanicked:", errors.Is(waiterErr, singleflight.ErrPanicked))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testUserDB() *slowUserDB {
+	return &slowUserDB{
+		users:   map[string]User{userId1: {UserId: userId1, Name: "John Doe"}},
+		latency: 50 * time.Millisecond,
+	}
+}
+
+// 100 goroutines asking for the same userLookupTable entry: one query
+func TestUserServiceOneQuery(t *testing.T) {
+	db := testUserDB()
+	s := &userService{db: db}
+	shared, errs := lookupsAtOnce(s, userId1, 100)
+	if db.queries.Load() != 1 || shared != 100 || len(errs) != 0 {
+		t.Errorf("queries %d, shared %d, errors %v, want 1 query shared by all 100", db.queries.Load(), shared, errs)
+	}
+	// the next lookup, after the first returned, queries again
+	if u, _, err := s.user(userId1); u.Name != "John Doe" || err != nil || db.queries.Load() != 2 {
+		t.Errorf("later lookup: %v, %v, queries %d, want John Doe after a second query", u, err, db.queries.Load())
+	}
+}
+
+func TestUserServiceMissingUser(t *testing.T) {
+	db := testUserDB()
+	_, errs := lookupsAtOnce(&userService{db: db}, "no-such-user", 10)
+	if db.queries.Load() != 1 || len(errs) != 10 {
+		t.Fatalf("queries %d, errors %d, want 1 query and 10 errors", db.queries.Load(), len(errs))
+	}
+	for _, err := range errs {
+		if !errors.Is(err, errUserNotFound) {
+			t.Errorf("err = %v, want errUserNotFound", err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"basics/sliceutil"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Ex. The loops from rangeForLoopEx, with generic helpers (see basics/sliceutil)
+
+func sliceutilExample() {
+	pow := []int{1, 2, 4, 8, 16, 32, 64, 128}
+	records := []User{
+		{UserId: userId1, Name: "John Doe"},
+		{UserId: userId2, Name: "Jack Eod"},
+	}
+
+	// for i, v := range pow { fmt.Printf("2**%d = %d\n", i, v) } - but building the lines instead of printing them
+	// Map passes no index, so the exponent comes from the value (the position of its one bit)
+	lines := sliceutil.Map(pow, func(v int) string { return fmt.Sprintf("2**%d = %d", bits.TrailingZeros(uint(v)), v) })
+	fmt.Println(strings.Join(lines, ", "))
+
+	// for _, value := range records { fmt.Println(value.Name) } - the names, as a slice
+	names := sliceutil.Map(records, func(u User) string { return u.Name })
+	fmt.Println("names:", names)
+
+	// The loops that would come next: a sum, a selection, a search
+	total := sliceutil.Reduce(pow, 0, func(acc, v int) int { return acc + v })
+	big := sliceutil.Filter(pow, func(v int) bool { return v > 10 })
+	jack := sliceutil.IndexFunc(records, func(u User) bool { return strings.HasPrefix(u.Name, "Jack") })
+	fmt.Println("sum:", total, "| over 10:", big, "| first Jack at:", jack)
+
+	isPowerOfTwo := func(v int) bool { return v > 0 && v&(v-1) == 0 }
+	fmt.Println("all powers of two:", sliceutil.All(pow, isPowerOfTwo), "| any over 100:", sliceutil.Any(pow, func(v int) bool { return v > 100 }))
+
+	// Reduce to a different type - here building the id -> user map from mapExample
+	byID := sliceutil.Reduce(records, map[string]User{}, func(m map[string]User, u User) map[string]User {
+		m[u.UserId] = u
+		return m
+	})
+	fmt.Println("lookup table:", byID[userId2].Name)
+
+	// sliceutil/sliceutil_test.go checks these against the hand-written loops, and on empty input
+}
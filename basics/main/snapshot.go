@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// --- Snapshot / restore ---
+
+// A stateful example (a store, a cache) can save what it holds and pick up where it left off later.
+// Snapshotter is the one interface for that, so the saving side doesn't need to know the concrete type:
+// anything that can turn its state into JSON and back implements it.
+//
+// Restore replaces the whole state - it doesn't merge - and leaves the old state in place if data is invalid.
+
+type Snapshotter interface {
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
+}
+
+var errSnapshotVersion = errors.New("unsupported snapshot version")
+
+// storeSnapshot is the JSON form of an InMemoryStore.
+// The version field lets a later format still read (or at least reject) older files.
+// Contents only has the files StoreFrom read (as base64, encoding/json's form for []byte);
+// a snapshot from before it existed has none, which reads back the same
+type storeSnapshot struct {
+	Version  int               `json:"version"`
+	Files    []string          `json:"files"`
+	Contents map[string][]byte `json:"contents,omitempty"`
+}
+
+const storeSnapshotVersion = 1
+
+func (s *InMemoryStore) Snapshot() ([]byte, error) {
+	s.mu.Lock()
+	files := slices.Sorted(maps.Keys(s.files)) // sorted, so the same state always gives the same bytes
+	contents := maps.Clone(s.contents)         // (encoding/json sorts map keys itself)
+	s.mu.Unlock()
+	return json.Marshal(storeSnapshot{Version: storeSnapshotVersion, Files: files, Contents: contents})
+}
+
+func (s *InMemoryStore) Restore(data []byte) error {
+	var snap storeSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("restoring store: %w", err)
+	}
+	if snap.Version != storeSnapshotVersion {
+		return fmt.Errorf("restoring store: version %d: %w", snap.Version, errSnapshotVersion)
+	}
+	// build the new map first, so nothing changes unless the whole snapshot is valid
+	files := make(map[string]bool, len(snap.Files))
+	for _, f := range snap.Files {
+		files[f] = true
+	}
+	for name := range snap.Contents {
+		if !files[name] {
+			return fmt.Errorf("restoring store: contents for %q, which isn't in files", name)
+		}
+	}
+	contents := snap.Contents
+	if contents == nil {
+		contents = make(map[string][]byte)
+	}
+	s.mu.Lock()
+	s.files, s.contents = files, contents
+	s.mu.Unlock()
+	return nil
+}
+
+var _ Snapshotter = (*InMemoryStore)(nil)
+
+// SaveSnapshot writes s's state to path. It writes a temporary file and renames it over path,
+// so a crash halfway through leaves the previous snapshot intact instead of half a file
+func SaveSnapshot(path string, s Snapshotter) error {
+	data, err := s.Snapshot()
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func LoadSnapshot(path string, s Snapshotter) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return s.Restore(data)
+}
+
+func snapshotExample() {
+	dir, err := os.MkdirTemp("", "snapshot")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "store.json")
+
+	store := NewInMemoryStore()
+	for _, f := range []string{"report.txt", "photo.png", "notes.md"} {
+		HandleFileUpload(discardLog, store, f)
+	}
+	fmt.Println("save:", SaveSnapshot(path, store))
+	data, _ := os.ReadFile(path)
+	fmt.Println("on disk:", string(data))
+
+	// Round trip: a fresh store restored from the file holds the same files (snapshot_test.go checks the rest)
+	restored := NewInMemoryStore()
+	fmt.Println("load:", LoadSnapshot(path, restored), "| restored has photo.png:", restored.Has("photo.png"))
+
+	// Restore replaces the state rather than adding to it
+	other := NewInMemoryStore()
+	other.Store("old.txt")
+	other.Restore(data)
+	fmt.Println("after restore - old.txt:", other.Has("old.txt"), "| notes.md:", other.Has("notes.md"))
+
+	// Invalid snapshots are rejected, and the store keeps what it had
+	for _, bad := range []string{`{"version": 1, "files": [`, `{"version": 2, "files": ["x"]}`} {
+		fmt.Printf("restore %-32s err: %v\n", bad, restored.Restore([]byte(bad)))
+	}
+
+	// A missing file is the os error, unchanged - a caller can treat it as "nothing saved yet"
+	err = LoadSnapshot(filepath.Join(dir, "missing.json"), NewInMemoryStore())
+	fmt.Println("no snapshot yet:", errors.Is(err, os.ErrNotExist))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func filledStore(t *testing.T) *InMemoryStore {
+	t.Helper()
+	s := NewInMemoryStore()
+	for _, f := range []string{"report.txt", "photo.png", "notes.md"} {
+		if err := s.Store(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.StoreFrom("upload.png", bytes.NewReader(testPNG)); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	store := filledStore(t)
+	if err := SaveSnapshot(path, store); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("the temporary file is still there: %v", err)
+	}
+
+	restored := NewInMemoryStore()
+	if err := LoadSnapshot(path, restored); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"report.txt", "photo.png", "notes.md", "upload.png"} {
+		if !restored.Has(f) {
+			t.Errorf("restored store lost %s", f)
+		}
+	}
+	if data, ok := restored.Contents("upload.png"); !ok || !bytes.Equal(data, testPNG) {
+		t.Errorf("restored contents of upload.png: %d bytes, %t; want the %d stored", len(data), ok, len(testPNG))
+	}
+	if _, ok := restored.Contents("report.txt"); ok {
+		t.Error("contents for a file stored by name only")
+	}
+
+	// the same state gives the same bytes, so a restored store snapshots to what was saved
+	saved, _ := os.ReadFile(path)
+	again, err := restored.Snapshot()
+	if err != nil || !bytes.Equal(again, saved) {
+		t.Errorf("snapshot of the restored store:\n%s\nwant\n%s", again, saved)
+	}
+	first, _ := store.Snapshot()
+	if second, _ := store.Snapshot(); !bytes.Equal(first, second) {
+		t.Error("two snapshots of one state differ")
+	}
+}
+
+func TestSnapshotEmptyStore(t *testing.T) {
+	data, err := NewInMemoryStore().Snapshot()
+	if err != nil || string(data) != `{"version":1,"files":null}` {
+		t.Errorf("empty store = %s, %v", data, err)
+	}
+	s := filledStore(t)
+	if err := s.Restore(data); err != nil || s.Has("report.txt") {
+		t.Errorf("restoring an empty snapshot: %v, report.txt still there: %t", err, s.Has("report.txt"))
+	}
+	// a snapshot without contents (as written before StoreFrom existed) reads, and StoreFrom still works after it
+	if err := s.Restore([]byte(`{"version":1,"files":["a"]}`)); err != nil || !s.Has("a") {
+		t.Fatalf("snapshot without contents: %v", err)
+	}
+	if err := s.StoreFrom("b", strings.NewReader("bee")); err != nil {
+		t.Errorf("StoreFrom after restoring: %v", err)
+	}
+}
+
+// Restore replaces the state, it doesn't merge into it
+func TestRestoreReplaces(t *testing.T) {
+	data, _ := filledStore(t).Snapshot()
+	other := NewInMemoryStore()
+	other.Store("old.txt")
+	other.StoreFrom("old.bin", strings.NewReader("old"))
+	if err := other.Restore(data); err != nil {
+		t.Fatal(err)
+	}
+	if other.Has("old.txt") || other.Has("old.bin") || !other.Has("notes.md") {
+		t.Errorf("after Restore: old.txt %t, old.bin %t, notes.md %t; want only notes.md", other.Has("old.txt"), other.Has("old.bin"), other.Has("notes.md"))
+	}
+	if _, ok := other.Contents("old.bin"); ok {
+		t.Error("old.bin's contents survived Restore")
+	}
+}
+
+// An invalid snapshot is rejected, and the store keeps everything it had
+func TestRestoreInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name, data string
+		is         error
+	}{
+		{"truncated", `{"version": 1, "files": [`, nil},
+		{"not an object", `["report.txt"]`, nil},
+		{"newer version", `{"version": 2, "files": ["x"]}`, errSnapshotVersion},
+		{"no version", `{"files": ["x"]}`, errSnapshotVersion},
+		{"contents for no file", `{"version": 1, "files": ["x"], "contents": {"y": "AA=="}}`, nil},
+		{"contents not base64", `{"version": 1, "files": ["x"], "contents": {"x": "!!"}}`, nil},
+	} {
+		s := filledStore(t)
+		err := s.Restore([]byte(tc.data))
+		if err == nil || tc.is != nil && !errors.Is(err, tc.is) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.is)
+		}
+		if !s.Has("report.txt") || s.Has("x") {
+			t.Errorf("%s: the store changed", tc.name)
+		}
+		if data, _ := s.Contents("upload.png"); !bytes.Equal(data, testPNG) {
+			t.Errorf("%s: the stored contents changed", tc.name)
+		}
+	}
+}
+
+// failingSnapshotter is a Snapshotter that always fails
+type failingSnapshotter struct{ err error }
+
+func (f failingSnapshotter) Snapshot() ([]byte, error) { return nil, f.err }
+func (f failingSnapshotter) Restore([]byte) error      { return f.err }
+
+func TestSaveLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+	if err := SaveSnapshot(path, filledStore(t)); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+
+	// a failed Snapshot writes nothing, so the previous file is untouched
+	errBroken := errors.New("broken")
+	if err := SaveSnapshot(path, failingSnapshotter{errBroken}); !errors.Is(err, errBroken) {
+		t.Errorf("SaveSnapshot = %v, want the Snapshot error", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, before) {
+		t.Error("a failed save changed the snapshot on disk")
+	}
+
+	if err := SaveSnapshot(filepath.Join(dir, "missing", "store.json"), NewInMemoryStore()); err == nil {
+		t.Error("saving into a missing directory: no error")
+	}
+	// a missing file is os.ErrNotExist, so a caller can tell "nothing saved yet"
+	if err := LoadSnapshot(filepath.Join(dir, "none.json"), NewInMemoryStore()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadSnapshot of a missing file = %v, want ErrNotExist", err)
+	}
+}
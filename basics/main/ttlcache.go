@@ -0,0 +1,98 @@
+package main
+
+import (
+	"basics/ttlcache"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Ex. A TTL cache in front of the slow user lookups (see basics/ttlcache)
+
+// cachedUserService remembers users for ttl, and merges concurrent misses through the singleflight userService
+type cachedUserService struct {
+	users *userService
+	cache *ttlcache.Cache[string, User]
+	ttl   time.Duration
+}
+
+func (s *cachedUserService) user(id string) (User, error) {
+	if u, ok := s.cache.Get(id); ok {
+		return u, nil
+	}
+	u, _, err := s.users.user(id)
+	if err != nil {
+		return User{}, err // errors aren't cached - the next lookup tries again
+	}
+	s.cache.Set(id, u, s.ttl)
+	return u, nil
+}
+
+func ttlCacheExample() {
+	goroutinesBefore := runtime.NumGoroutine()
+	db := &slowUserDB{
+		users:   map[string]User{userId1: {UserId: userId1, Name: "John Doe"}, userId2: {UserId: userId2, Name: "Jack Eod"}},
+		latency: 20 * time.Millisecond,
+	}
+	cache := ttlcache.New[string, User](10 * time.Millisecond)
+	s := &cachedUserService{users: &userService{db: db}, cache: cache, ttl: 50 * time.Millisecond}
+
+	// Hits until the TTL is up, then one more query
+	for range 5 {
+		s.user(userId1)
+	}
+	fmt.Println("5 lookups within the TTL - queries:", db.queries.Load())
+	time.Sleep(60 * time.Millisecond)
+	s.user(userId1)
+	fmt.Println("one more after the TTL - queries:", db.queries.Load(), "| expired entry queried again:", db.queries.Load() == 2)
+
+	// 100 goroutines at once: hits and Sets from all of them (go run -race finds nothing)
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Go(func() {
+			if i%2 == 0 {
+				s.user(userId2)
+			} else {
+				s.user(userId1)
+			}
+		})
+	}
+	wg.Wait()
+	fmt.Println("100 concurrent lookups of 2 cached users - queries:", db.queries.Load(), "| at most one per user:", db.queries.Load() <= 3)
+
+	// Per-entry TTL: a short one expires, a long one and one without a TTL stay
+	cache.Set("short", User{Name: "short"}, 5*time.Millisecond)
+	cache.Set("long", User{Name: "long"}, time.Hour)
+	cache.Set("forever", User{Name: "forever"}, 0)
+	time.Sleep(10 * time.Millisecond)
+	_, short := cache.Get("short")
+	_, long := cache.Get("long")
+	_, forever := cache.Get("forever")
+	fmt.Println("after 10ms - short:", short, "| long:", long, "| no TTL:", forever)
+
+	// Get skips an expired entry at once, the janitor removes it a little later - without anyone asking for it
+	cache.Delete("long")
+	cache.Set("gone soon", User{}, 5*time.Millisecond)
+	fmt.Println("entries:", cache.Len())
+	time.Sleep(70 * time.Millisecond) // the users from above expire too
+	fmt.Println("entries after the janitor ran:", cache.Len(), "| only the one without a TTL left:", cache.Len() == 1)
+
+	// Close stops the janitor - twice is fine - and the goroutine is gone
+	cache.Close()
+	cache.Close()
+	fmt.Println("janitor stopped, goroutines back to before:", runtime.NumGoroutine() == goroutinesBefore)
+	cache.Set("after close", User{}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok := cache.Get("after close")
+	fmt.Println("still usable after Close - expired entry is a miss:", !ok, "| but nothing removes it:", cache.Len() == 2)
+
+	// No cleanup interval: no janitor at all, as if closed from the start
+	manual := ttlcache.New[string, User](0)
+	manual.Set("a", User{}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = manual.Get("a")
+	fmt.Println("New(0) - expired entry is a miss:", !ok, "| kept until deleted:", manual.Len() == 1,
+		"| no goroutine:", runtime.NumGoroutine() == goroutinesBefore)
+	manual.Close() // returns at once
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"basics/files"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Ex. Errors of HandleFileUpload: sentinels, a wrapping error type, and matching with errors.Is / errors.As
+// (see the errorsdeep notes for the pieces on their own)
+
+// MaxUploadSize is the largest file HandleFileUpload accepts
+const MaxUploadSize = 1 << 20 // 1 MB
+
+// Sentinel errors: values to compare with errors.Is, through any wrapping
+var (
+	ErrFileTooLarge = errors.New("file too large")
+	ErrBadFile      = errors.New("bad file") // a store refusing a file for what it is
+)
+
+// StorageError is a store failing to store a file. It carries which backend it was, and wraps the
+// store's own error - so errors.Is still finds ErrBadFile, or fs.ErrExist, inside it
+type StorageError struct {
+	Backend string
+	File    string
+	Err     error
+}
+
+func (e *StorageError) Error() string {
+	return fmt.Sprintf("storing %s in %s: %v", filepath.Base(e.File), e.Backend, e.Err) // the store's error often has the full path
+}
+
+func (e *StorageError) Unwrap() error { return e.Err }
+
+// A store can name itself for StorageError by having a Backend method. It's a separate interface rather
+// than part of FileStore, so every store doesn't have to have one
+type namedStore interface {
+	Backend() string
+}
+
+func backendName(store FileStore) string {
+	if n, ok := store.(namedStore); ok {
+		return n.Backend()
+	}
+	return fmt.Sprintf("%T", store) // ex. "*main.InMemoryStore"
+}
+
+// UploadMessage is the text for the user about an upload's result - the caller's job, not
+// HandleFileUpload's: an API would pick a status code the same way, a CLI an exit code
+func UploadMessage(err error) string {
+	var storageErr *StorageError
+	switch {
+	case err == nil:
+		return "Successfully created"
+	case errors.Is(err, ErrFileTooLarge):
+		return fmt.Sprintf("The file is over the %d MB limit", MaxUploadSize>>20)
+	case errors.Is(err, ErrTooManyFiles):
+		return fmt.Sprintf("At most %d files can be sent at once", maxUploadFiles)
+	case errors.Is(err, ErrUnsupportedType):
+		return "Only text, PNG, JPEG and PDF files are accepted"
+	case errors.Is(err, ErrNoFile):
+		return "No file was sent"
+	case errors.Is(err, ErrBadFile), errors.Is(err, files.ErrBadName):
+		return "The file was not accepted"
+	case errors.Is(err, files.ErrExists):
+		return "A file with that name is already stored"
+	case errors.As(err, &storageErr):
+		return "The file could not be stored in " + storageErr.Backend + ", try again later"
+	}
+	return "An error occurred while attempting to create"
+}
+
+func uploadErrorsExample() {
+	dir, err := os.MkdirTemp("", "notes-uploads-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	big := filepath.Join(dir, "video.mp4")
+	os.WriteFile(big, make([]byte, MaxUploadSize+1), 0o644)
+
+	memory := NewInMemoryStore()
+	for _, tc := range []struct {
+		store FileStore
+		file  string
+	}{
+		{memory, "report.txt"},
+		{memory, big},
+		{memory, "bad_file"},
+		{FailingStore{Err: errors.New("database unavailable")}, "report.txt"},
+	} {
+		err := HandleFileUpload(discardLog, tc.store, tc.file)
+		fmt.Printf("%-10s %-45s | %v\n", filepath.Base(tc.file), UploadMessage(err), err)
+	}
+
+	// The checks a caller makes: each error is what it looks like, through the wrapping
+	errTooLarge := HandleFileUpload(discardLog, memory, big)
+	errBad := HandleFileUpload(discardLog, memory, "bad_file")
+	var storageErr *StorageError
+	fmt.Println("| ok: too large is ErrFileTooLarge, not a StorageError:",
+		errors.Is(errTooLarge, ErrFileTooLarge) && !errors.As(errTooLarge, &storageErr))
+	fmt.Println("| ok: bad_file is a StorageError and ErrBadFile at once:",
+		errors.As(errBad, &storageErr) && errors.Is(errBad, ErrBadFile), "- backend", storageErr.Backend)
+	fmt.Println("| ok: stored is nil:", HandleFileUpload(discardLog, memory, "notes.md") == nil, "| big file not stored:", !memory.Has(big))
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"basics/files"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Ex. HandleFileUpload over HTTP: multipart/form-data uploads, streamed into a FileStore
+
+// UploadHandler takes a POST of multipart/form-data - what a browser sends for <input type="file" name="file">
+// - and stores each "file" part. It reads the body part by part with r.MultipartReader, so a file goes from
+// the connection into the store without being held in memory whole (r.ParseMultipartForm would buffer it).
+// On the way, each file is:
+// - limited to MaxSize: reading past it is ErrFileTooLarge, and the store removes what it had
+// - sniffed: its first 512 bytes decide its type (http.DetectContentType), not the Content-Type the client
+//   sent, and a type that isn't in AllowedTypes is ErrUnsupportedType
+// - streamed into a store with StoreFrom (InMemoryStore, files.DiskStore), or else written to a temp file
+//   and passed to HandleFileUpload, which any FileStore takes
+//
+// Every error is a JSON response: its status is the UploadStatus, its message UploadMessage's, and the HTTP
+// status code says which kind it was (uploadHTTPStatus). Files stored before the one that failed stay
+// stored, and the response lists them.
+
+var (
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrNoFile          = errors.New("no file in the upload")
+	ErrTooManyFiles    = errors.New("too many files in the upload")
+)
+
+// streamStore is a FileStore that can store contents read from a stream, under a name, rather than
+// a file at a path. Optional, like namedStore: a store without it still works, through a temp file
+type streamStore interface {
+	StoreFrom(name string, r io.Reader) error
+}
+
+// AllowedUploadTypes are the types UploadHandler accepts by default, as http.DetectContentType names them
+var AllowedUploadTypes = []string{"text/plain; charset=utf-8", "image/png", "image/jpeg", "application/pdf"}
+
+// maxUploadFiles is how many files one request may carry - a file part past it is ErrTooManyFiles.
+// With MaxSize, it's also the bound on the whole body
+const maxUploadFiles = 10
+
+type UploadHandler struct {
+	log   *slog.Logger
+	store FileStore
+
+	MaxSize      int64    // the largest file accepted, in bytes
+	AllowedTypes []string // the content types accepted
+	TempDir      string   // where files for a store without StoreFrom are written first ("" is os.TempDir)
+}
+
+// NewUploadHandler stores uploads in store, with MaxUploadSize and AllowedUploadTypes as the limits
+func NewUploadHandler(log *slog.Logger, store FileStore) *UploadHandler {
+	return &UploadHandler{log: log, store: store, MaxSize: MaxUploadSize, AllowedTypes: AllowedUploadTypes}
+}
+
+type uploadedFile struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
+
+type uploadResponse struct {
+	Status string         `json:"status"`
+	Files  []uploadedFile `json:"files"`
+}
+
+type uploadErrorResponse struct {
+	Status  string         `json:"status"`
+	Message string         `json:"message"`
+	File    string         `json:"file,omitempty"`
+	Stored  []uploadedFile `json:"stored,omitempty"`
+}
+
+// uploadHTTPStatus is the HTTP status code for an upload's error
+func uploadHTTPStatus(err error) int {
+	var storageErr *StorageError
+	switch {
+	case errors.Is(err, ErrFileTooLarge), errors.Is(err, ErrTooManyFiles):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrNoFile):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrBadFile), errors.Is(err, files.ErrBadName):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, files.ErrExists):
+		return http.StatusConflict
+	case errors.As(err, &storageErr):
+		return http.StatusServiceUnavailable // the store is failing - worth trying again later
+	}
+	return http.StatusInternalServerError
+}
+
+func writeUploadJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (h *UploadHandler) fail(w http.ResponseWriter, file string, stored []uploadedFile, err error) {
+	h.log.Warn("upload failed", "file", file, "err", err)
+	writeUploadJSON(w, uploadHTTPStatus(err), uploadErrorResponse{
+		Status: UploadStatusOf(err).String(), Message: UploadMessage(err), File: file, Stored: stored,
+	})
+}
+
+func (h *UploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The whole body is limited too, so a client can't send an endless stream of parts
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadFiles*h.MaxSize+1<<20)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		h.fail(w, "", nil, fmt.Errorf("%w: %v", ErrNoFile, err)) // not multipart/form-data
+		return
+	}
+	var stored []uploadedFile
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.fail(w, "", stored, fmt.Errorf("%w: the request is over %d bytes", ErrFileTooLarge, maxErr.Limit))
+			return
+		}
+		if err != nil {
+			h.fail(w, "", stored, fmt.Errorf("%w: %v", ErrNoFile, err))
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close() // another form field - skipped
+			continue
+		}
+		if len(stored) == maxUploadFiles {
+			part.Close()
+			h.fail(w, part.FileName(), stored, fmt.Errorf("%w: the limit is %d", ErrTooManyFiles, maxUploadFiles))
+			return
+		}
+		f, err := h.receive(part)
+		part.Close()
+		if err != nil {
+			h.fail(w, part.FileName(), stored, err)
+			return
+		}
+		h.log.Info("stored upload", "file", f.Name, "size", f.Size, "type", f.ContentType)
+		stored = append(stored, f)
+	}
+	if len(stored) == 0 {
+		h.fail(w, "", nil, ErrNoFile)
+		return
+	}
+	writeUploadJSON(w, http.StatusCreated, uploadResponse{Status: UploadStored.String(), Files: stored})
+}
+
+// receive checks one file part and streams it into the store
+func (h *UploadHandler) receive(part *multipart.Part) (uploadedFile, error) {
+	// The name comes from the client. FileName already drops any directories ("../../etc/passwd" is
+	// "passwd"), and what's left must be a plain name before it's used in a path
+	name := part.FileName()
+	if !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) {
+		return uploadedFile{}, fmt.Errorf("%w: %q", files.ErrBadName, name)
+	}
+
+	head := make([]byte, 512) // all DetectContentType looks at
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return uploadedFile{}, err
+	}
+	head = head[:n]
+	ctype := http.DetectContentType(head)
+	if !slices.Contains(h.AllowedTypes, ctype) {
+		return uploadedFile{}, fmt.Errorf("%s: %w: %s", name, ErrUnsupportedType, ctype)
+	}
+
+	body := &sizeLimitReader{r: io.MultiReader(bytes.NewReader(head), part), left: h.MaxSize}
+	if ss, ok := h.store.(streamStore); ok {
+		err = ss.StoreFrom(name, body)
+	} else {
+		err = h.storeViaTempFile(name, body)
+	}
+	size := h.MaxSize - body.left
+	var storageErr *StorageError
+	switch {
+	case errors.Is(err, ErrFileTooLarge):
+		return uploadedFile{}, fmt.Errorf("%s: %w (the limit is %d bytes)", name, ErrFileTooLarge, h.MaxSize)
+	case err != nil && !errors.As(err, &storageErr):
+		err = &StorageError{Backend: backendName(h.store), File: name, Err: err}
+	}
+	if err != nil {
+		return uploadedFile{}, err
+	}
+	return uploadedFile{Name: name, Size: size, ContentType: ctype}, nil
+}
+
+// storeViaTempFile writes r to a temp file named name, and hands it to HandleFileUpload
+func (h *UploadHandler) storeViaTempFile(name string, r io.Reader) error {
+	dir, err := os.MkdirTemp(h.TempDir, "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return HandleFileUpload(h.log, h.store, path)
+}
+
+// sizeLimitReader reads up to left bytes from r, and fails with ErrFileTooLarge on the first byte past
+// them. (io.LimitReader stops there too, but with io.EOF - the file would look complete, only shorter)
+type sizeLimitReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1] // one byte past the limit is enough to know
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.left {
+		l.left = 0
+		return 0, ErrFileTooLarge
+	}
+	l.left -= int64(n)
+	return n, err
+}
+
+// multipartBody is a multipart/form-data body with a file part per name in parts, and one plain field -
+// what a browser's form would send. It returns the body and its Content-Type (with the boundary)
+func multipartBody(parts map[string][]byte, order ...string) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("description", "notes")
+	for _, name := range order {
+		fw, _ := mw.CreateFormFile("file", name)
+		fw.Write(parts[name])
+	}
+	mw.Close()
+	return &buf, mw.FormDataContentType()
+}
+
+func uploadHTTPExample() {
+	dir, err := os.MkdirTemp("", "notes-uploads-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	disk, _ := files.NewDiskStore(filepath.Join(dir, "stored"))
+	memory := NewInMemoryStore()
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /uploads/memory", NewUploadHandler(discardLog, memory))
+	mux.Handle("POST /uploads/disk", NewUploadHandler(discardLog, disk))
+	// mockStore has no StoreFrom: its uploads go through a temp file and HandleFileUpload
+	mock := &mockStore{}
+	viaTemp := NewUploadHandler(discardLog, mock)
+	viaTemp.TempDir = dir
+	mux.Handle("POST /uploads/mock", viaTemp)
+	mux.Handle("POST /uploads/down", NewUploadHandler(discardLog, FailingStore{Err: errors.New("database unavailable")}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+	elf := append([]byte("\x7fELF"), bytes.Repeat([]byte{1}, 100)...) // an executable - not an allowed type
+	post := func(path string, parts map[string][]byte, order ...string) (int, string) {
+		body, ctype := multipartBody(parts, order...)
+		res, err := http.Post(srv.URL+path, ctype, body)
+		if err != nil {
+			return 0, err.Error()
+		}
+		defer res.Body.Close()
+		out, _ := io.ReadAll(res.Body)
+		return res.StatusCode, strings.TrimSpace(string(out))
+	}
+
+	for _, tc := range []struct {
+		name, path string
+		parts      map[string][]byte
+		order      []string
+	}{
+		{"two files", "/uploads/memory", map[string][]byte{"notes.txt": []byte("slices and maps"), "logo.png": png}, []string{"notes.txt", "logo.png"}},
+		{"to disk", "/uploads/disk", map[string][]byte{"report.txt": []byte("quarterly numbers")}, []string{"report.txt"}},
+		{"again", "/uploads/disk", map[string][]byte{"report.txt": []byte("quarterly numbers")}, []string{"report.txt"}},
+		{"through a temp file", "/uploads/mock", map[string][]byte{"photo.png": png}, []string{"photo.png"}},
+		{"too large", "/uploads/disk", map[string][]byte{"big.txt": bytes.Repeat([]byte("a"), MaxUploadSize+1)}, []string{"big.txt"}},
+		{"wrong type", "/uploads/memory", map[string][]byte{"tool.txt": elf}, []string{"tool.txt"}},
+		{"bad_file", "/uploads/memory", map[string][]byte{"bad_file": []byte("x")}, []string{"bad_file"}},
+		{"a path as name", "/uploads/disk", map[string][]byte{"../../etc/passwd": []byte("x")}, []string{"../../etc/passwd"}},
+		{"hidden name", "/uploads/disk", map[string][]byte{".env": []byte("SECRET=1")}, []string{".env"}},
+		{"no file", "/uploads/memory", nil, nil},
+		{"store down", "/uploads/down", map[string][]byte{"a.txt": []byte("a")}, []string{"a.txt"}},
+		{"second fails", "/uploads/disk", map[string][]byte{"ok.txt": []byte("fine"), "tool.txt": elf}, []string{"ok.txt", "tool.txt"}},
+	} {
+		code, body := post(tc.path, tc.parts, tc.order...)
+		fmt.Printf("%-19s %d %s\n", tc.name, code, body)
+	}
+	res, _ := http.Get(srv.URL + "/uploads/memory")
+	res.Body.Close()
+	fmt.Println("GET:", res.StatusCode)
+
+	// What the stores hold afterwards (uploadhttp_test.go checks these, the limits, and concurrent uploads)
+	names, _ := disk.List()
+	fmt.Println("on disk:", names, "| through a temp file:", len(mock.calls), "Store call")
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"basics/files"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+var (
+	testPNG = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+	testELF = append([]byte("\x7fELF"), bytes.Repeat([]byte{1}, 100)...)
+)
+
+// upload POSTs a multipart body with a file part per name to h, and decodes the JSON response
+func upload(t *testing.T, h http.Handler, parts map[string][]byte, order ...string) (int, map[string]any) {
+	t.Helper()
+	body, ctype := multipartBody(parts, order...)
+	req := httptest.NewRequest(http.MethodPost, "/uploads", body)
+	req.Header.Set("Content-Type", ctype)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type %q, want application/json", got)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("response %q: %v", rec.Body, err)
+	}
+	return rec.Code, out
+}
+
+// storedNames are the names in a response's "files" (a success) or "stored" (a failure)
+func storedNames(resp map[string]any) []string {
+	list, _ := resp["files"].([]any)
+	if l, ok := resp["stored"].([]any); ok {
+		list = l
+	}
+	var names []string
+	for _, f := range list {
+		names = append(names, f.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func newDisk(t *testing.T) *files.DiskStore {
+	t.Helper()
+	disk, err := files.NewDiskStore(filepath.Join(t.TempDir(), "stored"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return disk
+}
+
+func TestUploadHandler(t *testing.T) {
+	memory := NewInMemoryStore()
+	disk := newDisk(t)
+	mk := func(store FileStore) *UploadHandler {
+		h := NewUploadHandler(discardLog, store)
+		h.MaxSize = 1 << 10
+		return h
+	}
+	memH, diskH := mk(memory), mk(disk)
+	downH := mk(FailingStore{Err: errors.New("database unavailable")})
+
+	tests := []struct {
+		name       string
+		h          http.Handler
+		parts      map[string][]byte
+		order      []string
+		wantCode   int
+		wantStatus string
+		wantStored []string
+	}{
+		{"two files", memH, map[string][]byte{"notes.txt": []byte("slices and maps"), "logo.png": testPNG}, []string{"notes.txt", "logo.png"},
+			http.StatusCreated, "Stored", []string{"notes.txt", "logo.png"}},
+		{"to disk", diskH, map[string][]byte{"report.txt": []byte("quarterly numbers")}, []string{"report.txt"},
+			http.StatusCreated, "Stored", []string{"report.txt"}},
+		{"again", diskH, map[string][]byte{"report.txt": []byte("other numbers")}, []string{"report.txt"},
+			http.StatusConflict, "Rejected", nil},
+		{"exactly MaxSize", diskH, map[string][]byte{"full.txt": bytes.Repeat([]byte("a"), 1<<10)}, []string{"full.txt"},
+			http.StatusCreated, "Stored", []string{"full.txt"}},
+		{"one byte over", diskH, map[string][]byte{"big.txt": bytes.Repeat([]byte("a"), 1<<10+1)}, []string{"big.txt"},
+			http.StatusRequestEntityTooLarge, "TooLarge", nil},
+		{"wrong type", memH, map[string][]byte{"tool.txt": testELF}, []string{"tool.txt"},
+			http.StatusUnsupportedMediaType, "Rejected", nil},
+		{"bad_file", memH, map[string][]byte{"bad_file": []byte("x")}, []string{"bad_file"},
+			http.StatusUnprocessableEntity, "Rejected", nil},
+		{"hidden name", diskH, map[string][]byte{".env": []byte("SECRET=1")}, []string{".env"},
+			http.StatusUnprocessableEntity, "Rejected", nil},
+		{"a path as name", diskH, map[string][]byte{"../../etc/passwd": []byte("x")}, []string{"../../etc/passwd"},
+			http.StatusCreated, "Stored", []string{"passwd"}},
+		{"no file", memH, nil, nil, http.StatusBadRequest, "Rejected", nil},
+		{"store down", downH, map[string][]byte{"a.txt": []byte("a")}, []string{"a.txt"},
+			http.StatusServiceUnavailable, "Failed", nil},
+		{"second fails", diskH, map[string][]byte{"ok.txt": []byte("fine"), "tool.txt": testELF}, []string{"ok.txt", "tool.txt"},
+			http.StatusUnsupportedMediaType, "Rejected", []string{"ok.txt"}},
+	}
+	for _, tt := range tests {
+		code, resp := upload(t, tt.h, tt.parts, tt.order...)
+		if code != tt.wantCode || resp["status"] != tt.wantStatus {
+			t.Errorf("%s: %d %v, want %d %s", tt.name, code, resp, tt.wantCode, tt.wantStatus)
+		}
+		if got := storedNames(resp); !slices.Equal(got, tt.wantStored) {
+			t.Errorf("%s: stored %q, want %q", tt.name, got, tt.wantStored)
+		}
+		if code != http.StatusCreated && resp["message"] == "" {
+			t.Errorf("%s: no message in %v", tt.name, resp)
+		}
+	}
+
+	// What the stores hold afterwards: the first report.txt, nothing of the big file or the rejected ones
+	if got, _ := memory.Contents("notes.txt"); string(got) != "slices and maps" {
+		t.Errorf("notes.txt in memory = %q", got)
+	}
+	if got, _ := memory.Contents("logo.png"); !bytes.Equal(got, testPNG) {
+		t.Error("logo.png in memory isn't what was sent")
+	}
+	if got, _ := os.ReadFile(filepath.Join(disk.Dir(), "report.txt")); string(got) != "quarterly numbers" {
+		t.Errorf("report.txt on disk = %q, want the first upload", got)
+	}
+	names, _ := disk.List()
+	if want := []string{"full.txt", "ok.txt", "passwd", "report.txt"}; !slices.Equal(names, want) {
+		t.Errorf("on disk: %q, want %q", names, want)
+	}
+	entries, _ := os.ReadDir(disk.Dir())
+	if len(entries) != len(names) {
+		t.Errorf("%d entries on disk, %d stored: partial copies left behind", len(entries), len(names))
+	}
+}
+
+func TestUploadHandlerSizes(t *testing.T) {
+	memory := NewInMemoryStore()
+	h := NewUploadHandler(discardLog, memory)
+	h.MaxSize = 1 << 10
+	code, resp := upload(t, h, map[string][]byte{"a.txt": bytes.Repeat([]byte("a"), 700)}, "a.txt")
+	if code != http.StatusCreated {
+		t.Fatalf("%d %v", code, resp)
+	}
+	f := resp["files"].([]any)[0].(map[string]any)
+	if f["size"] != 700.0 || f["content_type"] != "text/plain; charset=utf-8" {
+		t.Errorf("file %v, want size 700 and text/plain", f)
+	}
+	// Under 512 bytes: what's sniffed is all there is, and it's all stored
+	code, _ = upload(t, h, map[string][]byte{"tiny.txt": []byte("hi")}, "tiny.txt")
+	if got, _ := memory.Contents("tiny.txt"); code != http.StatusCreated || string(got) != "hi" {
+		t.Errorf("tiny.txt: %d, %q", code, got)
+	}
+}
+
+func TestUploadHandlerMaxFiles(t *testing.T) {
+	parts := map[string][]byte{}
+	var order []string
+	for i := range maxUploadFiles + 1 {
+		name := fmt.Sprintf("f%02d.txt", i)
+		parts[name] = []byte(name)
+		order = append(order, name)
+	}
+
+	// maxUploadFiles is fine
+	memory := NewInMemoryStore()
+	code, resp := upload(t, NewUploadHandler(discardLog, memory), parts, order[:maxUploadFiles]...)
+	if code != http.StatusCreated || len(storedNames(resp)) != maxUploadFiles {
+		t.Errorf("%d files: %d, %d stored", maxUploadFiles, code, len(storedNames(resp)))
+	}
+
+	// One more is 413, naming the file it stopped at, and the ones before it are stored
+	memory = NewInMemoryStore()
+	code, resp = upload(t, NewUploadHandler(discardLog, memory), parts, order...)
+	if code != http.StatusRequestEntityTooLarge || resp["status"] != "TooLarge" {
+		t.Errorf("%d files: %d %v, want 413 TooLarge", maxUploadFiles+1, code, resp)
+	}
+	if resp["file"] != order[maxUploadFiles] {
+		t.Errorf("failed at %v, want %s", resp["file"], order[maxUploadFiles])
+	}
+	if got := storedNames(resp); !slices.Equal(got, order[:maxUploadFiles]) {
+		t.Errorf("stored %q, want the first %d", got, maxUploadFiles)
+	}
+	if memory.Has(order[maxUploadFiles]) {
+		t.Error("the file over the limit was stored")
+	}
+}
+
+// The whole body is limited, not only each file: a stream of parts that never ends is cut off
+func TestUploadHandlerBodyLimit(t *testing.T) {
+	h := NewUploadHandler(discardLog, NewInMemoryStore())
+	h.MaxSize = 100
+	var buf strings.Builder
+	buf.WriteString("--b\r\nContent-Disposition: form-data; name=\"filler\"\r\n\r\n")
+	buf.WriteString(strings.Repeat("x", maxUploadFiles*100+1<<20+1))
+	buf.WriteString("\r\n--b--\r\n")
+	req := httptest.NewRequest(http.MethodPost, "/uploads", strings.NewReader(buf.String()))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=b")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("a body over the limit: %d %s, want 413", rec.Code, rec.Body)
+	}
+}
+
+func TestUploadHandlerNotMultipart(t *testing.T) {
+	h := NewUploadHandler(discardLog, NewInMemoryStore())
+	for _, ctype := range []string{"application/json", "text/plain", ""} {
+		req := httptest.NewRequest(http.MethodPost, "/uploads", strings.NewReader(`{"file":"a"}`))
+		req.Header.Set("Content-Type", ctype)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Content-Type %q: %d, want 400", ctype, rec.Code)
+		}
+	}
+}
+
+// A store without StoreFrom gets a temp file through HandleFileUpload, removed afterwards
+func TestUploadHandlerViaTempFile(t *testing.T) {
+	dir := t.TempDir()
+	mock := &mockStore{}
+	h := NewUploadHandler(discardLog, mock)
+	h.TempDir = dir
+	code, _ := upload(t, h, map[string][]byte{"photo.png": testPNG}, "photo.png")
+	if code != http.StatusCreated || len(mock.calls) != 1 || filepath.Base(mock.calls[0]) != "photo.png" {
+		t.Errorf("%d, calls %q, want one Store of a photo.png", code, mock.calls)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("temp files left: %v", entries)
+	}
+
+	mock = &mockStore{err: ErrBadFile}
+	h = NewUploadHandler(discardLog, mock)
+	h.TempDir = dir
+	if code, _ := upload(t, h, map[string][]byte{"photo.png": testPNG}, "photo.png"); code != http.StatusUnprocessableEntity {
+		t.Errorf("a store refusing the file: %d, want 422", code)
+	}
+}
+
+// Over a real server and mux: the method pattern answers GET with 405
+func TestUploadHandlerServer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("POST /uploads", NewUploadHandler(discardLog, NewInMemoryStore()))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	body, ctype := multipartBody(map[string][]byte{"a.txt": []byte("a")}, "a.txt")
+	res, err := http.Post(srv.URL+"/uploads", ctype, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		t.Errorf("POST: %d", res.StatusCode)
+	}
+	res, err = http.Get(srv.URL + "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET: %d, want 405", res.StatusCode)
+	}
+}
+
+// Many uploads of one name to a DiskStore at once: one is stored, whole, and every other is a 409.
+// Different names at once are all stored
+func TestUploadHandlerConcurrentDisk(t *testing.T) {
+	disk := newDisk(t)
+	h := NewUploadHandler(discardLog, disk)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	post := func(name string, data []byte) int {
+		body, ctype := multipartBody(map[string][]byte{name: data}, name)
+		res, err := http.Post(srv.URL, ctype, body)
+		if err != nil {
+			t.Error(err)
+			return 0
+		}
+		defer res.Body.Close()
+		io.Copy(io.Discard, res.Body)
+		return res.StatusCode
+	}
+
+	const n = 20
+	codes := make([]int, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			codes[i] = post("same.txt", bytes.Repeat([]byte{'a' + byte(i)}, 4096))
+		})
+	}
+	wg.Wait()
+	created, conflicts := 0, 0
+	for _, c := range codes {
+		switch c {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		}
+	}
+	if created != 1 || conflicts != n-1 {
+		t.Errorf("%d uploads of one name: %d created, %d conflicts (%v)", n, created, conflicts, codes)
+	}
+	data, _ := os.ReadFile(filepath.Join(disk.Dir(), "same.txt"))
+	if len(data) != 4096 || !bytes.Equal(data, bytes.Repeat(data[:1], 4096)) {
+		t.Errorf("same.txt is %d bytes, not one upload whole", len(data))
+	}
+
+	for i := range n {
+		wg.Go(func() {
+			if c := post(fmt.Sprintf("file%d.txt", i), []byte("contents")); c != http.StatusCreated {
+				t.Errorf("file%d.txt: %d", i, c)
+			}
+		})
+	}
+	wg.Wait()
+	names, _ := disk.List()
+	entries, _ := os.ReadDir(disk.Dir())
+	if len(names) != n+1 || len(entries) != n+1 {
+		t.Errorf("%d stored, %d entries, want %d of each - no partial copies", len(names), len(entries), n+1)
+	}
+}
+
+func TestSizeLimitReader(t *testing.T) {
+	tests := []struct {
+		size, limit int
+		tooLarge    bool
+	}{
+		{0, 10, false},
+		{10, 10, false},
+		{11, 10, true},
+		{100, 10, true},
+		{1, 0, true},
+		{0, 0, false},
+	}
+	for _, tt := range tests {
+		data := bytes.Repeat([]byte("a"), tt.size)
+		for _, r := range []io.Reader{bytes.NewReader(data), iotest.OneByteReader(bytes.NewReader(data))} {
+			got, err := io.ReadAll(&sizeLimitReader{r: r, left: int64(tt.limit)})
+			if tt.tooLarge != errors.Is(err, ErrFileTooLarge) {
+				t.Errorf("%d bytes, limit %d: err %v, want too large %t", tt.size, tt.limit, err, tt.tooLarge)
+			}
+			if !tt.tooLarge && (err != nil || len(got) != tt.size) {
+				t.Errorf("%d bytes, limit %d: read %d, %v", tt.size, tt.limit, len(got), err)
+			}
+			if len(got) > tt.limit {
+				t.Errorf("%d bytes, limit %d: read %d, past the limit", tt.size, tt.limit, len(got))
+			}
+		}
+	}
+}
+
+func TestUploadHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{ErrFileTooLarge, http.StatusRequestEntityTooLarge},
+		{ErrTooManyFiles, http.StatusRequestEntityTooLarge},
+		{ErrUnsupportedType, http.StatusUnsupportedMediaType},
+		{ErrNoFile, http.StatusBadRequest},
+		{ErrBadFile, http.StatusUnprocessableEntity},
+		{files.ErrBadName, http.StatusUnprocessableEntity},
+		{&StorageError{Backend: "disk", File: "a", Err: files.ErrExists}, http.StatusConflict},
+		{&StorageError{Backend: "disk", File: "a", Err: errors.New("disk full")}, http.StatusServiceUnavailable},
+		{errors.New("something else"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := uploadHTTPStatus(fmt.Errorf("upload: %w", tt.err)); got != tt.want {
+			t.Errorf("uploadHTTPStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"basics/files"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Ex. An enum whose String method is generated: UploadStatus, and the stringer tool
+// (methodsinterfaces.go writes a Stringer by hand; codegen has a generator of its own)
+
+// Go has no enum keyword: an enum is a defined integer type and a block of constants, numbered by iota.
+// Printing one gives the number - unless it has a String method, and writing that switch by hand for
+// every enum is the kind of code that goes stale when a constant is added.
+//
+// stringer (golang.org/x/tools/cmd/stringer) writes it from the constants. To regenerate
+// uploadstatus_string.go after changing them:
+//
+//	go install golang.org/x/tools/cmd/stringer@latest
+//	go generate ./...
+//
+// The generated file is committed, so building doesn't need stringer - only changing the constants does.
+// It guards itself: if a constant's value moves without regenerating, the file stops compiling
+// ("invalid argument: index 1 out of bounds"), instead of printing the wrong names.
+// In CI, `go generate ./... && git diff --exit-code` checks it's current.
+
+//go:generate stringer -type=UploadStatus -trimprefix=Upload
+
+// UploadStatus is where an upload ended up. The zero value is UploadPending - not tried yet
+type UploadStatus int
+
+const (
+	UploadPending  UploadStatus = iota
+	UploadStored                // HandleFileUpload returned nil
+	UploadTooLarge              // over MaxUploadSize
+	UploadRejected              // the file itself was refused (ErrBadFile, ErrUnsupportedType...)
+	UploadFailed                // any other error - ex. the store failing
+)
+
+// UploadStatusOf is the status for HandleFileUpload's error, matched the same way as UploadMessage
+func UploadStatusOf(err error) UploadStatus {
+	switch {
+	case err == nil:
+		return UploadStored
+	case errors.Is(err, ErrFileTooLarge), errors.Is(err, ErrTooManyFiles):
+		return UploadTooLarge
+	case errors.Is(err, ErrBadFile), errors.Is(err, ErrUnsupportedType), errors.Is(err, ErrNoFile),
+		errors.Is(err, files.ErrBadName), errors.Is(err, files.ErrExists):
+		return UploadRejected
+	}
+	return UploadFailed
+}
+
+func uploadStatusExample() {
+	dir, err := os.MkdirTemp("", "notes-uploads-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	big := filepath.Join(dir, "video.mp4")
+	os.WriteFile(big, make([]byte, MaxUploadSize+1), 0o644)
+
+	memory := NewInMemoryStore()
+	down := FailingStore{Err: errors.New("database unavailable")}
+	statuses := map[string]UploadStatus{
+		"report.txt": UploadStatusOf(HandleFileUpload(discardLog, memory, "report.txt")),
+		"video.mp4":  UploadStatusOf(HandleFileUpload(discardLog, memory, big)),
+		"bad_file":   UploadStatusOf(HandleFileUpload(discardLog, memory, "bad_file")),
+		"notes.md":   UploadStatusOf(HandleFileUpload(discardLog, down, "notes.md")),
+	}
+	// %v and Println use String; %d is still the number underneath
+	for _, file := range []string{"report.txt", "video.mp4", "bad_file", "notes.md", "queued.txt"} {
+		s := statuses[file] // queued.txt was never uploaded: the zero value
+		fmt.Printf("%-10s %-9v (%d)\n", file, s, s)
+	}
+
+	// A value outside the constants prints as its number, not a wrong name or a panic
+	// (uploadstatus_test.go checks every name, and the statuses above)
+	fmt.Println("out of range:", UploadStatus(9), UploadStatus(-1))
+	fmt.Println("printed in a map too:", statuses)
+}
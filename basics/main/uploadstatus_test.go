@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"basics/files"
+)
+
+// The generated String, against the names it should produce: if a constant is added without
+// running go generate, its name is missing here
+func TestUploadStatusString(t *testing.T) {
+	tests := []struct {
+		s    UploadStatus
+		want string
+	}{
+		{UploadPending, "Pending"},
+		{UploadStored, "Stored"},
+		{UploadTooLarge, "TooLarge"},
+		{UploadRejected, "Rejected"},
+		{UploadFailed, "Failed"},
+		{UploadFailed + 1, "UploadStatus(5)"},
+		{-1, "UploadStatus(-1)"},
+		{9, "UploadStatus(9)"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("UploadStatus(%d).String() = %q, want %q", int(tt.s), got, tt.want)
+		}
+	}
+	var zero UploadStatus
+	if zero != UploadPending {
+		t.Errorf("the zero value is %v, want Pending", zero)
+	}
+	// fmt uses String; %d doesn't
+	if got := fmt.Sprintf("%v %d %s", UploadTooLarge, UploadTooLarge, UploadTooLarge); got != "TooLarge 2 TooLarge" {
+		t.Errorf("formatted: %q", got)
+	}
+}
+
+func TestUploadStatusOf(t *testing.T) {
+	dir := t.TempDir()
+	big := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(big, make([]byte, MaxUploadSize+1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	memory := NewInMemoryStore()
+	down := FailingStore{Err: errors.New("database unavailable")}
+	tests := []struct {
+		name string
+		err  error
+		want UploadStatus
+	}{
+		{"stored", HandleFileUpload(discardLog, memory, "report.txt"), UploadStored},
+		{"too large", HandleFileUpload(discardLog, memory, big), UploadTooLarge},
+		{"bad file", HandleFileUpload(discardLog, memory, "bad_file"), UploadRejected},
+		{"store down", HandleFileUpload(discardLog, down, "notes.md"), UploadFailed},
+		{"too many files", fmt.Errorf("upload: %w", ErrTooManyFiles), UploadTooLarge},
+		{"unsupported type", fmt.Errorf("upload: %w", ErrUnsupportedType), UploadRejected},
+		{"no file", ErrNoFile, UploadRejected},
+		{"bad name", &StorageError{Backend: "disk", File: "..", Err: files.ErrBadName}, UploadRejected},
+		{"exists", &StorageError{Backend: "disk", File: "a", Err: files.ErrExists}, UploadRejected},
+	}
+	for _, tt := range tests {
+		if got := UploadStatusOf(tt.err); got != tt.want {
+			t.Errorf("%s: UploadStatusOf(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package mapsx
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Helpers for turning a map into a list of (key, value) pairs and back, and for inverting a map
+// (value -> key, ex. a reverse lookup). The standard maps package has Keys, Values and Collect (iterators),
+// but nothing that decides what happens when two entries end up on the same key.
+//
+// That decision is a collision policy, passed in as a function:
+//
+//	resolve(key, old, new) (value to keep, error)
+//
+// Order matters for the policy: a slice of entries has an order, so KeepFirst / KeepLast are well defined
+// for FromEntries. Ranging over a map does NOT (random on every run), so Invert only makes sense with
+// a policy that gives the same answer in any order - KeepMin, KeepMax, or ErrorOnDuplicate
+// (which fails whenever there is a duplicate, though the pair it names may vary, see its docs).
+
+// ErrDuplicate is returned (wrapped) by ErrorOnDuplicate
+var ErrDuplicate = errors.New("duplicate key")
+
+// Entry is one key/value pair of a map
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Entries returns the pairs of m, in no particular order (the map's iteration order)
+func Entries[M ~map[K]V, K comparable, V any](m M) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, Entry[K, V]{k, v})
+	}
+	return entries
+}
+
+// SortedEntries returns the pairs of m sorted by key, so the result is the same on every run
+func SortedEntries[M ~map[K]V, K cmp.Ordered, V any](m M) []Entry[K, V] {
+	entries := Entries(m)
+	slices.SortFunc(entries, func(a, b Entry[K, V]) int { return cmp.Compare(a.Key, b.Key) })
+	return entries
+}
+
+// FromEntries builds a map from pairs. When a key repeats, resolve picks the value to keep
+// (or returns an error, which stops the build)
+func FromEntries[K comparable, V any](entries []Entry[K, V], resolve func(key K, old, new V) (V, error)) (map[K]V, error) {
+	m := make(map[K]V, len(entries))
+	for _, e := range entries {
+		if old, ok := m[e.Key]; ok {
+			v, err := resolve(e.Key, old, e.Value)
+			if err != nil {
+				return nil, err
+			}
+			e.Value = v
+		}
+		m[e.Key] = e.Value
+	}
+	return m, nil
+}
+
+// Invert swaps keys and values. Several keys with the same value collide on one key of the result,
+// and resolve picks which of them to keep
+func Invert[M ~map[K]V, K, V comparable](m M, resolve func(value V, old, new K) (K, error)) (map[V]K, error) {
+	inverted := make(map[V]K, len(m))
+	for k, v := range m {
+		if old, ok := inverted[v]; ok {
+			kept, err := resolve(v, old, k)
+			if err != nil {
+				return nil, err
+			}
+			k = kept
+		}
+		inverted[v] = k
+	}
+	return inverted, nil
+}
+
+// InvertAll keeps every key instead of picking one: each value maps to all of its keys, sorted
+func InvertAll[M ~map[K]V, K cmp.Ordered, V comparable](m M) map[V][]K {
+	inverted := make(map[V][]K)
+	for k, v := range m {
+		inverted[v] = append(inverted[v], k)
+	}
+	for _, keys := range inverted {
+		slices.Sort(keys)
+	}
+	return inverted
+}
+
+// --- Collision policies ---
+
+// Generic functions, so they can be passed without type arguments: FromEntries(pairs, mapsx.KeepFirst)
+// (the type parameters are inferred from the parameter they are passed to)
+
+// KeepFirst keeps the value seen first. Order dependent - use with FromEntries
+func KeepFirst[K, V any](_ K, old, _ V) (V, error) { return old, nil }
+
+// KeepLast keeps the value seen last, like assigning m[k] = v in a loop. Order dependent - use with FromEntries
+func KeepLast[K, V any](_ K, _, new V) (V, error) { return new, nil }
+
+// KeepMin keeps the smaller value, in any order
+func KeepMin[K any, V cmp.Ordered](_ K, old, new V) (V, error) { return min(old, new), nil }
+
+// KeepMax keeps the larger value, in any order
+func KeepMax[K any, V cmp.Ordered](_ K, old, new V) (V, error) { return max(old, new), nil }
+
+// ErrorOnDuplicate fails on the first collision.
+// The two values are printed in sorted order, so when exactly two entries collide the message is the same in any order.
+// With three or more entries on one key, or several keys colliding, which pair is met first still depends
+// on the iteration order - check the error with errors.Is(err, ErrDuplicate), not by its text
+func ErrorOnDuplicate[K, V any](key K, old, new V) (V, error) {
+	var zero V
+	a, b := fmt.Sprint(old), fmt.Sprint(new)
+	if a > b {
+		a, b = b, a
+	}
+	return zero, fmt.Errorf("%w %v (for %s and %s)", ErrDuplicate, key, a, b)
+}
@@ -0,0 +1,117 @@
+package mapsx
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// 3 keys share one value, 2 share another, and one is unique
+var colors = map[string]string{
+	"cherry": "red", "apple": "red", "strawberry": "red",
+	"lime": "green", "kiwi": "green",
+	"banana": "yellow",
+}
+
+// Map iteration order changes from run to run, so repeat the order-independent checks
+const rounds = 50
+
+func TestInvertDuplicates(t *testing.T) {
+	for range rounds {
+		lo, err := Invert(colors, KeepMin)
+		if want := map[string]string{"red": "apple", "green": "kiwi", "yellow": "banana"}; err != nil || !maps.Equal(lo, want) {
+			t.Fatalf("KeepMin = %v, %v; want %v", lo, err, want)
+		}
+		hi, err := Invert(colors, KeepMax)
+		if want := map[string]string{"red": "strawberry", "green": "lime", "yellow": "banana"}; err != nil || !maps.Equal(hi, want) {
+			t.Fatalf("KeepMax = %v, %v; want %v", hi, err, want)
+		}
+		_, err = Invert(colors, ErrorOnDuplicate)
+		if !errors.Is(err, ErrDuplicate) {
+			t.Fatalf("ErrorOnDuplicate: %v, want ErrDuplicate", err)
+		}
+	}
+	all := InvertAll(colors)
+	want := map[string][]string{"red": {"apple", "cherry", "strawberry"}, "green": {"kiwi", "lime"}, "yellow": {"banana"}}
+	if !maps.EqualFunc(all, want, slices.Equal) {
+		t.Errorf("InvertAll = %v, want %v", all, want)
+	}
+}
+
+// Two keys on one value give the same message in any order. With more, the pair named varies -
+// but it's always two keys that really collide
+func TestErrorOnDuplicateMessage(t *testing.T) {
+	two := map[string]string{"car": "vehicle", "automobile": "vehicle", "apple": "fruit"}
+	const want = "duplicate key vehicle (for automobile and car)"
+	for range rounds {
+		if _, err := Invert(two, ErrorOnDuplicate); err == nil || err.Error() != want {
+			t.Fatalf("err = %v, want %q", err, want)
+		}
+	}
+
+	three := map[string]string{"a": "x", "b": "x", "c": "x"}
+	valid := []string{"(for a and b)", "(for a and c)", "(for b and c)"}
+	for range rounds {
+		_, err := Invert(three, ErrorOnDuplicate)
+		if err == nil || !slices.ContainsFunc(valid, func(pair string) bool { return strings.HasSuffix(err.Error(), pair) }) {
+			t.Fatalf("err = %v, want one of the pairs %v", err, valid)
+		}
+	}
+}
+
+// No duplicates: no error, and inverting twice gives the map back
+func TestInvertOneToOne(t *testing.T) {
+	m := map[string]int{"one": 1, "two": 2, "three": 3}
+	inv, err := Invert(m, ErrorOnDuplicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := Invert(inv, ErrorOnDuplicate)
+	if err != nil || !maps.Equal(back, m) {
+		t.Errorf("round trip = %v, %v; want %v", back, err, m)
+	}
+	if inv, err := Invert(map[string]int{}, ErrorOnDuplicate); err != nil || len(inv) != 0 {
+		t.Errorf("empty map: %v, %v", inv, err)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	m := map[int]string{3: "three", 1: "one", 2: "two"}
+	want := []Entry[int, string]{{1, "one"}, {2, "two"}, {3, "three"}}
+	if got := SortedEntries(m); !slices.Equal(got, want) {
+		t.Errorf("SortedEntries = %v, want %v", got, want)
+	}
+	got := Entries(m)
+	slices.SortFunc(got, func(a, b Entry[int, string]) int { return a.Key - b.Key })
+	if !slices.Equal(got, want) {
+		t.Errorf("Entries = %v, want %v in some order", got, want)
+	}
+	if back, err := FromEntries(Entries(m), ErrorOnDuplicate); err != nil || !maps.Equal(back, m) {
+		t.Errorf("FromEntries(Entries(m)) = %v, %v", back, err)
+	}
+}
+
+func TestFromEntriesPolicies(t *testing.T) {
+	updates := []Entry[string, int]{{"a", 2}, {"b", 1}, {"a", 5}, {"a", 1}}
+	for _, tc := range []struct {
+		name    string
+		resolve func(string, int, int) (int, error)
+		want    int
+	}{
+		{"KeepFirst", KeepFirst[string, int], 2},
+		{"KeepLast", KeepLast[string, int], 1},
+		{"KeepMin", KeepMin[string, int], 1},
+		{"KeepMax", KeepMax[string, int], 5},
+	} {
+		m, err := FromEntries(updates, tc.resolve)
+		if err != nil || m["a"] != tc.want || m["b"] != 1 || len(m) != 2 {
+			t.Errorf("%s: %v, %v; want a=%d, b=1", tc.name, m, err, tc.want)
+		}
+	}
+	m, err := FromEntries(updates, ErrorOnDuplicate)
+	if m != nil || !errors.Is(err, ErrDuplicate) || err.Error() != "duplicate key a (for 2 and 5)" {
+		t.Errorf("ErrorOnDuplicate: %v, %v; want no map and the first collision", m, err)
+	}
+}
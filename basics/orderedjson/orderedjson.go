@@ -0,0 +1,111 @@
+package orderedjson
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// encoding/json already sorts map keys, but it sorts them as STRINGS,
+// so a map[int]string comes out as {"1":..,"10":..,"2":..}.
+// Map sorts keys by their natural order instead (numbers numerically, strings lexically),
+// so map based example output is both deterministic and readable.
+
+// Key is the set of key types encoding/json can use as object keys
+// (strings and integers - float and struct keys are not allowed in JSON objects)
+type Key interface {
+	~string | ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Map is a normal map (it can be indexed, ranged over and passed to len)
+// with a custom MarshalJSON method.
+type Map[K Key, V any] map[K]V
+
+// MarshalJSON implements json.Marshaler, so json.Marshal, json.Encoder and
+// any value containing a Map all use it automatically.
+func (m Map[K, V]) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, cmp.Compare[K])
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := keyName(k)
+		if err != nil {
+			return nil, err
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		val, err := json.Marshal(m[k])
+		if err != nil {
+			return nil, fmt.Errorf("orderedjson: value for key %v: %w", k, err)
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// keyName is k as a JSON object key - always a string, so numbers are written as "10":
+//   - a key with a MarshalText method is written as its text (ex. a named string type with its own spelling);
+//     UnmarshalJSON's plain map reads it back with UnmarshalText
+//   - otherwise string kinds as they are, and integer kinds in base 10 with strconv
+//
+// Anything else is an error - Key allows no other kinds, so it would mean Key grew without this
+func keyName[K Key](k K) (string, error) {
+	if tm, ok := any(k).(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("orderedjson: key %v: %w", k, err)
+		}
+		return string(text), nil
+	}
+	v := reflect.ValueOf(k)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("orderedjson: unsupported key type %T", k)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Decoding has no ordering concerns, so it hands off to a plain map[K]V
+// (a different type with no UnmarshalJSON method - calling json.Unmarshal on *m would recurse forever)
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	var plain map[K]V
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return err
+	}
+	*m = plain
+	return nil
+}
+
+// MarshalIndent is json.MarshalIndent with two space indentation, for printing examples.
+func MarshalIndent(v any) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
@@ -0,0 +1,169 @@
+package orderedjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal(%v): %v", v, err)
+	}
+	return string(data)
+}
+
+func TestMarshalKeyOrder(t *testing.T) {
+	type name string
+	for _, tt := range []struct {
+		m    any
+		want string
+	}{
+		{Map[int, string]{10: "ten", 2: "two", 1: "one", -5: "minus five"}, `{"-5":"minus five","1":"one","2":"two","10":"ten"}`},
+		{Map[int64, int]{math.MaxInt64: 1, math.MinInt64: 2, 0: 3}, `{"-9223372036854775808":2,"0":3,"9223372036854775807":1}`},
+		{Map[uint8, bool]{255: true, 16: false, 3: true}, `{"3":true,"16":false,"255":true}`},
+		{Map[uint64, int]{math.MaxUint64: 1, 9: 2}, `{"9":2,"18446744073709551615":1}`},
+		{Map[string, int]{"b": 1, "a": 2, "B": 3, "": 4}, `{"":4,"B":3,"a":2,"b":1}`},
+		{Map[name, int]{"tour": 1, "go": 2}, `{"go":2,"tour":1}`},
+		{Map[string, int]{`say "hi"`: 1, "<a&b>": 2}, `{"\u003ca\u0026b\u003e":2,"say \"hi\"":1}`}, // escaped like any JSON string
+	} {
+		if got := marshal(t, tt.m); got != tt.want {
+			t.Errorf("%T: %s, want %s", tt.m, got, tt.want)
+		}
+	}
+}
+
+// encoding/json on the same map sorts the keys as strings - what Map is for
+func TestMarshalDiffersFromPlainMap(t *testing.T) {
+	m := map[int]string{1: "a", 2: "b", 10: "c"}
+	if got := marshal(t, m); got != `{"1":"a","10":"c","2":"b"}` {
+		t.Fatalf("encoding/json changed its key order: %s", got)
+	}
+	if got := marshal(t, Map[int, string](m)); got != `{"1":"a","2":"b","10":"c"}` {
+		t.Errorf("Map = %s", got)
+	}
+}
+
+func TestMarshalNilAndEmpty(t *testing.T) {
+	var nilMap Map[int, int]
+	if got := marshal(t, nilMap); got != "null" {
+		t.Errorf("nil Map = %s, want null", got)
+	}
+	if got := marshal(t, Map[int, int]{}); got != "{}" {
+		t.Errorf("empty Map = %s, want {}", got)
+	}
+	// as a field, and as a nil value inside another Map
+	type doc struct {
+		Counts Map[int, int]
+	}
+	if got := marshal(t, doc{}); got != `{"Counts":null}` {
+		t.Errorf("nil field = %s", got)
+	}
+	nested := Map[string, Map[int, string]]{"empty": {}, "none": nil, "some": {3: "c", 1: "a"}}
+	if got, want := marshal(t, nested), `{"empty":{},"none":null,"some":{"1":"a","3":"c"}}`; got != want {
+		t.Errorf("nested = %s, want %s", got, want)
+	}
+}
+
+// level is an int key with a name of its own. The names sort differently from the numbers,
+// and the keys come out in number order
+type level int
+
+var levelNames = []string{"quiet", "normal", "loud"}
+
+func (l level) MarshalText() ([]byte, error) {
+	if l < 0 || int(l) >= len(levelNames) {
+		return nil, fmt.Errorf("no level %d", int(l))
+	}
+	return []byte(levelNames[l]), nil
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	i := slices.Index(levelNames, string(text))
+	if i < 0 {
+		return fmt.Errorf("no level %q", text)
+	}
+	*l = level(i)
+	return nil
+}
+
+func TestTextMarshalerKeys(t *testing.T) {
+	m := Map[level, int]{2: 30, 0: 10, 1: 20}
+	data := marshal(t, m)
+	if want := `{"quiet":10,"normal":20,"loud":30}`; data != want {
+		t.Errorf("text keys = %s, want %s", data, want)
+	}
+	var back Map[level, int]
+	if err := json.Unmarshal([]byte(data), &back); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(back, m) {
+		t.Errorf("round trip = %v, want %v", back, m)
+	}
+
+	if _, err := json.Marshal(Map[level, int]{7: 1}); err == nil || !strings.Contains(err.Error(), "no level 7") {
+		t.Errorf("a key MarshalText rejects: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"shouting":1}`), &back); err == nil {
+		t.Error("a key UnmarshalText rejects: no error")
+	}
+}
+
+func TestMarshalValueError(t *testing.T) {
+	_, err := json.Marshal(Map[int, any]{1: "fine", 2: make(chan int)})
+	var typeErr *json.UnsupportedTypeError
+	if !errors.As(err, &typeErr) || !strings.Contains(err.Error(), "key 2") {
+		t.Errorf("err = %v, want the UnsupportedTypeError, naming the key", err)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	want := Map[int, string]{-5: "minus five", 1: "one", 2: "two", 10: "ten"}
+	var got Map[int, string]
+	if err := json.Unmarshal([]byte(marshal(t, want)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("round trip = %v, want %v", got, want)
+	}
+
+	// decoding replaces the map, it doesn't merge into the old one
+	got = Map[int, string]{99: "old"}
+	if err := json.Unmarshal([]byte(`{"1":"new"}`), &got); err != nil || !maps.Equal(got, Map[int, string]{1: "new"}) {
+		t.Errorf("into a non-empty Map: %v, %v", got, err)
+	}
+	if err := json.Unmarshal([]byte("null"), &got); err != nil || got != nil {
+		t.Errorf("null = %v, %v; want a nil Map", got, err)
+	}
+
+	// as a field
+	var doc struct{ Counts Map[uint8, int] }
+	if err := json.Unmarshal([]byte(`{"Counts":{"3":1,"255":2}}`), &doc); err != nil || !maps.Equal(doc.Counts, Map[uint8, int]{3: 1, 255: 2}) {
+		t.Errorf("field = %v, %v", doc.Counts, err)
+	}
+
+	for _, bad := range []string{`{"x":"not a number"}`, `{"256":"too big"}`, `[1,2]`, `{"1":2}`, `{`} {
+		var m Map[uint8, string]
+		if err := json.Unmarshal([]byte(bad), &m); err == nil {
+			t.Errorf("Unmarshal(%s) into Map[uint8, string]: no error", bad)
+		}
+	}
+}
+
+func TestMarshalIndent(t *testing.T) {
+	data, err := MarshalIndent(Map[int, Map[string, int]]{2: {"b": 1, "a": 2}, 1: nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"1\": null,\n  \"2\": {\n    \"a\": 2,\n    \"b\": 1\n  }\n}"
+	if string(data) != want {
+		t.Errorf("MarshalIndent =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,119 @@
+package shardmap
+
+import (
+	"hash/maphash"
+	"iter"
+	"sync"
+)
+
+// A map split into shards, each a plain map with its own RWMutex. Operations on keys in different shards
+// don't touch the same lock, so with N shards up to N writers can go at once - where one map behind one
+// Mutex lets a single goroutine in at a time.
+//
+// A key's shard is its hash (hash/maphash, as Go's own maps use - see the hashing notes) modulo the
+// shard count. The count is a power of two, so the modulo is a mask.
+//
+// What's given up for it: anything that needs every shard at once is no longer atomic. Len adds up the
+// shards one after another, and Range sees each shard as it is when it gets there - neither is a snapshot
+// of the whole map while writers are running. An update of two keys together needs a lock of its own.
+
+// shard is one map and its lock. The padding puts each shard on its own 64 byte cache line: without it, two
+// shards' locks could share a line, and every Lock on one would invalidate the other in other cores'
+// caches ("false sharing" - the memlayout notes have struct layout and padding)
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	_  [64 - 24 - 8]byte // RWMutex is 24 bytes, a map 8
+}
+
+// Map is a concurrency-safe map with per-shard locks. Make one with New
+type Map[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []shard[K, V]
+	mask   uint64
+}
+
+// New makes a Map with n shards, rounded up to a power of two (at least 1). A few times the number of
+// cores is plenty: more shards only cost memory
+func New[K comparable, V any](n int) *Map[K, V] {
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	m := &Map[K, V]{seed: maphash.MakeSeed(), shards: make([]shard[K, V], size), mask: uint64(size - 1)}
+	for i := range m.shards {
+		m.shards[i].m = make(map[K]V)
+	}
+	return m
+}
+
+func (m *Map[K, V]) shardFor(key K) *shard[K, V] {
+	return &m.shards[maphash.Comparable(m.seed, key)&m.mask]
+}
+
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	s := m.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+func (m *Map[K, V]) Set(key K, v V) {
+	s := m.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = v
+}
+
+func (m *Map[K, V]) Delete(key K) {
+	s := m.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// Update sets key to f of its current value (and whether there was one), under the shard's lock -
+// a read-modify-write no other goroutine can get in the middle of
+func (m *Map[K, V]) Update(key K, f func(v V, ok bool) V) V {
+	s := m.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.m[key]
+	v := f(old, ok)
+	s.m[key] = v
+	return v
+}
+
+// Len is the number of entries, counted one shard at a time
+func (m *Map[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// All yields every entry, a shard at a time, holding that shard's read lock meanwhile - so the loop
+// body mustn't Set or Delete on the same Map (it would wait for itself)
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := range m.shards {
+			s := &m.shards[i]
+			s.mu.RLock()
+			for k, v := range s.m {
+				if !yield(k, v) {
+					s.mu.RUnlock()
+					return
+				}
+			}
+			s.mu.RUnlock()
+		}
+	}
+}
+
+// Shards is the number of shards
+func (m *Map[K, V]) Shards() int { return len(m.shards) }
@@ -0,0 +1,94 @@
+package shardmap
+
+import (
+	"maps"
+	"strconv"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestNewRoundsUp(t *testing.T) {
+	for _, tt := range []struct{ n, shards int }{{-1, 1}, {0, 1}, {1, 1}, {2, 2}, {3, 4}, {10, 16}, {32, 32}, {33, 64}} {
+		if got := New[string, int](tt.n).Shards(); got != tt.shards {
+			t.Errorf("New(%d): %d shards, want %d", tt.n, got, tt.shards)
+		}
+	}
+}
+
+// Each shard is one cache line, so two shards' locks never share one
+func TestShardIsACacheLine(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("the padding is worked out for 64-bit platforms")
+	}
+	if size := unsafe.Sizeof(shard[string, int]{}); size != 64 {
+		t.Errorf("a shard is %d bytes, want 64", size)
+	}
+}
+
+func TestGetSetDelete(t *testing.T) {
+	m := New[string, int](4)
+	if _, ok := m.Get("a"); ok {
+		t.Error("Get on an empty map found a")
+	}
+	for i := range 100 {
+		m.Set(strconv.Itoa(i), i)
+	}
+	m.Set("7", 700)
+	if v, ok := m.Get("7"); !ok || v != 700 {
+		t.Errorf("Get(7) = %d, %t", v, ok)
+	}
+	m.Delete("7")
+	m.Delete("missing")
+	if _, ok := m.Get("7"); ok || m.Len() != 99 {
+		t.Errorf("after Delete: found %t, len %d", ok, m.Len())
+	}
+
+	// All yields every entry once, over all the shards
+	got := maps.Collect(m.All())
+	if len(got) != 99 || got["42"] != 42 {
+		t.Errorf("All: %d entries, 42 = %d", len(got), got["42"])
+	}
+	n := 0
+	for range m.All() {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("All didn't stop: %d", n)
+	}
+	// After a break, the shard's read lock was let go: a write doesn't wait forever
+	m.Set("after", 1)
+}
+
+// 8 goroutines counting logins at once: Update is a read-modify-write under one shard's lock,
+// so no increment is lost
+func TestUpdateConcurrent(t *testing.T) {
+	logins := New[string, int](16)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				logins.Update("user-"+strconv.Itoa(i%100), func(n int, _ bool) int { return n + 1 })
+			}
+		})
+	}
+	wg.Wait()
+	total := 0
+	for _, n := range logins.All() {
+		total += n
+	}
+	if total != 8000 || logins.Len() != 100 {
+		t.Errorf("%d logins over %d users, want 8000 over 100", total, logins.Len())
+	}
+	if v := logins.Update("new", func(n int, ok bool) int {
+		if ok {
+			t.Error("Update of a new key: ok = true")
+		}
+		return n + 1
+	}); v != 1 {
+		t.Errorf("Update of a new key = %d, want 1", v)
+	}
+}
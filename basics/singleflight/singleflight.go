@@ -0,0 +1,74 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+)
+
+// When many goroutines ask for the same thing at once (the same user, on a cache miss), they all do the same
+// slow work - a thundering herd on the database. A Group deduplicates them: the first caller for a key runs
+// the function, and everyone else asking for that key while it runs waits for, and shares, its result.
+//
+// It is not a cache. Once the call returns the key is forgotten, and the next Do runs the function again -
+// it only merges calls that overlap in time. (golang.org/x/sync/singleflight is the full version,
+// with DoChan and Forget - this is the core of it, with generic keys and values.)
+//
+// Everyone sharing a call gets the same value. If it's a pointer, map or slice they share that too,
+// so it must not be modified.
+
+// ErrPanicked is what the waiting callers get if the function panics. The panic itself continues
+// in the goroutine that ran it - the waiters can't be left waiting forever
+var ErrPanicked = errors.New("singleflight: function panicked")
+
+type call[V any] struct {
+	wg   sync.WaitGroup
+	val  V
+	err  error
+	dups int // callers waiting for this one - guarded by Group.mu
+}
+
+// Group is ready to use as its zero value
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V] // the calls running now
+}
+
+// Do runs fn, unless a call for key is already running - then it waits for that one and returns its result.
+// shared reports whether the result went to more than one caller
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, true, c.err
+	}
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	g.run(key, c, fn)
+	g.mu.Lock()
+	shared = c.dups > 0
+	g.mu.Unlock()
+	return c.val, shared, c.err
+}
+
+func (g *Group[K, V]) run(key K, c *call[V], fn func() (V, error)) {
+	returned := false
+	defer func() {
+		if !returned {
+			c.err = ErrPanicked
+		}
+		g.mu.Lock()
+		delete(g.calls, key) // from now on, Do for key starts a new call
+		g.mu.Unlock()
+		c.wg.Done() // wakes the waiters. After the delete - so a Do that still finds the call has a result coming
+	}()
+	c.val, c.err = fn()
+	returned = true
+}
@@ -0,0 +1,159 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitDups waits until n callers are waiting on key's running call
+func waitDups[K comparable, V any](t *testing.T, g *Group[K, V], key K, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		g.mu.Lock()
+		c := g.calls[key]
+		joined := c != nil && c.dups == n
+		g.mu.Unlock()
+		if joined {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d waiters never joined the call for %v", n, key)
+		}
+	}
+}
+
+// 100 callers at once, one underlying call - the fn is held until all 99 others are waiting on it,
+// so the overlap doesn't depend on timing
+func TestDoOneCall(t *testing.T) {
+	var g Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+	const n = 100
+	var wg sync.WaitGroup
+	var sharedCount atomic.Int32
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		if v, shared, err := g.Do("user-1", fn); v != 42 || !shared || err != nil {
+			t.Errorf("leader: %d, %t, %v, want 42, shared, nil", v, shared, err)
+		}
+	}()
+	for deadline := time.Now().Add(time.Second); calls.Load() == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	for range n - 1 {
+		wg.Go(func() {
+			v, shared, err := g.Do("user-1", fn)
+			if v != 42 || err != nil {
+				t.Errorf("waiter: %d, %v, want 42", v, err)
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+		})
+	}
+	waitDups(t, &g, "user-1", n-1)
+	close(release)
+	wg.Wait()
+	<-leaderDone
+	if calls.Load() != 1 {
+		t.Errorf("fn ran %d times for %d overlapping calls, want 1", calls.Load(), n)
+	}
+	if sharedCount.Load() != n-1 {
+		t.Errorf("%d waiters got shared = true, want %d", sharedCount.Load(), n-1)
+	}
+}
+
+// Not a cache: calls that don't overlap each run fn, and a call alone isn't shared
+func TestDoSequential(t *testing.T) {
+	var g Group[int, int]
+	calls := 0
+	for i := range 3 {
+		v, shared, err := g.Do(1, func() (int, error) { calls++; return calls, nil })
+		if v != i+1 || shared || err != nil {
+			t.Errorf("call %d: %d, %t, %v, want %d, not shared", i, v, shared, err, i+1)
+		}
+	}
+}
+
+func TestDoDifferentKeys(t *testing.T) {
+	var g Group[string, string]
+	// a held, b can still run - keys don't wait on each other
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.Do("a", func() (string, error) { <-release; return "a", nil })
+	}()
+	if v, _, _ := g.Do("b", func() (string, error) { return "b", nil }); v != "b" {
+		t.Errorf("b = %q", v)
+	}
+	close(release)
+	<-done
+}
+
+func TestDoSharesErrors(t *testing.T) {
+	var g Group[string, int]
+	errMissing := errors.New("missing")
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var leaderErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, leaderErr = g.Do("k", func() (int, error) { close(started); <-release; return 0, errMissing })
+	}()
+	<-started
+	var waiterErr error
+	waited := make(chan struct{})
+	go func() {
+		defer close(waited)
+		_, _, waiterErr = g.Do("k", func() (int, error) { return 1, nil })
+	}()
+	waitDups(t, &g, "k", 1)
+	close(release)
+	<-done
+	<-waited
+	if leaderErr != errMissing || waiterErr != errMissing {
+		t.Errorf("errors %v and %v, want errMissing for both", leaderErr, waiterErr)
+	}
+}
+
+// The panic stays with the caller that ran fn, the waiters get ErrPanicked, and the key is free again
+func TestDoPanic(t *testing.T) {
+	var g Group[string, int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+	recovered := make(chan any)
+	go func() {
+		defer func() { recovered <- recover() }()
+		g.Do("k", func() (int, error) { close(started); <-release; panic("boom") })
+	}()
+	<-started
+	var waiterErr error
+	waited := make(chan struct{})
+	go func() {
+		defer close(waited)
+		_, _, waiterErr = g.Do("k", func() (int, error) { return 1, nil })
+	}()
+	waitDups(t, &g, "k", 1)
+	close(release)
+	if r := <-recovered; r != "boom" {
+		t.Errorf("leader recovered %v, want boom", r)
+	}
+	<-waited
+	if !errors.Is(waiterErr, ErrPanicked) {
+		t.Errorf("waiter got %v, want ErrPanicked", waiterErr)
+	}
+	if v, _, err := g.Do("k", func() (int, error) { return 7, nil }); v != 7 || err != nil {
+		t.Errorf("after the panic: %d, %v, want a new call", v, err)
+	}
+}
@@ -0,0 +1,116 @@
+package sliceutil
+
+// Higher-order helpers for slices: each takes a function and applies it to every element,
+// so a loop that only transforms, selects or combines elements becomes one call that says which of those it does.
+//
+// The standard slices package already has IndexFunc and ContainsFunc (Any here), and generic iterators
+// can do the same lazily - these are the eager, slice in, slice out versions, small enough to read in one go.
+// Map needs two type parameters (T in, U out); Go methods can't have their own, which is why these are
+// functions and not methods on a slice type.
+//
+// Zip, Chunk and Window, at the end, take no function - they regroup the elements (pairs, batches, windows).
+
+// Map returns f applied to each element, in order
+func Map[T, U any](s []T, f func(T) U) []U {
+	out := make([]U, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+// Filter returns the elements keep returns true for, in order. s itself is left as it is
+// (unlike slices.DeleteFunc, which filters in place)
+func Filter[S ~[]E, E any](s S, keep func(E) bool) S {
+	var out S
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Reduce combines the elements left to right, starting from init: f(f(f(init, s[0]), s[1]), s[2])...
+// An empty slice gives init
+func Reduce[T, A any](s []T, init A, f func(acc A, v T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Any reports whether pred is true for at least one element - false for an empty slice
+func Any[T any](s []T, pred func(T) bool) bool {
+	return IndexFunc(s, pred) >= 0
+}
+
+// All reports whether pred is true for every element - true for an empty slice (there's no element it's false for)
+func All[T any](s []T, pred func(T) bool) bool {
+	for _, v := range s {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexFunc returns the index of the first element pred is true for, or -1 - Index from the generics notes,
+// with a function in place of ==, so T doesn't need to be comparable
+func IndexFunc[T any](s []T, pred func(T) bool) int {
+	for i, v := range s {
+		if pred(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Pair is one element from each of two slices, from Zip
+type Pair[T, U any] struct {
+	First  T
+	Second U
+}
+
+// Zip pairs up a[i] and b[i]. If the lengths differ, the extra elements of the longer one are left out
+func Zip[T, U any](a []T, b []U) []Pair[T, U] {
+	out := make([]Pair[T, U], min(len(a), len(b)))
+	for i := range out {
+		out[i] = Pair[T, U]{a[i], b[i]}
+	}
+	return out
+}
+
+// Chunk splits s into batches of size elements, the last one shorter if len(s) isn't a multiple of size.
+// The batches share s's backing array (no copying), with their capacity cut to their length -
+// so an append to one batch can't overwrite the start of the next. size must be at least 1.
+// (slices.Chunk is the iterator version)
+func Chunk[T any](s []T, size int) [][]T {
+	if size < 1 {
+		panic("sliceutil.Chunk: size must be at least 1")
+	}
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for start := 0; start < len(s); start += size {
+		end := min(start+size, len(s))
+		chunks = append(chunks, s[start:end:end])
+	}
+	return chunks
+}
+
+// Window returns every run of size consecutive elements, sliding one at a time:
+// Window([1 2 3 4], 2) is [[1 2] [2 3] [3 4]]. Fewer than size elements give no windows.
+// Like Chunk, the windows share s's backing array - and overlap each other, so don't modify them
+func Window[T any](s []T, size int) [][]T {
+	if size < 1 {
+		panic("sliceutil.Window: size must be at least 1")
+	}
+	if len(s) < size {
+		return nil
+	}
+	windows := make([][]T, 0, len(s)-size+1)
+	for start := 0; start+size <= len(s); start++ {
+		windows = append(windows, s[start:start+size:start+size])
+	}
+	return windows
+}
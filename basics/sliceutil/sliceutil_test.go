@@ -0,0 +1,179 @@
+package sliceutil
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+var pow = []int{1, 2, 4, 8, 16, 32, 64, 128}
+
+func isPowerOfTwo(v int) bool { return v > 0 && v&(v-1) == 0 }
+
+func TestMap(t *testing.T) {
+	got := Map(pow, strconv.Itoa)
+	if want := []string{"1", "2", "4", "8", "16", "32", "64", "128"}; !slices.Equal(got, want) {
+		t.Errorf("Map = %v, want %v", got, want)
+	}
+	if got := Map([]int(nil), strconv.Itoa); got == nil || len(got) != 0 {
+		t.Errorf("Map(nil) = %#v, want an empty, non-nil slice", got)
+	}
+}
+
+// Filter and Reduce give what the hand-written loops they replace give
+func TestFilterReduceMatchLoops(t *testing.T) {
+	loopTotal := 0
+	var loopBig []int
+	for _, v := range pow {
+		loopTotal += v
+		if v > 10 {
+			loopBig = append(loopBig, v)
+		}
+	}
+	if got := Reduce(pow, 0, func(acc, v int) int { return acc + v }); got != loopTotal {
+		t.Errorf("Reduce sum = %d, want %d", got, loopTotal)
+	}
+	if got := Filter(pow, func(v int) bool { return v > 10 }); !slices.Equal(got, loopBig) {
+		t.Errorf("Filter = %v, want %v", got, loopBig)
+	}
+	if !slices.Equal(pow, []int{1, 2, 4, 8, 16, 32, 64, 128}) {
+		t.Errorf("Filter changed its input: %v", pow)
+	}
+}
+
+// Reduce is left to right, and can build a value of another type
+func TestReduceOrder(t *testing.T) {
+	got := Reduce([]string{"a", "b", "c"}, "", func(acc, v string) string { return acc + v })
+	if got != "abc" {
+		t.Errorf("Reduce concat = %q, want abc", got)
+	}
+	byLen := Reduce([]string{"go", "is", "fun"}, map[int][]string{}, func(m map[int][]string, v string) map[int][]string {
+		m[len(v)] = append(m[len(v)], v)
+		return m
+	})
+	if !slices.Equal(byLen[2], []string{"go", "is"}) || !slices.Equal(byLen[3], []string{"fun"}) {
+		t.Errorf("Reduce into a map = %v", byLen)
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	over := func(n int) func(int) bool { return func(v int) bool { return v > n } }
+	for _, c := range []struct {
+		name      string
+		got, want any
+	}{
+		{"All powers of two", All(pow, isPowerOfTwo), true},
+		{"All over 1", All(pow, over(1)), false},
+		{"Any over 100", Any(pow, over(100)), true},
+		{"Any over 200", Any(pow, over(200)), false},
+		{"IndexFunc over 10", IndexFunc(pow, over(10)), 4},
+		{"IndexFunc over 200", IndexFunc(pow, over(200)), -1},
+		{"IndexFunc matches slices.IndexFunc", IndexFunc(pow, over(30)), slices.IndexFunc(pow, over(30))},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	var none []int
+	if Filter(none, isPowerOfTwo) != nil {
+		t.Error("Filter(nil) isn't nil")
+	}
+	if got := Reduce(none, 42, func(a, v int) int { return a + v }); got != 42 {
+		t.Errorf("Reduce(nil) = %d, want init (42)", got)
+	}
+	if Any(none, isPowerOfTwo) || !All(none, isPowerOfTwo) || IndexFunc(none, isPowerOfTwo) != -1 {
+		t.Error("empty slice: want Any false, All true, IndexFunc -1")
+	}
+}
+
+func TestZip(t *testing.T) {
+	got := Zip([]int{1, 2, 3}, []string{"a", "b"})
+	if want := []Pair[int, string]{{1, "a"}, {2, "b"}}; !slices.Equal(got, want) {
+		t.Errorf("Zip truncates to the shorter: %v, want %v", got, want)
+	}
+	if got := Zip([]int{}, []string{"a"}); len(got) != 0 {
+		t.Errorf("Zip with an empty slice: %v", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	for _, c := range []struct {
+		n, size int
+		lens    []int
+	}{
+		{7, 3, []int{3, 3, 1}},
+		{4, 2, []int{2, 2}},
+		{2, 5, []int{2}},
+		{0, 3, nil},
+	} {
+		s := make([]int, c.n)
+		for i := range s {
+			s[i] = i
+		}
+		chunks := Chunk(s, c.size)
+		if got := Map(chunks, func(c []int) int { return len(c) }); !slices.Equal(got, c.lens) {
+			t.Errorf("Chunk %d by %d: lengths %v, want %v", c.n, c.size, got, c.lens)
+		}
+		if !slices.Equal(slices.Concat(chunks...), s) {
+			t.Errorf("Chunk %d by %d: %v doesn't cover the slice once, in order", c.n, c.size, chunks)
+		}
+	}
+}
+
+// The chunks share the backing array, but an append to one copies instead of overwriting the next
+func TestChunkAppendLeavesTheNextAlone(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6}
+	chunks := Chunk(s, 3)
+	chunks[0] = append(chunks[0], 99)
+	if chunks[1][0] != 4 || s[3] != 4 {
+		t.Errorf("append to chunk 0 overwrote chunk 1: %v, s = %v", chunks, s)
+	}
+	chunks[1][0] = 40 // no copying: a write goes through to s
+	if s[3] != 40 {
+		t.Errorf("chunks don't share s's backing array: s = %v", s)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	for _, c := range []struct {
+		s    []int
+		size int
+		want [][]int
+	}{
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {2, 3}, {3, 4}}},
+		{[]int{1, 2, 3}, 1, [][]int{{1}, {2}, {3}}},
+		{[]int{1, 2}, 2, [][]int{{1, 2}}},
+		{[]int{1}, 2, nil},
+		{nil, 1, nil},
+	} {
+		got := Window(c.s, c.size)
+		if !slices.EqualFunc(got, c.want, slices.Equal) || (c.want == nil) != (got == nil) {
+			t.Errorf("Window(%v, %d) = %v, want %v", c.s, c.size, got, c.want)
+		}
+	}
+	ws := Window([]int{1, 2, 3}, 2)
+	if ws[0] = append(ws[0], 9); ws[1][1] != 3 {
+		t.Errorf("append to a window overwrote the next: %v", ws)
+	}
+}
+
+func TestChunkWindowRejectSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		for name, f := range map[string]func(){
+			"Chunk":  func() { Chunk([]int{1}, size) },
+			"Window": func() { Window([]int{1}, size) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s with size %d did not panic", name, size)
+					}
+				}()
+				f()
+			}()
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package ttlcache
+
+import (
+	"sync"
+	"time"
+)
+
+// A map that forgets: each entry is kept for its TTL (time to live), then it's gone.
+// Expired entries have to be removed by someone, or a cache of short-lived entries grows forever:
+// - Get checks the expiry itself, so an expired entry is a miss the moment its TTL is up
+// - a background goroutine, the janitor, wakes up on a time.Ticker and deletes everything that has expired -
+//   entries nobody asks for again are removed too
+//
+// The janitor keeps the Cache alive (it holds a reference), so a Cache that is no longer used
+// is never garbage collected while the janitor runs - Close stops it.
+// (singleflight merges calls that overlap in time; this keeps results around after them - they go together)
+
+type entry[V any] struct {
+	val     V
+	expires time.Time // the zero time: never expires
+}
+
+func (e entry[V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+type Cache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]entry[V]
+
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// New returns a Cache whose janitor removes expired entries every cleanupInterval, until Close.
+// cleanupInterval <= 0 starts no janitor (time.NewTicker would panic): expired entries are then
+// only skipped by Get, and removed by Delete or a Set of the same key - as after Close
+func New[K comparable, V any](cleanupInterval time.Duration) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items:   make(map[K]entry[V]),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if cleanupInterval <= 0 {
+		close(c.stopped) // nothing for Close to wait for
+		return c
+	}
+	go c.janitor(cleanupInterval)
+	return c
+}
+
+// Set stores v under key for ttl, replacing any entry (and TTL) that was there. ttl <= 0 never expires
+func (c *Cache[K, V]) Set(key K, v V, ttl time.Duration) {
+	e := entry[V]{val: v}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = e
+}
+
+// Get returns the value for key, and false if there's none or it has expired
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.items[key]
+	if !ok || e.expired(time.Now()) {
+		var zero V
+		return zero, false
+	}
+	return e.val, true
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Len is the number of entries stored, including expired ones the janitor hasn't removed yet
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// Close stops the janitor and waits for it to return. The cache still works afterwards, but expired entries
+// are only skipped by Get, no longer removed. Closing more than once is fine
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() { close(c.stop) })
+	<-c.stopped
+}
+
+func (c *Cache[K, V]) janitor(interval time.Duration) {
+	defer close(c.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.deleteExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *Cache[K, V]) deleteExpired() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.items {
+		if e.expired(now) {
+			delete(c.items, k) // deleting during range is allowed
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package bufiopkg
+
+import (
+	"bufio"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bufio puts a buffer between a program and a Reader or Writer, so many small reads or writes become a few
+// big ones (each Read or Write on a file or socket is a system call):
+// - bufio.Scanner: read by lines (or words, or anything a SplitFunc finds) - the simple way to read text
+// - bufio.Reader: ReadString, ReadBytes, ReadRune, Peek - reading up to a delimiter, with more control
+// - bufio.Writer: collects writes and passes them on when its buffer fills - or when Flush is called,
+//   which must happen at the end, or the last bufferful is never written
+
+// A SplitFunc is how a Scanner finds tokens. It's called with the unread data so far, and returns:
+// - advance: how many bytes to consume
+// - token: the token found, if any (nil: none yet)
+// - err: a non-nil error stops the scan (bufio.ErrFinalToken stops it after this token, without an error)
+// Returning 0, nil, nil asks for more data; atEOF says there's no more coming.
+
+// ScanIdentifiers is a SplitFunc for words made of letters, digits and _ - anything else separates them,
+// so "fmt.Println(x, y)" is fmt, Println, x, y. (bufio.ScanWords splits on spaces only: "fmt.Println(x," is one word.)
+func ScanIdentifiers(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	isPart := func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	start := 0
+	for i := 0; i < len(data); {
+		// A rune cut in two at the end of the data so far: wait for the rest, rather than misread it.
+		// Consuming start bytes (the separators skipped) is fine, the token so far is kept by not consuming it
+		if !atEOF && !utf8.FullRune(data[i:]) {
+			return start, nil, nil
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		switch {
+		case isPart(r):
+		case i == start: // a separator before the identifier
+			start += size
+		default: // the separator after it
+			return i, data[start:i], nil
+		}
+		i += size
+	}
+	// The data ran out: at EOF what's left is the last identifier, otherwise it may go on - ask for more
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+// CountLines counts the lines in r. maxLine is the longest line it accepts - a Scanner's default is 64 KB,
+// and a longer line is bufio.ErrTooLong
+func CountLines(r io.Reader, maxLine int) (int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, min(maxLine, 4096)), maxLine) // starts small, grows to maxLine if needed
+	n := 0
+	for sc.Scan() {
+		n++
+	}
+	return n, sc.Err() // nil at a clean EOF - Scan returning false isn't itself an error
+}
+
+// ReadRecords reads r as records ending in delim, without the delim. The last record needn't end in it:
+// ReadString returns the data before EOF together with io.EOF, so it has to be kept before stopping
+func ReadRecords(r io.Reader, delim byte) ([]string, error) {
+	br := bufio.NewReader(r)
+	var records []string
+	for {
+		s, err := br.ReadString(delim)
+		if len(s) > 0 {
+			if s[len(s)-1] == delim {
+				s = s[:len(s)-1]
+			}
+			records = append(records, s)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+}
+
+// WriteLines writes each line and a newline through a bufio.Writer, and flushes it. size is the buffer size
+func WriteLines(w io.Writer, size int, lines []string) error {
+	bw := bufio.NewWriterSize(w, size)
+	for _, l := range lines {
+		// After a failed write, the Writer keeps the error and every later call returns it,
+		// so checking once at the end (Flush's error) would be enough - checking here just stops sooner
+		if _, err := bw.WriteString(l); err != nil {
+			return err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,195 @@
+package bufiopkg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// scan runs a Scanner with split over r and returns its tokens
+func scan(r io.Reader, split bufio.SplitFunc) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(split)
+	var tokens []string
+	for sc.Scan() {
+		tokens = append(tokens, sc.Text())
+	}
+	return tokens, sc.Err()
+}
+
+const source = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello, 世界", len(os.Args))
+}
+`
+
+func TestScanIdentifiers(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{source, []string{"package", "main", "import", "fmt", "func", "main", "fmt", "Println", "Hello", "世界", "len", "os", "Args"}},
+		{"", nil},
+		{"  ,;  ", nil},
+		{"x", []string{"x"}},
+		{"__init__ a1 1a", []string{"__init__", "a1", "1a"}},
+		{"(a)(b)", []string{"a", "b"}},
+		{"Zoë,Ångström", []string{"Zoë", "Ångström"}},
+		{"a\xffb", []string{"a", "b"}}, // invalid UTF-8 is a separator, not part of a word
+		{"trailing ", []string{"trailing"}},
+	} {
+		// whole, one byte per Read (every multibyte rune arrives in pieces), and with the last data and EOF together
+		for name, r := range map[string]io.Reader{
+			"":                  strings.NewReader(tc.in),
+			", one byte a time": iotest.OneByteReader(strings.NewReader(tc.in)),
+			", data with EOF":   iotest.DataErrReader(strings.NewReader(tc.in)),
+		} {
+			got, err := scan(r, ScanIdentifiers)
+			if err != nil || !slices.Equal(got, tc.want) {
+				t.Errorf("%q%s: %q, %v - want %q", tc.in, name, got, err, tc.want)
+			}
+		}
+	}
+}
+
+// The Scanner's buffer starts small and grows: a token much longer than the initial buffer still comes out whole
+func TestScanIdentifiersLongToken(t *testing.T) {
+	long := strings.Repeat("世", 10_000)
+	got, err := scan(strings.NewReader("a "+long+" b"), ScanIdentifiers)
+	if err != nil || !slices.Equal(got, []string{"a", long, "b"}) {
+		t.Errorf("a 30 KB identifier: %d tokens, %v", len(got), err)
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\r\ntwo\n\nfour", 4},
+		{"\n\n\n", 3},
+	} {
+		if n, err := CountLines(strings.NewReader(tc.in), bufio.MaxScanTokenSize); n != tc.want || err != nil {
+			t.Errorf("CountLines(%q) = %d, %v, want %d", tc.in, n, err, tc.want)
+		}
+	}
+
+	long := strings.Repeat("x", 100_000) + "\nshort\n"
+	if _, err := CountLines(strings.NewReader(long), bufio.MaxScanTokenSize); !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("a 100 KB line with the default max: %v, want ErrTooLong", err)
+	}
+	if n, err := CountLines(strings.NewReader(long), 1<<20); n != 2 || err != nil {
+		t.Errorf("a 100 KB line with a 1 MB max: %d, %v, want 2 lines", n, err)
+	}
+
+	diskErr := errors.New("disk error")
+	n, err := CountLines(io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(diskErr)), 1024)
+	if n != 2 || !errors.Is(err, diskErr) {
+		t.Errorf("a read error after 2 lines: %d, %v", n, err)
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []string
+	}{
+		{"john;jack;jill", []string{"john", "jack", "jill"}}, // the last one needn't end in ;
+		{"a;;b;", []string{"a", "", "b"}},                    // an empty record, and a trailing ;
+		{";", []string{""}},
+		{"", nil},
+		{"no delimiter at all", []string{"no delimiter at all"}},
+	} {
+		for name, r := range map[string]io.Reader{
+			"":                  strings.NewReader(tc.in),
+			", one byte a time": iotest.OneByteReader(strings.NewReader(tc.in)),
+		} {
+			if got, err := ReadRecords(r, ';'); err != nil || !slices.Equal(got, tc.want) {
+				t.Errorf("ReadRecords(%q)%s = %q, %v, want %q", tc.in, name, got, err, tc.want)
+			}
+		}
+	}
+
+	// TimeoutReader fails its second Read: the first Read got everything, so what's returned is the records in it,
+	// the unfinished last one too, and the error
+	got, err := ReadRecords(iotest.TimeoutReader(strings.NewReader("first;second;third")), ';')
+	if !errors.Is(err, iotest.ErrTimeout) || !slices.Equal(got, []string{"first", "second", "third"}) {
+		t.Errorf("a failing reader: %q, %v", got, err)
+	}
+}
+
+// countingWriter counts the Write calls that reach it
+type countingWriter struct {
+	strings.Builder
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Builder.Write(p)
+}
+
+// failingWriter fails every write after the first n bytes - a full disk
+type failingWriter struct{ left int }
+
+var errDiskFull = errors.New("disk full")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.left {
+		n := w.left
+		w.left = 0
+		return n, errDiskFull
+	}
+	w.left -= len(p)
+	return len(p), nil
+}
+
+func TestWriteLines(t *testing.T) {
+	lines := make([]string, 1000)
+	var want strings.Builder
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+		fmt.Fprintln(&want, lines[i])
+	}
+	w := &countingWriter{}
+	if err := WriteLines(w, 4096, lines); err != nil {
+		t.Fatal(err)
+	}
+	if w.String() != want.String() {
+		t.Error("WriteLines output differs from one Fprintln per line")
+	}
+	if wantWrites := (want.Len() + 4095) / 4096; w.writes != wantWrites {
+		t.Errorf("%d writes for %d bytes through a 4 KB buffer, want %d", w.writes, want.Len(), wantWrites)
+	}
+
+	// no lines, and a last partial buffer - it still arrives, Flush happened
+	for _, tc := range []struct {
+		lines []string
+		want  string
+	}{{nil, ""}, {[]string{"short"}, "short\n"}} {
+		w := &countingWriter{}
+		if err := WriteLines(w, 4096, tc.lines); err != nil || w.String() != tc.want {
+			t.Errorf("WriteLines(%q) wrote %q, %v, want %q", tc.lines, w.String(), err, tc.want)
+		}
+	}
+}
+
+func TestWriteLinesError(t *testing.T) {
+	lines := slices.Repeat([]string{"0123456789"}, 1000)
+	for _, left := range []int{0, 5000, 10_999} { // failing early, mid-way, and on the Flush of the last bytes
+		if err := WriteLines(&failingWriter{left: left}, 4096, lines); !errors.Is(err, errDiskFull) {
+			t.Errorf("disk full after %d bytes: %v", left, err)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module bufiopkg
+
+go 1.25.0
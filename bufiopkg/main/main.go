@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"bufiopkg"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// === Ex. bufio: scanning, reading up to a delimiter, buffered writing ===
+
+// Every input here is a strings.Reader - the same io.Reader a file or a network connection is.
+// The readers below wrap one to misbehave, like testing/iotest's do in bufiopkg_test.go: oneByteReader returns
+// one byte per Read, so every token arrives in pieces - what a slow connection does, and the way to check
+// a SplitFunc handles it.
+
+const source = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello, 世界", len(os.Args))
+}
+`
+
+// oneByteReader returns at most one byte per Read
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return r.r.Read(p[:1])
+}
+
+// errReader fails every Read with err
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+var errTimeout = errors.New("timeout")
+
+// timeoutReader reads from r once, then fails every Read after that with errTimeout - a connection that stalls
+type timeoutReader struct {
+	r    io.Reader
+	read bool
+}
+
+func (r *timeoutReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, errTimeout
+	}
+	r.read = true
+	return r.r.Read(p)
+}
+
+// scan runs a Scanner with split over r and returns its tokens
+func scan(r io.Reader, split bufio.SplitFunc) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(split)
+	var tokens []string
+	for sc.Scan() {
+		tokens = append(tokens, sc.Text()) // Text copies; sc.Bytes() would be overwritten by the next Scan
+	}
+	return tokens, sc.Err()
+}
+
+func scannerExample() {
+	// Lines - the default split. The newline (and a \r before it) is dropped, and a last line
+	// without one is still a line
+	lines, _ := scan(strings.NewReader("one\r\ntwo\n\nfour"), bufio.ScanLines)
+	fmt.Printf("lines: %q\n", lines)
+
+	words, _ := scan(strings.NewReader(source), bufio.ScanWords)
+	idents, _ := scan(strings.NewReader(source), bufiopkg.ScanIdentifiers)
+	fmt.Printf("ScanWords: %q\n", words)
+	fmt.Printf("ScanIdentifiers: %q\n", idents)
+
+	oneByte, err := scan(&oneByteReader{strings.NewReader(source)}, bufiopkg.ScanIdentifiers)
+	fmt.Println("the same one byte at a time (世 and 界 arrive in 3 pieces each):", slices.Equal(oneByte, idents), err)
+	// bufiopkg_test.go checks ScanIdentifiers on the edge cases, whole and in pieces
+
+	// Long lines: past the Scanner's buffer (64 KB by default) Scan stops with ErrTooLong
+	long := strings.Repeat("x", 100_000) + "\nshort\n"
+	_, err = bufiopkg.CountLines(strings.NewReader(long), bufio.MaxScanTokenSize)
+	fmt.Println("a 100 KB line, default buffer:", err)
+	n, err := bufiopkg.CountLines(strings.NewReader(long), 1<<20)
+	fmt.Println("with a 1 MB max:", n, "lines, err:", err)
+
+	// A read error is sc.Err(), after the tokens read before it
+	failing := io.MultiReader(strings.NewReader("a\nb\n"), errReader{errors.New("disk error")})
+	got, err := scan(failing, bufio.ScanLines)
+	fmt.Println("lines before a read error:", got, "| err:", err)
+}
+
+func readStringExample() {
+	// ReadString includes the delimiter, and at the end returns what's left along with io.EOF
+	br := bufio.NewReader(strings.NewReader("GET /users HTTP/1.1\r\nHost: example.com\r\n\r\nbody without newline"))
+	for {
+		s, err := br.ReadString('\n')
+		fmt.Printf("  %q, err: %v\n", s, err)
+		if err != nil {
+			break
+		}
+	}
+
+	records, err := bufiopkg.ReadRecords(strings.NewReader("john;jack;jill"), ';')
+	fmt.Printf("split on ';': %q %v\n", records, err)
+	records, _ = bufiopkg.ReadRecords(&oneByteReader{strings.NewReader("a;;b;")}, ';')
+	fmt.Printf("one byte at a time, an empty record, a trailing ';': %q\n", records)
+	records, err = bufiopkg.ReadRecords(&timeoutReader{r: strings.NewReader("first;second;third")}, ';')
+	fmt.Printf("a reader that fails on its second Read: %q, err: %v\n", records, err)
+
+	// Peek looks ahead without consuming, ReadRune reads UTF-8 a character at a time
+	br = bufio.NewReader(strings.NewReader("世界!"))
+	head, _ := br.Peek(3)
+	r, size, _ := br.ReadRune()
+	fmt.Printf("Peek(3): %q (not consumed) | ReadRune: %c, %d bytes | buffered: %d\n", head, r, size, br.Buffered())
+}
+
+// countingWriter counts the Write calls that reach it - each would be a system call on a file or socket
+type countingWriter struct {
+	strings.Builder
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Builder.Write(p)
+}
+
+// failingWriter fails every write after the first n bytes - a full disk
+type failingWriter struct{ left int }
+
+var errDiskFull = errors.New("disk full")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.left {
+		n := w.left
+		w.left = 0
+		return n, errDiskFull
+	}
+	w.left -= len(p)
+	return len(p), nil
+}
+
+func writerExample() {
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+
+	direct := &countingWriter{}
+	for _, l := range lines {
+		fmt.Fprintln(direct, l)
+	}
+	buffered := &countingWriter{}
+	err := bufiopkg.WriteLines(buffered, 4096, lines)
+	fmt.Printf("1000 lines: %d writes directly, %d through a 4 KB bufio.Writer (%d bytes) | same output: %t, err: %v\n",
+		direct.writes, buffered.writes, buffered.Len(), direct.String() == buffered.String(), err)
+
+	// Forgetting Flush: the last partial buffer never arrives
+	w := &countingWriter{}
+	bw := bufio.NewWriterSize(w, 4096)
+	for _, l := range lines {
+		fmt.Fprintln(bw, l)
+	}
+	fmt.Printf("without Flush: %d of %d bytes written, %d still in the buffer (%d free)\n",
+		w.Len(), direct.Len(), bw.Buffered(), bw.Available())
+	bw.Flush()
+	fmt.Println("after Flush:", w.Len() == direct.Len())
+
+	// A write error is sticky: it comes back from the write that hit it, and every call after
+	err = bufiopkg.WriteLines(&failingWriter{left: 5000}, 4096, lines)
+	fmt.Println("disk full after 5000 bytes:", err)
+	bw = bufio.NewWriterSize(&failingWriter{left: 10}, 16)
+	bw.WriteString("more than sixteen bytes")
+	_, err = bw.WriteString("x")
+	fmt.Println("a later write:", err, "| Flush:", bw.Flush())
+}
+
+func main() {
+	scannerExample()
+	// readStringExample()
+	// writerExample()
+}
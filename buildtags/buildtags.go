@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// === Build constraints (build tags) ===
+
+// A build constraint is a line comment at the top of a file that decides whether the file is
+// part of the build. It must come before the package clause, followed by a blank line:
+//
+//	//go:build linux && amd64
+//
+//	package main
+//
+// The expression can use && || ! and parentheses, with these terms:
+// - an operating system (linux, darwin, windows, ...) or architecture (amd64, arm64, ...)
+// - unix - any unix-like OS (linux, darwin, the BSDs, ...)
+// - a Go version, ex. go1.23 (true for Go 1.23 and later)
+// - cgo, if cgo is enabled
+// - any custom tag passed with go build -tags tag1,tag2
+// (the older "// +build" syntax also exists, but gofmt now writes //go:build for you)
+
+// File names give an implicit constraint too:
+// name_linux.go, name_amd64.go and name_windows_arm64.go only build for that OS / architecture,
+// without a //go:build line. (name_test.go files are only built by go test)
+
+// This module has one function, userCacheDir, implemented in three files:
+// - cachedir_unix.go     //go:build unix
+// - cachedir_windows.go  (implicit: _windows suffix)
+// - cachedir_other.go    //go:build !unix && !windows  (portable fallback, ex. wasm or plan9)
+// Exactly one file is built for any platform, so the function is always defined exactly once.
+// A missing case is a compile error ("undefined: userCacheDir"), and an overlap is also a compile error ("redeclared").
+
+// And one custom tag:
+// - verbose_on.go   //go:build verbose
+// - verbose_off.go  //go:build !verbose
+// Try: go run -tags verbose .
+
+// See which files are built for a platform without building:
+//
+//	go list -f '{{.GoFiles}}' .
+//	GOOS=windows go list -f '{{.GoFiles}}' .
+//	GOOS=js GOARCH=wasm go list -f '{{.GoFiles}}' .
+
+// Runtime check vs build constraint:
+// runtime.GOOS is a constant, so `if runtime.GOOS == "windows"` works for small differences,
+// but BOTH branches still have to compile on every platform.
+// Code that calls OS specific APIs (ex. syscall functions that only exist on one OS) needs separate files.
+
+func buildTagExamples() {
+	fmt.Printf("built for %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Println("cache dir implementation:", cacheDirImpl)
+
+	dir, err := userCacheDir()
+	fmt.Println("user cache dir:", dir, "err:", err)
+
+	// The portable parts of the standard library already hide these differences
+	fmt.Printf("filepath.Separator: %q, filepath.ListSeparator: %q\n", filepath.Separator, filepath.ListSeparator)
+	fmt.Println("os.TempDir():", os.TempDir())
+	fmt.Println("joined path:", filepath.Join("notes", "buildtags", "buildtags.go"))
+
+	// (buildtags_test.go checks what holds on every platform, and which file each platform builds)
+
+	debugf("verbose logging is enabled (built with -tags verbose)")
+	fmt.Println("verbose build:", verbose)
+}
+
+func main() {
+	buildTagExamples()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// For every platform Go supports (a sample of each OS), exactly one cachedir file is built -
+// checked with go/build's own constraint matching, so this runs the same on any platform
+func TestExactlyOneCacheDirFile(t *testing.T) {
+	files := []string{"cachedir_unix.go", "cachedir_windows.go", "cachedir_other.go"}
+	for _, tc := range []struct{ goos, goarch, want string }{
+		{"linux", "amd64", "cachedir_unix.go"},
+		{"android", "arm64", "cachedir_unix.go"}, // android implies linux
+		{"darwin", "arm64", "cachedir_unix.go"},
+		{"ios", "arm64", "cachedir_unix.go"},
+		{"freebsd", "amd64", "cachedir_unix.go"},
+		{"openbsd", "arm64", "cachedir_unix.go"},
+		{"illumos", "amd64", "cachedir_unix.go"},
+		{"aix", "ppc64", "cachedir_unix.go"},
+		{"windows", "amd64", "cachedir_windows.go"},
+		{"windows", "arm64", "cachedir_windows.go"},
+		{"js", "wasm", "cachedir_other.go"},
+		{"wasip1", "wasm", "cachedir_other.go"},
+		{"plan9", "amd64", "cachedir_other.go"},
+	} {
+		ctx := build.Default
+		ctx.GOOS, ctx.GOARCH = tc.goos, tc.goarch
+		if got := matching(t, ctx, files); len(got) != 1 || got[0] != tc.want {
+			t.Errorf("%s/%s builds %v, want only %s", tc.goos, tc.goarch, got, tc.want)
+		}
+	}
+}
+
+func TestVerboseTag(t *testing.T) {
+	files := []string{"verbose_on.go", "verbose_off.go"}
+	for _, tc := range []struct {
+		tags []string
+		want string
+	}{
+		{nil, "verbose_off.go"},
+		{[]string{"verbose"}, "verbose_on.go"},
+		{[]string{"other", "verbose"}, "verbose_on.go"},
+	} {
+		ctx := build.Default
+		ctx.BuildTags = tc.tags
+		if got := matching(t, ctx, files); len(got) != 1 || got[0] != tc.want {
+			t.Errorf("tags %v build %v, want only %s", tc.tags, got, tc.want)
+		}
+	}
+}
+
+// matching is the files ctx would build, of the ones given
+func matching(t *testing.T, ctx build.Context, files []string) []string {
+	t.Helper()
+	var got []string
+	for _, f := range files {
+		ok, err := ctx.MatchFile(".", f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			got = append(got, f)
+		}
+	}
+	return got
+}
+
+// What holds for whichever file this platform built
+func TestUserCacheDir(t *testing.T) {
+	dir, err := userCacheDir()
+	if err != nil {
+		t.Skipf("no cache dir in this environment: %v", err)
+	}
+	if !filepath.IsAbs(dir) {
+		t.Errorf("userCacheDir() = %q, not an absolute path", dir)
+	}
+}
+
+// The environment variables each implementation reads
+func TestUserCacheDirEnv(t *testing.T) {
+	abs := t.TempDir()
+	switch {
+	case strings.HasPrefix(cacheDirImpl, "unix"):
+		t.Setenv("XDG_CACHE_HOME", abs)
+		if dir, err := userCacheDir(); dir != abs || err != nil {
+			t.Errorf("with $XDG_CACHE_HOME set: %q, %v; want %q", dir, err, abs)
+		}
+		t.Setenv("XDG_CACHE_HOME", "relative/cache") // the spec says to ignore a relative path
+		t.Setenv("HOME", abs)
+		if dir, err := userCacheDir(); dir != filepath.Join(abs, ".cache") || err != nil {
+			t.Errorf("with a relative $XDG_CACHE_HOME: %q, %v; want $HOME/.cache", dir, err)
+		}
+		t.Setenv("XDG_CACHE_HOME", "")
+		t.Setenv("HOME", "")
+		if _, err := userCacheDir(); err == nil {
+			t.Error("neither variable set: no error")
+		}
+	case strings.HasPrefix(cacheDirImpl, "windows"):
+		t.Setenv("LocalAppData", abs)
+		if dir, err := userCacheDir(); dir != abs || err != nil {
+			t.Errorf("with %%LocalAppData%% set: %q, %v; want %q", dir, err, abs)
+		}
+		t.Setenv("LocalAppData", "")
+		if _, err := userCacheDir(); err == nil {
+			t.Error("%LocalAppData% not set: no error")
+		}
+	default:
+		if dir, err := userCacheDir(); dir != os.TempDir() || err != nil {
+			t.Errorf("fallback: %q, %v; want os.TempDir()", dir, err)
+		}
+	}
+}
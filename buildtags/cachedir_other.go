@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package main
+
+import "os"
+
+const cacheDirImpl = "portable fallback (os.TempDir)"
+
+// Fallback for every platform the other two files do not cover (ex. js/wasm, plan9).
+// Only uses the portable os API, so it compiles anywhere
+func userCacheDir() (string, error) {
+	return os.TempDir(), nil
+}
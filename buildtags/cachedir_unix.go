@@ -0,0 +1,25 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const cacheDirImpl = "unix ($XDG_CACHE_HOME or ~/.cache)"
+
+// On unix-like systems follow the XDG base directory spec.
+// (macOS also matches the unix tag. Giving it its own ~/Library/Caches version would mean adding
+// cachedir_darwin.go AND changing this file to //go:build unix && !darwin, so they don't overlap)
+func userCacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", errors.New("neither $XDG_CACHE_HOME nor $HOME are defined")
+	}
+	return filepath.Join(home, ".cache"), nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// No //go:build line needed - the _windows.go file name suffix is the constraint
+
+const cacheDirImpl = "windows (%LocalAppData%)"
+
+func userCacheDir() (string, error) {
+	dir := os.Getenv("LocalAppData")
+	if dir == "" {
+		return "", errors.New("%LocalAppData% is not defined")
+	}
+	return dir, nil
+}
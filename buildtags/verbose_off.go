@@ -0,0 +1,9 @@
+//go:build !verbose
+
+package main
+
+const verbose = false
+
+// An empty function is inlined away, so debugf calls cost almost nothing in normal builds
+// (the arguments are still evaluated)
+func debugf(format string, args ...any) {}
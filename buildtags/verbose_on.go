@@ -0,0 +1,11 @@
+//go:build verbose
+
+package main
+
+import "log"
+
+const verbose = true
+
+func debugf(format string, args ...any) {
+	log.Printf("[debug] "+format, args...)
+}
@@ -0,0 +1,64 @@
+package cmppkg
+
+import (
+	"cmp"
+	"strings"
+)
+
+// A three-way comparison says in one call whether a is less than, equal to or greater than b:
+// negative, 0 or positive. That's the shape slices.SortFunc, slices.BinarySearchFunc and friends take,
+// and the cmp package has the pieces to build one:
+// - cmp.Compare(a, b): the three-way comparison of any cmp.Ordered type, with NaN less than every other float
+// - cmp.Less(a, b): just a < b, with the same NaN order
+// - cmp.Or(vals...): the first argument that isn't the zero value - for comparisons, the first one that
+//   isn't a tie, so cmp.Or(byName, byID) is "by name, then id"
+//
+// cmp.Ordered is the constraint for <: every type whose underlying type is an integer, a float or a string.
+// It's a type set like generics/constraints.go's Number - the same ~int | ~float64 | ... lines, plus ~string.
+
+// User is the basics notes' User
+type User struct {
+	UserId string
+	Name   string
+}
+
+// ByName orders users by name
+func ByName(a, b User) int { return cmp.Compare(a.Name, b.Name) }
+
+// ByID orders users by id
+func ByID(a, b User) int { return cmp.Compare(a.UserId, b.UserId) }
+
+// ByNameThenID orders by name, and users with the same name by id. Both comparisons are always made -
+// cmp.Or takes values, not functions - which costs one extra string compare per call
+func ByNameThenID(a, b User) int { return cmp.Or(ByName(a, b), ByID(a, b)) }
+
+// ByNameThenIDLoop is ByNameThenID without cmp: the if/else ladder it replaces
+func ByNameThenIDLoop(a, b User) int {
+	if a.Name < b.Name {
+		return -1
+	}
+	if a.Name > b.Name {
+		return 1
+	}
+	return strings.Compare(a.UserId, b.UserId)
+}
+
+// Clamp is v limited to [lo, hi], for any ordered type - numbers, and strings too
+func Clamp[T cmp.Ordered](v, lo, hi T) T {
+	return min(max(v, lo), hi)
+}
+
+// MinMax is the smallest and largest of vals, by cmp.Compare - so a NaN is the smallest, and hi is the
+// largest of the rest, where the built-in max would return NaN. It panics if vals is empty
+func MinMax[T cmp.Ordered](vals ...T) (lo, hi T) {
+	lo, hi = vals[0], vals[0]
+	for _, v := range vals[1:] {
+		if cmp.Less(v, lo) {
+			lo = v
+		}
+		if cmp.Less(hi, v) {
+			hi = v
+		}
+	}
+	return lo, hi
+}
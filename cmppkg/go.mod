@@ -0,0 +1,3 @@
+module cmppkg
+
+go 1.25.0
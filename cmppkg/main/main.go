@@ -0,0 +1,128 @@
+package main
+
+import (
+	"cmp"
+	"cmppkg"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// === Ex. cmp: three-way comparison, cmp.Ordered, cmp.Or, and slices.SortFunc ===
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func sampleUsers() []cmppkg.User {
+	return []cmppkg.User{
+		{UserId: userId2, Name: "Jack Eod"},
+		{UserId: "c4d5e6f7-0a1b-4c2d-9e3f-405162738495", Name: "John Doe"}, // a second John Doe
+		{UserId: "0f3e2d1c-4b5a-4c69-8d7e-1f2a3b4c5d6e", Name: "Grace Hopper"},
+		{UserId: userId1, Name: "John Doe"},
+	}
+}
+
+func main() {
+	compareExample()
+	// orderedExample()
+	// orExample()
+	// sortFuncCmpExample()
+}
+
+func compareExample() {
+	// -1, 0 or +1 - for numbers and strings alike (strings byte by byte, so "Z" < "a")
+	fmt.Println("Compare(1, 2):", cmp.Compare(1, 2), "| (2, 2):", cmp.Compare(2, 2), "| (\"b\", \"a\"):", cmp.Compare("b", "a"),
+		"| (\"Zed\", \"ada\"):", cmp.Compare("Zed", "ada"))
+
+	// strings.Compare is the same for strings; a - b is NOT a safe comparison for ints - it can overflow
+	a, b := math.MinInt64+1, 2
+	fmt.Println("strings.Compare agrees:", strings.Compare("b", "a") == cmp.Compare("b", "a"),
+		"| a - b says a > b:", a-b > 0, "| cmp.Compare says:", cmp.Compare(a, b))
+
+	// NaN: < and == are false for it both ways, so it has no place in an order. cmp puts it before everything
+	nan := math.NaN()
+	fmt.Println("NaN < 1:", nan < 1, "| NaN > 1:", nan > 1, "| NaN == NaN:", nan == nan)
+	fmt.Println("Compare(NaN, 1):", cmp.Compare(nan, 1.0), "| Compare(NaN, NaN):", cmp.Compare(nan, nan), "| Less(NaN, -Inf):", cmp.Less(nan, math.Inf(-1)))
+
+	// So sorting with cmp.Compare gives one answer; with < a NaN can land anywhere
+	vals := []float64{3, nan, 1, 2}
+	slices.SortFunc(vals, cmp.Compare[float64])
+	fmt.Println("sorted with cmp:", vals, "| ok:", math.IsNaN(vals[0]) && slices.IsSortedFunc(vals, cmp.Compare[float64]))
+	lo, hi := cmppkg.MinMax(3, nan, 1)
+	fmt.Println("MinMax(3, NaN, 1):", lo, hi, "| the built-ins:", min(3, nan, 1), max(3, nan, 1))
+}
+
+// Celsius and Name are defined types: cmp.Ordered has ~float64 and ~string, so they satisfy it
+type Celsius float64
+
+type Name string
+
+func orderedExample() {
+	// One generic function for every ordered type - the constraint is what allows the < inside min and max
+	fmt.Println("Clamp(15, 0, 10):", cmppkg.Clamp(15, 0, 10), "| Clamp(-2.5, 0, 1):", cmppkg.Clamp(-2.5, 0, 1))
+	fmt.Println("Clamp(Celsius(40), 0, 37):", cmppkg.Clamp(Celsius(40), 0, 37), "| Clamp(\"zebra\", \"a\", \"m\"):", cmppkg.Clamp("zebra", "a", "m"))
+	lo, hi := cmppkg.MinMax[Name]("John Doe", "Jack Eod", "Grace Hopper")
+	fmt.Printf("MinMax of Names: %q %q (type %T)\n", lo, hi, lo)
+
+	// cmp.Ordered vs generics/constraints.go's Number: the same integer and float lines, plus ~string -
+	// so an Ordered function can't use + or * as a Number one can (strings have + but not *), only comparisons.
+	// Neither has complex128 (no <), bool, or structs: a User isn't ordered - it needs a comparison function
+	// (Clamp(cmppkg.User{}, ...) doesn't compile: "User does not satisfy cmp.Ordered")
+	fmt.Println("| ok:", cmppkg.Clamp(15, 0, 10) == 10 && cmppkg.Clamp(Celsius(40), 0, 37) == 37 && lo == "Grace Hopper" && hi == "John Doe")
+}
+
+func orExample() {
+	// cmp.Or returns its first non-zero argument. With strings, it's a default for an empty value
+	var flagName, envName string
+	fmt.Println("name:", cmp.Or(flagName, envName, "gopher"))
+	envName = "from-env"
+	fmt.Println("name with the env set:", cmp.Or(flagName, envName, "gopher"))
+
+	// With comparisons, 0 means a tie - so the first comparison that isn't a tie decides
+	users := sampleUsers()
+	john1, john2 := users[3], users[1]
+	fmt.Println("ByName(John, John):", cmppkg.ByName(john1, john2), "| ByNameThenID:", cmppkg.ByNameThenID(john1, john2))
+
+	// Check: cmp.Or agrees with the if/else ladder on every pair
+	agree := true
+	for _, a := range users {
+		for _, b := range users {
+			agree = agree && cmppkg.ByNameThenID(a, b) == cmppkg.ByNameThenIDLoop(a, b)
+		}
+	}
+	fmt.Println("| ok: cmp.Or matches the if/else ladder on all", len(users)*len(users), "pairs:", agree)
+}
+
+func sortFuncCmpExample() {
+	users := sampleUsers()
+
+	// By name alone, the two John Does tie - SortFunc isn't stable, so their order isn't fixed
+	slices.SortFunc(users, cmppkg.ByName)
+	fmt.Println("by name:")
+	for _, u := range users {
+		fmt.Printf("  %-12s %s\n", u.Name, u.UserId[:8])
+	}
+
+	// By name then id is a total order: one answer, whatever the input's order
+	slices.SortFunc(users, cmppkg.ByNameThenID)
+	fmt.Println("by name, then id:")
+	for _, u := range users {
+		fmt.Printf("  %-12s %s\n", u.Name, u.UserId[:8])
+	}
+	reversed := slices.Clone(users)
+	slices.Reverse(reversed)
+	slices.SortFunc(reversed, cmppkg.ByNameThenID)
+	fmt.Println("| ok: sorted, and the same from the reverse order:", slices.IsSortedFunc(users, cmppkg.ByNameThenID) && slices.Equal(users, reversed))
+
+	// Descending: swap the arguments (or negate the result) - generics/compare.go's Reverse
+	slices.SortFunc(users, func(a, b cmppkg.User) int { return cmppkg.ByNameThenID(b, a) })
+	fmt.Println("descending, first:", users[0].Name, users[0].UserId[:8])
+
+	// A comparison also finds things: BinarySearchFunc on a slice sorted the same way
+	slices.SortFunc(users, cmppkg.ByNameThenID)
+	i, found := slices.BinarySearchFunc(users, "John Doe", func(u cmppkg.User, name string) int { return cmp.Compare(u.Name, name) })
+	fmt.Println("first John Doe at:", i, found, "| id:", users[i].UserId == userId1)
+	// (sortpkg sorts the same users with sort.Slice and sort.Stable too; generics/compare.go builds
+	// comparisons from key functions with By and CompareBy)
+}
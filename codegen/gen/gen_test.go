@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The committed types_gen.go is what gen makes from types.json today - the same check as
+// go run ../gen -check, run by go test so an edited spec or template can't go unnoticed
+func TestCommittedFileIsCurrent(t *testing.T) {
+	data, err := os.ReadFile("../main/types.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec Spec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+	src, err := Generate(spec, "types.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	committed, err := os.ReadFile("../main/types_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, committed) {
+		t.Errorf("main/types_gen.go is %v, run go generate ./... in codegen/main", errOutOfDate)
+	}
+}
+
+// Each generated file parses, and declares what the spec asked for
+func TestGenerate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec Spec
+		want []string // declarations that must appear
+		skip []string // and ones that must not
+	}{
+		{"ids only", Spec{Package: "ids", IDs: []IDSpec{{"ItemID", "item-"}}},
+			[]string{"package ids", "type ItemID int64", `"item-" + strconv.FormatInt`, "func ParseItemID("},
+			[]string{"iota"}},
+		{"enums only", Spec{Package: "enums", Enums: []EnumSpec{{"Color", []string{"Red", "Green"}}}},
+			[]string{"type Color int", "Red Color = iota", "var ColorValues = []Color{Red, Green}", "func ParseColor("},
+			[]string{`"strconv"`, `"strings"`}}, // no ids, so only fmt is imported
+		{"both", Spec{Package: "main", IDs: []IDSpec{{"A", ""}}, Enums: []EnumSpec{{"B", []string{"X"}}}},
+			[]string{"type A int64", "type B int", "X B = iota"}, nil},
+	} {
+		src, err := Generate(tc.spec, "spec.json")
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "gen.go", src, 0); err != nil {
+			t.Errorf("%s: doesn't parse: %v", tc.name, err)
+		}
+		if !bytes.HasPrefix(src, []byte("// Code generated by gen from spec.json; DO NOT EDIT.\n")) {
+			t.Errorf("%s: no generated-code header", tc.name)
+		}
+		for _, s := range tc.want {
+			if !bytes.Contains(src, []byte(s)) {
+				t.Errorf("%s: no %q in\n%s", tc.name, s, src)
+			}
+		}
+		for _, s := range tc.skip {
+			if bytes.Contains(src, []byte(s)) {
+				t.Errorf("%s: unexpected %q", tc.name, s)
+			}
+		}
+	}
+}
+
+func TestGenerateRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec Spec
+		want string
+	}{
+		{"bad package", Spec{Package: "my-pkg", IDs: []IDSpec{{"A", ""}}}, "not a valid name"},
+		{"nothing to generate", Spec{Package: "main"}, "no ids or enums"},
+		{"unexported id", Spec{Package: "main", IDs: []IDSpec{{"userID", ""}}}, "exported identifier"},
+		{"duplicate name", Spec{Package: "main", IDs: []IDSpec{{"A", ""}}, Enums: []EnumSpec{{"A", []string{"X"}}}}, "declared twice"},
+		{"value clashes with a Parse func", Spec{Package: "main", IDs: []IDSpec{{"A", ""}}, Enums: []EnumSpec{{"B", []string{"ParseA"}}}}, "declared twice"},
+		{"empty enum", Spec{Package: "main", Enums: []EnumSpec{{"B", nil}}}, "no values"},
+		{"bad value", Spec{Package: "main", Enums: []EnumSpec{{"B", []string{"not-ok"}}}}, "exported identifier"},
+	} {
+		_, err := Generate(tc.spec, "spec.json")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v, want an error about %q", tc.name, err, tc.want)
+		}
+	}
+}
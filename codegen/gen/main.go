@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+)
+
+// === gen - generating typed wrappers from a spec file ===
+
+// Some code is the same every time except for a name and a type: an ID type with String/Parse,
+// an enum with String/Parse/Values. Instead of writing it by hand for every type, write it once as
+// a text/template, and generate the Go source from a small spec:
+//
+//	{"package": "main",
+//	 "ids":   [{"name": "UserID", "prefix": "usr_"}],
+//	 "enums": [{"name": "Status", "values": ["Pending", "Active"]}]}
+//
+// Steps: parse the spec -> validate it -> execute the template -> go/format the result -> write it.
+// go/format.Source does two jobs: gofmt's layout (so the template doesn't have to get whitespace right),
+// and a syntax check (a template bug shows up as an error here, not as a broken file later).
+//
+// The generated file is committed like any other source file. The package that uses it has a
+//
+//	//go:generate go run ../gen -spec types.json -out types_gen.go
+//
+// comment, and `go generate ./...` re-runs it after the spec changes. go generate never runs on its own
+// (not in go build or go test) - that's why -check exists: it fails if the committed file is out of date.
+
+// Spec is the file format
+type Spec struct {
+	Package string     `json:"package"`
+	IDs     []IDSpec   `json:"ids"`
+	Enums   []EnumSpec `json:"enums"`
+}
+
+// IDSpec describes a typed ID: an int64 with a prefix when printed, ex. UserID(42) is "usr_42"
+type IDSpec struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
+// EnumSpec describes an int enum. The first value is the zero value
+type EnumSpec struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+func (s Spec) validate() error {
+	if !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("package %q is not a valid name", s.Package)
+	}
+	if len(s.IDs) == 0 && len(s.Enums) == 0 {
+		return errors.New("no ids or enums to generate")
+	}
+	names := map[string]bool{}
+	declare := func(name string) error {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("%q must be an exported identifier", name)
+		}
+		if names[name] {
+			return fmt.Errorf("%q is declared twice", name)
+		}
+		names[name] = true
+		return nil
+	}
+	for _, id := range s.IDs {
+		if err := declare(id.Name); err != nil {
+			return fmt.Errorf("id: %w", err)
+		}
+		if err := declare("Parse" + id.Name); err != nil {
+			return fmt.Errorf("id: %w", err)
+		}
+	}
+	for _, e := range s.Enums {
+		if len(e.Values) == 0 {
+			return fmt.Errorf("enum %s has no values", e.Name)
+		}
+		for _, name := range append([]string{e.Name, "Parse" + e.Name, e.Name + "Values"}, e.Values...) {
+			if err := declare(name); err != nil {
+				return fmt.Errorf("enum %s: %w", e.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Generate turns a spec into formatted Go source
+func Generate(s Spec, specName string) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		Spec
+		Source string
+	}{s, specName}); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code doesn't parse (a template bug): %w", err)
+	}
+	return src, nil
+}
+
+var errOutOfDate = errors.New("out of date, run go generate")
+
+func run() error {
+	specPath := flag.String("spec", "types.json", "spec file")
+	out := flag.String("out", "types_gen.go", "output file")
+	check := flag.Bool("check", false, "don't write, only fail if the output file differs from what would be generated")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		return err
+	}
+	var spec Spec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields() // a misspelled field is an error, not a silently missing type
+	if err := dec.Decode(&spec); err != nil {
+		return fmt.Errorf("%s: %w", *specPath, err)
+	}
+
+	src, err := Generate(spec, filepath.Base(*specPath))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *specPath, err)
+	}
+
+	if *check {
+		current, err := os.ReadFile(*out)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, src) {
+			return fmt.Errorf("%s: %w", *out, errOutOfDate)
+		}
+		return nil
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strconv"
+	"text/template"
+)
+
+// The template for the generated file. Whitespace doesn't matter much - go/format fixes the layout,
+// so the template is written to be readable instead
+
+var tmpl = template.Must(template.New("types").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(`// Code generated by gen from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	{{- if .IDs}}
+	"strconv"
+	"strings"
+	{{- end}}
+)
+
+{{range .IDs}}
+// --- {{.Name}} ---
+
+// {{.Name}} is a typed ID, printed as {{quote .Prefix}} followed by the number
+type {{.Name}} int64
+
+func (id {{.Name}}) String() string {
+	return {{quote .Prefix}} + strconv.FormatInt(int64(id), 10)
+}
+
+// Parse{{.Name}} is the inverse of String
+func Parse{{.Name}}(s string) ({{.Name}}, error) {
+	digits, ok := strings.CutPrefix(s, {{quote .Prefix}})
+	if !ok {
+		return 0, fmt.Errorf("{{.Name}} %q: missing prefix %q", s, {{quote .Prefix}})
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("{{.Name}} %q: %w", s, err)
+	}
+	return {{.Name}}(n), nil
+}
+{{end}}
+
+{{range .Enums}}
+{{- $enum := .Name}}
+// --- {{$enum}} ---
+
+type {{$enum}} int
+
+const (
+{{- range $i, $v := .Values}}
+	{{$v}}{{if eq $i 0}} {{$enum}} = iota{{end}}
+{{- end}}
+)
+
+// {{$enum}}Values lists every valid {{$enum}}, in declaration order
+var {{$enum}}Values = []{{$enum}}{ {{- range .Values}}{{.}}, {{end -}} }
+
+var _{{$enum}}Names = [...]string{ {{- range .Values}}{{quote .}}, {{end -}} }
+
+// Valid reports whether v is one of the declared values (a conversion like {{$enum}}(99) compiles fine)
+func (v {{$enum}}) Valid() bool {
+	return v >= 0 && int(v) < len(_{{$enum}}Names)
+}
+
+func (v {{$enum}}) String() string {
+	if !v.Valid() {
+		return fmt.Sprintf("{{$enum}}(%d)", int(v))
+	}
+	return _{{$enum}}Names[v]
+}
+
+// Parse{{$enum}} is the inverse of String
+func Parse{{$enum}}(s string) ({{$enum}}, error) {
+	for i, name := range _{{$enum}}Names {
+		if name == s {
+			return {{$enum}}(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown {{$enum}} %q", s)
+}
+{{end}}
+`))
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// === Using generated code ===
+
+// types_gen.go is generated from types.json by the gen command (see ../gen/main.go).
+// After editing types.json, regenerate with:
+//
+//	go generate ./...
+//
+// and check that the committed file is current (ex. in CI) with:
+//
+//	go run ../gen -spec types.json -out types_gen.go -check
+
+//go:generate go run ../gen -spec types.json -out types_gen.go
+
+// (gen is written for this repo; basics/main/uploadstatus.go runs a standard tool, stringer, the same way)
+
+// The generated types are ordinary Go types - they are type checked like hand written ones:
+//
+//	var u UserID = OrderID(1)  // compile error: cannot use OrderID(1) as UserID value
+//	placeOrder(OrderID(7), UserID(42)) // compile error - the arguments are swapped
+func placeOrder(user UserID, order OrderID) string {
+	return fmt.Sprintf("%v placed %v", user, order)
+}
+
+func generatedTypesExample() {
+	fmt.Println(placeOrder(42, 7)) // untyped constants convert to either type
+	fmt.Println("statuses:", OrderStatusValues)
+	fmt.Println("an out of range value:", OrderStatus(99), "valid:", OrderStatus(99).Valid())
+
+	// (types_gen_test.go checks that every value round trips, and gen's tests that types_gen.go is current)
+
+	// Bad input is rejected
+	_, errPrefix := ParseUserID("ord_7") // an OrderID's text isn't a UserID
+	_, errNumber := ParseOrderID("ord_seven")
+	_, errEnum := ParseOrderStatus("Lost")
+	fmt.Println(errPrefix)
+	fmt.Println(errNumber)
+	fmt.Println(errEnum)
+	fmt.Println("number error wraps strconv's:", errors.Is(errNumber, strconv.ErrSyntax))
+}
+
+func main() {
+	generatedTypesExample()
+}
@@ -0,0 +1,132 @@
+// Code generated by gen from types.json; DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --- UserID ---
+
+// UserID is a typed ID, printed as "usr_" followed by the number
+type UserID int64
+
+func (id UserID) String() string {
+	return "usr_" + strconv.FormatInt(int64(id), 10)
+}
+
+// ParseUserID is the inverse of String
+func ParseUserID(s string) (UserID, error) {
+	digits, ok := strings.CutPrefix(s, "usr_")
+	if !ok {
+		return 0, fmt.Errorf("UserID %q: missing prefix %q", s, "usr_")
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("UserID %q: %w", s, err)
+	}
+	return UserID(n), nil
+}
+
+// --- OrderID ---
+
+// OrderID is a typed ID, printed as "ord_" followed by the number
+type OrderID int64
+
+func (id OrderID) String() string {
+	return "ord_" + strconv.FormatInt(int64(id), 10)
+}
+
+// ParseOrderID is the inverse of String
+func ParseOrderID(s string) (OrderID, error) {
+	digits, ok := strings.CutPrefix(s, "ord_")
+	if !ok {
+		return 0, fmt.Errorf("OrderID %q: missing prefix %q", s, "ord_")
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("OrderID %q: %w", s, err)
+	}
+	return OrderID(n), nil
+}
+
+// --- OrderStatus ---
+
+type OrderStatus int
+
+const (
+	Pending OrderStatus = iota
+	Paid
+	Shipped
+	Cancelled
+)
+
+// OrderStatusValues lists every valid OrderStatus, in declaration order
+var OrderStatusValues = []OrderStatus{Pending, Paid, Shipped, Cancelled}
+
+var _OrderStatusNames = [...]string{"Pending", "Paid", "Shipped", "Cancelled"}
+
+// Valid reports whether v is one of the declared values (a conversion like OrderStatus(99) compiles fine)
+func (v OrderStatus) Valid() bool {
+	return v >= 0 && int(v) < len(_OrderStatusNames)
+}
+
+func (v OrderStatus) String() string {
+	if !v.Valid() {
+		return fmt.Sprintf("OrderStatus(%d)", int(v))
+	}
+	return _OrderStatusNames[v]
+}
+
+// ParseOrderStatus is the inverse of String
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	for i, name := range _OrderStatusNames {
+		if name == s {
+			return OrderStatus(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown OrderStatus %q", s)
+}
+
+// --- Weekday ---
+
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+)
+
+// WeekdayValues lists every valid Weekday, in declaration order
+var WeekdayValues = []Weekday{Sunday, Monday, Tuesday, Wednesday, Thursday, Friday, Saturday}
+
+var _WeekdayNames = [...]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// Valid reports whether v is one of the declared values (a conversion like Weekday(99) compiles fine)
+func (v Weekday) Valid() bool {
+	return v >= 0 && int(v) < len(_WeekdayNames)
+}
+
+func (v Weekday) String() string {
+	if !v.Valid() {
+		return fmt.Sprintf("Weekday(%d)", int(v))
+	}
+	return _WeekdayNames[v]
+}
+
+// ParseWeekday is the inverse of String
+func ParseWeekday(s string) (Weekday, error) {
+	for i, name := range _WeekdayNames {
+		if name == s {
+			return Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown Weekday %q", s)
+}
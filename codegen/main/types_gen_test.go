@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// Every String output parses back to the same value
+func TestIDsRoundTrip(t *testing.T) {
+	for _, id := range []UserID{0, 1, 42, -3, 1 << 62} {
+		if back, err := ParseUserID(id.String()); err != nil || back != id {
+			t.Errorf("ParseUserID(%q) = %v, %v; want %d", id.String(), back, err, int64(id))
+		}
+	}
+	for _, id := range []OrderID{0, 7, -1 << 63} {
+		if back, err := ParseOrderID(id.String()); err != nil || back != id {
+			t.Errorf("ParseOrderID(%q) = %v, %v; want %d", id.String(), back, err, int64(id))
+		}
+	}
+	if s := UserID(42).String(); s != "usr_42" {
+		t.Errorf("UserID(42) = %q, want usr_42", s)
+	}
+}
+
+func TestParseIDRejects(t *testing.T) {
+	for _, s := range []string{"ord_7", "42", "usr_", "usr_seven", "USR_1", "usr_99999999999999999999"} {
+		if _, err := ParseUserID(s); err == nil {
+			t.Errorf("ParseUserID(%q): no error", s)
+		}
+	}
+	if _, err := ParseOrderID("ord_seven"); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("err = %v, want it to wrap strconv.ErrSyntax", err)
+	}
+}
+
+func TestEnums(t *testing.T) {
+	if !slices.Equal(OrderStatusValues, []OrderStatus{Pending, Paid, Shipped, Cancelled}) {
+		t.Errorf("OrderStatusValues = %v", OrderStatusValues)
+	}
+	if len(WeekdayValues) != 7 || !slices.IsSorted(WeekdayValues) || Weekday(0) != Sunday {
+		t.Errorf("WeekdayValues = %v, want 7 days in declaration order, Sunday first", WeekdayValues)
+	}
+	for _, s := range OrderStatusValues {
+		if back, err := ParseOrderStatus(s.String()); err != nil || back != s || !s.Valid() {
+			t.Errorf("%v: parsed back as %v, %v", s, back, err)
+		}
+	}
+	for _, d := range WeekdayValues {
+		if back, err := ParseWeekday(d.String()); err != nil || back != d {
+			t.Errorf("%v: parsed back as %v, %v", d, back, err)
+		}
+	}
+	if Saturday.String() != "Saturday" || Pending.String() != "Pending" {
+		t.Errorf("names: %q, %q", Saturday.String(), Pending.String())
+	}
+}
+
+func TestEnumOutOfRange(t *testing.T) {
+	for _, v := range []OrderStatus{-1, 4, 99} {
+		if v.Valid() {
+			t.Errorf("OrderStatus(%d) is valid", int(v))
+		}
+		if want := "OrderStatus(" + strconv.Itoa(int(v)) + ")"; v.String() != want {
+			t.Errorf("String = %q, want %q", v.String(), want)
+		}
+	}
+	for _, s := range []string{"Lost", "pending", "", "OrderStatus(99)"} {
+		if _, err := ParseOrderStatus(s); err == nil {
+			t.Errorf("ParseOrderStatus(%q): no error", s)
+		}
+	}
+}
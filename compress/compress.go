@@ -0,0 +1,81 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// gzip composes with everything else that's an io.Reader or io.Writer (see the io.Reader notes in
+// methodsinterfaces): a gzip.Writer wraps a Writer and compresses what's written through it, and a
+// gzip.Reader wraps a Reader and decompresses what's read from it. Neither needs the whole data in
+// memory - io.Copy streams through in 32KB pieces, so a gigabyte file needs a few KB.
+//
+// The end of a gzip stream is a footer with a CRC-32 and the length: a gzip.Writer writes it only on
+// Close, and a gzip.Reader checks it at the end - so data cut short, or changed, is an error, not short
+// output. The errors:
+//   - io.ErrUnexpectedEOF: the stream ends too early (a truncated file, a Writer never Closed)
+//   - gzip.ErrChecksum: the data doesn't match the footer (it was changed)
+//   - gzip.ErrHeader: it isn't gzip at all
+
+// ErrTooLarge is the error when decompressed data is over the limit given to Decompress
+var ErrTooLarge = errors.New("compress: decompressed data too large")
+
+// Compress gzips everything read from r into w, at level (gzip.DefaultCompression, gzip.BestSpeed...).
+// It closes the gzip.Writer - without that the footer is missing - but not w
+func Compress(w io.Writer, r io.Reader, level int) error {
+	zw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return err // only for a level out of range
+	}
+	if _, err := io.Copy(zw, r); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// Decompress gunzips everything read from r into w. It stops with ErrTooLarge after max bytes of
+// output (0 is no limit): a few KB of gzip can decompress to gigabytes, so data from outside needs a limit
+func Decompress(w io.Writer, r io.Reader, max int64) (int64, error) {
+	zr, err := gzip.NewReader(r) // reads the header now: not gzip is an error already
+	if err != nil {
+		return 0, fmt.Errorf("decompress: %w", err)
+	}
+	defer zr.Close()
+	var src io.Reader = zr
+	if max > 0 {
+		src = io.LimitReader(zr, max)
+	}
+	n, err := io.Copy(w, src)
+	if err != nil {
+		return n, fmt.Errorf("decompress: %w", err)
+	}
+	if max > 0 && n == max {
+		// Exactly max, or more? One more byte says - and reading to the end checks the footer
+		more, err := io.Copy(io.Discard, io.LimitReader(zr, 1))
+		if err != nil {
+			return n, fmt.Errorf("decompress: %w", err)
+		}
+		if more > 0 {
+			return n, ErrTooLarge
+		}
+	}
+	return n, nil
+}
+
+// Gzip is Compress for a []byte
+func Gzip(data []byte) []byte {
+	var buf bytes.Buffer
+	Compress(&buf, bytes.NewReader(data), gzip.DefaultCompression) // a bytes.Buffer and a bytes.Reader don't fail
+	return buf.Bytes()
+}
+
+// Gunzip is Decompress for a []byte, with no limit
+func Gunzip(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := Decompress(&buf, bytes.NewReader(data), 0)
+	return buf.Bytes(), err
+}
@@ -0,0 +1,178 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var text = strings.Repeat(`{"userId": "1d02455e-f24c-4c26-90d2-f1073c686314", "name": "John Doe"}`+"\n", 200)
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"nil", nil},
+		{"empty", []byte{}},
+		{"one byte", []byte("a")},
+		{"text", []byte(text)},
+		{"random", randomBytes(100_000)},
+		{"zeros over io.Copy's buffer", make([]byte, 1<<20)},
+	}
+	for _, tt := range tests {
+		back, err := Gunzip(Gzip(tt.data))
+		if err != nil || !bytes.Equal(back, tt.data) {
+			t.Errorf("%s: Gunzip(Gzip) = %d bytes, %v, want the %d back", tt.name, len(back), err, len(tt.data))
+		}
+	}
+}
+
+func TestCompressStreams(t *testing.T) {
+	// strings.NewReader in, strings.Builder out, a byte at a time on both sides
+	var zipped bytes.Buffer
+	if err := Compress(&zipped, iotest.OneByteReader(strings.NewReader(text)), gzip.BestSpeed); err != nil {
+		t.Fatal(err)
+	}
+	if zipped.Len() >= len(text)/10 {
+		t.Errorf("%d bytes gzipped to %d: text like this compresses to far less", len(text), zipped.Len())
+	}
+	var out strings.Builder
+	n, err := Decompress(&out, iotest.OneByteReader(&zipped), 0)
+	if err != nil || n != int64(len(text)) || out.String() != text {
+		t.Errorf("Decompress = %d, %v, want the %d bytes of text", n, err, len(text))
+	}
+}
+
+func TestCompressLevels(t *testing.T) {
+	for _, level := range []int{gzip.NoCompression, gzip.BestSpeed, gzip.DefaultCompression, gzip.BestCompression, gzip.HuffmanOnly} {
+		var buf bytes.Buffer
+		if err := Compress(&buf, strings.NewReader(text), level); err != nil {
+			t.Errorf("level %d: %v", level, err)
+			continue
+		}
+		if back, err := Gunzip(buf.Bytes()); err != nil || string(back) != text {
+			t.Errorf("level %d: Gunzip = %v", level, err)
+		}
+	}
+	if err := Compress(io.Discard, strings.NewReader(text), 42); err == nil {
+		t.Error("level 42 succeeded")
+	}
+}
+
+func TestCompressErrors(t *testing.T) {
+	errRead := errors.New("read failed")
+	if err := Compress(io.Discard, iotest.ErrReader(errRead), gzip.DefaultCompression); !errors.Is(err, errRead) {
+		t.Errorf("Compress of a failing reader = %v, want %v", err, errRead)
+	}
+	// The footer is written on Close: a writer that fails only then is still reported
+	w := &failAfter{n: 20}
+	if err := Compress(w, strings.NewReader("short"), gzip.DefaultCompression); err == nil {
+		t.Error("Compress into a writer that fails succeeded")
+	}
+}
+
+// failAfter is a Writer that fails once n bytes have been written
+type failAfter struct{ n int }
+
+func (w *failAfter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		k := w.n
+		w.n = 0
+		return k, errors.New("disk full")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestBrokenInput(t *testing.T) {
+	zipped := Gzip([]byte(text))
+	changedCRC := bytes.Clone(zipped)
+	changedCRC[len(changedCRC)-8] ^= 0xff
+	changedLen := bytes.Clone(zipped)
+	changedLen[len(changedLen)-1] ^= 0xff
+
+	var unclosed bytes.Buffer
+	zw := gzip.NewWriter(&unclosed)
+	io.WriteString(zw, text)
+	zw.Flush()
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  error // nil: any error will do
+	}{
+		{"truncated", zipped[:len(zipped)/2], io.ErrUnexpectedEOF},
+		{"no footer", zipped[:len(zipped)-4], io.ErrUnexpectedEOF},
+		{"header only", zipped[:10], io.ErrUnexpectedEOF},
+		{"Writer not closed", unclosed.Bytes(), io.ErrUnexpectedEOF},
+		{"changed CRC", changedCRC, gzip.ErrChecksum},
+		{"changed length", changedLen, gzip.ErrChecksum},
+		{"plain text", []byte(text), gzip.ErrHeader},
+		{"empty", nil, io.EOF},
+		{"random", randomBytes(1000), nil},
+	}
+	for _, tt := range tests {
+		_, err := Gunzip(tt.input)
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+		} else if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// What came before a cut is still written out
+	var out bytes.Buffer
+	n, err := Decompress(&out, bytes.NewReader(zipped[:len(zipped)/2]), 0)
+	if err == nil || n == 0 || int64(out.Len()) != n || !strings.HasPrefix(text, out.String()) {
+		t.Errorf("truncated: %d bytes, %v, want a start of the text and an error", n, err)
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	const size = 1 << 20
+	zeros := Gzip(make([]byte, size))
+	tests := []struct {
+		max   int64
+		wantN int64
+		want  error
+	}{
+		{0, size, nil},
+		{size / 2, size / 2, ErrTooLarge},
+		{1, 1, ErrTooLarge},
+		{size - 1, size - 1, ErrTooLarge},
+		{size, size, nil},
+		{size + 1, size, nil},
+	}
+	for _, tt := range tests {
+		n, err := Decompress(io.Discard, bytes.NewReader(zeros), tt.max)
+		if n != tt.wantN || !errors.Is(err, tt.want) {
+			t.Errorf("max %d: %d, %v, want %d, %v", tt.max, n, err, tt.wantN, tt.want)
+		}
+	}
+
+	// Exactly at the limit the footer is still checked
+	bad := bytes.Clone(zeros)
+	bad[len(bad)-8] ^= 0xff
+	if _, err := Decompress(io.Discard, bytes.NewReader(bad), size); !errors.Is(err, gzip.ErrChecksum) {
+		t.Errorf("a changed CRC at exactly the limit: %v, want gzip.ErrChecksum", err)
+	}
+}
+
+func TestDecompressWriteError(t *testing.T) {
+	_, err := Decompress(&failAfter{n: 100}, bytes.NewReader(Gzip([]byte(text))), 0)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Decompress into a failing writer = %v", err)
+	}
+}
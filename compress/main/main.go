@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"compress"
+	"compress/gzip"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// === Ex. compress/gzip: Writers and Readers wrapping other Writers and Readers ===
+// (the checks are in compress_test.go)
+
+func main() {
+	roundTripExample()
+	// streamingExample()
+	// corruptInputExample()
+	// compressionLevelsExample()
+	// limitExample()
+}
+
+// text is something that compresses well: repetitive, like logs or JSON
+var text = strings.Repeat(`{"userId": "1d02455e-f24c-4c26-90d2-f1073c686314", "name": "John Doe"}`+"\n", 200)
+
+func roundTripExample() {
+	// strings.NewReader -> gzip -> bytes.Buffer: no []byte of the input ever made
+	var zipped bytes.Buffer
+	err := compress.Compress(&zipped, strings.NewReader(text), gzip.DefaultCompression)
+	fmt.Printf("%d bytes -> %d gzipped (%.1f%%), err %v\n", len(text), zipped.Len(), 100*float64(zipped.Len())/float64(len(text)), err)
+
+	// ...and back, streamed into a strings.Builder - it's an io.Writer too
+	var out strings.Builder
+	n, err := compress.Decompress(&out, &zipped, 0)
+	fmt.Printf("decompressed %d bytes, err %v: %.40q...\n", n, err, out.String())
+
+	// The []byte helpers round trip any data, the empty one too (compress_test.go checks them)
+	back, err := compress.Gunzip(compress.Gzip([]byte("a")))
+	fmt.Printf("Gzip then Gunzip: %q, err %v\n", back, err)
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+func streamingExample() {
+	// An io.Pipe connects a Writer to a Reader: the compressing goroutine writes into one end while the
+	// decompressing side reads from the other - neither holds more than a piece at a time
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzip.NewWriter(pw)
+		// The header can carry a file name and time - gunzip uses them for the file it writes
+		zw.Name, zw.ModTime = "users.jsonl", time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		for i := range 3 {
+			fmt.Fprintf(zw, `{"line": %d}`+"\n", i)
+		}
+		pw.CloseWithError(zw.Close()) // the Reader side sees the error, or io.EOF once everything's read
+	}()
+
+	zr, err := gzip.NewReader(pr)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer zr.Close()
+	fmt.Println("header:", zr.Name, zr.ModTime.UTC())
+	var out strings.Builder
+	io.Copy(&out, zr)
+	fmt.Print(out.String())
+
+	// A gzip.Reader can be Reset onto another stream, reusing its buffers (a gzip.Writer onto another Writer)
+	fmt.Println("Reset:", zr.Reset(bytes.NewReader(compress.Gzip([]byte("again")))))
+	again, _ := io.ReadAll(zr)
+	fmt.Printf("after Reset: %q\n", again)
+}
+
+func corruptInputExample() {
+	zipped := compress.Gzip([]byte(text))
+
+	// Cut short - a download that stopped halfway. What came before the cut decompresses, then the error
+	var out strings.Builder
+	n, err := compress.Decompress(&out, bytes.NewReader(zipped[:len(zipped)/2]), 0)
+	fmt.Printf("truncated: %d bytes out, then %v - is io.ErrUnexpectedEOF: %t\n", n, err, errors.Is(err, io.ErrUnexpectedEOF))
+
+	// Only the footer cut off: all the data's there, but it can't be checked - still an error
+	_, err = compress.Gunzip(zipped[:len(zipped)-4])
+	fmt.Println("no footer:", err)
+
+	// A Writer that was never Closed: the same - the footer was never written
+	var unclosed bytes.Buffer
+	zw := gzip.NewWriter(&unclosed)
+	io.WriteString(zw, text)
+	zw.Flush() // writes out what's compressed so far, but not the footer
+	_, err = compress.Gunzip(unclosed.Bytes())
+	fmt.Println("Writer not closed:", err)
+
+	// A changed byte: in the data, it usually no longer decodes, or decodes to something else the CRC in
+	// the footer doesn't match. Here it's the footer's CRC itself
+	changed := bytes.Clone(zipped)
+	changed[len(changed)-8] ^= 0xff
+	_, err = compress.Gunzip(changed)
+	fmt.Println("changed CRC:", err, "- is gzip.ErrChecksum:", errors.Is(err, gzip.ErrChecksum))
+
+	// Not gzip at all
+	_, err = compress.Gunzip([]byte(text))
+	fmt.Println("plain text:", err, "- is gzip.ErrHeader:", errors.Is(err, gzip.ErrHeader))
+}
+
+func compressionLevelsExample() {
+	big := strings.Repeat(text, 50) // ~700 KB
+	for _, level := range []int{gzip.NoCompression, gzip.BestSpeed, gzip.DefaultCompression, gzip.BestCompression, gzip.HuffmanOnly} {
+		var buf bytes.Buffer
+		start := time.Now()
+		compress.Compress(&buf, strings.NewReader(big), level)
+		fmt.Printf("level %2d: %8d bytes (%5.2f%%) in %v\n", level, buf.Len(), 100*float64(buf.Len())/float64(len(big)), time.Since(start).Round(time.Millisecond))
+	}
+	// Random data has no patterns to find: gzip makes it (a little) bigger. So compressing what's
+	// already compressed - JPEG, PNG, zip, gzip - is wasted work
+	random := randomBytes(100_000)
+	fmt.Printf("100000 random bytes -> %d gzipped\n", len(compress.Gzip(random)))
+	_, err := gzip.NewWriterLevel(io.Discard, 42)
+	fmt.Println("level 42:", err)
+}
+
+func limitExample() {
+	// 10 MB of zeros gzips to about 20 KB - a "zip bomb" is the same idea, at gigabytes
+	zeros := compress.Gzip(make([]byte, 10<<20))
+	fmt.Printf("10 MB of zeros -> %d bytes gzipped\n", len(zeros))
+
+	n, err := compress.Decompress(io.Discard, bytes.NewReader(zeros), 1<<20)
+	fmt.Println("with a 1 MB limit:", n, "bytes, then", err)
+	n, err = compress.Decompress(io.Discard, bytes.NewReader(zeros), 10<<20)
+	fmt.Println("with a limit of exactly its size:", n, err)
+}
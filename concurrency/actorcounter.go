@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// === SafeCounter without a Mutex - an actor ===
+
+// "Don't communicate by sharing memory; share memory by communicating."
+// SafeCounter shares its map and guards it with a lock. actorCounter shares nothing: one goroutine owns
+// the map, and the only way to reach it is to send that goroutine a command. Commands come off one channel
+// in order, so they never overlap - the serializing a Mutex would do, done by the channel.
+// A command that needs an answer (a Get) carries its own reply channel.
+//
+// It's slower per operation (writersBenchmarkExample has the numbers for the one-number version, chanCounter),
+// but the same shape scales to state no lock would be simple for: the owner can do several things per command,
+// keep timers, or talk to other goroutines, without anyone else seeing a half-done update.
+
+type counterCmd struct {
+	key   string
+	inc   bool
+	reply chan int // nil for an Inc - it needs no answer
+}
+
+type actorCounter struct {
+	cmds chan counterCmd
+	done chan struct{} // closed when the owner has returned
+}
+
+// newActorCounter starts the goroutine that owns the map. Close stops it
+func newActorCounter() *actorCounter {
+	a := &actorCounter{cmds: make(chan counterCmd), done: make(chan struct{})}
+	go a.own()
+	return a
+}
+
+func (a *actorCounter) own() {
+	defer close(a.done)
+	v := make(map[string]int) // only this goroutine ever touches v - no lock
+	for cmd := range a.cmds {
+		if cmd.inc {
+			v[cmd.key]++
+		}
+		if cmd.reply != nil {
+			cmd.reply <- v[cmd.key]
+		}
+	}
+}
+
+// SafeInc returns once the owner has taken the command - a GetValue after it, from any goroutine, sees it
+func (a *actorCounter) SafeInc(key string) {
+	a.cmds <- counterCmd{key: key, inc: true}
+}
+
+func (a *actorCounter) GetValue(key string) int {
+	reply := make(chan int) // a new one per call: two GetValues at once can't get each other's answer
+	a.cmds <- counterCmd{key: key, reply: reply}
+	return <-reply
+}
+
+// Close stops the owner after the commands already sent. Using the counter after Close panics (send on closed channel)
+func (a *actorCounter) Close() {
+	close(a.cmds)
+	<-a.done
+}
+
+func actorCounterExample() {
+	// The mutex example, with the actor: 100 goroutines incrementing
+	actor := newActorCounter()
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { actor.SafeInc(COUNTER_KEY) })
+	}
+	wg.Wait()
+	fmt.Println("actor counter after 100 concurrent increments:", actor.GetValue(COUNTER_KEY))
+	fmt.Println("a key never incremented:", actor.GetValue("z"))
+	actor.Close()
+	// actorcounter_test.go runs the same random workload on this and the mutex SafeCounter, and compares them
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"maps"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+// The same random workload on the mutex SafeCounter and the actor: the totals must match key by key,
+// and no read may be below what the reading goroutine's own increments already put in
+func TestActorCounterMatchesMutex(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	r := rand.New(rand.NewPCG(3, 4))
+	expected := map[string]int{}
+	var workloads [][]int
+	for range 20 {
+		ops := make([]int, 500)
+		for i := range ops {
+			ops[i] = r.IntN(1000)
+			if ops[i]%3 != 0 { // a third are reads
+				expected[keys[ops[i]%len(keys)]]++
+			}
+		}
+		workloads = append(workloads, ops)
+	}
+
+	run := func(name string, c keyCounter, ops []int) {
+		seen := map[string]int{}
+		for _, op := range ops {
+			key := keys[op%len(keys)]
+			if op%3 != 0 {
+				c.SafeInc(key)
+				seen[key]++
+				continue
+			}
+			if got := c.GetValue(key); got < seen[key] {
+				t.Errorf("%s: read %s = %d, after seeing %d", name, key, got, seen[key])
+			} else {
+				seen[key] = got
+			}
+		}
+	}
+
+	mutex := &SafeCounter{v: make(map[string]int)}
+	actor := newActorCounter()
+	defer actor.Close()
+	var wg sync.WaitGroup
+	for _, ops := range workloads {
+		wg.Go(func() { run("mutex", mutex, ops) })
+		wg.Go(func() { run("actor", actor, ops) })
+	}
+	wg.Wait()
+
+	gotMutex, gotActor := map[string]int{}, map[string]int{}
+	for _, k := range append(keys, "never incremented") {
+		gotMutex[k], gotActor[k] = mutex.GetValue(k), actor.GetValue(k)
+	}
+	expected["never incremented"] = 0
+	if !maps.Equal(gotMutex, expected) || !maps.Equal(gotActor, expected) {
+		t.Errorf("totals - mutex %v, actor %v, want %v", gotMutex, gotActor, expected)
+	}
+}
+
+// Close returns once the owner goroutine has, and the counter can't be used after it
+func TestActorCounterClose(t *testing.T) {
+	actor := newActorCounter()
+	for range 10 {
+		actor.SafeInc("k")
+	}
+	got := actor.GetValue("k")
+	actor.Close()
+	select {
+	case <-actor.done:
+	default:
+		t.Error("Close returned before the owner goroutine did")
+	}
+	if got != 10 {
+		t.Errorf("GetValue = %d, want 10", got)
+	}
+	wantPanic(t, "SafeInc after Close", func() { actor.SafeInc("k") })
+}
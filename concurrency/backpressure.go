@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"strings"
+	"time"
+)
+
+// === Backpressure - a fast producer and a slow consumer ===
+
+// The buffered channel notes above say a sender can keep sending at a "rate" without waiting.
+// That's only true until the buffer fills: from then on every send waits for a receive,
+// so the producer runs at the CONSUMER's speed. That slowing down of the producer is backpressure -
+// the buffer only absorbs bursts, it doesn't make a slow consumer faster.
+//
+// A queue without a limit (a slice that grows) never pushes back. The producer finishes right away,
+// and everything the consumer hasn't got to yet is held in memory - which grows for as long as
+// the producer is faster, until the process runs out of it.
+//
+// The other choices when the queue is full: drop the value (a select with a default case, like
+// the heartbeats in selectpatterns.go), or fail the request so the caller can back off (ex. HTTP 503).
+
+const backpressurePayload = 32 << 10 // bytes per item, so the memory held by a queue is visible
+
+type backpressureItem struct {
+	ID      int
+	Payload []byte
+}
+
+// pressureQueue is what the producer and the consumer share: a buffered channel, or an unbounded slice
+type pressureQueue interface {
+	Put(backpressureItem)
+	Close()
+	Get() (backpressureItem, bool)
+	Len() int
+}
+
+type chanQueue chan backpressureItem
+
+func (q chanQueue) Put(v backpressureItem) { q <- v }
+func (q chanQueue) Close()                 { close(q) }
+func (q chanQueue) Len() int               { return len(q) }
+func (q chanQueue) Get() (backpressureItem, bool) {
+	v, ok := <-q
+	return v, ok
+}
+
+// sliceQueue is NewUnboundedQueue's BoundedQueue (see syncprims.go) - Put appends and never waits
+type sliceQueue struct {
+	*BoundedQueue[backpressureItem]
+}
+
+func newSliceQueue() sliceQueue { return sliceQueue{NewUnboundedQueue[backpressureItem]()} }
+
+func (q sliceQueue) Put(v backpressureItem) { q.BoundedQueue.Put(v) }
+
+type backpressureRun struct {
+	Produced, Consumed time.Duration // how long until the producer sent its last item / the consumer took it
+	Blocked            time.Duration // producer time spent waiting in Put
+	Depths             []int         // queue length, sampled every few milliseconds
+	PeakHeap           uint64        // most heap in use during the run, above what was in use before
+}
+
+func (r backpressureRun) maxDepth() int {
+	m := 0
+	for _, d := range r.Depths {
+		m = max(m, d)
+	}
+	return m
+}
+
+// runBackpressure sends n items through q as fast as possible, and receives them taking work per item.
+// Items the consumer is done with are garbage, but still count as heap until a GC runs -
+// so GC runs far more often than usual meanwhile, and the peak is close to what the queue really holds
+func runBackpressure(q pressureQueue, n int, work time.Duration) backpressureRun {
+	defer debug.SetGCPercent(debug.SetGCPercent(5))
+	heap := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	readHeap := func() uint64 {
+		metrics.Read(heap)
+		return heap[0].Value.Uint64()
+	}
+	runtime.GC()
+	base := readHeap()
+
+	var run backpressureRun
+	start := time.Now()
+	stopSampling, sampled := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(sampled)
+		tick := time.NewTicker(5 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			run.Depths = append(run.Depths, q.Len())
+			run.PeakHeap = max(run.PeakHeap, readHeap())
+			select {
+			case <-stopSampling:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		for i := range n {
+			v := backpressureItem{ID: i, Payload: make([]byte, backpressurePayload)}
+			t := time.Now()
+			q.Put(v)
+			run.Blocked += time.Since(t)
+		}
+		run.Produced = time.Since(start)
+		q.Close()
+	}()
+
+	for {
+		if _, ok := q.Get(); !ok {
+			break
+		}
+		time.Sleep(work) // the consumer is the slow part (ex. a database write per item)
+	}
+	run.Consumed = time.Since(start)
+	<-produced
+	close(stopSampling)
+	<-sampled
+	run.PeakHeap -= min(base, run.PeakHeap)
+	return run
+}
+
+// depthSparkline shows every few samples of the queue depth, scaled to 0-8
+func depthSparkline(depths []int, limit int) string {
+	bars := []rune(" ▁▂▃▄▅▆▇█")
+	var sb strings.Builder
+	step := max(1, len(depths)/40)
+	for i := 0; i < len(depths); i += step {
+		sb.WriteRune(bars[min(8, depths[i]*8/max(1, limit))])
+	}
+	return sb.String()
+}
+
+func backpressureExample() {
+	const (
+		n        = 300
+		capacity = 8
+		work     = time.Millisecond
+	)
+	bounded := runBackpressure(make(chanQueue, capacity), n, work)
+	unbounded := runBackpressure(newSliceQueue(), n, work)
+
+	fmt.Printf("%d items of %d KB, the consumer taking %v each\n\n", n, backpressurePayload>>10, work)
+	fmt.Printf("%-22s %12s %12s %12s %10s %12s\n", "queue", "producer", "blocked", "consumer", "max depth", "peak heap")
+	for _, r := range []struct {
+		name string
+		run  backpressureRun
+	}{{fmt.Sprintf("chan, buffer of %d", capacity), bounded}, {"slice, unbounded", unbounded}} {
+		fmt.Printf("%-22s %12v %12v %12v %10d %9d KB\n", r.name, r.run.Produced.Round(time.Millisecond),
+			r.run.Blocked.Round(time.Millisecond), r.run.Consumed.Round(time.Millisecond), r.run.maxDepth(), r.run.PeakHeap>>10)
+	}
+	fmt.Printf("\nqueue depth over time (full height = %d items):\n", n)
+	fmt.Printf("  chan   |%s|\n", depthSparkline(bounded.Depths, n))
+	fmt.Printf("  slice  |%s|\n", depthSparkline(unbounded.Depths, n))
+	fmt.Printf("  chan, scaled to its buffer of %d: |%s|\n\n", capacity, depthSparkline(bounded.Depths, capacity))
+
+	// Bounded: the buffer stays (nearly) full, and the producer spends most of the run waiting -
+	// it finishes only a buffer's worth of items before the consumer does
+	fmt.Println("chan: depth never above the buffer:", bounded.maxDepth() <= capacity,
+		"| producer blocked most of its run:", bounded.Blocked > bounded.Produced/2,
+		"| producer slowed to the consumer's pace:", bounded.Produced > bounded.Consumed*3/4)
+	// Unbounded: no waiting at all, and nearly every item sits in memory at once
+	fmt.Println("slice: producer never blocked:", unbounded.Blocked < bounded.Blocked/10,
+		"| finished long before the consumer:", unbounded.Produced < unbounded.Consumed/4,
+		"| queue grew to most of the items:", unbounded.maxDepth() > n/2)
+	// All the payloads together are n*backpressurePayload - the slice held most of them at once,
+	// the channel only a buffer's worth (plus garbage waiting for the next GC)
+	all := uint64(n * backpressurePayload)
+	fmt.Println("memory: slice peak over half of all payloads:", unbounded.PeakHeap > all/2,
+		"| chan peak under a quarter:", bounded.PeakHeap < all/4)
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// === Pub/sub broker ===
+
+// A Broker delivers every message published on a topic to every subscriber of that topic.
+//
+// Each topic has one goroutine (the "dispatcher") that owns the subscriber channels:
+//
+//	Publish --> topic.queue (buffered) --> dispatcher --> subscriber chan 1
+//	Publish -/                                       \--> subscriber chan 2
+//
+// The dispatcher is the ONLY goroutine that sends on the subscriber channels,
+// so it is the only one allowed to close them (the "only the sender closes" rule from the Range and Close notes).
+// Publishers are the senders on the queue, so closing the queue needs care too - see CloseTopic.
+//
+// Slow subscribers: with one dispatcher per topic, a subscriber that stops reading (and has a full buffer)
+// holds up every other subscriber of the topic, and then the publishers once the queue fills up.
+// Each subscription picks what happens when its buffer is full (a DropPolicy):
+// - Block: wait for the subscriber (nothing is lost, but one slow reader slows everyone down)
+// - DropNewest: skip the new message for this subscriber
+// - DropOldest: throw away the oldest buffered message to make room (the subscriber sees the latest ones)
+
+var ErrTopicClosed = errors.New("broker: topic closed")
+
+type DropPolicy int
+
+const (
+	Block DropPolicy = iota
+	DropNewest
+	DropOldest
+)
+
+type subscription[T any] struct {
+	ch      chan T
+	policy  DropPolicy
+	stop    chan struct{} // closed by Unsubscribe, so a Block send to a reader that has gone away gives up
+	dropped atomic.Int64
+}
+
+// deliver is only called by the dispatcher - the one sender on s.ch
+func (s *subscription[T]) deliver(msg T) {
+	switch s.policy {
+	case Block:
+		select {
+		case s.ch <- msg:
+		case <-s.stop:
+		}
+	case DropNewest:
+		select {
+		case s.ch <- msg:
+		default:
+			s.dropped.Add(1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.ch <- msg:
+				return
+			default:
+			}
+			// full - receive the oldest message ourselves (the subscriber may take it first, then the retry succeeds)
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+type topic[T any] struct {
+	// mu guards closed, and is held (read locked) by Publish while it sends on queue
+	mu     sync.RWMutex
+	closed bool
+
+	// subsMu guards the subscriber list. It is a separate lock on purpose: if the dispatcher needed mu,
+	// a CloseTopic waiting for the write lock would block the dispatcher's read lock (pending writers block new readers),
+	// while a Publish holding the read lock waits for the dispatcher to make room in the queue - a deadlock
+	subsMu     sync.Mutex
+	subs       []*subscription[T]
+	subsClosed bool
+
+	// byChan finds the subscription for Unsubscribe. Its own lock, since subsMu can be held
+	// by a dispatcher blocked on the very subscriber that is unsubscribing
+	byChanMu sync.Mutex
+	byChan   map[<-chan T]*subscription[T]
+
+	queue chan T        // messages waiting for the dispatcher
+	done  chan struct{} // closed by the dispatcher once every subscriber channel is closed
+}
+
+type Broker[T any] struct {
+	mu        sync.Mutex
+	topics    map[string]*topic[T]
+	queueSize int
+	subBuffer int
+}
+
+// queueSize is how many published messages can wait for the dispatcher,
+// subBuffer is the buffer size of each subscriber's channel. Neither can be negative
+func NewBroker[T any](queueSize, subBuffer int) *Broker[T] {
+	if queueSize < 0 || subBuffer < 0 {
+		panic("NewBroker: negative queueSize or subBuffer")
+	}
+	return &Broker[T]{
+		topics:    make(map[string]*topic[T]),
+		queueSize: queueSize,
+		subBuffer: subBuffer,
+	}
+}
+
+// getTopic returns the topic, creating it (and starting its dispatcher) on first use
+func (b *Broker[T]) getTopic(name string) *topic[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic[T]{
+			queue:  make(chan T, b.queueSize),
+			done:   make(chan struct{}),
+			byChan: make(map[<-chan T]*subscription[T]),
+		}
+		b.topics[name] = t
+		go t.dispatch()
+	}
+	return t
+}
+
+// lookupTopic returns the topic if it exists - for the calls that only look, so they don't create a topic
+// (and start a dispatcher that nothing stops until Close) for every name they're asked about
+func (b *Broker[T]) lookupTopic(name string) (*topic[T], bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.topics[name]
+	return t, ok
+}
+
+func (t *topic[T]) dispatch() {
+	// range ends once CloseTopic closes the queue AND every queued message has been received,
+	// so messages published before the close are still delivered (flushed)
+	for msg := range t.queue {
+		t.subsMu.Lock()
+		for _, sub := range t.subs {
+			sub.deliver(msg)
+		}
+		t.subsMu.Unlock()
+	}
+
+	// Phase 2 - nothing else can send on the subscriber channels now, so it is safe to close them
+	t.subsMu.Lock()
+	for _, sub := range t.subs {
+		close(sub.ch)
+	}
+	t.subs = nil
+	t.subsClosed = true
+	t.subsMu.Unlock()
+	close(t.done)
+}
+
+// Subscribe returns a channel receiving every message published on the topic from now on.
+// The channel is closed when the topic is closed, so subscribers can simply range over it.
+// A subscriber that falls behind slows down the topic (the Block policy)
+func (b *Broker[T]) Subscribe(name string) <-chan T {
+	return b.SubscribeWithPolicy(name, Block)
+}
+
+// SubscribeWithPolicy is Subscribe, with a choice of what happens when the subscriber's buffer is full.
+// DropOldest needs a subBuffer of at least 1: with no buffer there's no oldest message to drop, and the
+// dispatcher would spin until the subscriber happens to be receiving
+func (b *Broker[T]) SubscribeWithPolicy(name string, policy DropPolicy) <-chan T {
+	if policy == DropOldest && b.subBuffer < 1 {
+		panic("SubscribeWithPolicy: DropOldest needs a subscriber buffer of at least 1")
+	}
+	t := b.getTopic(name)
+	sub := &subscription[T]{ch: make(chan T, b.subBuffer), policy: policy, stop: make(chan struct{})}
+
+	t.subsMu.Lock()
+	defer t.subsMu.Unlock()
+	if t.subsClosed {
+		close(sub.ch) // subscribing to a closed topic gives an already closed channel
+		return sub.ch
+	}
+	t.subs = append(t.subs, sub)
+	t.byChanMu.Lock()
+	t.byChan[sub.ch] = sub
+	t.byChanMu.Unlock()
+	return sub.ch // returned as receive-only, so subscribers can't send on (or close) it
+}
+
+// Unsubscribe stops deliveries to ch and closes it. Messages already in its buffer can still be received.
+// It is safe to call more than once, and after the topic is closed
+func (b *Broker[T]) Unsubscribe(name string, ch <-chan T) {
+	t, ok := b.lookupTopic(name)
+	if !ok {
+		return // never subscribed to
+	}
+	t.byChanMu.Lock()
+	sub, ok := t.byChan[ch]
+	delete(t.byChan, ch)
+	t.byChanMu.Unlock()
+	if !ok {
+		return
+	}
+
+	close(sub.stop) // first, so a dispatcher blocked sending to this subscriber lets go of subsMu
+
+	t.subsMu.Lock()
+	defer t.subsMu.Unlock()
+	if i := slices.Index(t.subs, sub); i >= 0 {
+		t.subs = slices.Delete(t.subs, i, i+1)
+		close(sub.ch) // under subsMu - the dispatcher only sends while holding it, so no send can race the close
+	}
+}
+
+// Dropped is the number of messages ch missed because of its drop policy (0 once ch is unsubscribed)
+func (b *Broker[T]) Dropped(name string, ch <-chan T) int64 {
+	t, ok := b.lookupTopic(name)
+	if !ok {
+		return 0
+	}
+	t.byChanMu.Lock()
+	defer t.byChanMu.Unlock()
+	if sub, ok := t.byChan[ch]; ok {
+		return sub.dropped.Load()
+	}
+	return 0
+}
+
+// Publish queues msg for every current subscriber of the topic.
+// It blocks while the topic's queue is full, and returns ErrTopicClosed once CloseTopic has been called.
+func (b *Broker[T]) Publish(name string, msg T) error {
+	t := b.getTopic(name)
+
+	// Holding the read lock during the send is what makes CloseTopic safe:
+	// many publishers can send at once (read lock), but CloseTopic's write lock waits for all of them
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return ErrTopicClosed
+	}
+	t.queue <- msg
+	return nil
+}
+
+// CloseTopic shuts a topic down in two phases and returns once both are complete:
+//  1. stop accepting publishes - mark the topic closed and close the queue
+//  2. the dispatcher flushes every queued message to the subscribers, then closes their channels
+//
+// Messages accepted by Publish (returned nil) before CloseTopic are never lost.
+func (b *Broker[T]) CloseTopic(name string) {
+	t, ok := b.lookupTopic(name)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	if !t.closed {
+		// With the write lock held no Publish is mid-send, and every later Publish sees closed,
+		// so nothing can send on the queue after it is closed (no "send on closed channel" panic)
+		t.closed = true
+		close(t.queue)
+	}
+	t.mu.Unlock()
+
+	<-t.done
+}
+
+// Close closes every topic
+func (b *Broker[T]) Close() {
+	b.mu.Lock()
+	names := make([]string, 0, len(b.topics))
+	for name := range b.topics {
+		names = append(names, name)
+	}
+	b.mu.Unlock()
+
+	for _, name := range names {
+		b.CloseTopic(name)
+	}
+}
+
+func brokerExample() {
+	broker := NewBroker[string](8, 8)
+
+	news := broker.Subscribe("news")
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for msg := range news {
+			fmt.Println("subscriber received:", msg)
+		}
+		fmt.Println("subscriber: channel closed")
+	})
+
+	broker.Publish("news", "hello")
+	broker.Publish("news", "world")
+	broker.CloseTopic("news")
+	wg.Wait()
+
+	fmt.Println("publish after close:", broker.Publish("news", "too late"))
+}
+
+// Ex. closing a topic while many goroutines are still publishing.
+// Run with the race detector: go run -race .
+// Every message that Publish accepted must reach every subscriber, and nothing panics
+func brokerCloseUnderLoadExample() {
+	broker := NewBroker[int](4, 4)
+
+	const numSubs = 3
+	var received [numSubs]atomic.Int64
+	var subsWg sync.WaitGroup
+	for i := range numSubs {
+		ch := broker.Subscribe("load")
+		subsWg.Go(func() {
+			for range ch {
+				received[i].Add(1)
+			}
+		})
+	}
+
+	var accepted, rejected atomic.Int64
+	var pubWg sync.WaitGroup
+	for p := range 8 {
+		pubWg.Go(func() {
+			for i := range 1000 {
+				if err := broker.Publish("load", p*1000+i); err != nil {
+					rejected.Add(1)
+				} else {
+					accepted.Add(1)
+				}
+			}
+		})
+	}
+
+	// close while the publishers are still going
+	for accepted.Load() < 2000 {
+		runtime.Gosched()
+	}
+	broker.CloseTopic("load")
+	pubWg.Wait()
+	subsWg.Wait()
+
+	fmt.Printf("accepted: %d, rejected after close: %d\n", accepted.Load(), rejected.Load())
+	for i := range numSubs {
+		fmt.Printf("subscriber %d received %d (all accepted: %t)\n", i, received[i].Load(), received[i].Load() == accepted.Load())
+	}
+}
+
+// Ex. slow subscribers and drop policies
+
+type Message struct {
+	Seq  int
+	Body string
+}
+
+func brokerSlowSubscriberExample() {
+	seqs := func(ch <-chan Message) []int {
+		var got []int
+		for m := range ch {
+			got = append(got, m.Seq)
+		}
+		return got
+	}
+
+	// Two subscribers that don't read at all until the end, with a buffer of 4 each.
+	// A third reads as fast as it can - and isn't held up by the other two
+	broker := NewBroker[Message](4, 4)
+	fast := broker.Subscribe("ticks")
+	newest := broker.SubscribeWithPolicy("ticks", DropNewest)
+	oldest := broker.SubscribeWithPolicy("ticks", DropOldest)
+
+	var fastGot []int
+	var wg sync.WaitGroup
+	wg.Go(func() { fastGot = seqs(fast) })
+	for i := range 20 {
+		broker.Publish("ticks", Message{Seq: i, Body: fmt.Sprint("tick ", i)})
+	}
+	broker.CloseTopic("ticks") // flushes the queue - the Dropped counts are final after this
+	droppedNewest, droppedOldest := broker.Dropped("ticks", newest), broker.Dropped("ticks", oldest)
+	wg.Wait()
+
+	fmt.Println("fast subscriber got all 20:", len(fastGot) == 20)
+	fmt.Println("drop newest kept:", seqs(newest), "dropped:", droppedNewest) // the first 4
+	fmt.Println("drop oldest kept:", seqs(oldest), "dropped:", droppedOldest) // the last 4
+
+	// With Block, one stuck subscriber stalls the whole topic - until it unsubscribes
+	broker = NewBroker[Message](4, 4)
+	stuck := broker.Subscribe("ticks")
+	var published atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 20 {
+			broker.Publish("ticks", Message{Seq: i})
+			published.Add(1)
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+	// 4 in stuck's buffer + 1 the dispatcher is trying to send + 4 in the queue
+	fmt.Println("published before stalling:", published.Load())
+
+	broker.Unsubscribe("ticks", stuck) // releases the dispatcher, and closes stuck's channel
+	<-done
+	fmt.Println("published after unsubscribing:", published.Load(), "| stuck still has its buffered:", seqs(stuck))
+	broker.Unsubscribe("ticks", stuck) // a second call does nothing
+	broker.Close()
+
+	// Looking at a topic nobody subscribed to doesn't create it - no dispatcher is started for it
+	before := runtime.NumGoroutine()
+	broker = NewBroker[Message](4, 4)
+	for i := range 10 {
+		broker.Unsubscribe(fmt.Sprint("nobody-", i), fast)
+		broker.Dropped(fmt.Sprint("nobody-", i), fast)
+	}
+	fmt.Println("Unsubscribe and Dropped on unknown topics - goroutines started:", runtime.NumGoroutine()-before)
+}
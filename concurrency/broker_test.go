@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// CloseTopic while publishers are mid-send: every message Publish accepted reaches every subscriber,
+// every later one is ErrTopicClosed, and nothing sends on a closed channel (run with -race)
+func TestBrokerCloseUnderLoad(t *testing.T) {
+	const numSubs, publishers, perPublisher = 3, 8, 1000
+	for round := range 20 {
+		broker := NewBroker[int](4, 4)
+		received := make([][]int, numSubs)
+		var subsWg sync.WaitGroup
+		for i := range numSubs {
+			ch := broker.Subscribe("load")
+			subsWg.Go(func() {
+				for msg := range ch {
+					received[i] = append(received[i], msg)
+				}
+			})
+		}
+
+		accepted := make([][]int, publishers)
+		var pubWg, started sync.WaitGroup
+		started.Add(publishers)
+		for p := range publishers {
+			pubWg.Go(func() {
+				started.Done()
+				for i := range perPublisher {
+					msg := p*perPublisher + i
+					err := broker.Publish("load", msg)
+					switch {
+					case err == nil:
+						accepted[p] = append(accepted[p], msg)
+					case !errors.Is(err, ErrTopicClosed):
+						t.Errorf("Publish: %v", err)
+					}
+				}
+			})
+		}
+		started.Wait()
+		if round%2 == 1 {
+			time.Sleep(time.Millisecond) // some rounds close later, with more accepted
+		}
+		broker.CloseTopic("load")
+		pubWg.Wait()
+		subsWg.Wait()
+
+		// after CloseTopic returns, nothing more is accepted
+		if err := broker.Publish("load", -1); !errors.Is(err, ErrTopicClosed) {
+			t.Fatalf("Publish after CloseTopic: %v, want ErrTopicClosed", err)
+		}
+
+		want := slices.Concat(accepted...)
+		slices.Sort(want)
+		for i, got := range received {
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("round %d: subscriber %d received %d messages, %d were accepted", round, i, len(got), len(want))
+			}
+		}
+		// each publisher's accepted messages are a prefix of what it tried - nothing accepted after a rejection
+		for p, msgs := range accepted {
+			for i, msg := range msgs {
+				if msg != p*perPublisher+i {
+					t.Fatalf("round %d: publisher %d had message %d accepted after one was rejected", round, p, msg)
+				}
+			}
+		}
+	}
+}
+
+// Closing a topic from several goroutines, and the whole broker, at once
+func TestBrokerConcurrentClose(t *testing.T) {
+	broker := NewBroker[int](1, 1)
+	var subs []<-chan int
+	for _, name := range []string{"a", "b", "c"} {
+		subs = append(subs, broker.Subscribe(name))
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() { broker.CloseTopic("a") })
+		wg.Go(broker.Close)
+	}
+	wg.Wait()
+	for i, ch := range subs {
+		if _, ok := <-ch; ok {
+			t.Errorf("subscriber %d: channel still open", i)
+		}
+	}
+}
+
+func TestBrokerDelivery(t *testing.T) {
+	broker := NewBroker[int](4, 16)
+	a, b := broker.Subscribe("t"), broker.Subscribe("t")
+	other := broker.Subscribe("other")
+	for i := range 10 {
+		broker.Publish("t", i)
+	}
+	broker.Close()
+	want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	for name, ch := range map[string]<-chan int{"a": a, "b": b} {
+		if got := slices.Collect(chanSeq(ch)); !slices.Equal(got, want) {
+			t.Errorf("subscriber %s got %v, want %v in order", name, got, want)
+		}
+	}
+	if got := slices.Collect(chanSeq(other)); len(got) != 0 {
+		t.Errorf("another topic's subscriber got %v", got)
+	}
+	// subscribing to a closed topic gives a closed channel
+	if _, ok := <-broker.Subscribe("t"); ok {
+		t.Error("Subscribe after close: channel open")
+	}
+}
+
+// chanSeq ranges over ch until it's closed
+func chanSeq[T any](ch <-chan T) func(func(T) bool) {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Subscribers that never read, with buffers of 4: DropNewest keeps the first messages, DropOldest the last,
+// and a Block subscriber reading along isn't held up by either
+func TestBrokerDropPolicies(t *testing.T) {
+	broker := NewBroker[int](4, 4)
+	fast := broker.Subscribe("ticks")
+	newest := broker.SubscribeWithPolicy("ticks", DropNewest)
+	oldest := broker.SubscribeWithPolicy("ticks", DropOldest)
+	var fastGot []int
+	var wg sync.WaitGroup
+	wg.Go(func() { fastGot = slices.Collect(chanSeq(fast)) })
+	for i := range 20 {
+		broker.Publish("ticks", i)
+	}
+	broker.CloseTopic("ticks")
+	droppedNewest, droppedOldest := broker.Dropped("ticks", newest), broker.Dropped("ticks", oldest)
+	wg.Wait()
+
+	if len(fastGot) != 20 {
+		t.Errorf("Block subscriber got %d of 20", len(fastGot))
+	}
+	if got := slices.Collect(chanSeq(newest)); !slices.Equal(got, []int{0, 1, 2, 3}) || droppedNewest != 16 {
+		t.Errorf("DropNewest kept %v, dropped %d; want [0 1 2 3] and 16", got, droppedNewest)
+	}
+	if got := slices.Collect(chanSeq(oldest)); !slices.Equal(got, []int{16, 17, 18, 19}) || droppedOldest != 16 {
+		t.Errorf("DropOldest kept %v, dropped %d; want [16 17 18 19] and 16", got, droppedOldest)
+	}
+}
+
+func TestBrokerDropOldestNeedsBuffer(t *testing.T) {
+	wantPanic(t, "DropOldest without a buffer", func() { NewBroker[int](1, 0).SubscribeWithPolicy("t", DropOldest) })
+	wantPanic(t, "negative queueSize", func() { NewBroker[int](-1, 1) })
+}
+
+// A Block subscriber that stops reading stalls the topic; unsubscribing it lets the publishers go on
+func TestBrokerUnsubscribeReleasesStalledTopic(t *testing.T) {
+	broker := NewBroker[int](4, 4)
+	stuck := broker.Subscribe("ticks")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 20 {
+			broker.Publish("ticks", i)
+		}
+	}()
+	select {
+	case <-done:
+		t.Fatal("20 publishes went through with a stuck subscriber and room for 9")
+	case <-time.After(20 * time.Millisecond):
+	}
+	broker.Unsubscribe("ticks", stuck)
+	<-done
+	if got := slices.Collect(chanSeq(stuck)); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("after Unsubscribe the buffer still had %v, want [0 1 2 3]", got)
+	}
+	broker.Unsubscribe("ticks", stuck) // a second call does nothing
+	broker.Close()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// === Channel benchmarks - buffer sizes, producers and consumers ===
+
+// The buffered channel notes say a buffer lets a sender keep going without waiting for a receiver.
+// What that buys, in time per item:
+// - unbuffered: every send waits for a receive, so each item is a hand-off between two goroutines -
+//   often a goroutine switch (on one CPU, always)
+// - buffered: the sender fills the buffer in one go, then the receiver empties it in one go -
+//   the switches happen once per buffer instead of once per item
+// - more producers / consumers: they take turns on the channel's one internal lock,
+//   so beyond a few of them the channel itself is the bottleneck
+// Latency - how long one item takes to get across - doesn't improve with a buffer: an idle receiver
+// waiting for the next item gets it just as fast either way.
+
+// channelThroughput sends n items from producers to consumers through a channel of the given size, and
+// returns how many arrived
+func channelThroughput(size, producers, consumers, n int) int {
+	ch := make(chan int, size)
+	var send, recv sync.WaitGroup
+	for p := range producers {
+		count := n / producers
+		if p == 0 {
+			count += n % producers
+		}
+		send.Go(func() {
+			for i := range count {
+				ch <- i
+			}
+		})
+	}
+	received := make([]int, consumers) // one counter each, summed at the end: no sharing while it runs
+	for c := range consumers {
+		recv.Go(func() {
+			for range ch {
+				received[c]++
+			}
+		})
+	}
+	send.Wait()
+	close(ch)
+	recv.Wait()
+	total := 0
+	for _, r := range received {
+		total += r
+	}
+	return total
+}
+
+// channelRoundTrips bounces n items, one at a time, off a second goroutine, and returns the sum of what
+// came back
+func channelRoundTrips(size, n int) int {
+	ping, pong := make(chan int, size), make(chan int, size)
+	go func() {
+		for v := range ping {
+			pong <- v
+		}
+		close(pong)
+	}()
+	sum := 0
+	for i := range n {
+		ping <- i
+		sum += <-pong
+	}
+	close(ping)
+	return sum
+}
+
+// timePerItem is f(n)'s time divided by n, after one untimed run to warm up. A rough timing:
+// BenchmarkChannelThroughput and BenchmarkChannelLatency in chanbench_test.go are the careful ones
+func timePerItem(n int, f func(n int) int) time.Duration {
+	f(n / 10)
+	start := time.Now()
+	f(n)
+	return time.Since(start) / time.Duration(n)
+}
+
+func channelBenchmarkExample() {
+	sizes := []int{0, 1, 16, 256}
+	shapes := []struct{ producers, consumers int }{{1, 1}, {4, 4}, {16, 1}, {1, 16}}
+
+	// (the checks are in chanbench_test.go)
+	const n = 200_000
+	fmt.Printf("ns per item, GOMAXPROCS=%d\n", runtime.GOMAXPROCS(0))
+	fmt.Println("(go test -bench Channel concurrency measures the same, more carefully)")
+	fmt.Printf("%-10s", "buffer")
+	for _, s := range shapes {
+		fmt.Printf(" %8s", fmt.Sprintf("%d->%d", s.producers, s.consumers))
+	}
+	fmt.Printf(" %14s\n", "round trip")
+
+	perItem := map[int]time.Duration{} // buffer size -> ns per item, 1 producer and 1 consumer
+	for _, size := range sizes {
+		fmt.Printf("%-10d", size)
+		for _, s := range shapes {
+			d := timePerItem(n, func(n int) int { return channelThroughput(size, s.producers, s.consumers, n) })
+			if s.producers == 1 && s.consumers == 1 {
+				perItem[size] = d
+			}
+			fmt.Printf(" %8d", d.Nanoseconds())
+		}
+		d := timePerItem(n, func(n int) int { return channelRoundTrips(size, n) })
+		fmt.Printf(" %14d\n", d.Nanoseconds())
+	}
+
+	fmt.Printf("\n1->1: a buffer of 256 is %.1fx the throughput of unbuffered, a buffer of 1 is %.1fx\n",
+		float64(perItem[0])/float64(perItem[256]), float64(perItem[0])/float64(perItem[1]))
+	fmt.Println("(the round trip column barely changes - a buffer helps throughput, not latency)")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Every item arrives once, for each buffer size and shape - n not a multiple of the producers included
+func TestChannelThroughput(t *testing.T) {
+	for _, size := range []int{0, 1, 16} {
+		for _, s := range []struct{ producers, consumers int }{{1, 1}, {4, 4}, {16, 1}, {1, 16}, {3, 2}} {
+			if got := channelThroughput(size, s.producers, s.consumers, 1001); got != 1001 {
+				t.Errorf("buffer %d, %d->%d: %d items arrived, want 1001", size, s.producers, s.consumers, got)
+			}
+		}
+	}
+	if got := channelThroughput(0, 4, 1, 0); got != 0 {
+		t.Errorf("no items: %d arrived", got)
+	}
+}
+
+func TestChannelRoundTrips(t *testing.T) {
+	for _, size := range []int{0, 1, 16} {
+		if got, want := channelRoundTrips(size, 1000), 999*1000/2; got != want {
+			t.Errorf("buffer %d: the echoes sum to %d, want %d", size, got, want)
+		}
+	}
+}
+
+// ns/op is the time per item, by buffer size and producers->consumers. go test -bench ChannelThroughput concurrency
+func BenchmarkChannelThroughput(b *testing.B) {
+	for _, size := range []int{0, 1, 16, 256} {
+		for _, s := range []struct{ producers, consumers int }{{1, 1}, {4, 4}, {16, 1}, {1, 16}} {
+			b.Run(fmt.Sprintf("buffer=%d/%d->%d", size, s.producers, s.consumers), func(b *testing.B) {
+				channelThroughput(size, s.producers, s.consumers, b.N)
+			})
+		}
+	}
+}
+
+// ns/op is one round trip - it barely changes with the buffer size
+func BenchmarkChannelLatency(b *testing.B) {
+	for _, size := range []int{0, 1, 16, 256} {
+		b.Run(fmt.Sprintf("buffer=%d", size), func(b *testing.B) {
+			channelRoundTrips(size, b.N)
+		})
+	}
+}
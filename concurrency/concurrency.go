@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -37,7 +38,7 @@ func goroutineExample() {
 // A typed conduit through which you can send / recieve values
 // Used to safely transfer data and sync executation between conc. goroutines
 // Use the channel operator, <- (data flows in the direction of the arrow)
-// Channels are bidirectional by default (can set as unidirectional for send only or receive only)
+// Channels are bidirectional by default (can set as unidirectional for send only or receive only) - see generators.go
 func channelExample() {
 
 	// Channels must be created before use, like maps and slices
@@ -53,6 +54,7 @@ func channelExample() {
 	halfSum2 := <-ch
 	// Note - there is no guarantee which goroutine sum goes into which variable
 	// (same reason as mentioned in goroutineExample above)
+	// (chunkedSum in mapreduce.go does the same split into any number of parts)
 
 	sum := halfSum1 + halfSum2
 
@@ -110,6 +112,19 @@ func deadlockExampleEmptyBufferBlock() {
 	fmt.Println(<-ch)
 }
 
+// The runtime only reports a deadlock when EVERY goroutine is blocked, with no timer left that could wake one.
+// A single goroutine that could still wake up (here a ticker) hides the deadlock, and the program just hangs -
+// the runtime can't tell that nothing will ever send on ch.
+// (To see what it was stuck on: go run . run -timeout 2s hiddenDeadlockExample from the tour dir)
+func hiddenDeadlockExample() {
+	go func() {
+		for range time.Tick(time.Second) {
+		}
+	}()
+	ch := make(chan int)
+	fmt.Println(<-ch)
+}
+
 // --- Unbuffered channels ---
 
 // See notes in channelExample()
@@ -143,6 +158,51 @@ func noDeadlockUnbufferedChannel() {
 	fmt.Println(<-ch)
 }
 
+// Ex. Ping-pong - two goroutines taking turns over one unbuffered channel (the table)
+
+// The ball is a pointer, and hits / rally are changed without a lock - only the goroutine holding
+// the ball ever touches it. Handing it over the channel is the synchronization
+// (everything the sender did happens before the receiver gets it, so go run -race is quiet)
+type ball struct {
+	hits  int
+	rally []string // who hit it, in order
+}
+
+// player receives the ball, hits it, and sends it back. Whoever makes the last hit closes the table
+// instead - it's the only one that could be sending, so that's safe - and the other player's range ends
+func player(name string, table chan *ball, maxHits int, done chan<- struct{}) {
+	defer func() { done <- struct{}{} }()
+	for b := range table {
+		b.hits++
+		b.rally = append(b.rally, name)
+		if b.hits == maxHits {
+			close(table)
+			return
+		}
+		table <- b // blocks until the other player receives it - they can't both hold the ball
+	}
+}
+
+func pingPongExample() {
+	const maxHits = 10
+	table := make(chan *ball)
+	done := make(chan struct{})
+	go player("ping", table, maxHits, done)
+	go player("pong", table, maxHits, done)
+
+	b := &ball{}
+	table <- b // serve - whichever player is receiving first gets it
+	<-done
+	<-done
+
+	alternates := true
+	for i := 1; i < len(b.rally); i++ {
+		alternates = alternates && b.rally[i] != b.rally[i-1]
+	}
+	fmt.Println("rally:", b.rally)
+	fmt.Println("hits:", b.hits, "| strictly alternating:", alternates)
+}
+
 // --- Channel Summary and Use cases: ---
 
 // Both: for synchronized communication between goroutines
@@ -156,6 +216,7 @@ func noDeadlockUnbufferedChannel() {
 // - For semi-synchronous communication - asynchronous but blocks if producer empty or if consumer full
 // - Allows a sender to continously send messages with set "rate" (based on num elements)
 // 	 without waiting for a receiver, reducing blocking
+// 	 (until the buffer is full - then the sender waits at the receiver's pace, see backpressure.go)
 
 // === Range and Close ===
 
@@ -220,6 +281,7 @@ func loopThroughValsUntilChannelClosedEx() {
 // The select statement lets a goroutine wait on multiple communication operations.
 // A select blocks until one of its cases can run, then it executes that case.
 // It chooses one at random if multiple are ready.
+// So a select has no favourite channel - to always drain one first, see prioritySelectExample in selectpatterns.go
 func fibonacci(c, quit chan int) {
 	x, y := 0, 1
 	defaultExecuted := false
@@ -258,8 +320,14 @@ func selectEx() {
 
 // Go provides mutual exclusion for when communication among goroutines are not needed
 // std library provides the libraries sync.Mutex, with Lock and Unlock methods
+// sync.RWMutex is a Mutex that also has RLock and RUnlock: any number of readers can hold it at once,
+// but Lock (a writer) waits for all of them and keeps everyone else out (see rwMutexBenchmarkExample)
+// Why a Mutex rather than a goroutine owning the counter: writersBenchmarkExample in syncatomic.go has the numbers
+// (and actorCounter in actorcounter.go is SafeCounter the other way - a goroutine owning the map)
+// (the rpc module serves a SafeCounter to other processes with net/rpc)
+// (the kvstore module is the same mutex-guarded map, made durable with a write-ahead log)
 type SafeCounter struct {
-	mu sync.Mutex
+	mu sync.RWMutex
 	v  map[string]int
 }
 
@@ -277,9 +345,10 @@ func (counter *SafeCounter) SafeInc(key string) {
 }
 
 func (counter *SafeCounter) GetValue(key string) int {
-	counter.mu.Lock()
+	// Only reads the map, so a read lock is enough - GetValue calls don't block each other
+	counter.mu.RLock()
 	// can unlock once function finishes executing using defer
-	defer counter.mu.Unlock()
+	defer counter.mu.RUnlock()
 	return counter.v[key]
 }
 
@@ -304,21 +373,88 @@ func unsafeIncrementExample() {
 	time.Sleep(time.Second)
 
 	// some executions is 99, others it is 100, depending on thread scheduling
+	// (atomicIncrementExample in syncatomic.go is the same loop without the race)
 	fmt.Printf("Counter value after unsafe increment with race conditions: %v", val)
 }
 
+// The same race on a map. Maps check for it themselves too (cheaply, so not every time):
+// two goroutines writing at once can stop the program with "fatal error: concurrent map writes",
+// which unlike a panic can't be recovered. SafeCounter above is the fix.
+// (race_test.go runs these counters under go test -race - the racy ones only with NOTES_RACY=1)
+func unsafeMapWriteExample() {
+	counts := map[string]int{}
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Go(func() { counts["key-"+strconv.Itoa(i%10)]++ })
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	// usually 100 on one CPU - the race is still there, it just wasn't hit this time
+	fmt.Println("total after unsafe map writes:", total)
+}
+
 func main() {
 	// goroutineExample()
 	// channelExample()
 	// bufferedChannelsExample()
 	// deadlockExampleOverfilledBufferBlock()
 	// deadlockExampleEmptyBufferBlock()
+	// hiddenDeadlockExample()
 	// deadlockExampleUnbufferedChNoReciever()
 	// noDeadlockUnbufferedChannel()
+	// pingPongExample()
+	// mapReduceExample()
 	// testClosedChannelEx()
 	// sendingOnClosedChannelPanicEx()
 	// loopThroughValsUntilChannelClosedEx()
 	// selectEx()
+	// perOperationTimeoutExample()
+	// overallDeadlineExample()
+	// heartbeatExample()
+	// tickerInSelectExample()
+	// prioritySelectExample()
+	// generatorExample()
 	// safeIncrementMutuxExample()
+	// actorCounterExample()
 	// unsafeIncrementExample()
+	// unsafeMapWriteExample()
+	// onceExample()
+	// rwMutexBenchmarkExample()
+	// boundedQueueExample()
+	// backpressureExample()
+	// channelBenchmarkExample()
+	// goroutineLeakExample()
+	// scatterGatherExample()
+	// retryPoolExample()
+	// ringBufferExample()
+	// atomicIncrementExample()
+	// compareAndSwapExample()
+	// counterBenchmarkExample()
+	// writersBenchmarkExample()
+	// syncMapCacheExample()
+	// syncMapBenchmarkExample()
+	// syncPoolExample()
+	// pollFakeClockExample()
+	// configReloadExample()
+	// rateTrackerFakeClockExample()
+	// requestMetricsExample()
+	// leastLoadedExample()
+	// brokerExample()
+	// brokerCloseUnderLoadExample()
+	// brokerSlowSubscriberExample()
+	// crawlCauseExample()
+	// muxExamples()
+	// muxMainLoopExample()
+	// gracefulShutdownExample()
+	// webCrawlerExample()
+	// rateLimitExamples()
+	// keyedRateLimitExample()
+	// faultInjectionExample()
+	// errgroupExamples()
+	// workPoolExample()
+	// recoveredExample()
 }
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// === Cancellation causes (context.WithCancelCause) ===
+
+// ctx.Err() only says THAT a context stopped: context.Canceled or context.DeadlineExceeded.
+// With several ways to stop (a time limit, the first error, the user pressing Ctrl+C),
+// that isn't enough to report what happened. Go 1.20+ adds a cause:
+//
+//	ctx, cancel := context.WithCancelCause(parent)
+//	cancel(err)              // instead of cancel()
+//	context.Cause(ctx)       // -> err (ctx.Err() is still context.Canceled)
+//
+//	ctx, cancel := context.WithTimeoutCause(parent, d, errTooSlow) // Cause is errTooSlow when the time runs out
+//
+// - only the first cause is kept, later cancel calls are no-ops
+// - the cause is passed down to child contexts, so code deep in the call chain sees the same reason
+// - cancel(nil) sets the cause to context.Canceled
+// (The pipeline module's Run returns context.Cause for the same reason)
+
+var (
+	ErrCrawlTimeout = errors.New("crawl time limit reached")
+	ErrUserAbort    = errors.New("aborted by user")
+)
+
+// --- A concurrent web crawler (the Tour's exercise), that reports why it stopped ---
+
+type Fetcher interface {
+	// Fetch returns the body of a URL and the URLs found on that page
+	Fetch(ctx context.Context, url string) (body string, urls []string, err error)
+}
+
+type CrawlReport struct {
+	Visited []string
+	Stopped error // nil if the crawl finished, otherwise the cause
+}
+
+// Crawl fetches pages in parallel, starting at url, up to depth links deep.
+// The first fetch error cancels the whole crawl, with that error as the cause
+func Crawl(ctx context.Context, url string, depth int, fetcher Fetcher) CrawlReport {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		mu      sync.Mutex
+		visited = map[string]bool{}
+		wg      sync.WaitGroup
+	)
+
+	var crawl func(url string, depth int)
+	crawl = func(url string, depth int) {
+		if depth <= 0 || ctx.Err() != nil {
+			return
+		}
+		mu.Lock()
+		if visited[url] {
+			mu.Unlock()
+			return
+		}
+		visited[url] = true
+		mu.Unlock()
+
+		_, urls, err := fetcher.Fetch(ctx, url)
+		if err != nil {
+			if ctx.Err() == nil {
+				cancel(fmt.Errorf("fetching %s: %w", url, err))
+			}
+			return
+		}
+		for _, u := range urls {
+			wg.Go(func() { crawl(u, depth-1) })
+		}
+	}
+	wg.Go(func() { crawl(url, depth) })
+	wg.Wait()
+
+	report := CrawlReport{}
+	for u := range visited {
+		report.Visited = append(report.Visited, u)
+	}
+	slices.Sort(report.Visited)
+	if ctx.Err() != nil {
+		report.Stopped = context.Cause(ctx) // read before the deferred cancel(nil) runs
+	}
+	return report
+}
+
+// fakeFetcher is the Tour's canned set of pages, with a delay per fetch
+type fakeFetcher struct {
+	pages map[string][]string
+	delay time.Duration
+}
+
+var errPageNotFound = errors.New("not found")
+
+func (f fakeFetcher) Fetch(ctx context.Context, url string) (string, []string, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return "", nil, context.Cause(ctx)
+	}
+	urls, ok := f.pages[url]
+	if !ok {
+		return "", nil, errPageNotFound
+	}
+	return "body of " + url, urls, nil
+}
+
+var tourPages = map[string][]string{
+	"https://golang.org/":         {"https://golang.org/pkg/", "https://golang.org/cmd/"},
+	"https://golang.org/pkg/":     {"https://golang.org/", "https://golang.org/cmd/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/"},
+	"https://golang.org/pkg/fmt/": {"https://golang.org/", "https://golang.org/pkg/"},
+	"https://golang.org/pkg/os/":  {"https://golang.org/", "https://golang.org/pkg/"},
+	"https://golang.org/cmd/":     {"https://golang.org/"},
+}
+
+// describeStop turns a cause into the line printed for the user
+func describeStop(cause error) string {
+	switch {
+	case cause == nil:
+		return "finished"
+	case errors.Is(cause, ErrCrawlTimeout), errors.Is(cause, context.DeadlineExceeded):
+		return "timed out: " + cause.Error()
+	case errors.Is(cause, ErrUserAbort):
+		return "stopped by the user"
+	default:
+		return "failed: " + cause.Error()
+	}
+}
+
+func crawlCauseExample() {
+	fast := fakeFetcher{pages: tourPages, delay: 5 * time.Millisecond}
+	slow := fakeFetcher{pages: tourPages, delay: 50 * time.Millisecond}
+
+	// A broken link: /cmd/ now links to a page that doesn't exist
+	broken := fakeFetcher{pages: maps.Clone(tourPages), delay: 5 * time.Millisecond}
+	broken.pages["https://golang.org/cmd/"] = []string{"https://golang.org/missing/"}
+
+	check := func(name string, report CrawlReport, want error) {
+		ok := errors.Is(report.Stopped, want) // errors.Is(nil, nil) is true
+		fmt.Printf("%-12s %-55s visited %d pages | cause as expected: %t\n", name+":", describeStop(report.Stopped), len(report.Visited), ok)
+	}
+
+	// 1. Runs to the end
+	check("complete", Crawl(context.Background(), "https://golang.org/", 4, fast), nil)
+
+	// 2. Time limit - WithTimeoutCause sets the cause reported when the deadline passes
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 80*time.Millisecond, ErrCrawlTimeout)
+	check("timeout", Crawl(ctx, "https://golang.org/", 4, slow), ErrCrawlTimeout)
+	cancel()
+
+	// Plain WithTimeout: the cause is just context.DeadlineExceeded
+	ctx, cancel = context.WithTimeout(context.Background(), 80*time.Millisecond)
+	check("deadline", Crawl(ctx, "https://golang.org/", 4, slow), context.DeadlineExceeded)
+	cancel()
+
+	// 3. First error - Crawl cancels itself with the fetch error
+	report := Crawl(context.Background(), "https://golang.org/", 4, broken)
+	check("first error", report, errPageNotFound)
+
+	// 4. User abort - the caller cancels the parent with its own cause (ex. from a signal handler),
+	// and Crawl's child context reports the same cause
+	ctx, abort := context.WithCancelCause(context.Background())
+	time.AfterFunc(70*time.Millisecond, func() { abort(ErrUserAbort) })
+	check("user abort", Crawl(ctx, "https://golang.org/", 4, slow), ErrUserAbort)
+	fmt.Println("ctx.Err() only says:", ctx.Err(), "| context.Cause says:", context.Cause(ctx))
+	abort(errors.New("second cause")) // ignored - the first cause is kept
+	fmt.Println("after a second cancel, cause is still:", context.Cause(ctx))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// In a synctest bubble the fetchers' delays are fake time: each case stops at exactly the same point every run
+func TestCrawlCause(t *testing.T) {
+	slow := fakeFetcher{pages: tourPages, delay: 50 * time.Millisecond}
+	broken := fakeFetcher{pages: maps.Clone(tourPages), delay: 5 * time.Millisecond}
+	broken.pages["https://golang.org/cmd/"] = []string{"https://golang.org/missing/"}
+
+	for _, tc := range []struct {
+		name    string
+		ctx     func() (context.Context, func())
+		fetcher Fetcher
+		want    error // nil: the crawl finished
+		visited int
+	}{
+		{"complete", func() (context.Context, func()) { return context.Background(), func() {} }, slow, nil, 5},
+		// 80ms: the root (done at 50ms) and its two links have started, the third level hasn't
+		{"timeout cause", func() (context.Context, func()) {
+			return context.WithTimeoutCause(context.Background(), 80*time.Millisecond, ErrCrawlTimeout)
+		}, slow, ErrCrawlTimeout, 3},
+		{"plain deadline", func() (context.Context, func()) {
+			return context.WithTimeout(context.Background(), 80*time.Millisecond)
+		}, slow, context.DeadlineExceeded, 3},
+		{"first error", func() (context.Context, func()) { return context.Background(), func() {} }, broken, errPageNotFound, 6},
+		{"user abort", func() (context.Context, func()) {
+			ctx, abort := context.WithCancelCause(context.Background())
+			timer := time.AfterFunc(70*time.Millisecond, func() { abort(ErrUserAbort) })
+			return ctx, func() { timer.Stop(); abort(nil) }
+		}, slow, ErrUserAbort, 3},
+	} {
+		synctest.Test(t, func(t *testing.T) {
+			ctx, cancel := tc.ctx()
+			defer cancel()
+			report := Crawl(ctx, "https://golang.org/", 4, tc.fetcher)
+			if !errors.Is(report.Stopped, tc.want) { // errors.Is(nil, nil) is true
+				t.Errorf("%s: Stopped = %v, want %v", tc.name, report.Stopped, tc.want)
+			}
+			if len(report.Visited) != tc.visited {
+				t.Errorf("%s: visited %v, want %d pages", tc.name, report.Visited, tc.visited)
+			}
+		})
+	}
+}
+
+// The first error's cause names the page, and a second failure doesn't replace it
+func TestCrawlFirstErrorWins(t *testing.T) {
+	pages := maps.Clone(tourPages)
+	pages["https://golang.org/"] = []string{"https://golang.org/a/", "https://golang.org/b/"} // both missing
+	synctest.Test(t, func(t *testing.T) {
+		report := Crawl(context.Background(), "https://golang.org/", 2, fakeFetcher{pages: pages, delay: time.Millisecond})
+		if !errors.Is(report.Stopped, errPageNotFound) || !strings.HasPrefix(report.Stopped.Error(), "fetching https://golang.org/") {
+			t.Errorf("Stopped = %v, want the first fetch error", report.Stopped)
+		}
+	})
+}
+
+// The user abort's cause reaches the caller's context too - ctx.Err() alone only says Canceled
+func TestCrawlAbortCauseOnParent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, abort := context.WithCancelCause(context.Background())
+		time.AfterFunc(70*time.Millisecond, func() { abort(ErrUserAbort) })
+		Crawl(ctx, "https://golang.org/", 4, fakeFetcher{pages: tourPages, delay: 50 * time.Millisecond})
+		abort(errors.New("second cause")) // ignored - the first cause is kept
+		if ctx.Err() != context.Canceled || context.Cause(ctx) != ErrUserAbort {
+			t.Errorf("Err = %v, Cause = %v; want context.Canceled and ErrUserAbort", ctx.Err(), context.Cause(ctx))
+		}
+	})
+}
+
+func TestDescribeStop(t *testing.T) {
+	for _, tc := range []struct {
+		cause error
+		want  string
+	}{
+		{nil, "finished"},
+		{ErrCrawlTimeout, "timed out: crawl time limit reached"},
+		{context.DeadlineExceeded, "timed out: context deadline exceeded"},
+		{ErrUserAbort, "stopped by the user"},
+		{errPageNotFound, "failed: not found"},
+	} {
+		if got := describeStop(tc.cause); got != tc.want {
+			t.Errorf("describeStop(%v) = %q, want %q", tc.cause, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// === errgroup - goroutines that return errors ===
+
+// sync.WaitGroup waits for goroutines, but has no idea whether they worked. Collecting errors by hand
+// means a mutex (or a channel), a "first error" variable, and - to stop the others early - a context
+// with a cancel (cause) func. errgroup.Group is exactly that bundle:
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(func() error { ... })  // like wg.Go, but the function returns an error
+//	err := g.Wait()             // the first error, after every goroutine has returned
+//
+// (golang.org/x/sync is maintained by the Go team, outside the standard library - go get golang.org/x/sync)
+//
+// Each part below does the same job twice: with a raw WaitGroup first, then with a Group.
+
+var pageURLs = []string{"https://golang.org/", "https://golang.org/pkg/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/", "https://golang.org/cmd/"}
+
+// --- 1. Several tasks, collect the results and the first error ---
+
+func fetchAllWaitGroup(ctx context.Context, f Fetcher, urls []string) ([]string, error) {
+	bodies := make([]string, len(urls)) // one slot per goroutine, so writing the results needs no lock
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i, u := range urls {
+		wg.Go(func() {
+			body, _, err := f.Fetch(ctx, u)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", u, err)
+				}
+				mu.Unlock()
+				return
+			}
+			bodies[i] = body
+		})
+	}
+	wg.Wait()
+	return bodies, firstErr
+}
+
+func fetchAllGroup(ctx context.Context, f Fetcher, urls []string) ([]string, error) {
+	bodies := make([]string, len(urls))
+	var g errgroup.Group // the zero value works - no context, no limit
+	for i, u := range urls {
+		g.Go(func() error {
+			body, _, err := f.Fetch(ctx, u)
+			if err != nil {
+				return fmt.Errorf("%s: %w", u, err)
+			}
+			bodies[i] = body
+			return nil
+		})
+	}
+	return bodies, g.Wait()
+}
+
+// --- 2. Cancel the others on the first error ---
+
+// task sleeps for d (the "work"), then fails if fail is set. It gives up as soon as ctx is cancelled
+func task(ctx context.Context, d time.Duration, fail bool) error {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", errStoppedEarly, context.Cause(ctx))
+	}
+	if fail {
+		return errUnlucky
+	}
+	return nil
+}
+
+var (
+	errUnlucky      = errors.New("task failed")
+	errStoppedEarly = errors.New("stopped early")
+)
+
+func firstErrorWaitGroup(ctx context.Context, durations []time.Duration, failAt int) (stopped int, err error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		count    atomic.Int32
+	)
+	for i, d := range durations {
+		wg.Go(func() {
+			err := task(ctx, d, i == failAt)
+			if err == nil {
+				return
+			}
+			if errors.Is(err, errStoppedEarly) {
+				count.Add(1)
+			}
+			once.Do(func() {
+				firstErr = err
+				cancel(err) // stop every other task
+			})
+		})
+	}
+	wg.Wait()
+	return int(count.Load()), firstErr
+}
+
+func firstErrorGroup(ctx context.Context, durations []time.Duration, failAt int) (stopped int, err error) {
+	g, ctx := errgroup.WithContext(ctx)
+	var count atomic.Int32
+	for i, d := range durations {
+		g.Go(func() error {
+			err := task(ctx, d, i == failAt)
+			if errors.Is(err, errStoppedEarly) {
+				count.Add(1)
+			}
+			return err
+		})
+	}
+	err = g.Wait()
+	return int(count.Load()), err
+}
+
+// --- 3. Bounded parallelism ---
+
+// concurrencyGauge records the most calls of enter..leave running at once
+type concurrencyGauge struct {
+	now, max atomic.Int32
+}
+
+func (c *concurrencyGauge) enter() {
+	n := c.now.Add(1)
+	for {
+		m := c.max.Load()
+		if n <= m || c.max.CompareAndSwap(m, n) {
+			return
+		}
+	}
+}
+
+func (c *concurrencyGauge) leave() { c.now.Add(-1) }
+
+func boundedWaitGroup(jobs, limit int) int {
+	var gauge concurrencyGauge
+	sem := make(chan struct{}, limit) // a counting semaphore
+	var wg sync.WaitGroup
+	for range jobs {
+		sem <- struct{}{} // acquire before starting the goroutine, so no more than limit goroutines exist
+		wg.Go(func() {
+			defer func() { <-sem }()
+			gauge.enter()
+			defer gauge.leave()
+			time.Sleep(5 * time.Millisecond)
+		})
+	}
+	wg.Wait()
+	return int(gauge.max.Load())
+}
+
+func boundedGroup(jobs, limit int) int {
+	var gauge concurrencyGauge
+	var g errgroup.Group
+	g.SetLimit(limit) // Go blocks while limit goroutines are running
+	for range jobs {
+		g.Go(func() error {
+			gauge.enter()
+			defer gauge.leave()
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+	g.Wait()
+	return int(gauge.max.Load())
+}
+
+func errgroupExamples() {
+	ctx := context.Background()
+	ok := fakeFetcher{pages: tourPages, delay: 5 * time.Millisecond}
+	missing := append(slices.Clone(pageURLs), "https://golang.org/missing/")
+
+	// 1.
+	for _, impl := range []struct {
+		name  string
+		fetch func(context.Context, Fetcher, []string) ([]string, error)
+	}{{"WaitGroup", fetchAllWaitGroup}, {"errgroup", fetchAllGroup}} {
+		bodies, err := impl.fetch(ctx, ok, pageURLs)
+		fmt.Printf("%-9s all pages: %d bodies, err: %v\n", impl.name, len(bodies), err)
+		_, err = impl.fetch(ctx, ok, missing)
+		fmt.Printf("%-9s with a missing page: err: %v (is errPageNotFound: %t)\n", impl.name, err, errors.Is(err, errPageNotFound))
+	}
+
+	// 2. Task 0 fails after 10ms. The other 4 would take a second - but are cancelled instead
+	durations := []time.Duration{10 * time.Millisecond, time.Second, time.Second, time.Second, time.Second}
+	for _, impl := range []struct {
+		name string
+		run  func(context.Context, []time.Duration, int) (int, error)
+	}{{"WaitGroup", firstErrorWaitGroup}, {"errgroup", firstErrorGroup}} {
+		start := time.Now()
+		stopped, err := impl.run(ctx, durations, 0)
+		took := time.Since(start)
+		fmt.Printf("%-9s first error: %v | others cancelled: %d/4 | took under 100ms: %t\n",
+			impl.name, err, stopped, took < 100*time.Millisecond)
+	}
+
+	// 3. 30 jobs, at most 4 at a time
+	fmt.Println("WaitGroup + semaphore, most at once:", boundedWaitGroup(30, 4))
+	fmt.Println("errgroup SetLimit(4), most at once:", boundedGroup(30, 4))
+
+	// TryGo - start only if under the limit (ex. shed load instead of queueing it)
+	var g errgroup.Group
+	g.SetLimit(1)
+	release := make(chan struct{})
+	started := g.TryGo(func() error { <-release; return nil })
+	rejected := !g.TryGo(func() error { return nil })
+	close(release)
+	g.Wait()
+	fmt.Println("TryGo with the limit reached - first started:", started, "| second rejected:", rejected)
+}
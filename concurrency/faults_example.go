@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"concurrency/internal/faults"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Failure injection (see internal/faults) ---
+
+// Code that handles failures (retries, partial writes, a crawl stopping on the first error) is hard to
+// check against real services - they fail rarely, and never the same way twice.
+// The faults package wraps a working implementation and makes it fail on purpose, reproducibly from a seed.
+
+// fetchWithRetry retries a fetch up to attempts times - the smallest possible retry loop
+func fetchWithRetry(ctx context.Context, f Fetcher, url string, attempts int) (tries int, err error) {
+	for tries = 1; ; tries++ {
+		_, _, err = f.Fetch(ctx, url)
+		if err == nil || tries == attempts || !errors.Is(err, faults.ErrInjected) {
+			return tries, err
+		}
+	}
+}
+
+// writeAll keeps writing what's left after a short write, until everything is written or it gives up.
+// (io.Copy and fmt.Fprint don't do this - a short write is an error for them)
+func writeAll(w interface{ Write([]byte) (int, error) }, p []byte, attempts int) error {
+	var err error
+	for range attempts {
+		var n int
+		n, err = w.Write(p)
+		p = p[n:]
+		if len(p) == 0 {
+			return nil
+		}
+	}
+	return err
+}
+
+func faultInjectionExample() {
+	urls := []string{"https://golang.org/", "https://golang.org/pkg/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/", "https://golang.org/cmd/"}
+
+	// The fake clock's Advance as the Sleep - latency is added up, but nothing actually waits
+	noWait := func(clock *fakeClock) func(context.Context, time.Duration) error {
+		return func(_ context.Context, d time.Duration) error {
+			clock.Advance(d)
+			return nil
+		}
+	}
+
+	// --- Determinism ---
+
+	// Same seed -> same decisions for each key, even when the calls arrive in a different order
+	// (the second injector is called from goroutines)
+	decisions := func(in *faults.Injector, concurrent bool) map[string][]faults.Decision {
+		got := map[string][]faults.Decision{}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, u := range urls {
+			run := func() {
+				var ds []faults.Decision
+				for range 5 {
+					ds = append(ds, in.Decide(u))
+				}
+				mu.Lock()
+				got[u] = ds
+				mu.Unlock()
+			}
+			if concurrent {
+				wg.Go(run)
+			} else {
+				run()
+			}
+		}
+		wg.Wait()
+		return got
+	}
+	cfg := faults.Config{Seed: 42, ErrorRate: 0.3, Latency: 10 * time.Millisecond, Jitter: 20 * time.Millisecond}
+	other := cfg
+	other.Seed = 43
+	a, b, c := decisions(faults.New(cfg), false), decisions(faults.New(cfg), true), decisions(faults.New(other), false)
+	same := func(x, y map[string][]faults.Decision) bool {
+		for _, u := range urls {
+			if !slices.Equal(x[u], y[u]) {
+				return false
+			}
+		}
+		return true
+	}
+	fmt.Println("same seed, sequential vs concurrent - same decisions:", same(a, b))
+	fmt.Println("different seed - different decisions:", !same(a, c))
+
+	// Over many calls, the failure rate and latency are what was configured
+	in := faults.New(cfg)
+	for i := range 10_000 {
+		in.Decide(fmt.Sprint("key-", i%100))
+	}
+	st := in.Stats()
+	avg := st.Delay / time.Duration(st.Calls)
+	fmt.Printf("10000 calls: failure rate %.3f (want 0.3 ± 0.02: %t), average delay %v (want ~20ms: %t)\n",
+		st.FailureRate(), math.Abs(st.FailureRate()-0.3) < 0.02, avg.Round(time.Millisecond), (avg-20*time.Millisecond).Abs() < time.Millisecond)
+
+	// --- A flaky fetcher, with a retry loop ---
+
+	clock := &fakeClock{}
+	flakyCfg := faults.Config{Seed: 7, ErrorRate: 0.5, Latency: 100 * time.Millisecond, Sleep: noWait(clock)}
+	tries := func() []int {
+		flaky := faults.FlakyFetcher{Fetcher: fakeFetcher{pages: tourPages}, Injector: faults.New(flakyCfg)}
+		var got []int
+		for _, u := range urls {
+			n, err := fetchWithRetry(context.Background(), flaky, u, 4)
+			if err != nil {
+				n = -1
+			}
+			got = append(got, n)
+		}
+		return got
+	}
+	first := tries()
+	fmt.Println("attempts needed per page (-1 = gave up):", first, "| same on a second run:", slices.Equal(first, tries()))
+	fmt.Println("injected latency (fake clock):", clock.Now().Sub(time.Time{}))
+
+	// The concurrent crawler stops on the first error - and with a seed, the run can be replayed
+	crawl := func() CrawlReport {
+		flaky := faults.FlakyFetcher{Fetcher: fakeFetcher{pages: tourPages}, Injector: faults.New(faults.Config{Seed: 3, ErrorRate: 0.2})}
+		return Crawl(context.Background(), "https://golang.org/", 4, flaky)
+	}
+	r1, r2 := crawl(), crawl()
+	fmt.Println("crawl:", describeStop(r1.Stopped), "| injected:", errors.Is(r1.Stopped, faults.ErrInjected),
+		"| replayed the same:", fmt.Sprint(r1.Stopped) == fmt.Sprint(r2.Stopped))
+
+	// --- Partial writes ---
+
+	var out bytes.Buffer
+	w := faults.Writer{W: &out, PartialWrites: true, Injector: faults.New(faults.Config{Seed: 1, ErrorRate: 0.5})}
+	text := []byte(strings.Repeat("gopher ", 20))
+	for i := range 4 {
+		n, err := w.Write(text)
+		fmt.Printf("write %d: %3d of %d bytes, err: %v\n", i, n, len(text), err)
+	}
+
+	out.Reset()
+	err := writeAll(w, text, 10)
+	fmt.Println("writeAll resumes after short writes - complete:", bytes.Equal(out.Bytes(), text), "err:", err)
+
+	// --- A FileStore that fails some uploads ---
+
+	store := faults.Store{Injector: faults.New(faults.Config{Seed: 9, ErrorRate: 0.25})}
+	var failed []string
+	for i := range 12 {
+		file := fmt.Sprintf("upload-%02d.txt", i)
+		if err := store.Store(file); err != nil {
+			failed = append(failed, file)
+		}
+	}
+	fmt.Println("failed uploads (the same every run with seed 9):", failed)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"concurrency/internal/faults"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The retry loops in faults_example.go, driven by the fakes - each failure comes from the seed, so a
+// failing test can be rerun and fails the same way
+
+func TestFetchWithRetry(t *testing.T) {
+	clock := &fakeClock{}
+	cfg := faults.Config{Seed: 7, ErrorRate: 0.5, Latency: 100 * time.Millisecond,
+		Sleep: func(_ context.Context, d time.Duration) error { clock.Advance(d); return nil }}
+	tries := func(attempts int) (got []int, errs []error) {
+		flaky := faults.FlakyFetcher{Fetcher: fakeFetcher{pages: tourPages}, Injector: faults.New(cfg)}
+		for _, u := range slices.Sorted(maps.Keys(tourPages)) {
+			n, err := fetchWithRetry(context.Background(), flaky, u, attempts)
+			got, errs = append(got, n), append(errs, err)
+		}
+		return got, errs
+	}
+
+	first, errs := tries(4)
+	retried := false
+	for i, n := range first {
+		if n < 1 || n > 4 {
+			t.Errorf("page %d: %d tries, want 1 to 4", i, n)
+		}
+		if errs[i] != nil && (n != 4 || !errors.Is(errs[i], faults.ErrInjected)) {
+			t.Errorf("page %d: gave up after %d tries with %v", i, n, errs[i])
+		}
+		retried = retried || n > 1
+	}
+	if !retried {
+		t.Error("no fetch was retried with a 0.5 error rate")
+	}
+	if again, _ := tries(4); !slices.Equal(first, again) {
+		t.Errorf("tries %v, then %v with the same seed", first, again)
+	}
+
+	// one attempt: a failure is returned as it is, not retried
+	_, errs = tries(1)
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, faults.ErrInjected) {
+			t.Errorf("page %d: %v", i, err)
+		}
+	}
+}
+
+func TestFetchWithRetryStopsOnRealErrors(t *testing.T) {
+	flaky := faults.FlakyFetcher{Fetcher: fakeFetcher{pages: tourPages}, Injector: faults.New(faults.Config{})}
+	n, err := fetchWithRetry(context.Background(), flaky, "https://golang.org/missing/", 4)
+	if n != 1 || !errors.Is(err, errPageNotFound) {
+		t.Errorf("a missing page: %d tries, err %v - want 1 try and errPageNotFound", n, err)
+	}
+}
+
+func TestWriteAllResumes(t *testing.T) {
+	var out bytes.Buffer
+	w := faults.Writer{W: &out, PartialWrites: true, Injector: faults.New(faults.Config{Seed: 1, ErrorRate: 0.5})}
+	text := []byte(strings.Repeat("gopher ", 20))
+	if err := writeAll(w, text, 10); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), text) {
+		t.Errorf("wrote %q, want %q", out.String(), text)
+	}
+
+	// always failing: it gives up after the attempts, with the last error
+	out.Reset()
+	w.Injector = faults.New(faults.Config{ErrorRate: 1})
+	if err := writeAll(w, text, 3); !errors.Is(err, faults.ErrInjected) {
+		t.Errorf("writeAll on a writer that always fails: %v", err)
+	}
+}
+
+func TestCrawlStopsOnInjectedFault(t *testing.T) {
+	crawl := func() CrawlReport {
+		flaky := faults.FlakyFetcher{Fetcher: fakeFetcher{pages: tourPages}, Injector: faults.New(faults.Config{Seed: 3, ErrorRate: 0.2})}
+		return Crawl(context.Background(), "https://golang.org/", 4, flaky)
+	}
+	r1, r2 := crawl(), crawl()
+	if !errors.Is(r1.Stopped, faults.ErrInjected) {
+		t.Fatalf("Stopped = %v, want ErrInjected", r1.Stopped)
+	}
+	if fmt.Sprint(r1.Stopped) != fmt.Sprint(r2.Stopped) {
+		t.Errorf("seed 3 stopped with %v, then %v", r1.Stopped, r2.Stopped)
+	}
+}
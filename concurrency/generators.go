@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"time"
+)
+
+// === Generators - functions returning receive-only channels ===
+
+// --- Directional channel types ---
+
+// chan T is bidirectional. The arrow in the type narrows it:
+// - chan<- T   send only: only ch <- v and close(ch) compile
+// - <-chan T   receive only: only <-ch and range compile (and NOT close - closing is the sender's job)
+// A chan T converts to either one implicitly (ex. when passed as an argument), but never back.
+// So a function's signature documents (and the compiler enforces) which side it is on:
+//
+//	func fill(out chan<- int) { v := <-out } // compile error: receive from send-only type chan<- int
+//	func drain(in <-chan int) { close(in) }  // compile error: cannot close receive-only channel in
+
+// fill only sends, drain only receives - both take the same bidirectional channel
+func fill(out chan<- int, n int) {
+	for i := range n {
+		out <- i
+	}
+	close(out)
+}
+
+func drain(in <-chan int) (got []int) {
+	for v := range in {
+		got = append(got, v)
+	}
+	return got
+}
+
+// --- Generators ---
+
+// A generator makes the channel, starts the goroutine that sends on it, and returns the channel as <-chan T.
+// The caller can only receive, so the generator alone decides when the channel closes.
+// An infinite generator never closes on its own - it takes a done channel, and the caller closes done
+// once it has taken what it needs. Without done, the goroutine would block on its next send forever (a leak)
+// (When the values don't need their own goroutine, a range-over-func iterator does the same without one - see generics/iterators)
+
+// intSequence sends start, start+1, start+2, ... until done is closed
+func intSequence(done <-chan struct{}, start int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := start; ; i++ {
+			select {
+			case out <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// repeatFn sends fn() over and over until done is closed (ex. random numbers, ids, timestamps)
+func repeatFn[T any](done <-chan struct{}, fn func() T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case out <- fn():
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// take passes on the first n values from in, then closes - a bounded prefix of an infinite generator.
+// It is a generator too (and a pipeline stage: receive-only in, receive-only out)
+func take[T any](done <-chan struct{}, in <-chan T, n int) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for range n {
+			v, ok := <-in
+			if !ok {
+				return
+			}
+			select {
+			case out <- v:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func generatorExample() {
+	// Directional parameters, one bidirectional channel
+	ch := make(chan int, 3)
+	go fill(ch, 3)
+	fmt.Println("fill (chan<-) then drain (<-chan):", drain(ch))
+
+	before := runtime.NumGoroutine()
+	done := make(chan struct{})
+
+	first := drain(take(done, intSequence(done, 0), 5))
+	fmt.Println("first 5 of intSequence(0):", first)
+
+	// Each generator is its own sequence - receiving from one doesn't advance another
+	seq := intSequence(done, 10)
+	a := drain(take(done, seq, 3))
+	b := drain(take(done, seq, 3)) // the same generator carries on where the first take left off
+	fmt.Println("two prefixes of one intSequence(10):", a, b)
+
+	calls := 0
+	counted := drain(take(done, repeatFn(done, func() int { calls++; return calls * calls }), 4))
+	fmt.Println("repeatFn(square of call count), 4 taken:", counted)
+
+	rolls := drain(take(done, repeatFn(done, func() int { return rand.IntN(6) + 1 }), 10))
+	fmt.Println("10 dice rolls:", rolls)
+
+	// The generators are still running here, each blocked on its next send (repeatFn has already called fn
+	// one more time, for the value it's trying to send - generators run one value ahead of the receiver)
+	fmt.Println("generator goroutines still running before done:", runtime.NumGoroutine()-before)
+	close(done)
+	time.Sleep(10 * time.Millisecond)                                // let them see done and return
+	fmt.Println("after close(done):", runtime.NumGoroutine()-before) // generators_test.go checks these with go test
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// waitGoroutines waits (up to a second) for the goroutine count to drop back to want
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines still running, want %d - a goroutine leaked", runtime.NumGoroutine(), want)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFillDrain(t *testing.T) {
+	ch := make(chan int)
+	go fill(ch, 4)
+	if got := drain(ch); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("drain = %v, want [0 1 2 3]", got)
+	}
+}
+
+func TestIntSequencePrefixes(t *testing.T) {
+	before := runtime.NumGoroutine()
+	done := make(chan struct{})
+	if got := drain(take(done, intSequence(done, 0), 5)); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("first 5 of intSequence(0) = %v", got)
+	}
+	// two takes from one generator: the second carries on where the first stopped
+	seq := intSequence(done, 10)
+	a, b := drain(take(done, seq, 3)), drain(take(done, seq, 3))
+	if !slices.Equal(a, []int{10, 11, 12}) || !slices.Equal(b, []int{13, 14, 15}) {
+		t.Errorf("two prefixes of intSequence(10) = %v %v, want [10 11 12] [13 14 15]", a, b)
+	}
+	if got := drain(take(done, intSequence(done, 0), 0)); len(got) != 0 {
+		t.Errorf("take 0 = %v, want nothing", got)
+	}
+	close(done)
+	waitGoroutines(t, before)
+}
+
+func TestRepeatFn(t *testing.T) {
+	before := runtime.NumGoroutine()
+	done := make(chan struct{})
+	calls := 0
+	got := drain(take(done, repeatFn(done, func() int { calls++; return calls * calls }), 4))
+	if !slices.Equal(got, []int{1, 4, 9, 16}) {
+		t.Errorf("4 taken from repeatFn = %v, want [1 4 9 16]", got)
+	}
+	close(done)
+	waitGoroutines(t, before)
+}
+
+// take stops early, without blocking, when its input runs out first
+func TestTakeShortInput(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	in := make(chan string, 2)
+	in <- "a"
+	in <- "b"
+	close(in)
+	if got := drainAll(take(done, in, 5)); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("take 5 of 2 values = %v", got)
+	}
+}
+
+// drainAll is drain for any element type
+func drainAll[T any](in <-chan T) (got []T) {
+	for v := range in {
+		got = append(got, v)
+	}
+	return got
+}
+
+// Closing done also releases a take whose receiver stopped reading
+func TestTakeDone(t *testing.T) {
+	before := runtime.NumGoroutine()
+	done := make(chan struct{})
+	out := take(done, intSequence(done, 0), 100)
+	<-out
+	close(done)
+	waitGoroutines(t, before)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"concurrency/internal/ring"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// === A bounded history - the last N lines (see internal/ring) ===
+
+// A long running program can't keep all its output, but the last few hundred lines are what's wanted
+// when something goes wrong (a crash report, a /debug page). A ring buffer keeps exactly that:
+// memory for N lines, the oldest overwritten by each new one.
+
+// tailOutput runs f with os.Stdout going into a pipe, and keeps the last n lines f printed.
+// A goroutine copies from the pipe into the buffer while f runs - a pipe has a small buffer,
+// so without a reader f would block on its first big print
+func tailOutput(n int, f func()) (tail []string, total int) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err)
+	}
+	history := ring.New[string](n)
+	copied := make(chan int)
+	go func() {
+		lines := 0
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			history.Push(sc.Text())
+			lines++
+		}
+		copied <- lines
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = w // fmt.Println writes to whatever os.Stdout is at the time
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close() // the reader sees EOF after the last line
+	total = <-copied
+	r.Close()
+	return history.Snapshot(), total
+}
+
+func ringBufferExample() {
+	// Overwriting: a history of 3
+	h := ring.New[int](3)
+	for i := 1; i <= 5; i++ {
+		if dropped, ok := h.Push(i); ok {
+			fmt.Printf("push %d drops %d | ", i, dropped)
+		}
+	}
+	fmt.Println("kept:", h.Snapshot())
+
+	// Refusing: a queue of 3
+	q := ring.New[int](3)
+	accepted := 0
+	for i := 1; i <= 5; i++ {
+		if q.TryPush(i) {
+			accepted++
+		}
+	}
+	first, _ := q.Pop()
+	fmt.Println("TryPush accepted", accepted, "of 5 | first out:", first, "| room again:", q.TryPush(6), "| now:", q.Snapshot())
+
+	empty := ring.New[string](0)
+	fmt.Println("capacity 0 is:", empty.Cap()) // internal/ring/ring_test.go checks the rest against a slice
+
+	// Many goroutines writing at once (go run -race finds nothing)
+	events := ring.New[string](10)
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := range 100 {
+				events.Push(fmt.Sprintf("worker %d event %d", w, i))
+			}
+		})
+	}
+	wg.Wait()
+	fmt.Println("800 events from 8 goroutines - kept:", events.Len(), "| the last:", events.Snapshot()[9])
+
+	// The use: the last lines of another example's output
+	tail, total := tailOutput(3, selectEx)
+	fmt.Printf("selectEx printed %d lines, the last %d:\n  %s\n", total, len(tail), strings.Join(tail, "\n  "))
+}
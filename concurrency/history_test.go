@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestTailOutput(t *testing.T) {
+	tail, total := tailOutput(3, func() {
+		for i := range 10 {
+			fmt.Println("line", i)
+		}
+	})
+	if total != 10 || !slices.Equal(tail, []string{"line 7", "line 8", "line 9"}) {
+		t.Errorf("tailOutput = %q, %d lines; want the last 3 of 10", tail, total)
+	}
+
+	// more output than a pipe buffers (64KB on Linux) - f mustn't block
+	tail, total = tailOutput(1, func() {
+		for i := range 20_000 {
+			fmt.Println("a longer line, to fill the pipe faster:", i)
+		}
+	})
+	if total != 20_000 || tail[0] != "a longer line, to fill the pipe faster: 19999" {
+		t.Errorf("big output: %d lines, last %q", total, tail)
+	}
+
+	if tail, total := tailOutput(5, func() {}); len(tail) != 0 || total != 0 {
+		t.Errorf("no output: %q, %d", tail, total)
+	}
+}
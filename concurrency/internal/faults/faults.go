@@ -0,0 +1,223 @@
+package faults
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// Misbehaving implementations of the interfaces used in the notes, for exercising the code that has to
+// cope with them (retries, timeouts, circuit breakers, partial writes):
+// - Fetcher: fails some fetches, and is slow
+// - Writer: slow, fails some writes, and writes only part of p on others
+// - Store: a FileStore whose Store fails some of the time
+//
+// Everything is decided from a seed, so a failing run can be replayed exactly.
+// The decision for a call is a hash of (seed, key, attempt) - ex. (42, "https://golang.org/", 2) -
+// NOT the next value of a shared random generator. With a shared generator, goroutines calling in a
+// different order would get different faults, and a concurrent run couldn't be replayed.
+//
+// internal/ - only code in this module (the concurrency notes) can import the package, and that's all
+// that uses it: the fetch retry loop, writeAll and Crawl, in faults_example.go and its tests. The other
+// modules' retries (errorsdeep, generics, httpclient) and basics' FileStore are in modules of their own,
+// which can't import this one whatever its path - there's no go.work tying them together. Store matches
+// FileStore's method set, so it shows the shape of such a fake, but basics would need its own copy.
+
+// ErrInjected is returned (wrapped) for every injected failure
+var ErrInjected = errors.New("faults: injected failure")
+
+type Config struct {
+	Seed      uint64
+	ErrorRate float64       // share of calls that fail, 0 to 1
+	Latency   time.Duration // added to every call
+	Jitter    time.Duration // plus up to this much more, different per call
+
+	// Sleep waits for the injected latency. The default is a real, ctx aware sleep;
+	// examples with a fake clock pass one that only records the time
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Decision is what happens to one call
+type Decision struct {
+	Fail  bool
+	Delay time.Duration
+	Cut   float64 // for partial writes: the fraction of the data that gets written, [0, 1)
+}
+
+// Injector makes the decisions, and counts them
+type Injector struct {
+	cfg Config
+
+	mu       sync.Mutex
+	attempts map[string]int
+	calls    int
+	failures int
+	delay    time.Duration
+}
+
+func New(cfg Config) *Injector {
+	if cfg.Sleep == nil {
+		cfg.Sleep = sleep
+	}
+	return &Injector{cfg: cfg, attempts: make(map[string]int)}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Decide returns the decision for the next call with this key. The n-th call for a key always gets
+// the same decision for the same seed, whatever happens to other keys in between
+func (in *Injector) Decide(key string) Decision {
+	in.mu.Lock()
+	attempt := in.attempts[key]
+	in.attempts[key]++
+	in.mu.Unlock()
+
+	d := Decision{
+		Fail:  unit(in.cfg.Seed, key, attempt, 0) < in.cfg.ErrorRate,
+		Delay: in.cfg.Latency + time.Duration(unit(in.cfg.Seed, key, attempt, 1)*float64(in.cfg.Jitter)),
+		Cut:   unit(in.cfg.Seed, key, attempt, 2),
+	}
+
+	in.mu.Lock()
+	in.calls++
+	in.delay += d.Delay
+	if d.Fail {
+		in.failures++
+	}
+	in.mu.Unlock()
+	return d
+}
+
+// unit hashes its inputs to a number in [0, 1). stream gives independent values for the same call
+func unit(seed uint64, key string, attempt, stream int) float64 {
+	h := fnv.New64a()
+	var buf [24]byte
+	binary.LittleEndian.PutUint64(buf[0:], seed)
+	binary.LittleEndian.PutUint64(buf[8:], uint64(attempt))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(stream))
+	h.Write(buf[:])
+	io.WriteString(h, key)
+	// FNV mixes poorly for inputs that differ only slightly (attempt 1, 2, 3...),
+	// so finish with splitmix64's finalizer, which spreads every input bit over the whole result
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / (1 << 53)
+}
+
+// Stats is what the injector did so far
+type Stats struct {
+	Calls, Failures int
+	Delay           time.Duration // total injected latency
+}
+
+func (in *Injector) Stats() Stats {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return Stats{Calls: in.calls, Failures: in.failures, Delay: in.delay}
+}
+
+// FailureRate is the observed share of failed calls
+func (s Stats) FailureRate() float64 {
+	if s.Calls == 0 {
+		return math.NaN()
+	}
+	return float64(s.Failures) / float64(s.Calls)
+}
+
+// --- Fetcher ---
+
+// Fetcher is the crawler's interface (concurrency/crawler.go)
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (body string, urls []string, err error)
+}
+
+// FlakyFetcher fails and delays fetches of the wrapped Fetcher. Decisions are per URL:
+// the first fetch of a URL can fail and the retry succeed, or the other way round
+type FlakyFetcher struct {
+	Fetcher
+	*Injector
+}
+
+func (f FlakyFetcher) Fetch(ctx context.Context, url string) (string, []string, error) {
+	d := f.Decide(url)
+	if err := f.cfg.Sleep(ctx, d.Delay); err != nil {
+		return "", nil, err
+	}
+	if d.Fail {
+		return "", nil, ErrInjected // unwrapped: callers like Crawl already add the url
+	}
+	return f.Fetcher.Fetch(ctx, url)
+}
+
+// --- Writer ---
+
+// Writer is a slow, unreliable io.Writer. A failed write is either nothing written,
+// or (with PartialWrites) a prefix of p - with an error, as the io.Writer rules require for n < len(p)
+type Writer struct {
+	W             io.Writer
+	PartialWrites bool
+	*Injector
+}
+
+func (w Writer) Write(p []byte) (int, error) {
+	d := w.Decide("write") // one key: the n-th write is the same on every run
+	if err := w.cfg.Sleep(context.Background(), d.Delay); err != nil {
+		return 0, err
+	}
+	if !d.Fail {
+		return w.W.Write(p)
+	}
+	if !w.PartialWrites || len(p) < 2 {
+		return 0, fmt.Errorf("write: %w", ErrInjected)
+	}
+	n, err := w.W.Write(p[:int(d.Cut*float64(len(p)))])
+	if err != nil {
+		return n, err
+	}
+	return n, fmt.Errorf("short write (%d of %d bytes): %w", n, len(p), ErrInjected)
+}
+
+// --- FileStore ---
+
+// Store implements the basics notes' FileStore, failing some of the stores.
+// Decisions are per file name, so the retry of one upload doesn't depend on other uploads
+type Store struct {
+	Inner interface{ Store(file string) error } // nil: successful stores go nowhere
+	*Injector
+}
+
+func (s Store) Store(file string) error {
+	d := s.Decide(file)
+	if err := s.cfg.Sleep(context.Background(), d.Delay); err != nil {
+		return err
+	}
+	if d.Fail {
+		return fmt.Errorf("storing %s: %w", file, ErrInjected)
+	}
+	if s.Inner == nil {
+		return nil
+	}
+	return s.Inner.Store(file)
+}
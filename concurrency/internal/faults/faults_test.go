@@ -0,0 +1,198 @@
+package faults
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var keys = []string{"https://golang.org/", "https://golang.org/pkg/", "https://golang.org/pkg/fmt/", "https://golang.org/cmd/"}
+
+// decisions makes 5 decisions per key - from one goroutine per key if concurrent, so the calls for
+// different keys interleave in whatever order the scheduler picks
+func decisions(in *Injector, concurrent bool) map[string][]Decision {
+	got := map[string][]Decision{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, k := range keys {
+		run := func() {
+			var ds []Decision
+			for range 5 {
+				ds = append(ds, in.Decide(k))
+			}
+			mu.Lock()
+			got[k] = ds
+			mu.Unlock()
+		}
+		if concurrent {
+			wg.Go(run)
+		} else {
+			run()
+		}
+	}
+	wg.Wait()
+	return got
+}
+
+func TestSameSeedSameDecisions(t *testing.T) {
+	cfg := Config{Seed: 42, ErrorRate: 0.3, Latency: 10 * time.Millisecond, Jitter: 20 * time.Millisecond}
+	want := decisions(New(cfg), false)
+	for i := range 10 {
+		got := decisions(New(cfg), true)
+		for _, k := range keys {
+			if !slices.Equal(got[k], want[k]) {
+				t.Fatalf("run %d, %s: concurrent decisions %v, sequential %v", i, k, got[k], want[k])
+			}
+		}
+	}
+}
+
+func TestDifferentSeedDifferentDecisions(t *testing.T) {
+	cfg := Config{Seed: 42, ErrorRate: 0.3, Jitter: 20 * time.Millisecond}
+	a := decisions(New(cfg), false)
+	cfg.Seed = 43
+	b := decisions(New(cfg), false)
+	for _, k := range keys {
+		if !slices.Equal(a[k], b[k]) {
+			return
+		}
+	}
+	t.Error("seeds 42 and 43 made the same decisions for every key")
+}
+
+func TestDecisionsPerKey(t *testing.T) {
+	cfg := Config{Seed: 5, ErrorRate: 0.5}
+	alone := New(cfg)
+	busy := New(cfg)
+	for i := range 5 {
+		for range i { // other keys' calls in between don't shift this key's decisions
+			busy.Decide("other")
+		}
+		if a, b := alone.Decide("key"), busy.Decide("key"); a != b {
+			t.Errorf("call %d: %v alone, %v with other keys in between", i, a, b)
+		}
+	}
+}
+
+func TestRates(t *testing.T) {
+	in := New(Config{Seed: 42, ErrorRate: 0.3, Latency: 10 * time.Millisecond, Jitter: 20 * time.Millisecond})
+	for i := range 10_000 {
+		in.Decide(fmt.Sprint("key-", i%100))
+	}
+	st := in.Stats()
+	if st.Calls != 10_000 {
+		t.Errorf("Calls = %d, want 10000", st.Calls)
+	}
+	if r := st.FailureRate(); math.Abs(r-0.3) > 0.02 {
+		t.Errorf("FailureRate = %.3f, want 0.3 ± 0.02", r)
+	}
+	if avg := st.Delay / time.Duration(st.Calls); (avg - 20*time.Millisecond).Abs() > time.Millisecond {
+		t.Errorf("average delay %v, want 20ms ± 1ms (10ms latency + half of the 20ms jitter)", avg)
+	}
+}
+
+func TestNoCallsNoRate(t *testing.T) {
+	if r := New(Config{}).Stats().FailureRate(); !math.IsNaN(r) {
+		t.Errorf("FailureRate with no calls = %v, want NaN", r)
+	}
+}
+
+type pages map[string][]string
+
+func (p pages) Fetch(_ context.Context, url string) (string, []string, error) {
+	return "body of " + url, p[url], nil
+}
+
+func TestFlakyFetcher(t *testing.T) {
+	var slept time.Duration
+	cfg := Config{Seed: 7, ErrorRate: 0.5, Latency: 100 * time.Millisecond,
+		Sleep: func(_ context.Context, d time.Duration) error { slept += d; return nil }}
+	f := FlakyFetcher{Fetcher: pages{}, Injector: New(cfg)}
+	failed := 0
+	for range 20 {
+		body, _, err := f.Fetch(context.Background(), keys[0])
+		switch {
+		case err == nil && body != "body of "+keys[0]:
+			t.Errorf("a fetch that didn't fail returned %q", body)
+		case err != nil && !errors.Is(err, ErrInjected):
+			t.Errorf("err = %v, want ErrInjected", err)
+		case err != nil:
+			failed++
+		}
+	}
+	if failed == 0 || failed == 20 {
+		t.Errorf("%d of 20 fetches failed with a 0.5 error rate", failed)
+	}
+	if slept != 20*100*time.Millisecond {
+		t.Errorf("slept %v, want 2s (20 fetches of 100ms)", slept)
+	}
+}
+
+func TestLatencyHonorsContext(t *testing.T) {
+	f := FlakyFetcher{Fetcher: pages{}, Injector: New(Config{Latency: time.Hour})} // the real sleep
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := f.Fetch(ctx, keys[0]); !errors.Is(err, context.Canceled) {
+		t.Errorf("Fetch with a canceled ctx: err = %v, want context.Canceled", err)
+	}
+}
+
+func TestPartialWrites(t *testing.T) {
+	var out bytes.Buffer
+	w := Writer{W: &out, PartialWrites: true, Injector: New(Config{Seed: 1, ErrorRate: 0.5})}
+	text := []byte(strings.Repeat("gopher ", 20))
+	short := 0
+	for i := range 20 {
+		out.Reset()
+		n, err := w.Write(text)
+		if err == nil {
+			if n != len(text) || !bytes.Equal(out.Bytes(), text) {
+				t.Errorf("write %d: no error, but %d of %d bytes written", i, n, len(text))
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInjected) {
+			t.Errorf("write %d: err = %v, want ErrInjected", i, err)
+		}
+		if n >= len(text) || !bytes.Equal(out.Bytes(), text[:n]) { // io.Writer: n < len(p) with the error
+			t.Errorf("write %d: failed with n = %d, out = %q", i, n, out.String())
+		}
+		if n > 0 {
+			short++
+		}
+	}
+	if short == 0 {
+		t.Error("no short writes in 20 with a 0.5 error rate")
+	}
+}
+
+func TestStoreReplays(t *testing.T) {
+	failures := func() []string {
+		s := Store{Injector: New(Config{Seed: 9, ErrorRate: 0.25})}
+		var failed []string
+		for i := range 12 {
+			file := fmt.Sprintf("upload-%02d.txt", i)
+			if err := s.Store(file); err != nil {
+				if !errors.Is(err, ErrInjected) {
+					t.Errorf("Store(%s) = %v, want ErrInjected", file, err)
+				}
+				failed = append(failed, file)
+			}
+		}
+		return failed
+	}
+	first := failures()
+	if len(first) == 0 {
+		t.Fatal("no failed stores in 12 with a 0.25 error rate")
+	}
+	if again := failures(); !slices.Equal(first, again) {
+		t.Errorf("seed 9 failed %v, then %v", first, again)
+	}
+}
@@ -0,0 +1,63 @@
+package recovered
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+// panic/recover as a pattern: recover only works in a deferred func, in the goroutine that panicked.
+// So every goroutine that runs code which might panic - a job, a callback, a plugin - needs its own,
+// at the top: a panic that reaches the top of any goroutine ends the whole program, every other goroutine
+// with it. These wrap that deferred recover once, for code that's handed a func to run:
+//
+// - Call runs f and turns a panic into an error, a *PanicError, that the caller handles like any other
+// - Go starts f on a new goroutine and logs a panic (with its stack) instead of crashing
+//
+// Recovering isn't fixing: the panicking code's state may be half-updated. It's for work where one item
+// failing shouldn't take the rest down - a request, a job - not for carrying on as if nothing happened.
+// (httpserver's Recover middleware is the same for HTTP handlers, turning the panic into a 500)
+
+// PanicError is a recovered panic: the value passed to panic, and the stack of the goroutine at that point
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap is the panic value when it was an error - a runtime error (nil map, index out of range) is one -
+// so errors.Is and errors.As see through to it
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Call runs f, returning its error - or a *PanicError if it panicked. The stack is taken inside the deferred
+// func, before unwinding: it still shows where the panic happened
+func Call(f func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// Go runs f on a new goroutine, logging a panic in it to logger as an error with a stack attribute,
+// instead of letting it end the program. The channel is closed once f has returned (and any panic is logged)
+func Go(logger *slog.Logger, name string, f func()) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := Call(func() error { f(); return nil })
+		if pe, ok := err.(*PanicError); ok {
+			logger.LogAttrs(context.Background(), slog.LevelError, "goroutine panicked",
+				slog.String("goroutine", name), slog.Any("panic", pe.Value), slog.String("stack", string(pe.Stack)))
+		}
+	}()
+	return done
+}
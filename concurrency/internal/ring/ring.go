@@ -0,0 +1,85 @@
+package ring
+
+import "sync"
+
+// A fixed-capacity ring buffer, for keeping the last N of something - log lines, events, measurements.
+//
+// The capacity is set once and the buffer never grows: when it's full, Push overwrites the oldest value
+// (a history - the newest N win), and TryPush refuses instead (a queue - nothing is lost silently).
+// Values live in one slice used in a circle, so neither allocates after New.
+//
+// A Buffer is safe for concurrent use: the examples feed it from several goroutines at once.
+
+type Buffer[T any] struct {
+	mu    sync.Mutex
+	buf   []T
+	start int // index of the oldest value
+	n     int
+}
+
+// New returns an empty buffer for capacity values (at least 1)
+func New[T any](capacity int) *Buffer[T] {
+	return &Buffer[T]{buf: make([]T, max(capacity, 1))}
+}
+
+// Push adds v as the newest value. If the buffer is full, the oldest is dropped to make room -
+// it's returned, with true
+func (b *Buffer[T]) Push(v T) (dropped T, overwrote bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.n == len(b.buf) {
+		dropped, overwrote = b.buf[b.start], true
+		b.buf[b.start] = v
+		b.start = (b.start + 1) % len(b.buf)
+		return dropped, overwrote
+	}
+	b.buf[(b.start+b.n)%len(b.buf)] = v
+	b.n++
+	return dropped, false
+}
+
+// TryPush adds v only if there is room, and reports whether it did
+func (b *Buffer[T]) TryPush(v T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.n == len(b.buf) {
+		return false
+	}
+	b.buf[(b.start+b.n)%len(b.buf)] = v
+	b.n++
+	return true
+}
+
+// Pop removes and returns the oldest value
+func (b *Buffer[T]) Pop() (T, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var zero T
+	if b.n == 0 {
+		return zero, false
+	}
+	v := b.buf[b.start]
+	b.buf[b.start] = zero
+	b.start = (b.start + 1) % len(b.buf)
+	b.n--
+	return v, true
+}
+
+func (b *Buffer[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.n
+}
+
+func (b *Buffer[T]) Cap() int { return len(b.buf) } // never changes, so no lock
+
+// Snapshot returns a copy of the values, oldest first. It doesn't change the buffer
+func (b *Buffer[T]) Snapshot() []T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]T, b.n)
+	for i := range b.n {
+		out[i] = b.buf[(b.start+i)%len(b.buf)]
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package ring
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPushOverwrites(t *testing.T) {
+	b := New[int](3)
+	var dropped []int
+	for i := 1; i <= 5; i++ {
+		if d, ok := b.Push(i); ok {
+			dropped = append(dropped, d)
+		}
+	}
+	if !slices.Equal(dropped, []int{1, 2}) || !slices.Equal(b.Snapshot(), []int{3, 4, 5}) {
+		t.Errorf("dropped %v, kept %v; want [1 2] and [3 4 5]", dropped, b.Snapshot())
+	}
+}
+
+func TestTryPushRefuses(t *testing.T) {
+	b := New[int](3)
+	accepted := 0
+	for i := 1; i <= 5; i++ {
+		if b.TryPush(i) {
+			accepted++
+		}
+	}
+	if accepted != 3 || !slices.Equal(b.Snapshot(), []int{1, 2, 3}) {
+		t.Errorf("accepted %d, kept %v; want 3 and [1 2 3]", accepted, b.Snapshot())
+	}
+	if v, _ := b.Pop(); v != 1 || !b.TryPush(6) || !slices.Equal(b.Snapshot(), []int{2, 3, 6}) {
+		t.Errorf("after Pop and TryPush(6): popped %d, kept %v", v, b.Snapshot())
+	}
+}
+
+// Mixed Push/TryPush/Pop against a slice holding the last Cap values
+func TestMatchesModel(t *testing.T) {
+	b := New[int](4)
+	var model []int
+	for i := range 1000 {
+		switch {
+		case i%7 == 0:
+			got, ok := b.Pop()
+			if len(model) == 0 {
+				if ok {
+					t.Fatalf("op %d: Pop on empty = %d, true", i, got)
+				}
+				continue
+			}
+			if !ok || got != model[0] {
+				t.Fatalf("op %d: Pop = %d, %t, want %d", i, got, ok, model[0])
+			}
+			model = model[1:]
+		case i%5 == 0:
+			pushed := b.TryPush(i)
+			if pushed != (len(model) < 4) {
+				t.Fatalf("op %d: TryPush = %t with %d values", i, pushed, len(model))
+			}
+			if pushed {
+				model = append(model, i)
+			}
+		default:
+			b.Push(i)
+			if model = append(model, i); len(model) > 4 {
+				model = model[1:]
+			}
+		}
+		if !slices.Equal(b.Snapshot(), model) || b.Len() != len(model) {
+			t.Fatalf("op %d: Snapshot %v (Len %d), want %v", i, b.Snapshot(), b.Len(), model)
+		}
+	}
+	if b.Cap() != 4 {
+		t.Errorf("Cap = %d, want 4 - it never grows", b.Cap())
+	}
+}
+
+func TestEmptyAndSmall(t *testing.T) {
+	b := New[string](0)
+	if b.Cap() != 1 {
+		t.Errorf("New(0).Cap() = %d, want 1", b.Cap())
+	}
+	if _, ok := b.Pop(); ok || len(b.Snapshot()) != 0 {
+		t.Error("empty buffer: Pop ok or non-empty Snapshot")
+	}
+	b.Push("a")
+	if d, ok := b.Push("b"); !ok || d != "a" || !slices.Equal(b.Snapshot(), []string{"b"}) {
+		t.Errorf("capacity 1: Push(b) dropped %q, %t, kept %v", d, ok, b.Snapshot())
+	}
+}
+
+// Snapshot is a copy: changing it doesn't reach the buffer
+func TestSnapshotCopies(t *testing.T) {
+	b := New[int](2)
+	b.Push(1)
+	s := b.Snapshot()
+	s[0] = 99
+	if v, _ := b.Pop(); v != 1 {
+		t.Errorf("after changing the snapshot, Pop = %d, want 1", v)
+	}
+}
+
+// Run with -race: many goroutines pushing, popping and reading at once
+func TestConcurrent(t *testing.T) {
+	b := New[string](10)
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := range 100 {
+				b.Push(fmt.Sprintf("worker %d event %d", w, i))
+				if i%10 == 0 {
+					b.Pop()
+					b.Snapshot()
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if b.Len() != 10 {
+		t.Errorf("Len = %d after 800 pushes and 80 pops, want 10", b.Len())
+	}
+}
@@ -0,0 +1,120 @@
+package workpool
+
+import (
+	"concurrency/internal/recovered"
+	"context"
+	"errors"
+	"sync"
+)
+
+// A worker pool with types: jobs of type In go in, each is turned into an Out by the pool's function,
+// and the Results come out with the job they came from. The plain pools in these notes (shutdown.go,
+// retrypool.go) are written for one job type each; this one is written once, for any.
+//
+//	p := workpool.New(ctx, 4, func(ctx context.Context, url string) (int, error) { ... })
+//	go func() {
+//		for _, u := range urls {
+//			p.Submit(u)
+//		}
+//		p.Wait()
+//	}()
+//	for r := range p.Results() { ... r.In, r.Out, r.Err ... }
+//
+// - Submit blocks until a worker takes the job, so a slow pool slows the submitter down (back pressure)
+// - Results is unbuffered: it must be read while jobs run, and Wait only returns once every result is read -
+//   so Submit and Wait go in one goroutine and the reading in another
+// - cancelling ctx stops the pool: Submit returns the cause, workers stop taking jobs (the running ones
+//   see the cancel through their ctx), and results nobody is waiting for anymore are dropped
+// - a job that panics is that job's Result, with a *recovered.PanicError as its Err - the worker goes on to
+//   the next job. Unrecovered, one bad input would end the program, and every other job's work with it
+
+// ErrClosed is returned by Submit after Wait
+var ErrClosed = errors.New("workpool: submit after Wait")
+
+// Result is what became of one job
+type Result[In, Out any] struct {
+	In  In
+	Out Out
+	Err error
+}
+
+type Pool[In, Out any] struct {
+	ctx     context.Context
+	fn      func(ctx context.Context, in In) (Out, error)
+	jobs    chan In
+	results chan Result[In, Out]
+	closing chan struct{} // closed by Wait: no more jobs
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// New starts workers (at least 1) running fn on the submitted jobs, until Wait or ctx is done
+func New[In, Out any](ctx context.Context, workers int, fn func(ctx context.Context, in In) (Out, error)) *Pool[In, Out] {
+	p := &Pool[In, Out]{
+		ctx:     ctx,
+		fn:      fn,
+		jobs:    make(chan In),
+		results: make(chan Result[In, Out]),
+		closing: make(chan struct{}),
+	}
+	for range max(workers, 1) {
+		p.wg.Go(p.work)
+	}
+	return p
+}
+
+func (p *Pool[In, Out]) work() {
+	for {
+		// jobs is never closed (a Submit racing with Wait would panic sending on it) - closing ends the loop instead.
+		// A job a worker has taken is always run: Submit returned nil for it
+		select {
+		case in := <-p.jobs:
+			var out Out
+			err := recovered.Call(func() (err error) {
+				out, err = p.fn(p.ctx, in)
+				return err
+			})
+			select {
+			case p.results <- Result[In, Out]{In: in, Out: out, Err: err}:
+			case <-p.ctx.Done():
+			}
+		case <-p.closing:
+			return
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// Submit hands in to a worker, waiting until one is free. It fails, without running in,
+// if ctx is done (with its cause) or Wait has been called (ErrClosed)
+func (p *Pool[In, Out]) Submit(in In) error {
+	if err := context.Cause(p.ctx); err != nil { // checked first: select picks at random among ready cases
+		return err
+	}
+	select {
+	case p.jobs <- in:
+		return nil
+	case <-p.closing:
+		return ErrClosed
+	case <-p.ctx.Done():
+		return context.Cause(p.ctx)
+	}
+}
+
+// Results delivers a Result for every job Submit accepted, in the order they finish, and is closed after Wait
+func (p *Pool[In, Out]) Results() <-chan Result[In, Out] {
+	return p.results
+}
+
+// Wait stops taking jobs, waits for the workers to finish the ones they have, and closes Results.
+// It returns ctx's cause if the pool was cancelled (some results may be missing then), or nil.
+// Calling it again (even at the same time) waits for the first call and returns the same
+func (p *Pool[In, Out]) Wait() error {
+	p.once.Do(func() {
+		close(p.closing)
+		p.wg.Wait()
+		close(p.results)
+	})
+	return context.Cause(p.ctx)
+}
@@ -0,0 +1,177 @@
+package workpool
+
+import (
+	"concurrency/internal/recovered"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitGoroutines waits (up to a second) for the goroutine count to drop back to want
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines still running, want %d - a goroutine leaked", runtime.NumGoroutine(), want)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// run submits jobs and waits in one goroutine, and collects the results in this one
+func run[In, Out any](p *Pool[In, Out], jobs []In) ([]Result[In, Out], error) {
+	waited := make(chan error, 1)
+	go func() {
+		for _, in := range jobs {
+			p.Submit(in)
+		}
+		waited <- p.Wait()
+	}()
+	var rs []Result[In, Out]
+	for r := range p.Results() {
+		rs = append(rs, r)
+	}
+	return rs, <-waited
+}
+
+func TestEveryJobOnce(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	missing := errors.New("missing")
+	p := New(context.Background(), 3, func(_ context.Context, n int) (string, error) {
+		if n%10 == 0 {
+			return "", fmt.Errorf("job %d: %w", n, missing)
+		}
+		return fmt.Sprint(n * n), nil
+	})
+	jobs := make([]int, 100)
+	for i := range jobs {
+		jobs[i] = i
+	}
+	rs, err := run(p, jobs)
+	if err != nil {
+		t.Errorf("Wait = %v", err)
+	}
+
+	var seen []int
+	for _, r := range rs {
+		seen = append(seen, r.In)
+		switch {
+		case r.In%10 == 0:
+			if !errors.Is(r.Err, missing) || r.Out != "" {
+				t.Errorf("job %d: %q, %v - want its own error", r.In, r.Out, r.Err)
+			}
+		case r.Err != nil || r.Out != fmt.Sprint(r.In*r.In):
+			t.Errorf("job %d: %q, %v - a result with the wrong job", r.In, r.Out, r.Err)
+		}
+	}
+	slices.Sort(seen)
+	if !slices.Equal(seen, jobs) {
+		t.Errorf("results for jobs %v, want each of 0..99 once", seen)
+	}
+	waitGoroutines(t, baseline)
+}
+
+func TestWorkerLimit(t *testing.T) {
+	for _, c := range []struct{ workers, want int }{{1, 1}, {4, 4}, {0, 1}, {-3, 1}} {
+		var running, peak atomic.Int32
+		p := New(context.Background(), c.workers, func(_ context.Context, n int) (int, error) {
+			cur := running.Add(1)
+			defer running.Add(-1)
+			for old := peak.Load(); cur > old && !peak.CompareAndSwap(old, cur); old = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			return n, nil
+		})
+		rs, _ := run(p, make([]int, 40))
+		if len(rs) != 40 || int(peak.Load()) > c.want {
+			t.Errorf("%d workers: %d results, up to %d jobs at once - want 40 and at most %d", c.workers, len(rs), peak.Load(), c.want)
+		}
+	}
+}
+
+// A job that panics is that job's Result - the worker goes on to the next
+func TestPanicIsAResult(t *testing.T) {
+	p := New(context.Background(), 1, func(_ context.Context, n int) (int, error) {
+		if n == 2 {
+			panic("bad input")
+		}
+		return n, nil
+	})
+	rs, _ := run(p, []int{1, 2, 3})
+	if len(rs) != 3 {
+		t.Fatalf("%d results, want 3 - the worker stopped after the panic", len(rs))
+	}
+	for _, r := range rs {
+		var pe *recovered.PanicError
+		if isPanic := errors.As(r.Err, &pe); isPanic != (r.In == 2) || (isPanic && pe.Value != "bad input") {
+			t.Errorf("job %d: %v", r.In, r.Err)
+		}
+	}
+}
+
+func TestSubmitAfterWait(t *testing.T) {
+	p := New(context.Background(), 2, func(_ context.Context, n int) (int, error) { return n, nil })
+	if _, err := run(p, []int{1}); err != nil {
+		t.Errorf("Wait = %v", err)
+	}
+	if err := p.Submit(2); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Wait = %v, want ErrClosed", err)
+	}
+	// again, and several at once: the same answer, no panic from closing twice
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if err := p.Wait(); err != nil {
+				t.Errorf("Wait again = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// Cancelled part way: Submit fails with the cause from then on, Wait reports it without anyone reading
+// Results, the running jobs see the cancel, and no goroutine is left behind
+func TestCancel(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	started := make(chan int, 2)
+	p := New(ctx, 2, func(ctx context.Context, n int) (int, error) {
+		started <- n
+		<-ctx.Done()
+		return 0, context.Cause(ctx)
+	})
+	submitted := make(chan error, 1)
+	go func() {
+		var err error
+		for n := 0; err == nil; n++ {
+			err = p.Submit(n)
+		}
+		submitted <- err
+	}()
+	<-started
+	<-started // both workers busy, the third Submit waiting for one
+	shutdown := errors.New("shutting down")
+	cancel(shutdown)
+
+	if err := <-submitted; !errors.Is(err, shutdown) {
+		t.Errorf("Submit after cancel = %v, want the cause", err)
+	}
+	if err := p.Submit(99); !errors.Is(err, shutdown) {
+		t.Errorf("another Submit = %v, want the cause", err)
+	}
+	if err := p.Wait(); !errors.Is(err, shutdown) {
+		t.Errorf("Wait = %v, want the cause", err)
+	}
+	if _, open := <-p.Results(); open {
+		t.Error("Results still open after Wait")
+	}
+	waitGoroutines(t, baseline)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// === Goroutine leaks ===
+
+// A goroutine is only freed when its function returns. One blocked forever - on a send nobody receives,
+// a receive nobody sends to, a select with no way out - is a leak: it's never collected, and neither is
+// anything it references. One per request adds up to a server slowly running out of memory.
+// The runtime can't report these (they aren't deadlocks: the rest of the program is still running).
+//
+// Every goroutine started needs an answer to "how does it end?" - before it's started.
+
+// --- Checking for leaks ---
+
+// The same idea as go.uber.org/goleak: list the goroutines before and after, and anything new that's still
+// there after a grace period leaked. runtime.Stack(buf, true) dumps every goroutine in the traceback format:
+//
+//	goroutine 7 [chan send]:
+//	main.searchLeaky.func1()
+//		/root/module/concurrency/leaks.go:64 +0x3c
+//	created by main.searchLeaky in goroutine 1
+//		...
+
+var stackHeader = regexp.MustCompile(`^goroutine (\d+) \[([^\]]+)\]:`)
+
+// goroutineStacks maps the id of every live goroutine to its stack
+func goroutineStacks() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf)) // didn't fit
+	}
+	stacks := map[string]string{}
+	for _, g := range bytes.Split(buf, []byte("\n\n")) {
+		if m := stackHeader.FindSubmatch(g); m != nil {
+			stacks[string(m[1])] = string(g)
+		}
+	}
+	return stacks
+}
+
+// leakedBy runs f, and returns a one-line summary of each goroutine it left behind (state and function).
+// Goroutines get a grace period to finish, like a test would give them
+func leakedBy(f func()) []string {
+	before := goroutineStacks()
+	f()
+	var leaked []string
+	for deadline := time.Now().Add(200 * time.Millisecond); ; time.Sleep(10 * time.Millisecond) {
+		leaked = leaked[:0]
+		for id, stack := range goroutineStacks() {
+			if _, ok := before[id]; !ok {
+				lines := strings.Split(stack, "\n")
+				state := stackHeader.FindStringSubmatch(lines[0])[2]
+				fn, _, _ := strings.Cut(lines[1], "(")
+				leaked = append(leaked, fmt.Sprintf("[%s] %s", state, fn))
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+	slices.Sort(leaked)
+	return leaked
+}
+
+// --- 1. A send nobody receives ---
+
+// searchLeaky asks a slow backend, giving up after timeout. On a timeout nobody will ever receive
+// the result, so the goroutine blocks on its unbuffered send forever
+func searchLeaky(query string, took, timeout time.Duration) (string, error) {
+	result := make(chan string)
+	go func() {
+		time.Sleep(took) // the backend
+		result <- "results for " + query
+	}()
+	select {
+	case r := <-result:
+		return r, nil
+	case <-time.After(timeout):
+		return "", errOpTimeout
+	}
+}
+
+// searchFixed gives the channel room for the one result, so the send never blocks -
+// the goroutine finishes even if nobody is waiting, and the result is garbage collected
+func searchFixed(query string, took, timeout time.Duration) (string, error) {
+	result := make(chan string, 1)
+	go func() {
+		time.Sleep(took)
+		result <- "results for " + query
+	}()
+	select {
+	case r := <-result:
+		return r, nil
+	case <-time.After(timeout):
+		return "", errOpTimeout
+	}
+}
+
+// --- 2. A worker with no way to stop ---
+
+// startLoggerLeaky logs every line sent to it, until lines is closed. But nobody closes it
+// (the senders come and go, and none of them owns it), so the worker waits for the next line forever
+func startLoggerLeaky(lines <-chan string) {
+	go func() {
+		for l := range lines {
+			_ = l // write it somewhere
+		}
+	}()
+}
+
+// startLoggerFixed takes a quit channel - or a context - and returns when it's closed
+func startLoggerFixed(ctx context.Context, lines <-chan string) {
+	go func() {
+		for {
+			select {
+			case l := <-lines:
+				_ = l
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// --- 3. Waiting on time.After as the only way out ---
+
+// Since Go 1.23 an unfired time.After timer is garbage collected as soon as nothing references it,
+// so a time.After in a loop no longer piles up timers in memory. But a goroutine whose only way to
+// finish is a long time.After still lingers for that long - here, an hour after every call
+
+// refreshLeaky notices a new value, or re-checks after an hour - there's no other way out
+func refreshLeaky(update <-chan int) {
+	go func() {
+		for {
+			select {
+			case <-update:
+			case <-time.After(time.Hour):
+				return // "it does stop, eventually"
+			}
+		}
+	}()
+}
+
+// refreshFixed stops with its context, and uses one Timer it can Stop (and Reset), instead of a new one per loop
+func refreshFixed(ctx context.Context, update <-chan int) {
+	go func() {
+		timer := time.NewTimer(time.Hour)
+		defer timer.Stop()
+		for {
+			select {
+			case <-update:
+				timer.Reset(time.Hour)
+			case <-timer.C:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func goroutineLeakExample() {
+	// leaks_test.go makes the same checks with go test
+	show := func(name string, leaked []string) {
+		fmt.Printf("%-34s leaked %d goroutine(s) %v\n", name+":", len(leaked), leaked)
+	}
+
+	show("searchLeaky, timing out", leakedBy(func() {
+		_, err := searchLeaky("gopher", 50*time.Millisecond, 10*time.Millisecond)
+		fmt.Println("searchLeaky:", err, "| timed out:", errors.Is(err, errOpTimeout))
+	}))
+	show("searchFixed, timing out", leakedBy(func() {
+		_, err := searchFixed("gopher", 50*time.Millisecond, 10*time.Millisecond)
+		fmt.Println("searchFixed:", err)
+		time.Sleep(50 * time.Millisecond) // the backend answers late - and the goroutine can still finish
+	}))
+
+	show("startLoggerLeaky", leakedBy(func() {
+		lines := make(chan string)
+		startLoggerLeaky(lines)
+		lines <- "started"
+	}))
+	show("startLoggerFixed, then cancel", leakedBy(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		lines := make(chan string)
+		startLoggerFixed(ctx, lines)
+		lines <- "started"
+	}))
+
+	show("refreshLeaky", leakedBy(func() {
+		update := make(chan int)
+		refreshLeaky(update)
+		update <- 1
+	}))
+	show("refreshFixed, then cancel", leakedBy(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		update := make(chan int)
+		refreshFixed(ctx, update)
+		update <- 1
+	}))
+
+	// The leaked ones above are still blocked, and stay that way until the program exits
+	fmt.Println("goroutines alive now:", runtime.NumGoroutine(), "(main, and the three leaks)")
+}
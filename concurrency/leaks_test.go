@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Each leaky version leaves exactly its one goroutine behind, blocked where the comments say;
+// each fixed version leaves none. The leaked goroutines stay blocked until the test binary exits
+func TestGoroutineLeaks(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		run  func()
+		want []string
+	}{
+		{"searchLeaky", func() {
+			if _, err := searchLeaky("gopher", 20*time.Millisecond, time.Millisecond); !errors.Is(err, errOpTimeout) {
+				t.Errorf("searchLeaky: %v, want errOpTimeout", err)
+			}
+		}, []string{"[chan send] searchLeaky.func1"}},
+		{"searchFixed", func() {
+			searchFixed("gopher", 20*time.Millisecond, time.Millisecond)
+		}, nil},
+
+		{"startLoggerLeaky", func() {
+			lines := make(chan string)
+			startLoggerLeaky(lines)
+			lines <- "started"
+		}, []string{"[chan receive] startLoggerLeaky.func1"}},
+		{"startLoggerFixed", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			lines := make(chan string)
+			startLoggerFixed(ctx, lines)
+			lines <- "started"
+		}, nil},
+
+		{"refreshLeaky", func() {
+			update := make(chan int)
+			refreshLeaky(update)
+			update <- 1
+		}, []string{"[select] refreshLeaky.func1"}},
+		{"refreshFixed", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			update := make(chan int)
+			refreshFixed(ctx, update)
+			update <- 1
+		}, nil},
+	} {
+		if got := withoutPackage(leakedBy(c.run)); !slices.Equal(got, c.want) {
+			t.Errorf("%s: leaked %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// withoutPackage drops the package from "[state] pkg.func": it's main in the example, concurrency under go test
+func withoutPackage(leaked []string) []string {
+	for i, l := range leaked {
+		state, fn, _ := strings.Cut(l, "] ")
+		_, fn, _ = strings.Cut(fn, ".")
+		leaked[i] = state + "] " + fn
+	}
+	return leaked
+}
+
+// The search answers in time: no leak either way, and the result comes back
+func TestSearchInTime(t *testing.T) {
+	for name, search := range map[string]func(string, time.Duration, time.Duration) (string, error){
+		"searchLeaky": searchLeaky, "searchFixed": searchFixed,
+	} {
+		var r string
+		var err error
+		leaked := leakedBy(func() { r, err = search("gopher", 0, time.Second) })
+		if err != nil || r != "results for gopher" || len(leaked) > 0 {
+			t.Errorf("%s: %q, %v, leaked %q", name, r, err, leaked)
+		}
+	}
+}
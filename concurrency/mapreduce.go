@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// === Map-reduce over a slice ===
+
+// channelExample splits its slice in two and sums each half on its own goroutine.
+// The same thing N ways: cut the slice into chunks, start a goroutine per chunk (map),
+// and combine the partial results as they come off the channel (reduce).
+//
+// The partials arrive in whatever order the goroutines finish, so the reduce step must not care
+// about order: combining a, b, c in any order and grouping must give the same result
+// (sum, max, counting - but not, say, "the first match" or a string concatenation).
+
+// chunkedSum is channelExample's sum with any number of parts - sum (in concurrency.go) is unchanged.
+// Like slices.Chunk, it panics if chunkSize < 1
+func chunkedSum(s []int, chunkSize int) int {
+	if chunkSize < 1 {
+		panic("chunkedSum: chunkSize must be at least 1")
+	}
+	ch := make(chan int)
+	chunks := 0
+	for c := range slices.Chunk(s, chunkSize) {
+		go sum(c, ch)
+		chunks++
+	}
+	total := 0
+	for range chunks { // exactly one partial per chunk, so no close is needed
+		total += <-ch
+	}
+	return total
+}
+
+// mapReduce is the general version: mapper turns a chunk into a partial result, and reduce merges two
+func mapReduce[T, R any](data []T, chunkSize int, mapper func([]T) R, reduce func(R, R) R) R {
+	if chunkSize < 1 {
+		panic("mapReduce: chunkSize must be at least 1")
+	}
+	partials := make(chan R, (len(data)+chunkSize-1)/chunkSize) // a buffer for every partial - no goroutine waits on the reducer
+	chunks := 0
+	for c := range slices.Chunk(data, chunkSize) {
+		go func() { partials <- mapper(c) }()
+		chunks++
+	}
+	var result R
+	for i := range chunks {
+		if i == 0 {
+			result = <-partials
+			continue
+		}
+		result = reduce(result, <-partials)
+	}
+	return result // the zero R for empty data
+}
+
+type stats struct {
+	count, sum int
+	min, max   int
+	histogram  [10]int // how many values end in each digit
+}
+
+func statsOf(chunk []int) stats {
+	st := stats{min: chunk[0], max: chunk[0]}
+	for _, v := range chunk {
+		st.count++
+		st.sum += v
+		st.min, st.max = min(st.min, v), max(st.max, v)
+		st.histogram[v%10]++
+	}
+	return st
+}
+
+func mergeStats(a, b stats) stats {
+	a.count += b.count
+	a.sum += b.sum
+	a.min, a.max = min(a.min, b.min), max(a.max, b.max)
+	for i := range a.histogram {
+		a.histogram[i] += b.histogram[i]
+	}
+	return a
+}
+
+func mapReduceExample() {
+	data := make([]int, 1_000_000)
+	for i := range data {
+		data[i] = rand.IntN(1_000_000)
+	}
+	want := statsOf(data) // the sequential answer
+
+	// Any chunk size gives the same answer - including one chunk, chunks that don't divide the slice evenly,
+	// and a chunk bigger than the slice
+	seqSum := want.sum
+	fmt.Printf("%-12s %8s %10s %10s\n", "chunk size", "chunks", "sum ok", "stats ok")
+	for _, size := range []int{1_000_000, 250_000, 100_000, 7_777, 1_000, 2_000_000} {
+		sumOK := chunkedSum(data, size) == seqSum
+		statsOK := mapReduce(data, size, statsOf, mergeStats) == want
+		fmt.Printf("%-12d %8d %10t %10t\n", size, (len(data)+size-1)/size, sumOK, statsOK)
+	}
+	fmt.Println("empty slice:", chunkedSum(nil, 10), mapReduce([]int{}, 10, statsOf, mergeStats) == stats{})
+
+	// Chunk size is the trade-off: each chunk costs a goroutine and a channel send, so tiny chunks spend
+	// more on coordination than on adding. Past GOMAXPROCS chunks, more parallelism isn't available anyway
+	for _, size := range []int{len(data), len(data) / runtime.GOMAXPROCS(0), 10_000, 100} {
+		start := time.Now()
+		chunkedSum(data, size)
+		fmt.Printf("chunk size %-8d %v\n", size, time.Since(start).Round(10*time.Microsecond))
+	}
+	fmt.Println("GOMAXPROCS:", runtime.GOMAXPROCS(0), "(with 1, the split can only cost time, not save it)")
+}
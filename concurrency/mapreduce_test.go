@@ -0,0 +1,39 @@
+package main
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// Every chunk size gives the sequential answer: one chunk, uneven chunks, single values, a chunk bigger than the slice
+func TestMapReduceMatchesSequential(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	data := make([]int, 100_003)
+	for i := range data {
+		data[i] = r.IntN(1_000_000)
+	}
+	want := statsOf(data)
+	for _, size := range []int{len(data), 25_000, 7_777, 1_000, 1, 2 * len(data)} {
+		if got := chunkedSum(data, size); got != want.sum {
+			t.Errorf("chunkedSum, chunks of %d: %d, want %d", size, got, want.sum)
+		}
+		if got := mapReduce(data, size, statsOf, mergeStats); got != want {
+			t.Errorf("mapReduce, chunks of %d: %+v, want %+v", size, got, want)
+		}
+	}
+}
+
+func TestMapReduceEmpty(t *testing.T) {
+	if got := chunkedSum(nil, 10); got != 0 {
+		t.Errorf("chunkedSum(nil) = %d", got)
+	}
+	// statsOf would panic on an empty chunk - there are none, so mapper is never called
+	if got := mapReduce([]int{}, 10, statsOf, mergeStats); got != (stats{}) {
+		t.Errorf("mapReduce(empty) = %+v, want the zero stats", got)
+	}
+}
+
+func TestMapReduceRejectsChunkSize(t *testing.T) {
+	wantPanic(t, "chunkedSum 0", func() { chunkedSum([]int{1}, 0) })
+	wantPanic(t, "mapReduce -1", func() { mapReduce([]int{1}, -1, statsOf, mergeStats) })
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// === A select-based main loop (multiplexing several inputs) ===
+
+// A long running program usually waits on several things at once: OS signals, a ticker for periodic work,
+// a shutdown request, and the actual work items. One goroutine with one select loop handles them all,
+// so the handlers never run concurrently with each other (no locks needed for the loop's own state).
+//
+// Priorities - when several cases are ready, select picks one AT RANDOM. So ordering is done with
+// non-blocking selects (a default case) checked first, from most to least important:
+// 1. shutdown and signals: a pending shutdown must not wait behind a queue full of work
+// 2. ticks: periodic jobs (ex. flushing stats) shouldn't starve while work keeps arriving
+// 3. otherwise block until anything is ready
+//
+// A closed input is set to nil - receiving from a nil channel blocks forever,
+// so its case is effectively removed from the select.
+
+var (
+	ErrMuxShutdown = errors.New("shutdown requested")
+	ErrMuxWorkDone = errors.New("work channel closed")
+	ErrMuxSignal   = errors.New("stopped by a signal")
+)
+
+// SignalError is returned when a signal handler asks the loop to stop. It is ErrMuxSignal for errors.Is,
+// like the other reasons Run stops, and has the signal for errors.As
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return "stopped by signal " + e.Signal.String()
+}
+
+func (e *SignalError) Unwrap() error { return ErrMuxSignal }
+
+// Mux holds the inputs and the handler for each. Every channel is injectable,
+// so the loop can be driven by fake signals/ticks in examples (and tests)
+type Mux struct {
+	Signals  <-chan os.Signal
+	Ticks    <-chan time.Time
+	Shutdown <-chan struct{}
+	Work     <-chan string
+
+	OnSignal func(os.Signal) (stop bool)
+	OnTick   func(time.Time)
+	OnWork   func(string)
+
+	// StopWhenWorkDone makes Run return ErrMuxWorkDone once Work is closed,
+	// instead of idling until a shutdown or signal
+	StopWhenWorkDone bool
+}
+
+// Run blocks until the loop stops, and returns why
+func (m *Mux) Run() error {
+	// local copies, so closed channels can be set to nil without changing m
+	signals, ticks, work := m.Signals, m.Ticks, m.Work
+
+	for {
+		// 1. shutdown and signals first
+		select {
+		case <-m.Shutdown:
+			return ErrMuxShutdown
+		case sig := <-signals:
+			if m.OnSignal(sig) {
+				return &SignalError{Signal: sig}
+			}
+			continue
+		default:
+		}
+
+		// 2. then ticks
+		select {
+		case t, ok := <-ticks:
+			if !ok {
+				ticks = nil
+			} else {
+				m.OnTick(t)
+			}
+			continue
+		default:
+		}
+
+		// 3. nothing urgent - wait for whatever comes next.
+		// Every input is in here too, otherwise a shutdown would wait for the next work item
+		select {
+		case <-m.Shutdown:
+			return ErrMuxShutdown
+		case sig := <-signals:
+			if m.OnSignal(sig) {
+				return &SignalError{Signal: sig}
+			}
+		case t, ok := <-ticks:
+			if !ok {
+				ticks = nil
+				continue
+			}
+			m.OnTick(t)
+		case item, ok := <-work:
+			if !ok {
+				if m.StopWhenWorkDone {
+					return ErrMuxWorkDone
+				}
+				work = nil
+				continue
+			}
+			m.OnWork(item)
+		}
+	}
+}
+
+// fakeSignal implements os.Signal (String and Signal methods), to send "signals" without the OS
+type fakeSignal string
+
+func (s fakeSignal) String() string { return string(s) }
+func (s fakeSignal) Signal()        {}
+
+// muxLog records what the handlers saw, in order
+type muxLog []string
+
+func (l *muxLog) mux() *Mux {
+	return &Mux{
+		OnSignal: func(sig os.Signal) bool {
+			*l = append(*l, "signal:"+sig.String())
+			return sig.String() != "hangup" // hangup = reload config and keep going, anything else stops
+		},
+		OnTick: func(time.Time) { *l = append(*l, "tick") },
+		OnWork: func(item string) { *l = append(*l, "work:"+item) },
+	}
+}
+
+func muxExamples() {
+	check := func(name string, log muxLog, err error, wantLog string, wantErr error) {
+		got := strings.Join(log, " ")
+		fmt.Printf("%-30s log [%s] stopped: %v | as expected: %t\n", name+":", got, err, got == wantLog && errors.Is(err, wantErr))
+	}
+
+	// Shutdown beats queued work: 3 items are waiting, but none are processed
+	{
+		var log muxLog
+		m := log.mux()
+		work := make(chan string, 3)
+		work <- "a"
+		work <- "b"
+		work <- "c"
+		shutdown := make(chan struct{})
+		close(shutdown)
+		m.Work, m.Shutdown = work, shutdown
+		err := m.Run()
+		check("shutdown before work", log, err, "", ErrMuxShutdown)
+	}
+
+	// Ticks are handled before work when both are ready
+	{
+		var log muxLog
+		m := log.mux()
+		ticks := make(chan time.Time, 2)
+		ticks <- time.Now()
+		ticks <- time.Now()
+		work := make(chan string, 2)
+		work <- "a"
+		work <- "b"
+		close(work)
+		close(ticks)
+		m.Ticks, m.Work, m.StopWhenWorkDone = ticks, work, true
+		err := m.Run()
+		check("ticks before work", log, err, "tick tick work:a work:b", ErrMuxWorkDone)
+	}
+
+	// A hangup signal is handled and the loop continues; an interrupt stops it
+	{
+		var log muxLog
+		m := log.mux()
+		signals := make(chan os.Signal, 2)
+		work := make(chan string)
+		m.Signals, m.Work = signals, work
+
+		done := make(chan error)
+		go func() { done <- m.Run() }()
+		work <- "a"
+		signals <- fakeSignal("hangup")
+		work <- "b"
+		signals <- fakeSignal("interrupt")
+		err := <-done
+
+		var sigErr *SignalError
+		check("signals", log, err, "work:a signal:hangup work:b signal:interrupt", ErrMuxSignal)
+		fmt.Println("  stopped by a *SignalError:", errors.As(err, &sigErr), "| signal:", sigErr.Signal)
+	}
+
+	// A closed work channel is switched off (set to nil), and the loop keeps running until shutdown
+	{
+		var log muxLog
+		m := log.mux()
+		work := make(chan string)
+		ticks := make(chan time.Time)
+		shutdown := make(chan struct{})
+		m.Work, m.Ticks, m.Shutdown = work, ticks, shutdown
+
+		done := make(chan error)
+		go func() { done <- m.Run() }()
+		work <- "last"
+		close(work)
+		ticks <- time.Now() // still handled after work is closed
+		close(shutdown)
+		check("closed work channel", log, <-done, "work:last tick", ErrMuxShutdown)
+	}
+}
+
+// The same loop wired to the real inputs: a ticker and os/signal.
+// Press Ctrl+C to stop it (or it stops itself after 2 seconds)
+func muxMainLoopExample() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	work := make(chan string)
+	go func() {
+		defer close(work)
+		for i := range 3 {
+			time.Sleep(300 * time.Millisecond)
+			work <- fmt.Sprint("job ", i)
+		}
+	}()
+
+	shutdown := make(chan struct{})
+	time.AfterFunc(2*time.Second, func() { close(shutdown) })
+
+	processed := 0
+	m := &Mux{
+		Signals:  signals,
+		Ticks:    ticker.C,
+		Shutdown: shutdown,
+		Work:     work,
+		OnSignal: func(sig os.Signal) bool {
+			fmt.Println("got", sig)
+			return true
+		},
+		OnTick: func(t time.Time) { fmt.Println("tick - processed so far:", processed) },
+		OnWork: func(item string) {
+			processed++
+			fmt.Println("processing", item)
+		},
+	}
+	fmt.Println("main loop stopped:", m.Run())
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runMux runs m in a goroutine, feeds it with drive, and returns what the handlers saw and why Run stopped
+func runMux(t *testing.T, log *muxLog, m *Mux, drive func()) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- m.Run() }()
+	drive()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run didn't return - log so far: %v", *log)
+		return nil
+	}
+}
+
+func checkMux(t *testing.T, log muxLog, err error, wantLog string, wantErr error) {
+	t.Helper()
+	if got := strings.Join(log, " "); got != wantLog {
+		t.Errorf("log [%s], want [%s]", got, wantLog)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("stopped with %v, want %v", err, wantErr)
+	}
+}
+
+func TestMuxShutdownBeatsWork(t *testing.T) {
+	var log muxLog
+	m := log.mux()
+	work := make(chan string, 3)
+	work <- "a"
+	work <- "b"
+	work <- "c"
+	shutdown := make(chan struct{})
+	close(shutdown)
+	m.Work, m.Shutdown = work, shutdown
+	checkMux(t, log, m.Run(), "", ErrMuxShutdown)
+}
+
+func TestMuxSignalBeatsTicks(t *testing.T) {
+	var log muxLog
+	m := log.mux()
+	ticks := make(chan time.Time, 1)
+	ticks <- time.Now()
+	signals := make(chan os.Signal, 1)
+	signals <- fakeSignal("interrupt")
+	m.Ticks, m.Signals = ticks, signals
+	checkMux(t, log, m.Run(), "signal:interrupt", ErrMuxSignal)
+}
+
+func TestMuxTicksBeforeWork(t *testing.T) {
+	var log muxLog
+	m := log.mux()
+	ticks := make(chan time.Time, 2)
+	ticks <- time.Now()
+	ticks <- time.Now()
+	close(ticks)
+	work := make(chan string, 2)
+	work <- "a"
+	work <- "b"
+	close(work)
+	m.Ticks, m.Work, m.StopWhenWorkDone = ticks, work, true
+	checkMux(t, log, m.Run(), "tick tick work:a work:b", ErrMuxWorkDone)
+}
+
+func TestMuxSignals(t *testing.T) {
+	var log muxLog
+	m := log.mux()
+	signals := make(chan os.Signal)
+	work := make(chan string)
+	m.Signals, m.Work = signals, work
+	err := runMux(t, &log, m, func() {
+		work <- "a"
+		signals <- fakeSignal("hangup") // handled, and the loop goes on
+		work <- "b"
+		signals <- fakeSignal("interrupt") // stops it
+	})
+	checkMux(t, log, err, "work:a signal:hangup work:b signal:interrupt", ErrMuxSignal)
+	var sigErr *SignalError
+	if !errors.As(err, &sigErr) || sigErr.Signal != fakeSignal("interrupt") {
+		t.Errorf("errors.As(%v, *SignalError) = %v, want the interrupt", err, sigErr)
+	}
+}
+
+func TestMuxClosedInputsSwitchedOff(t *testing.T) {
+	var log muxLog
+	m := log.mux()
+	work := make(chan string)
+	ticks := make(chan time.Time)
+	shutdown := make(chan struct{})
+	m.Work, m.Ticks, m.Shutdown = work, ticks, shutdown
+	err := runMux(t, &log, m, func() {
+		work <- "last"
+		close(work) // without StopWhenWorkDone, the loop keeps going
+		ticks <- time.Now()
+		close(ticks)
+		close(shutdown)
+	})
+	checkMux(t, log, err, "work:last tick", ErrMuxShutdown)
+}
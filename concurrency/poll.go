@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// === Polling to channel adapter ===
+
+// Many older APIs are "pull" style - you have to call them to find out if something changed
+// (ex. reading a file, checking an HTTP status endpoint).
+// Poll turns a pull style function into a "push" style channel stream,
+// so the caller can consume it with range or combine it with other channels in a select.
+// (the scheduler module runs many such loops at once - jobs on a Ticker each, with jitter and overlap prevention)
+
+// Result carries either a value or the error returned while fetching it.
+// Errors are sent on the channel as events instead of stopping the stream,
+// so a single failed read does not end the polling.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Poll calls fetch once straight away, then once every interval, and sends each result on the returned channel.
+// The channel is closed when ctx is cancelled (only the sender closes the channel).
+func Poll[T any](ctx context.Context, interval time.Duration, fetch func(ctx context.Context) (T, error)) <-chan Result[T] {
+	ticker := time.NewTicker(interval)
+	out := pollTicks(ctx, ticker.C, fetch)
+
+	// stop the ticker once polling is done, so it can be garbage collected
+	go func() {
+		<-ctx.Done()
+		ticker.Stop()
+	}()
+
+	return out
+}
+
+// pollTicks does the actual work. The ticks channel is passed in rather than created here,
+// so a fake clock (any chan time.Time the caller sends on) can drive it without waiting for real time.
+func pollTicks[T any](ctx context.Context, ticks <-chan time.Time, fetch func(ctx context.Context) (T, error)) <-chan Result[T] {
+	out := make(chan Result[T])
+
+	go func() {
+		defer close(out)
+		for {
+			v, err := fetch(ctx)
+
+			// the send must also watch ctx, otherwise this goroutine blocks forever (leaks)
+			// if the receiver stops reading after cancelling
+			select {
+			case out <- Result[T]{Value: v, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ticks:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Ex. fake clock - the example controls exactly when each poll happens
+func pollFakeClockExample() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := make(chan time.Time)
+	calls := 0
+	results := pollTicks(ctx, ticks, func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 2 {
+			return 0, fmt.Errorf("fetch %d failed", calls)
+		}
+		return calls, nil
+	})
+
+	fmt.Println(<-results) // first fetch happens without a tick
+	for range 2 {
+		ticks <- time.Now() // "advance" the clock by one interval
+		fmt.Println(<-results)
+	}
+}
+
+// --- Ex. config reload ---
+
+// Reloads a config file whenever its contents change.
+// Read errors (ex. the file being deleted and recreated by an editor) are reported but do not stop the loop.
+func configReloadExample() {
+	dir, err := os.MkdirTemp("", "config-reload")
+	if err != nil {
+		fmt.Println("could not create temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.conf")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	readConfig := func(ctx context.Context) (string, error) {
+		b, err := os.ReadFile(path)
+		return string(b), err
+	}
+
+	// simulate another process editing the file
+	go func() {
+		time.Sleep(120 * time.Millisecond)
+		os.WriteFile(path, []byte("log_level=info"), 0o644)
+		time.Sleep(120 * time.Millisecond)
+		os.WriteFile(path, []byte("log_level=debug"), 0o644)
+	}()
+
+	current := ""
+	for res := range Poll(ctx, 50*time.Millisecond, readConfig) {
+		if res.Err != nil {
+			fmt.Println("config not readable yet:", res.Err)
+			continue
+		}
+		if res.Value != current {
+			current = res.Value
+			fmt.Println("config reloaded:", current)
+		}
+	}
+	fmt.Println("stopped polling:", ctx.Err())
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFetch = errors.New("fetch failed")
+
+// The ticks channel is the fake clock: each send is one interval passing
+func TestPollTicks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := make(chan time.Time)
+	calls := 0
+	results := pollTicks(ctx, ticks, func(ctx context.Context) (int, error) {
+		calls++
+		if calls == 2 {
+			return 0, errFetch
+		}
+		return calls, nil
+	})
+
+	if r := <-results; r.Value != 1 || r.Err != nil {
+		t.Errorf("first result, before any tick: %+v, want {1 <nil>}", r)
+	}
+	ticks <- time.Now()
+	if r := <-results; !errors.Is(r.Err, errFetch) {
+		t.Errorf("second result: %+v, want the fetch error", r)
+	}
+	ticks <- time.Now() // an error doesn't stop the polling
+	if r := <-results; r.Value != 3 || r.Err != nil {
+		t.Errorf("third result: %+v, want {3 <nil>}", r)
+	}
+	if calls != 3 {
+		t.Errorf("fetch called %d times for 2 ticks, want 3", calls)
+	}
+}
+
+func TestPollTicksNoFetchWithoutATick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fetched := make(chan struct{}, 10)
+	results := pollTicks(ctx, make(chan time.Time), func(ctx context.Context) (int, error) {
+		fetched <- struct{}{}
+		return 0, nil
+	})
+	<-results
+	select {
+	case <-results:
+		t.Error("a second result without a tick")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if n := len(fetched); n != 1 {
+		t.Errorf("fetched %d times, want 1", n)
+	}
+}
+
+// Cancelling closes the channel - whether the poller is waiting for a tick or for the reader
+func TestPollTicksCancel(t *testing.T) {
+	for _, read := range []bool{true, false} {
+		ctx, cancel := context.WithCancel(context.Background())
+		results := pollTicks(ctx, make(chan time.Time), func(ctx context.Context) (int, error) { return 1, nil })
+		if read {
+			<-results // now blocked on the tick
+		} // else blocked sending the first result
+		cancel()
+		deadline := time.After(time.Second)
+		for closed := false; !closed; {
+			select {
+			case _, ok := <-results:
+				closed = !ok
+			case <-deadline:
+				t.Fatalf("read first: %t - results not closed after cancel", read)
+			}
+		}
+	}
+}
+
+func TestPoll(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+	n := 0
+	for r := range Poll(ctx, 10*time.Millisecond, func(ctx context.Context) (int, error) { return 7, nil }) {
+		if r.Value != 7 {
+			t.Errorf("result %+v", r)
+		}
+		n++
+	}
+	if n < 2 {
+		t.Errorf("%d results in 55ms at a 10ms interval", n)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package main
+
+// raceEnabled is true when the tests are built with -race (the race build tag is set then)
+const raceEnabled = true
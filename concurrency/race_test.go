@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// The notes say which counters race and which don't. A race rarely shows in the result (on one CPU the
+// unsafe counter is usually still 100), but the race detector reports it whenever two accesses aren't
+// ordered by synchronization - so these are run with it:
+//
+//	go test -race ./...                            the safe counters pass, the racy tests are skipped
+//	NOTES_RACY=1 go test -race -run Racy .         the racy tests fail with "race detected during execution of test"
+//
+// The racy ones only run on request: otherwise `go test -race ./...` would fail on purpose, and without
+// -race the map one could stop the whole test binary with "fatal error: concurrent map writes".
+
+// racy skips t unless the race detector is on and NOTES_RACY is set
+func racy(t *testing.T) {
+	t.Helper()
+	if !raceEnabled {
+		t.Skip("needs the race detector: go test -race")
+	}
+	if os.Getenv("NOTES_RACY") == "" {
+		t.Skip("fails on purpose under -race; set NOTES_RACY=1 to run it")
+	}
+}
+
+// incrementAll runs inc in n goroutines, and waits for all of them
+func incrementAll(n int, inc func()) {
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(inc)
+	}
+	wg.Wait()
+}
+
+// unsafeIncrementExample's loop: Inc is a read and a write of val, with nothing between goroutines
+func TestUnsafeIncrementRacy(t *testing.T) {
+	racy(t)
+	val := 0
+	incrementAll(100, func() { Inc(&val) })
+	t.Logf("val = %d (the race detector fails the test whatever it is)", val)
+}
+
+// unsafeMapWriteExample's loop: writes to one map from many goroutines
+func TestUnsafeMapWriteRacy(t *testing.T) {
+	racy(t)
+	counts := map[string]int{}
+	var mu sync.Mutex // only for the goroutine numbers - not around the map
+	next := 0
+	incrementAll(100, func() {
+		mu.Lock()
+		i := next
+		next++
+		mu.Unlock()
+		counts["key-"+strconv.Itoa(i%10)]++
+	})
+	t.Logf("%d keys", len(counts))
+}
+
+func TestSafeIncrementMutex(t *testing.T) {
+	counter := SafeCounter{v: map[string]int{}}
+	incrementAll(100, func() { counter.SafeInc(COUNTER_KEY) })
+	if got := counter.GetValue(COUNTER_KEY); got != 100 {
+		t.Errorf("GetValue = %d, want 100", got)
+	}
+}
+
+func TestAtomicIncrement(t *testing.T) {
+	var val int64
+	var typed atomic.Int64
+	incrementAll(100, func() {
+		atomic.AddInt64(&val, 1)
+		typed.Add(1)
+	})
+	if got := atomic.LoadInt64(&val); got != 100 {
+		t.Errorf("atomic.AddInt64: %d, want 100", got)
+	}
+	if got := typed.Load(); got != 100 {
+		t.Errorf("atomic.Int64: %d, want 100", got)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// === Rate limiting ===
+
+// Keep calls to something (an API, a database) under a rate, ex. at most 5 per second.
+// Three ways, from simplest to most flexible:
+//
+// 1. a ticker: wait for a tick before each call. Calls are evenly spaced, never faster than one per tick,
+//    and there is no burst - even after a quiet minute, the next 5 calls still wait a tick each
+// 2. a token bucket on a buffered channel: the channel holds up to `burst` tokens, a ticker adds one per interval,
+//    and a call takes one. Quiet periods fill the bucket, so a burst can go straight through,
+//    but over time the rate is still one per interval
+// 3. a token bucket computed from timestamps, which is how golang.org/x/time/rate works (see the end of this file)
+//
+// A time.Ticker's channel has a buffer of 1 and drops ticks nobody receives -
+// so a ticker limiter "remembers" at most one tick, which is why it can't burst.
+// (time.Tick is fine for a limiter that lives as long as the program: since Go 1.23 an unreferenced ticker is
+// garbage collected even if it isn't stopped)
+
+var ErrRateLimited = errors.New("rate limited")
+
+// --- 1. Ticker limiter ---
+
+type TickLimiter struct {
+	ticks <-chan time.Time
+	stop  func()
+}
+
+// NewTickLimiter allows one call per interval. Stop it when done, to release the ticker
+func NewTickLimiter(interval time.Duration) *TickLimiter {
+	if interval <= 0 {
+		panic("NewTickLimiter: interval must be positive")
+	}
+	t := time.NewTicker(interval)
+	return &TickLimiter{ticks: t.C, stop: t.Stop}
+}
+
+// Wait blocks until the next tick (or until ctx is done)
+func (l *TickLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.ticks:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Allow takes a tick if one is waiting, without blocking
+func (l *TickLimiter) Allow() bool {
+	select {
+	case <-l.ticks:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *TickLimiter) Stop() { l.stop() }
+
+// --- 2. Token bucket on a buffered channel ---
+
+type TokenBucket struct {
+	tokens chan struct{}
+	stop   func()
+}
+
+// NewTokenBucket allows bursts of up to burst calls, refilled at one token per interval.
+// The bucket starts full. A burst of 0 would be a bucket that never holds a token - only a Wait already
+// blocked when the ticker fires would get one - so it's refused like a non-positive interval
+func NewTokenBucket(interval time.Duration, burst int) *TokenBucket {
+	if interval <= 0 || burst < 1 {
+		panic("NewTokenBucket: interval must be positive and burst at least 1")
+	}
+	b := newTokenBucket(burst)
+	t := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				b.Refill()
+			case <-done:
+				return
+			}
+		}
+	}()
+	b.stop = func() {
+		t.Stop()
+		close(done)
+	}
+	return b
+}
+
+// newTokenBucket has no refill goroutine - whoever creates it calls Refill (ex. a fake clock)
+func newTokenBucket(burst int) *TokenBucket {
+	if burst < 1 {
+		panic("newTokenBucket: burst must be at least 1")
+	}
+	b := &TokenBucket{tokens: make(chan struct{}, burst), stop: func() {}}
+	for range burst {
+		b.tokens <- struct{}{}
+	}
+	return b
+}
+
+// Refill adds one token, unless the bucket is already full (the send would block, so default drops it)
+func (b *TokenBucket) Refill() {
+	select {
+	case b.tokens <- struct{}{}:
+	default:
+	}
+}
+
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	select {
+	case <-b.tokens:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+func (b *TokenBucket) Allow() bool {
+	select {
+	case <-b.tokens:
+		return true
+	default:
+		return false
+	}
+}
+
+// Available is the number of tokens in the bucket right now (len of a buffered channel = queued values)
+func (b *TokenBucket) Available() int { return len(b.tokens) }
+
+func (b *TokenBucket) Stop() { b.stop() }
+
+// --- A fake ticker ---
+
+// fakeTicker calls onTick once for every interval that Advance moves past, synchronously,
+// so an example knows exactly how many ticks have happened when Advance returns
+type fakeTicker struct {
+	clock    *fakeClock // see ratetracker.go
+	interval time.Duration
+	next     time.Time
+	onTick   func()
+}
+
+func newFakeTicker(clock *fakeClock, interval time.Duration, onTick func()) *fakeTicker {
+	return &fakeTicker{clock: clock, interval: interval, next: clock.Now().Add(interval), onTick: onTick}
+}
+
+func (t *fakeTicker) Advance(d time.Duration) {
+	t.clock.Advance(d)
+	for !t.next.After(t.clock.Now()) {
+		t.onTick()
+		t.next = t.next.Add(t.interval)
+	}
+}
+
+// A ticker limiter driven by a fake ticker. The channel has a buffer of 1, and a tick is dropped
+// when the buffer is full - the same as a real time.Ticker
+func newFakeTickLimiter(clock *fakeClock, interval time.Duration) (*TickLimiter, *fakeTicker) {
+	ticks := make(chan time.Time, 1)
+	ticker := newFakeTicker(clock, interval, func() {
+		select {
+		case ticks <- clock.Now():
+		default:
+		}
+	})
+	return &TickLimiter{ticks: ticks, stop: func() {}}, ticker
+}
+
+// --- A fake API with its own limit ---
+
+// fakeAPI rejects a call when more than limit calls arrived during the last window
+// (a server enforcing "at most 5 requests per second")
+type fakeAPI struct {
+	now    func() time.Time
+	limit  int
+	window time.Duration
+	calls  []time.Time
+}
+
+func (a *fakeAPI) Call() error {
+	now := a.now()
+	recent := a.calls[:0]
+	for _, t := range a.calls {
+		if now.Sub(t) < a.window {
+			recent = append(recent, t)
+		}
+	}
+	a.calls = recent
+	if len(a.calls) >= a.limit {
+		return fmt.Errorf("429 too many requests: %w", ErrRateLimited)
+	}
+	a.calls = append(a.calls, now)
+	return nil
+}
+
+// throttle sends n requests to api as fast as allow lets it, checking every step of fake time.
+// It returns how many were rejected by the API and how much fake time it took
+func throttle(n int, api *fakeAPI, clock *fakeClock, allow func() bool, advance func(time.Duration), step time.Duration) (rejected int, took time.Duration) {
+	start := clock.Now()
+	for sent := 0; sent < n; {
+		for sent < n && allow() {
+			if err := api.Call(); err != nil {
+				rejected++
+			}
+			sent++
+		}
+		if sent < n {
+			advance(step)
+		}
+	}
+	return rejected, clock.Now().Sub(start)
+}
+
+func rateLimitExamples() {
+	const (
+		requests = 20
+		interval = 200 * time.Millisecond // 5 per second
+		burst    = 5
+		step     = 10 * time.Millisecond
+	)
+	newAPI := func(clock *fakeClock) *fakeAPI {
+		return &fakeAPI{now: clock.Now, limit: 5, window: time.Second}
+	}
+	report := func(name string, rejected int, took time.Duration, wantRejected int, wantTook time.Duration) {
+		fmt.Printf("%-14s %2d rejected, took %-6v | as expected: %t\n", name+":", rejected, took, rejected == wantRejected && took == wantTook)
+	}
+
+	// No limiter - everything is sent at once, and the API turns away all but its first 5
+	{
+		clock := &fakeClock{}
+		api := newAPI(clock)
+		rejected, took := throttle(requests, api, clock, func() bool { return true }, clock.Advance, step)
+		report("no limiter", rejected, took, 15, 0)
+	}
+
+	// Ticker - one request per 200ms: nothing rejected, but the first request already waits a tick,
+	// and the 20th goes out at 20 * 200ms = 4s
+	{
+		clock := &fakeClock{}
+		api := newAPI(clock)
+		lim, ticker := newFakeTickLimiter(clock, interval)
+		rejected, took := throttle(requests, api, clock, lim.Allow, ticker.Advance, step)
+		report("ticker", rejected, took, 0, 4*time.Second)
+	}
+
+	// Token bucket - 5 go out immediately (the bucket starts full), then one per 200ms:
+	// the last of the remaining 15 at 15 * 200ms = 3s.
+	// But 4 are rejected - the burst comes ON TOP of the rate, so the first second sends 5 + 4 requests,
+	// more than the API's 5 per second. A bucket only stays under a limit if burst + rate * window fits in it
+	{
+		clock := &fakeClock{}
+		api := newAPI(clock)
+		bucket := newTokenBucket(burst)
+		ticker := newFakeTicker(clock, interval, bucket.Refill)
+		rejected, took := throttle(requests, api, clock, bucket.Allow, ticker.Advance, step)
+		report("token bucket", rejected, took, 4, 3*time.Second)
+
+		// After a quiet period, the bucket is full again - but never holds more than burst tokens
+		ticker.Advance(10 * time.Second)
+		fmt.Println("  tokens after 10s idle:", bucket.Available(), "(capped at burst:", bucket.Available() == burst, ")")
+	}
+
+	// With a burst of 1 it's the ticker limiter, except the first request doesn't wait: 19 * 200ms = 3.8s
+	{
+		clock := &fakeClock{}
+		api := newAPI(clock)
+		bucket := newTokenBucket(1)
+		ticker := newFakeTicker(clock, interval, bucket.Refill)
+		rejected, took := throttle(requests, api, clock, bucket.Allow, ticker.Advance, step)
+		report("bucket of 1", rejected, took, 0, 3800*time.Millisecond)
+	}
+
+	// The timestamp based bucket gives the same results as the channel one
+	{
+		clock := &fakeClock{}
+		api := newAPI(clock)
+		lim := newLazyBucket(interval, burst, clock.Now)
+		rejected, took := throttle(requests, api, clock, lim.Allow, clock.Advance, step)
+		report("lazy bucket", rejected, took, 4, 3*time.Second)
+	}
+
+	// The ticker limiter can't burst: after an idle second, only one tick is waiting, not five
+	{
+		clock := &fakeClock{}
+		lim, ticker := newFakeTickLimiter(clock, interval)
+		ticker.Advance(time.Second)
+		allowed := 0
+		for lim.Allow() {
+			allowed++
+		}
+		fmt.Println("ticker limiter after 1s idle allows:", allowed)
+	}
+
+	// --- With real time ---
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bucket := NewTokenBucket(20*time.Millisecond, 3)
+	defer bucket.Stop()
+	start := time.Now()
+	var mu sync.Mutex
+	var at []time.Duration
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			if bucket.Wait(ctx) == nil {
+				mu.Lock()
+				at = append(at, time.Since(start).Round(10*time.Millisecond))
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	fmt.Println("real token bucket, 6 calls with burst 3, one token per 20ms:", at)
+}
+
+// --- 3. A token bucket without a goroutine (the golang.org/x/time/rate approach) ---
+
+// Instead of a ticker adding tokens, the bucket stores the time it was last updated,
+// and works out how many tokens have arrived since then whenever it is used:
+//
+//	tokens = min(burst, tokens + elapsed / interval)
+//
+// No goroutine and no ticker to stop, and with float tokens the rate doesn't have to be a whole interval.
+// golang.org/x/time/rate does the same with more on top:
+//
+//	lim := rate.NewLimiter(rate.Every(200*time.Millisecond), 5) // ~ NewTokenBucket(200ms, 5)
+//	lim.Allow()              // ~ Allow
+//	lim.Wait(ctx)            // ~ Wait, but sleeps for exactly the time until the next token
+//	lim.AllowN(now, 3)       // take several tokens at once
+//	r := lim.Reserve()       // take a token now, and r.Delay() says how long to wait before using it
+//	lim.SetLimit(rate.Inf)   // change the rate while in use
+//
+// It is a separate module (golang.org/x/time), not the standard library, so the modules here stick to the
+// two versions above and this small one, to stay dependency free
+
+type lazyBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func newLazyBucket(interval time.Duration, burst int, now func() time.Time) *lazyBucket {
+	return &lazyBucket{interval: interval, burst: float64(burst), tokens: float64(burst), last: now(), now: now}
+}
+
+func (b *lazyBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.interval))
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
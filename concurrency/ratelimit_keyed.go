@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// --- Per-client rate limiting (a bucket per key) ---
+
+// One limiter for the whole server lets one busy client use up everyone's allowance.
+// KeyedLimiter keeps a separate token bucket per key (a user ID, an API key, an IP address).
+//
+// It uses the timestamp bucket (lazyBucket) rather than the channel one: a bucket per key means
+// thousands of buckets, and a refill goroutine + ticker for each would cost far more than two numbers.
+//
+// Buckets for clients that have gone away would pile up forever, so idle ones are evicted.
+// That loses nothing: a bucket idle for burst * interval has refilled completely,
+// which is exactly the state a new bucket starts in.
+
+type KeyedLimiter[K comparable] struct {
+	interval time.Duration
+	burst    int
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[K]*keyedBucket
+	lastSweep time.Time
+}
+
+type keyedBucket struct {
+	*lazyBucket
+	// when Allow last looked the bucket up, set under the limiter's lock. Eviction goes by this rather than
+	// the bucket's own last, which is only set once Allow gets to the bucket - after the limiter's lock is released,
+	// so a sweep in between could drop a bucket that is in use, and the key would get a second, full one
+	lastUsed time.Time
+}
+
+// NewKeyedLimiter panics unless interval > 0 and burst >= 1
+func NewKeyedLimiter[K comparable](interval time.Duration, burst int) *KeyedLimiter[K] {
+	return newKeyedLimiterWithClock[K](interval, burst, time.Now)
+}
+
+func newKeyedLimiterWithClock[K comparable](interval time.Duration, burst int, now func() time.Time) *KeyedLimiter[K] {
+	if interval <= 0 || burst < 1 {
+		panic("NewKeyedLimiter: interval must be positive and burst at least 1")
+	}
+	return &KeyedLimiter[K]{
+		interval:  interval,
+		burst:     burst,
+		now:       now,
+		buckets:   make(map[K]*keyedBucket),
+		lastSweep: now(),
+	}
+}
+
+// idleAfter is how long until an unused bucket is full again
+func (l *KeyedLimiter[K]) idleAfter() time.Duration {
+	return time.Duration(l.burst) * l.interval
+}
+
+// Allow reports whether key may make a call now
+func (l *KeyedLimiter[K]) Allow(key K) bool {
+	l.mu.Lock()
+	now := l.now()
+	// Sweep at most once per idle period - often enough to bound memory,
+	// without scanning the whole map on every call
+	if now.Sub(l.lastSweep) >= l.idleAfter() {
+		l.evictIdle(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &keyedBucket{lazyBucket: newLazyBucket(l.interval, l.burst, l.now)}
+		l.buckets[key] = b
+	}
+	b.lastUsed = now
+	l.mu.Unlock()
+
+	// the bucket has its own lock - calls for different keys don't wait for each other here
+	return b.Allow()
+}
+
+func (l *KeyedLimiter[K]) evictIdle(now time.Time) {
+	for key, b := range l.buckets { // deleting during range is allowed
+		if now.Sub(b.lastUsed) >= l.idleAfter() {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Len is the number of buckets currently kept
+func (l *KeyedLimiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+func keyedRateLimitExample() {
+	const burst = 3
+	interval := 500 * time.Millisecond
+
+	// --- Concurrent callers across many keys ---
+
+	// The clock doesn't move, so every key gets exactly its burst - no matter how the calls interleave
+	clock := &fakeClock{}
+	lim := newKeyedLimiterWithClock[int](interval, burst, clock.Now)
+	const keys, callsPerKey = 1000, 10
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for g := range 50 {
+		wg.Go(func() {
+			for i := range keys * callsPerKey / 50 {
+				key := (g*7919 + i) % keys // every goroutine hits the keys in a different order
+				if lim.Allow(key) {
+					allowed.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	// ratelimit_keyed_test.go checks every key got exactly its burst
+	fmt.Printf("%d keys x %d calls: %d allowed (%d per key)\n", keys, callsPerKey, allowed.Load(), burst)
+
+	// One key refills without affecting the others
+	clock.Advance(interval)
+	fmt.Println("after one interval - key 0:", lim.Allow(0), lim.Allow(0), "| key 1:", lim.Allow(1))
+
+	// --- Eviction ---
+
+	fmt.Println("buckets kept:", lim.Len())
+	clock.Advance(time.Duration(burst) * interval) // everyone is idle long enough to be full again
+	lim.Allow(42)                                  // the next call sweeps
+	fmt.Println("after all idle, buckets kept:", lim.Len(), "| key 42 starts full again:", lim.Allow(42) && lim.Allow(42))
+	// The HTTP middleware limiting each user with one of these is httpserver.RateLimit, in the httpserver
+	// module. Not here: linking net/http into this package stops the runtime reporting the deadlock examples
+}
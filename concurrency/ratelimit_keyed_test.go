@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// With the clock stopped, every key gets exactly its burst - however the calls from many goroutines interleave
+func TestKeyedLimiterConcurrentKeys(t *testing.T) {
+	const burst, keys, callsPerKey, goroutines = 3, 500, 10, 25
+	lim := newKeyedLimiterWithClock[int](time.Second, burst, (&fakeClock{}).Now)
+	perKey := make([]atomic.Int32, keys)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			for i := range keys * callsPerKey / goroutines {
+				key := (g*7919 + i) % keys
+				if lim.Allow(key) {
+					perKey[key].Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	for key := range perKey {
+		if got := perKey[key].Load(); got != burst {
+			t.Errorf("key %d: %d calls allowed, want %d", key, got, burst)
+		}
+	}
+	if lim.Len() != keys {
+		t.Errorf("Len = %d, want %d", lim.Len(), keys)
+	}
+}
+
+func TestKeyedLimiterRefillPerKey(t *testing.T) {
+	clock := &fakeClock{}
+	lim := newKeyedLimiterWithClock[string](time.Second, 1, clock.Now)
+	if !lim.Allow("a") || lim.Allow("a") {
+		t.Fatal("burst 1: want one call allowed, then refused")
+	}
+	if !lim.Allow("b") {
+		t.Error("b refused though only a used its token")
+	}
+	clock.Advance(time.Second)
+	if !lim.Allow("a") || lim.Allow("a") {
+		t.Error("a after one interval: want one more call allowed")
+	}
+}
+
+func TestKeyedLimiterEvictsIdle(t *testing.T) {
+	const burst = 2
+	interval := time.Second
+	idle := burst * interval
+	clock := &fakeClock{}
+	lim := newKeyedLimiterWithClock[int](interval, burst, clock.Now)
+	for key := range 10 {
+		lim.Allow(key)
+	}
+	clock.Advance(idle / 2)
+	lim.Allow(0) // key 0 stays in use
+	clock.Advance(idle / 2)
+	lim.Allow(42) // the first call a whole idle period after the start sweeps
+	if got := lim.Len(); got != 2 {
+		t.Errorf("after the sweep %d buckets kept, want 2 (key 0 and key 42)", got)
+	}
+	// an evicted key starts again with a full bucket - the same state it would have reached by waiting
+	if !lim.Allow(5) || !lim.Allow(5) || lim.Allow(5) {
+		t.Error("evicted key 5 doesn't start with exactly its burst")
+	}
+}
+
+func TestNewKeyedLimiterRejects(t *testing.T) {
+	wantPanic(t, "zero interval", func() { NewKeyedLimiter[int](0, 1) })
+	wantPanic(t, "negative interval", func() { NewKeyedLimiter[int](-time.Second, 1) })
+	wantPanic(t, "zero burst", func() { NewKeyedLimiter[int](time.Second, 0) })
+}
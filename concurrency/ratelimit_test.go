@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// 20 requests to an API allowing 5 a second, through each limiter, in fake time
+func TestLimitersAgainstAPI(t *testing.T) {
+	const (
+		requests = 20
+		interval = 200 * time.Millisecond
+		step     = 10 * time.Millisecond
+	)
+	for _, tc := range []struct {
+		name         string
+		limiter      func(clock *fakeClock) (allow func() bool, advance func(time.Duration))
+		wantRejected int
+		wantTook     time.Duration
+	}{
+		{"no limiter", func(clock *fakeClock) (func() bool, func(time.Duration)) {
+			return func() bool { return true }, clock.Advance
+		}, 15, 0},
+		// the first request already waits a tick, the 20th goes out at 20 * 200ms
+		{"ticker", func(clock *fakeClock) (func() bool, func(time.Duration)) {
+			lim, ticker := newFakeTickLimiter(clock, interval)
+			return lim.Allow, ticker.Advance
+		}, 0, 4 * time.Second},
+		// 5 at once, then one per 200ms - the burst on top of the rate is 9 in the first second
+		{"token bucket", func(clock *fakeClock) (func() bool, func(time.Duration)) {
+			bucket := newTokenBucket(5)
+			return bucket.Allow, newFakeTicker(clock, interval, bucket.Refill).Advance
+		}, 4, 3 * time.Second},
+		{"bucket of 1", func(clock *fakeClock) (func() bool, func(time.Duration)) {
+			bucket := newTokenBucket(1)
+			return bucket.Allow, newFakeTicker(clock, interval, bucket.Refill).Advance
+		}, 0, 3800 * time.Millisecond},
+		{"lazy bucket", func(clock *fakeClock) (func() bool, func(time.Duration)) {
+			return newLazyBucket(interval, 5, clock.Now).Allow, clock.Advance
+		}, 4, 3 * time.Second},
+	} {
+		clock := &fakeClock{}
+		api := &fakeAPI{now: clock.Now, limit: 5, window: time.Second}
+		allow, advance := tc.limiter(clock)
+		rejected, took := throttle(requests, api, clock, allow, advance, step)
+		if rejected != tc.wantRejected || took != tc.wantTook {
+			t.Errorf("%s: %d rejected in %v, want %d in %v", tc.name, rejected, took, tc.wantRejected, tc.wantTook)
+		}
+	}
+}
+
+// countAllowed takes every token there is
+func countAllowed(allow func() bool) int {
+	n := 0
+	for allow() {
+		n++
+	}
+	return n
+}
+
+// A ticker remembers one tick at most, so idle time doesn't add up to a burst
+func TestTickLimiterNoBurst(t *testing.T) {
+	clock := &fakeClock{}
+	lim, ticker := newFakeTickLimiter(clock, 200*time.Millisecond)
+	if lim.Allow() {
+		t.Error("allowed before the first tick")
+	}
+	ticker.Advance(time.Second)
+	if n := countAllowed(lim.Allow); n != 1 {
+		t.Errorf("after 5 intervals idle, %d allowed, want 1", n)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	clock := &fakeClock{}
+	bucket := newTokenBucket(3)
+	ticker := newFakeTicker(clock, 100*time.Millisecond, bucket.Refill)
+	if n := countAllowed(bucket.Allow); n != 3 {
+		t.Errorf("a new bucket allowed %d, want its burst of 3", n)
+	}
+	ticker.Advance(99 * time.Millisecond)
+	if bucket.Allow() {
+		t.Error("a token before the interval has passed")
+	}
+	ticker.Advance(time.Millisecond)
+	if bucket.Available() != 1 || !bucket.Allow() {
+		t.Errorf("one interval: %d tokens, want 1", bucket.Available())
+	}
+	ticker.Advance(10 * time.Second)
+	if bucket.Available() != 3 {
+		t.Errorf("after 100 intervals idle: %d tokens, want the burst of 3", bucket.Available())
+	}
+}
+
+func TestLimiterWaitCancelled(t *testing.T) {
+	cause := errors.New("shutting down")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	lim, _ := newFakeTickLimiter(&fakeClock{}, time.Second)
+	if err := lim.Wait(ctx); err != cause {
+		t.Errorf("TickLimiter.Wait = %v, want the context's cause", err)
+	}
+	bucket := newTokenBucket(1)
+	bucket.Allow()
+	if err := bucket.Wait(ctx); err != cause {
+		t.Errorf("TokenBucket.Wait = %v, want the context's cause", err)
+	}
+}
+
+// The real constructors, in a synctest bubble: the ticker's time is the bubble's fake clock,
+// and the test fails if Stop leaves the refill goroutine running
+func TestTokenBucketRealTicker(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		bucket := NewTokenBucket(20*time.Millisecond, 3)
+		defer bucket.Stop()
+		start := time.Now()
+		var mu sync.Mutex
+		var at []time.Duration
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Go(func() {
+				if err := bucket.Wait(context.Background()); err != nil {
+					t.Error(err)
+				}
+				mu.Lock()
+				at = append(at, time.Since(start))
+				mu.Unlock()
+			})
+		}
+		wg.Wait()
+		slices.Sort(at)
+		want := []time.Duration{0, 0, 0, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond}
+		if !slices.Equal(at, want) {
+			t.Errorf("6 calls with a burst of 3 went at %v, want %v", at, want)
+		}
+	})
+}
+
+func TestTickLimiterRealTicker(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		lim := NewTickLimiter(50 * time.Millisecond)
+		defer lim.Stop()
+		start := time.Now()
+		for i := 1; i <= 3; i++ {
+			lim.Wait(context.Background())
+			if d := time.Since(start); d != time.Duration(i)*50*time.Millisecond {
+				t.Errorf("call %d at %v, want %v", i, d, time.Duration(i)*50*time.Millisecond)
+			}
+		}
+	})
+}
+
+func TestRateLimitersReject(t *testing.T) {
+	wantPanic(t, "NewTickLimiter zero interval", func() { NewTickLimiter(0) })
+	wantPanic(t, "NewTickLimiter negative interval", func() { NewTickLimiter(-time.Second) })
+	wantPanic(t, "NewTokenBucket zero interval", func() { NewTokenBucket(0, 1) })
+	wantPanic(t, "NewTokenBucket negative interval", func() { NewTokenBucket(-time.Second, 1) })
+	wantPanic(t, "NewTokenBucket zero burst", func() { NewTokenBucket(time.Second, 0) })
+	wantPanic(t, "NewTokenBucket negative burst", func() { NewTokenBucket(time.Second, -1) })
+	wantPanic(t, "newTokenBucket zero burst", func() { newTokenBucket(0) })
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// === Sliding window rate statistics ===
+
+// RateTracker answers "how many events per second recently, and what was their average value?"
+// (ex. requests per second and average latency over the last 10 seconds)
+//
+// Storing every event would grow without limit, so time is split into a ring of fixed width buckets.
+// Each bucket keeps only a count and a sum. As time moves forward, buckets older than the window
+// are reset and reused, so memory stays constant no matter how many events happen.
+//
+//	window = 1s, 4 buckets of 250ms
+//	[ bucket 0 | bucket 1 | bucket 2 | bucket 3 ]   <- ring, index = (time / 250ms) % 4
+
+// Number is the set of value types the tracker can average
+type Number interface {
+	~int | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+type rateBucket[V Number] struct {
+	slot  int64 // which time slot (time / bucket width) the counts belong to
+	count int64
+	sum   V
+}
+
+type RateTracker[V Number] struct {
+	mu      sync.Mutex
+	buckets []rateBucket[V]
+	width   time.Duration
+
+	// now is time.Now, unless replaced by a fake clock so bucket rotation can be stepped through exactly
+	now func() time.Time
+}
+
+// NewRateTracker tracks the last window in numBuckets buckets. numBuckets must be at least 1, and the window
+// at least numBuckets nanoseconds - a bucket is window/numBuckets wide, and a width of 0 would divide by zero
+func NewRateTracker[V Number](window time.Duration, numBuckets int) *RateTracker[V] {
+	return newRateTrackerWithClock[V](window, numBuckets, time.Now)
+}
+
+func newRateTrackerWithClock[V Number](window time.Duration, numBuckets int, now func() time.Time) *RateTracker[V] {
+	if numBuckets < 1 {
+		panic("NewRateTracker: numBuckets must be at least 1")
+	}
+	if window < time.Duration(numBuckets) {
+		panic("NewRateTracker: window must be at least numBuckets nanoseconds")
+	}
+	return &RateTracker[V]{
+		buckets: make([]rateBucket[V], numBuckets),
+		width:   window / time.Duration(numBuckets),
+		now:     now,
+	}
+}
+
+// currentSlot rounds down, before 1970 too: / truncates toward zero, which would make the slot
+// just before 1970 and the one just after both 0
+func (r *RateTracker[V]) currentSlot() int64 {
+	ns, w := r.now().UnixNano(), int64(r.width)
+	slot := ns / w
+	if ns%w < 0 {
+		slot--
+	}
+	return slot
+}
+
+// Add records one event with a value (ex. a latency in milliseconds)
+func (r *RateTracker[V]) Add(v V) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.currentSlot()
+	n := int64(len(r.buckets))
+	b := &r.buckets[(slot%n+n)%n] // a negative slot has a negative %, which isn't an index
+	if b.slot != slot {
+		// the bucket still holds counts from a full window ago - rotate it
+		*b = rateBucket[V]{slot: slot}
+	}
+	b.count++
+	b.sum += v
+}
+
+// totals adds up the buckets still inside the window. Must be called with mu held
+func (r *RateTracker[V]) totals() (count int64, sum V) {
+	slot := r.currentSlot()
+	oldest := slot - int64(len(r.buckets)) + 1
+	for _, b := range r.buckets {
+		if b.slot >= oldest && b.slot <= slot {
+			count += b.count
+			sum += b.sum
+		}
+	}
+	return count, sum
+}
+
+// Rate is events per second over the window
+func (r *RateTracker[V]) Rate() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count, _ := r.totals()
+	window := r.width * time.Duration(len(r.buckets))
+	return float64(count) / window.Seconds()
+}
+
+// Average is the moving average of the values added within the window (0 if there were none)
+func (r *RateTracker[V]) Average() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count, sum := r.totals()
+	if count == 0 {
+		return 0
+	}
+	return float64(sum) / float64(count)
+}
+
+// fakeClock is a settable clock for the examples - time only moves when Advance is called
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// Ex. stepping a fake clock through bucket rotation
+func rateTrackerFakeClockExample() {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	tracker := newRateTrackerWithClock[int](time.Second, 4, clock.Now) // 4 buckets of 250ms
+
+	report := func(label string) {
+		fmt.Printf("%-28s rate: %4.1f/s  avg: %5.1f\n", label, tracker.Rate(), tracker.Average())
+	}
+
+	for range 4 {
+		tracker.Add(10)
+	}
+	report("t=0ms, 4 events of 10")
+
+	clock.Advance(500 * time.Millisecond)
+	tracker.Add(30)
+	tracker.Add(30)
+	report("t=500ms, 2 events of 30")
+
+	// The first bucket (t=0) is now a full window old and drops out
+	clock.Advance(750 * time.Millisecond)
+	report("t=1250ms, first bucket gone")
+
+	clock.Advance(time.Second)
+	report("t=2250ms, all buckets gone")
+}
+
+// --- Ex. request metrics ---
+
+// Several goroutines record fake request latencies while the main goroutine reads the stats
+func requestMetricsExample() {
+	latencyMs := NewRateTracker[float64](time.Second, 10)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 50 {
+				latencyMs.Add(5 + rand.Float64()*10) // 5-15ms
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+
+	for range 3 {
+		time.Sleep(150 * time.Millisecond)
+		fmt.Printf("requests/s: %6.1f  avg latency: %4.1fms\n", latencyMs.Rate(), latencyMs.Average())
+	}
+	wg.Wait()
+}
+
+// --- Ex. least-loaded balancing ---
+
+// Pick the backend with the lowest recent request rate, so traffic evens out
+// even when some requests are sent to a backend directly (here: all early traffic went to "a")
+func leastLoadedExample() {
+	backends := map[string]*RateTracker[int]{
+		"a": NewRateTracker[int](time.Second, 10),
+		"b": NewRateTracker[int](time.Second, 10),
+		"c": NewRateTracker[int](time.Second, 10),
+	}
+	for range 30 {
+		backends["a"].Add(1)
+	}
+
+	pick := func() string {
+		best := ""
+		for _, name := range []string{"a", "b", "c"} {
+			if best == "" || backends[name].Rate() < backends[best].Rate() {
+				best = name
+			}
+		}
+		return best
+	}
+
+	sent := map[string]int{}
+	for range 60 {
+		name := pick()
+		backends[name].Add(1)
+		sent[name]++
+	}
+	fmt.Println("requests sent per backend:", sent)
+	for _, name := range []string{"a", "b", "c"} {
+		fmt.Printf("  %s: %.0f/s\n", name, backends[name].Rate())
+	}
+}
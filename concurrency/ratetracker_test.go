@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// wantPanic fails t unless f panics
+func wantPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s didn't panic", name)
+		}
+	}()
+	f()
+}
+
+func TestRateTrackerRotation(t *testing.T) {
+	for _, start := range []time.Time{time.Unix(0, 0), time.Unix(1_700_000_000, 0), time.Unix(-3, 0)} {
+		t.Run(start.UTC().Format(time.RFC3339), func(t *testing.T) {
+			clock := &fakeClock{t: start}
+			tracker := newRateTrackerWithClock[int](time.Second, 4, clock.Now) // 4 buckets of 250ms
+			check := func(label string, rate, avg float64) {
+				t.Helper()
+				if got := tracker.Rate(); math.Abs(got-rate) > 1e-9 {
+					t.Errorf("%s: Rate = %v, want %v", label, got, rate)
+				}
+				if got := tracker.Average(); math.Abs(got-avg) > 1e-9 {
+					t.Errorf("%s: Average = %v, want %v", label, got, avg)
+				}
+			}
+
+			for range 4 {
+				tracker.Add(10)
+			}
+			check("4 events of 10", 4, 10)
+			clock.Advance(500 * time.Millisecond)
+			tracker.Add(30)
+			tracker.Add(30)
+			check("then 2 of 30", 6, 100.0/6)
+			clock.Advance(750 * time.Millisecond) // the first bucket is a full window old
+			check("first bucket gone", 2, 30)
+			clock.Advance(time.Second)
+			check("all gone", 0, 0)
+		})
+	}
+}
+
+// Just before and just after 1970 are different slots - / alone would round both to 0
+func TestRateTrackerAcrossZero(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0).Add(-time.Nanosecond)}
+	tracker := newRateTrackerWithClock[int](4*time.Nanosecond, 4, clock.Now) // buckets 1ns wide
+	tracker.Add(1)
+	clock.Advance(time.Nanosecond)
+	tracker.Add(1)
+	if c, _ := tracker.totals(); c != 2 {
+		t.Errorf("2 events in adjacent buckets - counted %d", c)
+	}
+	clock.Advance(3 * time.Nanosecond) // the bucket before 0 is out of the window
+	if c, _ := tracker.totals(); c != 1 {
+		t.Errorf("a window later - counted %d, want 1", c)
+	}
+}
+
+func TestRateTrackerConcurrent(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(100, 0)}
+	tracker := newRateTrackerWithClock[float64](time.Second, 10, clock.Now)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 100 {
+				tracker.Add(2)
+				tracker.Rate()
+			}
+		})
+	}
+	wg.Wait()
+	if r, avg := tracker.Rate(), tracker.Average(); r != 800 || avg != 2 {
+		t.Errorf("800 events of 2 in one second: Rate %v, Average %v", r, avg)
+	}
+}
+
+func TestNewRateTrackerRejects(t *testing.T) {
+	for _, c := range []struct {
+		window  time.Duration
+		buckets int
+	}{{time.Second, 0}, {time.Second, -1}, {3 * time.Nanosecond, 4}, {0, 1}} {
+		wantPanic(t, fmt.Sprintf("NewRateTracker(%v, %d)", c.window, c.buckets), func() { NewRateTracker[int](c.window, c.buckets) })
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"concurrency/internal/recovered"
+	"concurrency/internal/workpool"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// === Recovering panics in goroutines (see internal/recovered) ===
+
+// parseScore is a job with a bug: an empty input indexes past the end of the slice
+func parseScore(ctx context.Context, line string) (int, error) {
+	fields := strings.Fields(line)
+	if fields[0] == "skip" { // panics for "": index out of range [0] with length 0
+		return 0, errors.New("skipped")
+	}
+	return len(fields), nil
+}
+
+func recoveredExample() {
+	// Call: the panic comes back as an error, with the stack from where it happened
+	err := recovered.Call(func() error {
+		_, err := parseScore(context.Background(), "")
+		return err
+	})
+	var pe *recovered.PanicError
+	var rerr runtime.Error
+	fmt.Println("Call:", err)
+	fmt.Println("| ok: a *PanicError:", errors.As(err, &pe), "| wrapping the runtime.Error:", errors.As(err, &rerr),
+		"| stack shows parseScore:", bytes.Contains(pe.Stack, []byte("main.parseScore")))
+	fmt.Println("| ok: no panic, no error:", recovered.Call(func() error { return nil }) == nil)
+
+	// Go: a panicking goroutine is logged, the program carries on
+	var logs bytes.Buffer
+	noTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{} // an empty Attr is dropped
+		}
+		return a
+	}
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{ReplaceAttr: noTime}))
+	done := recovered.Go(logger, "cache-warmer", func() {
+		var cache map[string]int
+		cache["warm"] = 1
+	})
+	<-done
+	line, _, _ := strings.Cut(logs.String(), `,"stack"`)
+	fmt.Println("logged:", line, "...}")
+	fmt.Println("| ok: with a stack:", strings.Contains(logs.String(), `"stack":"goroutine `))
+
+	// The worker pool: a panicking job is its own failed Result, every other job still comes out
+	p := workpool.New(context.Background(), 2, parseScore)
+	lines := []string{"a b c", "", "d e", "", "f"}
+	go func() {
+		for _, l := range lines {
+			p.Submit(l)
+		}
+		p.Wait()
+	}()
+	ok, panicked := 0, 0
+	for r := range p.Results() {
+		if errors.As(r.Err, &pe) {
+			panicked++
+			continue
+		}
+		ok++
+	}
+	fmt.Printf("pool: %d jobs done, %d panicked (%v) | ok: %t\n", ok, panicked, pe, ok == 3 && panicked == 2)
+	// Without the recover in the pool's worker, the first "" would have ended this program - the other
+	// jobs, and everything else running, with it
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// === A worker pool with retries ===
+
+// The worker pool in shutdown.go runs each job once. Jobs that call other services fail now and then
+// for reasons that go away (a timeout, a restart), so a pool usually retries - but carefully:
+// - a limit on attempts: a job that keeps failing is given up on, not retried forever
+// - exponential backoff: wait base, 2*base, 4*base... (up to a max) between attempts, so a struggling
+//   service gets more room each time instead of a steady hammering
+// - jitter: a random part of each wait, so jobs that failed together don't all retry at the same moment
+// - the waits watch ctx: a cancelled pool stops now, not after its longest backoff
+// - a dead-letter channel: jobs that failed for good come out there with their last error,
+//   instead of being logged and lost - someone can look at them, or resubmit them later
+// - some errors aren't worth retrying (bad input won't get better) - permanent() marks those
+
+type retryPolicy struct {
+	maxAttempts int
+	base, max   time.Duration // the first wait, and the cap on any wait
+	jitter      float64       // 0 to 1: how much of each wait may be cut at random (1 is "full jitter")
+}
+
+// backoff is the wait after the given failed attempt (1 for the first). r is in [0, 1)
+func (p retryPolicy) backoff(attempt int, r float64) time.Duration {
+	d := p.base << (attempt - 1)
+	if d > p.max || d <= 0 { // d <= 0: shifted past the top of an int64
+		d = p.max
+	}
+	return d - time.Duration(p.jitter*r*float64(d))
+}
+
+var errPermanent = errors.New("permanent")
+
+// permanent marks err as not worth retrying
+func permanent(err error) error { return fmt.Errorf("%w: %w", errPermanent, err) }
+
+type retryJob struct {
+	id     int
+	run    func(ctx context.Context, attempt int) error
+	policy *retryPolicy // nil: the pool's policy
+}
+
+// jobReport is what happened to a job - on the done channel with err == nil, on the dead-letter channel otherwise
+type jobReport struct {
+	id       int
+	attempts int
+	waits    []time.Duration // the backoff before each retry
+	err      error           // the last error
+}
+
+type retryPool struct {
+	workers int
+	policy  retryPolicy
+	// sleep waits d, or less if ctx is done (then it returns why). The default is a real timer;
+	// a fake clock's Advance makes the backoff checkable without waiting
+	sleep func(ctx context.Context, d time.Duration) error
+	// rand gives the jitter, in [0, 1). Called from every worker, so it must be safe for concurrent use
+	rand func() float64
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// run starts the workers on jobs. Both returned channels are closed once jobs is closed and every job
+// has been reported, so the caller must read both until then. Each job comes out on exactly one of them.
+// It panics if p.workers < 1 - nothing would ever read jobs
+func (p *retryPool) run(ctx context.Context, jobs <-chan retryJob) (done, dead <-chan jobReport) {
+	if p.workers < 1 {
+		panic("retryPool.run: workers must be at least 1")
+	}
+	if p.sleep == nil {
+		p.sleep = sleepCtx
+	}
+	if p.rand == nil {
+		p.rand = rand.Float64
+	}
+	doneCh, deadCh := make(chan jobReport), make(chan jobReport)
+	var wg sync.WaitGroup
+	for range p.workers {
+		wg.Go(func() {
+			for j := range jobs {
+				r := p.attempt(ctx, j)
+				if r.err == nil {
+					doneCh <- r
+				} else {
+					deadCh <- r
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(doneCh)
+		close(deadCh)
+	}()
+	return doneCh, deadCh
+}
+
+// attempt runs j until it succeeds, fails permanently, runs out of attempts, or ctx is done
+func (p *retryPool) attempt(ctx context.Context, j retryJob) jobReport {
+	policy := p.policy
+	if j.policy != nil {
+		policy = *j.policy
+	}
+	r := jobReport{id: j.id}
+	for {
+		if err := context.Cause(ctx); err != nil { // jobs still queued when the pool is cancelled aren't started
+			if r.err == nil {
+				r.err = err
+			}
+			return r
+		}
+		r.attempts++
+		r.err = j.run(ctx, r.attempts)
+		if r.err == nil || errors.Is(r.err, errPermanent) || r.attempts >= policy.maxAttempts {
+			return r
+		}
+		wait := policy.backoff(r.attempts, p.rand())
+		r.waits = append(r.waits, wait)
+		if err := p.sleep(ctx, wait); err != nil {
+			r.err = fmt.Errorf("gave up waiting to retry: %w (last error: %w)", err, r.err)
+			return r
+		}
+	}
+}
+
+// collectReports reads both channels to the end - the nil channel trick from Tee, a closed one is set to nil
+func collectReports(done, dead <-chan jobReport) (ok, failed []jobReport) {
+	for done != nil || dead != nil {
+		select {
+		case r, more := <-done:
+			if !more {
+				done = nil
+				continue
+			}
+			ok = append(ok, r)
+		case r, more := <-dead:
+			if !more {
+				dead = nil
+				continue
+			}
+			failed = append(failed, r)
+		}
+	}
+	sortReports := func(a, b jobReport) int { return a.id - b.id }
+	slices.SortFunc(ok, sortReports)
+	slices.SortFunc(failed, sortReports)
+	return ok, failed
+}
+
+// submitJobs sends the jobs, then closes the channel (the pool ends when it's drained)
+func submitJobs(jobs ...retryJob) <-chan retryJob {
+	ch := make(chan retryJob)
+	go func() {
+		defer close(ch)
+		for _, j := range jobs {
+			ch <- j
+		}
+	}()
+	return ch
+}
+
+var errFlaky = errors.New("service unavailable")
+
+// failTimes is a job that fails its first n attempts, then succeeds
+func failTimes(n int) func(context.Context, int) error {
+	return func(_ context.Context, attempt int) error {
+		if attempt <= n {
+			return errFlaky
+		}
+		return nil
+	}
+}
+
+func retryPoolExample() {
+	policy := retryPolicy{maxAttempts: 5, base: 10 * time.Millisecond, max: 50 * time.Millisecond}
+
+	// --- The backoff schedule ---
+
+	var waits []time.Duration
+	for a := 1; a < 6; a++ {
+		waits = append(waits, policy.backoff(a, 0.99))
+	}
+	fmt.Println("no jitter:", waits)
+
+	jittered := policy
+	jittered.jitter = 0.5
+	fmt.Println("jitter 0.5, attempt 3:", jittered.backoff(3, 0), jittered.backoff(3, 0.5), jittered.backoff(3, 0.999))
+
+	// --- Retries on a fake clock ---
+
+	clock := &fakeClock{}
+	fakeSleep := func(ctx context.Context, d time.Duration) error {
+		clock.Advance(d) // the workers share the clock - their waits add up
+		return context.Cause(ctx)
+	}
+	seeded := rand.New(rand.NewPCG(1, 2))
+	var randMu sync.Mutex
+	pool := &retryPool{workers: 3, policy: jittered, sleep: fakeSleep, rand: func() float64 {
+		randMu.Lock()
+		defer randMu.Unlock()
+		return seeded.Float64()
+	}}
+
+	twice := retryPolicy{maxAttempts: 2, base: time.Second, max: time.Second}
+	start := time.Now()
+	ok, failed := collectReports(pool.run(context.Background(), submitJobs(
+		retryJob{id: 1, run: failTimes(0)},
+		retryJob{id: 2, run: failTimes(2)},
+		retryJob{id: 3, run: failTimes(10)},                // never makes it in 5 attempts
+		retryJob{id: 4, run: failTimes(3), policy: &twice}, // its own policy: would succeed on attempt 4, gets 2
+		retryJob{id: 5, run: func(context.Context, int) error { return permanent(errors.New("bad request")) }},
+	)))
+	for _, r := range slices.Concat(ok, failed) {
+		fmt.Printf("job %d: %d attempts, waits %v, err: %v\n", r.id, r.attempts, r.waits, r.err)
+	}
+	// retrypool_test.go checks each of these with go test
+	fmt.Println("done:", len(ok), "dead letters:", len(failed), "| fake clock advanced:", clock.Now().Sub(time.Time{}).Round(time.Millisecond),
+		"| in real time:", time.Since(start).Round(time.Millisecond))
+
+	// --- Cancelling during a backoff ---
+
+	// A real sleep this time, with a 1 minute backoff - the cancel ends it straight away
+	ctx, cancel := context.WithCancelCause(context.Background())
+	slow := &retryPool{workers: 1, policy: retryPolicy{maxAttempts: 3, base: time.Minute, max: time.Minute}}
+	time.AfterFunc(20*time.Millisecond, func() { cancel(errors.New("shutting down")) })
+	start = time.Now()
+	_, failed = collectReports(slow.run(ctx, submitJobs(
+		retryJob{id: 1, run: failTimes(10)},
+		retryJob{id: 2, run: failTimes(0)}, // queued behind job 1 - never started
+	)))
+	fmt.Printf("cancelled after %v: %d dead letters\n", time.Since(start).Round(10*time.Millisecond), len(failed))
+	for _, r := range failed {
+		fmt.Printf("  job %d, %d attempts: %v\n", r.id, r.attempts, r.err)
+	}
+}
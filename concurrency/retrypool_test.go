@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestBackoff(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 5, base: 10 * ms, max: 50 * ms}
+	var waits []time.Duration
+	for a := 1; a <= 5; a++ {
+		waits = append(waits, policy.backoff(a, 0.99)) // no jitter: r doesn't matter
+	}
+	if want := []time.Duration{10 * ms, 20 * ms, 40 * ms, 50 * ms, 50 * ms}; !slices.Equal(waits, want) {
+		t.Errorf("waits %v, want %v - doubling, capped at max", waits, want)
+	}
+	for _, attempt := range []int{63, 64, 100, 1000} { // the shift runs past the top of an int64
+		if d := policy.backoff(attempt, 0); d != policy.max {
+			t.Errorf("attempt %d: %v, want max", attempt, d)
+		}
+	}
+
+	jittered := policy
+	jittered.jitter = 0.5
+	for a := 1; a <= 5; a++ {
+		full := policy.backoff(a, 0)
+		for _, r := range []float64{0, 0.25, 0.5, 0.999} {
+			if d := jittered.backoff(a, r); d > full || d <= full/2 {
+				t.Errorf("jitter 0.5, attempt %d, r %v: %v, want in (%v, %v]", a, r, d, full/2, full)
+			}
+		}
+	}
+}
+
+// fakeSleepPool is a pool whose backoff sleeps only advance clock, with a fixed jitter
+func fakeSleepPool(workers int, policy retryPolicy, clock *fakeClock) *retryPool {
+	return &retryPool{
+		workers: workers,
+		policy:  policy,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			clock.Advance(d)
+			return context.Cause(ctx)
+		},
+		rand: func() float64 { return 0.5 },
+	}
+}
+
+func TestRetryPool(t *testing.T) {
+	clock := &fakeClock{}
+	policy := retryPolicy{maxAttempts: 5, base: 10 * ms, max: 50 * ms, jitter: 0.5}
+	pool := fakeSleepPool(3, policy, clock)
+	twice := retryPolicy{maxAttempts: 2, base: time.Second, max: time.Second}
+	errBad := errors.New("bad request")
+
+	start := time.Now()
+	ok, failed := collectReports(pool.run(context.Background(), submitJobs(
+		retryJob{id: 1, run: failTimes(0)},
+		retryJob{id: 2, run: failTimes(2)},
+		retryJob{id: 3, run: failTimes(10)},
+		retryJob{id: 4, run: failTimes(3), policy: &twice},
+		retryJob{id: 5, run: func(context.Context, int) error { return permanent(errBad) }},
+	)))
+	if time.Since(start) > time.Second {
+		t.Errorf("took %v with a fake sleep", time.Since(start))
+	}
+
+	okIDs, failedIDs := []int{}, []int{}
+	for _, r := range ok {
+		okIDs = append(okIDs, r.id)
+	}
+	for _, r := range failed {
+		failedIDs = append(failedIDs, r.id)
+	}
+	if !slices.Equal(okIDs, []int{1, 2}) || !slices.Equal(failedIDs, []int{3, 4, 5}) {
+		t.Fatalf("done %v, dead letters %v, want [1 2] and [3 4 5]", okIDs, failedIDs)
+	}
+
+	// with r = 0.5 and jitter 0.5 each wait is 3/4 of the full backoff
+	for _, c := range []struct {
+		r        jobReport
+		attempts int
+		waits    []time.Duration
+		err      error
+	}{
+		{ok[0], 1, nil, nil},
+		{ok[1], 3, []time.Duration{7500 * time.Microsecond, 15 * ms}, nil},
+		{failed[0], 5, []time.Duration{7500 * time.Microsecond, 15 * ms, 30 * ms, 37500 * time.Microsecond}, errFlaky},
+		{failed[1], 2, []time.Duration{time.Second}, errFlaky}, // its own policy, with no jitter
+		{failed[2], 1, nil, errPermanent},                      // not retried
+	} {
+		if c.r.attempts != c.attempts || !slices.Equal(c.r.waits, c.waits) || !errors.Is(c.r.err, c.err) {
+			t.Errorf("job %d: %d attempts, waits %v, err %v; want %d, %v, %v", c.r.id, c.r.attempts, c.r.waits, c.r.err, c.attempts, c.waits, c.err)
+		}
+	}
+	if !errors.Is(failed[2].err, errBad) {
+		t.Errorf("job 5: %v, want the cause kept inside the permanent error", failed[2].err)
+	}
+
+	var total time.Duration
+	for _, r := range slices.Concat(ok, failed) {
+		for _, w := range r.waits {
+			total += w
+		}
+	}
+	if got := clock.Now().Sub(time.Time{}); got != total {
+		t.Errorf("fake clock advanced %v, want the sum of every wait, %v", got, total)
+	}
+}
+
+// Cancelled during a long real backoff: the sleep ends at once, and queued jobs are reported, not started
+func TestRetryPoolCancelDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	errShutdown := errors.New("shutting down")
+	pool := &retryPool{workers: 1, policy: retryPolicy{maxAttempts: 3, base: time.Minute, max: time.Minute}}
+	started := make(chan struct{})
+	var once sync.Once
+	first := func(ctx context.Context, attempt int) error {
+		once.Do(func() { close(started) })
+		return errFlaky
+	}
+	go func() {
+		<-started
+		time.Sleep(10 * ms) // let the worker get into its backoff
+		cancel(errShutdown)
+	}()
+	start := time.Now()
+	ok, failed := collectReports(pool.run(ctx, submitJobs(
+		retryJob{id: 1, run: first},
+		retryJob{id: 2, run: failTimes(0)},
+	)))
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("took %v - the cancel didn't cut the 1 minute backoff short", time.Since(start))
+	}
+	if len(ok) != 0 || len(failed) != 2 {
+		t.Fatalf("%d done, %d dead letters, want 0 and 2", len(ok), len(failed))
+	}
+	if r := failed[0]; r.attempts != 1 || !errors.Is(r.err, errShutdown) || !errors.Is(r.err, errFlaky) {
+		t.Errorf("job 1: %d attempts, %v; want 1, with both the cancel cause and the last error", r.attempts, r.err)
+	}
+	if r := failed[1]; r.attempts != 0 || !errors.Is(r.err, errShutdown) {
+		t.Errorf("job 2: %d attempts, %v; want never started, with the cancel cause", r.attempts, r.err)
+	}
+}
+
+func TestRetryPoolRejectsNoWorkers(t *testing.T) {
+	wantPanic(t, "0 workers", func() { (&retryPool{}).run(context.Background(), nil) })
+}
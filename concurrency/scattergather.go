@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// === Scatter-gather ===
+
+// Send the same request to N backends at once (replicas, or shards that each hold some of the answer),
+// and use the first K good responses - the slow ones don't hold up the answer.
+// The parts:
+// - scatter: a goroutine per backend, all sharing one context with the deadline
+// - gather: a select on the results channel and ctx.Done(), until K successes, or time is up
+// - cancel: once the answer is in, cancel() tells the stragglers to stop (their work is no longer needed)
+// - a WaitGroup closes the results channel after the last backend returns, so the gather loop
+//   also ends when every backend has answered and there still aren't K successes
+
+var errNotEnoughResponses = errors.New("not enough responses")
+
+type fakeBackend struct {
+	name    string
+	latency time.Duration
+	fail    bool
+}
+
+// search "looks up" q, taking b.latency - or less, if ctx is cancelled first
+func (b fakeBackend) search(ctx context.Context, q string) ([]string, error) {
+	select {
+	case <-time.After(b.latency):
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+	if b.fail {
+		return nil, fmt.Errorf("%s: unavailable", b.name)
+	}
+	return []string{q + " tutorial", q + " " + b.name}, nil
+}
+
+type backendResponse struct {
+	backend string
+	hits    []string
+	err     error
+	took    time.Duration
+}
+
+type gathered struct {
+	used      []backendResponse // the successes used for the answer, fastest first
+	failed    []backendResponse
+	cancelled []string // backends stopped by cancel() before they answered
+	hits      []string // merged: the hits of every response used, without duplicates, sorted
+}
+
+func scatterGather(ctx context.Context, backends []fakeBackend, q string, k int, deadline time.Duration) (gathered, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, deadline, fmt.Errorf("no %d responses within %v", k, deadline))
+	defer cancel()
+
+	// buffered for every backend: a straggler finishing after the gather loop is done must not block (and leak)
+	responses := make(chan backendResponse, len(backends))
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, b := range backends {
+		wg.Go(func() {
+			hits, err := b.search(ctx, q)
+			responses <- backendResponse{backend: b.name, hits: hits, err: err, took: time.Since(start)}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(responses)
+	}()
+
+	var g gathered
+	var err error
+gather:
+	for len(g.used) < k {
+		select {
+		case r, ok := <-responses:
+			switch {
+			case !ok:
+				err = fmt.Errorf("%w: %d of %d backends succeeded, wanted %d", errNotEnoughResponses, len(g.used), len(backends), k)
+				break gather
+			case r.err != nil:
+				g.failed = append(g.failed, r)
+			default:
+				g.used = append(g.used, r)
+			}
+		case <-ctx.Done():
+			err = fmt.Errorf("%w: %w", errNotEnoughResponses, context.Cause(ctx))
+			break gather
+		}
+	}
+	cancel() // the stragglers stop now, instead of running to completion for nothing
+
+	// Only for the example's output - waiting for the stragglers isn't needed for the answer
+	for r := range responses {
+		if errors.Is(r.err, context.Canceled) || r.err == context.Cause(ctx) { // cancelled by us, or timed out
+			g.cancelled = append(g.cancelled, r.backend)
+		} else if r.err != nil {
+			g.failed = append(g.failed, r)
+		}
+	}
+
+	for _, r := range g.used {
+		g.hits = append(g.hits, r.hits...)
+	}
+	slices.Sort(g.hits)
+	g.hits = slices.Compact(g.hits)
+	return g, err
+}
+
+func scatterGatherExample() {
+	backends := []fakeBackend{
+		{"eu-1", 10 * time.Millisecond, false},
+		{"eu-2", 15 * time.Millisecond, true},
+		{"us-1", 25 * time.Millisecond, false},
+		{"us-2", 40 * time.Millisecond, false},
+		{"ap-1", 300 * time.Millisecond, false}, // a straggler
+	}
+	names := func(rs []backendResponse) string {
+		var s []string
+		for _, r := range rs {
+			s = append(s, fmt.Sprintf("%s (%v)", r.backend, r.took.Round(5*time.Millisecond)))
+		}
+		return strings.Join(s, ", ")
+	}
+
+	// First 3 successes within 100ms: eu-1, us-1 and us-2 - the failure is skipped, the straggler cancelled
+	start := time.Now()
+	g, err := scatterGather(context.Background(), backends, "golang", 3, 100*time.Millisecond)
+	took := time.Since(start)
+	fmt.Println("k=3 within 100ms - err:", err)
+	fmt.Println("  used:", names(g.used), "| failed:", names(g.failed), "| cancelled:", g.cancelled)
+	fmt.Printf("  merged hits: %q\n", g.hits)
+	fmt.Println("  answered with the 3rd success, not the slowest backend:", took < 100*time.Millisecond,
+		"| straggler cancelled:", slices.Equal(g.cancelled, []string{"ap-1"}), "| duplicates merged:", len(g.hits) == 4)
+
+	// The deadline comes first: only 2 successes by 30ms. The partial result comes back with the error
+	g, err = scatterGather(context.Background(), backends, "golang", 3, 30*time.Millisecond)
+	fmt.Println("k=3 within 30ms - err:", err)
+	fmt.Println("  used:", names(g.used), "| cancelled:", g.cancelled,
+		"| is errNotEnoughResponses:", errors.Is(err, errNotEnoughResponses), "| partial result kept:", len(g.used) == 2)
+
+	// Every backend answered, but too few succeeded - the closed channel ends the wait, not the deadline
+	start = time.Now()
+	g, err = scatterGather(context.Background(), backends[:2], "golang", 2, time.Second)
+	fmt.Println("k=2 of eu-1 and eu-2 - err:", err)
+	fmt.Println("  didn't wait for the deadline:", time.Since(start) < 100*time.Millisecond)
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// === Select - timeouts, deadlines, heartbeats and tickers ===
+
+// time.After(d) returns a channel that receives once, d from now. As a select case it puts a time limit
+// on whatever the other cases are waiting for. Where the time.After call is placed decides what is limited:
+// - inside the loop: a fresh timer on every iteration - a limit per operation
+// - once, before the loop: one timer for the whole loop - an overall deadline
+// (Since Go 1.23 an unused timer is garbage collected even if it never fired, so neither leaks)
+
+var errOpTimeout = errors.New("operation timed out")
+
+// slowOp delivers v after d on a buffered channel. Buffered matters: if the caller has stopped waiting,
+// the send still succeeds and the goroutine exits. Unbuffered, it would block forever (a goroutine leak)
+func slowOp[T any](v T, d time.Duration) <-chan T {
+	ch := make(chan T, 1)
+	go func() {
+		time.Sleep(d)
+		ch <- v
+	}()
+	return ch
+}
+
+// --- 1. A timeout per operation ---
+
+func withTimeout[T any](ch <-chan T, timeout time.Duration) (T, error) {
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(timeout):
+		var zero T
+		return zero, errOpTimeout
+	}
+}
+
+func perOperationTimeoutExample() {
+	const timeout = 30 * time.Millisecond
+	for _, d := range []time.Duration{5 * time.Millisecond, 60 * time.Millisecond, 10 * time.Millisecond} {
+		start := time.Now()
+		v, err := withTimeout(slowOp(fmt.Sprint("took ", d), d), timeout)
+		waited := time.Since(start)
+		fmt.Printf("op taking %-5v -> %q, err: %v | waited at most the timeout: %t\n", d, v, err, waited < timeout+20*time.Millisecond)
+	}
+}
+
+// --- 2. One deadline for the whole loop ---
+
+// collect receives from items until it closes or the deadline passes
+func collect(items <-chan int, deadline <-chan time.Time) (got []int, timedOut bool) {
+	for {
+		select {
+		case v, ok := <-items:
+			if !ok {
+				return got, false
+			}
+			got = append(got, v)
+		case <-deadline:
+			return got, true
+		}
+	}
+}
+
+// trickle sends n items, one every d
+func trickle(n int, d time.Duration) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range n {
+			time.Sleep(d)
+			ch <- i
+		}
+	}()
+	return ch
+}
+
+func overallDeadlineExample() {
+	// 10 items, one every 20ms (200ms in total), against a 90ms deadline - about 4 of them make it
+	got, timedOut := collect(trickle(10, 20*time.Millisecond), time.After(90*time.Millisecond))
+	fmt.Printf("overall deadline: got %d items, timed out: %t | stopped early: %t\n", len(got), timedOut, len(got) < 10)
+
+	// The classic mistake - time.After inside the loop is a new 90ms timer for every item.
+	// Each item arrives within 20ms, so the timer never fires, and the "deadline" never comes
+	items := trickle(10, 20*time.Millisecond)
+	n := 0
+loop:
+	for {
+		select {
+		case _, ok := <-items:
+			if !ok {
+				break loop
+			}
+			n++
+		case <-time.After(90 * time.Millisecond):
+			break loop
+		}
+	}
+	fmt.Printf("time.After inside the loop: got %d items - all of them, the limit reset every time\n", n)
+}
+
+// --- 3. Heartbeats ---
+
+// A consumer can't tell a slow worker from a stuck one by waiting for results alone.
+// A heartbeat is a signal the worker sends regularly while it is alive - even when it has no result yet.
+// No heartbeat for a couple of intervals means the worker is stuck (or dead).
+
+// heartbeatWorker sends a heartbeat every interval, and a result every 3 intervals.
+// After hangAfter results it gets stuck (keeps running, but stops sending anything)
+func heartbeatWorker(interval time.Duration, hangAfter int, done <-chan struct{}) (heartbeat <-chan struct{}, results <-chan int) {
+	beats := make(chan struct{}, 1) // a buffer of 1 - a beat is waiting, or it isn't; more carry no information
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for i := 1; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if i/3 > hangAfter {
+				<-done // stuck: the goroutine is still there, but the heartbeats stop
+				return
+			}
+			select {
+			case beats <- struct{}{}:
+			default: // nobody took the last one yet - never block on a heartbeat
+			}
+			if i%3 == 0 {
+				select {
+				case out <- i / 3:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return beats, out
+}
+
+func heartbeatExample() {
+	const interval = 10 * time.Millisecond
+	done := make(chan struct{})
+	defer close(done)
+	heartbeat, results := heartbeatWorker(interval, 3, done)
+
+	var got []int
+	beats := 0
+	start := time.Now()
+	lastSign := start
+watch:
+	for {
+		select {
+		case <-heartbeat:
+			beats++
+			lastSign = time.Now()
+		case r := <-results:
+			got = append(got, r)
+			lastSign = time.Now()
+		case <-time.After(3 * interval): // a per-operation timeout again: reset by every sign of life
+			break watch
+		}
+	}
+	silence := time.Since(lastSign)
+	fmt.Printf("heartbeat: got results %v and %d beats, then declared the worker stuck after %v of silence\n",
+		got, beats, silence.Round(interval))
+	fmt.Println("detected within 3 intervals (+ slack):", silence < 3*interval+20*time.Millisecond, "| all results before the hang:", len(got) == 3)
+}
+
+// --- 4. A ticker in a select ---
+
+// A time.Ticker delivers on ticker.C every period, for work that repeats (progress reports, flushing a buffer).
+// ticker.C has a buffer of one: if the receiver is busy when ticks come due, the extra ticks are DROPPED,
+// not queued up - so a slow loop doesn't get a burst of catch-up ticks afterwards.
+// Stop it when done (defer ticker.Stop()) - Reset(d) changes the period
+
+func tickerInSelectExample() {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	items := trickle(10, 7*time.Millisecond)
+	processed, reports := 0, 0
+loop:
+	for {
+		select {
+		case _, ok := <-items:
+			if !ok {
+				break loop
+			}
+			processed++
+		case <-ticker.C:
+			reports++ // ex. log "processed N so far"
+		}
+	}
+	fmt.Printf("ticker in select: %d items processed, with %d progress reports along the way (one per 10ms)\n", processed, reports)
+
+	// Ticks are dropped while the receiver is busy
+	ticker.Reset(10 * time.Millisecond)
+	time.Sleep(55 * time.Millisecond) // busy for 5 periods
+	buffered := 0
+drain:
+	for {
+		select {
+		case <-ticker.C:
+			buffered++
+		default:
+			break drain
+		}
+	}
+	fmt.Println("ticks waiting after being busy for 5 periods:", buffered, "| dropped, not queued:", buffered == 1)
+}
+
+// --- 5. Priority between channels ---
+
+// When several cases are ready, select picks one at random - fair, so no channel is starved, but it means
+// a select has no notion of "this channel first". For that, ask the high priority channel on its own first,
+// in a select with a default (which makes it "receive if there's something, else don't wait"),
+// and only if it had nothing wait on both.
+// - the priority is checked between items: a low item already taken isn't interrupted by a high one arriving
+// - it's strict: while high always has something, low gets nothing at all
+
+type prioritized struct {
+	high bool
+	v    int
+}
+
+// consumeRandom is the plain select - either channel, at random when both are ready
+func consumeRandom(high, low <-chan int) []prioritized {
+	var got []prioritized
+	for high != nil || low != nil {
+		select {
+		case v, ok := <-high:
+			if !ok {
+				high = nil // a nil channel is never ready, so the select only waits on the other one
+				continue
+			}
+			got = append(got, prioritized{true, v})
+		case v, ok := <-low:
+			if !ok {
+				low = nil
+				continue
+			}
+			got = append(got, prioritized{false, v})
+		}
+	}
+	return got
+}
+
+// consumePriority takes from high whenever it has something, and from low only when it doesn't
+func consumePriority(high, low <-chan int) []prioritized {
+	var got []prioritized
+	for high != nil || low != nil {
+		select {
+		case v, ok := <-high:
+			if !ok {
+				high = nil
+				continue
+			}
+			got = append(got, prioritized{true, v})
+			continue
+		default: // nothing in high right now
+		}
+		select {
+		case v, ok := <-high: // high may get something while we wait - it still counts
+			if !ok {
+				high = nil
+				continue
+			}
+			got = append(got, prioritized{true, v})
+		case v, ok := <-low:
+			if !ok {
+				low = nil
+				continue
+			}
+			got = append(got, prioritized{false, v})
+		}
+	}
+	return got
+}
+
+// filled returns a closed channel holding n values - every receive is ready at once
+func filled(n int) <-chan int {
+	ch := make(chan int, n)
+	for i := range n {
+		ch <- i
+	}
+	close(ch)
+	return ch
+}
+
+// lowBeforeHighDone counts the low items taken while there were still high items left
+func lowBeforeHighDone(got []prioritized) int {
+	last := -1
+	for i, p := range got {
+		if p.high {
+			last = i
+		}
+	}
+	n := 0
+	for _, p := range got[:last+1] {
+		if !p.high {
+			n++
+		}
+	}
+	return n
+}
+
+func prioritySelectExample() {
+	// Both channels full from the start: 100 high and 100 low items ready at once
+	const n = 100
+	random := consumeRandom(filled(n), filled(n))
+	priority := consumePriority(filled(n), filled(n))
+	fmt.Println("low items taken before the last high one - random select:", lowBeforeHighDone(random),
+		"| priority select:", lowBeforeHighDone(priority))
+	fmt.Println("nothing lost either way:", len(random) == 2*n && len(priority) == 2*n)
+
+	// The same, 50 times over. Random takes each side half the time, so both run out at about the same point -
+	// most low items come before the last high one. Priority never takes a low item while a high one is waiting
+	const runs = 50
+	randomLow, priorityLow := 0, 0
+	for range runs {
+		randomLow += lowBeforeHighDone(consumeRandom(filled(n), filled(n)))
+		priorityLow += lowBeforeHighDone(consumePriority(filled(n), filled(n)))
+	}
+	fmt.Printf("over %d runs, low items taken early on average - random: %.1f, priority: %.1f\n",
+		runs, float64(randomLow)/runs, float64(priorityLow)/runs)
+	fmt.Println("priority select always drains high first:", priorityLow == 0, "| random select never does:", randomLow > runs*n/4)
+
+	// Within one channel the order is kept, whichever select is used
+	inOrder := func(got []prioritized) bool {
+		next := map[bool]int{}
+		for _, p := range got {
+			if p.v != next[p.high] {
+				return false
+			}
+			next[p.high]++
+		}
+		return true
+	}
+	fmt.Println("each channel's items still in order:", inOrder(random) && inOrder(priority))
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// The patterns run in synctest bubbles: time only moves when every goroutine is waiting on it,
+// so "after 30ms" is exactly 30ms and the tests can compare times with ==
+
+func TestWithTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		const timeout = 30 * time.Millisecond
+		for _, tc := range []struct {
+			op       time.Duration
+			wantErr  error
+			wantWait time.Duration
+		}{
+			{5 * time.Millisecond, nil, 5 * time.Millisecond},
+			{60 * time.Millisecond, errOpTimeout, timeout},
+			{timeout - time.Nanosecond, nil, timeout - time.Nanosecond},
+		} {
+			start := time.Now()
+			v, err := withTimeout(slowOp("done", tc.op), timeout)
+			if waited := time.Since(start); err != tc.wantErr || waited != tc.wantWait {
+				t.Errorf("op of %v: %q, %v after %v; want %v after %v", tc.op, v, err, waited, tc.wantErr, tc.wantWait)
+			}
+			if err != nil && v != "" {
+				t.Errorf("op of %v timed out, but returned %q, not the zero value", tc.op, v)
+			}
+		}
+		// the timed out op's goroutine still finishes, once its 60ms are up: its channel has room for the value
+		// nobody takes. (synctest.Test fails if a goroutine in the bubble is left blocked forever)
+		time.Sleep(time.Second)
+	})
+}
+
+func TestOverallDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// items at 20, 40, 60, 80ms... against a 90ms deadline
+		items := trickle(10, 20*time.Millisecond)
+		got, timedOut := collect(items, time.After(90*time.Millisecond))
+		if !slices.Equal(got, []int{0, 1, 2, 3}) || !timedOut {
+			t.Errorf("got %v, timed out %t; want [0 1 2 3] and a time out", got, timedOut)
+		}
+		for range items { // trickle's channel is unbuffered - it would wait forever to send the rest
+		}
+	})
+}
+
+func TestOverallDeadlineNotReached(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		got, timedOut := collect(trickle(3, 20*time.Millisecond), time.After(90*time.Millisecond))
+		if !slices.Equal(got, []int{0, 1, 2}) || timedOut {
+			t.Errorf("got %v, timed out %t; want all 3 and no time out", got, timedOut)
+		}
+	})
+}
+
+// The worker beats every 10ms and sends results at 30, 60 and 90ms, then hangs at 120ms.
+// Its last sign of life is the beat at 110ms, so the watcher with a 30ms timeout declares it stuck at 140ms
+func TestHeartbeat(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		const interval = 10 * time.Millisecond
+		done := make(chan struct{})
+		defer close(done)
+		heartbeat, results := heartbeatWorker(interval, 3, done)
+
+		var got []int
+		beats := 0
+		start := time.Now()
+		lastSign := start
+	watch:
+		for {
+			select {
+			case <-heartbeat:
+				beats++
+				lastSign = time.Now()
+			case r := <-results:
+				got = append(got, r)
+				lastSign = time.Now()
+			case <-time.After(3 * interval):
+				break watch
+			}
+		}
+		if !slices.Equal(got, []int{1, 2, 3}) || beats != 11 {
+			t.Errorf("got results %v and %d beats, want [1 2 3] and 11", got, beats)
+		}
+		if last, stuck := lastSign.Sub(start), time.Since(start); last != 110*time.Millisecond || stuck != 140*time.Millisecond {
+			t.Errorf("last sign at %v, declared stuck at %v; want 110ms and 140ms", last, stuck)
+		}
+	})
+}
+
+// A heartbeat nobody takes is dropped, never queued: the worker keeps going, and one beat is waiting
+func TestHeartbeatNeverBlocks(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		done := make(chan struct{})
+		defer close(done)
+		heartbeat, results := heartbeatWorker(10*time.Millisecond, 100, done)
+		for want := 1; want <= 5; want++ {
+			if r := <-results; r != want {
+				t.Fatalf("result %d, want %d", r, want)
+			}
+		}
+		if len(heartbeat) != 1 {
+			t.Errorf("%d beats waiting after 15 unread, want 1", len(heartbeat))
+		}
+	})
+}
+
+func TestTickerInSelect(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		items := trickle(9, 7*time.Millisecond) // the last at 63ms - never at the same instant as a tick
+		processed, reports := 0, 0
+	loop:
+		for {
+			select {
+			case _, ok := <-items:
+				if !ok {
+					break loop
+				}
+				processed++
+			case <-ticker.C:
+				reports++
+			}
+		}
+		if processed != 9 || reports != 6 {
+			t.Errorf("%d items and %d reports, want 9 and one per 10ms up to 63ms: 6", processed, reports)
+		}
+	})
+}
+
+// While the receiver is busy for 5 periods, the ticks aren't queued: one is waiting, the rest were dropped
+func TestTickerDropsTicks(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		time.Sleep(55 * time.Millisecond)
+		waiting := 0
+	drain:
+		for {
+			select {
+			case <-ticker.C:
+				waiting++
+			default:
+				break drain
+			}
+		}
+		if waiting != 1 {
+			t.Errorf("%d ticks waiting after 5 periods busy, want 1", waiting)
+		}
+		// the next tick comes on the ticker's schedule, not 10ms after the late receive
+		start := time.Now()
+		<-ticker.C
+		if d := time.Since(start); d != 5*time.Millisecond {
+			t.Errorf("next tick %v later, want 5ms (at 60ms)", d)
+		}
+	})
+}
+
+// inOrder reports whether each channel's items came out in the order they were sent
+func inOrder(got []prioritized) bool {
+	next := map[bool]int{}
+	for _, p := range got {
+		if p.v != next[p.high] {
+			return false
+		}
+		next[p.high]++
+	}
+	return true
+}
+
+// Both channels full from the start: the priority consumer takes every high item before any low one,
+// the plain select mixes them. Neither loses an item or reorders a channel
+func TestPrioritySelectOrdering(t *testing.T) {
+	const n, runs = 100, 50
+	randomLow := 0
+	for run := range runs {
+		priority := consumePriority(filled(n), filled(n))
+		if early := lowBeforeHighDone(priority); early != 0 {
+			t.Fatalf("run %d: priority select took %d low items while high ones were waiting", run, early)
+		}
+		for i, p := range priority {
+			if want := (prioritized{high: i < n, v: i % n}); p != want {
+				t.Fatalf("run %d: item %d = %+v, want %+v", run, i, p, want)
+			}
+		}
+
+		random := consumeRandom(filled(n), filled(n))
+		if len(random) != 2*n || !inOrder(random) {
+			t.Fatalf("run %d: random select gave %d items, in order %t", run, len(random), inOrder(random))
+		}
+		randomLow += lowBeforeHighDone(random)
+	}
+	// at random, each side is picked half the time, so most low items come before the last high one
+	if randomLow < runs*n/4 {
+		t.Errorf("random select took %.1f low items early on average, want most of the %d", float64(randomLow)/runs, n)
+	}
+}
+
+// Priority is checked between items: while the consumer waits with both channels empty, whichever
+// arrives first is taken - a high item arriving then is taken at once, ahead of the low items after it
+func TestPrioritySelectWhileWaiting(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		high, low := make(chan int), make(chan int, 2)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			low <- 0 // first, while the consumer waits on both
+			time.Sleep(10 * time.Millisecond)
+			high <- 0
+			low <- 1
+			close(high)
+			close(low)
+		}()
+		got := consumePriority(high, low)
+		want := []prioritized{{false, 0}, {true, 0}, {false, 1}}
+		if !slices.Equal(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// === Graceful shutdown ===
+
+// A long running program (a server, a queue worker) gets SIGINT from Ctrl+C and SIGTERM from whatever
+// runs it (systemd, Docker, Kubernetes). Dying straight away loses the work in progress; shutting down
+// gracefully means:
+// 1. stop taking new work
+// 2. let the work already started finish (with a time limit - a stuck job mustn't block the exit forever)
+// 3. close each channel after its last send, in pipeline order, so every loop downstream ends by itself
+// 4. return from main normally
+//
+// signal.NotifyContext turns the signals into a context that is cancelled on the first one -
+// everything that already takes a ctx stops without knowing about signals at all.
+// Calling its stop func restores the default behaviour, so a SECOND Ctrl+C kills the process
+// (the usual escape hatch when the graceful part hangs).
+//
+// The example below is a ticker-driven worker pool:
+//
+//	ticker ──> producer ──jobs──> 3 workers ──results──> collector
+//
+// Press Ctrl+C to stop it - or it sends itself SIGINT after about half a second.
+// An HTTP server shuts down the same way, with http.Server.Shutdown draining the requests in flight -
+// that half is gracefulServerShutdownExample in httpserver/main (net/http linked into this package would
+// stop the runtime reporting the deadlock examples here)
+
+type workItem struct {
+	ID      int
+	Created time.Time
+}
+
+// produce turns ticks into work items until ctx is cancelled, then closes jobs (it is the only sender)
+func produce(ctx context.Context, every time.Duration, jobs chan<- workItem, produced *atomic.Int32) {
+	defer close(jobs)
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for id := 0; ; id++ {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			select {
+			case jobs <- workItem{ID: id, Created: t}:
+				produced.Add(1)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// work handles jobs until the channel is closed. It doesn't watch ctx - a job that has started
+// is finished, which is the "drain in-flight work" part
+func work(jobs <-chan workItem, results chan<- workItem, took time.Duration) {
+	for j := range jobs {
+		time.Sleep(took)
+		results <- j
+	}
+}
+
+func gracefulShutdownExample() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	goroutinesBefore := runtime.NumGoroutine()
+
+	// --- The worker pool ---
+
+	jobs := make(chan workItem)
+	results := make(chan workItem)
+	var produced, processed atomic.Int32
+	go produce(ctx, 40*time.Millisecond, jobs, &produced)
+
+	var workers sync.WaitGroup
+	for range 3 {
+		workers.Go(func() { work(jobs, results, 100*time.Millisecond) })
+	}
+	go func() {
+		workers.Wait() // the last worker is done, so nothing can send on results anymore
+		close(results)
+	}()
+
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for range results {
+			processed.Add(1)
+		}
+	}()
+
+	// Unless Ctrl+C comes first, the program interrupts itself - p.Signal(os.Interrupt) is the same as pressing it
+	fmt.Println("working - press Ctrl+C to stop")
+	time.AfterFunc(500*time.Millisecond, func() {
+		p, _ := os.FindProcess(os.Getpid())
+		p.Signal(os.Interrupt)
+	})
+
+	// --- Shutdown, in order ---
+
+	<-ctx.Done()
+	stop() // from here on, a second Ctrl+C kills the process
+	start := time.Now()
+	fmt.Println("\nsignal received:", context.Cause(ctx))
+
+	// The deadline for all of the draining below
+	drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 1. new work: the producer saw ctx.Done() and closed jobs - nothing else has to be told
+	fmt.Println("1. producer stopped, jobs closed")
+
+	// 2. in-flight jobs: the workers finish what they hold, their range over the closed jobs ends,
+	// and the goroutine waiting on them closes results - which ends the collector's range
+	select {
+	case <-collected:
+		fmt.Println("2. workers drained, results closed, collector done")
+	case <-drainCtx.Done():
+		fmt.Println("2. gave up waiting for the workers:", drainCtx.Err())
+	}
+
+	// 3. checks
+	fmt.Printf("3. produced %d, processed %d | nothing lost: %t\n", produced.Load(), processed.Load(), produced.Load() == processed.Load())
+	time.Sleep(10 * time.Millisecond) // let the exiting goroutines finish returning
+	fmt.Printf("   shut down in %v | goroutines leaked: %t\n",
+		time.Since(start).Round(10*time.Millisecond), runtime.NumGoroutine() > goroutinesBefore)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// === sync/atomic ===
+
+// For a single number (a counter, a flag, a pointer), sync/atomic does the read-modify-write as one
+// indivisible CPU instruction - no lock, and no goroutine ever waits for another.
+// Two styles, same operations:
+// - functions on plain variables: atomic.AddInt64(&n, 1), atomic.LoadInt64(&n), atomic.CompareAndSwapInt64(&n, old, new)
+// - types (Go 1.19+): var n atomic.Int64; n.Add(1), n.Load(), n.CompareAndSwap(old, new)
+// The types are the safer choice: a plain int64 can still be read or written without atomics by mistake
+// (a data race), an atomic.Int64 can't.
+//
+// The limit: each call is atomic on its own, but two calls together are not.
+// Anything that has to keep several values consistent with each other still needs a Mutex.
+
+// atomicIncrementExample is unsafeIncrementExample with the race fixed -
+// the same 100 goroutines, but the increment can no longer be lost
+func atomicIncrementExample() {
+	var val int64 // a plain variable, only ever touched through atomic functions
+	var typed atomic.Int64
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			atomic.AddInt64(&val, 1)
+			typed.Add(1)
+		})
+	}
+	wg.Wait() // instead of sleeping and hoping every goroutine has run
+
+	// is 100 every time, and go run -race reports nothing
+	fmt.Println("atomic.AddInt64:", atomic.LoadInt64(&val), "| atomic.Int64:", typed.Load())
+}
+
+// --- CompareAndSwap ---
+
+// CompareAndSwap(old, new) sets the value to new only if it is still old, and reports whether it did.
+// It's how to build operations sync/atomic doesn't have: read, compute, then CAS - and if another
+// goroutine changed the value in between, the CAS fails and the loop tries again with the new value.
+// (concurrencyGauge in errgroup_example.go raises a maximum this way)
+
+// incrementUpTo adds 1 to n unless that would go past limit, and reports whether it did
+func incrementUpTo(n *atomic.Int64, limit int64) bool {
+	for {
+		cur := n.Load()
+		if cur >= limit {
+			return false
+		}
+		if n.CompareAndSwap(cur, cur+1) {
+			return true
+		}
+		// lost the race - cur is stale, go around again
+	}
+}
+
+func compareAndSwapExample() {
+	// 100 goroutines x 100 attempts against a limit of 500: exactly 500 succeed, never one more.
+	// With n.Load() then n.Add(1) as two calls, several goroutines could all see 499 and all add
+	const limit = 500
+	var n, succeeded atomic.Int64
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			for range 100 {
+				if incrementUpTo(&n, limit) {
+					succeeded.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	fmt.Printf("capped counter: %d (limit %d), successful increments: %d | as expected: %t\n",
+		n.Load(), limit, succeeded.Load(), n.Load() == limit && succeeded.Load() == limit)
+
+	// A one-shot claim: only the first CAS from 0 succeeds, so exactly one goroutine becomes the leader
+	var leader atomic.Int64
+	var winners atomic.Int32
+	for id := range int64(10) {
+		wg.Go(func() {
+			if leader.CompareAndSwap(0, id+1) { // +1 so that 0 can mean "no leader yet"
+				winners.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	fmt.Println("leader:", leader.Load()-1, "| exactly one winner:", winners.Load() == 1)
+}
+
+// --- Benchmark: atomic vs Mutex vs a goroutine owning the counter ---
+
+type incCounter interface {
+	Inc()
+	Value() int64
+}
+
+type atomicCounter struct{ n atomic.Int64 }
+
+func (c *atomicCounter) Inc()         { c.n.Add(1) }
+func (c *atomicCounter) Value() int64 { return c.n.Load() }
+
+type mutexIntCounter struct {
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *mutexIntCounter) Inc() {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func (c *mutexIntCounter) Value() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// chanCounter is "share memory by communicating": only its own goroutine touches n,
+// everyone else sends it requests. Each Inc is a channel send + a goroutine switch
+type chanCounter struct {
+	inc  chan struct{}
+	get  chan chan int64
+	done chan struct{}
+}
+
+func newChanCounter() *chanCounter {
+	c := &chanCounter{inc: make(chan struct{}), get: make(chan chan int64), done: make(chan struct{})}
+	go func() {
+		var n int64
+		for {
+			select {
+			case <-c.inc:
+				n++
+			case reply := <-c.get:
+				reply <- n
+			case <-c.done:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+func (c *chanCounter) Inc() { c.inc <- struct{}{} }
+
+func (c *chanCounter) Value() int64 {
+	reply := make(chan int64)
+	c.get <- reply
+	return <-reply
+}
+
+func (c *chanCounter) Close() { close(c.done) }
+
+// timeIncs does n increments of c, split across goroutines started together, and returns the time per
+// increment. A rough timing: BenchmarkCounter in syncatomic_test.go is the careful one
+func timeIncs(c incCounter, goroutines, n int) time.Duration {
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		count := n / goroutines
+		if g == 0 {
+			count += n % goroutines
+		}
+		wg.Go(func() {
+			<-start
+			for range count {
+				c.Inc()
+			}
+		})
+	}
+	began := time.Now() // starting the goroutines isn't the increments
+	close(start)
+	wg.Wait()
+	return time.Since(began) / time.Duration(n)
+}
+
+func counterBenchmarkExample() {
+	// (the checks are in syncatomic_test.go)
+	const n = 200_000
+	goroutines := 4 * runtime.GOMAXPROCS(0) // 4 goroutines per CPU, all incrementing the same counter
+	fmt.Println("GOMAXPROCS:", runtime.GOMAXPROCS(0))
+	fmt.Println("(go test -bench Counter concurrency measures the same, more carefully)")
+	fmt.Printf("%-10s %8s %14s\n", "counter", "ns/op", "counted")
+	for _, bm := range []struct {
+		name string
+		make func() incCounter
+	}{
+		{"atomic", func() incCounter { return &atomicCounter{} }},
+		{"Mutex", func() incCounter { return &mutexIntCounter{} }},
+		{"channel", func() incCounter { return newChanCounter() }},
+	} {
+		var d time.Duration
+		var counted int64
+		for _, ops := range []int{n / 10, n} { // the first run is a warm up
+			c := bm.make()
+			d, counted = timeIncs(c, goroutines, ops), c.Value()
+			if cc, ok := c.(*chanCounter); ok {
+				cc.Close()
+			}
+		}
+		fmt.Printf("%-10s %8d %14d\n", bm.name, d.Nanoseconds(), counted)
+	}
+	// Typically atomic is fastest, Mutex a few times slower under contention,
+	// and the channel version slowest by far - it's the right tool when the goroutine owns more than a number
+}
+
+// --- Benchmark: SafeCounter vs the channel counter vs atomic, by number of writers ---
+
+// The sync.Mutex note says a Mutex is for when goroutines don't need to communicate. This puts numbers on it:
+// the same increments split across exactly 1, 4 and 32 writer goroutines (not SetParallelism, which
+// multiplies by GOMAXPROCS), with SafeCounter itself - map and RWMutex, as used in safeIncrementMutuxExample - in the race.
+
+// safeCounterInc adapts SafeCounter to incCounter, always counting COUNTER_KEY
+type safeCounterInc struct{ c *SafeCounter }
+
+func (s safeCounterInc) Inc()         { s.c.SafeInc(COUNTER_KEY) }
+func (s safeCounterInc) Value() int64 { return int64(s.c.GetValue(COUNTER_KEY)) }
+
+func writersBenchmarkExample() {
+	writers := []int{1, 4, 32}
+	counters := []struct {
+		name string
+		make func() incCounter
+	}{
+		{"atomic", func() incCounter { return &atomicCounter{} }},
+		{"SafeCounter", func() incCounter { return safeCounterInc{&SafeCounter{v: make(map[string]int)}} }},
+		{"channel", func() incCounter { return newChanCounter() }},
+	}
+
+	// (the checks are in syncatomic_test.go)
+	fmt.Printf("ns per increment, GOMAXPROCS=%d\n", runtime.GOMAXPROCS(0))
+	fmt.Println("(go test -bench Writers concurrency measures the same, more carefully)")
+	fmt.Printf("%-12s", "counter")
+	for _, w := range writers {
+		fmt.Printf(" %10s", fmt.Sprintf("%d writers", w))
+	}
+	fmt.Printf(" %8s\n", "exact")
+
+	const n = 200_000
+	ns := map[string][]int64{}
+	for _, c := range counters {
+		fmt.Printf("%-12s", c.name)
+		allExact := true
+		for _, w := range writers {
+			var d time.Duration
+			for _, ops := range []int{n / 10, n} { // the first run is a warm up
+				counter := c.make()
+				d = timeIncs(counter, w, ops)
+				allExact = allExact && counter.Value() == int64(ops)
+				if cc, ok := counter.(*chanCounter); ok {
+					cc.Close()
+				}
+			}
+			ns[c.name] = append(ns[c.name], d.Nanoseconds())
+			fmt.Printf(" %10d", d.Nanoseconds())
+		}
+		fmt.Printf(" %8t\n", allExact)
+	}
+
+	mutexBeatsChannel := true
+	for i := range writers {
+		mutexBeatsChannel = mutexBeatsChannel && ns["SafeCounter"][i] < ns["channel"][i]
+	}
+	fmt.Println("\nSafeCounter is faster than the channel counter at every writer count:", mutexBeatsChannel)
+	fmt.Printf("at 32 writers the channel counter is %.1fx SafeCounter, SafeCounter %.1fx atomic\n",
+		float64(ns["channel"][2])/float64(ns["SafeCounter"][2]), float64(ns["SafeCounter"][2])/float64(ns["atomic"][2]))
+	// SafeCounter pays for a map lookup under the lock and still wins: a lock nobody else holds is a couple of
+	// atomic operations, while every channel Inc is a hand-off to the owning goroutine
+}
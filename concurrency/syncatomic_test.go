@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func incCounters() []struct {
+	name string
+	make func() incCounter
+} {
+	return []struct {
+		name string
+		make func() incCounter
+	}{
+		{"atomic", func() incCounter { return &atomicCounter{} }},
+		{"Mutex", func() incCounter { return &mutexIntCounter{} }},
+		{"channel", func() incCounter { return newChanCounter() }},
+	}
+}
+
+// closeCounter stops a chanCounter's goroutine - the others have nothing to stop
+func closeCounter(c incCounter) {
+	if cc, ok := c.(*chanCounter); ok {
+		cc.Close()
+	}
+}
+
+// Every counter counts every increment, from many goroutines at once
+func TestCounters(t *testing.T) {
+	for _, ic := range incCounters() {
+		c := ic.make()
+		if got := c.Value(); got != 0 {
+			t.Errorf("%s: a new counter is %d", ic.name, got)
+		}
+		timeIncs(c, 50, 10_001)
+		if got := c.Value(); got != 10_001 {
+			t.Errorf("%s: %d after 10001 increments on 50 goroutines", ic.name, got)
+		}
+		closeCounter(c)
+	}
+}
+
+// 100 goroutines x 100 attempts against a limit of 500: exactly 500 succeed, never one more
+func TestIncrementUpTo(t *testing.T) {
+	const limit = 500
+	var n, succeeded atomic.Int64
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() {
+			for range 100 {
+				if incrementUpTo(&n, limit) {
+					succeeded.Add(1)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if n.Load() != limit || succeeded.Load() != limit {
+		t.Errorf("counter %d, %d successful increments, want %d of each", n.Load(), succeeded.Load(), limit)
+	}
+	if incrementUpTo(&n, limit) {
+		t.Error("incrementUpTo succeeded at the limit")
+	}
+}
+
+// ns/op is one increment, 4 x GOMAXPROCS goroutines all incrementing the same counter.
+// go test -bench Counter concurrency
+func BenchmarkCounter(b *testing.B) {
+	for _, ic := range incCounters() {
+		b.Run(ic.name, func(b *testing.B) {
+			c := ic.make()
+			defer closeCounter(c)
+			b.SetParallelism(4)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					c.Inc()
+				}
+			})
+			// every run must have counted every increment - a fast but wrong counter doesn't count
+			if got := c.Value(); got != int64(b.N) {
+				b.Fatalf("counted %d of %d increments", got, b.N)
+			}
+		})
+	}
+}
+
+func writerCounters() []struct {
+	name string
+	make func() incCounter
+} {
+	return []struct {
+		name string
+		make func() incCounter
+	}{
+		{"atomic", func() incCounter { return &atomicCounter{} }},
+		{"SafeCounter", func() incCounter { return safeCounterInc{&SafeCounter{v: make(map[string]int)}} }},
+		{"channel", func() incCounter { return newChanCounter() }},
+	}
+}
+
+// SafeCounter through the adapter counts COUNTER_KEY, and only it
+func TestSafeCounterInc(t *testing.T) {
+	sc := &SafeCounter{v: make(map[string]int)}
+	c := safeCounterInc{sc}
+	timeIncs(c, 32, 1000)
+	if c.Value() != 1000 || sc.GetValue(COUNTER_KEY) != 1000 || len(sc.v) != 1 {
+		t.Errorf("Value %d, %q counted %d, %d keys", c.Value(), COUNTER_KEY, sc.GetValue(COUNTER_KEY), len(sc.v))
+	}
+}
+
+// ns/op is one increment, b.N of them split across exactly 1, 4 and 32 writers started together.
+// go test -bench Writers concurrency
+func BenchmarkWriters(b *testing.B) {
+	for _, wc := range writerCounters() {
+		for _, writers := range []int{1, 4, 32} {
+			b.Run(fmt.Sprintf("%s/writers=%d", wc.name, writers), func(b *testing.B) {
+				c := wc.make()
+				defer closeCounter(c)
+				start := make(chan struct{})
+				var wg sync.WaitGroup
+				for w := range writers {
+					n := b.N / writers
+					if w == 0 {
+						n += b.N % writers
+					}
+					wg.Go(func() {
+						<-start
+						for range n {
+							c.Inc()
+						}
+					})
+				}
+				b.ResetTimer() // starting the goroutines isn't the increments
+				close(start)
+				wg.Wait()
+				if got := c.Value(); got != int64(b.N) {
+					b.Fatalf("counted %d of %d increments", got, b.N)
+				}
+			})
+		}
+	}
+}
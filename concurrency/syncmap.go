@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// === sync.Map ===
+
+// A map that is safe for concurrent use without a separate lock. It is not a drop-in for map+Mutex:
+// keys and values are `any` (so every Load needs a type assertion), and there's no Len.
+// The docs name the two cases it's built for:
+// 1. an entry is written once and then read many times (caches that only grow)
+// 2. goroutines read and write disjoint sets of keys
+// For everything else - and whenever the map has to stay consistent with other state - use map + Mutex.
+//
+// Operations beyond Load / Store / Delete:
+// - LoadOrStore(k, v): returns the existing value if k is present, otherwise stores v. loaded says which
+// - LoadAndDelete, Swap, CompareAndSwap, CompareAndDelete
+// - Range(f): calls f for each entry until f returns false. NOT a snapshot - entries stored or deleted
+//   during the Range may or may not be seen
+
+// --- A cache of rendered pages ---
+
+// pageCache renders each page once, no matter how many goroutines ask for it at the same time.
+// LoadOrStore alone isn't enough for that: every goroutine that misses would render the page, and all but
+// one result would be thrown away. So the map holds an entry with its own sync.Once - LoadOrStore decides
+// which entry wins, and the Once makes sure only one goroutine does the (slow) rendering
+type pageCache struct {
+	pages   sync.Map // url -> *cacheEntry
+	renders atomic.Int32
+}
+
+type cacheEntry struct {
+	once sync.Once
+	html string
+}
+
+func (c *pageCache) render(url string) string {
+	c.renders.Add(1)
+	time.Sleep(5 * time.Millisecond)
+	return "<h1>" + url + "</h1>"
+}
+
+func (c *pageCache) Get(url string) string {
+	v, _ := c.pages.LoadOrStore(url, &cacheEntry{})
+	e := v.(*cacheEntry) // the type assertion sync.Map makes you write everywhere
+	e.once.Do(func() { e.html = c.render(url) })
+	return e.html
+}
+
+func syncMapCacheExample() {
+	// (the checks are in syncmap_test.go)
+	var cache pageCache
+	urls := []string{"/tour/", "/tour/basics/1", "/tour/concurrency/9", "/tour/generics/1"}
+
+	// 200 goroutines, each page requested 50 times at once
+	var wg sync.WaitGroup
+	for i := range 200 {
+		wg.Go(func() { cache.Get(urls[i%len(urls)]) })
+	}
+	wg.Wait()
+	fmt.Printf("200 concurrent requests for %d pages - rendered: %d | once per page: %t\n",
+		len(urls), cache.renders.Load(), int(cache.renders.Load()) == len(urls))
+
+	// LoadOrStore's loaded result: false the first time a key is stored, true after
+	var m sync.Map
+	_, loaded := m.LoadOrStore("gopher", 1)
+	actual, loadedAgain := m.LoadOrStore("gopher", 2)
+	fmt.Println("first LoadOrStore loaded:", loaded, "| second loaded:", loadedAgain, "with the value kept:", actual)
+
+	// Range visits the entries in no particular order - sort them to print
+	var cached []string
+	cache.pages.Range(func(k, v any) bool {
+		cached = append(cached, k.(string))
+		return true // false would stop the iteration
+	})
+	slices.Sort(cached)
+	fmt.Println("cached pages:", cached)
+}
+
+// --- Benchmark: sync.Map vs map + Mutex ---
+
+type intCache interface {
+	Load(key string) (int, bool)
+	Store(key string, v int)
+}
+
+// lockedMap is the SafeCounter shape: a plain map behind a Mutex
+type lockedMap struct {
+	mu sync.Mutex
+	m  map[string]int
+}
+
+func (c *lockedMap) Load(key string) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.m[key]
+	return v, ok
+}
+
+func (c *lockedMap) Store(key string, v int) {
+	c.mu.Lock()
+	c.m[key] = v
+	c.mu.Unlock()
+}
+
+type syncMapCache struct{ m sync.Map }
+
+func (c *syncMapCache) Load(key string) (int, bool) {
+	v, ok := c.m.Load(key)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (c *syncMapCache) Store(key string, v int) { c.m.Store(key, v) }
+
+// timeCache runs random operations over keys (all stored up front), writePercent of them Stores, on
+// 4 x GOMAXPROCS goroutines - the time per operation, all goroutines together. A rough timing:
+// BenchmarkIntCache in syncmap_test.go is the careful one
+func timeCache(c intCache, keys []string, writePercent int) time.Duration {
+	for i, k := range keys {
+		c.Store(k, i)
+	}
+	const ops = 400_000
+	goroutines := 4 * runtime.GOMAXPROCS(0)
+	perGoroutine := ops / goroutines
+	start := time.Now()
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			rng := rand.New(rand.NewPCG(uint64(g), 0)) // one per goroutine - a shared one would be its own contention
+			for range perGoroutine {
+				k := keys[rng.IntN(len(keys))]
+				if rng.IntN(100) < writePercent {
+					c.Store(k, 1)
+				} else {
+					c.Load(k)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return time.Since(start) / time.Duration(perGoroutine*goroutines)
+}
+
+func syncMapBenchmarkExample() {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	// Which wins depends on the core count: a Mutex serializes everyone, which costs little on one core
+	// and a lot on many, while sync.Map reads don't take a lock at all
+	fmt.Println("GOMAXPROCS:", runtime.GOMAXPROCS(0))
+	fmt.Println("(go test -bench IntCache concurrency measures the same, more carefully)")
+	fmt.Printf("%-14s %16s %16s\n", "workload", "map+Mutex ns/op", "sync.Map ns/op")
+	for _, w := range []struct {
+		name         string
+		writePercent int
+	}{{"1% writes", 1}, {"10% writes", 10}, {"50% writes", 50}, {"writes only", 100}} {
+		locked := timeCache(&lockedMap{m: map[string]int{}}, keys, w.writePercent)
+		syncMap := timeCache(&syncMapCache{}, keys, w.writePercent)
+		fmt.Printf("%-14s %16d %16d\n", w.name, locked.Nanoseconds(), syncMap.Nanoseconds())
+	}
+	// Typical shape on a multi-core machine: sync.Map well ahead for read-heavy work, the gap
+	// closing as writes grow. On top of raw speed, map+Mutex keeps static types, len, and the option
+	// to update several entries under one lock - sync.Map has to earn its place by measurement
+	// (basics/main/lookuptable.go adds a sharded map - basics/shardmap - and more goroutine counts)
+}
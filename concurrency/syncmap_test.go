@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// 200 goroutines asking for 4 pages at once: each page is rendered once, and everyone gets it
+func TestPageCacheRendersOnce(t *testing.T) {
+	var cache pageCache
+	urls := []string{"/tour/", "/tour/basics/1", "/tour/concurrency/9", "/tour/generics/1"}
+	got := make([]string, 200)
+	var wg sync.WaitGroup
+	for i := range 200 {
+		wg.Go(func() { got[i] = cache.Get(urls[i%len(urls)]) })
+	}
+	wg.Wait()
+	if n := cache.renders.Load(); n != int32(len(urls)) {
+		t.Errorf("%d renders for %d pages, want one each", n, len(urls))
+	}
+	for i, html := range got {
+		if want := "<h1>" + urls[i%len(urls)] + "</h1>"; html != want {
+			t.Errorf("request %d got %q, want %q", i, html, want)
+		}
+	}
+	var cached []string
+	cache.pages.Range(func(k, _ any) bool {
+		cached = append(cached, k.(string))
+		return true
+	})
+	slices.Sort(cached)
+	if !slices.Equal(cached, slices.Sorted(slices.Values(urls))) {
+		t.Errorf("cached %q", cached)
+	}
+}
+
+func TestLoadOrStore(t *testing.T) {
+	var m sync.Map
+	if _, loaded := m.LoadOrStore("gopher", 1); loaded {
+		t.Error("the first LoadOrStore loaded")
+	}
+	if actual, loaded := m.LoadOrStore("gopher", 2); !loaded || actual != 1 {
+		t.Errorf("the second LoadOrStore = %v, %t, want the value kept, 1, true", actual, loaded)
+	}
+}
+
+func intCaches() []struct {
+	name  string
+	cache func() intCache
+} {
+	return []struct {
+		name  string
+		cache func() intCache
+	}{
+		{"mutex", func() intCache { return &lockedMap{m: map[string]int{}} }},
+		{"syncmap", func() intCache { return &syncMapCache{} }},
+	}
+}
+
+func TestIntCaches(t *testing.T) {
+	for _, ic := range intCaches() {
+		c := ic.cache()
+		if _, ok := c.Load("a"); ok {
+			t.Errorf("%s: Load on an empty cache found a", ic.name)
+		}
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Go(func() {
+				for i := range 100 {
+					c.Store(fmt.Sprintf("g%d:%d", g, i), i)
+					c.Load(fmt.Sprintf("g%d:%d", (g+1)%8, i))
+				}
+			})
+		}
+		wg.Wait()
+		for g := range 8 {
+			if v, ok := c.Load(fmt.Sprintf("g%d:99", g)); !ok || v != 99 {
+				t.Errorf("%s: g%d:99 = %d, %t", ic.name, g, v, ok)
+			}
+		}
+	}
+}
+
+// BenchmarkIntCache runs random operations over 1000 stored keys on 4 x GOMAXPROCS goroutines,
+// some percent of them Stores. go test -bench IntCache concurrency
+func BenchmarkIntCache(b *testing.B) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+	for _, writePercent := range []int{1, 10, 50, 100} {
+		for _, ic := range intCaches() {
+			b.Run(fmt.Sprintf("writes=%d%%/%s", writePercent, ic.name), func(b *testing.B) {
+				c := ic.cache()
+				for i, k := range keys {
+					c.Store(k, i)
+				}
+				var seed atomic.Uint64
+				b.SetParallelism(4)
+				b.RunParallel(func(pb *testing.PB) {
+					rng := rand.New(rand.NewPCG(seed.Add(1), 0)) // one per goroutine - a shared one would be its own contention
+					for pb.Next() {
+						k := keys[rng.IntN(len(keys))]
+						if rng.IntN(100) < writePercent {
+							c.Store(k, 1)
+						} else {
+							c.Load(k)
+						}
+					}
+				})
+			})
+		}
+	}
+}
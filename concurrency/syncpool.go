@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// === sync.Pool ===
+
+// The Reader loop in methodsinterfaces.go reads into one []byte, over and over - one buffer for the whole stream.
+// A server reading many streams at once (one per request) needs a buffer per stream, and allocating a fresh
+// 32 KB for each is garbage the GC then has to clean up, at the rate requests come in.
+//
+// sync.Pool is a set of reusable objects that any goroutine can take from (Get) and give back to (Put):
+// - Get returns something Put earlier, or a new one from New if the pool is empty. It may be dirty -
+//   reset it before use
+// - the pool is emptied by the GC (what was pooled survives one collection, then goes),
+//   so it's a cache for garbage, not a place to keep things - a pool can't limit how many exist
+// - put pointers: Put(any) of a []byte boxes the slice header, which is an allocation of its own
+// - each P (GOMAXPROCS) has its own part of the pool, so Get and Put rarely contend
+
+const readBufSize = 32 << 10
+
+var readBufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, readBufSize)
+		return &b
+	},
+}
+
+// countLines reads r to the end through buf, the same loop as the Reader example
+func countLines(r io.Reader, buf []byte) (int, error) {
+	lines := 0
+	for {
+		n, err := r.Read(buf)
+		lines += bytes.Count(buf[:n], []byte("\n"))
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+func countLinesFreshBuffer(r io.Reader) (int, error) {
+	return countLines(r, make([]byte, readBufSize)) // escapes (r.Read could keep it), so it's on the heap
+}
+
+func countLinesPooledBuffer(r io.Reader) (int, error) {
+	bp := readBufPool.Get().(*[]byte)
+	defer readBufPool.Put(bp) // countLines is done with it when it returns - it must not keep buf
+	return countLines(r, *bp)
+}
+
+// The mistake - a pool of []byte. The buffers are reused, but every Put allocates a box for the slice header
+var sliceBufPool = sync.Pool{New: func() any { return make([]byte, readBufSize) }}
+
+func countLinesSlicePool(r io.Reader) (int, error) {
+	buf := sliceBufPool.Get().([]byte)
+	defer sliceBufPool.Put(buf)
+	return countLines(r, buf)
+}
+
+// --- Pooled bytes.Buffer ---
+
+// A bytes.Buffer keeps its grown capacity after Reset, so reusing one skips growing it again.
+// But the pool keeps whatever it's given: one huge render would keep a huge buffer alive (and handed out)
+// from then on, so too big buffers aren't put back
+var renderPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+const maxPooledRender = 64 << 10
+
+func renderGreeting(names []string) string {
+	buf := renderPool.Get().(*bytes.Buffer)
+	buf.Reset() // it may hold the last render
+	defer func() {
+		if buf.Cap() <= maxPooledRender {
+			renderPool.Put(buf)
+		}
+	}()
+	for _, n := range names {
+		fmt.Fprintf(buf, "hello, %s\n", n)
+	}
+	return buf.String() // a copy - the buffer is reused after this returns
+}
+
+func syncPoolExample() {
+	// (the checks are in syncpool_test.go)
+	doc := strings.Repeat("a line of a request body\n", 4000) // about 100 KB
+	lines, _ := countLinesPooledBuffer(strings.NewReader(doc))
+	fresh, _ := countLinesFreshBuffer(strings.NewReader(doc))
+	fmt.Println("lines:", lines, "| with a fresh buffer:", fresh)
+
+	fmt.Print(renderGreeting([]string{"gopher", "ferris"}))
+	fmt.Print(renderGreeting([]string{"pooled buffer, reset"}))
+
+	// Allocations per read, each way: TestCountLinesAllocs in syncpool_test.go measures them (and logs them with -v),
+	// and BenchmarkCountLines times them on many goroutines at once:
+	//
+	//	go test -run CountLinesAllocs -v concurrency
+	//	go test -bench CountLines concurrency
+	//
+	// Fresh: a 32 KB buffer every read. The pool of pointers: nothing. The pool of slices: the buffer is
+	// reused, but every Put boxes its slice header - a small allocation, still one per read
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var countLinesFuncs = []struct {
+	name string
+	fn   func(io.Reader) (int, error)
+}{
+	{"fresh", countLinesFreshBuffer},
+	{"pool of *[]byte", countLinesPooledBuffer},
+	{"pool of []byte", countLinesSlicePool},
+}
+
+func TestCountLines(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want int
+	}{
+		{"", 0},
+		{"no newline", 0},
+		{"one\n", 1},
+		{"a\nb\n\nc", 3},
+		{strings.Repeat("a line of a request body\n", 4000), 4000}, // more than one buffer's worth
+	}
+	for _, tt := range tests {
+		for _, f := range countLinesFuncs {
+			if got, err := f.fn(strings.NewReader(tt.doc)); got != tt.want || err != nil {
+				t.Errorf("%s, %d bytes: %d, %v, want %d", f.name, len(tt.doc), got, err, tt.want)
+			}
+			// A byte at a time: the same count, so nothing depends on how the reads are split
+			if got, _ := f.fn(iotest.OneByteReader(strings.NewReader(tt.doc))); got != tt.want {
+				t.Errorf("%s, %d bytes, one byte a read: %d, want %d", f.name, len(tt.doc), got, tt.want)
+			}
+		}
+	}
+}
+
+// A read error is returned, with the lines counted before it
+func TestCountLinesError(t *testing.T) {
+	errRead := errors.New("connection reset")
+	for _, f := range countLinesFuncs {
+		r := io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(errRead))
+		if got, err := f.fn(r); got != 2 || !errors.Is(err, errRead) {
+			t.Errorf("%s: %d, %v, want 2, %v", f.name, got, err, errRead)
+		}
+	}
+}
+
+func TestRenderGreeting(t *testing.T) {
+	if got := renderGreeting([]string{"gopher", "ferris"}); got != "hello, gopher\nhello, ferris\n" {
+		t.Errorf("first render = %q", got)
+	}
+	// The buffer is reset: nothing of the last render is left in the next
+	if got := renderGreeting([]string{"pooled"}); got != "hello, pooled\n" {
+		t.Errorf("second render = %q", got)
+	}
+	if got := renderGreeting(nil); got != "" {
+		t.Errorf("render of nobody = %q", got)
+	}
+}
+
+// A render too big to pool is still returned whole - and its buffer never comes out of the pool again
+func TestRenderGreetingOversize(t *testing.T) {
+	names := make([]string, 10_000) // 10,000 x "hello, gopher\n": 140 KB, over maxPooledRender
+	for i := range names {
+		names[i] = "gopher"
+	}
+	got := renderGreeting(names)
+	if len(got) != len(names)*len("hello, gopher\n") {
+		t.Errorf("oversize render is %d bytes", len(got))
+	}
+	for range 10 {
+		if buf := renderPool.Get().(*bytes.Buffer); buf.Cap() > maxPooledRender {
+			t.Fatalf("got a %d byte buffer from the pool, over the %d limit", buf.Cap(), maxPooledRender)
+		}
+	}
+}
+
+// The point of the pool: a fresh buffer is an allocation per read, a pooled pointer none, and a pool of
+// slices still one - the box for the slice header on every Put
+func TestCountLinesAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("under -race sync.Pool drops a random share of Puts, so pooled buffers are allocated again")
+	}
+	doc := strings.Repeat("a line of a request body\n", 100)
+	r := strings.NewReader(doc)
+	want := map[string]float64{"fresh": 1, "pool of *[]byte": 0, "pool of []byte": 1}
+	for _, f := range countLinesFuncs {
+		f.fn(r) // fill the pool first
+		got := testing.AllocsPerRun(100, func() {
+			r.Reset(doc) // the same reader over again, so only the buffer is allocated per read
+			f.fn(r)
+		})
+		t.Logf("%-16s %v allocs per read", f.name, got)
+		if got != want[f.name] {
+			t.Errorf("%s: %v allocs per read, want %v", f.name, got, want[f.name])
+		}
+	}
+}
+
+// Many goroutines reading at once, each read needing a buffer: the B/op column is the difference.
+// go test -bench CountLines concurrency
+func BenchmarkCountLines(b *testing.B) {
+	doc := strings.Repeat("a line of a request body\n", 4000) // about 100 KB
+	for _, f := range countLinesFuncs {
+		b.Run(f.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetParallelism(4)
+			b.RunParallel(func(pb *testing.PB) {
+				r := strings.NewReader(doc)
+				for pb.Next() {
+					r.Reset(doc) // the same reader over again, so only the buffer is allocated per op
+					f.fn(r)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// === sync.Once ===
+
+// Once runs a function exactly one time, no matter how many goroutines call Do at once.
+// The callers that arrive while it is running wait for it to finish, so they all see the result.
+// The usual use: a value that is expensive to build and might not be needed (lazy initialization)
+
+// SiteIndex maps every page to the pages linking to it - built from the whole site, so it's slow to make
+type SiteIndex struct {
+	linkedFrom map[string][]string
+}
+
+var indexBuilds atomic.Int32 // how many times buildIndex actually ran
+
+func buildIndex() *SiteIndex {
+	indexBuilds.Add(1)
+	time.Sleep(20 * time.Millisecond) // the expensive part
+	idx := &SiteIndex{linkedFrom: map[string][]string{}}
+	for page, links := range tourPages {
+		for _, to := range links {
+			idx.linkedFrom[to] = append(idx.linkedFrom[to], page)
+		}
+	}
+	for _, from := range idx.linkedFrom {
+		slices.Sort(from)
+	}
+	return idx
+}
+
+var (
+	indexOnce sync.Once
+	index     *SiteIndex
+)
+
+// Index returns the one shared SiteIndex, building it on the first call
+func Index() *SiteIndex {
+	indexOnce.Do(func() { index = buildIndex() })
+	return index
+}
+
+// Since Go 1.21, sync.OnceValue does the same without the two package level variables:
+//
+//	var Index = sync.OnceValue(buildIndex)
+//
+// One difference: if the function panics, Once.Do counts it as done (later calls return, with index still nil),
+// while a OnceValue func panics again with the same value on every call
+
+func onceExample() {
+	var wg sync.WaitGroup
+	got := make([]*SiteIndex, 100)
+	for i := range got {
+		wg.Go(func() { got[i] = Index() })
+	}
+	wg.Wait()
+
+	same := true
+	for _, idx := range got {
+		same = same && idx == got[0]
+	}
+	fmt.Println("100 concurrent callers - built:", indexBuilds.Load(), "time(s) | all got the same index:", same)
+	fmt.Println("pages linking to /cmd/:", Index().linkedFrom["https://golang.org/cmd/"])
+
+	start := time.Now()
+	Index()
+	fmt.Println("later calls don't wait:", time.Since(start) < time.Millisecond, "| still built once:", indexBuilds.Load() == 1)
+}
+
+// === sync.RWMutex ===
+
+// SafeCounter uses an RWMutex: GetValue takes the read lock, so reads only wait for writes, not for each other.
+// mutexCounter is the same counter with a plain Mutex, to compare against
+
+type mutexCounter struct {
+	mu sync.Mutex
+	v  map[string]int
+}
+
+func (c *mutexCounter) SafeInc(key string) {
+	c.mu.Lock()
+	c.v[key]++
+	c.mu.Unlock()
+}
+
+func (c *mutexCounter) GetValue(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.v[key]
+}
+
+type keyCounter interface {
+	SafeInc(key string)
+	GetValue(key string) int
+}
+
+// timeCounter runs one write per writeEvery calls, the rest reads, on 4 x GOMAXPROCS goroutines at once -
+// so they contend even on a single core - and returns the time per call, all goroutines together.
+// A rough timing: BenchmarkKeyCounter in syncprims_test.go is the careful one
+func timeCounter(c keyCounter, writeEvery int) time.Duration {
+	const calls = 400_000
+	goroutines := 4 * runtime.GOMAXPROCS(0)
+	perGoroutine := calls / goroutines
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for i := range perGoroutine {
+				if i%writeEvery == 0 {
+					c.SafeInc(COUNTER_KEY)
+				} else {
+					c.GetValue(COUNTER_KEY)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return time.Since(start) / time.Duration(perGoroutine*goroutines)
+}
+
+func rwMutexBenchmarkExample() {
+	// (the checks, and those of Once and BoundedQueue, are in syncprims_test.go)
+	// Correctness first: the RWMutex version still counts every increment
+	counter := SafeCounter{v: make(map[string]int)}
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { counter.SafeInc(COUNTER_KEY) })
+		wg.Go(func() { counter.GetValue(COUNTER_KEY) })
+	}
+	wg.Wait()
+	fmt.Println("100 increments alongside 100 reads:", counter.GetValue(COUNTER_KEY))
+
+	// The gap depends on how many reads really run in parallel, so it grows with the number of cores.
+	// With mostly writes, RWMutex is slightly slower - its bookkeeping costs more than a plain Mutex
+	fmt.Println("GOMAXPROCS:", runtime.GOMAXPROCS(0))
+	fmt.Println("(go test -bench KeyCounter concurrency measures the same, more carefully)")
+	fmt.Printf("%-22s %14s %14s\n", "workload", "Mutex ns/op", "RWMutex ns/op")
+	for _, w := range []struct {
+		name       string
+		writeEvery int
+	}{{"1 write per 100 reads", 100}, {"1 write per 10 reads", 10}, {"writes only", 1}} {
+		timeCounter(&mutexCounter{v: map[string]int{}}, w.writeEvery) // warm up
+		m := timeCounter(&mutexCounter{v: map[string]int{}}, w.writeEvery)
+		rw := timeCounter(&SafeCounter{v: map[string]int{}}, w.writeEvery)
+		fmt.Printf("%-22s %14d %14d\n", w.name, m.Nanoseconds(), rw.Nanoseconds())
+	}
+}
+
+// === sync.Cond ===
+
+// A Cond is a place for goroutines to wait until some condition (on data guarded by a mutex) becomes true:
+// - Wait unlocks the mutex, sleeps until woken, then locks it again before returning
+// - Signal wakes one waiting goroutine, Broadcast wakes all of them
+// Being woken doesn't mean the condition holds (another goroutine may have got there first),
+// so Wait always goes in a loop: for !condition { cond.Wait() }
+//
+// A buffered channel is already a bounded queue, and is usually the better choice.
+// Cond is for when the condition is something a channel can't express - here, a queue that can also
+// report its length, and whose Close wakes every blocked Put and Get at once
+
+var ErrQueueClosed = errors.New("queue closed")
+
+type BoundedQueue[T any] struct {
+	mu       sync.Mutex
+	notEmpty sync.Cond // Get waits on this
+	notFull  sync.Cond // Put waits on this
+	items    []T
+	capacity int // 0: no limit, see NewUnboundedQueue
+	closed   bool
+	maxLen   int // the longest the queue has been, to check the bound
+}
+
+// NewBoundedQueue holds up to capacity items, at least 1: with no room at all every Put would wait forever
+func NewBoundedQueue[T any](capacity int) *BoundedQueue[T] {
+	if capacity < 1 {
+		panic("NewBoundedQueue: capacity must be at least 1")
+	}
+	return newQueue[T](capacity)
+}
+
+// NewUnboundedQueue is a queue with no limit: Put appends and never waits, so nothing pushes back on
+// a producer that's faster than its consumers (see backpressure.go)
+func NewUnboundedQueue[T any]() *BoundedQueue[T] {
+	return newQueue[T](0)
+}
+
+func newQueue[T any](capacity int) *BoundedQueue[T] {
+	q := &BoundedQueue[T]{capacity: capacity}
+	q.notEmpty.L = &q.mu // both conditions are about the same data, so they share one mutex
+	q.notFull.L = &q.mu
+	return q
+}
+
+// Put adds v, waiting while the queue is full
+func (q *BoundedQueue[T]) Put(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.capacity > 0 && len(q.items) == q.capacity && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return ErrQueueClosed
+	}
+	q.items = append(q.items, v)
+	q.maxLen = max(q.maxLen, len(q.items))
+	q.notEmpty.Signal() // one item -> one waiting Get can take it
+	return nil
+}
+
+// Get removes the oldest item, waiting while the queue is empty.
+// ok is false once the queue is closed and everything in it has been taken
+func (q *BoundedQueue[T]) Get() (v T, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if len(q.items) == 0 {
+		return v, false
+	}
+	v = q.items[0]
+	q.items = q.items[1:]
+	q.notFull.Signal()
+	return v, true
+}
+
+// Close makes later Puts fail. Gets still drain what's left
+func (q *BoundedQueue[T]) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	// every waiter has to recheck its condition - Signal would only wake one of them
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+func (q *BoundedQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+func boundedQueueExample() {
+	const capacity, producers, consumers, perProducer = 5, 4, 3, 250
+	q := NewBoundedQueue[int](capacity)
+
+	var producing, consuming sync.WaitGroup
+	for p := range producers {
+		producing.Go(func() {
+			for i := range perProducer {
+				q.Put(p*perProducer + i)
+			}
+		})
+	}
+	received := make([][]int, consumers)
+	for c := range consumers {
+		consuming.Go(func() {
+			for {
+				v, ok := q.Get()
+				if !ok {
+					return
+				}
+				received[c] = append(received[c], v)
+			}
+		})
+	}
+	producing.Wait()
+	q.Close() // the consumers finish what's queued, then Get returns ok = false
+	consuming.Wait()
+
+	all := slices.Concat(received...)
+	slices.Sort(all)
+	exactlyOnce := len(all) == producers*perProducer
+	for i, v := range all {
+		exactlyOnce = exactlyOnce && v == i
+	}
+	fmt.Printf("%d items from %d producers to %d consumers - each delivered exactly once: %t\n",
+		producers*perProducer, producers, consumers, exactlyOnce)
+	fmt.Printf("longest the queue got: %d (capacity %d) | bound respected: %t\n", q.maxLen, capacity, q.maxLen <= capacity)
+	fmt.Println("Put after Close:", q.Put(1))
+
+	// Close wakes goroutines that are blocked right now, on both sides
+	empty, full := NewBoundedQueue[int](1), NewBoundedQueue[int](1)
+	full.Put(0)
+	getOK, putErr := make(chan bool), make(chan error)
+	go func() { _, ok := empty.Get(); getOK <- ok }()
+	go func() { putErr <- full.Put(1) }()
+	time.Sleep(10 * time.Millisecond) // let both block
+	empty.Close()
+	full.Close()
+	fmt.Println("blocked Get woken, ok:", <-getOK, "| blocked Put woken, err:", <-putErr)
+}
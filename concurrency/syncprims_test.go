@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+)
+
+// 100 callers at once: buildIndex runs once, and everyone gets the same index
+func TestIndexOnce(t *testing.T) {
+	got := make([]*SiteIndex, 100)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Go(func() { got[i] = Index() })
+	}
+	wg.Wait()
+	if n := indexBuilds.Load(); n != 1 {
+		t.Errorf("built %d times, want 1", n)
+	}
+	for i, idx := range got {
+		if idx == nil || idx != got[0] {
+			t.Fatalf("caller %d got a different index", i)
+		}
+	}
+	// Every link is in the index under its target, and each list is sorted
+	idx := Index()
+	for page, links := range tourPages {
+		for _, to := range links {
+			if !slices.Contains(idx.linkedFrom[to], page) {
+				t.Errorf("%s links to %s, but isn't in linkedFrom[%s]", page, to, to)
+			}
+		}
+	}
+	for to, from := range idx.linkedFrom {
+		if !slices.IsSorted(from) {
+			t.Errorf("linkedFrom[%s] isn't sorted: %q", to, from)
+		}
+	}
+}
+
+// Both counters count every increment made alongside reads
+func TestKeyCounters(t *testing.T) {
+	for _, c := range []keyCounter{&mutexCounter{v: map[string]int{}}, &SafeCounter{v: map[string]int{}}} {
+		var wg sync.WaitGroup
+		for range 100 {
+			wg.Go(func() { c.SafeInc(COUNTER_KEY) })
+			wg.Go(func() { c.GetValue(COUNTER_KEY) })
+		}
+		wg.Wait()
+		if got := c.GetValue(COUNTER_KEY); got != 100 {
+			t.Errorf("%T: %d after 100 increments", c, got)
+		}
+		if got := c.GetValue("other"); got != 0 {
+			t.Errorf("%T: an untouched key is %d", c, got)
+		}
+	}
+}
+
+// Producers and consumers through a small queue: every item arrives exactly once, and the queue never
+// holds more than its capacity
+func TestBoundedQueue(t *testing.T) {
+	const capacity, producers, consumers, perProducer = 5, 4, 3, 250
+	q := NewBoundedQueue[int](capacity)
+	var producing, consuming sync.WaitGroup
+	for p := range producers {
+		producing.Go(func() {
+			for i := range perProducer {
+				if err := q.Put(p*perProducer + i); err != nil {
+					t.Errorf("Put: %v", err)
+				}
+			}
+		})
+	}
+	received := make([][]int, consumers)
+	for c := range consumers {
+		consuming.Go(func() {
+			for {
+				v, ok := q.Get()
+				if !ok {
+					return
+				}
+				received[c] = append(received[c], v)
+			}
+		})
+	}
+	producing.Wait()
+	q.Close()
+	consuming.Wait()
+
+	all := slices.Concat(received...)
+	slices.Sort(all)
+	for i, v := range all {
+		if v != i {
+			t.Fatalf("received %d items, not each of 0..%d once (at %d: %d)", len(all), producers*perProducer-1, i, v)
+		}
+	}
+	if len(all) != producers*perProducer {
+		t.Errorf("received %d items, want %d", len(all), producers*perProducer)
+	}
+	if q.maxLen > capacity {
+		t.Errorf("the queue held %d, over its capacity of %d", q.maxLen, capacity)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len after draining = %d", q.Len())
+	}
+}
+
+// What's queued at Close is still handed out, in order; after it, Get is ok = false and Put fails
+func TestBoundedQueueClose(t *testing.T) {
+	q := NewBoundedQueue[string](3)
+	q.Put("a")
+	q.Put("b")
+	q.Close()
+	q.Close() // twice is fine
+	if err := q.Put("c"); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Put after Close = %v, want ErrQueueClosed", err)
+	}
+	for _, want := range []string{"a", "b"} {
+		if v, ok := q.Get(); v != want || !ok {
+			t.Errorf("Get = %q, %t, want %q, true", v, ok, want)
+		}
+	}
+	if v, ok := q.Get(); ok {
+		t.Errorf("Get on a closed, empty queue = %q, true", v)
+	}
+}
+
+// Close wakes the goroutines blocked on it right now: a Get on an empty queue and a Put on a full one
+func TestBoundedQueueCloseWakes(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		empty, full := NewBoundedQueue[int](1), NewBoundedQueue[int](1)
+		full.Put(0)
+		getOK, putErr := make(chan bool, 1), make(chan error, 1)
+		go func() { _, ok := empty.Get(); getOK <- ok }()
+		go func() { putErr <- full.Put(1) }()
+		synctest.Wait() // both are waiting on their Cond
+		select {
+		case <-getOK:
+			t.Fatal("Get returned before Close")
+		case <-putErr:
+			t.Fatal("Put returned before Close")
+		default:
+		}
+		empty.Close()
+		full.Close()
+		if ok := <-getOK; ok {
+			t.Error("the woken Get returned ok = true")
+		}
+		if err := <-putErr; !errors.Is(err, ErrQueueClosed) {
+			t.Errorf("the woken Put returned %v, want ErrQueueClosed", err)
+		}
+	})
+}
+
+func TestNewBoundedQueueRejects(t *testing.T) {
+	wantPanic(t, "zero capacity", func() { NewBoundedQueue[int](0) })
+	wantPanic(t, "negative capacity", func() { NewBoundedQueue[int](-1) })
+}
+
+// An unbounded queue's Put never waits, however many items nobody has taken yet
+func TestUnboundedQueue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		q := NewUnboundedQueue[int]()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 10_000 {
+				q.Put(i)
+			}
+		}()
+		synctest.Wait()
+		select {
+		case <-done:
+		default:
+			t.Fatal("Put waited on an unbounded queue")
+		}
+		q.Close()
+		for want := range 10_000 {
+			if v, ok := q.Get(); v != want || !ok {
+				t.Fatalf("Get = %d, %t, want %d, true", v, ok, want)
+			}
+		}
+	})
+}
+
+// ns/op is one call, one write per writeEvery calls and the rest reads, on 4 x GOMAXPROCS goroutines.
+// RWMutex wins when the reads really run in parallel - with many cores and few writes.
+// go test -bench KeyCounter concurrency
+func BenchmarkKeyCounter(b *testing.B) {
+	for _, w := range []struct {
+		name       string
+		writeEvery int
+	}{{"writes=1per100", 100}, {"writes=1per10", 10}, {"writes=all", 1}} {
+		for _, kc := range []struct {
+			name string
+			c    keyCounter
+		}{{"Mutex", &mutexCounter{v: map[string]int{}}}, {"RWMutex", &SafeCounter{v: map[string]int{}}}} {
+			b.Run(w.name+"/"+kc.name, func(b *testing.B) {
+				b.SetParallelism(4)
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						if i%w.writeEvery == 0 {
+							kc.c.SafeInc(COUNTER_KEY)
+						} else {
+							kc.c.GetValue(COUNTER_KEY)
+						}
+					}
+				})
+			})
+		}
+	}
+}
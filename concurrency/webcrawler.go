@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// === Exercise: Web Crawler ===
+
+// The Tour's version of the exercise: crawl in parallel, without fetching the same URL twice.
+// (crawler.go has a context-aware Crawl built on the same idea, that also reports why it stopped,
+// and the exercises module has the mutex version as the Tour declares it, for tour grade to check)
+//
+// Two ways to keep the "seen" set safe across goroutines:
+// 1. share it behind a sync.Mutex - check and mark in ONE critical section,
+//    otherwise two goroutines can both see "not visited" and both fetch
+// 2. don't share it: one coordinator goroutine owns the set, and the workers send it the links they find
+//    ("share memory by communicating"). No lock, and the coordinator also knows when the crawl is over -
+//    it counts the fetches still running
+
+// PageFetcher is the Fetcher interface from the Tour (no context)
+type PageFetcher interface {
+	// Fetch returns the body of URL and a slice of URLs found on that page
+	Fetch(url string) (body string, urls []string, err error)
+}
+
+// --- 1. Mutex-guarded seen set ---
+
+type seenSet struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// visit marks url as seen, and reports whether this call was the first one for it
+func (s *seenSet) visit(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[url] {
+		return false
+	}
+	s.seen[url] = true
+	return true
+}
+
+// CrawlMutex crawls pages starting with url, to a maximum of depth, and returns what found prints
+func CrawlMutex(url string, depth int, fetcher PageFetcher) []string {
+	var (
+		seen  = &seenSet{seen: map[string]bool{}}
+		wg    sync.WaitGroup
+		outMu sync.Mutex
+		out   []string
+	)
+	found := func(line string) {
+		outMu.Lock()
+		out = append(out, line)
+		outMu.Unlock()
+	}
+
+	var crawl func(url string, depth int)
+	crawl = func(url string, depth int) {
+		if depth <= 0 || !seen.visit(url) {
+			return
+		}
+		body, urls, err := fetcher.Fetch(url)
+		if err != nil {
+			found(err.Error())
+			return
+		}
+		found(fmt.Sprintf("found: %s %q", url, body))
+		for _, u := range urls {
+			wg.Go(func() { crawl(u, depth-1) })
+		}
+	}
+	crawl(url, depth)
+	wg.Wait() // every crawl goroutine was started (wg.Go) before its parent returned, so Wait sees them all
+
+	slices.Sort(out) // goroutines finish in any order
+	return out
+}
+
+// --- 2. Channel-guarded seen set (a coordinator goroutine) ---
+
+type crawlResult struct {
+	line  string
+	urls  []string
+	depth int // depth left for the urls found on this page
+}
+
+// CrawlChannel is CrawlMutex without a lock: only the loop below touches seen
+func CrawlChannel(url string, depth int, fetcher PageFetcher) []string {
+	if depth <= 0 {
+		return nil
+	}
+	results := make(chan crawlResult)
+	fetch := func(url string, depth int) {
+		body, urls, err := fetcher.Fetch(url)
+		if err != nil {
+			results <- crawlResult{line: err.Error()}
+			return
+		}
+		results <- crawlResult{line: fmt.Sprintf("found: %s %q", url, body), urls: urls, depth: depth - 1}
+	}
+
+	seen := map[string]bool{url: true}
+	go fetch(url, depth)
+	var out []string
+
+	// running = fetches started but not received yet. At 0 there is nothing left that could find a new link
+	for running := 1; running > 0; running-- {
+		r := <-results
+		out = append(out, r.line)
+		if r.depth <= 0 {
+			continue
+		}
+		for _, u := range r.urls {
+			if !seen[u] {
+				seen[u] = true
+				running++
+				go fetch(u, r.depth)
+			}
+		}
+	}
+
+	slices.Sort(out)
+	return out
+}
+
+// --- The Tour's fake fetcher ---
+
+// tourFetcher returns canned results, like the fakeFetcher in the Tour
+type tourFetcher map[string]*tourResult
+
+type tourResult struct {
+	body string
+	urls []string
+}
+
+func (f tourFetcher) Fetch(url string) (string, []string, error) {
+	if res, ok := f[url]; ok {
+		return res.body, res.urls, nil
+	}
+	return "", nil, fmt.Errorf("not found: %s", url)
+}
+
+var tourSite = tourFetcher{
+	"https://golang.org/": &tourResult{
+		"The Go Programming Language",
+		[]string{"https://golang.org/pkg/", "https://golang.org/cmd/"},
+	},
+	"https://golang.org/pkg/": &tourResult{
+		"Packages",
+		[]string{"https://golang.org/", "https://golang.org/cmd/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/"},
+	},
+	"https://golang.org/pkg/fmt/": &tourResult{
+		"Package fmt",
+		[]string{"https://golang.org/", "https://golang.org/pkg/"},
+	},
+	"https://golang.org/pkg/os/": &tourResult{
+		"Package os",
+		[]string{"https://golang.org/", "https://golang.org/pkg/"},
+	},
+}
+
+// Both crawlers give the same lines. webcrawler_test.go runs them many times with a fetcher that counts,
+// to check that no URL is fetched twice and none past the depth is fetched at all
+func webCrawlerExample() {
+	for _, c := range []struct {
+		name  string
+		crawl func(string, int, PageFetcher) []string
+	}{
+		{"mutex", CrawlMutex},
+		{"channel", CrawlChannel},
+	} {
+		lines := c.crawl("https://golang.org/", 4, tourSite)
+		fmt.Printf("%s, depth 4:\n  %s\n", c.name, strings.Join(lines, "\n  "))
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// countingFetcher wraps a fetcher and counts the fetches of each URL (safe for concurrent use)
+type countingFetcher struct {
+	PageFetcher
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (f *countingFetcher) Fetch(url string) (string, []string, error) {
+	f.mu.Lock()
+	f.counts[url]++
+	f.mu.Unlock()
+	return f.PageFetcher.Fetch(url)
+}
+
+var crawlers = []struct {
+	name  string
+	crawl func(string, int, PageFetcher) []string
+}{
+	{"mutex", CrawlMutex},
+	{"channel", CrawlChannel},
+}
+
+// checkFetchedOnce crawls many times - a check-then-mark race usually only shows up once in a while -
+// and checks each URL within depth was fetched exactly once, and no other URL at all
+func checkFetchedOnce(t *testing.T, site PageFetcher, root string, depth int, wantFetched []string) {
+	t.Helper()
+	for _, c := range crawlers {
+		for run := range 200 {
+			f := &countingFetcher{PageFetcher: site, counts: map[string]int{}}
+			c.crawl(root, depth, f)
+			for url, n := range f.counts {
+				if n != 1 {
+					t.Errorf("%s, depth %d, run %d: %s fetched %d times", c.name, depth, run, url, n)
+				}
+			}
+			if got := slices.Sorted(maps.Keys(f.counts)); !slices.Equal(got, wantFetched) {
+				t.Fatalf("%s, depth %d: fetched %v, want %v", c.name, depth, got, wantFetched)
+			}
+		}
+	}
+}
+
+func TestCrawlFetchesEachURLOnce(t *testing.T) {
+	// depth 2 stops after the root's links: /pkg/fmt/ and /pkg/os/ are 3 links deep
+	for _, tc := range []struct {
+		depth       int
+		wantFetched []string
+	}{
+		{4, []string{"https://golang.org/", "https://golang.org/cmd/", "https://golang.org/pkg/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/"}},
+		{2, []string{"https://golang.org/", "https://golang.org/cmd/", "https://golang.org/pkg/"}},
+		{1, []string{"https://golang.org/"}},
+		{0, nil},
+	} {
+		checkFetchedOnce(t, tourSite, "https://golang.org/", tc.depth, tc.wantFetched)
+	}
+}
+
+// Every page links to every other page: many goroutines find the same link at once
+func TestCrawlFetchesEachURLOnceDenseSite(t *testing.T) {
+	const pages = 20
+	site := tourFetcher{}
+	var all []string
+	for i := range pages {
+		all = append(all, fmt.Sprintf("https://example.com/%02d", i))
+	}
+	for _, url := range all {
+		site[url] = &tourResult{body: url, urls: all}
+	}
+	checkFetchedOnce(t, site, all[0], 3, all)
+}
+
+func TestCrawlLines(t *testing.T) {
+	want := []string{
+		`found: https://golang.org/ "The Go Programming Language"`,
+		`found: https://golang.org/pkg/ "Packages"`,
+		`found: https://golang.org/pkg/fmt/ "Package fmt"`,
+		`found: https://golang.org/pkg/os/ "Package os"`,
+		`not found: https://golang.org/cmd/`,
+	}
+	for _, c := range crawlers {
+		if got := c.crawl("https://golang.org/", 4, tourSite); !slices.Equal(got, want) {
+			t.Errorf("%s: %q, want %q", c.name, got, want)
+		}
+	}
+}
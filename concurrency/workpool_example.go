@@ -0,0 +1,119 @@
+package main
+
+import (
+	"concurrency/internal/workpool"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// === A typed worker pool (see internal/workpool) ===
+
+// The pool in shutdown.go passes workItems, and its workers are written for them - another job type means
+// another produce, work and results channel. workpool.Pool[In, Out] is the same shape written once:
+// the job type and the result type are type parameters, so a Pool[string, pageInfo] takes strings and
+// gives pageInfos, checked at compile time - no any, no type assertions on the results.
+
+type pageInfo struct {
+	words int
+	title string
+}
+
+// fetchPage pretends to fetch and parse a page (missing ones are errPageNotFound, from crawler.go), counting how many fetches run at once
+func fetchPage(running, peak *atomic.Int32) func(context.Context, string) (pageInfo, error) {
+	return func(ctx context.Context, url string) (pageInfo, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		select {
+		case <-time.After(5 * time.Millisecond):
+		case <-ctx.Done():
+			return pageInfo{}, context.Cause(ctx)
+		}
+		if strings.HasSuffix(url, "/missing") {
+			return pageInfo{}, fmt.Errorf("%s: %w", url, errPageNotFound)
+		}
+		title, _ := strings.CutPrefix(url, "https://go.dev/tour/")
+		return pageInfo{words: len(url), title: title}, nil
+	}
+}
+
+func workPoolExample() {
+	urls := []string{"https://go.dev/tour/missing"}
+	for _, page := range []string{"basics/1", "basics/2", "flowcontrol/1", "moretypes/1", "methods/1", "generics/1", "concurrency/1"} {
+		urls = append(urls, "https://go.dev/tour/"+page)
+	}
+
+	var running, peak atomic.Int32
+	p := workpool.New(context.Background(), 3, fetchPage(&running, &peak))
+	go func() {
+		for _, u := range urls {
+			p.Submit(u)
+		}
+		p.Wait()
+	}()
+	got := map[string]pageInfo{}
+	var failed []error
+	for r := range p.Results() { // r.Out is a pageInfo, r.In the url it came from
+		if r.Err != nil {
+			failed = append(failed, r.Err)
+			continue
+		}
+		got[r.In] = r.Out
+	}
+	fmt.Println("pages:", len(got), "| failed:", failed)
+	fmt.Println(urls[1], "->", got[urls[1]], "| most fetches at once:", peak.Load())
+
+	err := p.Submit("https://go.dev/tour/late")
+	fmt.Println("Submit after Wait:", err, "| Wait again:", p.Wait())
+
+	// Another pool, other types - the same code: ints in, their squares as strings out
+	squares := workpool.New(context.Background(), 2, func(_ context.Context, n int) (string, error) {
+		return fmt.Sprint(n * n), nil
+	})
+	go func() {
+		for n := range 5 {
+			squares.Submit(n)
+		}
+		squares.Wait()
+	}()
+	var sq []string
+	for r := range squares.Results() {
+		sq = append(sq, r.Out)
+	}
+	slices.Sort(sq)
+	fmt.Println("squares (sorted, they finish in any order):", sq)
+
+	// Cancelled part way: Submit fails from then on, Wait reports why, and the workers are gone
+	ctx, cancel := context.WithCancelCause(context.Background())
+	slow := workpool.New(ctx, 2, func(ctx context.Context, n int) (int, error) {
+		select {
+		case <-time.After(time.Minute):
+			return n, nil
+		case <-ctx.Done():
+			return 0, context.Cause(ctx)
+		}
+	})
+	submitted := make(chan error, 1)
+	go func() {
+		var err error
+		for n := 0; err == nil; n++ {
+			err = slow.Submit(n)
+		}
+		submitted <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // 2 jobs running, a third Submit waiting for a worker
+	start := time.Now()
+	cancel(errors.New("shutting down"))
+	fmt.Println("Submit after cancel:", <-submitted)
+	// Nobody reads Results here: a cancelled pool drops the results of the jobs that were running,
+	// so Wait doesn't wait on a reader - and the minute-long jobs saw the cancel through their ctx
+	fmt.Println("Wait:", slow.Wait(), "| after", time.Since(start).Round(time.Millisecond))
+	// internal/workpool/workpool_test.go checks every job coming out once, the worker limit, panics,
+	// and that a cancelled pool leaves no goroutine behind
+}
@@ -0,0 +1,79 @@
+package cryptopkg
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The cryptographic counterparts of the hashing notes' FNV and maphash:
+//
+// - SHA-256 (crypto/sha256) is a hash no one knows how to collide: two files with the same sum have the
+//   same contents. So a sum checks a file arrived intact - but anyone can compute one, so it doesn't say
+//   who sent it
+// - an HMAC (crypto/hmac) is a hash keyed with a secret: only someone with the key can make the right
+//   one, so it says the data is from them, and unchanged
+// - crypto/rand reads the OS's secure random source - for ids, tokens and keys, never math/rand,
+//   whose output can be predicted from a few values (the randpkg module is math/rand/v2, and where it fits)
+// - comparing a secret with == stops at the first byte that differs, so how long it takes says how much
+//   of a guess was right. subtle.ConstantTimeCompare (and hmac.Equal) look at every byte, every time
+
+// SHA256Reader is the hex SHA-256 of everything read from r. The hash is an io.Writer, so io.Copy
+// streams into it - a file of any size in a 32KB buffer
+func SHA256Reader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SHA256File is the hex SHA-256 of the file at path - the checksum that sha256sum prints
+func SHA256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return SHA256Reader(f)
+}
+
+// NewKey is a random 32 byte key, for Sign
+func NewKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// Sign is the HMAC-SHA256 of data with key
+func Sign(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Verify reports whether sig is data's HMAC with key, in constant time
+func Verify(key, data, sig []byte) bool {
+	return hmac.Equal(sig, Sign(key, data))
+}
+
+// NewUUID is a random (version 4) UUID, in the usual form - like the notes' userId1,
+// 1d02455e-f24c-4c26-90d2-f1073c686314. 122 of its 128 bits are random: collisions don't happen in practice
+func NewUUID() string {
+	var b [16]byte
+	rand.Read(b[:])         // never returns an error: it crashes the program if the OS can't give randomness
+	b[6] = b[6]&0x0f | 0x40 // version 4: the first hex digit of the third group is 4
+	b[8] = b[8]&0x3f | 0x80 // variant 10: the first digit of the fourth group is 8, 9, a or b
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// EqualSecret reports whether a and b are the same, in time that doesn't depend on where they differ.
+// Only unequal lengths return early - so the length of a secret isn't secret
+func EqualSecret(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,150 @@
+package cryptopkg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// Known answers: the FIPS 180-2 examples for SHA-256, and RFC 4231's test case 2 for HMAC-SHA256
+const (
+	sumEmpty = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	sumABC   = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	hmacJefe = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+)
+
+func TestSHA256Reader(t *testing.T) {
+	tests := []struct {
+		name string
+		r    io.Reader
+		want string
+	}{
+		{"empty", strings.NewReader(""), sumEmpty},
+		{"abc", strings.NewReader("abc"), sumABC},
+		{"a byte at a time", iotest.OneByteReader(strings.NewReader("abc")), sumABC},
+	}
+	for _, tt := range tests {
+		if got, err := SHA256Reader(tt.r); err != nil || got != tt.want {
+			t.Errorf("%s: SHA256Reader = %s, %v, want %s", tt.name, got, err, tt.want)
+		}
+	}
+
+	// Larger than io.Copy's buffer: the same as one Write
+	big := bytes.Repeat([]byte("quarterly numbers\n"), 10_000)
+	whole, _ := SHA256Reader(bytes.NewReader(big))
+	halves, _ := SHA256Reader(io.MultiReader(bytes.NewReader(big[:len(big)/2]), bytes.NewReader(big[len(big)/2:])))
+	if whole != halves {
+		t.Errorf("streamed in two parts: %s, in one: %s", halves, whole)
+	}
+
+	errRead := errors.New("read failed")
+	if _, err := SHA256Reader(iotest.ErrReader(errRead)); !errors.Is(err, errRead) {
+		t.Errorf("SHA256Reader of a failing reader = %v, want %v", err, errRead)
+	}
+}
+
+func TestSHA256File(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "abc.txt")
+	if err := os.WriteFile(path, []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := SHA256File(path); err != nil || got != sumABC {
+		t.Errorf("SHA256File = %s, %v, want %s", got, err, sumABC)
+	}
+	if _, err := SHA256File(filepath.Join(dir, "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("SHA256File of a missing file = %v, want os.ErrNotExist", err)
+	}
+	if _, err := SHA256File(dir); err == nil {
+		t.Error("SHA256File of a directory succeeded")
+	}
+}
+
+func TestSign(t *testing.T) {
+	got := hex.EncodeToString(Sign([]byte("Jefe"), []byte("what do ya want for nothing?")))
+	if got != hmacJefe {
+		t.Errorf("Sign = %s, want %s", got, hmacJefe)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key := NewKey()
+	data := []byte(`{"file": "report.txt", "size": 17}`)
+	sig := Sign(key, data)
+	if !Verify(key, data, sig) {
+		t.Fatal("a signature doesn't verify")
+	}
+	tampered := bytes.Clone(sig)
+	tampered[len(tampered)-1] ^= 1
+	tests := []struct {
+		name           string
+		key, data, sig []byte
+	}{
+		{"changed data", key, []byte(`{"file": "report.txt", "size": 1700}`), sig},
+		{"another key", NewKey(), data, sig},
+		{"a flipped bit", key, data, tampered},
+		{"cut short", key, data, sig[:len(sig)-1]},
+		{"no signature", key, data, nil},
+	}
+	for _, tt := range tests {
+		if Verify(tt.key, tt.data, tt.sig) {
+			t.Errorf("%s: verifies", tt.name)
+		}
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	a, b := NewKey(), NewKey()
+	if len(a) != 32 || len(b) != 32 {
+		t.Fatalf("key lengths %d and %d, want 32", len(a), len(b))
+	}
+	if bytes.Equal(a, b) {
+		t.Error("two keys are the same")
+	}
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUUID(t *testing.T) {
+	seen := map[string]bool{}
+	for range 10_000 {
+		id := NewUUID()
+		if !uuidRe.MatchString(id) {
+			t.Fatalf("NewUUID = %q, not a version 4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewUUID repeated %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestEqualSecret(t *testing.T) {
+	const token = "v8Zq3mL2rT9wX4kP"
+	tests := []struct {
+		guess string
+		want  bool
+	}{
+		{token, true},
+		{"v8Zq3mL2rT9wX4kQ", false}, // the last byte differs
+		{"a8Zq3mL2rT9wX4kP", false}, // the first
+		{"short", false},
+		{token + "x", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := EqualSecret(tt.guess, token); got != tt.want {
+			t.Errorf("EqualSecret(%q) = %t, want %t", tt.guess, got, tt.want)
+		}
+	}
+	if !EqualSecret("", "") {
+		t.Error(`EqualSecret("", "") = false`)
+	}
+}
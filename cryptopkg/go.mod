@@ -0,0 +1,3 @@
+module cryptopkg
+
+go 1.25.0
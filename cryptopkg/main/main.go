@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"cryptopkg"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// === Ex. crypto: SHA-256 checksums, HMAC signatures, crypto/rand ids, constant-time comparison ===
+// (the checks are in cryptopkg_test.go)
+
+// basics/main/checksum.go uses the checksums to check an upload before HandleFileUpload stores it.
+
+func main() {
+	checksumExample()
+	// hmacExample()
+	// newUUIDExample()
+	// constantTimeExample()
+}
+
+func checksumExample() {
+	// Sum256 hashes a []byte at once; the same bytes streamed through a hash.Hash give the same sum
+	data := []byte("quarterly numbers")
+	sum := sha256.Sum256(data)
+	streamed, _ := cryptopkg.SHA256Reader(strings.NewReader(string(data)))
+	fmt.Println("sha256:", hex.EncodeToString(sum[:]))
+	fmt.Println("streamed:", streamed)
+
+	// One changed character changes the whole sum - there's no "close" sum for a close file
+	other := sha256.Sum256([]byte("quarterly Numbers"))
+	fmt.Println("one letter up:", hex.EncodeToString(other[:]))
+
+	// A file's: the sum sent with an upload, to check it arrived whole
+	dir, _ := os.MkdirTemp("", "cryptopkg")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "report.txt")
+	os.WriteFile(path, data, 0o644)
+	fileSum, err := cryptopkg.SHA256File(path)
+	fmt.Println("report.txt:", fileSum == streamed, err)
+	_, err = cryptopkg.SHA256File(filepath.Join(dir, "missing.txt"))
+	fmt.Println("missing file:", err != nil)
+}
+
+func hmacExample() {
+	key := cryptopkg.NewKey()
+	upload := []byte(`{"file": "report.txt", "size": 17}`)
+	sig := cryptopkg.Sign(key, upload)
+	fmt.Println("signature:", hex.EncodeToString(sig))
+	fmt.Println("verifies:", cryptopkg.Verify(key, upload, sig))
+
+	// Changed data, or the right data with another key: a plain SHA-256 anyone could recompute for the
+	// changed data - an HMAC no one can without the key
+	tampered := []byte(`{"file": "report.txt", "size": 1700}`)
+	fmt.Println("tampered data:", cryptopkg.Verify(key, tampered, sig))
+	fmt.Println("another key:", cryptopkg.Verify(cryptopkg.NewKey(), upload, sig))
+}
+
+func newUUIDExample() {
+	// Ids made when needed, instead of consts like userId1 - every one different
+	seen := map[string]bool{}
+	for range 3 {
+		id := cryptopkg.NewUUID()
+		seen[id] = true
+		fmt.Println("NewUUID:", id)
+	}
+	// (cryptopkg_test.go checks 10,000 of them are all different, and version 4)
+	for range 10_000 {
+		seen[cryptopkg.NewUUID()] = true
+	}
+	fmt.Println("10,003 ids, different ones:", len(seen))
+
+	// For a token that doesn't need the UUID form, rand.Text (Go 1.24+): 26 base32 characters, 130 bits
+	fmt.Println("rand.Text:", rand.Text())
+}
+
+func constantTimeExample() {
+	token := "v8Zq3mL2rT9wX4kP"
+
+	// Same answers as ==...
+	for _, guess := range []string{token, "v8Zq3mL2rT9wX4kQ", "a8Zq3mL2rT9wX4kP", "short"} {
+		fmt.Printf("%-18q ==: %-5t EqualSecret: %t\n", guess, guess == token, cryptopkg.EqualSecret(guess, token))
+	}
+	// ...but == on strings of the same length compares until the first difference: a guess with the
+	// right first byte takes (a little) longer to reject than one without, and over many tries, from a
+	// network away, that's measurable. An attacker then finds the token byte by byte, not all at once.
+	// EqualSecret always looks at all of them. Use it (or hmac.Equal) for a secret: a token, a password
+	// hash, an HMAC - a checksum, which isn't secret, can use ==
+}
@@ -0,0 +1,129 @@
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// The basics notes' users, kept in a SQL database through database/sql:
+//
+// - sql.Open doesn't connect - it checks the driver name and returns a *sql.DB, a pool of connections
+//   that's safe for concurrent use. Make one per database, and keep it: it's meant to live as long as
+//   the program. PingContext is what actually connects
+// - QueryRowContext is for one row: Scan copies its columns into Go variables, and no row at all is
+//   sql.ErrNoRows - from Scan, not from QueryRowContext
+// - QueryContext is for many: the *sql.Rows holds a connection until it's closed, so defer rows.Close()
+//   right after the error check, and check rows.Err() after the loop - Next returning false can mean an error
+// - ? are placeholders: the values go to the database separately from the SQL, so they can't change it
+//   (no SQL injection), and a prepared statement can be run many times with different ones
+//
+// The database here is memdb (memdriver.go), an in-memory stand-in for sqlite: the code is the same for any driver.
+
+// User is the basics notes' User
+type User struct {
+	UserId string
+	Name   string
+}
+
+// ErrNotFound is the error when there's no user with an id
+var ErrNotFound = errors.New("user not found")
+
+// Open opens the memdb database name, and creates the users table if it's new
+func Open(ctx context.Context, name string) (*sql.DB, error) {
+	db, err := sql.Open("memdb", name)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	var n int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&n); err == nil {
+		return db, nil // the table's there: another Open of the same name made it
+	}
+	if _, err := db.ExecContext(ctx, "CREATE TABLE users (user_id TEXT PRIMARY KEY, name TEXT NOT NULL)"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// AddUser inserts u
+func AddUser(ctx context.Context, db *sql.DB, u User) error {
+	_, err := db.ExecContext(ctx, "INSERT INTO users (user_id, name) VALUES (?, ?)", u.UserId, u.Name)
+	if err != nil {
+		return fmt.Errorf("adding user %s: %w", u.UserId, err)
+	}
+	return nil
+}
+
+// GetUser is the user with id, or an error matching ErrNotFound
+func GetUser(ctx context.Context, db *sql.DB, id string) (User, error) {
+	var u User
+	err := db.QueryRowContext(ctx, "SELECT user_id, name FROM users WHERE user_id = ?", id).Scan(&u.UserId, &u.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("user %s: %w", id, ErrNotFound)
+	}
+	return u, err
+}
+
+// ListUsers is every user, ordered by name
+func ListUsers(ctx context.Context, db *sql.DB) ([]User, error) {
+	rows, err := db.QueryContext(ctx, "SELECT user_id, name FROM users ORDER BY name, user_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() // gives the connection back to the pool, also on an early return
+	var users []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.UserId, &u.Name); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// AddUsers inserts all of users in one transaction, with one prepared statement: all of them, or -
+// if any fails - none. The deferred Rollback undoes everything on an early return, and does nothing
+// (sql.ErrTxDone, ignored) after a Commit
+func AddUsers(ctx context.Context, db *sql.DB, users []User) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Prepared once, run once per user. A statement of a Tx belongs to it - closed at Commit or Rollback,
+	// but closing it here says so
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO users (user_id, name) VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, u := range users {
+		if _, err := stmt.ExecContext(ctx, u.UserId, u.Name); err != nil {
+			return fmt.Errorf("adding user %s: %w", u.UserId, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Rename changes the name of the user with id, or returns an error matching ErrNotFound
+func Rename(ctx context.Context, db *sql.DB, id, name string) error {
+	res, err := db.ExecContext(ctx, "UPDATE users SET name = ? WHERE user_id = ?", name, id)
+	if err != nil {
+		return err
+	}
+	// An UPDATE that matches no row isn't an error to SQL - RowsAffected says whether it did anything
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("user %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
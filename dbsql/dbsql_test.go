@@ -0,0 +1,322 @@
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// open opens a database of its own for the test, closed at the end of it
+func open(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(context.Background(), t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func count(t *testing.T, q interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}) int {
+	t.Helper()
+	var n int
+	if err := q.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM users").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestOpenTwice(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	if err := AddUser(ctx, db, User{"a", "Ada"}); err != nil {
+		t.Fatal(err)
+	}
+	// The same name is the same database: the table isn't made again, and the user's there
+	again, err := Open(ctx, t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if u, err := GetUser(ctx, again, "a"); err != nil || u.Name != "Ada" {
+		t.Errorf("GetUser from the second Open = %+v, %v", u, err)
+	}
+}
+
+func TestAddGet(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	want := User{"1d02455e-f24c-4c26-90d2-f1073c686314", "John Doe"}
+	if err := AddUser(ctx, db, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetUser(ctx, db, want.UserId); err != nil || got != want {
+		t.Errorf("GetUser = %+v, %v, want %+v", got, err, want)
+	}
+
+	err := AddUser(ctx, db, User{want.UserId, "John Again"})
+	if !errors.Is(err, ErrConstraint) {
+		t.Errorf("AddUser of a used id = %v, want ErrConstraint", err)
+	}
+	if got, _ := GetUser(ctx, db, want.UserId); got != want {
+		t.Errorf("after the failed AddUser: %+v, want %+v", got, want)
+	}
+
+	_, err = GetUser(ctx, db, "no-such-id")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUser of a missing id = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	if users, err := ListUsers(ctx, db); err != nil || len(users) != 0 {
+		t.Errorf("ListUsers of an empty table = %v, %v", users, err)
+	}
+	for _, u := range []User{{"3", "Cy"}, {"2", "Ada"}, {"1", "Bob"}, {"0", "Ada"}} {
+		if err := AddUser(ctx, db, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []User{{"0", "Ada"}, {"2", "Ada"}, {"1", "Bob"}, {"3", "Cy"}}
+	if got, err := ListUsers(ctx, db); err != nil || !slices.Equal(got, want) {
+		t.Errorf("ListUsers = %v, %v, want %v (by name, then id)", got, err, want)
+	}
+}
+
+// A value for a placeholder is stored as it is, never run
+func TestPlaceholderNotSQL(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	evil := "x'); DROP TABLE users; --"
+	if err := AddUser(ctx, db, User{"evil", evil}); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := GetUser(ctx, db, "evil"); err != nil || u.Name != evil {
+		t.Errorf("GetUser = %+v, %v", u, err)
+	}
+	if n := count(t, db); n != 1 {
+		t.Errorf("%d users, want 1", n)
+	}
+}
+
+func TestScanConverts(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	AddUsers(ctx, db, []User{{"a", "Ada"}, {"b", "Bob"}})
+	var n int
+	var s string
+	row := "SELECT COUNT(*) FROM users"
+	if err := db.QueryRowContext(ctx, row).Scan(&n); err != nil || n != 2 {
+		t.Errorf("COUNT(*) into an int = %d, %v", n, err)
+	}
+	if err := db.QueryRowContext(ctx, row).Scan(&s); err != nil || s != "2" {
+		t.Errorf("COUNT(*) into a string = %q, %v", s, err)
+	}
+}
+
+func TestBadStatements(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	if _, err := db.ExecContext(ctx, "SELEKT * FROM users"); err == nil {
+		t.Error("unknown SQL ran")
+	}
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (user_id, name) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if _, err := stmt.ExecContext(ctx, "one arg"); err == nil {
+		t.Error("one arg for two placeholders ran")
+	}
+	if _, err := db.ExecContext(ctx, "CREATE TABLE users (user_id TEXT PRIMARY KEY, name TEXT NOT NULL)"); err == nil {
+		t.Error("CREATE TABLE of an existing table succeeded")
+	}
+}
+
+func TestPrepared(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (user_id, name) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	for i, name := range []string{"Ada Lovelace", "Grace Hopper", "Ken Thompson"} {
+		res, err := stmt.ExecContext(ctx, fmt.Sprint("id-", i), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n != 1 {
+			t.Errorf("RowsAffected = %d, %v, want 1", n, err)
+		}
+	}
+	if n := count(t, db); n != 3 {
+		t.Errorf("%d users, want 3", n)
+	}
+}
+
+func TestAddUsers(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	if err := AddUsers(ctx, db, []User{{"a", "Ada"}, {"b", "Bob"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddUsers(ctx, db, nil); err != nil {
+		t.Errorf("AddUsers(nil) = %v", err)
+	}
+
+	// b is taken: c, before it, is rolled back too
+	err := AddUsers(ctx, db, []User{{"c", "Cy"}, {"b", "Bob Again"}})
+	if !errors.Is(err, ErrConstraint) {
+		t.Errorf("AddUsers with a used id = %v, want ErrConstraint", err)
+	}
+	if _, err := GetUser(ctx, db, "c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("c after the rollback: %v, want ErrNotFound", err)
+	}
+	if u, _ := GetUser(ctx, db, "b"); u.Name != "Bob" {
+		t.Errorf("b = %+v, want the first Bob", u)
+	}
+	// A duplicate within the same batch
+	if err := AddUsers(ctx, db, []User{{"d", "Di"}, {"d", "Di Again"}}); !errors.Is(err, ErrConstraint) {
+		t.Errorf("AddUsers with a repeated id = %v, want ErrConstraint", err)
+	}
+	if n := count(t, db); n != 2 {
+		t.Errorf("%d users, want 2", n)
+	}
+}
+
+func TestTransactionIsolation(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	AddUsers(ctx, db, []User{{"a", "Ada"}, {"b", "Bob"}})
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users WHERE user_id = ?", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if inTx, outside := count(t, tx), count(t, db); inTx != 1 || outside != 2 {
+		t.Errorf("after DELETE: %d in the tx, %d outside, want 1 and 2", inTx, outside)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(t, db); n != 2 {
+		t.Errorf("after Rollback: %d users, want 2", n)
+	}
+	if err := tx.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("Commit after Rollback = %v, want sql.ErrTxDone", err)
+	}
+
+	tx, _ = db.BeginTx(ctx, nil)
+	tx.ExecContext(ctx, "DELETE FROM users WHERE user_id = ?", "a")
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetUser(ctx, db, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("a after the Commit: %v, want ErrNotFound", err)
+	}
+}
+
+func TestRename(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	AddUser(ctx, db, User{"a", "Ada"})
+	if err := Rename(ctx, db, "a", "Ada Lovelace"); err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := GetUser(ctx, db, "a"); u.Name != "Ada Lovelace" {
+		t.Errorf("after Rename: %+v", u)
+	}
+	if err := Rename(ctx, db, "zz", "Nobody"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Rename of a missing user = %v, want ErrNotFound", err)
+	}
+}
+
+func TestContextDone(t *testing.T) {
+	db := open(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	if _, err := GetUser(ctx, db, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetUser with an expired ctx = %v, want context.DeadlineExceeded", err)
+	}
+	if err := AddUsers(ctx, db, []User{{"a", "Ada"}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("AddUsers with an expired ctx = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// Rows keep their connection until closed: with a pool of one, the next query waits for it
+func TestRowsHoldConnection(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	AddUsers(ctx, db, []User{{"a", "Ada"}, {"b", "Bob"}})
+	db.SetMaxOpenConns(1)
+
+	rows, err := db.QueryContext(ctx, "SELECT user_id, name FROM users ORDER BY name, user_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Next()
+	if n := db.Stats().InUse; n != 1 {
+		t.Errorf("with rows open: %d in use, want 1", n)
+	}
+	wait, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := GetUser(wait, db, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("a query while rows hold the only connection = %v, want context.DeadlineExceeded", err)
+	}
+	rows.Close()
+	if _, err := GetUser(ctx, db, "a"); err != nil {
+		t.Errorf("after rows.Close: %v", err)
+	}
+
+	// None of the package's functions keep one, whatever they return
+	for range 3 {
+		ListUsers(ctx, db)
+		GetUser(ctx, db, "missing")
+		AddUsers(ctx, db, []User{{"a", "dup"}})
+	}
+	if n := db.Stats().InUse; n != 0 {
+		t.Errorf("%d connections still in use", n)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	ctx := context.Background()
+	db := open(t)
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			id := fmt.Sprint("u", i)
+			if err := AddUser(ctx, db, User{id, "User"}); err != nil {
+				t.Error(err)
+			}
+			if _, err := GetUser(ctx, db, id); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+	if n := count(t, db); n != 50 {
+		t.Errorf("%d users, want 50", n)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	db := open(t)
+	db.Close()
+	if _, err := GetUser(context.Background(), db, "a"); err == nil {
+		t.Error("GetUser after Close succeeded")
+	}
+}
@@ -0,0 +1,3 @@
+module dbsql
+
+go 1.25.0
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"dbsql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// === Ex. database/sql: Open, QueryRowContext, prepared statements, Scan, transactions, Close ===
+// (the checks are in dbsql_test.go)
+
+const userId1, userId2 = "1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func main() {
+	queryExample()
+	// preparedExample()
+	// transactionExample()
+	// closeExample()
+}
+
+// open opens a fresh database for an example - each its own name, so they don't see each other's users
+func open(name string) *sql.DB {
+	db, err := dbsql.Open(context.Background(), name)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+func queryExample() {
+	ctx := context.Background()
+	db := open("query")
+	defer db.Close() // closes the pool's connections; once, when the program's done with the database
+
+	fmt.Println(dbsql.AddUser(ctx, db, dbsql.User{UserId: userId1, Name: "John Doe"}),
+		dbsql.AddUser(ctx, db, dbsql.User{UserId: userId2, Name: "Jack Eod"}))
+	err := dbsql.AddUser(ctx, db, dbsql.User{UserId: userId1, Name: "John Again"})
+	fmt.Println("the same id again:", err)
+
+	u, err := dbsql.GetUser(ctx, db, userId1)
+	fmt.Printf("GetUser: %+v, err %v\n", u, err)
+	_, err = dbsql.GetUser(ctx, db, "no-such-id")
+	fmt.Println("missing:", err, "- is ErrNotFound:", errors.Is(err, dbsql.ErrNotFound))
+
+	users, err := dbsql.ListUsers(ctx, db)
+	fmt.Printf("ListUsers, by name: %+v, err %v\n", users, err)
+
+	// Scan converts: the count is an int64 from the driver, and goes into an int (or a string, or...)
+	var n int
+	var s string
+	db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&n)
+	db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&s)
+	fmt.Printf("COUNT(*) into an int: %d, into a string: %q\n", n, s)
+
+	// Every ...Context method gives up when ctx is done - a query never outlives its request
+	expired, cancel := context.WithTimeout(ctx, 0)
+	defer cancel()
+	_, err = dbsql.GetUser(expired, db, userId1)
+	fmt.Println("an expired ctx:", err)
+}
+
+func preparedExample() {
+	ctx := context.Background()
+	db := open("prepared")
+	defer db.Close()
+
+	// Prepared once on the DB: database/sql prepares it again on each pool connection it gets run on
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (user_id, name) VALUES (?, ?)")
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+	for i, name := range []string{"Ada Lovelace", "Grace Hopper", "Ken Thompson"} {
+		res, err := stmt.ExecContext(ctx, fmt.Sprint("id-", i), name)
+		n, _ := res.RowsAffected()
+		fmt.Println("inserted", name, "- rows affected:", n, err)
+	}
+
+	// A placeholder's value is only ever a value: this name is stored as it is, not run
+	evil := "x'); DROP TABLE users; --"
+	stmt.ExecContext(ctx, "id-evil", evil)
+	u, _ := dbsql.GetUser(ctx, db, "id-evil")
+	users, err := dbsql.ListUsers(ctx, db)
+	fmt.Printf("stored the name %q - the table's still there: %d users, err %v\n", u.Name, len(users), err)
+
+	// database/sql checks the number of args against the statement's placeholders
+	_, err = stmt.ExecContext(ctx, "id-9")
+	fmt.Println("one arg for two ?:", err)
+	// ...and the driver rejects SQL it can't run
+	_, err = db.ExecContext(ctx, "SELEKT * FROM users")
+	fmt.Println("bad SQL:", err)
+}
+
+func transactionExample() {
+	ctx := context.Background()
+	db := open("tx")
+	defer db.Close()
+	count := func() (n int) {
+		db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&n)
+		return n
+	}
+
+	err := dbsql.AddUsers(ctx, db, []dbsql.User{{UserId: "a", Name: "Ada"}, {UserId: "b", Name: "Bob"}})
+	fmt.Println("AddUsers a, b:", err, "| users:", count())
+
+	// c is new, b is taken: the error rolls the whole transaction back - c isn't added either
+	err = dbsql.AddUsers(ctx, db, []dbsql.User{{UserId: "c", Name: "Cy"}, {UserId: "b", Name: "Bob Again"}})
+	fmt.Println("AddUsers c, b:", err)
+	_, errC := dbsql.GetUser(ctx, db, "c")
+	fmt.Println("users:", count(), "- c:", errC)
+
+	// Inside a transaction, its own changes are visible to it - and to nothing else until Commit
+	tx, _ := db.BeginTx(ctx, nil)
+	tx.ExecContext(ctx, "DELETE FROM users WHERE user_id = ?", "a")
+	var inTx int
+	tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&inTx)
+	fmt.Println("after DELETE, in the tx:", inTx, "| outside it:", count())
+	fmt.Println("Rollback:", tx.Rollback(), "| users:", count())
+	fmt.Println("a Tx is done after Rollback:", tx.Commit())
+
+	err = dbsql.Rename(ctx, db, "a", "Ada Lovelace")
+	u, _ := dbsql.GetUser(ctx, db, "a")
+	fmt.Println("Rename:", err, u.Name, "| a missing user:", dbsql.Rename(ctx, db, "zz", "Nobody"))
+}
+
+func closeExample() {
+	ctx := context.Background()
+	db := open("close")
+	defer db.Close()
+	dbsql.AddUsers(ctx, db, []dbsql.User{{UserId: "a", Name: "Ada"}, {UserId: "b", Name: "Bob"}})
+	db.SetMaxOpenConns(1) // a pool of one, so a connection that isn't given back shows at once
+
+	// Rows hold their connection until Close - or until Next has returned false
+	rows, _ := db.QueryContext(ctx, "SELECT user_id, name FROM users ORDER BY name, user_id")
+	rows.Next() // read one row of two, then forget about rows
+	fmt.Println("rows not closed - connections in use:", db.Stats().InUse)
+
+	// So the next query waits for a free connection - here, until its ctx gives up
+	wait, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err := dbsql.GetUser(wait, db, "a")
+	fmt.Println("the next query:", err)
+
+	rows.Close()
+	fmt.Println("after rows.Close - in use:", db.Stats().InUse)
+	u, err := dbsql.GetUser(ctx, db, "a")
+	fmt.Println("the next query:", u, err)
+
+	// ListUsers defers rows.Close, so it never leaks one, whatever it returns
+	for range 3 {
+		dbsql.ListUsers(ctx, db)
+	}
+	s := db.Stats()
+	fmt.Printf("3 ListUsers later - in use: %d, idle: %d\n", s.InUse, s.Idle)
+
+	// After Close the pool is gone
+	db.Close()
+	_, err = dbsql.GetUser(ctx, db, "a")
+	fmt.Println("after db.Close:", err)
+}
@@ -0,0 +1,232 @@
+package dbsql
+
+import (
+	"cmp"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// memdb is a database/sql driver for an in-memory users table, standing in for a real database
+// (a real one is an import away: _ "modernc.org/sqlite", then sql.Open("sqlite", ":memory:")).
+// It knows only the statements in queries, word for word, so it's small enough to read - and it shows
+// what database/sql asks of a driver: a Conn that prepares a Stmt, Rows that fill dest one row at a
+// time, and a Tx to Commit or Rollback. Everything else - the connection pool, turning args into
+// driver.Values, Scan converting them into Go variables - is database/sql's.
+//
+// Databases are by name: every sql.Open("memdb", name) with the same name shares one, until the program exits.
+// A transaction works on its own copy of the table and Commit replaces the table with it - so there's
+// no isolation between two concurrent transactions, the last Commit wins.
+
+func init() {
+	sql.Register("memdb", &memDriver{dbs: map[string]*memDB{}})
+}
+
+// ErrConstraint is the error of an INSERT with a user_id that's already used
+var ErrConstraint = errors.New("memdb: UNIQUE constraint failed: users.user_id")
+
+type memDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*memDB
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	db, ok := d.dbs[name]
+	if !ok {
+		db = &memDB{}
+		d.dbs[name] = db
+	}
+	return &memConn{db: db}, nil
+}
+
+type row struct{ id, name string }
+
+type memDB struct {
+	mu    sync.Mutex
+	table bool // false until CREATE TABLE
+	users []row
+}
+
+// query is a statement the driver knows: how many ? it has, and what it does with the table
+type query struct {
+	args int
+	exec func(t *[]row, args []driver.Value) (rowsAffected int64, err error)
+	// query statements return columns and rows instead
+	columns []string
+	query   func(t []row, args []driver.Value) [][]driver.Value
+}
+
+var queries = map[string]query{
+	"CREATE TABLE users (user_id TEXT PRIMARY KEY, name TEXT NOT NULL)": {},
+	"INSERT INTO users (user_id, name) VALUES (?, ?)": {args: 2, exec: func(t *[]row, args []driver.Value) (int64, error) {
+		id, name := args[0].(string), args[1].(string)
+		if slices.ContainsFunc(*t, func(r row) bool { return r.id == id }) {
+			return 0, ErrConstraint
+		}
+		*t = append(*t, row{id, name})
+		return 1, nil
+	}},
+	"UPDATE users SET name = ? WHERE user_id = ?": {args: 2, exec: func(t *[]row, args []driver.Value) (int64, error) {
+		for i := range *t {
+			if (*t)[i].id == args[1] {
+				(*t)[i].name = args[0].(string)
+				return 1, nil
+			}
+		}
+		return 0, nil
+	}},
+	"DELETE FROM users WHERE user_id = ?": {args: 1, exec: func(t *[]row, args []driver.Value) (int64, error) {
+		n := len(*t)
+		*t = slices.DeleteFunc(*t, func(r row) bool { return r.id == args[0] })
+		return int64(n - len(*t)), nil
+	}},
+	"SELECT user_id, name FROM users WHERE user_id = ?": {args: 1, columns: []string{"user_id", "name"},
+		query: func(t []row, args []driver.Value) (out [][]driver.Value) {
+			for _, r := range t {
+				if r.id == args[0] {
+					out = append(out, []driver.Value{r.id, r.name})
+				}
+			}
+			return out
+		}},
+	"SELECT user_id, name FROM users ORDER BY name, user_id": {columns: []string{"user_id", "name"},
+		query: func(t []row, args []driver.Value) (out [][]driver.Value) {
+			sorted := slices.SortedFunc(slices.Values(t), func(a, b row) int {
+				return cmp.Or(strings.Compare(a.name, b.name), strings.Compare(a.id, b.id))
+			})
+			for _, r := range sorted {
+				out = append(out, []driver.Value{r.id, r.name})
+			}
+			return out
+		}},
+	"SELECT COUNT(*) FROM users": {columns: []string{"COUNT(*)"},
+		query: func(t []row, args []driver.Value) [][]driver.Value {
+			return [][]driver.Value{{int64(len(t))}}
+		}},
+}
+
+// memConn is one connection. database/sql uses a connection from one goroutine at a time
+type memConn struct {
+	db *memDB
+	tx *[]row // the transaction's copy of the table, while one is open
+}
+
+func (c *memConn) Prepare(text string) (driver.Stmt, error) {
+	q, ok := queries[strings.Join(strings.Fields(text), " ")]
+	if !ok {
+		return nil, fmt.Errorf("memdb: unsupported statement %q", text)
+	}
+	return &memStmt{c: c, q: q}, nil
+}
+
+func (c *memConn) Close() error { return nil }
+
+func (c *memConn) Begin() (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("memdb: a transaction is already open")
+	}
+	c.db.mu.Lock()
+	users := slices.Clone(c.db.users)
+	c.db.mu.Unlock()
+	c.tx = &users
+	return c, nil
+}
+
+// memConn is its own driver.Tx
+func (c *memConn) Commit() error {
+	c.db.mu.Lock()
+	c.db.users = *c.tx
+	c.db.mu.Unlock()
+	c.tx = nil
+	return nil
+}
+
+func (c *memConn) Rollback() error {
+	c.tx = nil
+	return nil
+}
+
+// table runs f on the transaction's table if one is open, else on the database's, under its lock
+func (c *memConn) table(f func(t *[]row) error) error {
+	if c.tx != nil {
+		return f(c.tx)
+	}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	return f(&c.db.users)
+}
+
+type memStmt struct {
+	c *memConn
+	q query
+}
+
+func (s *memStmt) Close() error  { return nil }
+func (s *memStmt) NumInput() int { return s.q.args } // database/sql checks the number of args against it
+
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.q.exec == nil && s.q.query == nil { // CREATE TABLE
+		s.c.db.mu.Lock()
+		defer s.c.db.mu.Unlock()
+		if s.c.db.table {
+			return nil, errors.New("memdb: table users already exists")
+		}
+		s.c.db.table = true
+		return driver.RowsAffected(0), nil
+	}
+	if s.q.exec == nil {
+		return nil, errors.New("memdb: Exec of a query - use Query")
+	}
+	var n int64
+	err := s.c.table(func(t *[]row) (err error) {
+		if !s.c.db.table {
+			return errors.New("memdb: no such table: users")
+		}
+		n, err = s.q.exec(t, args)
+		return err
+	})
+	return driver.RowsAffected(n), err
+}
+
+func (s *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.q.query == nil {
+		return nil, errors.New("memdb: Query of a statement that returns no rows - use Exec")
+	}
+	var out [][]driver.Value
+	err := s.c.table(func(t *[]row) error {
+		if !s.c.db.table {
+			return errors.New("memdb: no such table: users")
+		}
+		out = s.q.query(*t, args)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &memRows{columns: s.q.columns, rows: out}, nil
+}
+
+type memRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *memRows) Columns() []string { return r.columns }
+func (r *memRows) Close() error      { return nil }
+
+// Next fills dest with the next row; io.EOF says there are no more
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,58 @@
+package embedpkg
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// //go:embed puts files into the binary at build time, into the variable under it:
+// - a string or []byte: the one file named
+// - an embed.FS: a read-only file tree of everything the patterns match, with the same paths
+// Paths are relative to the package's directory and can't reach outside it (no ..) - so the data sits
+// next to the code. The directive needs the embed package imported (a blank import for a string or []byte).
+//
+// A directory pattern leaves out names starting with . or _ (.git, _drafts) - "all:" before it keeps them.
+// The files are read when the program is built, so changing one means building again.
+
+// Welcome is data/welcome.txt, as a string
+//
+//go:embed data/welcome.txt
+var Welcome string
+
+// Samples is data/samples and everything in it, except the hidden .draft.md
+//
+//go:embed data/samples
+var Samples embed.FS
+
+// AllSamples is the same directory with all:, so .draft.md is in it
+//
+//go:embed all:data/samples
+var AllSamples embed.FS
+
+// SampleFS is Samples with the data/samples/ prefix taken off - "users.csv" rather than "data/samples/users.csv"
+func SampleFS() fs.FS {
+	sub, err := fs.Sub(Samples, "data/samples")
+	if err != nil {
+		panic(err) // only a malformed path errs, and this one is a constant
+	}
+	return sub
+}
+
+// Handler serves the embedded files, routed the way the httpserver example routes its API:
+//
+//	GET /welcome    -> the welcome text
+//	GET /samples/   -> the sample files (a directory gets a listing)
+//
+// It's an http.Handler like any other, so it can be mounted on another mux: mux.Handle("/static/", http.StripPrefix("/static", h))
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /welcome", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(Welcome))
+	})
+	// FileServerFS serves any fs.FS - the embedded one here, os.DirFS(dir) for files on disk.
+	// An embed.FS has no modification times, so responses have no Last-Modified
+	mux.Handle("GET /samples/", http.StripPrefix("/samples", http.FileServerFS(SampleFS())))
+	return mux
+}
@@ -0,0 +1,3 @@
+module embedpkg
+
+go 1.25.0
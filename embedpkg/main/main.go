@@ -0,0 +1,122 @@
+package main
+
+import (
+	_ "embed"
+	"embedpkg"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+)
+
+// === Ex. //go:embed: a file as a string, a directory as an fs.FS, served over HTTP ===
+
+// tour/quiz embeds its question bank the same way - questions/*.json into an embed.FS, read with fs.Glob
+// and fs.ReadFile - so `tour quiz` needs only its binary.
+
+func main() {
+	embedFileExample()
+	// embedFSExample()
+	// serveEmbedExample()
+}
+
+// A directive works in any package, main too - for a file in the package's own directory.
+// Only a []byte is used here, so the embed package is imported blank
+//
+//go:embed hello.txt
+var hello []byte
+
+func embedFileExample() {
+	fmt.Print("Welcome (a string):\n", embedpkg.Welcome)
+	fmt.Printf("hello.txt (a []byte, %d bytes): %q\n", len(hello), hello)
+
+	// A string or []byte is just a variable holding the contents - changing the []byte changes only this copy
+	first := hello[0]
+	hello[0] = 'J'
+	fmt.Printf("the []byte can be changed in memory: %q | the next build embeds the file as it is again\n", hello[:5])
+	hello[0] = first
+}
+
+func embedFSExample() {
+	// An embed.FS is an fs.FS: the io/fs functions work on it, as on os.DirFS
+	var names []string
+	fs.WalkDir(embedpkg.Samples, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			names = append(names, path)
+		}
+		return nil
+	})
+	fmt.Println("embedded:", names)
+
+	var all []string
+	fs.WalkDir(embedpkg.AllSamples, ".", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			all = append(all, path)
+		}
+		return err
+	})
+	fmt.Println("with all:", slices.Contains(all, "data/samples/.draft.md"), "| without:", !slices.Contains(names, "data/samples/.draft.md"))
+
+	// fs.Sub drops the prefix; ReadFile, Glob and Open take slash-separated paths, on every OS
+	samples := embedpkg.SampleFS()
+	data, err := fs.ReadFile(samples, "users.csv")
+	fmt.Printf("users.csv: %d bytes, err %v\n", len(data), err)
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	fmt.Println("parsed:", rows[1:], err)
+
+	md, _ := fs.Glob(samples, "notes/*.md")
+	fmt.Println("Glob notes/*.md:", md)
+
+	// Read-only: there's no way to write, and Open of a missing file is fs.ErrNotExist like on disk
+	_, err = samples.Open("users.xml")
+	fmt.Println("missing file:", err, "| is fs.ErrNotExist:", errors.Is(err, fs.ErrNotExist))
+	f, _ := embedpkg.Samples.Open("data/samples/users.csv")
+	_, isWriter := f.(io.Writer)
+	fmt.Println("an embedded file is an io.Writer:", isWriter)
+
+	// Stat works, but there are no modification times (the build would otherwise differ by when it ran)
+	info, _ := fs.Stat(samples, "users.json")
+	fmt.Println("users.json:", info.Size(), "bytes, modified", info.ModTime())
+}
+
+func serveEmbedExample() {
+	srv := httptest.NewServer(embedpkg.Handler())
+	defer srv.Close()
+
+	get := func(path string) (int, string, string) {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			return 0, "", err.Error()
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return res.StatusCode, res.Header.Get("Content-Type"), string(body)
+	}
+
+	for _, path := range []string{"/welcome", "/samples/users.json", "/samples/notes/channels.md", "/samples/", "/samples/.draft.md", "/samples/../go.mod"} {
+		status, ctype, body := get(path)
+		first, _, _ := strings.Cut(body, "\n")
+		fmt.Printf("GET %-28s %d %-26s %q\n", path, status, ctype, first)
+	}
+
+	// The checks: what's embedded is served, what isn't is a 404, and nothing outside data/samples is reachable
+	welcome, _, body := get("/welcome")
+	draft, _, _ := get("/samples/.draft.md")
+	escape, _, _ := get("/samples/../go.mod")
+	fmt.Println("| ok:", welcome == 200 && body == embedpkg.Welcome && draft == 404 && escape == 404)
+
+	// Mounted under a prefix in another mux - as the httpserver example's Server could be
+	mux := http.NewServeMux()
+	mux.Handle("/static/", http.StripPrefix("/static", embedpkg.Handler()))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest("GET", "/static/samples/users.csv", nil))
+	fmt.Println("under /static:", rec.Code, strings.SplitN(rec.Body.String(), "\n", 2)[0])
+}
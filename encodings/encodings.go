@@ -0,0 +1,105 @@
+package encodings
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Ways to turn values into bytes, besides JSON (see jsonpkg):
+//
+// - base64 and hex turn any bytes into printable text, for where only text goes (a URL, a header, JSON).
+//   hex doubles the size; base64 adds a third
+// - gob is Go's own binary format for Go values, and only Go reads it. A stream describes each type once,
+//   then sends values of it without field names - so past the first few values it's smaller than JSON.
+//   For Go programs talking to each other
+// - encoding/binary writes numbers in a fixed number of bytes, in a chosen byte order - for file
+//   formats and network protocols with a layout decided in advance
+//   (the framing module uses it for a length-prefixed header, with gob or JSON payloads, over a net.Conn)
+
+// User is the basics notes' User
+type User struct {
+	UserId string
+	Name   string
+	admin  bool // unexported: gob (like json) skips it
+}
+
+// EncodeUsers writes users to w as one gob stream
+func EncodeUsers(w io.Writer, users []User) error {
+	enc := gob.NewEncoder(w)
+	for _, u := range users {
+		if err := enc.Encode(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeUsers reads the users of a gob stream from r, until it ends
+func DecodeUsers(r io.Reader) ([]User, error) {
+	dec := gob.NewDecoder(r)
+	var users []User
+	for {
+		var u User
+		err := dec.Decode(&u)
+		if errors.Is(err, io.EOF) {
+			return users, nil
+		}
+		if err != nil {
+			return users, err
+		}
+		users = append(users, u)
+	}
+}
+
+// Header is a fixed-layout file header: 20 bytes, big-endian ("network order"), always the same
+// size - so it can be read with binary.Read, or at known offsets. Every field has a fixed size:
+// binary.Write can't write an int or a string, only sized numbers, and arrays and structs of them
+type Header struct {
+	Magic   [4]byte // identifies the format: files start with these bytes
+	Version uint16
+	Flags   uint16
+	Count   uint32
+	Created int64 // Unix seconds
+}
+
+// Magic is what Header.Magic must be
+var Magic = [4]byte{'N', 'O', 'T', 'E'}
+
+// HeaderSize is the bytes a Header takes - binary.Size counts them from the type
+var HeaderSize = binary.Size(Header{})
+
+// MarshalBinary encodes h (it implements encoding.BinaryMarshaler)
+func (h Header) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := binary.Write(&buf, binary.BigEndian, h)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a Header, checking its magic bytes
+func (h *Header) UnmarshalBinary(data []byte) error {
+	if len(data) < HeaderSize {
+		return fmt.Errorf("header: %d bytes, need %d", len(data), HeaderSize)
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.BigEndian, h); err != nil {
+		return err
+	}
+	if h.Magic != Magic {
+		return fmt.Errorf("header: magic %q, not %q", h.Magic[:], Magic[:])
+	}
+	return nil
+}
+
+// AppendHeader is MarshalBinary by hand, with the byte order's Append functions - no reflection,
+// and no allocation when b has room. The offsets are the layout
+func AppendHeader(b []byte, h Header) []byte {
+	b = append(b, h.Magic[:]...)                            // 0..4
+	b = binary.BigEndian.AppendUint16(b, h.Version)         // 4..6
+	b = binary.BigEndian.AppendUint16(b, h.Flags)           // 6..8
+	b = binary.BigEndian.AppendUint32(b, h.Count)           // 8..12
+	b = binary.BigEndian.AppendUint64(b, uint64(h.Created)) // 12..20
+	return b
+}
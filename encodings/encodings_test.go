@@ -0,0 +1,250 @@
+package encodings
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+)
+
+const userId1, userId2 = "1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"
+
+var (
+	_ encoding.BinaryMarshaler   = Header{}
+	_ encoding.BinaryUnmarshaler = (*Header)(nil)
+)
+
+// testData is every byte value, so each encoding meets its awkward characters
+func testData() [][]byte {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	return [][]byte{nil, {0}, []byte("a"), []byte("ab"), []byte("abc"), []byte("hello, gophers?>"), all}
+}
+
+func TestHexRoundTrip(t *testing.T) {
+	for _, data := range testData() {
+		s := hex.EncodeToString(data)
+		if len(s) != 2*len(data) {
+			t.Errorf("hex of %d bytes is %d characters", len(data), len(s))
+		}
+		if back, err := hex.DecodeString(s); err != nil || !bytes.Equal(back, data) {
+			t.Errorf("hex round trip of %q = %q, %v", data, back, err)
+		}
+	}
+	for _, bad := range []string{"4g", "abc"} {
+		if _, err := hex.DecodeString(bad); err == nil {
+			t.Errorf("hex.DecodeString(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestBase64RoundTrip(t *testing.T) {
+	encs := map[string]*base64.Encoding{
+		"Std":    base64.StdEncoding,
+		"URL":    base64.URLEncoding,
+		"RawStd": base64.RawStdEncoding,
+		"RawURL": base64.RawURLEncoding,
+	}
+	for name, enc := range encs {
+		for _, data := range testData() {
+			s := enc.EncodeToString(data)
+			if len(s) != enc.EncodedLen(len(data)) {
+				t.Errorf("%s: %d characters, EncodedLen says %d", name, len(s), enc.EncodedLen(len(data)))
+			}
+			if back, err := enc.DecodeString(s); err != nil || !bytes.Equal(back, data) {
+				t.Errorf("%s: round trip of %q = %q, %v", name, data, back, err)
+			}
+		}
+	}
+	// \xfb\xff is "+/8" in Std and "-_8" in URL: decoding with the other one fails
+	data := []byte{0xfb, 0xff}
+	if _, err := base64.StdEncoding.DecodeString(base64.RawURLEncoding.EncodeToString(data)); err == nil {
+		t.Error("RawURL decoded as Std")
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(base64.StdEncoding.EncodeToString([]byte("a"))); err == nil {
+		t.Error("padded Std decoded as RawURL")
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	tests := [][]User{
+		nil,
+		{{UserId: userId1, Name: "John Doe"}},
+		{{UserId: userId1, Name: "John Doe"}, {UserId: userId2, Name: "Jack Eod"}},
+		{{UserId: "", Name: ""}, {UserId: "x", Name: "日本語"}},
+		slices.Repeat([]User{{UserId: userId1, Name: "John Doe"}}, 100),
+	}
+	for _, users := range tests {
+		var buf bytes.Buffer
+		if err := EncodeUsers(&buf, users); err != nil {
+			t.Fatal(err)
+		}
+		got, err := DecodeUsers(&buf)
+		if err != nil || !slices.Equal(got, users) {
+			t.Errorf("round trip of %d users = %d users, %v", len(users), len(got), err)
+		}
+	}
+}
+
+func TestGobSkipsUnexported(t *testing.T) {
+	var buf bytes.Buffer
+	EncodeUsers(&buf, []User{{UserId: "a", Name: "Ada", admin: true}})
+	got, err := DecodeUsers(&buf)
+	if err != nil || len(got) != 1 || got[0].admin {
+		t.Errorf("DecodeUsers = %+v, %v, want admin false", got, err)
+	}
+}
+
+// The type is described once per stream: the first user costs more than each one after it, and those
+// cost the same
+func TestGobDescribesTypeOnce(t *testing.T) {
+	size := func(n int) int {
+		var buf bytes.Buffer
+		EncodeUsers(&buf, slices.Repeat([]User{{UserId: userId1, Name: "John Doe"}}, n))
+		return buf.Len()
+	}
+	one, two := size(1), size(2)
+	perUser := two - one
+	if perUser >= one {
+		t.Errorf("the first user is %d bytes, each more %d: the type description is missing from the first", one, perUser)
+	}
+	if hundred := size(100); hundred != one+99*perUser {
+		t.Errorf("100 users: %d bytes, want %d", hundred, one+99*perUser)
+	}
+}
+
+func TestGobFieldsByName(t *testing.T) {
+	var buf bytes.Buffer
+	EncodeUsers(&buf, []User{{UserId: userId1, Name: "John Doe"}})
+	var v2 struct {
+		Name  string
+		Email string
+	}
+	if err := gob.NewDecoder(&buf).Decode(&v2); err != nil || v2.Name != "John Doe" || v2.Email != "" {
+		t.Errorf("as another struct: %+v, %v", v2, err)
+	}
+
+	buf.Reset()
+	EncodeUsers(&buf, []User{{UserId: userId1, Name: "John Doe"}})
+	var bad struct{ Name int }
+	if err := gob.NewDecoder(&buf).Decode(&bad); err == nil {
+		t.Error("a string field decoded into an int")
+	}
+}
+
+func TestDecodeUsersErrors(t *testing.T) {
+	var buf bytes.Buffer
+	EncodeUsers(&buf, []User{{UserId: userId1, Name: "John Doe"}, {UserId: userId2, Name: "Jack Eod"}})
+	whole := buf.Bytes()
+
+	// Cut in the second user: the first is returned, with the error
+	got, err := DecodeUsers(bytes.NewReader(whole[:len(whole)-3]))
+	if !errors.Is(err, io.ErrUnexpectedEOF) || len(got) != 1 {
+		t.Errorf("cut short: %d users, %v, want 1 and io.ErrUnexpectedEOF", len(got), err)
+	}
+	if _, err := DecodeUsers(bytes.NewReader([]byte("not gob at all"))); err == nil {
+		t.Error("DecodeUsers of text succeeded")
+	}
+	if got, err := DecodeUsers(bytes.NewReader(nil)); err != nil || got != nil {
+		t.Errorf("DecodeUsers of nothing = %v, %v, want none", got, err)
+	}
+}
+
+func TestHeaderRoundTrip(t *testing.T) {
+	tests := []Header{
+		{Magic: Magic},
+		{Magic: Magic, Version: 2, Flags: 1, Count: 42, Created: 1714521600},
+		{Magic: Magic, Version: math.MaxUint16, Flags: math.MaxUint16, Count: math.MaxUint32, Created: math.MinInt64},
+		{Magic: Magic, Created: -1},
+	}
+	for _, h := range tests {
+		data, err := h.MarshalBinary()
+		if err != nil || len(data) != HeaderSize {
+			t.Fatalf("MarshalBinary(%+v) = %d bytes, %v, want %d", h, len(data), err, HeaderSize)
+		}
+		if byHand := AppendHeader(nil, h); !bytes.Equal(byHand, data) {
+			t.Errorf("AppendHeader = % x, MarshalBinary = % x", byHand, data)
+		}
+		var back Header
+		if err := back.UnmarshalBinary(data); err != nil || back != h {
+			t.Errorf("round trip of %+v = %+v, %v", h, back, err)
+		}
+	}
+}
+
+func TestHeaderLayout(t *testing.T) {
+	if HeaderSize != 20 {
+		t.Errorf("HeaderSize = %d, want 20", HeaderSize)
+	}
+	h := Header{Magic: Magic, Version: 0x0102, Flags: 0x0304, Count: 0x05060708, Created: 0x090a0b0c0d0e0f10}
+	want := []byte{'N', 'O', 'T', 'E', 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	if got, _ := h.MarshalBinary(); !bytes.Equal(got, want) {
+		t.Errorf("MarshalBinary = % x, want % x", got, want)
+	}
+	// AppendHeader appends, and doesn't allocate when there's room
+	prefix := []byte("xy")
+	if got := AppendHeader(prefix, h); !bytes.Equal(got, append([]byte("xy"), want...)) {
+		t.Errorf("AppendHeader after a prefix = % x", got)
+	}
+	buf := make([]byte, 0, HeaderSize)
+	if allocs := testing.AllocsPerRun(100, func() { AppendHeader(buf[:0], h) }); allocs != 0 {
+		t.Errorf("AppendHeader into a big enough slice: %v allocations", allocs)
+	}
+}
+
+func TestHeaderUnmarshalErrors(t *testing.T) {
+	data, _ := Header{Magic: Magic, Version: 1}.MarshalBinary()
+	tests := map[string][]byte{
+		"empty":     nil,
+		"short":     data[:10],
+		"one short": data[:HeaderSize-1],
+		"not ours":  append([]byte("PNG!"), data[4:]...),
+	}
+	for name, in := range tests {
+		var h Header
+		if err := h.UnmarshalBinary(in); err == nil {
+			t.Errorf("%s: UnmarshalBinary succeeded: %+v", name, h)
+		}
+	}
+	// Bytes past the header are left alone
+	var h Header
+	if err := h.UnmarshalBinary(append(data, "payload"...)); err != nil || h.Version != 1 {
+		t.Errorf("with a payload after it: %+v, %v", h, err)
+	}
+}
+
+func TestByteOrder(t *testing.T) {
+	n := uint32(0x0A0B0C0D)
+	if got := binary.BigEndian.AppendUint32(nil, n); !bytes.Equal(got, []byte{0x0a, 0x0b, 0x0c, 0x0d}) {
+		t.Errorf("big-endian % x", got)
+	}
+	if got := binary.LittleEndian.AppendUint32(nil, n); !bytes.Equal(got, []byte{0x0d, 0x0c, 0x0b, 0x0a}) {
+		t.Errorf("little-endian % x", got)
+	}
+	if got := binary.LittleEndian.Uint32(binary.BigEndian.AppendUint32(nil, n)); got != 0x0D0C0B0A {
+		t.Errorf("written big, read little: %#x", got)
+	}
+}
+
+func TestUvarint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		size int
+	}{{0, 1}, {1, 1}, {127, 1}, {128, 2}, {300, 2}, {1 << 32, 5}, {math.MaxUint64, 10}}
+	for _, tt := range tests {
+		b := binary.AppendUvarint(nil, tt.v)
+		got, size := binary.Uvarint(b)
+		if got != tt.v || size != tt.size || len(b) != tt.size {
+			t.Errorf("Uvarint(%d): % x back as %d, %d bytes, want %d", tt.v, b, got, size, tt.size)
+		}
+	}
+}
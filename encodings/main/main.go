@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"encodings"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// === Ex. binary encodings: base64, hex, gob, encoding/binary ===
+// (the round trips are checked in encodings_test.go)
+
+const userId1, userId2 = "1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func main() {
+	base64HexExample()
+	// gobExample()
+	// binaryExample()
+}
+
+func base64HexExample() {
+	data := []byte("hello, gophers?>")
+
+	// hex: two characters per byte - easy to read, double the size
+	h := hex.EncodeToString(data)
+	fmt.Println("hex:", h)
+	back, err := hex.DecodeString(h)
+	fmt.Printf("back: %q, err %v\n", back, err)
+	_, err = hex.DecodeString("4g")
+	fmt.Println("not hex:", err)
+	fmt.Print(hex.Dump(data[:12])) // the hexdump -C layout, for looking at binary data
+
+	// base64: four characters per three bytes. The encodings differ in two characters and the padding
+	for _, enc := range []struct {
+		name string
+		enc  *base64.Encoding
+	}{
+		{"StdEncoding", base64.StdEncoding},       // + and /, padded with = to a multiple of 4
+		{"URLEncoding", base64.URLEncoding},       // - and _ instead: safe in URLs and file names
+		{"RawURLEncoding", base64.RawURLEncoding}, // and no padding - ex. JWTs
+	} {
+		s := enc.enc.EncodeToString(data)
+		back, err := enc.enc.DecodeString(s)
+		fmt.Printf("%-15s %-26s back: %q %v\n", enc.name, s, back, err)
+	}
+	// Decoding with the wrong one fails - the characters or the padding don't fit
+	_, err = base64.StdEncoding.DecodeString(base64.RawURLEncoding.EncodeToString(data))
+	fmt.Println("Raw URL decoded as Std:", err)
+
+	// encoding/json does this for a []byte by itself: it's a base64 string in JSON
+	j, _ := json.Marshal(struct{ Data []byte }{data})
+	fmt.Println("a []byte in JSON:", string(j))
+}
+
+func gobExample() {
+	users := []encodings.User{{UserId: userId1, Name: "John Doe"}, {UserId: userId2, Name: "Jack Eod"}}
+
+	// Over a bytes.Buffer - the same as over a file or a net.Conn
+	var buf bytes.Buffer
+	err := encodings.EncodeUsers(&buf, users)
+	gobSize := buf.Len()
+	got, derr := encodings.DecodeUsers(&buf)
+	fmt.Printf("2 users: %d bytes of gob, err %v %v\n", gobSize, err, derr)
+	fmt.Printf("back: %+v\n", got)
+
+	// The type is described once per stream, then each value is only its fields: the first user costs
+	// the most, and the more there are, the further ahead of JSON, with its field names in every value
+	for _, n := range []int{1, 2, 100} {
+		many := slices.Repeat(users[:1], n)
+		var g bytes.Buffer
+		encodings.EncodeUsers(&g, many)
+		j, _ := json.Marshal(many)
+		fmt.Printf("%3d users: %5d bytes of gob, %5d of JSON\n", n, g.Len(), len(j))
+	}
+
+	// Fields match by name, not position: decoding into another struct takes the fields it shares,
+	// ignores the rest, and leaves missing ones zero - so a type can gain fields and still read old data
+	type userV2 struct {
+		Name  string
+		Email string
+	}
+	encodings.EncodeUsers(&buf, users[:1])
+	var v2 userV2
+	err = gob.NewDecoder(&buf).Decode(&v2)
+	fmt.Printf("as another struct: %+v, err %v\n", v2, err)
+
+	// A field whose type changed can't be decoded
+	type badUser struct{ Name int }
+	encodings.EncodeUsers(&buf, users[:1])
+	var bad badUser
+	fmt.Println("Name as an int:", gob.NewDecoder(&buf).Decode(&bad))
+}
+
+func binaryExample() {
+	// The byte order: which byte of a number comes first. Big-endian is the order you write it in,
+	// and the order of network protocols; little-endian is what x86 and ARM CPUs use in memory
+	n := uint32(0x0A0B0C0D)
+	fmt.Printf("0x%08X big-endian: % x | little-endian: % x\n", n,
+		binary.BigEndian.AppendUint32(nil, n), binary.LittleEndian.AppendUint32(nil, n))
+	// Read with the wrong order, the bytes make another number
+	fmt.Printf("written big, read little: 0x%08X\n", binary.LittleEndian.Uint32(binary.BigEndian.AppendUint32(nil, n)))
+
+	// A fixed-layout struct with binary.Write / binary.Read
+	h := encodings.Header{Magic: encodings.Magic, Version: 2, Flags: 1, Count: 42, Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC).Unix()}
+	data, err := h.MarshalBinary()
+	fmt.Printf("Header, %d bytes: % x, err %v\n", len(data), data, err)
+	var back encodings.Header
+	err = back.UnmarshalBinary(data)
+	fmt.Printf("back: %+v, err %v\n", back, err)
+	fmt.Printf("by hand: % x\n", encodings.AppendHeader(nil, h))
+	fmt.Println("short:", back.UnmarshalBinary(data[:10]))
+	fmt.Println("not ours:", back.UnmarshalBinary(append([]byte("PNG!"), data[4:]...)))
+
+	// Varints: a small number in fewer bytes - 1 byte up to 127, each 7 bits more one more byte (gob and
+	// protobuf store numbers this way)
+	for _, v := range []uint64{1, 127, 128, 300, 1 << 32} {
+		b := binary.AppendUvarint(nil, v)
+		got, size := binary.Uvarint(b)
+		fmt.Printf("Uvarint %10d: % -16x (%d bytes) back: %d\n", v, b, size, got)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// === Errors from deferred Close ===
+
+// defer f.Close() is the usual way to make sure a file is closed, but it throws away Close's error.
+// For files opened for READING that is fine. For WRITING it is not:
+// the OS (or a buffered writer, or a network filesystem) may only report a failed write when the file is closed,
+// so a swallowed Close error can mean the data never reached the disk while the function reports success.
+
+var ErrFlushFailed = errors.New("flush failed")
+
+// failingWriteCloser is a fake io.WriteCloser for the examples.
+// Writes go to a buffer and succeed unless writeErr is set, but Close fails - like a disk that fills up on the final flush
+type failingWriteCloser struct {
+	written  []byte
+	writeErr error
+	closeErr error
+	closed   bool
+}
+
+func (f *failingWriteCloser) Write(p []byte) (int, error) {
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	f.written = append(f.written, p...)
+	return len(p), nil
+}
+
+func (f *failingWriteCloser) Close() error {
+	f.closed = true
+	return f.closeErr
+}
+
+// --- The common version: the Close error is lost ---
+
+func saveReportSwallowed(w io.WriteCloser, report string) error {
+	defer w.Close() // return value ignored
+
+	_, err := io.WriteString(w, report)
+	return err
+}
+
+// --- Capturing the Close error with a named return value ---
+
+// A deferred function runs after the return value is set, and can still change a NAMED return value.
+// errors.Join keeps both errors if the write AND the close failed (and returns nil if neither did)
+func saveReport(w io.WriteCloser, report string) (err error) {
+	defer func() {
+		err = errors.Join(err, w.Close())
+	}()
+
+	_, err = io.WriteString(w, report)
+	return err
+}
+
+// Ex. with a real file. Opening is done before the defer, since there is nothing to close if it fails
+func saveReportToFile(path, report string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("close %s: %w", path, closeErr))
+		}
+	}()
+
+	if _, err := f.WriteString(report); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	// For data that must survive a crash, Sync flushes the OS buffers to the disk before closing
+	return f.Sync()
+}
+
+// An alternative without a deferred closure: close explicitly on the success path,
+// and keep the defer only as a safety net for early returns
+// (calling Close twice on an *os.File is safe - the second call returns os.ErrClosed, which is ignored here).
+func saveReportExplicitClose(path, report string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(report); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func deferredCloseExamples() {
+	const report = "users: 2\n"
+
+	fake := &failingWriteCloser{closeErr: ErrFlushFailed}
+	err := saveReportSwallowed(fake, report)
+	fmt.Printf("swallowed: closed=%t err=%v  <- reports success, but the data was not saved\n", fake.closed, err)
+
+	fake = &failingWriteCloser{closeErr: ErrFlushFailed}
+	err = saveReport(fake, report)
+	fmt.Printf("captured:  closed=%t err=%v | is ErrFlushFailed: %t\n", fake.closed, err, errors.Is(err, ErrFlushFailed))
+
+	fake = &failingWriteCloser{}
+	err = saveReport(fake, report)
+	fmt.Printf("no errors: closed=%t err=%v wrote=%q\n", fake.closed, err, fake.written)
+
+	dir, err := os.MkdirTemp("", "close-errors")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.txt")
+	fmt.Println("real file:", saveReportToFile(path, report))
+	fmt.Println("explicit close:", saveReportExplicitClose(path, report))
+
+	// Create fails (the directory does not exist), so there is no file to close
+	fmt.Println("bad path:", saveReportToFile(filepath.Join(dir, "missing", "report.txt"), report))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var errShortWrite = errors.New("short write")
+
+func TestSaveReportCloseErrors(t *testing.T) {
+	const report = "users: 2\n"
+	for _, c := range []struct {
+		name               string
+		save               func(*failingWriteCloser) error
+		writeErr, closeErr error
+		want               []error // errors.Is must hold for each; nil means no error
+	}{
+		{"swallowed, close fails", func(f *failingWriteCloser) error { return saveReportSwallowed(f, report) }, nil, ErrFlushFailed, nil}, // the bug: reports success
+		{"swallowed, write fails", func(f *failingWriteCloser) error { return saveReportSwallowed(f, report) }, errShortWrite, nil, []error{errShortWrite}},
+		{"captured, no errors", func(f *failingWriteCloser) error { return saveReport(f, report) }, nil, nil, nil},
+		{"captured, close fails", func(f *failingWriteCloser) error { return saveReport(f, report) }, nil, ErrFlushFailed, []error{ErrFlushFailed}},
+		{"captured, write fails", func(f *failingWriteCloser) error { return saveReport(f, report) }, errShortWrite, nil, []error{errShortWrite}},
+		{"captured, both fail", func(f *failingWriteCloser) error { return saveReport(f, report) }, errShortWrite, ErrFlushFailed, []error{errShortWrite, ErrFlushFailed}},
+	} {
+		fake := &failingWriteCloser{writeErr: c.writeErr, closeErr: c.closeErr}
+		err := c.save(fake)
+		if !fake.closed {
+			t.Errorf("%s: not closed", c.name)
+		}
+		if (err != nil) != (len(c.want) > 0) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+		for _, want := range c.want {
+			if !errors.Is(err, want) {
+				t.Errorf("%s: err = %v, does not wrap %v", c.name, err, want)
+			}
+		}
+		if c.writeErr == nil && string(fake.written) != report {
+			t.Errorf("%s: wrote %q, want %q", c.name, fake.written, report)
+		}
+	}
+}
+
+func TestSaveReportToFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, save := range map[string]func(path, report string) error{
+		"saveReportToFile":        saveReportToFile,
+		"saveReportExplicitClose": saveReportExplicitClose,
+	} {
+		path := filepath.Join(dir, name+".txt")
+		if err := save(path, "users: 2\n"); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if data, err := os.ReadFile(path); err != nil || string(data) != "users: 2\n" {
+			t.Errorf("%s: file has %q, %v", name, data, err)
+		}
+
+		// nothing was opened, so the error is Create's alone
+		err := save(filepath.Join(dir, "missing", "report.txt"), "x")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s into a missing directory: %v, want ErrNotExist", name, err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+// === Errors in depth ===
+
+// Recap: error is a built-in interface with a single method
+//
+//	type error interface {
+//		Error() string
+//	}
+//
+// Any type with an Error() string method is an error.
+// This file covers what happens once errors are passed up through several layers of functions.
+// basics/main/uploaderrors.go puts it all to work on HandleFileUpload: sentinels, a StorageError
+// wrapping the store's error, and a caller choosing its message with errors.Is and errors.As.
+
+type User struct {
+	UserId string
+	Name   string
+}
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+
+var users = map[string]User{
+	userId1: {UserId: userId1, Name: "John Doe"},
+}
+
+// --- Sentinel errors ---
+
+// A sentinel error is a package level error value that callers compare against.
+// Convention is to name them ErrXxx and create them with errors.New
+// (each call to errors.New returns a distinct value, even with the same text)
+var ErrNotFound = errors.New("not found")
+var ErrPermission = errors.New("permission denied")
+
+func findUser(id string) (User, error) {
+	u, ok := users[id]
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+// --- Wrapping with %w ---
+
+// fmt.Errorf with the %w verb creates a new error that wraps the original.
+// The message gets extra context, but the original error is still reachable.
+// (with %v the original is only copied into the message text - the chain is lost)
+func loadProfile(id string) (User, error) {
+	u, err := findUser(id)
+	if err != nil {
+		return User{}, fmt.Errorf("load profile %q: %w", id, err)
+	}
+	return u, nil
+}
+
+// Each layer adds its own context, building a chain:
+// "handle request: load profile "x": not found"
+func handleRequest(id string) error {
+	if _, err := loadProfile(id); err != nil {
+		return fmt.Errorf("handle request: %w", err)
+	}
+	return nil
+}
+
+func wrappingExample() {
+	err := handleRequest("missing-id")
+	fmt.Println("error:", err)
+
+	// == only compares the outermost error, which is the wrapper
+	fmt.Println("err == ErrNotFound:", err == ErrNotFound)
+
+	// errors.Is walks the chain (calling Unwrap) and compares each error with the target
+	fmt.Println("errors.Is(err, ErrNotFound):", errors.Is(err, ErrNotFound))
+	fmt.Println("errors.Is(err, ErrPermission):", errors.Is(err, ErrPermission))
+
+	// Wrapping with %v instead of %w breaks the chain
+	flattened := fmt.Errorf("handle request: %v", ErrNotFound)
+	fmt.Printf("same message, but errors.Is after wrapping with %%v: %t\n", errors.Is(flattened, ErrNotFound))
+
+	// --- Unwrapping the chain one step at a time ---
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		fmt.Printf("  %T: %v\n", e, e)
+	}
+}
+
+// --- Custom error types ---
+
+// A struct error can carry extra fields the caller can inspect (not just a message)
+type ValidationError struct {
+	Field string
+	Value string
+	Err   error // the underlying cause
+}
+
+// Pointer receiver - so the *ValidationError is the type that implements error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Field, e.Value, e.Err)
+}
+
+// Implementing Unwrap lets errors.Is / errors.As continue into the cause
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func parseAge(s string) (int, error) {
+	age, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, &ValidationError{Field: "age", Value: s, Err: err}
+	}
+	if age < 0 {
+		return 0, &ValidationError{Field: "age", Value: s, Err: errors.New("must not be negative")}
+	}
+	return age, nil
+}
+
+func customErrorTypeExample() {
+	_, err := parseAge("forty")
+	err = fmt.Errorf("signup form: %w", err)
+	fmt.Println("error:", err)
+
+	// errors.As finds the first error in the chain that can be assigned to the target,
+	// and sets the target to it. The target must be a pointer to the type being looked for
+	// (here a pointer to a *ValidationError, since *ValidationError implements error)
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		fmt.Printf("field: %s, value: %q\n", ve.Field, ve.Value)
+	}
+
+	// As keeps going past the ValidationError - the cause from strconv is a *strconv.NumError
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		fmt.Println("strconv function that failed:", numErr.Func)
+	}
+
+	// A type assertion only checks the outermost error, so it misses the wrapped one
+	_, ok := err.(*ValidationError)
+	fmt.Println("type assertion on the wrapped error:", ok)
+
+	// Same pattern in the standard library - os functions return *fs.PathError
+	_, err = os.Open("/does/not/exist")
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		fmt.Println("path:", pathErr.Path, "| op:", pathErr.Op, "| not exist:", errors.Is(err, fs.ErrNotExist))
+	}
+}
+
+// --- Custom Is method ---
+
+// By default errors.Is compares with ==.
+// A type can define Is(target error) bool to decide for itself what it matches
+// Ex. a status error matches any other status error with the same code
+type StatusError struct {
+	Code int
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("status %d", e.Code)
+}
+
+func (e StatusError) Is(target error) bool {
+	t, ok := target.(StatusError)
+	return ok && t.Code == e.Code
+}
+
+var ErrStatusNotFound = StatusError{Code: 404}
+
+func customIsExample() {
+	err := fmt.Errorf("fetch users: %w", StatusError{Code: 404})
+	fmt.Println("is 404:", errors.Is(err, ErrStatusNotFound))
+	fmt.Println("is 500:", errors.Is(err, StatusError{Code: 500}))
+}
+
+// --- errors.Join (Go 1.20+) ---
+
+// Joins several errors into one. Nil errors are dropped, and if every error is nil, Join returns nil.
+// errors.Is / errors.As check every joined error (the joined error has an Unwrap() []error method)
+func validateUser(u User) error {
+	var errs []error
+	if u.UserId == "" {
+		errs = append(errs, &ValidationError{Field: "UserId", Value: u.UserId, Err: errors.New("required")})
+	}
+	if u.Name == "" {
+		errs = append(errs, &ValidationError{Field: "Name", Value: u.Name, Err: errors.New("required")})
+	}
+	if _, err := findUser(u.UserId); err != nil {
+		errs = append(errs, fmt.Errorf("lookup: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func joinExample() {
+	err := validateUser(User{})
+
+	// The message is each error on its own line
+	fmt.Printf("joined error:\n%v\n", err)
+
+	fmt.Println("contains ErrNotFound:", errors.Is(err, ErrNotFound))
+
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		fmt.Println("first validation error is for field:", ve.Field)
+	}
+
+	// To get every error back, assert to the interface with Unwrap() []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		fmt.Println("number of joined errors:", len(joined.Unwrap()))
+	}
+
+	fmt.Println("no errors gives nil:", validateUser(users[userId1]) == nil)
+
+	// fmt.Errorf can also wrap more than one error with multiple %w verbs
+	multi := fmt.Errorf("both: %w and %w", ErrNotFound, ErrPermission)
+	fmt.Println(multi, "|", errors.Is(multi, ErrNotFound), errors.Is(multi, ErrPermission))
+}
+
+// --- Guidelines ---
+
+// - Handle an error OR return it, not both (logging and returning often logs the same error twice)
+// - Add context when returning: fmt.Errorf("what was being done: %w", err)
+// - Use %w when callers may need to check the cause, %v when the cause is an implementation detail
+//   (wrapping makes the wrapped error part of the function's API)
+// - Compare with errors.Is and errors.As, not == or type assertions, since the error may be wrapped
+// - Error strings are lowercase with no ending punctuation, because they get joined into longer messages
+
+func main() {
+	wrappingExample()
+	// customErrorTypeExample()
+	// customIsExample()
+	// joinExample()
+	// deferredCloseExamples()
+	// retryExample()
+}
@@ -0,0 +1,3 @@
+module errorsdeep
+
+go 1.25.0
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// === Retrying, generically ===
+
+// Retry calls fn until it succeeds, and gives back fn's result - a T, whatever fn returns,
+// so a retried lookup of a User returns a User, not an any to assert on.
+// Whether an error is worth another try is the error's to say: an error in the chain with a
+// Retryable() bool method decides, found with errors.As on an interface (errors.As accepts a pointer to
+// an interface type, and finds the first error in the chain implementing it). Anything else is final -
+// retrying a "not found" or a bad request only gets the same answer again.
+// (concurrency/retrypool.go runs many jobs like this, with jitter on the waits.)
+
+// retryable is what the errors say about themselves
+type retryable interface {
+	Retryable() bool
+}
+
+// A server error or "too many requests" may go away; other statuses (404, 400) won't
+func (e StatusError) Retryable() bool {
+	return e.Code >= 500 || e.Code == 429
+}
+
+// isRetryable is the default classification
+func isRetryable(err error) bool {
+	var r retryable
+	return errors.As(err, &r) && r.Retryable()
+}
+
+type RetryPolicy struct {
+	MaxAttempts int           // including the first; 0 is 1
+	Base, Max   time.Duration // the wait after the first failure, doubling each time up to Max (<= 0: no cap)
+
+	// Retryable says if err is worth another attempt. nil: isRetryable
+	Retryable func(err error) bool
+	// Sleep waits d, or returns early if ctx is done (with why). nil: a real timer
+	Sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RetryError is what Retry returns when it gives up: how many attempts it made, and the last error
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Retry runs fn until it returns a nil error, an error policy doesn't retry, or runs out of attempts;
+// or ctx is done, checked before each attempt and during each wait. Any error is a *RetryError wrapping
+// fn's last error (and the context's, if that's why it stopped), so errors.Is and errors.As see through it
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	retry, sleep := policy.Retryable, policy.Sleep
+	if retry == nil {
+		retry = isRetryable
+	}
+	if sleep == nil {
+		sleep = sleepCtx
+	}
+	wait := policy.Base
+	var zero T
+	for attempt := 1; ; attempt++ {
+		if err := context.Cause(ctx); err != nil {
+			return zero, &RetryError{Attempts: attempt - 1, Err: err}
+		}
+		v, err := fn()
+		if err == nil {
+			return v, nil
+		}
+		if !retry(err) || attempt >= policy.MaxAttempts {
+			return zero, &RetryError{Attempts: attempt, Err: err}
+		}
+		if serr := sleep(ctx, wait); serr != nil {
+			return zero, &RetryError{Attempts: attempt, Err: errors.Join(serr, err)}
+		}
+		wait = nextWait(wait, policy.Max)
+	}
+}
+
+// nextWait doubles wait, up to max if it's > 0 - and never past math.MaxInt64, where doubling would
+// wrap around to a negative wait
+func nextWait(wait, max time.Duration) time.Duration {
+	if wait > math.MaxInt64/2 {
+		wait = math.MaxInt64
+	} else {
+		wait *= 2
+	}
+	if max > 0 {
+		wait = min(wait, max)
+	}
+	return wait
+}
+
+// fakeClock is a clock that only moves when told to: its Sleep returns at once, adding d to the time
+// and recording it, so a test of the backoff takes no real time
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Duration
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now += d
+	c.sleeps = append(c.sleeps, d)
+	return context.Cause(ctx)
+}
+
+// flakyFind is findUser behind a service that answers 503 the first failures times
+func flakyFind(id string, failures int) (func() (User, error), *int) {
+	calls := 0
+	return func() (User, error) {
+		calls++
+		if calls <= failures {
+			return User{}, fmt.Errorf("find %s: %w", id, StatusError{Code: 503})
+		}
+		return findUser(id)
+	}, &calls
+}
+
+func retryExample() {
+	policy := RetryPolicy{MaxAttempts: 5, Base: 100 * time.Millisecond, Max: 300 * time.Millisecond}
+	ctx := context.Background()
+
+	// Succeeds on the third attempt - the result is a User, straight from Retry
+	clock := &fakeClock{}
+	policy.Sleep = clock.Sleep
+	find, calls := flakyFind(userId1, 2)
+	u, err := Retry(ctx, policy, find)
+	fmt.Printf("got %v, err %v, after %d calls, waited %v\n", u, err, *calls, clock.sleeps)
+
+	// Never succeeds: MaxAttempts, with the waits capped at Max
+	clock = &fakeClock{}
+	policy.Sleep = clock.Sleep
+	find, calls = flakyFind(userId1, 100)
+	_, err = Retry(ctx, policy, find)
+	fmt.Println("error:", err)
+	fmt.Println("still a 503 underneath:", errors.Is(err, StatusError{Code: 503}), "| waits capped:", clock.sleeps, "| total", clock.now)
+
+	// No Max: the waits keep doubling, rather than dropping to 0 after the first
+	clock = &fakeClock{}
+	uncapped := RetryPolicy{MaxAttempts: 5, Base: 100 * time.Millisecond, Sleep: clock.Sleep}
+	find, _ = flakyFind(userId1, 100)
+	Retry(ctx, uncapped, find)
+	fmt.Println("no Max - waits:", clock.sleeps)
+
+	// Not retryable: ErrNotFound has no Retryable method, so the first answer is final
+	clock = &fakeClock{}
+	policy.Sleep = clock.Sleep
+	calls404 := 0
+	_, err = Retry(ctx, policy, func() (User, error) {
+		calls404++
+		return findUser("no-such-user")
+	})
+	fmt.Println("not found:", err, "| calls:", calls404)
+	_, err = Retry(ctx, policy, func() (int, error) { return 0, StatusError{Code: 404} }) // a StatusError, but a 404
+	fmt.Println("404:", err)
+
+	// A policy can classify errors its own way - here a ValidationError (from parseAge) is retried, with new input
+	inputs := []string{"forty", "-1", "40"}
+	attempt := 0
+	custom := policy
+	custom.Retryable = func(err error) bool {
+		var ve *ValidationError
+		return errors.As(err, &ve)
+	}
+	age, err := Retry(ctx, custom, func() (int, error) {
+		s := inputs[attempt]
+		attempt++
+		return parseAge(s)
+	})
+	fmt.Println("age:", age, err, "| attempts:", attempt)
+
+	// Cancelled during a wait: the real timer is stopped, and both the cause and the last error are kept
+	cctx, cancel := context.WithCancelCause(ctx)
+	time.AfterFunc(20*time.Millisecond, func() { cancel(errors.New("request abandoned")) })
+	slow := RetryPolicy{MaxAttempts: 3, Base: time.Minute, Max: time.Minute}
+	find, _ = flakyFind(userId1, 100)
+	start := time.Now()
+	_, err = Retry(cctx, slow, find)
+	fmt.Println("cancelled after", time.Since(start).Round(10*time.Millisecond), "-", err)
+
+	// retry_test.go checks the waits, the classification, and cancelling before and between attempts
+}
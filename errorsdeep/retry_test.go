@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestRetrySucceeds(t *testing.T) {
+	clock := &fakeClock{}
+	policy := RetryPolicy{MaxAttempts: 5, Base: 100 * ms, Max: 300 * ms, Sleep: clock.Sleep}
+	find, calls := flakyFind(userId1, 2)
+	u, err := Retry(context.Background(), policy, find)
+	if err != nil || u.Name != "John Doe" || *calls != 3 {
+		t.Errorf("Retry = %v, %v after %d calls, want John Doe on the third", u, err, *calls)
+	}
+	if want := []time.Duration{100 * ms, 200 * ms}; !slices.Equal(clock.sleeps, want) {
+		t.Errorf("waited %v, want %v", clock.sleeps, want)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		policy    RetryPolicy
+		attempts  int
+		wantWaits []time.Duration
+	}{
+		{"capped at Max", RetryPolicy{MaxAttempts: 5, Base: 100 * ms, Max: 300 * ms}, 5, []time.Duration{100 * ms, 200 * ms, 300 * ms, 300 * ms}},
+		{"no Max keeps doubling", RetryPolicy{MaxAttempts: 5, Base: 100 * ms}, 5, []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms}},
+		{"MaxAttempts 0 is one attempt", RetryPolicy{Base: 100 * ms}, 1, nil},
+		{"MaxAttempts 1", RetryPolicy{MaxAttempts: 1, Base: 100 * ms}, 1, nil},
+	} {
+		clock := &fakeClock{}
+		c.policy.Sleep = clock.Sleep
+		find, calls := flakyFind(userId1, 100)
+		u, err := Retry(context.Background(), c.policy, find)
+
+		var re *RetryError
+		if !errors.As(err, &re) || re.Attempts != c.attempts || *calls != c.attempts {
+			t.Errorf("%s: %v after %d calls, want a *RetryError after %d attempts", c.name, err, *calls, c.attempts)
+		}
+		if !errors.Is(err, StatusError{Code: 503}) || u != (User{}) {
+			t.Errorf("%s: got %v, %v - want the zero User and the 503 underneath", c.name, u, err)
+		}
+		if !slices.Equal(clock.sleeps, c.wantWaits) {
+			t.Errorf("%s: waited %v, want %v", c.name, clock.sleeps, c.wantWaits)
+		}
+	}
+}
+
+func TestRetryClassification(t *testing.T) {
+	for _, c := range []struct {
+		err   error
+		retry bool
+	}{
+		{StatusError{Code: 503}, true},
+		{StatusError{Code: 500}, true},
+		{StatusError{Code: 429}, true},
+		{StatusError{Code: 404}, false},
+		{StatusError{Code: 400}, false},
+		{ErrNotFound, false},
+		{&RetryError{Attempts: 2, Err: StatusError{Code: 502}}, true}, // found through the chain
+		{errors.Join(ErrNotFound, StatusError{Code: 503}), true},
+	} {
+		clock := &fakeClock{}
+		calls := 0
+		_, err := Retry(context.Background(), RetryPolicy{MaxAttempts: 3, Sleep: clock.Sleep}, func() (int, error) {
+			calls++
+			return 0, c.err
+		})
+		if want := map[bool]int{true: 3, false: 1}[c.retry]; calls != want || !errors.Is(err, c.err) {
+			t.Errorf("%v: %d calls, %v - want %d calls", c.err, calls, err, want)
+		}
+	}
+}
+
+// A policy can classify errors its own way - here a ValidationError is retried, with new input
+func TestRetryCustomClassification(t *testing.T) {
+	inputs := []string{"forty", "-1", "40"}
+	attempt := 0
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		Sleep:       (&fakeClock{}).Sleep,
+		Retryable: func(err error) bool {
+			var ve *ValidationError
+			return errors.As(err, &ve)
+		},
+	}
+	age, err := Retry(context.Background(), policy, func() (int, error) {
+		s := inputs[attempt]
+		attempt++
+		return parseAge(s)
+	})
+	if age != 40 || err != nil || attempt != 3 {
+		t.Errorf("Retry = %d, %v after %d attempts, want 40 on the third", age, err, attempt)
+	}
+}
+
+// Cancelled during a real wait: Retry returns at once, keeping both the cause and the last error
+func TestRetryCancelDuringWait(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	abandoned := errors.New("request abandoned")
+	time.AfterFunc(20*ms, func() { cancel(abandoned) })
+	find, calls := flakyFind(userId1, 100)
+	start := time.Now()
+	_, err := Retry(ctx, RetryPolicy{MaxAttempts: 3, Base: time.Minute}, find)
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("Retry took %v - the wait wasn't cut short", time.Since(start))
+	}
+	var re *RetryError
+	if !errors.Is(err, abandoned) || !errors.Is(err, StatusError{Code: 503}) || !errors.As(err, &re) || re.Attempts != 1 || *calls != 1 {
+		t.Errorf("%v after %d calls - want the cause and the 503, after 1 attempt", err, *calls)
+	}
+}
+
+func TestRetryAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	_, err := Retry(ctx, RetryPolicy{MaxAttempts: 3}, func() (int, error) { called = true; return 1, nil })
+	var re *RetryError
+	if called || !errors.Is(err, context.Canceled) || !errors.As(err, &re) || re.Attempts != 0 {
+		t.Errorf("fn called %t, err %v - want no calls and context.Canceled after 0 attempts", called, err)
+	}
+}
+
+// The context is checked before every attempt, not only during the waits
+func TestRetryCancelBetweenAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := Retry(ctx, RetryPolicy{MaxAttempts: 10, Sleep: func(context.Context, time.Duration) error { return nil }}, func() (int, error) {
+		if calls++; calls == 2 {
+			cancel()
+		}
+		return 0, StatusError{Code: 503}
+	})
+	if calls != 2 || !errors.Is(err, context.Canceled) {
+		t.Errorf("%d calls, %v - want 2, then context.Canceled", calls, err)
+	}
+}
+
+func TestNextWait(t *testing.T) {
+	for _, c := range []struct{ wait, max, want time.Duration }{
+		{100 * ms, 0, 200 * ms},
+		{100 * ms, 150 * ms, 150 * ms},
+		{0, 0, 0},
+		{math.MaxInt64/2 + 1, 0, math.MaxInt64}, // doubling would wrap negative
+		{math.MaxInt64, time.Hour, time.Hour},
+	} {
+		if got := nextWait(c.wait, c.max); got != c.want {
+			t.Errorf("nextWait(%v, %v) = %v, want %v", c.wait, c.max, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package exercises
+
+import (
+	"fmt"
+	"sync"
+)
+
+// === Exercise: Web Crawler ===
+
+// The Tour's Crawl fetches the same URL more than once, and one at a time. This one fetches each page in
+// its own goroutine, with the seen set behind a mutex - checked and marked in one critical section, or two
+// goroutines could both find a URL new and both fetch it. A WaitGroup makes Crawl wait for all of them.
+// (The concurrency module's webcrawler.go has this, a version without the lock, and one with a context)
+
+// Fetcher is the Tour's Fetcher interface
+type Fetcher interface {
+	// Fetch returns the body of URL and a slice of URLs found on that page
+	Fetch(url string) (body string, urls []string, err error)
+}
+
+// Crawl fetches pages starting with url, following links to a maximum of depth pages deep (a depth of 1
+// is url on its own), and prints what it finds like the Tour's does. It returns when every fetch has
+func Crawl(url string, depth int, fetcher Fetcher) {
+	var (
+		mu   sync.Mutex
+		seen = map[string]bool{}
+		wg   sync.WaitGroup
+	)
+	var crawl func(url string, depth int)
+	crawl = func(url string, depth int) {
+		if depth <= 0 {
+			return
+		}
+		mu.Lock()
+		if seen[url] {
+			mu.Unlock()
+			return
+		}
+		seen[url] = true
+		mu.Unlock()
+
+		body, urls, err := fetcher.Fetch(url)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("found: %s %q\n", url, body)
+		for _, u := range urls {
+			wg.Go(func() { crawl(u, depth-1) })
+		}
+	}
+	crawl(url, depth)
+	wg.Wait()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"exercises"
+	"exercises/tree"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+)
+
+// === Ex. the Tour's exercises, solved ===
+
+// Each example prints what the Tour's page prints for the exercise, with a few checks of its own.
+// `tour grade` runs many more, that the solutions haven't been written against (see tour/grader)
+
+func main() {
+	sqrtExample()
+	// wordCountExample()
+	// rot13ReaderExample()
+	// equivalentTreesExample()
+	// crawlExerciseExample()
+}
+
+func sqrtExample() {
+	for _, x := range []float64{2, 9, 1e10, 0.25, -2} {
+		z, err := exercises.Sqrt(x)
+		fmt.Printf("Sqrt(%v) = %v, %v | math.Sqrt: %v\n", x, z, err, math.Sqrt(x))
+	}
+	_, err := exercises.Sqrt(-2)
+	var neg exercises.ErrNegativeSqrt
+	fmt.Println("| ok: the error is an ErrNegativeSqrt holding -2:", errors.As(err, &neg) && neg == -2)
+}
+
+func wordCountExample() {
+	counts := exercises.WordCount("I ate a donut. Then I ate another donut.")
+	fmt.Println(counts) // fmt prints maps sorted by key
+	fmt.Println("| ok: punctuation is part of a word:", counts["donut."] == 2 && counts["donut"] == 0)
+}
+
+func rot13ReaderExample() {
+	r := exercises.NewRot13Reader(strings.NewReader("Lbh penpxrq gur pbqr!"))
+	io.Copy(os.Stdout, r)
+	fmt.Println()
+
+	twice, _ := io.ReadAll(exercises.NewRot13Reader(exercises.NewRot13Reader(strings.NewReader("Hello, Gophers 123"))))
+	fmt.Println("| ok: twice is the original:", string(twice) == "Hello, Gophers 123")
+}
+
+func equivalentTreesExample() {
+	t1, t2 := tree.New(1), tree.New(1)
+	fmt.Println("t1:", t1)
+	fmt.Println("t2:", t2) // the same values, very likely in another shape
+
+	ch := make(chan int)
+	go func() {
+		exercises.Walk(t1, ch)
+		close(ch)
+	}()
+	for v := range ch {
+		fmt.Print(v, " ")
+	}
+	fmt.Println()
+
+	fmt.Println("| ok: Same(New(1), New(1)):", exercises.Same(t1, t2))
+	fmt.Println("| ok: !Same(New(1), New(2)):", !exercises.Same(tree.New(1), tree.New(2)))
+}
+
+// tourFetcher is the Tour's fakeFetcher, counting the fetches of each URL. Crawl calls Fetch from many
+// goroutines at once, so the counts need a lock of their own
+type tourFetcher struct {
+	pages   map[string][]string // URL -> the URLs on it; the body is the URL's path
+	mu      sync.Mutex
+	fetches map[string]int
+}
+
+func (f *tourFetcher) Fetch(url string) (string, []string, error) {
+	f.mu.Lock()
+	f.fetches[url]++
+	f.mu.Unlock()
+	if urls, ok := f.pages[url]; ok {
+		return strings.TrimPrefix(url, "https://golang.org"), urls, nil
+	}
+	return "", nil, fmt.Errorf("not found: %s", url)
+}
+
+func crawlExerciseExample() {
+	f := &tourFetcher{pages: map[string][]string{
+		"https://golang.org/":         {"https://golang.org/pkg/", "https://golang.org/cmd/"},
+		"https://golang.org/pkg/":     {"https://golang.org/", "https://golang.org/cmd/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/"},
+		"https://golang.org/pkg/fmt/": {"https://golang.org/", "https://golang.org/pkg/"},
+		"https://golang.org/pkg/os/":  {"https://golang.org/", "https://golang.org/pkg/"},
+	}, fetches: map[string]int{}}
+	exercises.Crawl("https://golang.org/", 4, f)
+
+	once := true
+	for _, n := range f.fetches {
+		once = once && n == 1
+	}
+	fmt.Println("| ok: 5 URLs, each fetched once:", len(f.fetches) == 5 && once)
+}
@@ -0,0 +1,37 @@
+package exercises
+
+import "io"
+
+// === Exercise: rot13Reader ===
+
+// rot13Reader wraps an io.Reader, replacing each letter with the one 13 places on in the alphabet.
+// It changes what r reads in place, in the caller's buffer - so it never buffers anything itself
+type rot13Reader struct {
+	r io.Reader
+}
+
+// NewRot13Reader returns a reader of r's bytes with ROT13 applied. Reading it twice over is the original:
+// 13 + 13 is the 26 letters
+func NewRot13Reader(r io.Reader) io.Reader {
+	return rot13Reader{r}
+}
+
+// Read passes on n and err as they are, after changing the n bytes read. A Reader can return data
+// and an error together (ex. the last bytes and io.EOF), so the bytes are changed whatever err is
+func (rr rot13Reader) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	for i, b := range p[:n] {
+		p[i] = rot13(b)
+	}
+	return n, err
+}
+
+func rot13(b byte) byte {
+	switch {
+	case 'a' <= b && b <= 'z':
+		return 'a' + (b-'a'+13)%26
+	case 'A' <= b && b <= 'Z':
+		return 'A' + (b-'A'+13)%26
+	}
+	return b
+}
@@ -0,0 +1,38 @@
+package exercises
+
+import "fmt"
+
+// === Exercise: Loops and Functions + Exercise: Errors ===
+
+// Newton's method: z -= (z*z - x) / (2*z) moves a guess z towards the root of z² - x, each step roughly
+// doubling the correct digits. The Tour starts at z = 1 and stops after 10 steps, or when z stops changing.
+// 10 isn't enough for a large x (from 1, each step only halves z until it gets close), so this loops until
+// the change is tiny relative to z instead
+
+// ErrNegativeSqrt is the error Sqrt returns for a negative number, holding the number
+type ErrNegativeSqrt float64
+
+// Error converts e to a float64 first: fmt.Sprint(e) would call e.Error() again, and recurse forever
+// (a Tour's "Note" on the exercise's page)
+func (e ErrNegativeSqrt) Error() string {
+	return fmt.Sprint("cannot Sqrt negative number: ", float64(e))
+}
+
+// Sqrt returns the square root of x, or ErrNegativeSqrt for a negative x
+func Sqrt(x float64) (float64, error) {
+	if x < 0 {
+		return 0, ErrNegativeSqrt(x)
+	}
+	if x == 0 {
+		return 0, nil // z would only halve forever
+	}
+	z := 1.0
+	for range 1000 { // a bound anyway - one step can overshoot and come back, it never diverges
+		next := z - (z*z-x)/(2*z)
+		if d := next - z; d == 0 || max(d, -d) < 1e-15*next {
+			return next, nil
+		}
+		z = next
+	}
+	return z, nil
+}
@@ -0,0 +1,54 @@
+// Package tree is the Tour's golang.org/x/tour/tree, for the Equivalent Binary Trees exercise
+package tree
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// A Tree is a binary tree with integer values
+type Tree struct {
+	Left  *Tree
+	Value int
+	Right *Tree
+}
+
+// New returns a new, random binary tree holding the values k, 2k, ..., 10k.
+// The shape is different each time - only the values are the same
+func New(k int) *Tree {
+	var t *Tree
+	for _, v := range rand.Perm(10) {
+		t = Insert(t, (1+v)*k)
+	}
+	return t
+}
+
+// Insert adds v to t in order - left of any node with a larger value, right of the rest - and returns the root.
+// (The Tour's insert is unexported; this one is so the grader can build trees of a known shape)
+func Insert(t *Tree, v int) *Tree {
+	if t == nil {
+		return &Tree{nil, v, nil}
+	}
+	if v < t.Value {
+		t.Left = Insert(t.Left, v)
+	} else {
+		t.Right = Insert(t.Right, v)
+	}
+	return t
+}
+
+// String shows t's shape, ex. ((1) 2 (3)) for 2 with 1 on its left and 3 on its right
+func (t *Tree) String() string {
+	if t == nil {
+		return "()"
+	}
+	s := ""
+	if t.Left != nil {
+		s += t.Left.String() + " "
+	}
+	s += fmt.Sprint(t.Value)
+	if t.Right != nil {
+		s += " " + t.Right.String()
+	}
+	return "(" + s + ")"
+}
@@ -0,0 +1,55 @@
+package exercises
+
+import "exercises/tree"
+
+// === Exercise: Equivalent Binary Trees ===
+
+// Two trees can hold the same values in different shapes. Walking each in order - left, node, right -
+// gives the values sorted, so the trees are the same if the two walks are.
+// Each walk runs in its own goroutine, sending on a channel, and Same compares the two as they come:
+// it can stop at the first difference rather than collecting both first
+
+// Walk sends t's values on ch in order - smallest first. It doesn't close ch: the Tour's signature lets
+// Walk call itself on the subtrees with the same channel, so the caller closes it once Walk returns
+func Walk(t *tree.Tree, ch chan int) {
+	if t == nil {
+		return
+	}
+	Walk(t.Left, ch)
+	ch <- t.Value
+	Walk(t.Right, ch)
+}
+
+// walkAll walks t in a new goroutine, closing the channel after the last value
+func walkAll(t *tree.Tree) chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		Walk(t, ch)
+	}()
+	return ch
+}
+
+// Same reports whether t1 and t2 hold the same values
+func Same(t1, t2 *tree.Tree) bool {
+	ch1, ch2 := walkAll(t1), walkAll(t2)
+	for {
+		v1, ok1 := <-ch1
+		v2, ok2 := <-ch2
+		if ok1 != ok2 || v1 != v2 {
+			// Returning leaves the walks blocked on their next send, forever - a goroutine leak.
+			// Draining the channels lets them finish
+			go drain(ch1)
+			go drain(ch2)
+			return false
+		}
+		if !ok1 {
+			return true // both ended together
+		}
+	}
+}
+
+func drain(ch chan int) {
+	for range ch {
+	}
+}
@@ -0,0 +1,15 @@
+package exercises
+
+import "strings"
+
+// === Exercise: Maps ===
+
+// WordCount returns how many times each word is in s. Words are what strings.Fields splits s into:
+// runs of anything but whitespace, so "Go!" and "Go" are different words
+func WordCount(s string) map[string]int {
+	counts := map[string]int{}
+	for _, w := range strings.Fields(s) {
+		counts[w]++ // a missing key reads as 0
+	}
+	return counts
+}
@@ -0,0 +1,85 @@
+package flagpkg
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// The flag package parses command-line flags into variables:
+// - fs.String, fs.Int, fs.Bool, fs.Duration... return a pointer, filled in by Parse
+// - fs.StringVar and friends fill in a variable (or a struct field) the caller already has
+// - fs.Var takes anything with String and Set - a flag.Value - for types flag doesn't know
+// -name=value, -name value and --name value all work; a bool flag is just -name (or -name=false).
+// Parsing stops at the first argument that isn't a flag (or after "--"): what's left is fs.Args().
+//
+// The top-level functions (flag.String, flag.Parse) use flag.CommandLine, a FlagSet over os.Args that
+// exits on an error. A FlagSet of one's own, made with ContinueOnError, returns the error instead -
+// which is what a subcommand, or anything parsing a slice it was given, wants.
+
+// List is a flag.Value that collects every use of its flag: -tag a -tag b,c gives [a b c]
+type List []string
+
+func (l *List) String() string { return strings.Join(*l, ",") }
+
+func (l *List) Set(s string) error {
+	for part := range strings.SplitSeq(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*l = append(*l, part)
+		}
+	}
+	return nil
+}
+
+// Enum is a flag.Value that accepts only one of a fixed set of values
+type Enum struct {
+	Value   string
+	Allowed []string
+}
+
+func (e *Enum) String() string { return e.Value }
+
+// Set's error is shown by Parse as: invalid value "x" for flag -name: <error>
+func (e *Enum) Set(s string) error {
+	if !slices.Contains(e.Allowed, s) {
+		return fmt.Errorf("must be one of %s", strings.Join(e.Allowed, ", "))
+	}
+	e.Value = s
+	return nil
+}
+
+// Command is one subcommand - its own FlagSet, and what to do with the arguments left after its flags
+type Command struct {
+	Name    string
+	Summary string
+	Flags   func(fs *flag.FlagSet) // defines the command's flags
+	Run     func(args []string) error
+}
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Dispatch runs the command named by args[0] with the rest of args, the way "go build -o x ." does.
+// Flags are defined on a new FlagSet for every call, so a command's flags only mean something after its name.
+// -h prints the command's flags to out and returns flag.ErrHelp.
+func Dispatch(args []string, out io.Writer, commands ...Command) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: none given", ErrUnknownCommand)
+	}
+	i := slices.IndexFunc(commands, func(c Command) bool { return c.Name == args[0] })
+	if i < 0 {
+		return fmt.Errorf("%w %q", ErrUnknownCommand, args[0])
+	}
+	c := commands[i]
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	return c.Run(fs.Args())
+}
@@ -0,0 +1,3 @@
+module flagpkg
+
+go 1.25.0
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"flagpkg"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// === Ex. flag: flags, custom flag.Values, subcommands ===
+
+// Every example parses a slice given to it, not os.Args, on a FlagSet made with ContinueOnError -
+// so a bad flag comes back as an error to print, instead of exiting the program.
+// tour run (tour/run.go) is a real command built the same way.
+
+func main() {
+	basicFlagsExample()
+	// customValueExample()
+	// subcommandExample()
+}
+
+func basicFlagsExample() {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	workers := fs.Int("workers", 4, "requests handled at once")
+	verbose := fs.Bool("v", false, "log every request")
+	timeout := fs.Duration("timeout", 5*time.Second, "give up on a request after this long")
+	// A Var flag fills in a variable already there - here a field of a config struct
+	var cfg struct{ Name string }
+	fs.StringVar(&cfg.Name, "name", "notes", "name to show in the logs")
+
+	// -name value, -name=value and --name all work. A bool flag takes no value: -v, or -v=false
+	err := fs.Parse([]string{"-addr", ":9000", "--workers=8", "-v", "-timeout", "1m30s", "notes.md", "-ignored"})
+	fmt.Printf("addr %q, workers %d, v %t, timeout %v, name %q, err %v\n", *addr, *workers, *verbose, *timeout, cfg.Name, err)
+	// Parsing stops at the first non-flag: "-ignored" is an argument, not a flag
+	fmt.Println("args left:", fs.Args(), "| stopped at the first non-flag:", slices.Equal(fs.Args(), []string{"notes.md", "-ignored"}))
+
+	// Visit: only the flags that were set. VisitAll: every flag, set or not
+	var set []string
+	fs.Visit(func(f *flag.Flag) { set = append(set, f.Name) })
+	fmt.Println("set on the command line:", set, "| name kept its default:", !slices.Contains(set, "name"))
+
+	// "--" ends the flags, so an argument can start with a dash
+	fs = flag.NewFlagSet("rm", flag.ContinueOnError)
+	force := fs.Bool("f", false, "don't ask")
+	fs.Parse([]string{"-f", "--", "-notes.md"})
+	fmt.Println("after --:", fs.Args(), "| -f still parsed:", *force)
+
+	// Errors: an unknown flag, a value that doesn't parse, a missing value. The FlagSet prints the error
+	// and its usage to its output - discarded here, the returned error is enough
+	for _, args := range [][]string{{"-port", "80"}, {"-workers", "many"}, {"-timeout"}} {
+		fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Int("workers", 4, "")
+		fs.Duration("timeout", time.Second, "")
+		fmt.Printf("%-18s -> %v\n", strings.Join(args, " "), fs.Parse(args))
+	}
+
+	// -h (or -help) isn't an error to report, it asks for the usage: Parse prints it and returns flag.ErrHelp
+	var usage strings.Builder
+	fs = flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(&usage)
+	fs.String("addr", "localhost:8080", "`host:port` to listen on")
+	err = fs.Parse([]string{"-h"})
+	fmt.Printf("-h: %v | is flag.ErrHelp: %t\n%s", err, errors.Is(err, flag.ErrHelp), usage.String())
+}
+
+func customValueExample() {
+	// --- flagpkg.List: a flag that can be given more than once ---
+
+	var tags flagpkg.List
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	fs.Var(&tags, "tag", "only notes with this tag (repeatable, or comma-separated)")
+	fs.Parse([]string{"-tag", "generics", "-tag=channels, select", "-tag", ""})
+	fmt.Printf("tags %q | every use collected: %t\n", tags, slices.Equal(tags, []string{"generics", "channels", "select"}))
+
+	// --- flagpkg.Enum: a flag that rejects a value it doesn't know ---
+
+	format := &flagpkg.Enum{Value: "text", Allowed: []string{"text", "json", "markdown"}}
+	fs = flag.NewFlagSet("coverage", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(format, "format", "output format: text, json or markdown")
+	before := format.String()
+	err := fs.Parse([]string{"-format", "json"})
+	fmt.Println("default:", before, "| after -format json:", format, err)
+	err = fs.Parse([]string{"-format", "yaml"})
+	fmt.Println("-format yaml:", err)
+	fmt.Println("the bad value is rejected, the last good one kept:", err != nil && format.Value == "json")
+
+	// --- flag.Func and flag.TextVar: a custom flag without a new type ---
+
+	// Func: Set is the given func, for a one-off flag
+	var levels []int
+	fs = flag.NewFlagSet("log", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Func("level", "log `level` (0 to 3)", func(s string) error {
+		var n int
+		if _, err := fmt.Sscan(s, &n); err != nil || n < 0 || n > 3 {
+			return errors.New("not a level from 0 to 3")
+		}
+		levels = append(levels, n)
+		return nil
+	})
+	fmt.Println("-level 2 -level 9:", fs.Parse([]string{"-level", "2", "-level", "9"}), "| levels:", levels)
+
+	// TextVar: anything with UnmarshalText (time.Time, net.IP, a type of one's own) is a flag already
+	var since time.Time
+	fs = flag.NewFlagSet("log", flag.ContinueOnError)
+	fs.TextVar(&since, "since", time.Time{}, "entries after this time (RFC 3339)")
+	fs.Parse([]string{"-since", "2026-01-02T15:04:05Z"})
+	fmt.Println("since:", since, "| parsed by time.Time's UnmarshalText:", since.Year() == 2026 && since.Hour() == 15)
+
+	// A Value's String is what the usage shows as the default, and what Lookup(name).Value prints
+	fmt.Println("-since through Lookup:", fs.Lookup("since").Value, "| a List:", &flagpkg.List{"a", "b"})
+}
+
+func subcommandExample() {
+	// A command like "notes add -tag x title" and "notes list -format json": a FlagSet per subcommand,
+	// so -tag only means something after add, and -format only after list
+	var notes []string
+	var out strings.Builder
+	var tags flagpkg.List
+	format := &flagpkg.Enum{Allowed: []string{"text", "json"}}
+	commands := []flagpkg.Command{
+		{
+			Name:    "add",
+			Summary: "add a note",
+			Flags:   func(fs *flag.FlagSet) { tags = nil; fs.Var(&tags, "tag", "tag the note (repeatable)") },
+			Run: func(args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("add takes one title, got %d", len(args))
+				}
+				notes = append(notes, fmt.Sprintf("%s %v", args[0], tags))
+				return nil
+			},
+		},
+		{
+			Name:    "list",
+			Summary: "list the notes",
+			Flags:   func(fs *flag.FlagSet) { format.Value = "text"; fs.Var(format, "format", "text or json") },
+			Run: func(args []string) error {
+				if format.Value == "json" {
+					fmt.Fprintf(&out, "%q\n", notes)
+				} else {
+					fmt.Fprintln(&out, strings.Join(notes, "; "))
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, args := range [][]string{
+		{"add", "-tag", "generics", "-tag", "constraints", "Type parameters"},
+		{"add", "Channels"},
+		{"list", "-format", "json"},
+		{"list"},
+		{"remove", "Channels"},
+		{"list", "-tag", "generics"}, // add's flag, given to list
+		{"add", "two", "titles"},
+	} {
+		out.Reset()
+		err := flagpkg.Dispatch(args, io.Discard, commands...)
+		fmt.Printf("%-50s -> err: %v", strings.Join(args, " "), err)
+		if out.Len() > 0 {
+			fmt.Print(", out: ", out.String())
+		} else {
+			fmt.Println()
+		}
+	}
+	fmt.Println("2 notes added:", len(notes) == 2, "| tags reset between runs:", notes[1] == "Channels []")
+
+	unknown := flagpkg.Dispatch([]string{"remove"}, io.Discard, commands...)
+	wrongFlag := flagpkg.Dispatch([]string{"list", "-tag", "x"}, io.Discard, commands...)
+	fmt.Println("unknown command is ErrUnknownCommand:", errors.Is(unknown, flagpkg.ErrUnknownCommand),
+		"| a flag of another command is an error:", wrongFlag != nil)
+	help := flagpkg.Dispatch([]string{"add", "-h"}, io.Discard, commands...)
+	fmt.Println("add -h is flag.ErrHelp:", errors.Is(help, flag.ErrHelp))
+}
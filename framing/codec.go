@@ -0,0 +1,40 @@
+package framing
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// A Codec turns a payload into bytes and back. Framing doesn't care which: the frame only carries a
+// length. Both ends have to use the same one
+
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON encodes payloads as JSON: readable, and any language can decode them
+var JSON Codec = jsonCodec{}
+
+// Gob encodes each payload as a gob stream of its own - so the type's description is in every frame.
+// A gob.Encoder kept for the whole connection would send it once, but then the frames depend on each
+// other: they're no longer messages that can be read, logged or dropped one at a time
+var Gob Codec = gobCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
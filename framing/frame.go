@@ -0,0 +1,82 @@
+package framing
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TCP is a stream of bytes, not of messages: one Write can arrive in several Reads, and several Writes
+// in one. The io notes' Reader contract says as much - Read returns what's there, up to len(p) -
+// so a protocol has to say where each message ends. Ways to:
+// - a delimiter, ex. a newline (the tcp and jsonrpc modules): simple, but the payload can't contain it
+// - a length prefix, here: each frame is a fixed size header saying how long the payload after it is.
+//   Any bytes can be in the payload, and the reader knows how much to allocate before it reads them
+//
+// A frame, big-endian ("network order") as encoding/binary writes it:
+//
+//	0..4  Length   uint32  the payload's length in bytes
+//	4     Kind     uint8   what the payload is (the protocol's message types - see Kind)
+//	5..   payload  Length bytes, encoded by a Codec
+//
+// Reading a frame is two io.ReadFull calls' worth (binary.Read does one for the header): ReadFull keeps
+// calling Read until the buffer is full, however the bytes were split on the way.
+
+// HeaderSize is the bytes before each payload
+const HeaderSize = 5
+
+// DefaultMaxPayload is the largest payload ReadFrame accepts by default. The length comes from the
+// other end: without a limit, four bytes of 0xff would make the reader allocate 4 GB
+const DefaultMaxPayload = 1 << 20
+
+// ErrFrameTooLarge is a frame whose header says its payload is over the limit.
+// The stream can't be read past it - the payload is still in the way - so the connection has to be closed
+var ErrFrameTooLarge = errors.New("framing: frame too large")
+
+// Kind is a frame's message type
+type Kind uint8
+
+// header is the wire layout of a frame's first HeaderSize bytes - sized fields only, so binary.Read
+// can fill it in
+type header struct {
+	Length uint32
+	Kind   Kind
+}
+
+// WriteFrame writes one frame to w. The header and payload go in a single Write, so frames from
+// goroutines sharing a net.Conn don't interleave (a net.Conn's Write is safe for concurrent use)
+func WriteFrame(w io.Writer, kind Kind, payload []byte) error {
+	if uint64(len(payload)) > 1<<32-1 {
+		return fmt.Errorf("%w: %d bytes doesn't fit a uint32", ErrFrameTooLarge, len(payload))
+	}
+	buf := make([]byte, 0, HeaderSize+len(payload))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = append(buf, byte(kind))
+	buf = append(buf, payload...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// ReadFrame reads one frame from r, refusing a payload over limit bytes (0 is DefaultMaxPayload).
+// r ending between frames is io.EOF - the other end is done; ending inside one is io.ErrUnexpectedEOF
+func ReadFrame(r io.Reader, limit int) (Kind, []byte, error) {
+	if limit == 0 {
+		limit = DefaultMaxPayload
+	}
+	var h header
+	if err := binary.Read(r, binary.BigEndian, &h); err != nil {
+		return 0, nil, err // binary.Read is io.EOF for no bytes at all, io.ErrUnexpectedEOF for some
+	}
+	if uint64(h.Length) > uint64(limit) {
+		return 0, nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrFrameTooLarge, h.Length, limit)
+	}
+	payload := make([]byte, h.Length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF // the header promised a payload
+		}
+		return 0, nil, err
+	}
+	return h.Kind, payload, nil
+}
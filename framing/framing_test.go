@@ -0,0 +1,228 @@
+package framing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	frames := []struct {
+		kind    Kind
+		payload []byte
+	}{
+		{KindGet, []byte(`"u1"`)},
+		{KindOK, nil},
+		{KindPut, bytes.Repeat([]byte{0xff, 0x00, '\n'}, 1000)}, // any bytes, a delimiter's included
+		{KindUser, []byte{}},
+	}
+	var buf bytes.Buffer
+	for _, f := range frames {
+		if err := WriteFrame(&buf, f.kind, f.payload); err != nil {
+			t.Fatalf("WriteFrame(%v): %v", f.kind, err)
+		}
+	}
+	wire := buf.Bytes()
+
+	for _, tc := range []struct {
+		name string
+		r    io.Reader
+	}{
+		{"whole", bytes.NewReader(wire)},
+		{"one byte per Read", iotest.OneByteReader(bytes.NewReader(wire))},
+		{"half the asked per Read", iotest.HalfReader(bytes.NewReader(wire))},
+		{"data with io.EOF", iotest.DataErrReader(bytes.NewReader(wire))},
+	} {
+		for i, f := range frames {
+			kind, payload, err := ReadFrame(tc.r, 0)
+			if err != nil || kind != f.kind || !bytes.Equal(payload, f.payload) {
+				t.Errorf("%s: frame %d = %v, %d bytes, %v; want %v, %d bytes", tc.name, i, kind, len(payload), err, f.kind, len(f.payload))
+			}
+		}
+		if _, _, err := ReadFrame(tc.r, 0); err != io.EOF {
+			t.Errorf("%s: after the last frame err = %v, want io.EOF", tc.name, err)
+		}
+	}
+}
+
+func TestFrameHeader(t *testing.T) {
+	var buf bytes.Buffer
+	WriteFrame(&buf, KindGet, []byte(`"u1"`))
+	want := []byte{0, 0, 0, 4, byte(KindGet), '"', 'u', '1', '"'}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("frame = % x, want % x", buf.Bytes(), want)
+	}
+}
+
+// A frame cut short is io.ErrUnexpectedEOF wherever the cut is - in the header, or in the payload it promised
+func TestReadFrameShort(t *testing.T) {
+	var buf bytes.Buffer
+	WriteFrame(&buf, KindPut, []byte("payload"))
+	wire := buf.Bytes()
+	for n := 1; n < len(wire); n++ {
+		for _, r := range []io.Reader{bytes.NewReader(wire[:n]), iotest.OneByteReader(bytes.NewReader(wire[:n]))} {
+			if _, _, err := ReadFrame(r, 0); err != io.ErrUnexpectedEOF {
+				t.Errorf("first %d of %d bytes: err = %v, want io.ErrUnexpectedEOF", n, len(wire), err)
+			}
+		}
+	}
+	if _, _, err := ReadFrame(bytes.NewReader(nil), 0); err != io.EOF {
+		t.Errorf("no bytes: err = %v, want io.EOF", err)
+	}
+}
+
+func TestReadFrameReaderError(t *testing.T) {
+	errBroken := errors.New("broken")
+	var buf bytes.Buffer
+	WriteFrame(&buf, KindPut, []byte("payload"))
+	r := io.MultiReader(bytes.NewReader(buf.Bytes()[:HeaderSize+2]), iotest.ErrReader(errBroken))
+	if _, _, err := ReadFrame(r, 0); err != errBroken {
+		t.Errorf("err = %v, want the reader's own error", err)
+	}
+}
+
+func TestReadFrameLimit(t *testing.T) {
+	header := func(length uint32) []byte {
+		return append(binary.BigEndian.AppendUint32(nil, length), byte(KindPut))
+	}
+	// Refused from the header alone: the reader has no payload, so reading one would fail some other way
+	for _, tc := range []struct {
+		length uint32
+		limit  int
+	}{
+		{0xffffffff, 0},
+		{DefaultMaxPayload + 1, 0},
+		{11, 10},
+	} {
+		if _, _, err := ReadFrame(bytes.NewReader(header(tc.length)), tc.limit); !errors.Is(err, ErrFrameTooLarge) {
+			t.Errorf("length %d, limit %d: err = %v, want ErrFrameTooLarge", tc.length, tc.limit, err)
+		}
+	}
+	var buf bytes.Buffer
+	WriteFrame(&buf, KindPut, make([]byte, 10))
+	if _, payload, err := ReadFrame(&buf, 10); err != nil || len(payload) != 10 {
+		t.Errorf("payload at the limit: %d bytes, %v", len(payload), err)
+	}
+}
+
+// startPipe serves srv on one end of a net.Pipe, and returns a Client on the other end
+// and a channel that gets ServeConn's error once the connection ends
+func startPipe(t *testing.T, srv *Server, codec Codec) (*Client, <-chan error) {
+	t.Helper()
+	clientEnd, serverEnd := net.Pipe()
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeConn(serverEnd) }()
+	c := NewClient(clientEnd, codec)
+	t.Cleanup(func() { c.Close() })
+	return c, served
+}
+
+var codecs = []struct {
+	name  string
+	codec Codec
+}{{"JSON", JSON}, {"gob", Gob}}
+
+func TestUsersOverPipe(t *testing.T) {
+	for _, tc := range codecs {
+		t.Run(tc.name, func(t *testing.T) {
+			c, served := startPipe(t, NewServer(tc.codec), tc.codec)
+			want := User{UserId: "u1", Name: "John Doe"}
+			if err := c.Put(want); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			if u, err := c.Get("u1"); err != nil || u != want {
+				t.Errorf("Get = %+v, %v; want %+v", u, err, want)
+			}
+
+			var remote *RemoteError
+			if _, err := c.Get("u2"); !errors.As(err, &remote) || remote.Message != `no user "u2"` {
+				t.Errorf("Get of a missing user: err = %v, want a RemoteError", err)
+			}
+			if err := c.Put(User{Name: "Nobody"}); !errors.As(err, &remote) {
+				t.Errorf("Put without a UserId: err = %v, want a RemoteError", err)
+			}
+			// the errors were replies, so the connection is still good
+			if _, err := c.Get("u1"); err != nil {
+				t.Errorf("Get after the error replies: %v", err)
+			}
+
+			c.Close()
+			if err := <-served; err != nil {
+				t.Errorf("ServeConn after the client closed = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// One Client shared by goroutines: each request and its reply are one round trip, so no reply reaches
+// the wrong caller
+func TestClientConcurrent(t *testing.T) {
+	srv := NewServer(Gob)
+	c, _ := startPipe(t, srv, Gob)
+	var wg sync.WaitGroup
+	for g := range 20 {
+		wg.Go(func() {
+			id := string(rune('a' + g))
+			if err := c.Put(User{UserId: id, Name: "Name " + id}); err != nil {
+				t.Errorf("Put %s: %v", id, err)
+				return
+			}
+			if u, err := c.Get(id); err != nil || u.Name != "Name "+id {
+				t.Errorf("Get %s = %+v, %v", id, u, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	// a second connection to the same server sees the first one's users
+	other, _ := startPipe(t, srv, Gob)
+	if u, err := other.Get("t"); err != nil || u.Name != "Name t" {
+		t.Errorf("Get from another connection = %+v, %v", u, err)
+	}
+}
+
+func TestServerBadFrames(t *testing.T) {
+	clientEnd, serverEnd := net.Pipe()
+	defer clientEnd.Close()
+	served := make(chan error, 1)
+	go func() { served <- NewServer(JSON).ServeConn(serverEnd) }()
+
+	// An unknown kind is the client's mistake: an error reply, and the connection goes on
+	if err := WriteFrame(clientEnd, 42, nil); err != nil {
+		t.Fatal(err)
+	}
+	if kind, payload, err := ReadFrame(clientEnd, 0); err != nil || kind != KindError || string(payload) != `"unknown request Kind(42)"` {
+		t.Errorf("reply to kind 42 = %v %s, %v; want an error frame", kind, payload, err)
+	}
+
+	// An oversized header is the stream's: the server hangs up
+	huge := binary.BigEndian.AppendUint32(nil, DefaultMaxPayload+1)
+	if _, err := clientEnd.Write(append(huge, byte(KindPut))); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadFrame(clientEnd, 0); err != io.EOF {
+		t.Errorf("read after an oversized frame: err = %v, want io.EOF", err)
+	}
+	if err := <-served; !errors.Is(err, ErrFrameTooLarge) {
+		t.Errorf("ServeConn = %v, want ErrFrameTooLarge", err)
+	}
+}
+
+func TestClientWrongReply(t *testing.T) {
+	clientEnd, serverEnd := net.Pipe()
+	c := NewClient(clientEnd, JSON)
+	defer c.Close()
+	go func() {
+		defer serverEnd.Close()
+		ReadFrame(serverEnd, 0)
+		WriteFrame(serverEnd, KindOK, nil) // a Get wants KindUser
+	}()
+	if _, err := c.Get("u1"); err == nil || err.Error() != "framing: ok reply to a get request" {
+		t.Errorf("err = %v, want the mismatched reply", err)
+	}
+}
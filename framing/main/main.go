@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"framing"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// === Ex. length-prefixed frames over a net.Conn, and a small User protocol on top ===
+
+// net.Pipe() is an in-memory net.Conn pair: what's written to one end is read from the other, with no
+// buffering - a Write blocks until Reads have taken all of it. So the examples need no network, except
+// the ones that show what TCP itself does
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func main() {
+	frameBytesExample()
+	// streamSplitExample()
+	// pipeUsersExample()
+	// tcpUsersExample()
+	// badFrameExample()
+}
+
+func frameBytesExample() {
+	var buf bytes.Buffer
+	framing.WriteFrame(&buf, framing.KindGet, []byte(`"u1"`))
+	framing.WriteFrame(&buf, framing.KindOK, nil)
+	fmt.Printf("two frames, %d bytes: % x\n", buf.Len(), buf.Bytes()) // 00 00 00 04 = length 4, 02 = KindGet
+
+	// However the bytes arrive - here one per Read - ReadFrame gets each frame whole, then io.EOF between frames
+	// (framing_test.go reads them in other splits too, and cut short at every byte)
+	r := &oneByteReader{bytes.NewReader(buf.Bytes())}
+	k1, p1, err1 := framing.ReadFrame(r, 0)
+	k2, p2, err2 := framing.ReadFrame(r, 0)
+	_, _, errEnd := framing.ReadFrame(r, 0)
+	fmt.Printf("read: %v %s, %v %q, then %v\n", k1, p1, k2, p2, errEnd)
+	fmt.Println("| ok: both frames whole, then io.EOF:", err1 == nil && err2 == nil && string(p1) == `"u1"` && len(p2) == 0 && errEnd == io.EOF)
+
+	// Cut short inside a frame is io.ErrUnexpectedEOF - in the header, or in the payload it promised
+	whole := buf.Bytes()[:framing.HeaderSize+4]
+	_, _, errHeader := framing.ReadFrame(bytes.NewReader(whole[:3]), 0)
+	_, _, errPayload := framing.ReadFrame(bytes.NewReader(whole[:7]), 0)
+	fmt.Println("| ok: cut in the header:", errHeader == io.ErrUnexpectedEOF, "| cut in the payload:", errPayload == io.ErrUnexpectedEOF)
+
+	// A length over the limit is refused before anything is allocated for it
+	huge := binary.BigEndian.AppendUint32(nil, 0xffffffff)
+	huge = append(huge, byte(framing.KindPut))
+	_, _, errHuge := framing.ReadFrame(bytes.NewReader(huge), 0)
+	fmt.Println(errHuge)
+	fmt.Println("| ok: ErrFrameTooLarge:", errors.Is(errHuge, framing.ErrFrameTooLarge))
+}
+
+// oneByteReader returns at most one byte per Read - as a slow connection might
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return r.r.Read(p[:1])
+}
+
+func streamSplitExample() {
+	// 1. One Write, several Reads: a reader taking 8 bytes at a time gets pieces of a frame.
+	// Assuming each Read is one message is the bug framing fixes
+	client, server := net.Pipe()
+	go func() {
+		framing.WriteFrame(client, framing.KindPut, []byte(`{"UserId":"u1","Name":"John Doe"}`))
+		client.Close()
+	}()
+	pieces := 0
+	buf := make([]byte, 8)
+	for {
+		n, err := server.Read(buf)
+		if err != nil {
+			break
+		}
+		pieces++
+		fmt.Printf("%q ", buf[:n])
+	}
+	fmt.Printf("\n| ok: one Write arrived in %d Reads: %v\n", pieces, pieces > 1)
+
+	// 2. Several Writes, one Read: over TCP, frames written before the other end reads sit in its buffer
+	// together, and a Read with room returns them all at once
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println("listen:", err)
+		return
+	}
+	defer ln.Close()
+	names := []string{"John Doe", "Jack Eod", "Grace Hopper"}
+	written := 0
+	for _, name := range names {
+		written += framing.HeaderSize + len(name)
+	}
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, name := range names {
+			framing.WriteFrame(conn, framing.KindPut, []byte(name))
+		}
+	}()
+	conn, err := ln.Accept()
+	if err != nil {
+		fmt.Println("accept:", err)
+		return
+	}
+	defer conn.Close()
+	time.Sleep(50 * time.Millisecond) // let all three arrive before reading
+	big := make([]byte, 4096)
+	n, _ := conn.Read(big)
+	fmt.Printf("one Read over TCP: %d bytes - all three frames (%d bytes) at once: %v\n", n, written, n == written)
+
+	// ReadFrame splits them again, from the bytes already read
+	r := bytes.NewReader(big[:n])
+	for {
+		kind, payload, err := framing.ReadFrame(r, 0)
+		if err != nil {
+			break
+		}
+		fmt.Printf("  frame: %v %q\n", kind, payload)
+	}
+}
+
+// startPipe serves srv on one end of a net.Pipe, and returns a Client on the other end -
+// and a channel that gets ServeConn's error once the connection ends
+func startPipe(srv *framing.Server, codec framing.Codec) (*framing.Client, <-chan error) {
+	clientEnd, serverEnd := net.Pipe()
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeConn(serverEnd) }()
+	return framing.NewClient(clientEnd, codec), served
+}
+
+func pipeUsersExample() {
+	for _, tc := range []struct {
+		name  string
+		codec framing.Codec
+	}{{"JSON", framing.JSON}, {"gob", framing.Gob}} {
+		c, served := startPipe(framing.NewServer(tc.codec), tc.codec)
+		errPut := c.Put(framing.User{UserId: userId1, Name: "John Doe"})
+		u, errGet := c.Get(userId1)
+		_, errMissing := c.Get(userId2)
+		errNoID := c.Put(framing.User{Name: "Nobody"})
+		c.Close()
+
+		var remote *framing.RemoteError
+		fmt.Printf("%-4s got %+v | missing: %v | no id: %v\n", tc.name, u, errMissing, errNoID)
+		fmt.Println("| ok: put and get:", errPut == nil && errGet == nil && u.Name == "John Doe",
+			"| errors are RemoteErrors:", errors.As(errMissing, &remote) && errors.As(errNoID, &remote),
+			"| server done, no error:", <-served == nil)
+	}
+
+	// The size of one User's payload: gob describes the type in every frame (see framing.Gob), JSON names
+	// the fields in every frame
+	u := framing.User{UserId: userId1, Name: "John Doe"}
+	j, _ := framing.JSON.Marshal(u)
+	g, _ := framing.Gob.Marshal(u)
+	fmt.Printf("one User: JSON %d bytes, gob %d bytes (+%d for each frame's header)\n", len(j), len(g), framing.HeaderSize)
+}
+
+func tcpUsersExample() {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println("listen:", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := framing.NewServer(framing.Gob)
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, ln) }()
+
+	// Three connections, each shared by four goroutines: each putting a user, then getting another's -
+	// frames from one Client's goroutines can't interleave, and each gets its own reply
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failures := 0
+	for conn := range 3 {
+		nc, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			fmt.Println("dial:", err)
+			return
+		}
+		c := framing.NewClient(nc, framing.Gob)
+		defer c.Close()
+		for g := range 4 {
+			wg.Go(func() {
+				id := fmt.Sprintf("user-%d-%d", conn, g)
+				err := c.Put(framing.User{UserId: id, Name: "Name " + id})
+				u, errGet := c.Get(id)
+				if err != nil || errGet != nil || u.Name != "Name "+id {
+					mu.Lock()
+					failures++
+					mu.Unlock()
+				}
+			})
+		}
+	}
+	wg.Wait()
+
+	// Any connection sees the others' users - they share the server's store
+	nc, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		fmt.Println("dial:", err)
+		return
+	}
+	other := framing.NewClient(nc, framing.Gob)
+	u, err := other.Get("user-2-3")
+	other.Close()
+	fmt.Printf("from a fourth connection: %+v %v\n", u, err)
+
+	cancel()
+	fmt.Println("| ok: 12 round trips over 3 connections:", failures == 0, "| Serve stopped with the context:", errors.Is(<-served, context.Canceled))
+}
+
+func badFrameExample() {
+	// An unknown kind is the client's mistake, not the stream's: the server replies with an error frame
+	// and goes on
+	clientEnd, serverEnd := net.Pipe()
+	srv := framing.NewServer(framing.JSON)
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeConn(serverEnd) }()
+
+	framing.WriteFrame(clientEnd, 42, nil)
+	kind, payload, _ := framing.ReadFrame(clientEnd, 0)
+	fmt.Printf("kind 42: the reply is %v %s\n", kind, payload)
+
+	// An oversized header is the stream's: nothing after it can be found, so the server hangs up
+	// and the client's next read is io.EOF
+	huge := binary.BigEndian.AppendUint32(nil, framing.DefaultMaxPayload+1)
+	clientEnd.Write(append(huge, byte(framing.KindPut)))
+	_, _, errRead := framing.ReadFrame(clientEnd, 0)
+	errServed := <-served
+	clientEnd.Close()
+	fmt.Println("server:", errServed)
+	fmt.Println("| ok: an error reply and on:", kind == framing.KindError,
+		"| too large closes the connection:", errors.Is(errServed, framing.ErrFrameTooLarge) && errRead == io.EOF)
+}
@@ -0,0 +1,204 @@
+package framing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// A tiny protocol on top of the frames: a client puts and gets Users, a server keeps them.
+// Every request frame gets one reply frame, in order, so a connection needs no request IDs
+// (the jsonrpc module has IDs, for replies that can come back in any order).
+//
+//	KindPut  User    -> KindOK, or KindError
+//	KindGet  user ID -> KindUser, or KindError
+//
+// The server works with any io.ReadWriteCloser: a net.Conn from a net.Listener, or one end of net.Pipe.
+
+// The protocol's frame kinds. 0 isn't one, so a zeroed header is never a valid frame
+const (
+	KindPut   Kind = iota + 1 // request: store the User in the payload
+	KindGet                   // request: the User with the ID in the payload
+	KindOK                    // reply: done, no payload
+	KindUser                  // reply: a User
+	KindError                 // reply: the payload is an error message
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindPut:
+		return "put"
+	case KindGet:
+		return "get"
+	case KindOK:
+		return "ok"
+	case KindUser:
+		return "user"
+	case KindError:
+		return "error"
+	}
+	return fmt.Sprintf("Kind(%d)", uint8(k))
+}
+
+// User is the basics notes' User
+type User struct {
+	UserId string
+	Name   string
+}
+
+// RemoteError is a KindError reply: the server's error, as its message
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string { return "server: " + e.Message }
+
+// Server stores Users for its clients. It's safe for concurrent use - each connection has its own goroutine
+type Server struct {
+	codec Codec
+	mu    sync.Mutex
+	users map[string]User
+}
+
+func NewServer(codec Codec) *Server {
+	return &Server{codec: codec, users: map[string]User{}}
+}
+
+// Serve accepts connections from ln until ctx is done or ln is closed, serving each in its own goroutine
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn answers conn's requests until it ends, then closes it. A frame that can't be read
+// ends the connection too: after a bad header the stream is out of step, and nothing after it is a frame.
+// It returns nil if the client closed the connection between frames
+func (s *Server) ServeConn(conn io.ReadWriteCloser) error {
+	defer conn.Close()
+	for {
+		kind, payload, err := ReadFrame(conn, 0)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		replyKind, reply, err := s.handle(kind, payload)
+		if err != nil {
+			replyKind, reply = KindError, s.errorPayload(err)
+		}
+		if err := WriteFrame(conn, replyKind, reply); err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers one request. Its error goes back to the client as a KindError frame
+func (s *Server) handle(kind Kind, payload []byte) (Kind, []byte, error) {
+	switch kind {
+	case KindPut:
+		var u User
+		if err := s.codec.Unmarshal(payload, &u); err != nil {
+			return 0, nil, fmt.Errorf("bad user: %v", err)
+		}
+		if u.UserId == "" {
+			return 0, nil, errors.New("a user needs a UserId")
+		}
+		s.mu.Lock()
+		s.users[u.UserId] = u
+		s.mu.Unlock()
+		return KindOK, nil, nil
+	case KindGet:
+		var id string
+		if err := s.codec.Unmarshal(payload, &id); err != nil {
+			return 0, nil, fmt.Errorf("bad user id: %v", err)
+		}
+		s.mu.Lock()
+		u, ok := s.users[id]
+		s.mu.Unlock()
+		if !ok {
+			return 0, nil, fmt.Errorf("no user %q", id)
+		}
+		data, err := s.codec.Marshal(u)
+		return KindUser, data, err
+	}
+	return 0, nil, fmt.Errorf("unknown request %v", kind)
+}
+
+func (s *Server) errorPayload(err error) []byte {
+	data, merr := s.codec.Marshal(err.Error())
+	if merr != nil {
+		return nil // a string always encodes; an empty payload is still a KindError
+	}
+	return data
+}
+
+// Client makes requests over one connection. It's safe for concurrent use: a request and its reply
+// are one round trip under a lock, so replies can't go to the wrong caller
+type Client struct {
+	conn  io.ReadWriteCloser
+	codec Codec
+	mu    sync.Mutex
+}
+
+func NewClient(conn io.ReadWriteCloser, codec Codec) *Client {
+	return &Client{conn: conn, codec: codec}
+}
+
+// Put stores u on the server
+func (c *Client) Put(u User) error {
+	return c.call(KindPut, u, KindOK, nil)
+}
+
+// Get returns the User with the id. One the server doesn't have is a *RemoteError
+func (c *Client) Get(id string) (User, error) {
+	var u User
+	err := c.call(KindGet, id, KindUser, &u)
+	return u, err
+}
+
+// call sends a request frame of v, and decodes the reply, which has to be of kind want, into out
+func (c *Client) call(kind Kind, v any, want Kind, out any) error {
+	payload, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := WriteFrame(c.conn, kind, payload); err != nil {
+		return err
+	}
+	got, reply, err := ReadFrame(c.conn, 0)
+	if err != nil {
+		return err
+	}
+	switch got {
+	case want:
+		if out == nil {
+			return nil
+		}
+		return c.codec.Unmarshal(reply, out)
+	case KindError:
+		var msg string
+		if err := c.codec.Unmarshal(reply, &msg); err != nil {
+			msg = "(unreadable error message)"
+		}
+		return &RemoteError{Message: msg}
+	}
+	return fmt.Errorf("framing: %v reply to a %v request", got, kind)
+}
+
+func (c *Client) Close() error { return c.conn.Close() }
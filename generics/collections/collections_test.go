@@ -0,0 +1,180 @@
+package collections
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// pushPopper is the method set Stack, LinkedStack, Queue and LinkedQueue share -
+// so the same tests run against all four
+type pushPopper[T any] interface {
+	Push(T)
+	Pop() (T, bool)
+	Peek() (T, bool)
+	Len() int
+}
+
+// matchesModel runs random pushes and pops on c and on a plain slice (the model), and reports the first
+// difference. lifo picks which end of the model Pop takes from
+func matchesModel(c pushPopper[int], lifo bool, ops int, r *rand.Rand) error {
+	var model []int
+	for i := range ops {
+		if r.IntN(3) > 0 { // push twice as often as pop, so the structure grows past its first buffer
+			c.Push(i)
+			model = append(model, i)
+		} else {
+			got, ok := c.Pop()
+			var want int
+			wantOK := len(model) > 0
+			if wantOK && lifo {
+				want, model = model[len(model)-1], model[:len(model)-1]
+			} else if wantOK {
+				want, model = model[0], model[1:]
+			}
+			if got != want || ok != wantOK {
+				return fmt.Errorf("op %d: Pop() = %d, %t, want %d, %t", i, got, ok, want, wantOK)
+			}
+		}
+		peek, ok := c.Peek()
+		if c.Len() != len(model) || ok != (len(model) > 0) {
+			return fmt.Errorf("op %d: Len() = %d, want %d", i, c.Len(), len(model))
+		}
+		if ok && ((lifo && peek != model[len(model)-1]) || (!lifo && peek != model[0])) {
+			return fmt.Errorf("op %d: Peek() = %d", i, peek)
+		}
+	}
+	for len(model) > 0 { // and empty it again
+		c.Pop()
+		model = model[1:]
+	}
+	if _, ok := c.Pop(); ok || c.Len() != 0 {
+		return fmt.Errorf("not empty after popping everything: Len() = %d", c.Len())
+	}
+	return nil
+}
+
+// dequeMatchesModel is the same for a Deque, with pushes and pops at both ends
+func dequeMatchesModel(d *Deque[int], ops int, r *rand.Rand) error {
+	var model []int
+	for i := range ops {
+		var got, want int
+		var ok, wantOK bool
+		switch r.IntN(6) {
+		case 0, 1:
+			d.PushBack(i)
+			model = append(model, i)
+		case 2, 3:
+			d.PushFront(i)
+			model = slices.Insert(model, 0, i)
+		case 4:
+			got, ok = d.PopFront()
+			if wantOK = len(model) > 0; wantOK {
+				want, model = model[0], model[1:]
+			}
+		case 5:
+			got, ok = d.PopBack()
+			if wantOK = len(model) > 0; wantOK {
+				want, model = model[len(model)-1], model[:len(model)-1]
+			}
+		}
+		if got != want || ok != wantOK || d.Len() != len(model) {
+			return fmt.Errorf("op %d: got %d, %t (Len %d), want %d, %t (Len %d)", i, got, ok, d.Len(), want, wantOK, len(model))
+		}
+		front, okF := d.PeekFront()
+		back, okB := d.PeekBack()
+		if len(model) > 0 && (!okF || !okB || front != model[0] || back != model[len(model)-1]) {
+			return fmt.Errorf("op %d: PeekFront/PeekBack = %d, %d", i, front, back)
+		}
+	}
+	return nil
+}
+
+// Each against the model, with random operations (10000 of them, so every buffer grows and wraps)
+func TestMatchesModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, c := range []struct {
+		name string
+		c    pushPopper[int]
+		lifo bool
+	}{
+		{"Stack", &Stack[int]{}, true},
+		{"LinkedStack", &LinkedStack[int]{}, true},
+		{"Queue", &Queue[int]{}, false},
+		{"LinkedQueue", &LinkedQueue[int]{}, false},
+	} {
+		if err := matchesModel(c.c, c.lifo, 10_000, r); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+	}
+	if err := dequeMatchesModel(&Deque[int]{}, 10_000, r); err != nil {
+		t.Errorf("Deque: %v", err)
+	}
+}
+
+// Empty and nil: Len is 0 and Pop/Peek return false, no panics
+func TestNilAndEmpty(t *testing.T) {
+	for name, c := range map[string]pushPopper[int]{
+		"nil Stack": (*Stack[int])(nil), "nil LinkedStack": (*LinkedStack[int])(nil),
+		"nil Queue": (*Queue[int])(nil), "nil LinkedQueue": (*LinkedQueue[int])(nil),
+		"empty Stack": &Stack[int]{}, "empty Queue": &Queue[int]{},
+	} {
+		_, popOK := c.Pop()
+		_, peekOK := c.Peek()
+		if c.Len() != 0 || popOK || peekOK {
+			t.Errorf("%s: Len %d, Pop %t, Peek %t", name, c.Len(), popOK, peekOK)
+		}
+	}
+	var d *Deque[int]
+	_, ok1 := d.PopFront()
+	_, ok2 := d.PopBack()
+	_, ok3 := d.PeekFront()
+	_, ok4 := d.PeekBack()
+	if d.Len() != 0 || ok1 || ok2 || ok3 || ok4 {
+		t.Error("nil Deque isn't empty")
+	}
+}
+
+// Popped values aren't kept alive by the slice behind the stack or queue
+func TestPopClears(t *testing.T) {
+	var s Stack[*int]
+	s.Push(new(int))
+	s.Pop()
+	if s.items[:1][0] != nil {
+		t.Error("Stack: popped pointer still in the backing array")
+	}
+	var q Queue[*int]
+	q.Push(new(int))
+	q.Pop()
+	if q.buf[0] != nil {
+		t.Error("Queue: popped pointer still in the buffer")
+	}
+}
+
+// Slice backed vs linked: push 1000, pop 1000. The slice backed ones allocate a handful of times
+// (each time the buffer doubles), the linked ones once per value
+func BenchmarkPushPop(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		make func() pushPopper[int]
+	}{
+		{"Stack", func() pushPopper[int] { return &Stack[int]{} }},
+		{"LinkedStack", func() pushPopper[int] { return &LinkedStack[int]{} }},
+		{"Queue", func() pushPopper[int] { return &Queue[int]{} }},
+		{"LinkedQueue", func() pushPopper[int] { return &LinkedQueue[int]{} }},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				c := bm.make()
+				for i := range 1000 {
+					c.Push(i)
+				}
+				for c.Len() > 0 {
+					c.Pop()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package collections
+
+// Deque (double-ended queue) can push and pop at both ends - a Stack and a Queue at once.
+// The same ring buffer as Queue: PushFront steps front back by one instead of forward
+type Deque[T any] struct {
+	buf   []T
+	front int
+	n     int
+}
+
+func (d *Deque[T]) PushBack(v T) {
+	if d.n == len(d.buf) {
+		d.grow()
+	}
+	d.buf[d.at(d.n)] = v
+	d.n++
+}
+
+func (d *Deque[T]) PushFront(v T) {
+	if d.n == len(d.buf) {
+		d.grow()
+	}
+	d.front = (d.front - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.front] = v
+	d.n++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.Len() == 0 {
+		return zero, false
+	}
+	v := d.buf[d.front]
+	d.buf[d.front] = zero
+	d.front = (d.front + 1) % len(d.buf)
+	d.n--
+	return v, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.Len() == 0 {
+		return zero, false
+	}
+	i := d.at(d.n - 1)
+	v := d.buf[i]
+	d.buf[i] = zero
+	d.n--
+	return v, true
+}
+
+func (d *Deque[T]) PeekFront() (T, bool) {
+	if d.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.front], true
+}
+
+func (d *Deque[T]) PeekBack() (T, bool) {
+	if d.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.buf[d.at(d.n-1)], true
+}
+
+func (d *Deque[T]) Len() int {
+	if d == nil {
+		return 0
+	}
+	return d.n
+}
+
+// at is the buffer index of the i-th value from the front
+func (d *Deque[T]) at(i int) int {
+	return (d.front + i) % len(d.buf)
+}
+
+func (d *Deque[T]) grow() {
+	buf := make([]T, max(2*len(d.buf), 4))
+	for i := range d.n {
+		buf[i] = d.buf[d.at(i)]
+	}
+	d.buf, d.front = buf, 0
+}
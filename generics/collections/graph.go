@@ -0,0 +1,150 @@
+package collections
+
+import (
+	"iter"
+	"slices"
+)
+
+// Graph is a directed graph over adjacency lists: each node maps to the nodes its edges go to.
+// An undirected graph is one with every edge added both ways (AddEdge(a, b) and AddEdge(b, a)).
+// Nodes are kept in the order they were first seen, and neighbors in the order their edges were added,
+// so the traversals visit in a fixed order - a map alone would make BFS and DFS differ from run to run.
+// The zero value is an empty graph ready to use.
+type Graph[T comparable] struct {
+	adj   map[T][]T
+	nodes []T
+}
+
+// AddNode adds v with no edges, if it isn't in the graph already
+func (g *Graph[T]) AddNode(v T) {
+	if g.adj == nil {
+		g.adj = map[T][]T{}
+	}
+	if _, ok := g.adj[v]; !ok {
+		g.adj[v] = nil
+		g.nodes = append(g.nodes, v)
+	}
+}
+
+// AddEdge adds an edge from -> to, adding the nodes if needed. Adding an edge twice keeps one
+func (g *Graph[T]) AddEdge(from, to T) {
+	g.AddNode(from)
+	g.AddNode(to)
+	if !slices.Contains(g.adj[from], to) {
+		g.adj[from] = append(g.adj[from], to)
+	}
+}
+
+func (g *Graph[T]) Has(v T) bool {
+	if g == nil {
+		return false
+	}
+	_, ok := g.adj[v]
+	return ok
+}
+
+// Neighbors are the nodes v has an edge to, in the order the edges were added
+func (g *Graph[T]) Neighbors(v T) []T {
+	if g == nil {
+		return nil
+	}
+	return slices.Clone(g.adj[v]) // a copy - appending to it mustn't add edges
+}
+
+// Nodes iterates over every node, in the order they were added
+func (g *Graph[T]) Nodes() iter.Seq[T] {
+	if g == nil {
+		return func(func(T) bool) {}
+	}
+	return slices.Values(g.nodes)
+}
+
+func (g *Graph[T]) Len() int {
+	if g == nil {
+		return 0
+	}
+	return len(g.nodes)
+}
+
+// BFS returns the nodes reachable from start, nearest first: start, then its neighbors, then theirs...
+// A node is marked seen when it's queued, not when it's visited, so it's queued once however many edges lead to it
+func (g *Graph[T]) BFS(start T) []T {
+	if !g.Has(start) {
+		return nil
+	}
+	order := []T{}
+	seen := map[T]bool{start: true}
+	var q Queue[T]
+	q.Push(start)
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		order = append(order, v)
+		for _, n := range g.adj[v] {
+			if !seen[n] {
+				seen[n] = true
+				q.Push(n)
+			}
+		}
+	}
+	return order
+}
+
+// DFS returns the nodes reachable from start, each branch followed to its end before the next:
+// the same order as the recursive version (visit v, then DFS each neighbor in turn), with a Stack instead of
+// the call stack, so a long chain can't overflow. Neighbors are pushed last first, so the first one pops first
+func (g *Graph[T]) DFS(start T) []T {
+	if !g.Has(start) {
+		return nil
+	}
+	order := []T{}
+	seen := map[T]bool{}
+	var s Stack[T]
+	s.Push(start)
+	for s.Len() > 0 {
+		v, _ := s.Pop()
+		if seen[v] { // pushed more than once, by different nodes, before it was visited
+			continue
+		}
+		seen[v] = true
+		order = append(order, v)
+		for _, n := range slices.Backward(g.adj[v]) {
+			if !seen[n] {
+				s.Push(n)
+			}
+		}
+	}
+	return order
+}
+
+// ShortestPath is a path from -> to with the fewest edges, both ends included, or false if to can't be reached.
+// It's a BFS that remembers how it got to each node: BFS reaches each node first by a shortest path,
+// so following prev back from to gives one (the first found, when there are several)
+func (g *Graph[T]) ShortestPath(from, to T) ([]T, bool) {
+	if !g.Has(from) || !g.Has(to) {
+		return nil, false
+	}
+	prev := map[T]T{}
+	seen := map[T]bool{from: true}
+	var q Queue[T]
+	q.Push(from)
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		if v == to {
+			path := []T{to}
+			for v != from {
+				v = prev[v]
+				path = append(path, v)
+			}
+			slices.Reverse(path)
+			return path, true
+		}
+		for _, n := range g.adj[v] {
+			if !seen[n] {
+				seen[n] = true
+				prev[n] = v
+				q.Push(n)
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,127 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+// graphOf builds a directed graph from edges given as pairs
+func graphOf[T comparable](edges ...[2]T) *Graph[T] {
+	g := &Graph[T]{}
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1])
+	}
+	return g
+}
+
+// testGrid is a 2x3 grid of cells 0..5, undirected (each edge both ways), and a node 9 on its own:
+//
+//	0 - 1 - 2
+//	|       |
+//	3 - 4 - 5
+func testGrid() *Graph[int] {
+	g := &Graph[int]{}
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {0, 3}, {2, 5}, {3, 4}, {4, 5}} {
+		g.AddEdge(e[0], e[1])
+		g.AddEdge(e[1], e[0])
+	}
+	g.AddNode(9)
+	return g
+}
+
+func TestTraversals(t *testing.T) {
+	grid := testGrid()
+	cycle := graphOf([2]int{1, 2}, [2]int{2, 3}, [2]int{3, 1})
+	diamond := graphOf([2]int{1, 2}, [2]int{1, 3}, [2]int{2, 4}, [2]int{3, 4}) // 4 reached twice
+	for _, tc := range []struct {
+		name     string
+		g        *Graph[int]
+		start    int
+		bfs, dfs []int
+	}{
+		{"grid from a corner", grid, 0, []int{0, 1, 3, 2, 4, 5}, []int{0, 1, 2, 5, 4, 3}},
+		{"grid from the middle", grid, 4, []int{4, 3, 5, 0, 2, 1}, []int{4, 3, 0, 1, 2, 5}},
+		{"a node with no edges", grid, 9, []int{9}, []int{9}},
+		{"a cycle ends where it started", cycle, 2, []int{2, 3, 1}, []int{2, 3, 1}},
+		{"two ways to the same node", diamond, 1, []int{1, 2, 3, 4}, []int{1, 2, 4, 3}},
+		{"directed: edges only lead one way", diamond, 2, []int{2, 4}, []int{2, 4}},
+		{"not in the graph", grid, 7, nil, nil},
+		{"the zero value graph", &Graph[int]{}, 0, nil, nil},
+		{"a nil graph", nil, 0, nil, nil},
+	} {
+		if got := tc.g.BFS(tc.start); !slices.Equal(got, tc.bfs) {
+			t.Errorf("%s: BFS = %v, want %v", tc.name, got, tc.bfs)
+		}
+		if got := tc.g.DFS(tc.start); !slices.Equal(got, tc.dfs) {
+			t.Errorf("%s: DFS = %v, want %v", tc.name, got, tc.dfs)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	grid := testGrid()
+	for _, tc := range []struct {
+		name     string
+		from, to int
+		want     []int
+		found    bool
+	}{
+		{"across the grid", 0, 5, []int{0, 1, 2, 5}, true}, // 0-3-4-5 is as short - BFS finds 1's branch first
+		{"the other way", 5, 0, []int{5, 2, 1, 0}, true},
+		{"a neighbor", 1, 2, []int{1, 2}, true},
+		{"to itself", 4, 4, []int{4}, true},
+		{"to the node on its own", 0, 9, nil, false},
+		{"from a missing node", 7, 0, nil, false},
+		{"to a missing node", 0, 7, nil, false},
+	} {
+		got, found := grid.ShortestPath(tc.from, tc.to)
+		if !slices.Equal(got, tc.want) || found != tc.found {
+			t.Errorf("%s: ShortestPath(%d, %d) = %v, %t, want %v, %t", tc.name, tc.from, tc.to, got, found, tc.want, tc.found)
+		}
+	}
+	if _, found := graphOf([2]string{"a", "b"}).ShortestPath("b", "a"); found {
+		t.Error("found a path back along a directed edge")
+	}
+}
+
+func TestGraphBuilding(t *testing.T) {
+	g := graphOf([2]string{"a", "b"}, [2]string{"a", "c"}, [2]string{"a", "b"}) // a -> b twice
+	g.AddNode("d")
+	g.AddNode("a") // already there: keeps its edges
+	if got := g.Neighbors("a"); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("Neighbors(a) = %v, want [b c] - a repeated edge is kept once", got)
+	}
+	if got := slices.Collect(g.Nodes()); !slices.Equal(got, []string{"a", "b", "c", "d"}) || g.Len() != 4 {
+		t.Errorf("Nodes = %v, Len %d, want [a b c d] in the order added", got, g.Len())
+	}
+	if !g.Has("d") || g.Has("e") || len(g.Neighbors("d")) != 0 || g.Neighbors("e") != nil {
+		t.Error("Has or Neighbors wrong for a node with no edges or a missing node")
+	}
+
+	n := g.Neighbors("a")
+	n[0] = "oops"
+	_ = append(n, "more")
+	if got := g.Neighbors("a"); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("changing the result of Neighbors changed the graph: %v", got)
+	}
+
+	var none *Graph[string]
+	if none.Len() != 0 || none.Has("a") || len(slices.Collect(none.Nodes())) != 0 {
+		t.Error("a nil graph isn't empty")
+	}
+}
+
+// DFS uses a Stack, not recursion: a chain far longer than a recursive walk would want is fine
+func TestDFSLongChain(t *testing.T) {
+	const n = 200_000
+	g := &Graph[int]{}
+	for i := range n - 1 {
+		g.AddEdge(i, i+1)
+	}
+	if order := g.DFS(0); len(order) != n || order[n-1] != n-1 {
+		t.Errorf("DFS down a chain of %d visited %d nodes", n, len(order))
+	}
+	if path, ok := g.ShortestPath(0, n-1); !ok || len(path) != n {
+		t.Errorf("ShortestPath down the chain: %d nodes, %t", len(path), ok)
+	}
+}
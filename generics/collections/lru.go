@@ -0,0 +1,109 @@
+package collections
+
+// LRU is a cache holding at most capacity entries. When it's full, Put evicts the entry that was used
+// least recently - Get and Put both count as a use.
+//
+// Two structures, each doing what the other can't:
+//   - a map from key to list node: finding an entry is O(1)
+//   - a doubly linked list in order of use, most recent at the front: moving a node to the front,
+//     or dropping the last one, is O(1) - with prev pointers a node can be unlinked from where it is
+//
+// A sentinel (a node that holds no entry) links the list into a circle: root.next is the front, root.prev
+// the back, and an empty list is root pointing at itself - no nil checks at the ends.
+//
+// Not safe for concurrent use: Get changes the list too, so even readers would need the lock (a Mutex, not an RWMutex).
+type LRU[K comparable, V any] struct {
+	capacity int
+	items    map[K]*lruNode[K, V]
+	root     lruNode[K, V]
+	onEvict  func(K, V)
+}
+
+type lruNode[K comparable, V any] struct {
+	key        K
+	val        V
+	prev, next *lruNode[K, V]
+}
+
+// NewLRU returns an empty cache for capacity entries (at least 1). onEvict, if not nil,
+// is called with each entry Put evicts to make room - not for entries replaced by a Put of the same key
+func NewLRU[K comparable, V any](capacity int, onEvict func(key K, val V)) *LRU[K, V] {
+	c := &LRU[K, V]{capacity: max(capacity, 1), items: make(map[K]*lruNode[K, V]), onEvict: onEvict}
+	c.root.next, c.root.prev = &c.root, &c.root
+	return c
+}
+
+// Get returns the value for key and marks it as just used
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	n, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.moveToFront(n)
+	return n.val, true
+}
+
+// Put stores val under key as the most recently used entry, evicting the least recently used one if full
+func (c *LRU[K, V]) Put(key K, val V) {
+	if n, ok := c.items[key]; ok {
+		n.val = val
+		c.moveToFront(n)
+		return
+	}
+	if len(c.items) == c.capacity {
+		c.evict(c.root.prev)
+	}
+	n := &lruNode[K, V]{key: key, val: val}
+	c.items[key] = n
+	c.insertFront(n)
+}
+
+// Remove deletes key, without calling onEvict, and reports whether it was there
+func (c *LRU[K, V]) Remove(key K) bool {
+	n, ok := c.items[key]
+	if ok {
+		c.unlink(n)
+		delete(c.items, key)
+	}
+	return ok
+}
+
+func (c *LRU[K, V]) Len() int { return len(c.items) }
+
+// Keys returns the keys from most to least recently used, without counting as a use
+func (c *LRU[K, V]) Keys() []K {
+	keys := make([]K, 0, len(c.items))
+	for n := c.root.next; n != &c.root; n = n.next {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+func (c *LRU[K, V]) evict(n *lruNode[K, V]) {
+	c.unlink(n)
+	delete(c.items, n.key)
+	if c.onEvict != nil {
+		c.onEvict(n.key, n.val)
+	}
+}
+
+func (c *LRU[K, V]) moveToFront(n *lruNode[K, V]) {
+	if c.root.next == n {
+		return
+	}
+	c.unlink(n)
+	c.insertFront(n)
+}
+
+func (c *LRU[K, V]) insertFront(n *lruNode[K, V]) {
+	n.prev, n.next = &c.root, c.root.next
+	c.root.next.prev = n
+	c.root.next = n
+}
+
+func (c *LRU[K, V]) unlink(n *lruNode[K, V]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev, n.next = nil, nil // a removed node mustn't keep the list (or the list it) alive
+}
@@ -0,0 +1,119 @@
+package collections
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// lruModel is the same cache as a slice of keys, most recent first - O(n) per operation, but obviously right
+type lruModel struct {
+	capacity int
+	keys     []string
+	vals     map[string]int
+	evicted  []string
+}
+
+func (m *lruModel) use(key string) {
+	m.keys = slices.Insert(slices.DeleteFunc(m.keys, func(k string) bool { return k == key }), 0, key)
+}
+
+func (m *lruModel) get(key string) (int, bool) {
+	v, ok := m.vals[key]
+	if ok {
+		m.use(key)
+	}
+	return v, ok
+}
+
+func (m *lruModel) put(key string, v int) {
+	if _, ok := m.vals[key]; !ok && len(m.keys) == m.capacity {
+		oldest := m.keys[len(m.keys)-1]
+		m.keys = m.keys[:len(m.keys)-1]
+		delete(m.vals, oldest)
+		m.evicted = append(m.evicted, oldest)
+	}
+	m.vals[key] = v
+	m.use(key)
+}
+
+// Eviction order against the model, over random operations on 8 keys with room for 4
+func TestLRUMatchesModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	var evicted []string
+	lru := NewLRU(4, func(k string, _ int) { evicted = append(evicted, k) })
+	model := &lruModel{capacity: 4, vals: map[string]int{}}
+	for i := range 10_000 {
+		key := string(rune('a' + r.IntN(8)))
+		if r.IntN(2) == 0 {
+			lru.Put(key, i)
+			model.put(key, i)
+		} else {
+			got, ok := lru.Get(key)
+			want, wantOK := model.get(key)
+			if got != want || ok != wantOK {
+				t.Fatalf("op %d: Get(%s) = %d, %t, want %d, %t", i, key, got, ok, want, wantOK)
+			}
+		}
+		if !slices.Equal(lru.Keys(), model.keys) {
+			t.Fatalf("op %d: Keys() = %v, want %v", i, lru.Keys(), model.keys)
+		}
+	}
+	if !slices.Equal(evicted, model.evicted) {
+		t.Errorf("%d evictions, model %d, or in a different order", len(evicted), len(model.evicted))
+	}
+}
+
+func TestLRUEvictionOrder(t *testing.T) {
+	var evicted []string
+	c := NewLRU(3, func(k string, v int) { evicted = append(evicted, fmt.Sprintf("%s=%d", k, v)) })
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")     // b is now the oldest
+	c.Put("d", 4)  // evicts b
+	c.Put("c", 30) // an update: no eviction, c moves to the front
+	c.Put("e", 5)  // evicts a
+	if want := []string{"b=2", "a=1"}; !slices.Equal(evicted, want) {
+		t.Errorf("evicted %v, want %v", evicted, want)
+	}
+	if want := []string{"e", "c", "d"}; !slices.Equal(c.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", c.Keys(), want)
+	}
+	if v, _ := c.Get("c"); v != 30 {
+		t.Errorf("Get(c) = %d, want the updated 30", v)
+	}
+	// Keys doesn't count as a use
+	c.Keys()
+	c.Put("f", 6)
+	if evicted[len(evicted)-1] != "d=4" {
+		t.Errorf("after Keys() and Put(f), evicted %v, want d last", evicted)
+	}
+}
+
+func TestLRURemove(t *testing.T) {
+	calls := 0
+	c := NewLRU(2, func(string, int) { calls++ })
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if !c.Remove("a") || c.Remove("a") || c.Len() != 1 {
+		t.Errorf("Remove: Len %d after removing a twice", c.Len())
+	}
+	c.Put("c", 3) // room after the Remove - nothing evicted
+	if calls != 0 || !slices.Equal(c.Keys(), []string{"c", "b"}) {
+		t.Errorf("onEvict called %d times, Keys %v; want 0 and [c b]", calls, c.Keys())
+	}
+}
+
+// A capacity below 1 is 1 - the cache always keeps the last Put
+func TestLRUSmallCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -5, 1} {
+		c := NewLRU[int, int](capacity, nil)
+		c.Put(1, 1)
+		c.Put(2, 2)
+		if _, has1 := c.Get(1); has1 || c.Len() != 1 {
+			t.Errorf("capacity %d: Len %d, has 1: %t, want only 2 kept", capacity, c.Len(), has1)
+		}
+	}
+}
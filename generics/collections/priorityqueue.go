@@ -0,0 +1,73 @@
+package collections
+
+import "container/heap"
+
+// PriorityQueue pops the "smallest" value first, by a less function given to NewPriorityQueue -
+// less(a, b) true means a comes out before b. It's a binary heap from container/heap:
+// Push and Pop are O(log n), Peek is O(1).
+//
+// container/heap works on anything with its five methods (Len, Less, Swap, Push, Pop - with any values),
+// so the generic part is a small adapter from a []T and a less func to that interface. The exported methods
+// are the typed ones; users never see heap.Interface or any.
+//
+// Equal values come out in no particular order - a heap isn't stable. Break ties in less if order matters
+// (ex. by an insertion counter, as the scheduler example does).
+//
+// hashing/pqueue is the same heap adapter, with Item handles and Fix on top (the top-k counters change
+// a count in place). It's a copy rather than a shared package because the two are separate modules, and
+// a module can't import another one here (no go.work): this one stays the small Push/Pop/Peek version.
+type PriorityQueue[T any] struct {
+	h pqHeap[T]
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: pqHeap[T]{less: less}}
+}
+
+func (q *PriorityQueue[T]) Push(v T) { heap.Push(&q.h, v) }
+
+// Pop removes and returns the first value by less
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if q.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(&q.h).(T), true
+}
+
+// Peek returns the value Pop would, without removing it
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if q.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.h.items[0], true // the root of the heap
+}
+
+func (q *PriorityQueue[T]) Len() int {
+	if q == nil {
+		return 0
+	}
+	return len(q.h.items)
+}
+
+// pqHeap implements heap.Interface. Push and Pop here are for container/heap to call -
+// they only add and remove at the end of the slice, heap.Push and heap.Pop do the reordering
+type pqHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h pqHeap[T]) Len() int           { return len(h.items) }
+func (h pqHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h pqHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *pqHeap[T]) Push(x any)        { h.items = append(h.items, x.(T)) }
+
+func (h *pqHeap[T]) Pop() any {
+	last := len(h.items) - 1
+	v := h.items[last]
+	var zero T
+	h.items[last] = zero
+	h.items = h.items[:last]
+	return v
+}
@@ -0,0 +1,80 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Popping everything gives the values sorted by less (a heap sort) - for a min-heap and, with the
+// opposite less, a max-heap
+func TestPriorityQueueSorts(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	minQ := NewPriorityQueue(func(a, b int) bool { return a < b })
+	maxQ := NewPriorityQueue(func(a, b int) bool { return a > b })
+	var in []int
+	for range 1000 {
+		v := r.IntN(100)
+		in = append(in, v)
+		minQ.Push(v)
+		maxQ.Push(v)
+	}
+	if top, ok := minQ.Peek(); !ok || top != slices.Min(in) || minQ.Len() != 1000 {
+		t.Errorf("Peek = %d, %t (Len %d), want the min %d without removing it", top, ok, minQ.Len(), slices.Min(in))
+	}
+	var ascending, descending []int
+	for minQ.Len() > 0 {
+		v, _ := minQ.Pop()
+		ascending = append(ascending, v)
+		w, _ := maxQ.Pop()
+		descending = append(descending, w)
+	}
+	want := slices.Sorted(slices.Values(in))
+	if !slices.Equal(ascending, want) {
+		t.Error("min-heap pops aren't the input sorted")
+	}
+	slices.Reverse(want)
+	if !slices.Equal(descending, want) {
+		t.Error("max-heap pops aren't the input sorted in reverse")
+	}
+}
+
+// Interleaved Push and Pop: each Pop is the min of what's in the queue at that moment
+func TestPriorityQueueInterleaved(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+	var model []int
+	for i := range 2000 {
+		if r.IntN(3) > 0 {
+			v := r.IntN(1000)
+			q.Push(v)
+			model = append(model, v)
+			continue
+		}
+		got, ok := q.Pop()
+		if len(model) == 0 {
+			if ok {
+				t.Fatalf("op %d: Pop on empty = %d, true", i, got)
+			}
+			continue
+		}
+		m := slices.Min(model)
+		model = slices.Delete(model, slices.Index(model, m), slices.Index(model, m)+1)
+		if !ok || got != m {
+			t.Fatalf("op %d: Pop = %d, %t, want %d", i, got, ok, m)
+		}
+	}
+}
+
+func TestPriorityQueueEmpty(t *testing.T) {
+	q := NewPriorityQueue(func(a, b string) bool { return a < b })
+	_, popOK := q.Pop()
+	_, peekOK := q.Peek()
+	if popOK || peekOK || q.Len() != 0 {
+		t.Error("empty queue: Pop or Peek ok")
+	}
+	var nilQ *PriorityQueue[int]
+	if nilQ.Len() != 0 {
+		t.Error("nil queue Len isn't 0")
+	}
+}
@@ -0,0 +1,102 @@
+package collections
+
+// Queue is first in, first out, over a ring buffer: a slice used in a circle, front marking where the queue
+// starts. Popping moves front instead of shifting everything left (which q = q[1:] does for free, but then
+// the start of the array is never reused - and a long-lived queue keeps growing)
+type Queue[T any] struct {
+	buf   []T
+	front int
+	n     int
+}
+
+// Push adds v at the back
+func (q *Queue[T]) Push(v T) {
+	if q.n == len(q.buf) {
+		q.grow()
+	}
+	q.buf[(q.front+q.n)%len(q.buf)] = v
+	q.n++
+}
+
+// Pop removes the value at the front
+func (q *Queue[T]) Pop() (T, bool) {
+	var zero T
+	if q.Len() == 0 {
+		return zero, false
+	}
+	v := q.buf[q.front]
+	q.buf[q.front] = zero
+	q.front = (q.front + 1) % len(q.buf)
+	q.n--
+	return v, true
+}
+
+func (q *Queue[T]) Peek() (T, bool) {
+	if q.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.buf[q.front], true
+}
+
+func (q *Queue[T]) Len() int {
+	if q == nil {
+		return 0
+	}
+	return q.n
+}
+
+// grow doubles the buffer, copying the values in queue order to its start
+func (q *Queue[T]) grow() {
+	buf := make([]T, max(2*len(q.buf), 4))
+	for i := range q.n {
+		buf[i] = q.buf[(q.front+i)%len(q.buf)]
+	}
+	q.buf, q.front = buf, 0
+}
+
+// LinkedQueue is a Queue as a singly linked list: Push adds at the tail, Pop takes from the head
+type LinkedQueue[T any] struct {
+	head, tail *node[T]
+	n          int
+}
+
+func (q *LinkedQueue[T]) Push(v T) {
+	nd := &node[T]{val: v}
+	if q.tail == nil {
+		q.head = nd
+	} else {
+		q.tail.next = nd
+	}
+	q.tail = nd
+	q.n++
+}
+
+func (q *LinkedQueue[T]) Pop() (T, bool) {
+	if q.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	v := q.head.val
+	q.head = q.head.next
+	if q.head == nil {
+		q.tail = nil
+	}
+	q.n--
+	return v, true
+}
+
+func (q *LinkedQueue[T]) Peek() (T, bool) {
+	if q.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.head.val, true
+}
+
+func (q *LinkedQueue[T]) Len() int {
+	if q == nil {
+		return 0
+	}
+	return q.n
+}
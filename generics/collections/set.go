@@ -0,0 +1,109 @@
+package collections
+
+import (
+	"iter"
+	"maps"
+)
+
+// Set is an unordered collection of distinct values, over map[T]struct{} - the map keys are the set,
+// and struct{} takes no memory. T must be comparable, since it's a map key.
+// Like the other types here, the zero value is an empty set ready to use, and a nil *Set reads as empty.
+// The set operations return a new set and leave both inputs unchanged.
+type Set[T comparable] struct {
+	m map[T]struct{}
+}
+
+func NewSet[T comparable](vs ...T) *Set[T] {
+	s := &Set[T]{}
+	s.Add(vs...)
+	return s
+}
+
+func (s *Set[T]) Add(vs ...T) {
+	if s.m == nil {
+		s.m = make(map[T]struct{}, len(vs))
+	}
+	for _, v := range vs {
+		s.m[v] = struct{}{}
+	}
+}
+
+func (s *Set[T]) Remove(v T) {
+	if s != nil {
+		delete(s.m, v) // delete on a nil map does nothing
+	}
+}
+
+func (s *Set[T]) Contains(v T) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.m[v]
+	return ok
+}
+
+func (s *Set[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.m)
+}
+
+// All iterates over the values, in no particular order (ex. slices.Sorted(s.All()) for a sorted slice)
+func (s *Set[T]) All() iter.Seq[T] {
+	if s == nil {
+		return func(func(T) bool) {}
+	}
+	return maps.Keys(s.m)
+}
+
+// Union is every value in s or other
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	out := &Set[T]{m: make(map[T]struct{}, max(s.Len(), other.Len()))}
+	for v := range s.All() {
+		out.m[v] = struct{}{}
+	}
+	for v := range other.All() {
+		out.m[v] = struct{}{}
+	}
+	return out
+}
+
+// Intersection is every value in both s and other
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	small, big := s, other
+	if small.Len() > big.Len() { // loop over the smaller one, look up in the bigger one
+		small, big = big, small
+	}
+	out := &Set[T]{}
+	for v := range small.All() {
+		if big.Contains(v) {
+			out.Add(v)
+		}
+	}
+	return out
+}
+
+// Difference is every value in s that isn't in other
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	out := &Set[T]{}
+	for v := range s.All() {
+		if !other.Contains(v) {
+			out.Add(v)
+		}
+	}
+	return out
+}
+
+// Equal reports whether s and other hold the same values
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	for v := range s.All() {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func sorted(s *Set[int]) []int { return slices.Sorted(s.All()) }
+
+func TestSetBasics(t *testing.T) {
+	var s Set[string] // the zero value is ready to use
+	s.Add("a", "b", "a")
+	if s.Len() != 2 || !s.Contains("a") || s.Contains("c") {
+		t.Errorf("after Add(a, b, a): Len %d, contains a %t, contains c %t", s.Len(), s.Contains("a"), s.Contains("c"))
+	}
+	s.Remove("a")
+	s.Remove("nobody")
+	if s.Len() != 1 || s.Contains("a") {
+		t.Errorf("after Remove(a): Len %d, contains a %t", s.Len(), s.Contains("a"))
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a, b := NewSet(1, 2, 3, 4), NewSet(3, 4, 5)
+	for _, c := range []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"intersection", a.Intersection(b), []int{3, 4}},
+		{"difference a - b", a.Difference(b), []int{1, 2}},
+		{"difference b - a", b.Difference(a), []int{5}},
+		{"difference a - a", a.Difference(a), nil},
+	} {
+		if got := sorted(c.got); !slices.Equal(got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if !slices.Equal(sorted(a), []int{1, 2, 3, 4}) || !slices.Equal(sorted(b), []int{3, 4, 5}) {
+		t.Errorf("inputs changed: %v, %v", sorted(a), sorted(b))
+	}
+}
+
+// The laws the operations must follow, on random sets
+func TestSetLaws(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	randomSet := func() *Set[int] {
+		s := NewSet[int]()
+		for range r.IntN(30) {
+			s.Add(r.IntN(40))
+		}
+		return s
+	}
+	for i := range 200 {
+		a, b := randomSet(), randomSet()
+		for _, law := range []struct {
+			name string
+			ok   bool
+		}{
+			{"a | b == b | a", a.Union(b).Equal(b.Union(a))},
+			{"a & b == b & a", a.Intersection(b).Equal(b.Intersection(a))},
+			{"a == (a - b) | (a & b)", a.Difference(b).Union(a.Intersection(b)).Equal(a)},
+			{"(a - b) & b is empty", a.Difference(b).Intersection(b).Len() == 0},
+			{"|a | b| == |a| + |b| - |a & b|", a.Union(b).Len() == a.Len()+b.Len()-a.Intersection(b).Len()},
+		} {
+			if !law.ok {
+				t.Errorf("sets %d: %s fails for a = %v, b = %v", i, law.name, sorted(a), sorted(b))
+			}
+		}
+	}
+}
+
+func TestSetEqual(t *testing.T) {
+	var empty Set[int]
+	var nilSet *Set[int]
+	for _, c := range []struct {
+		name string
+		a, b *Set[int]
+		want bool
+	}{
+		{"order and repeats", NewSet(1, 2, 3), NewSet(3, 1, 2, 2), true},
+		{"same size, different values", NewSet(1, 2), NewSet(1, 3), false},
+		{"subset", NewSet(1, 2), NewSet(1, 2, 3), false},
+		{"zero value and nil", &empty, nilSet, true},
+		{"nil and a set", nilSet, NewSet(1), false},
+	} {
+		if got := c.a.Equal(c.b); got != c.want {
+			t.Errorf("%s: Equal = %t, want %t", c.name, got, c.want)
+		}
+	}
+}
+
+// A nil *Set reads as empty through every method except Add
+func TestSetNil(t *testing.T) {
+	var s *Set[int]
+	s.Remove(1)
+	if s.Len() != 0 || s.Contains(1) || len(sorted(s)) != 0 {
+		t.Error("nil set isn't empty")
+	}
+	a := NewSet(1, 2)
+	if !s.Union(a).Equal(a) || s.Intersection(a).Len() != 0 || s.Difference(a).Len() != 0 || !a.Difference(s).Equal(a) {
+		t.Error("operations with a nil set don't treat it as empty")
+	}
+}
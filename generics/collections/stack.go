@@ -0,0 +1,93 @@
+package collections
+
+// Stacks, queues, deques, sets and a graph, each with a type parameter for the element.
+//
+// All of them are ready to use as their zero value: var s Stack[int]; s.Push(1) - except LRU and PriorityQueue,
+// which need a capacity or a less function first (NewLRU, NewPriorityQueue).
+// Pop and Peek on an empty one return the zero T and false instead of panicking,
+// and so do Len, Pop and Peek on a nil pointer - a nil *Stack is an empty stack (Push on nil still panics,
+// there's nowhere to put the value).
+//
+// There are two implementations of Stack and Queue:
+// - slice backed (Stack, Queue): values sit next to each other in memory, and an allocation is only needed
+//   when the slice grows - usually the faster one
+// - linked (LinkedStack, LinkedQueue): a node allocated per Push, but never a big reallocation and copy -
+//   each Push takes about the same time, and memory is given back as values are popped
+
+// Stack is last in, first out, over a slice (the top is the end of the slice)
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(v T) {
+	s.items = append(s.items, v)
+}
+
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if s.Len() == 0 {
+		return zero, false
+	}
+	last := len(s.items) - 1
+	v := s.items[last]
+	s.items[last] = zero // don't keep what v points to alive from the unused part of the slice
+	s.items = s.items[:last]
+	return v, true
+}
+
+func (s *Stack[T]) Peek() (T, bool) {
+	if s.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+func (s *Stack[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.items)
+}
+
+type node[T any] struct {
+	val  T
+	next *node[T]
+}
+
+// LinkedStack is a Stack as a singly linked list - Push and Pop only touch the head
+type LinkedStack[T any] struct {
+	head *node[T]
+	n    int
+}
+
+func (s *LinkedStack[T]) Push(v T) {
+	s.head = &node[T]{val: v, next: s.head}
+	s.n++
+}
+
+func (s *LinkedStack[T]) Pop() (T, bool) {
+	if s.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	v := s.head.val
+	s.head = s.head.next
+	s.n--
+	return v, true
+}
+
+func (s *LinkedStack[T]) Peek() (T, bool) {
+	if s.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.head.val, true
+}
+
+func (s *LinkedStack[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return s.n
+}
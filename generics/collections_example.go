@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"generics/collections"
+)
+
+// --- Generic data structures: Stack, Queue and Deque (see collections/) ---
+
+// The "useful for implementing generic data structures" from generics.go, in practice:
+// one Stack[T] is a stack of ints, of strings, of Users - checked by the compiler, no interface{} and no casts
+
+func collectionsExample() {
+	// The zero value is ready to use
+	var s collections.Stack[string]
+	s.Push("a")
+	s.Push("b")
+	top, _ := s.Peek()
+	fmt.Println("stack - peek:", top, "| len:", s.Len())
+
+	var q collections.Queue[User]
+	q.Push(User{UserId: "1", Name: "John Doe"})
+	q.Push(User{UserId: "2", Name: "Jack Eod"})
+	first, _ := q.Pop()
+	fmt.Println("queue - first out:", first, "| len:", q.Len())
+
+	var d collections.Deque[int]
+	d.PushBack(2)
+	d.PushFront(1)
+	d.PushBack(3)
+	f, _ := d.PopFront()
+	b, _ := d.PopBack()
+	fmt.Println("deque - front:", f, "back:", b, "| len:", d.Len())
+
+	// Empty and nil: no panics, just false
+	var nilStack *collections.Stack[int]
+	var nilQueue *collections.LinkedQueue[int]
+	var nilDeque *collections.Deque[int]
+	_, ok1 := nilStack.Pop()
+	_, ok2 := nilQueue.Peek()
+	_, ok3 := nilDeque.PopBack()
+	_, ok4 := new(collections.Queue[int]).Pop()
+	fmt.Println("nil and empty - Len:", nilStack.Len(), nilQueue.Len(), nilDeque.Len(), "| Pop/Peek ok:", ok1, ok2, ok3, ok4)
+
+	// collections/collections_test.go checks all of them against a model, and benchmarks slice backed vs linked
+}
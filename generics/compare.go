@@ -0,0 +1,82 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// --- Generic comparison chains (multi-key sorting) ---
+
+// slices.SortFunc takes a comparison function: negative if a < b, 0 if equal, positive if a > b.
+// Sorting by several keys ("by Name, then by UserId when names are equal") means
+// comparing the first key, and only moving on to the next key on a tie.
+
+// Same User struct as in basics.go
+type User struct {
+	UserId string
+	Name   string
+}
+
+// CompareBy combines comparison functions into one.
+// The first one that returns non-zero decides the order, later ones only break ties.
+func CompareBy[T any](fields ...func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		for _, compare := range fields {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// By builds a comparison function from a key function,
+// ex. By(func(u User) string { return u.Name })
+// K is constrained by cmp.Ordered (any type that supports < and >), so cmp.Compare can be used on it
+func By[T any, K cmp.Ordered](key func(T) K) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// Reverse flips a comparison function (descending order)
+func Reverse[T any](compare func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		return compare(b, a)
+	}
+}
+
+func compareByExample() {
+	users := []User{
+		{UserId: "96aeb270", Name: "Jack Eod"},
+		{UserId: "1d02455e", Name: "John Doe"},
+		{UserId: "0b7c61f2", Name: "Jack Eod"},
+		{UserId: "5e1a90c3", Name: "Alice"},
+		{UserId: "33d4e8a1", Name: "John Doe"},
+	}
+
+	byName := By(func(u User) string { return u.Name })
+	byId := By(func(u User) string { return u.UserId })
+
+	// Name first, then UserId breaks ties between users with the same name
+	slices.SortFunc(users, CompareBy(byName, byId))
+	fmt.Println("by name, then id:")
+	for _, u := range users {
+		fmt.Printf("  %-9s %s\n", u.Name, u.UserId)
+	}
+
+	// The same comparators can be reordered or reversed without writing new functions
+	slices.SortFunc(users, CompareBy(byName, Reverse(byId)))
+	fmt.Println("by name, then id descending:", users)
+
+	// For comparison, the standard library way (Go 1.22+): cmp.Or returns its first non-zero argument.
+	// The difference is that every key is compared up front, while CompareBy stops at the first non-zero result
+	slices.SortFunc(users, func(a, b User) int {
+		return cmp.Or(
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.UserId, b.UserId),
+		)
+	})
+	fmt.Println("with cmp.Or:", users)
+}
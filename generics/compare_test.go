@@ -0,0 +1,76 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+var (
+	byName = By(func(u User) string { return u.Name })
+	byId   = By(func(u User) string { return u.UserId })
+)
+
+func testUsers() []User {
+	return []User{
+		{UserId: "96aeb270", Name: "Jack Eod"},
+		{UserId: "1d02455e", Name: "John Doe"},
+		{UserId: "0b7c61f2", Name: "Jack Eod"},
+		{UserId: "5e1a90c3", Name: "Alice"},
+		{UserId: "33d4e8a1", Name: "John Doe"},
+	}
+}
+
+func ids(users []User) []string {
+	out := make([]string, len(users))
+	for i, u := range users {
+		out[i] = u.UserId
+	}
+	return out
+}
+
+func TestCompareByTieBreak(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		compare func(a, b User) int
+		want    []string
+	}{
+		// names decide first, ids only order users with the same name
+		{"name, id", CompareBy(byName, byId), []string{"5e1a90c3", "0b7c61f2", "96aeb270", "1d02455e", "33d4e8a1"}},
+		{"name, id descending", CompareBy(byName, Reverse(byId)), []string{"5e1a90c3", "96aeb270", "0b7c61f2", "33d4e8a1", "1d02455e"}},
+		{"name descending, id", CompareBy(Reverse(byName), byId), []string{"1d02455e", "33d4e8a1", "0b7c61f2", "96aeb270", "5e1a90c3"}},
+		{"id only", CompareBy(byId), []string{"0b7c61f2", "1d02455e", "33d4e8a1", "5e1a90c3", "96aeb270"}},
+		// the second key, when the first never ties, changes nothing
+		{"id, name", CompareBy(byId, byName), []string{"0b7c61f2", "1d02455e", "33d4e8a1", "5e1a90c3", "96aeb270"}},
+	} {
+		users := testUsers()
+		slices.SortFunc(users, c.compare)
+		if got := ids(users); !slices.Equal(got, c.want) {
+			t.Errorf("%s: sorted ids %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCompareByStopsAtFirstDifference(t *testing.T) {
+	calls := 0
+	counted := func(a, b User) int { calls++; return 0 }
+	CompareBy(byName, counted)(User{Name: "a"}, User{Name: "b"})
+	if calls != 0 {
+		t.Errorf("names differ, but the tie-breaker ran %d times", calls)
+	}
+	CompareBy(byName, counted)(User{Name: "a"}, User{Name: "a"})
+	if calls != 1 {
+		t.Errorf("names tie, the tie-breaker ran %d times, want 1", calls)
+	}
+}
+
+func TestCompareByNoKeys(t *testing.T) {
+	if c := CompareBy[User]()(User{Name: "a"}, User{Name: "b"}); c != 0 {
+		t.Errorf("CompareBy() = %d, want 0 (everything equal)", c)
+	}
+	// so a stable sort keeps the input order
+	users := testUsers()
+	slices.SortStableFunc(users, CompareBy[User]())
+	if !slices.Equal(ids(users), ids(testUsers())) {
+		t.Errorf("stable sort with no keys reordered users: %v", ids(users))
+	}
+}
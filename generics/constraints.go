@@ -0,0 +1,158 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// --- Constraints are interfaces: type sets ---
+
+// `comparable` and `any` are the two built-in constraints. Any interface can be a constraint, and as a constraint
+// an interface means a set of types - the types that are allowed for the type parameter:
+// - a method list: every type that has those methods (what an interface always meant)
+// - a union of types, int | float64: exactly those types
+// - ~T: every type whose underlying type is T - int, but also type Celsius int, type Count int, ...
+// - several lines: the intersection - a type must be in every line
+// An interface with a union or ~ in it can only be used as a constraint, not as the type of a variable
+// (see constraints_nocompile.go for the errors).
+
+// Number is every type that is an integer or a float underneath - int, uint8, float64, and defined types
+// like Celsius below. The operators all of them have (+ - * / < ==) can be used on a T
+// (cmp.Ordered is the standard library's constraint for <: these lines plus ~string - see the cmppkg module)
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+func SumOf[T Number](vals ...T) T {
+	var total T
+	for _, v := range vals {
+		total += v
+	}
+	return total
+}
+
+// exactNumber is a union without ~ - only int, int64 and float64 themselves
+type exactNumber interface {
+	int | int64 | float64
+}
+
+func sumExact[T exactNumber](vals ...T) T {
+	var total T
+	for _, v := range vals {
+		total += v
+	}
+	return total
+}
+
+type Celsius float64
+
+func (c Celsius) String() string { return fmt.Sprintf("%.1f°C", float64(c)) }
+
+type Count int
+
+// --- Composite constraints: types AND methods ---
+
+// A type in Number that also has a String method. Only defined types can have methods,
+// so float64 itself is out - Celsius is in, Count (no String) is out
+type StringableNumber interface {
+	Number
+	fmt.Stringer
+}
+
+// Hottest can compare the values (they're Numbers) and print them with their own String
+func Hottest[T StringableNumber](readings ...T) string {
+	best := readings[0]
+	for _, r := range readings[1:] {
+		best = max(best, r)
+	}
+	return "hottest: " + best.String()
+}
+
+// --- ~ on composite types ---
+
+// S ~[]E is "any slice of E, including defined slice types" - the signature slices.Clone and friends use.
+// With s []E instead, passing a Readings would give back a plain []Celsius, losing the type (and its methods)
+type Readings []Celsius
+
+func (r Readings) String() string {
+	parts := make([]string, len(r))
+	for i, c := range r {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func Filter[S ~[]E, E any](s S, keep func(E) bool) S {
+	var out S
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// --- golang.org/x/exp/constraints ---
+
+// x/exp/constraints names the common numeric type sets: constraints.Signed (~int | ~int8 | ... | ~int64),
+// constraints.Unsigned (the ~uint kinds and ~uintptr), constraints.Integer (Signed | Unsigned - a union of interfaces
+// is the union of their type sets), constraints.Float (~float32 | ~float64) and constraints.Complex.
+// (x/exp is maintained by the Go team, outside the standard library - go get golang.org/x/exp.)
+// Its Ordered is in the standard library since Go 1.21, as cmp.Ordered.
+
+// GCD needs % - which integers have and floats don't, so the constraint is Integer
+func GCD[T constraints.Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Mean of integers would truncate, so it's only for floats
+func Mean[T constraints.Float](vals ...T) T {
+	return Total(vals) / T(len(vals))
+}
+
+// Total sums any Integer or Float - a union can be written right in the brackets
+func Total[T constraints.Integer | constraints.Float](vals []T) T {
+	var total T
+	for _, v := range vals {
+		total += v
+	}
+	return total
+}
+
+// Clamp works on anything with < - numbers and strings (cmp.Ordered, which was constraints.Ordered)
+func Clamp[T cmp.Ordered](v, lo, hi T) T {
+	return min(max(v, lo), hi)
+}
+
+func constraintsExample() {
+	// Number, with ~: the defined types are in
+	fmt.Println("SumOf ints:", SumOf(1, 2, 3), "| floats:", SumOf(1.5, 2.5), "| Celsius:", SumOf(Celsius(20), Celsius(1.5)), "| Count:", SumOf(Count(2), Count(3)))
+	// without ~, only int, int64 and float64 - sumExact(Celsius(20)) doesn't compile
+	fmt.Println("sumExact ints:", sumExact(1, 2, 3))
+
+	// Number and a String method
+	fmt.Println(Hottest(Celsius(18.5), Celsius(23), Celsius(21.25)))
+
+	// ~[]E keeps the defined slice type
+	week := Readings{18, 25.5, 21, 30, 16}
+	warm := Filter(week, func(c Celsius) bool { return c > 20 })
+	fmt.Printf("warm days: %v (%T)\n", warm, warm)
+
+	// x/exp/constraints style
+	fmt.Println("GCD(84, 36):", GCD(84, 36), "| uint8:", GCD(uint8(48), 18), "| Count:", GCD(Count(21), 14))
+	fmt.Println("Mean:", Mean(1.0, 2.0, 4.5), "| float32:", Mean(float32(1), 2), "| Celsius:", Mean(week...))
+	fmt.Println("Clamp:", Clamp(150, 0, 100), Clamp(-3.5, -1, 1), Clamp("m", "a", "k"))
+
+	// Checks
+	fmt.Println("defined types work with ~:", SumOf(Celsius(1), 2) == Celsius(3))
+	fmt.Println("Filter kept the slice type:", fmt.Sprintf("%T", warm) == "main.Readings")
+	fmt.Println("GCD and Clamp:", GCD(84, 36) == 12 && Clamp(150, 0, 100) == 100 && Clamp("m", "a", "k") == "k")
+}
@@ -0,0 +1,37 @@
+//go:build nocompile
+
+package main
+
+// Excluded from normal builds, like units_nocompile.go. Build it on purpose to see the constraint errors:
+//
+//	go build -tags nocompile .
+
+func constraintMistakes() {
+	// Without ~, a defined type isn't in the set - even with the right underlying type
+	// Celsius does not satisfy exactNumber (possibly missing ~ for float64 in exactNumber)
+	_ = sumExact(Celsius(20), Celsius(1.5))
+
+	// A type element makes an interface a constraint only
+	// cannot use type Number outside a type constraint: interface contains type constraints
+	var n Number
+	_ = n
+
+	// Count is a Number, but has no String method
+	// in call to Hottest, T (type Count) does not satisfy StringableNumber (missing method String)
+	_ = Hottest(Count(1), Count(2))
+
+	// % isn't defined for floats, so a float type can't be passed to GCD
+	// float64 does not satisfy constraints.Integer (float64 missing in ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr)
+	_ = GCD(1.5, 0.5)
+
+	// An empty type set: nothing is both an int and a string, so nothing can instantiate it
+	// cannot satisfy interface{int; string} (empty type set)
+	_ = only[int](1)
+}
+
+func only[T interface {
+	int
+	string
+}](v T) T {
+	return v
+}
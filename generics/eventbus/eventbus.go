@@ -0,0 +1,73 @@
+package eventbus
+
+import (
+	"reflect"
+	"sync"
+)
+
+// An in-process publish/subscribe bus, keyed by the Go type of the event.
+//
+// The untyped version keeps handlers as func(any), and every handler starts with a type assertion -
+// event.(UserCreated) - that is only checked at run time. Here Subscribe and Publish are generic functions,
+// so the event type is a type argument: Subscribe(bus, func(e UserCreated) {...}) only ever gets UserCreated
+// events, and the compiler checks it. Inside, the bus still stores handlers as any, keyed by reflect.Type -
+// the one assertion is in this file, where it can't go wrong.
+//
+// They're functions and not methods because methods can't have type parameters of their own.
+//
+// The key is the type argument, not the dynamic type of the value: Publish[error](bus, err) reaches
+// Subscribe[error] handlers only, not handlers subscribed for the concrete error type.
+//
+// Handlers run synchronously, in the publisher's goroutine, in the order they subscribed.
+// Publish and Subscribe are safe to call from many goroutines at once - so a handler can be running
+// in several goroutines at the same time, and must be safe for that itself.
+
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]*subscription
+}
+
+type subscription struct {
+	fn any // always a func(T) for the T it's filed under
+}
+
+func New() *Bus {
+	return &Bus{handlers: make(map[reflect.Type][]*subscription)}
+}
+
+// Subscribe calls handler for every T published from now on. The returned func unsubscribes it
+func Subscribe[T any](b *Bus, handler func(T)) (unsubscribe func()) {
+	key := reflect.TypeFor[T]()
+	sub := &subscription{fn: handler}
+	b.mu.Lock()
+	b.handlers[key] = append(b.handlers[key], sub)
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			subs := b.handlers[key]
+			for i, s := range subs {
+				if s == sub {
+					// a new slice, not an in-place delete: a Publish may be ranging over the old one right now
+					b.handlers[key] = append(subs[:i:i], subs[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Publish calls every handler subscribed for T with event, and returns how many there were
+func Publish[T any](b *Bus, event T) int {
+	b.mu.RLock()
+	subs := b.handlers[reflect.TypeFor[T]()] // only ever replaced, never changed in place - safe to use unlocked
+	b.mu.RUnlock()
+	// outside the lock: a handler may Publish or Subscribe itself
+	for _, s := range subs {
+		s.fn.(func(T))(event)
+	}
+	return len(subs)
+}
@@ -0,0 +1,126 @@
+package eventbus
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type created struct{ id int }
+type deleted struct{ id int }
+
+func TestOrderAndTypes(t *testing.T) {
+	b := New()
+	var got []string
+	Subscribe(b, func(e created) { got = append(got, "first") })
+	Subscribe(b, func(e created) { got = append(got, "second") })
+	Subscribe(b, func(e deleted) { got = append(got, "deleted") })
+
+	if n := Publish(b, created{1}); n != 2 {
+		t.Errorf("Publish(created) reached %d handlers, want 2", n)
+	}
+	if want := []string{"first", "second"}; !slices.Equal(got, want) {
+		t.Errorf("handlers ran %v, want %v", got, want)
+	}
+	if n := Publish(b, 42); n != 0 {
+		t.Errorf("Publish(int) with no int handlers reached %d", n)
+	}
+}
+
+func TestTypeArgumentIsTheKey(t *testing.T) {
+	b := New()
+	var asError, asPathErr int
+	Subscribe(b, func(error) { asError++ })
+	Subscribe(b, func(*fs.PathError) { asPathErr++ })
+	err := &fs.PathError{Op: "write", Path: "/tmp/out", Err: errors.New("disk full")}
+
+	Publish(b, err)
+	Publish[error](b, err)
+	if asError != 1 || asPathErr != 1 {
+		t.Errorf("error handlers ran %d times, *fs.PathError handlers %d, want 1 each", asError, asPathErr)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := New()
+	var got []int
+	Subscribe(b, func(e created) { got = append(got, 1) })
+	unsub := Subscribe(b, func(e created) { got = append(got, 2) })
+	Subscribe(b, func(e created) { got = append(got, 3) })
+
+	unsub()
+	unsub() // a second call mustn't remove another handler
+	if n := Publish(b, created{}); n != 2 || !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("after unsubscribe: %d handlers ran %v, want 2 running [1 3]", n, got)
+	}
+}
+
+// A handler that publishes, subscribes or unsubscribes itself mustn't deadlock, and a handler added
+// or removed during a Publish doesn't change who that Publish calls
+func TestReentrantHandlers(t *testing.T) {
+	b := New()
+	var deletes, lateCalls int
+	var unsubSelf func()
+	unsubSelf = Subscribe(b, func(e created) {
+		Publish(b, deleted{e.id})
+		Subscribe(b, func(created) { lateCalls++ })
+		unsubSelf()
+	})
+	Subscribe(b, func(deleted) { deletes++ })
+
+	if n := Publish(b, created{1}); n != 1 {
+		t.Errorf("first Publish reached %d handlers, want 1", n)
+	}
+	if deletes != 1 || lateCalls != 0 {
+		t.Errorf("deletes %d, late handler calls %d, want 1 and 0", deletes, lateCalls)
+	}
+	if n := Publish(b, created{2}); n != 1 || lateCalls != 1 {
+		t.Errorf("second Publish reached %d handlers, late handler calls %d, want 1 and 1", n, lateCalls)
+	}
+}
+
+// Many publishers while others subscribe and unsubscribe: none of the long-lived handler's events
+// are lost. Meant for go test -race
+func TestConcurrentPublish(t *testing.T) {
+	b := New()
+	var count, total atomic.Int64
+	Subscribe(b, func(e created) {
+		count.Add(1)
+		total.Add(int64(e.id))
+	})
+
+	const publishers, each = 8, 1000
+	var wg sync.WaitGroup
+	for range publishers {
+		wg.Go(func() {
+			for i := range each {
+				Publish(b, created{i})
+			}
+		})
+	}
+	for range 2 {
+		wg.Go(func() {
+			for range 200 {
+				Subscribe(b, func(created) {})()
+				Subscribe(b, func(deleted) {})()
+			}
+		})
+	}
+	wg.Wait()
+
+	if want := int64(publishers * each); count.Load() != want {
+		t.Errorf("handled %d events, want %d", count.Load(), want)
+	}
+	if want := int64(publishers * each * (each - 1) / 2); total.Load() != want {
+		t.Errorf("total %d, want %d", total.Load(), want)
+	}
+	if n := Publish(b, created{}); n != 1 {
+		t.Errorf("after the churn %d created handlers are left, want 1", n)
+	}
+	if n := Publish(b, deleted{}); n != 0 {
+		t.Errorf("after the churn %d deleted handlers are left, want 0", n)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"generics/eventbus"
+	"io/fs"
+)
+
+// --- A type-safe event bus (see eventbus/) ---
+
+type UserCreated struct{ User User }
+type UserDeleted struct{ UserId string }
+type OrderPlaced struct {
+	Order  ID[Order] // the typed ids from units.go
+	Amount float64
+}
+
+func eventBusExample() {
+	bus := eventbus.New()
+
+	// Each handler gets its own event type - no type switch, no assertion
+	var log []string
+	eventbus.Subscribe(bus, func(e UserCreated) { log = append(log, "welcome email to "+e.User.Name) })
+	eventbus.Subscribe(bus, func(e UserCreated) { log = append(log, "audit: created "+e.User.UserId) })
+	unsubscribe := eventbus.Subscribe(bus, func(e UserDeleted) { log = append(log, "audit: deleted "+e.UserId) })
+
+	eventbus.Publish(bus, UserCreated{User{UserId: "1d02455e", Name: "John Doe"}})
+	eventbus.Publish(bus, UserDeleted{UserId: "1d02455e"})
+	unsubscribe()
+	unsubscribe() // twice is fine
+	n := eventbus.Publish(bus, UserDeleted{UserId: "96aeb270"})
+	fmt.Println("handled:", log)
+	fmt.Println("after unsubscribe, UserDeleted handlers:", n)
+
+	// A type nobody subscribed to goes nowhere - and the compiler stops a handler of the wrong type:
+	// eventbus.Subscribe[UserCreated](bus, func(e UserDeleted) {}) doesn't compile
+	fmt.Println("OrderPlaced handlers:", eventbus.Publish(bus, OrderPlaced{Order: 1, Amount: 9.5}))
+
+	// The key is the type argument: an error published as error reaches error handlers only
+	errEvents := 0
+	eventbus.Subscribe(bus, func(err error) { errEvents++ })
+	pathErr := &fs.PathError{Op: "write", Path: "/tmp/out", Err: errors.New("disk full")}
+	eventbus.Publish(bus, pathErr)        // T inferred as *fs.PathError - a different key, no handlers
+	eventbus.Publish[error](bus, pathErr) // T is error
+	fmt.Println("error handlers called once (for Publish[error]):", errEvents == 1)
+
+	// eventbus/eventbus_test.go checks order, unsubscribing, reentrant handlers and concurrent publishing
+}
@@ -0,0 +1,108 @@
+package main
+
+import "fmt"
+
+// --- Implementing a generic interface: Flyer ---
+
+// Flyer[T] (generics.go) isn't one interface but one per T: Flyer[float64] and Flyer[int] are as different
+// as two interfaces with different method signatures. A type implements Flyer[float64] by having
+// Fly(float64), and the compiler checks it like any other interface:
+var (
+	_ Flyer[float64] = (*Bird)(nil)
+	_ Flyer[int]     = (*Drone)(nil)
+)
+
+// Bird flies distances in km
+type Bird struct {
+	name    string
+	flownKm float64
+}
+
+func (b *Bird) Fly(km float64) { b.flownKm += km }
+func (b *Bird) Flown() float64 { return b.flownKm }
+
+// Drone flies whole meters, and stops where its battery runs out
+type Drone struct {
+	id      int
+	flownM  int
+	rangeM  int // how far it can fly on one charge
+	stopped bool
+}
+
+func (d *Drone) Fly(m int) {
+	if d.flownM+m > d.rangeM {
+		m, d.stopped = d.rangeM-d.flownM, true
+	}
+	d.flownM += m
+}
+func (d *Drone) Flown() int { return d.flownM }
+
+// FlyFleet flies every member of the fleet each leg in turn. F is the type of the fleet's members (*Bird, *Drone),
+// constrained by Flyer[T] - so the slice is a []*Bird, not a slice of interface values, and the call to Fly
+// needs no interface lookup of our own. T is inferred from F's Fly method: FlyFleet(birds, 1.5) is enough
+func FlyFleet[T comparable, F Flyer[T]](fleet []F, legs ...T) {
+	for _, leg := range legs {
+		for _, f := range fleet {
+			f.Fly(leg)
+		}
+	}
+}
+
+// Flyer's T is comparable, which allows == and nothing else - a generic function can't add two Ts.
+// Totting up how far a fleet has flown needs a constraint with +: Number (constraints.go),
+// and a way to ask each member - a constraint can combine an interface and more methods
+type odometerFlyer[T Number] interface {
+	Flyer[T]
+	Flown() T
+}
+
+func FleetDistance[T Number, F odometerFlyer[T]](fleet []F) T {
+	var total T
+	for _, f := range fleet {
+		total += f.Flown()
+	}
+	return total
+}
+
+// When a generic interface isn't the tool: a fleet of birds AND drones. []Flyer[float64] holds anything with
+// Fly(float64), but a *Drone's Fly takes an int - no T makes both fit, so no one slice (or FlyFleet call) has both:
+//
+//	mixed := []Flyer[float64]{&Bird{}, &Drone{}} // *Drone does not implement Flyer[float64] (wrong type for method Fly)
+//
+// What the two have in common is flying a distance, so the fix is to agree on a unit - a plain interface
+// with Fly(meters float64), and the Drone converting. A type parameter on an interface pays off when the
+// implementations really differ in T and code is written once for each T (a Stack[T] has a Push(T)),
+// not when different Ts have to sit side by side.
+type metersFlyer interface{ FlyMeters(m float64) }
+
+func (b *Bird) FlyMeters(m float64)  { b.Fly(m / 1000) }
+func (d *Drone) FlyMeters(m float64) { d.Fly(int(m)) }
+
+func flyerExample() {
+	birds := []*Bird{{name: "swift"}, {name: "gull"}}
+	FlyFleet(birds, 1.5, 2.5)
+	for _, b := range birds {
+		fmt.Printf("%s flew %.1f km\n", b.name, b.flownKm)
+	}
+	fmt.Println("fleet total:", FleetDistance(birds), "km")
+
+	drones := []*Drone{{id: 1, rangeM: 1000}, {id: 2, rangeM: 5000}}
+	FlyFleet(drones, 300, 900)
+	for _, d := range drones {
+		fmt.Printf("drone %d flew %d m, out of battery: %t\n", d.id, d.flownM, d.stopped)
+	}
+	fmt.Println("fleet total:", FleetDistance(drones), "m")
+
+	// Interface values work too, for things of one T - the dynamic type can be anything with Fly(float64)
+	var f Flyer[float64] = birds[0]
+	f.Fly(1)
+	fmt.Printf("a Flyer[float64] holding a *Bird: %s is at %.1f km\n", birds[0].name, birds[0].Flown())
+
+	// The mixed fleet, through the plain interface
+	mixed := []metersFlyer{&Bird{name: "crow"}, &Drone{id: 3, rangeM: 10000}}
+	for _, m := range mixed {
+		m.FlyMeters(2500)
+	}
+	fmt.Printf("mixed fleet, 2500 m each: bird %.1f km, drone %d m\n", mixed[0].(*Bird).Flown(), mixed[1].(*Drone).Flown())
+	// flyer_test.go checks the fleets, the drone's range, and empty fleets
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestFlyFleetBirds(t *testing.T) {
+	birds := []*Bird{{name: "swift"}, {name: "gull", flownKm: 1}}
+	FlyFleet(birds, 1.5, 2.5)
+	if birds[0].Flown() != 4 || birds[1].Flown() != 5 {
+		t.Errorf("flown %v and %v km, want 4 and 5", birds[0].Flown(), birds[1].Flown())
+	}
+	if got := FleetDistance(birds); got != 9 {
+		t.Errorf("FleetDistance = %v, want 9", got)
+	}
+}
+
+func TestFlyFleetDrones(t *testing.T) {
+	drones := []*Drone{{id: 1, rangeM: 1000}, {id: 2, rangeM: 5000}}
+	FlyFleet(drones, 300, 900)
+	if d := drones[0]; d.Flown() != 1000 || !d.stopped {
+		t.Errorf("drone 1: flew %d m, stopped %t - want 1000 m and stopped at its range", d.Flown(), d.stopped)
+	}
+	if d := drones[1]; d.Flown() != 1200 || d.stopped {
+		t.Errorf("drone 2: flew %d m, stopped %t - want 1200 m, still flying", d.Flown(), d.stopped)
+	}
+	FlyFleet(drones, 100) // a stopped drone goes no further
+	if drones[0].Flown() != 1000 || FleetDistance(drones) != 2300 {
+		t.Errorf("after another leg: drone 1 at %d m, fleet at %d m - want 1000 and 2300", drones[0].Flown(), FleetDistance(drones))
+	}
+}
+
+func TestFlyFleetEmpty(t *testing.T) {
+	FlyFleet([]*Bird{}, 1.0)
+	var none []*Drone
+	FlyFleet(none, 10)
+	birds := []*Bird{{name: "swift"}}
+	FlyFleet(birds) // no legs
+	if FleetDistance(none) != 0 || birds[0].Flown() != 0 {
+		t.Errorf("empty fleet or no legs flew something: %d, %v", FleetDistance(none), birds[0].Flown())
+	}
+}
+
+func TestFlyerInterfaceValue(t *testing.T) {
+	b := &Bird{name: "swift"}
+	var f Flyer[float64] = b
+	f.Fly(2)
+	if b.Flown() != 2 {
+		t.Errorf("Fly through a Flyer[float64]: flown %v, want 2", b.Flown())
+	}
+}
+
+func TestMetersFlyer(t *testing.T) {
+	b, d := &Bird{name: "crow"}, &Drone{id: 3, rangeM: 10000}
+	for _, m := range []metersFlyer{b, d} {
+		m.FlyMeters(2500)
+	}
+	if b.Flown() != 2.5 || d.Flown() != 2500 {
+		t.Errorf("2500 m each: bird %v km, drone %d m - want 2.5 and 2500", b.Flown(), d.Flown())
+	}
+}
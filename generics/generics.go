@@ -1,6 +1,11 @@
 package main
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // --- Type parameters in generic function or method ---
 
@@ -21,6 +26,9 @@ func Index[T comparable](s []T, x T) int {
 
 // The above declaration means that s is a slice of any type T that
 // fulfills the built-in constraint comparable. x is also a value of the same type.
+// (constraints.go goes past comparable and any: type sets, ~, and constraints with methods)
+// (basics/sliceutil has functions taking functions: Map, Filter, Reduce, IndexFunc)
+// (Index is slices.Index in the standard library - the mapsslices module compares the slices and maps packages to such loops)
 
 func main() {
 	// index works on a slice of ints as well as slice of strings
@@ -28,30 +36,175 @@ func main() {
 	ss := []string{"foo", "bar", "baz"}
 	fmt.Println(Index(si, 15))
 	fmt.Println(Index(ss, "hi"))
+
+	// compareByExample()
+	// unitSafetyExample()
+	// treePrintExample()
+	// constraintsExample()
+	// numbersExample()
+	// collectionsExample()
+	// setExample()
+	// llistExample()
+	// optionResultExample()
+	// lruExample()
+	// priorityQueueExample()
+	// paginateExample()
+	// eventBusExample()
+	// iteratorsExample()
+	// sortByExample()
+	// flyerExample()
+	// graphExample()
+	// genericsBenchmarkExample()
+	// genericMethodsExample()
 }
 
 // --- Generic Types ---
 
 // In Go, a struct or interface can be parameterized with a type parameter,
 // which can be useful for implementing generic data structures.
+// (collections/ has some: Stack, Queue, Deque, Set, PriorityQueue, an LRU cache and a Graph)
 
-// List represents a singly-linked list that holds
+// LList represents a singly-linked list that holds
 // values of any type.
 type LList[T comparable] struct {
 	next *LList[T]
 	val  T
 }
 
+// A *LList[T] is a pointer to the first node - the list is the node and everything after it,
+// and nil is the empty list. Methods that can change the first node (Push, Remove, Reverse, Append
+// on an empty list) return the new one, like append does for slices: l = l.Push(v)
+
+// Push adds v at the front
+func (l *LList[T]) Push(v T) *LList[T] {
+	return &LList[T]{next: l, val: v}
+}
+
+// Append adds v at the end - walking the whole list to get there
+func (l *LList[T]) Append(v T) *LList[T] {
+	n := &LList[T]{val: v}
+	if l == nil {
+		return n
+	}
+	last := l
+	for last.next != nil {
+		last = last.next
+	}
+	last.next = n
+	return l
+}
+
+// InsertAfter adds v after the first node holding after, and reports whether there was one
+func (l *LList[T]) InsertAfter(after, v T) bool {
+	for n := l; n != nil; n = n.next {
+		if n.val == after { // == is why T is comparable
+			n.next = &LList[T]{next: n.next, val: v}
+			return true
+		}
+	}
+	return false
+}
+
+// Remove removes the first node holding v
+func (l *LList[T]) Remove(v T) *LList[T] {
+	if l == nil {
+		return nil
+	}
+	if l.val == v {
+		return l.next
+	}
+	for n := l; n.next != nil; n = n.next {
+		if n.next.val == v {
+			n.next = n.next.next
+			break
+		}
+	}
+	return l
+}
+
+func (l *LList[T]) Len() int {
+	count := 0
+	for n := l; n != nil; n = n.next { // methods can be called on a nil pointer - the loop just doesn't run
+		count++
+	}
+	return count
+}
+
+func (l *LList[T]) Contains(v T) bool {
+	for n := l; n != nil; n = n.next {
+		if n.val == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Reverse turns the links around in place - no new nodes - and returns the old last node
+func (l *LList[T]) Reverse() *LList[T] {
+	var prev *LList[T]
+	for n := l; n != nil; {
+		next := n.next
+		n.next = prev
+		prev, n = n, next
+	}
+	return prev
+}
+
+// String makes fmt.Println(l) print [1 -> 2 -> 3]
+func (l *LList[T]) String() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for n := l; n != nil; n = n.next {
+		if n != l {
+			sb.WriteString(" -> ")
+		}
+		fmt.Fprint(&sb, n.val)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// MarshalJSON makes a list encode as a JSON array of its values: [1,2,3]. The values go through
+// json.Marshal, the same as a slice's elements would - so a T with a MarshalJSON of its own is used too.
+// A method can't tighten T's constraint (there's no "where T is marshalable" for one method), so an LList
+// of something JSON can't encode is an error from here at run time, not a compile error: ex. a chan, or
+// a complex128 - comparable (so allowed by LList's constraint), but not a type encoding/json supports.
+// (A func never gets this far: funcs aren't comparable, so LList[func()] doesn't compile)
+// (json.Marshal never calls this on a nil *LList: a nil pointer encodes as null. See genericMethodsExample)
+func (l *LList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.ToSlice())
+}
+
+func (l *LList[T]) ToSlice() []T {
+	s := make([]T, 0, l.Len())
+	for n := l; n != nil; n = n.next {
+		s = append(s, n.val)
+	}
+	return s
+}
+
+// LListFrom builds a list holding s in the same order - built from the back, so each value is a Push
+func LListFrom[T comparable](s []T) *LList[T] {
+	var l *LList[T]
+	for _, v := range slices.Backward(s) {
+		l = l.Push(v)
+	}
+	return l
+}
+
+// An interface can have type parameters too - each Flyer[T] is its own interface (flyer.go implements two)
 type Flyer[T comparable] interface {
 	Fly(distance T)
 }
 
+// One page of a list response - built by Paginate (paginate.go). The fields are exported, with json tags,
+// so encoding/json can see them; nextPage and prevPage are pointers so "no next page" is null, not 0
 type PaginatedResDto[T any] struct {
-	totalItems   int
-	totalPages   int
-	currPage     int
-	itemsPerPage int
-	nextPage     *int
-	prevPage     *int
-	data         []T
+	TotalItems   int  `json:"totalItems"`
+	TotalPages   int  `json:"totalPages"`
+	CurrPage     int  `json:"currPage"`
+	ItemsPerPage int  `json:"itemsPerPage"`
+	NextPage     *int `json:"nextPage"`
+	PrevPage     *int `json:"prevPage"`
+	Data         []T  `json:"data"`
 }
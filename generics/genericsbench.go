@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// --- What generics cost: generic vs interface{} vs hand-written for one type ---
+
+// Three ways to write Index and Sum for more than one type:
+// - generic: Index[T comparable], SumOf[T Number] - one source, the compiler makes the copies
+// - interface{} (any): one function for everything, values boxed into interfaces, and a type switch or
+//   assertion to get them back - and []int can't be passed as []any, it has to be copied element by element
+// - hand-written for int: what the generic version competes with
+//
+// The Go compiler doesn't make one copy per type argument, but one per "GC shape": the underlying type
+// for non-pointer types (int, and any type defined as int, share one), and one shape for all pointer types.
+// Each copy gets a hidden dictionary argument describing the actual type. So:
+// - for Index[int] and SumOf[int], == and + are plain machine instructions - about hand-written speed
+// - calling a METHOD through a type parameter goes through the dictionary - an indirect call, like an
+//   interface method call (and, like one, not inlined); the last benchmark below shows that case
+
+func indexAny(s []any, x any) int {
+	for i, v := range s {
+		if v == x { // compares the dynamic types, then the values
+			return i
+		}
+	}
+	return -1
+}
+
+func indexInts(s []int, x int) int {
+	for i, v := range s {
+		if v == x {
+			return i
+		}
+	}
+	return -1
+}
+
+func sumAny(vals []any) (total float64) {
+	for _, v := range vals {
+		switch v := v.(type) {
+		case int:
+			total += float64(v)
+		case float64:
+			total += v
+		}
+	}
+	return total
+}
+
+func sumInts(vals []int) (total int) {
+	for _, v := range vals {
+		total += v
+	}
+	return total
+}
+
+func toAny[T any](s []T) []any {
+	out := make([]any, len(s))
+	for i, v := range s {
+		out[i] = v // boxing: an int outside 0-255 is copied to the heap
+	}
+	return out
+}
+
+// Methods through a type parameter vs an interface vs the concrete type
+type valuer interface{ Value() int }
+
+type meters int
+
+func (m meters) Value() int { return int(m) }
+
+func sumValues[T valuer](vals []T) (total int) {
+	for _, v := range vals {
+		total += v.Value() // through the dictionary - the shape of meters is int, which has no Value method
+	}
+	return total
+}
+
+func sumValuers(vals []valuer) (total int) {
+	for _, v := range vals {
+		total += v.Value() // interface method call
+	}
+	return total
+}
+
+func sumMeters(vals []meters) (total int) {
+	for _, v := range vals {
+		total += v.Value() // inlined
+	}
+	return total
+}
+
+var benchSink int // results go here, so the compiler can't drop the work
+
+func genericsBenchmarkExample() {
+	const n = 1000
+	ints := make([]int, n)
+	ms := make([]meters, n)
+	vs := make([]valuer, n)
+	for i := range n {
+		ints[i] = i * 7
+		ms[i] = meters(i)
+		vs[i] = meters(i)
+	}
+	anys := toAny(ints)
+	last := ints[n-1] // the worst case for Index: scans the whole slice
+
+	// The three versions give the same answers (TestGenericsBenchAgree)
+	fmt.Println("Index:", Index(ints, last), indexAny(anys, last), indexInts(ints, last),
+		"| Sum:", SumOf(ints...), sumAny(anys), sumInts(ints), "| Value sums:", sumValues(ms), sumValuers(vs), sumMeters(ms))
+
+	// A rough timing of each, allocations left out - BenchmarkIndex and BenchmarkSum in genericsbench_test.go
+	// are the careful version, with B/op and allocs/op: go test -bench 'Index|Sum' generics
+	timeIt := func(f func()) time.Duration {
+		const runs = 2000
+		start := time.Now()
+		for range runs {
+			f()
+		}
+		return time.Since(start) / runs
+	}
+	rows := []struct {
+		name string
+		f    func()
+	}{
+		{"Index[int]", func() { benchSink = Index(ints, last) }},
+		{"indexAny", func() { benchSink = indexAny(anys, last) }},
+		{"indexAny + toAny", func() { benchSink = indexAny(toAny(ints), last) }},
+		{"indexInts", func() { benchSink = indexInts(ints, last) }},
+		{"SumOf[int]", func() { benchSink = SumOf(ints...) }},
+		{"sumAny", func() { benchSink = int(sumAny(anys)) }},
+		{"sumInts", func() { benchSink = sumInts(ints) }},
+		{"sumValues[meters]", func() { benchSink = sumValues(ms) }},
+		{"sumValuers", func() { benchSink = sumValuers(vs) }},
+		{"sumMeters", func() { benchSink = sumMeters(ms) }},
+	}
+	fmt.Printf("\n%-20s %14s\n", fmt.Sprint(n, " values"), "ns/op")
+	res := map[string]time.Duration{}
+	for _, r := range rows {
+		res[r.name] = timeIt(r.f)
+		fmt.Printf("%-20s %14d\n", r.name, res[r.name].Nanoseconds())
+	}
+
+	ratio := func(a, b string) float64 { return float64(res[a]) / float64(max(res[b], 1)) }
+	fmt.Printf("\nIndex[int] / indexInts: %.1fx | indexAny / indexInts: %.1fx\n", ratio("Index[int]", "indexInts"), ratio("indexAny", "indexInts"))
+	fmt.Printf("SumOf[int] / sumInts: %.1fx | sumAny / sumInts: %.1fx\n", ratio("SumOf[int]", "sumInts"), ratio("sumAny", "sumInts"))
+	fmt.Printf("sumValues[meters] / sumMeters: %.1fx | sumValuers / sumMeters: %.1fx\n", ratio("sumValues[meters]", "sumMeters"), ratio("sumValuers", "sumMeters"))
+	// The generic and int-only versions allocate nothing; getting a []int into []any allocates a box per int
+	// over 255, and the slice (TestGenericsAllocs checks both).
+	// Typically: the generic Index and Sum within a little of the int-only ones, the any versions slower
+	// (a boxed value is a pointer away, and each == or type switch checks the type first), and a method
+	// called through a type parameter about as slow as through an interface - generics make code shared,
+	// not free; the int-only version is still the one to write for a hot loop calling methods
+}
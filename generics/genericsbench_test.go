@@ -0,0 +1,113 @@
+package main
+
+import "testing"
+
+// go test -bench 'Index|Sum' generics: the generic, interface{} and int-only versions side by side
+
+type benchData struct {
+	ints  []int
+	ms    []meters
+	vs    []valuer
+	anys  []any
+	last  int // the worst case for Index: scans the whole slice
+	total int
+}
+
+func newBenchData(n int) benchData {
+	d := benchData{ints: make([]int, n), ms: make([]meters, n), vs: make([]valuer, n)}
+	for i := range n {
+		d.ints[i] = i * 7
+		d.ms[i] = meters(i)
+		d.vs[i] = meters(i)
+		d.total += i
+	}
+	d.anys = toAny(d.ints)
+	d.last = d.ints[n-1]
+	return d
+}
+
+func TestGenericsBenchAgree(t *testing.T) {
+	d := newBenchData(1000)
+	if got := []int{Index(d.ints, d.last), indexInts(d.ints, d.last), indexAny(d.anys, d.last)}; got[0] != 999 || got[1] != 999 || got[2] != 999 {
+		t.Errorf("Index, indexInts, indexAny = %v, want 999 each", got)
+	}
+	if indexAny(d.anys, -1) != -1 || indexInts(d.ints, -1) != -1 {
+		t.Error("a missing value was found")
+	}
+	// indexAny compares the dynamic types first: an int64 7 isn't the int 7
+	if i := indexAny(d.anys, int64(7)); i != -1 {
+		t.Errorf("indexAny(int64(7)) = %d, want -1", i)
+	}
+	if SumOf(d.ints...) != 7*d.total || sumInts(d.ints) != 7*d.total || sumAny(d.anys) != float64(7*d.total) {
+		t.Errorf("sums: %d %d %v, want %d", SumOf(d.ints...), sumInts(d.ints), sumAny(d.anys), 7*d.total)
+	}
+	if sumAny([]any{1, 2.5, "ignored"}) != 3.5 {
+		t.Error("sumAny of mixed types")
+	}
+	if sumValues(d.ms) != d.total || sumValuers(d.vs) != d.total || sumMeters(d.ms) != d.total {
+		t.Errorf("method sums: %d %d %d, want %d", sumValues(d.ms), sumValuers(d.vs), sumMeters(d.ms), d.total)
+	}
+}
+
+// The generic and int-only versions allocate nothing; getting a []int into []any allocates a box per
+// int over 255, and the slice
+func TestGenericsAllocs(t *testing.T) {
+	d := newBenchData(1000)
+	for name, f := range map[string]func(){
+		"Index[int]":        func() { benchSink = Index(d.ints, d.last) },
+		"indexInts":         func() { benchSink = indexInts(d.ints, d.last) },
+		"SumOf[int]":        func() { benchSink = SumOf(d.ints...) },
+		"sumValues[meters]": func() { benchSink = sumValues(d.ms) },
+		"indexAny":          func() { benchSink = indexAny(d.anys, d.last) },
+	} {
+		if allocs := testing.AllocsPerRun(10, f); allocs != 0 {
+			t.Errorf("%s: %v allocations", name, allocs)
+		}
+	}
+	if allocs := testing.AllocsPerRun(10, func() { benchSink = len(toAny(d.ints)) }); allocs < 500 {
+		t.Errorf("toAny of 1000 ints: %v allocations, want one per int over 255 and the slice", allocs)
+	}
+}
+
+func BenchmarkIndex(b *testing.B) {
+	d := newBenchData(1000)
+	for _, bc := range []struct {
+		name string
+		f    func()
+	}{
+		{"generic", func() { benchSink = Index(d.ints, d.last) }},
+		{"any", func() { benchSink = indexAny(d.anys, d.last) }},
+		{"any+toAny", func() { benchSink = indexAny(toAny(d.ints), d.last) }},
+		{"ints", func() { benchSink = indexInts(d.ints, d.last) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				bc.f()
+			}
+		})
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	d := newBenchData(1000)
+	for _, bc := range []struct {
+		name string
+		f    func()
+	}{
+		{"generic", func() { benchSink = SumOf(d.ints...) }},
+		{"any", func() { benchSink = int(sumAny(d.anys)) }},
+		{"ints", func() { benchSink = sumInts(d.ints) }},
+		// A method through a type parameter vs an interface vs the concrete type
+		{"method/generic", func() { benchSink = sumValues(d.ms) }},
+		{"method/interface", func() { benchSink = sumValuers(d.vs) }},
+		{"method/concrete", func() { benchSink = sumMeters(d.ms) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				bc.f()
+			}
+		})
+	}
+}
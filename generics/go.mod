@@ -1,3 +1,5 @@
 module generics
 
 go 1.25.0
+
+require golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"generics/collections"
+)
+
+// --- A generic Graph with BFS, DFS and shortest paths (see collections/graph.go) ---
+
+// graphOf builds a directed graph from edges given as pairs
+func graphOf[T comparable](edges ...[2]T) *collections.Graph[T] {
+	g := &collections.Graph[T]{}
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1])
+	}
+	return g
+}
+
+func graphExample() {
+	// Which chapters of these notes lead to which - a string graph
+	chapters := graphOf(
+		[2]string{"basics", "methodsinterfaces"}, [2]string{"basics", "errorsdeep"},
+		[2]string{"methodsinterfaces", "generics"}, [2]string{"methodsinterfaces", "concurrency"},
+		[2]string{"generics", "concurrency"}, [2]string{"concurrency", "pipeline"},
+		[2]string{"errorsdeep", "pipeline"},
+	)
+	fmt.Println("BFS from basics:", chapters.BFS("basics"))
+	fmt.Println("DFS from basics:", chapters.DFS("basics"))
+	path, _ := chapters.ShortestPath("basics", "pipeline")
+	fmt.Println("fewest chapters from basics to pipeline:", path)
+
+	// Directed: an edge one way isn't a way back
+	_, back := chapters.ShortestPath("pipeline", "basics")
+	fmt.Println("a way back from pipeline to basics:", back)
+	// collections/graph_test.go has the traversal and shortest-path tables (a grid, a cycle, missing nodes)
+}
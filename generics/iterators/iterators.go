@@ -0,0 +1,72 @@
+package iterators
+
+import "iter"
+
+// Iterator adaptors for Go 1.23 range-over-func iterators.
+//
+// An iter.Seq[T] is just a function, func(yield func(T) bool): it calls yield with each value in turn,
+// and stops as soon as yield returns false. `for v := range seq { ... }` is compiled into a call to seq
+// with the loop body as yield - a break makes yield return false.
+// iter.Seq2[K, V] is the same with two values per step, like `for i, v := range slice`.
+//
+// Against a channel generator (concurrency/generators.go), the same "values one at a time, made on demand":
+// - no goroutine and no channel: the iterator runs in the loop's own goroutine, each step is a function call
+//   (tens of times faster), there is nothing to leak, and no done channel is needed to stop early - break is enough
+// - no concurrency either: the producer can't get ahead of the consumer or run on another core.
+//   When that's the point (a producer doing slow I/O while the consumer works), a channel is still the tool
+//
+// The adaptors below take a Seq and return a Seq, so they chain: Map(Filter(tree.All(), even), square).
+// Nothing runs until the result is ranged over, and only as far as the loop goes.
+
+// Filter yields the values of seq that keep returns true for
+func Filter[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return // the loop over the result stopped - stop the loop over seq too
+			}
+		}
+	}
+}
+
+// Map yields f(v) for each value of seq
+func Map[T, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Take yields at most the first n values of seq - which makes an endless seq safe to range over
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if i++; i == n {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate pairs each value with its position, as a Seq2: for i, v := range Enumerate(seq)
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
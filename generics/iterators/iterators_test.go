@@ -0,0 +1,92 @@
+package iterators
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+// counted yields 1, 2, 3, ... forever, counting in *pulled how many values were asked for
+func counted(pulled *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 1; ; i++ {
+			*pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func isEven(n int) bool { return n%2 == 0 }
+
+func TestFilter(t *testing.T) {
+	if got := slices.Collect(Filter(slices.Values([]int{1, 2, 3, 4, 5, 6}), isEven)); !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("Filter: %v, want [2 4 6]", got)
+	}
+	if got := slices.Collect(Filter(slices.Values([]int{1, 3}), isEven)); len(got) != 0 {
+		t.Errorf("Filter keeping nothing: %v", got)
+	}
+}
+
+func TestMap(t *testing.T) {
+	got := slices.Collect(Map(slices.Values([]string{"go", "is", "fun"}), func(s string) int { return len(s) }))
+	if !slices.Equal(got, []int{2, 2, 3}) {
+		t.Errorf("Map: %v, want [2 2 3]", got)
+	}
+	if got := slices.Collect(Map(slices.Values([]int(nil)), isEven)); len(got) != 0 {
+		t.Errorf("Map of nothing: %v", got)
+	}
+}
+
+func TestTake(t *testing.T) {
+	for _, c := range []struct {
+		n    int
+		want []int
+	}{
+		{3, []int{1, 2, 3}},
+		{1, []int{1}},
+		{0, nil},
+		{-2, nil},
+	} {
+		pulled := 0
+		if got := slices.Collect(Take(counted(&pulled), c.n)); !slices.Equal(got, c.want) {
+			t.Errorf("Take %d: %v, want %v", c.n, got, c.want)
+		}
+		if pulled != len(c.want) {
+			t.Errorf("Take %d pulled %d values from its seq, want %d", c.n, pulled, len(c.want))
+		}
+	}
+	if got := slices.Collect(Take(slices.Values([]int{7, 8}), 5)); !slices.Equal(got, []int{7, 8}) {
+		t.Errorf("Take past the end: %v, want [7 8]", got)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	var got []string
+	for i, s := range Enumerate(slices.Values([]string{"a", "b", "c"})) {
+		got = append(got, string(rune('0'+i))+s)
+	}
+	if want := []string{"0a", "1b", "2c"}; !slices.Equal(got, want) {
+		t.Errorf("Enumerate: %v, want %v", got, want)
+	}
+}
+
+// A break in the outer loop stops the whole chain: nothing is pulled from the source after it
+func TestChainStopsEarly(t *testing.T) {
+	pulled := 0
+	square := func(n int) int { return n * n }
+	var got []int
+	for i, v := range Enumerate(Map(Filter(counted(&pulled), isEven), square)) {
+		got = append(got, v)
+		if i == 2 {
+			break
+		}
+	}
+	if !slices.Equal(got, []int{4, 16, 36}) {
+		t.Errorf("got %v, want [4 16 36]", got)
+	}
+	if pulled != 6 {
+		t.Errorf("pulled %d values from the source, want 6 - the chain didn't stop at the break", pulled)
+	}
+}
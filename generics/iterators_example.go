@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"generics/iterators"
+	"iter"
+	"slices"
+)
+
+// --- Range-over-func iterators (see iterators/) ---
+
+// All iterates over the list's values, front to back: for v := range l.All()
+func (l *LList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := l; n != nil; n = n.next {
+			if !yield(n.val) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates over the tree in order - smallest first. The recursion is the usual in-order walk;
+// what makes it an iterator is that it stops (all the way up) as soon as yield returns false.
+// (A channel generator for this needs a goroutine holding the recursion's stack, and a done channel
+// to unwind it if the loop breaks)
+func (n *bstNode[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		n.walk(yield)
+	}
+}
+
+func (n *bstNode[T]) walk(yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.walk(yield) && yield(n.val) && n.right.walk(yield)
+}
+
+// endless counts up from 1 forever - only safe with a break or Take
+func endless() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 1; yield(i); i++ {
+		}
+	}
+}
+
+func iteratorsExample() {
+	l := LListFrom([]string{"go", "is", "fun"})
+	for i, w := range iterators.Enumerate(l.All()) {
+		fmt.Printf("%d:%s ", i, w)
+	}
+	fmt.Println()
+
+	var tree *bstNode[int]
+	for _, v := range []int{50, 30, 70, 20, 40, 60, 80, 35} {
+		tree = tree.insert(v)
+	}
+	fmt.Println("tree in order:", slices.Collect(tree.All()))
+
+	isEven := func(n int) bool { return n%2 == 0 }
+	label := func(n int) string { return fmt.Sprintf("<%d>", n) }
+	fmt.Println("even values, labelled:", slices.Collect(iterators.Map(iterators.Filter(tree.All(), isEven), label)))
+
+	// Stopping early: break ends the walk - the rest of the tree isn't visited
+	visited := 0
+	counting := func(yield func(int) bool) {
+		for v := range tree.All() {
+			visited++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+	for v := range counting {
+		if v >= 40 {
+			break
+		}
+	}
+	fmt.Println("visited before the break at 40:", visited, "of", len(slices.Collect(tree.All())))
+
+	first5 := slices.Collect(iterators.Take(endless(), 5))
+	fmt.Println("Take from an endless seq:", first5)
+	// iterators_example_test.go and iterators/iterators_test.go check these;
+	// go test -bench SeqVsChannel measures the iterator against a channel generator
+}
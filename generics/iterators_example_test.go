@@ -0,0 +1,96 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func testTree(vals ...int) *bstNode[int] {
+	var tree *bstNode[int]
+	for _, v := range vals {
+		tree = tree.insert(v)
+	}
+	return tree
+}
+
+func TestLListAll(t *testing.T) {
+	l := LListFrom([]string{"go", "is", "fun"})
+	if got := slices.Collect(l.All()); !slices.Equal(got, l.ToSlice()) {
+		t.Errorf("All: %v, want %v", got, l.ToSlice())
+	}
+	if got := slices.Collect((*LList[int])(nil).All()); len(got) != 0 {
+		t.Errorf("All of an empty list: %v", got)
+	}
+}
+
+func TestTreeAll(t *testing.T) {
+	vals := []int{50, 30, 70, 20, 40, 60, 80, 35}
+	got := slices.Collect(testTree(vals...).All())
+	if want := slices.Sorted(slices.Values(vals)); !slices.Equal(got, want) {
+		t.Errorf("in order: %v, want %v", got, want)
+	}
+	if got := slices.Collect((*bstNode[int])(nil).All()); len(got) != 0 {
+		t.Errorf("All of an empty tree: %v", got)
+	}
+	// anything taking an iter.Seq takes it
+	if desc := slices.SortedFunc(testTree(vals...).All(), func(a, b int) int { return cmp.Compare(b, a) }); desc[0] != 80 {
+		t.Errorf("SortedFunc descending starts with %d, want 80", desc[0])
+	}
+}
+
+// A break stops the walk all the way up: nodes past it aren't visited
+func TestTreeAllStopsEarly(t *testing.T) {
+	tree := testTree(50, 30, 70, 20, 40, 60, 80, 35)
+	visited := 0
+	for v := range tree.All() {
+		visited++
+		if v >= 40 {
+			break
+		}
+	}
+	if visited != 4 { // 20 30 35 40
+		t.Errorf("visited %d values before the break at 40, want 4", visited)
+	}
+}
+
+// chanGenerator is endless as a channel generator, for the comparison (intSequence in concurrency/generators.go)
+func chanGenerator(done <-chan struct{}) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 1; ; i++ {
+			select {
+			case out <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// The cost of the goroutine and channel - the first 1000 numbers each way:
+// go test -bench SeqVsChannel
+func BenchmarkSeqVsChannel(b *testing.B) {
+	b.Run("iter.Seq", func(b *testing.B) {
+		for b.Loop() {
+			for v := range endless() {
+				if v == 1000 {
+					break
+				}
+			}
+		}
+	})
+	b.Run("channel", func(b *testing.B) {
+		for b.Loop() {
+			done := make(chan struct{})
+			for v := range chanGenerator(done) {
+				if v == 1000 {
+					break
+				}
+			}
+			close(done)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+// --- LList, the exercise (see generics.go) ---
+
+func llistExample() {
+	var l *LList[int] // the empty list
+	fmt.Println("empty:", l, "| len:", l.Len(), "| contains 1:", l.Contains(1))
+
+	l = l.Push(2).Push(1) // 1 -> 2
+	l = l.Append(4)
+	l.InsertAfter(2, 3)
+	fmt.Println("pushed, appended, inserted:", l, "| len:", l.Len())
+
+	l = l.Remove(1) // the first node
+	l = l.Remove(3) // one in the middle
+	l = l.Remove(9) // not there - nothing happens
+	fmt.Println("after removes:", l)
+
+	words := LListFrom([]string{"go", "is", "fun"})
+	fmt.Println("from a slice:", words)
+	// Reverse reuses the nodes, so words (still the old first node) is now the last one: [go]
+	reversed := words.Reverse()
+	fmt.Println("reversed:", reversed, "| words now:", words)
+
+	// llist_test.go checks each method against the same operations on a slice
+}
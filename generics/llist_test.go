@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestLList(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	insertAfterLast := LListFrom(s)
+	insertAfterLast.InsertAfter(5, 6)
+	insertInMiddle := LListFrom(s)
+	insertInMiddle.InsertAfter(2, 9)
+	for _, c := range []struct {
+		name string
+		got  *LList[int]
+		want []int
+	}{
+		{"LListFrom", LListFrom(s), s},
+		{"LListFrom(nil)", LListFrom([]int(nil)), nil},
+		{"Push", LListFrom(s).Push(0), []int{0, 1, 2, 3, 4, 5}},
+		{"Push on empty", (*LList[int])(nil).Push(1), []int{1}},
+		{"Append", LListFrom(s).Append(6), []int{1, 2, 3, 4, 5, 6}},
+		{"Append to empty", (*LList[int])(nil).Append(1), []int{1}},
+		{"InsertAfter the last", insertAfterLast, []int{1, 2, 3, 4, 5, 6}},
+		{"InsertAfter in the middle", insertInMiddle, []int{1, 2, 9, 3, 4, 5}},
+		{"Remove the first", LListFrom(s).Remove(1), []int{2, 3, 4, 5}},
+		{"Remove in the middle", LListFrom(s).Remove(3), []int{1, 2, 4, 5}},
+		{"Remove the last", LListFrom(s).Remove(5), []int{1, 2, 3, 4}},
+		{"Remove the only one", LListFrom([]int{7}).Remove(7), nil},
+		{"Remove only the first of two", LListFrom([]int{1, 2, 1}).Remove(1), []int{2, 1}},
+		{"Remove a missing value", LListFrom(s).Remove(9), s},
+		{"Remove from empty", (*LList[int])(nil).Remove(1), nil},
+		{"Reverse", LListFrom(s).Reverse(), []int{5, 4, 3, 2, 1}},
+		{"Reverse of one", LListFrom([]int{7}).Reverse(), []int{7}},
+		{"Reverse of empty", (*LList[int])(nil).Reverse(), nil},
+	} {
+		if got := c.got.ToSlice(); !slices.Equal(got, c.want) || c.got.Len() != len(c.want) {
+			t.Errorf("%s: %v (Len %d), want %v", c.name, got, c.got.Len(), c.want)
+		}
+	}
+}
+
+func TestLListInsertAfterMissing(t *testing.T) {
+	l := LListFrom([]int{1, 2})
+	if l.InsertAfter(9, 0) || !slices.Equal(l.ToSlice(), []int{1, 2}) {
+		t.Errorf("InsertAfter a missing value: changed the list to %v", l)
+	}
+	if (*LList[int])(nil).InsertAfter(1, 2) {
+		t.Error("InsertAfter on an empty list reported success")
+	}
+}
+
+func TestLListContains(t *testing.T) {
+	l := LListFrom([]string{"go", "is", "fun"})
+	if !l.Contains("go") || !l.Contains("fun") || l.Contains("") || (*LList[string])(nil).Contains("go") {
+		t.Errorf("Contains on %v gives wrong answers", l)
+	}
+}
+
+func TestLListString(t *testing.T) {
+	for _, c := range []struct {
+		l    *LList[string]
+		want string
+	}{
+		{nil, "[]"},
+		{LListFrom([]string{"go"}), "[go]"},
+		{LListFrom([]string{"go", "is", "fun"}), "[go -> is -> fun]"},
+	} {
+		if got := c.l.String(); got != c.want {
+			t.Errorf("String = %q, want %q", got, c.want)
+		}
+	}
+}
+
+func TestLListMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(LListFrom([]string{"a", "b"}))
+	if err != nil || string(b) != `["a","b"]` {
+		t.Errorf("Marshal = %s, %v", b, err)
+	}
+	// nil encodes as null - json.Marshal doesn't call MarshalJSON on a nil pointer
+	if b, _ := json.Marshal((*LList[int])(nil)); string(b) != "null" {
+		t.Errorf("Marshal(nil) = %s, want null", b)
+	}
+	// comparable, but not something encoding/json supports
+	if _, err := json.Marshal(LListFrom([]complex128{1i})); err == nil {
+		t.Error("Marshal of an LList[complex128]: no error")
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"generics/collections"
+)
+
+// --- An LRU cache (see collections/lru.go) ---
+
+func lruExample() {
+	var evicted []string
+	cache := collections.NewLRU(3, func(k string, v int) {
+		evicted = append(evicted, fmt.Sprintf("%s=%d", k, v))
+	})
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	fmt.Println("most recent first:", cache.Keys())
+
+	cache.Get("a") // a is used, so b is now the oldest
+	cache.Put("d", 4)
+	fmt.Println("after Get(a), Put(d):", cache.Keys(), "| evicted:", evicted)
+	_, hasB := cache.Get("b")
+	fmt.Println("b is gone:", !hasB)
+
+	cache.Put("c", 30) // an update: no eviction, c moves to the front
+	cache.Put("e", 5)  // a is the oldest now
+	fmt.Println("after Put(c, 30), Put(e):", cache.Keys(), "| evicted:", evicted)
+
+	// collections/lru_test.go checks the eviction order against a simple model over random operations
+	evicted = nil
+	fmt.Println("Remove:", cache.Remove("d"), "| again:", cache.Remove("d"), "| evicted:", evicted, "| keys:", cache.Keys())
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// --- Min, Max, Sum, Average over Numbers ---
+
+// Index[T comparable] only needs ==. These need < and +, so they take a Number (constraints.go) -
+// one function for every integer and float type instead of one per type.
+// (Sum is SumOf and Clamp is Clamp, both in constraints.go)
+//
+// Edge cases:
+// - an empty slice has no minimum, maximum or average - those return false rather than making one up
+//   (slices.Min panics instead)
+// - NaN: any comparison with NaN is false, so a loop of `if v < m` silently skips it or not depending on
+//   where it is. The builtin min and max return NaN if any argument is NaN, and these follow them:
+//   a NaN anywhere gives NaN, wherever it is in the slice
+
+func Min[T Number](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	m := s[0]
+	for _, v := range s[1:] {
+		m = min(m, v)
+	}
+	return m, true
+}
+
+func Max[T Number](s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	m := s[0]
+	for _, v := range s[1:] {
+		m = max(m, v)
+	}
+	return m, true
+}
+
+// Average is a float64 whatever T is - the average of 1 and 2 is 1.5, not the int 1.
+// The sum is taken in float64 too, so ints near the top of their range don't overflow on the way
+func Average[T Number](s []T) (float64, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	var total float64
+	for _, v := range s {
+		total += float64(v)
+	}
+	return total / float64(len(s)), true
+}
+
+func numbersExample() {
+	ints := []int{3, -7, 12, 0}
+	temps := []Celsius{18.5, 23, 21.25}
+	bytes := []uint8{200, 100, 250}
+
+	lo, _ := Min(ints)
+	hi, _ := Max(temps)
+	avg, _ := Average(bytes)
+	fmt.Println("min ints:", lo, "| max Celsius:", hi, "| average uint8:", avg, "| sum uint8 (wraps):", SumOf(bytes...))
+	fmt.Println("clamp:", Clamp(12, 0, 10), Clamp(Celsius(-5), 0, 30), Clamp(uint8(7), 10, 20))
+
+	// numbers_test.go covers the edge cases: empty slices, NaN, infinities, -0
+	_, ok := Min([]int{})
+	fmt.Println("min of nothing ok:", ok, "| NaN wins:", must(Max([]float64{1, math.NaN(), 2})))
+}
+
+// must drops the ok of a result that is known to be there
+func must[T any](v T, ok bool) T {
+	if !ok {
+		panic("no value")
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumbersEmpty(t *testing.T) {
+	_, okMin := Min([]int{})
+	_, okMax := Max([]float64(nil))
+	_, okAvg := Average([]int{})
+	if okMin || okMax || okAvg {
+		t.Errorf("empty: Min %t, Max %t, Average %t, want all false", okMin, okMax, okAvg)
+	}
+	if SumOf[int]() != 0 {
+		t.Error("SumOf() isn't 0")
+	}
+}
+
+func TestMinMaxAverage(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		s      []int
+		lo, hi int
+		avg    float64
+	}{
+		{"one element", []int{5}, 5, 5, 5},
+		{"mixed", []int{3, -7, 12, 0}, -7, 12, 2},
+		{"negatives", []int{-1, -9, -3}, -9, -1, -13.0 / 3},
+		{"not truncated", []int{1, 2}, 1, 2, 1.5},
+	} {
+		if lo, hi, avg := must(Min(c.s)), must(Max(c.s)), must(Average(c.s)); lo != c.lo || hi != c.hi || avg != c.avg {
+			t.Errorf("%s: Min %d, Max %d, Average %v; want %d, %d, %v", c.name, lo, hi, avg, c.lo, c.hi, c.avg)
+		}
+	}
+	// defined types and unsigned ones
+	if hi := must(Max([]Celsius{18.5, 23, 21.25})); hi != 23 {
+		t.Errorf("Max Celsius = %v", hi)
+	}
+	if avg := must(Average([]uint8{200, 100, 250})); avg != 550.0/3 {
+		t.Errorf("Average uint8 = %v, want %v - summed in float64, so no wrap at 255", avg, 550.0/3)
+	}
+	if avg := must(Average([]int64{math.MaxInt64, math.MaxInt64})); avg != math.MaxInt64 {
+		t.Errorf("Average of two MaxInt64 = %v, want no overflow", avg)
+	}
+}
+
+// A NaN anywhere gives NaN - the same as the builtin min and max
+func TestNaN(t *testing.T) {
+	nan := math.NaN()
+	for _, s := range [][]float64{{nan, 1, 2}, {1, nan, 2}, {1, 2, nan}, {nan}} {
+		if !math.IsNaN(must(Min(s))) || !math.IsNaN(must(Max(s))) || !math.IsNaN(must(Average(s))) {
+			t.Errorf("%v: Min %v, Max %v, Average %v, want NaN for all", s, must(Min(s)), must(Max(s)), must(Average(s)))
+		}
+	}
+	if !math.IsNaN(Clamp(nan, 0, 1)) {
+		t.Error("Clamp(NaN) isn't NaN")
+	}
+}
+
+func TestInfinitiesAndSignedZero(t *testing.T) {
+	if lo := must(Min([]float64{1, math.Inf(-1)})); !math.IsInf(lo, -1) {
+		t.Errorf("Min with -Inf = %v", lo)
+	}
+	if hi := must(Max([]float64{1, math.Inf(1)})); !math.IsInf(hi, 1) {
+		t.Errorf("Max with +Inf = %v", hi)
+	}
+	negZero := math.Copysign(0, -1)
+	if lo := must(Min([]float64{0, negZero})); !math.Signbit(lo) {
+		t.Error("Min(0, -0) isn't -0")
+	}
+	if hi := must(Max([]float64{negZero, 0})); math.Signbit(hi) {
+		t.Error("Max(-0, 0) isn't +0")
+	}
+}
+
+func TestClamp(t *testing.T) {
+	for _, c := range []struct{ v, want int }{{5, 5}, {-1, 0}, {11, 10}, {0, 0}, {10, 10}} {
+		if got := Clamp(c.v, 0, 10); got != c.want {
+			t.Errorf("Clamp(%d, 0, 10) = %d, want %d", c.v, got, c.want)
+		}
+	}
+	if got := Clamp(Celsius(-5), 0, 30); got != 0 {
+		t.Errorf("Clamp(Celsius(-5), 0, 30) = %v", got)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// --- Generic structs with methods: Option[T] and Result[T] ---
+
+// A method on a generic type names the type parameters in its receiver - func (o Option[T]) Get() -
+// and can use T, but can't add type parameters of its own. So Map from T to another type U has to be
+// a function (MapOption), and the method version can only map T to T.
+// Pointer receivers work as on any type: *Option[T] is "a pointer to an Option of T".
+//
+// Go's own answer to "maybe a value" is (v, ok) and to "a value or an error" is (v, err) - these don't
+// replace those, they are for when the pair has to be stored or passed around as one value
+// (a field, a map value, a slice of results from goroutines).
+
+// Option holds a T, or nothing. The zero value is None
+type Option[T any] struct {
+	val T
+	ok  bool
+}
+
+func Some[T any](v T) Option[T] { return Option[T]{val: v, ok: true} }
+
+// None needs its T spelled out - there's no argument to infer it from: None[int]()
+func None[T any]() Option[T] { return Option[T]{} }
+
+// Get is the (v, ok) form, for an if
+func (o Option[T]) Get() (T, bool) { return o.val, o.ok }
+
+func (o Option[T]) IsSome() bool { return o.ok }
+
+func (o Option[T]) OrElse(fallback T) T {
+	if o.ok {
+		return o.val
+	}
+	return fallback
+}
+
+// Map applies f to the value, if there is one. f must return a T - see MapOption for other types
+func (o Option[T]) Map(f func(T) T) Option[T] {
+	if !o.ok {
+		return o
+	}
+	return Some(f(o.val))
+}
+
+// Take returns the value and leaves None behind - a pointer receiver, since it changes o
+func (o *Option[T]) Take() Option[T] {
+	taken := *o
+	*o = None[T]()
+	return taken
+}
+
+// Set stores v, replacing what was there
+func (o *Option[T]) Set(v T) { *o = Some(v) }
+
+func (o Option[T]) String() string {
+	if !o.ok {
+		return "None"
+	}
+	return fmt.Sprintf("Some(%v)", o.val)
+}
+
+// MapOption is Map to another type - a function, since methods can't have type parameters
+func MapOption[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return Some(f(o.val))
+}
+
+// Result holds a T or an error. The zero value is Ok of the zero T (a nil error)
+type Result[T any] struct {
+	val T
+	err error
+}
+
+func Ok[T any](v T) Result[T] { return Result[T]{val: v} }
+
+func Err[T any](err error) Result[T] { return Result[T]{err: err} }
+
+// ResultOf wraps the (v, err) a function returns: ResultOf(strconv.Atoi(s))
+func ResultOf[T any](v T, err error) Result[T] { return Result[T]{val: v, err: err} }
+
+// Get is back to the (v, err) form, for an if err != nil
+func (r Result[T]) Get() (T, error) { return r.val, r.err }
+
+func (r Result[T]) Err() error { return r.err }
+
+// Unwrap returns the value and panics on an error - for when an error is a bug (tests, constants).
+// Not errors.Unwrap: that one is the method `Unwrap() error` on an error, finding the error it wraps
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Sprintf("Unwrap on an error: %v", r.err))
+	}
+	return r.val
+}
+
+func (r Result[T]) OrElse(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+	return r.val
+}
+
+func (r Result[T]) String() string {
+	if r.err != nil {
+		return fmt.Sprintf("Err(%v)", r.err)
+	}
+	return fmt.Sprintf("Ok(%v)", r.val)
+}
+
+// MapResult applies f to the value, or passes the error along - f itself can fail too
+func MapResult[T, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return ResultOf(f(r.val))
+}
+
+var errNegative = errors.New("negative")
+
+func optionResultExample() {
+	// Option: a value that may be missing, stored as one value
+	nicknames := map[string]Option[string]{"alice": Some("ally"), "bob": None[string]()}
+	for _, name := range []string{"alice", "bob"} {
+		nick := nicknames[name]
+		fmt.Printf("%s: %v, greeting %s\n", name, nick, nick.Map(func(s string) string { return "hey " + s }).OrElse("hello "+name))
+	}
+	length := MapOption(Some("gopher"), func(s string) int { return len(s) })
+	fmt.Println("MapOption to int:", length)
+
+	var slot Option[int] // zero value: None
+	slot.Set(7)
+	taken := slot.Take()
+	fmt.Println("taken:", taken, "| left:", slot)
+
+	// Result: a value or an error, stored as one value - ex. results from several parses
+	parse := func(s string) Result[int] { return ResultOf(strconv.Atoi(s)) }
+	sqrtInt := func(n int) (int, error) {
+		if n < 0 {
+			return 0, fmt.Errorf("sqrt of %d: %w", n, errNegative)
+		}
+		r := 0
+		for (r+1)*(r+1) <= n {
+			r++
+		}
+		return r, nil
+	}
+	var results []Result[int]
+	for _, s := range []string{"16", "x", "-4"} {
+		results = append(results, MapResult(parse(s), sqrtInt))
+	}
+	fmt.Println("parse then sqrt:", results)
+
+	// The error inside is an ordinary error: errors.Is and errors.As see through the wrapping as usual
+	var numErr *strconv.NumError
+	fmt.Println("x failed to parse:", errors.As(results[1].Err(), &numErr), "| -4 is negative:", errors.Is(results[2].Err(), errNegative))
+	if v, err := results[0].Get(); err == nil {
+		fmt.Println("back to (v, err):", v)
+	}
+
+	// option_test.go covers the zero values, None/Err passing through Map, OrElse and Unwrap
+}
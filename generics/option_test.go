@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestOption(t *testing.T) {
+	if (Option[int]{}).IsSome() {
+		t.Error("zero Option isn't None")
+	}
+	if v, ok := Some(3).Get(); v != 3 || !ok {
+		t.Errorf("Some(3).Get() = %d, %t", v, ok)
+	}
+	if v, ok := None[string]().Get(); v != "" || ok {
+		t.Errorf("None.Get() = %q, %t", v, ok)
+	}
+	inc := func(i int) int { return i + 1 }
+	if got := Some(1).Map(inc); got != Some(2) {
+		t.Errorf("Some(1).Map(inc) = %v", got)
+	}
+	if got := None[int]().Map(inc); got.IsSome() {
+		t.Errorf("None.Map = %v, want None", got)
+	}
+	if Some(1).OrElse(9) != 1 || None[int]().OrElse(9) != 9 {
+		t.Error("OrElse")
+	}
+	if got := MapOption(Some("gopher"), func(s string) int { return len(s) }); got != Some(6) {
+		t.Errorf("MapOption = %v", got)
+	}
+	if got := MapOption(None[string](), func(s string) int { panic("not called") }); got.IsSome() {
+		t.Errorf("MapOption(None) = %v", got)
+	}
+	if Some("a").String() != "Some(a)" || None[int]().String() != "None" {
+		t.Errorf("String: %s, %s", Some("a"), None[int]())
+	}
+}
+
+// Take and Set have pointer receivers: they change the Option they're called on
+func TestOptionPointerMethods(t *testing.T) {
+	var slot Option[int]
+	slot.Set(7)
+	if slot != Some(7) {
+		t.Errorf("after Set(7): %v", slot)
+	}
+	if taken := slot.Take(); taken != Some(7) || slot.IsSome() {
+		t.Errorf("Take = %v, left %v; want Some(7) and None", taken, slot)
+	}
+	if taken := slot.Take(); taken.IsSome() {
+		t.Errorf("Take on None = %v", taken)
+	}
+}
+
+func TestResult(t *testing.T) {
+	if (Result[int]{}).Err() != nil {
+		t.Error("zero Result isn't Ok")
+	}
+	errBoom := errors.New("boom")
+	if v, err := Ok(4).Get(); v != 4 || err != nil {
+		t.Errorf("Ok(4).Get() = %d, %v", v, err)
+	}
+	if _, err := Err[int](errBoom).Get(); err != errBoom {
+		t.Errorf("Err.Get() error = %v", err)
+	}
+	if Ok(4).OrElse(-1) != 4 || Err[int](errBoom).OrElse(-1) != -1 {
+		t.Error("OrElse")
+	}
+	if Ok(1).String() != "Ok(1)" || Err[int](errBoom).String() != "Err(boom)" {
+		t.Errorf("String: %s, %s", Ok(1), Err[int](errBoom))
+	}
+}
+
+func TestMapResult(t *testing.T) {
+	parse := func(s string) Result[int] { return ResultOf(strconv.Atoi(s)) }
+	double := func(n int) (int, error) {
+		if n < 0 {
+			return 0, errNegative
+		}
+		return 2 * n, nil
+	}
+	if got := MapResult(parse("16"), double); got.Unwrap() != 32 {
+		t.Errorf("16: %v", got)
+	}
+	// the parse error passes along, f isn't called
+	got := MapResult(parse("x"), func(int) (int, error) { panic("not called") })
+	var numErr *strconv.NumError
+	if !errors.As(got.Err(), &numErr) {
+		t.Errorf("x: %v, want a *strconv.NumError", got)
+	}
+	// f's own error
+	if got := MapResult(parse("-4"), double); !errors.Is(got.Err(), errNegative) {
+		t.Errorf("-4: %v, want errNegative", got)
+	}
+}
+
+func TestResultUnwrapPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Unwrap on an error didn't panic")
+		}
+	}()
+	Err[int](errors.New("boom")).Unwrap()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// --- Paginate: filling in PaginatedResDto ---
+
+var (
+	errInvalidPerPage = errors.New("perPage must be at least 1")
+	errPageOutOfRange = errors.New("page out of range")
+)
+
+// Paginate returns page number page (counting from 1) of items, perPage items to a page.
+// Data is a copy of that window, never nil - it encodes as [] rather than null, even for an empty list.
+// An empty list has one, empty, page 1, so a client asking for the first page never gets an error
+func Paginate[T any](items []T, page, perPage int) (PaginatedResDto[T], error) {
+	if perPage < 1 {
+		return PaginatedResDto[T]{}, fmt.Errorf("%w, got %d", errInvalidPerPage, perPage)
+	}
+	totalPages := 1
+	if len(items) > 0 {
+		totalPages = (len(items)-1)/perPage + 1 // round up - without len+perPage-1, which overflows for a huge perPage
+	}
+	if page < 1 || page > totalPages {
+		return PaginatedResDto[T]{}, fmt.Errorf("%w: page %d of %d", errPageOutOfRange, page, totalPages)
+	}
+
+	start := (page - 1) * perPage
+	end := min(start+perPage, len(items))
+	res := PaginatedResDto[T]{
+		TotalItems:   len(items),
+		TotalPages:   totalPages,
+		CurrPage:     page,
+		ItemsPerPage: perPage,
+		Data:         append(make([]T, 0, end-start), items[start:end]...), // a copy: the caller's slice can change later
+	}
+	if page < totalPages {
+		next := page + 1
+		res.NextPage = &next
+	}
+	if page > 1 {
+		prev := page - 1
+		res.PrevPage = &prev
+	}
+	return res, nil
+}
+
+// String is a one-line summary, for logs: page 2 of 3 (5 items): [{...} {...}].
+// Data's elements are printed with %v - any T prints, through its own String method if it has one
+func (p PaginatedResDto[T]) String() string {
+	return fmt.Sprintf("page %d of %d (%d items): %v", p.CurrPage, p.TotalPages, p.TotalItems, p.Data)
+}
+
+// paginatedJSON has PaginatedResDto's fields and json tags, and none of its methods - so MarshalJSON can
+// encode one without calling itself forever. It has to be declared out here: a generic function or method
+// can't declare a type inside itself
+type paginatedJSON[T any] PaginatedResDto[T]
+
+// MarshalJSON keeps data an array on the wire: Paginate never leaves it nil, but a PaginatedResDto made
+// some other way - a zero value, a literal without Data - would encode "data": null, and clients ranging
+// over it would trip on that. A value receiver, so a PaginatedResDto and a pointer to one both have it
+func (p PaginatedResDto[T]) MarshalJSON() ([]byte, error) {
+	if p.Data == nil {
+		p.Data = []T{} // p is a copy - the caller's value isn't changed
+	}
+	return json.Marshal(paginatedJSON[T](p))
+}
+
+func paginateExample() {
+	users := []User{
+		{UserId: "96aeb270", Name: "Jack Eod"},
+		{UserId: "1d02455e", Name: "John Doe"},
+		{UserId: "0b7c61f2", Name: "Jack Eod"},
+		{UserId: "5e1a90c3", Name: "Alice"},
+		{UserId: "33d4e8a1", Name: "John Doe"},
+	}
+	for page := 1; page <= 3; page++ {
+		res, _ := Paginate(users, page, 2)
+		body, _ := json.Marshal(res)
+		fmt.Printf("page %d: %s\n", page, body)
+	}
+
+	_, err := Paginate(users, 4, 2)
+	fmt.Println("past the end:", err) // paginate_test.go checks the edges (empty, exact, out of range) and the JSON
+}
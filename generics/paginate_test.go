@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func testPageUsers() []User {
+	return []User{
+		{UserId: "96aeb270", Name: "Jack Eod"},
+		{UserId: "1d02455e", Name: "John Doe"},
+		{UserId: "0b7c61f2", Name: "Jack Eod"},
+		{UserId: "5e1a90c3", Name: "Alice"},
+		{UserId: "33d4e8a1", Name: "John Doe"},
+	}
+}
+
+func pageNum(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func TestPaginate(t *testing.T) {
+	users := testPageUsers()
+	for _, c := range []struct {
+		name                   string
+		items                  []User
+		page, perPage          int
+		totalPages, next, prev int // 0 for no next/prev page
+		data                   []User
+	}{
+		{"first page", users, 1, 2, 3, 2, 0, users[0:2]},
+		{"middle page", users, 2, 2, 3, 3, 1, users[2:4]},
+		{"last page, left over item", users, 3, 2, 3, 0, 2, users[4:]},
+		{"exactly full last page", users[:4], 2, 2, 2, 0, 1, users[2:4]},
+		{"perPage past the end", users, 1, 100, 1, 0, 0, users},
+		{"huge perPage", users, 1, math.MaxInt, 1, 0, 0, users},
+		{"one per page", users, 5, 1, 5, 0, 4, users[4:]},
+		{"empty list", []User{}, 1, 10, 1, 0, 0, []User{}},
+	} {
+		res, err := Paginate(c.items, c.page, c.perPage)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if res.TotalPages != c.totalPages || res.TotalItems != len(c.items) || res.CurrPage != c.page || res.ItemsPerPage != c.perPage ||
+			pageNum(res.NextPage) != c.next || pageNum(res.PrevPage) != c.prev || !slices.Equal(res.Data, c.data) {
+			t.Errorf("%s: %v (next %d, prev %d), want %d pages, next %d, prev %d, data %v",
+				c.name, res, pageNum(res.NextPage), pageNum(res.PrevPage), c.totalPages, c.next, c.prev, c.data)
+		}
+	}
+}
+
+func TestPaginateErrors(t *testing.T) {
+	users := testPageUsers()
+	for _, c := range []struct {
+		name          string
+		page, perPage int
+		want          error
+	}{
+		{"page 0", 0, 2, errPageOutOfRange},
+		{"negative page", -1, 2, errPageOutOfRange},
+		{"page past the end", 4, 2, errPageOutOfRange},
+		{"perPage 0", 1, 0, errInvalidPerPage},
+		{"negative perPage", 1, -3, errInvalidPerPage},
+	} {
+		if _, err := Paginate(users, c.page, c.perPage); !errors.Is(err, c.want) {
+			t.Errorf("%s: %v, want %v", c.name, err, c.want)
+		}
+	}
+	// the first page of nothing isn't an error, the second is
+	if _, err := Paginate([]User(nil), 2, 10); !errors.Is(err, errPageOutOfRange) {
+		t.Errorf("page 2 of an empty list: %v", err)
+	}
+}
+
+// Data is a copy of the window - changing the caller's slice afterwards doesn't reach it
+func TestPaginateCopies(t *testing.T) {
+	users := testPageUsers()
+	res, _ := Paginate(users, 1, 2)
+	users[0].Name = "changed"
+	if res.Data[0].Name != "Jack Eod" {
+		t.Errorf("Data[0] = %v, changed along with the input", res.Data[0])
+	}
+}
+
+func TestPaginateJSON(t *testing.T) {
+	empty, _ := Paginate([]User{}, 1, 10)
+	body, err := json.Marshal(empty)
+	if want := `{"totalItems":0,"totalPages":1,"currPage":1,"itemsPerPage":10,"nextPage":null,"prevPage":null,"data":[]}`; err != nil || string(body) != want {
+		t.Errorf("empty page: %s, %v\nwant %s", body, err, want)
+	}
+	// a zero value made without Paginate still encodes data as [], through a pointer too
+	for _, v := range []any{PaginatedResDto[int]{}, &PaginatedResDto[int]{}} {
+		var decoded map[string]any
+		b, _ := json.Marshal(v)
+		if err := json.Unmarshal(b, &decoded); err != nil || decoded["data"] == nil {
+			t.Errorf("zero value: %s, want \"data\":[]", b)
+		}
+	}
+
+	last, _ := Paginate(testPageUsers(), 3, 2)
+	body, _ = json.Marshal(last)
+	var decoded PaginatedResDto[User]
+	if err := json.Unmarshal(body, &decoded); err != nil || decoded.NextPage != nil || pageNum(decoded.PrevPage) != 2 || !slices.Equal(decoded.Data, last.Data) {
+		t.Errorf("round trip of %s: %v, %v", body, decoded, err)
+	}
+}
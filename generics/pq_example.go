@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"generics/collections"
+)
+
+// --- A priority queue (see collections/priorityqueue.go), scheduling jobs ---
+
+type job struct {
+	name     string
+	priority int // lower runs first
+	seq      int // the order it was submitted in - breaks ties, so equal priorities run first come first served
+}
+
+// scheduler runs the waiting job with the best priority each time it has room for one
+type scheduler struct {
+	queue *collections.PriorityQueue[job]
+	next  int
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{queue: collections.NewPriorityQueue(func(a, b job) bool {
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		return a.seq < b.seq
+	})}
+}
+
+func (s *scheduler) submit(name string, priority int) {
+	s.queue.Push(job{name: name, priority: priority, seq: s.next})
+	s.next++
+}
+
+// runNext "runs" the best waiting job, and returns its name
+func (s *scheduler) runNext() (string, bool) {
+	j, ok := s.queue.Pop()
+	return j.name, ok
+}
+
+func priorityQueueExample() {
+	s := newScheduler()
+	s.submit("send newsletter", 5)
+	s.submit("charge card", 1)
+	s.submit("resize image", 3)
+	s.submit("refund", 1) // same priority as charge card, submitted later
+	var order []string
+	for i := 0; ; i++ {
+		if i == 2 {
+			s.submit("page on-call", 0) // arrives while the queue is being worked through - goes straight to the front
+		}
+		name, ok := s.runNext()
+		if !ok {
+			break
+		}
+		order = append(order, name)
+	}
+	fmt.Println("run order:", order)
+	// pq_example_test.go checks this order, and collections/priorityqueue_test.go the queue itself
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// Ties run first come first served, and an urgent job submitted midway jumps the queue
+func TestSchedulerOrder(t *testing.T) {
+	s := newScheduler()
+	s.submit("send newsletter", 5)
+	s.submit("charge card", 1)
+	s.submit("resize image", 3)
+	s.submit("refund", 1)
+	var order []string
+	for i := 0; ; i++ {
+		if i == 2 {
+			s.submit("page on-call", 0)
+		}
+		name, ok := s.runNext()
+		if !ok {
+			break
+		}
+		order = append(order, name)
+	}
+	if want := []string{"charge card", "refund", "page on-call", "resize image", "send newsletter"}; !slices.Equal(order, want) {
+		t.Errorf("run order %q, want %q", order, want)
+	}
+}
+
+// Many equal priorities: the seq tie-breaker keeps them in submission order, though a heap isn't stable
+func TestSchedulerTiesInOrder(t *testing.T) {
+	s := newScheduler()
+	var want []string
+	for i := range 100 {
+		name := string(rune('A'+i%26)) + string(rune('0'+i/26))
+		s.submit(name, 1)
+		want = append(want, name)
+	}
+	var got []string
+	for name, ok := s.runNext(); ok; name, ok = s.runNext() {
+		got = append(got, name)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("equal priorities ran as %v, want submission order", got)
+	}
+}
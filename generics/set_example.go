@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"generics/collections"
+	"slices"
+)
+
+// --- A generic Set (see collections/set.go) ---
+
+// Dedupe drops repeated values, keeping the first of each in its original order -
+// the set remembers what has been seen, the slice keeps the order (a set has none)
+func Dedupe[T comparable](s []T) []T {
+	var seen collections.Set[T]
+	var out []T
+	for _, v := range s {
+		if !seen.Contains(v) {
+			seen.Add(v)
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func setExample() {
+	// The records from basics.go, collected from the range example, the map example and the append example -
+	// John Doe is in all three. User is comparable (all its fields are), so it can be a set element
+	const userId1, userId2 = "1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"
+	records := []User{
+		{UserId: userId1, Name: "John Doe"},
+		{UserId: userId2, Name: "Jack Eod"},
+		{UserId: userId2, Name: "Jack Eod"},
+		{UserId: userId1, Name: "John Doe"},
+		{userId1, "John Doe"},
+	}
+	unique := Dedupe(records)
+	fmt.Println("records:", len(records), "| unique:", len(unique), unique)
+
+	// The algebra, on user ids
+	admins := collections.NewSet("alice", "bob")
+	active := collections.NewSet("bob", "carol", "dave")
+	sorted := func(s *collections.Set[string]) []string { return slices.Sorted(s.All()) }
+	fmt.Println("union:", sorted(admins.Union(active)))
+	fmt.Println("intersection - active admins:", sorted(admins.Intersection(active)))
+	fmt.Println("difference - inactive admins:", sorted(admins.Difference(active)), "| active non-admins:", sorted(active.Difference(admins)))
+
+	// collections/set_test.go checks the laws these follow (a = (a - b) + (a & b), ...) on random sets
+
+	// Equal ignores order and duplicates, and the zero value and nil are empty sets
+	var empty collections.Set[string]
+	var nilSet *collections.Set[string]
+	fmt.Println("Equal ignores order and repeats:", collections.NewSet(1, 2, 3).Equal(collections.NewSet(3, 1, 2, 2)),
+		"| zero value equals nil:", empty.Equal(nilSet), "| nil union:", sorted(nilSet.Union(admins)))
+	admins.Remove("alice")
+	admins.Remove("nobody") // removing what isn't there is fine
+	fmt.Println("after Remove:", sorted(admins), "| contains alice:", admins.Contains("alice"))
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	john, jack := User{UserId: "1", Name: "John Doe"}, User{UserId: "2", Name: "Jack Eod"}
+	if got := Dedupe([]User{john, jack, jack, john, {"1", "John Doe"}}); !slices.Equal(got, []User{john, jack}) {
+		t.Errorf("Dedupe = %v, want the first of each, in order: %v", got, []User{john, jack})
+	}
+	// same name, different id: different users
+	other := User{UserId: "3", Name: "John Doe"}
+	if got := Dedupe([]User{john, other}); len(got) != 2 {
+		t.Errorf("Dedupe merged users with different ids: %v", got)
+	}
+	if got := Dedupe([]int(nil)); len(got) != 0 {
+		t.Errorf("Dedupe(nil) = %v", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// --- SortBy and SortByKey: sorting with a less function or a key ---
+
+// slices.SortFunc wants a three-way compare (negative, 0, positive). Often what's at hand is either
+// a less function (the sort.Slice style, and what a priority queue takes) or a key to sort by.
+// These adapt both to slices.SortFunc:
+// - a less function is turned into a compare by asking it both ways: less(a, b) -> -1, less(b, a) -> 1, else 0
+//   (so less must be a strict ordering: less(a, a) is false)
+// - a key function is By from compare.go: cmp.Compare on the keys - cmp.Compare, unlike <, orders NaN first,
+//   so float keys with NaN still sort consistently
+//
+// slices.SortFunc isn't stable: equal elements can end up in any order. The Stable variants keep equal elements
+// in the order they were in - what makes "sort by name, then sort by department" give "by department, then name".
+//
+// The key is computed on every comparison, twice - fine for a field, not for something expensive
+// (a lowercased copy of a string, a parse). Then compute the keys once, sort pairs, and unpack.
+
+func compareFromLess[T any](less func(a, b T) bool) func(a, b T) int {
+	return func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	}
+}
+
+func SortBy[T any](s []T, less func(a, b T) bool) {
+	slices.SortFunc(s, compareFromLess(less))
+}
+
+func SortStableBy[T any](s []T, less func(a, b T) bool) {
+	slices.SortStableFunc(s, compareFromLess(less))
+}
+
+func SortByKey[T any, K cmp.Ordered](s []T, key func(T) K) {
+	slices.SortFunc(s, By(key))
+}
+
+func SortStableByKey[T any, K cmp.Ordered](s []T, key func(T) K) {
+	slices.SortStableFunc(s, By(key))
+}
+
+type employee struct {
+	name string
+	dept string
+	age  int
+}
+
+func sortByExample() {
+	staff := []employee{
+		{"John Doe", "eng", 31}, {"Alice", "sales", 28}, {"Jack Eod", "eng", 45},
+		{"Bob", "sales", 31}, {"Carol", "eng", 28}, {"Dave", "ops", 39},
+	}
+	names := func(es []employee) string {
+		ns := make([]string, len(es))
+		for i, e := range es {
+			ns[i] = e.name
+		}
+		return strings.Join(ns, ", ")
+	}
+
+	byAge := slices.Clone(staff)
+	SortBy(byAge, func(a, b employee) bool { return a.age < b.age })
+	fmt.Println("by age:", names(byAge))
+
+	byName := slices.Clone(staff)
+	SortByKey(byName, func(e employee) string { return e.name })
+	fmt.Println("by name:", names(byName))
+
+	// Two stable sorts: by name, then by department - within each department, names stay sorted
+	grouped := slices.Clone(byName)
+	SortStableByKey(grouped, func(e employee) string { return e.dept })
+	fmt.Println("by department, then name:", names(grouped))
+
+	// Descending: swap the arguments of less, or Reverse a compare
+	desc := slices.Clone(staff)
+	SortBy(desc, func(a, b employee) bool { return a.age > b.age })
+	fmt.Println("descending by age:", names(desc))
+
+	// sortby_test.go checks the stable variants against sort.SliceStable, NaN keys, and empty slices
+}
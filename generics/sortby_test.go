@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func testStaff() []employee {
+	return []employee{
+		{"John Doe", "eng", 31}, {"Alice", "sales", 28}, {"Jack Eod", "eng", 45},
+		{"Bob", "sales", 31}, {"Carol", "eng", 28}, {"Dave", "ops", 39},
+	}
+}
+
+func staffNames(es []employee) []string {
+	ns := make([]string, len(es))
+	for i, e := range es {
+		ns[i] = e.name
+	}
+	return ns
+}
+
+func byAgeLess(a, b employee) bool { return a.age < b.age }
+func ageKey(e employee) int        { return e.age }
+
+func TestSortBy(t *testing.T) {
+	staff := testStaff()
+	SortBy(staff, byAgeLess)
+	if !slices.IsSortedFunc(staff, By(ageKey)) {
+		t.Errorf("SortBy age: %v", staff)
+	}
+	SortBy(staff, func(a, b employee) bool { return a.age > b.age }) // descending: less the other way round
+	if !slices.IsSortedFunc(staff, Reverse(By(ageKey))) || staff[0].name != "Jack Eod" {
+		t.Errorf("SortBy age descending: %v", staff)
+	}
+}
+
+func TestSortByKey(t *testing.T) {
+	staff := testStaff()
+	SortByKey(staff, func(e employee) string { return e.name })
+	if want := []string{"Alice", "Bob", "Carol", "Dave", "Jack Eod", "John Doe"}; !slices.Equal(staffNames(staff), want) {
+		t.Errorf("SortByKey name: %v, want %v", staffNames(staff), want)
+	}
+}
+
+// Equal elements keep their order in the Stable variants - checked against sort.SliceStable,
+// on random slices with plenty of equal keys
+func TestStableVariants(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	type item struct{ key, pos int }
+	for range 200 {
+		s := make([]item, r.IntN(50))
+		for i := range s {
+			s[i] = item{key: r.IntN(5), pos: i}
+		}
+		want := slices.Clone(s)
+		sort.SliceStable(want, func(i, j int) bool { return want[i].key < want[j].key })
+
+		byLess := slices.Clone(s)
+		SortStableBy(byLess, func(a, b item) bool { return a.key < b.key })
+		byKey := slices.Clone(s)
+		SortStableByKey(byKey, func(v item) int { return v.key })
+		if !slices.Equal(byLess, want) || !slices.Equal(byKey, want) {
+			t.Fatalf("stable sort of %v:\nSortStableBy    %v\nSortStableByKey %v\nwant            %v", s, byLess, byKey, want)
+		}
+	}
+}
+
+// Two stable sorts - by name, then by department - equal one sort on both keys
+func TestStableSortsCompose(t *testing.T) {
+	grouped := testStaff()
+	SortByKey(grouped, func(e employee) string { return e.name })
+	SortStableByKey(grouped, func(e employee) string { return e.dept })
+
+	want := testStaff()
+	slices.SortFunc(want, CompareBy(By(func(e employee) string { return e.dept }), By(func(e employee) string { return e.name })))
+	if !slices.Equal(grouped, want) {
+		t.Errorf("by department, then name: %v, want %v", staffNames(grouped), staffNames(want))
+	}
+}
+
+// cmp.Compare puts NaN first, so a float key with NaN still sorts to a consistent order
+func TestSortByKeyNaN(t *testing.T) {
+	s := []float64{3, math.NaN(), 1, math.NaN(), 2}
+	SortByKey(s, func(f float64) float64 { return f })
+	if !math.IsNaN(s[0]) || !math.IsNaN(s[1]) || !slices.Equal(s[2:], []float64{1, 2, 3}) {
+		t.Errorf("sorted with NaN: %v, want [NaN NaN 1 2 3]", s)
+	}
+}
+
+func TestSortByShort(t *testing.T) {
+	var none []employee
+	SortBy(none, byAgeLess)
+	SortStableByKey(none, ageKey)
+	one := testStaff()[:1]
+	SortBy(one, byAgeLess)
+	SortStableBy(one, byAgeLess)
+	if len(none) != 0 || one[0].name != "John Doe" {
+		t.Errorf("empty %v, single %v", none, one)
+	}
+}
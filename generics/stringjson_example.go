@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// --- Methods and the standard interfaces, on generic types ---
+
+// A generic type's methods are written once, for every T: LList[int] and LList[string] both get String and
+// MarshalJSON, so both are fmt.Stringers and json.Marshalers. Each instantiation is its own type, checked on
+// its own - these compile for any T the constraint allows
+var (
+	_ fmt.Stringer   = (*LList[int])(nil)
+	_ json.Marshaler = (*LList[string])(nil)
+	_ fmt.Stringer   = PaginatedResDto[User]{}
+	_ json.Marshaler = &PaginatedResDto[User]{} // the pointer too: value receiver methods are in *T's method set
+)
+
+// What a method can't do is narrow T: func (l *LList[T]) MarshalJSON() where T is marshalable isn't Go.
+// A function can have tighter constraints of its own, though - it only accepts the lists it can handle
+func JoinStrings[T interface {
+	comparable
+	fmt.Stringer
+}](l *LList[T], sep string) string {
+	var parts []string
+	for n := l; n != nil; n = n.next {
+		parts = append(parts, n.val.String()) // a method call on T: allowed, because the constraint has it
+	}
+	return strings.Join(parts, sep)
+}
+
+func genericMethodsExample() {
+	// (the checks, over more element types, are in stringjson_test.go)
+	marshal := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		return string(b)
+	}
+
+	// String: %v on each value, so every element type prints - its own String method included
+	fmt.Println("LList[int]:                 ", LListFrom([]int{1, 2, 3}))
+	fmt.Println("LList[string]:              ", LListFrom([]string{"go", "fun"}))
+	fmt.Println("LList[Celsius] (a Stringer):", LListFrom([]Celsius{21.5, -3}))
+	fmt.Println("LList[User]:                ", LListFrom([]User{{"1d02455e", "John Doe"}}))
+	fmt.Println("JoinStrings on LList[Celsius]:", JoinStrings(LListFrom([]Celsius{21.5, -3}), ", "))
+	// JoinStrings(LListFrom([]int{1}), ", ") doesn't compile: int does not satisfy fmt.Stringer (missing method String)
+
+	// MarshalJSON: an array, each element as json.Marshal would encode it
+	fmt.Println("LList[int] as JSON:         ", marshal(LListFrom([]int{1, 2, 3})))
+	fmt.Println("LList[string] as JSON:      ", marshal(LListFrom([]string{"go", "fun"})))
+	fmt.Println("LList[User] as JSON:        ", marshal(LListFrom([]User{{"1d02455e", "John Doe"}})))
+	fmt.Println("the empty list, nil:        ", marshal(LListFrom([]int{7}).Remove(7)))
+	fmt.Println("nil *LList inside a struct: ", marshal(struct {
+		Tags *LList[string] `json:"tags"`
+	}{}))
+	// A nil pointer is null before MarshalJSON gets a say. ToSlice would have made it [] -
+	// a field that must be [] has to be a non-nil value, or a slice
+
+	// The run time error for a T that JSON can't encode: chan int is comparable, so LList[chan int] is fine by the
+	// compiler. json's error names the method it came through
+	fmt.Println("LList[chan int] as JSON:    ", marshal(LListFrom([]chan int{make(chan int)})))
+
+	// PaginatedResDto: String for logs, MarshalJSON keeping data an array
+	page, _ := Paginate([]Celsius{21.5, 19, -3}, 1, 2)
+	fmt.Println("PaginatedResDto[Celsius]:   ", page)
+	fmt.Println("  as JSON:", marshal(page))
+	fmt.Println("zero PaginatedResDto[int]:", marshal(PaginatedResDto[int]{}))
+
+	// Nested: a page of lists - PaginatedResDto's MarshalJSON encodes Data, which calls LList's for each element
+	lists, _ := Paginate([]*LList[int]{LListFrom([]int{1, 2}), nil, LListFrom([]int{3})}, 1, 10)
+	fmt.Println("PaginatedResDto[*LList[int]]:", lists)
+	fmt.Println("  as JSON:", marshal(lists))
+	// (the nil list prints as [] through String - a method on a nil pointer runs - but encodes as null)
+}
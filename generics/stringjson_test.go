@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// One String and one MarshalJSON, checked on each instantiation: ints, strings, a Stringer, a struct
+
+func TestLListStringElementTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		got  fmt.Stringer
+		want string
+	}{
+		{"int", LListFrom([]int{1, 2, 3}), "[1 -> 2 -> 3]"},
+		{"float64", LListFrom([]float64{0.5, -2}), "[0.5 -> -2]"},
+		{"string", LListFrom([]string{"go", "fun"}), "[go -> fun]"},
+		{"Celsius, a Stringer", LListFrom([]Celsius{21.5, -3}), "[21.5°C -> -3.0°C]"},
+		{"User", LListFrom([]User{{"1d02455e", "John Doe"}}), "[{1d02455e John Doe}]"},
+		{"bool", LListFrom([]bool{true}), "[true]"},
+		{"nil", (*LList[Celsius])(nil), "[]"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s: String = %q, want %q", tt.name, got, tt.want)
+		}
+		if got := fmt.Sprint(tt.got); got != tt.want {
+			t.Errorf("%s: fmt.Sprint = %q, want %q - fmt should use String", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLListMarshalJSONElementTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"int", LListFrom([]int{1, 2, 3}), "[1,2,3]"},
+		{"string", LListFrom([]string{"go", "fun"}), `["go","fun"]`},
+		{"string to escape, HTML-safe", LListFrom([]string{`"<quoted>"`}), `["\"\u003cquoted\u003e\""]`},
+		{"Celsius, a float", LListFrom([]Celsius{21.5, -3}), "[21.5,-3]"},
+		{"User", LListFrom([]User{{"1d02455e", "John Doe"}}), `[{"UserId":"1d02455e","Name":"John Doe"}]`},
+		{"the list emptied: nil", LListFrom([]int{7}).Remove(7), "null"},
+		{"nil inside a struct", struct {
+			Tags *LList[string] `json:"tags"`
+		}{}, `{"tags":null}`},
+		{"in a struct", struct {
+			Tags *LList[string] `json:"tags"`
+		}{LListFrom([]string{"a"})}, `{"tags":["a"]}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.v)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("%s: %s, %v, want %s", tt.name, b, err, tt.want)
+		}
+	}
+
+	// Comparable, so the compiler allows it - but JSON can't encode a chan: a run time error, through MarshalJSON
+	_, err := json.Marshal(LListFrom([]chan int{make(chan int)}))
+	if err == nil || !strings.Contains(err.Error(), "MarshalJSON for type *main.LList[chan int]") {
+		t.Errorf("LList[chan int]: %v, want an error naming MarshalJSON", err)
+	}
+}
+
+// What MarshalJSON encodes decodes back into a slice of the same values
+func TestLListJSONRoundTrip(t *testing.T) {
+	users := []User{{"1d02455e", "John Doe"}, {"96aeb270", "Jack Eod"}}
+	b, _ := json.Marshal(LListFrom(users))
+	var back []User
+	if err := json.Unmarshal(b, &back); err != nil || !slices.Equal(back, users) {
+		t.Errorf("round trip: %v, %v", back, err)
+	}
+}
+
+func TestJoinStrings(t *testing.T) {
+	if got := JoinStrings(LListFrom([]Celsius{21.5, -3}), ", "); got != "21.5°C, -3.0°C" {
+		t.Errorf("JoinStrings = %q", got)
+	}
+	if got := JoinStrings((*LList[Celsius])(nil), ", "); got != "" {
+		t.Errorf("JoinStrings of nil = %q, want empty", got)
+	}
+	if got := JoinStrings(LListFrom([]Celsius{0}), "|"); got != "0.0°C" {
+		t.Errorf("JoinStrings of one = %q", got)
+	}
+}
+
+func TestPaginatedString(t *testing.T) {
+	celsius, _ := Paginate([]Celsius{21.5, 19, -3}, 1, 2)
+	ints, _ := Paginate([]int{1, 2, 3, 4, 5}, 3, 2)
+	users, _ := Paginate([]User{{"1d02455e", "John Doe"}}, 1, 10)
+	tests := []struct {
+		got  fmt.Stringer
+		want string
+	}{
+		{celsius, "page 1 of 2 (3 items): [21.5°C 19.0°C]"},
+		{ints, "page 3 of 3 (5 items): [5]"},
+		{users, "page 1 of 1 (1 items): [{1d02455e John Doe}]"},
+		{PaginatedResDto[string]{}, "page 0 of 0 (0 items): []"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("String = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPaginatedJSONElementTypes(t *testing.T) {
+	celsius, _ := Paginate([]Celsius{21.5, 19, -3}, 1, 2)
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"Celsius", celsius, `{"totalItems":3,"totalPages":2,"currPage":1,"itemsPerPage":2,"nextPage":2,"prevPage":null,"data":[21.5,19]}`},
+		{"zero int", PaginatedResDto[int]{}, `{"totalItems":0,"totalPages":0,"currPage":0,"itemsPerPage":0,"nextPage":null,"prevPage":null,"data":[]}`},
+		{"zero string, a pointer", &PaginatedResDto[string]{}, `{"totalItems":0,"totalPages":0,"currPage":0,"itemsPerPage":0,"nextPage":null,"prevPage":null,"data":[]}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.v)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("%s: %s, %v\nwant %s", tt.name, b, err, tt.want)
+		}
+	}
+	// A nil *PaginatedResDto is null, like any nil pointer
+	if b, _ := json.Marshal((*PaginatedResDto[int])(nil)); string(b) != "null" {
+		t.Errorf("nil pointer: %s, want null", b)
+	}
+}
+
+// A page of lists: PaginatedResDto's String and MarshalJSON use LList's for each element
+func TestPageOfLists(t *testing.T) {
+	lists, _ := Paginate([]*LList[int]{LListFrom([]int{1, 2}), nil, LListFrom([]int{3})}, 1, 10)
+	if got, want := lists.String(), "page 1 of 1 (3 items): [[1 -> 2] [] [3]]"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+	b, err := json.Marshal(lists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"data":[[1,2],null,[3]]`) {
+		t.Errorf("JSON %s, want data [[1,2],null,[3]]", b)
+	}
+}
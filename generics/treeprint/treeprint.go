@@ -0,0 +1,94 @@
+package treeprint
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// A generic ASCII tree printer (TreePrint) for hierarchical example output.
+//
+// It doesn't need a tree type - only a way to label a node and a way to get its children,
+// so the same code prints a search tree, a directory walk, or a dependency graph:
+//
+//	treeprint.Print(root, func(n *Node) string { return n.Name }, func(n *Node) []*Node { return n.Kids })
+//
+//	build
+//	├── compile
+//	│   └── generate
+//	└── test
+//	    └── compile
+//	        └── generate
+
+// Sprint returns the tree below root, one node per line.
+func Sprint[T any](root T, label func(T) string, children func(T) []T) string {
+	var sb strings.Builder
+	p := printer[T]{w: &sb, label: label, children: children}
+	p.node(root, "", "")
+	return sb.String()
+}
+
+// SprintGraph is Sprint for graphs, where a node can be reachable more than one way
+// (or be part of a cycle). A node is only expanded the first time it is printed;
+// later appearances are marked with (*) instead of repeating (or endlessly looping over) its children.
+func SprintGraph[T comparable](root T, label func(T) string, children func(T) []T) string {
+	var sb strings.Builder
+	seen := make(map[T]bool)
+	p := printer[T]{w: &sb, label: label, children: children, alreadySeen: func(n T) bool {
+		if seen[n] {
+			return true
+		}
+		seen[n] = true
+		return false
+	}}
+	p.node(root, "", "")
+	return sb.String()
+}
+
+// Fprint writes the tree below root to w.
+func Fprint[T any](w io.Writer, root T, label func(T) string, children func(T) []T) error {
+	_, err := io.WriteString(w, Sprint(root, label, children))
+	return err
+}
+
+// Print writes the tree below root to standard output.
+func Print[T any](root T, label func(T) string, children func(T) []T) {
+	Fprint(os.Stdout, root, label, children)
+}
+
+const (
+	branch     = "├── "
+	lastBranch = "└── "
+	pipe       = "│   "
+	space      = "    "
+)
+
+type printer[T any] struct {
+	w        *strings.Builder
+	label    func(T) string
+	children func(T) []T
+
+	// Only set for graphs. A map[T]bool would need T comparable, so SprintGraph wraps its map
+	// in a func and the printer itself still works with any T
+	alreadySeen func(T) bool
+}
+
+// node writes n's line with linePrefix in front of the label,
+// then its children with childPrefix (the vertical lines of the levels above)
+func (p *printer[T]) node(n T, linePrefix, childPrefix string) {
+	p.w.WriteString(linePrefix + p.label(n))
+	if p.alreadySeen != nil && p.alreadySeen(n) {
+		p.w.WriteString(" (*)\n")
+		return
+	}
+	p.w.WriteString("\n")
+
+	kids := p.children(n)
+	for i, kid := range kids {
+		if i == len(kids)-1 {
+			p.node(kid, childPrefix+lastBranch, childPrefix+space)
+		} else {
+			p.node(kid, childPrefix+branch, childPrefix+pipe)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package treeprint
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path"
+	"testing"
+	"testing/fstest"
+)
+
+// Golden outputs - the exact text each printer must produce
+
+const goldenBST = `8
+├── L: 3
+│   ├── L: 1
+│   └── R: 6
+│       ├── L: 4
+│       └── R: 7
+└── R: 10
+    └── R: 14
+        └── L: 13
+`
+
+const goldenDir = `./
+├── README.md
+├── cmd/
+│   └── tour/
+│       └── main.go
+└── internal/
+    ├── lessons.go
+    └── run/
+        └── run.go
+`
+
+const goldenDeps = `release
+├── test
+│   ├── build
+│   │   ├── generate
+│   │   └── fetch-deps
+│   └── fixtures
+│       └── generate (*)
+├── build (*)
+└── docs
+    └── release (*)
+`
+
+type bstNode[T cmp.Ordered] struct {
+	val         T
+	left, right *bstNode[T]
+}
+
+func (n *bstNode[T]) insert(v T) *bstNode[T] {
+	if n == nil {
+		return &bstNode[T]{val: v}
+	}
+	switch {
+	case v < n.val:
+		n.left = n.left.insert(v)
+	case v > n.val:
+		n.right = n.right.insert(v)
+	}
+	return n
+}
+
+// bstView is the node type printed: the node, and which side of its parent it is on
+type bstView[T cmp.Ordered] struct {
+	side string
+	node *bstNode[T]
+}
+
+func TestSprintBST(t *testing.T) {
+	var root *bstNode[int]
+	for _, v := range []int{8, 3, 10, 1, 6, 14, 4, 7, 13} {
+		root = root.insert(v)
+	}
+	got := Sprint(bstView[int]{"", root},
+		func(v bstView[int]) string { return fmt.Sprintf("%s%v", v.side, v.node.val) },
+		func(v bstView[int]) []bstView[int] {
+			var kids []bstView[int]
+			if v.node.left != nil {
+				kids = append(kids, bstView[int]{"L: ", v.node.left})
+			}
+			if v.node.right != nil {
+				kids = append(kids, bstView[int]{"R: ", v.node.right})
+			}
+			return kids
+		})
+	if got != goldenBST {
+		t.Errorf("got:\n%s\nwant:\n%s", got, goldenBST)
+	}
+}
+
+func TestSprintDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":           {Data: []byte("# tour")},
+		"cmd/tour/main.go":    {Data: []byte("package main")},
+		"internal/lessons.go": {Data: []byte("package internal")},
+		"internal/run/run.go": {Data: []byte("package run")},
+	}
+	got := Sprint(".",
+		func(p string) string {
+			if info, err := fs.Stat(fsys, p); err == nil && info.IsDir() {
+				return path.Base(p) + "/"
+			}
+			return path.Base(p)
+		},
+		func(p string) []string {
+			entries, _ := fs.ReadDir(fsys, p)
+			kids := make([]string, len(entries))
+			for i, e := range entries {
+				kids[i] = path.Join(p, e.Name())
+			}
+			return kids
+		})
+	if got != goldenDir {
+		t.Errorf("got:\n%s\nwant:\n%s", got, goldenDir)
+	}
+}
+
+// Shared dependencies and the cycle through docs are printed once, then marked
+func TestSprintGraph(t *testing.T) {
+	deps := map[string][]string{
+		"release":  {"test", "build", "docs"},
+		"test":     {"build", "fixtures"},
+		"build":    {"generate", "fetch-deps"},
+		"fixtures": {"generate"},
+		"docs":     {"release"},
+	}
+	label := func(task string) string { return task }
+	children := func(task string) []string { return deps[task] }
+	if got := SprintGraph("release", label, children); got != goldenDeps {
+		t.Errorf("got:\n%s\nwant:\n%s", got, goldenDeps)
+	}
+}
+
+func TestSprintSingleNode(t *testing.T) {
+	got := Sprint("lonely", func(s string) string { return s }, func(string) []string { return nil })
+	if got != "lonely\n" {
+		t.Errorf("got %q, want %q", got, "lonely\n")
+	}
+}
+
+func TestFprint(t *testing.T) {
+	var buf bytes.Buffer
+	children := func(n int) []int {
+		if n < 3 {
+			return []int{n + 1}
+		}
+		return nil
+	}
+	if err := Fprint(&buf, 1, func(n int) string { return fmt.Sprint(n) }, children); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n└── 2\n    └── 3\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
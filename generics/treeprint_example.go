@@ -0,0 +1,111 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"generics/treeprint"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// --- Printing trees with a generic helper (see treeprint/treeprint.go) ---
+
+// treeprint takes the node type as a type parameter, plus two functions: one to label a node,
+// one to list its children. Any existing structure can be printed without implementing an interface,
+// and the node type can even be a small "view" struct made just for printing.
+
+// A generic binary search tree
+type bstNode[T cmp.Ordered] struct {
+	val         T
+	left, right *bstNode[T]
+}
+
+func (n *bstNode[T]) insert(v T) *bstNode[T] {
+	if n == nil {
+		return &bstNode[T]{val: v}
+	}
+	switch {
+	case v < n.val:
+		n.left = n.left.insert(v)
+	case v > n.val:
+		n.right = n.right.insert(v)
+	}
+	return n
+}
+
+// With only the values, a node with one child wouldn't show which side it is on,
+// so the printed node type carries that too
+type bstView[T cmp.Ordered] struct {
+	side string
+	node *bstNode[T]
+}
+
+func printBST[T cmp.Ordered](root *bstNode[T]) string {
+	return treeprint.Sprint(bstView[T]{"", root},
+		func(v bstView[T]) string { return fmt.Sprintf("%s%v", v.side, v.node.val) },
+		func(v bstView[T]) []bstView[T] {
+			var kids []bstView[T]
+			if v.node.left != nil {
+				kids = append(kids, bstView[T]{"L: ", v.node.left})
+			}
+			if v.node.right != nil {
+				kids = append(kids, bstView[T]{"R: ", v.node.right})
+			}
+			return kids
+		})
+}
+
+// A directory walk - the nodes are just path strings
+func printDir(fsys fs.FS, root string) string {
+	return treeprint.Sprint(root,
+		func(p string) string {
+			if info, err := fs.Stat(fsys, p); err == nil && info.IsDir() {
+				return path.Base(p) + "/"
+			}
+			return path.Base(p)
+		},
+		func(p string) []string {
+			entries, _ := fs.ReadDir(fsys, p) // sorted by name; a file has no entries
+			kids := make([]string, len(entries))
+			for i, e := range entries {
+				kids[i] = path.Join(p, e.Name())
+			}
+			return kids
+		})
+}
+
+// A dependency graph - shared dependencies (and cycles) are printed once, then marked with (*)
+func printDeps(deps map[string][]string, root string) string {
+	return treeprint.SprintGraph(root,
+		func(task string) string { return task },
+		func(task string) []string { return deps[task] })
+}
+
+func treePrintExample() {
+	var bst *bstNode[int]
+	for _, v := range []int{8, 3, 10, 1, 6, 14, 4, 7, 13} {
+		bst = bst.insert(v)
+	}
+
+	deps := map[string][]string{
+		"release":  {"test", "build", "docs"},
+		"test":     {"build", "fixtures"},
+		"build":    {"generate", "fetch-deps"},
+		"fixtures": {"generate"},
+		"docs":     {"release"}, // a cycle: printed once, not forever
+	}
+
+	// treeprint/treeprint_test.go checks the printers against golden outputs, the directory one on an fstest.MapFS
+	fmt.Printf("binary search tree:\n%s\n", printBST(bst))
+	fmt.Printf("directory:\n%s\n", printDir(os.DirFS("."), "treeprint")) // this module's treeprint directory
+	fmt.Printf("dependency graph:\n%s\n", printDeps(deps, "release"))
+
+	// A single node, and the same generic printBST with strings
+	fmt.Print(treeprint.Sprint("lonely", func(s string) string { return s }, func(string) []string { return nil }))
+	var words *bstNode[string]
+	for _, w := range []string{"mango", "apple", "zucchini", "kiwi"} {
+		words = words.insert(w)
+	}
+	fmt.Print(printBST(words)) // printBST[string]
+}
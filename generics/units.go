@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// --- Unit-safe types (typed IDs and quantities) ---
+
+// Go's type system is nominal: a defined type is a new, distinct type, even if its underlying type is the same.
+// Metres and Seconds are both float64 underneath, but a Seconds can't be used where Metres is expected
+// without an explicit conversion - so mixing up units (or IDs) is a compile error instead of a bug.
+// (See units_nocompile.go for the errors)
+
+type Metres float64
+type Seconds float64
+type MetresPerSecond float64
+
+// The units are part of the signature, so arguments can't be swapped by accident
+func Speed(d Metres, t Seconds) MetresPerSecond {
+	return MetresPerSecond(float64(d) / float64(t))
+}
+
+// Quantity is any type whose underlying type is float64 (the ~ allows defined types like Metres)
+type Quantity interface {
+	~float64
+}
+
+// Every argument must be the same Q - so Sum(Metres(1), Seconds(2)) doesn't compile,
+// and the result keeps the unit of the inputs
+func Sum[Q Quantity](qs ...Q) Q {
+	var total Q
+	for _, q := range qs {
+		total += q
+	}
+	return total
+}
+
+// Scaling by a plain number keeps the unit
+func Scale[Q Quantity](q Q, factor float64) Q {
+	return q * Q(factor)
+}
+
+// --- Typed IDs with a phantom type parameter ---
+
+// Instead of declaring UserID, OrderID, ProductID, ... separately, one generic type can do it.
+// T isn't used in the definition at all (a "phantom" type parameter) - it only makes
+// ID[User] and ID[Order] different types
+type ID[T any] int64
+
+type Order struct {
+	ID     ID[Order]
+	UserID ID[User] // the User from compare.go
+	Total  Metres   // (a delivery distance, to keep using the quantities above)
+}
+
+func (id ID[T]) String() string {
+	return fmt.Sprintf("%s#%d", reflect.TypeFor[T]().Name(), int64(id))
+}
+
+// ParseID checks the input once, and returns an ID of the type asked for: ParseID[Order]("42")
+func ParseID[T any](s string) (ID[T], error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s id %q", reflect.TypeFor[T]().Name(), s)
+	}
+	return ID[T](n), nil
+}
+
+// The parameter types document (and enforce) which id goes where -
+// with two int64 parameters, cancelOrder(userID, orderID) would compile and silently do the wrong thing
+func cancelOrder(user ID[User], order ID[Order], orders map[ID[Order]]Order) error {
+	o, ok := orders[order]
+	if !ok {
+		return fmt.Errorf("%v not found", order)
+	}
+	if o.UserID != user {
+		return fmt.Errorf("%v does not belong to %v", order, user)
+	}
+	delete(orders, order)
+	return nil
+}
+
+func unitSafetyExample() {
+	lap := Metres(400)
+	lapTime := Seconds(80)
+	fmt.Printf("speed: %.2f m/s\n", Speed(lap, lapTime))
+
+	laps := []Metres{400, 400, 400, 200}
+	total := Sum(laps...)
+	fmt.Printf("total distance: %v (%T)\n", total, total)
+	fmt.Printf("scaled: %v, sum of times: %v\n", Scale(lap, 2.5), Sum(Seconds(80), 85, 90)) // untyped constants adapt to Q
+
+	// Converting between units is possible, but must be written out - easy to spot in review
+	var km float64 = float64(total) / 1000
+	fmt.Println("in km:", km)
+
+	// Typed IDs
+	orders := map[ID[Order]]Order{
+		1: {ID: 1, UserID: 10, Total: 1200},
+		2: {ID: 2, UserID: 11, Total: 300},
+	}
+	alice, _ := ParseID[User]("10")
+	orderID, _ := ParseID[Order]("2")
+	fmt.Println("ids print their kind:", alice, orderID)
+
+	fmt.Println("cancel someone else's order:", cancelOrder(alice, orderID, orders))
+	fmt.Println("cancel own order:", cancelOrder(alice, 1, orders), "| orders left:", len(orders))
+
+	_, err := ParseID[Order]("-3")
+	fmt.Println("parse error:", err)
+
+	// Limits - this is nominal typing, not dimensional analysis
+	// - lap * lap compiles and is still Metres (not square metres)
+	// - an explicit conversion like Metres(lapTime) always compiles
+	// - untyped constants convert implicitly: lap + 5 is fine
+	area := lap * lap
+	fmt.Printf("lap * lap = %v, but the type is still %T\n", area, area)
+}
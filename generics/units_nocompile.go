@@ -0,0 +1,48 @@
+//go:build nocompile
+
+package main
+
+// This file is excluded from normal builds by the build constraint above.
+// Build it on purpose to see the unit mix-ups from units.go caught by the compiler:
+//
+//	go build -tags nocompile .
+//
+// Every statement in unitMixUps is an error.
+
+func unitMixUps() {
+	var d Metres = 100
+	var t Seconds = 10
+
+	// cannot use t (variable of float64 type Seconds) as Metres value in variable declaration
+	var distance Metres = t
+
+	// invalid operation: d + t (mismatched types Metres and Seconds)
+	_ = d + t
+
+	// cannot use t (variable of float64 type Seconds) as Metres value in argument to Speed
+	// cannot use d (variable of float64 type Metres) as Seconds value in argument to Speed
+	_ = Speed(t, d)
+
+	// in call to Sum, type Seconds of t does not match inferred type Metres for Q
+	_ = Sum(d, t)
+
+	// cannot use f (variable of type float64) as Metres value in argument to Sum
+	// (an untyped constant would be fine, a float64 variable is not)
+	var f float64 = 3.5
+	_ = Sum(d, f)
+
+	var user ID[User] = 10
+	var order ID[Order] = 2
+	orders := map[ID[Order]]Order{}
+
+	// cannot use user (variable of int64 type ID[User]) as ID[Order] value in map index
+	_ = orders[user]
+
+	// arguments swapped: cannot use order as ID[User] value, cannot use user as ID[Order] value
+	_ = cancelOrder(order, user, orders)
+
+	// invalid operation: user == order (mismatched types ID[User] and ID[Order])
+	_ = user == order
+
+	_ = distance
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpeed(t *testing.T) {
+	if got := Speed(400, 80); got != 5 {
+		t.Errorf("Speed(400m, 80s) = %v, want 5", got)
+	}
+	if got := Speed(0, 10); got != 0 {
+		t.Errorf("Speed(0m, 10s) = %v, want 0", got)
+	}
+}
+
+func TestSumScaleKeepUnit(t *testing.T) {
+	total := Sum[Metres](400, 400, 400, 200)
+	if total != 1400 {
+		t.Errorf("Sum = %v, want 1400", total)
+	}
+	if Sum[Seconds]() != 0 {
+		t.Error("Sum() isn't 0")
+	}
+	if got := Scale(Seconds(80), 2.5); got != 200 {
+		t.Errorf("Scale(80s, 2.5) = %v, want 200", got)
+	}
+	// the result has the type of the inputs, not float64
+	if typ := reflect.TypeOf(total); typ != reflect.TypeFor[Metres]() {
+		t.Errorf("Sum of Metres is a %v", typ)
+	}
+	if typ := reflect.TypeOf(Scale(Seconds(1), 2)); typ != reflect.TypeFor[Seconds]() {
+		t.Errorf("Scale of Seconds is a %v", typ)
+	}
+}
+
+// The same underlying type, but distinct types - what makes mixing them a compile error
+func TestDistinctTypes(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		a, b reflect.Type
+	}{
+		{"Metres and Seconds", reflect.TypeFor[Metres](), reflect.TypeFor[Seconds]()},
+		{"ID[User] and ID[Order]", reflect.TypeFor[ID[User]](), reflect.TypeFor[ID[Order]]()},
+	} {
+		if c.a == c.b {
+			t.Errorf("%s are the same type", c.name)
+		}
+		if c.a.Kind() != c.b.Kind() {
+			t.Errorf("%s: kinds %v and %v, want the same", c.name, c.a.Kind(), c.b.Kind())
+		}
+	}
+}
+
+func TestIDString(t *testing.T) {
+	if got := ID[User](10).String(); got != "User#10" {
+		t.Errorf("ID[User](10) = %q, want User#10", got)
+	}
+	if got := ID[Order](2).String(); got != "Order#2" {
+		t.Errorf("ID[Order](2) = %q, want Order#2", got)
+	}
+}
+
+func TestParseID(t *testing.T) {
+	id, err := ParseID[Order]("42")
+	if err != nil || id != 42 {
+		t.Errorf(`ParseID[Order]("42") = %v, %v; want Order#42`, id, err)
+	}
+	for _, s := range []string{"", "0", "-3", "abc", "4.2", "99999999999999999999"} {
+		if _, err := ParseID[Order](s); err == nil {
+			t.Errorf("ParseID[Order](%q): no error", s)
+		}
+	}
+	if _, err := ParseID[User]("x"); err == nil || err.Error() != `invalid User id "x"` {
+		t.Errorf("error = %v, want it to name the kind of id", err)
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	orders := map[ID[Order]]Order{
+		1: {ID: 1, UserID: 10, Total: 1200},
+		2: {ID: 2, UserID: 11, Total: 300},
+	}
+	if err := cancelOrder(10, 2, orders); err == nil || len(orders) != 2 {
+		t.Errorf("cancelling another user's order: err %v, %d orders left; want an error and 2", err, len(orders))
+	}
+	if err := cancelOrder(10, 3, orders); err == nil {
+		t.Error("cancelling a missing order: no error")
+	}
+	if err := cancelOrder(10, 1, orders); err != nil {
+		t.Errorf("cancelling own order: %v", err)
+	}
+	if _, ok := orders[1]; ok || len(orders) != 1 {
+		t.Errorf("after cancelling order 1: %v", orders)
+	}
+}
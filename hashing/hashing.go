@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"hash/maphash"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// === Hash functions ===
+
+// A (non-cryptographic) hash function maps a value to a fixed size number.
+// Used for picking a bucket / shard / bit for a value, where the goal is speed and an
+// even spread of outputs - NOT security (see crypto/sha256 for that).
+// The same input always gives the same output, but different inputs can collide.
+
+// --- FNV (hash/fnv) ---
+
+// FNV-1a is a simple, fast hash with a fixed algorithm and no seed:
+// the output is the same in every program on every machine, so it is safe to store or send
+// (ex. as a checksum in a file). The downside is that anyone can precompute collisions.
+// fnv.New64a returns a hash.Hash64, which is an io.Writer - write the bytes in, then call Sum64
+func fnv64a(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s)) // Write on a hash never returns an error
+	return h.Sum64()
+}
+
+// --- maphash (hash/maphash) ---
+
+// The hash function Go's own maps use. Every maphash.Seed is random,
+// so outputs differ between seeds (and so between program runs).
+// This prevents hash flooding attacks, but means the values must never be persisted.
+// - maphash.String / maphash.Bytes hash strings and byte slices
+// - maphash.Comparable (Go 1.24+) hashes any comparable value, like a map key
+
+// --- A Hasher[T] abstraction ---
+
+// The data structures below only need "a number for this value",
+// so they depend on this one method interface instead of a specific hash function
+type Hasher[T any] interface {
+	Hash(v T) uint64
+}
+
+// HasherFunc lets a plain function be used as a Hasher (same idea as http.HandlerFunc)
+type HasherFunc[T any] func(v T) uint64
+
+func (f HasherFunc[T]) Hash(v T) uint64 {
+	return f(v)
+}
+
+// MapHasher hashes any comparable value with maphash
+type MapHasher[T comparable] struct {
+	seed maphash.Seed
+}
+
+func NewMapHasher[T comparable]() MapHasher[T] {
+	return MapHasher[T]{seed: maphash.MakeSeed()}
+}
+
+func (h MapHasher[T]) Hash(v T) uint64 {
+	return maphash.Comparable(h.seed, v)
+}
+
+// FNVHasher is deterministic across runs, but only works on strings
+type FNVHasher struct{}
+
+func (FNVHasher) Hash(s string) uint64 {
+	return fnv64a(s)
+}
+
+// Compile time checks
+var _ Hasher[string] = MapHasher[string]{}
+var _ Hasher[string] = FNVHasher{}
+var _ Hasher[string] = HasherFunc[string](fnv64a)
+
+// --- Bloom filter built on a Hasher ---
+
+// A Bloom filter answers "have I seen this value?" using a fixed number of bits:
+// - "no" is always correct
+// - "maybe" can be wrong (a false positive), at a rate controlled by the size and number of hashes
+// Each value sets k bits. k independent hashes are simulated from one 64 bit hash
+// by splitting it in two halves h1, h2 and using h1 + i*h2 (the Kirsch-Mitzenmacher trick)
+type BloomFilter[T any] struct {
+	bits   []uint64
+	m      uint64 // number of bits
+	k      uint64 // number of bits set per value
+	hasher Hasher[T]
+}
+
+// NewBloomFilter sizes the filter for n values at roughly the given false positive rate.
+// n must be at least 1, and the rate between 0 and 1 (exclusive) - otherwise there are no bits to set
+func NewBloomFilter[T any](n int, falsePositiveRate float64, hasher Hasher[T]) *BloomFilter[T] {
+	if n < 1 {
+		panic("hashing.NewBloomFilter: n must be at least 1")
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) { // written this way round, NaN fails too
+		panic("hashing.NewBloomFilter: falsePositiveRate must be between 0 and 1")
+	}
+	m := math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/float64(n)*math.Ln2))
+	return &BloomFilter[T]{
+		bits:   make([]uint64, (uint64(m)+63)/64),
+		m:      uint64(m),
+		k:      uint64(k),
+		hasher: hasher,
+	}
+}
+
+func (b *BloomFilter[T]) positions(v T) func(yield func(uint64) bool) {
+	h := b.hasher.Hash(v)
+	h1, h2 := h&math.MaxUint32, h>>32
+	return func(yield func(uint64) bool) {
+		for i := range b.k {
+			if !yield((h1 + i*h2) % b.m) {
+				return
+			}
+		}
+	}
+}
+
+func (b *BloomFilter[T]) Add(v T) {
+	for pos := range b.positions(v) {
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+func (b *BloomFilter[T]) MayContain(v T) bool {
+	for pos := range b.positions(v) {
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Sharded map built on a Hasher ---
+
+// One mutex around a big map makes every goroutine wait for the same lock.
+// Splitting the map into shards, each with its own lock, lets goroutines using
+// different shards run in parallel. The hash picks the shard, so the same key always lands in the same one
+type ShardedMap[K comparable, V any] struct {
+	shards []shard[K, V]
+	hasher Hasher[K]
+}
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewShardedMap splits the map into n shards. n must be at least 1
+func NewShardedMap[K comparable, V any](n int, hasher Hasher[K]) *ShardedMap[K, V] {
+	if n < 1 {
+		panic("hashing.NewShardedMap: n must be at least 1")
+	}
+	shards := make([]shard[K, V], n)
+	for i := range shards {
+		shards[i].m = make(map[K]V)
+	}
+	return &ShardedMap[K, V]{shards: shards, hasher: hasher}
+}
+
+func (s *ShardedMap[K, V]) shardFor(key K) *shard[K, V] {
+	return &s.shards[s.hasher.Hash(key)%uint64(len(s.shards))]
+}
+
+func (s *ShardedMap[K, V]) Get(key K) (V, bool) {
+	sh := s.shardFor(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	v, ok := sh.m[key]
+	return v, ok
+}
+
+func (s *ShardedMap[K, V]) Set(key K, value V) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.m[key] = value
+}
+
+// ShardSizes reports how many keys each shard holds (to check the spread)
+func (s *ShardedMap[K, V]) ShardSizes() []int {
+	sizes := make([]int, len(s.shards))
+	for i := range s.shards {
+		s.shards[i].mu.RLock()
+		sizes[i] = len(s.shards[i].m)
+		s.shards[i].mu.RUnlock()
+	}
+	return sizes
+}
+
+// --- Examples ---
+
+// fixtureKeys is the dataset the examples hash: the user ids from the basics notes,
+// followed by n generated keys with a lot of shared prefix (a realistic worst-ish case for a weak hash)
+func fixtureKeys(n int) []string {
+	keys := []string{"1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"}
+	for i := range n {
+		keys = append(keys, fmt.Sprintf("user-%06d", i))
+	}
+	return keys
+}
+
+func hashBasicsExample() {
+	fmt.Printf("fnv64a(%q) = %#016x (same every run)\n", "gopher", fnv64a("gopher"))
+
+	seed := maphash.MakeSeed()
+	fmt.Printf("maphash.String(%q) = %#016x (changes every run)\n", "gopher", maphash.String(seed, "gopher"))
+	fmt.Println("same seed, same hash:", maphash.String(seed, "gopher") == maphash.String(seed, "gopher"))
+
+	// maphash.Comparable works on any comparable value, ex. a struct key
+	type point struct{ X, Y int }
+	h := NewMapHasher[point]()
+	fmt.Println("struct hash equal for equal values:", h.Hash(point{1, 2}) == h.Hash(point{1, 2}))
+}
+
+// distribution puts every key in one of n buckets and reports how uneven the counts are.
+// chi-squared close to (buckets - 1) means the spread looks uniform
+func distribution(keys []string, buckets int, hasher Hasher[string]) (minCount, maxCount int, chiSquared float64) {
+	counts := make([]int, buckets)
+	for _, k := range keys {
+		counts[hasher.Hash(k)%uint64(buckets)]++
+	}
+
+	expected := float64(len(keys)) / float64(buckets)
+	minCount, maxCount = counts[0], counts[0]
+	for _, c := range counts {
+		minCount, maxCount = min(minCount, c), max(maxCount, c)
+		chiSquared += (float64(c) - expected) * (float64(c) - expected) / expected
+	}
+	return minCount, maxCount, chiSquared
+}
+
+func distributionExample() {
+	keys := fixtureKeys(100_000)
+	const buckets = 64
+
+	// A deliberately bad hash for comparison - sums the bytes, so anagrams collide
+	// and similar keys cluster into a few buckets
+	byteSum := HasherFunc[string](func(s string) uint64 {
+		var sum uint64
+		for i := range len(s) {
+			sum += uint64(s[i])
+		}
+		return sum
+	})
+
+	fmt.Printf("%d keys into %d buckets (ideal: %d per bucket, chi-squared ~%d)\n", len(keys), buckets, len(keys)/buckets, buckets-1)
+	for _, c := range []struct {
+		name   string
+		hasher Hasher[string]
+	}{
+		{"fnv-1a", FNVHasher{}},
+		{"maphash", NewMapHasher[string]()},
+		{"byte sum", byteSum},
+	} {
+		lo, hi, chi := distribution(keys, buckets, c.hasher)
+		fmt.Printf("  %-8s min %5d  max %5d  chi-squared %10.1f\n", c.name, lo, hi, chi)
+	}
+}
+
+func bloomFilterExample() {
+	keys := fixtureKeys(10_000)
+	filter := NewBloomFilter[string](len(keys), 0.01, NewMapHasher[string]())
+	for _, k := range keys {
+		filter.Add(k)
+	}
+
+	// No false negatives - every added key is reported
+	missing := 0
+	for _, k := range keys {
+		if !filter.MayContain(k) {
+			missing++
+		}
+	}
+
+	// False positives - keys never added, but reported as "maybe"
+	falsePositives := 0
+	const probes = 10_000
+	for i := range probes {
+		if filter.MayContain(fmt.Sprintf("other-%06d", i)) {
+			falsePositives++
+		}
+	}
+
+	fmt.Printf("bloom filter: %d bits (%d KiB), %d hashes per key\n", filter.m, len(filter.bits)*8/1024, filter.k)
+	fmt.Printf("false negatives: %d, false positive rate: %.2f%% (target 1%%)\n", missing, 100*float64(falsePositives)/probes)
+
+	// The filter only depends on Hasher, so the FNV version works the same way
+	fnvFilter := NewBloomFilter[string](len(keys), 0.01, FNVHasher{})
+	fnvFilter.Add("gopher")
+	fmt.Println("fnv filter contains gopher:", fnvFilter.MayContain("gopher"), "| contains rust:", fnvFilter.MayContain("rust"))
+}
+
+func shardedMapExample() {
+	m := NewShardedMap[string, int](8, NewMapHasher[string]())
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				m.Set(fmt.Sprintf("user-%d-%d", w, i), i)
+			}
+		})
+	}
+	wg.Wait()
+
+	v, ok := m.Get("user-3-42")
+	fmt.Println("user-3-42:", v, ok)
+
+	sizes := m.ShardSizes()
+	fmt.Println("keys per shard:", strings.Trim(fmt.Sprint(sizes), "[]"))
+}
+
+// shardedMapBenchmarkExample has every goroutine writing at once, with 1 shard (one lock for the whole map -
+// the same as map + Mutex) up to 64. More shards, fewer goroutines waiting on the same lock.
+// The difference shows with several cores - "tour contention shardedMapBenchmarkExample" shows the waiting itself.
+// A rough timing, for the profile to have something to record: go test -bench ShardedMap measures it properly
+// (BenchmarkShardedMapSet in hashing_test.go)
+func shardedMapBenchmarkExample() {
+	keys := fixtureKeys(10_000)
+	const goroutines, writes = 16, 50_000
+	fmt.Println("GOMAXPROCS:", runtime.GOMAXPROCS(0))
+	fmt.Printf("%-8s %8s\n", "shards", "ns/op")
+	for _, n := range []int{1, 8, 64} {
+		m := NewShardedMap[string, int](n, NewMapHasher[string]())
+		start := time.Now()
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Go(func() {
+				i := g * 7919 // each goroutine starts somewhere else in the keys
+				for range writes {
+					m.Set(keys[i%len(keys)], i)
+					i++
+				}
+			})
+		}
+		wg.Wait()
+		fmt.Printf("%-8d %8d\n", n, time.Since(start).Nanoseconds()/(goroutines*writes))
+	}
+}
+
+func main() {
+	hashBasicsExample()
+	// distributionExample()
+	// bloomFilterExample()
+	// shardedMapExample()
+	// shardedMapBenchmarkExample()
+	// topKExample()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// wantPanic fails t unless f panics
+func wantPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s didn't panic", name)
+		}
+	}()
+	f()
+}
+
+func TestBloomFilter(t *testing.T) {
+	keys := fixtureKeys(10_000)
+	for _, c := range []struct {
+		name   string
+		hasher Hasher[string]
+	}{{"maphash", NewMapHasher[string]()}, {"fnv-1a", FNVHasher{}}} {
+		filter := NewBloomFilter(len(keys), 0.01, c.hasher)
+		for _, k := range keys {
+			filter.Add(k)
+		}
+		for _, k := range keys {
+			if !filter.MayContain(k) {
+				t.Fatalf("%s: %q was added, but MayContain is false", c.name, k)
+			}
+		}
+		falsePositives := 0
+		for i := range 10_000 {
+			if filter.MayContain(fmt.Sprintf("other-%06d", i)) {
+				falsePositives++
+			}
+		}
+		if rate := float64(falsePositives) / 10_000; rate > 0.02 {
+			t.Errorf("%s: false positive rate %.4f, sized for 0.01", c.name, rate)
+		}
+	}
+}
+
+func TestBloomFilterOneValue(t *testing.T) {
+	filter := NewBloomFilter(1, 0.5, Hasher[string](FNVHasher{}))
+	filter.Add("gopher")
+	if !filter.MayContain("gopher") {
+		t.Error("the one value added isn't there")
+	}
+}
+
+func TestNewBloomFilterRejects(t *testing.T) {
+	h := Hasher[string](FNVHasher{})
+	for _, c := range []struct {
+		n    int
+		rate float64
+	}{{0, 0.01}, {-1, 0.01}, {100, 0}, {100, 1}, {100, 1.5}, {100, -0.1}} {
+		wantPanic(t, fmt.Sprintf("NewBloomFilter(%d, %v)", c.n, c.rate), func() { NewBloomFilter(c.n, c.rate, h) })
+	}
+}
+
+func TestShardedMap(t *testing.T) {
+	m := NewShardedMap[string, int](8, NewMapHasher[string]())
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Go(func() {
+			for i := range 100 {
+				m.Set(fmt.Sprintf("user-%d-%d", g, i), i)
+			}
+		})
+	}
+	wg.Wait()
+	if v, ok := m.Get("user-3-42"); !ok || v != 42 {
+		t.Errorf("Get(user-3-42) = %d, %t, want 42, true", v, ok)
+	}
+	if _, ok := m.Get("user-4-0"); ok {
+		t.Error("Get of a key never set is found")
+	}
+	total := 0
+	for i, n := range m.ShardSizes() {
+		if n == 0 {
+			t.Errorf("shard %d is empty with 400 keys in 8 shards", i)
+		}
+		total += n
+	}
+	if total != 400 {
+		t.Errorf("%d keys in the shards, want 400", total)
+	}
+}
+
+func TestNewShardedMapRejects(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		wantPanic(t, fmt.Sprintf("NewShardedMap(%d)", n), func() { NewShardedMap[string, int](n, NewMapHasher[string]()) })
+	}
+}
+
+// BenchmarkShardedMapSet has every goroutine writing at once, with 1 shard (one lock for the whole map) up
+// to 64. The difference shows with several cores: go test -bench ShardedMap -cpu 1,4
+func BenchmarkShardedMapSet(b *testing.B) {
+	keys := fixtureKeys(10_000)
+	for _, n := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("shards=%d", n), func(b *testing.B) {
+			m := NewShardedMap[string, int](n, NewMapHasher[string]())
+			var next atomic.Int64
+			b.SetParallelism(4)
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(next.Add(1)) * 7919 // each goroutine starts somewhere else in the keys
+				for pb.Next() {
+					m.Set(keys[i%len(keys)], i)
+					i++
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	for _, c := range []struct {
+		name   string
+		hasher Hasher[string]
+	}{{"fnv-1a", FNVHasher{}}, {"maphash", NewMapHasher[string]()}} {
+		b.Run(c.name, func(b *testing.B) {
+			for b.Loop() {
+				c.hasher.Hash("1d02455e-f24c-4c26-90d2-f1073c686314")
+			}
+		})
+	}
+}
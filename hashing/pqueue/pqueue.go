@@ -0,0 +1,100 @@
+package pqueue
+
+import "container/heap"
+
+// A generic priority queue on top of container/heap.
+//
+// container/heap works on any type implementing heap.Interface (Len, Less, Swap, Push, Pop) with
+// `any` values - so every user writes the same five methods, and type asserts what comes out.
+// Queue does that once, for any element type, with the order given as a less function:
+//
+//	q := pqueue.New(func(a, b int) bool { return a < b }) // min-heap
+//	q := pqueue.New(func(a, b int) bool { return a > b }) // max-heap
+//
+// Push returns an *Item handle. Changing item.Value and calling Fix moves it to its new place in O(log n),
+// without searching for it - which is what a "decrease key" / "update priority" needs.
+//
+// generics/collections has a PriorityQueue too, without the handles. The two can't share code - they're
+// in different modules - so each module keeps its own.
+
+// Item is one element of a Queue
+type Item[T any] struct {
+	Value T
+	index int // position in the heap slice, kept up to date by Swap; -1 once removed
+}
+
+type Queue[T any] struct {
+	h heapSlice[T]
+}
+
+// New returns an empty queue where less(a, b) means a comes out before b
+func New[T any](less func(a, b T) bool) *Queue[T] {
+	return &Queue[T]{h: heapSlice[T]{less: less}}
+}
+
+func (q *Queue[T]) Len() int { return len(q.h.items) }
+
+// Push adds v in O(log n)
+func (q *Queue[T]) Push(v T) *Item[T] {
+	it := &Item[T]{Value: v}
+	heap.Push(&q.h, it)
+	return it
+}
+
+// Peek returns the first item without removing it, or nil if the queue is empty
+func (q *Queue[T]) Peek() *Item[T] {
+	if len(q.h.items) == 0 {
+		return nil
+	}
+	return q.h.items[0]
+}
+
+// Pop removes and returns the first value. It panics on an empty queue, like indexing an empty slice
+func (q *Queue[T]) Pop() T {
+	return heap.Pop(&q.h).(*Item[T]).Value
+}
+
+// Fix restores the order after it.Value changed
+func (q *Queue[T]) Fix(it *Item[T]) {
+	heap.Fix(&q.h, it.index)
+}
+
+// Remove takes it out of the queue, wherever it is
+func (q *Queue[T]) Remove(it *Item[T]) T {
+	return heap.Remove(&q.h, it.index).(*Item[T]).Value
+}
+
+// All returns the items in heap order (NOT sorted - only the first one is known to be the smallest)
+func (q *Queue[T]) All() []*Item[T] {
+	return q.h.items
+}
+
+// heapSlice implements heap.Interface. Its methods are only called by container/heap
+type heapSlice[T any] struct {
+	items []*Item[T]
+	less  func(a, b T) bool
+}
+
+func (h heapSlice[T]) Len() int           { return len(h.items) }
+func (h heapSlice[T]) Less(i, j int) bool { return h.less(h.items[i].Value, h.items[j].Value) }
+
+func (h heapSlice[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *heapSlice[T]) Push(x any) {
+	it := x.(*Item[T])
+	it.index = len(h.items)
+	h.items = append(h.items, it)
+}
+
+func (h *heapSlice[T]) Pop() any {
+	old := h.items
+	it := old[len(old)-1]
+	old[len(old)-1] = nil // don't keep a reference in the unused part of the array
+	h.items = old[:len(old)-1]
+	it.index = -1
+	return it
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"hashing/pqueue"
+	"io"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// === Streaming Top-K (heavy hitters) ===
+
+// "Which 10 pages get the most hits?" over a log too big to keep in memory - and with too many distinct
+// pages to keep a counter for every one of them. The exact answer needs a map of every key seen.
+// These two keep a fixed amount of memory no matter how long the stream is, and give an approximate answer
+// that is very good for skewed data (a few keys are most of the traffic, which is what logs usually look like):
+//
+// 1. Space-Saving: keep m counters in a min-heap. A new key, when all m are taken, replaces the SMALLEST
+//    counter and inherits its count (+1). The count can only be too high, by at most what was inherited (err),
+//    so count-err <= true count <= count. Any key with more than N/m hits is guaranteed to be kept
+// 2. Count-min sketch + a heap of k: the sketch is a depth x width grid of counters, each row indexed by a
+//    different hash. Adding a key increments one counter per row, and the estimate is the smallest of them -
+//    collisions only ever add, so it is never too low, and with width = e/eps it is at most eps*N too high
+//    (with probability 1 - e^-depth). A min-heap of the k keys with the highest estimates so far is the answer
+
+// Counted is a key with its (estimated) count
+type Counted[K any] struct {
+	Key   K
+	Count int
+	Err   int // how much of Count may be overcounted (Space-Saving only)
+}
+
+// byCountDesc sorts the highest counts first, ties by key (so results are the same every run)
+func byCountDesc[K cmp.Ordered](a, b Counted[K]) int {
+	if c := cmp.Compare(b.Count, a.Count); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Key, b.Key)
+}
+
+func minByCount[K any](a, b Counted[K]) bool { return a.Count < b.Count }
+
+// --- 1. Space-Saving ---
+
+type SpaceSaving[K cmp.Ordered] struct {
+	capacity int
+	heap     *pqueue.Queue[Counted[K]] // smallest count first
+	items    map[K]*pqueue.Item[Counted[K]]
+}
+
+// NewSpaceSaving keeps capacity counters. capacity must be at least 1 - Add evicts a counter to make room
+func NewSpaceSaving[K cmp.Ordered](capacity int) *SpaceSaving[K] {
+	if capacity < 1 {
+		panic("hashing.NewSpaceSaving: capacity must be at least 1")
+	}
+	return &SpaceSaving[K]{
+		capacity: capacity,
+		heap:     pqueue.New(minByCount[K]),
+		items:    make(map[K]*pqueue.Item[Counted[K]], capacity),
+	}
+}
+
+func (s *SpaceSaving[K]) Add(key K) {
+	if it, ok := s.items[key]; ok {
+		it.Value.Count++
+		s.heap.Fix(it)
+		return
+	}
+	if s.heap.Len() < s.capacity {
+		s.items[key] = s.heap.Push(Counted[K]{Key: key, Count: 1})
+		return
+	}
+	// evict the smallest counter, and reuse it for the new key
+	it := s.heap.Peek()
+	delete(s.items, it.Value.Key)
+	it.Value = Counted[K]{Key: key, Count: it.Value.Count + 1, Err: it.Value.Count}
+	s.heap.Fix(it)
+	s.items[key] = it
+}
+
+// Top returns the k highest counters
+func (s *SpaceSaving[K]) Top(k int) []Counted[K] {
+	return topOfHeap(s.heap, k)
+}
+
+func topOfHeap[K cmp.Ordered](q *pqueue.Queue[Counted[K]], k int) []Counted[K] {
+	all := make([]Counted[K], 0, q.Len())
+	for _, it := range q.All() {
+		all = append(all, it.Value)
+	}
+	slices.SortFunc(all, byCountDesc)
+	return all[:min(k, len(all))]
+}
+
+// --- 2. Count-min sketch ---
+
+type CountMinSketch[T any] struct {
+	rows   [][]uint32
+	width  uint64
+	hasher Hasher[T]
+}
+
+// NewCountMinSketch is sized so estimates are at most eps*N too high, with probability 1 - delta.
+// Both must be between 0 and 1 (exclusive): eps 0 is infinitely wide, delta 1 has no rows
+func NewCountMinSketch[T any](eps, delta float64, hasher Hasher[T]) *CountMinSketch[T] {
+	if !(eps > 0 && eps < 1) || !(delta > 0 && delta < 1) { // written this way round, NaN fails too
+		panic("hashing.NewCountMinSketch: eps and delta must be between 0 and 1")
+	}
+	width := uint64(math.Ceil(math.E / eps))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	rows := make([][]uint32, depth)
+	for i := range rows {
+		rows[i] = make([]uint32, width)
+	}
+	return &CountMinSketch[T]{rows: rows, width: width, hasher: hasher}
+}
+
+// Add counts v once and returns its new estimate.
+// Each row needs its own hash - simulated from one 64 bit hash, like the Bloom filter does
+func (c *CountMinSketch[T]) Add(v T) int {
+	h := c.hasher.Hash(v)
+	h1, h2 := h&math.MaxUint32, h>>32
+	est := uint32(math.MaxUint32)
+	for i, row := range c.rows {
+		pos := (h1 + uint64(i)*h2) % c.width
+		row[pos]++
+		est = min(est, row[pos])
+	}
+	return int(est)
+}
+
+func (c *CountMinSketch[T]) Counters() int { return len(c.rows) * int(c.width) }
+
+// SketchTopK keeps the k keys with the highest estimates
+type SketchTopK[K cmp.Ordered] struct {
+	k      int
+	sketch *CountMinSketch[K]
+	heap   *pqueue.Queue[Counted[K]]
+	items  map[K]*pqueue.Item[Counted[K]]
+}
+
+// NewSketchTopK keeps the top k keys, over a count-min sketch sized by eps and delta. k must be at least 1
+func NewSketchTopK[K cmp.Ordered](k int, eps, delta float64, hasher Hasher[K]) *SketchTopK[K] {
+	if k < 1 {
+		panic("hashing.NewSketchTopK: k must be at least 1")
+	}
+	return &SketchTopK[K]{
+		k:      k,
+		sketch: NewCountMinSketch(eps, delta, hasher),
+		heap:   pqueue.New(minByCount[K]),
+		items:  make(map[K]*pqueue.Item[Counted[K]], k),
+	}
+}
+
+func (s *SketchTopK[K]) Add(key K) {
+	est := s.sketch.Add(key)
+	switch it, ok := s.items[key]; {
+	case ok:
+		it.Value.Count = est
+		s.heap.Fix(it)
+	case s.heap.Len() < s.k:
+		s.items[key] = s.heap.Push(Counted[K]{Key: key, Count: est})
+	case est > s.heap.Peek().Value.Count:
+		it := s.heap.Peek()
+		delete(s.items, it.Value.Key)
+		it.Value = Counted[K]{Key: key, Count: est}
+		s.heap.Fix(it)
+		s.items[key] = it
+	}
+}
+
+func (s *SketchTopK[K]) Top() []Counted[K] { return topOfHeap(s.heap, s.k) }
+
+// --- The log fixture ---
+
+// accessLog streams n generated access log lines, without ever building the whole log:
+// the lines are written into an io.Pipe by a goroutine, as fast as the reader reads them.
+// Paths follow a Zipf distribution (page i is about 1/i^s as popular as page 1), from a fixed seed,
+// so the log - and every count below - is the same on every run
+func accessLog(n, pages int, seed uint64) io.Reader {
+	r, w := io.Pipe()
+	go func() {
+		rng := rand.New(rand.NewPCG(seed, seed))
+		zipf := rand.NewZipf(rng, 1.1, 2, uint64(pages-1))
+		bw := bufio.NewWriter(w)
+		for i := range n {
+			status := 200
+			if rng.IntN(50) == 0 {
+				status = 404
+			}
+			fmt.Fprintf(bw, "2026-10-14T12:%02d:%02d GET /tour/page-%06d %d %dms\n",
+				i/60%60, i%60, zipf.Uint64(), status, 1+rng.IntN(90))
+		}
+		bw.Flush()
+		w.Close()
+	}()
+	return r
+}
+
+// eachPath calls f with the path field of every line
+func eachPath(log io.Reader, f func(path string)) error {
+	sc := bufio.NewScanner(log)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text()) // time, method, path, status, duration
+		if len(fields) >= 3 {
+			f(fields[2])
+		}
+	}
+	return sc.Err()
+}
+
+func topKExample() {
+	const (
+		lines = 200_000
+		pages = 1_000_000
+		k     = 10
+		seed  = 42
+	)
+
+	// The exact answer, for comparison - a counter for every distinct path
+	exact := map[string]int{}
+	eachPath(accessLog(lines, pages, seed), func(p string) { exact[p]++ })
+	var exactTop []Counted[string]
+	for key, n := range exact {
+		exactTop = append(exactTop, Counted[string]{Key: key, Count: n})
+	}
+	slices.SortFunc(exactTop, byCountDesc)
+	exactTop = exactTop[:k]
+
+	// The same log again, streamed through both bounded-memory counters
+	ss := NewSpaceSaving[string](20 * k)
+	const eps, delta = 0.002, 0.01
+	sketch := NewSketchTopK(k, eps, delta, Hasher[string](FNVHasher{}))
+	eachPath(accessLog(lines, pages, seed), func(p string) {
+		ss.Add(p)
+		sketch.Add(p)
+	})
+
+	fmt.Printf("%d lines, %d distinct paths\n", lines, len(exact))
+	fmt.Printf("memory - exact: %d counters | space-saving: %d | count-min: %d + %d in the heap\n\n",
+		len(exact), ss.capacity, sketch.sketch.Counters(), k)
+
+	name := func(path string) string { return strings.TrimPrefix(path, "/tour/") }
+	fmt.Printf("%-4s | %-18s | %-18s | %-18s\n", "rank", "exact", "space-saving", "count-min")
+	ssTop, cmTop := ss.Top(k), sketch.Top()
+	for i, e := range exactTop {
+		fmt.Printf("%-4d | %s %6d | %s %6d | %s %6d\n", i+1,
+			name(e.Key), e.Count, name(ssTop[i].Key), ssTop[i].Count, name(cmTop[i].Key), cmTop[i].Count)
+	}
+
+	// --- Accuracy checks against the exact count ---
+
+	recall := func(got []Counted[string]) int {
+		n := 0
+		for _, g := range got {
+			if slices.ContainsFunc(exactTop, func(e Counted[string]) bool { return e.Key == g.Key }) {
+				n++
+			}
+		}
+		return n
+	}
+	// Space-Saving's guarantee: count-err <= true count <= count, for every key it still tracks
+	ssBounds := true
+	for _, c := range ss.Top(ss.capacity) {
+		if actual := exact[c.Key]; actual > c.Count || actual < c.Count-c.Err {
+			ssBounds = false
+		}
+	}
+	// Count-min's: never too low, and at most eps*N too high (with probability 1 - delta per key)
+	cmNeverLow, cmMaxOver := true, 0
+	for _, c := range cmTop {
+		over := c.Count - exact[c.Key]
+		cmNeverLow = cmNeverLow && over >= 0
+		cmMaxOver = max(cmMaxOver, over)
+	}
+	bound := int(eps * lines)
+
+	fmt.Printf("\nspace-saving - top %d found: %d/%d, every count within its error bound: %t\n", k, recall(ssTop), k, ssBounds)
+	fmt.Printf("count-min    - top %d found: %d/%d, never underestimates: %t, worst overestimate %d (bound eps*N = %d): %t\n",
+		k, recall(cmTop), k, cmNeverLow, cmMaxOver, bound, cmMaxOver <= bound)
+}
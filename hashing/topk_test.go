@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+const (
+	testLines = 50_000
+	testPages = 100_000
+	testK     = 10
+)
+
+// exactCounts counts the fixture log exactly, and returns its top k too
+func exactCounts(t *testing.T) (map[string]int, []Counted[string]) {
+	t.Helper()
+	exact := map[string]int{}
+	if err := eachPath(accessLog(testLines, testPages, 42), func(p string) { exact[p]++ }); err != nil {
+		t.Fatal(err)
+	}
+	var top []Counted[string]
+	for key, n := range exact {
+		top = append(top, Counted[string]{Key: key, Count: n})
+	}
+	slices.SortFunc(top, byCountDesc)
+	return exact, top[:testK]
+}
+
+func found(got, want []Counted[string]) int {
+	n := 0
+	for _, g := range got {
+		if slices.ContainsFunc(want, func(w Counted[string]) bool { return w.Key == g.Key }) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSpaceSaving(t *testing.T) {
+	exact, top := exactCounts(t)
+	ss := NewSpaceSaving[string](20 * testK)
+	eachPath(accessLog(testLines, testPages, 42), ss.Add)
+
+	if n := found(ss.Top(testK), top); n < testK-1 {
+		t.Errorf("found %d of the top %d", n, testK)
+	}
+	// the guarantee: count-err <= true count <= count, for every key still tracked
+	for _, c := range ss.Top(ss.capacity) {
+		if actual := exact[c.Key]; actual > c.Count || actual < c.Count-c.Err {
+			t.Errorf("%s: counted %d (err %d), actually %d", c.Key, c.Count, c.Err, actual)
+		}
+	}
+}
+
+func TestSketchTopK(t *testing.T) {
+	exact, top := exactCounts(t)
+	const eps, delta = 0.002, 0.01
+	sketch := NewSketchTopK(testK, eps, delta, Hasher[string](FNVHasher{}))
+	eachPath(accessLog(testLines, testPages, 42), sketch.Add)
+
+	got := sketch.Top()
+	if len(got) != testK {
+		t.Fatalf("Top has %d keys, want %d", len(got), testK)
+	}
+	if n := found(got, top); n < testK-1 {
+		t.Errorf("found %d of the top %d", n, testK)
+	}
+	for _, c := range got {
+		if over := c.Count - exact[c.Key]; over < 0 || over > int(eps*testLines) {
+			t.Errorf("%s: estimated %d, actually %d - want 0 to eps*N = %d over", c.Key, c.Count, exact[c.Key], int(eps*testLines))
+		}
+	}
+}
+
+// A capacity or k of 1 is the smallest that works - and every Add past the first evicts
+func TestTopKOfOne(t *testing.T) {
+	ss := NewSpaceSaving[string](1)
+	sketch := NewSketchTopK(1, 0.01, 0.01, Hasher[string](FNVHasher{}))
+	for _, k := range []string{"a", "b", "a", "a"} {
+		ss.Add(k)
+		sketch.Add(k)
+	}
+	if got := ss.Top(1); len(got) != 1 || got[0].Key != "a" || got[0].Count != 4 || got[0].Err != 2 {
+		t.Errorf("SpaceSaving(1).Top = %v, want [{a 4 2}]", got)
+	}
+	if got := sketch.Top(); len(got) != 1 || got[0].Key != "a" || got[0].Count < 3 {
+		t.Errorf("SketchTopK(1).Top = %v, want a with 3 or more", got)
+	}
+}
+
+func TestTopKConstructorsReject(t *testing.T) {
+	h := Hasher[string](FNVHasher{})
+	for _, n := range []int{0, -1} {
+		wantPanic(t, fmt.Sprintf("NewSpaceSaving(%d)", n), func() { NewSpaceSaving[string](n) })
+		wantPanic(t, fmt.Sprintf("NewSketchTopK(%d, ...)", n), func() { NewSketchTopK(n, 0.01, 0.01, h) })
+	}
+	for _, c := range [][2]float64{{0, 0.01}, {1, 0.01}, {0.01, 0}, {0.01, 1}, {-1, 0.5}} {
+		wantPanic(t, fmt.Sprintf("NewCountMinSketch(%v, %v)", c[0], c[1]), func() { NewCountMinSketch(c[0], c[1], h) })
+	}
+}
+
+func BenchmarkTopKAdd(b *testing.B) {
+	var paths []string
+	eachPath(accessLog(10_000, testPages, 42), func(p string) { paths = append(paths, p) })
+	b.Run("space-saving", func(b *testing.B) {
+		ss := NewSpaceSaving[string](20 * testK)
+		i := 0
+		for b.Loop() {
+			ss.Add(paths[i%len(paths)])
+			i++
+		}
+	})
+	b.Run("count-min", func(b *testing.B) {
+		sketch := NewSketchTopK(testK, 0.002, 0.01, Hasher[string](FNVHasher{}))
+		i := 0
+		for b.Loop() {
+			sketch.Add(paths[i%len(paths)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Calling a JSON API with net/http, the careful way:
+// - never the zero http.Client (http.Get uses it): it has no timeout, so a server that stops answering
+//   hangs the caller forever. Client.Timeout bounds the whole exchange, body included
+// - a per-request deadline goes in the request's context (http.NewRequestWithContext) - the tighter of
+//   the two wins, and cancelling the context also aborts the request
+// - the body must always be closed, or the connection leaks. Reading it to the end first lets the
+//   connection go back to the pool for the next request; closing it half-read throws the connection away
+// - a non-2xx status is not an error to http.Client - checking resp.StatusCode is the caller's job
+//
+//	c := &http.Client{Timeout: 5 * time.Second, Transport: &httpclient.RetryTransport{MaxAttempts: 3}}
+//	page, err := httpclient.GetJSON[httpclient.PaginatedResDto[httpclient.User]](ctx, c, url)
+
+// User and PaginatedResDto are the types from the basics and generics notes, as the API sends them
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+type PaginatedResDto[T any] struct {
+	TotalItems   int  `json:"totalItems"`
+	TotalPages   int  `json:"totalPages"`
+	CurrPage     int  `json:"currPage"`
+	ItemsPerPage int  `json:"itemsPerPage"`
+	NextPage     *int `json:"nextPage"`
+	PrevPage     *int `json:"prevPage"`
+	Data         []T  `json:"data"`
+}
+
+// StatusError is a response that wasn't 2xx, with the start of its body (usually the server's error message)
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Code, http.StatusText(e.Code), e.Body)
+}
+
+// maxResponseBytes caps how much of a response is read - a broken or hostile server can send forever
+const maxResponseBytes = 10 << 20
+
+// GetJSON GETs url and decodes a 2xx JSON body into a T
+func GetJSON[T any](ctx context.Context, c *http.Client, url string) (T, error) {
+	var v T
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return v, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return v, err // a *url.Error - errors.Is sees a context.DeadlineExceeded inside
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return v, &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&v); err != nil {
+		return v, fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return v, nil
+}
+
+// closeBody reads what's left of a body (up to a limit - past that, reusing the connection isn't worth it) and closes it
+func closeBody(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	body.Close()
+}
+
+// AllPages GETs url?page=1, then follows NextPage until there's none, collecting the Data of every page
+func AllPages[T any](ctx context.Context, c *http.Client, rawURL string) ([]T, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	var all []T
+	for page := 1; ; {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		u.RawQuery = q.Encode()
+		res, err := GetJSON[PaginatedResDto[T]](ctx, c, u.String())
+		if err != nil {
+			return all, fmt.Errorf("page %d: %w", page, err)
+		}
+		all = append(all, res.Data...)
+		if res.NextPage == nil {
+			return all, nil
+		}
+		if *res.NextPage <= page { // a server bug would otherwise be an endless loop
+			return all, errors.New("next page doesn't move forward")
+		}
+		page = *res.NextPage
+	}
+}
@@ -0,0 +1,417 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// --- Timeouts ---
+
+// stallHandler never answers, until the client gives up
+func stallHandler(w http.ResponseWriter, r *http.Request) {
+	<-r.Context().Done()
+}
+
+func TestClientTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(stallHandler))
+	defer ts.Close()
+	c := &http.Client{Timeout: 20 * time.Millisecond}
+	_, err := GetJSON[map[string]bool](context.Background(), c, ts.URL)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(stallHandler))
+	defer ts.Close()
+	c := &http.Client{Timeout: time.Minute} // the context's deadline is the tighter one
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := GetJSON[map[string]bool](ctx, c, ts.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := GetJSON[map[string]bool](ctx, c, ts.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: err = %v, want context.Canceled", err)
+	}
+}
+
+// Client.Timeout covers the body: the headers come at once, the body never does
+func TestClientTimeoutBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	c := &http.Client{Timeout: 20 * time.Millisecond}
+	_, err := GetJSON[map[string]bool](context.Background(), c, ts.URL)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("err = %v, want a timeout while decoding the body", err)
+	}
+}
+
+// --- Bodies ---
+
+// countConns counts the connections ts accepts. Call it before ts.Start
+func countConns(ts *httptest.Server) *atomic.Int32 {
+	var n atomic.Int32
+	ts.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			n.Add(1)
+		}
+	}
+	return &n
+}
+
+// GetJSON drains and closes every body - a decoded one, an error one - so one connection serves all the requests
+func TestGetJSONReusesConnection(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, `{"error": "no such page"}`+strings.Repeat(" ", 4096), http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, `{"ok": true}`+strings.Repeat(" ", 4096)) // more after the value the decoder wants
+	}))
+	conns := countConns(ts)
+	ts.Start()
+	defer ts.Close()
+	c := &http.Client{Timeout: 5 * time.Second}
+	for i := range 6 {
+		path := "/"
+		if i%2 == 1 {
+			path = "/missing"
+		}
+		GetJSON[map[string]bool](context.Background(), c, ts.URL+path)
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("6 requests took %d connections, want 1", n)
+	}
+}
+
+func TestGetJSONStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "no such page"}`, http.StatusNotFound)
+	}))
+	defer ts.Close()
+	_, err := GetJSON[map[string]any](context.Background(), ts.Client(), ts.URL)
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusNotFound || se.Body != `{"error": "no such page"}` {
+		t.Fatalf("err = %#v, want a 404 StatusError with the body", err)
+	}
+	if want := `404 Not Found: {"error": "no such page"}`; se.Error() != want {
+		t.Errorf("Error() = %q, want %q", se.Error(), want)
+	}
+}
+
+// --- JSON pages ---
+
+// usersAPI serves n users in pages of perPage, counting the requests
+func usersAPI(n, perPage int, requests *atomic.Int32) http.HandlerFunc {
+	users := make([]User, n)
+	for i := range users {
+		users[i] = User{UserId: fmt.Sprintf("user-%02d", i+1), Name: fmt.Sprintf("User %d", i+1)}
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		totalPages := max((n+perPage-1)/perPage, 1)
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 || page > totalPages {
+			http.Error(w, `{"error": "page out of range"}`, http.StatusNotFound)
+			return
+		}
+		start := (page - 1) * perPage
+		res := PaginatedResDto[User]{
+			TotalItems: n, TotalPages: totalPages, CurrPage: page, ItemsPerPage: perPage,
+			Data: users[start:min(start+perPage, n)],
+		}
+		if page < totalPages {
+			next := page + 1
+			res.NextPage = &next
+		}
+		if page > 1 {
+			prev := page - 1
+			res.PrevPage = &prev
+		}
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func TestGetJSONPage(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(usersAPI(23, 10, &requests))
+	defer ts.Close()
+	page, err := GetJSON[PaginatedResDto[User]](context.Background(), ts.Client(), ts.URL+"/users?page=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalItems != 23 || page.TotalPages != 3 || page.CurrPage != 3 || page.ItemsPerPage != 10 {
+		t.Errorf("page = %+v", page)
+	}
+	if page.NextPage != nil || page.PrevPage == nil || *page.PrevPage != 2 {
+		t.Errorf("last page: NextPage %v, PrevPage %v; want nil and 2", page.NextPage, page.PrevPage)
+	}
+	if want := []User{{"user-21", "User 21"}, {"user-22", "User 22"}, {"user-23", "User 23"}}; fmt.Sprint(page.Data) != fmt.Sprint(want) {
+		t.Errorf("Data = %v, want %v", page.Data, want)
+	}
+}
+
+func TestGetJSONDecodeError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"totalItems": "twenty-three"}`)
+	}))
+	defer ts.Close()
+	_, err := GetJSON[PaginatedResDto[User]](context.Background(), ts.Client(), ts.URL)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field != "totalItems" {
+		t.Errorf("err = %v, want an UnmarshalTypeError for totalItems", err)
+	}
+}
+
+func TestAllPages(t *testing.T) {
+	for _, tc := range []struct {
+		n, perPage, requests int
+	}{
+		{23, 10, 3},
+		{20, 10, 2},
+		{0, 10, 1}, // one empty page
+	} {
+		var requests atomic.Int32
+		ts := httptest.NewServer(usersAPI(tc.n, tc.perPage, &requests))
+		all, err := AllPages[User](context.Background(), ts.Client(), ts.URL+"/users")
+		ts.Close()
+		if err != nil || len(all) != tc.n || int(requests.Load()) != tc.requests {
+			t.Errorf("%d users: %d back in %d requests, %v; want %d requests", tc.n, len(all), requests.Load(), err, tc.requests)
+			continue
+		}
+		for i, u := range all {
+			if want := fmt.Sprintf("user-%02d", i+1); u.UserId != want {
+				t.Errorf("%d users: all[%d] = %s, want %s", tc.n, i, u.UserId, want)
+			}
+		}
+	}
+}
+
+func TestAllPagesStopsOnLoop(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		io.WriteString(w, `{"data": [{"userId": "u1"}], "nextPage": 1}`) // a server bug: page 1 again
+	}))
+	defer ts.Close()
+	all, err := AllPages[User](context.Background(), ts.Client(), ts.URL)
+	if err == nil || len(all) != 1 || requests.Load() != 1 {
+		t.Errorf("got %d users in %d requests, err %v; want the first page and an error", len(all), requests.Load(), err)
+	}
+}
+
+// The page parameter is AllPages' own: one already in the URL is replaced
+func TestAllPagesSetsPage(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(usersAPI(23, 10, &requests))
+	defer ts.Close()
+	if all, err := AllPages[User](context.Background(), ts.Client(), ts.URL+"/users?page=x"); err != nil || len(all) != 23 {
+		t.Errorf("got %d users, %v; want all 23", len(all), err)
+	}
+	if _, err := AllPages[User](context.Background(), ts.Client(), "://bad"); err == nil {
+		t.Error("a bad URL: no error")
+	}
+}
+
+// --- RetryTransport ---
+
+// flakyHandler fails the first failures requests with status, then answers with the attempt's number
+func flakyHandler(failures int, status int, retryAfter string, hits *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if int(n) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, `{"error": "try again"}`, status)
+			return
+		}
+		fmt.Fprintf(w, `{"attempt": %d, "body": %q}`, n, body)
+	}
+}
+
+type attemptRes struct {
+	Attempt int    `json:"attempt"`
+	Body    string `json:"body"`
+}
+
+func retryClient(attempts int) *http.Client {
+	return &http.Client{Timeout: 5 * time.Second, Transport: &RetryTransport{MaxAttempts: attempts, Backoff: time.Millisecond}}
+}
+
+func TestRetryTransport(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		failures    int
+		status      int
+		retryAfter  string
+		attempts    int
+		wantHits    int32
+		wantAttempt int // 0: the last failure comes back as a StatusError
+	}{
+		{"ok at once", 0, 0, "", 4, 1, 1},
+		{"two 503s", 2, http.StatusServiceUnavailable, "", 4, 3, 3},
+		{"429 with Retry-After", 1, http.StatusTooManyRequests, "0", 4, 2, 2},
+		{"always 502", 100, http.StatusBadGateway, "", 4, 4, 0},
+		{"a 4xx isn't retried", 100, http.StatusNotFound, "", 4, 1, 0},
+		{"MaxAttempts 0 is 3", 100, http.StatusServiceUnavailable, "", 0, 3, 0},
+		{"negative MaxAttempts is 3", 100, http.StatusServiceUnavailable, "", -1, 3, 0},
+	} {
+		var hits atomic.Int32
+		ts := httptest.NewServer(flakyHandler(tc.failures, tc.status, tc.retryAfter, &hits))
+		res, err := GetJSON[attemptRes](context.Background(), retryClient(tc.attempts), ts.URL)
+		ts.Close()
+		if hits.Load() != tc.wantHits {
+			t.Errorf("%s: the server saw %d attempts, want %d", tc.name, hits.Load(), tc.wantHits)
+		}
+		if tc.wantAttempt == 0 {
+			var se *StatusError
+			if !errors.As(err, &se) || se.Code != tc.status {
+				t.Errorf("%s: err = %v, want a %d StatusError", tc.name, err, tc.status)
+			}
+		} else if err != nil || res.Attempt != tc.wantAttempt {
+			t.Errorf("%s: %+v, %v; want attempt %d", tc.name, res, err, tc.wantAttempt)
+		}
+	}
+}
+
+func TestRetryTransportMethods(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(flakyHandler(1, http.StatusServiceUnavailable, "", &hits))
+	defer ts.Close()
+	c := retryClient(4)
+
+	// A POST isn't retried: it may have done something the first time
+	resp, err := c.Post(ts.URL, "application/json", strings.NewReader(`{"name": "Ferris"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || hits.Load() != 1 {
+		t.Errorf("POST: %d after %d attempts, want the 503 after 1", resp.StatusCode, hits.Load())
+	}
+
+	// A PUT is, with its body sent again
+	hits.Store(0)
+	req, _ := http.NewRequest(http.MethodPut, ts.URL, strings.NewReader(`{"name": "Ferris"}`))
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res attemptRes
+	json.NewDecoder(resp.Body).Decode(&res)
+	resp.Body.Close()
+	if res.Attempt != 2 || res.Body != `{"name": "Ferris"}` {
+		t.Errorf("PUT: %+v, want attempt 2 with the whole body", res)
+	}
+
+	// A body it can't rewind isn't retried either
+	hits.Store(0)
+	req, _ = http.NewRequest(http.MethodPut, ts.URL, io.NopCloser(strings.NewReader("x")))
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if hits.Load() != 1 {
+		t.Errorf("PUT without GetBody: %d attempts, want 1", hits.Load())
+	}
+}
+
+// The context ends the wait between attempts
+func TestRetryTransportCancelledWait(t *testing.T) {
+	var hits atomic.Int32
+	ts := httptest.NewServer(flakyHandler(100, http.StatusServiceUnavailable, "", &hits))
+	defer ts.Close()
+	c := &http.Client{Transport: &RetryTransport{MaxAttempts: 5, Backoff: time.Minute, MaxWait: time.Minute}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := GetJSON[attemptRes](ctx, c, ts.URL)
+	if !errors.Is(err, context.DeadlineExceeded) || hits.Load() != 1 {
+		t.Errorf("err = %v after %d attempts, want DeadlineExceeded after 1", err, hits.Load())
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("took %v - the minute's backoff wasn't cut short", d)
+	}
+}
+
+// roundTripFunc is a RoundTripper from a function, to see what RetryTransport does with responses
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+// Each failed response's body is closed before the next attempt; the last response's is left to the caller.
+// The waits double from Backoff, capped at MaxWait, and a Retry-After replaces one
+func TestRetryTransportBodiesAndWaits(t *testing.T) {
+	var bodies []*trackedBody
+	var times []time.Time
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		times = append(times, time.Now())
+		if len(times) == 2 {
+			return nil, errors.New("connection reset") // a network error is retried too
+		}
+		b := &trackedBody{Reader: strings.NewReader("unavailable")}
+		bodies = append(bodies, b)
+		h := http.Header{}
+		if len(times) == 3 {
+			h.Set("Retry-After", "0")
+		}
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: h, Body: b}, nil
+	})
+	rt := &RetryTransport{Base: base, MaxAttempts: 5, Backoff: 100 * time.Millisecond, MaxWait: 300 * time.Millisecond}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || len(times) != 5 {
+		t.Fatalf("%v after %d attempts, want the last 503 after 5", err, len(times))
+	}
+	for i, b := range bodies {
+		if last := i == len(bodies)-1; b.closed == last {
+			t.Errorf("body %d of %d: closed %v", i+1, len(bodies), b.closed)
+		}
+	}
+
+	// waits: 100ms, 200ms, Retry-After 0, then 300ms (400ms capped)
+	wantMin := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 0, 300 * time.Millisecond}
+	for i, want := range wantMin {
+		if d := times[i+1].Sub(times[i]); d < want {
+			t.Errorf("wait %d = %v, want at least %v", i+1, d, want)
+		}
+	}
+	if d := times[3].Sub(times[2]); d >= 100*time.Millisecond {
+		t.Errorf("wait after Retry-After: 0 = %v, want less than the backoff", d)
+	}
+}
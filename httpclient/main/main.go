@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"httpclient"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// === Ex. An HTTP client: timeouts, bodies, JSON pages and retries ===
+
+// Every example talks to an httptest.Server - a real server on a local port, so the client side is
+// exactly what it would be against a remote API, and the server misbehaves on cue (slow, failing, paging).
+
+// --- Timeouts ---
+
+// slowHandler answers after d - or gives up when the client does (r.Context() is cancelled when it disconnects)
+func slowHandler(d time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(d):
+			fmt.Fprintln(w, `{"ok": true}`)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+func timeoutExample() {
+	ts := httptest.NewServer(slowHandler(500 * time.Millisecond))
+	defer ts.Close()
+
+	// Client.Timeout: the whole exchange, for every request made with this client
+	c := &http.Client{Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	_, err := c.Get(ts.URL)
+	var netErr net.Error
+	fmt.Println("Client.Timeout:", err)
+	fmt.Println("a timeout:", errors.As(err, &netErr) && netErr.Timeout(), "| after about 50ms:", time.Since(start) < 300*time.Millisecond)
+
+	// A deadline for one request, in its context - here tighter than the client's
+	c = &http.Client{Timeout: 10 * time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err = httpclient.GetJSON[map[string]bool](ctx, c, ts.URL)
+	fmt.Println("context deadline:", err, "| is DeadlineExceeded:", errors.Is(err, context.DeadlineExceeded))
+
+	// The timeout covers reading the body too: these headers come at once, the body never does
+	stall := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer stall.Close()
+	c = &http.Client{Timeout: 50 * time.Millisecond}
+	resp, err := c.Get(stall.URL)
+	if err != nil {
+		fmt.Println("get:", err)
+		return
+	}
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	fmt.Println("status", resp.StatusCode, "then reading the body:", err)
+}
+
+// --- Reading and closing bodies ---
+
+// connCounter counts the connections a test server accepts
+func connCounter(ts *httptest.Server) *atomic.Int32 {
+	var n atomic.Int32
+	ts.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			n.Add(1)
+		}
+	}
+	return &n
+}
+
+func bodyExample() {
+	big := strings.Repeat("x", 1<<20)
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, `{"error": "no such page"}`, http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, big)
+	}))
+	conns := connCounter(ts)
+	ts.Start()
+	defer ts.Close()
+	c := &http.Client{Timeout: 5 * time.Second}
+
+	for range 5 {
+		resp, err := c.Get(ts.URL)
+		if err != nil {
+			fmt.Println("get:", err)
+			return
+		}
+		io.Copy(io.Discard, resp.Body) // read to the end...
+		resp.Body.Close()              // ...then close: the connection goes back to the pool
+	}
+	fmt.Println("5 requests, bodies read and closed - new connections:", conns.Load())
+
+	conns.Store(0)
+	for range 5 {
+		resp, err := c.Get(ts.URL)
+		if err != nil {
+			fmt.Println("get:", err)
+			return
+		}
+		resp.Body.Close() // closed with most of a megabyte unread: the connection can't be reused
+	}
+	// (the first of them gets the connection left in the pool by the loop above, so 4 new ones)
+	fmt.Println("5 requests, bodies closed unread - new connections:", conns.Load())
+
+	// A 404 is a response, not an error - GetJSON turns it into a *StatusError, after closing the body
+	_, err := httpclient.GetJSON[map[string]any](context.Background(), c, ts.URL+"/missing")
+	var se *httpclient.StatusError
+	fmt.Println("404:", err, "| as StatusError:", errors.As(err, &se) && se.Code == 404)
+}
+
+// --- JSON pages ---
+
+// usersAPI serves n users in pages of perPage, as PaginatedResDto[User] - a server-side Paginate
+func usersAPI(n, perPage int, requests *atomic.Int32) http.HandlerFunc {
+	users := make([]httpclient.User, n)
+	for i := range users {
+		users[i] = httpclient.User{UserId: fmt.Sprintf("user-%02d", i+1), Name: fmt.Sprintf("User %d", i+1)}
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		totalPages := max((n+perPage-1)/perPage, 1)
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page < 1 || page > totalPages {
+			http.Error(w, `{"error": "page out of range"}`, http.StatusNotFound)
+			return
+		}
+		start := (page - 1) * perPage
+		res := httpclient.PaginatedResDto[httpclient.User]{
+			TotalItems: n, TotalPages: totalPages, CurrPage: page, ItemsPerPage: perPage,
+			Data: users[start:min(start+perPage, n)],
+		}
+		if page < totalPages {
+			next := page + 1
+			res.NextPage = &next
+		}
+		if page > 1 {
+			prev := page - 1
+			res.PrevPage = &prev
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func paginationExample() {
+	var requests atomic.Int32
+	ts := httptest.NewServer(usersAPI(23, 10, &requests))
+	defer ts.Close()
+	c := &http.Client{Timeout: 5 * time.Second}
+	ctx := context.Background()
+
+	page, err := httpclient.GetJSON[httpclient.PaginatedResDto[httpclient.User]](ctx, c, ts.URL+"/users?page=2")
+	if err != nil {
+		fmt.Println("page 2:", err)
+		return
+	}
+	fmt.Printf("page %d of %d: %d users, first %v, prev %d, next %d\n",
+		page.CurrPage, page.TotalPages, len(page.Data), page.Data[0], *page.PrevPage, *page.NextPage)
+
+	requests.Store(0)
+	all, err := httpclient.AllPages[httpclient.User](ctx, c, ts.URL+"/users")
+	fmt.Println("all pages:", len(all), "users in", requests.Load(), "requests, err:", err,
+		"| all there, in order:", len(all) == 23 && all[0].UserId == "user-01" && all[22].UserId == "user-23")
+
+	_, err = httpclient.GetJSON[httpclient.PaginatedResDto[httpclient.User]](ctx, c, ts.URL+"/users?page=9")
+	fmt.Println("page 9:", err)
+
+	// A body that isn't the expected JSON is a decode error, not a half-filled value passed off as fine
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"totalItems": "twenty-three"}`)
+	}))
+	defer bad.Close()
+	_, err = httpclient.GetJSON[httpclient.PaginatedResDto[httpclient.User]](ctx, c, bad.URL)
+	var typeErr *json.UnmarshalTypeError
+	fmt.Println("wrong JSON:", errors.As(err, &typeErr), "|", err)
+}
+
+// --- Retries ---
+
+// flakyHandler fails the first failures requests with status, then answers
+func flakyHandler(failures int, status int, retryAfter string, hits *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		io.Copy(io.Discard, r.Body)
+		if int(n) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, `{"error": "try again"}`, status)
+			return
+		}
+		fmt.Fprintf(w, `{"attempt": %d}`, n)
+	}
+}
+
+func retryTransportExample() {
+	ctx := context.Background()
+	client := func() *http.Client {
+		return &http.Client{Timeout: 5 * time.Second, Transport: &httpclient.RetryTransport{MaxAttempts: 4, Backoff: 10 * time.Millisecond}}
+	}
+	type attemptRes struct {
+		Attempt int `json:"attempt"`
+	}
+
+	var hits atomic.Int32
+	ts := httptest.NewServer(flakyHandler(2, http.StatusServiceUnavailable, "", &hits))
+	res, err := httpclient.GetJSON[attemptRes](ctx, client(), ts.URL)
+	fmt.Println("two 503s, then ok:", res, err, "| server saw:", hits.Load(), "| ok:", err == nil && res.Attempt == 3)
+	ts.Close()
+
+	// Retry-After: 0 - the server says when; a long one would be capped at MaxWait
+	hits.Store(0)
+	ts = httptest.NewServer(flakyHandler(1, http.StatusTooManyRequests, "0", &hits))
+	res, err = httpclient.GetJSON[attemptRes](ctx, client(), ts.URL)
+	fmt.Println("429 with Retry-After:", res.Attempt, err)
+	ts.Close()
+
+	// Out of attempts: the last response is returned as it is - GetJSON reports its status
+	hits.Store(0)
+	ts = httptest.NewServer(flakyHandler(100, http.StatusBadGateway, "", &hits))
+	_, err = httpclient.GetJSON[attemptRes](ctx, client(), ts.URL)
+	var se *httpclient.StatusError
+	fmt.Println("always 502:", err, "| attempts:", hits.Load(), "| ok:", errors.As(err, &se) && se.Code == 502 && hits.Load() == 4)
+
+	// A POST isn't retried - it may have done something the first time
+	hits.Store(0)
+	resp, err := client().Post(ts.URL, "application/json", strings.NewReader(`{"name": "Ferris"}`))
+	if err == nil {
+		resp.Body.Close()
+		fmt.Println("POST:", resp.StatusCode, "| attempts:", hits.Load())
+	}
+	// A PUT is, with its body sent again each time (NewRequest sets GetBody for a strings.Reader)
+	hits.Store(0)
+	req, _ := http.NewRequest(http.MethodPut, ts.URL, strings.NewReader(`{"name": "Ferris"}`))
+	if resp, err = client().Do(req); err == nil {
+		resp.Body.Close()
+		fmt.Println("PUT:", resp.StatusCode, "| attempts:", hits.Load())
+	}
+	ts.Close()
+
+	// A cancelled context ends the waiting between attempts
+	hits.Store(0)
+	ts = httptest.NewServer(flakyHandler(100, http.StatusServiceUnavailable, "", &hits))
+	defer ts.Close()
+	slow := &http.Client{Transport: &httpclient.RetryTransport{MaxAttempts: 5, Backoff: time.Minute, MaxWait: time.Minute}}
+	cctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = httpclient.GetJSON[attemptRes](cctx, slow, ts.URL)
+	fmt.Println("cancelled in a 1 minute backoff:", err, "| quickly:", time.Since(start) < time.Second, "| attempts:", hits.Load())
+}
+
+func main() {
+	timeoutExample()
+	// bodyExample()
+	// paginationExample()
+	// retryTransportExample()
+}
@@ -0,0 +1,105 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryTransport is an http.RoundTripper - the part of http.Client that sends one request and gets one response -
+// that retries on the failures that may go away: a network error, a 5xx, or 429 Too Many Requests.
+// As a Transport, it's invisible to the code making requests: GetJSON doesn't know retries happen.
+//
+// It only retries requests it can send again safely:
+// - idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) - a retried POST could create something twice
+// - with a body it can rewind: req.GetBody, which http.NewRequest sets for bytes, strings.Reader and bytes.Reader bodies
+// Each failed response's body is drained and closed before the next attempt, so its connection is reused.
+// The waits double from Base, or follow a Retry-After header (in seconds), and end early if the request's
+// context is done.
+type RetryTransport struct {
+	Base        http.RoundTripper // nil: http.DefaultTransport
+	MaxAttempts int               // including the first; 0 or less is 3
+	Backoff     time.Duration     // the first wait; 0 or less is 100ms
+	MaxWait     time.Duration     // the longest wait, Retry-After included; 0 or less is 5s
+}
+
+// defaults fills in the zero fields - and the negative ones: RoundTrip stops when the attempt count
+// reaches MaxAttempts, which a count starting at 1 never does for a negative one
+func (t *RetryTransport) defaults() (base http.RoundTripper, attempts int, backoff, maxWait time.Duration) {
+	base, attempts, backoff, maxWait = t.Base, t.MaxAttempts, t.Backoff, t.MaxWait
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if attempts <= 0 {
+		attempts = 3
+	}
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	if maxWait <= 0 {
+		maxWait = 5 * time.Second
+	}
+	return base, attempts, backoff, maxWait
+}
+
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+func retryStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base, attempts, wait, maxWait := t.defaults()
+	if !retryable(req) {
+		return base.RoundTrip(req)
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if attempt == attempts || (err == nil && !retryStatus(resp.StatusCode)) || req.Context().Err() != nil {
+			return resp, err // the last response is the caller's, body unread
+		}
+
+		d := wait
+		if err == nil {
+			if s, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && s >= 0 {
+				d = time.Duration(s) * time.Second
+			}
+			closeBody(resp.Body)
+		}
+		d = min(d, maxWait)
+		if err := sleepCtx(req.Context(), d); err != nil {
+			return nil, err
+		}
+		wait = min(wait*2, maxWait)
+
+		// A RoundTripper must not change the caller's request, so the retry is a copy with a fresh body
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var _ http.RoundTripper = (*RetryTransport)(nil)
@@ -0,0 +1,194 @@
+package httpserver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A small JSON API over net/http - the users from the basics notes, listed and created over HTTP:
+//
+//	GET  /users       -> 200 [{"userId": ..., "name": ...}, ...]
+//	GET  /users/{id}  -> 200 {"userId": ..., "name": ...}, or 404
+//	POST /users       {"name": "..."} -> 201 with the new user (and a Location header), or 400
+//
+// The pieces:
+// - a handler is anything with ServeHTTP(http.ResponseWriter, *http.Request) - the http.Handler interface.
+//   http.HandlerFunc is a func type with that method, so a plain func with the same signature becomes a Handler
+//   by conversion: http.HandlerFunc(f)
+// - http.ServeMux routes by method and path pattern (Go 1.22+): "GET /users/{id}" matches only GETs,
+//   and r.PathValue("id") is the {id} part. A path with no matching method gets 405, with an Allow header
+// - middleware (middleware.go) is a func from Handler to Handler, wrapping one in another
+//
+//	srv := httpserver.New(httpserver.NewStore(users...))
+//	http.ListenAndServe(":8080", httpserver.Chain(srv, httpserver.Logging(logger), httpserver.Recover(slogger)))
+//
+// (the shorturl module is an app built from these pieces, with the generics and concurrency notes' too)
+
+// User is the User struct from the basics notes, with json tags for the API's field names
+type User struct {
+	UserId string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// Store keeps users in memory, in the order they were added. It's safe for concurrent use -
+// the server runs each request in its own goroutine
+type Store struct {
+	mu    sync.RWMutex
+	users []User
+	byID  map[string]int // index into users
+}
+
+func NewStore(users ...User) *Store {
+	s := &Store{byID: map[string]int{}}
+	for _, u := range users {
+		s.Add(u)
+	}
+	return s
+}
+
+var ErrDuplicateID = errors.New("duplicate user id")
+
+func (s *Store) Add(u User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.byID[u.UserId]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateID, u.UserId)
+	}
+	s.byID[u.UserId] = len(s.users)
+	s.users = append(s.users, u)
+	return nil
+}
+
+func (s *Store) Get(id string) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.byID[id]
+	if !ok {
+		return User{}, false
+	}
+	return s.users[i], true
+}
+
+// List is a copy, so the caller can't change the store's slice
+func (s *Store) List() []User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.users)
+}
+
+// Server is the API's handler
+type Server struct {
+	store *Store
+	mux   *http.ServeMux
+	newID func() string // ids for created users; a UUID by default
+}
+
+func New(store *Store) *Server {
+	s := &Server{store: store, mux: http.NewServeMux(), newID: newUUID}
+	s.mux.HandleFunc("GET /users", s.listUsers)
+	s.mux.HandleFunc("GET /users/{id}", s.getUser)
+	s.mux.HandleFunc("POST /users", s.createUser)
+	return s
+}
+
+// WithIDs replaces the id generator - ex. with a counter, so ids are known in advance
+func (s *Server) WithIDs(newID func() string) *Server {
+	s.newID = newID
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	users := s.store.List()
+	if users == nil {
+		users = []User{} // [] in the JSON, not null
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	u, ok := s.store.Get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no user with id "+id)
+		return
+	}
+	writeJSON(w, http.StatusOK, u)
+}
+
+// maxBodyBytes caps a request body - without it, a client can send an endless one
+const maxBodyBytes = 1 << 20
+
+type createUserRequest struct {
+	Name string `json:"name"`
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields() // {"nmae": "x"} is a mistake to report, not an empty name
+	var req createUserRequest
+	if err := dec.Decode(&req); err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			writeError(w, http.StatusRequestEntityTooLarge, "body over 1 MB")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if dec.More() {
+		writeError(w, http.StatusBadRequest, "invalid JSON: more than one value")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	u := User{UserId: s.newID(), Name: req.Name}
+	if err := s.store.Add(u); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	w.Header().Set("Location", "/users/"+u.UserId)
+	writeJSON(w, http.StatusCreated, u)
+}
+
+// writeJSON sends v as the response. Headers go first: once WriteHeader (or the first Write) has been called,
+// the status and headers are sent and can't change
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) // an error here is the client gone - nothing left to tell it
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// newUUID is a random (version 4) UUID, like the ids in the basics notes
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:]) // never fails (crypto/rand panics instead, if it can't read)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
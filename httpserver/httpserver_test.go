@@ -0,0 +1,198 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const userId1, userId2 = "1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func seedStore() *Store {
+	return NewStore(User{UserId: userId1, Name: "John Doe"}, User{UserId: userId2, Name: "Jack Eod"})
+}
+
+// counterIDs gives new-1, new-2, ...
+func counterIDs() func() string {
+	var mu sync.Mutex
+	next := 0
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		next++
+		return fmt.Sprintf("new-%d", next)
+	}
+}
+
+// serve sends one request straight to h, and returns the recorded response
+func serve(h http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, r)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPI(t *testing.T) {
+	srv := New(seedStore()).WithIDs(counterIDs())
+	const js = "application/json"
+	for _, tc := range []struct {
+		name, method, target, contentType, body string
+		status                                  int
+		want                                    string // the whole body, trimmed
+	}{
+		{"list", "GET", "/users", "", "", 200,
+			`[{"userId":"` + userId1 + `","name":"John Doe"},{"userId":"` + userId2 + `","name":"Jack Eod"}]`},
+		{"get one", "GET", "/users/" + userId1, "", "", 200, `{"userId":"` + userId1 + `","name":"John Doe"}`},
+		{"get missing", "GET", "/users/nobody", "", "", 404, `{"error":"no user with id nobody"}`},
+		{"create", "POST", "/users", js, `{"name": "Ferris"}`, 201, `{"userId":"new-1","name":"Ferris"}`},
+		{"create, name padded", "POST", "/users", js, `{"name": "  Gopher "}`, 201, `{"userId":"new-2","name":"Gopher"}`},
+		{"create, charset in the Content-Type", "POST", "/users", js + "; charset=utf-8", `{"name": "Carol"}`, 201, `{"userId":"new-3","name":"Carol"}`},
+		{"create, no Content-Type", "POST", "/users", "", `{"name": "Dave"}`, 201, `{"userId":"new-4","name":"Dave"}`},
+		{"create, no name", "POST", "/users", js, `{"name": "  "}`, 400, `{"error":"name is required"}`},
+		{"create, empty body", "POST", "/users", js, "", 400, `{"error":"invalid JSON: EOF"}`},
+		{"create, bad JSON", "POST", "/users", js, `{"name": `, 400, `{"error":"invalid JSON: unexpected EOF"}`},
+		{"create, unknown field", "POST", "/users", js, `{"nmae": "Ferris"}`, 400, `{"error":"invalid JSON: json: unknown field \"nmae\""}`},
+		{"create, two values", "POST", "/users", js, `{"name": "a"} {"name": "b"}`, 400, `{"error":"invalid JSON: more than one value"}`},
+		{"create, text/plain", "POST", "/users", "text/plain", `{"name": "x"}`, 415, `{"error":"Content-Type must be application/json"}`},
+		{"wrong method", "DELETE", "/users/" + userId1, "", "", 405, "Method Not Allowed"},
+	} {
+		rec := serve(srv, tc.method, tc.target, tc.contentType, tc.body)
+		if got := strings.TrimSpace(rec.Body.String()); rec.Code != tc.status || got != tc.want {
+			t.Errorf("%s: %d %s\nwant %d %s", tc.name, rec.Code, got, tc.status, tc.want)
+		}
+	}
+
+	var users []User
+	json.Unmarshal(serve(srv, "GET", "/users", "", "").Body.Bytes(), &users)
+	names := make([]string, len(users))
+	for i, u := range users {
+		names[i] = u.Name
+	}
+	if want := []string{"John Doe", "Jack Eod", "Ferris", "Gopher", "Carol", "Dave"}; !slices.Equal(names, want) {
+		t.Errorf("after the creates, list is %v, want %v in the order added", names, want)
+	}
+}
+
+func TestCreateHeaders(t *testing.T) {
+	rec := serve(New(NewStore()).WithIDs(counterIDs()), "POST", "/users", "application/json", `{"name": "Ferris"}`)
+	if loc := rec.Header().Get("Location"); loc != "/users/new-1" {
+		t.Errorf("Location = %q, want /users/new-1", loc)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if rec.Header().Get("Allow") != "" {
+		t.Error("an Allow header on a 201")
+	}
+	rec = serve(New(NewStore()), "DELETE", "/users", "", "")
+	if rec.Code != 405 || !strings.Contains(rec.Header().Get("Allow"), "POST") {
+		t.Errorf("DELETE /users: %d, Allow %q - want 405 allowing GET and POST", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestEmptyListIsNotNull(t *testing.T) {
+	if got := strings.TrimSpace(serve(New(NewStore()), "GET", "/users", "", "").Body.String()); got != "[]" {
+		t.Errorf("empty list: %s, want []", got)
+	}
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	store := NewStore()
+	rec := serve(New(store), "POST", "/users", "application/json", `{"name": "`+strings.Repeat("x", 2<<20)+`"}`)
+	if rec.Code != 413 || len(store.List()) != 0 {
+		t.Errorf("2 MB body: %d %s, %d users - want 413 and nothing added", rec.Code, rec.Body, len(store.List()))
+	}
+}
+
+func TestDuplicateID(t *testing.T) {
+	srv := New(seedStore()).WithIDs(func() string { return userId1 })
+	rec := serve(srv, "POST", "/users", "application/json", `{"name": "Imposter"}`)
+	if rec.Code != 409 || !strings.Contains(rec.Body.String(), "duplicate user id") {
+		t.Errorf("a clashing id: %d %s, want 409", rec.Code, rec.Body)
+	}
+	if u, _ := srv.store.Get(userId1); u.Name != "John Doe" {
+		t.Errorf("the clash replaced the user: %v", u)
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	seen := map[string]bool{}
+	for range 100 {
+		id := newUUID()
+		if len(id) != 36 || id[14] != '4' || !strings.ContainsRune("89ab", rune(id[19])) || strings.Count(id, "-") != 4 {
+			t.Fatalf("%q isn't a version 4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("%q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+// The store is shared by every request's goroutine: concurrent creates and lists, for go test -race
+func TestConcurrentRequests(t *testing.T) {
+	srv := New(NewStore()).WithIDs(counterIDs())
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			if rec := serve(srv, "POST", "/users", "application/json", fmt.Sprintf(`{"name": "user%d"}`, i)); rec.Code != 201 {
+				t.Errorf("create %d: %d %s", i, rec.Code, rec.Body)
+			}
+		})
+		wg.Go(func() { serve(srv, "GET", "/users", "", "") })
+	}
+	wg.Wait()
+	if n := len(srv.store.List()); n != 50 {
+		t.Errorf("%d users after 50 concurrent creates", n)
+	}
+}
+
+func TestStoreListIsACopy(t *testing.T) {
+	s := seedStore()
+	users := s.List()
+	users[0].Name = "changed"
+	if u, _ := s.Get(userId1); u.Name != "John Doe" {
+		t.Errorf("changing List's result changed the store: %v", u)
+	}
+}
+
+// The same API through a real connection and http.Client
+func TestServer(t *testing.T) {
+	ts := httptest.NewServer(New(seedStore()))
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/users", "application/json", strings.NewReader(`{"name": "Ferris"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created User
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if resp.StatusCode != 201 || len(created.UserId) != 36 {
+		t.Fatalf("POST: %s, %v", resp.Status, created)
+	}
+
+	resp, err = ts.Client().Get(ts.URL + resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetched User
+	json.NewDecoder(resp.Body).Decode(&fetched)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || fetched != created {
+		t.Errorf("GET the Location: %s, %v - want %v", resp.Status, fetched, created)
+	}
+}
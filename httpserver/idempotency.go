@@ -0,0 +1,216 @@
+package httpserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// --- Idempotency keys ---
+
+// A client that times out on a POST can't know whether the server ran it. Retrying might create a second
+// order; not retrying might lose the first. With an Idempotency-Key header (a random ID the client makes
+// up per operation, and resends unchanged on every retry) the server can tell retries apart from new requests:
+// - the first request with a key runs, and its response is kept for a while (ttl)
+// - a repeat gets the kept response back - the handler does not run again
+// - a repeat that arrives while the first is still running waits for it, and gets the same response
+// - the same key with a DIFFERENT request (another body or path) is a client bug: 422
+//
+// Requests are compared by a SHA-256 of the path and body - a fixed-size fingerprint, so the cache doesn't
+// have to hold every request body. 5xx responses (and panics) aren't kept, so the client's retry runs again.
+// A body over MaxIdempotentBody is refused with 413 - it has to be read whole for the fingerprint, and
+// cut short it would be a different request.
+//
+// IdempotencyCache is its own small TTL cache rather than basics/ttlcache: that's another module, which
+// this one can't import (no go.work here), and its entries need something ttlcache doesn't have anyway -
+// an in-flight entry, which never expires while its request runs, and which duplicates wait on (done).
+// Its sweep is the one concurrency's KeyedLimiter uses: at most once per ttl.
+
+// MaxIdempotentBody is the largest body Idempotency reads to fingerprint - a bigger one gets 413
+const MaxIdempotentBody = 1 << 20
+
+type recordedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	done        chan struct{}     // closed once the first request has finished
+	resp        *recordedResponse // set before done is closed; nil if the response wasn't kept
+	expires     time.Time
+}
+
+type IdempotencyCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+// NewIdempotencyCache panics if ttl <= 0: a response kept for no time at all would never be replayed
+func NewIdempotencyCache(ttl time.Duration) *IdempotencyCache {
+	if ttl <= 0 {
+		panic("NewIdempotencyCache: ttl must be positive")
+	}
+	return &IdempotencyCache{ttl: ttl, now: time.Now, entries: make(map[string]*idempotencyEntry), lastSweep: time.Now()}
+}
+
+// WithClock replaces time.Now - ex. with a fake clock, so expiry can be shown without waiting an hour.
+// Set it before the cache is used
+func (c *IdempotencyCache) WithClock(now func() time.Time) *IdempotencyCache {
+	c.now = now
+	c.lastSweep = now()
+	return c
+}
+
+// claim returns the entry for key, and whether the caller is the one who has to run the request
+func (c *IdempotencyCache) claim(key string, fingerprint [sha256.Size]byte) (e *idempotencyEntry, owner bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for k, e := range c.entries {
+			if e.resp != nil && !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+	if e, ok := c.entries[key]; ok && (e.resp == nil || now.Before(e.expires)) {
+		return e, false
+	}
+	e = &idempotencyEntry{fingerprint: fingerprint, done: make(chan struct{})}
+	c.entries[key] = e
+	return e, true
+}
+
+// finish stores the response (or drops the entry, if resp is nil or a server error) and wakes the waiters
+func (c *IdempotencyCache) finish(key string, e *idempotencyEntry, resp *recordedResponse) {
+	c.mu.Lock()
+	if resp == nil || resp.status >= 500 {
+		delete(c.entries, key)
+	} else {
+		e.resp = resp
+		e.expires = c.now().Add(c.ttl)
+	}
+	c.mu.Unlock()
+	close(e.done)
+}
+
+func (c *IdempotencyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// responseRecorder passes the response through to the client, keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+func (r *responseRecorder) response() *recordedResponse {
+	return &recordedResponse{status: max(r.status, http.StatusOK), header: r.Header().Clone(), body: bytes.Clone(r.body.Bytes())}
+}
+
+func replay(w http.ResponseWriter, resp *recordedResponse) {
+	for k, v := range resp.header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.status)
+	w.Write(resp.body)
+}
+
+// Idempotency deduplicates POSTs that carry an Idempotency-Key header. Anything else passes through
+func Idempotency(c *IdempotencyCache) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// the body is needed for the fingerprint, and again by the handler - read it once, hand over a copy
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxIdempotentBody))
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				http.Error(w, fmt.Sprintf("body over %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, "reading body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			h := sha256.New()
+			io.WriteString(h, r.URL.Path)
+			h.Write([]byte{0}) // a separator, so path "/a" + body "b" isn't the same as "/ab" + ""
+			h.Write(body)
+			var fingerprint [sha256.Size]byte
+			h.Sum(fingerprint[:0])
+
+			for {
+				e, owner := c.claim(key, fingerprint)
+				if e.fingerprint != fingerprint {
+					http.Error(w, "Idempotency-Key reused with a different request", http.StatusUnprocessableEntity)
+					return
+				}
+				if owner {
+					rec := &responseRecorder{ResponseWriter: w}
+					completed := false
+					// deferred, so a panicking handler still wakes the waiters (and isn't cached)
+					defer func() {
+						var resp *recordedResponse
+						if completed {
+							resp = rec.response()
+						}
+						c.finish(key, e, resp)
+					}()
+					r.Body = io.NopCloser(bytes.NewReader(body))
+					next.ServeHTTP(rec, r)
+					completed = true
+					return
+				}
+
+				select {
+				case <-e.done:
+				case <-r.Context().Done(): // the duplicate's client gave up waiting
+					return
+				}
+				if e.resp != nil {
+					replay(w, e.resp)
+					return
+				}
+				// the first attempt failed and wasn't kept - go around, and maybe run it this time
+			}
+		})
+	}
+}
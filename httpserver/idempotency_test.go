@@ -0,0 +1,236 @@
+package httpserver
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a time.Now that only moves when told to
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// orderServer serves Idempotency over a real listener. The handler takes delay per request,
+// so duplicates sent together really overlap with the first one
+func orderServer(t *testing.T, cache *IdempotencyCache, delay time.Duration, handle func(w http.ResponseWriter, body string)) (*httptest.Server, *atomic.Int32) {
+	var runs atomic.Int32
+	srv := httptest.NewUnstartedServer(Idempotency(cache)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := runs.Add(1)
+		time.Sleep(delay)
+		body, _ := io.ReadAll(r.Body)
+		if handle != nil {
+			handle(w, string(body))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "order %d: %s", n, body)
+	})))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // the panic test's recovered panic would be logged
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv, &runs
+}
+
+type reply struct {
+	code     int
+	body     string
+	replayed bool
+}
+
+func postOrder(t *testing.T, srv *httptest.Server, key, body string) reply {
+	t.Helper()
+	req, _ := http.NewRequest("POST", srv.URL+"/orders", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		return reply{code: -1, body: err.Error()}
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return reply{resp.StatusCode, string(b), resp.Header.Get("Idempotent-Replayed") == "true"}
+}
+
+func TestIdempotencyConcurrentDuplicates(t *testing.T) {
+	srv, runs := orderServer(t, NewIdempotencyCache(time.Hour), 50*time.Millisecond, nil)
+	const copies = 20
+	replies := make([]reply, copies)
+	var wg sync.WaitGroup
+	for i := range copies {
+		wg.Go(func() { replies[i] = postOrder(t, srv, "key-1", "1 gopher plush") })
+	}
+	wg.Wait()
+
+	if runs.Load() != 1 {
+		t.Errorf("handler ran %d times for %d duplicates, want 1", runs.Load(), copies)
+	}
+	replayed := 0
+	for i, r := range replies {
+		if r.code != http.StatusCreated || r.body != "order 1: 1 gopher plush" {
+			t.Errorf("copy %d: %d %q, want 201 \"order 1: 1 gopher plush\"", i, r.code, r.body)
+		}
+		if r.replayed {
+			replayed++
+		}
+	}
+	if replayed != copies-1 {
+		t.Errorf("%d replies marked replayed, want %d (all but the first)", replayed, copies-1)
+	}
+
+	// a retry after the first finished gets the kept response too
+	if r := postOrder(t, srv, "key-1", "1 gopher plush"); !r.replayed || r.body != "order 1: 1 gopher plush" || runs.Load() != 1 {
+		t.Errorf("later retry: %+v, handler runs %d", r, runs.Load())
+	}
+}
+
+// Different keys sent at the same time don't wait for each other, and each runs once
+func TestIdempotencyConcurrentKeys(t *testing.T) {
+	srv, runs := orderServer(t, NewIdempotencyCache(time.Hour), 20*time.Millisecond, nil)
+	var wg sync.WaitGroup
+	for k := range 5 {
+		for range 4 {
+			wg.Go(func() {
+				if r := postOrder(t, srv, fmt.Sprint("key-", k), fmt.Sprint("item ", k)); r.code != http.StatusCreated {
+					t.Errorf("key-%d: %d %q", k, r.code, r.body)
+				}
+			})
+		}
+	}
+	wg.Wait()
+	if runs.Load() != 5 {
+		t.Errorf("handler ran %d times for 5 keys, want 5", runs.Load())
+	}
+}
+
+func TestIdempotencyKeyReusedWithAnotherRequest(t *testing.T) {
+	srv, runs := orderServer(t, NewIdempotencyCache(time.Hour), 50*time.Millisecond, nil)
+	var wg sync.WaitGroup
+	wg.Go(func() { postOrder(t, srv, "key-1", "1 gopher plush") })
+	time.Sleep(10 * time.Millisecond) // while the first is still running
+	if r := postOrder(t, srv, "key-1", "3 mugs"); r.code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with another body, in flight: %d, want 422", r.code)
+	}
+	wg.Wait()
+	if r := postOrder(t, srv, "key-1", "3 mugs"); r.code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with another body, after: %d, want 422", r.code)
+	}
+	if runs.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", runs.Load())
+	}
+}
+
+// A 5xx or a panic isn't kept: the duplicates waiting on it, and later retries, run the handler again
+func TestIdempotencyFailuresNotKept(t *testing.T) {
+	var calls atomic.Int32
+	srv, runs := orderServer(t, NewIdempotencyCache(time.Hour), 30*time.Millisecond, func(w http.ResponseWriter, body string) {
+		switch calls.Add(1) {
+		case 1:
+			http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+		case 2:
+			panic("handler bug")
+		default:
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, "created "+body)
+		}
+	})
+	if r := postOrder(t, srv, "key-1", "a"); r.code != http.StatusServiceUnavailable {
+		t.Fatalf("first: %d, want 503", r.code)
+	}
+
+	// the second run panics with a duplicate waiting on it - the duplicate must wake up and run it itself
+	var wg sync.WaitGroup
+	var waiter reply
+	wg.Go(func() { postOrder(t, srv, "key-1", "a") }) // the panicking one: the connection is just closed
+	time.Sleep(10 * time.Millisecond)
+	wg.Go(func() { waiter = postOrder(t, srv, "key-1", "a") })
+	wg.Wait()
+	if waiter.code != http.StatusCreated || waiter.body != "created a" || waiter.replayed {
+		t.Errorf("duplicate of a panicking request: %+v, want its own 201", waiter)
+	}
+	if runs.Load() != 3 {
+		t.Errorf("handler ran %d times, want 3 (503, panic, 201)", runs.Load())
+	}
+	if r := postOrder(t, srv, "key-1", "a"); !r.replayed {
+		t.Errorf("after the 201: %+v, want it replayed", r)
+	}
+}
+
+func TestIdempotencyExpiry(t *testing.T) {
+	clock := &fakeClock{}
+	cache := NewIdempotencyCache(time.Hour).WithClock(clock.Now)
+	srv, runs := orderServer(t, cache, 0, nil)
+	postOrder(t, srv, "key-1", "a")
+	clock.Advance(59 * time.Minute)
+	if r := postOrder(t, srv, "key-1", "a"); !r.replayed {
+		t.Error("replay within the ttl: not replayed")
+	}
+	clock.Advance(time.Minute)
+	if r := postOrder(t, srv, "key-1", "a"); r.replayed || r.body != "order 2: a" {
+		t.Errorf("after the ttl: %+v, want the handler to run again", r)
+	}
+	// the sweep dropped the expired entry before the new one was added
+	if cache.Len() != 1 || runs.Load() != 2 {
+		t.Errorf("Len %d, runs %d, want 1 and 2", cache.Len(), runs.Load())
+	}
+}
+
+func TestIdempotencyPassThrough(t *testing.T) {
+	srv, runs := orderServer(t, NewIdempotencyCache(time.Hour), 0, nil)
+	postOrder(t, srv, "", "a")
+	postOrder(t, srv, "", "a")
+	resp, err := srv.Client().Get(srv.URL + "/orders")
+	if err == nil {
+		resp.Body.Close()
+	}
+	if runs.Load() != 3 {
+		t.Errorf("handler ran %d times for 2 keyless POSTs and a GET, want 3", runs.Load())
+	}
+}
+
+func TestIdempotencyBodyTooLarge(t *testing.T) {
+	srv, runs := orderServer(t, NewIdempotencyCache(time.Hour), 0, nil)
+	if r := postOrder(t, srv, "key-1", strings.Repeat("x", MaxIdempotentBody+1)); r.code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body over the limit: %d, want 413", r.code)
+	}
+	if r := postOrder(t, srv, "key-2", strings.Repeat("x", MaxIdempotentBody)); r.code != http.StatusCreated {
+		t.Errorf("body at the limit: %d, want 201", r.code)
+	}
+	if runs.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", runs.Load())
+	}
+}
+
+func TestNewIdempotencyCacheRejects(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewIdempotencyCache(%v) didn't panic", ttl)
+				}
+			}()
+			NewIdempotencyCache(ttl)
+		}()
+	}
+}
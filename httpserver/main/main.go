@@ -0,0 +1,490 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"httpserver"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// === Ex. An HTTP JSON API with net/http ===
+
+// Handlers, a ServeMux, middleware, and testing them all with net/http/httptest (the tests themselves
+// are in httpserver_test.go and middleware_test.go):
+// - httptest.NewRecorder is a ResponseWriter that keeps what was written - call ServeHTTP on it directly,
+//   no network involved
+// - httptest.NewServer runs a handler on a real local port, for going through an actual http.Client
+
+const userId1, userId2 = "1d02455e-f24c-4c26-90d2-f1073c686314", "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func seedStore() *httpserver.Store {
+	return httpserver.NewStore(
+		httpserver.User{UserId: userId1, Name: "John Doe"},
+		httpserver.User{UserId: userId2, Name: "Jack Eod"},
+	)
+}
+
+// serve sends one request straight to h, and returns the recorded response
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, r)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// --- Handlers and HandlerFunc ---
+
+// A plain func with the handler signature...
+func hello(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "gopher"
+	}
+	fmt.Fprintf(w, "hello, %s\n", name)
+}
+
+// ...and a type with a ServeHTTP method - a struct handler can hold state (here, a hit counter)
+type hitCounter struct {
+	hits atomic.Int64 // requests run concurrently, so the counter must be safe for that
+}
+
+func (c *hitCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "hit %d\n", c.hits.Add(1))
+}
+
+func handlerFuncExample() {
+	var h http.Handler = http.HandlerFunc(hello) // a conversion, not a call: HandlerFunc's ServeHTTP calls hello
+	fmt.Print(serve(h, "GET", "/?name=ferris", "").Body.String())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hello", hello) // HandleFunc does the conversion
+	mux.Handle("/hits", &hitCounter{})  // no method in the pattern: any method
+	serve(mux, "GET", "/hits", "")
+	fmt.Print("/hits twice: ", serve(mux, "POST", "/hits", "").Body.String())
+	rec := serve(mux, "POST", "/hello", "")
+	fmt.Println("POST /hello:", rec.Code, "| Allow:", rec.Header().Get("Allow"), "| GET /nope:", serve(mux, "GET", "/nope", "").Code)
+}
+
+// --- The users API ---
+
+func apiExample() {
+	next := 0
+	srv := httpserver.New(seedStore()).WithIDs(func() string { next++; return fmt.Sprintf("new-%d", next) })
+
+	cases := []struct {
+		name, method, target, body string
+	}{
+		{"get one", "GET", "/users/" + userId1, ""},
+		{"get missing", "GET", "/users/nobody", ""},
+		{"create", "POST", "/users", `{"name": "Ferris"}`},
+		{"create, name padded", "POST", "/users", `{"name": "  Gopher "}`},
+		{"create, no name", "POST", "/users", `{"name": "  "}`},
+		{"create, unknown field", "POST", "/users", `{"nmae": "Ferris"}`},
+		{"create, two values", "POST", "/users", `{"name": "a"} {"name": "b"}`},
+		{"wrong method", "DELETE", "/users/" + userId1, ""},
+		{"list after creating", "GET", "/users", ""},
+	}
+	for _, tc := range cases {
+		rec := serve(srv, tc.method, tc.target, tc.body)
+		fmt.Printf("  %-22s %-6s %-45s %d %s\n", tc.name, tc.method, tc.target, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+
+	rec := serve(srv, "POST", "/users", `{"name": "Carol"}`)
+	fmt.Println("Location:", rec.Header().Get("Location"), "| Content-Type:", rec.Header().Get("Content-Type"))
+	var users []httpserver.User
+	json.Unmarshal(serve(srv, "GET", "/users", "").Body.Bytes(), &users)
+	fmt.Println("users now:", len(users), "| last added:", users[len(users)-1].Name)
+
+	empty := serve(httpserver.New(httpserver.NewStore()), "GET", "/users", "")
+	fmt.Printf("an empty list is %q, not null\n", strings.TrimSpace(empty.Body.String()))
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name": "x"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	fmt.Println("text/plain body:", rec.Code)
+	rec = serve(srv, "POST", "/users", `{"name": "`+strings.Repeat("x", 2<<20)+`"}`)
+	fmt.Println("2 MB body:", rec.Code, strings.TrimSpace(rec.Body.String()))
+	// httpserver_test.go has the full table of requests, and concurrent ones
+}
+
+// --- Middleware ---
+
+// tag is a middleware that notes when the request goes in and the response comes out
+func tag(name string, trace *[]string) httpserver.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*trace = append(*trace, name+" in")
+			next.ServeHTTP(w, r)
+			*trace = append(*trace, name+" out")
+		})
+	}
+}
+
+func middlewareExample() {
+	var trace []string
+	h := httpserver.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "handler")
+	}), tag("a", &trace), tag("b", &trace))
+	serve(h, "GET", "/", "")
+	fmt.Println("Chain(h, a, b):", trace)
+
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	slogger := slog.New(slog.NewTextHandler(&logs, nil))
+	mux := http.NewServeMux()
+	mux.Handle("/", httpserver.New(seedStore()))
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		var users map[string]httpserver.User
+		users["x"] = httpserver.User{} // assignment to a nil map - panics
+	})
+	h = httpserver.Chain(mux, httpserver.Logging(logger), httpserver.Recover(slogger))
+
+	serve(h, "GET", "/users", "")
+	serve(h, "GET", "/users/nobody", "")
+	serve(h, "POST", "/users", `{"name": "Ferris"}`)
+	rec := serve(h, "GET", "/panic", "")
+	fmt.Println("a panicking handler:", rec.Code, strings.TrimSpace(rec.Body.String()))
+
+	// The log, without the stack trace lines
+	for _, l := range strings.Split(logs.String(), "\n") {
+		if method, _, _ := strings.Cut(l, " "); method == "GET" || method == "POST" {
+			fmt.Println("  log:", l)
+		} else if start, _, ok := strings.Cut(l, " stack="); ok {
+			_, start, _ = strings.Cut(start, " ") // without the time
+			fmt.Println("  log:", start, "stack=...")
+		}
+	}
+	// middleware_test.go checks the order, the logged statuses and sizes, and what Recover logs
+}
+
+// --- Rate limiting, per client ---
+
+// quota lets each key make n requests, then refuses it until reset - a Limiter small enough for the example.
+// The real one is concurrency's KeyedLimiter[string]: a token bucket per key that refills, and
+// httpserver.RateLimit takes it as is
+type quota struct {
+	n    int
+	mu   sync.Mutex
+	used map[string]int
+}
+
+func (q *quota) Allow(key string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.used[key] == q.n {
+		return false
+	}
+	q.used[key]++
+	return true
+}
+
+func (q *quota) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	clear(q.used)
+}
+
+func rateLimitMiddlewareExample() {
+	lim := &quota{n: 2, used: map[string]int{}}
+	hello := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "hello", r.Header.Get("X-User"))
+	})
+	h := httpserver.RateLimit(lim, 500*time.Millisecond, func(r *http.Request) string { return r.Header.Get("X-User") })(hello)
+
+	call := func(user string) string {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-User", user)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code == http.StatusTooManyRequests {
+			return "429 (retry after " + rec.Header().Get("Retry-After") + "s)"
+		}
+		return strconv.Itoa(rec.Code)
+	}
+	fmt.Println("alice:", call("alice"), call("alice"), call("alice"))
+	fmt.Println("bob (not affected by alice):", call("bob"))
+	lim.reset() // as the buckets refilling would
+	fmt.Println("alice after a refill:", call("alice"), call("alice"))
+	// ratelimit_test.go checks the statuses, the Retry-After rounding, and that a refused request never
+	// reaches the handler
+}
+
+// --- Idempotency keys ---
+
+// fakeClock is a time.Now that only moves when told to
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func idempotencyExample() {
+	// (the checks are in idempotency_test.go - every copy getting the same response over a real connection among them)
+	clock := &fakeClock{}
+	cache := httpserver.NewIdempotencyCache(time.Hour).WithClock(clock.Now)
+
+	// createOrder is slow enough that concurrent duplicates really overlap with it
+	var orders, executions atomic.Int32
+	createOrder := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executions.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		item, _ := io.ReadAll(r.Body)
+		if string(item) == "flaky" {
+			http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "order %d: %s", orders.Add(1), item)
+	})
+	handler := httpserver.Idempotency(cache)(createOrder)
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// --- Concurrent duplicates ---
+
+	// 20 copies of the same request at once (ex. a client retrying aggressively through a proxy)
+	const copies = 20
+	responses := make([]*httptest.ResponseRecorder, copies)
+	var wg sync.WaitGroup
+	for i := range copies {
+		wg.Go(func() { responses[i] = post("key-1", "1 gopher plush") })
+	}
+	wg.Wait()
+	replayed := 0
+	for _, r := range responses {
+		if r.Header().Get("Idempotent-Replayed") == "true" {
+			replayed++
+		}
+	}
+	fmt.Printf("%d concurrent duplicates - handler ran: %d | first got %q | replayed: %d\n",
+		copies, executions.Load(), responses[0].Body.String(), replayed)
+
+	// A later retry, and a new key
+	fmt.Printf("retry later:  %d %q\n", post("key-1", "1 gopher plush").Code, post("key-1", "1 gopher plush").Body.String())
+	fmt.Printf("new key:      %d %q\n", post("key-2", "2 stickers").Code, post("key-2", "2 stickers").Body.String())
+
+	// The same key on a different request is refused, not replayed
+	fmt.Println("key reused with another body:", post("key-1", "3 mugs").Code)
+
+	// No key - no deduplication
+	before := executions.Load()
+	post("", "a pen")
+	post("", "a pen")
+	fmt.Println("2 POSTs without a key, handler runs:", executions.Load()-before)
+
+	// --- Server errors aren't kept, so the retry runs again ---
+
+	before = executions.Load()
+	first, second := post("key-3", "flaky"), post("key-3", "flaky")
+	fmt.Println("5xx:", first.Code, second.Code, "| handler runs:", executions.Load()-before)
+
+	// A body too big to fingerprint is refused, rather than cut short
+	big := post("key-4", strings.Repeat("x", httpserver.MaxIdempotentBody+1))
+	fmt.Println("body over the limit:", big.Code)
+
+	// --- Expiry ---
+
+	fmt.Println("entries kept:", cache.Len())
+	clock.Advance(time.Hour)
+	before = executions.Load()
+	r := post("key-1", "1 gopher plush")
+	fmt.Printf("after the ttl, key-1 runs again: %d run (%q) | entries kept: %d\n", executions.Load()-before, r.Body.String(), cache.Len())
+}
+
+// --- Recover, over a real connection: what the client sees ---
+
+func recoverMiddlewareExample() {
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /early", func(w http.ResponseWriter, r *http.Request) {
+		var users []httpserver.User
+		_ = users[3] // before anything is written
+	})
+	mux.HandleFunc("GET /late", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte(`{"users": [`))
+		http.NewResponseController(w).Flush() // the 200 and the start of the body are sent...
+		panic("lost the database connection") // ...and then this
+	})
+	mux.HandleFunc("GET /abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler) // aborting on purpose - not a bug to log
+	})
+
+	get := func(h http.Handler, path string) string {
+		ts := httptest.NewServer(h)
+		defer ts.Close()
+		ts.Config.ErrorLog = log.New(io.Discard, "", 0) // net/http's own "panic serving" lines
+		resp, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			return "error: " + err.Error()[strings.LastIndex(err.Error(), ": ")+2:]
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Sprintf("%s, then reading the body: %v", resp.Status, err)
+		}
+		return resp.Status + " " + strings.TrimSpace(string(body))
+	}
+	withRecover := httpserver.Recover(slog.New(slog.NewTextHandler(&logs, nil)))(mux)
+	for _, path := range []string{"/early", "/late", "/abort"} {
+		fmt.Printf("%-6s without Recover: %s\n       with Recover:    %s\n", path, get(mux, path), get(withRecover, path))
+	}
+	fmt.Println("panics logged:", strings.Count(logs.String(), `msg="handler panicked"`), "(the abort isn't one)")
+	// Without Recover, net/http recovers for you - by closing the connection, so the client learns nothing.
+	// With it, a panic before the first Write is a proper 500; one after still has to cut the connection,
+	// since a 200 has gone out already - but the client sees the body was cut short, instead of trusting it
+}
+
+// --- Over a real connection ---
+
+func serverExample() {
+	var logs bytes.Buffer
+	logger := log.New(&logs, "", 0)
+	ts := httptest.NewServer(httpserver.Chain(httpserver.New(seedStore()), httpserver.Logging(logger), httpserver.Recover(slog.New(slog.NewTextHandler(&logs, nil)))))
+	defer ts.Close()
+	fmt.Println("serving on", ts.URL)
+
+	resp, err := ts.Client().Post(ts.URL+"/users", "application/json", strings.NewReader(`{"name": "Ferris"}`))
+	if err != nil {
+		fmt.Println("post:", err)
+		return
+	}
+	var created httpserver.User
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	fmt.Println("POST /users:", resp.Status, "| id:", created.UserId)
+
+	resp, err = ts.Client().Get(ts.URL + resp.Header.Get("Location"))
+	if err != nil {
+		fmt.Println("get:", err)
+		return
+	}
+	var fetched httpserver.User
+	json.NewDecoder(resp.Body).Decode(&fetched)
+	resp.Body.Close()
+	fmt.Println("GET", "/users/"+created.UserId+":", resp.Status, fetched.Name)
+	fmt.Println("requests logged:", strings.Count(logs.String(), "\n"))
+}
+
+// --- Graceful shutdown ---
+
+// concurrency's gracefulShutdownExample drains a worker pool on SIGINT; a server is drained the same way.
+// http.Server.Shutdown closes the listeners (no new connections), closes idle connections, and waits for the
+// requests in progress to finish - or for its context to expire. Serve returns http.ErrServerClosed
+// straight away, so the waiting is done by Shutdown, not by the goroutine running Serve
+
+func gracefulServerShutdownExample() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var served atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "requests served: %d\n", served.Add(1))
+	})
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond) // still running when the signal arrives
+		served.Add(1)
+		fmt.Fprintln(w, "slow request finished")
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0") // port 0 - any free port
+	if err != nil {
+		fmt.Println("listen:", err)
+		return
+	}
+	srv := &http.Server{Handler: mux}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ln) }()
+	base := "http://" + ln.Addr().String()
+	fmt.Println("listening on", base, "- press Ctrl+C to stop")
+
+	get := func(path string) string {
+		resp, err := http.Get(base + path)
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Sprintf("%d %s", resp.StatusCode, body)
+	}
+	fmt.Print("GET /status: ", get("/status"))
+
+	// A request that is still in flight when the signal arrives. Then, unless Ctrl+C came first,
+	// the program interrupts itself - p.Signal(os.Interrupt) is the same as pressing Ctrl+C
+	slow := make(chan string, 1)
+	go func() { slow <- get("/slow") }()
+	time.AfterFunc(100*time.Millisecond, func() {
+		p, _ := os.FindProcess(os.Getpid())
+		p.Signal(os.Interrupt)
+	})
+
+	<-ctx.Done()
+	stop() // from here on, a second Ctrl+C kills the process
+	start := time.Now()
+	fmt.Println("\nsignal received:", context.Cause(ctx))
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(drainCtx); err != nil {
+		fmt.Println("shutdown:", err) // drainCtx expired with requests still running
+	}
+	fmt.Println("Serve returned:", <-serveErr)
+	fmt.Print("in-flight GET /slow: ", <-slow)
+	_, err = http.Get(base + "/status")
+	fmt.Println("after Shutdown, a new connection is refused:", errors.Is(err, syscall.ECONNREFUSED))
+	fmt.Printf("drained in %v, %d requests served\n", time.Since(start).Round(10*time.Millisecond), served.Load())
+}
+
+func main() {
+	handlerFuncExample()
+	// apiExample()
+	// middlewareExample()
+	// serverExample()
+	// recoverMiddlewareExample()
+	// rateLimitMiddlewareExample()
+	// idempotencyExample()
+	// gracefulServerShutdownExample()
+}
@@ -0,0 +1,98 @@
+package httpserver
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware wraps a handler in another: it can do something before (check a header, start a timer),
+// call next - or not, and answer itself - then do something after (log the status).
+type Middleware func(next http.Handler) http.Handler
+
+// Chain wraps h in the middleware, the first one outermost: Chain(h, a, b) is a(b(h)),
+// so a request goes through a, then b, then h - the order they're listed in
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// statusRecorder is a ResponseWriter that remembers the status and size of what went through it.
+// A handler that never calls WriteHeader sends 200 with its first Write
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the real ResponseWriter (for Flush, deadlines...) past the wrapper
+func (r *statusRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// Logging logs one line per request: method, path, status, bytes and how long it took
+func Logging(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 { // nothing written at all - net/http sends 200
+				rec.status = http.StatusOK
+			}
+			logger.Printf("%s %s %d %dB %v", r.Method, r.URL.Path, rec.status, rec.bytes, time.Since(start).Round(time.Microsecond))
+		})
+	}
+}
+
+// Recover turns a panic in a handler into a 500, and logs it to logger as an error, with its stack.
+// net/http would recover it too, but only by closing the connection - the client gets no response at all.
+// http.ErrAbortHandler is left alone: it's the way to abort a response on purpose.
+// Put it inside Logging, so the logged status is the 500.
+// (concurrency/internal/recovered is the same pattern for goroutines that aren't handlers - a panic in a
+// goroutine a handler starts isn't caught here, and ends the whole server)
+func Recover(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				logger.LogAttrs(r.Context(), slog.LevelError, "handler panicked",
+					slog.String("method", r.Method), slog.String("path", r.URL.Path),
+					slog.Any("panic", v), slog.String("stack", string(debug.Stack())))
+				if rec.status != 0 {
+					// The status is already sent, and a 500 can't replace it. Cutting the connection is the
+					// only way left to tell the client the response is broken - rather than have it take
+					// half a body as the whole one
+					panic(http.ErrAbortHandler)
+				}
+				writeError(w, http.StatusInternalServerError, "internal error")
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
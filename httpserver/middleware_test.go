@@ -0,0 +1,148 @@
+package httpserver
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// tag is a middleware that notes when the request goes in and the response comes out
+func tag(name string, trace *[]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*trace = append(*trace, name+" in")
+			next.ServeHTTP(w, r)
+			*trace = append(*trace, name+" out")
+		})
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var trace []string
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "handler")
+	}), tag("a", &trace), tag("b", &trace))
+	serve(h, "GET", "/", "", "")
+	if want := []string{"a in", "b in", "handler", "b out", "a out"}; !slices.Equal(trace, want) {
+		t.Errorf("Chain(h, a, b) ran %v, want %v", trace, want)
+	}
+
+	trace = nil
+	serve(Chain(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { trace = append(trace, "handler") })), "GET", "/", "", "")
+	if !slices.Equal(trace, []string{"handler"}) {
+		t.Errorf("Chain with no middleware ran %v", trace)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	mux.Handle("/", New(seedStore()))
+	mux.HandleFunc("GET /silent", func(http.ResponseWriter, *http.Request) {}) // writes nothing: a 200
+	mux.HandleFunc("GET /teapot", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.WriteHeader(http.StatusOK) // too late - ignored, and not what's logged
+		io.WriteString(w, "short and stout")
+	})
+	h := Chain(mux, Logging(log.New(&logs, "", 0)))
+
+	serve(h, "GET", "/users/nobody", "", "")
+	serve(h, "POST", "/users", "application/json", `{"name": "Ferris"}`)
+	serve(h, "GET", "/silent", "", "")
+	serve(h, "GET", "/teapot", "", "")
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	for i, want := range []string{"GET /users/nobody 404 ", "POST /users 201 ", "GET /silent 200 0B ", "GET /teapot 418 15B "} {
+		if i >= len(lines) || !strings.HasPrefix(lines[i], want) {
+			t.Errorf("log line %d: %q, want it to start %q", i, lines, want)
+		}
+	}
+}
+
+func TestStatusRecorderUnwrap(t *testing.T) {
+	flushed := false
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flushed = http.NewResponseController(w).Flush() == nil // reaches the recorder underneath both wrappers
+	}), Logging(log.New(io.Discard, "", 0)), Recover(slog.New(slog.DiscardHandler)))
+	rec := serve(h, "GET", "/", "", "")
+	if !flushed || !rec.Flushed {
+		t.Error("Flush through the middleware didn't reach the ResponseWriter")
+	}
+}
+
+// A panic before anything is written is a 500, logged with its stack - and Logging, outside, sees the 500
+func TestRecover(t *testing.T) {
+	var logs bytes.Buffer
+	h := Chain(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		var users map[string]User
+		users["x"] = User{} // assignment to a nil map - panics
+	}), Logging(log.New(&logs, "", 0)), Recover(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	rec := serve(h, "GET", "/panic", "", "")
+	if rec.Code != 500 || strings.TrimSpace(rec.Body.String()) != `{"error":"internal error"}` {
+		t.Errorf("panicking handler: %d %s, want a 500 JSON error", rec.Code, rec.Body)
+	}
+	for _, want := range []string{`msg="handler panicked"`, "path=/panic", "assignment to entry in nil map", "runtime/debug.Stack", "GET /panic 500"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log doesn't contain %q:\n%s", want, logs.String())
+		}
+	}
+}
+
+// get fetches path from h over a real connection, and what the client makes of the answer
+func get(t *testing.T, h http.Handler, path string) (status int, body string, err error) {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0) // net/http's own "panic serving" lines
+	resp, err := ts.Client().Get(ts.URL + path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b), err
+}
+
+func TestRecoverOverAConnection(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /early", func(http.ResponseWriter, *http.Request) {
+		panic("before anything is written")
+	})
+	mux.HandleFunc("GET /late", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte(`{"users": [`))
+		http.NewResponseController(w).Flush() // the 200 and the start of the body are sent...
+		panic("lost the database connection") // ...and then this
+	})
+	mux.HandleFunc("GET /abort", func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	var logs bytes.Buffer
+	h := Recover(slog.New(slog.NewTextHandler(&logs, nil)))(mux)
+
+	if status, body, err := get(t, h, "/early"); status != 500 || err != nil || !strings.Contains(body, "internal error") {
+		t.Errorf("/early: %d %q %v, want a 500", status, body, err)
+	}
+	// the 200 is out already: the connection is cut, so the client sees a short body, not a whole one
+	if status, body, err := get(t, h, "/late"); status != 200 || err == nil {
+		t.Errorf("/late: %d %q %v, want the 200 and then an error reading the body", status, body, err)
+	}
+	if _, _, err := get(t, h, "/abort"); err == nil {
+		t.Error("/abort: got a response, want the connection closed")
+	}
+	if n := strings.Count(logs.String(), `msg="handler panicked"`); n != 2 {
+		t.Errorf("%d panics logged, want 2 - ErrAbortHandler isn't a bug to log:\n%s", n, logs.String())
+	}
+
+	// without Recover the client gets nothing at all for the early panic
+	if _, _, err := get(t, mux, "/early"); err == nil {
+		t.Error("/early without Recover: got a response")
+	}
+}
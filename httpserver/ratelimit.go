@@ -0,0 +1,31 @@
+package httpserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Limiter says whether the client with key may make a request now.
+// concurrency's KeyedLimiter[string] (concurrency/ratelimit_keyed.go) is one: a token bucket per key, with idle
+// buckets evicted. The modules don't import each other, so RateLimit only asks for the method
+type Limiter interface {
+	Allow(key string) bool
+}
+
+// RateLimit answers 429 Too Many Requests to the requests l refuses, keyed by clientID - a user ID, an API key.
+// retryAfter goes in the Retry-After header, rounded up to whole seconds: a client that waits that long
+// has at least one request again
+func RateLimit(l Limiter, retryAfter time.Duration, clientID func(*http.Request) string) Middleware {
+	seconds := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.Allow(clientID(r)) {
+				w.Header().Set("Retry-After", seconds)
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// quota lets each key make n requests, and refuses it after that
+type quota struct {
+	n    int
+	mu   sync.Mutex
+	used map[string]int
+}
+
+func (q *quota) Allow(key string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.used[key] == q.n {
+		return false
+	}
+	q.used[key]++
+	return true
+}
+
+func TestRateLimit(t *testing.T) {
+	lim := &quota{n: 2, used: map[string]int{}}
+	ran := 0
+	h := RateLimit(lim, 1500*time.Millisecond, func(r *http.Request) string { return r.Header.Get("X-User") })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { ran++ }))
+	call := func(user string) (int, string, string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-User", user)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code, rec.Header().Get("Retry-After"), rec.Body.String()
+	}
+
+	for i := range 2 {
+		if code, _, _ := call("alice"); code != http.StatusOK {
+			t.Errorf("alice request %d: %d, want 200", i+1, code)
+		}
+	}
+	code, retry, body := call("alice")
+	if code != http.StatusTooManyRequests || retry != "2" || body != `{"error":"rate limit exceeded"}`+"\n" {
+		t.Errorf("alice request 3: %d, Retry-After %q, %q - want 429, 2 (1.5s rounded up) and the JSON error", code, retry, body)
+	}
+	if code, _, _ := call("bob"); code != http.StatusOK {
+		t.Errorf("bob: %d, want 200 - alice's limit must not affect bob", code)
+	}
+	if ran != 3 {
+		t.Errorf("the handler ran %d times, want 3 - never for a refused request", ran)
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{{time.Second, "1"}, {time.Second + time.Nanosecond, "2"}, {100 * time.Millisecond, "1"}, {time.Minute, "60"}} {
+		h := RateLimit(&quota{used: map[string]int{}}, tt.d, func(*http.Request) string { return "" })(http.NotFoundHandler())
+		if got := serve(h, "GET", "/", "", "").Header().Get("Retry-After"); got != tt.want {
+			t.Errorf("retryAfter %v: Retry-After %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module jsonpkg
+
+go 1.25.0
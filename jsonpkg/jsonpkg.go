@@ -0,0 +1,97 @@
+package jsonpkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Two helpers for JSON whose shape isn't fixed in advance, or is too big to hold:
+// - Walk visits every value of JSON decoded into an any, with a type switch over the types
+//   encoding/json produces for it (the Type switches notes in methodsinterfaces.go)
+// - Elements decodes a top-level JSON array one element at a time, so a file of a million records
+//   is read with one record in memory (basics/jsonstream writes such an array the same way)
+
+// Decoded into an any (or a map[string]any, or a []any), JSON becomes one of these Go types:
+//
+//	null -> nil    true/false -> bool    numbers -> float64 (json.Number with Decoder.UseNumber)
+//	"text" -> string    [...] -> []any    {...} -> map[string]any
+
+// Walk calls visit for v and every value inside it, depth first, with the path to it (ex. "$.users[0].name").
+// Object keys are visited in sorted order - map order is random, and the paths should come out the same each time
+func Walk(v any, visit func(path string, v any)) {
+	walk("$", v, visit)
+}
+
+func walk(path string, v any, visit func(string, any)) {
+	visit(path, v)
+	switch v := v.(type) {
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			walk(path+"."+k, v[k], visit)
+		}
+	case []any:
+		for i, e := range v {
+			walk(path+"["+strconv.Itoa(i)+"]", e, visit)
+		}
+	}
+}
+
+// Kind names the JSON type of a decoded value
+func Kind(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return fmt.Sprintf("array(%d)", len(v))
+	case map[string]any:
+		return fmt.Sprintf("object(%d)", len(v))
+	default:
+		return fmt.Sprintf("not from JSON: %T", v) // ex. a map[string]string built by hand
+	}
+}
+
+var ErrNotArray = errors.New("not a JSON array")
+
+// Elements decodes r's top-level array element by element, as a T each. The sequence ends at the closing ]
+// or at the first error, which is yielded (with a zero T) - a bad element stops the stream, since the
+// decoder can't know where the next element starts. Breaking out of the loop early reads no further
+func Elements[T any](r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		dec := json.NewDecoder(r)
+		tok, err := dec.Token() // the opening [ - Token reads one delimiter, string, number... at a time
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if tok != json.Delim('[') {
+			yield(zero, fmt.Errorf("%w: starts with %v", ErrNotArray, tok))
+			return
+		}
+		for i := 0; dec.More(); i++ { // More: is there another element before the ]
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				yield(zero, fmt.Errorf("element %d (at byte %d): %w", i, dec.InputOffset(), err))
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if _, err := dec.Token(); err != nil { // the closing ] - without it the array was cut off
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"jsonpkg"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// === Ex. encoding/json in depth ===
+
+// json.Marshal turns a Go value into JSON, json.Unmarshal the other way. For structs, only EXPORTED
+// fields are seen (the package can't reach the others), and struct tags control the rest:
+//
+//	`json:"name"`            the key is "name" instead of the field name
+//	`json:"name,omitempty"`  left out when the value is empty: false, 0, "", nil, and empty slices/maps
+//	`json:",omitzero"`       left out when the value is its zero value (Go 1.24+) - works for structs like time.Time,
+//	                         which are never "empty" to omitempty
+//	`json:"id,string"`       a number or bool sent as a JSON string ("42") - for ids too big for a JavaScript number
+//	`json:"-"`               never encoded or decoded
+//
+// Unmarshal matches keys to fields by the tag name, or the field name, ignoring case.
+
+// User is the basics notes' User, made into an API record
+type User struct {
+	UserId   string    `json:"userId"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email,omitempty"`
+	Age      int       `json:"age,omitempty"` // omitempty on a number: 0 isn't sent - so 0 can't be sent at all
+	Score    *int      `json:"score"`         // a pointer: null when nil, and "missing" is different from 0
+	Tags     []string  `json:"tags"`
+	Created  time.Time `json:"created,omitzero"`
+	Sequence int64     `json:"seq,string"`
+	Password string    `json:"-"`
+	internal string    // unexported - invisible to encoding/json
+}
+
+func marshalIndent(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(b)
+}
+
+func tagsExample() {
+	score := 0
+	u := User{
+		UserId: "1d02455e-f24c-4c26-90d2-f1073c686314", Name: "John Doe",
+		Score: &score, Sequence: 9007199254740993, Password: "hunter2", internal: "x",
+	}
+	b, _ := json.Marshal(u)
+	fmt.Println(string(b))
+	s := string(b)
+	fmt.Println("no email, age or created:", !strings.Contains(s, "email") && !strings.Contains(s, "age") && !strings.Contains(s, "created"),
+		"| a score of 0 is kept (a non-nil pointer):", strings.Contains(s, `"score":0`),
+		"| nil tags are null:", strings.Contains(s, `"tags":null`),
+		"| no password:", !strings.Contains(s, "hunter2"))
+
+	u.Created = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	u.Tags = []string{}
+	u.Score = nil
+	fmt.Println(marshalIndent(u))
+
+	// Decoding: keys match case-insensitively, unknown keys are ignored, the ,string field wants a string
+	var back User
+	err := json.Unmarshal([]byte(`{"USERID": "42", "name": "Ferris", "seq": "7", "unknown": true, "password": "x"}`), &back)
+	fmt.Printf("decoded: %+v, err: %v\n", back, err)
+	err = json.Unmarshal([]byte(`{"seq": 7}`), &back)
+	fmt.Println("seq as a bare number:", err)
+}
+
+// --- Pointer vs value fields ---
+
+// Partial updates ("PATCH"): which fields did the client send? With value fields, an absent key and a zero
+// value look the same after decoding. Pointer fields tell them apart: nil means the key wasn't there
+// (or was null), a non-nil pointer the value sent - even 0 or ""
+type userPatch struct {
+	Name  *string `json:"name"`
+	Age   *int    `json:"age"`
+	Email *string `json:"email"`
+}
+
+func applyPatch(u User, p userPatch) User {
+	if p.Name != nil {
+		u.Name = *p.Name
+	}
+	if p.Age != nil {
+		u.Age = *p.Age
+	}
+	if p.Email != nil {
+		u.Email = *p.Email
+	}
+	return u
+}
+
+func pointerExample() {
+	u := User{Name: "John Doe", Age: 35, Email: "john@example.com"}
+
+	var p userPatch
+	json.Unmarshal([]byte(`{"age": 0, "email": ""}`), &p)
+	fmt.Println("name sent:", p.Name != nil, "| age sent:", p.Age != nil, "| email sent:", p.Email != nil)
+	patched := applyPatch(u, p)
+	fmt.Printf("patched: name %q, age %d, email %q\n", patched.Name, patched.Age, patched.Email)
+
+	// Unmarshal into a value that already has data: only the keys in the JSON are written, the rest is kept
+	existing := u
+	json.Unmarshal([]byte(`{"name": "Jack Eod"}`), &existing)
+	fmt.Printf("into an existing value: name %q, age %d (kept)\n", existing.Name, existing.Age)
+
+	// Unmarshal needs a pointer - it has to write somewhere. go vet flags json.Unmarshal(data, u) when it can see
+	// u isn't a pointer; behind an any it can't, and the error comes at run time
+	var notPointer any = u
+	err := json.Unmarshal([]byte(`{"name": "x"}`), notPointer)
+	var invalid *json.InvalidUnmarshalError
+	fmt.Println("Unmarshal into a non-pointer:", err, "| InvalidUnmarshalError:", errors.As(err, &invalid))
+
+	// Receivers matter for custom marshalling: a MarshalJSON on the pointer type isn't used for a plain value
+	type wrapper struct {
+		ByValue   celsius
+		ByPointer *celsius
+	}
+	c := celsius(21.5)
+	b, _ := json.Marshal(wrapper{ByValue: c, ByPointer: &c})
+	fmt.Println("value receiver MarshalJSON, for both:", string(b))
+}
+
+// celsius encodes as a string with its unit
+type celsius float64
+
+func (c celsius) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%.1f°C", float64(c)))
+}
+
+// --- Nested and embedded structs ---
+
+type address struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type auditFields struct {
+	CreatedBy string `json:"createdBy"`
+	Version   int    `json:"version"`
+}
+
+type customer struct {
+	User                  // embedded, without a tag: its fields are promoted to the outer object
+	auditFields           // unexported embedded type - its exported fields are still promoted
+	Address     address   `json:"address"`            // a named field: a nested object
+	Previous    []address `json:"previous,omitempty"` // an array of objects
+	Notes       struct {  // an anonymous struct type works too
+		Text string `json:"text"`
+	} `json:"notes"`
+}
+
+func nestedExample() {
+	c := customer{
+		User:        User{UserId: "c-1", Name: "Ferris"},
+		auditFields: auditFields{CreatedBy: "admin", Version: 3},
+		Address:     address{City: "Oslo", Country: "NO"},
+		Previous:    []address{{City: "Bergen", Country: "NO"}},
+	}
+	c.Notes.Text = "prefers email"
+	out := marshalIndent(c)
+	fmt.Println(out)
+	fmt.Println("embedded fields are flat:", strings.Contains(out, `"createdBy": "admin"`) && !strings.Contains(out, `"User"`))
+
+	var back customer
+	err := json.Unmarshal([]byte(out), &back)
+	fmt.Println("round trip:", err == nil && back.Name == "Ferris" && back.Version == 3 && back.Address.City == "Oslo" && back.Previous[0].City == "Bergen")
+}
+
+// --- Dynamic JSON: map[string]any and type switches ---
+
+const webhook = `{
+	"event": "user.created",
+	"attempt": 2,
+	"user": {"userId": "1d02455e", "name": "John Doe", "tags": ["admin", "beta"], "manager": null, "active": true},
+	"id": 9007199254740993
+}`
+
+func dynamicExample() {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(webhook), &doc); err != nil {
+		fmt.Println("unmarshal:", err)
+		return
+	}
+	jsonpkg.Walk(doc, func(path string, v any) {
+		switch v.(type) {
+		case map[string]any, []any: // what's in them comes next
+			fmt.Printf("  %-22s %s\n", path, jsonpkg.Kind(v))
+		default:
+			fmt.Printf("  %-22s %-10s %v\n", path, jsonpkg.Kind(v), v)
+		}
+	})
+
+	// Getting at a value means asserting each level - with the ok form, since the JSON could be anything
+	if user, ok := doc["user"].(map[string]any); ok {
+		name, _ := user["name"].(string)
+		tags, _ := user["tags"].([]any)
+		fmt.Println("user name:", name, "| tags:", len(tags))
+	}
+	// Every number is a float64 - attempt is 2.0, and an id over 2^53 loses its last digits
+	attempt := doc["attempt"].(float64)
+	fmt.Printf("attempt: %v (%T) | id: %.0f - not 9007199254740993\n", attempt, attempt, doc["id"])
+
+	// UseNumber keeps numbers as json.Number, the exact text - converted when needed
+	dec := json.NewDecoder(strings.NewReader(webhook))
+	dec.UseNumber()
+	var exact map[string]any
+	dec.Decode(&exact)
+	id, err := exact["id"].(json.Number).Int64()
+	fmt.Println("with UseNumber, id:", id, err, "| exact:", id == 9007199254740993)
+
+	// A type switch over what an untyped field can hold
+	for _, raw := range []string{`"7"`, `7`, `[7, 8]`, `{"n": 7}`, `null`} {
+		var v any
+		json.Unmarshal([]byte(raw), &v)
+		switch v := v.(type) {
+		case string:
+			fmt.Printf("  %-10s a string, %d bytes\n", raw, len(v))
+		case float64:
+			fmt.Printf("  %-10s a number, twice is %v\n", raw, v*2)
+		case []any:
+			fmt.Printf("  %-10s an array of %d\n", raw, len(v))
+		case map[string]any:
+			fmt.Printf("  %-10s an object with %d key(s)\n", raw, len(v))
+		case nil:
+			fmt.Printf("  %-10s null\n", raw)
+		}
+	}
+}
+
+// --- Streaming a large array ---
+
+// userArray writes a JSON array of n users to w, one at a time (so it's never all in memory either)
+func userArray(w io.Writer, n int) {
+	io.WriteString(w, "[")
+	enc := json.NewEncoder(w)
+	for i := range n {
+		if i > 0 {
+			io.WriteString(w, ",")
+		}
+		enc.Encode(User{UserId: fmt.Sprint("user-", i), Name: fmt.Sprint("User ", i), Tags: []string{"a", "b"}})
+	}
+	io.WriteString(w, "]")
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapInuse
+}
+
+func streamExample() {
+	const n = 200_000
+	pr, pw := io.Pipe() // the array is produced while it's read - like a response body or a file
+	go func() {
+		userArray(pw, n)
+		pw.Close()
+	}()
+
+	before := heapInUse()
+	count, names := 0, 0
+	var peak uint64
+	for u, err := range jsonpkg.Elements[User](pr) {
+		if err != nil {
+			fmt.Println("stream:", err)
+			return
+		}
+		count++
+		names += len(u.Name)
+		if count%50_000 == 0 {
+			peak = max(peak, heapInUse())
+		}
+	}
+	fmt.Println("decoded", count, "users, one at a time | all there:", count == n)
+
+	// All at once, for comparison - Unmarshal needs the whole document and the whole slice in memory
+	var buf bytes.Buffer
+	userArray(&buf, n)
+	var all []User
+	json.Unmarshal(buf.Bytes(), &all)
+	held := heapInUse()
+	fmt.Printf("heap above the start - streaming: %d KB at most | json.Unmarshal: %d MB (%d MB of JSON plus the slice of %d)\n",
+		(peak-min(before, peak))>>10, (held-min(before, held))>>20, buf.Len()>>20, len(all))
+	runtime.KeepAlive(all)
+	runtime.KeepAlive(buf.Bytes())
+
+	// Stopping early reads no further (than the decoder's buffer, a few KB)
+	buf.Reset()
+	userArray(&buf, 10_000)
+	r := bytes.NewReader(buf.Bytes())
+	for u := range jsonpkg.Elements[User](r) {
+		if u.UserId == "user-1" {
+			break
+		}
+	}
+	fmt.Printf("stopped at the second user - read %d bytes of %d KB\n", int(r.Size())-r.Len(), r.Size()>>10)
+
+	// Errors: a bad element, a cut-off array, and something that isn't an array
+	for _, in := range []string{`[{"name": "a"}, {"name": 5}]`, `[{"name": "a"}, {"name"`, `{"name": "a"}`, `[]`} {
+		got := 0
+		var last error
+		for _, err := range jsonpkg.Elements[User](strings.NewReader(in)) {
+			if err != nil {
+				last = err
+				break
+			}
+			got++
+		}
+		fmt.Printf("  %-30s %d element(s), err: %v\n", in, got, last)
+	}
+}
+
+func main() {
+	tagsExample()
+	// pointerExample()
+	// nestedExample()
+	// dynamicExample()
+	// streamExample()
+}
@@ -0,0 +1,424 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// A small JSON-RPC 2.0 server and client (https://www.jsonrpc.org/specification).
+//
+// Framing: one JSON value per line (newline-delimited JSON) - the spec leaves the transport open,
+// and json.Encoder already writes a newline after every value.
+// Anything that is an io.ReadWriteCloser works as a connection: a net.Conn from TCP, or net.Pipe() in memory.
+//
+//	s := jsonrpc.NewServer()
+//	jsonrpc.Register(s, "sum", func(ctx context.Context, nums []int) (int, error) { ... })
+//	go s.Serve(ctx, listener)
+//
+//	c := jsonrpc.NewClient(conn)
+//	var total int
+//	err := c.Call(ctx, "sum", []int{1, 2, 3}, &total)
+//
+// (the rpc module is the standard library's net/rpc, and its JSON-RPC 1.0 codec, for comparison)
+
+// Error codes defined by the spec. -32000 to -32099 are free for server-defined errors
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeServerError    = -32000
+)
+
+const version = "2.0"
+
+// Request is a call, or a notification when ID is absent (nil).
+// ID and Params are kept as raw JSON: an id can be a string, number or null and must be echoed back unchanged,
+// and params are only decoded once the method (and so the params type) is known
+type Request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// Response has exactly one of Result or Error set.
+// ID is always sent, as null when the request's id couldn't be read
+type Response struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Error is both the error object sent on the wire and a Go error.
+// Handlers can return an *Error to choose the code; any other error is sent as CodeServerError
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Data != nil {
+		return fmt.Sprintf("jsonrpc: %s (code %d): %v", e.Message, e.Code, e.Data)
+	}
+	return fmt.Sprintf("jsonrpc: %s (code %d)", e.Message, e.Code)
+}
+
+func errParse() *Error          { return &Error{Code: CodeParseError, Message: "Parse error"} }
+func errInvalidRequest() *Error { return &Error{Code: CodeInvalidRequest, Message: "Invalid Request"} }
+
+// --- Server ---
+
+// handler is the type-erased form every registered method is stored as
+type handler func(ctx context.Context, params json.RawMessage) (any, error)
+
+type Server struct {
+	mu      sync.RWMutex
+	methods map[string]handler
+}
+
+func NewServer() *Server {
+	return &Server{methods: make(map[string]handler)}
+}
+
+// Register adds a method to the server. The params are decoded into P, and the returned R is encoded as the result.
+// A function rather than a method, because methods cannot have their own type parameters.
+// (The other common approach is reflection over a struct's methods, like net/rpc)
+//
+// P is usually a struct for named params ({"a": 1}) or a slice for positional params ([1, 2]).
+// Like http.ServeMux, it panics on programmer errors: a duplicate name, or a reserved "rpc." name
+func Register[P, R any](s *Server, method string, fn func(ctx context.Context, params P) (R, error)) {
+	if method == "" || strings.HasPrefix(method, "rpc.") {
+		panic(fmt.Sprintf("jsonrpc: invalid method name %q", method))
+	}
+
+	h := func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var params P
+		if len(raw) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&params); err != nil {
+				return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params", Data: err.Error()}
+			}
+		}
+		return fn(ctx, params)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.methods[method]; exists {
+		panic(fmt.Sprintf("jsonrpc: method %q registered twice", method))
+	}
+	s.methods[method] = h
+}
+
+// Handle processes one frame - a single request or a batch (a JSON array of requests) -
+// and returns the encoded response, or nil when nothing should be sent back (notifications only).
+// Independent of the transport, so it can be checked directly against the spec's examples
+func (s *Server) Handle(ctx context.Context, msg []byte) []byte {
+	msg = bytes.TrimSpace(msg)
+	if !json.Valid(msg) {
+		return encode(&Response{Version: version, Error: errParse()})
+	}
+
+	if msg[0] != '[' {
+		resp := s.handleOne(ctx, msg)
+		if resp == nil {
+			return nil
+		}
+		return encode(resp)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil || len(batch) == 0 {
+		return encode(&Response{Version: version, Error: errInvalidRequest()})
+	}
+	// Requests in a batch are run in order. The spec allows any order (and running them concurrently),
+	// since clients match responses by id
+	responses := make([]*Response, 0, len(batch))
+	for _, raw := range batch {
+		if resp := s.handleOne(ctx, raw); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return encode(responses)
+}
+
+func (s *Server) handleOne(ctx context.Context, raw json.RawMessage) *Response {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil || !validRequest(&req) {
+		// the id can't be trusted if the request is malformed, so it is sent back as null
+		return &Response{Version: version, Error: errInvalidRequest()}
+	}
+	isNotification := req.ID == nil
+
+	s.mu.RLock()
+	h, ok := s.methods[req.Method]
+	s.mu.RUnlock()
+	if !ok {
+		if isNotification {
+			return nil
+		}
+		return &Response{Version: version, ID: req.ID, Error: &Error{Code: CodeMethodNotFound, Message: "Method not found"}}
+	}
+
+	result, err := call(ctx, h, req.Params)
+	if isNotification {
+		return nil // never answered, even on error
+	}
+
+	resp := &Response{Version: version, ID: req.ID}
+	if err == nil {
+		resp.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeServerError, Message: err.Error()}
+		}
+		resp.Result, resp.Error = nil, rpcErr
+	}
+	return resp
+}
+
+// call runs a handler, turning a panic into an internal error instead of crashing the whole server
+func call(ctx context.Context, h handler, params json.RawMessage) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &Error{Code: CodeInternalError, Message: "Internal error", Data: fmt.Sprint(r)}
+		}
+	}()
+	return h(ctx, params)
+}
+
+func validRequest(req *Request) bool {
+	if req.Version != version || req.Method == "" {
+		return false
+	}
+	// params must be structured (an object or an array) if present
+	if len(req.Params) > 0 && req.Params[0] != '{' && req.Params[0] != '[' {
+		return false
+	}
+	// an id can only be a string, a number, or null
+	if req.ID != nil {
+		switch req.ID[0] {
+		case '{', '[', 't', 'f':
+			return false
+		}
+	}
+	return true
+}
+
+func encode(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// only possible for a result that can't be encoded, which handleOne already turned into an error
+		panic(err)
+	}
+	return data
+}
+
+// ServeConn reads requests from conn, one per line, until the connection is closed or ctx is cancelled.
+// Requests on one connection are handled one at a time, in order; use several connections for concurrency.
+// Note - lines have no length limit here; a real server should cap them (ex. bufio.Scanner's Buffer)
+func (s *Server) ServeConn(ctx context.Context, conn io.ReadWriteCloser) error {
+	defer conn.Close()
+	// Closing the connection is what unblocks the pending read when ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if resp := s.Handle(ctx, line); resp != nil {
+				if _, werr := conn.Write(append(resp, '\n')); werr != nil {
+					return werr
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Serve accepts connections until ctx is cancelled, with one goroutine per connection.
+// It waits for every connection to finish before returning
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil // closed by the AfterFunc - a normal shutdown
+			}
+			return err
+		}
+		wg.Go(func() { s.ServeConn(ctx, conn) })
+	}
+}
+
+// --- Client ---
+
+var ErrClosed = errors.New("jsonrpc: connection closed")
+
+// Client sends calls over one connection. It is safe to use from several goroutines:
+// a single reader goroutine matches each response to its waiting Call by id
+type Client struct {
+	conn    io.ReadWriteCloser
+	writeMu sync.Mutex // one request written at a time, so lines don't interleave
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan *Response
+	err     error // set once the reader stops
+}
+
+func NewClient(conn io.ReadWriteCloser) *Client {
+	c := &Client{conn: conn, pending: make(map[uint64]chan *Response)}
+	go c.readLoop()
+	return c
+}
+
+func (c *Client) readLoop() {
+	dec := json.NewDecoder(c.conn)
+	var err error
+	for {
+		var resp Response
+		if err = dec.Decode(&resp); err != nil {
+			break
+		}
+		var id uint64
+		if json.Unmarshal(resp.ID, &id) != nil {
+			continue // not one of ours (ex. a null id after a malformed request)
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[id]
+		delete(c.pending, id)
+		c.mu.Unlock()
+		if ok {
+			ch <- &resp // buffered, never blocks
+		}
+	}
+
+	// Fail every call still waiting, and any later ones
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		c.err = fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+// Call sends a request and waits for its response, decoding the result into result (if not nil).
+// An error from the server is returned as an *Error
+func (c *Client) Call(ctx context.Context, method string, params, result any) error {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan *Response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	if err := c.send(method, params, id); err != nil {
+		c.forget(id)
+		return err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.err
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-ctx.Done():
+		// The server will still answer, the reader just drops the response
+		c.forget(id)
+		return ctx.Err()
+	}
+}
+
+// Notify sends a notification - the server runs the method but never responds, so errors are not reported
+func (c *Client) Notify(method string, params any) error {
+	return c.send(method, params, nil)
+}
+
+func (c *Client) send(method string, params, id any) error {
+	req := Request{Version: version, Method: method}
+	var err error
+	if params != nil {
+		if req.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+		// checked here, since the server can only answer an invalid request with a null id - which matches no call
+		if req.Params[0] != '{' && req.Params[0] != '[' {
+			return fmt.Errorf("jsonrpc: params must be a JSON object or array, got %s", req.Params)
+		}
+	}
+	if id != nil {
+		if req.ID, err = json.Marshal(id); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.conn.Write(append(data, '\n'))
+	return err
+}
+
+func (c *Client) forget(id uint64) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = ErrClosed
+	}
+	c.mu.Unlock()
+	return c.conn.Close()
+}
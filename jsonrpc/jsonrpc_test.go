@@ -0,0 +1,410 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// subtractParams accepts both positional ([42, 23]) and named ({"minuend": 42, "subtrahend": 23}) params,
+// as the spec's subtract examples use both
+type subtractParams struct {
+	Minuend, Subtrahend int
+}
+
+func (p *subtractParams) UnmarshalJSON(data []byte) error {
+	var positional []int
+	if err := json.Unmarshal(data, &positional); err == nil {
+		if len(positional) != 2 {
+			return fmt.Errorf("want 2 positional params, got %d", len(positional))
+		}
+		p.Minuend, p.Subtrahend = positional[0], positional[1]
+		return nil
+	}
+	var named struct {
+		Minuend    *int `json:"minuend"`
+		Subtrahend *int `json:"subtrahend"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return err
+	}
+	if named.Minuend == nil || named.Subtrahend == nil {
+		return errors.New("minuend and subtrahend are required")
+	}
+	p.Minuend, p.Subtrahend = *named.Minuend, *named.Subtrahend
+	return nil
+}
+
+// notificationLog records the notifications that ran, in order
+type notificationLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *notificationLog) add(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *notificationLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries)
+}
+
+// newTestServer has the methods the spec's examples call, plus some for the error cases
+func newTestServer() (*Server, *notificationLog) {
+	s := NewServer()
+	notifications := &notificationLog{}
+	Register(s, "subtract", func(ctx context.Context, p subtractParams) (int, error) {
+		return p.Minuend - p.Subtrahend, nil
+	})
+	Register(s, "sum", func(ctx context.Context, nums []int) (int, error) {
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total, nil
+	})
+	Register(s, "get_data", func(ctx context.Context, _ struct{}) ([]any, error) {
+		return []any{"hello", 5}, nil
+	})
+	Register(s, "update", func(ctx context.Context, nums []int) (struct{}, error) {
+		notifications.add(fmt.Sprint("update ", nums))
+		return struct{}{}, nil
+	})
+	Register(s, "notify_hello", func(ctx context.Context, nums []int) (struct{}, error) {
+		notifications.add(fmt.Sprint("notify_hello ", nums))
+		return struct{}{}, nil
+	})
+	type divideParams struct {
+		A float64 `json:"a"`
+		B float64 `json:"b"`
+	}
+	Register(s, "divide", func(ctx context.Context, p divideParams) (float64, error) {
+		if p.B == 0 {
+			return 0, &Error{Code: -32001, Message: "division by zero"}
+		}
+		return p.A / p.B, nil
+	})
+	Register(s, "fail", func(ctx context.Context, _ struct{}) (int, error) {
+		return 0, errors.New("disk full")
+	})
+	Register(s, "crash", func(ctx context.Context, _ struct{}) (int, error) {
+		var m map[string]int
+		m["boom"] = 1
+		return 0, nil
+	})
+	type sleepParams struct {
+		Ms int `json:"ms"`
+	}
+	Register(s, "sleep", func(ctx context.Context, p sleepParams) (string, error) {
+		select {
+		case <-time.After(time.Duration(p.Ms) * time.Millisecond):
+			return "slept", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	return s, notifications
+}
+
+// --- Conformance - the examples from the spec, plus extra error cases ---
+
+// An empty want means no response at all (notifications)
+var conformanceCases = []struct {
+	name, request, want string
+}{
+	{"positional params", `{"jsonrpc": "2.0", "method": "subtract", "params": [42, 23], "id": 1}`,
+		`{"jsonrpc": "2.0", "result": 19, "id": 1}`},
+	{"positional params reversed", `{"jsonrpc": "2.0", "method": "subtract", "params": [23, 42], "id": 2}`,
+		`{"jsonrpc": "2.0", "result": -19, "id": 2}`},
+	{"named params", `{"jsonrpc": "2.0", "method": "subtract", "params": {"subtrahend": 23, "minuend": 42}, "id": 3}`,
+		`{"jsonrpc": "2.0", "result": 19, "id": 3}`},
+	{"named params reordered", `{"jsonrpc": "2.0", "method": "subtract", "params": {"minuend": 42, "subtrahend": 23}, "id": 4}`,
+		`{"jsonrpc": "2.0", "result": 19, "id": 4}`},
+	{"notification", `{"jsonrpc": "2.0", "method": "update", "params": [1,2,3,4,5]}`, ``},
+	{"notification of unknown method", `{"jsonrpc": "2.0", "method": "foobar"}`, ``},
+	{"string id", `{"jsonrpc": "2.0", "method": "sum", "params": [1,2,4], "id": "abc"}`,
+		`{"jsonrpc": "2.0", "result": 7, "id": "abc"}`},
+	{"null id is a call, not a notification", `{"jsonrpc": "2.0", "method": "sum", "params": [1], "id": null}`,
+		`{"jsonrpc": "2.0", "result": 1, "id": null}`},
+	{"method not found", `{"jsonrpc": "2.0", "method": "foobar", "id": "1"}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32601, "message": "Method not found"}, "id": "1"}`},
+	{"invalid JSON", `{"jsonrpc": "2.0", "method": "foobar, "params": "bar", "baz]`,
+		`{"jsonrpc": "2.0", "error": {"code": -32700, "message": "Parse error"}, "id": null}`},
+	{"invalid request object", `{"jsonrpc": "2.0", "method": 1, "params": "bar"}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null}`},
+	{"wrong version", `{"jsonrpc": "1.0", "method": "sum", "params": [1], "id": 5}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null}`},
+	{"object id", `{"jsonrpc": "2.0", "method": "sum", "params": [1], "id": {"a": 1}}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null}`},
+	{"batch with invalid JSON", `[
+		{"jsonrpc": "2.0", "method": "sum", "params": [1,2,4], "id": "1"},
+		{"jsonrpc": "2.0", "method"
+	]`, `{"jsonrpc": "2.0", "error": {"code": -32700, "message": "Parse error"}, "id": null}`},
+	{"empty batch", `[]`,
+		`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null}`},
+	{"invalid batch of one", `[1]`,
+		`[{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null}]`},
+	{"invalid batch", `[1,2,3]`, `[
+		{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null},
+		{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null},
+		{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null}
+	]`},
+	{"mixed batch", `[
+		{"jsonrpc": "2.0", "method": "sum", "params": [1,2,4], "id": "1"},
+		{"jsonrpc": "2.0", "method": "notify_hello", "params": [7]},
+		{"jsonrpc": "2.0", "method": "subtract", "params": [42,23], "id": "2"},
+		{"foo": "boo"},
+		{"jsonrpc": "2.0", "method": "foo.get", "params": {"name": "myself"}, "id": "5"},
+		{"jsonrpc": "2.0", "method": "get_data", "id": "9"}
+	]`, `[
+		{"jsonrpc": "2.0", "result": 7, "id": "1"},
+		{"jsonrpc": "2.0", "result": 19, "id": "2"},
+		{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}, "id": null},
+		{"jsonrpc": "2.0", "error": {"code": -32601, "message": "Method not found"}, "id": "5"},
+		{"jsonrpc": "2.0", "result": ["hello", 5], "id": "9"}
+	]`},
+	{"batch of notifications", `[
+		{"jsonrpc": "2.0", "method": "notify_sum", "params": [1,2,4]},
+		{"jsonrpc": "2.0", "method": "notify_hello", "params": [7]}
+	]`, ``},
+	{"invalid params", `{"jsonrpc": "2.0", "method": "subtract", "params": {"minuend": 1}, "id": 6}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32602, "message": "Invalid params", "data": "minuend and subtrahend are required"}, "id": 6}`},
+	{"unknown named param", `{"jsonrpc": "2.0", "method": "divide", "params": {"a": 1, "c": 2}, "id": 7}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32602, "message": "Invalid params", "data": "json: unknown field \"c\""}, "id": 7}`},
+	{"custom error code", `{"jsonrpc": "2.0", "method": "divide", "params": {"a": 1, "b": 0}, "id": 8}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32001, "message": "division by zero"}, "id": 8}`},
+	{"plain Go error", `{"jsonrpc": "2.0", "method": "fail", "id": 9}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32000, "message": "disk full"}, "id": 9}`},
+	{"panicking handler", `{"jsonrpc": "2.0", "method": "crash", "id": 10}`,
+		`{"jsonrpc": "2.0", "error": {"code": -32603, "message": "Internal error", "data": "assignment to entry in nil map"}, "id": 10}`},
+}
+
+// sameJSON compares two JSON documents by value, so spacing and key order don't matter
+func sameJSON(a, b []byte) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestConformance(t *testing.T) {
+	s, notifications := newTestServer()
+	for _, tc := range conformanceCases {
+		got := s.Handle(context.Background(), []byte(tc.request))
+		if !sameJSON(got, []byte(tc.want)) {
+			t.Errorf("%s:\n got  %s\n want %s", tc.name, got, tc.want)
+		}
+	}
+	// the notifications ran, though nothing was sent back for them - and the unknown ones didn't
+	want := []string{"update [1 2 3 4 5]", "notify_hello [7]", "notify_hello [7]"}
+	if got := notifications.list(); !slices.Equal(got, want) {
+		t.Errorf("notifications ran: %q, want %q", got, want)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	for _, name := range []string{"", "rpc.discover", "sum"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) didn't panic", name)
+				}
+			}()
+			s, _ := newTestServer() // has "sum" already
+			Register(s, name, func(ctx context.Context, _ struct{}) (int, error) { return 0, nil })
+		}()
+	}
+}
+
+// --- A Client and a Server over net.Pipe ---
+
+// pipe serves a test server on one end of a net.Pipe, and returns a Client on the other.
+// ServeConn's error is sent on served once it returns
+func pipe(t *testing.T) (c *Client, notifications *notificationLog, served <-chan error) {
+	t.Helper()
+	s, notifications := newTestServer()
+	serverConn, clientConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	done := make(chan error, 1)
+	go func() { done <- s.ServeConn(ctx, serverConn) }()
+	c = NewClient(clientConn)
+	t.Cleanup(func() { c.Close() })
+	return c, notifications, done
+}
+
+// Concurrent calls on one client each get their own response, matched by id
+func TestClientConcurrentCalls(t *testing.T) {
+	c, _, _ := pipe(t)
+	results := make([]int, 20)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Go(func() {
+			if err := c.Call(context.Background(), "subtract", []int{100, i}, &results[i]); err != nil {
+				t.Errorf("call %d: %v", i, err)
+			}
+		})
+	}
+	wg.Wait()
+	for i, r := range results {
+		if r != 100-i {
+			t.Errorf("subtract(100, %d) = %d", i, r)
+		}
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	c, _, _ := pipe(t)
+	ctx := context.Background()
+	tests := []struct {
+		method   string
+		params   any
+		wantCode int
+		wantMsg  string
+	}{
+		{"divide", map[string]float64{"a": 1, "b": 0}, -32001, "division by zero"},
+		{"nope", nil, CodeMethodNotFound, "Method not found"},
+		{"subtract", map[string]int{"minuend": 1}, CodeInvalidParams, "Invalid params"},
+		{"fail", nil, CodeServerError, "disk full"},
+		{"crash", nil, CodeInternalError, "Internal error"},
+	}
+	for _, tt := range tests {
+		err := c.Call(ctx, tt.method, tt.params, nil)
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != tt.wantCode || rpcErr.Message != tt.wantMsg {
+			t.Errorf("%s: %v, want an *Error with code %d and message %q", tt.method, err, tt.wantCode, tt.wantMsg)
+		}
+	}
+	// and the connection is still fine after them
+	var total int
+	if err := c.Call(ctx, "sum", []int{1, 2}, &total); err != nil || total != 3 {
+		t.Errorf("sum after the errors: %d, %v", total, err)
+	}
+}
+
+// Scalar params are refused before anything is sent: the server could only answer with a null id
+func TestClientScalarParams(t *testing.T) {
+	c, _, _ := pipe(t)
+	err := c.Call(context.Background(), "sleep", 50, nil)
+	if err == nil || !strings.Contains(err.Error(), "params must be a JSON object or array") {
+		t.Errorf("scalar params: %v", err)
+	}
+}
+
+// A timeout stops the waiting call; the late response is dropped, and the next call gets its own
+func TestClientTimeout(t *testing.T) {
+	c, _, _ := pipe(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.Call(ctx, "sleep", map[string]int{"ms": 100}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("call past its deadline: %v, want DeadlineExceeded", err)
+	}
+	var total int
+	if err := c.Call(context.Background(), "sum", []int{4, 5}, &total); err != nil || total != 9 {
+		t.Errorf("the call after: %d, %v, want 9", total, err)
+	}
+}
+
+// A notification runs, and runs before a call sent after it on the same connection
+func TestClientNotify(t *testing.T) {
+	c, notifications, _ := pipe(t)
+	if err := c.Notify("update", []int{7}); err != nil {
+		t.Fatal(err)
+	}
+	c.Notify("nope", nil) // unknown: nothing comes back, and nothing breaks
+	var total int
+	if err := c.Call(context.Background(), "sum", []int{1, 2, 3}, &total); err != nil || total != 6 {
+		t.Fatalf("sum: %d, %v", total, err)
+	}
+	if got := notifications.list(); !slices.Equal(got, []string{"update [7]"}) {
+		t.Errorf("notifications: %q", got)
+	}
+}
+
+// After Close, calls fail with ErrClosed - and the server's ServeConn returns nil, the client having hung up
+func TestClientClose(t *testing.T) {
+	c, _, served := pipe(t)
+	c.Close()
+	if err := c.Call(context.Background(), "sum", []int{1}, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("call after Close: %v, want ErrClosed", err)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("ServeConn returned %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ServeConn still running after the client closed")
+	}
+}
+
+// A call waiting when the server goes away fails with ErrClosed instead of waiting forever
+func TestClientServerGone(t *testing.T) {
+	s, _ := newTestServer()
+	serverConn, clientConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.ServeConn(ctx, serverConn)
+	c := NewClient(clientConn)
+	defer c.Close()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel() // ServeConn closes its end
+	}()
+	if err := c.Call(context.Background(), "sleep", map[string]int{"ms": 1000}, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("call with the server gone: %v, want ErrClosed", err)
+	}
+}
+
+// Serve gives each connection its own goroutine: calls on four connections run at the same time
+func TestServe(t *testing.T) {
+	s, _ := newTestServer()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback listener:", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(ctx, ln) }()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			conn, err := net.Dial("tcp", ln.Addr().String())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			c := NewClient(conn)
+			defer c.Close()
+			var reply string
+			if err := c.Call(ctx, "sleep", map[string]int{"ms": 100}, &reply); err != nil || reply != "slept" {
+				t.Errorf("client %d: %q, %v", i, reply, err)
+			}
+		})
+	}
+	wg.Wait()
+	if d := time.Since(start); d > 350*time.Millisecond {
+		t.Errorf("4 concurrent 100ms calls took %v - run one after another?", d)
+	}
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("Serve after cancel: %v, want nil", err)
+	}
+}
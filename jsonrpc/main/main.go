@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jsonrpc"
+	"net"
+	"sync"
+	"time"
+)
+
+// === Ex. JSON-RPC 2.0 over net.Conn ===
+
+// Puts several notes together:
+// - interfaces: the server works with any io.ReadWriteCloser, net.Pipe() and TCP connections alike
+// - goroutines: one per connection on the server, plus a reader goroutine in the client
+// - encoding: custom UnmarshalJSON, json.RawMessage to delay decoding until the type is known
+// - generics: jsonrpc.Register decodes params into the handler's own parameter type
+
+// subtract accepts both positional ([42, 23]) and named ({"minuend": 42, "subtrahend": 23}) params,
+// so its params type implements json.Unmarshaler
+type subtractParams struct {
+	Minuend, Subtrahend int
+}
+
+func (p *subtractParams) UnmarshalJSON(data []byte) error {
+	var positional []int
+	if err := json.Unmarshal(data, &positional); err == nil {
+		if len(positional) != 2 {
+			return fmt.Errorf("want 2 positional params, got %d", len(positional))
+		}
+		p.Minuend, p.Subtrahend = positional[0], positional[1]
+		return nil
+	}
+
+	var named struct {
+		Minuend    *int `json:"minuend"`
+		Subtrahend *int `json:"subtrahend"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return err
+	}
+	if named.Minuend == nil || named.Subtrahend == nil {
+		return errors.New("minuend and subtrahend are required")
+	}
+	p.Minuend, p.Subtrahend = *named.Minuend, *named.Subtrahend
+	return nil
+}
+
+var errDivideByZero = &jsonrpc.Error{Code: -32001, Message: "division by zero"}
+
+// newServer registers the methods used by the spec's examples, plus a few more
+func newServer() (*jsonrpc.Server, *notificationLog) {
+	s := jsonrpc.NewServer()
+	notifications := &notificationLog{}
+
+	jsonrpc.Register(s, "subtract", func(ctx context.Context, p subtractParams) (int, error) {
+		return p.Minuend - p.Subtrahend, nil
+	})
+	jsonrpc.Register(s, "sum", func(ctx context.Context, nums []int) (int, error) {
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total, nil
+	})
+	jsonrpc.Register(s, "get_data", func(ctx context.Context, _ struct{}) ([]any, error) {
+		return []any{"hello", 5}, nil
+	})
+	jsonrpc.Register(s, "update", func(ctx context.Context, nums []int) (struct{}, error) {
+		notifications.add(fmt.Sprint("update ", nums))
+		return struct{}{}, nil
+	})
+	jsonrpc.Register(s, "notify_hello", func(ctx context.Context, nums []int) (struct{}, error) {
+		notifications.add(fmt.Sprint("notify_hello ", nums))
+		return struct{}{}, nil
+	})
+
+	type divideParams struct {
+		A float64 `json:"a"`
+		B float64 `json:"b"`
+	}
+	jsonrpc.Register(s, "divide", func(ctx context.Context, p divideParams) (float64, error) {
+		if p.B == 0 {
+			return 0, errDivideByZero // an *Error keeps its code
+		}
+		return p.A / p.B, nil
+	})
+	jsonrpc.Register(s, "fail", func(ctx context.Context, _ struct{}) (int, error) {
+		return 0, errors.New("disk full") // a plain error becomes a server error (-32000)
+	})
+	jsonrpc.Register(s, "crash", func(ctx context.Context, _ struct{}) (int, error) {
+		var m map[string]int
+		m["boom"] = 1 // panics - the server recovers and answers with an internal error
+		return 0, nil
+	})
+	type sleepParams struct {
+		Ms int `json:"ms"`
+	}
+	jsonrpc.Register(s, "sleep", func(ctx context.Context, p sleepParams) (string, error) {
+		select {
+		case <-time.After(time.Duration(p.Ms) * time.Millisecond):
+			return "slept", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	return s, notifications
+}
+
+type notificationLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *notificationLog) add(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *notificationLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+// --- The spec's examples ---
+
+// Handle takes one frame and returns the response to send, so the spec's examples can be tried without a
+// connection. jsonrpc_test.go checks the server against all of them, plus more error cases, and a Client
+// and Server over net.Pipe
+func conformanceExample() {
+	s, notifications := newServer()
+	for _, request := range []string{
+		`{"jsonrpc": "2.0", "method": "subtract", "params": [42, 23], "id": 1}`,
+		`{"jsonrpc": "2.0", "method": "subtract", "params": {"subtrahend": 23, "minuend": 42}, "id": 3}`,
+		`{"jsonrpc": "2.0", "method": "update", "params": [1,2,3,4,5]}`,
+		`{"jsonrpc": "2.0", "method": "foobar", "id": "1"}`,
+		`{"jsonrpc": "2.0", "method": "foobar, "params": "bar", "baz]`,
+		`[1,2]`,
+		`[{"jsonrpc": "2.0", "method": "sum", "params": [1,2,4], "id": "1"}, {"jsonrpc": "2.0", "method": "notify_hello", "params": [7]}]`,
+	} {
+		resp := s.Handle(context.Background(), []byte(request))
+		if resp == nil {
+			resp = []byte("(nothing - a notification)")
+		}
+		fmt.Printf("--> %s\n<-- %s\n", request, resp)
+	}
+	fmt.Println("notifications that ran:", notifications.list())
+}
+
+// --- Over a connection ---
+
+// net.Pipe gives two connected in-memory net.Conns - no ports needed.
+// Raw lines are written by hand here, to see the framing
+func pipeExample() {
+	s, _ := newServer()
+	serverConn, clientConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- s.ServeConn(ctx, serverConn) }()
+
+	r := bufio.NewReader(clientConn)
+	fmt.Fprintln(clientConn, `{"jsonrpc": "2.0", "method": "update", "params": [1]}`)
+	fmt.Fprintln(clientConn, `{"jsonrpc": "2.0", "method": "sum", "params": [1, 2], "id": 1}`)
+
+	// The notification sent nothing back, so the first line read is the answer to the call
+	line, _ := r.ReadString('\n')
+	fmt.Print("first line back: ", line)
+
+	clientConn.Close()
+	fmt.Println("ServeConn returned after the client hung up:", <-done)
+}
+
+// A real TCP listener, several clients at once.
+// Each connection has its own goroutine, so four 100ms calls on four connections take ~100ms in total
+func tcpExample() {
+	s, _ := newServer()
+	ln, err := net.Listen("tcp", "127.0.0.1:0") // port 0 - let the OS pick a free one
+	if err != nil {
+		fmt.Println("listen:", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() { served <- s.Serve(ctx, ln) }()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			conn, err := net.Dial("tcp", ln.Addr().String())
+			if err != nil {
+				fmt.Println("dial:", err)
+				return
+			}
+			c := jsonrpc.NewClient(conn)
+			defer c.Close()
+
+			var reply string
+			err = c.Call(ctx, "sleep", map[string]int{"ms": 100}, &reply)
+			fmt.Printf("client %d: %q %v\n", i, reply, err)
+		})
+	}
+	wg.Wait()
+	fmt.Printf("4 concurrent connections took %v\n", time.Since(start).Round(10*time.Millisecond))
+
+	cancel()
+	fmt.Println("Serve returned after cancel:", <-served)
+}
+
+// One client shared by several goroutines, and errors coming back as *jsonrpc.Error
+func clientExample() {
+	s, notifications := newServer()
+	serverConn, clientConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.ServeConn(ctx, serverConn)
+
+	c := jsonrpc.NewClient(clientConn)
+	defer c.Close()
+
+	// Responses are matched to calls by id, so concurrent calls on one client are fine
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	for i := range results {
+		wg.Go(func() {
+			c.Call(ctx, "subtract", []int{100, i}, &results[i])
+		})
+	}
+	wg.Wait()
+	fmt.Println("concurrent results:", results)
+
+	var quotient float64
+	err := c.Call(ctx, "divide", map[string]float64{"a": 1, "b": 0}, &quotient)
+	var rpcErr *jsonrpc.Error
+	fmt.Println("divide by zero:", err, "| as *jsonrpc.Error:", errors.As(err, &rpcErr), "| code:", rpcErr.Code)
+
+	err = c.Call(ctx, "nope", nil, nil)
+	fmt.Println("unknown method:", err)
+
+	// A timeout only stops waiting on the client side
+	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancelTimeout()
+	err = c.Call(timeoutCtx, "sleep", map[string]int{"ms": 50}, nil)
+	fmt.Println("call with timeout:", err)
+
+	// params have to be structured (an object or an array) - caught before sending
+	fmt.Println("scalar params:", c.Call(ctx, "sleep", 50, nil))
+
+	c.Notify("update", []int{7})
+	var total int
+	c.Call(ctx, "sum", []int{1, 2, 3}, &total) // requests on a connection run in order, so the notification has run by now
+	fmt.Println("sum:", total, "| notifications:", notifications.list())
+
+	c.Close()
+	fmt.Println("call after close:", errors.Is(c.Call(ctx, "sum", []int{1}, &total), jsonrpc.ErrClosed))
+}
+
+func main() {
+	conformanceExample()
+	// pipeExample()
+	// tcpExample()
+	// clientExample()
+}
@@ -0,0 +1,264 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// A key-value store that survives restarts: a map behind a mutex (the concurrency notes' SafeCounter),
+// with every change written to a log file before it's applied - a write-ahead log (WAL).
+//
+// On disk, a store is a directory with two files:
+// - snapshot.json: the whole map, as of the last compaction
+// - wal.log: every Set and Delete since then, one JSON record per line, appended
+//
+// Open loads the snapshot and replays the log over it, which gives back the map as it was after the last
+// change that reached the log. The log only grows, so Compact writes the map as a new snapshot and starts
+// an empty log - by hand, every Options.CompactEvery, or once the log has Options.CompactAfter records.
+//
+// Durability is Options.Sync: with it, Set returns only once the record is on disk (fsync), so a power cut
+// loses nothing that was acknowledged; without it, the record is in the OS's cache, which survives the
+// process crashing but not the machine. (BenchmarkSet in kvstore_test.go has the cost of each.)
+
+var (
+	ErrClosed  = errors.New("kvstore: closed")
+	ErrCorrupt = errors.New("kvstore: corrupt log")
+)
+
+const (
+	snapshotFile = "snapshot.json"
+	walFile      = "wal.log"
+)
+
+type Options struct {
+	Sync         bool          // fsync the log after every write
+	CompactEvery time.Duration // compact in the background this often (0: never)
+	CompactAfter int           // compact once the log has this many records (0: never)
+}
+
+type Store struct {
+	dir  string
+	opts Options
+
+	mu         sync.RWMutex
+	data       map[string]string
+	wal        *wal
+	records    int   // in the log since the last compaction
+	compactErr error // the last compaction's error, nil once one succeeds
+	closed     bool
+
+	stop chan struct{} // closed by Close, to end the background compaction
+	done sync.WaitGroup
+}
+
+// Open opens the store in dir, creating dir if needed, and recovers its contents
+func Open(dir string, opts Options) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, opts: opts, data: make(map[string]string), stop: make(chan struct{})}
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	n, err := replay(filepath.Join(dir, walFile), s.apply)
+	if err != nil {
+		return nil, err
+	}
+	s.records = n
+	if s.wal, err = openWAL(filepath.Join(dir, walFile), opts.Sync); err != nil {
+		return nil, err
+	}
+	if opts.CompactEvery > 0 {
+		s.done.Go(s.compactPeriodically)
+	}
+	return s, nil
+}
+
+func (s *Store) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, snapshotFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // a new store
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrCorrupt, snapshotFile, err)
+	}
+	if s.data == nil { // a snapshot of "null" sets the map to nil, and the first write would panic
+		s.data = make(map[string]string)
+	}
+	return nil
+}
+
+// apply makes one record's change to the map. The caller holds the lock (or is Open, before anyone else can)
+func (s *Store) apply(r record) {
+	switch r.Op {
+	case opSet:
+		s.data[r.Key] = r.Value
+	case opDelete:
+		delete(s.data, r.Key)
+	}
+}
+
+// Get is key's value. Readers share the lock, so Gets don't wait for each other - only for writes
+func (s *Store) Get(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.data[key]
+	return v, ok
+}
+
+// Set sets key to value: first in the log, then in the map. If the log write fails, the map is
+// left as it was - a change that isn't in the log would be lost on the next Open
+func (s *Store) Set(key, value string) error {
+	return s.write(record{Op: opSet, Key: key, Value: value})
+}
+
+// Delete removes key. Deleting a key that isn't there is not an error (and still logged - it's cheaper
+// than checking, and replaying it changes nothing)
+func (s *Store) Delete(key string) error {
+	return s.write(record{Op: opDelete, Key: key})
+}
+
+func (s *Store) write(r record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if err := s.wal.append(r); err != nil {
+		return fmt.Errorf("kvstore: writing the log: %w", err)
+	}
+	s.apply(r)
+	s.records++
+	if s.opts.CompactAfter > 0 && s.records >= s.opts.CompactAfter {
+		// The change is in the log and the map already, so it's done - a failed compaction only leaves
+		// the log longer, and is tried again on the next write. It's kept for CompactErr, not returned:
+		// an error from Set would say the value wasn't stored
+		s.compactLocked()
+	}
+	return nil
+}
+
+// CompactErr is the error of the last compaction - by hand, background or after CompactAfter records -
+// or nil if it succeeded. The automatic ones have no caller to return it to
+func (s *Store) CompactErr() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.compactErr
+}
+
+// Len is the number of keys
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data)
+}
+
+// Keys is every key, sorted
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Sorted(maps.Keys(s.data))
+}
+
+// LogRecords is how many records the log has - what Compact resets to 0
+func (s *Store) LogRecords() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.records
+}
+
+// Compact writes the map as the snapshot and empties the log. Writes wait while it runs; reads don't
+// wait any longer than for a write
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return s.compactLocked()
+}
+
+// compactLocked is Compact with s.mu held, keeping its error for CompactErr
+func (s *Store) compactLocked() error {
+	s.compactErr = s.compact()
+	return s.compactErr
+}
+
+// compact writes the snapshot and empties the log. The order is what makes a crash at any point safe:
+//  1. the new snapshot is written to a temp file and renamed into place - the old one or the new, never half
+//  2. only then is the log emptied. A crash between the two leaves the new snapshot AND the old log, and
+//     replaying the old log over it redoes changes that are already in it - ending at the same map,
+//     as the log's last change to each key is the value the snapshot has
+func (s *Store) compact() error {
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, snapshotFile), data); err != nil {
+		return fmt.Errorf("kvstore: writing the snapshot: %w", err)
+	}
+	if err := s.wal.truncate(); err != nil {
+		return fmt.Errorf("kvstore: emptying the log: %w", err)
+	}
+	s.records = 0
+	return nil
+}
+
+func (s *Store) compactPeriodically() {
+	t := time.NewTicker(s.opts.CompactEvery)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.Compact() // an error (in CompactErr) leaves the log as it was, to be compacted next time
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Close stops the background compaction and closes the log. Every change made before it is in the log
+// (synced to disk first, whatever Options.Sync says)
+func (s *Store) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.closed = true
+	s.mu.Unlock()
+	close(s.stop)
+	s.done.Wait()
+	return s.wal.close()
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // after the rename there's nothing to remove
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
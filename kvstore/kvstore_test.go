@@ -0,0 +1,350 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func open(t *testing.T, dir string, opts Options) *Store {
+	t.Helper()
+	s, err := Open(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func get(t *testing.T, s *Store, key string) string {
+	t.Helper()
+	v, ok := s.Get(key)
+	if !ok {
+		t.Fatalf("%s isn't there", key)
+	}
+	return v
+}
+
+func TestSetGetDelete(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, Options{})
+	s.Set("user:1", "John Doe")
+	s.Set("user:2", "Jack Eod")
+	s.Set("user:1", "John Q. Doe")
+	s.Delete("user:2")
+	s.Delete("user:3") // not there: not an error
+	if v := get(t, s, "user:1"); v != "John Q. Doe" {
+		t.Errorf("user:1 = %q", v)
+	}
+	if _, ok := s.Get("user:2"); ok {
+		t.Error("user:2 is still there")
+	}
+	if keys := s.Keys(); !slices.Equal(keys, []string{"user:1"}) || s.Len() != 1 {
+		t.Errorf("keys %q, len %d", keys, s.Len())
+	}
+	if s.LogRecords() != 5 {
+		t.Errorf("%d records, want 5", s.LogRecords())
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The log, as it is on disk: every change, in order, one JSON line each
+	log, _ := os.ReadFile(filepath.Join(dir, walFile))
+	want := `{"op":"set","key":"user:1","value":"John Doe"}
+{"op":"set","key":"user:2","value":"Jack Eod"}
+{"op":"set","key":"user:1","value":"John Q. Doe"}
+{"op":"del","key":"user:2"}
+{"op":"del","key":"user:3"}
+`
+	if string(log) != want {
+		t.Errorf("wal.log:\n%s\nwant:\n%s", log, want)
+	}
+
+	// Reopening replays it
+	again := open(t, dir, Options{})
+	defer again.Close()
+	if v := get(t, again, "user:1"); v != "John Q. Doe" || again.Len() != 1 || again.LogRecords() != 5 {
+		t.Errorf("reopened: user:1 = %q, len %d, %d records", v, again.Len(), again.LogRecords())
+	}
+}
+
+func TestClosed(t *testing.T) {
+	s := open(t, t.TempDir(), Options{})
+	s.Set("a", "1")
+	s.Close()
+	if err := s.Set("b", "2"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Set after Close = %v, want ErrClosed", err)
+	}
+	if err := s.Delete("a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Delete after Close = %v, want ErrClosed", err)
+	}
+	if err := s.Compact(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Compact after Close = %v, want ErrClosed", err)
+	}
+	if err := s.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Close twice = %v, want ErrClosed", err)
+	}
+	// Reads still work on what was there
+	if v, ok := s.Get("a"); !ok || v != "1" {
+		t.Errorf("Get after Close = %q, %t", v, ok)
+	}
+}
+
+// A crash mid-write leaves the last record cut short: it was never acknowledged, so it's dropped,
+// and the file cut back so the next record isn't glued to it
+func TestTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, Options{})
+	s.Set("a", "1")
+	s.Set("b", "2")
+	s.Close()
+	whole, _ := os.ReadFile(filepath.Join(dir, walFile))
+
+	f, _ := os.OpenFile(filepath.Join(dir, walFile), os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"op":"set","key":"c","val`)
+	f.Close()
+
+	s = open(t, dir, Options{})
+	if keys := s.Keys(); !slices.Equal(keys, []string{"a", "b"}) || s.LogRecords() != 2 {
+		t.Errorf("after a torn write: %q, %d records", keys, s.LogRecords())
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, walFile)); string(data) != string(whole) {
+		t.Errorf("the torn record is still in the log:\n%s", data)
+	}
+	s.Set("c", "3")
+	s.Close()
+
+	s = open(t, dir, Options{})
+	defer s.Close()
+	if v := get(t, s, "c"); v != "3" || s.Len() != 3 {
+		t.Errorf("c = %q, len %d", v, s.Len())
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	tests := []struct {
+		name, file, data string
+	}{
+		{"a damaged record", walFile, "{\"op\":\"set\",\"key\":\"a\",\"value\":\"1\"}\n{\"op\":\"set\",\"key\":b\"}\n{\"op\":\"del\",\"key\":\"a\"}\n"},
+		{"an unknown op", walFile, "{\"op\":\"incr\",\"key\":\"a\"}\n"},
+		{"a damaged snapshot", snapshotFile, `{"a":`},
+		{"a snapshot that isn't a map", snapshotFile, `["a"]`},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.data), 0o644)
+		if _, err := Open(dir, Options{}); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: Open = %v, want ErrCorrupt", tt.name, err)
+		}
+		// Refusing to open changes nothing: the file is left for someone to look at
+		if data, _ := os.ReadFile(filepath.Join(dir, tt.file)); string(data) != tt.data {
+			t.Errorf("%s: Open changed the file", tt.name)
+		}
+	}
+}
+
+// A snapshot of null is valid JSON for a map - an empty one, still writable
+func TestNullSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, snapshotFile), []byte("null"), 0o644)
+	s := open(t, dir, Options{})
+	defer s.Close()
+	if s.Len() != 0 {
+		t.Errorf("len %d", s.Len())
+	}
+	if err := s.Set("a", "1"); err != nil {
+		t.Errorf("Set = %v", err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, Options{})
+	for i := range 1000 {
+		s.Set(fmt.Sprintf("counter:%d", i%10), fmt.Sprint(i))
+	}
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if s.LogRecords() != 0 {
+		t.Errorf("%d records after Compact", s.LogRecords())
+	}
+	if info, _ := os.Stat(filepath.Join(dir, walFile)); info.Size() != 0 {
+		t.Errorf("wal.log is %d bytes after Compact", info.Size())
+	}
+	s.Set("counter:0", "new")
+	s.Close()
+	oldLog, _ := os.ReadFile(filepath.Join(dir, walFile))
+
+	// Snapshot plus the log since - the same map
+	s = open(t, dir, Options{})
+	if v0, v9 := get(t, s, "counter:0"), get(t, s, "counter:9"); v0 != "new" || v9 != "999" || s.LogRecords() != 1 {
+		t.Errorf("reopened: counter:0 %q, counter:9 %q, %d records", v0, v9, s.LogRecords())
+	}
+
+	// A crash between writing the snapshot and emptying the log: both are there, and replaying the old log
+	// over the new snapshot ends at the same values
+	s.Compact()
+	s.Close()
+	os.WriteFile(filepath.Join(dir, walFile), oldLog, 0o644)
+	s = open(t, dir, Options{})
+	defer s.Close()
+	if v0 := get(t, s, "counter:0"); v0 != "new" || s.Len() != 10 {
+		t.Errorf("a Compact interrupted halfway: counter:0 %q, len %d", v0, s.Len())
+	}
+	// No temp file left behind by writeFileAtomic
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.Name() != walFile && e.Name() != snapshotFile {
+			t.Errorf("left in the store's directory: %s", e.Name())
+		}
+	}
+}
+
+func TestCompactAfter(t *testing.T) {
+	s := open(t, t.TempDir(), Options{CompactAfter: 100})
+	defer s.Close()
+	for i := range 250 {
+		s.Set("k", fmt.Sprint(i))
+	}
+	if s.LogRecords() != 50 {
+		t.Errorf("CompactAfter 100, 250 Sets: %d records, want 50", s.LogRecords())
+	}
+}
+
+// A compaction after a Set that fails: the Set still happened, and CompactErr says why the log is still long
+func TestCompactFails(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, Options{CompactAfter: 1})
+	defer s.Close()
+	os.Mkdir(filepath.Join(dir, snapshotFile), 0o755) // a file can't be renamed over a directory
+	if err := s.Set("k", "v"); err != nil {
+		t.Fatalf("Set = %v, want nil - the value was stored", err)
+	}
+	if v := get(t, s, "k"); v != "v" || s.LogRecords() != 1 {
+		t.Errorf("k = %q, %d records", v, s.LogRecords())
+	}
+	if s.CompactErr() == nil {
+		t.Error("CompactErr = nil after a failed compaction")
+	}
+	if err := s.Compact(); err == nil || err.Error() != s.CompactErr().Error() {
+		t.Errorf("Compact = %v, CompactErr = %v", err, s.CompactErr())
+	}
+
+	// Fixed, and the next one succeeds
+	os.Remove(filepath.Join(dir, snapshotFile))
+	s.Set("k", "w")
+	if s.CompactErr() != nil || s.LogRecords() != 0 {
+		t.Errorf("after the fix: CompactErr %v, %d records", s.CompactErr(), s.LogRecords())
+	}
+}
+
+// On synctest's clock: the background compaction runs every CompactEvery, and Close stops it
+func TestCompactEvery(t *testing.T) {
+	dir := t.TempDir()
+	synctest.Test(t, func(t *testing.T) {
+		s := open(t, dir, Options{CompactEvery: 20 * time.Millisecond})
+		s.Set("k", "v")
+		time.Sleep(19 * time.Millisecond)
+		synctest.Wait()
+		if s.LogRecords() != 1 {
+			t.Errorf("before CompactEvery: %d records, want 1", s.LogRecords())
+		}
+		time.Sleep(time.Millisecond)
+		synctest.Wait()
+		if s.LogRecords() != 0 {
+			t.Errorf("after CompactEvery: %d records, want 0", s.LogRecords())
+		}
+		if err := s.Close(); err != nil {
+			t.Error(err)
+		}
+		// the bubble ends only once the compaction goroutine has: Close stopped it
+	})
+}
+
+// 8 writers and 8 readers at once - and whatever the interleaving, and the compactions in between,
+// the files give back the same map
+func TestConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, Options{CompactAfter: 500})
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				if err := s.Set(fmt.Sprintf("w%d:%d", w, i%50), fmt.Sprint(i)); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+	for range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				s.Get(fmt.Sprintf("w0:%d", i%50))
+			}
+		})
+	}
+	wg.Wait()
+	if s.Len() != 400 {
+		t.Errorf("len %d, want 8 writers x 50 keys", s.Len())
+	}
+	s.Close()
+
+	s = open(t, dir, Options{})
+	defer s.Close()
+	for w := range 8 {
+		for k := range 50 {
+			if v := get(t, s, fmt.Sprintf("w%d:%d", w, k)); v != fmt.Sprint(150+k) {
+				t.Errorf("w%d:%d = %s after reopening, want its last value %d", w, k, v, 150+k)
+			}
+		}
+	}
+}
+
+func TestKeysWithNewlines(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, Options{})
+	s.Set("multi\nline", "a\nb")
+	s.Close()
+	s = open(t, dir, Options{})
+	defer s.Close()
+	// JSON escapes the newline, so a record is still one line
+	if v := get(t, s, "multi\nline"); v != "a\nb" {
+		t.Errorf("value %q", v)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, walFile)); strings.Count(string(data), "\n") != 1 {
+		t.Errorf("the record isn't one line: %q", data)
+	}
+}
+
+// BenchmarkSet is the cost of Options.Sync: without it a Set is a write to the OS's page cache; with it,
+// a wait for the disk. Databases batch waiting writers into one fsync (group commit) to get most of both
+func BenchmarkSet(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts Options
+	}{
+		{"nosync", Options{}},
+		{"sync", Options{Sync: true}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			s, err := Open(b.TempDir(), bc.opts)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer s.Close()
+			i := 0
+			for b.Loop() {
+				s.Set("key", fmt.Sprint(i))
+				i++
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"kvstore"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// === Ex. a key-value store with a write-ahead log: Get/Set/Delete, replay, compaction ===
+
+// Each example opens its stores in a temp directory of its own, removed at the end.
+// (the checks are in kvstore_test.go)
+func tempDir() string {
+	dir, err := os.MkdirTemp("", "notes-kvstore-*")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func main() {
+	kvBasicsExample()
+	// recoveryExample()
+	// compactionExample()
+	// concurrentKVExample()
+	// kvBenchmarkExample()
+}
+
+func kvBasicsExample() {
+	dir := tempDir()
+	defer os.RemoveAll(dir)
+
+	s, err := kvstore.Open(dir, kvstore.Options{})
+	if err != nil {
+		fmt.Println("open:", err)
+		return
+	}
+	s.Set("user:1", "John Doe")
+	s.Set("user:2", "Jack Eod")
+	s.Set("user:1", "John Q. Doe")
+	s.Delete("user:2")
+	v, ok := s.Get("user:1")
+	_, gone := s.Get("user:2")
+	fmt.Printf("user:1 = %q %t | user:2 there: %t | keys %v\n", v, ok, gone, s.Keys())
+	s.Close()
+
+	// The log, as it is on disk: every change, in order
+	log, _ := os.ReadFile(filepath.Join(dir, "wal.log"))
+	fmt.Print("wal.log:\n", string(log))
+
+	// Reopening replays it - the same map, from the file alone
+	again, err := kvstore.Open(dir, kvstore.Options{})
+	if err != nil {
+		fmt.Println("reopen:", err)
+		return
+	}
+	defer again.Close()
+	v2, _ := again.Get("user:1")
+	fmt.Println("reopened: user:1 =", v2, "| keys", again.Keys(), "| replayed records:", again.LogRecords())
+
+	// A closed store refuses changes, instead of losing them
+	err = s.Set("user:3", "x")
+	fmt.Println("Set after Close:", err, "| is ErrClosed:", errors.Is(err, kvstore.ErrClosed))
+}
+
+func recoveryExample() {
+	dir := tempDir()
+	defer os.RemoveAll(dir)
+	wal := filepath.Join(dir, "wal.log")
+
+	s, _ := kvstore.Open(dir, kvstore.Options{})
+	s.Set("a", "1")
+	s.Set("b", "2")
+	s.Close()
+
+	// A crash mid-write: the last record cut short, no newline. It was never acknowledged, so it's dropped
+	f, _ := os.OpenFile(wal, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"op":"set","key":"c","val`)
+	f.Close()
+	s, err := kvstore.Open(dir, kvstore.Options{})
+	fmt.Println("after a torn write:", s.Keys(), err)
+	s.Set("c", "3") // and the log is whole again - the new record isn't glued to the torn one
+	s.Close()
+	s, err = kvstore.Open(dir, kvstore.Options{})
+	c, _ := s.Get("c")
+	fmt.Println("reopened:", s.Keys(), "| c =", c, err)
+	s.Close()
+
+	// A damaged line in the middle is different: which later changes still make sense? Open refuses
+	data, _ := os.ReadFile(wal)
+	os.WriteFile(wal, []byte(strings.Replace(string(data), `"key":"b"`, `"key":b"`, 1)), 0o644)
+	_, err = kvstore.Open(dir, kvstore.Options{})
+	fmt.Println("a damaged record:", err, "| is ErrCorrupt:", errors.Is(err, kvstore.ErrCorrupt))
+
+	// A snapshot of null is valid JSON for a map - an empty one, still writable
+	empty := filepath.Join(dir, "empty")
+	os.MkdirAll(empty, 0o755)
+	os.WriteFile(filepath.Join(empty, "snapshot.json"), []byte("null"), 0o644)
+	s, err = kvstore.Open(empty, kvstore.Options{})
+	fmt.Println("a null snapshot:", s.Len(), "keys", err, "| Set:", s.Set("a", "1"))
+	s.Close()
+}
+
+func compactionExample() {
+	dir := tempDir()
+	defer os.RemoveAll(dir)
+	size := func(name string) int64 {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return 0
+		}
+		return info.Size()
+	}
+
+	// 1000 changes to 10 keys: the log grows with every one, the map doesn't
+	s, _ := kvstore.Open(dir, kvstore.Options{})
+	for i := range 1000 {
+		s.Set(fmt.Sprintf("counter:%d", i%10), fmt.Sprint(i))
+	}
+	fmt.Printf("before Compact: %d records, wal.log %d bytes, %d keys\n", s.LogRecords(), size("wal.log"), s.Len())
+	s.Compact()
+	fmt.Printf("after:          %d records, wal.log %d bytes, snapshot.json %d bytes\n", s.LogRecords(), size("wal.log"), size("snapshot.json"))
+	s.Set("counter:0", "new")
+	s.Close()
+
+	// Snapshot plus the log since - the same map
+	s, _ = kvstore.Open(dir, kvstore.Options{})
+	v0, _ := s.Get("counter:0")
+	v9, _ := s.Get("counter:9")
+	fmt.Println("reopened from snapshot + log: counter:0 =", v0, "| counter:9 =", v9, "| records in the log:", s.LogRecords())
+	s.Close()
+
+	// A crash between writing the snapshot and emptying the log: both are there, and replaying the old log
+	// over the new snapshot ends at the same values
+	oldLog, _ := os.ReadFile(filepath.Join(dir, "wal.log"))
+	s, _ = kvstore.Open(dir, kvstore.Options{})
+	s.Compact()
+	s.Close()
+	os.WriteFile(filepath.Join(dir, "wal.log"), oldLog, 0o644) // the log as it was before the Compact
+	s, _ = kvstore.Open(dir, kvstore.Options{})
+	v0, _ = s.Get("counter:0")
+	fmt.Println("a Compact interrupted halfway: counter:0 =", v0, "|", s.Len(), "keys")
+	s.Close()
+
+	// Automatic: after every 100 records, and on a timer
+	auto, _ := kvstore.Open(filepath.Join(dir, "auto"), kvstore.Options{CompactAfter: 100})
+	for i := range 250 {
+		auto.Set("k", fmt.Sprint(i))
+	}
+	fmt.Println("CompactAfter 100, 250 Sets - records in the log:", auto.LogRecords())
+	auto.Close()
+
+	// A compaction after a Set that fails: the Set still happened, and CompactErr has why the log wasn't emptied
+	broken := filepath.Join(dir, "broken")
+	b, _ := kvstore.Open(broken, kvstore.Options{CompactAfter: 1})
+	os.Mkdir(filepath.Join(broken, "snapshot.json"), 0o755) // a file can't be renamed over a directory
+	err := b.Set("k", "v")
+	v, _ := b.Get("k")
+	fmt.Println("Set:", err, "| k =", v, "| records in the log:", b.LogRecords())
+	fmt.Println("CompactErr:", b.CompactErr())
+	b.Close()
+	timed, _ := kvstore.Open(filepath.Join(dir, "timed"), kvstore.Options{CompactEvery: 20 * time.Millisecond})
+	timed.Set("k", "v")
+	time.Sleep(60 * time.Millisecond)
+	fmt.Println("CompactEvery 20ms, after 60ms - records in the log:", timed.LogRecords())
+	timed.Close() // stops the compaction goroutine before closing the log
+}
+
+func concurrentKVExample() {
+	dir := tempDir()
+	defer os.RemoveAll(dir)
+	s, _ := kvstore.Open(dir, kvstore.Options{CompactAfter: 500})
+
+	// 8 writers and 8 readers at once: the RWMutex orders the writes, readers share it
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				if err := s.Set(fmt.Sprintf("w%d:%d", w, i%50), fmt.Sprint(i)); err != nil {
+					fmt.Println("set:", err)
+				}
+			}
+		})
+	}
+	for range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				s.Get(fmt.Sprintf("w0:%d", i%50))
+			}
+		})
+	}
+	wg.Wait()
+	n := s.Len()
+	v, _ := s.Get("w7:49")
+	s.Close()
+
+	// Whatever the interleaving (and the compactions in between), the file gives back the same map
+	s, err := kvstore.Open(dir, kvstore.Options{})
+	if err != nil {
+		fmt.Println("reopen:", err)
+		return
+	}
+	defer s.Close()
+	v2, _ := s.Get("w7:49")
+	fmt.Println("keys:", n, "| w7:49 =", v, "| after reopening:", s.Len(), "keys, w7:49 =", v2)
+	// (go run -race . has nothing to report: every access to the map is under the mutex)
+}
+
+func kvBenchmarkExample() {
+	// A rough timing - BenchmarkSet in kvstore_test.go is the careful one: go test -bench Set kvstore
+	dir := tempDir()
+	defer os.RemoveAll(dir)
+	perSet := func(opts kvstore.Options, name string) time.Duration {
+		s, err := kvstore.Open(filepath.Join(dir, name), opts)
+		if err != nil {
+			fmt.Println("open:", err)
+			return 0
+		}
+		defer s.Close()
+		const n = 200
+		start := time.Now()
+		for i := range n {
+			s.Set("key", fmt.Sprint(i))
+		}
+		return time.Since(start) / n
+	}
+	noSync := perSet(kvstore.Options{}, "nosync")
+	withSync := perSet(kvstore.Options{Sync: true}, "sync")
+	fmt.Printf("Set without Sync: %v | with Sync (fsync per write): %v - %.0fx\n", noSync, withSync, float64(withSync)/float64(noSync))
+	// Without Sync a Set is a write to the OS's page cache; with it, a wait for the disk. Databases batch
+	// waiting writers into one fsync (group commit) to get most of both
+}
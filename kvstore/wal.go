@@ -0,0 +1,115 @@
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// The log is JSON lines, one record per change:
+//
+//	{"op":"set","key":"user:1","value":"John Doe"}
+//	{"op":"del","key":"user:1"}
+//
+// A record and its newline go to the file in one Write, so a crash mid-write leaves at most the last line
+// cut short - a line without its newline. replay drops such a line (that change was never acknowledged)
+// and truncates the file back to the last whole record. A whole line that doesn't parse is something else -
+// the file was damaged - and Open reports it, rather than guess which changes to keep.
+
+const (
+	opSet    = "set"
+	opDelete = "del"
+)
+
+type record struct {
+	Op    string `json:"op"`
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+type wal struct {
+	f    *os.File
+	sync bool
+}
+
+func openWAL(path string, sync bool) (*wal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &wal{f: f, sync: sync}, nil
+}
+
+func (w *wal) append(r record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := w.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if w.sync {
+		return w.f.Sync()
+	}
+	return nil
+}
+
+// truncate empties the log. The file is opened with O_APPEND, so the next write goes to the (new) end
+func (w *wal) truncate() error {
+	if err := w.f.Truncate(0); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+func (w *wal) close() error {
+	if err := w.f.Sync(); err != nil {
+		w.f.Close()
+		return err
+	}
+	return w.f.Close()
+}
+
+// replay calls apply with each record of the log at path, in order, and returns how many there were.
+// No log yet is no records
+func replay(path string, apply func(record)) (int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var good int64 // bytes up to the end of the last whole record
+	n := 0
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 { // a torn last record
+				return n, os.Truncate(path, good)
+			}
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		var rec record
+		if err := json.Unmarshal(bytes.TrimSuffix(line, []byte("\n")), &rec); err != nil {
+			return n, fmt.Errorf("%w: %s line %d: %v", ErrCorrupt, walFile, n+1, err)
+		}
+		if rec.Op != opSet && rec.Op != opDelete {
+			return n, fmt.Errorf("%w: %s line %d: unknown op %q", ErrCorrupt, walFile, n+1, rec.Op)
+		}
+		apply(rec)
+		good += int64(len(line))
+		n++
+	}
+}
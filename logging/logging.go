@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// log/slog writes structured logs: a message plus key-value attributes, so a log line can be searched
+// by its fields (user=..., status=500) instead of by matching text.
+// - a Logger is what code calls: Info, Warn, Error, Debug, or Log with any level
+// - a Handler is what it calls: it decides which records are written (Enabled) and how (Handle) -
+//   slog.TextHandler writes key=value, slog.JSONHandler one JSON object per line
+// - With adds attributes to every record from then on, WithGroup puts the following ones under a name
+// The Logger is the same whatever the Handler, so code that logs never needs to know where it goes to.
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying l - a logger with the request's attributes already on it,
+// so everything called with ctx logs them without being passed them
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext is the logger NewContext put in ctx, or slog.Default() if there's none
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// DropTime is a ReplaceAttr for the built-in handlers that leaves out the time - for output that's the same every run
+func DropTime(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+	return a
+}
+
+// Entry is one record kept by a Recorder. Attrs in groups have keys like "req.method"
+type Entry struct {
+	Level   slog.Level
+	Message string
+	Attrs   map[string]string
+}
+
+func (e Entry) String() string {
+	s := fmt.Sprintf("%s %q", e.Level, e.Message)
+	for _, k := range slices.Sorted(maps.Keys(e.Attrs)) {
+		s += fmt.Sprintf(" %s=%s", k, e.Attrs[k])
+	}
+	return s
+}
+
+// Recorder is a slog.Handler that keeps records in memory instead of writing them, so a check can look
+// at what was logged. It's also a whole Handler in a small space - what the built-in ones have to get
+// right: the level, attributes from With, groups from WithGroup, and values that compute themselves (LogValuer).
+// Handlers made by WithAttrs and WithGroup record into the same list
+type Recorder struct {
+	level   slog.Leveler
+	attrs   []slog.Attr // from WithAttrs, keys already prefixed with the groups at the time
+	prefix  string      // the open groups, "req.user."
+	mu      *sync.Mutex
+	entries *[]Entry
+}
+
+// NewRecorder records everything at level and above (nil: slog.LevelInfo, like the built-in handlers)
+func NewRecorder(level slog.Leveler) *Recorder {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &Recorder{level: level, mu: new(sync.Mutex), entries: new([]Entry)}
+}
+
+// Enabled is asked before a record is even built - a disabled Debug call costs almost nothing
+func (h *Recorder) Enabled(_ context.Context, l slog.Level) bool { return l >= h.level.Level() }
+
+func (h *Recorder) Handle(_ context.Context, r slog.Record) error {
+	e := Entry{Level: r.Level, Message: r.Message, Attrs: map[string]string{}}
+	for _, a := range h.attrs {
+		addAttr(e.Attrs, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(e.Attrs, h.prefix, a)
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.entries = append(*h.entries, e)
+	return nil
+}
+
+// addAttr adds a under prefix, flattening groups. As the built-in handlers do: a LogValuer is resolved first,
+// an empty attribute is left out, and so is a group with nothing in it
+func addAttr(into map[string]string, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" { // an unnamed group's attributes are inlined
+			p += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addAttr(into, p, ga)
+		}
+		return
+	}
+	into[prefix+a.Key] = a.Value.String()
+}
+
+func (h *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		if h.prefix != "" {
+			// kept as a group, so addAttr flattens it under the groups open now - not the ones opened later
+			a = slog.Attr{Key: h.prefix[:len(h.prefix)-1], Value: slog.GroupValue(a)}
+		}
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+func (h *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix += name + "."
+	return &h2
+}
+
+// Entries returns what was recorded so far
+func (h *Recorder) Entries() []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(*h.entries)
+}
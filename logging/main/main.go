@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"logging"
+	"os"
+	"strings"
+	"time"
+)
+
+// === Ex. log/slog: handlers, levels, groups, loggers in a context, a Handler of one's own ===
+
+// Every handler here writes to os.Stdout with logging.DropTime, so the output is the same every run.
+// basics/main's HandleFileUpload is given a *slog.Logger the same way (see uploadLoggingExample there).
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+
+func main() {
+	handlersExample()
+	// levelsExample()
+	// groupsExample()
+	// contextExample()
+	// recorderExample()
+}
+
+func handlersExample() {
+	opts := &slog.HandlerOptions{ReplaceAttr: logging.DropTime}
+
+	// The same calls, two handlers: key=value text, or a JSON object per line
+	for _, h := range []slog.Handler{slog.NewTextHandler(os.Stdout, opts), slog.NewJSONHandler(os.Stdout, opts)} {
+		log := slog.New(h)
+		log.Info("file stored", "user", userId1, "file", "notes.md", "bytes", 2048)
+		// Typed attributes (slog.Int, slog.Duration...) skip the any boxing of "key", value pairs
+		log.Warn("slow upload", slog.String("file", "photo.png"), slog.Duration("took", 1500*time.Millisecond))
+		log.Error("store failed", "file", "big.iso", "err", errors.New("disk full"))
+	}
+
+	// A key without a value is a mistake - slog keeps it, under !BADKEY, rather than lose it.
+	// (go vet reports Info("oops", "file") written out; through a slice it can't see it)
+	args := []any{"file"}
+	slog.New(slog.NewTextHandler(os.Stdout, opts)).Info("oops", args...)
+
+	// The default logger (slog.Info, and the log package's log.Printf) goes through slog.Default().
+	// SetDefault swaps it - here to JSON, then back
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, opts)))
+	slog.Info("through the default logger")
+	slog.SetDefault(old)
+
+	// ReplaceAttr rewrites or drops any attribute on the way out - here a secret, wherever it is
+	redact := &slog.HandlerOptions{ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "token" {
+			return slog.String("token", "REDACTED")
+		}
+		return logging.DropTime(groups, a)
+	}}
+	slog.New(slog.NewTextHandler(os.Stdout, redact)).Info("login", "user", userId1, "token", "s3cr3t")
+}
+
+func levelsExample() {
+	// Levels are ints: Debug -4, Info 0, Warn 4, Error 8 - gaps for levels of one's own in between
+	fmt.Println(slog.LevelDebug, int(slog.LevelDebug), slog.LevelError+2)
+
+	// A handler's Level is the lowest it writes (Info by default: Debug is dropped)
+	var level slog.LevelVar // a Level that can change while the program runs
+	level.Set(slog.LevelWarn)
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: &level, ReplaceAttr: logging.DropTime}))
+
+	log.Info("not written at Warn")
+	log.Warn("written at Warn")
+	level.Set(slog.LevelDebug) // ex. from a -verbose flag, or an admin endpoint
+	log.Debug("now written", "level", level.Level())
+
+	// Enabled answers before any work is done - to skip building something costly for a dropped record
+	level.Set(slog.LevelInfo)
+	built := false
+	if log.Enabled(context.Background(), slog.LevelDebug) {
+		built = true
+	}
+	fmt.Println("built a debug-only value at Info:", built)
+
+	// A LogValuer computes its value only when the record is written - a cheap way to say the same
+	calls := 0
+	costly := lazyValue(func() slog.Value { calls++; return slog.IntValue(42) })
+	log.Debug("dropped", "answer", costly)
+	log.Info("written", "answer", costly)
+	fmt.Println("LogValue called for the written record only:", calls == 1)
+}
+
+// lazyValue is a slog.LogValuer from a func
+type lazyValue func() slog.Value
+
+func (f lazyValue) LogValue() slog.Value { return f() }
+
+// request is logged as a group of its fields, through LogValue - not all of its fields (the body stays out)
+type request struct {
+	Method, Path string
+	Body         []byte
+}
+
+func (r request) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("method", r.Method), slog.String("path", r.Path), slog.Int("body_bytes", len(r.Body)))
+}
+
+func groupsExample() {
+	opts := &slog.HandlerOptions{ReplaceAttr: logging.DropTime}
+	var buf bytes.Buffer
+	for _, h := range []slog.Handler{slog.NewTextHandler(os.Stdout, opts), slog.NewJSONHandler(&buf, opts)} {
+		log := slog.New(h)
+
+		// slog.Group puts attributes under a name: req.method=... in text, {"req":{"method":...}} in JSON
+		log.Info("upload", slog.Group("req", "method", "PUT", "path", "/files/notes.md"), "status", 201)
+
+		// With: attributes on every record of the new logger. WithGroup: every attribute after it in the group
+		svc := log.With("service", "uploads").WithGroup("upload")
+		svc.Info("stored", "file", "notes.md", "bytes", 2048)
+
+		// A LogValuer returning a group
+		log.Info("request", "req", request{"PUT", "/files/photo.png", make([]byte, 734003)})
+	}
+	fmt.Print(buf.String())
+
+	// JSON groups nest - decode the second line to see
+	var rec struct {
+		Service string `json:"service"`
+		Upload  struct {
+			File  string `json:"file"`
+			Bytes int    `json:"bytes"`
+		} `json:"upload"`
+	}
+	line := strings.Split(buf.String(), "\n")[1]
+	err := json.Unmarshal([]byte(line), &rec)
+	fmt.Println("| ok: the group is a nested object:", err == nil && rec.Service == "uploads" && rec.Upload.Bytes == 2048)
+}
+
+// storeFile is a function deep in a call chain: it logs with whatever logger ctx carries, and gets the
+// request's attributes on its lines without being passed them
+func storeFile(ctx context.Context, file string) {
+	log := logging.FromContext(ctx)
+	log.Info("storing", "file", file)
+	log.InfoContext(ctx, "stored", "file", file) // the ...Context methods pass ctx on to the Handler too
+}
+
+func handleUpload(ctx context.Context, requestID, user string, files ...string) {
+	log := logging.FromContext(ctx).With("request_id", requestID, "user", user)
+	ctx = logging.NewContext(ctx, log)
+	for _, f := range files {
+		storeFile(ctx, f)
+	}
+}
+
+func contextExample() {
+	base := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: logging.DropTime}))
+	ctx := logging.NewContext(context.Background(), base)
+	handleUpload(ctx, "req-1", userId1, "notes.md")
+	handleUpload(ctx, "req-2", "96aeb270-dd19-4274-a2fe-30415644864b", "photo.png")
+
+	// Nothing in the context: slog.Default()
+	fmt.Println("FromContext without one is the default:", logging.FromContext(context.Background()) == slog.Default())
+}
+
+func recorderExample() {
+	rec := logging.NewRecorder(slog.LevelDebug)
+	log := slog.New(rec)
+
+	log.Debug("cache miss", "key", "notes.md")
+	log.With("service", "uploads").WithGroup("upload").With("user", userId1[:8]).Info("stored", "file", "notes.md")
+	log.WithGroup("req").Info("request", "r", request{"GET", "/files", nil})
+	log.Error("store failed", "err", errors.New("disk full"), slog.Group("empty"))
+
+	for _, e := range rec.Entries() {
+		fmt.Println(e)
+	}
+	entries := rec.Entries()
+	ok := len(entries) == 4 &&
+		entries[1].Attrs["service"] == "uploads" && // With before WithGroup: outside it
+		entries[1].Attrs["upload.user"] == "1d02455e" && // With after WithGroup: inside it
+		entries[1].Attrs["upload.file"] == "notes.md" &&
+		entries[2].Attrs["req.r.method"] == "GET" && // a LogValuer's group, inside an open group
+		len(entries[3].Attrs) == 1 // the empty group is left out
+	fmt.Println("| ok: With, WithGroup, LogValuer and empty groups handled like the built-in handlers:", ok)
+
+	// Enabled: a Recorder at Warn keeps only Warn and above
+	warn := logging.NewRecorder(slog.LevelWarn)
+	slog.New(warn).Info("dropped")
+	slog.New(warn).Warn("kept")
+	fmt.Println("| ok: Recorder at Warn kept 1:", len(warn.Entries()) == 1)
+}
@@ -0,0 +1,3 @@
+module mapsslices
+
+go 1.25.0
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"mapsslices"
+	"slices"
+	"strings"
+)
+
+// === Ex. the maps and slices packages on the basics notes' maps and slices ===
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+// dictionary and userLookupTable are mapExample's maps, pow rangeForLoopEx's slice and primes arrayExample's array
+func dictionary() map[string]string {
+	return map[string]string{
+		"apple":  "round, edible fruit of an apple tree",
+		"orange": "a round juicy citrus fruit with a tough bright reddish-yellow rind",
+	}
+}
+
+func userLookupTable() map[string]mapsslices.User {
+	return map[string]mapsslices.User{
+		userId1: {UserId: userId1, Name: "John Doe"},
+		userId2: {UserId: userId2, Name: "Jack Eod"},
+	}
+}
+
+var pow = []int{1, 2, 4, 8, 16, 32, 64, 128}
+
+var primes = [6]int{2, 3, 5, 7, 11, 13}
+
+func main() {
+	mapsExample()
+	// searchExample()
+	// editExample()
+}
+
+func mapsExample() {
+	dict, users := dictionary(), userLookupTable()
+
+	// maps.Keys and maps.Values are iterators, in the map's random order - sort them to print them
+	fmt.Println("words:", slices.Sorted(maps.Keys(dict)))
+	fmt.Println("ids:", mapsslices.SortedKeys(users))
+	var names []string
+	for u := range mapsslices.Values(users) {
+		names = append(names, u.Name)
+	}
+	fmt.Println("names, in id order:", names)
+	fmt.Println("| ok: SortedKeys matches the loop:", slices.Equal(mapsslices.SortedKeys(users), mapsslices.SortedKeysLoop(users)))
+
+	// maps.Clone is a shallow copy: a new map, the same values. Changing the copy leaves dict alone
+	draft := maps.Clone(dict)
+	draft["apple"] = strings.ToUpper(draft["apple"])
+	delete(draft, "orange")
+	fmt.Println("clone:", len(draft), "word | original:", len(dict), "words, apple unchanged:", dict["apple"] == dictionary()["apple"])
+
+	// ...but it's only the map that's new. A map of slices shares the slices
+	tags := map[string][]string{"apple": {"fruit"}}
+	tagsCopy := maps.Clone(tags)
+	tagsCopy["apple"][0] = "tree"
+	fmt.Println("a cloned map of slices shares them:", tags["apple"][0])
+
+	// maps.Equal: same keys, and == values. The loop it replaces
+	equal := func(a, b map[string]mapsslices.User) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || w != v {
+				return false
+			}
+		}
+		return true
+	}
+	again := userLookupTable()
+	fmt.Println("| ok: maps.Equal matches the loop:", maps.Equal(users, again) == equal(users, again) && maps.Equal(users, again))
+	again[userId2] = mapsslices.User{UserId: userId2, Name: "Jack Doe"}
+	fmt.Println("after a rename - equal:", maps.Equal(users, again), "| loop:", equal(users, again))
+	// (== can't compare maps, or values that aren't comparable - maps.EqualFunc takes the comparison,
+	// and reflect.DeepEqual compares anything, slowly)
+
+	// maps.Collect builds a map from an iterator, maps.Insert adds one's pairs to a map - ex. reversing one
+	byName := make(map[string]string)
+	for id, u := range users {
+		byName[u.Name] = id
+	}
+	merged := maps.Collect(maps.All(dict))
+	maps.Insert(merged, maps.All(map[string]string{"pear": "sweet fruit, narrow at the top"}))
+	fmt.Println("by name:", byName["John Doe"] == userId1, "| merged:", mapsslices.SortedKeys(merged))
+}
+
+func searchExample() {
+	// slices.Contains and slices.Index are the search loops, and generics.go's Index, in the standard library
+	loopIndex := func(s []int, x int) int {
+		for i, v := range s {
+			if v == x {
+				return i
+			}
+		}
+		return -1
+	}
+	fmt.Println("pow contains 64:", slices.Contains(pow, 64), "| at", slices.Index(pow, 64), "| the loop says", loopIndex(pow, 64))
+	fmt.Println("pow contains 100:", slices.Contains(pow, 100), "| at", slices.Index(pow, 100))
+
+	// They take slices, so an array is sliced first - primes[:] is the whole array, no copy
+	fmt.Println("7 is prime:", slices.Contains(primes[:], 7), "| 9:", slices.Contains(primes[:], 9))
+
+	// The Func versions take a predicate - sliceutil's IndexFunc and Any
+	firstOver10 := slices.IndexFunc(pow, func(v int) bool { return v > 10 })
+	anyEven := slices.ContainsFunc(primes[:], func(v int) bool { return v%2 == 0 })
+	fmt.Println("first power over 10 at:", firstOver10, "| any even prime:", anyEven)
+
+	// On a sorted slice, BinarySearch is O(log n) against Index's O(n), and says where x would go if missing
+	i, found := slices.BinarySearch(pow, 100)
+	fmt.Printf("100: found %t | would go at %d, before %d\n", found, i, pow[i])
+	fmt.Println("| ok:", slices.Index(pow, 64) == loopIndex(pow, 64) && loopIndex(pow, 100) == -1 && firstOver10 == 4 && anyEven) // 2 is the even prime
+
+	// Min, Max and Equal are loops too
+	fmt.Println("primes: min", slices.Min(primes[:]), "max", slices.Max(primes[:]), "| pow[:3] equals {1 2 4}:", slices.Equal(pow[:3], []int{1, 2, 4}))
+}
+
+func editExample() {
+	// Sort, Compact, Insert and Delete change the slice - and, like append, return it. Keep the result
+	nums := slices.Concat(pow[:4], primes[:4]) // a new slice: pow and primes stay as they are
+	fmt.Println("pow[:4] + primes[:4]:", nums)
+	slices.Sort(nums)
+	fmt.Println("sorted:", nums)
+	nums = slices.Compact(nums) // the 2 twice - only adjacent duplicates go, so sort first
+	fmt.Println("compacted:", nums, "| Unique does both, on a clone:", mapsslices.Unique(slices.Concat(pow[:4], primes[:4])))
+
+	// Insert shifts the rest right (growing the array if it's full), Delete shifts them left
+	nums = slices.Insert(nums, 1, 0) // before index 1
+	fmt.Println("insert 0 at 1:", nums)
+	nums = slices.Delete(nums, 1, 2) // the half-open range [1, 2)
+	fmt.Println("delete [1,2):", nums)
+
+	// The loop Delete replaces - and the part it adds: since Go 1.22 the elements past the new length are
+	// zeroed, so an old slice header of the same array sees zeros, not stale copies (and pointers there let go)
+	old := slices.Clone(pow)
+	alias := old
+	shorter := slices.Delete(old, 0, 2)
+	fmt.Println("after Delete(old, 0, 2):", shorter, "| the same array through the old header:", alias)
+	loopDelete := func(s []int, i, j int) []int { return append(s[:i], s[j:]...) } // leaves the tail as it was
+	byLoop := loopDelete(slices.Clone(pow), 0, 2)
+	fmt.Println("| ok:", slices.Equal(shorter, byLoop) && alias[len(alias)-1] == 0 && slices.IsSorted(nums) && len(nums) == 7)
+
+	// Sorting a copy, and sorting by a key: slices.Sorted takes an iterator, SortFunc a cmp-style function
+	// (sortpkg sorts []User by name, then id)
+	backwards := slices.SortedFunc(slices.Values(primes[:]), func(a, b int) int { return b - a })
+	fmt.Println("primes, largest first:", backwards, "| primes itself:", primes)
+}
@@ -0,0 +1,58 @@
+package mapsslices
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// The maps and slices packages (Go 1.21; maps.Keys and maps.Values return iterators since 1.23) are the
+// loops the basics notes write by hand - over pow, primes, dictionary and userLookupTable - as generic
+// functions. generics.go's Index is slices.Index, and basics/sliceutil's IndexFunc and Any are
+// slices.IndexFunc and slices.ContainsFunc; sliceutil's Map, Filter and Reduce have no slices version
+// (slices.DeleteFunc filters, but in place).
+//
+// The signatures take S ~[]E and M ~map[K]V rather than []E and map[K]V, so a defined type like
+// type Primes []int works too, and comes back as a Primes (see generics/constraints.go).
+
+// User is the basics notes' User
+type User struct {
+	UserId string
+	Name   string
+}
+
+// SortedKeys is m's keys in order - the one way to range over a map in the same order every run.
+// maps.Keys is an iter.Seq in the map's (random) order; slices.Sorted collects and sorts it
+func SortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// SortedKeysLoop is SortedKeys as it was written before Go 1.21
+func SortedKeysLoop[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Unique is s's distinct values, sorted. Compact only drops ADJACENT duplicates, so it sorts first -
+// a clone, to leave s as it was
+func Unique[S ~[]E, E cmp.Ordered](s S) S {
+	u := slices.Clone(s)
+	slices.Sort(u)
+	return slices.Compact(u)
+}
+
+// Values is m's values in key order - maps.Values without the random order
+func Values[M ~map[K]V, K cmp.Ordered, V any](m M) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, k := range SortedKeys(m) {
+			if !yield(m[k]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"memlayout"
+	"time"
+	"unsafe"
+)
+
+// === Ex. memory layout: alignment, padding, string and slice headers ===
+
+// Sizes here are for 64-bit platforms (amd64, arm64), where an int and a pointer are 8 bytes.
+// (the checks are in memlayout_test.go, and the benchmark in main_test.go)
+
+func main() {
+	alignmentExample()
+	// reorderExample()
+	// headersExample()
+	// paddingBenchmarkExample()
+	// unsafeWarningsExample()
+}
+
+func alignmentExample() {
+	fmt.Printf("%-10s %5s %6s\n", "type", "size", "align")
+	for _, t := range []struct {
+		name        string
+		size, align uintptr
+	}{
+		{"bool", unsafe.Sizeof(false), unsafe.Alignof(false)},
+		{"int8", unsafe.Sizeof(int8(0)), unsafe.Alignof(int8(0))},
+		{"int16", unsafe.Sizeof(int16(0)), unsafe.Alignof(int16(0))},
+		{"int32", unsafe.Sizeof(int32(0)), unsafe.Alignof(int32(0))},
+		{"int64", unsafe.Sizeof(int64(0)), unsafe.Alignof(int64(0))},
+		{"int", unsafe.Sizeof(0), unsafe.Alignof(0)},
+		{"string", unsafe.Sizeof(""), unsafe.Alignof("")},
+		{"[]int", unsafe.Sizeof([]int(nil)), unsafe.Alignof([]int(nil))},
+		{"any", unsafe.Sizeof(any(nil)), unsafe.Alignof(any(nil))},
+		{"struct{}", unsafe.Sizeof(struct{}{}), unsafe.Alignof(struct{}{})},
+	} {
+		fmt.Printf("%-10s %5d %6d\n", t.name, t.size, t.align)
+	}
+
+	// Offsetof: where a field starts. b can't start at 1 - it's 8-aligned
+	var s struct {
+		a bool
+		b int64
+	}
+	fmt.Println("struct{a bool; b int64}: b at", unsafe.Offsetof(s.b), "| size", unsafe.Sizeof(s))
+
+	// A zero-size field at the end still takes space: a pointer to it mustn't point past the struct
+	// (into the next object), so the compiler pads it
+	fmt.Println("struct{a int64; z struct{}}:", unsafe.Sizeof(struct {
+		a int64
+		z struct{}
+	}{}), "bytes | with z first:", unsafe.Sizeof(struct {
+		z struct{}
+		a int64
+	}{}))
+}
+
+// The same five fields, in two orders
+type padded struct {
+	active bool
+	id     int64
+	flag   bool
+	count  int32
+	kind   bool
+}
+
+// packed puts the biggest fields first - an easy rule that rarely leaves a gap
+type packed struct {
+	id     int64
+	count  int32
+	active bool
+	flag   bool
+	kind   bool
+}
+
+func reorderExample() {
+	fmt.Print(memlayout.Layout[padded](), "\n", memlayout.Layout[packed](), "\n")
+
+	p, q := memlayout.Layout[padded](), memlayout.Layout[packed]()
+	fmt.Printf("reordered: %d -> %d bytes, so a million of them take %d MB instead of %d MB\n",
+		p.Size, q.Size, q.Size*1_000_000>>20, p.Size*1_000_000>>20)
+	// (the fieldalignment analyzer, golang.org/x/tools/go/analysis/passes/fieldalignment, finds these -
+	// but the order that reads best usually matters more, except for types with millions of values)
+}
+
+func headersExample() {
+	// A string is a pointer and a length. Slicing it makes a new header on the same bytes - no copy
+	s := "hello, gophers"
+	sub := s[7:]
+	hs, hsub := memlayout.StringHeaderOf(s), memlayout.StringHeaderOf(sub)
+	fmt.Printf("s: len %d | s[7:]: len %d, starts %d bytes into s's data: %t\n", hs.Len, hsub.Len, hsub.Data-hs.Data, hsub.Data == hs.Data+7)
+	// ...so a small substring of a huge string keeps all of it alive. strings.Clone copies just the part
+	fmt.Println("unsafe.Sizeof of a string is the header:", unsafe.Sizeof(s), "bytes, whatever its length")
+
+	// A slice is a pointer, a length and a capacity
+	nums := make([]int, 3, 4)
+	h1 := memlayout.SliceHeaderOf(nums)
+	nums = append(nums, 1) // fits in the capacity: same array
+	h2 := memlayout.SliceHeaderOf(nums)
+	nums = append(nums, 2) // doesn't: a new, bigger array, the elements copied over
+	h3 := memlayout.SliceHeaderOf(nums)
+	fmt.Printf("make([]int, 3, 4): len %d cap %d\n", h1.Len, h1.Cap)
+	fmt.Printf("append within cap - same array: %t | past cap - moved: %t, new cap %d\n", h1.Data == h2.Data, h2.Data != h3.Data, h3.Cap)
+
+	// A sub-slice shares the array, and its cap runs to the array's end - what append overwrites
+	base := []int{1, 2, 3, 4}
+	head := base[:2]
+	hb, hh := memlayout.SliceHeaderOf(base), memlayout.SliceHeaderOf(head)
+	head = append(head, 99)
+	fmt.Printf("base[:2]: same data %t, cap %d | append to it wrote into base: %v\n", hb.Data == hh.Data, hh.Cap, base)
+}
+
+// sumPadded and sumPacked read one field from every element: the loop walks all the memory of the slice,
+// so the time is mostly how many bytes come from RAM
+func sumPadded(s []padded) (n int64) {
+	for i := range s {
+		n += s[i].id
+	}
+	return n
+}
+
+func sumPacked(s []packed) (n int64) {
+	for i := range s {
+		n += s[i].id
+	}
+	return n
+}
+
+func paddingBenchmarkExample() {
+	const n = 1 << 20 // 32 MB of padded, 16 MB of packed - more than the CPU caches, so from RAM
+	ps, qs := make([]padded, n), make([]packed, n)
+	for i := range n {
+		ps[i].id, qs[i].id = int64(i), int64(i)
+	}
+	// A rough timing, a few passes each - BenchmarkSum in main_test.go is the careful one:
+	// go test -bench Sum memlayout/main
+	var total int64
+	timeIt := func(sum func() int64) time.Duration {
+		total += sum() // one pass first, untimed
+		start := time.Now()
+		for range 20 {
+			total += sum()
+		}
+		return time.Since(start) / 20
+	}
+	pt := timeIt(func() int64 { return sumPadded(ps) })
+	qt := timeIt(func() int64 { return sumPacked(qs) })
+	fmt.Printf("sum of %d ids: padded (%d B each) %v, packed (%d B each) %v\n",
+		n, unsafe.Sizeof(padded{}), pt, unsafe.Sizeof(packed{}), qt)
+	fmt.Printf("packed is %.2fx as fast - half the bytes to read (the gain shrinks once it all fits in cache)\n",
+		float64(pt)/float64(qt))
+	fmt.Println("sums:", sumPadded(ps), sumPacked(qs), "| all passes:", total)
+}
+
+func unsafeWarningsExample() {
+	// Why the unsafe parts are NOT FOR PRODUCTION - each line is the danger happening
+
+	// 1. BytesToString breaks string immutability: change the bytes, and the "immutable" string changes
+	b := []byte("gopher")
+	s := memlayout.BytesToString(b)
+	m := map[string]int{s: 1}
+	b[0] = 'G'
+	fmt.Printf("string after changing its bytes: %q\n", s)
+	_, found := m["gopher"]
+	fmt.Println("  the map key changed under the map - lookups by the old key fail:", !found)
+
+	// 2. string(b) copies, so the string keeps its value - the safe way, and the one to use
+	b = []byte("gopher")
+	safe := string(b)
+	b[0] = 'G'
+	fmt.Println("string(b) after changing b:", safe)
+
+	// 3. A uintptr isn't a pointer: the GC doesn't see it. A header's Data can outlive what it points to,
+	// and after the slice grows it points at the OLD array - a stale address, no error anywhere
+	nums := []int{1, 2}
+	h := memlayout.SliceHeaderOf(nums)
+	nums = append(nums, 3, 4, 5)
+	fmt.Println("the old header's Data still points at the old array:", h.Data != memlayout.SliceHeaderOf(nums).Data,
+		"- and nothing says so")
+	// (go vet's unsafeptr check reports turning such a uintptr back into an unsafe.Pointer)
+}
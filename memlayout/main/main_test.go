@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+// BenchmarkSum reads one field of every element: padded is twice the bytes to read of packed.
+// go test -bench Sum memlayout/main
+func BenchmarkSum(b *testing.B) {
+	const n = 1 << 20 // 32 MB of padded, 16 MB of packed - more than the CPU caches, so from RAM
+	ps, qs := make([]padded, n), make([]packed, n)
+	for i := range n {
+		ps[i].id, qs[i].id = int64(i), int64(i)
+	}
+	b.Run("padded", func(b *testing.B) {
+		for b.Loop() {
+			sumPadded(ps)
+		}
+	})
+	b.Run("packed", func(b *testing.B) {
+		for b.Loop() {
+			sumPacked(qs)
+		}
+	})
+}
+
+func TestSumsAgree(t *testing.T) {
+	ps, qs := make([]padded, 100), make([]packed, 100)
+	for i := range 100 {
+		ps[i].id, qs[i].id = int64(i), int64(i)
+	}
+	if p, q := sumPadded(ps), sumPacked(qs); p != 4950 || q != 4950 {
+		t.Errorf("sums %d, %d, want 4950", p, q)
+	}
+}
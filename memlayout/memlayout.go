@@ -0,0 +1,106 @@
+package memlayout
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// Every type has a size and an alignment: a value of it must start at an address that's a multiple of
+// its alignment (an int64 at a multiple of 8, an int32 of 4, a bool anywhere). In a struct, the compiler
+// adds padding - unused bytes - before a field that would otherwise start misaligned, and at the end, so
+// the next element of an array is aligned too. Fields are laid out in the order they're declared,
+// so the order decides how much padding there is.
+//
+// unsafe.Sizeof, Alignof and Offsetof are compile-time constants, and safe to call - it's the rest of
+// package unsafe (unsafe.Pointer, arithmetic on it, unsafe.String...) that steps outside the type system.
+// NOT FOR PRODUCTION code, that part: it ties the code to the compiler's current layout, the garbage
+// collector and race detector can't help with it, and go vet can catch only some of its mistakes.
+
+// Field is one field of a struct's layout
+type Field struct {
+	Name          string
+	Offset, Size  uintptr
+	PaddingBefore uintptr
+}
+
+// StructLayout describes the fields of a struct type, with the padding before each,
+// and the padding at the end (Tail)
+type StructLayout struct {
+	Type   string
+	Size   uintptr
+	Align  uintptr
+	Fields []Field
+	Tail   uintptr
+}
+
+// Padding is the total unused bytes in the struct
+func (l StructLayout) Padding() uintptr {
+	p := l.Tail
+	for _, f := range l.Fields {
+		p += f.PaddingBefore
+	}
+	return p
+}
+
+func (l StructLayout) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: size %d, align %d, %d bytes of padding\n", l.Type, l.Size, l.Align, l.Padding())
+	for _, f := range l.Fields {
+		if f.PaddingBefore > 0 {
+			fmt.Fprintf(&b, "  %3d  [%d bytes padding]\n", f.Offset-f.PaddingBefore, f.PaddingBefore)
+		}
+		fmt.Fprintf(&b, "  %3d  %-10s %d bytes\n", f.Offset, f.Name, f.Size)
+	}
+	if l.Tail > 0 {
+		fmt.Fprintf(&b, "  %3d  [%d bytes padding]\n", l.Size-l.Tail, l.Tail)
+	}
+	return b.String()
+}
+
+// Layout is the layout of the struct type T, from reflect - the same numbers unsafe.Offsetof gives,
+// for every field at once. It panics if T isn't a struct
+func Layout[T any]() StructLayout {
+	t := reflect.TypeFor[T]()
+	l := StructLayout{Type: t.String(), Size: t.Size(), Align: uintptr(t.Align())}
+	end := uintptr(0)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		l.Fields = append(l.Fields, Field{Name: f.Name, Offset: f.Offset, Size: f.Type.Size(), PaddingBefore: f.Offset - end})
+		end = f.Offset + f.Type.Size()
+	}
+	l.Tail = l.Size - end
+	return l
+}
+
+// StringHeader is what a string value is: a pointer to the bytes, and a length - 16 bytes on 64-bit
+// platforms, however long the string. (reflect.StringHeader was this; it's deprecated for unsafe.StringData)
+type StringHeader struct {
+	Data uintptr
+	Len  int
+}
+
+// SliceHeader is what a slice value is: pointer, length, capacity
+type SliceHeader struct {
+	Data     uintptr
+	Len, Cap int
+}
+
+// StringHeaderOf reads s's header. The pointer is only a number here - it says where the bytes are,
+// but holding it doesn't keep them alive
+func StringHeaderOf(s string) StringHeader {
+	return StringHeader{Data: uintptr(unsafe.Pointer(unsafe.StringData(s))), Len: len(s)}
+}
+
+// SliceHeaderOf reads s's header
+func SliceHeaderOf[T any](s []T) SliceHeader {
+	return SliceHeader{Data: uintptr(unsafe.Pointer(unsafe.SliceData(s))), Len: len(s), Cap: cap(s)}
+}
+
+// BytesToString makes a string sharing b's memory, without copying it - what strings.Builder does inside.
+// Only correct if b is never changed afterwards: a string must not change, and code (map keys, the
+// compiler's own assumptions) relies on that. Don't do this in prod - string(b) copies, and is fast enough.
+func BytesToString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
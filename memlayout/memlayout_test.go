@@ -0,0 +1,183 @@
+package memlayout
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// The sizes are for 64-bit platforms, where an int and a pointer are 8 bytes
+func skipIfNot64Bit(t *testing.T) {
+	t.Helper()
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("layouts here are for 64-bit platforms")
+	}
+}
+
+type padded struct {
+	active bool
+	id     int64
+	flag   bool
+	count  int32
+	kind   bool
+}
+
+type packed struct {
+	id     int64
+	count  int32
+	active bool
+	flag   bool
+	kind   bool
+}
+
+func TestLayout(t *testing.T) {
+	skipIfNot64Bit(t)
+	p := Layout[padded]()
+	var v padded
+	if p.Size != unsafe.Sizeof(v) || p.Align != unsafe.Alignof(v) {
+		t.Errorf("padded: size %d align %d, want %d and %d", p.Size, p.Align, unsafe.Sizeof(v), unsafe.Alignof(v))
+	}
+	wantOffsets := []uintptr{unsafe.Offsetof(v.active), unsafe.Offsetof(v.id), unsafe.Offsetof(v.flag), unsafe.Offsetof(v.count), unsafe.Offsetof(v.kind)}
+	for i, f := range p.Fields {
+		if f.Offset != wantOffsets[i] {
+			t.Errorf("padded.%s at %d, Offsetof says %d", f.Name, f.Offset, wantOffsets[i])
+		}
+	}
+	// bool, 7 padding, int64, bool, 3 padding, int32, bool, 7 padding
+	if p.Size != 32 || p.Padding() != 17 || p.Tail != 7 || p.Fields[1].PaddingBefore != 7 || p.Fields[3].PaddingBefore != 3 {
+		t.Errorf("padded: %+v", p)
+	}
+
+	// The same fields, biggest first: 16 bytes, 1 of them padding
+	q := Layout[packed]()
+	if q.Size != 16 || q.Padding() != 1 || q.Tail != 1 {
+		t.Errorf("packed: %+v", q)
+	}
+	for _, f := range q.Fields {
+		if f.PaddingBefore != 0 {
+			t.Errorf("packed.%s has %d bytes of padding before it", f.Name, f.PaddingBefore)
+		}
+	}
+
+	if l := Layout[struct{}](); l.Size != 0 || l.Padding() != 0 || len(l.Fields) != 0 {
+		t.Errorf("struct{}: %+v", l)
+	}
+	// A zero-size field at the end takes space: a pointer to it mustn't point past the struct
+	if l := Layout[struct {
+		a int64
+		z struct{}
+	}](); l.Size != 16 || l.Tail != 8 {
+		t.Errorf("a trailing struct{}: %+v", l)
+	}
+}
+
+func TestLayoutString(t *testing.T) {
+	skipIfNot64Bit(t)
+	got := Layout[padded]().String()
+	for _, want := range []string{
+		"memlayout.padded: size 32, align 8, 17 bytes of padding\n",
+		"    1  [7 bytes padding]\n",
+		"    8  id         8 bytes\n",
+		"   25  [7 bytes padding]\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLayoutNotAStruct(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Layout[int] didn't panic")
+		}
+	}()
+	Layout[int]()
+}
+
+func TestStringHeaderOf(t *testing.T) {
+	s := "hello, gophers"
+	hs, hsub := StringHeaderOf(s), StringHeaderOf(s[7:])
+	if hs.Len != 14 || hsub.Len != 7 {
+		t.Errorf("lens %d, %d, want 14, 7", hs.Len, hsub.Len)
+	}
+	// Slicing a string is a new header on the same bytes, not a copy
+	if hsub.Data != hs.Data+7 {
+		t.Errorf("s[7:] is at %#x, want s's data + 7 = %#x", hsub.Data, hs.Data+7)
+	}
+	if c := StringHeaderOf(strings.Clone(s[7:])); c.Data == hsub.Data {
+		t.Error("strings.Clone shared the bytes")
+	}
+}
+
+func TestSliceHeaderOf(t *testing.T) {
+	nums := make([]int, 3, 4)
+	h1 := SliceHeaderOf(nums)
+	if h1.Len != 3 || h1.Cap != 4 {
+		t.Errorf("make([]int, 3, 4): %+v", h1)
+	}
+	nums = append(nums, 1)
+	h2 := SliceHeaderOf(nums)
+	nums = append(nums, 2)
+	h3 := SliceHeaderOf(nums)
+	if h2.Data != h1.Data {
+		t.Error("append within the capacity moved the array")
+	}
+	if h3.Data == h2.Data || h3.Cap <= 4 {
+		t.Errorf("append past the capacity: %+v, want a new, bigger array", h3)
+	}
+
+	base := []int{1, 2, 3, 4}
+	head := base[:2]
+	if hh := SliceHeaderOf(head); hh.Data != SliceHeaderOf(base).Data || hh.Cap != 4 {
+		t.Errorf("base[:2]: %+v, want base's array and cap 4", hh)
+	}
+	// The nil slice: no array at all
+	if h := SliceHeaderOf[int](nil); h != (SliceHeader{}) {
+		t.Errorf("nil slice: %+v", h)
+	}
+}
+
+// The NOT FOR PRODUCTION part, as tests: each one passes because the danger happens
+
+// BytesToString shares b's memory, so changing b changes an "immutable" string - and a map key with it
+func TestBytesToStringSharesMemory(t *testing.T) {
+	b := []byte("gopher")
+	s := BytesToString(b)
+	if StringHeaderOf(s).Data != SliceHeaderOf(b).Data {
+		t.Fatal("BytesToString copied")
+	}
+	m := map[string]int{s: 1}
+	b[0] = 'G'
+	if s != "Gopher" {
+		t.Errorf("s = %q after changing b, want Gopher - the string changed under us", s)
+	}
+	if _, found := m["gopher"]; found {
+		t.Error("the map still finds the key by its old value")
+	}
+
+	// string(b) copies: the safe way
+	b = []byte("gopher")
+	safe := string(b)
+	b[0] = 'G'
+	if safe != "gopher" {
+		t.Errorf("string(b) = %q after changing b", safe)
+	}
+	if BytesToString(nil) != "" {
+		t.Error("BytesToString(nil) isn't empty")
+	}
+}
+
+// A header's Data is a uintptr, not a pointer: once the slice grows, it's the old array's address, and
+// nothing says so
+func TestSliceHeaderGoesStale(t *testing.T) {
+	nums := []int{1, 2}
+	h := SliceHeaderOf(nums)
+	nums = append(nums, 3, 4, 5)
+	if h.Data == SliceHeaderOf(nums).Data {
+		t.Error("the slice didn't move - the stale header isn't shown")
+	}
+	if h.Len != 2 {
+		t.Errorf("the old header's len is %d - a copy, not updated", h.Len)
+	}
+}
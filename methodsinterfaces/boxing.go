@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// === Interface boxing, and an allocation-free logging fast path ===
+
+// An interface value is a (type, value) pair (see the interface notes), and the value part is one pointer wide.
+// Storing anything else in an interface - an int, a struct - means copying it to the heap and keeping a pointer to it:
+// "boxing", one allocation each time. (Go avoids it for pointers, zero-size values, and single byte values / small ints in 0..255)
+//
+// Logging is where this adds up: slog.Info("msg", "user", id, "took", d) passes ...any,
+// so every argument is boxed - on every call, even when the log level is disabled, since the boxing
+// happens at the call site before the logger checks anything.
+//
+// Ways around it:
+// - slog.LogAttrs with typed attrs (slog.Int, slog.String, slog.Duration, ...):
+//   slog.Value stores numbers and short values inline, without an interface
+// - check logger.Enabled before doing the work of building the arguments
+// - for a very hot path, a small logger with typed (generic) functions that append straight to a reused buffer
+
+type requestInfo struct {
+	Path   string
+	Status int
+}
+
+// --- A typed fast-path logger ---
+
+// Integer is the set of integer types the fast logger accepts without conversion
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// fastLogger writes "msg key=value" lines. Not safe for concurrent use (one buffer)
+type fastLogger struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (l *fastLogger) begin(msg string) {
+	l.buf = append(l.buf[:0], msg...) // reuse the buffer's memory from the previous line
+}
+
+func (l *fastLogger) end() {
+	l.buf = append(l.buf, '\n')
+	l.w.Write(l.buf)
+}
+
+// Methods can't have type parameters, so the typed appends are functions taking the logger.
+// T is known at compile time, so there is no interface and no boxing
+func appendInt[T Integer](l *fastLogger, key string, v T) {
+	l.buf = append(l.buf, ' ')
+	l.buf = append(l.buf, key...)
+	l.buf = append(l.buf, '=')
+	if v < 0 {
+		l.buf = strconv.AppendInt(l.buf, int64(v), 10)
+	} else {
+		l.buf = strconv.AppendUint(l.buf, uint64(v), 10)
+	}
+}
+
+func appendString(l *fastLogger, key, v string) {
+	l.buf = append(l.buf, ' ')
+	l.buf = append(l.buf, key...)
+	l.buf = append(l.buf, '=')
+	l.buf = strconv.AppendQuote(l.buf, v)
+}
+
+func (l *fastLogger) logRequest(r requestInfo, took time.Duration) {
+	l.begin("request")
+	appendString(l, "path", r.Path)
+	appendInt(l, "status", r.Status)
+	appendInt(l, "took_us", took.Microseconds())
+	l.end()
+}
+
+// --- Measuring ---
+
+// loggingCalls are the ways of logging one request, to compare what each allocates. name is the
+// benchmark's, desc the example's
+func loggingCalls() []struct {
+	name, desc string
+	fn         func()
+} {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil)) // Info level - Debug is disabled
+	fast := &fastLogger{w: io.Discard}
+	req := requestInfo{Path: "/tour/welcome", Status: 200}
+	took := 1500 * time.Microsecond
+	return []struct {
+		name, desc string
+		fn         func()
+	}{
+		{"Info/any", "slog.Info with ...any", func() {
+			logger.Info("request", "path", req.Path, "status", req.Status, "took", took)
+		}},
+		{"Info/struct", "slog.Info with a struct value", func() {
+			logger.Info("request", "req", req, "took", took)
+		}},
+		{"LogAttrs", "slog.LogAttrs with typed attrs", func() {
+			logger.LogAttrs(ctx, slog.LevelInfo, "request",
+				slog.String("path", req.Path), slog.Int("status", req.Status), slog.Duration("took", took))
+		}},
+		{"Debug/disabled", "slog.Debug (disabled) with ...any", func() {
+			logger.Debug("request", "path", req.Path, "status", req.Status, "took", took)
+		}},
+		{"Debug/guarded", "disabled, guarded with Enabled", func() {
+			if logger.Enabled(ctx, slog.LevelDebug) {
+				logger.Debug("request", "path", req.Path, "status", req.Status, "took", took)
+			}
+		}},
+		{"fastLogger", "fastLogger", func() {
+			fast.logRequest(req, took)
+		}},
+	}
+}
+
+func loggingAllocExample() {
+	// The allocation counts come from tests, which log them with -v - TestBoxingAllocs for boxing each kind of value,
+	// TestLoggingAllocs for each call below - and BenchmarkLogging adds the ns/op and B/op:
+	//
+	//	go test -run 'BoxingAllocs|LoggingAllocs' -v methodsinterfaces
+	//	go test -bench Logging methodsinterfaces
+	//
+	// Boxing an int > 255 or a struct allocates; an int < 256 (from a static table) or a pointer doesn't.
+	// LogAttrs allocates less than Info; a disabled Debug still boxes its arguments, the guarded one doesn't;
+	// and fastLogger allocates nothing at all
+	fmt.Println("the logging calls compared:")
+	for _, c := range loggingCalls() {
+		fmt.Println(" ", c.desc)
+	}
+
+	req := requestInfo{Path: "/tour/welcome", Status: 200}
+	took := 1500 * time.Microsecond
+	var out strings.Builder
+	(&fastLogger{w: &out}).logRequest(req, took)
+	fmt.Print("fastLogger output: ", out.String())
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -bench Logging methodsinterfaces: ns/op, B/op and allocs/op for each
+func BenchmarkLogging(b *testing.B) {
+	for _, c := range loggingCalls() {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				c.fn()
+			}
+		})
+	}
+}
+
+func TestLoggingAllocs(t *testing.T) {
+	allocs := map[string]float64{}
+	for _, c := range loggingCalls() {
+		allocs[c.name] = testing.AllocsPerRun(100, c.fn)
+		t.Logf("%-36s %v allocations per call", c.desc, allocs[c.name])
+	}
+	if allocs["LogAttrs"] >= allocs["Info/any"] {
+		t.Errorf("LogAttrs %v allocations, Info with ...any %v: want fewer", allocs["LogAttrs"], allocs["Info/any"])
+	}
+	// The boxing is at the call site, before the logger checks the level
+	if allocs["Debug/disabled"] == 0 {
+		t.Error("a disabled Debug with ...any doesn't allocate")
+	}
+	for _, name := range []string{"Debug/guarded", "fastLogger"} {
+		if allocs[name] != 0 {
+			t.Errorf("%s: %v allocations, want 0", name, allocs[name])
+		}
+	}
+}
+
+var boxSink any // a package level variable, so the compiler can't optimise the boxing away
+
+func TestBoxingAllocs(t *testing.T) {
+	// The values change on every call - a constant would be boxed once at compile time (into static data)
+	big, small := 1000, 7
+	req := requestInfo{Path: "/tour/welcome", Status: 200}
+	tests := []struct {
+		name   string
+		f      func()
+		allocs float64
+	}{
+		{"an int > 255", func() { big++; boxSink = big }, 1},
+		{"an int < 256", func() { small ^= 1; boxSink = small }, 0},
+		{"a struct", func() { req.Status++; boxSink = req }, 1},
+		{"a pointer", func() { boxSink = &req }, 0},
+	}
+	for _, tt := range tests {
+		allocs := testing.AllocsPerRun(100, tt.f)
+		t.Logf("boxing %-14s %v allocations", tt.name, allocs)
+		if allocs != tt.allocs {
+			t.Errorf("boxing %s: %v allocations, want %v", tt.name, allocs, tt.allocs)
+		}
+	}
+}
+
+func TestFastLogger(t *testing.T) {
+	var out strings.Builder
+	l := &fastLogger{w: &out}
+	l.logRequest(requestInfo{Path: "/tour/welcome", Status: 200}, 1500*time.Microsecond)
+	l.begin("ints")
+	appendInt(l, "neg", int8(-128))
+	appendInt(l, "max", ^uint64(0))
+	appendString(l, "q", `say "hi"`)
+	l.end()
+	want := "request path=\"/tour/welcome\" status=200 took_us=1500\n" +
+		"ints neg=-128 max=18446744073709551615 q=\"say \\\"hi\\\"\"\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// === Interface embedding and composition ===
+
+// An interface can embed other interfaces - its method set is the union of theirs.
+// This is how the io package builds bigger interfaces out of the single method ones:
+//
+//	type ReadWriter interface {
+//		Reader
+//		Writer
+//	}
+//
+// A type implements ReadWriter if it has both Read and Write (still implicit, no "implements" keyword)
+
+// Ex. compile time checks that a type implements an interface
+// Assigning to the blank identifier costs nothing at runtime, but fails to compile if a method is missing
+var _ io.ReadWriter = (*bytes.Buffer)(nil)
+var _ io.Writer = (*CountingWriter)(nil)
+
+// Ex. a custom multi-method interface composed from smaller ones
+
+type Getter interface {
+	Get(key string) (string, error)
+}
+
+type Setter interface {
+	Set(key, value string) error
+}
+
+type Deleter interface {
+	Delete(key string) error
+}
+
+// Store is every operation together. Only code that really needs all three should ask for a Store
+type Store interface {
+	Getter
+	Setter
+	Deleter
+}
+
+var errKeyNotFound = errors.New("key not found")
+
+// memoryStore is one concrete implementation. It never mentions Getter, Setter or Store
+type memoryStore struct {
+	data map[string]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{data: make(map[string]string)}
+}
+
+func (s *memoryStore) Get(key string) (string, error) {
+	v, ok := s.data[key]
+	if !ok {
+		return "", errKeyNotFound
+	}
+	return v, nil
+}
+
+func (s *memoryStore) Set(key, value string) error {
+	s.data[key] = value
+	return nil
+}
+
+func (s *memoryStore) Delete(key string) error {
+	delete(s.data, key)
+	return nil
+}
+
+// --- Small consumer side interfaces ---
+
+// "The bigger the interface, the weaker the abstraction"
+// Functions should ask for the smallest interface they need, defined next to the function (the consumer)
+// rather than in the package that implements it.
+// - makes it obvious what the function actually uses
+// - any type with the right method can be passed in, including ones written later or in tests
+
+// greet only reads, so it only asks for a Getter
+// (a read-only cache, a config file, or a fake with a hard coded map all satisfy it)
+func greet(users Getter, id string) string {
+	name, err := users.Get(id)
+	if err != nil {
+		return fmt.Sprintf("hello, stranger (%v)", err)
+	}
+	return "hello, " + name
+}
+
+// Ex. a one-off implementation for the consumer side interface, without touching memoryStore
+type fixedNames map[string]string
+
+func (f fixedNames) Get(key string) (string, error) {
+	if v, ok := f[key]; ok {
+		return v, nil
+	}
+	return "", errKeyNotFound
+}
+
+// A consumer can also define its own combination from the small pieces
+type getSetter interface {
+	Getter
+	Setter
+}
+
+func incrementVisits(s getSetter, page string) {
+	count := 0
+	if v, err := s.Get(page); err == nil {
+		fmt.Sscan(v, &count)
+	}
+	s.Set(page, fmt.Sprint(count+1))
+}
+
+// --- Struct embedding to satisfy an interface ---
+
+// Embedding an interface value in a struct "promotes" its methods onto the struct,
+// so the struct implements the interface without writing the methods itself.
+// Override only the method that needs to change (like decorating)
+type upperCaseReadWriter struct {
+	io.ReadWriter // Read is promoted unchanged from the embedded value
+}
+
+// Write is overridden, then delegates to the embedded value. It reports len(p), not what the embedded
+// Write returned: upper case can be a different length ("ı" is 2 bytes, "I" is 1; "ɐ" is 2, "Ɐ" is 3),
+// and the io.Writer contract is about p - io.Copy fails with a short or invalid write on any other n.
+// On an error it can't tell how much of p was written, so it reports 0
+func (u upperCaseReadWriter) Write(p []byte) (int, error) {
+	if _, err := u.ReadWriter.Write(bytes.ToUpper(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func interfaceEmbeddingExamples() {
+	store := newMemoryStore()
+
+	// *memoryStore can be used as a Store, Getter, Setter or Deleter
+	var s Store = store
+	s.Set("u1", "John Doe")
+
+	fmt.Println(greet(store, "u1"))
+	fmt.Println(greet(fixedNames{"u2": "Jack Eod"}, "u2"))
+	fmt.Println(greet(store, "missing"))
+
+	incrementVisits(store, "/home")
+	incrementVisits(store, "/home")
+	visits, _ := store.Get("/home")
+	fmt.Println("visits:", visits)
+
+	// A Store value can be narrowed to one of its embedded interfaces (no assertion needed)
+	var g Getter = s
+	name, _ := g.Get("u1")
+	fmt.Println("from narrowed Getter:", name)
+
+	// Going the other way (widening) needs a type assertion, since not every Getter is a Store
+	_, isStore := Getter(fixedNames{}).(Store)
+	fmt.Println("fixedNames is a Store?", isStore)
+
+	// io.ReadWriter composed from Reader + Writer
+	var rw io.ReadWriter = upperCaseReadWriter{&bytes.Buffer{}}
+	fmt.Fprint(rw, "shouting through an embedded buffer")
+	io.Copy(os.Stdout, rw)
+	fmt.Println()
+	n, err := io.Copy(rw, strings.NewReader("ıɐ")) // 2 + 2 bytes, in upper case 1 + 3
+	fmt.Println("copied:", n, err, "| read back:", rw.(upperCaseReadWriter).ReadWriter.(*bytes.Buffer).String())
+
+	// The same value works wherever just a Reader or just a Writer is needed
+	var r io.Reader = strings.NewReader("a strings.Reader is a Reader, but not a Writer")
+	_, isWriter := r.(io.Writer)
+	fmt.Println("strings.Reader is a Writer?", isWriter)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUpperCaseReadWriter(t *testing.T) {
+	for _, in := range []string{"shouting", "ıɐ", ""} { // "ıɐ" is 2 + 2 bytes, in upper case 1 + 3
+		var buf bytes.Buffer
+		n, err := io.Copy(upperCaseReadWriter{&buf}, strings.NewReader(in))
+		if err != nil || n != int64(len(in)) {
+			t.Errorf("io.Copy of %q: %d, %v - want %d, nil", in, n, err, len(in))
+		}
+		if want := strings.ToUpper(in); buf.String() != want {
+			t.Errorf("wrote %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+type failingReadWriter struct{ bytes.Buffer }
+
+var errDiskFull = errors.New("disk full")
+
+func (f *failingReadWriter) Write(p []byte) (int, error) { return len(p) / 2, errDiskFull }
+
+func TestUpperCaseReadWriterError(t *testing.T) {
+	n, err := upperCaseReadWriter{&failingReadWriter{}}.Write([]byte("abcd"))
+	if n != 0 || !errors.Is(err, errDiskFull) {
+		t.Errorf("failed write: %d, %v - want 0, errDiskFull", n, err)
+	}
+}
+
+func TestConsumerInterfaces(t *testing.T) {
+	store := newMemoryStore()
+	store.Set("u1", "John Doe")
+	for _, c := range []struct {
+		users Getter
+		id    string
+		want  string
+	}{
+		{store, "u1", "hello, John Doe"},
+		{fixedNames{"u2": "Jack Eod"}, "u2", "hello, Jack Eod"}, // any Getter will do
+		{store, "missing", "hello, stranger (key not found)"},
+	} {
+		if got := greet(c.users, c.id); got != c.want {
+			t.Errorf("greet(%T, %q) = %q, want %q", c.users, c.id, got, c.want)
+		}
+	}
+
+	incrementVisits(store, "/home")
+	incrementVisits(store, "/home")
+	if v, err := store.Get("/home"); v != "2" || err != nil {
+		t.Errorf("visits after 2 increments: %q, %v", v, err)
+	}
+	store.Delete("/home")
+	if _, err := store.Get("/home"); !errors.Is(err, errKeyNotFound) {
+		t.Errorf("Get after Delete: %v, want errKeyNotFound", err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// --- Beyond Stringer: fmt.Formatter and fmt.GoStringer ---
+
+// String() is used for %v and %s, but it returns the same text for every verb and flag.
+// The fmt package checks for two more interfaces (in the fmt package, like Stringer):
+//
+//	type GoStringer interface {
+//		GoString() string // used for %#v (Go syntax representation)
+//	}
+//
+//	type Formatter interface {
+//		Format(f State, verb rune) // full control over every verb and flag
+//	}
+//
+// Priority when printing a value:
+// 1. Formatter - if implemented, Format is called for EVERY verb, even %#v (only %T and %p skip it)
+// 2. GoStringer - for %#v only
+// 3. error, then Stringer - for %v, %s, %q, %x, %X
+
+// Ex. GoStringer on its own - %#v prints a constructor call instead of the default struct literal
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// String writes the sign first, then the absolute amount: -150 cents is -1.50, not -1.-50 (/ and % both
+// keep the sign). The absolute value is a uint64, as -math.MinInt64 doesn't fit in an int64
+func (m Money) String() string {
+	sign, abs := "", uint64(m.Cents)
+	if m.Cents < 0 {
+		sign, abs = "-", -abs
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, abs/100, abs%100, m.Currency)
+}
+
+func (m Money) GoString() string {
+	return fmt.Sprintf("NewMoney(%d, %q)", m.Cents, m.Currency)
+}
+
+// Ex. Formatter - different output for %v, %+v, %s, %q and %#v
+type Book struct {
+	Title  string
+	Author string
+	Year   int
+}
+
+func (b Book) GoString() string {
+	return fmt.Sprintf("Book{Title: %q, Author: %q, Year: %d}", b.Title, b.Author, b.Year)
+}
+
+// f.Flag reports whether a flag (ex. '+' or '#') was used with the verb,
+// and f is also an io.Writer, so the output is written straight into it
+func (b Book) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			// Formatter wins over GoStringer, so hand off to it explicitly
+			io.WriteString(f, b.GoString())
+		case f.Flag('+'):
+			fmt.Fprintf(f, "%s by %s (%d)", b.Title, b.Author, b.Year)
+		default:
+			fmt.Fprintf(f, "%s (%d)", b.Title, b.Year)
+		}
+	case 's':
+		io.WriteString(f, b.Title)
+	case 'q':
+		io.WriteString(f, strconv.Quote(b.Title))
+	default:
+		// unsupported verbs print like the fmt package's own errors, ex. %!d(main.Book=...)
+		fmt.Fprintf(f, "%%!%c(main.Book=%s)", verb, b.Title)
+	}
+}
+
+func formatterExamples() {
+	m := Money{Cents: 1999, Currency: "CAD"}
+	fmt.Printf("%%v:  %v\n", m)
+	fmt.Printf("%%#v: %#v\n", m)
+	fmt.Println("refunds:", Money{Cents: -150, Currency: "CAD"}, Money{Cents: -5, Currency: "CAD"}) // -1.50 and -0.05
+
+	b := Book{Title: "The Go Programming Language", Author: "Donovan & Kernighan", Year: 2015}
+	for _, verb := range []string{"%v", "%+v", "%s", "%q", "%#v", "%d"} {
+		fmt.Printf("%-4s -> %s\n", verb, fmt.Sprintf(verb, b))
+	}
+
+	// Formatting is applied recursively, so it also works inside slices and structs
+	fmt.Printf("%+v\n", []Book{b})
+	// (formatting_test.go checks the exact text for each verb)
+}
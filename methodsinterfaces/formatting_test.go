@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestBookFormat(t *testing.T) {
+	b := Book{Title: "The Go Programming Language", Author: "Donovan & Kernighan", Year: 2015}
+	for _, c := range []struct {
+		verb string
+		arg  any
+		want string
+	}{
+		{"%v", b, "The Go Programming Language (2015)"},
+		{"%+v", b, "The Go Programming Language by Donovan & Kernighan (2015)"},
+		{"%s", b, "The Go Programming Language"},
+		{"%q", b, `"The Go Programming Language"`},
+		{"%#v", b, `Book{Title: "The Go Programming Language", Author: "Donovan & Kernighan", Year: 2015}`},
+		{"%d", b, "%!d(main.Book=The Go Programming Language)"},
+		{"%+v", []Book{b}, "[The Go Programming Language by Donovan & Kernighan (2015)]"}, // applied inside a slice too
+	} {
+		if got := fmt.Sprintf(c.verb, c.arg); got != c.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", c.verb, got, c.want)
+		}
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	for _, c := range []struct {
+		cents int64
+		want  string
+	}{
+		{1999, "19.99 CAD"},
+		{5, "0.05 CAD"},
+		{0, "0.00 CAD"},
+		{-150, "-1.50 CAD"},
+		{-5, "-0.05 CAD"},
+		{math.MinInt64, "-92233720368547758.08 CAD"},
+	} {
+		m := Money{Cents: c.cents, Currency: "CAD"}
+		if got := fmt.Sprintf("%v", m); got != c.want {
+			t.Errorf("%%v of %d cents = %q, want %q", c.cents, got, c.want)
+		}
+	}
+	if got, want := fmt.Sprintf("%#v", Money{Cents: -150, Currency: "CAD"}), `NewMoney(-150, "CAD")`; got != want {
+		t.Errorf("%%#v = %q, want %q", got, want)
+	}
+}
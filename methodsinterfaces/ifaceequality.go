@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// === Interface equality and comparability ===
+
+// Two interface values are == when:
+// - both are nil, or
+// - they have the SAME dynamic type, and the dynamic values are == (using that type's rules)
+//
+// Comparing interfaces always compiles, since the compiler can't know what they will hold.
+// So if both hold the same uncomparable type (slice, map, func, or a struct/array containing one),
+// == panics at runtime: "runtime error: comparing uncomparable type []int".
+// The same goes for using such a value as a key in a map[any]V ("hash of unhashable type").
+
+// Holds a slice, so the struct type is not comparable
+type tagged struct {
+	Name string
+	Tags []string
+}
+
+// Only has comparable fields - but the field is an interface, so it depends on what it holds
+type boxed struct {
+	V any
+}
+
+// Comparable with generics: since Go 1.20 the interface type any satisfies comparable,
+// so this compiles with T = any and can still panic at runtime
+func equalGeneric[T comparable](a, b T) bool {
+	return a == b
+}
+
+// panics reports whether f panicked
+func panics(f func()) (didPanic bool) {
+	defer func() {
+		if recover() != nil {
+			didPanic = true
+		}
+	}()
+	f()
+	return false
+}
+
+func interfaceEqualityExamples() {
+	// ifaceequality_test.go has the full table - every case below and more, with each guard checked against it
+	show := func(name string, a, b any) {
+		var got string
+		if panics(func() { got = fmt.Sprint(a == b) }) {
+			got = "panic"
+		}
+		fmt.Printf("%-46s %s\n", name+":", got)
+	}
+	show("1 == 1", 1, 1)
+	show("1 == int64(1) (different types)", 1, int64(1))
+	show("Vertex{1, 2} == Vertex{1, 2}", Vertex{1, 2}, Vertex{1, 2})
+	show("&Vertex{1, 2} == &Vertex{1, 2}", &Vertex{1, 2}, &Vertex{1, 2})
+	show("errors.New twice with the same text", errors.New("not found"), errors.New("not found"))
+	show("nil == (*Vertex)(nil)", nil, (*Vertex)(nil))
+	show("[]int{1} == []int{1}", []int{1}, []int{1})
+	show("boxed{[]int{1}} == boxed{[]int{1}}", boxed{[]int{1}}, boxed{[]int{1}})
+	show("[]int{1} == 1 (different types - no panic)", []int{1}, 1)
+	fmt.Println()
+
+	// --- Guards ---
+
+	fmt.Println("reflect.TypeOf([]int{}).Comparable():", reflect.TypeOf([]int{}).Comparable())
+	fmt.Println("reflect.TypeOf(tagged{}).Comparable():", reflect.TypeOf(tagged{}).Comparable())
+	// the type check isn't enough when an interface is involved: a struct with an interface field counts as
+	// comparable even when it holds a slice. reflect.Value.Comparable (Go 1.20+) looks inside - a guard built
+	// on it is safeEqual, in ifaceequality_test.go
+	fmt.Println("reflect.TypeOf(boxed{[]int{1}}).Comparable():", reflect.TypeOf(boxed{[]int{1}}).Comparable(),
+		"| reflect.ValueOf(...).Comparable():", reflect.ValueOf(boxed{[]int{1}}).Comparable())
+
+	// --- Map keys ---
+
+	seen := map[any]bool{}
+	seen[Vertex{1, 2}] = true
+	seen["x"] = true
+	fmt.Println("comparable keys in a map[any]bool:", len(seen))
+	fmt.Println("slice as a map[any] key panics:", panics(func() { seen[[]int{1}] = true }))
+
+	// --- Generics don't catch it either ---
+
+	fmt.Println("equalGeneric(1, 1):", equalGeneric(1, 1))
+	fmt.Println("equalGeneric[any] with slices panics:", panics(func() {
+		equalGeneric[any]([]int{1}, []int{1})
+	}))
+}
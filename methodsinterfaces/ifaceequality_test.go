@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// safeEqual compares a and b without panicking. ok is false when they can't be compared.
+// reflect.TypeOf(x).Comparable() is about the (static) type: []int is never comparable, but a struct
+// with an interface field counts as comparable even when it holds a slice. reflect.Value.Comparable looks inside
+func safeEqual(a, b any) (equal, ok bool) {
+	if a == nil || b == nil {
+		return a == b, true
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false, true // different dynamic types are never equal - and == doesn't panic for them
+	}
+	if !reflect.ValueOf(a).Comparable() {
+		return false, false
+	}
+	return a == b, true
+}
+
+type equalityCase struct {
+	name string
+	a, b any
+	want string // "equal", "not equal" or "panic"
+}
+
+func equalityCases() []equalityCase {
+	sentinel := errors.New("not found")
+	v := &Vertex{1, 2}
+	return []equalityCase{
+		{"same type and value", 1, 1, "equal"},
+		{"same value, different type (int vs int64)", 1, int64(1), "not equal"},
+		{"1 vs 1.0", 1, 1.0, "not equal"}, // untyped constants get their default type: int and float64
+		{"named type vs underlying type", Latitude(5), 5.0, "not equal"},
+		{"structs compared field by field", Vertex{1, 2}, Vertex{1, 2}, "equal"},
+		{"same pointer", v, v, "equal"},
+		{"different pointers to equal values", &Vertex{1, 2}, &Vertex{1, 2}, "not equal"},
+		{"errors.New twice with the same text", errors.New("not found"), errors.New("not found"), "not equal"},
+		{"same sentinel error", sentinel, sentinel, "equal"},
+		{"nil vs nil", nil, nil, "equal"},
+		{"nil vs typed nil *Vertex", nil, (*Vertex)(nil), "not equal"},
+		{"typed nil vs typed nil", (*Vertex)(nil), (*Vertex)(nil), "equal"},
+		{"slice vs slice", []int{1}, []int{1}, "panic"},
+		{"nil slice vs nil slice", []int(nil), []int(nil), "panic"}, // still an uncomparable type
+		{"map vs map", map[string]int{}, map[string]int{}, "panic"},
+		{"func vs func", fmt.Println, fmt.Println, "panic"},
+		{"struct containing a slice", tagged{"a", nil}, tagged{"a", nil}, "panic"},
+		{"array of slices", [1][]int{}, [1][]int{}, "panic"},
+		{"struct with an interface field holding a slice", boxed{[]int{1}}, boxed{[]int{1}}, "panic"},
+		{"struct with an interface field holding an int", boxed{1}, boxed{1}, "equal"},
+		{"struct with interface fields holding different types", boxed{1}, boxed{"1"}, "not equal"},
+		{"slice vs int (different types - no panic)", []int{1}, 1, "not equal"},
+		{"slice vs nil", []int{1}, nil, "not equal"},
+	}
+}
+
+func interfaceEqual(a, b any) (got string) {
+	defer func() {
+		if recover() != nil {
+			got = "panic"
+		}
+	}()
+	if a == b {
+		return "equal"
+	}
+	return "not equal"
+}
+
+func TestInterfaceEquality(t *testing.T) {
+	for _, c := range equalityCases() {
+		if got := interfaceEqual(c.a, c.b); got != c.want {
+			t.Errorf("%s: %#v == %#v is %s, want %s", c.name, c.a, c.b, got, c.want)
+		}
+	}
+}
+
+// safeEqual agrees with == wherever == doesn't panic, and reports ok = false exactly where it does
+func TestSafeEqual(t *testing.T) {
+	for _, c := range equalityCases() {
+		equal, ok := safeEqual(c.a, c.b)
+		switch {
+		case c.want == "panic" && ok:
+			t.Errorf("%s: safeEqual = %t, ok; want ok = false", c.name, equal)
+		case c.want != "panic" && (!ok || equal != (c.want == "equal")):
+			t.Errorf("%s: safeEqual = %t, %t; want %s", c.name, equal, ok, c.want)
+		}
+	}
+}
+
+// The static type check misses an interface field holding a slice; the value check doesn't
+func TestComparableGuards(t *testing.T) {
+	for _, c := range []struct {
+		v             any
+		typeOK, valOK bool
+	}{
+		{1, true, true},
+		{[]int{1}, false, false},
+		{tagged{}, false, false},
+		{boxed{1}, true, true},
+		{boxed{[]int{1}}, true, false},
+	} {
+		if got := reflect.TypeOf(c.v).Comparable(); got != c.typeOK {
+			t.Errorf("reflect.TypeOf(%#v).Comparable() = %t, want %t", c.v, got, c.typeOK)
+		}
+		if got := reflect.ValueOf(c.v).Comparable(); got != c.valOK {
+			t.Errorf("reflect.ValueOf(%#v).Comparable() = %t, want %t", c.v, got, c.valOK)
+		}
+	}
+}
+
+func TestUncomparableMapKeyAndGenerics(t *testing.T) {
+	seen := map[any]bool{Vertex{1, 2}: true, "x": true}
+	if !seen[Vertex{1, 2}] || len(seen) != 2 {
+		t.Errorf("comparable keys: %v", seen)
+	}
+	if !panics(func() { seen[[]int{1}] = true }) {
+		t.Error("a slice as a map[any] key didn't panic")
+	}
+	if !equalGeneric(1, 1) || equalGeneric("a", "b") {
+		t.Error("equalGeneric on comparable types is wrong")
+	}
+	if !panics(func() { equalGeneric[any]([]int{1}, []int{1}) }) {
+		t.Error("equalGeneric[any] with slices didn't panic")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// === Method sets: value vs pointer receivers ===
+
+// methodExamples() shows that v.scaleOriginal(10) works on a Vertex VALUE,
+// because Go rewrites it to (&v).scaleOriginal(10). That shortcut only applies to method CALLS
+// on addressable values - it does not apply when asking "does this type implement an interface?"
+
+// The rules (the "method set" of a type):
+// - the method set of T contains only the methods with value receivers (func (v T) ...)
+// - the method set of *T contains the methods with value receivers AND pointer receivers
+// A type implements an interface only if the interface's methods are all in its method set.
+
+// Vertex has:
+// - abs()              value receiver   -> in the method set of Vertex and *Vertex
+// - scaleCopy()        value receiver   -> in the method set of Vertex and *Vertex
+// - scaleOriginal()    pointer receiver -> only in the method set of *Vertex
+// - PrintX()           pointer receiver -> only in the method set of *Vertex
+
+type Scaler interface {
+	scaleOriginal(f float64)
+}
+
+// Why the rule exists: an interface holds a COPY of a value.
+// If Vertex values could be stored as a Scaler, scaleOriginal would modify the copy inside the interface,
+// and the change would silently be lost. So Go only lets *Vertex (which points at the original) be a Scaler.
+
+// Compile time checks (these compile):
+var _ Abser = Vertex{}   // abs has a value receiver
+var _ Abser = &Vertex{}  // *Vertex also has abs (Go dereferences the pointer automatically)
+var _ Scaler = &Vertex{} // scaleOriginal has a pointer receiver
+var _ I = &Vertex{}      // PrintX has a pointer receiver
+
+// The lines that do NOT compile are in methodsets_nocompile.go,
+// which is only built with the nocompile build tag:
+//
+//	go build -tags nocompile .
+//
+// prints errors like:
+//
+//	cannot use Vertex{} (value of struct type Vertex) as Scaler value in variable declaration:
+//	Vertex does not implement Scaler (method scaleOriginal has pointer receiver)
+
+func methodSetExamples() {
+	// reflect can check the same rules at runtime.
+	// reflect.TypeFor[Scaler]() is the interface type itself (not the type of a value stored in it)
+	interfaces := []reflect.Type{
+		reflect.TypeFor[Abser](),
+		reflect.TypeFor[Scaler](),
+		reflect.TypeFor[I](),
+	}
+	types := []reflect.Type{
+		reflect.TypeFor[Vertex](),
+		reflect.TypeFor[*Vertex](),
+	}
+
+	for _, t := range types {
+		for _, iface := range interfaces {
+			fmt.Printf("%-15s implements %-22s %t\n", t, iface.String()+"?", t.Implements(iface))
+		}
+	}
+
+	// reflect only lists EXPORTED methods: MarshalText for Vertex (textmarshal.go), and for *Vertex
+	// PrintX and UnmarshalText as well - the pointer receivers, plus the value ones - so 1 and 3
+	fmt.Println("exported methods of Vertex:", reflect.TypeFor[Vertex]().NumMethod())
+	fmt.Println("exported methods of *Vertex:", reflect.TypeFor[*Vertex]().NumMethod())
+
+	// --- Calls vs interface satisfaction ---
+
+	v := Vertex{3, 4}
+
+	// OK - v is addressable (a variable), so Go takes its address for the call
+	v.scaleOriginal(2)
+
+	// OK - storing the pointer in the interface
+	var s Scaler = &v
+	s.scaleOriginal(0.5)
+	fmt.Println("after scaling through the interface:", v)
+
+	// Values that are NOT addressable can't use the automatic &:
+	// map elements and function return values. These do not compile:
+	//
+	//	m := map[string]Vertex{"a": {1, 2}}
+	//	m["a"].scaleOriginal(2)           // cannot call pointer method scaleOriginal on Vertex
+	//	Vertex{1, 2}.scaleOriginal(2)     // same - a composite literal is not addressable
+	//	newVertex().scaleOriginal(2)      // same - a return value is not addressable
+	//
+	// (Storing *Vertex in the map, map[string]*Vertex, avoids the problem)
+	pm := map[string]*Vertex{"a": {1, 2}}
+	pm["a"].scaleOriginal(2)
+	fmt.Println("through a map of pointers:", *pm["a"])
+
+	// --- Value receiver methods through a nil pointer panic ---
+
+	// abs has a value receiver, so calling it through a *Vertex dereferences the pointer first.
+	// With a nil *Vertex there is nothing to copy, so it panics (unlike PrintX which checks for nil itself)
+	var nilV *Vertex
+	var a Abser = nilV
+	func() {
+		defer func() {
+			fmt.Println("recovered from calling a value method on a nil pointer:", recover())
+		}()
+		a.abs()
+	}()
+}
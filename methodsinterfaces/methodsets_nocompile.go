@@ -0,0 +1,25 @@
+//go:build nocompile
+
+package main
+
+// This file is excluded from normal builds by the build constraint above.
+// Build it on purpose to see the compiler enforce the method set rules from methodsets.go:
+//
+//	go build -tags nocompile .
+//
+// Every declaration below is an error.
+
+// Vertex does not implement Scaler (method scaleOriginal has pointer receiver)
+var _ Scaler = Vertex{}
+
+// Vertex does not implement I (method PrintX has pointer receiver)
+var _ I = Vertex{}
+
+func notAddressable() {
+	// cannot call pointer method scaleOriginal on Vertex
+	m := map[string]Vertex{"a": {1, 2}}
+	m["a"].scaleOriginal(2)
+
+	// cannot call pointer method scaleOriginal on Vertex
+	Vertex{1, 2}.scaleOriginal(2)
+}
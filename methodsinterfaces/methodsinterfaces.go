@@ -78,6 +78,7 @@ func methodExamples() {
 
 	// "In general, all methods on a given type should have either value or pointer receivers,
 	// but not a mixture of both"
+	// (receivers.go measures the difference: copies of a large struct vs a pointer, and when a pointer means the heap)
 
 	fmt.Printf("Original vertex val after scaling using pointer reciever method: %g\n", v.abs())
 }
@@ -120,6 +121,7 @@ type Person struct {
 // - Stringer is a type that can define itself as a string.
 // - The fmt package uses this interface to print values (interface is defined where it is used)
 // - You create the implementation for a concrete type
+// - For an enum (a block of iota constants), the stringer tool can write String instead (basics/main/uploadstatus.go)
 func (p Person) String() string {
 	return fmt.Sprintf("%v (%v years)", p.Name, p.Age)
 }
@@ -211,6 +213,8 @@ func interfaceExamples() {
 	var p Person = Person{"John Doe", 35}
 	fmt.Printf("Custom string format of type %T:\n%v\n", p, p)
 
+	// For different output per verb (%v vs %+v vs %#v), see fmt.Formatter and fmt.GoStringer in formatting.go
+
 	// --- The error interface ---
 
 	// Go programs express error state with error values.
@@ -220,7 +224,7 @@ func interfaceExamples() {
 	// Functions often return an error value, and calling code should handle errors by
 	// explicitly testing whether the error equals nil.
 
-	// Ex.
+	// Ex. (the strconvpkg module has the rest of strconv: bases, bit sizes, and the *NumError inside errAtoi)
 	val, errAtoi := strconv.Atoi("42")
 
 	// A nil error denotes success; a non-nil error denotes failure.
@@ -244,6 +248,8 @@ func interfaceExamples() {
 
 	// This method populates a given byte slice with data, then returns the num bytes populated (n) and an error value
 	// Returns an io.EOF error when the stream ends
+	// n can be less than len(b) even before the end - a network connection returns what has arrived so far,
+	// which is why a protocol over one needs io.ReadFull and a way to mark where messages end (see the framing module)
 
 	// Ex. implemenation of the Reader interface - strings package
 	reader := strings.NewReader("Hello, Reader")
@@ -251,7 +257,7 @@ func interfaceExamples() {
 	// set the max num bytes that can be read per iteration to 8 (equal to the set length of the slice)
 	var bytes []byte = make([]byte, 8)
 
-	// like StringBuilder in Java
+	// like StringBuilder in Java (stringspkg's BenchmarkConcat measures it against +=)
 	var readValueBdr strings.Builder
 
 	var err error
@@ -272,4 +278,20 @@ func interfaceExamples() {
 func main() {
 	// methodExamples()
 	interfaceExamples()
+	// sortingExamples()
+	// writerExamples()
+	// interfaceEmbeddingExamples()
+	// formatterExamples()
+	// methodSetExamples()
+	// typedNilExamples()
+	// shapeExamples()
+	// textMarshalerExamples()
+	// uploaderRegistryExamples()
+	// interfaceEqualityExamples()
+	// randomAccessExamples()
+	// nilReceiverExamples()
+	// loggingAllocExample()
+	// interfaceUpgradeExamples()
+	// receiverCostExamples()
+	// escapeAnalysisExamples()
 }
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// === Nil receivers as a pattern ===
+
+// From the interface notes: calling a method on a nil pointer still calls the method, with a nil receiver
+// (PrintX checks for nil and prints "<nil>" instead of panicking).
+// That turns out to be useful - if every method handles nil, then nil is a valid, ready-to-use value:
+// - a nil *IntList is the empty list, a nil *Tree is the empty tree (like how a nil slice or map can be read from)
+// - callers don't need "if list != nil" checks everywhere
+// - a zero value struct with a nil *Tree field works without a constructor
+
+// --- Linked list ---
+
+type IntList struct {
+	Val  int
+	Next *IntList
+}
+
+// Push returns the new head. A pointer receiver can't make a nil list non-nil in place
+// (the receiver is a copy of the caller's pointer), so methods that "modify" an empty list return the result
+func (l *IntList) Push(v int) *IntList {
+	return &IntList{Val: v, Next: l}
+}
+
+func (l *IntList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return 1 + l.Next.Len() // l.Next may be nil - that call handles it
+}
+
+func (l *IntList) Contains(v int) bool {
+	for n := l; n != nil; n = n.Next {
+		if n.Val == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *IntList) String() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for n := l; n != nil; n = n.Next {
+		if n != l {
+			sb.WriteString(" -> ")
+		}
+		sb.WriteString(strconv.Itoa(n.Val))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// --- Binary search tree ---
+
+// A nil *Tree is an empty tree, and every subtree is either a *Tree or nil,
+// so the recursive methods need no special cases for missing children
+type Tree struct {
+	Left, Right *Tree
+	Val         int
+}
+
+func (t *Tree) Insert(v int) *Tree {
+	if t == nil {
+		return &Tree{Val: v}
+	}
+	switch {
+	case v < t.Val:
+		t.Left = t.Left.Insert(v)
+	case v > t.Val:
+		t.Right = t.Right.Insert(v)
+	}
+	return t
+}
+
+func (t *Tree) Contains(v int) bool {
+	switch {
+	case t == nil:
+		return false
+	case v < t.Val:
+		return t.Left.Contains(v)
+	case v > t.Val:
+		return t.Right.Contains(v)
+	}
+	return true
+}
+
+func (t *Tree) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.Left.Len() + 1 + t.Right.Len()
+}
+
+func (t *Tree) Height() int {
+	if t == nil {
+		return 0
+	}
+	return 1 + max(t.Left.Height(), t.Right.Height())
+}
+
+// Walk visits the values in order
+func (t *Tree) Walk(visit func(int)) {
+	if t == nil {
+		return
+	}
+	t.Left.Walk(visit)
+	visit(t.Val)
+	t.Right.Walk(visit)
+}
+
+func (t *Tree) String() string {
+	if t == nil {
+		return "()"
+	}
+	return fmt.Sprintf("(%v %d %v)", t.Left, t.Val, t.Right)
+}
+
+// --- Optional dependency ---
+
+// A nil *Logger means "logging disabled". Code holding a logger just calls it,
+// instead of checking for nil before every call (or needing a separate no-op type)
+type Logger struct {
+	prefix string
+	lines  []string
+}
+
+func (l *Logger) Logf(format string, args ...any) {
+	if l == nil {
+		return
+	}
+	l.lines = append(l.lines, l.prefix+fmt.Sprintf(format, args...))
+}
+
+type Importer struct {
+	Log *Logger // optional - the zero value Importer works
+	set *Tree
+}
+
+func (imp *Importer) Import(values ...int) {
+	for _, v := range values {
+		if imp.set.Contains(v) {
+			imp.Log.Logf("skipping duplicate %d", v)
+			continue
+		}
+		imp.set = imp.set.Insert(v)
+	}
+	imp.Log.Logf("imported, %d unique values", imp.set.Len())
+}
+
+func nilReceiverExamples() {
+	// The zero value of each type is usable as is
+	var list *IntList
+	fmt.Printf("empty list: %v, len %d, contains 3: %t\n", list, list.Len(), list.Contains(3))
+
+	list = list.Push(3).Push(2).Push(1)
+	fmt.Printf("list: %v, len %d, contains 3: %t\n", list, list.Len(), list.Contains(3))
+
+	var tree *Tree
+	fmt.Printf("empty tree: %v, len %d, height %d, contains 5: %t\n", tree, tree.Len(), tree.Height(), tree.Contains(5))
+
+	for _, v := range []int{5, 3, 8, 1, 4, 9, 5} {
+		tree = tree.Insert(v)
+	}
+	fmt.Printf("tree: %v, len %d, height %d, contains 4: %t, contains 7: %t\n",
+		tree, tree.Len(), tree.Height(), tree.Contains(4), tree.Contains(7))
+
+	var sorted []int
+	tree.Walk(func(v int) { sorted = append(sorted, v) })
+	fmt.Println("in order:", sorted)
+
+	// "Walk" on a nil subtree, ex. the left child of a leaf, is simply nothing
+	tree.Left.Left.Left.Walk(func(int) { fmt.Println("never called") })
+
+	// A nil *Tree still satisfies fmt.Stringer - String is called with the nil receiver.
+	// (The interface holding it is not nil, see typednil.go)
+	var s fmt.Stringer = (*Tree)(nil)
+	fmt.Println("nil *Tree as a Stringer:", s.String(), "| interface == nil:", s == nil)
+
+	// Optional logger - both versions run the same code
+	quiet := Importer{}
+	quiet.Import(1, 2, 2, 3)
+	fmt.Println("without logger, unique values:", quiet.set.Len())
+
+	logged := Importer{Log: &Logger{prefix: "[import] "}}
+	logged.Import(1, 2, 2, 3)
+	fmt.Println("with logger:", strings.Join(logged.Log.lines, "; "))
+
+	// Where it doesn't help: field access still panics, only method calls are fine
+	// fmt.Println(list.Next.Next.Next.Val) // panic: nil pointer dereference
+}
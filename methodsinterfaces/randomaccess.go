@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// === Random access: io.ReaderAt and io.Seeker ===
+
+// io.Reader reads from wherever the previous Read stopped. Two more interfaces allow jumping around:
+//
+//	type ReaderAt interface {
+//		ReadAt(p []byte, off int64) (n int, err error)
+//	}
+//
+//	type Seeker interface {
+//		Seek(offset int64, whence int) (int64, error)
+//	}
+//
+// - Seek moves the current position (whence: io.SeekStart, io.SeekCurrent or io.SeekEnd), and later Reads continue from there
+// - ReadAt reads at an absolute offset and does NOT use or change the current position,
+//   so several goroutines can call ReadAt on the same *os.File at once (Read + Seek would race on the shared position).
+//   Also, unlike Read, ReadAt returns an error whenever n < len(p)
+//
+// *os.File, *bytes.Reader and *strings.Reader implement both.
+
+// --- Fixed-size records ---
+
+// Every record takes exactly recordSize bytes, so record i starts at offset i*recordSize -
+// no need to read the records before it
+const (
+	recordSize = 32
+	nameSize   = recordSize - 4 - 8
+)
+
+type Record struct {
+	ID    uint32
+	Score float64
+	Name  string // at most nameSize bytes, padded with zeros in the file
+}
+
+func (r Record) marshal() []byte {
+	buf := make([]byte, recordSize)
+	binary.BigEndian.PutUint32(buf[0:4], r.ID)
+	binary.BigEndian.PutUint64(buf[4:12], math.Float64bits(r.Score))
+	copy(buf[12:], r.Name)
+	return buf
+}
+
+func unmarshalRecord(buf []byte) Record {
+	return Record{
+		ID:    binary.BigEndian.Uint32(buf[0:4]),
+		Score: math.Float64frombits(binary.BigEndian.Uint64(buf[4:12])),
+		Name:  strings.TrimRight(string(buf[12:recordSize]), "\x00"),
+	}
+}
+
+// readRecord works with any io.ReaderAt - a file, or bytes in memory
+func readRecord(r io.ReaderAt, i int) (Record, error) {
+	buf := make([]byte, recordSize)
+	if _, err := r.ReadAt(buf, int64(i)*recordSize); err != nil {
+		return Record{}, fmt.Errorf("record %d: %w", i, err)
+	}
+	return unmarshalRecord(buf), nil
+}
+
+// writeRecordFixture generates a file of n records with predictable contents
+func writeRecordFixture(path string, n int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	for i := range n {
+		rec := Record{ID: uint32(i), Score: float64(i%100) / 4, Name: fmt.Sprintf("user-%04d", i)}
+		if _, err := f.Write(rec.marshal()); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// --- Concurrent chunked reading ---
+
+// chunkSum splits the records into one range per worker, and each goroutine reads its own range
+// through an io.SectionReader (a Reader over a part of a ReaderAt).
+// The ranges are whole records, so no record is split between two workers.
+// workers is clamped to between 1 and the number of records: 0 workers still reads, and more workers
+// than records start one per record
+func chunkSum(r io.ReaderAt, size int64, workers int) (float64, error) {
+	records := size / recordSize
+	if records == 0 {
+		return 0, nil
+	}
+	workers = int(min(max(int64(workers), 1), records))
+	perWorker := (records + int64(workers) - 1) / int64(workers)
+
+	var wg sync.WaitGroup
+	sums := make([]float64, workers)
+	errs := make([]error, workers)
+	for w := range workers {
+		start := int64(w) * perWorker
+		end := min(start+perWorker, records)
+		if start >= end {
+			continue
+		}
+		wg.Go(func() {
+			section := io.NewSectionReader(r, start*recordSize, (end-start)*recordSize)
+			buf := make([]byte, recordSize)
+			for {
+				_, err := io.ReadFull(section, buf)
+				if err == io.EOF {
+					return
+				}
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				sums[w] += unmarshalRecord(buf).Score
+			}
+		})
+	}
+	wg.Wait()
+
+	total := 0.0
+	for _, s := range sums {
+		total += s
+	}
+	return total, errors.Join(errs...)
+}
+
+// sequentialSum is the simple version to check chunkSum against
+func sequentialSum(r io.Reader) (float64, error) {
+	buf := make([]byte, recordSize)
+	total := 0.0
+	for {
+		_, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		total += unmarshalRecord(buf).Score
+	}
+}
+
+func randomAccessExamples() {
+	dir, err := os.MkdirTemp("", "records")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	const n = 1000
+	path := filepath.Join(dir, "records.bin")
+	if err := writeRecordFixture(path, n); err != nil {
+		fmt.Println(err)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer f.Close()
+	info, _ := f.Stat()
+	fmt.Printf("fixture: %d records, %d bytes\n", n, info.Size())
+
+	// --- ReaderAt ---
+
+	rec, err := readRecord(f, 742)
+	fmt.Printf("record 742: %+v %v\n", rec, err)
+
+	_, err = readRecord(f, n) // one past the end
+	fmt.Println("past the end:", err, "| is io.EOF:", errors.Is(err, io.EOF))
+
+	// --- Seeker ---
+
+	// Jump to the last record, relative to the end of the file
+	pos, _ := f.Seek(-recordSize, io.SeekEnd)
+	buf := make([]byte, recordSize)
+	io.ReadFull(f, buf)
+	fmt.Printf("last record at offset %d: %+v\n", pos, unmarshalRecord(buf))
+
+	// io.SeekCurrent with offset 0 just reports the position - the Read above moved it to the end
+	pos, _ = f.Seek(0, io.SeekCurrent)
+	fmt.Println("position after reading:", pos, "== size:", pos == info.Size())
+
+	// Skip forward from the current position: to record 10, then skip 5 records
+	f.Seek(10*recordSize, io.SeekStart)
+	f.Seek(5*recordSize, io.SeekCurrent)
+	io.ReadFull(f, buf)
+	fmt.Println("record after seeking to 10 and skipping 5:", unmarshalRecord(buf).ID)
+
+	// ReadAt didn't move the position - the file is still right after record 15
+	readRecord(f, 0)
+	pos, _ = f.Seek(0, io.SeekCurrent)
+	fmt.Println("position after ReadAt (still after record 15):", pos)
+
+	// --- Chunked reading ---
+
+	f.Seek(0, io.SeekStart)
+	want, _ := sequentialSum(f)
+	fmt.Printf("sequentialSum: %.2f\n", want)
+	for _, workers := range []int{1, 3, 8, 2000} {
+		got, err := chunkSum(f, info.Size(), workers)
+		fmt.Printf("chunkSum with %4d workers: %.2f %v\n", workers, got, err)
+	}
+
+	// The same code on an in-memory ReaderAt
+	var mem bytes.Buffer
+	for i := range 10 {
+		mem.Write(Record{ID: uint32(i), Score: 1}.marshal())
+	}
+	memSum, _ := chunkSum(bytes.NewReader(mem.Bytes()), int64(mem.Len()), 4)
+	fmt.Println("chunkSum over a bytes.Reader:", memSum)
+}
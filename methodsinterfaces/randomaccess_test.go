@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestChunkSumWorkers(t *testing.T) {
+	var data bytes.Buffer
+	want := 0.0
+	for i := range 10 {
+		data.Write(Record{ID: uint32(i), Score: float64(i)}.marshal())
+		want += float64(i)
+	}
+	for _, workers := range []int{-1, 0, 1, 3, 4, 10, 11, 2000} {
+		got, err := chunkSum(bytes.NewReader(data.Bytes()), int64(data.Len()), workers)
+		if err != nil || got != want {
+			t.Errorf("%d workers: %v, %v - want %v", workers, got, err, want)
+		}
+	}
+	if got, err := chunkSum(bytes.NewReader(nil), 0, 0); got != 0 || err != nil {
+		t.Errorf("no records, no workers: %v, %v", got, err)
+	}
+}
+
+// openFixture writes a fixture of n records and opens it
+func openFixture(t *testing.T, n int) *os.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "records.bin")
+	if err := writeRecordFixture(path, n); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+// fixtureRecord is what writeRecordFixture writes as record i
+func fixtureRecord(i int) Record {
+	return Record{ID: uint32(i), Score: float64(i%100) / 4, Name: fmt.Sprintf("user-%04d", i)}
+}
+
+func TestReadRecordFromFixture(t *testing.T) {
+	const n = 1000
+	f := openFixture(t, n)
+	if info, err := f.Stat(); err != nil || info.Size() != n*recordSize {
+		t.Fatalf("fixture size %v, %v; want %d", info.Size(), err, n*recordSize)
+	}
+	for _, i := range []int{0, 1, 99, 100, 742, n - 1} {
+		if rec, err := readRecord(f, i); err != nil || rec != fixtureRecord(i) {
+			t.Errorf("readRecord(%d) = %+v, %v; want %+v", i, rec, err, fixtureRecord(i))
+		}
+	}
+	if _, err := readRecord(f, n); !errors.Is(err, io.EOF) {
+		t.Errorf("one past the end: %v, want io.EOF", err)
+	}
+	if _, err := readRecord(f, -1); err == nil {
+		t.Error("a negative index: no error")
+	}
+}
+
+// A file cut partway through its last record: ReadAt returns the bytes there are, and an error
+func TestReadRecordTruncated(t *testing.T) {
+	f := openFixture(t, 3)
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cut := bytes.NewReader(data[:len(data)-5])
+	if rec, err := readRecord(cut, 1); err != nil || rec != fixtureRecord(1) {
+		t.Errorf("a whole record before the cut: %+v, %v", rec, err)
+	}
+	if _, err := readRecord(cut, 2); !errors.Is(err, io.EOF) {
+		t.Errorf("the cut record: %v, want io.EOF with the short read", err)
+	}
+}
+
+func TestRecordNameTruncated(t *testing.T) {
+	long := Record{ID: 1, Name: strings.Repeat("x", nameSize+10)}
+	if got := unmarshalRecord(long.marshal()); got.Name != strings.Repeat("x", nameSize) {
+		t.Errorf("a long name read back as %q, want its first %d bytes", got.Name, nameSize)
+	}
+}
+
+func TestSeekFixture(t *testing.T) {
+	const n = 50
+	f := openFixture(t, n)
+	buf := make([]byte, recordSize)
+	readNext := func() Record {
+		t.Helper()
+		if _, err := io.ReadFull(f, buf); err != nil {
+			t.Fatal(err)
+		}
+		return unmarshalRecord(buf)
+	}
+	pos := func() int64 {
+		t.Helper()
+		p, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	if p, err := f.Seek(-recordSize, io.SeekEnd); err != nil || p != (n-1)*recordSize {
+		t.Fatalf("Seek to the last record = %d, %v", p, err)
+	}
+	if rec := readNext(); rec != fixtureRecord(n-1) {
+		t.Errorf("last record = %+v", rec)
+	}
+	if p := pos(); p != n*recordSize {
+		t.Errorf("position after the last record = %d, want the size %d", p, n*recordSize)
+	}
+	if _, err := io.ReadFull(f, buf); err != io.EOF {
+		t.Errorf("reading at the end: %v, want io.EOF", err)
+	}
+
+	f.Seek(10*recordSize, io.SeekStart)
+	f.Seek(5*recordSize, io.SeekCurrent)
+	if rec := readNext(); rec != fixtureRecord(15) {
+		t.Errorf("after seeking to 10 and skipping 5: %+v, want record 15", rec)
+	}
+	f.Seek(-2*recordSize, io.SeekCurrent)
+	if rec := readNext(); rec != fixtureRecord(14) {
+		t.Errorf("after stepping back 2: %+v, want record 14", rec)
+	}
+
+	// ReadAt neither uses nor moves the position
+	before := pos()
+	if rec, err := readRecord(f, 40); err != nil || rec != fixtureRecord(40) {
+		t.Errorf("readRecord(40) = %+v, %v", rec, err)
+	}
+	if p := pos(); p != before {
+		t.Errorf("position %d after ReadAt, want %d", p, before)
+	}
+	if rec := readNext(); rec != fixtureRecord(15) {
+		t.Errorf("next Read after ReadAt: %+v, want record 15", rec)
+	}
+
+	if _, err := f.Seek(-1, io.SeekStart); err == nil {
+		t.Error("seeking before the start: no error")
+	}
+}
+
+// ReadAt on one file from many goroutines at once (run with -race)
+func TestReadRecordConcurrent(t *testing.T) {
+	const n = 200
+	f := openFixture(t, n)
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := w; i < n; i += 8 {
+				if rec, err := readRecord(f, i); err != nil || rec != fixtureRecord(i) {
+					t.Errorf("readRecord(%d) = %+v, %v", i, rec, err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestChunkSumFixture(t *testing.T) {
+	f := openFixture(t, 1000)
+	want, err := sequentialSum(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := f.Stat()
+	for _, workers := range []int{1, 3, 8, 2000} {
+		if got, err := chunkSum(f, info.Size(), workers); err != nil || got != want {
+			t.Errorf("%d workers: %v, %v; want %v", workers, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// === Value vs pointer receivers: what each costs, measured ===
+
+// The advice in methodsinterfaces.go - a pointer receiver to modify the value, or when the struct is large,
+// and one kind of receiver per type - comes down to two costs:
+// - a value receiver is a copy of the struct on every call. For Vertex (16 bytes) that's two registers,
+//   less work than going through a pointer. For a struct of a few hundred bytes, it's a memcpy each call
+// - a pointer only costs something if it makes the value escape: if the compiler can't prove a pointer
+//   is gone by the time the function returns, the value is put on the heap - an allocation, and GC work.
+//   A pointer receiver alone doesn't do that: calling v.scaleOriginal(2) on a local v keeps v on the stack
+//
+// go build -gcflags=-m prints the compiler's decisions (escape analysis and inlining); -m=2 says why.
+// The lines quoted below are from its output for this package (go build -gcflags=-m, in methodsinterfaces/)
+
+// BigVertex is a Vertex with 62 more coordinates - 512 bytes, to copy on each value receiver call
+type BigVertex struct {
+	X, Y   float64
+	Extras [62]float64
+}
+
+// The methods are kept from being inlined: inlined, the compiler optimises the copy away along with the
+// call, and the benchmark would measure nothing. A real method that big usually isn't inlined anyway
+
+//go:noinline
+func (v BigVertex) scaleCopy(f float64) BigVertex {
+	v.X, v.Y = v.X*f, v.Y*f
+	return v
+}
+
+//go:noinline
+func (v *BigVertex) scaleOriginal(f float64) { // "v does not escape": the method doesn't keep the pointer
+	v.X, v.Y = v.X*f, v.Y*f
+}
+
+//go:noinline
+func (v Vertex) scaleCopyNoInline(f float64) Vertex {
+	v.X, v.Y = v.X*f, v.Y*f
+	return v
+}
+
+//go:noinline
+func (v *Vertex) scaleOriginalNoInline(f float64) {
+	v.X, v.Y = v.X*f, v.Y*f
+}
+
+// The values the benchmarks work on are package variables: a local captured by the benchmark closures
+// would be "moved to heap" itself, and muddle the -m output
+var (
+	benchVertex Vertex
+	benchBig    BigVertex
+)
+
+func receiverCostExamples() {
+	// A rough timing of each - the benchmarks in receivers_test.go are the careful version:
+	// go test -bench Receiver methodsinterfaces
+	bench := func(f func()) float64 {
+		const n = 2_000_000
+		start := time.Now()
+		for range n {
+			f()
+		}
+		return float64(time.Since(start).Nanoseconds()) / n
+	}
+
+	// Vertex, 16 bytes: the copy is free. Both methods are small enough to inline (-m: "can inline
+	// Vertex.scaleCopy", "can inline (*Vertex).scaleOriginal"), and then there's no call at all
+	benchVertex = Vertex{3, 4}
+	v := &benchVertex
+	small := []struct {
+		name string
+		ns   float64
+	}{
+		{"Vertex.scaleCopy, inlined", bench(func() { *v = v.scaleCopy(1.0000001) })},
+		{"(*Vertex).scaleOriginal, inlined", bench(func() { v.scaleOriginal(1.0000001) })},
+		{"Vertex.scaleCopy, a real call", bench(func() { *v = v.scaleCopyNoInline(1.0000001) })},
+		{"(*Vertex).scaleOriginal, a real call", bench(func() { v.scaleOriginalNoInline(1.0000001) })},
+	}
+	for _, r := range small {
+		fmt.Printf("%-38s %6.2f ns/call\n", r.name, r.ns)
+	}
+
+	// BigVertex, 512 bytes: copied in, and copied back out as the result - the value receiver pays for both
+	big := &benchBig
+	copyNs := bench(func() { *big = big.scaleCopy(1.0000001) })
+	ptrNs := bench(func() { big.scaleOriginal(1.0000001) })
+	fmt.Printf("%-38s %6.2f ns/call\n%-38s %6.2f ns/call\n", "BigVertex.scaleCopy (512 B)", copyNs, "(*BigVertex).scaleOriginal", ptrNs)
+	fmt.Printf("the 512 byte copy costs: the value receiver is %.1fx the pointer one\n", copyNs/ptrNs)
+
+	// Neither allocates: the copy is on the stack, and the pointer is to a variable that stays put
+	// (TestEscapeAllocs in receivers_test.go measures it: go test -run TestEscapeAllocs -v methodsinterfaces)
+}
+
+// --- Escape analysis: when a pointer puts a value on the heap ---
+
+var vertexSink *Vertex // a package variable outlives every call: whatever it points to must be on the heap
+var abserSink Abser
+
+//go:noinline
+func vertexOnStack() float64 {
+	v := Vertex{3, 4}
+	v.scaleOriginal(2) // "inlining call to (*Vertex).scaleOriginal" - and nothing about v: it stays on the stack
+	return v.abs()
+}
+
+//go:noinline
+func newVertexValue() Vertex {
+	return Vertex{3, 4} // returned as a value: copied to the caller, nothing escapes
+}
+
+//go:noinline
+func newVertexPointer() *Vertex {
+	v := Vertex{3, 4} // "moved to heap: v" - the pointer outlives the function, so v can't be in its frame
+	return &v
+}
+
+//go:noinline
+func keepVertex(v *Vertex) { // "leaking param: v" - the pointer is kept, so whatever it points to escapes
+	vertexSink = v
+}
+
+//go:noinline
+func vertexAsInterface(x float64) {
+	v := Vertex{x, 4}
+	abserSink = v // "v escapes to heap" - boxing (boxing.go): the interface holds a pointer to a heap copy
+	// (with Vertex{3, 4}, a constant, the compiler points the interface at a read-only copy instead - no allocation)
+}
+
+func escapeAnalysisExamples() {
+	// TestEscapeAllocs in receivers_test.go measures the allocations per call of each, and logs them with -v:
+	//	go test -run TestEscapeAllocs -v methodsinterfaces
+	fmt.Println("allocations per call, and the compiler's reason (go build -gcflags=-m):")
+	for _, tc := range []struct {
+		name   string
+		allocs int
+		why    string
+	}{
+		{"local Vertex, pointer method called on it", 0, "v does not escape"},
+		{"returning a Vertex", 0, "copied to the caller"},
+		{"returning a *Vertex", 1, "moved to heap: v"},
+		{"a local's address kept by a callee", 1, "leaking param: v"},
+		{"a Vertex stored as an Abser", 1, "v escapes to heap"},
+	} {
+		fmt.Printf("  %-44s %d  %s\n", tc.name, tc.allocs, tc.why)
+	}
+
+	// So a pointer receiver doesn't allocate, but returning pointers, keeping them, and interfaces do.
+	// A constructor returning *T allocates unless it's inlined and the result doesn't escape the caller either
+	// Value receivers don't avoid the heap either: v.abs() through an Abser is a copy on the heap all the same
+}
@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+// The receiver costs receivers.go describes. go test -bench Receiver methodsinterfaces
+
+// BenchmarkVertexReceiver: for a 16 byte Vertex the copy is free, inlined or not
+func BenchmarkVertexReceiver(b *testing.B) {
+	benchVertex = Vertex{3, 4}
+	v := &benchVertex
+	b.Run("copy/inlined", func(b *testing.B) {
+		for b.Loop() {
+			*v = v.scaleCopy(1.0000001)
+		}
+	})
+	b.Run("pointer/inlined", func(b *testing.B) {
+		for b.Loop() {
+			v.scaleOriginal(1.0000001)
+		}
+	})
+	b.Run("copy/call", func(b *testing.B) {
+		for b.Loop() {
+			*v = v.scaleCopyNoInline(1.0000001)
+		}
+	})
+	b.Run("pointer/call", func(b *testing.B) {
+		for b.Loop() {
+			v.scaleOriginalNoInline(1.0000001)
+		}
+	})
+}
+
+// BenchmarkBigVertexReceiver: 512 bytes copied in, and copied back out as the result - the value receiver
+// pays for both
+func BenchmarkBigVertexReceiver(b *testing.B) {
+	big := &benchBig
+	b.Run("copy", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			*big = big.scaleCopy(1.0000001)
+		}
+	})
+	b.Run("pointer", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			big.scaleOriginal(1.0000001)
+		}
+	})
+}
+
+func TestReceiversScale(t *testing.T) {
+	v := Vertex{3, 4}
+	if got := v.scaleCopyNoInline(2); got != (Vertex{6, 8}) || v != (Vertex{3, 4}) {
+		t.Errorf("scaleCopy: %v, and v %v - a value receiver changes a copy", got, v)
+	}
+	v.scaleOriginalNoInline(2)
+	if v != (Vertex{6, 8}) {
+		t.Errorf("scaleOriginal: v = %v", v)
+	}
+	big := BigVertex{X: 1, Y: 2}
+	big.Extras[61] = 5
+	if got := big.scaleCopy(3); got.X != 3 || got.Y != 6 || got.Extras[61] != 5 || big.X != 1 {
+		t.Errorf("BigVertex.scaleCopy: %v %v %v, original X %v", got.X, got.Y, got.Extras[61], big.X)
+	}
+}
+
+// Neither receiver allocates: the copy is on the stack, and the pointer is to a variable that stays put.
+// Returning pointers, keeping them, and storing in an interface do
+func TestEscapeAllocs(t *testing.T) {
+	big := &benchBig
+	tests := []struct {
+		name   string
+		f      func()
+		allocs float64
+	}{
+		{"BigVertex.scaleCopy", func() { *big = big.scaleCopy(1) }, 0},
+		{"(*BigVertex).scaleOriginal", func() { big.scaleOriginal(1) }, 0},
+		{"local Vertex, pointer method called on it", func() { vertexOnStack() }, 0},
+		{"returning a Vertex", func() { _ = newVertexValue() }, 0},
+		{"returning a *Vertex", func() { vertexSink = newVertexPointer() }, 1},
+		{"a local's address kept by a callee", func() { v := Vertex{1, 2}; keepVertex(&v) }, 1},
+		{"a Vertex stored as an Abser", func() { vertexAsInterface(3) }, 1},
+	}
+	for _, tt := range tests {
+		allocs := testing.AllocsPerRun(100, tt.f)
+		t.Logf("%-44s %v allocations per call", tt.name, allocs)
+		if allocs != tt.allocs {
+			t.Errorf("%s: %v allocations per call, want %v", tt.name, allocs, tt.allocs)
+		}
+	}
+}
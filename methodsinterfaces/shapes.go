@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// === Ex. Shapes - polymorphism with interfaces ===
+
+// Several unrelated types, one interface. Code written against Shape works for
+// every shape, including ones added later, without changing that code.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+type Rectangle struct {
+	Width, Height float64
+}
+
+// Triangle given by its three side lengths
+type Triangle struct {
+	A, B, C float64
+}
+
+func (c Circle) Area() float64      { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+
+func (r Rectangle) Area() float64      { return r.Width * r.Height }
+func (r Rectangle) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+
+// Heron's formula
+func (t Triangle) Area() float64 {
+	s := t.Perimeter() / 2
+	return math.Sqrt(s * (s - t.A) * (s - t.B) * (s - t.C))
+}
+func (t Triangle) Perimeter() float64 { return t.A + t.B + t.C }
+
+// Value receivers, so both Circle and *Circle are Shapes (see methodsets.go)
+var _ Shape = Circle{}
+var _ Shape = Rectangle{}
+var _ Shape = Triangle{}
+
+// Polymorphism - only uses the interface's methods, so it never needs to know the concrete types
+func TotalArea(shapes []Shape) float64 {
+	total := 0.0
+	for _, s := range shapes {
+		total += s.Area()
+	}
+	return total
+}
+
+func Largest(shapes []Shape) (Shape, bool) {
+	if len(shapes) == 0 {
+		return nil, false
+	}
+	largest := shapes[0]
+	for _, s := range shapes[1:] {
+		if s.Area() > largest.Area() {
+			largest = s
+		}
+	}
+	return largest, true
+}
+
+// Type switch - for behaviour that depends on the concrete type (the fields are only reachable after the switch).
+// Prefer adding a method to the interface when every shape needs the behaviour;
+// a type switch fits one-off code that only a few types are special in
+func Describe(s Shape) string {
+	switch shape := s.(type) {
+	case Circle:
+		return fmt.Sprintf("circle with radius %g", shape.Radius)
+	case Rectangle:
+		if shape.Width == shape.Height {
+			return fmt.Sprintf("square with side %g", shape.Width)
+		}
+		return fmt.Sprintf("%g x %g rectangle", shape.Width, shape.Height)
+	case Triangle:
+		if shape.A == shape.B && shape.B == shape.C {
+			return fmt.Sprintf("equilateral triangle with side %g", shape.A)
+		}
+		return fmt.Sprintf("triangle with sides %g, %g, %g", shape.A, shape.B, shape.C)
+	case nil:
+		return "no shape"
+	default:
+		// a Shape from somewhere else - still usable through the interface
+		return fmt.Sprintf("unknown shape %T with area %.2f", shape, shape.Area())
+	}
+}
+
+// Adding a new shape only needs the two methods. TotalArea and Largest work with it as is,
+// and Describe falls through to its default case
+type Hexagon struct {
+	Side float64
+}
+
+func (h Hexagon) Area() float64      { return 3 * math.Sqrt(3) / 2 * h.Side * h.Side }
+func (h Hexagon) Perimeter() float64 { return 6 * h.Side }
+
+func shapeExamples() {
+	shapes := []Shape{
+		Circle{Radius: 1},
+		Rectangle{Width: 3, Height: 4},
+		Rectangle{Width: 2, Height: 2},
+		Triangle{A: 3, B: 4, C: 5},
+		Triangle{A: 2, B: 2, C: 2},
+		Hexagon{Side: 1},
+	}
+
+	for _, s := range shapes {
+		fmt.Printf("%-34s area %6.2f  perimeter %6.2f\n", Describe(s), s.Area(), s.Perimeter())
+	}
+
+	fmt.Printf("total area: %.2f\n", TotalArea(shapes))
+	if s, ok := Largest(shapes); ok {
+		fmt.Println("largest:", Describe(s))
+	}
+	fmt.Println(Describe(nil))
+}
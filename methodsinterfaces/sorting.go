@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// === Sorting ===
+
+// --- sort.Interface ---
+
+// The sort package sorts any collection that implements sort.Interface:
+//
+//	type Interface interface {
+//		Len() int           // number of elements in the collection
+//		Less(i, j int) bool // reports whether element i should sort before element j
+//		Swap(i, j int)      // swaps the elements with indexes i and j
+//	}
+//
+// The collection does not need to be a slice, it only needs to be indexable.
+// The usual approach is to declare a named slice type and put the three methods on it.
+
+// Ex. ByAge implements sort.Interface for []Person based on the Age field
+type ByAge []Person
+
+func (a ByAge) Len() int           { return len(a) }
+func (a ByAge) Less(i, j int) bool { return a[i].Age < a[j].Age }
+func (a ByAge) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// Value receivers are fine here - a slice header is copied, but it still points to the same
+// underlying array, so Swap modifies the caller's elements (see the slice notes in basics.go)
+
+func samplePeople() []Person {
+	return []Person{
+		{"Alice", 30},
+		{"Bob", 25},
+		{"Carol", 35},
+		{"Dave", 25},
+		{"Eve", 30},
+		{"Frank", 25},
+	}
+}
+
+func sortInterfaceExample() {
+	people := samplePeople()
+
+	// Convert []Person to ByAge (same underlying type, so no copy is made) and sort in place
+	sort.Sort(ByAge(people))
+	fmt.Println("sort.Sort(ByAge):", people)
+
+	// sort.Reverse wraps an Interface and flips its Less method
+	sort.Sort(sort.Reverse(ByAge(people)))
+	fmt.Println("sort.Sort(sort.Reverse(ByAge)):", people)
+
+	// false - the slice is now in descending order
+	fmt.Println("Sorted by age ascending?", sort.IsSorted(ByAge(people)))
+}
+
+// --- sort.Slice with a closure ---
+
+// Declaring a new type for every sort order gets verbose.
+// sort.Slice takes the less function as a closure instead, so no extra type is needed.
+// (The closure captures the people slice, and compares by index)
+func sortSliceExample() {
+	people := samplePeople()
+
+	sort.Slice(people, func(i, j int) bool {
+		return people[i].Name > people[j].Name
+	})
+	fmt.Println("sort.Slice by name descending:", people)
+}
+
+// --- slices.SortFunc (Go 1.21+) ---
+
+// The generic slices package is the modern replacement.
+// The comparison function receives the elements themselves (not indexes) and
+// returns a negative number, zero, or a positive number (like compareTo in Java)
+func slicesSortFuncExample() {
+	people := samplePeople()
+
+	slices.SortFunc(people, func(a, b Person) int {
+		return a.Age - b.Age
+	})
+	fmt.Println("slices.SortFunc by age:", people)
+
+	// strings.Compare already returns -1, 0 or +1
+	slices.SortFunc(people, func(a, b Person) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	fmt.Println("slices.SortFunc by name:", people)
+}
+
+// --- Stable sorting ---
+
+// sort.Sort, sort.Slice and slices.SortFunc are NOT stable:
+// elements that compare equal may end up in any order.
+// A stable sort keeps equal elements in their original relative order,
+// which matters when sorting by one key after another.
+func stableSortExample() {
+	// Already sorted by name
+	people := samplePeople()
+
+	// Stable sort by age - people with the same age stay in name order
+	// (Bob, Dave, Frank are all 25 and remain alphabetical)
+	sort.Stable(ByAge(people))
+	fmt.Println("sort.Stable(ByAge):", people)
+
+	// Same result using the other two APIs
+	people = samplePeople()
+	sort.SliceStable(people, func(i, j int) bool {
+		return people[i].Age < people[j].Age
+	})
+	fmt.Println("sort.SliceStable by age:", people)
+
+	people = samplePeople()
+	slices.SortStableFunc(people, func(a, b Person) int {
+		return a.Age - b.Age
+	})
+	fmt.Println("slices.SortStableFunc by age:", people)
+
+	// An unstable sort is only guaranteed to order the ages.
+	// The names within each age group may come out shuffled (depends on the algorithm and input size)
+	people = samplePeople()
+	sort.Sort(ByAge(people))
+	fmt.Println("sort.Sort(ByAge), ties in any order:", people)
+}
+
+func sortingExamples() {
+	sortInterfaceExample()
+	sortSliceExample()
+	slicesSortFuncExample()
+	stableSortExample()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// The three stable sorts, each sorting by age
+var stableSorts = []struct {
+	name string
+	sort func([]Person)
+}{
+	{"sort.Stable", func(p []Person) { sort.Stable(ByAge(p)) }},
+	{"sort.SliceStable", func(p []Person) {
+		sort.SliceStable(p, func(i, j int) bool { return p[i].Age < p[j].Age })
+	}},
+	{"slices.SortStableFunc", func(p []Person) {
+		slices.SortStableFunc(p, func(a, b Person) int { return a.Age - b.Age })
+	}},
+}
+
+func TestStableSortTies(t *testing.T) {
+	want := []string{"Bob", "Dave", "Frank", "Alice", "Eve", "Carol"}
+	for _, s := range stableSorts {
+		people := samplePeople()
+		s.sort(people)
+		if got := names(people); !slices.Equal(got, want) {
+			t.Errorf("%s: %v, want %v - equal ages in their original order", s.name, got, want)
+		}
+	}
+}
+
+// Six elements are sorted by insertion sort, which keeps ties anyway.
+// Hundreds of them, in 4 age groups, go through the real algorithms
+func TestStableSortTiesLarge(t *testing.T) {
+	in := make([]Person, 500)
+	for i := range in {
+		in[i] = Person{Name: fmt.Sprintf("p%03d", i), Age: (i * 7) % 4}
+	}
+	for _, s := range stableSorts {
+		people := slices.Clone(in)
+		s.sort(people)
+		for i := 1; i < len(people); i++ {
+			prev, cur := people[i-1], people[i]
+			if prev.Age > cur.Age || prev.Age == cur.Age && prev.Name > cur.Name {
+				t.Fatalf("%s: %v before %v", s.name, prev, cur)
+			}
+		}
+	}
+}
+
+func names(people []Person) []string {
+	out := make([]string, len(people))
+	for i, p := range people {
+		out[i] = p.Name
+	}
+	return out
+}
+
+func TestSortByAge(t *testing.T) {
+	people := samplePeople()
+	sort.Sort(ByAge(people))
+	if !sort.IsSorted(ByAge(people)) {
+		t.Errorf("sort.Sort(ByAge) = %v", people)
+	}
+	sort.Sort(sort.Reverse(ByAge(people)))
+	if sort.IsSorted(ByAge(people)) || people[0].Age != 35 || people[len(people)-1].Age != 25 {
+		t.Errorf("reversed = %v, want descending ages", people)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// === encoding.TextMarshaler and encoding.TextUnmarshaler ===
+
+// Another standard interface family, defined in the encoding package:
+//
+//	type TextMarshaler interface {
+//		MarshalText() (text []byte, err error)
+//	}
+//
+//	type TextUnmarshaler interface {
+//		UnmarshalText(text []byte) error
+//	}
+//
+// A type that converts itself to and from text works with every package that
+// checks for these interfaces: encoding/json (values AND map keys), encoding/xml, flag.TextVar, log/slog, ...
+// (Like Stringer, the interface is defined where it is used, and implemented implicitly)
+
+// MarshalText has a value receiver (so a Vertex value can be marshalled),
+// UnmarshalText must have a pointer receiver (it has to modify the original).
+// This mix is the usual exception to the "don't mix receiver types" advice.
+
+func (f Latitude) MarshalText() ([]byte, error) {
+	return strconv.AppendFloat(nil, float64(f), 'f', -1, 64), nil
+}
+
+func (f *Latitude) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		return fmt.Errorf("latitude: %w", err)
+	}
+	if v < -90 || v > 90 {
+		return fmt.Errorf("latitude %g out of range [-90, 90]", v)
+	}
+	*f = Latitude(v)
+	return nil
+}
+
+// Vertex as "X,Y", ex. "3,4"
+func (v Vertex) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%s,%s",
+		strconv.FormatFloat(v.X, 'f', -1, 64),
+		strconv.FormatFloat(v.Y, 'f', -1, 64)), nil
+}
+
+func (v *Vertex) UnmarshalText(text []byte) error {
+	xs, ys, found := strings.Cut(string(text), ",")
+	if !found {
+		return fmt.Errorf("vertex %q: want X,Y", text)
+	}
+	x, err := strconv.ParseFloat(strings.TrimSpace(xs), 64)
+	if err != nil {
+		return fmt.Errorf("vertex X: %w", err)
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(ys), 64)
+	if err != nil {
+		return fmt.Errorf("vertex Y: %w", err)
+	}
+	v.X, v.Y = x, y
+	return nil
+}
+
+var _ encoding.TextMarshaler = Latitude(0)
+var _ encoding.TextUnmarshaler = (*Latitude)(nil)
+var _ encoding.TextMarshaler = Vertex{}
+var _ encoding.TextUnmarshaler = (*Vertex)(nil)
+
+func textMarshalerExamples() {
+	// --- JSON map keys ---
+
+	// JSON object keys must be strings. encoding/json only allows a struct as a map key
+	// if the key type implements TextMarshaler (and TextUnmarshaler for decoding)
+	labels := map[Vertex]string{
+		{3, 4}:    "a",
+		{-1, 0.5}: "b",
+	}
+	data, err := json.Marshal(labels)
+	fmt.Println("map[Vertex]string as JSON:", string(data), err)
+
+	var decodedLabels map[Vertex]string
+	err = json.Unmarshal(data, &decodedLabels)
+	fmt.Println("decoded back:", decodedLabels, err)
+
+	// As values too - a Vertex becomes a JSON string instead of an object
+	data, _ = json.Marshal(struct {
+		Position Vertex
+		Lat      Latitude
+	}{Vertex{1, 2}, 49.2827})
+	fmt.Println("struct as JSON:", string(data))
+
+	// Validation happens while decoding
+	var lat Latitude
+	fmt.Println("invalid latitude:", json.Unmarshal([]byte(`"123.4"`), &lat))
+
+	// --- CSV cells ---
+
+	// encoding/csv only deals with strings, so call the methods directly.
+	// The writer quotes the Vertex cell because it contains a comma
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Write([]string{"name", "position", "latitude"})
+	rows := []struct {
+		name string
+		pos  Vertex
+		lat  Latitude
+	}{
+		{"vancouver", Vertex{3, 4}, 49.2827},
+		{"origin", Vertex{0, 0}, 0},
+	}
+	for _, r := range rows {
+		pos, _ := r.pos.MarshalText()
+		lat, _ := r.lat.MarshalText()
+		w.Write([]string{r.name, string(pos), string(lat)})
+	}
+	w.Flush()
+	fmt.Print("CSV:\n", sb.String())
+
+	records, _ := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	for _, rec := range records[1:] {
+		var pos Vertex
+		var lat Latitude
+		errPos := pos.UnmarshalText([]byte(rec[1]))
+		errLat := lat.UnmarshalText([]byte(rec[2]))
+		fmt.Printf("parsed %s: %+v, %v m north of the equator (errors: %v, %v)\n", rec[0], pos, lat.toMetres(), errPos, errLat)
+	}
+
+	var bad Vertex
+	fmt.Println("invalid vertex:", bad.UnmarshalText([]byte("3;4")))
+}
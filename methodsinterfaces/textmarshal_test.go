@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+)
+
+var textVertices = []Vertex{{3, 4}, {-1, 0.5}, {0, 0}, {1e-7, -123456.789}}
+var textLatitudes = []Latitude{49.2827, 0, -90, 90, -33.8688}
+
+func TestTextJSONMapKeys(t *testing.T) {
+	byVertex := map[Vertex]string{}
+	for i, v := range textVertices {
+		byVertex[v] = string(rune('a' + i))
+	}
+	byLat := map[Latitude]int{}
+	for i, l := range textLatitudes {
+		byLat[l] = i
+	}
+
+	data, err := json.Marshal(byVertex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"3,4":"a"`) {
+		t.Errorf("map[Vertex]string = %s, want the key written as \"3,4\"", data)
+	}
+	var gotVertex map[Vertex]string
+	if err := json.Unmarshal(data, &gotVertex); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(gotVertex, byVertex) {
+		t.Errorf("Vertex keys round trip: %v, want %v", gotVertex, byVertex)
+	}
+
+	data, err = json.Marshal(byLat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotLat map[Latitude]int
+	if err := json.Unmarshal(data, &gotLat); err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(gotLat, byLat) {
+		t.Errorf("Latitude keys round trip through %s: %v, want %v", data, gotLat, byLat)
+	}
+}
+
+// JSON keys go through the same validation as values
+func TestTextJSONRejects(t *testing.T) {
+	for _, c := range []struct {
+		name, data string
+		into       any
+	}{
+		{"latitude out of range", `{"91":1}`, &map[Latitude]int{}},
+		{"latitude not a number", `{"north":1}`, &map[Latitude]int{}},
+		{"latitude value", `"-90.5"`, new(Latitude)},
+		{"vertex without a comma", `{"3;4":"a"}`, &map[Vertex]string{}},
+		{"vertex bad Y", `{"3,y":"a"}`, &map[Vertex]string{}},
+		{"vertex value", `"x,4"`, new(Vertex)},
+	} {
+		if err := json.Unmarshal([]byte(c.data), c.into); err == nil {
+			t.Errorf("%s: decoding %s gave no error", c.name, c.data)
+		}
+	}
+}
+
+func TestTextCSVCells(t *testing.T) {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	for i, v := range textVertices {
+		pos, err := v.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lat, err := textLatitudes[i].MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]string{string(pos), string(lat)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if first, _, _ := strings.Cut(sb.String(), "\n"); first != `"3,4",49.2827` {
+		t.Errorf("first row %s, want the vertex cell quoted", first)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(textVertices) {
+		t.Fatalf("%d rows read back, want %d", len(records), len(textVertices))
+	}
+	for i, rec := range records {
+		var pos Vertex
+		var lat Latitude
+		if err := pos.UnmarshalText([]byte(rec[0])); err != nil || pos != textVertices[i] {
+			t.Errorf("row %d: vertex %q read as %+v (%v), want %+v", i, rec[0], pos, err, textVertices[i])
+		}
+		if err := lat.UnmarshalText([]byte(rec[1])); err != nil || lat != textLatitudes[i] {
+			t.Errorf("row %d: latitude %q read as %v (%v), want %v", i, rec[1], lat, err, textLatitudes[i])
+		}
+	}
+}
+
+// A failed UnmarshalText leaves the value as it was
+func TestUnmarshalTextKeepsValueOnError(t *testing.T) {
+	v := Vertex{1, 2}
+	if err := v.UnmarshalText([]byte("5,oops")); err == nil || v != (Vertex{1, 2}) {
+		t.Errorf("after a bad Y: %+v, %v; want {1 2} and an error", v, err)
+	}
+	lat := Latitude(10)
+	if err := lat.UnmarshalText([]byte("100")); err == nil || lat != 10 {
+		t.Errorf("after an out-of-range latitude: %v, %v; want 10 and an error", lat, err)
+	}
+	// spaces around the numbers are allowed in a vertex
+	if err := v.UnmarshalText([]byte(" 3 , 4 ")); err != nil || v != (Vertex{3, 4}) {
+		t.Errorf("\" 3 , 4 \" read as %+v, %v", v, err)
+	}
+}
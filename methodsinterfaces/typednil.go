@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// === The typed nil pitfall ===
+
+// From the interface notes: an interface value is a (value, type) pair,
+// and "an interface value that holds a nil concrete value is itself non-nil".
+// An interface is only == nil when BOTH parts are nil: (nil, nil).
+// Storing a nil *Vertex gives (nil, *Vertex) - the type is set, so the interface is not nil.
+
+// Where this bites: functions returning the error interface
+
+type MyError struct {
+	Op string
+}
+
+func (e *MyError) Error() string {
+	return "failed: " + e.Op
+}
+
+// BUG - the return type is *MyError (a concrete pointer), not error.
+// Returning it through an error interface wraps a nil *MyError into (nil, *MyError)
+func validateBuggy(input string) *MyError {
+	var err *MyError
+	if input == "" {
+		err = &MyError{Op: "validate"}
+	}
+	return err // nil pointer, but still a *MyError
+}
+
+func processBuggy(input string) error {
+	return validateBuggy(input) // converted to error here: (nil, *MyError) != nil
+}
+
+// Same bug in a single function - the error variable has the concrete type
+func processBuggy2(input string) error {
+	var err *MyError
+	if input == "" {
+		err = &MyError{Op: "process"}
+	}
+	return err
+}
+
+// FIX - declare the return type (and variables) as error, and return a literal nil on success
+func processFixed(input string) error {
+	if input == "" {
+		return &MyError{Op: "process"}
+	}
+	return nil // (nil, nil)
+}
+
+// IsNil reports whether v is nil, OR is an interface holding a nil pointer, map, slice, chan, or func.
+// Useful for debugging / asserting in tests - in normal code, fix the function instead of checking with reflection
+func IsNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	// other kinds (ints, structs, ...) can never be nil
+	return false
+}
+
+func typedNilExamples() {
+	err := processBuggy("valid input")
+
+	// The surprise - nothing went wrong, but the check says there is an error.
+	// (and %v prints <nil>, because fmt formats the nil pointer inside - hiding the problem even more)
+	if err != nil {
+		fmt.Printf("buggy: err != nil is true! err = %v, type = %T\n", err, err)
+	}
+
+	fmt.Println("buggy2 err != nil:", processBuggy2("valid input") != nil)
+	fmt.Println("fixed err != nil:", processFixed("valid input") != nil)
+
+	// The (value, type) pair, made visible
+	var nilPtr *MyError
+	var asError error = nilPtr
+	fmt.Printf("var asError error = nilPtr -> (%v, %T), == nil: %t\n", asError, asError, asError == nil)
+
+	// Calling Error() on the typed nil still calls the method with a nil receiver,
+	// and this method dereferences e, so it panics (unlike PrintX, which checks for nil)
+	func() {
+		defer func() {
+			fmt.Println("calling Error() on the typed nil panicked:", recover() != nil)
+		}()
+		_ = asError.Error()
+	}()
+
+	// IsNil looks inside the interface
+	fmt.Println("IsNil(nil):", IsNil(nil))
+	fmt.Println("IsNil(processBuggy(valid)):", IsNil(processBuggy("valid input")))
+	fmt.Println("IsNil(processFixed(valid)):", IsNil(processFixed("valid input")))
+	fmt.Println("IsNil(processFixed(empty)):", IsNil(processFixed("")))
+	fmt.Println("IsNil([]int(nil)):", IsNil([]int(nil)), "| IsNil(0):", IsNil(0), "| IsNil(Vertex{}):", IsNil(Vertex{}))
+
+	// Same pitfall with any other interface, ex. the I interface from the nil receiver notes
+	var v *Vertex
+	var i I = v
+	fmt.Println("I holding a nil *Vertex == nil:", i == nil, "| IsNil:", IsNil(i))
+}
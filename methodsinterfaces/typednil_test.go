@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestIsNil(t *testing.T) {
+	var (
+		nilVertex *Vertex
+		nilErr    *MyError
+		nilI      I = nilVertex
+		nilChan   chan int
+		nilUnsafe unsafe.Pointer
+		x         = 1
+	)
+	for _, c := range []struct {
+		name string
+		v    any
+		want bool
+	}{
+		{"nil", nil, true},
+		{"nil error", processFixed("valid input"), true},
+		{"typed nil pointer", nilVertex, true},
+		{"typed nil through error", processBuggy("valid input"), true},
+		{"typed nil through I", nilI, true},
+		{"nil *MyError", nilErr, true},
+		{"nil slice", []int(nil), true},
+		{"nil map", map[string]int(nil), true},
+		{"nil func", (func())(nil), true},
+		{"nil chan", nilChan, true},
+		{"nil unsafe.Pointer", nilUnsafe, true},
+
+		{"pointer", &x, false},
+		{"non-nil error", processFixed(""), false},
+		{"empty slice", []int{}, false},
+		{"empty map", map[string]int{}, false},
+		{"func", typedNilExamples, false},
+		{"chan", make(chan int), false},
+
+		// kinds that can't be nil at all
+		{"zero int", 0, false},
+		{"empty string", "", false},
+		{"false", false, false},
+		{"zero struct", Vertex{}, false},
+		{"zero array", [2]*int{}, false},
+	} {
+		if got := IsNil(c.v); got != c.want {
+			t.Errorf("%s: IsNil(%#v) = %t, want %t", c.name, c.v, got, c.want)
+		}
+	}
+}
+
+// The pitfall itself: each buggy version returns a non-nil error on success
+func TestTypedNilError(t *testing.T) {
+	if processBuggy("valid input") == nil || processBuggy2("valid input") == nil {
+		t.Error("the buggy versions returned a nil error - the pitfall isn't shown")
+	}
+	if err := processFixed("valid input"); err != nil {
+		t.Errorf("processFixed = %v, want nil", err)
+	}
+	for name, err := range map[string]error{"buggy": processBuggy(""), "buggy2": processBuggy2(""), "fixed": processFixed("")} {
+		if err == nil || IsNil(err) {
+			t.Errorf("%s with empty input: %v, want a *MyError", name, err)
+		}
+	}
+}
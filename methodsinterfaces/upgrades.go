@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+// === Interface upgrades (optional interfaces) ===
+
+// A function takes the smallest interface it needs (io.Writer), then checks with a type assertion
+// whether the value it got can do more, and uses the faster path if so:
+//
+//	if sw, ok := w.(io.StringWriter); ok {
+//		sw.WriteString(s) // no []byte(s) copy
+//	} else {
+//		w.Write([]byte(s))
+//	}
+//
+// The standard library does this all over: io.WriteString (io.StringWriter), io.Copy (io.WriterTo / io.ReaderFrom),
+// and HTTP handlers checking if the ResponseWriter is an http.Flusher, to send data before the handler returns.
+//
+// The catch is wrappers. A wrapper type only has the methods it declares - wrapping an http.ResponseWriter
+// to count bytes HIDES its Flush method, and the assertion on the wrapper fails. The fixes:
+// - the wrapper declares the optional method too, and forwards it (CountingWriter.WriteString does this)
+// - the wrapper has an Unwrap method, and callers use http.ResponseController,
+//   which unwraps until it finds a writer that can flush (Go 1.20+)
+
+// --- The fast path for strings ---
+
+// writeOnly hides every method but Write - like a wrapper that doesn't forward WriteString
+type writeOnly struct {
+	w     io.Writer
+	calls int
+}
+
+func (w *writeOnly) Write(p []byte) (int, error) {
+	w.calls++
+	return w.w.Write(p)
+}
+
+// stringRecorder implements both, and records which one was used
+type stringRecorder struct {
+	strings.Builder
+	writeCalls, writeStringCalls int
+}
+
+func (r *stringRecorder) Write(p []byte) (int, error) {
+	r.writeCalls++
+	return r.Builder.Write(p)
+}
+
+func (r *stringRecorder) WriteString(s string) (int, error) {
+	r.writeStringCalls++
+	return r.Builder.WriteString(s)
+}
+
+// --- Server-sent events ---
+
+// SSE sends a stream of events over one HTTP response ("data: ...\n\n" per event).
+// Each event has to be flushed, or it sits in the server's buffer until the handler returns
+
+// countingResponseWriter counts the body bytes of a response.
+// Embedding http.ResponseWriter promotes Header, Write and WriteHeader - but NOT Flush,
+// since Flush isn't part of the http.ResponseWriter interface
+type countingResponseWriter struct {
+	http.ResponseWriter
+	bytes int
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the Flush of the writer underneath
+func (w *countingResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// sseHandler sends each event, and flushes if the writer supports it.
+// Without a flusher it still works - the client just gets every event at the end
+func sseHandler(events []string, delay time.Duration, flushed *bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		rc := http.NewResponseController(w)
+		*flushed = true
+		for _, e := range events {
+			fmt.Fprintf(w, "data: %s\n\n", e)
+			if err := rc.Flush(); err != nil {
+				*flushed = false // errors.Is(err, http.ErrNotSupported) - carry on without flushing
+			}
+			time.Sleep(delay)
+		}
+	}
+}
+
+// noFlushWriter is a ResponseWriter without Flush (and without Unwrap), to test the fallback branch
+type noFlushWriter struct {
+	header http.Header
+	body   strings.Builder
+}
+
+func (w *noFlushWriter) Header() http.Header         { return w.header }
+func (w *noFlushWriter) Write(p []byte) (int, error) { return w.body.Write(p) }
+func (w *noFlushWriter) WriteHeader(int)             {}
+
+func interfaceUpgradeExamples() {
+	// --- CountingWriter.WriteString, both branches ---
+
+	rec := &stringRecorder{}
+	cw := &CountingWriter{W: rec}
+	io.WriteString(cw, "fast path") // io.WriteString checks cw for WriteString, and CountingWriter checks rec
+	fmt.Println("underlying has WriteString - used it:", rec.writeStringCalls == 1 && rec.writeCalls == 0, "| counted:", cw.Count)
+
+	plain := &writeOnly{w: io.Discard}
+	cw = &CountingWriter{W: plain}
+	io.WriteString(cw, "fallback")
+	fmt.Println("underlying has only Write - fell back to it:", plain.calls == 1, "| counted:", cw.Count)
+	// what the fast path saves is the []byte(s) copy, one allocation per call (see TestWriteStringAllocs)
+
+	// --- The hidden Flush ---
+
+	var w http.ResponseWriter = httptest.NewRecorder() // ResponseRecorder has a Flush method
+	_, direct := w.(http.Flusher)
+	_, wrapped := http.ResponseWriter(&countingResponseWriter{ResponseWriter: w}).(http.Flusher)
+	fmt.Println("is an http.Flusher - recorder:", direct, "| wrapped in countingResponseWriter:", wrapped)
+
+	// --- sseHandler, both branches ---
+
+	events := []string{"hello", "tour", "bye"}
+	want := "data: hello\n\ndata: tour\n\ndata: bye\n\n"
+
+	var flushed bool
+	recorder := httptest.NewRecorder()
+	counting := &countingResponseWriter{ResponseWriter: recorder}
+	sseHandler(events, 0, &flushed).ServeHTTP(counting, httptest.NewRequest("GET", "/events", nil))
+	fmt.Println("through the counting wrapper - flushed via Unwrap:", flushed && recorder.Flushed,
+		"| body ok:", recorder.Body.String() == want, "| counted:", counting.bytes)
+
+	nf := &noFlushWriter{header: http.Header{}}
+	sseHandler(events, 0, &flushed).ServeHTTP(nf, httptest.NewRequest("GET", "/events", nil))
+	err := http.NewResponseController(nf).Flush()
+	fmt.Println("no Flush anywhere - fell back:", !flushed, "| body ok:", nf.body.String() == want,
+		"| error is ErrNotSupported:", errors.Is(err, http.ErrNotSupported))
+
+	// --- Over a real connection: events arrive while the handler is still running ---
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sseHandler(events, 50*time.Millisecond, &flushed).ServeHTTP(&countingResponseWriter{ResponseWriter: w}, r)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		fmt.Println("get:", err)
+		return
+	}
+	defer resp.Body.Close()
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+			fmt.Printf("  event %-6q after %v\n", data, time.Since(start).Round(50*time.Millisecond))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCountingWriterWriteString(t *testing.T) {
+	rec := &stringRecorder{}
+	cw := &CountingWriter{W: rec}
+	if n, err := io.WriteString(cw, "fast path"); n != 9 || err != nil {
+		t.Errorf("io.WriteString = %d, %v", n, err)
+	}
+	if rec.writeStringCalls != 1 || rec.writeCalls != 0 || cw.Count != 9 || rec.String() != "fast path" {
+		t.Errorf("underlying with WriteString: %d WriteString and %d Write calls, counted %d, wrote %q; want 1, 0, 9",
+			rec.writeStringCalls, rec.writeCalls, cw.Count, rec.String())
+	}
+
+	var sb strings.Builder
+	plain := &writeOnly{w: &sb}
+	cw = &CountingWriter{W: plain}
+	io.WriteString(cw, "fallback")
+	if plain.calls != 1 || cw.Count != 8 || sb.String() != "fallback" {
+		t.Errorf("underlying with only Write: %d calls, counted %d, wrote %q; want 1 and 8", plain.calls, cw.Count, sb.String())
+	}
+}
+
+// The fast path saves the []byte(s) copy - one allocation per call
+func TestWriteStringAllocs(t *testing.T) {
+	s := strings.Repeat("x", 64)
+	var b strings.Builder
+	b.Grow(1 << 20)
+	fast := &CountingWriter{W: &b}
+	slow := &CountingWriter{W: &writeOnly{w: io.Discard}}
+	if allocs := testing.AllocsPerRun(100, func() { fast.WriteString(s) }); allocs != 0 {
+		t.Errorf("fast path: %v allocations per WriteString, want 0", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { slow.WriteString(s) }); allocs != 1 {
+		t.Errorf("fallback: %v allocations per WriteString, want 1", allocs)
+	}
+}
+
+// Embedding http.ResponseWriter doesn't promote Flush
+func TestWrapperHidesFlush(t *testing.T) {
+	var w http.ResponseWriter = httptest.NewRecorder()
+	if _, ok := w.(http.Flusher); !ok {
+		t.Fatal("ResponseRecorder isn't an http.Flusher")
+	}
+	if _, ok := http.ResponseWriter(&countingResponseWriter{ResponseWriter: w}).(http.Flusher); ok {
+		t.Error("countingResponseWriter is an http.Flusher")
+	}
+}
+
+func TestSSEHandler(t *testing.T) {
+	events := []string{"hello", "tour", "bye"}
+	const want = "data: hello\n\ndata: tour\n\ndata: bye\n\n"
+
+	t.Run("flusher under the wrapper", func(t *testing.T) {
+		var flushed bool
+		recorder := httptest.NewRecorder()
+		counting := &countingResponseWriter{ResponseWriter: recorder}
+		sseHandler(events, 0, &flushed).ServeHTTP(counting, httptest.NewRequest("GET", "/events", nil))
+		if !flushed || !recorder.Flushed {
+			t.Errorf("flushed %t, recorder flushed %t; want both, through Unwrap", flushed, recorder.Flushed)
+		}
+		if got := recorder.Body.String(); got != want || counting.bytes != len(want) {
+			t.Errorf("body %q, counted %d; want %q and %d", got, counting.bytes, want, len(want))
+		}
+		if ct := recorder.Header().Get("Content-Type"); ct != "text/event-stream" {
+			t.Errorf("Content-Type %q", ct)
+		}
+	})
+
+	t.Run("no flusher", func(t *testing.T) {
+		flushed := true
+		nf := &noFlushWriter{header: http.Header{}}
+		sseHandler(events, 0, &flushed).ServeHTTP(nf, httptest.NewRequest("GET", "/events", nil))
+		if flushed {
+			t.Error("flushed with nothing to flush")
+		}
+		if got := nf.body.String(); got != want {
+			t.Errorf("body %q, want %q - every event, just at the end", got, want)
+		}
+		if err := http.NewResponseController(nf).Flush(); !errors.Is(err, http.ErrNotSupported) {
+			t.Errorf("Flush = %v, want ErrNotSupported", err)
+		}
+	})
+}
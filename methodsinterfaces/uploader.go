@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// === Ex. A plugin-style registry of interface implementations ===
+
+// Several storage backends implement one small interface, and each registers a constructor under a name.
+// The rest of the program only knows the interface and picks a backend by name at runtime,
+// ex. from a config file or a flag - the same idea as database/sql drivers, image formats (image.RegisterFormat),
+// or hash functions (crypto.RegisterHash).
+//
+// Because interfaces are implemented implicitly, a backend doesn't import or mention anything
+// besides the Uploader method set and RegisterUploader. The backends are in uploader_*.go,
+// and each file registers itself in its own init() - adding a backend means adding a file, nothing else changes.
+
+type Uploader interface {
+	// Upload stores the contents of r under key and returns where it ended up
+	Upload(key string, r io.Reader) (location string, err error)
+}
+
+// Optional extra behaviour, checked with a type assertion (like io.Copy checking for io.WriterTo).
+// Not every backend can list its contents, so it isn't part of Uploader itself
+type Lister interface {
+	List() []string
+}
+
+// UploaderFactory builds a backend from its settings
+type UploaderFactory func(config map[string]string) (Uploader, error)
+
+var (
+	uploadersMu sync.RWMutex
+	uploaders   = make(map[string]UploaderFactory)
+)
+
+var ErrUnknownUploader = errors.New("unknown uploader")
+
+// RegisterUploader is meant to be called from init().
+// A duplicate name is a programming error, so it panics (like sql.Register)
+func RegisterUploader(name string, factory UploaderFactory) {
+	uploadersMu.Lock()
+	defer uploadersMu.Unlock()
+	if factory == nil {
+		panic("RegisterUploader: nil factory for " + name)
+	}
+	if _, dup := uploaders[name]; dup {
+		panic("RegisterUploader: called twice for " + name)
+	}
+	uploaders[name] = factory
+}
+
+// NewUploader is the factory: it returns the backend registered under name, as the Uploader interface
+func NewUploader(name string, config map[string]string) (Uploader, error) {
+	uploadersMu.RLock()
+	factory, ok := uploaders[name]
+	uploadersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownUploader, name, strings.Join(UploaderNames(), ", "))
+	}
+	u, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("uploader %q: %w", name, err)
+	}
+	return u, nil
+}
+
+func UploaderNames() []string {
+	uploadersMu.RLock()
+	defer uploadersMu.RUnlock()
+	names := make([]string, 0, len(uploaders))
+	for name := range uploaders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// --- Code that only knows the interface ---
+
+func uploadReport(u Uploader, name string, report []byte) error {
+	location, err := u.Upload("reports/"+name, bytes.NewReader(report))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %T uploaded to %s\n", u, location)
+
+	if l, ok := u.(Lister); ok {
+		fmt.Println("  backend contents:", l.List())
+	}
+	return nil
+}
+
+func uploaderRegistryExamples() {
+	fmt.Println("registered backends:", UploaderNames())
+
+	dir, err := os.MkdirTemp("", "uploads")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	// The "config" - in a real program this comes from a file or flags
+	configs := []struct {
+		backend string
+		config  map[string]string
+	}{
+		{"memory", nil},
+		{"local", map[string]string{"dir": dir}},
+		{"s3-fake", map[string]string{"bucket": "tour-reports", "region": "ca-central-1"}},
+	}
+
+	report := []byte("total: 42\n")
+	for _, c := range configs {
+		fmt.Println(c.backend + ":")
+		u, err := NewUploader(c.backend, c.config)
+		if err != nil {
+			fmt.Println("  error:", err)
+			continue
+		}
+		if err := uploadReport(u, "2024-01.txt", report); err != nil {
+			fmt.Println("  error:", err)
+		}
+	}
+
+	// Errors from the factory
+	_, err = NewUploader("ftp", nil)
+	fmt.Println("unknown backend:", err, "| errors.Is ErrUnknownUploader:", errors.Is(err, ErrUnknownUploader))
+	_, err = NewUploader("s3-fake", map[string]string{})
+	fmt.Println("missing config:", err)
+
+	// Errors from a backend - the local one refuses keys escaping its directory
+	u, _ := NewUploader("local", map[string]string{"dir": dir})
+	_, err = u.Upload("../outside.txt", strings.NewReader("x"))
+	fmt.Println("path traversal:", err)
+}
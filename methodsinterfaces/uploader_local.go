@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// "local" backend - writes uploads as files under a directory.
+// Config: "dir" (required)
+
+func init() {
+	RegisterUploader("local", func(config map[string]string) (Uploader, error) {
+		dir := config["dir"]
+		if dir == "" {
+			return nil, errors.New(`missing "dir"`)
+		}
+		return &localUploader{dir: dir}, nil
+	})
+}
+
+type localUploader struct {
+	dir string
+}
+
+func (l *localUploader) Upload(key string, r io.Reader) (string, error) {
+	// os.Root (Go 1.24+) only allows access inside dir - a key like "../../etc/passwd" is an error
+	root, err := os.OpenRoot(l.dir)
+	if err != nil {
+		return "", err
+	}
+	defer root.Close()
+
+	if err := root.MkdirAll(filepath.Dir(key), 0o755); err != nil {
+		return "", err
+	}
+	f, err := root.Create(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.dir, key), nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// "memory" backend - keeps uploads in a map, handy for tests and demos
+
+func init() {
+	RegisterUploader("memory", func(config map[string]string) (Uploader, error) {
+		return &memoryUploader{files: make(map[string][]byte)}, nil
+	})
+}
+
+type memoryUploader struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func (m *memoryUploader) Upload(key string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[key] = data
+	return "memory://" + key, nil
+}
+
+func (m *memoryUploader) List() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Sorted(maps.Keys(m.files))
+}
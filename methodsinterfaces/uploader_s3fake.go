@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// "s3-fake" backend - pretends to be an S3 bucket, without any network.
+// Config: "bucket" (required), "region" (default us-east-1)
+
+func init() {
+	RegisterUploader("s3-fake", func(config map[string]string) (Uploader, error) {
+		bucket := config["bucket"]
+		if bucket == "" {
+			return nil, errors.New(`missing "bucket"`)
+		}
+		region := config["region"]
+		if region == "" {
+			region = "us-east-1"
+		}
+		return &fakeS3Uploader{bucket: bucket, region: region, objects: make(map[string]fakeS3Object)}, nil
+	})
+}
+
+type fakeS3Object struct {
+	data []byte
+	etag string
+}
+
+type fakeS3Uploader struct {
+	bucket, region string
+
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+func (s *fakeS3Uploader) Upload(key string, r io.Reader) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", fmt.Errorf("s3-fake: invalid object key %q", key)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	// S3 returns the MD5 of the object as its ETag (for single part uploads)
+	sum := md5.Sum(data)
+	obj := fakeS3Object{data: data, etag: hex.EncodeToString(sum[:])}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = obj
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s (etag %s)", s.bucket, s.region, key, obj.etag), nil
+}
+
+func (s *fakeS3Uploader) List() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Sorted(maps.Keys(s.objects))
+}
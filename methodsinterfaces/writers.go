@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// --- Writers ---
+
+// The other half of the io package. The io.Writer interface has a Write method:
+// func (T) Write(p []byte) (n int, err error)
+
+// Write writes len(p) bytes from p to the underlying data stream,
+// and returns the number of bytes written from p (0 <= n <= len(p)).
+// Rules from the io docs:
+// - it must return a non-nil error if it returns n < len(p)
+// - it must not modify the slice data, not even temporarily
+// - it must not keep (retain) p after returning, as the caller may reuse the slice
+
+// Anything that implements Write can be passed to fmt.Fprintf, io.Copy, io.MultiWriter, etc.
+// (files, network connections, strings.Builder, bytes.Buffer and os.Stdout are all Writers)
+
+// Ex. CountingWriter passes writes through to another writer and counts the bytes written
+// Uses a pointer receiver, since Write needs to update the count on the original value
+type CountingWriter struct {
+	W     io.Writer
+	Count int64
+}
+
+func (cw *CountingWriter) Write(p []byte) (int, error) {
+	n, err := cw.W.Write(p)
+	cw.Count += int64(n) // only count what was actually written, even on error
+	return n, err
+}
+
+// WriteString implements io.StringWriter, so io.WriteString and fmt don't need to convert to []byte first.
+// It only helps if W can take a string too - otherwise it converts, same as without the method
+// (the optional interface check, see upgrades.go)
+func (cw *CountingWriter) WriteString(s string) (int, error) {
+	var n int
+	var err error
+	if sw, ok := cw.W.(io.StringWriter); ok {
+		n, err = sw.WriteString(s)
+	} else {
+		n, err = cw.W.Write([]byte(s))
+	}
+	cw.Count += int64(n)
+	return n, err
+}
+
+// Ex. PrefixWriter writes a tag at the start of every line
+// (like the prefix of a log.Logger)
+type PrefixWriter struct {
+	W      io.Writer
+	Prefix string
+
+	// Write may be called with partial lines, so need to remember whether
+	// the next byte written is at the start of a line
+	midLine bool
+}
+
+func (pw *PrefixWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if !pw.midLine {
+			if _, err := io.WriteString(pw.W, pw.Prefix); err != nil {
+				return written, err
+			}
+			pw.midLine = true
+		}
+
+		// write up to and including the next newline (or the rest of p if there is none)
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+		}
+
+		n, err := pw.W.Write(line)
+		// n is the bytes written from p - the prefix bytes are not counted,
+		// otherwise callers like io.Copy would report a short write
+		written += n
+		if err != nil {
+			return written, err // still mid-line, even if the newline was in the part not written
+		}
+		pw.midLine = line[len(line)-1] != '\n'
+		p = p[len(line):]
+	}
+	return written, nil
+}
+
+func writerExamples() {
+	// --- CountingWriter ---
+
+	// fmt.Fprintf accepts any io.Writer as its first argument
+	var sb strings.Builder
+	counter := &CountingWriter{W: &sb}
+	fmt.Fprintf(counter, "Hello, %s!\n", "Writer")
+	fmt.Fprintf(counter, "%d + %d = %d\n", 2, 3, 2+3)
+
+	fmt.Printf("wrote %d bytes: %q\n", counter.Count, sb.String())
+
+	// --- PrefixWriter ---
+
+	// Partial lines across multiple writes still get exactly one prefix per line
+	prefixed := &PrefixWriter{W: os.Stdout, Prefix: "[notes] "}
+	fmt.Fprint(prefixed, "first line\nsecond ")
+	fmt.Fprint(prefixed, "line\n")
+
+	// --- Composing writers ---
+
+	// Writers that wrap other writers can be layered, like decorators.
+	// io.MultiWriter duplicates every write to all the writers it is given (like the unix tee command),
+	// Ex. write to stdout with a prefix, while also counting the same bytes and keeping a copy in a buffer
+	var buf bytes.Buffer
+	total := &CountingWriter{W: &buf}
+	out := io.MultiWriter(&PrefixWriter{W: os.Stdout, Prefix: "[multi] "}, total)
+
+	fmt.Fprintln(out, "written to two places")
+	fmt.Fprintln(out, "at the same time")
+
+	fmt.Printf("buffer copy (no prefix): %q, counted %d bytes\n", buf.String(), total.Count)
+
+	// --- io.Copy from a Reader into a Writer ---
+
+	// Pairs with the Reader example - io.Copy reads in chunks and writes each chunk as it goes
+	copyCounter := &CountingWriter{W: &PrefixWriter{W: os.Stdout, Prefix: "[copy] "}}
+	n, err := io.Copy(copyCounter, strings.NewReader("Hello, Reader\nHello, Writer\n"))
+	fmt.Println("io.Copy copied", n, "bytes, counted", copyCounter.Count, "err:", err)
+}
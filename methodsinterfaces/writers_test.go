@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// limitedWriter accepts limit bytes in all, then fails with errDiskFull (from embedding_test.go) -
+// a short write if it runs out partway through p. It has no WriteString, so CountingWriter falls back to Write
+type limitedWriter struct {
+	out   strings.Builder
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n, _ := w.out.Write(p[:w.limit])
+		w.limit = 0
+		return n, errDiskFull
+	}
+	w.limit -= len(p)
+	return w.out.Write(p)
+}
+
+func (w *limitedWriter) String() string { return w.out.String() }
+
+func TestPrefixWriterPartialLines(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"whole lines", []string{"a\nb\n"}, "> a\n> b\n"},
+		{"line split across writes", []string{"fir", "st\nsec", "ond\n"}, "> first\n> second\n"},
+		{"one byte at a time", strings.Split("ab\ncd\n", ""), "> ab\n> cd\n"},
+		{"newline on its own", []string{"a", "\n", "\n", "b"}, "> a\n> \n> b"},
+		{"no trailing newline", []string{"tail"}, "> tail"},
+		{"empty writes", []string{"", "x\n", ""}, "> x\n"},
+	} {
+		var sb strings.Builder
+		pw := &PrefixWriter{W: &sb, Prefix: "> "}
+		for _, s := range c.writes {
+			if n, err := pw.Write([]byte(s)); n != len(s) || err != nil {
+				t.Errorf("%s: Write(%q) = %d, %v; want %d, nil - the prefix isn't counted", c.name, s, n, err, len(s))
+			}
+		}
+		if sb.String() != c.want {
+			t.Errorf("%s: wrote %q, want %q", c.name, sb.String(), c.want)
+		}
+	}
+}
+
+func TestPrefixWriterFailingWriter(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		limit   int
+		wantN   int
+		wantOut string
+		nextOut string // after writing "z\n" once the writer works again
+	}{
+		{"fails on the first prefix", 1, 0, ">", "> z\n"},        // a torn prefix, then a whole one
+		{"fails mid-line", 3, 1, "> a", "z\n"},                   // still mid-line: no new prefix
+		{"fails just before a newline", 4, 2, "> ab", "z\n"},     // the newline went unwritten
+		{"fails on the second prefix", 6, 3, "> ab\n>", "> z\n"}, // the first line was complete
+		{"enough room", 100, 6, "> ab\n> cd\n", "> z\n"},
+	} {
+		w := &limitedWriter{limit: c.limit}
+		pw := &PrefixWriter{W: w, Prefix: "> "}
+		n, err := pw.Write([]byte("ab\ncd\n"))
+		if n != c.wantN || w.String() != c.wantOut {
+			t.Errorf("%s: Write = %d, wrote %q; want %d and %q", c.name, n, w.String(), c.wantN, c.wantOut)
+		}
+		if (err != nil) != (c.wantN < 6) || err != nil && !errors.Is(err, errDiskFull) {
+			t.Errorf("%s: err = %v", c.name, err)
+		}
+		w.out.Reset()
+		w.limit = 100
+		pw.Write([]byte("z\n"))
+		if w.String() != c.nextOut {
+			t.Errorf("%s: the next write gave %q, want %q", c.name, w.String(), c.nextOut)
+		}
+	}
+}
+
+func TestCountingWriterCountsWritten(t *testing.T) {
+	w := &limitedWriter{limit: 10}
+	cw := &CountingWriter{W: w}
+	if n, err := cw.Write([]byte("hello, ")); n != 7 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	n, err := cw.Write([]byte("world"))
+	if n != 3 || !errors.Is(err, errDiskFull) {
+		t.Errorf("short Write = %d, %v; want 3 and the writer's error", n, err)
+	}
+	if cw.Count != 10 {
+		t.Errorf("Count = %d after a short write, want the 10 bytes written", cw.Count)
+	}
+	// WriteString, through the Write fallback
+	n, err = cw.WriteString("more")
+	if n != 0 || err == nil || cw.Count != 10 {
+		t.Errorf("WriteString on a full writer = %d, %v, Count %d; want 0, an error and 10", n, err, cw.Count)
+	}
+
+	// through io.Copy, into a writer that fails partway
+	cw = &CountingWriter{W: &limitedWriter{limit: 4}}
+	copied, err := io.Copy(cw, strings.NewReader("0123456789"))
+	if copied != 4 || cw.Count != 4 || !errors.Is(err, errDiskFull) {
+		t.Errorf("io.Copy = %d, %v, Count %d; want 4 each and the writer's error", copied, err, cw.Count)
+	}
+}
@@ -0,0 +1,60 @@
+package pipeline
+
+import "context"
+
+// Demux is the opposite of a fan-in: it splits one channel into one channel per key
+// (ex. log entries by level, orders by customer), so each group can be handled by its own goroutine.
+//
+// The keys don't have to be known up front - the channel for a key is created the first time a value
+// with that key arrives, and announced on the returned channel as a Partition:
+//
+//	for part := range pipeline.Demux(ctx, entries, func(e Entry) string { return e.Level }) {
+//		go handle(part.Key, part.C) // start a consumer for each new key
+//	}
+//
+// When in is closed, every partition channel is closed, then the returned channel.
+// When ctx is cancelled, Demux stops and closes them the same way (values still in flight are dropped).
+//
+// The partition channels are unbuffered and fed by a single goroutine, so a slow consumer holds up
+// the other keys too (the value for the next key waits behind it). Consumers that must not stall
+// each other need their own buffering.
+
+// Partition is the channel carrying every value with the same key
+type Partition[K comparable, T any] struct {
+	Key K
+	C   <-chan T
+}
+
+func Demux[T any, K comparable](ctx context.Context, in <-chan T, key func(T) K) <-chan Partition[K, T] {
+	parts := make(chan Partition[K, T])
+	go func() {
+		outs := make(map[K]chan T)
+		// Demux is the only sender on all of these, so it closes them - on every way out of the loop
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+			close(parts)
+		}()
+
+		for v := range OrDone(ctx, in) { // a plain range would keep waiting on an idle in after ctx is cancelled
+			k := key(v)
+			out, ok := outs[k]
+			if !ok {
+				out = make(chan T)
+				outs[k] = out
+				select {
+				case parts <- Partition[K, T]{Key: k, C: out}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return parts
+}
@@ -0,0 +1,136 @@
+package pipeline
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type entry struct {
+	Level string
+	N     int
+}
+
+// collectPartitions starts a consumer for every partition Demux announces, and returns the keys in the order they
+// were announced and what each partition received, once every partition has closed
+func collectPartitions[K comparable, T any](parts <-chan Partition[K, T]) ([]K, map[K][]T) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		keys  []K
+		byKey = map[K][]T{}
+	)
+	for part := range parts {
+		keys = append(keys, part.Key)
+		wg.Go(func() {
+			got := drain(part.C)
+			mu.Lock()
+			byKey[part.Key] = got
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return keys, byKey
+}
+
+func TestDemux(t *testing.T) {
+	in := make(chan entry, 8)
+	for _, e := range []entry{{"INFO", 0}, {"INFO", 1}, {"WARN", 2}, {"INFO", 3}, {"ERROR", 4}, {"WARN", 5}} {
+		in <- e
+	}
+	close(in)
+	keys, byKey := collectPartitions(Demux(context.Background(), in, func(e entry) string { return e.Level }))
+
+	// a key's partition is announced when its first value arrives - WARN and ERROR mid-stream
+	if want := []string{"INFO", "WARN", "ERROR"}; !slices.Equal(keys, want) {
+		t.Errorf("partitions announced %v, want %v", keys, want)
+	}
+	for key, want := range map[string][]int{"INFO": {0, 1, 3}, "WARN": {2, 5}, "ERROR": {4}} {
+		var got []int
+		for _, e := range byKey[key] {
+			got = append(got, e.N)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s partition got %v, want %v in order", key, got, want)
+		}
+	}
+}
+
+// Closing in closes every partition (collect returns), then the Partition channel, and Demux's goroutine ends
+func TestDemuxClosesEveryPartition(t *testing.T) {
+	before := runtime.NumGoroutine()
+	keys, byKey := collectPartitions(Demux(context.Background(), values(100), func(n int) int { return n % 7 }))
+	if len(keys) != 7 {
+		t.Errorf("%d partitions, want 7", len(keys))
+	}
+	total := 0
+	for k, vs := range byKey {
+		total += len(vs)
+		for _, v := range vs {
+			if v%7 != k {
+				t.Errorf("%d in partition %d", v, k)
+			}
+		}
+	}
+	if total != 100 {
+		t.Errorf("%d values delivered, want 100", total)
+	}
+	waitGoroutines(t, before)
+}
+
+func TestDemuxEmpty(t *testing.T) {
+	if keys, _ := collectPartitions(Demux(context.Background(), values(0), func(n int) int { return n })); len(keys) != 0 {
+		t.Errorf("an empty input announced %v", keys)
+	}
+}
+
+// Cancelled at each place Demux can be blocked: waiting on an idle in, announcing a partition nobody takes,
+// and sending to a partition nobody reads. Each time every channel is closed and no goroutine is left
+func TestDemuxCancel(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		run  func(ctx context.Context, parts <-chan Partition[int, int], in chan<- int)
+	}{
+		{"in idle", func(ctx context.Context, parts <-chan Partition[int, int], in chan<- int) {
+			in <- 1
+			part := <-parts
+			<-part.C // Demux now waits for the next value, which never comes
+			go func() {
+				for range part.C {
+				}
+			}()
+		}},
+		{"announcing", func(ctx context.Context, parts <-chan Partition[int, int], in chan<- int) {
+			in <- 1 // Demux blocks announcing key 1 - nobody receives from parts yet
+		}},
+		{"sending", func(ctx context.Context, parts <-chan Partition[int, int], in chan<- int) {
+			in <- 1
+			<-parts // announced, but nobody reads the partition: Demux blocks sending the 1 on it
+		}},
+	} {
+		before := runtime.NumGoroutine()
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int) // never closed: only ctx can stop Demux
+		parts := Demux(ctx, in, func(n int) int { return n })
+		tc.run(ctx, parts, in)
+		cancel()
+
+		closed := make(chan struct{})
+		go func() {
+			for part := range parts { // whatever's left is closed too
+				for range part.C {
+				}
+			}
+			close(closed)
+		}()
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: the Partition channel still open a second after cancel", tc.name)
+		}
+		waitGoroutines(t, before)
+	}
+}
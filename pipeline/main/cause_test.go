@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// Run returns context.Cause, so each way of stopping comes back as its own error.
+// In a synctest bubble the stage's sleeps are fake time, and the timers fire at exactly 30ms
+func TestShutdownCause(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{"timeout cause", func() error {
+			ctx, cancel := context.WithTimeoutCause(context.Background(), 30*time.Millisecond, errTimeLimit)
+			defer cancel()
+			return endless(-1).Run(ctx)
+		}, errTimeLimit},
+		{"first error", func() error { return endless(5).Run(context.Background()) }, errUnlucky},
+		{"user abort", func() error {
+			ctx, abort := context.WithCancelCause(context.Background())
+			defer abort(nil)
+			time.AfterFunc(30*time.Millisecond, func() { abort(errAborted) })
+			return endless(-1).Run(ctx)
+		}, errAborted},
+		{"plain cancel", func() error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			time.AfterFunc(30*time.Millisecond, cancel)
+			return endless(-1).Run(ctx)
+		}, context.Canceled},
+	} {
+		synctest.Test(t, func(t *testing.T) {
+			if err := tc.run(); !errors.Is(err, tc.want) {
+				t.Errorf("%s: Run = %v, want %v", tc.name, err, tc.want)
+			}
+		})
+	}
+}
+
+// A cause set by the caller is what Run returns - not wrapped in, or replaced by, the error of a stage
+// that stopped because of it
+func TestShutdownCauseNotMasked(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, abort := context.WithCancelCause(context.Background())
+		defer abort(nil)
+		time.AfterFunc(30*time.Millisecond, func() { abort(errAborted) })
+		if err := endless(-1).Run(ctx); err != errAborted {
+			t.Errorf("Run = %v, want errAborted itself", err)
+		}
+		if err := endless(-1).Run(ctx); err != errAborted { // already cancelled: stops at once, same cause
+			t.Errorf("Run on a cancelled context = %v, want errAborted", err)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"pipeline"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// === Demux - sharding by key ===
+
+// A log pipeline: read lines -> parse -> split by level, with one consumer per level.
+// The consumer for a level only starts once a line with that level shows up (see pipeline.Demux) -
+// in this log DEBUG doesn't appear until near the end, so its consumer doesn't exist until then.
+//
+//	                                  ┌─> INFO  consumer
+//	logLines ──> parseLogs ──> Demux ─┼─> WARN  consumer
+//	                                  └─> ERROR consumer ...
+
+type logEntry struct {
+	Line  int
+	Level string
+	Msg   string
+}
+
+// logLine is line i of a made-up log. The levels first appear at different points:
+// INFO from the start, WARN from line 24, ERROR at line 49, DEBUG only at line 90
+func logLine(i int) string {
+	level, msg := "INFO", "request served"
+	switch {
+	case i == 90:
+		level, msg = "DEBUG", "cache stats dumped"
+	case i%50 == 49:
+		level, msg = "ERROR", "upstream timed out"
+	case i >= 20 && i%7 == 3:
+		level, msg = "WARN", "slow response"
+	}
+	return fmt.Sprintf("%04d %s %s", i, level, msg)
+}
+
+func logLines(ctx context.Context, n int) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for i := range n {
+			select {
+			case out <- logLine(i):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func parseLogs(ctx context.Context, in <-chan string) <-chan logEntry {
+	out := make(chan logEntry)
+	go func() {
+		defer close(out)
+		for line := range in {
+			num, rest, _ := strings.Cut(line, " ")
+			level, msg, _ := strings.Cut(rest, " ")
+			n, _ := strconv.Atoi(num)
+			select {
+			case out <- logEntry{Line: n, Level: level, Msg: msg}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func byLevel(e logEntry) string { return e.Level }
+
+func demuxExample() {
+	const lines = 100
+	before := runtime.NumGoroutine()
+	ctx := context.Background()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		announced []string // the keys, in the order their partitions appeared
+		shards    = map[string][]logEntry{}
+	)
+	for part := range pipeline.Demux(ctx, parseLogs(ctx, logLines(ctx, lines)), byLevel) {
+		announced = append(announced, part.Key)
+		wg.Go(func() {
+			var got []logEntry
+			for e := range part.C { // ends when Demux closes the partition
+				got = append(got, e)
+			}
+			mu.Lock()
+			shards[part.Key] = got
+			mu.Unlock()
+		})
+	}
+	wg.Wait() // the range over Demux ended, so every partition is closed - every consumer finishes
+
+	// --- Checks ---
+
+	// Keys appeared on demand, in the order they first show up in the log
+	var firstSeen []string
+	for i := range lines {
+		_, rest, _ := strings.Cut(logLine(i), " ")
+		if level, _, _ := strings.Cut(rest, " "); !slices.Contains(firstSeen, level) {
+			firstSeen = append(firstSeen, level)
+		}
+	}
+	fmt.Println("partitions, in order of appearance:", announced, "| matches the log:", slices.Equal(announced, firstSeen))
+
+	// Every entry went to its own level's shard, in log order, and none went missing
+	total, routed := 0, true
+	for _, level := range announced {
+		entries := shards[level]
+		total += len(entries)
+		routed = routed && slices.IsSortedFunc(entries, func(a, b logEntry) int { return a.Line - b.Line })
+		for _, e := range entries {
+			routed = routed && e.Level == level
+		}
+		fmt.Printf("  %-5s %3d entries, first at line %d\n", level, len(entries), entries[0].Line)
+	}
+	fmt.Printf("all %d lines delivered: %t | each to its own level, in order: %t\n", lines, total == lines, routed)
+
+	// Clean shutdown: closing the input closed every partition, so no goroutine is left behind
+	time.Sleep(10 * time.Millisecond)
+	fmt.Println("goroutines leaked after the input closed:", runtime.NumGoroutine()-before)
+
+	// --- Stopping early ---
+
+	// Cancel once the ERROR consumer has seen 3 entries: Demux closes every partition and returns,
+	// even though the source still had most of a million lines to send
+	earlyCtx, stop := context.WithCancel(context.Background())
+	var errorsSeen, infoSeen int
+	var early sync.WaitGroup
+	for part := range pipeline.Demux(earlyCtx, parseLogs(earlyCtx, logLines(earlyCtx, 1_000_000)), byLevel) {
+		early.Go(func() {
+			for range part.C {
+				mu.Lock()
+				switch part.Key {
+				case "ERROR":
+					if errorsSeen++; errorsSeen == 3 {
+						stop()
+					}
+				case "INFO":
+					infoSeen++
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	early.Wait()
+	stop()
+	time.Sleep(10 * time.Millisecond) // let the source and parser see ctx.Done() and exit
+	fmt.Printf("stopped after %d errors (%d INFO lines of 1000000) | goroutines leaked: %d\n",
+		errorsSeen, infoSeen, runtime.NumGoroutine()-before)
+}
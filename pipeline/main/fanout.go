@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"pipeline"
+	"runtime"
+	"time"
+)
+
+// === Fan-out / fan-in ===
+
+// Fan-out: several workers read from the SAME channel - each value is received by exactly one of them,
+// so the work is split without any extra coordination.
+// Fan-in: pipeline.Merge (stages.go) combines the workers' output channels back onto one channel -
+// one goroutine per input forwards its values to the output.
+//
+//	            ┌─> worker 0 ─┐
+//	generate ───┼─> worker 1 ─┼──> Merge ──> consumer
+//	            └─> worker 2 ─┘
+//
+// Closing order - each channel is closed by its only sender, after its last send:
+// 1. generate closes jobs when it runs out of input
+// 2. each worker's range over jobs then ends, and the worker closes its own output channel
+// 3. Merge's forwarding goroutines finish as their inputs close, and after the LAST one, Merge closes its output
+// 4. the consumer's range over the merged channel ends
+//
+// Unlike handRolledPipelineExample, every stage also selects on ctx.Done(),
+// so the consumer can stop early without leaving goroutines blocked on a send.
+
+type job struct {
+	ID    int
+	Input int
+}
+
+type jobResult struct {
+	JobID  int
+	Worker int
+	Output int
+}
+
+func generate(ctx context.Context, n int) <-chan job {
+	out := make(chan job)
+	go func() {
+		defer close(out)
+		for i := range n {
+			select {
+			case out <- job{ID: i, Input: i}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// process is one fan-out worker, with its own output channel
+func process(ctx context.Context, worker int, jobs <-chan job) <-chan jobResult {
+	out := make(chan jobResult)
+	go func() {
+		defer close(out)
+		for j := range jobs {
+			time.Sleep(time.Duration(j.ID%3) * time.Millisecond) // uneven work, so the workers finish out of order
+			select {
+			case out <- jobResult{JobID: j.ID, Worker: worker, Output: j.Input * j.Input}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func fanOut(ctx context.Context, jobs <-chan job, workers int) []<-chan jobResult {
+	outputs := make([]<-chan jobResult, workers)
+	for w := range workers {
+		outputs[w] = process(ctx, w, jobs)
+	}
+	return outputs
+}
+
+func fanOutFanInExample() {
+	const jobs, workers = 100, 4
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	seen := make(map[int]int) // job ID -> times a result came back
+	perWorker := make([]int, workers)
+	sum, inOrder, last := 0, true, -1
+
+	for r := range pipeline.Merge(ctx, fanOut(ctx, generate(ctx, jobs), workers)...) {
+		seen[r.JobID]++
+		perWorker[r.Worker]++
+		sum += r.Output
+		if r.JobID < last {
+			inOrder = false
+		}
+		last = r.JobID
+	}
+
+	// Checks: every job was processed exactly once, by one of the workers
+	exactlyOnce := len(seen) == jobs
+	for _, count := range seen {
+		if count != 1 {
+			exactlyOnce = false
+		}
+	}
+	wantSum := 0
+	for i := range jobs {
+		wantSum += i * i
+	}
+	fmt.Printf("%d results, every job exactly once: %t, sum correct: %t\n", len(seen), exactlyOnce, sum == wantSum)
+	fmt.Println("jobs per worker:", perWorker)
+	fmt.Println("results arrived in job order:", inOrder, "(fan-in doesn't keep the order)")
+
+	// --- Stopping early ---
+
+	before := runtime.NumGoroutine()
+	earlyCtx, stop := context.WithCancel(context.Background())
+	received := 0
+	for range pipeline.Merge(earlyCtx, fanOut(earlyCtx, generate(earlyCtx, 1_000_000), workers)...) {
+		received++
+		if received == 10 {
+			stop() // every stage sees ctx.Done() and returns, closing its channel on the way out
+		}
+	}
+	stop()
+	time.Sleep(20 * time.Millisecond) // let the stopped goroutines exit
+	fmt.Printf("stopped early after %d results (at least 10), goroutines leaked: %d\n",
+		received, runtime.NumGoroutine()-before)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"pipeline"
+	"runtime"
+	"testing"
+)
+
+// However many workers share the jobs channel, each job comes out of the merge exactly once, with its own result
+func TestFanOutExactlyOnce(t *testing.T) {
+	const jobs = 300
+	for _, workers := range []int{1, 2, 4, 16} {
+		ctx := context.Background()
+		seen := make([]int, jobs)
+		perWorker := make([]int, workers)
+		for r := range pipeline.Merge(ctx, fanOut(ctx, generate(ctx, jobs), workers)...) {
+			if r.JobID < 0 || r.JobID >= jobs {
+				t.Fatalf("%d workers: a result for job %d, which was never sent", workers, r.JobID)
+			}
+			seen[r.JobID]++
+			perWorker[r.Worker]++
+			if r.Output != r.JobID*r.JobID {
+				t.Errorf("%d workers: job %d gave %d, want %d", workers, r.JobID, r.Output, r.JobID*r.JobID)
+			}
+		}
+		for id, n := range seen {
+			if n != 1 {
+				t.Errorf("%d workers: job %d came back %d times, want exactly once", workers, id, n)
+			}
+		}
+		total := 0
+		for _, n := range perWorker {
+			total += n
+		}
+		if total != jobs {
+			t.Errorf("%d workers: %v jobs per worker, %d in all, want %d", workers, perWorker, total, jobs)
+		}
+	}
+}
+
+// Cancelling after a few results stops generate, every worker and Merge
+func TestFanOutCancel(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	received := 0
+	for range pipeline.Merge(ctx, fanOut(ctx, generate(ctx, 1_000_000), 4)...) {
+		if received++; received == 10 {
+			cancel()
+		}
+	}
+	cancel()
+	if received >= 1_000_000 {
+		t.Errorf("received all %d results after cancelling", received)
+	}
+	if left := goroutinesAbove(baseline); left != 0 {
+		t.Errorf("%d goroutines left behind after cancel", left)
+	}
+}
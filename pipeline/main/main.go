@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pipeline"
+	"sync"
+	"time"
+)
+
+// === Pipelines ===
+
+// A pipeline is a series of stages connected by channels,
+// where each stage is a group of goroutines running the same function.
+// Each stage receives values from upstream via an inbound channel,
+// does some work on them, and sends the results downstream on an outbound channel.
+
+// --- Hand-rolled version ---
+
+// Each stage owns (creates and closes) its outbound channel.
+// Only the sender closes a channel, so the range loop in the next stage ends.
+
+func gen(nums ...int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for _, n := range nums {
+			out <- n
+		}
+	}()
+	return out
+}
+
+func square(in <-chan int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for n := range in {
+			out <- n * n
+		}
+	}()
+	return out
+}
+
+// Running a stage with several workers needs a WaitGroup,
+// so the outbound channel is closed only after the LAST worker is done.
+func slowAddOne(in <-chan int, workers int) <-chan int {
+	out := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for n := range in {
+				time.Sleep(10 * time.Millisecond)
+				out <- n + 1
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Note - this version has no way to stop early. If the consumer stops reading
+// (ex. on an error), every goroutine upstream blocks on its send forever (goroutine leak).
+// Adding a done channel / context to every stage by hand is where the boilerplate grows
+// (see teardown.go for that version).
+func handRolledPipelineExample() {
+	total := 0
+	for n := range slowAddOne(square(gen(1, 2, 3, 4, 5, 6, 7, 8)), 4) {
+		total += n
+	}
+	fmt.Println("hand-rolled total:", total)
+}
+
+// --- Same pipeline using the builder ---
+
+func squareStage(ctx context.Context, n int) (int, error) {
+	return n * n, nil
+}
+
+func slowAddOneStage(ctx context.Context, n int) (int, error) {
+	select {
+	case <-time.After(10 * time.Millisecond):
+		return n + 1, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func builderPipelineExample() {
+	total := 0
+	err := pipeline.New[int]().
+		From(1, 2, 3, 4, 5, 6, 7, 8).
+		Stage(squareStage).
+		StageN(4, slowAddOneStage).
+		Sink(func(ctx context.Context, n int) error {
+			total += n // the sink runs on a single goroutine, so no lock is needed
+			return nil
+		}).
+		Run(context.Background())
+
+	fmt.Println("builder total:", total, "err:", err)
+}
+
+// --- Errors from any stage stop the whole pipeline ---
+
+var errUnlucky = errors.New("unlucky number")
+
+func errorPropagationExample() {
+	failOn := func(bad int) pipeline.StageFunc[int] {
+		return func(ctx context.Context, n int) (int, error) {
+			if n == bad {
+				return 0, fmt.Errorf("got %d: %w", n, errUnlucky)
+			}
+			return n, nil
+		}
+	}
+
+	// fails in the first stage (stage 0), then in the second (stage 1, with 3 workers), then in the sink
+	for _, p := range []*pipeline.Pipeline[int]{
+		pipeline.New[int]().From(1, 2, 13, 4).Stage(failOn(13)).Stage(squareStage),
+		pipeline.New[int]().From(1, 2, 3, 4).Stage(squareStage).StageN(3, failOn(9)),
+		pipeline.New[int]().From(1, 2, 3, 4).Stage(squareStage).Sink(func(ctx context.Context, n int) error {
+			if n > 4 {
+				return errUnlucky
+			}
+			return nil
+		}),
+	} {
+		err := p.Run(context.Background())
+		// the original error is still in the chain, so errors.Is works on the wrapped error
+		fmt.Printf("err: %v | is errUnlucky: %t\n", err, errors.Is(err, errUnlucky))
+	}
+
+	// ErrSkip drops a value instead of failing (works like a filter stage)
+	evens := []int{}
+	pipeline.New[int]().
+		From(1, 2, 3, 4, 5, 6).
+		Stage(func(ctx context.Context, n int) (int, error) {
+			if n%2 != 0 {
+				return 0, pipeline.ErrSkip
+			}
+			return n, nil
+		}).
+		Sink(func(ctx context.Context, n int) error {
+			evens = append(evens, n)
+			return nil
+		}).
+		Run(context.Background())
+	fmt.Println("evens:", evens)
+}
+
+// --- Cancellation ---
+
+// An endless source stops as soon as the context times out, and Run reports why
+func cancellationExample() {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	received := 0
+	err := pipeline.New[int]().
+		Source(func(ctx context.Context, out chan<- int) error {
+			for i := 0; ; i++ {
+				select {
+				case out <- i:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}).
+		StageN(4, slowAddOneStage).
+		Sink(func(ctx context.Context, n int) error {
+			received++
+			return nil
+		}).
+		Run(ctx)
+
+	fmt.Printf("received %d values before stopping, err: %v\n", received, err)
+}
+
+// --- Why did it stop? ---
+
+// Run returns context.Cause, so a custom cause passed by the caller comes back out of Run as is -
+// the output can say "timed out" / "failed" / "aborted" instead of just "context canceled"
+
+var (
+	errTimeLimit = errors.New("time limit reached")
+	errAborted   = errors.New("aborted by user")
+)
+
+// endless builds a pipeline that only stops when it is cancelled or a stage fails
+func endless(failAt int) *pipeline.Pipeline[int] {
+	return pipeline.New[int]().
+		Source(func(ctx context.Context, out chan<- int) error {
+			for i := 1; ; i++ {
+				select {
+				case out <- i:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}).
+		Stage(func(ctx context.Context, n int) (int, error) {
+			time.Sleep(time.Millisecond)
+			if n == failAt {
+				return 0, fmt.Errorf("got %d: %w", n, errUnlucky)
+			}
+			return n, nil
+		})
+}
+
+func shutdownCauseExample() {
+	report := func(name string, err, want error) {
+		reason := "failed: " + err.Error()
+		switch {
+		case errors.Is(err, errTimeLimit):
+			reason = "timed out"
+		case errors.Is(err, errAborted):
+			reason = "aborted"
+		}
+		fmt.Printf("%-12s stopped because %-50s cause as expected: %t\n", name+":", reason, errors.Is(err, want))
+	}
+
+	// Timeout with a cause
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 30*time.Millisecond, errTimeLimit)
+	report("timeout", endless(-1).Run(ctx), errTimeLimit)
+	cancel()
+
+	// First error - Run cancels its own context with the stage's error
+	report("first error", endless(5).Run(context.Background()), errUnlucky)
+
+	// User abort, from outside the pipeline
+	ctx, abort := context.WithCancelCause(context.Background())
+	time.AfterFunc(30*time.Millisecond, func() { abort(errAborted) })
+	report("user abort", endless(-1).Run(ctx), errAborted)
+
+	// Without a cause, all that's left is context.Canceled
+	ctx, plainCancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, plainCancel)
+	err := endless(-1).Run(ctx)
+	fmt.Println("plain cancel:", err, "| is context.Canceled:", errors.Is(err, context.Canceled))
+}
+
+func main() {
+	handRolledPipelineExample()
+	builderPipelineExample()
+	// errorPropagationExample()
+	// cancellationExample()
+	// shutdownCauseExample()
+	// fanOutFanInExample()
+	// demuxExample()
+	// orDoneTeeExample()
+	// pipelineTeardownExample()
+	// channelStagesExample()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"pipeline"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// === Or-done and tee ===
+
+// numbers sends 1..n (or forever, for n < 0) until ctx is cancelled
+func numbers(ctx context.Context, n int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 1; n < 0 || i <= n; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func orDoneTeeExample() {
+	before := runtime.NumGoroutine()
+	leaked := func() int {
+		time.Sleep(10 * time.Millisecond) // let the goroutines see ctx.Done() and return
+		return runtime.NumGoroutine() - before
+	}
+
+	// --- A channel that is never closed ---
+
+	// 3 values, and then nothing - the sender forgot to close it (or is stuck)
+	stuck := make(chan int, 3)
+	stuck <- 1
+	stuck <- 2
+	stuck <- 3
+
+	// A plain range loop receives the 3 values and then waits forever
+	plainDone := make(chan struct{})
+	go func() {
+		defer close(plainDone)
+		for range stuck {
+		}
+	}()
+	select {
+	case <-plainDone:
+		fmt.Println("plain range: returned")
+	case <-time.After(50 * time.Millisecond):
+		fmt.Println("plain range: still waiting after 50ms - the goroutine is leaked")
+	}
+
+	// The same loop over OrDone stops at the deadline
+	refill := make(chan int, 3)
+	refill <- 1
+	refill <- 2
+	refill <- 3
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	var got []int
+	start := time.Now()
+	for v := range pipeline.OrDone(ctx, refill) {
+		got = append(got, v)
+	}
+	cancel()
+	fmt.Printf("range over OrDone: got %v, returned after %v at the deadline | all values, then stopped: %t\n",
+		got, time.Since(start).Round(10*time.Millisecond), slices.Equal(got, []int{1, 2, 3}))
+
+	close(stuck) // only now does the plain loop end
+	<-plainDone
+	fmt.Println("goroutines leaked:", leaked())
+
+	// --- A sender that ignores ctx ---
+
+	// OrDone stops receiving, but can't stop the sender - it stays blocked on its next send
+	// until its owner stops it (here, with quit)
+	quit := make(chan struct{})
+	ignoresCtx := make(chan int)
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case ignoresCtx <- i:
+			case <-quit:
+				return
+			}
+		}
+	}()
+	ctx, cancel = context.WithCancel(context.Background())
+	for v := range pipeline.OrDone(ctx, ignoresCtx) {
+		if v == 5 {
+			cancel()
+		}
+	}
+	fmt.Println("after cancelling OrDone, the sender is still running:", leaked() == 1)
+	close(quit)
+	fmt.Println("after its owner stops it:", leaked() == 0)
+
+	// --- Tee ---
+
+	// Both copies get every value, in order
+	a, b := pipeline.Tee(context.Background(), numbers(context.Background(), 10))
+	var gotA, gotB []int
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for v := range a {
+			gotA = append(gotA, v)
+		}
+	})
+	wg.Go(func() {
+		for v := range b {
+			gotB = append(gotB, v)
+			time.Sleep(time.Millisecond) // a slower consumer - it paces the other one too
+		}
+	})
+	wg.Wait()
+	fmt.Println("tee of 1..10:", gotA, gotB, "| both got all of them:", slices.Equal(gotA, gotB) && len(gotA) == 10)
+
+	// Cancelling stops the endless source, the tee, and both consumers
+	ctx, cancel = context.WithCancel(context.Background())
+	a, b = pipeline.Tee(ctx, numbers(ctx, -1))
+	var nA, nB int
+	wg.Go(func() {
+		for range a {
+			if nA++; nA == 100 {
+				cancel()
+			}
+		}
+	})
+	wg.Go(func() {
+		for range b {
+			nB++
+		}
+	})
+	wg.Wait() // both channels closed - otherwise this would hang
+	cancel()
+	fmt.Printf("cancelled tee: consumer a got %d, b got %d | in lockstep: %t | goroutines leaked: %d\n",
+		nA, nB, max(nA, nB)-min(nA, nB) <= 1, leaked())
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"pipeline"
+	"runtime"
+	"slices"
+	"strconv"
+)
+
+// === Generic channel stages: MapCh, FilterCh, Merge, Take (see pipeline/stages.go) ===
+
+func channelStagesExample() {
+	isEven := func(n int) bool { return n%2 == 0 }
+
+	// numbers -> square -> keep even -> first 5
+	ctx, cancel := context.WithCancel(context.Background())
+	baseline := runtime.NumGoroutine()
+	squares := pipeline.MapCh(ctx, numbers(ctx, -1), func(n int) int { return n * n }) // an endless source
+	var got []int
+	for v := range pipeline.Take(ctx, pipeline.FilterCh(ctx, squares, isEven), 5) {
+		got = append(got, v)
+	}
+	fmt.Println("first 5 even squares of an endless stream:", got)
+	fmt.Println("after Take, before cancel - stages still blocked:", runtime.NumGoroutine() > baseline)
+	cancel()
+	fmt.Println("after cancel, goroutines left behind:", goroutinesAbove(baseline)) // goroutinesAbove is in teardown.go
+
+	// Changing the type along the way: ints -> strings, and two sources merged
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	labels := pipeline.MapCh(ctx, pipeline.Merge(ctx, numbers(ctx, 3), numbers(ctx, 3)), func(n int) string { return "#" + strconv.Itoa(n) })
+	var merged []string
+	for s := range labels {
+		merged = append(merged, s)
+	}
+	slices.Sort(merged) // merge order depends on scheduling
+	fmt.Println("merged and mapped to strings:", merged)
+
+	// pipeline/stages_test.go covers the edges: empty inputs, Take 0 or past the end, Merge of nothing
+
+	// Cancelled in the middle: every stage returns, even blocked on a send nobody receives
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	baseline = runtime.NumGoroutine()
+	stalled := pipeline.FilterCh(ctx2, pipeline.MapCh(ctx2, pipeline.Merge(ctx2, numbers(ctx2, -1), numbers(ctx2, -1)), func(n int) int { return n + 1 }), isEven)
+	<-stalled // one value, then the consumer walks away
+	cancel2()
+	fmt.Println("abandoned mid-stream, then cancelled - goroutines left behind:", goroutinesAbove(baseline))
+}
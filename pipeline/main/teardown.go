@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// === Tearing down a hand-rolled pipeline ===
+
+// The hand-rolled pipeline in main.go can't stop early: a consumer that stops reading leaves every stage
+// upstream blocked on a send, forever. The fix, by hand, is a done channel passed to every stage, and
+// every send (and receive) in a select with it - closing done then unblocks every stage at once,
+// since a receive from a closed channel is always ready.
+//
+// ctx.Done() is exactly such a channel, so the same stages work with a context:
+// cancel() closes it. Either way, the one who closes it is the consumer - it's the one that knows
+// it has stopped reading.
+
+func genDone(done <-chan struct{}, nums ...int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for _, n := range nums {
+			select {
+			case out <- n:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func squareDone(done <-chan struct{}, in <-chan int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for n := range in { // ends when upstream closes in - which it does once it sees done
+			select {
+			case out <- n * n:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func slowAddOneDone(done <-chan struct{}, in <-chan int, workers int) <-chan int {
+	out := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for n := range in {
+				select {
+				case <-time.After(10 * time.Millisecond):
+				case <-done: // don't finish the slow work for a result nobody wants
+					return
+				}
+				select {
+				case out <- n + 1:
+				case <-done:
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// goroutinesAbove waits up to a second for the goroutine count to drop back to baseline,
+// and returns how many are still above it (the stages need a moment to see done and return)
+func goroutinesAbove(baseline int) int {
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return runtime.NumGoroutine() - baseline
+}
+
+func pipelineTeardownExample() {
+	nums := make([]int, 1000)
+	for i := range nums {
+		nums[i] = i
+	}
+
+	// The original pipeline, abandoned after 3 values: gen, square, the 4 workers and the closer are stuck
+	baseline := runtime.NumGoroutine()
+	out := slowAddOne(square(gen(nums...)), 4)
+	for range 3 {
+		<-out
+	}
+	fmt.Println("no done channel - stopped reading after 3 values, goroutines left behind:", goroutinesAbove(baseline))
+	// (they stay blocked until the program exits - the baselines below start from here)
+
+	// Closing a done channel
+	baseline = runtime.NumGoroutine()
+	done := make(chan struct{})
+	out = slowAddOneDone(done, squareDone(done, genDone(done, nums...)), 4)
+	for range 3 {
+		<-out
+	}
+	fmt.Println("with a done channel, before close(done) - goroutines running:", runtime.NumGoroutine()-baseline)
+	close(done)
+	fmt.Println("after close(done), left behind:", goroutinesAbove(baseline))
+
+	// Cancelling a context - the same stages, given ctx.Done()
+	baseline = runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	out = slowAddOneDone(ctx.Done(), squareDone(ctx.Done(), genDone(ctx.Done(), nums...)), 4)
+	got := 0
+	for range out {
+		if got++; got == 3 {
+			cancel()
+			break
+		}
+	}
+	fmt.Println("after cancel(), left behind:", goroutinesAbove(baseline)) // teardown_test.go checks both with go test
+
+	// Cancelling doesn't lose anything when the consumer reads to the end - done is just never closed early
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	total := 0
+	for n := range slowAddOneDone(ctx.Done(), squareDone(ctx.Done(), genDone(ctx.Done(), 1, 2, 3, 4, 5, 6, 7, 8)), 4) {
+		total += n
+	}
+	fmt.Println("read to the end - total:", total, "(212, the same as the hand-rolled pipeline)")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func upTo(n int) []int {
+	nums := make([]int, n)
+	for i := range nums {
+		nums[i] = i
+	}
+	return nums
+}
+
+func TestTeardownCloseDone(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	done := make(chan struct{})
+	out := slowAddOneDone(done, squareDone(done, genDone(done, upTo(1000)...)), 4)
+	for range 3 {
+		<-out
+	}
+	if runtime.NumGoroutine() <= baseline {
+		t.Fatal("no stage goroutines running before close(done) - nothing to tear down")
+	}
+	close(done)
+	if left := goroutinesAbove(baseline); left != 0 {
+		t.Errorf("%d goroutines left behind after close(done)", left)
+	}
+}
+
+func TestTeardownCancel(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	out := slowAddOneDone(ctx.Done(), squareDone(ctx.Done(), genDone(ctx.Done(), upTo(1000)...)), 4)
+	got := 0
+	for range out {
+		if got++; got == 3 {
+			break
+		}
+	}
+	cancel()
+	if left := goroutinesAbove(baseline); left != 0 {
+		t.Errorf("%d goroutines left behind after cancel()", left)
+	}
+}
+
+// Cancelled before anything was read: every stage is still blocked on its first send, and must return too
+func TestTeardownCancelBeforeReading(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	slowAddOneDone(ctx.Done(), squareDone(ctx.Done(), genDone(ctx.Done(), upTo(10)...)), 4)
+	cancel()
+	if left := goroutinesAbove(baseline); left != 0 {
+		t.Errorf("%d goroutines left behind", left)
+	}
+}
+
+// Read to the end, the done stages give the same answer as the hand-rolled pipeline, and all exit
+func TestTeardownReadToTheEnd(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	done := make(chan struct{})
+	defer close(done)
+	nums := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	want := 0
+	for n := range slowAddOne(square(gen(nums...)), 4) {
+		want += n
+	}
+	got := 0
+	for n := range slowAddOneDone(done, squareDone(done, genDone(done, nums...)), 4) {
+		got += n
+	}
+	if got != want || want != 212 {
+		t.Errorf("total %d, hand-rolled pipeline %d, want 212", got, want)
+	}
+	if left := goroutinesAbove(baseline); left != 0 {
+		t.Errorf("%d goroutines left behind with done never closed", left)
+	}
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import "context"
+
+// A goroutine that receives from a channel it doesn't own can only stop when that channel closes.
+// If the sender never closes it (it's stuck, or it's an endless source that doesn't watch ctx),
+// a plain `for v := range in` never ends, and whatever the loop holds on to stays alive with it.
+// Every receive has to be a select on the channel AND ctx.Done() - OrDone wraps that up once,
+// so the loop can stay a plain range:
+//
+//	for v := range pipeline.OrDone(ctx, in) { // ends when in closes OR ctx is cancelled
+//		...
+//	}
+//
+// OrDone frees the receiving side only. A sender that ignores ctx is still blocked on its next send
+// after OrDone stops receiving - stopping it is up to whatever started it.
+
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done(): // the receiver may have left too - don't block on the send either
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Tee sends every value from in to both returned channels, like the tee command (ex. one copy to be
+// processed and one to be logged). Both close when in closes or ctx is cancelled.
+//
+// Each value is sent to both before the next one is taken from in, so the two receivers move
+// in lockstep - the slower one sets the pace for both. If one receiver stops reading, the other
+// stalls too, so both must keep reading until the channels close, or cancel ctx.
+
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	out1, out2 := make(chan T), make(chan T)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		for v := range OrDone(ctx, in) {
+			// a send on a nil channel never happens, so after one side has its copy it's set to nil
+			// and the select only waits on the other - whichever receiver is ready first goes first
+			o1, o2 := out1, out2
+			for range 2 {
+				select {
+				case o1 <- v:
+					o1 = nil
+				case o2 <- v:
+					o2 = nil
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out1, out2
+}
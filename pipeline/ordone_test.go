@@ -0,0 +1,118 @@
+package pipeline
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitGoroutines waits (up to a second) for the goroutine count to drop back to want
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines still running, want %d - a goroutine leaked", runtime.NumGoroutine(), want)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func values(n int) <-chan int {
+	ch := make(chan int, n)
+	for i := range n {
+		ch <- i
+	}
+	close(ch)
+	return ch
+}
+
+func TestOrDoneUntilClosed(t *testing.T) {
+	var got []int
+	for v := range OrDone(context.Background(), values(5)) {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("got %v, want [0 1 2 3 4]", got)
+	}
+}
+
+// A channel nobody ever closes: the range still ends once ctx is cancelled
+func TestOrDoneCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	never := make(chan int)
+	ended := make(chan struct{})
+	go func() {
+		for range OrDone(ctx, never) {
+		}
+		close(ended)
+	}()
+	cancel()
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("range over OrDone still blocked after cancel")
+	}
+	waitGoroutines(t, before)
+}
+
+// Cancelled while holding a value nobody receives: OrDone's goroutine must not stay blocked on that send
+func TestOrDoneCancelWhileSending(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	out := OrDone(ctx, values(3))
+	<-out // the goroutine now holds 1, and waits to send it
+	cancel()
+	waitGoroutines(t, before)
+}
+
+func TestTee(t *testing.T) {
+	a, b := Tee(context.Background(), values(100))
+	var gotA, gotB []int
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for v := range a {
+			gotA = append(gotA, v)
+		}
+	})
+	wg.Go(func() {
+		for v := range b {
+			gotB = append(gotB, v)
+			time.Sleep(10 * time.Microsecond) // a slower reader only slows the other down
+		}
+	})
+	wg.Wait()
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i
+	}
+	if !slices.Equal(gotA, want) || !slices.Equal(gotB, want) {
+		t.Errorf("Tee copies: %v and %v, want 0..99 in both", gotA, gotB)
+	}
+}
+
+// With one side never read, the other stalls - cancelling ctx is what frees both and the goroutine
+func TestTeeCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	a, b := Tee(ctx, values(10))
+	if v := <-a; v != 0 {
+		t.Fatalf("first value %d, want 0", v)
+	}
+	select {
+	case v := <-a:
+		t.Fatalf("a got %d while b hasn't taken 0 yet, want the two in lockstep", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	for range a {
+	}
+	for range b {
+	}
+	waitGoroutines(t, before)
+}
@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// A small builder for channel pipelines:
+//
+//	err := pipeline.New[int]().
+//		From(1, 2, 3).
+//		Stage(double).
+//		StageN(4, slowLookup).
+//		Sink(print).
+//		Run(ctx)
+//
+// Each stage runs in its own goroutine(s) connected by unbuffered channels.
+// The builder takes care of the parts that are easy to get wrong by hand:
+// - closing each channel exactly once, after ALL the workers writing to it have finished
+// - stopping every goroutine when the context is cancelled (so none are leaked)
+// - stopping the whole pipeline on the first error and returning that error from Run
+
+// Note - methods cannot have their own type parameters in Go,
+// so every stage takes and returns the same type T.
+// Use a struct type for T if the stages need to carry different data.
+
+// StageFunc transforms one value. Returning an error stops the pipeline,
+// unless the error is ErrSkip, which drops just that value (like a filter).
+type StageFunc[T any] func(ctx context.Context, v T) (T, error)
+
+// ErrSkip can be returned by a StageFunc to drop a value without stopping the pipeline.
+var ErrSkip = errors.New("pipeline: skip value")
+
+type stage[T any] struct {
+	workers int
+	fn      StageFunc[T]
+}
+
+type Pipeline[T any] struct {
+	source func(ctx context.Context, out chan<- T) error
+	stages []stage[T]
+	sink   func(ctx context.Context, v T) error
+}
+
+func New[T any]() *Pipeline[T] {
+	return &Pipeline[T]{}
+}
+
+// Source sets the function producing the pipeline's input.
+// It should send on out until it runs out of values, then return (the pipeline closes out, not the source).
+// Sends should select on ctx.Done() so the source stops when the pipeline is cancelled.
+func (p *Pipeline[T]) Source(fn func(ctx context.Context, out chan<- T) error) *Pipeline[T] {
+	p.source = fn
+	return p
+}
+
+// From is a Source that sends each of the given values in order.
+func (p *Pipeline[T]) From(values ...T) *Pipeline[T] {
+	return p.Source(func(ctx context.Context, out chan<- T) error {
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// Stage adds a stage processed by a single goroutine (keeps the input order).
+func (p *Pipeline[T]) Stage(fn StageFunc[T]) *Pipeline[T] {
+	return p.StageN(1, fn)
+}
+
+// StageN adds a stage processed by n goroutines in parallel (fan-out / fan-in).
+// Output order is not guaranteed when n > 1.
+func (p *Pipeline[T]) StageN(n int, fn StageFunc[T]) *Pipeline[T] {
+	p.stages = append(p.stages, stage[T]{workers: max(n, 1), fn: fn})
+	return p
+}
+
+// Sink sets the function receiving the pipeline's output. It is called from a single goroutine.
+func (p *Pipeline[T]) Sink(fn func(ctx context.Context, v T) error) *Pipeline[T] {
+	p.sink = fn
+	return p
+}
+
+// Run starts every stage and blocks until the pipeline finishes.
+// It returns the first error from any stage (wrapped with the stage's position),
+// or the context's error if ctx was cancelled first.
+func (p *Pipeline[T]) Run(ctx context.Context) error {
+	if p.source == nil {
+		return errors.New("pipeline: no source")
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// fail records the first error and cancels the context so every other goroutine stops.
+	// Later calls are no-ops (cancel only keeps the first cause)
+	fail := func(err error) {
+		cancel(err)
+	}
+
+	var wg sync.WaitGroup
+
+	// --- source ---
+	src := make(chan T)
+	wg.Go(func() {
+		defer close(src)
+		if err := p.source(ctx, src); err != nil {
+			fail(fmt.Errorf("pipeline: source: %w", err))
+		}
+	})
+	var in <-chan T = src
+
+	// --- stages ---
+	for i, s := range p.stages {
+		// in is reassigned below, so bind this stage's input to its own variable
+		// before the worker goroutines capture it
+		stageIn, out := in, make(chan T)
+		var stageWg sync.WaitGroup
+
+		for range s.workers {
+			stageWg.Go(func() {
+				runStage(ctx, i, s.fn, stageIn, out, fail)
+			})
+		}
+
+		// out can only be closed once every worker of this stage is done sending on it
+		wg.Go(func() {
+			stageWg.Wait()
+			close(out)
+		})
+
+		in = out // the output of this stage is the input of the next one
+	}
+
+	// --- sink ---
+	// Keep receiving even after an error, so upstream senders are never left blocked.
+	// (They also watch ctx.Done(), but draining makes the shutdown order not matter)
+	for v := range in {
+		if ctx.Err() != nil || p.sink == nil {
+			continue
+		}
+		if err := p.sink(ctx, v); err != nil {
+			fail(fmt.Errorf("pipeline: sink: %w", err))
+		}
+	}
+
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+func runStage[T any](ctx context.Context, i int, fn StageFunc[T], in <-chan T, out chan<- T, fail func(error)) {
+	for v := range in {
+		if ctx.Err() != nil {
+			continue // drain the remaining input without processing it
+		}
+
+		res, err := fn(ctx, v)
+		if errors.Is(err, ErrSkip) {
+			continue
+		}
+		if err != nil {
+			fail(fmt.Errorf("pipeline: stage %d: %w", i, err))
+			continue
+		}
+
+		select {
+		case out <- res:
+		case <-ctx.Done():
+		}
+	}
+}
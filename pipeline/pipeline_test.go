@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func double(_ context.Context, v int) (int, error) { return v * 2, nil }
+
+// collect is a Sink appending to got - safe from one goroutine, which is how Run calls a sink
+func collect(got *[]int) func(context.Context, int) error {
+	return func(_ context.Context, v int) error {
+		*got = append(*got, v)
+		return nil
+	}
+}
+
+func TestRunInOrder(t *testing.T) {
+	var got []int
+	err := New[int]().From(1, 2, 3, 4).Stage(double).Stage(double).Sink(collect(&got)).Run(context.Background())
+	if err != nil || !slices.Equal(got, []int{4, 8, 12, 16}) {
+		t.Errorf("Run = %v, got %v, want [4 8 12 16]", err, got)
+	}
+}
+
+func TestStageN(t *testing.T) {
+	var inFlight, most int
+	var mu sync.Mutex
+	slow := func(_ context.Context, v int) (int, error) {
+		mu.Lock()
+		inFlight++
+		most = max(most, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return v + 100, nil
+	}
+	var got []int
+	err := New[int]().From(1, 2, 3, 4, 5, 6, 7, 8).StageN(4, slow).Sink(collect(&got)).Run(context.Background())
+	slices.Sort(got) // StageN doesn't keep the order
+	if err != nil || !slices.Equal(got, []int{101, 102, 103, 104, 105, 106, 107, 108}) {
+		t.Errorf("Run = %v, got %v", err, got)
+	}
+	if most < 2 || most > 4 {
+		t.Errorf("at most %d values in the stage at once, want 2 to 4 with StageN(4)", most)
+	}
+}
+
+func TestErrSkip(t *testing.T) {
+	odd := func(_ context.Context, v int) (int, error) {
+		if v%2 == 0 {
+			return 0, ErrSkip
+		}
+		return v, nil
+	}
+	var got []int
+	err := New[int]().From(1, 2, 3, 4, 5).Stage(odd).Sink(collect(&got)).Run(context.Background())
+	if err != nil || !slices.Equal(got, []int{1, 3, 5}) {
+		t.Errorf("Run = %v, got %v, want [1 3 5]", err, got)
+	}
+}
+
+var errBoom = errors.New("boom")
+
+// failOn returns a stage that fails on the value n and passes every other value on
+func failOn(n int) func(context.Context, int) (int, error) {
+	return func(_ context.Context, v int) (int, error) {
+		if v == n {
+			return 0, errBoom
+		}
+		return v, nil
+	}
+}
+
+// An error from any part stops the pipeline, and Run returns it - saying where it came from
+func TestErrorFromAnyStage(t *testing.T) {
+	failingSource := func(ctx context.Context, out chan<- int) error {
+		out <- 1
+		return errBoom
+	}
+	failingSink := func(ctx context.Context, v int) error {
+		_, err := failOn(6)(ctx, v)
+		return err
+	}
+	for _, c := range []struct {
+		name  string
+		p     *Pipeline[int]
+		where string
+	}{
+		{"source", New[int]().Source(failingSource).Stage(double), "pipeline: source"},
+		{"first stage", New[int]().From(1, 2, 3, 4).Stage(failOn(3)).Stage(double), "pipeline: stage 0"},
+		{"last stage", New[int]().From(1, 2, 3, 4).Stage(double).Stage(double).Stage(failOn(12)), "pipeline: stage 2"},
+		{"parallel stage", New[int]().From(1, 2, 3, 4).StageN(3, failOn(3)), "pipeline: stage 0"},
+		{"sink", New[int]().From(1, 2, 3, 4).Stage(double).Sink(failingSink), "pipeline: sink"},
+	} {
+		err := c.p.Run(context.Background())
+		if !errors.Is(err, errBoom) || !strings.HasPrefix(err.Error(), c.where+":") {
+			t.Errorf("%s: Run = %v, want errBoom from %q", c.name, err, c.where)
+		}
+	}
+}
+
+func TestErrorStopsTheRest(t *testing.T) {
+	var mu sync.Mutex
+	var sunk int
+	endless := func(ctx context.Context, out chan<- int) error {
+		for i := 0; ; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	err := New[int]().Source(endless).Stage(failOn(3)).Sink(func(context.Context, int) error {
+		mu.Lock()
+		sunk++
+		mu.Unlock()
+		return nil
+	}).Run(context.Background())
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Run = %v, want errBoom - an endless source must stop too", err)
+	}
+	if sunk > 3 {
+		t.Errorf("%d values reached the sink, want at most 3 (0, 1, 2 came before the failing 3)", sunk)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	endless := func(ctx context.Context, out chan<- int) error {
+		for i := 0; ; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+	err := New[int]().Source(endless).StageN(4, double).Sink(func(_ context.Context, v int) error {
+		if v > 100 {
+			cancel()
+		}
+		return nil
+	}).Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+	// every goroutine Run started has returned by the time it does
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines before Run, %d after", before, after)
+	}
+}
+
+func TestNoSource(t *testing.T) {
+	if err := New[int]().Stage(double).Run(context.Background()); err == nil {
+		t.Error("Run without a source: no error")
+	}
+}
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Channel combinators: each takes channels and returns a channel, so they nest into a pipeline -
+//
+//	squares := pipeline.MapCh(ctx, nums, func(n int) int { return n * n })
+//	firstFive := pipeline.Take(ctx, pipeline.FilterCh(ctx, squares, isEven), 5)
+//
+// Unlike the builder in pipeline.go, a stage can change the type: MapCh[T, U] turns a <-chan T into a <-chan U
+// (functions can have their own type parameters, methods can't).
+// Each runs in its own goroutine, closes its output when its input closes, and returns as soon as ctx is
+// cancelled - including when it's blocked sending to a consumer that has gone. Cancelling ctx is how the
+// consumer stops all of them at once; every receive goes through OrDone.
+
+// MapCh sends f(v) for every v from in
+func MapCh[T, U any](ctx context.Context, in <-chan T, f func(T) U) <-chan U {
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for v := range OrDone(ctx, in) {
+			select {
+			case out <- f(v):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FilterCh passes on the values keep returns true for
+func FilterCh[T any](ctx context.Context, in <-chan T, keep func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range OrDone(ctx, in) {
+			if !keep(v) {
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Merge sends the values from all inputs on one channel, in whatever order they arrive (fan-in).
+// It closes once every input has closed
+func Merge[T any](ctx context.Context, inputs ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, in := range inputs {
+		wg.Go(func() {
+			for v := range OrDone(ctx, in) {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait() // every forwarder is done, so nothing can send on out anymore
+		close(out)
+	}()
+	return out
+}
+
+// Take passes on the first n values, then closes - without reading in any further.
+// The stages before it are then blocked on their next send until ctx is cancelled,
+// so cancel ctx once you're done (a defer cancel() where the pipeline is built)
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for range n {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
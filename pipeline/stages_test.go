@@ -0,0 +1,118 @@
+package pipeline
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// endless sends 1, 2, 3, ... until ctx is cancelled
+func endless(ctx context.Context) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 1; ; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func drain[T any](ch <-chan T) []T {
+	var got []T
+	for v := range ch {
+		got = append(got, v)
+	}
+	return got
+}
+
+func isEven(n int) bool { return n%2 == 0 }
+
+func TestMapCh(t *testing.T) {
+	got := drain(MapCh(context.Background(), values(4), func(n int) string { return "#" + strconv.Itoa(n) }))
+	if want := []string{"#0", "#1", "#2", "#3"}; !slices.Equal(got, want) {
+		t.Errorf("MapCh: %v, want %v", got, want)
+	}
+	if got := drain(MapCh(context.Background(), values(0), strconv.Itoa)); len(got) != 0 {
+		t.Errorf("MapCh of an empty input: %v", got)
+	}
+}
+
+func TestFilterCh(t *testing.T) {
+	if got := drain(FilterCh(context.Background(), values(7), isEven)); !slices.Equal(got, []int{0, 2, 4, 6}) {
+		t.Errorf("FilterCh: %v, want [0 2 4 6]", got)
+	}
+	if got := drain(FilterCh(context.Background(), values(5), func(int) bool { return false })); len(got) != 0 {
+		t.Errorf("FilterCh keeping nothing: %v", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+	got := drain(Merge(ctx, values(3), values(5), values(0)))
+	slices.Sort(got) // the order depends on scheduling
+	if want := []int{0, 0, 1, 1, 2, 2, 3, 4}; !slices.Equal(got, want) {
+		t.Errorf("Merge: %v, want %v in some order", got, want)
+	}
+	if _, open := <-Merge[int](ctx); open {
+		t.Error("Merge of no inputs didn't close")
+	}
+}
+
+func TestTake(t *testing.T) {
+	ctx := context.Background()
+	for _, c := range []struct {
+		n, take int
+		want    []int
+	}{
+		{5, 3, []int{0, 1, 2}},
+		{2, 10, []int{0, 1}}, // fewer than n: closes when in does
+		{5, 0, nil},
+		{5, -1, nil},
+	} {
+		if got := drain(Take(ctx, values(c.n), c.take)); !slices.Equal(got, c.want) {
+			t.Errorf("Take %d of %d values: %v, want %v", c.take, c.n, got, c.want)
+		}
+	}
+}
+
+// Take doesn't read past n: the rest is still there for someone else
+func TestTakeLeavesTheRest(t *testing.T) {
+	in := values(5)
+	drain(Take(context.Background(), in, 2))
+	if got := drain(in); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("left after Take 2: %v, want [2 3 4]", got)
+	}
+}
+
+func TestStagesCompose(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	squares := MapCh(ctx, endless(ctx), func(n int) int { return n * n })
+	got := drain(Take(ctx, FilterCh(ctx, squares, isEven), 5))
+	if want := []int{4, 16, 36, 64, 100}; !slices.Equal(got, want) {
+		t.Errorf("first 5 even squares: %v, want %v", got, want)
+	}
+	cancel() // the stages before Take are blocked on a send until now
+	waitGoroutines(t, baseline)
+}
+
+// A consumer that walks away mid-stream and cancels: every stage returns, even the ones
+// blocked on a send nobody will receive
+func TestStagesCancel(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	merged := Merge(ctx, endless(ctx), endless(ctx), endless(ctx))
+	out := FilterCh(ctx, MapCh(ctx, merged, func(n int) int { return n + 1 }), isEven)
+	<-out
+	cancel()
+	for range out { // closes soon after cancel, whatever was already in flight
+	}
+	waitGoroutines(t, baseline)
+}
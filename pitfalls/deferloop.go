@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// A deferred call runs when the FUNCTION returns - not at the end of the loop iteration, or of the block
+// it's in. So `defer f.Close()` inside a loop keeps every file open until the loop and everything after it
+// is done: 10 files, 10 open at once; 100000 of them, and Open fails with "too many open files" long
+// before the end (the limit is often 1024 per process - ulimit -n). A defer in a loop also piles up:
+// each one is kept, with its arguments, until the function returns.
+//
+// The fix: give each iteration a function of its own to return from - a helper, or a func literal called
+// in place - so its defers run at the end of each iteration.
+
+// openFiles counts the files open right now, and the most open at once
+type openFiles struct {
+	mu         sync.Mutex
+	open, peak int
+}
+
+// trackedFile is an *os.File that tells openFiles when it's closed
+type trackedFile struct {
+	*os.File
+	counter *openFiles
+}
+
+func (c *openFiles) Open(path string) (*trackedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.open++
+	c.peak = max(c.peak, c.open)
+	c.mu.Unlock()
+	return &trackedFile{File: f, counter: c}, nil
+}
+
+func (f *trackedFile) Close() error {
+	f.counter.mu.Lock()
+	f.counter.open--
+	f.counter.mu.Unlock()
+	return f.File.Close()
+}
+
+// totalSizeDeferInLoop is the pitfall: every Close waits for the function to return
+func totalSizeDeferInLoop(files *openFiles, paths []string) (int64, error) {
+	var total int64
+	for _, p := range paths {
+		f, err := files.Open(p)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close() // runs when totalSizeDeferInLoop returns - after the whole loop
+		n, err := io.Copy(io.Discard, f)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// fileSize is the fix as a helper: its defer runs when it returns, once per file
+func fileSize(files *openFiles, path string) (int64, error) {
+	f, err := files.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(io.Discard, f)
+}
+
+func totalSize(files *openFiles, paths []string) (int64, error) {
+	var total int64
+	for _, p := range paths {
+		n, err := fileSize(files, p)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// totalSizeFuncLiteral is the same fix in place: a func literal per iteration, called straight away
+func totalSizeFuncLiteral(files *openFiles, paths []string) (int64, error) {
+	var total int64
+	for _, p := range paths {
+		err := func() error {
+			f, err := files.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close() // the end of this func - so the end of the iteration
+			n, err := io.Copy(io.Discard, f)
+			total += n
+			return err
+		}()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// (the checks are in deferloop_test.go)
+func deferInLoopExample() {
+	dir, err := os.MkdirTemp("", "notes-pitfalls-*")
+	if err != nil {
+		fmt.Println("temp dir:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	var paths []string
+	for i := range 20 {
+		p := filepath.Join(dir, fmt.Sprintf("part-%02d.txt", i))
+		os.WriteFile(p, []byte(fmt.Sprintf("part %d of the notes\n", i)), 0o644)
+		paths = append(paths, p)
+	}
+
+	for _, tc := range []struct {
+		name string
+		fn   func(*openFiles, []string) (int64, error)
+	}{
+		{"defer in the loop", totalSizeDeferInLoop},
+		{"a helper function", totalSize},
+		{"a func literal per iteration", totalSizeFuncLiteral},
+	} {
+		var files openFiles
+		n, err := tc.fn(&files, paths)
+		fmt.Printf("%-30s %d bytes, err %v | most files open at once: %2d | open after: %d\n", tc.name, n, err, files.peak, files.open)
+	}
+
+	// The same with a lock is worse than slow: the second iteration waits for an Unlock that's deferred to
+	// after the loop - a deadlock
+	deadlocked, unlocked := lockInLoop(2)
+	fmt.Println("defer Unlock in a loop would deadlock:", deadlocked, "| unlocked once the func returned:", unlocked)
+}
+
+// lockInLoop locks a mutex each iteration, deferring the Unlock: whether an iteration couldn't get the
+// lock, and whether it was free again once the func returned. TryLock shows the deadlock without hanging
+func lockInLoop(n int) (deadlocked, unlockedAfter bool) {
+	var mu sync.Mutex
+	func() {
+		for range n {
+			if !mu.TryLock() { // with mu.Lock() here, iteration 1 would block forever
+				deadlocked = true // iteration 0's deferred Unlock hasn't run
+				break
+			}
+			defer mu.Unlock()
+		}
+	}()
+	return deadlocked, mu.TryLock()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeParts writes n small files, returning their paths and total size
+func writeParts(t *testing.T, n int) ([]string, int64) {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	var total int64
+	for i := range n {
+		p := filepath.Join(dir, fmt.Sprintf("part-%02d.txt", i))
+		data := fmt.Sprintf("part %d of the notes\n", i)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+		total += int64(len(data))
+	}
+	return paths, total
+}
+
+func TestTotalSize(t *testing.T) {
+	paths, size := writeParts(t, 20)
+	tests := []struct {
+		name string
+		fn   func(*openFiles, []string) (int64, error)
+		peak int
+	}{
+		{"defer in the loop", totalSizeDeferInLoop, 20}, // every file open until the end
+		{"a helper function", totalSize, 1},
+		{"a func literal per iteration", totalSizeFuncLiteral, 1},
+	}
+	for _, tt := range tests {
+		var files openFiles
+		n, err := tt.fn(&files, paths)
+		if err != nil || n != size {
+			t.Errorf("%s: %d bytes, %v, want %d", tt.name, n, err, size)
+		}
+		if files.peak != tt.peak {
+			t.Errorf("%s: %d files open at once, want %d", tt.name, files.peak, tt.peak)
+		}
+		if files.open != 0 {
+			t.Errorf("%s: %d files still open after", tt.name, files.open)
+		}
+	}
+}
+
+// An Open failing partway: the files opened before it are closed, by all three
+func TestTotalSizeOpenError(t *testing.T) {
+	paths, _ := writeParts(t, 5)
+	paths = append(paths[:3:3], filepath.Join(t.TempDir(), "missing.txt"), paths[3])
+	for name, fn := range map[string]func(*openFiles, []string) (int64, error){
+		"defer in the loop": totalSizeDeferInLoop,
+		"a helper function": totalSize,
+		"a func literal":    totalSizeFuncLiteral,
+	} {
+		var files openFiles
+		if _, err := fn(&files, paths); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s: %v, want os.ErrNotExist", name, err)
+		}
+		if files.open != 0 {
+			t.Errorf("%s: %d files left open", name, files.open)
+		}
+	}
+}
+
+func TestLockInLoop(t *testing.T) {
+	if deadlocked, unlocked := lockInLoop(2); !deadlocked || !unlocked {
+		t.Errorf("lockInLoop(2) = %t, %t: want the second iteration stuck, and mu free after", deadlocked, unlocked)
+	}
+	// One iteration is fine: its Unlock runs at the return, with nothing waiting on it
+	if deadlocked, unlocked := lockInLoop(1); deadlocked || !unlocked {
+		t.Errorf("lockInLoop(1) = %t, %t, want false, true", deadlocked, unlocked)
+	}
+}
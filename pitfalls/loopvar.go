@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// The same loops as loopvar_go121.go, under Go 1.22+ semantics: each iteration of a for loop has its own
+// variable, a copy of the previous iteration's at the start of the next (so i++ still works). Closures and
+// goroutines made in different iterations see different variables - the bug can't happen, and `i := i`
+// is no longer needed. (go vet's loopclosure check reports the old bug, in files still on the old rules)
+
+func goroutinesSee(values []string) []string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var seen []string
+	release := make(chan struct{})
+	for _, v := range values { // a new v each iteration
+		wg.Go(func() {
+			<-release // as goroutinesSeeOld: read v once the loop is done
+			mu.Lock()
+			seen = append(seen, v)
+			mu.Unlock()
+		})
+	}
+	close(release)
+	wg.Wait()
+	return seen
+}
+
+func closures(n int) []int {
+	var fs []func() int
+	for i := 0; i < n; i++ {
+		fs = append(fs, func() int { return i })
+	}
+	var out []int
+	for _, f := range fs {
+		out = append(out, f())
+	}
+	return out
+}
+
+func pointers(values []int) []*int {
+	var ps []*int
+	for _, v := range values {
+		ps = append(ps, &v) // a different v, so a different address, each time
+	}
+	return ps
+}
+
+func deferred(n int) (args, closures []int) {
+	func() {
+		for i := 0; i < n; i++ {
+			defer func() { closures = append(closures, i) }()
+			defer func(v int) { args = append(args, v) }(i)
+		}
+	}()
+	return args, closures
+}
+
+// sharesLoopVar is the check that tells the semantics apart: whether closures made in different
+// iterations saw the same variable. Under the old rules they all return the final value
+func sharesLoopVar(got []int) bool {
+	return len(got) > 1 && slices.Max(got) == slices.Min(got)
+}
+
+// (the checks for these examples are in loopvar_test.go)
+
+func goroutineCaptureExample() {
+	values := []string{"basics", "methods", "generics", "concurrency"}
+	old, now := goroutinesSeeOld(values), goroutinesSee(values)
+	slices.Sort(old)
+	slices.Sort(now) // goroutines finish in any order
+	// Under the old rules each goroutine saw the last value
+	fmt.Println("Go 1.21 rules:", old)
+	fmt.Println("Go 1.22 rules:", now)
+}
+
+func closureCaptureExample() {
+	fmt.Println("closures, Go 1.21 rules:", closuresOld(3), "| with i := i:", closuresOldFixed(3), "| Go 1.22 rules:", closures(3))
+
+	// A pointer to the loop variable is the same bug without a closure: under the old rules every &v
+	// is one address, holding the last element
+	oldVals, oldAddrs := deref(pointersOld([]int{10, 20, 30}))
+	newVals, newAddrs := deref(pointers([]int{10, 20, 30}))
+	fmt.Printf("&v per element: Go 1.21 rules %v (%d address) | Go 1.22 rules %v (%d addresses)\n", oldVals, oldAddrs, newVals, newAddrs)
+	// (for i := range slice, then &slice[i], points at the elements themselves - right under both rules)
+}
+
+// deref is the values ps point at, and how many different addresses they are
+func deref(ps []*int) (vals []int, distinct int) {
+	seen := map[*int]bool{}
+	for _, p := range ps {
+		vals = append(vals, *p)
+		seen[p] = true
+	}
+	return vals, len(seen)
+}
+
+func deferArgsExample() {
+	// Deferred calls run last-in first-out, so both lists come out 2, 1, 0 - or don't
+	oldArgs, oldClosures := deferredOld(3)
+	args, closed := deferred(3)
+	fmt.Println("defer f(i), Go 1.21 rules:", oldArgs, "| defer func() { ...i... }():", oldClosures) // the closures all saw 3
+	fmt.Println("defer f(i), Go 1.22 rules:", args, "| defer func() { ...i... }():", closed)
+}
@@ -0,0 +1,90 @@
+//go:build go1.21
+
+package main
+
+import "sync"
+
+// This file is compiled with Go 1.21's loop semantics. A //go:build line naming a Go version sets the
+// language version of its file: go1.21 is true for every toolchain since (so the file is always built),
+// but the file is held to 1.21's rules - go.mod's go 1.25.0 applies to the other files. It's how code
+// written for the old semantics keeps them while a module upgrades, and how these examples can show them.
+//
+// Before Go 1.22, a for loop had ONE variable for the whole loop, updated in place each iteration.
+// A closure captures the variable, not its value - so every closure made in the loop saw the same one,
+// and by the time they ran it held its final value. The same loops in loopvar.go each get their own.
+
+// goroutinesSeeOld starts a goroutine per value and collects what each one saw. They wait to read v until
+// the loop is done - what goroutines usually do anyway, as starting one takes longer than an iteration.
+// Without the wait the old loop isn't even reliably wrong, and it's a data race: the loop writes v while
+// they read it. With it, every run comes out the same, and the race detector has nothing to report
+func goroutinesSeeOld(values []string) []string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var seen []string
+	release := make(chan struct{})
+	started := 0
+	for _, v := range values { // one v, reused
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-release
+			mu.Lock()
+			seen = append(seen, v) // reads v when the goroutine runs - after the loop is done
+			mu.Unlock()
+		}()
+		// go vet's loopclosure check reports a go or defer statement capturing the loop variable - but only
+		// when it's the last statement of the loop body, where it can be sure the closure outlives the
+		// iteration. This line moves it off the end, which is all it takes to get this bug past vet
+		started++
+	}
+	close(release)
+	wg.Wait()
+	return seen[:started]
+}
+
+// closuresOld collects a closure per iteration of a 3-clause loop, then calls them
+func closuresOld(n int) []int {
+	var fs []func() int
+	for i := 0; i < n; i++ {
+		fs = append(fs, func() int { return i })
+	}
+	var out []int
+	for _, f := range fs {
+		out = append(out, f()) // all n, the value that ended the loop
+	}
+	return out
+}
+
+// closuresOldFixed is the fix everyone used to write: a new variable per iteration, by hand
+func closuresOldFixed(n int) []int {
+	var fs []func() int
+	for i := 0; i < n; i++ {
+		i := i // shadows the loop's i with a copy - one per iteration
+		fs = append(fs, func() int { return i })
+	}
+	var out []int
+	for _, f := range fs {
+		out = append(out, f())
+	}
+	return out
+}
+
+// pointersOld keeps &v for each element - the other way to capture a loop variable
+func pointersOld(values []int) []*int {
+	var ps []*int
+	for _, v := range values {
+		ps = append(ps, &v) // the same address every time
+	}
+	return ps
+}
+
+// deferredOld records, in deferred calls, the loop variable - once as an argument, once through a closure
+func deferredOld(n int) (args, closures []int) {
+	func() {
+		for i := 0; i < n; i++ {
+			defer func() { closures = append(closures, i) }() // the closure reads i when it finally runs
+			defer func(v int) { args = append(args, v) }(i)   // arguments are evaluated when defer runs: a copy
+		}
+	}()
+	return args, closures
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// Each loop is run under both rules: the old file must show the bug, the new one mustn't. If loopvar_go121.go
+// lost its //go:build go1.21 line, it would get go.mod's semantics and the Old checks would fail
+
+func TestGoroutinesSeeOld(t *testing.T) {
+	values := []string{"basics", "methods", "generics", "concurrency"}
+	got := goroutinesSeeOld(values)
+	if want := []string{"concurrency", "concurrency", "concurrency", "concurrency"}; !slices.Equal(got, want) {
+		t.Errorf("old rules: goroutines saw %q, want the last value each time", got)
+	}
+}
+
+func TestGoroutinesSee(t *testing.T) {
+	values := []string{"basics", "methods", "generics", "concurrency"}
+	got := goroutinesSee(values)
+	slices.Sort(got) // goroutines finish in any order
+	if want := []string{"basics", "concurrency", "generics", "methods"}; !slices.Equal(got, want) {
+		t.Errorf("new rules: goroutines saw %q, want each value once", got)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	tests := []struct {
+		name   string
+		got    []int
+		want   []int
+		shared bool
+	}{
+		{"old rules", closuresOld(3), []int{3, 3, 3}, true},
+		{"old rules, i := i", closuresOldFixed(3), []int{0, 1, 2}, false},
+		{"new rules", closures(3), []int{0, 1, 2}, false},
+		{"new rules, one iteration", closures(1), []int{0}, false},
+		{"new rules, none", closures(0), nil, false},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.want) {
+			t.Errorf("%s: closures returned %v, want %v", tt.name, tt.got, tt.want)
+		}
+		if got := sharesLoopVar(tt.got); got != tt.shared {
+			t.Errorf("%s: sharesLoopVar(%v) = %t, want %t", tt.name, tt.got, got, tt.shared)
+		}
+	}
+}
+
+func TestPointers(t *testing.T) {
+	values := []int{10, 20, 30}
+	if vals, addrs := deref(pointersOld(values)); addrs != 1 || !slices.Equal(vals, []int{30, 30, 30}) {
+		t.Errorf("old rules: %v at %d addresses, want the last value at 1", vals, addrs)
+	}
+	if vals, addrs := deref(pointers(values)); addrs != 3 || !slices.Equal(vals, values) {
+		t.Errorf("new rules: %v at %d addresses, want %v at 3", vals, addrs, values)
+	}
+	// Neither points into the slice: v is a copy of each element
+	ps := pointers(values)
+	*ps[0] = 99
+	if values[0] != 10 {
+		t.Error("a pointer to v changed the slice")
+	}
+}
+
+func TestDeferred(t *testing.T) {
+	oldArgs, oldClosures := deferredOld(3)
+	args, closed := deferred(3)
+	// Arguments are evaluated at the defer statement, so they're right under both rules. LIFO: 2, 1, 0
+	if !slices.Equal(oldArgs, []int{2, 1, 0}) || !slices.Equal(args, []int{2, 1, 0}) {
+		t.Errorf("deferred args: old %v, new %v, want [2 1 0] both", oldArgs, args)
+	}
+	if !slices.Equal(oldClosures, []int{3, 3, 3}) {
+		t.Errorf("old rules: deferred closures saw %v, want [3 3 3]", oldClosures)
+	}
+	if !slices.Equal(closed, []int{2, 1, 0}) {
+		t.Errorf("new rules: deferred closures saw %v, want [2 1 0]", closed)
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+// === Ex. loop pitfalls: closures over the loop variable, and defer inside a loop ===
+
+// Each pitfall is shown twice - the loop as it behaves now, and the same loop under the old rules
+// (loopvar_go121.go) - with checks that tell the two apart.
+
+func main() {
+	goroutineCaptureExample()
+	// closureCaptureExample()
+	// deferArgsExample()
+	// deferInLoopExample()
+}
@@ -0,0 +1,3 @@
+module randpkg
+
+go 1.25.0
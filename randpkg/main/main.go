@@ -0,0 +1,133 @@
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math/rand/v2"
+	"randpkg"
+	"slices"
+	"time"
+)
+
+// === Ex. math/rand/v2: IntN, Shuffle, Perm, seeded and unseeded generators, benchmark workloads ===
+// (the seeded checks and the benchmarks are in randpkg_test.go)
+
+// (tour quiz -shuffle shuffles the quiz's questions and choices the same way - see tour/quiz/shuffle.go)
+
+func main() {
+	intNExample()
+	// shuffleExample()
+	// seededExample()
+	// workloadExample()
+	// cryptoRandExample()
+}
+
+func intNExample() {
+	// IntN(n) is in [0, n) - a die is IntN(6) + 1. N is the generic version, for any integer type, durations too
+	rolls := make([]int, 10)
+	for i := range rolls {
+		rolls[i] = rand.IntN(6) + 1
+	}
+	fmt.Println("ten dice:", rolls)
+	fmt.Println("a delay up to 100ms:", rand.N(100*time.Millisecond).Round(time.Millisecond), "| a float in [0, 1):", rand.Float64())
+
+	// Evenly spread: every face comes up about 1/6 of the time (v2's IntN has no modulo bias)
+	counts := make([]int, 6)
+	const n = 60_000
+	for range n {
+		counts[rand.IntN(6)]++
+	}
+	fmt.Println("60000 rolls:", counts, "- each about 10000")
+	// IntN(0) panics - there's no number in [0, 0)
+}
+
+func shuffleExample() {
+	questions := []string{"goroutines", "channels", "select", "mutex", "WaitGroup"}
+
+	// Shuffle works in place, through a swap func - so it shuffles anything indexable, even two slices at once
+	order := slices.Clone(questions)
+	rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	fmt.Println("shuffled:", order)
+
+	// Perm is a random order of the indexes 0..n-1 - for a shuffled view without moving anything
+	perm := rand.Perm(len(questions))
+	fmt.Print("through Perm:")
+	for _, i := range perm {
+		fmt.Print(" ", questions[i])
+	}
+	fmt.Println()
+
+	// A shuffle is a reordering: the same elements, each once
+	fmt.Println("sorted again:", slices.Sorted(slices.Values(order)))
+
+	// Sample: 2 different questions at random
+	fmt.Println("two at random:", randpkg.Sample(rand.New(rand.NewPCG(rand.Uint64(), 0)), questions, 2))
+}
+
+func seededExample() {
+	// The same seed, the same sequence - every run, on every machine
+	a, b := randpkg.New(42), randpkg.New(42)
+	seqA, seqB := make([]int, 5), make([]int, 5)
+	for i := range 5 {
+		seqA[i], seqB[i] = a.IntN(100), b.IntN(100)
+	}
+	fmt.Println("seed 42:", seqA, "| again:", seqB)
+
+	// So a "random" test is repeatable: shuffle with a seed, and the order is fixed
+	items := []string{"a", "b", "c", "d", "e"}
+	first := randpkg.Shuffled(randpkg.New(7), items)
+	second := randpkg.Shuffled(randpkg.New(7), items)
+	other := randpkg.Shuffled(randpkg.New(8), items)
+	fmt.Println("seed 7:", first, second, "| seed 8:", other, "| items:", items)
+
+	// The top-level functions can't be seeded: two runs of this line print different numbers
+	fmt.Println("unseeded (changes every run):", rand.IntN(1_000_000), rand.IntN(1_000_000))
+
+	// A test that must be random but debuggable picks a seed, logs it, and builds everything from it:
+	seed := rand.Uint64()
+	r := randpkg.New(seed)
+	got := r.IntN(1000)
+	fmt.Printf("with seed %d: %d | replayed: %d\n", seed, got, randpkg.New(seed).IntN(1000))
+}
+
+func workloadExample() {
+	// A benchmark's input should be random (no pattern the code could luck into) AND the same every run
+	// (so two runs measure the same work) - a seeded generator, made before the timed loop.
+	// BenchmarkLookups in randpkg_test.go times these two: go test -bench Lookups randpkg
+	const lookups, space = 10_000, 100_000
+	cache := make(map[string]int, space/10)
+	for i := range space / 10 {
+		cache[fmt.Sprintf("key-%d", i*10)] = i // a tenth of the keys are cached
+	}
+	hits := func(keys []string) int {
+		n := 0
+		for _, k := range keys {
+			if _, ok := cache[k]; ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	uniform := randpkg.Keys(randpkg.New(1), lookups, space)
+	skewed := randpkg.SkewedKeys(randpkg.New(1), lookups, space, 1.2)
+	fmt.Printf("uniform keys: %d hits in %d lookups, starting %v\n", hits(uniform), lookups, uniform[:3])
+	fmt.Printf("skewed keys:  %d hits in %d lookups, starting %v\n", hits(skewed), lookups, skewed[:3])
+	// The same number of lookups, but the skewed keys hit the cache far more often: what a benchmark of a
+	// cache measures depends on the workload's shape as much as on the code
+}
+
+func cryptoRandExample() {
+	// crypto/rand for secrets: Text is a random 26-character base32 string (128 bits) - a token
+	fmt.Println("token:", crand.Text())
+	key := make([]byte, 32)
+	crand.Read(key) // never fails on supported platforms (since Go 1.24 it crashes the program rather than return an error)
+	fmt.Printf("a 256-bit key: %x...\n", key[:8])
+
+	// math/rand/v2 can use crypto/rand as its source: rand.New(rand.NewChaCha8(seed)) with a seed from
+	// crypto/rand is unpredictable - as long as the seed stays secret. For the speed of each,
+	// BenchmarkRandom8Bytes in randpkg_test.go: crypto/rand is tens of times slower, and still well under a µs
+	fmt.Println("two more tokens:", crand.Text(), crand.Text())
+	// Use math/rand for shuffles, jitter, sampling, simulations; crypto/rand whenever guessing the value
+	// would let someone in (see cryptopkg's NewKey and NewUUID)
+}
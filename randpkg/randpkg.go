@@ -0,0 +1,61 @@
+package randpkg
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// math/rand/v2 (Go 1.22) is the pseudo-random package: fast, and NOT for secrets. Two ways to use it:
+// - the top-level functions (rand.IntN, rand.Shuffle, ...) use a generator seeded randomly when the
+//   program starts - a different sequence every run, and there's no way to seed it (v1's rand.Seed is gone)
+// - rand.New(source) with a seeded source (rand.NewPCG, rand.NewChaCha8) - the same sequence every run,
+//   for tests, benchmarks and simulations that must repeat. A *rand.Rand isn't safe for concurrent use;
+//   the top-level functions are
+//
+// For anything an attacker mustn't guess - tokens, keys, session IDs - use crypto/rand, which reads the
+// operating system's secure generator (see the cryptopkg module). Since Go 1.22 the top-level
+// generator is ChaCha8 and hard to predict, but the package makes no such promise, and a seeded one is
+// predictable by design.
+
+// New is a generator that gives the same sequence for the same seed
+func New(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// Shuffled is a shuffled copy of s - rand.Shuffle itself works in place, through a swap function
+func Shuffled[S ~[]E, E any](r *rand.Rand, s S) S {
+	out := slices.Clone(s)
+	r.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// Sample is n different elements of s, chosen at random - the first n of a random permutation of
+// the indexes. It panics if n > len(s)
+func Sample[S ~[]E, E any](r *rand.Rand, s S, n int) S {
+	out := make(S, n)
+	for i, j := range r.Perm(len(s))[:n] {
+		out[i] = s[j]
+	}
+	return out
+}
+
+// Keys is a benchmark workload: n lookups of keys "key-0" to "key-<space-1>", all equally likely
+func Keys(r *rand.Rand, n, space int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", r.IntN(space))
+	}
+	return keys
+}
+
+// SkewedKeys is Keys with a Zipf distribution: a few hot keys get most of the lookups, as in most real
+// caches. skew is Zipf's s, > 1 - higher is more skewed
+func SkewedKeys(r *rand.Rand, n, space int, skew float64) []string {
+	z := rand.NewZipf(r, skew, 1, uint64(space-1))
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", z.Uint64())
+	}
+	return keys
+}
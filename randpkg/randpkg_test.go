@@ -0,0 +1,218 @@
+package randpkg
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Every test builds its generator from a fixed seed: a failure replays the same way every run
+
+func TestNewRepeats(t *testing.T) {
+	a, b, c := New(42), New(42), New(43)
+	same, differs := true, false
+	for range 100 {
+		x, y, z := a.Uint64(), b.Uint64(), c.Uint64()
+		same = same && x == y
+		differs = differs || x != z
+	}
+	if !same {
+		t.Error("two generators with seed 42 gave different sequences")
+	}
+	if !differs {
+		t.Error("seeds 42 and 43 gave the same sequence")
+	}
+}
+
+func TestIntNRange(t *testing.T) {
+	r := New(1)
+	counts := make([]int, 6)
+	const n = 60_000
+	for range n {
+		v := r.IntN(6)
+		if v < 0 || v >= 6 {
+			t.Fatalf("IntN(6) = %d", v)
+		}
+		counts[v]++
+	}
+	// Evenly spread: every face within 10% of n/6 (for this seed; a fair die is well inside it)
+	for face, c := range counts {
+		if c < n/6*9/10 || c > n/6*11/10 {
+			t.Errorf("face %d came up %d times of %d", face+1, c, n)
+		}
+	}
+}
+
+func TestShuffled(t *testing.T) {
+	items := []string{"goroutines", "channels", "select", "mutex", "WaitGroup"}
+	orig := slices.Clone(items)
+	first := Shuffled(New(7), items)
+	if !slices.Equal(items, orig) {
+		t.Errorf("Shuffled changed its input: %v", items)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(first)), slices.Sorted(slices.Values(items))) {
+		t.Errorf("Shuffled(%v) = %v: not the same elements", items, first)
+	}
+	if again := Shuffled(New(7), items); !slices.Equal(first, again) {
+		t.Errorf("seed 7 shuffled %v, then %v", first, again)
+	}
+
+	// Some seed in a few gives another order
+	differs := false
+	for seed := range uint64(10) {
+		differs = differs || !slices.Equal(Shuffled(New(seed+8), items), first)
+	}
+	if !differs {
+		t.Error("ten seeds all shuffled the same way")
+	}
+
+	if got := Shuffled(New(1), []int(nil)); len(got) != 0 {
+		t.Errorf("Shuffled(nil) = %v", got)
+	}
+}
+
+// Every order of 3 elements comes up, about equally often
+func TestShuffledUniform(t *testing.T) {
+	r := New(3)
+	counts := map[string]int{}
+	const n = 60_000
+	for range n {
+		counts[fmt.Sprint(Shuffled(r, []int{1, 2, 3}))]++
+	}
+	if len(counts) != 6 {
+		t.Fatalf("%d different orders, want 6: %v", len(counts), counts)
+	}
+	for order, c := range counts {
+		if c < n/6*9/10 || c > n/6*11/10 {
+			t.Errorf("%s came up %d times of %d", order, c, n)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	for n := range len(items) + 1 {
+		got := Sample(New(uint64(n)), items, n)
+		if len(got) != n {
+			t.Fatalf("Sample(n=%d) has %d elements", n, len(got))
+		}
+		for i, v := range got {
+			if !slices.Contains(items, v) || slices.Contains(got[:i], v) {
+				t.Errorf("Sample(n=%d) = %v: not %d different elements of %v", n, got, n, items)
+				break
+			}
+		}
+	}
+	if a, b := Sample(New(5), items, 3), Sample(New(5), items, 3); !slices.Equal(a, b) {
+		t.Errorf("seed 5 sampled %v, then %v", a, b)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Sample of more elements than there are didn't panic")
+		}
+	}()
+	Sample(New(1), items, len(items)+1)
+}
+
+func keyIndex(t *testing.T, k string) int {
+	t.Helper()
+	i, err := strconv.Atoi(strings.TrimPrefix(k, "key-"))
+	if err != nil || !strings.HasPrefix(k, "key-") {
+		t.Fatalf("key %q", k)
+	}
+	return i
+}
+
+func TestKeys(t *testing.T) {
+	const n, space = 10_000, 100
+	keys := Keys(New(1), n, space)
+	if len(keys) != n {
+		t.Fatalf("%d keys, want %d", len(keys), n)
+	}
+	seen := map[int]bool{}
+	for _, k := range keys {
+		i := keyIndex(t, k)
+		if i < 0 || i >= space {
+			t.Fatalf("key %q outside [0, %d)", k, space)
+		}
+		seen[i] = true
+	}
+	if len(seen) != space {
+		t.Errorf("%d of %d keys came up in %d lookups", len(seen), space, n)
+	}
+	if again := Keys(New(1), n, space); !slices.Equal(keys, again) {
+		t.Error("the same seed gave another workload")
+	}
+}
+
+func TestSkewedKeys(t *testing.T) {
+	const n, space = 10_000, 1000
+	keys := SkewedKeys(New(1), n, space, 1.2)
+	if len(keys) != n {
+		t.Fatalf("%d keys, want %d", len(keys), n)
+	}
+	counts := make([]int, space)
+	for _, k := range keys {
+		i := keyIndex(t, k)
+		if i < 0 || i >= space {
+			t.Fatalf("key %q outside [0, %d)", k, space)
+		}
+		counts[i]++
+	}
+	// Skewed: key-0 is the hottest, and the 10 hottest keys get most of the lookups - a uniform
+	// workload would give them 1%
+	hot := 0
+	for _, c := range counts[:10] {
+		hot += c
+	}
+	if counts[0] != slices.Max(counts) || hot < n/2 {
+		t.Errorf("key-0: %d lookups, the 10 hottest: %d of %d - not skewed", counts[0], hot, n)
+	}
+	if again := SkewedKeys(New(1), n, space, 1.2); !slices.Equal(keys, again) {
+		t.Error("the same seed gave another workload")
+	}
+}
+
+// A map lookup workload, the same every run: uniform keys miss the cache nine times in ten, skewed
+// ones mostly hit its hot keys
+func BenchmarkLookups(b *testing.B) {
+	const lookups, space = 10_000, 100_000
+	cache := make(map[string]int, space/10)
+	for i := range space / 10 {
+		cache[fmt.Sprintf("key-%d", i*10)] = i
+	}
+	for _, w := range []struct {
+		name string
+		keys []string
+	}{
+		{"uniform", Keys(New(1), lookups, space)},
+		{"skewed", SkewedKeys(New(1), lookups, space, 1.2)},
+	} {
+		b.Run(w.name, func(b *testing.B) {
+			for b.Loop() {
+				for _, k := range w.keys {
+					_ = cache[k]
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRandom8Bytes(b *testing.B) {
+	b.Run("math/rand", func(b *testing.B) {
+		for b.Loop() {
+			rand.Uint64()
+		}
+	})
+	buf := make([]byte, 8)
+	b.Run("crypto/rand", func(b *testing.B) {
+		for b.Loop() {
+			crand.Read(buf)
+		}
+	})
+}
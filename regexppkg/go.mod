@@ -0,0 +1,3 @@
+module regexppkg
+
+go 1.25.0
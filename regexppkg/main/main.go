@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"regexppkg"
+	"strings"
+)
+
+// === Ex. regexp: compiled once, submatches, named groups, replacing ===
+
+// The user ids from the basics notes
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+var uploadLog = `2026-03-07T14:05:09Z INFO upload user=` + userId1 + ` file=notes.md bytes=2048
+2026-03-07T14:05:11Z WARN retry user=` + strings.ToUpper(userId2) + ` file=photo.png attempt=2
+2026-03-07T14:05:12Z INFO upload user=` + userId2 + ` file=photo.png bytes=734003
+not a log line
+2026-03-07T14:06:00Z ERROR upload user=` + userId1 + ` file=big.iso reason=too_large`
+
+func main() {
+	matchExample()
+	// namedGroupsExample()
+	// replaceExample()
+	// uuidExample()
+}
+
+// A package level pattern: compiled once, when the package is initialized - not on every call
+var version = regexp.MustCompile(`v(\d+)\.(\d+)(?:\.(\d+))?`)
+
+func matchExample() {
+	fmt.Println("MatchString:", version.MatchString("go v1.25"), version.MatchString("version one"))
+
+	// Find returns the leftmost match; FindAll every one (n is how many at most, -1 for all)
+	text := "upgraded v1.22.4 to v1.25, then v2.0.1"
+	fmt.Printf("FindString: %q | FindAllString: %q\n", version.FindString(text), version.FindAllString(text, -1))
+
+	// Submatch: [whole match, group 1, group 2, ...]. A group that didn't take part is ""
+	for _, m := range version.FindAllStringSubmatch(text, -1) {
+		fmt.Printf("  %-8q major %s minor %s patch %q\n", m[0], m[1], m[2], m[3])
+	}
+	// Index: the positions instead of the strings - pairs of [start, end) into text, -1 for a group that didn't match
+	fmt.Println("FindStringSubmatchIndex:", version.FindStringSubmatchIndex("to v1.25"))
+
+	// regexp.Compile for a pattern from input - a bad one is an error, not a panic
+	_, err := regexp.Compile(`v(\d+`)
+	fmt.Println("Compile(`v(\\d+`):", err)
+	// RE2 has no backreferences: a pattern that needs them doesn't compile
+	_, err = regexp.Compile(`(\w+) \1`)
+	fmt.Println("a backreference:", err)
+
+	// Leftmost-first, like Perl: the first alternative that matches wins, not the longest
+	fmt.Printf("`go|golang` on \"golang\": %q | Longest(): ", regexp.MustCompile(`go|golang`).FindString("golang"))
+	longest := regexp.MustCompile(`go|golang`)
+	longest.Longest()
+	fmt.Printf("%q\n", longest.FindString("golang"))
+
+	// QuoteMeta: matching a string literally, when it has . or * or ( in it
+	ext := regexp.MustCompile(regexp.QuoteMeta("notes.md") + `$`)
+	fmt.Println("notes.md matches notes.md:", ext.MatchString("notes.md"), "| notesXmd:", ext.MatchString("notesXmd"))
+}
+
+func namedGroupsExample() {
+	entries, bad := regexppkg.ParseLog(uploadLog)
+	for _, e := range entries {
+		fmt.Printf("%s %-5s %-6s %v\n", e.Time, e.Level, e.Msg, e.Fields)
+	}
+	fmt.Printf("didn't fit: %q\n", bad)
+
+	// SubexpIndex finds a group by name, for a pattern used directly
+	date := regexp.MustCompile(`(?P<year>\d{4})-(?P<month>\d{2})-(?P<day>\d{2})`)
+	m := date.FindStringSubmatch("uploaded on 2026-03-07")
+	fmt.Println("year:", m[date.SubexpIndex("year")], "month:", m[date.SubexpIndex("month")], "| no such group:", date.SubexpIndex("hour"))
+
+	// Expand fills a template from the named groups - $name or ${name}
+	var out []byte
+	for _, sm := range date.FindAllStringSubmatchIndex("2026-03-07 and 2026-12-31", -1) {
+		out = date.ExpandString(out, "${day}/${month}/$year ", "2026-03-07 and 2026-12-31", sm)
+	}
+	fmt.Printf("Expand: %q\n", out)
+}
+
+func replaceExample() {
+	// ReplaceAllString: $1, ${name} in the replacement refer to the groups.
+	// ${1}x, not $1x - "$1x" is read as a group named "1x"
+	fmt.Println(version.ReplaceAllString("on v1.22.4", "version ${1}.${2}"))
+	fmt.Println(version.ReplaceAllString("on v1.22.4", "$1x"), "<- $1x is no group, so empty")
+	// ReplaceAllLiteralString: no $ expansion
+	fmt.Println(version.ReplaceAllLiteralString("on v1.22.4", "$1"))
+
+	// ReplaceAllStringFunc: the replacement is computed from each match
+	bump := version.ReplaceAllStringFunc("upgraded v1.22.4 to v1.25", func(v string) string {
+		m := version.FindStringSubmatch(v) // the func gets the match only, not its groups
+		var major, minor int
+		fmt.Sscan(m[1], &major)
+		fmt.Sscan(m[2], &minor)
+		return fmt.Sprintf("v%d.%d", major, minor+1)
+	})
+	fmt.Println("minor versions bumped:", bump)
+
+	redacted := regexppkg.RedactUUIDs(uploadLog)
+	fmt.Println(strings.SplitN(redacted, "\n", 2)[0])
+	fmt.Println("UUIDs left after redacting:", len(regexppkg.FindUUIDs(redacted)))
+}
+
+func uuidExample() {
+	ids := regexppkg.FindUUIDs(uploadLog)
+	fmt.Println("UUIDs in the log:", ids)
+	// userId1 and userId2, each once - the upper case one lowercased.
+	// regexppkg_test.go has the table of near misses (too short, glued to a word, wrong separators)
+}
@@ -0,0 +1,74 @@
+package regexppkg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Go's regexp is RE2: matching takes time linear in the input, whatever the pattern - no catastrophic
+// backtracking. The price is no backreferences (\1) and no lookaround ((?=...)).
+//
+// Compiling is the slow part, so patterns are compiled once, into package variables:
+// - regexp.MustCompile panics on a bad pattern - right for a constant pattern, where a typo should
+//   stop the program at startup (every test run catches it)
+// - regexp.Compile returns the error - for patterns that come from input
+// A *Regexp is safe for concurrent use.
+// Raw strings (`...`) keep the backslashes as they are: `\d` rather than "\\d".
+
+// UUIDPattern matches a UUID in its usual 8-4-4-4-12 hex form, in either case
+var UUIDPattern = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+
+// FindUUIDs returns the UUIDs in text, lowercased, each once, in the order they first appear
+func FindUUIDs(text string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range UUIDPattern.FindAllString(text, -1) {
+		id = strings.ToLower(id)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// logLine is one line of the upload log: "2026-03-07T14:05:09Z INFO upload user=<uuid> file=<name> bytes=<n>"
+var logLine = regexp.MustCompile(`^(?P<time>\S+) (?P<level>[A-Z]+) (?P<msg>\w+)(?P<fields>(?: \w+=\S+)*)$`)
+
+var field = regexp.MustCompile(`(\w+)=(\S+)`)
+
+// Entry is a parsed log line
+type Entry struct {
+	Time, Level, Msg string
+	Fields           map[string]string
+}
+
+// ParseLog parses each line of the log, by the named groups of logLine. Lines that don't fit are returned apart
+func ParseLog(log string) (entries []Entry, bad []string) {
+	names := logLine.SubexpNames() // names[i] is group i's name ("" for group 0, the whole match)
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		m := logLine.FindStringSubmatch(line)
+		if m == nil {
+			bad = append(bad, line)
+			continue
+		}
+		groups := map[string]string{}
+		for i, name := range names {
+			if name != "" {
+				groups[name] = m[i]
+			}
+		}
+		e := Entry{Time: groups["time"], Level: groups["level"], Msg: groups["msg"], Fields: map[string]string{}}
+		for _, f := range field.FindAllStringSubmatch(groups["fields"], -1) {
+			e.Fields[f[1]] = f[2]
+		}
+		entries = append(entries, e)
+	}
+	return entries, bad
+}
+
+// RedactUUIDs replaces every UUID with its first 8 characters and "-…", so a log can be shared
+// while the ids stay tellable apart
+func RedactUUIDs(text string) string {
+	return UUIDPattern.ReplaceAllStringFunc(text, func(id string) string { return strings.ToLower(id[:8]) + "-…" })
+}
@@ -0,0 +1,101 @@
+package regexppkg
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+var uploadLog = `2026-03-07T14:05:09Z INFO upload user=` + userId1 + ` file=notes.md bytes=2048
+2026-03-07T14:05:11Z WARN retry user=` + strings.ToUpper(userId2) + ` file=photo.png attempt=2
+2026-03-07T14:05:12Z INFO upload user=` + userId2 + ` file=photo.png bytes=734003
+not a log line
+2026-03-07T14:06:00Z ERROR upload user=` + userId1 + ` file=big.iso reason=too_large`
+
+func TestFindUUIDs(t *testing.T) {
+	for _, c := range []struct {
+		text string
+		want []string
+	}{
+		{uploadLog, []string{userId1, userId2}}, // each once, the upper case one lowercased
+		{"user " + userId1, []string{userId1}},
+		{"no ids here", nil},
+		{"", nil},
+		{"(" + userId1 + "),\"" + userId2 + "\"", []string{userId1, userId2}}, // punctuation around them
+		{userId1 + "0", nil},                          // 13 hex digits at the end: not a UUID, \b stops a partial match
+		{"x" + userId1, nil},                          // glued to a word
+		{"1d02455e-f24c-4c26-90d2-f1073c68631", nil},  // one short
+		{"1d02455e_f24c_4c26_90d2_f1073c686314", nil}, // wrong separators
+		{"1d02455g-f24c-4c26-90d2-f1073c686314", nil}, // not hex
+		{"1D02455E-F24C-4C26-90D2-F1073C686314", []string{userId1}},
+		{userId2 + " " + userId2, []string{userId2}},
+	} {
+		if got := FindUUIDs(c.text); !slices.Equal(got, c.want) {
+			t.Errorf("FindUUIDs(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	entries, bad := ParseLog(uploadLog)
+	if !slices.Equal(bad, []string{"not a log line"}) {
+		t.Errorf("bad lines %q, want just \"not a log line\"", bad)
+	}
+	want := []Entry{
+		{"2026-03-07T14:05:09Z", "INFO", "upload", map[string]string{"user": userId1, "file": "notes.md", "bytes": "2048"}},
+		{"2026-03-07T14:05:11Z", "WARN", "retry", map[string]string{"user": strings.ToUpper(userId2), "file": "photo.png", "attempt": "2"}},
+		{"2026-03-07T14:05:12Z", "INFO", "upload", map[string]string{"user": userId2, "file": "photo.png", "bytes": "734003"}},
+		{"2026-03-07T14:06:00Z", "ERROR", "upload", map[string]string{"user": userId1, "file": "big.iso", "reason": "too_large"}},
+	}
+	if !slices.EqualFunc(entries, want, func(a, b Entry) bool {
+		return a.Time == b.Time && a.Level == b.Level && a.Msg == b.Msg && maps.Equal(a.Fields, b.Fields)
+	}) {
+		t.Errorf("entries:\n%v\nwant\n%v", entries, want)
+	}
+}
+
+func TestParseLogLines(t *testing.T) {
+	for _, c := range []struct {
+		line string
+		ok   bool
+	}{
+		{"2026-03-07T14:05:09Z INFO start", true}, // no fields
+		{"t INFO upload a=1 b=2", true},
+		{"t info upload", false},      // the level is upper case
+		{"t INFO upload a=", false},   // a field with no value
+		{"t INFO upload  a=1", false}, // two spaces
+		{"t INFO", false},
+	} {
+		entries, bad := ParseLog(c.line)
+		if ok := len(entries) == 1 && len(bad) == 0; ok != c.ok {
+			t.Errorf("ParseLog(%q): parsed %t, want %t", c.line, ok, c.ok)
+		}
+	}
+	if entries, _ := ParseLog("t INFO start"); len(entries) != 1 || entries[0].Fields == nil || len(entries[0].Fields) != 0 {
+		t.Errorf("a line with no fields: %v, want an empty Fields map", entries)
+	}
+}
+
+func TestRedactUUIDs(t *testing.T) {
+	redacted := RedactUUIDs(uploadLog)
+	if ids := FindUUIDs(redacted); len(ids) != 0 {
+		t.Errorf("UUIDs left after redacting: %q", ids)
+	}
+	first, _, _ := strings.Cut(redacted, "\n")
+	if want := "2026-03-07T14:05:09Z INFO upload user=1d02455e-… file=notes.md bytes=2048"; first != want {
+		t.Errorf("first line %q, want %q", first, want)
+	}
+	if !strings.Contains(redacted, "user=96aeb270-… file=photo.png attempt=2") {
+		t.Errorf("the upper case UUID wasn't redacted to lower case:\n%s", redacted)
+	}
+	if strings.Count(redacted, "\n") != strings.Count(uploadLog, "\n") || !strings.Contains(redacted, "\nnot a log line\n") {
+		t.Error("redacting changed more than the UUIDs")
+	}
+	if got := RedactUUIDs("nothing to hide"); got != "nothing to hide" {
+		t.Errorf("no UUIDs: %q", got)
+	}
+}
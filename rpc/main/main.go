@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	netrpc "net/rpc"
+	"rpc"
+	"strings"
+	"sync"
+)
+
+// === Ex. net/rpc: SafeCounter as a remote CounterService, over an in-memory listener and TCP ===
+// (the checks are in rpc_test.go)
+
+func main() {
+	counterServiceExample()
+	// jsonCodecExample()
+	// rpcErrorsExample()
+	// tcpRPCExample()
+}
+
+// countVisits is written against the Counter interface: it works the same on a local SafeCounter
+// and on a Client talking to one in another process
+func countVisits(c rpc.Counter, pages []string) (int, error) {
+	for _, p := range pages {
+		if _, err := c.Inc(p); err != nil {
+			return 0, err
+		}
+	}
+	return c.Get(pages[0])
+}
+
+func counterServiceExample() {
+	pages := []string{"/home", "/about", "/home"}
+
+	// locally
+	local, _ := countVisits(rpc.NewSafeCounter(), pages)
+
+	// remotely: a server with its own SafeCounter, on an in-memory listener
+	counter := rpc.NewSafeCounter()
+	srv, err := rpc.NewServer(counter)
+	if err != nil {
+		fmt.Println("register:", err)
+		return
+	}
+	lis := rpc.NewPipeListener()
+	defer lis.Close()
+	go rpc.Serve(srv, lis)
+
+	conn, _ := lis.Dial()
+	client := rpc.NewClient(conn)
+	defer client.Close()
+	remote, err := countVisits(client, pages)
+	fmt.Println("/home visits - local:", local, "| remote:", remote, err)
+
+	// The server calls each request in its own goroutine: 10 clients incrementing at once
+	// is SafeCounter's mutex at work on the server side
+	var wg sync.WaitGroup
+	for range 10 {
+		conn, _ := lis.Dial()
+		c := rpc.NewClient(conn)
+		wg.Go(func() {
+			defer c.Close()
+			for range 10 {
+				c.Inc("hits")
+			}
+		})
+	}
+	wg.Wait()
+	hits, _ := client.Get("hits")
+	direct, _ := counter.Get("hits")
+	fmt.Println("10 clients x 10 Incs:", hits, "| the server's own counter:", direct)
+
+	// Calls can overlap on one connection: Go returns at once, Done says when the reply came
+	calls := make([]*netrpc.Call, 5)
+	for i := range calls {
+		calls[i] = client.IncAsync("async")
+	}
+	counts := make([]int, len(calls))
+	for i, call := range calls {
+		<-call.Done
+		counts[i] = *call.Reply.(*int)
+	}
+	fmt.Println("5 async Incs, each its own count:", counts)
+}
+
+func jsonCodecExample() {
+	srv, _ := rpc.NewServer(rpc.NewSafeCounter())
+	// JSON-RPC on the server side - a raw conn shows what goes over the wire
+	server, raw := net.Pipe()
+	go rpc.ServeJSON(srv, server)
+	defer raw.Close()
+
+	request := `{"method":"Counter.Inc","params":[{"Key":"gopher"}],"id":1}` + "\n"
+	raw.Write([]byte(request))
+	buf := make([]byte, 256)
+	n, _ := raw.Read(buf)
+	fmt.Print("-> ", request, "<- ", string(buf[:n]))
+	// (JSON-RPC 1.0: params is an array of one, and there's no "jsonrpc":"2.0" - the jsonrpc module has 2.0)
+
+	// The same server with the JSON client: one Counter, reached through two codecs
+	server2, client2 := net.Pipe()
+	go rpc.ServeJSON(srv, server2)
+	c := rpc.NewJSONClient(client2)
+	defer c.Close()
+	got, err := c.Inc("gopher")
+	fmt.Println("the JSON client, after the raw call's Inc:", got, err)
+	_, err = c.Get("")
+	fmt.Println("an error over JSON:", err)
+}
+
+func rpcErrorsExample() {
+	srv, _ := rpc.NewServer(rpc.NewSafeCounter())
+	lis := rpc.NewPipeListener()
+	defer lis.Close()
+	go rpc.Serve(srv, lis)
+	conn, _ := lis.Dial()
+	client := rpc.NewClient(conn)
+
+	// The method's error crosses as text: an rpc.ServerError, which isn't ErrEmptyKey any more
+	_, err := client.Inc("")
+	var serverErr netrpc.ServerError
+	fmt.Printf("error: %v (%T)\n", err, err)
+	fmt.Println("a ServerError:", errors.As(err, &serverErr), "| errors.Is(err, ErrEmptyKey):", errors.Is(err, rpc.ErrEmptyKey))
+	// (a client that needs to tell errors apart compares the text, or the reply carries a code - as JSON-RPC's errors do)
+
+	// A method that doesn't exist is an error from the server too, and the connection stays usable
+	// (netrpc.Client's Call takes any method name - rpc.Client only has the Counter's)
+	conn2, _ := lis.Dial()
+	raw := netrpc.NewClient(conn2)
+	defer raw.Close()
+	err = raw.Call("Counter.Reset", rpc.KeyArgs{Key: "a"}, new(int))
+	fmt.Println("unknown method:", err)
+	var n int
+	err = raw.Call("Counter.Inc", rpc.KeyArgs{Key: "a"}, &n)
+	fmt.Println("still works after it:", n, err)
+
+	// After Close, every call fails with ErrShutdown
+	client.Close()
+	_, err = client.Get("a")
+	fmt.Println("after Close:", err)
+
+	// Registering checks the methods' shape: a type with none that fit is an error, at startup (net/rpc logs it too)
+	other := netrpc.NewServer()
+	err = other.Register(strings.NewReader(""))
+	fmt.Println("registering a type without RPC methods:", err)
+}
+
+// (the tcp module's Server handles connections the same way - one goroutine per connection)
+func tcpRPCExample() {
+	srv, _ := rpc.NewServer(rpc.NewSafeCounter())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println("listen:", err)
+		return
+	}
+	done := make(chan error)
+	go func() { done <- rpc.Serve(srv, lis) }()
+
+	// netrpc.Dial is net.Dial plus NewClient - the Client here wraps the connection the same way
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		fmt.Println("dial:", err)
+		return
+	}
+	client := rpc.NewClient(conn)
+	for range 3 {
+		client.Inc("tcp")
+	}
+	n, err := client.Get("tcp")
+	fmt.Println("over TCP:", n, err, "| from", conn.LocalAddr().Network())
+	client.Close()
+	lis.Close()
+	fmt.Println("Serve, once the listener closes:", <-done)
+}
@@ -0,0 +1,205 @@
+package rpc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+)
+
+// net/rpc calls methods of a Go value in another process as if they were local. A type's methods are
+// exported for RPC when they look like
+//
+//	func (t *T) Method(args A, reply *R) error
+//
+// and the server is registered under a name ("Counter") - clients call "Counter.Inc". The default codec is
+// gob (Go only, compact); net/rpc/jsonrpc is a JSON-RPC 1.0 codec for the same server, so other languages
+// can call it. The jsonrpc module is the other way round: a JSON-RPC 2.0 implementation from scratch.
+//
+// net/rpc is frozen (no new features; the docs point at gRPC and others), but it's all in the standard
+// library and shows the shape of every RPC system: a service interface, a server adapting a value to it,
+// and a client stub that turns method calls into messages.
+
+// ErrEmptyKey is CounterService's error for a call with no key. Over RPC it arrives as an rpc.ServerError -
+// only its text crosses the process boundary, so errors.Is can't find it on the client side
+var ErrEmptyKey = errors.New("empty counter key")
+
+// Counter is what both sides agree on. SafeCounter is the local implementation, Client the remote one -
+// code written against Counter doesn't know which it has
+type Counter interface {
+	Inc(key string) (int, error)
+	Get(key string) (int, error)
+}
+
+var (
+	_ Counter = (*SafeCounter)(nil)
+	_ Counter = (*Client)(nil)
+)
+
+// SafeCounter is the concurrency notes' SafeCounter: a map behind an RWMutex
+type SafeCounter struct {
+	mu sync.RWMutex
+	v  map[string]int
+}
+
+func NewSafeCounter() *SafeCounter { return &SafeCounter{v: make(map[string]int)} }
+
+// Inc adds one to key's count, and returns the new count
+func (c *SafeCounter) Inc(key string) (int, error) {
+	if key == "" {
+		return 0, ErrEmptyKey
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.v[key]++
+	return c.v[key], nil
+}
+
+// Get is key's count
+func (c *SafeCounter) Get(key string) (int, error) {
+	if key == "" {
+		return 0, ErrEmptyKey
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.v[key], nil
+}
+
+// KeyArgs are the arguments of CounterService's methods. Args go through gob or JSON, so they're a type
+// with exported fields - a plain string would do too, but a struct can grow fields without breaking calls
+type KeyArgs struct {
+	Key string
+}
+
+// CounterService adapts a Counter to net/rpc's method shape. The server runs each call in its own
+// goroutine, so the Counter must be safe for concurrent use - SafeCounter is
+type CounterService struct {
+	counter Counter
+}
+
+func (s *CounterService) Inc(args KeyArgs, reply *int) error {
+	n, err := s.counter.Inc(args.Key)
+	*reply = n
+	return err
+}
+
+func (s *CounterService) Get(args KeyArgs, reply *int) error {
+	n, err := s.counter.Get(args.Key)
+	*reply = n
+	return err
+}
+
+// ServiceName is what CounterService is registered as
+const ServiceName = "Counter"
+
+// NewServer is an RPC server with c registered as ServiceName. It's a server of its own, not
+// rpc.DefaultServer, so two of them in one process don't clash
+func NewServer(c Counter) (*rpc.Server, error) {
+	s := rpc.NewServer()
+	if err := s.RegisterName(ServiceName, &CounterService{counter: c}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Serve accepts connections on l and serves s on each, in its own goroutine, until l is closed.
+// (s.Accept does the same, but logs the listener closing as an error)
+func Serve(s *rpc.Server, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeJSON serves s on conn with the JSON-RPC codec, until conn is closed. s.ServeConn is the gob version
+func ServeJSON(s *rpc.Server, conn io.ReadWriteCloser) {
+	s.ServeCodec(jsonrpc.NewServerCodec(conn))
+}
+
+// Client is a Counter on the other end of a connection
+type Client struct {
+	c *rpc.Client
+}
+
+// NewClient is a gob client on conn
+func NewClient(conn io.ReadWriteCloser) *Client { return &Client{rpc.NewClient(conn)} }
+
+// NewJSONClient is a JSON-RPC client on conn, for a server using ServeJSON
+func NewJSONClient(conn io.ReadWriteCloser) *Client { return &Client{jsonrpc.NewClient(conn)} }
+
+func (c *Client) Inc(key string) (int, error) {
+	var n int
+	err := c.c.Call(ServiceName+".Inc", KeyArgs{key}, &n)
+	return n, err
+}
+
+func (c *Client) Get(key string) (int, error) {
+	var n int
+	err := c.c.Call(ServiceName+".Get", KeyArgs{key}, &n)
+	return n, err
+}
+
+// IncAsync starts an Inc without waiting for it: the *rpc.Call's Done channel receives it when the reply
+// arrives, with Reply (an *int) and Error set. Several can be in flight on one connection
+func (c *Client) IncAsync(key string) *rpc.Call {
+	return c.c.Go(ServiceName+".Inc", KeyArgs{key}, new(int), nil)
+}
+
+// Close closes the connection. Calls after it, and calls in flight, fail with rpc.ErrShutdown
+func (c *Client) Close() error { return c.c.Close() }
+
+// PipeListener is a net.Listener in memory: each Dial is a net.Pipe, the server end of which Accept returns.
+// Examples and tests get a real client and server - codecs, goroutines, closing - without a port
+type PipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewPipeListener() *PipeListener {
+	return &PipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+var errListenerClosed = errors.New("pipe listener closed")
+
+// Dial connects to the listener, waiting for an Accept
+func (l *PipeListener) Dial() (net.Conn, error) {
+	client, server := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		client.Close()
+		server.Close()
+		return nil, errListenerClosed
+	}
+}
+
+func (l *PipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed // what Serve loops check for, as with a TCP listener
+	}
+}
+
+func (l *PipeListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *PipeListener) Addr() net.Addr { return pipeAddr{} }
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
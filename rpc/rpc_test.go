@@ -0,0 +1,348 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/rpc"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// start serves a fresh SafeCounter on an in-memory listener until the end of the test
+func start(t *testing.T) (*PipeListener, *SafeCounter) {
+	t.Helper()
+	counter := NewSafeCounter()
+	srv, err := NewServer(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := NewPipeListener()
+	done := make(chan error, 1)
+	go func() { done <- Serve(srv, lis) }()
+	t.Cleanup(func() {
+		lis.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve after Close = %v, want nil", err)
+		}
+	})
+	return lis, counter
+}
+
+func dial(t *testing.T, lis *PipeListener) *Client {
+	t.Helper()
+	conn, err := lis.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(conn)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// waitGoroutines waits up to a second for the number of goroutines to be back to want
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	for range 100 {
+		if runtime.NumGoroutine() <= want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("%d goroutines, want %d", runtime.NumGoroutine(), want)
+}
+
+// countVisits is written against Counter, and runs on either implementation
+func countVisits(c Counter, pages []string) (int, error) {
+	for _, p := range pages {
+		if _, err := c.Inc(p); err != nil {
+			return 0, err
+		}
+	}
+	return c.Get(pages[0])
+}
+
+func TestSafeCounter(t *testing.T) {
+	c := NewSafeCounter()
+	for want := 1; want <= 3; want++ {
+		if n, err := c.Inc("a"); err != nil || n != want {
+			t.Errorf("Inc = %d, %v, want %d", n, err, want)
+		}
+	}
+	if n, err := c.Get("a"); err != nil || n != 3 {
+		t.Errorf("Get = %d, %v, want 3", n, err)
+	}
+	if n, err := c.Get("never"); err != nil || n != 0 {
+		t.Errorf("Get of an unused key = %d, %v, want 0", n, err)
+	}
+	if _, err := c.Inc(""); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Inc(\"\") = %v, want ErrEmptyKey", err)
+	}
+	if _, err := c.Get(""); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Get(\"\") = %v, want ErrEmptyKey", err)
+	}
+}
+
+func TestLocalAndRemoteAgree(t *testing.T) {
+	lis, counter := start(t)
+	pages := []string{"/home", "/about", "/home"}
+	local, err := countVisits(NewSafeCounter(), pages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := countVisits(dial(t, lis), pages)
+	if err != nil || remote != local {
+		t.Errorf("remote /home visits = %d, %v, want %d as locally", remote, err, local)
+	}
+	if n, _ := counter.Get("/about"); n != 1 {
+		t.Errorf("the server's counter has /about %d, want 1", n)
+	}
+}
+
+func TestConcurrentClients(t *testing.T) {
+	lis, counter := start(t)
+	var wg sync.WaitGroup
+	for range 10 {
+		c := dial(t, lis)
+		wg.Go(func() {
+			for range 10 {
+				if _, err := c.Inc("hits"); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if n, err := dial(t, lis).Get("hits"); err != nil || n != 100 {
+		t.Errorf("Get after 10 clients x 10 Incs = %d, %v, want 100", n, err)
+	}
+	if n, _ := counter.Get("hits"); n != 100 {
+		t.Errorf("the server's counter has %d, want 100", n)
+	}
+}
+
+func TestIncAsync(t *testing.T) {
+	lis, _ := start(t)
+	c := dial(t, lis)
+	calls := make([]*rpc.Call, 5)
+	for i := range calls {
+		calls[i] = c.IncAsync("async")
+	}
+	seen := map[int]bool{}
+	for _, call := range calls {
+		<-call.Done
+		if call.Error != nil {
+			t.Fatal(call.Error)
+		}
+		seen[*call.Reply.(*int)] = true
+	}
+	for want := 1; want <= 5; want++ {
+		if !seen[want] {
+			t.Errorf("no call got count %d: %v", want, seen)
+		}
+	}
+}
+
+func TestServerError(t *testing.T) {
+	lis, _ := start(t)
+	c := dial(t, lis)
+	_, err := c.Inc("")
+	var serverErr rpc.ServerError
+	if !errors.As(err, &serverErr) || err.Error() != ErrEmptyKey.Error() {
+		t.Errorf("Inc(\"\") = %v (%T), want an rpc.ServerError with ErrEmptyKey's text", err, err)
+	}
+	// Only the text crosses: the sentinel is gone
+	if errors.Is(err, ErrEmptyKey) {
+		t.Error("errors.Is found ErrEmptyKey on the client side")
+	}
+	// The connection is still good after an error
+	if n, err := c.Inc("a"); err != nil || n != 1 {
+		t.Errorf("Inc after an error = %d, %v", n, err)
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	lis, _ := start(t)
+	conn, err := lis.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := rpc.NewClient(conn)
+	defer raw.Close()
+	if err := raw.Call("Counter.Reset", KeyArgs{Key: "a"}, new(int)); err == nil {
+		t.Error("Counter.Reset succeeded")
+	}
+	if err := raw.Call("Nope.Inc", KeyArgs{Key: "a"}, new(int)); err == nil {
+		t.Error("Nope.Inc succeeded")
+	}
+	var n int
+	if err := raw.Call("Counter.Inc", KeyArgs{Key: "a"}, &n); err != nil || n != 1 {
+		t.Errorf("Counter.Inc after unknown methods = %d, %v", n, err)
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	lis, _ := start(t)
+	c := dial(t, lis)
+	c.Inc("a")
+	c.Close()
+	if _, err := c.Get("a"); !errors.Is(err, rpc.ErrShutdown) {
+		t.Errorf("Get after Close = %v, want rpc.ErrShutdown", err)
+	}
+}
+
+func TestJSONCodec(t *testing.T) {
+	srv, err := NewServer(NewSafeCounter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// On the wire: a JSON-RPC 1.0 request, and its response
+	server, raw := net.Pipe()
+	go ServeJSON(srv, server)
+	defer raw.Close()
+	raw.SetDeadline(time.Now().Add(time.Second))
+	if _, err := raw.Write([]byte(`{"method":"Counter.Inc","params":[{"Key":"gopher"}],"id":1}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		ID     int
+		Result int
+		Error  any
+	}
+	if err := json.NewDecoder(raw).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != 1 || resp.Result != 1 || resp.Error != nil {
+		t.Errorf("response %+v, want id 1, result 1, no error", resp)
+	}
+
+	// The JSON client on the same server sees that Inc
+	server2, client2 := net.Pipe()
+	go ServeJSON(srv, server2)
+	c := NewJSONClient(client2)
+	defer c.Close()
+	if n, err := c.Inc("gopher"); err != nil || n != 2 {
+		t.Errorf("JSON client Inc = %d, %v, want 2", n, err)
+	}
+	if _, err := c.Get(""); err == nil || err.Error() != ErrEmptyKey.Error() {
+		t.Errorf("JSON client Get(\"\") = %v, want %q", err, ErrEmptyKey)
+	}
+}
+
+func TestOverTCP(t *testing.T) {
+	srv, _ := NewServer(NewSafeCounter())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- Serve(srv, lis) }()
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(conn)
+	for range 3 {
+		c.Inc("tcp")
+	}
+	if n, err := c.Get("tcp"); err != nil || n != 3 {
+		t.Errorf("Get over TCP = %d, %v, want 3", n, err)
+	}
+	c.Close()
+	lis.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Serve after the listener closed = %v, want nil", err)
+	}
+}
+
+func TestNewServerTwice(t *testing.T) {
+	// Each is a server of its own: registering the same name twice in one process is fine
+	for range 2 {
+		if _, err := NewServer(NewSafeCounter()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rpc.NewServer().Register(strings.NewReader("")); err == nil {
+		t.Error("registering a type without RPC methods succeeded")
+	}
+}
+
+func TestPipeListener(t *testing.T) {
+	before := runtime.NumGoroutine()
+	lis := NewPipeListener()
+	if lis.Addr().Network() != "pipe" || lis.Addr().String() != "pipe" {
+		t.Errorf("Addr = %s %s", lis.Addr().Network(), lis.Addr())
+	}
+	accepted := make(chan net.Conn)
+	go func() {
+		c, err := lis.Accept()
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- c
+	}()
+	client, err := lis.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := <-accepted
+	go client.Write([]byte("ping"))
+	buf := make([]byte, 4)
+	if n, err := server.Read(buf); err != nil || string(buf[:n]) != "ping" {
+		t.Errorf("Read = %q, %v", buf[:n], err)
+	}
+	client.Close()
+	server.Close()
+
+	// Closed: Accept is net.ErrClosed, Dial fails, a second Close is fine
+	lis.Close()
+	lis.Close()
+	if _, err := lis.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Accept after Close = %v, want net.ErrClosed", err)
+	}
+	if _, err := lis.Dial(); err == nil {
+		t.Error("Dial after Close succeeded")
+	}
+	waitGoroutines(t, before)
+}
+
+// A Dial blocked waiting for an Accept returns when the listener closes
+func TestPipeListenerCloseUnblocksDial(t *testing.T) {
+	lis := NewPipeListener()
+	errc := make(chan error, 1)
+	go func() {
+		_, err := lis.Dial()
+		errc <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	lis.Close()
+	select {
+	case err := <-errc:
+		if err == nil {
+			t.Error("Dial succeeded with no Accept")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Dial still blocked after Close")
+	}
+}
+
+// Closing the clients ends the server's goroutines - ServeConn returns when its conn does
+func TestNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	func() {
+		srv, _ := NewServer(NewSafeCounter())
+		lis := NewPipeListener()
+		defer lis.Close()
+		go Serve(srv, lis)
+		for range 5 {
+			conn, _ := lis.Dial()
+			c := NewClient(conn)
+			c.Inc("a")
+			c.Close()
+		}
+	}()
+	waitGoroutines(t, before)
+}
@@ -0,0 +1,3 @@
+module runtimepkg
+
+go 1.25.0
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtimepkg"
+	"slices"
+	"sync"
+	"time"
+)
+
+// === Ex. the runtime from inside: goroutine counts, GOMAXPROCS, memory stats, the GC's pacing ===
+// (the checks and BenchmarkParallelSum are in runtimepkg_test.go)
+
+func main() {
+	goroutinesExample()
+	// gomaxprocsExample()
+	// memStatsExample()
+	// gcPercentExample()
+}
+
+func goroutinesExample() {
+	base := runtime.NumGoroutine()
+	fmt.Println("at the start:", base, "(main is one)")
+
+	// 10,000 goroutines blocked on a channel: each starts with a 2-8 KB stack, so this is tens of MB at
+	// most - an OS thread each would be gigabytes of stack
+	before := runtimepkg.Read()
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 10_000 {
+		wg.Go(func() { <-release })
+	}
+	during := runtimepkg.Read()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Printf("with 10000 blocked: %d goroutines (%d more) | stacks in use: %d MB\n",
+		during.Goroutines, during.Since(before).Goroutines, m.StackInuse>>20)
+
+	close(release)
+	wg.Wait()
+	// Wait returns once each has called Done - a moment before they've all actually exited
+	fmt.Println("after release:", runtimepkg.WaitGoroutines(base, time.Second))
+
+	// A leak: a goroutine blocked forever on a channel no one will send on stays in the count
+	leak := make(chan int)
+	go func() { <-leak }()
+	fmt.Println("with one leaked:", runtimepkg.WaitGoroutines(base, 10*time.Millisecond),
+		"- watching this number go up over time is how a leak shows (see concurrency/leaks.go)")
+}
+
+// timeSum is the time per ParallelSum over 8 workers, a rough timing of a few runs -
+// BenchmarkParallelSum is the careful one: go test -bench ParallelSum runtimepkg
+func timeSum(data []int) time.Duration {
+	runtimepkg.ParallelSum(data, 8) // once first, untimed
+	const runs = 10
+	start := time.Now()
+	for range runs {
+		runtimepkg.ParallelSum(data, 8)
+	}
+	return time.Since(start) / runs
+}
+
+func gomaxprocsExample() {
+	fmt.Println("CPUs:", runtime.NumCPU(), "| GOMAXPROCS:", runtime.GOMAXPROCS(0))
+
+	// The same 8 goroutines of work, with 1, 2, 4... threads to run them. Up to the number of CPUs each
+	// doubling about halves the time; past it, nothing more runs at once - only more switching
+	data := make([]int, 1<<22)
+	for i := range data {
+		data[i] = i
+	}
+	var base float64
+	procs := []int{1, 2, 4, runtime.NumCPU()}
+	slices.Sort(procs)
+	for _, procs := range slices.Compact(procs) {
+		var d time.Duration
+		runtimepkg.WithGOMAXPROCS(procs, func() { d = timeSum(data) })
+		if base == 0 {
+			base = float64(d)
+		}
+		fmt.Printf("GOMAXPROCS=%-2d %8.2fms per sum, %.2fx GOMAXPROCS=1\n", procs, float64(d)/1e6, base/float64(d))
+	}
+	// The concurrency notes' benchmarks (channelBenchmarkExample, counterBenchmarkExample...) print their
+	// GOMAXPROCS: the GOMAXPROCS environment variable sets it for a whole run, ex. GOMAXPROCS=2 go run .
+	if runtime.NumCPU() == 1 {
+		fmt.Println("(one CPU here: more threads can't run at once, so every row is about the same)")
+	}
+	fmt.Println("put back:", runtime.GOMAXPROCS(0))
+}
+
+func memStatsExample() {
+	runtime.GC() // start from a collected heap, so the numbers are this example's
+	before := runtimepkg.Read()
+
+	// One big allocation: 64 MB, live while big is used
+	big := make([]byte, 64<<20)
+	big[len(big)-1] = 1
+	after := runtimepkg.Read().Since(before)
+	fmt.Printf("after make([]byte, 64MB): heap %d MB, allocated %d MB in %d objects\n", after.HeapAlloc>>20, after.TotalAlloc>>20, after.Mallocs)
+	runtime.KeepAlive(big) // big is used up to here - without this the compiler may treat it as dead earlier
+
+	// Dropped, it's garbage - but still counted in the heap until a GC runs
+	big = nil
+	fmt.Println("after big = nil:", runtimepkg.Read().HeapAlloc>>20, "MB - still there")
+	runtime.GC()
+	fmt.Println("after runtime.GC():", runtimepkg.Read().HeapAlloc>>20, "MB")
+
+	// Many small allocations: what escapes to the heap is counted one by one (go build -gcflags=-m says why)
+	before = runtimepkg.Read()
+	var keep []*[64]byte
+	for range 100_000 {
+		keep = append(keep, new([64]byte))
+	}
+	d := runtimepkg.Read().Since(before)
+	fmt.Printf("100000 new([64]byte): %d objects (the slice's regrowths too), %d MB, %d GCs ran meanwhile\n", d.Mallocs, d.TotalAlloc>>20, d.NumGC)
+	runtime.KeepAlive(keep)
+}
+
+// churn allocates 200 MB of garbage in 1 MB pieces, with 16 MB kept live, and counts the GCs it caused
+func churn() (gcs uint32, peak uint64) {
+	live := make([][]byte, 16)
+	for i := range live {
+		live[i] = make([]byte, 1<<20)
+	}
+	before := runtimepkg.Read()
+	var sink []byte
+	for i := range 200 {
+		sink = make([]byte, 1<<20)
+		sink[0] = byte(i)
+		if h := runtimepkg.Read().HeapAlloc; h > peak {
+			peak = h
+		}
+	}
+	runtime.KeepAlive(live)
+	runtime.KeepAlive(sink)
+	return runtimepkg.Read().Since(before).NumGC, peak
+}
+
+func gcPercentExample() {
+	// SetGCPercent returns the previous setting - put it back when done
+	prev := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(prev)
+
+	// With 16 MB live, GOGC=100 lets the heap grow to about 32 MB before a GC: at 25 to about 20, at 400 to about 80
+	for _, pct := range []int{25, 100, 400, -1} {
+		debug.SetGCPercent(pct)
+		runtime.GC()
+		gcs, peak := churn()
+		fmt.Printf("GOGC=%-4d %3d GCs for 200 MB of garbage | heap peaked at %3d MB\n", pct, gcs, peak>>20)
+	}
+	// Off, the heap just grows: with no GC, all 200 MB was still there. A memory limit (GOMEMLIMIT) is the
+	// safety net for that: the GC runs when the heap nears it, whatever GOGC says
+	debug.SetGCPercent(-1)
+	prevLimit := debug.SetMemoryLimit(48 << 20)
+	gcs, peak := churn()
+	debug.SetMemoryLimit(prevLimit)
+	fmt.Printf("GOGC=off with a 48 MB limit: %d GCs | heap peaked at %d MB\n", gcs, peak>>20)
+}
@@ -0,0 +1,99 @@
+package runtimepkg
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Looking at the scheduler and the garbage collector from inside the program, so the claims in the
+// concurrency notes' comments - "goroutines are cheap", "past GOMAXPROCS there's no more parallelism",
+// "it's garbage until the next GC" - can be checked rather than taken on trust:
+//
+// - runtime.NumGoroutine: goroutines that exist now, running or blocked - main's included
+// - runtime.GOMAXPROCS(n): how many OS threads run Go code at once (default: the CPUs available).
+//   n < 1 only reads it. More goroutines than that take turns
+// - runtime.ReadMemStats: the heap and the GC's counters. It stops the world for a moment, so it's for
+//   examples and occasional metrics, not a hot loop (runtime/metrics reads the same without stopping)
+// - debug.SetGCPercent: how much the heap may grow past what was live after the last GC before the next
+//   one starts (GOGC, default 100: double). Lower is less memory, more GC work; -1 turns the GC off
+
+// Snapshot is a few of ReadMemStats' numbers, and the goroutine count
+type Snapshot struct {
+	Goroutines int
+	HeapAlloc  uint64 // bytes of heap objects, live and not yet collected
+	TotalAlloc uint64 // bytes ever allocated - it only grows
+	Mallocs    uint64 // heap objects ever allocated
+	NumGC      uint32 // GC cycles completed
+	PauseTotal time.Duration
+}
+
+// Read takes a Snapshot now
+func Read() Snapshot {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return Snapshot{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  m.HeapAlloc,
+		TotalAlloc: m.TotalAlloc,
+		Mallocs:    m.Mallocs,
+		NumGC:      m.NumGC,
+		PauseTotal: time.Duration(m.PauseTotalNs),
+	}
+}
+
+// Since is how the counters moved from before to s. HeapAlloc can go down, so it's s's own value
+func (s Snapshot) Since(before Snapshot) Snapshot {
+	return Snapshot{
+		Goroutines: s.Goroutines - before.Goroutines,
+		HeapAlloc:  s.HeapAlloc,
+		TotalAlloc: s.TotalAlloc - before.TotalAlloc,
+		Mallocs:    s.Mallocs - before.Mallocs,
+		NumGC:      s.NumGC - before.NumGC,
+		PauseTotal: s.PauseTotal - before.PauseTotal,
+	}
+}
+
+// WithGOMAXPROCS runs f with GOMAXPROCS set to n, then puts it back
+func WithGOMAXPROCS(n int, f func()) {
+	prev := runtime.GOMAXPROCS(n)
+	defer runtime.GOMAXPROCS(prev)
+	f()
+}
+
+// ParallelSum sums data in workers goroutines, each over its own part - CPU-bound work that only gets
+// faster with more threads to run it on
+func ParallelSum(data []int, workers int) int {
+	sums := make([]int, workers)
+	var wg sync.WaitGroup
+	chunk := (len(data) + workers - 1) / workers
+	for w := range workers {
+		lo, hi := min(w*chunk, len(data)), min((w+1)*chunk, len(data))
+		wg.Go(func() {
+			s := 0
+			for _, v := range data[lo:hi] {
+				s += v
+			}
+			sums[w] = s // each worker its own element: no lock, and the sums are read after Wait
+		})
+	}
+	wg.Wait()
+	total := 0
+	for _, s := range sums {
+		total += s
+	}
+	return total
+}
+
+// WaitGoroutines waits, up to timeout, for the goroutine count to fall to n - goroutines that have
+// been told to stop take a moment to actually exit. It returns the count it last saw
+func WaitGoroutines(n int, timeout time.Duration) int {
+	deadline := time.Now().Add(timeout)
+	for {
+		got := runtime.NumGoroutine()
+		if got <= n || time.Now().After(deadline) {
+			return got
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,117 @@
+package runtimepkg
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallelSum(t *testing.T) {
+	data := make([]int, 1001)
+	for i := range data {
+		data[i] = i
+	}
+	for _, workers := range []int{1, 2, 3, 8, 1001, 2000} { // 2000: more workers than elements, some with none
+		if got := ParallelSum(data, workers); got != 500500 {
+			t.Errorf("ParallelSum over %d workers = %d, want 500500", workers, got)
+		}
+	}
+	if got := ParallelSum(nil, 4); got != 0 {
+		t.Errorf("ParallelSum(nil) = %d", got)
+	}
+}
+
+func TestWithGOMAXPROCS(t *testing.T) {
+	prev := runtime.GOMAXPROCS(0)
+	var during int
+	WithGOMAXPROCS(1, func() { during = runtime.GOMAXPROCS(0) })
+	if during != 1 || runtime.GOMAXPROCS(0) != prev {
+		t.Errorf("during: %d, after: %d, want 1 and %d", during, runtime.GOMAXPROCS(0), prev)
+	}
+	// Put back even when f panics
+	func() {
+		defer func() { recover() }()
+		WithGOMAXPROCS(1, func() { panic("f failed") })
+	}()
+	if runtime.GOMAXPROCS(0) != prev {
+		t.Errorf("after a panic: GOMAXPROCS %d, want %d", runtime.GOMAXPROCS(0), prev)
+	}
+}
+
+// Goroutines blocked on a channel are counted, and WaitGoroutines sees them gone once they've exited
+func TestGoroutineCount(t *testing.T) {
+	base := WaitGoroutines(runtime.NumGoroutine(), 0)
+	before := Read()
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 1000 {
+		wg.Go(func() { <-release })
+	}
+	if d := Read().Since(before).Goroutines; d != 1000 {
+		t.Errorf("%d more goroutines, want 1000", d)
+	}
+	close(release)
+	wg.Wait()
+	if got := WaitGoroutines(base, time.Second); got > base {
+		t.Errorf("after release: %d goroutines, want %d", got, base)
+	}
+
+	// One that never exits: WaitGoroutines gives up at the timeout, and says how many there still are
+	leak := make(chan struct{})
+	go func() { <-leak }()
+	defer close(leak)
+	start := time.Now()
+	if got := WaitGoroutines(base, 20*time.Millisecond); got != base+1 {
+		t.Errorf("with one blocked: %d, want %d", got, base+1)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("returned after %v, before the timeout", waited)
+	}
+}
+
+var sink []byte
+
+func TestReadSince(t *testing.T) {
+	runtime.GC()
+	before := Read()
+	sink = make([]byte, 64<<20)
+	d := Read().Since(before)
+	if d.TotalAlloc < 64<<20 || d.Mallocs < 1 || d.HeapAlloc < 64<<20 {
+		t.Errorf("after 64 MB: %+v", d)
+	}
+	// Garbage stays in HeapAlloc until a GC
+	sink = nil
+	runtime.GC()
+	after := Read()
+	if after.HeapAlloc >= 32<<20 || after.NumGC <= before.NumGC {
+		t.Errorf("after a GC: heap %d MB, %d GCs (before %d)", after.HeapAlloc>>20, after.NumGC, before.NumGC)
+	}
+	// TotalAlloc only grows
+	if after.TotalAlloc < before.TotalAlloc+64<<20 {
+		t.Errorf("TotalAlloc went from %d to %d", before.TotalAlloc, after.TotalAlloc)
+	}
+}
+
+// BenchmarkParallelSum is the same 8 goroutines of work, with 1, 2, 4... threads to run them. Up to the
+// number of CPUs each doubling about halves the time; past it, nothing more runs at once.
+// go test -bench ParallelSum runtimepkg
+func BenchmarkParallelSum(b *testing.B) {
+	data := make([]int, 1<<22)
+	for i := range data {
+		data[i] = i
+	}
+	procs := []int{1, 2, 4, runtime.NumCPU()}
+	slices.Sort(procs)
+	for _, n := range slices.Compact(procs) {
+		b.Run(fmt.Sprintf("GOMAXPROCS=%d", n), func(b *testing.B) {
+			WithGOMAXPROCS(n, func() {
+				for b.Loop() {
+					ParallelSum(data, 8)
+				}
+			})
+		})
+	}
+}
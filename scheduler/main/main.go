@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"scheduler"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// === Ex. a job scheduler: time.Ticker per job, jitter, overlap prevention, panic recovery, Stop ===
+
+// The intervals are milliseconds, so each example is done in well under a second. The counts can be a
+// little off: a loaded machine runs goroutines late, and a tick that comes late is a run missed.
+// scheduler_test.go checks them exactly, on synctest's fake clock
+
+func main() {
+	scheduleExample()
+	// jitterExample()
+	// overlapExample()
+	// panicRecoveryExample()
+	// stopExample()
+}
+
+func scheduleExample() {
+	logger := log.New(os.Stdout, "  log: ", 0)
+	s := scheduler.New(logger)
+	var fast, slow atomic.Int64
+	s.Add(scheduler.Job{Name: "fast", Every: 10 * time.Millisecond, Run: func(context.Context) error { fast.Add(1); return nil }})
+	s.Add(scheduler.Job{Name: "slow", Every: 50 * time.Millisecond, Run: func(context.Context) error { slow.Add(1); return nil }})
+	fails := 0
+	s.Add(scheduler.Job{Name: "flaky", Every: 40 * time.Millisecond, Run: func(context.Context) error {
+		fails++ // only ever one run of a job at a time, so this needs no lock
+		return fmt.Errorf("attempt %d: upstream unavailable", fails)
+	}})
+	fmt.Println("Add again:", s.Add(scheduler.Job{Name: "fast", Every: time.Second, Run: func(context.Context) error { return nil }}))
+	fmt.Println("Add with no interval:", s.Add(scheduler.Job{Name: "never", Run: func(context.Context) error { return nil }}))
+
+	s.Start(context.Background())
+	fmt.Println("Start again:", s.Start(context.Background()))
+	time.Sleep(205 * time.Millisecond)
+	s.Stop(context.Background())
+
+	f, _ := s.Stats("fast")
+	sl, _ := s.Stats("slow")
+	fl, _ := s.Stats("flaky")
+	fmt.Printf("in ~200ms: fast ran %d times, slow %d, flaky %d (%d failures, last: %v)\n", f.Runs, sl.Runs, fl.Runs, fl.Failures, fl.LastErr)
+
+	// After Stop, nothing more runs
+	before := fast.Load()
+	time.Sleep(30 * time.Millisecond)
+	fmt.Println("fast runs, 30ms after Stop:", fast.Load()-before, "more")
+}
+
+func jitterExample() {
+	// Without jitter, 5 jobs on the same interval start at the same moment, every time...
+	starts := func(jitter time.Duration) time.Duration {
+		var mu sync.Mutex
+		var first []time.Time
+		s := scheduler.New(nil)
+		for i := range 5 {
+			s.Add(scheduler.Job{Name: fmt.Sprint("job", i), Every: 20 * time.Millisecond, Jitter: jitter, Run: func(context.Context) error {
+				mu.Lock()
+				first = append(first, time.Now())
+				mu.Unlock()
+				return nil
+			}})
+		}
+		s.Start(context.Background())
+		time.Sleep(50 * time.Millisecond) // the first tick, and all the jitter after it
+		s.Stop(context.Background())
+		mu.Lock()
+		defer mu.Unlock()
+		// the first 5 runs are one from each job: received in order, so the last minus the first is the spread
+		return first[min(len(first), 5)-1].Sub(first[0])
+	}
+	none, some := starts(0), starts(25*time.Millisecond)
+	fmt.Printf("spread of the first runs: no jitter %v | up to 25ms of jitter %v\n", none.Round(time.Millisecond), some.Round(time.Millisecond))
+	// ...so a thousand servers each running "every minute" hit the database together, at :00. Jitter smears that
+	// over a few seconds. The interval itself stays: each run is still every 20ms, plus its own delay
+}
+
+func overlapExample() {
+	// Every 10ms, a job that takes 35ms: a run is still going at the next 3 ticks, which are skipped
+	s := scheduler.New(nil)
+	var inFlight, most atomic.Int64
+	s.Add(scheduler.Job{Name: "report", Every: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		if n > most.Load() {
+			most.Store(n)
+		}
+		time.Sleep(35 * time.Millisecond)
+		return nil
+	}})
+	s.Start(context.Background())
+	time.Sleep(205 * time.Millisecond)
+	s.Stop(context.Background())
+	st, _ := s.Stats("report")
+	fmt.Printf("20 ticks: %d runs, %d skipped | at most %d running at once\n", st.Runs, st.Skipped, most.Load())
+}
+
+func panicRecoveryExample() {
+	var logged atomic.Int64
+	logger := log.New(writerFunc(func(p []byte) (int, error) { logged.Add(1); return len(p), nil }), "", 0)
+	s := scheduler.New(logger)
+	var ok atomic.Int64
+	s.Add(scheduler.Job{Name: "broken", Every: 10 * time.Millisecond, Run: func(context.Context) error {
+		var m map[string]int
+		m["boom"]++ // a nil map: panics, every time
+		return nil
+	}})
+	s.Add(scheduler.Job{Name: "healthy", Every: 10 * time.Millisecond, Run: func(context.Context) error { ok.Add(1); return nil }})
+	s.Start(context.Background())
+	time.Sleep(55 * time.Millisecond)
+	s.Stop(context.Background())
+
+	st, _ := s.Stats("broken")
+	fmt.Printf("broken: %d runs, %d panics | last: %v\n", st.Runs, st.Panics, st.LastErr)
+	fmt.Println("is ErrPanic:", errors.Is(st.LastErr, scheduler.ErrPanic), "| logged:", logged.Load(), "| the other job's runs:", ok.Load())
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func stopExample() {
+	// A job that watches its context finishes when Stop cancels it - Stop waits for that
+	s := scheduler.New(nil)
+	var cleanedUp atomic.Bool
+	s.Add(scheduler.Job{Name: "upload", Every: 5 * time.Millisecond, Run: func(ctx context.Context) error {
+		select {
+		case <-time.After(time.Second): // a long upload...
+			return nil
+		case <-ctx.Done(): // ...abandoned on Stop
+			cleanedUp.Store(true)
+			return ctx.Err()
+		}
+	}})
+	s.Start(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	err := s.Stop(context.Background())
+	st, _ := s.Stats("upload")
+	fmt.Println("Stop:", err, "after", time.Since(start).Round(time.Millisecond), "| the run saw:", st.LastErr)
+	fmt.Println("the run cleaned up before Stop returned:", cleanedUp.Load())
+
+	// Cancelling Start's context stops the jobs too - ex. signal.NotifyContext in a main
+	ctx, cancel := context.WithCancel(context.Background())
+	s2 := scheduler.New(nil)
+	var n atomic.Int64
+	s2.Add(scheduler.Job{Name: "tick", Every: 5 * time.Millisecond, Run: func(context.Context) error { n.Add(1); return nil }})
+	s2.Start(ctx)
+	time.Sleep(22 * time.Millisecond)
+	cancel()
+	time.Sleep(5 * time.Millisecond) // the loop sees it
+	before := n.Load()
+	time.Sleep(20 * time.Millisecond)
+	fmt.Println("runs before the cancel:", before, "| after:", n.Load()-before)
+	s2.Stop(context.Background())
+
+	// A job that ignores its context can't be stopped - Stop gives up waiting at its deadline instead
+	s3 := scheduler.New(nil)
+	s3.Add(scheduler.Job{Name: "stubborn", Every: 5 * time.Millisecond, Run: func(context.Context) error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	}})
+	s3.Start(context.Background())
+	time.Sleep(10 * time.Millisecond)
+	deadline, cancel3 := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel3()
+	err = s3.Stop(deadline)
+	fmt.Println("Stop with a 30ms deadline:", err)
+}
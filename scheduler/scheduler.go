@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A cron-like scheduler: each job runs every so often, on its own goroutine.
+//
+// - a time.Ticker per job is the clock. A Ticker's channel holds one tick: if nobody is receiving when the
+//   next one comes, it's dropped, not queued - so the loop never falls behind and catches up in a burst
+// - Jitter delays each run by a random amount up to it, so jobs with the same interval (or the same job on
+//   many machines) don't all fire at the same instant
+// - each run is on a goroutine of its own, so a slow run doesn't delay the ticks. If a run is still going
+//   at the next tick, that tick is skipped rather than starting a second copy: two copies of a cleanup or a
+//   report at once usually does the work twice, or worse
+// - a panicking job is recovered and counted - one broken job doesn't take the scheduler down with it
+// - Start takes a context: cancelling it stops everything, as Stop does. Runs get a context that's
+//   cancelled on Stop, and Stop waits for them - up to its own context's deadline, like http.Server.Shutdown
+//
+//	s := scheduler.New(logger)
+//	s.Add(scheduler.Job{Name: "cleanup", Every: time.Minute, Jitter: 5 * time.Second, Run: cleanup})
+//	s.Start(ctx)
+//	defer s.Stop(context.Background())
+
+// Job is a func to run every Every, each run delayed by a random 0 up to Jitter
+type Job struct {
+	Name   string
+	Every  time.Duration
+	Jitter time.Duration
+	Run    func(ctx context.Context) error
+}
+
+// JobStats are what's happened to a job so far
+type JobStats struct {
+	Runs     int // started
+	Failures int // returned an error, or panicked
+	Panics   int
+	Skipped  int // ticks that came while the previous run was still going
+	LastErr  error
+	LastRun  time.Time
+}
+
+var (
+	ErrDuplicate = errors.New("scheduler: duplicate job name")
+	ErrBadJob    = errors.New("scheduler: invalid job")
+	ErrRunning   = errors.New("scheduler: already started")
+	ErrPanic     = errors.New("job panicked")
+)
+
+// job is a Job and its state. running is the overlap guard: set while a run is in progress
+type job struct {
+	Job
+	running atomic.Bool
+
+	mu    sync.Mutex
+	stats JobStats
+}
+
+type Scheduler struct {
+	logger *log.Logger
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	order   []string
+	ctx     context.Context // nil until Start
+	cancel  context.CancelFunc
+	loops   sync.WaitGroup // one per job, ticking
+	runs    sync.WaitGroup // one per run in progress
+	stopped bool
+}
+
+// New makes a Scheduler logging failures to logger - nil for no logging
+func New(logger *log.Logger) *Scheduler {
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	return &Scheduler{logger: logger, jobs: make(map[string]*job)}
+}
+
+// Add registers j. After Start, it starts ticking straight away
+func (s *Scheduler) Add(j Job) error {
+	if j.Name == "" || j.Every <= 0 || j.Jitter < 0 || j.Run == nil {
+		return fmt.Errorf("%w %q: needs a name, a positive interval and a Run func", ErrBadJob, j.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[j.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicate, j.Name)
+	}
+	jb := &job{Job: j}
+	s.jobs[j.Name] = jb
+	s.order = append(s.order, j.Name)
+	if s.ctx != nil && !s.stopped {
+		s.startLocked(jb)
+	}
+	return nil
+}
+
+// Start starts every job's ticker. The jobs stop when ctx is cancelled or on Stop.
+// A Scheduler starts once: after a Stop, make a new one
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return ErrRunning
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, name := range s.order {
+		s.startLocked(s.jobs[name])
+	}
+	return nil
+}
+
+func (s *Scheduler) startLocked(j *job) {
+	s.loops.Go(func() { s.loop(s.ctx, j) })
+}
+
+// loop is one job's clock: wait for a tick, wait out the jitter, start a run - until ctx is done
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	ticker := time.NewTicker(j.Every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if j.Jitter > 0 {
+			delay := time.NewTimer(rand.N(j.Jitter))
+			select {
+			case <-ctx.Done():
+				delay.Stop()
+				return
+			case <-delay.C:
+			}
+		}
+		// A select with a tick and ctx.Done both ready picks either: a tick that came with Stop mustn't
+		// start a run after it
+		if ctx.Err() != nil {
+			return
+		}
+		if !j.running.CompareAndSwap(false, true) {
+			j.mu.Lock()
+			j.stats.Skipped++
+			j.mu.Unlock()
+			continue
+		}
+		s.runs.Go(func() {
+			defer j.running.Store(false)
+			s.run(ctx, j)
+		})
+	}
+}
+
+// run runs j once, turning a panic into an error
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = fmt.Errorf("%w: %v", ErrPanic, v)
+				s.logger.Printf("job %s: panic: %v\n%s", j.Name, v, debug.Stack())
+			}
+		}()
+		return j.Run(ctx)
+	}()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.stats.Runs++
+	j.stats.LastRun = start
+	j.stats.LastErr = err
+	if err != nil {
+		j.stats.Failures++
+		if errors.Is(err, ErrPanic) {
+			j.stats.Panics++
+		} else {
+			s.logger.Printf("job %s: %v", j.Name, err)
+		}
+	}
+}
+
+// Stats are name's stats, and false if there's no such job
+func (s *Scheduler) Stats(name string) (JobStats, bool) {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return JobStats{}, false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.stats, true
+}
+
+// Stop stops the tickers, cancels the running jobs' context, and waits for the runs to return.
+// If ctx is done first, Stop returns its error, with the stuck runs still going - a job that ignores its
+// context can't be stopped from outside, only no longer waited for. Stopping twice is fine
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait() // first: once the loops are gone, no new runs start
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
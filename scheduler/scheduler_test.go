@@ -0,0 +1,364 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// The tests run in a synctest bubble: its Tickers, Timers and Sleeps run on a fake clock that jumps ahead
+// whenever every goroutine in the bubble is waiting. So the intervals are exact - 200ms is 20 ticks of 10ms,
+// every time, however loaded the machine is - and the tests take no real time
+
+func nop(context.Context) error { return nil }
+
+func counting(n *atomic.Int64) func(context.Context) error {
+	return func(context.Context) error { n.Add(1); return nil }
+}
+
+func TestAddValidates(t *testing.T) {
+	s := New(nil)
+	tests := []Job{
+		{Every: time.Second, Run: nop},
+		{Name: "no interval", Run: nop},
+		{Name: "negative", Every: -time.Second, Run: nop},
+		{Name: "negative jitter", Every: time.Second, Jitter: -1, Run: nop},
+		{Name: "no func", Every: time.Second},
+	}
+	for _, j := range tests {
+		if err := s.Add(j); !errors.Is(err, ErrBadJob) {
+			t.Errorf("Add(%q) = %v, want ErrBadJob", j.Name, err)
+		}
+	}
+	if err := s.Add(Job{Name: "ok", Every: time.Second, Run: nop}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Job{Name: "ok", Every: time.Minute, Run: nop}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Add of a used name = %v, want ErrDuplicate", err)
+	}
+	if _, ok := s.Stats("missing"); ok {
+		t.Error("Stats of a job that isn't there: ok")
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var logs bytes.Buffer
+		s := New(log.New(&logs, "", 0))
+		var fast, slow atomic.Int64
+		s.Add(Job{Name: "fast", Every: 10 * time.Millisecond, Run: counting(&fast)})
+		s.Add(Job{Name: "slow", Every: 50 * time.Millisecond, Run: counting(&slow)})
+		s.Add(Job{Name: "flaky", Every: 40 * time.Millisecond, Run: func(context.Context) error {
+			return errors.New("upstream unavailable")
+		}})
+		start := time.Now()
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Start(context.Background()); !errors.Is(err, ErrRunning) {
+			t.Errorf("a second Start = %v, want ErrRunning", err)
+		}
+		time.Sleep(205 * time.Millisecond)
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string]int{"fast": 20, "slow": 4, "flaky": 5}
+		for name, runs := range want {
+			st, ok := s.Stats(name)
+			if !ok || st.Runs != runs {
+				t.Errorf("%s: %d runs in 205ms, want %d", name, st.Runs, runs)
+			}
+		}
+		if got := fast.Load(); got != 20 {
+			t.Errorf("fast's func ran %d times, want 20", got)
+		}
+		if st, _ := s.Stats("fast"); !st.LastRun.Equal(start.Add(200*time.Millisecond)) || st.Failures != 0 || st.LastErr != nil {
+			t.Errorf("fast: %+v, last run %v after the start", st, st.LastRun.Sub(start))
+		}
+		st, _ := s.Stats("flaky")
+		if st.Failures != st.Runs || st.Panics != 0 || st.LastErr == nil {
+			t.Errorf("flaky: %+v, want every run failed", st)
+		}
+		if n := strings.Count(logs.String(), "job flaky: upstream unavailable"); n != st.Failures {
+			t.Errorf("%d failures logged, want %d:\n%s", n, st.Failures, logs.String())
+		}
+
+		// Nothing runs after Stop
+		time.Sleep(100 * time.Millisecond)
+		if got := fast.Load(); got != 20 {
+			t.Errorf("fast ran %d times, 20 before Stop", got)
+		}
+	})
+}
+
+func TestJitter(t *testing.T) {
+	// firstRuns are when each of 5 jobs with the same interval first ran, after the start
+	firstRuns := func(jitter time.Duration) []time.Duration {
+		var mu sync.Mutex
+		first := map[string]time.Duration{}
+		synctest.Test(t, func(t *testing.T) {
+			s := New(nil)
+			start := time.Now()
+			for i := range 5 {
+				name := fmt.Sprint("job", i)
+				s.Add(Job{Name: name, Every: 20 * time.Millisecond, Jitter: jitter, Run: func(context.Context) error {
+					mu.Lock()
+					defer mu.Unlock()
+					if _, ok := first[name]; !ok {
+						first[name] = time.Since(start)
+					}
+					return nil
+				}})
+			}
+			s.Start(context.Background())
+			time.Sleep(50 * time.Millisecond)
+			s.Stop(context.Background())
+		})
+		var out []time.Duration
+		for _, d := range first {
+			out = append(out, d)
+		}
+		return out
+	}
+
+	for _, d := range firstRuns(0) {
+		if d != 20*time.Millisecond {
+			t.Errorf("no jitter: a first run at %v, want 20ms", d)
+		}
+	}
+	got := firstRuns(25 * time.Millisecond)
+	if len(got) != 5 {
+		t.Fatalf("%d jobs ran, want 5", len(got))
+	}
+	spread := false
+	for _, d := range got {
+		if d < 20*time.Millisecond || d >= 45*time.Millisecond {
+			t.Errorf("jitter 25ms: a first run at %v, want in [20ms, 45ms)", d)
+		}
+		spread = spread || d != got[0]
+	}
+	if !spread {
+		t.Errorf("jitter 25ms: every job first ran at %v", got[0])
+	}
+}
+
+// Every 10ms, a run of 35ms: the ticks while it's going are skipped, not queued
+func TestNoOverlap(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := New(nil)
+		var inFlight, most atomic.Int64
+		s.Add(Job{Name: "report", Every: 10 * time.Millisecond, Run: func(context.Context) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := most.Load()
+				if n <= m || most.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(35 * time.Millisecond)
+			return nil
+		}})
+		s.Start(context.Background())
+		time.Sleep(205 * time.Millisecond)
+		s.Stop(context.Background())
+
+		st, _ := s.Stats("report")
+		// Runs at 10, 50, 90, 130 and 170ms; the other 15 of the 20 ticks found one going
+		if st.Runs != 5 || st.Skipped != 15 {
+			t.Errorf("%d runs, %d skipped, want 5 and 15", st.Runs, st.Skipped)
+		}
+		if most.Load() != 1 {
+			t.Errorf("%d runs at once, want 1", most.Load())
+		}
+	})
+}
+
+func TestPanicRecovery(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var logs bytes.Buffer
+		var mu sync.Mutex
+		s := New(log.New(writerFunc(func(p []byte) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return logs.Write(p)
+		}), "", 0))
+		var healthy atomic.Int64
+		s.Add(Job{Name: "broken", Every: 10 * time.Millisecond, Run: func(context.Context) error {
+			var m map[string]int
+			m["boom"]++
+			return nil
+		}})
+		s.Add(Job{Name: "healthy", Every: 10 * time.Millisecond, Run: counting(&healthy)})
+		s.Start(context.Background())
+		time.Sleep(55 * time.Millisecond)
+		s.Stop(context.Background())
+
+		st, _ := s.Stats("broken")
+		if st.Runs != 5 || st.Panics != 5 || st.Failures != 5 || !errors.Is(st.LastErr, ErrPanic) {
+			t.Errorf("broken: %+v, want 5 runs, each a recovered panic", st)
+		}
+		if !strings.Contains(st.LastErr.Error(), "nil map") {
+			t.Errorf("LastErr %q doesn't say what panicked", st.LastErr)
+		}
+		if got := healthy.Load(); got != 5 {
+			t.Errorf("healthy ran %d times beside the broken one, want 5", got)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if n := strings.Count(logs.String(), "job broken: panic:"); n != 5 {
+			t.Errorf("%d panics logged, want 5", n)
+		}
+		if !strings.Contains(logs.String(), "goroutine ") {
+			t.Error("no stack trace logged with the panic")
+		}
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// Stop cancels the runs' context, and waits for them
+func TestStopCancelsRuns(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := New(nil)
+		s.Add(Job{Name: "upload", Every: 5 * time.Millisecond, Run: func(ctx context.Context) error {
+			select {
+			case <-time.After(time.Second):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}})
+		s.Start(context.Background())
+		time.Sleep(20 * time.Millisecond)
+		start := time.Now()
+		if err := s.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if took := time.Since(start); took != 0 {
+			t.Errorf("Stop took %v: the run didn't see the cancel", took)
+		}
+		st, _ := s.Stats("upload")
+		if st.Runs != 1 || !errors.Is(st.LastErr, context.Canceled) {
+			t.Errorf("upload: %+v, want one run, cancelled", st)
+		}
+		if err := s.Stop(context.Background()); err != nil {
+			t.Errorf("a second Stop = %v", err)
+		}
+		if err := s.Start(context.Background()); !errors.Is(err, ErrRunning) {
+			t.Errorf("Start after Stop = %v, want ErrRunning", err)
+		}
+	})
+}
+
+// A run that ignores its context: Stop gives up at its own deadline
+func TestStopDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := New(nil)
+		s.Add(Job{Name: "stubborn", Every: 5 * time.Millisecond, Run: func(context.Context) error {
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		}})
+		s.Start(context.Background())
+		time.Sleep(10 * time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Stop = %v, want context.DeadlineExceeded", err)
+		}
+		if took := time.Since(start); took != 30*time.Millisecond {
+			t.Errorf("Stop gave up after %v, want 30ms", took)
+		}
+		// The run is still going; a Stop without a deadline waits it out
+		if err := s.Stop(context.Background()); err != nil {
+			t.Errorf("Stop = %v", err)
+		}
+		if st, _ := s.Stats("stubborn"); st.Runs != 1 {
+			t.Errorf("%d runs, want 1", st.Runs)
+		}
+	})
+}
+
+// Cancelling Start's context stops the jobs, like Stop
+func TestStartContext(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := New(nil)
+		var n atomic.Int64
+		s.Add(Job{Name: "tick", Every: 5 * time.Millisecond, Jitter: time.Millisecond, Run: counting(&n)})
+		s.Start(ctx)
+		time.Sleep(22 * time.Millisecond)
+		cancel()
+		synctest.Wait()
+		before := n.Load()
+		if before != 4 {
+			t.Errorf("%d runs in 22ms of 5ms ticks with under 1ms of jitter, want 4", before)
+		}
+		time.Sleep(50 * time.Millisecond)
+		if got := n.Load(); got != before {
+			t.Errorf("%d runs after the cancel, want %d", got, before)
+		}
+		s.Stop(context.Background())
+	})
+}
+
+func TestAddWhileRunning(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := New(nil)
+		s.Start(context.Background())
+		var late atomic.Int64
+		time.Sleep(50 * time.Millisecond)
+		// Added after Start: ticks from when it's added
+		if err := s.Add(Job{Name: "late", Every: 10 * time.Millisecond, Run: counting(&late)}); err != nil {
+			t.Fatal(err)
+		}
+		// Several at once, for the race detector
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Go(func() {
+				if err := s.Add(Job{Name: fmt.Sprint("job", i), Every: 10 * time.Millisecond, Run: nop}); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+		wg.Wait()
+		time.Sleep(35 * time.Millisecond)
+		s.Stop(context.Background())
+		if got := late.Load(); got != 3 {
+			t.Errorf("late ran %d times in 35ms, want 3", got)
+		}
+		for i := range 10 {
+			if st, _ := s.Stats(fmt.Sprint("job", i)); st.Runs != 3 {
+				t.Errorf("job%d: %d runs, want 3", i, st.Runs)
+			}
+		}
+
+		// Added after Stop: never runs
+		var never atomic.Int64
+		s.Add(Job{Name: "after stop", Every: time.Millisecond, Run: counting(&never)})
+		time.Sleep(20 * time.Millisecond)
+		if never.Load() != 0 {
+			t.Error("a job added after Stop ran")
+		}
+	})
+}
+
+func TestStopBeforeStart(t *testing.T) {
+	s := New(nil)
+	s.Add(Job{Name: "a", Every: time.Millisecond, Run: nop})
+	if err := s.Stop(context.Background()); err != nil {
+		t.Errorf("Stop before Start = %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// === Ex. Inverted index (a mini search engine) ===
+
+// An inverted index maps each word to the set of documents containing it
+// (the "inverse" of a document, which maps to its words):
+//
+//	"channel"   -> {doc 2, doc 3}
+//	"goroutine" -> {doc 2}
+//
+// Searching for a word is then a single map lookup, instead of scanning every document.
+// Combines maps, a generic set, string processing and sorting from across the notes.
+
+type DocID int
+
+type Document struct {
+	ID    DocID
+	Title string
+	Body  string
+}
+
+// --- A generic set on top of a map ---
+
+// map[T]struct{} - the empty struct takes no memory, only the keys matter
+type Set[T comparable] map[T]struct{}
+
+func (s Set[T]) Add(v T) { s[v] = struct{}{} }
+
+func (s Set[T]) Has(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+func (s Set[T]) Intersect(other Set[T]) Set[T] {
+	// loop over the smaller set - each lookup in the bigger one is O(1)
+	small, big := s, other
+	if len(big) < len(small) {
+		small, big = big, small
+	}
+	out := Set[T]{}
+	for v := range small {
+		if big.Has(v) {
+			out.Add(v)
+		}
+	}
+	return out
+}
+
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	out := Set[T]{}
+	for v := range s {
+		out.Add(v)
+	}
+	for v := range other {
+		out.Add(v)
+	}
+	return out
+}
+
+// --- Text processing ---
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "is": true, "are": true,
+	"of": true, "to": true, "in": true, "it": true, "or": true, "on": true, "can": true,
+}
+
+// tokenize lowercases the text and splits it on anything that is not a letter or digit.
+// Trailing "s" is removed as a (very) rough stemmer, so "channels" matches "channel"
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if stopWords[f] {
+			continue
+		}
+		if len(f) > 3 && strings.HasSuffix(f, "s") && !strings.HasSuffix(f, "ss") {
+			f = strings.TrimSuffix(f, "s")
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
+
+// --- The index ---
+
+type Index struct {
+	docs     map[DocID]Document
+	postings map[string]Set[DocID]
+
+	// termFreq[term][doc] = how many times term appears in doc, for ranking
+	termFreq map[string]map[DocID]int
+
+	// terms has the same sets as postings, by term in a trie, for prefix queries (trie.go)
+	terms Trie[Set[DocID]]
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[DocID]Document),
+		postings: make(map[string]Set[DocID]),
+		termFreq: make(map[string]map[DocID]int),
+	}
+}
+
+func (idx *Index) Add(doc Document) {
+	idx.docs[doc.ID] = doc
+	for _, term := range tokenize(doc.Title + " " + doc.Body) {
+		if idx.postings[term] == nil {
+			idx.postings[term] = Set[DocID]{}
+			idx.termFreq[term] = make(map[DocID]int)
+			idx.terms.Insert(term, idx.postings[term]) // the same map, so adding to the posting adds to it too
+		}
+		idx.postings[term].Add(doc.ID)
+		idx.termFreq[term][doc.ID]++
+	}
+}
+
+// And returns the documents containing every term
+func (idx *Index) And(terms ...string) Set[DocID] {
+	if len(terms) == 0 {
+		return Set[DocID]{}
+	}
+	result := idx.postings[terms[0]] // a missing term gives a nil set, which behaves as empty
+	for _, t := range terms[1:] {
+		result = result.Intersect(idx.postings[t])
+	}
+	return result.Union(nil) // copy, so callers can't modify the index's own set
+}
+
+// Or returns the documents containing at least one of the terms
+func (idx *Index) Or(terms ...string) Set[DocID] {
+	result := Set[DocID]{}
+	for _, t := range terms {
+		result = result.Union(idx.postings[t])
+	}
+	return result
+}
+
+type Result struct {
+	Doc   Document
+	Score float64
+}
+
+// Search runs a query and ranks the matches.
+// - words separated by spaces must all match (AND)
+// - "OR" between words matches either side, ex. "mutex OR channel"
+// - a word ending in * matches any term starting with it, ex. "chan*" (the prefix isn't stemmed)
+//
+// Ranking is tf-idf: a term counts more the more often it is in the document (term frequency),
+// and the rarer it is across all documents (inverse document frequency) - so a word found in
+// most documents barely affects the order
+func (idx *Index) Search(query string) []Result {
+	var matches Set[DocID]
+	var allTerms []string
+
+	for _, clause := range strings.Split(query, " OR ") {
+		var words []string
+		var prefixed []Set[DocID]
+		for _, w := range strings.Fields(clause) {
+			if prefix, ok := strings.CutSuffix(w, "*"); ok {
+				expanded := idx.Complete(prefix)
+				allTerms = append(allTerms, expanded...)
+				prefixed = append(prefixed, idx.Or(expanded...))
+			} else {
+				words = append(words, w)
+			}
+		}
+		terms := tokenize(strings.Join(words, " "))
+		allTerms = append(allTerms, terms...)
+
+		var clauseMatches Set[DocID]
+		if len(terms) > 0 || len(prefixed) == 0 {
+			clauseMatches = idx.And(terms...)
+		} else {
+			clauseMatches, prefixed = prefixed[0], prefixed[1:]
+		}
+		for _, p := range prefixed {
+			clauseMatches = clauseMatches.Intersect(p)
+		}
+		matches = matches.Union(clauseMatches)
+	}
+
+	results := make([]Result, 0, len(matches))
+	for id := range matches {
+		score := 0.0
+		for _, term := range allTerms {
+			tf := float64(idx.termFreq[term][id])
+			idf := math.Log(float64(len(idx.docs)) / float64(1+len(idx.postings[term])))
+			score += tf * math.Max(idf, 0.01)
+		}
+		results = append(results, Result{Doc: idx.docs[id], Score: score})
+	}
+
+	// Highest score first. Map iteration order is random, so the ID breaks ties for a stable order
+	slices.SortFunc(results, func(a, b Result) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Doc.ID, b.Doc.ID))
+	})
+	return results
+}
+
+// --- Fixture documents (condensed from the other notes in this repo) ---
+
+var fixtureDocs = []Document{
+	{1, "Basics", "Go functions can return multiple values. Slices are a view into an array, maps map keys to values."},
+	{2, "Goroutines", "A goroutine is a lightweight thread. Channels are used to send values between goroutines."},
+	{3, "Channels", "Buffered channels block when the buffer is full. Only the sender should close a channel. Range over a channel until it is closed."},
+	{4, "Mutex", "sync.Mutex provides mutual exclusion when goroutines share memory. Lock and Unlock guard the map in SafeCounter."},
+	{5, "Interfaces", "An interface type is a set of method signatures. Interfaces are implemented implicitly. The error interface and Stringer."},
+	{6, "Generics", "Type parameters let Go functions work with many types. The comparable constraint allows == on values."},
+	{7, "Select", "Select waits on multiple channel operations. The default case runs if no channel is ready."},
+}
+
+func printResults(idx *Index, query string) {
+	results := idx.Search(query)
+	fmt.Printf("%q -> %d result(s)\n", query, len(results))
+	for _, r := range results {
+		fmt.Printf("  %5.2f  [%d] %s\n", r.Score, r.Doc.ID, r.Doc.Title)
+	}
+}
+
+func searchExamples() {
+	idx := NewIndex()
+	for _, doc := range fixtureDocs {
+		idx.Add(doc)
+	}
+	fmt.Println("indexed", len(idx.docs), "documents,", len(idx.postings), "distinct terms")
+
+	printResults(idx, "channel")
+	printResults(idx, "goroutines channels") // AND
+	printResults(idx, "mutex OR select")     // OR
+	printResults(idx, "interface OR generics OR map")
+	printResults(idx, "closures") // no matches
+
+	// And / Or can also be used directly with already tokenized terms
+	fmt.Println("AND(value, function):", sortedIDs(idx.And("value", "function")))
+	fmt.Println("OR(lock, default):", sortedIDs(idx.Or("lock", "default")))
+}
+
+func sortedIDs(s Set[DocID]) []DocID {
+	ids := make([]DocID, 0, len(s))
+	for id := range s {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func main() {
+	searchExamples()
+	// prefixSearchExamples()
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// === A generic trie, for prefix search ===
+
+// The postings map answers "which documents have this term" in one lookup, but not "which terms start with chan" -
+// a map has no order, so that's a scan of every key. A trie (prefix tree) keeps keys by their bytes:
+// each node is one byte further into a key, so every key starting with a prefix is under the prefix's node.
+//
+//	root -c-> . -h-> . -a-> . -n-> [chan] -n-> . -e-> . -l-> [channel]
+//
+// Get, Insert and Delete take time in the length of the key, not the number of keys.
+// Keys are split into bytes, not runes: a UTF-8 prefix is a byte prefix too, so "é" still finds "école".
+// V is any type - here a Set[DocID], so a prefix gives the documents directly.
+
+type trieNode[V any] struct {
+	children map[byte]*trieNode[V]
+	val      V
+	hasVal   bool // a key ends here (an inner node like "cha" has no value unless "cha" was inserted)
+}
+
+// Trie maps strings to values of type V. The zero value is an empty trie ready to use
+type Trie[V any] struct {
+	root trieNode[V]
+	len  int
+}
+
+// Insert sets key's value, replacing any value already there
+func (t *Trie[V]) Insert(key string, v V) {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		if n.children == nil {
+			n.children = map[byte]*trieNode[V]{}
+		}
+		next := n.children[key[i]]
+		if next == nil {
+			next = &trieNode[V]{}
+			n.children[key[i]] = next
+		}
+		n = next
+	}
+	if !n.hasVal {
+		t.len++
+	}
+	n.val, n.hasVal = v, true
+}
+
+// find is the node at the end of key, or nil if no key starts with it
+func (t *Trie[V]) find(key string) *trieNode[V] {
+	n := &t.root
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.children[key[i]]
+	}
+	return n
+}
+
+func (t *Trie[V]) Get(key string) (V, bool) {
+	n := t.find(key)
+	if n == nil || !n.hasVal {
+		var zero V
+		return zero, false
+	}
+	return n.val, true
+}
+
+// Delete removes key, and the nodes that led only to it, so a deleted key leaves no dead branch behind.
+// It reports whether key was there
+func (t *Trie[V]) Delete(key string) bool {
+	path := make([]*trieNode[V], 0, len(key)+1) // the nodes from the root to key's node
+	n := &t.root
+	path = append(path, n)
+	for i := 0; i < len(key); i++ {
+		if n = n.children[key[i]]; n == nil {
+			return false
+		}
+		path = append(path, n)
+	}
+	if !n.hasVal {
+		return false
+	}
+	var zero V
+	n.val, n.hasVal = zero, false // don't keep the value reachable
+	t.len--
+	// Walk back up, cutting off nodes that hold nothing: no value, no children
+	for i := len(key); i > 0 && !path[i].hasVal && len(path[i].children) == 0; i-- {
+		delete(path[i-1].children, key[i-1])
+	}
+	return true
+}
+
+func (t *Trie[V]) Len() int { return t.len }
+
+// WithPrefix iterates over the keys starting with prefix and their values, in sorted (byte) order
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		if n := t.find(prefix); n != nil {
+			n.walk([]byte(prefix), yield)
+		}
+	}
+}
+
+// walk yields n's key (if it has a value), then every key below it - children by byte, so the keys come out sorted.
+// key is the path to n - siblings append into the same spare byte, which is fine: string(key) copies it out first
+func (n *trieNode[V]) walk(key []byte, yield func(string, V) bool) bool {
+	if n.hasVal && !yield(string(key), n.val) {
+		return false
+	}
+	for _, b := range slices.Sorted(maps.Keys(n.children)) {
+		if !n.children[b].walk(append(key, b), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// PrefixSearch returns the values of every key starting with prefix, in key order. "" is every value
+func (t *Trie[V]) PrefixSearch(prefix string) []V {
+	var vs []V
+	for _, v := range t.WithPrefix(prefix) {
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+// --- Prefix queries on the index ---
+
+// Complete returns the indexed terms starting with prefix, sorted - the suggestions for a search box
+func (idx *Index) Complete(prefix string) []string {
+	var terms []string
+	for term := range idx.terms.WithPrefix(strings.ToLower(prefix)) {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Prefix returns the documents containing any term starting with prefix - "chan*" in a query
+func (idx *Index) Prefix(prefix string) Set[DocID] {
+	result := Set[DocID]{}
+	for _, docs := range idx.terms.PrefixSearch(strings.ToLower(prefix)) {
+		result = result.Union(docs)
+	}
+	return result
+}
+
+func prefixSearchExamples() {
+	idx := NewIndex()
+	for _, doc := range fixtureDocs {
+		idx.Add(doc)
+	}
+	fmt.Println("terms in the trie:", idx.terms.Len(), "| same as the postings map:", idx.terms.Len() == len(idx.postings))
+
+	fmt.Println(`Complete("chan"):`, idx.Complete("chan"))
+	fmt.Println(`Complete("go"):`, idx.Complete("go"))
+	fmt.Println(`Prefix("go"):`, sortedIDs(idx.Prefix("go")))
+	printResults(idx, "chan*")
+	printResults(idx, "go* value")    // a prefix and a word: both must match
+	printResults(idx, "mut* OR sel*") // prefixes on either side of OR
+
+	// trie_test.go checks the Trie itself - Get, Delete and pruning, WithPrefix order - against a map
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func testTrie() *Trie[int] {
+	var t Trie[int]
+	for i, k := range []string{"go", "gopher", "goroutine", "good", "chan", "channel", "école"} {
+		t.Insert(k, i)
+	}
+	return &t
+}
+
+func keys[V any](t *Trie[V], prefix string) []string {
+	var ks []string
+	for k := range t.WithPrefix(prefix) {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
+func TestTrieGet(t *testing.T) {
+	tr := testTrie()
+	tr.Insert("go", 10) // replaces
+	if v, ok := tr.Get("go"); v != 10 || !ok {
+		t.Errorf(`Get("go") = %d, %t, want the replaced value 10`, v, ok)
+	}
+	if tr.Len() != 7 {
+		t.Errorf("Len = %d after replacing a value, want 7", tr.Len())
+	}
+	for _, k := range []string{"gor", "g", "", "gophers", "x"} { // inner nodes, past the end, nowhere
+		if v, ok := tr.Get(k); ok {
+			t.Errorf("Get(%q) = %d, found - it isn't a key", k, v)
+		}
+	}
+	var empty Trie[string]
+	if _, ok := empty.Get("a"); ok || empty.Len() != 0 {
+		t.Error("the zero value Trie isn't empty")
+	}
+	empty.Insert("", "root") // the empty key is a key like any other
+	if v, ok := empty.Get(""); v != "root" || !ok || empty.Len() != 1 {
+		t.Errorf(`Get("") = %q, %t, Len %d`, v, ok, empty.Len())
+	}
+}
+
+func TestTrieWithPrefix(t *testing.T) {
+	tr := testTrie()
+	for _, c := range []struct {
+		prefix string
+		want   []string
+	}{
+		{"go", []string{"go", "good", "gopher", "goroutine"}}, // sorted, the prefix itself first
+		{"gop", []string{"gopher"}},
+		{"chan", []string{"chan", "channel"}},
+		{"é", []string{"école"}}, // a byte prefix of a multibyte rune
+		{"x", nil},
+		{"gopherx", nil},
+		{"", []string{"chan", "channel", "go", "good", "gopher", "goroutine", "école"}},
+	} {
+		if got := keys(tr, c.prefix); !slices.Equal(got, c.want) {
+			t.Errorf("WithPrefix(%q) = %v, want %v", c.prefix, got, c.want)
+		}
+	}
+	if got := tr.PrefixSearch("chan"); !slices.Equal(got, []int{4, 5}) {
+		t.Errorf(`PrefixSearch("chan") = %v, want [4 5]`, got)
+	}
+	if got := tr.PrefixSearch("x"); got != nil {
+		t.Errorf(`PrefixSearch("x") = %v, want nil`, got)
+	}
+
+	n := 0
+	for range tr.WithPrefix("go") {
+		if n++; n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("break after 2 keys: %d", n)
+	}
+}
+
+func TestTrieDelete(t *testing.T) {
+	tr := testTrie()
+	if !tr.Delete("goroutine") || tr.Delete("goroutine") || tr.Delete("gop") || tr.Delete("x") {
+		t.Error("Delete reports removing something that wasn't a key, or not removing goroutine")
+	}
+	if _, ok := tr.Get("goroutine"); ok {
+		t.Error("goroutine still there after Delete")
+	}
+	if tr.find("gor") != nil || tr.find("gop") == nil {
+		t.Error("Delete left goroutine's dead branch behind, or pruned gopher's")
+	}
+	tr.Delete("chan") // a key with another below it
+	if v, ok := tr.Get("channel"); v != 5 || !ok || tr.find("chan") == nil {
+		t.Errorf(`after Delete("chan"), Get("channel") = %d, %t - want channel kept`, v, ok)
+	}
+	if tr.Len() != 5 || len(keys(tr, "")) != 5 {
+		t.Errorf("Len %d, %d keys walked, want 5", tr.Len(), len(keys(tr, "")))
+	}
+	for _, k := range keys(tr, "") {
+		tr.Delete(k)
+	}
+	if tr.Len() != 0 || len(tr.root.children) != 0 {
+		t.Errorf("deleting every key left Len %d and %d branches", tr.Len(), len(tr.root.children))
+	}
+}
+
+// Random inserts and deletes on a small alphabet, against a map
+func TestTrieMatchesMap(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var tr Trie[int]
+	model := map[string]int{}
+	for i := range 5000 {
+		b := make([]byte, r.IntN(5))
+		for j := range b {
+			b[j] = "abc"[r.IntN(3)]
+		}
+		k := string(b)
+		if r.IntN(3) == 0 {
+			_, had := model[k]
+			if tr.Delete(k) != had {
+				t.Fatalf("op %d: Delete(%q) = %t, want %t", i, k, !had, had)
+			}
+			delete(model, k)
+		} else {
+			tr.Insert(k, i)
+			model[k] = i
+		}
+		if tr.Len() != len(model) {
+			t.Fatalf("op %d: Len = %d, want %d", i, tr.Len(), len(model))
+		}
+	}
+	if got, want := keys(&tr, ""), slices.Sorted(maps.Keys(model)); !slices.Equal(got, want) {
+		t.Fatalf("keys %v, want %v", got, want)
+	}
+	for k, want := range model {
+		if v, ok := tr.Get(k); v != want || !ok {
+			t.Errorf("Get(%q) = %d, %t, want %d", k, v, ok, want)
+		}
+	}
+}
+
+func testIndex() *Index {
+	idx := NewIndex()
+	for _, doc := range fixtureDocs {
+		idx.Add(doc)
+	}
+	return idx
+}
+
+func resultIDs(rs []Result) []DocID {
+	ids := make([]DocID, len(rs))
+	for i, r := range rs {
+		ids[i] = r.Doc.ID
+	}
+	return ids
+}
+
+func TestIndexPrefix(t *testing.T) {
+	idx := testIndex()
+	if idx.terms.Len() != len(idx.postings) {
+		t.Errorf("%d terms in the trie, %d in the postings map", idx.terms.Len(), len(idx.postings))
+	}
+	if got := idx.Complete("Go"); !slices.Equal(got, []string{"go", "goroutine"}) {
+		t.Errorf(`Complete("Go") = %v, want [go goroutine]`, got)
+	}
+	if got := sortedIDs(idx.Prefix("go")); !slices.Equal(got, []DocID{1, 2, 4, 6}) {
+		t.Errorf(`Prefix("go") = %v, want [1 2 4 6]`, got)
+	}
+	if got := idx.Complete("zz"); got != nil {
+		t.Errorf(`Complete("zz") = %v`, got)
+	}
+
+	// a document added later is found by prefix too - the trie holds the same sets as postings
+	idx.Add(Document{8, "Closures", "A closure captures goroutine state."})
+	if got := sortedIDs(idx.Prefix("clos")); !slices.Equal(got, []DocID{3, 8}) {
+		t.Errorf(`Prefix("clos") after adding a document = %v, want [3 8]`, got)
+	}
+}
+
+func TestSearchWithPrefixes(t *testing.T) {
+	idx := testIndex()
+	for _, c := range []struct {
+		query string
+		want  []DocID // by rank
+	}{
+		{"chan*", []DocID{3, 7, 2}},
+		{"go* value", []DocID{2, 1, 6}}, // a prefix and a word: both must match
+		{"mut* OR sel*", []DocID{4, 7}}, // prefixes on either side of OR
+		{"chan* go*", []DocID{2}},       // two prefixes in one clause
+		{"zz*", []DocID{}},              // a prefix matching no term
+		{"zz* OR mutex", []DocID{4}},
+	} {
+		if got := resultIDs(idx.Search(c.query)); !slices.Equal(got, c.want) {
+			t.Errorf("Search(%q) = %v, want %v", c.query, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package shorturl
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// A code is the short part of a short URL: /Ab3xY9q. Random rather than counting up (1, 2, 3...),
+// so the codes don't tell anyone how many links there are, or let them guess the next one
+
+const (
+	alphabet   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	codeLength = 7 // 62^7 is about 3.5 trillion codes
+)
+
+// NewCode is a random code of codeLength characters from alphabet, from crypto/rand.
+// A random byte is 0-255, and 256 isn't a multiple of 62: byte%62 would make the first 8 characters
+// more likely than the rest. So bytes from 248 (62*4) up are thrown away and another one is read instead
+func NewCode() string {
+	code := make([]byte, 0, codeLength)
+	var buf [codeLength * 2]byte
+	for len(code) < codeLength {
+		rand.Read(buf[:]) // never fails (crypto/rand panics instead, if it can't read)
+		for _, b := range buf {
+			if b < 248 && len(code) < codeLength {
+				code = append(code, alphabet[b%62])
+			}
+		}
+	}
+	return string(code)
+}
+
+var ErrBadCode = errors.New("a code is 3 to 32 letters, digits, - or _")
+
+// CheckCode is for codes a user picks (a custom alias): short enough to be a short URL, only
+// characters that need no escaping in a path, and not a path the server has a route of its own for
+func CheckCode(code string) error {
+	if reservedCodes[code] {
+		return fmt.Errorf("%w, and not %q (a path the server uses)", ErrBadCode, code)
+	}
+	if len(code) < 3 || len(code) > 32 {
+		return fmt.Errorf("%w, got %d characters", ErrBadCode, len(code))
+	}
+	for _, c := range code {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_':
+		default:
+			return fmt.Errorf("%w, got %q", ErrBadCode, c)
+		}
+	}
+	return nil
+}
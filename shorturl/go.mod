@@ -0,0 +1,3 @@
+module shorturl
+
+go 1.25.0
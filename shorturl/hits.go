@@ -0,0 +1,28 @@
+package shorturl
+
+import "sync"
+
+// Hits counts redirects per code - the concurrency notes' SafeCounter: a map behind a mutex, since every
+// redirect is a write and they arrive on many goroutines at once
+type Hits struct {
+	mu sync.RWMutex
+	v  map[string]int
+}
+
+func NewHits() *Hits {
+	return &Hits{v: make(map[string]int)}
+}
+
+// Inc adds one to code's count, and returns the new count
+func (h *Hits) Inc(code string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.v[code]++
+	return h.v[code]
+}
+
+func (h *Hits) Get(code string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.v[code]
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"shorturl"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// === Ex. a URL shortener: the generics, concurrency, errors and HTTP notes in one app ===
+
+// Each example runs the app on an httptest.Server, and talks to it with an http.Client - as a user would
+
+// counterCodes are codes known in advance - a1, a2... - in place of random ones
+func counterCodes() func() string {
+	n := 0
+	return func() string {
+		n++
+		return fmt.Sprintf("a%d", n)
+	}
+}
+
+// noRedirects is a client that returns a redirect as it is, instead of following it
+var noRedirects = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+// call does one request, and decodes a JSON body into v (if v isn't nil)
+func call(c *http.Client, method, url, body string, v any) *http.Response {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer res.Body.Close()
+	if v != nil {
+		json.NewDecoder(res.Body).Decode(v)
+	}
+	io.Copy(io.Discard, res.Body)
+	return res
+}
+
+func main() {
+	shortenExample()
+	// listLinksExample()
+	// hitCountersExample()
+	// linkErrorsExample()
+	// codesExample()
+}
+
+func shortenExample() {
+	// The site links point to
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "the Go tour, at ", r.URL.Path)
+	}))
+	defer target.Close()
+
+	logger := log.New(os.Stdout, "  log: ", 0)
+	app := shorturl.New(shorturl.NewStore[string, shorturl.Link]()).WithCodes(counterCodes())
+	srv := httptest.NewServer(shorturl.Chain(app, shorturl.Logging(logger)))
+	defer srv.Close()
+
+	var link shorturl.Link
+	res := call(http.DefaultClient, "POST", srv.URL+"/links", `{"url": "`+target.URL+`/welcome/1"}`, &link)
+	fmt.Println("POST /links:", res.Status, "| code", link.Code, "| Location", res.Header.Get("Location"))
+
+	// The short URL: a 302 to the long one, with a Location header...
+	res = call(noRedirects, "GET", srv.URL+"/"+link.Code, "", nil)
+	fmt.Println("GET /"+link.Code+":", res.Status, "->", strings.TrimPrefix(res.Header.Get("Location"), target.URL))
+	// ...which an ordinary client follows by itself
+	res, _ = http.Get(srv.URL + "/" + link.Code)
+	page, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	fmt.Printf("followed: %s %q\n", res.Status, page)
+
+	var got shorturl.Link
+	call(http.DefaultClient, "GET", srv.URL+"/links/"+link.Code, "", &got)
+	fmt.Println("| ok: two visits counted:", got.Hits == 2 && app.Hits().Get(link.Code) == 2, "| url kept:", got.URL == link.URL)
+}
+
+func listLinksExample() {
+	app := shorturl.New(shorturl.NewStore[string, shorturl.Link]()).WithCodes(counterCodes())
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	for i := range 25 {
+		call(http.DefaultClient, "POST", srv.URL+"/links", fmt.Sprintf(`{"url": "https://go.dev/tour/basics/%d"}`, i+1), nil)
+	}
+
+	// The list is PaginatedResDto pages - the same JSON as the generics notes' users
+	var first shorturl.PaginatedResDto[shorturl.Link]
+	call(http.DefaultClient, "GET", srv.URL+"/links?perPage=10", "", &first)
+	fmt.Printf("page %d of %d (%d links): %s .. %s | next %d, prev %v\n", first.CurrPage, first.TotalPages, first.TotalItems,
+		first.Data[0].Code, first.Data[len(first.Data)-1].Code, *first.NextPage, first.PrevPage)
+
+	// Following nextPage to the end gets every link exactly once, oldest first
+	var codes []string
+	for p := 1; ; {
+		var pg shorturl.PaginatedResDto[shorturl.Link]
+		call(http.DefaultClient, "GET", fmt.Sprintf("%s/links?page=%d&perPage=10", srv.URL, p), "", &pg)
+		for _, l := range pg.Data {
+			codes = append(codes, l.Code)
+		}
+		if pg.NextPage == nil {
+			break
+		}
+		p = *pg.NextPage
+	}
+	fmt.Println("| ok: 3 pages, 25 links in order:", len(codes) == 25 && codes[0] == "a1" && codes[24] == "a25")
+
+	for _, q := range []string{"page=4&perPage=10", "perPage=0", "page=x"} {
+		var e struct{ Error string }
+		res := call(http.DefaultClient, "GET", srv.URL+"/links?"+q, "", &e)
+		fmt.Printf("GET /links?%-18s %s %s\n", q, res.Status, e.Error)
+	}
+}
+
+func hitCountersExample() {
+	app := shorturl.New(shorturl.NewStore[string, shorturl.Link]()).WithCodes(counterCodes())
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	for _, u := range []string{"https://go.dev/tour", "https://go.dev/doc", "https://go.dev/blog"} {
+		call(http.DefaultClient, "POST", srv.URL+"/links", `{"url": "`+u+`"}`, nil)
+	}
+
+	// 20 visitors at once, 30 visits each, spread over the three links: each request on its own goroutine
+	// in the server, all incrementing the same map
+	want := map[string]int{}
+	var wg sync.WaitGroup
+	for v := range 20 {
+		code := fmt.Sprintf("a%d", v%3+1)
+		want[code] += 30
+		wg.Go(func() {
+			for range 30 {
+				call(noRedirects, "GET", srv.URL+"/"+code, "", nil)
+			}
+		})
+	}
+	wg.Wait()
+
+	var page shorturl.PaginatedResDto[shorturl.Link]
+	call(http.DefaultClient, "GET", srv.URL+"/links", "", &page)
+	got := map[string]int{}
+	for _, l := range page.Data {
+		got[l.Code] = l.Hits
+		fmt.Printf("%s %-20s %d hits\n", l.Code, l.URL, l.Hits)
+	}
+	fmt.Println("| ok: 600 visits, none lost:", maps.Equal(got, want))
+	// (with a plain map and no mutex, go run -race reports the counter - and the counts come out short)
+}
+
+func linkErrorsExample() {
+	app := shorturl.New(shorturl.NewStore[string, shorturl.Link]())
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	call(http.DefaultClient, "POST", srv.URL+"/links", `{"url": "https://go.dev/tour", "code": "tour"}`, nil)
+
+	for _, tc := range []struct{ method, path, body string }{
+		{"POST", "/links", `{"url": "https://go.dev/doc", "code": "docs"}`},
+		{"POST", "/links", `{"url": "https://go.dev/blog", "code": "tour"}`},
+		{"POST", "/links", `{"url": "javascript:alert(1)"}`},
+		{"POST", "/links", `{"url": "/admin"}`},
+		{"POST", "/links", `{"url": "https://go.dev", "code": "a/b"}`},
+		{"POST", "/links", `{"url": "https://go.dev", "code": "links"}`},
+		{"POST", "/links", `{"link": "https://go.dev"}`},
+		{"GET", "/nope", ""},
+		{"GET", "/links/nope", ""},
+		{"DELETE", "/links/tour", ""},
+	} {
+		var e struct{ Error string }
+		res := call(noRedirects, tc.method, srv.URL+tc.path, tc.body, &e)
+		fmt.Printf("%-6s %-11s %-26s %s\n", tc.method, tc.path, res.Status, e.Error)
+	}
+	var tour shorturl.Link
+	call(http.DefaultClient, "GET", srv.URL+"/links/tour", "", &tour)
+	fmt.Println("| ok: the taken alias still points where it did:", tour.URL == "https://go.dev/tour")
+}
+
+func codesExample() {
+	codes := map[string]bool{}
+	counts := map[byte]int{}
+	for range 100_000 {
+		c := shorturl.NewCode()
+		codes[c] = true
+		for i := range len(c) {
+			counts[c[i]]++
+		}
+	}
+	fmt.Println("some codes:", shorturl.NewCode(), shorturl.NewCode(), shorturl.NewCode())
+	fmt.Println("| ok: 100000 codes, all different:", len(codes) == 100_000)
+
+	// Each of the 62 characters about equally often - 700000 characters / 62 is about 11290
+	lo, hi := slices.Min(slices.Collect(maps.Values(counts))), slices.Max(slices.Collect(maps.Values(counts)))
+	fmt.Printf("characters used: %d | least common %d, most %d times\n", len(counts), lo, hi)
+	// With byte%62 and no rejection, a-h (the values 248-255 wrap onto) would each come up about 5/4 as often
+
+	// A generator that keeps repeating itself: after maxCodeAttempts collisions the server gives up with a 500
+	app := shorturl.New(shorturl.NewStore[string, shorturl.Link]()).WithCodes(func() string { return "same" })
+	srv := httptest.NewServer(app)
+	defer srv.Close()
+	first := call(http.DefaultClient, "POST", srv.URL+"/links", `{"url": "https://go.dev"}`, nil)
+	var e struct{ Error string }
+	second := call(http.DefaultClient, "POST", srv.URL+"/links", `{"url": "https://go.dev/doc"}`, &e)
+	fmt.Println("stuck generator:", first.Status, "then", second.Status, e.Error)
+}
@@ -0,0 +1,52 @@
+package shorturl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PaginatedResDto and Paginate are the generics notes' (generics.go, paginate.go): one page of a list
+
+type PaginatedResDto[T any] struct {
+	TotalItems   int  `json:"totalItems"`
+	TotalPages   int  `json:"totalPages"`
+	CurrPage     int  `json:"currPage"`
+	ItemsPerPage int  `json:"itemsPerPage"`
+	NextPage     *int `json:"nextPage"`
+	PrevPage     *int `json:"prevPage"`
+	Data         []T  `json:"data"`
+}
+
+var (
+	ErrInvalidPerPage = errors.New("perPage must be at least 1")
+	ErrPageOutOfRange = errors.New("page out of range")
+)
+
+// Paginate returns page number page (counting from 1) of items, perPage items to a page
+func Paginate[T any](items []T, page, perPage int) (PaginatedResDto[T], error) {
+	if perPage < 1 {
+		return PaginatedResDto[T]{}, fmt.Errorf("%w, got %d", ErrInvalidPerPage, perPage)
+	}
+	totalPages := max((len(items)+perPage-1)/perPage, 1)
+	if page < 1 || page > totalPages {
+		return PaginatedResDto[T]{}, fmt.Errorf("%w: page %d of %d", ErrPageOutOfRange, page, totalPages)
+	}
+	start := (page - 1) * perPage
+	end := min(start+perPage, len(items))
+	res := PaginatedResDto[T]{
+		TotalItems:   len(items),
+		TotalPages:   totalPages,
+		CurrPage:     page,
+		ItemsPerPage: perPage,
+		Data:         append(make([]T, 0, end-start), items[start:end]...),
+	}
+	if page < totalPages {
+		next := page + 1
+		res.NextPage = &next
+	}
+	if page > 1 {
+		prev := page - 1
+		res.PrevPage = &prev
+	}
+	return res, nil
+}
@@ -0,0 +1,216 @@
+package shorturl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A URL shortener: the notes' pieces put together in one small app.
+//
+//	POST /links                {"url": "https://...", "code": "optional-alias"} -> 201 with the Link, 400, or 409
+//	GET  /links?page=&perPage= -> 200 PaginatedResDto[Link], newest last
+//	GET  /links/{code}         -> 200 the Link with its hit count, or 404
+//	GET  /{code}               -> 302 to the Link's URL (counting the hit), or 404
+//
+// Which notes each part comes from:
+// - Store[K, V] (store.go) is a generic type (generics), with a sync.RWMutex around its map (concurrency)
+// - Hits (hits.go) is the concurrency notes' SafeCounter
+// - codes (code.go) are from crypto/rand, with the modulo bias the cryptopkg notes warn about avoided
+// - the handlers, writeJSON and the ServeMux patterns are the httpserver notes', and so is the Logging middleware
+// - lists are PaginatedResDto pages (generics), json tags and all (jsonpkg)
+// - errors are sentinels matched with errors.Is (errorsdeep), turned into a status in one place
+//
+//	srv := shorturl.New(shorturl.NewStore[string, shorturl.Link]())
+//	http.ListenAndServe(":8080", shorturl.Chain(srv, shorturl.Logging(logger)))
+
+// Link is a short code and the URL it stands for
+type Link struct {
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+	Hits      int       `json:"hits"` // filled in from Hits when the Link is sent - the Store's copy stays 0
+}
+
+var ErrBadURL = errors.New("url must be an absolute http or https URL")
+
+// CheckURL accepts only absolute http(s) URLs: a redirect to javascript: or to a relative path
+// on this server isn't a link anyone meant to shorten
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrBadURL
+	}
+	return nil
+}
+
+// Server is the app's handler
+type Server struct {
+	links   *Store[string, Link]
+	hits    *Hits
+	mux     *http.ServeMux
+	newCode func() string
+	now     func() time.Time
+}
+
+// reservedCodes are the first path segments of the routes below other than /{code}. A link with one of
+// these as its code could never be followed - GET /links is listLinks, not a redirect - so CheckCode
+// refuses them. A new route goes in both places
+var reservedCodes = map[string]bool{"links": true}
+
+func New(links *Store[string, Link]) *Server {
+	s := &Server{links: links, hits: NewHits(), mux: http.NewServeMux(), newCode: NewCode, now: time.Now}
+	s.mux.HandleFunc("POST /links", s.createLink)
+	s.mux.HandleFunc("GET /links", s.listLinks)
+	s.mux.HandleFunc("GET /links/{code}", s.getLink)
+	s.mux.HandleFunc("GET /{code}", s.redirect) // the more specific /links patterns win over this one
+	return s
+}
+
+// WithCodes replaces the code generator - ex. with a counter, so codes are known in advance
+func (s *Server) WithCodes(newCode func() string) *Server {
+	s.newCode = newCode
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Hits is the server's hit counter, for reading the counts
+func (s *Server) Hits() *Hits { return s.hits }
+
+const maxBodyBytes = 64 << 10
+
+type createLinkRequest struct {
+	URL  string `json:"url"`
+	Code string `json:"code,omitempty"`
+}
+
+// maxCodeAttempts is how many random codes createLink tries before giving up. With billions of codes a
+// collision is rare, and several in a row means something's wrong (a broken generator), not bad luck
+const maxCodeAttempts = 5
+
+var errNoFreeCode = errors.New("could not find a free code")
+
+func (s *Server) createLink(w http.ResponseWriter, r *http.Request) {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	var req createLinkRequest
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if err := CheckURL(req.URL); err != nil {
+		writeError(w, statusOf(err), err.Error())
+		return
+	}
+
+	link := Link{URL: req.URL, CreatedAt: s.now().UTC()}
+	err := errNoFreeCode
+	if req.Code != "" { // an alias the user picked: taken is a 409, not a reason to pick another
+		if err = CheckCode(req.Code); err == nil {
+			link.Code = req.Code
+			if err = s.links.Add(link.Code, link); err != nil {
+				err = fmt.Errorf("code %s %w", link.Code, err)
+			}
+		}
+	} else {
+		for range maxCodeAttempts {
+			link.Code = s.newCode()
+			if err = s.links.Add(link.Code, link); !errors.Is(err, ErrExists) {
+				break
+			}
+		}
+		if errors.Is(err, ErrExists) {
+			err = errNoFreeCode
+		}
+	}
+	if err != nil {
+		writeError(w, statusOf(err), err.Error())
+		return
+	}
+	w.Header().Set("Location", "/links/"+link.Code)
+	writeJSON(w, http.StatusCreated, link)
+}
+
+func (s *Server) listLinks(w http.ResponseWriter, r *http.Request) {
+	page, perPage := 1, 10
+	for name, p := range map[string]*int{"page": &page, "perPage": &perPage} {
+		if v := r.URL.Query().Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, name+" must be a number")
+				return
+			}
+			*p = n
+		}
+	}
+	links := s.links.Values()
+	for i := range links {
+		links[i].Hits = s.hits.Get(links[i].Code)
+	}
+	res, err := Paginate(links, page, perPage)
+	if err != nil {
+		writeError(w, statusOf(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getLink(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	link, ok := s.links.Get(code)
+	if !ok {
+		writeError(w, http.StatusNotFound, "no link with code "+code)
+		return
+	}
+	link.Hits = s.hits.Get(code)
+	writeJSON(w, http.StatusOK, link)
+}
+
+// redirect sends a 302 (Found) rather than a 301 (Moved Permanently): browsers cache a 301 and stop
+// asking - so every visit after the first would go uncounted
+func (s *Server) redirect(w http.ResponseWriter, r *http.Request) {
+	link, ok := s.links.Get(r.PathValue("code"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no link with code "+r.PathValue("code"))
+		return
+	}
+	s.hits.Inc(link.Code)
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
+
+// statusOf is the one place errors become statuses
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrBadURL), errors.Is(err, ErrBadCode), errors.Is(err, ErrInvalidPerPage):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrPageOutOfRange):
+		return http.StatusNotFound
+	case errors.Is(err, ErrExists):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// writeJSON sends v as the response, headers first
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
@@ -0,0 +1,66 @@
+package shorturl
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrExists is Add's error for a key that's already in the Store
+var ErrExists = errors.New("already exists")
+
+// Store is a generic in-memory store (the generics notes): values by key, in the order they were added -
+// so a list of them pages the same way every time. Safe for concurrent use: handlers run in goroutines
+type Store[K comparable, V any] struct {
+	mu    sync.RWMutex
+	byKey map[K]V
+	order []K
+}
+
+func NewStore[K comparable, V any]() *Store[K, V] {
+	return &Store[K, V]{byKey: make(map[K]V)}
+}
+
+// Add stores v under k, unless k is taken - checking and adding under one lock, so two goroutines
+// adding the same key can't both succeed
+func (s *Store[K, V]) Add(k K, v V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.byKey[k]; ok {
+		return ErrExists
+	}
+	s.byKey[k] = v
+	s.order = append(s.order, k)
+	return nil
+}
+
+func (s *Store[K, V]) Get(k K) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.byKey[k]
+	return v, ok
+}
+
+func (s *Store[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.order)
+}
+
+// Values are all the values, oldest first - a new slice, so the caller can't change the Store's
+func (s *Store[K, V]) Values() []V {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	vs := make([]V, 0, len(s.order))
+	for _, k := range s.order {
+		vs = append(vs, s.byKey[k])
+	}
+	return vs
+}
+
+// Keys are the keys, oldest first
+func (s *Store[K, V]) Keys() []K {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.order)
+}
@@ -0,0 +1,3 @@
+module sortpkg
+
+go 1.25.0
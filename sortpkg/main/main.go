@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sortpkg"
+)
+
+// === Ex. sorting []User: sort.Slice, slices.SortFunc, binary search, stability ===
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func sampleUsers() []sortpkg.User {
+	return []sortpkg.User{
+		{UserId: userId2, Name: "Jack Eod"},
+		{UserId: "5b1f0c3a-9e2d-4f11-8a7b-0c6d2e9f4a10", Name: "ada Lovelace"},
+		{UserId: userId1, Name: "John Doe"},
+		{UserId: "0f3e2d1c-4b5a-4c69-8d7e-1f2a3b4c5d6e", Name: "Grace Hopper"},
+		{UserId: "c4d5e6f7-0a1b-4c2d-9e3f-405162738495", Name: "John Doe"}, // a second John Doe
+	}
+}
+
+func names(users []sortpkg.User) []string {
+	var ns []string
+	for _, u := range users {
+		ns = append(ns, u.Name)
+	}
+	return ns
+}
+
+func main() {
+	sortUsersExample()
+	// binarySearchExample()
+	// stabilityExample()
+}
+
+func sortUsersExample() {
+	// sort.Slice: a less func over indexes, into the slice the closure captured
+	users := sampleUsers()
+	sort.Slice(users, func(i, j int) bool { return users[i].UserId < users[j].UserId })
+	fmt.Println("sort.Slice by id:     ", names(users))
+
+	// slices.SortFunc: a cmp func over the elements - no indexes, no captured slice to get wrong
+	users = sampleUsers()
+	slices.SortFunc(users, sortpkg.ByID)
+	fmt.Println("slices.SortFunc by id:", names(users))
+
+	// By name: "ada" would sort after every capital with a plain <, ByName ignores case
+	users = sampleUsers()
+	slices.SortFunc(users, sortpkg.ByName)
+	fmt.Println("by name:", names(users))
+	plain := sampleUsers()
+	slices.SortFunc(plain, func(a, b sortpkg.User) int {
+		if a.Name < b.Name {
+			return -1
+		} else if a.Name > b.Name {
+			return 1
+		}
+		return 0
+	})
+	fmt.Println("by name with <, case-sensitive:", names(plain))
+
+	// Two keys: name, then id - the two John Does always come out the same way round
+	users = sampleUsers()
+	slices.SortFunc(users, sortpkg.ByNameThenID)
+	fmt.Println("by name, then id:", names(users), "| the John Does:", users[3].UserId[:8], users[4].UserId[:8])
+
+	// Descending: swap the arguments
+	slices.SortFunc(users, func(a, b sortpkg.User) int { return sortpkg.ByName(b, a) })
+	fmt.Println("by name, descending:", names(users))
+}
+
+func binarySearchExample() {
+	users := sampleUsers()
+	slices.SortFunc(users, sortpkg.ByID) // binary search needs the slice sorted by what it searches on
+
+	u, ok := sortpkg.FindByID(users, userId1)
+	fmt.Println("FindByID(userId1):", u, ok)
+	_, ok = sortpkg.FindByID(users, "00000000-0000-0000-0000-000000000000")
+	fmt.Println("FindByID(unknown):", ok)
+
+	// sort.Search is the older one: the smallest index where the func turns true
+	i := sort.Search(len(users), func(i int) bool { return users[i].UserId >= userId2 })
+	fmt.Println("sort.Search for userId2: index", i, "->", users[i].Name)
+
+	// Searching a slice that ISN'T sorted by the key gives wrong answers, not an error
+	byName := sampleUsers()
+	slices.SortFunc(byName, sortpkg.ByName)
+	missed := 0
+	for _, u := range byName {
+		if _, found := sortpkg.FindByID(byName, u.UserId); !found {
+			missed++
+		}
+	}
+	fmt.Printf("FindByID on a slice sorted by name: %d of %d users not found (they're there - the search looked in the wrong half)\n", missed, len(byName))
+
+	// Inserting in order keeps the slice searchable
+	sorted := []sortpkg.User{}
+	for _, u := range sampleUsers() {
+		sorted = sortpkg.InsertSorted(sorted, u)
+	}
+	sorted = sortpkg.InsertSorted(sorted, sortpkg.User{UserId: userId1, Name: "John Doe (renamed)"})
+	u, _ = sortpkg.FindByID(sorted, userId1)
+	fmt.Println("built with InsertSorted:", len(sorted), "users | a repeated id replaced:", u.Name)
+	// sortpkg_test.go checks every user is found, misses, and InsertSorted against sorting at the end
+}
+
+// manyUsers is n users in id order, with only 3 different names - so most names tie
+func manyUsers(n int) []sortpkg.User {
+	names := []string{"John Doe", "Jack Eod", "Ada Lovelace"}
+	users := make([]sortpkg.User, n)
+	for i := range users {
+		users[i] = sortpkg.User{UserId: fmt.Sprintf("%08x-0000-4000-8000-000000000000", i), Name: names[(i*7)%3]}
+	}
+	return users
+}
+
+// tiesInIDOrder reports whether users with the same name are still in id order
+func tiesInIDOrder(users []sortpkg.User) bool {
+	for i := 1; i < len(users); i++ {
+		if sortpkg.ByName(users[i-1], users[i]) == 0 && users[i-1].UserId > users[i].UserId {
+			return false
+		}
+	}
+	return true
+}
+
+func stabilityExample() {
+	// Every sort below is by name only, on users already in id order. A stable sort keeps the id order
+	// within each name; an unstable one only promises the names are in order.
+	// (Short slices are insertion sorted, which happens to be stable - ties are only shuffled from 13 elements on)
+	for _, n := range []int{6, 50} {
+		unstable, stable := manyUsers(n), manyUsers(n)
+		slices.SortFunc(unstable, sortpkg.ByName)
+		slices.SortStableFunc(stable, sortpkg.ByName)
+
+		users := manyUsers(n)
+		sort.SliceStable(users, func(i, j int) bool { return sortpkg.ByName(users[i], users[j]) < 0 })
+		fmt.Printf("%d users: SortFunc kept ties in id order: %-5t | SortStableFunc: %t | sort.SliceStable: %t\n",
+			n, tiesInIDOrder(unstable), tiesInIDOrder(stable), tiesInIDOrder(users))
+	}
+
+	// Sorting by a second key: a stable sort by the first key, after sorting by the second -
+	// or one unstable sort with both keys in the cmp func (ByNameThenID), which gives the same order
+	twoPasses := manyUsers(50)
+	slices.Reverse(twoPasses) // out of id order to start
+	slices.SortFunc(twoPasses, sortpkg.ByID)
+	slices.SortStableFunc(twoPasses, sortpkg.ByName)
+	onePass := manyUsers(50)
+	slices.Reverse(onePass)
+	slices.SortFunc(onePass, sortpkg.ByNameThenID)
+	fmt.Println("stable sort by name after id == one sort by name then id:", slices.Equal(twoPasses, onePass))
+}
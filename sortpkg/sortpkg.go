@@ -0,0 +1,48 @@
+package sortpkg
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// methodsinterfaces/sorting.go has the three ways to sort (sort.Interface, sort.Slice, slices.SortFunc)
+// on a []Person. Here they're put to work on the basics notes' []User: sorting by a key, by two keys,
+// and finding a user in a sorted slice with a binary search.
+
+// User is the basics notes' User
+type User struct {
+	UserId string
+	Name   string
+}
+
+// ByName orders users by name, ignoring case. Users with the same name compare equal
+func ByName(a, b User) int { return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+
+// ByID orders users by id. Ids are unique, so no two users compare equal
+func ByID(a, b User) int { return strings.Compare(a.UserId, b.UserId) }
+
+// ByNameThenID orders by name, and users with the same name by id - a total order, so even an
+// unstable sort gives one answer. cmp.Or returns the first comparison that isn't 0
+func ByNameThenID(a, b User) int { return cmp.Or(ByName(a, b), ByID(a, b)) }
+
+// FindByID finds the user with id in users, which must be sorted ByID. O(log n), against a scan's O(n)
+func FindByID(users []User, id string) (User, bool) {
+	// BinarySearchFunc compares an element to the target - here a User to a string, so no User needs making
+	i, found := slices.BinarySearchFunc(users, id, func(u User, id string) int { return strings.Compare(u.UserId, id) })
+	if !found {
+		return User{}, false
+	}
+	return users[i], true
+}
+
+// InsertSorted puts u where it goes in users, sorted ByID - where BinarySearchFunc says it would be.
+// An id already there is replaced rather than added twice
+func InsertSorted(users []User, u User) []User {
+	i, found := slices.BinarySearchFunc(users, u, ByID)
+	if found {
+		users[i] = u
+		return users
+	}
+	return slices.Insert(users, i, u)
+}
@@ -0,0 +1,179 @@
+package sortpkg
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+)
+
+const userId1 = "1d02455e-f24c-4c26-90d2-f1073c686314"
+const userId2 = "96aeb270-dd19-4274-a2fe-30415644864b"
+
+func sampleUsers() []User {
+	return []User{
+		{UserId: userId2, Name: "Jack Eod"},
+		{UserId: "5b1f0c3a-9e2d-4f11-8a7b-0c6d2e9f4a10", Name: "ada Lovelace"},
+		{UserId: userId1, Name: "John Doe"},
+		{UserId: "0f3e2d1c-4b5a-4c69-8d7e-1f2a3b4c5d6e", Name: "Grace Hopper"},
+		{UserId: "c4d5e6f7-0a1b-4c2d-9e3f-405162738495", Name: "John Doe"}, // a second John Doe
+	}
+}
+
+func names(users []User) []string {
+	var ns []string
+	for _, u := range users {
+		ns = append(ns, u.Name)
+	}
+	return ns
+}
+
+func ids(users []User) []string {
+	var is []string
+	for _, u := range users {
+		is = append(is, u.UserId)
+	}
+	return is
+}
+
+func TestByID(t *testing.T) {
+	users := sampleUsers()
+	slices.SortFunc(users, ByID)
+	ref := sampleUsers()
+	sort.Slice(ref, func(i, j int) bool { return ref[i].UserId < ref[j].UserId })
+	if !slices.Equal(users, ref) {
+		t.Errorf("SortFunc(ByID) = %v, want sort.Slice's %v", ids(users), ids(ref))
+	}
+}
+
+func TestByName(t *testing.T) {
+	users := sampleUsers()
+	slices.SortFunc(users, ByName)
+	if want := []string{"ada Lovelace", "Grace Hopper", "Jack Eod", "John Doe", "John Doe"}; !slices.Equal(names(users), want) {
+		t.Errorf("by name: %v, want %v - case ignored", names(users), want)
+	}
+	if ByName(User{Name: "ADA"}, User{Name: "ada"}) != 0 {
+		t.Error("ByName isn't case-insensitive")
+	}
+}
+
+// ByNameThenID is a total order: whatever order the users start in, one sort gives one answer
+func TestByNameThenID(t *testing.T) {
+	want := sampleUsers()
+	slices.SortFunc(want, ByNameThenID)
+	if want[3].UserId != userId1 || want[4].UserId != "c4d5e6f7-0a1b-4c2d-9e3f-405162738495" {
+		t.Errorf("the John Does aren't in id order: %v", ids(want[3:]))
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 50 {
+		users := sampleUsers()
+		r.Shuffle(len(users), func(i, j int) { users[i], users[j] = users[j], users[i] })
+		slices.SortFunc(users, ByNameThenID)
+		if !slices.Equal(users, want) {
+			t.Fatalf("from another start: %v, want %v", ids(users), ids(want))
+		}
+	}
+
+	desc := slices.Clone(want)
+	slices.SortFunc(desc, func(a, b User) int { return ByNameThenID(b, a) })
+	slices.Reverse(desc)
+	if !slices.Equal(desc, want) {
+		t.Error("swapping the arguments doesn't give the reverse order")
+	}
+}
+
+func TestFindByID(t *testing.T) {
+	users := sampleUsers()
+	slices.SortFunc(users, ByID)
+	for _, want := range sampleUsers() {
+		if got, found := FindByID(users, want.UserId); !found || got != want {
+			t.Errorf("FindByID(%s) = %v, %t, want %v", want.UserId, got, found, want)
+		}
+	}
+	for _, id := range []string{"00000000-0000-0000-0000-000000000000", "ffffffff-0000-0000-0000-000000000000", "5b1f", ""} {
+		if u, found := FindByID(users, id); found || u != (User{}) {
+			t.Errorf("FindByID(%q) = %v, %t, want not found", id, u, found)
+		}
+	}
+	if _, found := FindByID(nil, userId1); found {
+		t.Error("found a user in an empty slice")
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	var users []User
+	for _, u := range sampleUsers() {
+		users = InsertSorted(users, u)
+		if !slices.IsSortedFunc(users, ByID) {
+			t.Fatalf("after inserting %s: %v isn't sorted by id", u.UserId, ids(users))
+		}
+	}
+	users = InsertSorted(users, User{UserId: userId1, Name: "John Doe (renamed)"})
+	if u, _ := FindByID(users, userId1); len(users) != 5 || u.Name != "John Doe (renamed)" {
+		t.Errorf("a repeated id: %d users, %v - want it replaced, not added", len(users), u)
+	}
+
+	// at random, against sorting everything at the end
+	r := rand.New(rand.NewPCG(3, 4))
+	users = nil
+	all := map[string]User{}
+	for i := range 500 {
+		u := User{UserId: fmt.Sprintf("%04d", r.IntN(200)), Name: fmt.Sprint(i)}
+		users = InsertSorted(users, u)
+		all[u.UserId] = u
+	}
+	var want []User
+	for _, u := range all {
+		want = append(want, u)
+	}
+	slices.SortFunc(want, ByID)
+	if !slices.Equal(users, want) {
+		t.Errorf("InsertSorted built %d users, want the %d distinct ids, latest name each, sorted", len(users), len(want))
+	}
+}
+
+// manyUsers is n users in id order, with only 3 different names - so most names tie
+func manyUsers(n int) []User {
+	names := []string{"John Doe", "Jack Eod", "Ada Lovelace"}
+	users := make([]User, n)
+	for i := range users {
+		users[i] = User{UserId: fmt.Sprintf("%08x-0000-4000-8000-000000000000", i), Name: names[(i*7)%3]}
+	}
+	return users
+}
+
+func TestStableSorts(t *testing.T) {
+	for _, n := range []int{6, 50, 1000} {
+		want := manyUsers(n)
+		slices.SortFunc(want, ByNameThenID) // the users were in id order, so a stable sort by name gives this
+
+		stable := manyUsers(n)
+		slices.SortStableFunc(stable, ByName)
+		ref := manyUsers(n)
+		sort.SliceStable(ref, func(i, j int) bool { return ByName(ref[i], ref[j]) < 0 })
+		if !slices.Equal(stable, want) || !slices.Equal(ref, want) {
+			t.Errorf("%d users: a stable sort by name didn't keep the ties in id order", n)
+		}
+
+		unstable := manyUsers(n)
+		slices.SortFunc(unstable, ByName) // the names are in order, the ties in any order
+		if !slices.IsSortedFunc(unstable, ByName) || !slices.Equal(names(unstable), names(want)) {
+			t.Errorf("%d users: SortFunc by name isn't sorted by name", n)
+		}
+	}
+}
+
+// Sorting by a second key: a stable sort by the first key after sorting by the second is one sort with both
+func TestTwoPassesIsOneSortWithBothKeys(t *testing.T) {
+	twoPasses := manyUsers(50)
+	slices.Reverse(twoPasses)
+	slices.SortFunc(twoPasses, ByID)
+	slices.SortStableFunc(twoPasses, ByName)
+	onePass := manyUsers(50)
+	slices.Reverse(onePass)
+	slices.SortFunc(onePass, ByNameThenID)
+	if !slices.Equal(twoPasses, onePass) {
+		t.Error("stable sort by name after id isn't the same as one sort by name then id")
+	}
+}
@@ -0,0 +1,3 @@
+module strconvpkg
+
+go 1.25.0
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strconvpkg"
+)
+
+// === Ex. strconv: parsing with bases and bit sizes, formatting, quoting, and a range-checked ParseInt ===
+
+// The tables print one row per input - what strconv returned. (the checks are in strconvpkg_test.go)
+
+func main() {
+	parseIntExample()
+	// parseFloatExample()
+	// parseBoolExample()
+	// formatNumbersExample()
+	// quoteExample()
+	// parseIntInRangeExample()
+}
+
+// describe is err's reason in short: ErrSyntax, ErrRange, or the error itself
+func describe(err error) string {
+	switch {
+	case err == nil:
+		return "-"
+	case errors.Is(err, strconv.ErrSyntax):
+		return "ErrSyntax"
+	case errors.Is(err, strconv.ErrRange):
+		return "ErrRange"
+	}
+	return err.Error()
+}
+
+func parseIntExample() {
+	for _, tc := range []struct {
+		s             string
+		base, bitSize int
+	}{
+		{"42", 10, 64},
+		{"-42", 10, 8},
+		{"127", 10, 8},
+		{"128", 10, 8}, // out of range: the nearest value that fits, and an error
+		{"-129", 10, 8},
+		{"ff", 16, 64},
+		{"0xff", 16, 64}, // with an explicit base, no prefix
+		{"0xff", 0, 64},  // base 0: the prefix decides - 0x, 0o (or a leading 0), 0b
+		{"0o17", 0, 64},
+		{"017", 0, 64},
+		{"0b1010", 0, 64},
+		{"1_000_000", 0, 64}, // underscores, as in Go source - base 0 only
+		{"1_000_000", 10, 64},
+		{"z", 36, 64},
+		{" 42", 10, 64}, // no trimming
+		{"", 10, 64},
+		{"9223372036854775808", 10, 64},
+	} {
+		n, err := strconv.ParseInt(tc.s, tc.base, tc.bitSize)
+		fmt.Printf("ParseInt(%-21q, %2d, %2d) = %-20d %s\n", tc.s, tc.base, tc.bitSize, n, describe(err))
+	}
+
+	// The error is a *strconv.NumError, and says what was being parsed
+	_, err := strconv.ParseInt("128", 10, 8)
+	fmt.Println(err)
+
+	// ParseUint has no sign, and Atoi is ParseInt(s, 10, 0) converted to int
+	_, errMinus := strconv.ParseUint("-1", 10, 64)
+	atoi, _ := strconv.Atoi("42")
+	fmt.Println("ParseUint(\"-1\"):", describe(errMinus), "| Atoi(\"42\"):", atoi)
+}
+
+func parseFloatExample() {
+	for _, s := range []string{"3.14", "1e10", "-0", "0x1p-2", "inf", "-Infinity", "NaN", "1e400", "1e-400", "3.14abc"} {
+		f, err := strconv.ParseFloat(s, 64)
+		fmt.Printf("ParseFloat(%-11q, 64) = %-8v %s\n", s, f, describe(err))
+	}
+
+	// bitSize 32 rounds to the nearest float32 - the float64 result then converts to float32 exactly
+	f64, _ := strconv.ParseFloat("0.1", 64)
+	f32, _ := strconv.ParseFloat("0.1", 32)
+	fmt.Println("0.1 as float64:", f64, "| as float32, held in a float64:", f32, "| float32(f32):", float32(f32))
+	// (and in the table: too big is ErrRange with ±Inf; too small for a float64 is 0 and no error - the nearest float)
+}
+
+func parseBoolExample() {
+	// Exactly these: 1 t T TRUE true True, and 0 f F FALSE false False. Not "yes", not "on"
+	for _, s := range []string{"1", "t", "TRUE", "True", "0", "f", "false", "yes", "tRuE", ""} {
+		b, err := strconv.ParseBool(s)
+		fmt.Printf("ParseBool(%-7q) = %-5v %s\n", s, b, describe(err))
+	}
+	fmt.Println("FormatBool(true):", strconv.FormatBool(true))
+}
+
+func formatNumbersExample() {
+	// FormatInt in any base from 2 to 36 - lower case letters, and no prefix
+	fmt.Println("FormatInt(255, 2/8/16/36):", strconv.FormatInt(255, 2), strconv.FormatInt(255, 8),
+		strconv.FormatInt(255, 16), strconv.FormatInt(255, 36), "| Itoa(-7):", strconv.Itoa(-7))
+
+	// FormatFloat(f, fmt, prec, bitSize): fmt 'f' is -ddd.ddd, 'e' -d.ddde±dd, 'g' whichever is shorter,
+	// 'b' and 'x' binary and hex exponents. prec -1 is the fewest digits that parse back to exactly f
+	f := 1234.5678
+	for _, tc := range []struct {
+		fmt  byte
+		prec int
+	}{{'f', 2}, {'f', -1}, {'e', 3}, {'e', -1}, {'g', 3}, {'g', -1}, {'x', -1}} {
+		fmt.Printf("FormatFloat(%v, '%c', %2d, 64) = %s\n", f, tc.fmt, tc.prec, strconv.FormatFloat(f, tc.fmt, tc.prec, 64))
+	}
+
+	// prec -1 round trips; bitSize 32 gives the digits for the float32 nearest f - fewer of them
+	// (variables: 0.1+0.2 in constants is exactly 0.3 - constant arithmetic isn't float64's)
+	a, b := 0.1, 0.2
+	shortest := strconv.FormatFloat(a+b, 'g', -1, 64)
+	back, _ := strconv.ParseFloat(shortest, 64)
+	fmt.Println("0.1+0.2:", shortest, "| parsed back:", back, "| as float32:", strconv.FormatFloat(a+b, 'g', -1, 32))
+
+	// Append* write into a []byte rather than making a string - for building output without allocating
+	buf := []byte("n=")
+	buf = strconv.AppendInt(buf, -42, 10)
+	buf = append(buf, " f="...)
+	buf = strconv.AppendFloat(buf, 2.5, 'f', 1, 64)
+	buf = append(buf, " q="...)
+	buf = strconv.AppendQuote(buf, "hi")
+	fmt.Println(string(buf))
+}
+
+func quoteExample() {
+	s := "Hello, 世界\n\t\"Go\"\x00"
+	fmt.Println("Quote:         ", strconv.Quote(s))
+	fmt.Println("QuoteToASCII:  ", strconv.QuoteToASCII(s)) // non-ASCII as \u escapes
+	fmt.Println("QuoteToGraphic:", strconv.QuoteToGraphic("tab\there"))
+	fmt.Println("QuoteRune:     ", strconv.QuoteRune('☺'), strconv.QuoteRune('\n'), strconv.QuoteRuneToASCII('☺'))
+
+	// Unquote takes any Go literal - "double quoted", `raw`, or a 'rune' - and returns what it stands for
+	for _, lit := range []string{`"a\tb"`, "`a\\tb`", `'x'`, `"世"`, `"unterminated`, `'ab'`, `hello`} {
+		v, err := strconv.Unquote(lit)
+		fmt.Printf("Unquote(%-14s) = %-8q %s\n", lit, v, describe(err))
+	}
+	fmt.Println("CanBackquote(\"a\\tb\"):", strconv.CanBackquote("a\tb"), "| (\"a`b\"):", strconv.CanBackquote("a`b"))
+}
+
+func parseIntInRangeExample() {
+	// ex. a port number from a flag or a form
+	const lo, hi = 1, 65535
+	for _, s := range []string{
+		"8080",
+		" 443\n", // space around it is fine
+		"1",
+		"65535",
+		"0",
+		"65536",
+		"-1",
+		"99999999999999999999", // too big for int64 too - still a RangeError
+		"80a",
+		"8 080",
+		"",
+		"0x50", // base 10 only
+	} {
+		n, err := strconvpkg.ParseIntInRange(s, lo, hi)
+		fmt.Printf("%-24q %-6d %-9s | %v\n", s, n, describe(err), err)
+	}
+
+	// Both kinds of range error are a *RangeError, and the int64 overflow still has strconv's error inside
+	_, errHuge := strconvpkg.ParseIntInRange("99999999999999999999", lo, hi)
+	var rangeErr *strconvpkg.RangeError
+	var numErr *strconv.NumError
+	fmt.Println("the huge one: a *RangeError:", errors.As(errHuge, &rangeErr), "| a *NumError:", errors.As(errHuge, &numErr))
+
+	// Wrapped again by a caller, the checks still work
+	_, err := strconvpkg.ParseIntInRange("80a", lo, hi)
+	err = fmt.Errorf("-port: %w", err)
+	fmt.Println(err, "|", describe(err))
+	_, errEmpty := strconvpkg.ParseIntInRange("5", 10, 1)
+	fmt.Println(errEmpty)
+}
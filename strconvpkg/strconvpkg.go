@@ -0,0 +1,62 @@
+package strconvpkg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// strconv converts between strings and the basic types. Atoi (in methodsinterfaces' error notes) is
+// the shortcut; the rest of the package is the general form of each conversion:
+// - ParseInt(s, base, bitSize), ParseUint, ParseFloat(s, bitSize), ParseBool: string -> value.
+//   bitSize is the type the result has to fit (0 for int, 8 for int8...), even though the result is
+//   always an int64 / uint64 / float64 - converting it to the smaller type afterwards is then safe
+// - FormatInt(i, base), FormatFloat(f, fmt, prec, bitSize), FormatBool: value -> string,
+//   and AppendInt, AppendFloat... doing the same onto a []byte, without allocating a string
+// - Quote, QuoteToASCII, QuoteRune: a Go string literal of a value, and Unquote back
+//
+// A failed Parse returns a *strconv.NumError: which function, the input, and the reason -
+// strconv.ErrSyntax (not a number at all) or strconv.ErrRange (a number, but it doesn't fit).
+// NumError has an Unwrap method, so errors.Is(err, strconv.ErrRange) finds the reason through it.
+// Out of range, the value returned isn't 0 but the nearest one that fits: ParseInt("300", 10, 8) is 127.
+
+// RangeError is a whole number outside the range it had to be in - either the caller's bounds,
+// or the int64 ParseInt itself parses into.
+//
+// It is strconv.ErrRange to errors.Is: the caller's bounds are a range just as int64 is,
+// so one check covers both
+type RangeError struct {
+	Num      string // the input, as given
+	Min, Max int64
+	Err      error // the *strconv.NumError, if the number didn't fit an int64 either
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("%s is out of range [%d, %d]", e.Num, e.Min, e.Max)
+}
+
+func (e *RangeError) Unwrap() error { return e.Err }
+
+func (e *RangeError) Is(target error) bool { return target == strconv.ErrRange }
+
+// ParseIntInRange parses s as a base 10 whole number between lo and hi, inclusive.
+// Space around s is ignored - it's usually someone's input - but anything else that isn't a number is
+// an error wrapping the *strconv.NumError, so errors.Is(err, strconv.ErrSyntax) still says what was wrong.
+// A number outside [lo, hi] is a *RangeError
+func ParseIntInRange(s string, lo, hi int64) (int64, error) {
+	if lo > hi {
+		return 0, fmt.Errorf("ParseIntInRange: empty range [%d, %d]", lo, hi)
+	}
+	num := strings.TrimSpace(s)
+	n, err := strconv.ParseInt(num, 10, 64)
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return 0, &RangeError{Num: num, Min: lo, Max: hi, Err: err}
+	case err != nil:
+		return 0, fmt.Errorf("%q is not a whole number: %w", s, err)
+	case n < lo || n > hi:
+		return 0, &RangeError{Num: num, Min: lo, Max: hi}
+	}
+	return n, nil
+}
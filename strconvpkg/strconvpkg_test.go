@@ -0,0 +1,344 @@
+package strconvpkg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// wantErr checks err against the reason a row expects: nil, strconv.ErrSyntax or strconv.ErrRange
+func wantErr(err, want error) bool {
+	if want == nil {
+		return err == nil
+	}
+	return errors.Is(err, want)
+}
+
+func TestParseIntInRange(t *testing.T) {
+	// ex. a port number from a flag or a form
+	const lo, hi = 1, 65535
+	tests := []struct {
+		s    string
+		want int64
+		err  error
+	}{
+		{"8080", 8080, nil},
+		{" 443\n", 443, nil}, // space around it is fine
+		{"1", 1, nil},
+		{"65535", 65535, nil},
+		{"+80", 80, nil},
+		{"0", 0, strconv.ErrRange},
+		{"65536", 0, strconv.ErrRange},
+		{"-1", 0, strconv.ErrRange},
+		{"99999999999999999999", 0, strconv.ErrRange}, // too big for int64 too - still a RangeError
+		{"-99999999999999999999", 0, strconv.ErrRange},
+		{"80a", 0, strconv.ErrSyntax},
+		{"8 080", 0, strconv.ErrSyntax},
+		{"", 0, strconv.ErrSyntax},
+		{"   ", 0, strconv.ErrSyntax},
+		{"0x50", 0, strconv.ErrSyntax}, // base 10 only
+		{"1_000", 0, strconv.ErrSyntax},
+		{"80.0", 0, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		n, err := ParseIntInRange(tt.s, lo, hi)
+		if n != tt.want || !wantErr(err, tt.err) {
+			t.Errorf("ParseIntInRange(%q) = %d, %v, want %d, %v", tt.s, n, err, tt.want, tt.err)
+		}
+		// Never both: a range error isn't a syntax error, or the other way round
+		if errors.Is(err, strconv.ErrRange) && errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("ParseIntInRange(%q): %v is both ErrRange and ErrSyntax", tt.s, err)
+		}
+	}
+}
+
+func TestParseIntInRangeBounds(t *testing.T) {
+	tests := []struct {
+		s      string
+		lo, hi int64
+		want   int64
+		err    error
+	}{
+		{"-5", -10, -1, -5, nil},
+		{"0", 0, 0, 0, nil}, // a range of one
+		{"1", 0, 0, 0, strconv.ErrRange},
+		{"9223372036854775807", math.MinInt64, math.MaxInt64, math.MaxInt64, nil},
+		{"-9223372036854775808", math.MinInt64, math.MaxInt64, math.MinInt64, nil},
+		{"9223372036854775808", math.MinInt64, math.MaxInt64, 0, strconv.ErrRange},
+	}
+	for _, tt := range tests {
+		n, err := ParseIntInRange(tt.s, tt.lo, tt.hi)
+		if n != tt.want || !wantErr(err, tt.err) {
+			t.Errorf("ParseIntInRange(%q, %d, %d) = %d, %v, want %d, %v", tt.s, tt.lo, tt.hi, n, err, tt.want, tt.err)
+		}
+	}
+
+	// lo > hi is the caller's mistake: neither reason, whatever s is
+	for _, s := range []string{"5", "x"} {
+		if _, err := ParseIntInRange(s, 10, 1); err == nil || errors.Is(err, strconv.ErrRange) || errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("ParseIntInRange(%q, 10, 1) = %v, want an empty range error", s, err)
+		}
+	}
+}
+
+// What's wrapped in the error: a *RangeError for either kind of range, the *strconv.NumError only when
+// ParseInt itself failed - and both still found once a caller wraps the error again
+func TestParseIntInRangeErrors(t *testing.T) {
+	var rangeErr *RangeError
+	var numErr *strconv.NumError
+
+	_, err := ParseIntInRange(" 65536 ", 1, 65535)
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("65536: %v, want a *RangeError", err)
+	}
+	if *rangeErr != (RangeError{Num: "65536", Min: 1, Max: 65535}) {
+		t.Errorf("65536: %+v", *rangeErr)
+	}
+	if errors.As(err, &numErr) {
+		t.Error("65536: ParseInt succeeded, but there's a *NumError")
+	}
+	if want := "65536 is out of range [1, 65535]"; err.Error() != want {
+		t.Errorf("65536: %q, want %q", err, want)
+	}
+
+	_, err = ParseIntInRange("99999999999999999999", 1, 65535)
+	if !errors.As(err, &rangeErr) || !errors.As(err, &numErr) {
+		t.Fatalf("a number too big for int64: %v, want a *RangeError wrapping a *NumError", err)
+	}
+	if numErr.Func != "ParseInt" || numErr.Num != "99999999999999999999" || numErr.Err != strconv.ErrRange {
+		t.Errorf("the *NumError: %+v", numErr)
+	}
+
+	_, err = ParseIntInRange("80a", 1, 65535)
+	if errors.As(err, &rangeErr) {
+		t.Error("80a: a *RangeError")
+	}
+	if !errors.As(err, &numErr) || numErr.Num != "80a" {
+		t.Errorf("80a: %v, want a *NumError for 80a", err)
+	}
+	if want := `"80a" is not a whole number: strconv.ParseInt: parsing "80a": invalid syntax`; err.Error() != want {
+		t.Errorf("80a: %q, want %q", err, want)
+	}
+
+	wrapped := fmt.Errorf("-port: %w", err)
+	if !errors.Is(wrapped, strconv.ErrSyntax) || !errors.As(wrapped, &numErr) {
+		t.Errorf("wrapped again: %v lost what it wraps", wrapped)
+	}
+	_, err = ParseIntInRange("0", 1, 65535)
+	if wrapped := fmt.Errorf("-port: %w", err); !errors.Is(wrapped, strconv.ErrRange) || !errors.As(wrapped, &rangeErr) {
+		t.Errorf("wrapped again: %v lost what it wraps", wrapped)
+	}
+}
+
+// The strconv behavior the notes describe, row by row
+
+func TestParseInt(t *testing.T) {
+	tests := []struct {
+		s             string
+		base, bitSize int
+		want          int64
+		err           error
+	}{
+		{"42", 10, 64, 42, nil},
+		{"-42", 10, 8, -42, nil},
+		{"127", 10, 8, 127, nil},
+		{"128", 10, 8, 127, strconv.ErrRange}, // out of range: the nearest value that fits, and an error
+		{"-129", 10, 8, -128, strconv.ErrRange},
+		{"32768", 10, 16, 32767, strconv.ErrRange},
+		{"ff", 16, 64, 255, nil},
+		{"FF", 16, 64, 255, nil},
+		{"0xff", 16, 64, 0, strconv.ErrSyntax}, // with an explicit base, no prefix
+		{"0xff", 0, 64, 255, nil},              // base 0: the prefix decides - 0x, 0o (or a leading 0), 0b
+		{"0o17", 0, 64, 15, nil},
+		{"017", 0, 64, 15, nil},
+		{"0b1010", 0, 64, 10, nil},
+		{"1_000_000", 0, 64, 1000000, nil}, // underscores, as in Go source - base 0 only
+		{"1_000_000", 10, 64, 0, strconv.ErrSyntax},
+		{"z", 36, 64, 35, nil},
+		{" 42", 10, 64, 0, strconv.ErrSyntax}, // no trimming
+		{"", 10, 64, 0, strconv.ErrSyntax},
+		{"9223372036854775808", 10, 64, math.MaxInt64, strconv.ErrRange},
+	}
+	for _, tt := range tests {
+		n, err := strconv.ParseInt(tt.s, tt.base, tt.bitSize)
+		if n != tt.want || !wantErr(err, tt.err) {
+			t.Errorf("ParseInt(%q, %d, %d) = %d, %v, want %d, %v", tt.s, tt.base, tt.bitSize, n, err, tt.want, tt.err)
+		}
+	}
+
+	_, err := strconv.ParseInt("128", 10, 8)
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || numErr.Func != "ParseInt" || numErr.Num != "128" {
+		t.Errorf("ParseInt(128, 10, 8): %v, want a *NumError naming ParseInt and 128", err)
+	}
+	// An invalid base or bit size is neither reason
+	if _, err := strconv.ParseInt("1", 1, 64); err == nil || errors.Is(err, strconv.ErrSyntax) || errors.Is(err, strconv.ErrRange) {
+		t.Errorf("base 1: %v", err)
+	}
+	if _, err := strconv.ParseUint("-1", 10, 64); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf(`ParseUint("-1") = %v, want ErrSyntax`, err)
+	}
+	if n, err := strconv.Atoi("42"); n != 42 || err != nil {
+		t.Errorf(`Atoi("42") = %d, %v`, n, err)
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	tests := []struct {
+		s    string
+		want float64
+		err  error
+	}{
+		{"3.14", 3.14, nil},
+		{"1e10", 1e10, nil},
+		{"0x1p-2", 0.25, nil},
+		{"inf", math.Inf(1), nil},
+		{"-Infinity", math.Inf(-1), nil},
+		{"1e400", math.Inf(1), strconv.ErrRange}, // too big: ±Inf and ErrRange
+		{"1e-400", 0, nil},                       // too small: the nearest float, 0, and no error
+		{"3.14abc", 0, strconv.ErrSyntax},
+		{"", 0, strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		f, err := strconv.ParseFloat(tt.s, 64)
+		if f != tt.want || !wantErr(err, tt.err) {
+			t.Errorf("ParseFloat(%q, 64) = %v, %v, want %v, %v", tt.s, f, err, tt.want, tt.err)
+		}
+	}
+	if f, err := strconv.ParseFloat("NaN", 64); !math.IsNaN(f) || err != nil {
+		t.Errorf("ParseFloat(NaN) = %v, %v", f, err)
+	}
+	if f, _ := strconv.ParseFloat("-0", 64); f != 0 || !math.Signbit(f) {
+		t.Errorf("ParseFloat(-0) = %v, want negative zero", f)
+	}
+
+	// bitSize 32 rounds to the nearest float32 - the float64 result then converts to float32 exactly
+	f64, _ := strconv.ParseFloat("0.1", 64)
+	f32, _ := strconv.ParseFloat("0.1", 32)
+	if float32(f32) != float32(0.1) || float64(float32(f32)) != f32 || f32 == f64 {
+		t.Errorf("0.1 with bitSize 32 = %v, want float32's 0.1", f32)
+	}
+	if _, err := strconv.ParseFloat("1e39", 32); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("1e39 with bitSize 32 = %v, want ErrRange", err)
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	// Exactly these: 1 t T TRUE true True, and 0 f F FALSE false False
+	for _, s := range []string{"1", "t", "T", "TRUE", "true", "True"} {
+		if b, err := strconv.ParseBool(s); !b || err != nil {
+			t.Errorf("ParseBool(%q) = %t, %v", s, b, err)
+		}
+	}
+	for _, s := range []string{"0", "f", "F", "FALSE", "false", "False"} {
+		if b, err := strconv.ParseBool(s); b || err != nil {
+			t.Errorf("ParseBool(%q) = %t, %v", s, b, err)
+		}
+	}
+	for _, s := range []string{"yes", "on", "tRuE", "", " true"} {
+		if _, err := strconv.ParseBool(s); !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("ParseBool(%q) = %v, want ErrSyntax", s, err)
+		}
+	}
+	if strconv.FormatBool(true) != "true" || strconv.FormatBool(false) != "false" {
+		t.Error("FormatBool")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{strconv.FormatInt(255, 2), "11111111"},
+		{strconv.FormatInt(255, 8), "377"},
+		{strconv.FormatInt(255, 16), "ff"}, // lower case, no prefix
+		{strconv.FormatInt(255, 36), "73"},
+		{strconv.FormatInt(-255, 16), "-ff"},
+		{strconv.FormatUint(math.MaxUint64, 10), "18446744073709551615"},
+		{strconv.Itoa(-7), "-7"},
+		{strconv.FormatFloat(1234.5678, 'f', 2, 64), "1234.57"},
+		{strconv.FormatFloat(1234.5678, 'f', -1, 64), "1234.5678"},
+		{strconv.FormatFloat(1234.5678, 'e', 3, 64), "1.235e+03"},
+		{strconv.FormatFloat(1234.5678, 'e', -1, 64), "1.2345678e+03"},
+		{strconv.FormatFloat(1234.5678, 'g', 3, 64), "1.23e+03"},
+		{strconv.FormatFloat(1234.5678, 'g', -1, 64), "1234.5678"},
+		{strconv.FormatFloat(0.25, 'x', -1, 64), "0x1p-02"},
+		{strconv.FormatFloat(math.Inf(-1), 'g', -1, 64), "-Inf"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	// prec -1 is the fewest digits that parse back to exactly f; bitSize 32, the fewest for the float32
+	a, b := 0.1, 0.2
+	shortest := strconv.FormatFloat(a+b, 'g', -1, 64)
+	if back, _ := strconv.ParseFloat(shortest, 64); shortest != "0.30000000000000004" || back != a+b {
+		t.Errorf("0.1+0.2 = %s, parsed back %v", shortest, back)
+	}
+	if got := strconv.FormatFloat(a+b, 'g', -1, 32); got != "0.3" {
+		t.Errorf("0.1+0.2 with bitSize 32 = %s, want 0.3", got)
+	}
+
+	buf := []byte("n=")
+	buf = strconv.AppendInt(buf, -42, 10)
+	buf = append(buf, " f="...)
+	buf = strconv.AppendFloat(buf, 2.5, 'f', 1, 64)
+	buf = append(buf, " q="...)
+	buf = strconv.AppendQuote(buf, "hi")
+	if want := `n=-42 f=2.5 q="hi"`; string(buf) != want {
+		t.Errorf("Append* = %q, want %q", buf, want)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	s := "Hello, 世界\n\t\"Go\"\x00"
+	tests := []struct {
+		got, want string
+	}{
+		{strconv.Quote(s), `"Hello, 世界\n\t\"Go\"\x00"`},
+		{strconv.QuoteToASCII(s), `"Hello, \u4e16\u754c\n\t\"Go\"\x00"`}, // non-ASCII as \u escapes
+		{strconv.QuoteToGraphic("tab\there"), `"tab\there"`},
+		{strconv.QuoteRune('☺'), `'☺'`},
+		{strconv.QuoteRune('\n'), `'\n'`},
+		{strconv.QuoteRuneToASCII('☺'), `'\u263a'`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %s, want %s", tt.got, tt.want)
+		}
+	}
+	if back, err := strconv.Unquote(strconv.Quote(s)); err != nil || back != s {
+		t.Errorf("Unquote(Quote(s)) = %q, %v", back, err)
+	}
+	if !strconv.CanBackquote("a\tb") || strconv.CanBackquote("a`b") || strconv.CanBackquote("a\nb") {
+		t.Error("CanBackquote")
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	// Any Go literal - "double quoted", `raw`, or a 'rune' - and what it stands for
+	tests := []struct {
+		lit, want string
+		err       error
+	}{
+		{`"a\tb"`, "a\tb", nil},
+		{"`a\\tb`", `a\tb`, nil}, // raw: no escapes
+		{`'x'`, "x", nil},
+		{`"世"`, "世", nil},
+		{`"\u4e16"`, "世", nil},
+		{`"unterminated`, "", strconv.ErrSyntax},
+		{`'ab'`, "", strconv.ErrSyntax}, // a rune literal is one rune
+		{`hello`, "", strconv.ErrSyntax},
+		{`"a"b"`, "", strconv.ErrSyntax},
+	}
+	for _, tt := range tests {
+		v, err := strconv.Unquote(tt.lit)
+		if v != tt.want || !wantErr(err, tt.err) {
+			t.Errorf("Unquote(%s) = %q, %v, want %q, %v", tt.lit, v, err, tt.want, tt.err)
+		}
+	}
+}
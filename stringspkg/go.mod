@@ -0,0 +1,3 @@
+module stringspkg
+
+go 1.25.0
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"stringspkg"
+	"unicode"
+)
+
+// === Ex. strings and bytes: splitting, replacing, trimming, building ===
+
+// The bytes package mirrors strings function for function (bytes.Split, bytes.Fields, bytes.Cut...),
+// for data that's already a []byte - a file's contents, a request body - so it needn't be converted
+
+func main() {
+	splitJoinExample()
+	// replacerExample()
+	// cutTrimExample()
+	// builderBufferExample()
+	// concatBenchmarkExample()
+}
+
+func splitJoinExample() {
+	csv := "go,rust,,zig"
+	fmt.Printf("Split: %q | SplitN(2): %q | SplitAfter: %q\n",
+		strings.Split(csv, ","), strings.SplitN(csv, ",", 2), strings.SplitAfter(csv, ","))
+	// Split keeps empty parts - and an empty string is one empty part, not none
+	fmt.Printf("Split(\"\", \",\"): %q, len %d\n", strings.Split("", ","), len(strings.Split("", ",")))
+
+	// Fields splits on runs of white space, and drops the empty parts
+	line := "  upload\tnotes.md \n 2048  "
+	fmt.Printf("Fields: %q | Split on \" \": %d parts\n", strings.Fields(line), len(strings.Split(line, " ")))
+	// FieldsFunc: anything can be the separator
+	fmt.Printf("FieldsFunc on non-letters: %q\n", strings.FieldsFunc("a1b22c;d", func(r rune) bool { return !unicode.IsLetter(r) }))
+
+	// SplitSeq and FieldsSeq (Go 1.24): the same parts as an iterator, without building the slice
+	n := 0
+	for range strings.FieldsSeq(line) {
+		n++
+	}
+	fmt.Println("FieldsSeq parts:", n)
+
+	fmt.Println("Join:", strings.Join([]string{"basics", "moretypes", "methods"}, " > "))
+	fmt.Println("Slug:", stringspkg.Slug("Hello, 世界! Go 1.25"), "|", stringspkg.Slug("  A Tour -- of Go "))
+
+	// bytes: the same, on []byte
+	fmt.Printf("bytes.Fields: %q\n", bytes.Fields([]byte(line)))
+}
+
+func replacerExample() {
+	fmt.Println(strings.Replace("a-b-c-d", "-", "+", 2), strings.ReplaceAll("a-b-c-d", "-", ""))
+
+	// A Replacer does many replacements in one pass - and they don't see each other's output,
+	// which chained ReplaceAll calls do: escaping & once < is already &lt; escapes it twice
+	html := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	in := "<b>Fish & Chips</b>"
+	chained := strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(in, "<", "&lt;"), ">", "&gt;"), "&", "&amp;")
+	fmt.Println("Replacer:", html.Replace(in))
+	fmt.Println("chained: ", chained, "<- & replaced last, so &lt; became &amp;lt;")
+
+	// Where two old strings could match at the same place, the one listed first wins
+	fmt.Println(strings.NewReplacer("a", "1", "aa", "2").Replace("aaa"), strings.NewReplacer("aa", "2", "a", "1").Replace("aaa"))
+
+	// A Replacer is safe for concurrent use and can write straight to a Writer
+	var out strings.Builder
+	html.WriteString(&out, "1 < 2")
+	fmt.Println("WriteString:", out.String())
+}
+
+func cutTrimExample() {
+	// Cut: split at the first sep - before, after, and whether it was there
+	before, after, found := strings.Cut("user=1d02455e", "=")
+	fmt.Printf("Cut: %q %q %t\n", before, after, found)
+	_, _, found = strings.Cut("no separator", "=")
+	fmt.Println("Cut without the sep:", found)
+	// CutPrefix / CutSuffix: TrimPrefix that says whether it trimmed
+	rest, ok := strings.CutPrefix("--verbose", "--")
+	fmt.Println("CutPrefix:", rest, ok)
+
+	kv := stringspkg.ParseKV("user = 1d02455e; file=notes.md; junk; note=a=b", ";")
+	fmt.Println("ParseKV:", kv) // only the first = splits a pair, and junk, without one, is skipped
+
+	// Trim takes a SET of characters, TrimPrefix a string: a common mix-up
+	fmt.Printf("Trim(\"abcHelloCBA\", \"abc\"): %q | TrimPrefix(\"abcabcx\", \"abc\"): %q | TrimLeft: %q\n",
+		strings.Trim("abcHelloCBA", "abc"), strings.TrimPrefix("abcabcx", "abc"), strings.TrimLeft("abcabcx", "abc"))
+	// TrimFunc: while the func says so, from both ends
+	fmt.Printf("TrimFunc (not a letter): %q\n", strings.TrimFunc("¡¡Hola, 世界!! 123", func(r rune) bool { return !unicode.IsLetter(r) }))
+	fmt.Printf("TrimSpace: %q\n", strings.TrimSpace("\t notes.md \n"))
+
+	// Index functions return a byte offset (or -1), not a rune count
+	s := "héllo"
+	fmt.Println("Index of l in héllo:", strings.Index(s, "l"), "(é is 2 bytes) | Contains:", strings.Contains(s, "ll"),
+		"| EqualFold:", strings.EqualFold("Go", "GO"))
+}
+
+func builderBufferExample() {
+	// strings.Builder: write, then String() - the bytes become the string, no copy
+	var b strings.Builder
+	b.Grow(32) // room for 32 bytes now: no growing below that
+	for i := range 3 {
+		fmt.Fprintf(&b, "line %d\n", i) // a Builder is an io.Writer
+	}
+	b.WriteByte('>')
+	b.WriteRune('世')
+	fmt.Printf("Builder: %q | Len %d Cap %d\n", b.String(), b.Len(), b.Cap())
+
+	// bytes.Buffer: written AND read - a queue of bytes
+	var buf bytes.Buffer
+	buf.WriteString("header\nbody line 1\nbody line 2\n")
+	first, _ := buf.ReadString('\n')
+	fmt.Printf("Buffer: read %q, %d bytes still in it\n", first, buf.Len())
+	rest, _ := io.ReadAll(&buf)
+	fmt.Printf("read the rest: %q | empty now: %t\n", rest, buf.Len() == 0)
+
+	// Buffer.Bytes() is its memory, not a copy: writing to the buffer later can change it
+	buf.Reset()
+	buf.WriteString("abc")
+	view, copied := buf.Bytes(), buf.String()
+	buf.Reset()
+	buf.WriteString("xyz")
+	fmt.Printf("Bytes() taken before a Reset now shows %q - String() kept %q\n", view, copied)
+
+	// A Builder can't be copied once written to - the copy would share (and corrupt) its buffer.
+	// It panics instead: "illegal use of non-zero Builder copied by value"
+	func() {
+		defer func() { fmt.Println("copying a used Builder, then writing:", recover()) }()
+		c := b
+		c.WriteString("x")
+	}()
+	// (a Builder is for building one string; for reading as well, or reuse after Reset, a Buffer)
+}
+
+func concatBenchmarkExample() {
+	// The measurements are tests in stringspkg_test.go: TestConcatAllocs counts the allocations per join
+	// (and logs them with -v), and BenchmarkConcat times each way, with B/op too:
+	//
+	//	go test -run ConcatAllocs -v stringspkg
+	//	go test -bench Concat stringspkg
+	//
+	// Where the difference comes from, seen in a Builder's capacity:
+	// each time it changes, a write allocated a bigger buffer and copied what was there
+	parts := make([]string, 100)
+	for i := range parts {
+		parts[i] = "a note line" // 11 bytes
+	}
+	var b strings.Builder
+	var caps []int
+	for _, p := range parts {
+		b.WriteString(p)
+		if len(caps) == 0 || caps[len(caps)-1] != b.Cap() {
+			caps = append(caps, b.Cap())
+		}
+	}
+	fmt.Printf("a Builder writing %d bytes grew %d times: %v\n", b.Len(), len(caps), caps)
+
+	var sized strings.Builder
+	sized.Grow(len(parts) * len(parts[0]))
+	grown := sized.Cap()
+	for _, p := range parts {
+		sized.WriteString(p)
+	}
+	fmt.Printf("after Grow(%d): capacity %d, and still %d once written\n", len(parts)*len(parts[0]), grown, sized.Cap())
+
+	// += allocates once per piece and copies O(n²) bytes; the Builder about log(n) times, doubling.
+	// strings.Join counts the bytes first and allocates once - same as a Builder with Grow
+}
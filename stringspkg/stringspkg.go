@@ -0,0 +1,73 @@
+package stringspkg
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+)
+
+// A Go string is an immutable sequence of bytes (usually UTF-8). "Changing" one makes a new one:
+// s += t copies all of s and t into a new string, so building a string of n pieces with += copies
+// the first piece n times - O(n²) bytes copied, and a new allocation for every piece.
+//
+// strings.Builder is the fix the notes compare to Java's StringBuilder: it appends into a []byte that
+// grows by doubling, and String() hands that []byte over as the string without copying it
+// (which is why a Builder can't be copied once used, and only ever grows).
+// bytes.Buffer does the same appending, but is also a Reader - and its String() copies.
+
+// ConcatPlus joins parts with +=, the slow way
+func ConcatPlus(parts []string) string {
+	s := ""
+	for _, p := range parts {
+		s += p
+	}
+	return s
+}
+
+// ConcatBuilder joins parts with a strings.Builder. With grow, it sizes the buffer up front - one allocation
+func ConcatBuilder(parts []string, grow bool) string {
+	var b strings.Builder
+	if grow {
+		n := 0
+		for _, p := range parts {
+			n += len(p)
+		}
+		b.Grow(n)
+	}
+	for _, p := range parts {
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// ConcatBuffer joins parts with a bytes.Buffer
+func ConcatBuffer(parts []string) string {
+	var b bytes.Buffer
+	for _, p := range parts {
+		b.WriteString(p)
+	}
+	return b.String() // a copy of the buffer's bytes
+}
+
+// ParseKV reads "key=value" pairs separated by sep - strings.Cut splits each at its first "=".
+// Pairs without an "=" are skipped, and keys and values are trimmed of spaces
+func ParseKV(s, sep string) map[string]string {
+	kv := map[string]string{}
+	for pair := range strings.SplitSeq(s, sep) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		kv[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return kv
+}
+
+// Slug makes a title into a URL path part: "Hello, 世界! Go 1.25" is "hello-世界-go-1-25".
+// Anything not a letter or digit separates words; Fields finds the words, Join puts dashes between them
+func Slug(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
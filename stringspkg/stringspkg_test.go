@@ -0,0 +1,114 @@
+package stringspkg
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func notes(n int) []string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = "a note line" // 11 bytes
+	}
+	return parts
+}
+
+var concats = []struct {
+	name string
+	fn   func([]string) string
+}{
+	{"plus", ConcatPlus},
+	{"builder", func(p []string) string { return ConcatBuilder(p, false) }},
+	{"builder-grow", func(p []string) string { return ConcatBuilder(p, true) }},
+	{"buffer", ConcatBuffer},
+	{"join", func(p []string) string { return strings.Join(p, "") }},
+}
+
+// Every way gives strings.Join's result
+func TestConcat(t *testing.T) {
+	for _, parts := range [][]string{nil, {""}, {"a"}, {"a", "", "b"}, {"héllo", ", ", "世界"}, notes(1000)} {
+		want := strings.Join(parts, "")
+		for _, c := range concats {
+			if got := c.fn(parts); got != want {
+				t.Errorf("%s of %d parts: %d bytes, want %q...", c.name, len(parts), len(got), want[:min(len(want), 20)])
+			}
+		}
+	}
+}
+
+// += allocates for every piece after the first; the Builder, doubling, about log(n) times; sized up
+// front, once - as Join does
+func TestConcatAllocs(t *testing.T) {
+	for _, n := range []int{10, 100, 1000} {
+		parts := notes(n)
+		allocs := func(fn func([]string) string) float64 {
+			return testing.AllocsPerRun(10, func() { fn(parts) })
+		}
+		for _, c := range concats {
+			t.Logf("%4d pieces, %-12s %5v allocations per join", n, c.name, allocs(c.fn))
+		}
+		if got := allocs(ConcatPlus); got != float64(n-1) {
+			t.Errorf("%d pieces: += made %v allocations, want %d", n, got, n-1)
+		}
+		if got := allocs(func(p []string) string { return ConcatBuilder(p, true) }); got != 1 {
+			t.Errorf("%d pieces: Builder with Grow made %v allocations, want 1", n, got)
+		}
+		if got := allocs(func(p []string) string { return strings.Join(p, "") }); got != 1 {
+			t.Errorf("%d pieces: Join made %v allocations, want 1", n, got)
+		}
+		if got := allocs(func(p []string) string { return ConcatBuilder(p, false) }); got > 16 {
+			t.Errorf("%d pieces: Builder made %v allocations, want at most about log2 of the bytes", n, got)
+		}
+	}
+}
+
+func TestParseKV(t *testing.T) {
+	tests := []struct {
+		s    string
+		want map[string]string
+	}{
+		{"user = 1d02455e; file=notes.md; junk; note=a=b", map[string]string{"user": "1d02455e", "file": "notes.md", "note": "a=b"}},
+		{"", map[string]string{}},
+		{"a=", map[string]string{"a": ""}},
+		{"=v", map[string]string{"": "v"}},
+		{"a=1;a=2", map[string]string{"a": "2"}}, // the last one wins
+	}
+	for _, tt := range tests {
+		if got := ParseKV(tt.s, ";"); !maps.Equal(got, tt.want) {
+			t.Errorf("ParseKV(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct{ title, want string }{
+		{"Hello, 世界! Go 1.25", "hello-世界-go-1-25"},
+		{"  A Tour -- of Go ", "a-tour-of-go"},
+		{"already-a-slug", "already-a-slug"},
+		{"ÉCOLE", "école"},
+		{"!!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.title); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+// The B/op column is the point: += copies O(n²) bytes. go test -bench Concat stringspkg
+func BenchmarkConcat(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		parts := notes(n)
+		for _, c := range concats {
+			b.Run(fmt.Sprintf("pieces=%d/%s", n, c.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					c.fn(parts)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"tcp"
+	"time"
+)
+
+// === Ex. TCP: an echo server with a goroutine per connection, a client, deadlines, graceful shutdown ===
+
+// Everything runs on 127.0.0.1 with port 0 - a free port the OS picks - so nothing else needs to be running,
+// and two runs at once don't collide.
+
+func main() {
+	echoExample()
+	// deadlineExample()
+	// shutdownExample()
+}
+
+// start runs an echo server on a random local port
+func start(idle time.Duration) (*tcp.Server, chan error) {
+	srv, err := tcp.Listen("127.0.0.1:0", idle)
+	if err != nil {
+		panic(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+	return srv, served
+}
+
+func echoExample() {
+	srv, _ := start(time.Second)
+	defer srv.Shutdown(context.Background())
+	fmt.Println("listening on", srv.Addr())
+
+	c, err := tcp.Dial(context.Background(), srv.Addr().String(), time.Second)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	fmt.Println("connected from", c.LocalAddr())
+	for _, line := range []string{"hello", "gophers, again"} {
+		reply, err := c.Echo(line)
+		fmt.Printf("sent %q, got %q, err %v\n", line, reply, err)
+	}
+
+	// Many clients at once: each connection has its own goroutine on the server, so they're served in parallel
+	const clients = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	good := 0
+	for i := range clients {
+		wg.Go(func() {
+			c, err := tcp.Dial(context.Background(), srv.Addr().String(), time.Second)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+			want := fmt.Sprint("client ", i)
+			for range 10 {
+				if reply, err := c.Echo(want); err != nil || reply != want {
+					return
+				}
+			}
+			mu.Lock()
+			good++
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	fmt.Printf("%d clients, 10 lines each, every reply their own: %d\n", clients, good)
+}
+
+func deadlineExample() {
+	// The server's idle timeout: a client that sends nothing for 100ms is disconnected
+	srv, _ := start(100 * time.Millisecond)
+	defer srv.Shutdown(context.Background())
+	c, _ := tcp.Dial(context.Background(), srv.Addr().String(), time.Second)
+	defer c.Close()
+	_, err := c.Echo("quick")
+	fmt.Println("right away:", err)
+	time.Sleep(200 * time.Millisecond)
+	_, err = c.Echo("too late")
+	// The write may still succeed - the OS buffers it - but the server has closed its end, so the read gets
+	// io.EOF (or a reset connection, if the server's OS answered the write with one)
+	fmt.Println("after 200ms of nothing:", err)
+
+	// The client's timeout: a server that accepts and then never answers
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close() // the connections stay open, unread, until the listener's closed
+		}
+	}()
+	c2, _ := tcp.Dial(context.Background(), ln.Addr().String(), 50*time.Millisecond)
+	defer c2.Close()
+	start := time.Now()
+	_, err = c2.Echo("anyone there?")
+	fmt.Printf("a silent server: %v after ~%v | os.ErrDeadlineExceeded: %t\n",
+		err, time.Since(start).Round(50*time.Millisecond), errors.Is(err, os.ErrDeadlineExceeded))
+
+	// Dial has its own bound, the ctx: nothing listens on a closed listener's port, so it's refused at once
+	addr := ln.Addr().String()
+	ln.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = tcp.Dial(ctx, addr, time.Second)
+	fmt.Println("dialing a closed port:", err)
+}
+
+func shutdownExample() {
+	srv, served := start(time.Minute)
+
+	// Three clients connected and idle - waiting on a read that Shutdown interrupts
+	var clients []*tcp.Client
+	for range 3 {
+		c, _ := tcp.Dial(context.Background(), srv.Addr().String(), time.Second)
+		c.Echo("hi")
+		clients = append(clients, c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	err := srv.Shutdown(ctx)
+	fmt.Printf("Shutdown: %v, in %v (not a minute: the idle connections were interrupted)\n", err, time.Since(start).Round(time.Millisecond*10))
+	fmt.Println("Serve returned:", <-served)
+
+	// The clients find their connections closed; new ones are refused
+	_, err = clients[0].Echo("still there?")
+	fmt.Println("a connected client:", err)
+	_, dialErr := tcp.Dial(context.Background(), srv.Addr().String(), time.Second)
+	fmt.Println("a new client:", dialErr)
+	for _, c := range clients {
+		c.Close()
+	}
+	// tcp_test.go runs all of this as tests, and a Shutdown whose ctx ends first
+}
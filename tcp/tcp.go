@@ -0,0 +1,161 @@
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// An echo server over TCP: a client sends lines, the server sends each one back.
+//
+// - net.Listen returns a net.Listener; Accept blocks until a client connects, and returns a net.Conn -
+//   an io.Reader and io.Writer for the connection. Each one is handled in its own goroutine, so a slow
+//   client doesn't hold up the others (the same as http.Server, which is built on this)
+// - a read on a net.Conn blocks until data comes, forever by default. SetReadDeadline sets a point in
+//   time after which it fails with os.ErrDeadlineExceeded - how an idle client gets disconnected
+// - the address "127.0.0.1:0" asks for any free port; Addr says which one was given
+//
+//	srv, _ := tcp.Listen("127.0.0.1:0", time.Minute)
+//	go srv.Serve()
+//	defer srv.Shutdown(ctx)
+
+// ErrServerClosed is what Serve returns after Shutdown
+var ErrServerClosed = errors.New("tcp: server closed")
+
+// Server is an echo server
+type Server struct {
+	ln   net.Listener
+	idle time.Duration // how long a connection may wait for its next line
+
+	closing atomic.Bool
+	wg      sync.WaitGroup // one per open connection
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+}
+
+// Listen listens on addr (host:port). A connection that sends nothing for idle is closed
+func Listen(addr string, idle time.Duration) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{ln: ln, idle: idle, conns: map[net.Conn]struct{}{}}, nil
+}
+
+// Addr is the address the server listens on - with the real port, if it was 0
+func (s *Server) Addr() net.Addr { return s.ln.Addr() }
+
+// Serve accepts connections until Shutdown, handling each in its own goroutine
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if s.closing.Load() {
+				return ErrServerClosed
+			}
+			return err
+		}
+		s.mu.Lock()
+		if s.closing.Load() { // accepted just as Shutdown began: it isn't counted, so it can't be served
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+	r := bufio.NewReader(conn)
+	for !s.closing.Load() {
+		// A new deadline for every line: it's a point in time, not a duration, so it doesn't reset by itself
+		conn.SetReadDeadline(time.Now().Add(s.idle))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return // the client closed (io.EOF), was idle too long, or Shutdown interrupted the read
+		}
+		if _, err := conn.Write([]byte(line)); err != nil {
+			return
+		}
+	}
+}
+
+// Shutdown stops the server gracefully: it stops accepting, and interrupts the connections waiting for
+// a line - a line being echoed is still sent back. If ctx ends before they're all done, the rest are
+// closed and Shutdown returns ctx's error
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing.Store(true)
+	s.mu.Unlock()
+	err := s.ln.Close() // Accept returns an error now, so Serve returns
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now()) // a deadline in the past: a blocked read fails at once
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() { s.wg.Wait(); close(done) }()
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Client is a connection to an echo server
+type Client struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+}
+
+// Dial connects to addr. ctx bounds the connecting; timeout bounds each Echo after that
+func Dial(ctx context.Context, addr string, timeout time.Duration) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, r: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+// Echo sends line and waits for it to come back. A server that doesn't answer in time is an error
+// matching os.ErrDeadlineExceeded
+func (c *Client) Echo(line string) (string, error) {
+	// SetDeadline covers both the write and the read
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write([]byte(line + "\n")); err != nil {
+		return "", err
+	}
+	reply, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return reply[:len(reply)-1], nil
+}
+
+// LocalAddr is the client's end of the connection: the same IP, and a port the OS picked
+func (c *Client) LocalAddr() net.Addr { return c.conn.LocalAddr() }
+
+func (c *Client) Close() error { return c.conn.Close() }
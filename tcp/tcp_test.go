@@ -0,0 +1,230 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitGoroutines waits (up to a second) for the goroutine count to drop back to want
+func waitGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines still running, want %d - a goroutine leaked", runtime.NumGoroutine(), want)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// start runs an echo server on a random local port, shut down when the test ends
+func start(t *testing.T, idle time.Duration) (*Server, chan error) {
+	t.Helper()
+	srv, err := Listen("127.0.0.1:0", idle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+	return srv, served
+}
+
+func dial(t *testing.T, srv *Server, timeout time.Duration) *Client {
+	t.Helper()
+	c, err := Dial(context.Background(), srv.Addr().String(), timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestEcho(t *testing.T) {
+	srv, _ := start(t, time.Second)
+	c := dial(t, srv, time.Second)
+	for _, line := range []string{"hello", "gophers, again", "", "世界", strings.Repeat("x", 100_000)} {
+		if reply, err := c.Echo(line); err != nil || reply != line {
+			t.Errorf("Echo(%.20q) = %.20q, %v", line, reply, err)
+		}
+	}
+	if host, _, _ := net.SplitHostPort(c.LocalAddr().String()); host != "127.0.0.1" {
+		t.Errorf("LocalAddr = %v", c.LocalAddr())
+	}
+}
+
+// Each connection has its own goroutine: many clients at once each get their own replies
+func TestManyClients(t *testing.T) {
+	srv, _ := start(t, time.Second)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			c, err := Dial(context.Background(), srv.Addr().String(), 5*time.Second)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer c.Close()
+			want := fmt.Sprint("client ", i)
+			for range 10 {
+				if reply, err := c.Echo(want); err != nil || reply != want {
+					t.Errorf("client %d: %q, %v", i, reply, err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// A client that sends nothing for longer than idle is disconnected; one that keeps talking isn't
+func TestIdleTimeout(t *testing.T) {
+	srv, _ := start(t, 100*time.Millisecond)
+	busy, idle := dial(t, srv, time.Second), dial(t, srv, time.Second)
+	for range 6 { // 300ms in all, never 100ms quiet
+		if _, err := busy.Echo("still here"); err != nil {
+			t.Fatalf("a busy client was disconnected: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if _, err := idle.Echo("too late"); err == nil {
+		t.Error("a client idle for 300ms is still connected")
+	}
+}
+
+// A server that accepts and then never answers: Echo gives up after the client's timeout
+func TestClientTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		var conns []net.Conn // kept open, never read, until the listener is closed
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				for _, c := range conns {
+					c.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	defer ln.Close()
+
+	c, err := Dial(context.Background(), ln.Addr().String(), 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	start := time.Now()
+	_, err = c.Echo("anyone there?")
+	var ne net.Error
+	if !errors.Is(err, os.ErrDeadlineExceeded) || !errors.As(err, &ne) || !ne.Timeout() {
+		t.Errorf("a silent server: %v, want a timeout", err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond || d > 5*time.Second {
+		t.Errorf("gave up after %v, want about 50ms", d)
+	}
+}
+
+func TestDialRefused(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if c, err := Dial(ctx, addr, time.Second); err == nil {
+		c.Close()
+		t.Error("dialed a closed port")
+	}
+}
+
+// Shutdown interrupts idle connections at once (not after the idle minute), Serve returns ErrServerClosed,
+// existing clients see their connection closed, new ones are refused - and nothing is left running
+func TestShutdown(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	srv, err := Listen("127.0.0.1:0", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+	var clients []*Client
+	for range 3 {
+		c, err := Dial(context.Background(), srv.Addr().String(), time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		c.Echo("hi")
+		clients = append(clients, c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown = %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Shutdown took %v - the idle connections weren't interrupted", d)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Serve returned %v, want ErrServerClosed", err)
+	}
+	for _, c := range clients {
+		if _, err := c.Echo("still there?"); !errors.Is(err, io.EOF) {
+			t.Errorf("a connected client after Shutdown: %v, want io.EOF", err)
+		}
+		c.Close()
+	}
+	if c, err := Dial(context.Background(), srv.Addr().String(), time.Second); err == nil {
+		c.Close()
+		t.Error("a new client connected after Shutdown")
+	}
+	waitGoroutines(t, baseline)
+}
+
+// A connection stuck writing its echo (to a client that doesn't read) isn't interrupted by the read deadline:
+// when ctx ends first, Shutdown closes it and returns ctx's error
+func TestShutdownDeadline(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+	srv, err := Listen("127.0.0.1:0", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// far more than the socket buffers hold, and never read: the server's Write of the echo blocks
+	if _, err := conn.Write([]byte(strings.Repeat("x", 32<<20) + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := srv.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want context.DeadlineExceeded", err)
+	}
+	<-served
+	conn.Close()
+	waitGoroutines(t, baseline)
+}
@@ -0,0 +1,3 @@
+module testingpkg
+
+go 1.25.0
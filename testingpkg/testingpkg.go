@@ -0,0 +1,37 @@
+package testingpkg
+
+import "unicode"
+
+// The code under test: two small functions with easy-to-state properties, so the tests in
+// testingpkg_test.go can check them against the standard library (WordCount against strings.Fields,
+// Index against strings.Index). From this directory:
+//
+//	go test -v                          every test, subtest and example, with === RUN / PAUSE / CONT lines
+//	go test -v -run 'TestWordCount/^tabs' one subtest: a name per level, split at the slashes
+//	go test -run '^$' -fuzz FuzzIndex -fuzztime 10s
+//	go test -run '^$' -fuzz FuzzBrokenIndex   finds brokenIndex's off-by-one (see the test file)
+
+// WordCount is the number of words in s: runs of anything that isn't white space (unicode.IsSpace)
+func WordCount(s string) int {
+	n, inWord := 0, false
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			inWord = false
+		case !inWord:
+			inWord = true
+			n++
+		}
+	}
+	return n
+}
+
+// Index is the index of the first sub in s, or -1 - strings.Index, written out by hand
+func Index(s, sub string) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if s[i:i+len(sub)] == sub {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,202 @@
+package testingpkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// In package testingpkg itself, not testingpkg_test: the tests can see unexported names too.
+// A file in package testingpkg_test would test only what the package exports, as a user would
+
+// --- table-driven subtests ---
+
+// TestWordCount is a table of cases, each run as a subtest with t.Run: it gets its own name
+// (TestWordCount/tabs), can fail or be picked (-run 'TestWordCount/tabs') on its own, and a failure
+// doesn't stop the other cases
+func TestWordCount(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"one word", "gopher", 1},
+		{"spaces around", "  hello   world  ", 2},
+		{"tabs and newlines", "a\tb\nc\r\nd", 4},
+		{"unicode space", "no\u00a0break\u2003em", 3}, // a no-break space and an em space are white space too
+		{"not ascii", "héllo 世界", 2},
+	}
+	for _, tc := range tests {
+		// Since Go 1.22 each iteration has its own tc, so the parallel closures don't all see the last case
+		// (the pitfalls module shows the old behaviour, and the i := i fix it needed)
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel() // pauses here until TestWordCount's own function returns, then the subtests run together
+			equal(t, WordCount(tc.in), tc.want)
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		s, sub string
+		want   int
+	}{
+		{"chicken", "ken", 4},
+		{"chicken", "dmr", -1},
+		{"chicken", "", 0},
+		{"", "", 0},
+		{"", "a", -1},
+		{"aaab", "ab", 2},
+		{"héllo", "llo", 3}, // a byte index: é is two bytes
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%q in %q", tc.sub, tc.s), func(t *testing.T) {
+			t.Parallel()
+			equal(t, Index(tc.s, tc.sub), tc.want)
+		})
+	}
+}
+
+// equal is a test helper: t.Helper makes a failure report the caller's line, not this one
+func equal[T comparable](t testing.TB, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// --- t.Cleanup ---
+
+// TestCleanup sets up a file and registers cleanups. They run after the test (and its subtests) finish,
+// last registered first - like defer, but a helper can register one for its caller's test.
+// t.TempDir registers its own: the directory is gone once the test is done
+func TestCleanup(t *testing.T) {
+	path := userFile(t, `{"userId": "1d02455e", "name": "John Doe"}`)
+	t.Cleanup(func() { t.Log("cleanup 2: registered last, runs first") })
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err) // stops this test - the cleanups still run
+	}
+	equal(t, WordCount(string(data)), 5) // split at white space only, so {"userId": is a word
+}
+
+// userFile writes contents to a file in a temporary directory, and returns its path. It cleans up
+// after itself when the test ends - the caller doesn't need a defer
+func userFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, err := os.Stat(filepath.Dir(path))
+		t.Log("cleanup 1: registered first, runs before TempDir's own; dir still there:", err == nil)
+	})
+	return path
+}
+
+// --- fuzzing ---
+
+// FuzzWordCount is a fuzz test. Plain go test runs its body once per seed - f.Add's, and any inputs
+// saved in testdata/fuzz/FuzzWordCount/. `go test -fuzz FuzzWordCount` mutates them, guided by coverage,
+// until a check fails or -fuzztime is up, and saves the failing input there: from then on plain go test
+// runs it too, so the bug stays fixed
+func FuzzWordCount(f *testing.F) {
+	for _, s := range []string{"", "hello world", "  tabs\tand\nnewlines  ", "héllo 世界", "no\u00a0break"} {
+		f.Add(s)
+	}
+	f.Fuzz(checkWordCount)
+}
+
+// checkWordCount is FuzzWordCount's body: the properties WordCount must have for any s
+func checkWordCount(t *testing.T, s string) {
+	if got, want := WordCount(s), len(strings.Fields(s)); got != want {
+		t.Errorf("WordCount(%q) = %d, strings.Fields has %d", s, got, want)
+	}
+	if got, want := WordCount(s+" "+s), 2*WordCount(s); got != want {
+		t.Errorf("WordCount of %q twice = %d, want %d", s, got, want)
+	}
+}
+
+// FuzzIndex fuzzes Index with two inputs: f.Add takes one value per argument of the body
+func FuzzIndex(f *testing.F) {
+	f.Add("chicken", "ken")
+	f.Add("aaab", "ab")
+	f.Add("", "")
+	f.Add("héllo", "é")
+	f.Fuzz(indexChecker(Index))
+}
+
+// indexChecker makes the body of FuzzIndex for an implementation of Index - so a broken one can be fuzzed
+func indexChecker(index func(s, sub string) int) func(t *testing.T, s, sub string) {
+	return func(t *testing.T, s, sub string) {
+		got, want := index(s, sub), strings.Index(s, sub)
+		if got != want {
+			t.Errorf("Index(%q, %q) = %d, strings.Index says %d", s, sub, got, want)
+		}
+		if got >= 0 && !strings.HasPrefix(s[got:], sub) {
+			t.Errorf("Index(%q, %q) = %d, but %q doesn't start with it", s, sub, got, s[got:])
+		}
+	}
+}
+
+// brokenIndex is Index with an off-by-one: it never looks at the last place sub could start.
+// FuzzBrokenIndex's seeds are inputs where that doesn't matter, so plain go test passes - and
+// `go test -run '^$' -fuzz FuzzBrokenIndex` finds a failing input within seconds, shrinks it, and saves it
+// in testdata/fuzz/FuzzBrokenIndex/. After that, plain go test runs the saved input and fails too,
+// until the directory is deleted (.gitignore keeps it out of the repository)
+func brokenIndex(s, sub string) int {
+	for i := 0; i+len(sub) < len(s); i++ {
+		if s[i:i+len(sub)] == sub {
+			return i
+		}
+	}
+	return -1
+}
+
+func FuzzBrokenIndex(f *testing.F) {
+	f.Add("chicken", "chi")
+	f.Add("gopher", "x")
+	f.Fuzz(indexChecker(brokenIndex))
+}
+
+// --- example tests ---
+
+// A function named Example<Func> with an // Output: comment is an example test: go test runs it and
+// compares what it printed with the comment, and go doc shows it in <Func>'s documentation.
+// With no // Output: comment it's only compiled, not run
+
+func ExampleWordCount() {
+	fmt.Println(WordCount("hello, gophers"))
+	fmt.Println(WordCount("   "))
+	// Output:
+	// 2
+	// 0
+}
+
+func ExampleIndex() {
+	fmt.Println(Index("chicken", "ken"))
+	fmt.Println(Index("chicken", "dmr"))
+	// Output:
+	// 4
+	// -1
+}
+
+// The suffix after _ (lower case) names a second example for the same function
+func ExampleWordCount_unordered() {
+	counts := map[string]int{}
+	for _, w := range strings.Fields("the gopher says the") {
+		counts[w]++
+	}
+	for w, n := range counts {
+		fmt.Println(w, n)
+	}
+	// Unordered output:
+	// the 2
+	// gopher 1
+	// says 1
+}
@@ -0,0 +1,3 @@
+module timepkg
+
+go 1.25.0
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"timepkg"
+)
+
+// === Ex. time: layouts, durations, timers and tickers, the monotonic clock ===
+
+// The concurrency notes use time casually - time.Sleep to make a goroutine slow, time.After in a select
+// for a timeout, time.Since for how long something took. This is what's underneath those.
+
+func main() {
+	formatExample()
+	// durationExample()
+	// timerTickerExample()
+	// afterFuncExample()
+	// monotonicExample()
+}
+
+func formatExample() {
+	t := time.Date(2026, time.March, 7, 14, 5, 9, 120_000_000, time.UTC)
+
+	// --- Format: the reference time, written the way it should look ---
+
+	for _, layout := range []string{
+		time.RFC3339, time.RFC3339Nano, time.DateTime, time.DateOnly, time.Kitchen, time.RFC1123,
+		"02/01/2006 15:04",              // day first
+		"Monday, January 2 at 3PM",      // names, 12-hour
+		"2006-01-02T15:04:05.000Z07:00", // always 3 digits of milliseconds
+	} {
+		fmt.Printf("%-38q %s\n", layout, t.Format(layout))
+	}
+	// A wrong number isn't an error. In "2007" the 2 is read as the day, and 007 is kept as it is
+	fmt.Println(`"2007-01-02" formats as:`, t.Format("2007-01-02"), "| 2006 would be the year:", t.Format("2006-01-02"))
+
+	// --- Parse: the same layout, read the other way ---
+
+	parsed, err := time.Parse(time.RFC3339, "2026-03-07T15:05:09+01:00")
+	fmt.Println("parsed:", parsed, err, "| the same instant as t (ignoring ms):", parsed.Equal(t.Truncate(time.Second)))
+	// == compares the zone too - Equal compares the instant
+	fmt.Println("parsed == t.Truncate(time.Second):", parsed == t.Truncate(time.Second), "| parsed.UTC() is:", parsed.UTC())
+
+	// Without a zone in the input, Parse gives UTC; ParseInLocation reads it as local time somewhere
+	ny, err := time.LoadLocation("America/New_York")
+	if err == nil {
+		local, _ := time.ParseInLocation(time.DateTime, "2026-03-07 09:05:09", ny)
+		fmt.Println("9:05 in New York is", local.UTC().Format(time.Kitchen), "UTC")
+	} else {
+		fmt.Println("no time zone database here:", err)
+	}
+
+	// Input in more than one format
+	for _, s := range []string{"2026-03-07", "07/03/2026 14:05", "March 7th"} {
+		t, err := timepkg.ParseAny(s, time.DateOnly, "02/01/2006 15:04", time.RFC3339)
+		if err != nil {
+			fmt.Println(strings.SplitN(err.Error(), "\n", 2)[0], "...")
+			continue
+		}
+		fmt.Printf("%-18q -> %s\n", s, t.Format(time.DateTime))
+	}
+
+	// Parse errors say which part didn't fit
+	_, err = time.Parse(time.DateOnly, "2026-02-30")
+	fmt.Println("February 30th:", err)
+}
+
+func durationExample() {
+	// A Duration is an int64 of nanoseconds. The constants are Durations - multiply them, don't build from ints
+	timeout := 90 * time.Second
+	fmt.Println("90 * time.Second:", timeout, "| in minutes:", timeout.Minutes(), "| in ms:", timeout.Milliseconds())
+
+	// An int variable must be converted first: time.Duration(n) * time.Second (n * time.Second won't compile)
+	retries := 3
+	fmt.Println("retries * backoff:", time.Duration(retries)*500*time.Millisecond)
+	// The mistake: time.Duration(n) alone is n NANOSECONDS
+	fmt.Println("time.Duration(5) - meant 5 seconds:", time.Duration(5))
+
+	d, err := time.ParseDuration("1h15m30.5s")
+	fmt.Println("ParseDuration(\"1h15m30.5s\"):", d, err, "| rounded to minutes:", d.Round(time.Minute), "| truncated:", d.Truncate(time.Minute))
+	_, err = time.ParseDuration("3 days")
+	fmt.Println("no day unit (days aren't always 24h):", err)
+
+	// Time arithmetic: Add/Sub with Durations, AddDate for calendar units
+	start := time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC)
+	fmt.Println("Jan 31 + 1 month (AddDate):", start.AddDate(0, 1, 0).Format(time.DateOnly), "- Feb 31 normalized to Mar 3")
+	fmt.Println("Jan 31 + 30*24h (Add):", start.Add(30*24*time.Hour).Format(time.DateOnly))
+	end := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	fmt.Println("Mar 1 - Jan 31 12:00:", end.Sub(start), "| Before:", start.Before(end), "| Until would be from now, Since to now")
+
+	// A Sub too big for a Duration gives the largest Duration instead of wrapping around
+	far := time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)
+	fmt.Println("2300 - 1700 doesn't fit in a Duration (about 292 years):", far.Sub(time.Date(1700, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func timerTickerExample() {
+	// --- Timer: fires once ---
+
+	timer := time.NewTimer(20 * time.Millisecond)
+	start := time.Now()
+	<-timer.C
+	fmt.Printf("timer fired after about %v\n", time.Since(start).Round(10*time.Millisecond))
+
+	// Stop before it fires: it never does. Stop says whether it stopped it (false: it had fired already)
+	timer = time.NewTimer(time.Hour)
+	fmt.Println("stopped an hour timer:", timer.Stop(), "| stopping again:", timer.Stop())
+
+	// Reset makes a timer fire again. Since Go 1.23 Stop and Reset also drain C, so a stale value
+	// from before the reset is never received - no more "if !t.Stop() { <-t.C }"
+	timer = time.NewTimer(time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // it fired, nobody received
+	timer.Reset(30 * time.Millisecond)
+	start = time.Now()
+	<-timer.C
+	fmt.Println("after Reset, the old value isn't received - waited the new 30ms:", time.Since(start) >= 25*time.Millisecond)
+
+	// time.After(d) is a Timer's C, for a one-off select. Since Go 1.23 an unstopped timer is freed once
+	// unreachable, so time.After in a loop no longer piles up timers until they fire
+	select {
+	case <-time.After(10 * time.Millisecond):
+		fmt.Println("time.After: timed out waiting")
+	case <-make(chan int):
+	}
+
+	// --- Ticker: fires every interval until stopped ---
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	ticks := 0
+	deadline := time.After(55 * time.Millisecond)
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			ticks++
+		case <-deadline:
+			break loop
+		}
+	}
+	ticker.Stop()
+	fmt.Println("ticks in 55ms at 10ms:", ticks, "| about 5:", ticks >= 4 && ticks <= 6)
+
+	// After Stop nothing more arrives
+	select {
+	case <-ticker.C:
+		fmt.Println("a tick after Stop")
+	case <-time.After(30 * time.Millisecond):
+		fmt.Println("no tick in 30ms after Stop")
+	}
+
+	// A slow receiver doesn't get a backlog: C has room for one tick, the rest are dropped
+	ticker = time.NewTicker(5 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // ~10 ticks due
+	waiting := 0
+	for {
+		select {
+		case <-ticker.C:
+			waiting++
+			continue
+		default:
+		}
+		break
+	}
+	ticker.Stop()
+	fmt.Println("ticks waiting after 50ms of not receiving:", waiting, "| at most one kept:", waiting <= 1)
+
+	// timepkg.Every: the usual ticker loop, stopped by a context
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+	defer cancel()
+	n := timepkg.Every(ctx, 10*time.Millisecond, func(time.Time) {})
+	fmt.Println("Every(10ms) for 35ms ran:", n, "| about 3:", n >= 2 && n <= 4)
+}
+
+func afterFuncExample() {
+	// time.AfterFunc runs a func in its own goroutine after the delay - no channel, no goroutine waiting for it
+	var fired atomic.Int32
+	t := time.AfterFunc(10*time.Millisecond, func() { fired.Add(1) })
+	time.Sleep(30 * time.Millisecond)
+	fmt.Println("AfterFunc ran:", fired.Load() == 1, "| Stop after it ran:", t.Stop())
+
+	// Stopped in time: it never runs
+	t = time.AfterFunc(20*time.Millisecond, func() { fired.Add(1) })
+	fmt.Println("stopped before it ran:", t.Stop())
+	time.Sleep(30 * time.Millisecond)
+	fmt.Println("and it didn't:", fired.Load() == 1)
+
+	// A debouncer: 5 calls 5ms apart, then quiet - fn runs once, 20ms after the last call
+	var runs atomic.Int32
+	var ranAt atomic.Int64
+	d := timepkg.NewDebouncer(20*time.Millisecond, func() {
+		runs.Add(1)
+		ranAt.Store(time.Now().UnixNano())
+	})
+	var lastCall time.Time
+	for range 5 {
+		d.Call()
+		lastCall = time.Now()
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	after := time.Unix(0, ranAt.Load()).Sub(lastCall)
+	fmt.Println("5 calls in a burst - runs:", runs.Load(), "| after the last call, by the delay:", after >= 20*time.Millisecond)
+
+	// Cancel drops a pending run
+	d.Call()
+	fmt.Println("cancelled a pending run:", d.Cancel())
+	time.Sleep(40 * time.Millisecond)
+	fmt.Println("runs still:", runs.Load())
+}
+
+func monotonicExample() {
+	// time.Now() reads two clocks:
+	// - the wall clock: the date and time, which can jump - NTP corrections, someone setting the clock,
+	//   leap second smearing - so the wall clock can run backwards
+	// - the monotonic clock: only ever goes forward, counting from some point (boot, usually); it's no date
+	// A Time from Now keeps both. Sub, Since, Until, Before and After use the monotonic reading when both times
+	// have one, so elapsed time is right even if the wall clock jumped in between. Everything that shows
+	// or stores a date (Format, Unix, MarshalJSON) uses the wall clock.
+
+	start := time.Now()
+	fmt.Println("Now() prints its monotonic reading, the m=:", strings.Contains(start.String(), " m=+"))
+
+	// Round(0) strips the monotonic reading - what's left is only a wall clock time
+	wall := start.Round(0)
+	fmt.Println("after Round(0):", !strings.Contains(wall.String(), " m="), "| same instant:", wall.Equal(start), "| but == is:", wall == start)
+
+	// Times that never had one: from Date, Parse, Unix - or through JSON and back
+	var decoded time.Time
+	b, _ := json.Marshal(start)
+	json.Unmarshal(b, &decoded)
+	fmt.Println("through JSON:", string(b), "| monotonic reading lost:", !strings.Contains(decoded.String(), " m="))
+
+	time.Sleep(20 * time.Millisecond)
+	now := time.Now()
+	fmt.Println("elapsed, monotonic:", now.Sub(start).Round(time.Millisecond), "| wall clock only:", now.Round(0).Sub(wall).Round(time.Millisecond))
+
+	// A wall clock jump, simulated: a start time an hour later, as if the clock was set back an hour
+	// since. Only the wall reading moves - Add keeps the monotonic reading, moved the same
+	jumped := wall.Add(time.Hour)
+	fmt.Println("with the wall clock set back an hour, wall-only elapsed:", now.Round(0).Sub(jumped).Round(time.Minute),
+		"- negative. Since(start) is still:", time.Since(start).Round(10*time.Millisecond))
+	// So measure with Now() and Since on the same process's times - not ones loaded from a file or a database
+	fmt.Println("Since(decoded) falls back to the wall clock:", time.Since(decoded) > 0)
+}
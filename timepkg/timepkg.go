@@ -0,0 +1,82 @@
+package timepkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Go has no "yyyy-MM-dd" patterns. A layout is the reference time, Mon Jan 2 15:04:05 MST 2006,
+// written the way the time should look - each part of it is a number that stands for one field:
+//
+//	2006 year   01 month   02 day   15 hour (03 for 12-hour, with PM)   04 minute   05 second
+//	Jan/January month name   Mon/Monday day name   MST zone name   -0700 or Z07:00 zone offset
+//	.000 milliseconds (.999 the same, with trailing zeros dropped)
+//
+// (1 2 3 4 5 6 7: month 1, day 2, hour 3 PM, minute 4, second 5, year 6, zone -7.)
+// The time package has the common ones as constants: time.RFC3339, time.DateTime, time.Kitchen...
+
+// ParseAny parses value with the first of layouts that fits, for input that comes in a few formats.
+// Layouts without a zone are read as UTC
+func ParseAny(value string, layouts ...string) (time.Time, error) {
+	var errs []error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+		errs = append(errs, err)
+	}
+	return time.Time{}, fmt.Errorf("%q fits none of %d layouts: %w", value, len(layouts), errors.Join(errs...))
+}
+
+// Every calls fn each interval until ctx is done, and returns how many times it did.
+// A slow fn doesn't queue up ticks: the Ticker drops the ones nobody was there to receive.
+// Stop in the defer - a ticker that's never stopped keeps firing (until it's garbage, since Go 1.23)
+func Every(ctx context.Context, interval time.Duration, fn func(time.Time)) int {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	n := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return n
+		case now := <-t.C:
+			fn(now)
+			n++
+		}
+	}
+}
+
+// Debouncer runs fn once calls to Call have stopped for the delay - a burst of calls (keystrokes,
+// file change events) runs fn once, after the last one. It's a time.AfterFunc, moved back by each Call
+type Debouncer struct {
+	mu    sync.Mutex
+	delay time.Duration
+	fn    func()
+	timer *time.Timer
+}
+
+func NewDebouncer(delay time.Duration, fn func()) *Debouncer {
+	return &Debouncer{delay: delay, fn: fn}
+}
+
+func (d *Debouncer) Call() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.delay, d.fn) // fn runs in its own goroutine
+		return
+	}
+	// Reset on an AfterFunc timer starts it again, whether or not it already fired
+	d.timer.Reset(d.delay)
+}
+
+// Cancel stops a pending run. It reports whether one was pending (false: fn already started, or nothing to run)
+func (d *Debouncer) Cancel() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.timer != nil && d.timer.Stop()
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"tour/notes"
+	"tour/runner"
+)
+
+// --- tour contention ---
+
+// Runs examples with the runtime's mutex and block profiles switched on, and prints where goroutines waited.
+// The two profiles answer different questions (see the sync.Mutex notes in concurrency.go):
+// - mutex: how long goroutines waited for a sync.Mutex / RWMutex, charged to the code that HELD the lock
+//   (it's recorded in Unlock - the holder is what made everyone else wait)
+// - block: how long goroutines were blocked, charged to where they blocked - Lock, channel sends and receives,
+//   select, WaitGroup.Wait, Cond.Wait
+//
+// The profiles are read in their text form (debug=1), which needs no pprof tooling:
+//
+//	--- mutex:
+//	cycles/second=1999994519
+//	sampling period=1
+//	84360800 13 @ 0x4df34f 0x4df34e 0x48874a
+//	#	0x4df34e	sync.(*Mutex).Unlock+0x6e	/usr/local/go/src/sync/mutex.go:65
+//	#	0x4df34d	main.main.func1+0x6d		/tmp/prof/main.go:21
+//
+// Each record is: total cycles spent waiting, number of events, then the stack (innermost frame first).
+
+// defaultContentionExamples are the benchmarks built to show lock contention
+var defaultContentionExamples = []string{"counterBenchmarkExample", "rwMutexBenchmarkExample", "shardedMapBenchmarkExample"}
+
+type stackFrame struct {
+	Func string
+	File string // file:line
+}
+
+type profileRecord struct {
+	Cycles int64
+	Count  int64
+	Stack  []stackFrame
+}
+
+type contentionProfile struct {
+	Kind            string // "mutex", or "contention" for the block profile
+	CyclesPerSecond float64
+	Records         []profileRecord
+}
+
+var errNotContentionProfile = errors.New("not a contention profile")
+
+func parseContentionProfile(r io.Reader) (*contentionProfile, error) {
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		return nil, cmp.Or(sc.Err(), errNotContentionProfile)
+	}
+	kind, ok := strings.CutPrefix(sc.Text(), "--- ")
+	if !ok {
+		return nil, fmt.Errorf("%w: first line %q", errNotContentionProfile, sc.Text())
+	}
+	p := &contentionProfile{Kind: strings.TrimSuffix(kind, ":")}
+
+	for n := 2; sc.Scan(); n++ {
+		line := sc.Text()
+		switch fields := strings.Fields(line); {
+		case len(fields) == 0:
+		case fields[0] == "#": // a frame of the current record: # addr func+offset file:line
+			if len(p.Records) == 0 || len(fields) < 4 {
+				return nil, fmt.Errorf("line %d: stray frame %q", n, line)
+			}
+			fn, _, _ := strings.Cut(fields[2], "+0x")
+			rec := &p.Records[len(p.Records)-1]
+			rec.Stack = append(rec.Stack, stackFrame{Func: fn, File: fields[3]})
+		case len(fields) >= 3 && fields[2] == "@": // cycles count @ addr...
+			cycles, err1 := strconv.ParseInt(fields[0], 10, 64)
+			count, err2 := strconv.ParseInt(fields[1], 10, 64)
+			if err := errors.Join(err1, err2); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			p.Records = append(p.Records, profileRecord{Cycles: cycles, Count: count})
+		case strings.HasPrefix(line, "cycles/second="):
+			cps, err := strconv.ParseFloat(strings.TrimPrefix(line, "cycles/second="), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			p.CyclesPerSecond = cps
+		case strings.Contains(line, "="): // other header lines, ex. sampling period=1
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", n, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if p.CyclesPerSecond == 0 {
+		return nil, fmt.Errorf("%w: no cycles/second header", errNotContentionProfile)
+	}
+	return p, nil
+}
+
+// contentionSite is the code responsible for some waiting, with the total over all its records
+type contentionSite struct {
+	Func, File string
+	Waited     time.Duration
+	Events     int64
+}
+
+// libraryPrefixes are skipped when looking for the responsible code - the sync and runtime frames
+// are the lock itself or the scheduler ("_." too), and the testing frames are the benchmark harness
+var libraryPrefixes = []string{"sync.", "runtime.", "testing.", "internal/", "sync/", "_."}
+
+func isLibraryFrame(fn string) bool {
+	return slices.ContainsFunc(libraryPrefixes, func(p string) bool { return strings.HasPrefix(fn, p) })
+}
+
+// topSites groups the records by their first frame outside the libraries, most waiting first
+func (p *contentionProfile) topSites(n int) []contentionSite {
+	byKey := map[string]*contentionSite{}
+	for _, rec := range p.Records {
+		i := slices.IndexFunc(rec.Stack, func(f stackFrame) bool { return !isLibraryFrame(f.Func) })
+		if i < 0 {
+			continue // entirely inside the runtime / harness (ex. the benchmark waiting for its goroutines)
+		}
+		f := rec.Stack[i]
+		site, ok := byKey[f.Func+" "+f.File]
+		if !ok {
+			site = &contentionSite{Func: f.Func, File: f.File}
+			byKey[f.Func+" "+f.File] = site
+		}
+		site.Waited += time.Duration(float64(rec.Cycles) / p.CyclesPerSecond * float64(time.Second))
+		site.Events += rec.Count
+	}
+	sites := make([]contentionSite, 0, len(byKey))
+	for _, s := range byKey {
+		sites = append(sites, *s)
+	}
+	slices.SortFunc(sites, func(a, b contentionSite) int {
+		return cmp.Or(cmp.Compare(b.Waited, a.Waited), cmp.Compare(a.Func, b.Func))
+	})
+	return sites[:min(n, len(sites))]
+}
+
+func runContention(args []string) error {
+	fs := flag.NewFlagSet("contention", flag.ContinueOnError)
+	root := fs.String("root", "..", "repository root that example directories are relative to")
+	procs := fs.Int("procs", max(4, runtime.NumCPU()), "GOMAXPROCS for the examples - a lock is only contended\nwhile goroutines run in parallel (more than the CPUs still works: the OS preempts lock holders)")
+	top := fs.Int("top", 5, "call sites to show per profile")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: tour contention [flags] [example ...]\n\nwith no examples, runs %s\n\n", strings.Join(defaultContentionExamples, ", "))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	names := fs.Args()
+	if len(names) == 0 {
+		names = defaultContentionExamples
+	}
+
+	absRoot, err := filepath.Abs(*root)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		ex, err := lookupExample(name)
+		if err != nil {
+			return err
+		}
+		if err := profileExample(absRoot, ex, *procs, *top); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// lookupExample finds the one registered example with this name
+func lookupExample(name string) (notes.Example, error) {
+	found := notes.ExamplesNamed(name)
+	switch {
+	case len(found) == 0:
+		return notes.Example{}, fmt.Errorf("no registered example %q (see tour/notes/examples.go)", name)
+	case len(found) > 1:
+		return notes.Example{}, fmt.Errorf("%q is registered in several directories", name)
+	}
+	return found[0], nil
+}
+
+func profileExample(root string, ex notes.Example, procs, top int) error {
+	tmp, err := os.MkdirTemp("", "tour-contention")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	fmt.Printf("=== %s (%s), GOMAXPROCS=%d ===\n", ex.Name, ex.Dir, procs)
+	cfg := runner.Config{
+		Root:         root,
+		Example:      ex,
+		MutexProfile: filepath.Join(tmp, "mutex.txt"),
+		BlockProfile: filepath.Join(tmp, "block.txt"),
+		Env:          []string{"GOMAXPROCS=" + strconv.Itoa(procs)},
+		Stdout:       os.Stdout,
+		Stderr:       os.Stderr,
+	}
+	if err := runner.Run(context.Background(), cfg); err != nil {
+		return err
+	}
+
+	for _, prof := range []struct{ path, title string }{
+		{cfg.MutexProfile, "mutex profile - time others waited for a lock, charged to the code holding it"},
+		{cfg.BlockProfile, "block profile - time spent blocked, charged to where it blocked"},
+	} {
+		f, err := os.Open(prof.path)
+		if err != nil {
+			return fmt.Errorf("profile not written: %w", err)
+		}
+		p, err := parseContentionProfile(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(prof.path), err)
+		}
+		fmt.Printf("\n%s:\n", prof.title)
+		sites := p.topSites(top)
+		if len(sites) == 0 {
+			fmt.Println("  nothing recorded outside the runtime")
+		}
+		for _, s := range sites {
+			file, _ := strings.CutPrefix(s.File, root+string(filepath.Separator))
+			fmt.Printf("  %10v %8d events  %s  %s\n", s.Waited.Round(time.Microsecond), s.Events, s.Func, file)
+		}
+	}
+	fmt.Println()
+	return nil
+}
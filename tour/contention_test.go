@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"tour/runner"
+)
+
+// A mutex profile as the runtime writes it (debug=1): two records charged to the same Unlock site,
+// and one entirely inside the runtime
+const mutexProfileText = `--- mutex:
+cycles/second=2000000000
+sampling period=1
+84360800 13 @ 0x4df34f 0x4df34e 0x48874a
+#	0x4df34e	sync.(*Mutex).Unlock+0x6e	/usr/local/go/src/sync/mutex.go:65
+#	0x4df34d	main.(*counter).Inc+0x6d	/tmp/prof/main.go:21
+#	0x48874a	main.main.func1+0x2a		/tmp/prof/main.go:30
+
+2000000000 7 @ 0x4df34f 0x4df34e
+#	0x4df34e	sync.(*Mutex).Unlock+0x6e	/usr/local/go/src/sync/mutex.go:65
+#	0x4df34d	main.(*counter).Inc+0x6d	/tmp/prof/main.go:21
+
+4000000 2 @ 0x4df34f
+#	0x4df34e	runtime.unlock+0x6e	/usr/local/go/src/runtime/lock_futex.go:100
+#	0x4df34d	testing.(*B).run1.func1+0x6d	/usr/local/go/src/testing/benchmark.go:238
+
+1000000 1 @ 0x4df34f
+#	0x4df34e	sync.(*RWMutex).Unlock+0x10	/usr/local/go/src/sync/rwmutex.go:200
+#	0x4df34d	main.(*cache).Set+0x6d	/tmp/prof/cache.go:9
+`
+
+func TestParseContentionProfile(t *testing.T) {
+	p, err := parseContentionProfile(strings.NewReader(mutexProfileText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Kind != "mutex" || p.CyclesPerSecond != 2e9 || len(p.Records) != 4 {
+		t.Fatalf("kind %q, %v cycles/s, %d records; want mutex, 2e9, 4", p.Kind, p.CyclesPerSecond, len(p.Records))
+	}
+	first := p.Records[0]
+	if first.Cycles != 84360800 || first.Count != 13 || len(first.Stack) != 3 {
+		t.Errorf("first record = %+v", first)
+	}
+	if f := first.Stack[1]; f.Func != "main.(*counter).Inc" || f.File != "/tmp/prof/main.go:21" {
+		t.Errorf("frame = %+v, want the function without its offset, and file:line", f)
+	}
+
+	sites := p.topSites(5)
+	want := []contentionSite{
+		{Func: "main.(*counter).Inc", File: "/tmp/prof/main.go:21", Waited: time.Second + 42180400*time.Nanosecond, Events: 20},
+		{Func: "main.(*cache).Set", File: "/tmp/prof/cache.go:9", Waited: 500 * time.Microsecond, Events: 1},
+	}
+	if len(sites) != len(want) {
+		t.Fatalf("sites = %+v, want %+v - the runtime-only record skipped", sites, want)
+	}
+	for i := range want {
+		if sites[i] != want[i] {
+			t.Errorf("site %d = %+v, want %+v", i, sites[i], want[i])
+		}
+	}
+	if got := p.topSites(1); len(got) != 1 || got[0].Func != "main.(*counter).Inc" {
+		t.Errorf("topSites(1) = %+v, want the site that waited most", got)
+	}
+}
+
+func TestParseContentionProfileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name, text string
+	}{
+		{"empty", ""},
+		{"not a profile", "hello\n"},
+		{"no cycles/second", "--- mutex:\nsampling period=1\n"},
+		{"stray frame", "--- mutex:\ncycles/second=1\n#\t0x1\tmain.f+0x1\tf.go:1\n"},
+		{"bad count", "--- mutex:\ncycles/second=1\n10 x @ 0x1\n"},
+		{"unexpected line", "--- mutex:\ncycles/second=1\nwhat is this\n"},
+	} {
+		if _, err := parseContentionProfile(strings.NewReader(tc.text)); err == nil {
+			t.Errorf("%s: no error", tc.name)
+		}
+	}
+	if _, err := parseContentionProfile(strings.NewReader("hello\n")); !errors.Is(err, errNotContentionProfile) {
+		t.Errorf("err = %v, want errNotContentionProfile", err)
+	}
+}
+
+// Builds and runs a contention example with both profiles on, as tour contention does, and parses what it wrote.
+// The counter benchmark contends a Mutex on purpose, so its own code has to show up in the mutex profile
+func TestContentionProfilesSmoke(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the concurrency module")
+	}
+	ex, err := lookupExample("counterBenchmarkExample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := t.TempDir()
+	cfg := runner.Config{
+		Root:         "..",
+		Example:      ex,
+		MutexProfile: filepath.Join(tmp, "mutex.txt"),
+		BlockProfile: filepath.Join(tmp, "block.txt"),
+		Env:          []string{"GOMAXPROCS=4"},
+		Stdout:       io.Discard,
+		Stderr:       io.Discard,
+	}
+	if err := runner.Run(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, prof := range []struct{ path, kind string }{{cfg.MutexProfile, "mutex"}, {cfg.BlockProfile, "contention"}} {
+		f, err := os.Open(prof.path)
+		if err != nil {
+			t.Fatalf("profile not written: %v", err)
+		}
+		p, err := parseContentionProfile(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", filepath.Base(prof.path), err)
+		}
+		if p.Kind != prof.kind || len(p.Records) == 0 {
+			t.Errorf("%s: kind %q with %d records, want %q with some", filepath.Base(prof.path), p.Kind, len(p.Records), prof.kind)
+		}
+		sites := p.topSites(10)
+		found := false
+		for _, s := range sites {
+			found = found || strings.Contains(s.File, "concurrency/syncatomic.go")
+		}
+		if !found {
+			t.Errorf("%s: no site in concurrency/syncatomic.go among %+v", prof.kind, sites)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"tour/notes"
+)
+
+// --- tour coverage ---
+
+// Cross-references the Tour's pages (notes.Lessons) against the registered examples (notes.Examples).
+// The registry is plain data, so it can go stale - every entry is also checked against the source:
+// go/parser reads the package directory and the function must be declared there (as a function, not a method).
+
+// staleExample is a registered example whose function doesn't exist (renamed, deleted, or a typo)
+type staleExample struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+}
+
+type coverageOutput struct {
+	notes.CoverageReport
+	Percent float64        `json:"percent"`
+	Stale   []staleExample `json:"stale_examples,omitempty"`
+}
+
+func runCoverage(args []string) error {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format: text or json")
+	root := fs.String("root", "..", "repository root that example directories are relative to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want text or json)", *format)
+	}
+
+	stale, err := findStaleExamples(*root, notes.Examples)
+	if err != nil {
+		return err
+	}
+
+	out := coverageOutput{CoverageReport: notes.Coverage(notes.Lessons, notes.Examples), Stale: stale}
+	out.Percent = out.CoverageReport.Percent()
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	writeCoverageText(os.Stdout, out)
+	return nil
+}
+
+// findStaleExamples parses each example directory once, and collects the top level function names
+func findStaleExamples(root string, examples []notes.Example) ([]staleExample, error) {
+	funcsByDir := make(map[string]map[string]*ast.FuncType)
+	var stale []staleExample
+	for _, ex := range examples {
+		funcs, ok := funcsByDir[ex.Dir]
+		if !ok {
+			var err error
+			if funcs, err = declaredFuncs(filepath.Join(root, ex.Dir)); err != nil {
+				return nil, err
+			}
+			funcsByDir[ex.Dir] = funcs
+		}
+		if funcs[ex.Name] == nil {
+			stale = append(stale, staleExample{ex.Name, ex.Dir})
+		}
+	}
+	return stale, nil
+}
+
+// declaredFuncs maps each top level function in dir to its signature
+func declaredFuncs(dir string) (map[string]*ast.FuncType, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no Go files in %s (is -root the repository root?)", dir)
+	}
+
+	funcs := make(map[string]*ast.FuncType)
+	fset := token.NewFileSet()
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		// SkipObjectResolution - only the declarations are needed, not what the identifiers refer to
+		f, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs[fn.Name.Name] = fn.Type
+			}
+		}
+	}
+	return funcs, nil
+}
+
+func writeCoverageText(w io.Writer, out coverageOutput) {
+	fmt.Fprintf(w, "Tour coverage: %d/%d pages have an example (%.0f%%)\n\n", out.Covered, out.Total, out.Percent)
+
+	chapter := ""
+	for _, l := range out.Lessons {
+		if l.Chapter != chapter {
+			chapter = l.Chapter
+			fmt.Fprintf(w, "%s\n", chapter)
+		}
+		fmt.Fprintf(w, "  %-60s %2d/%-2d\n", l.Lesson, l.Covered, l.Total)
+		for _, p := range l.Missing {
+			fmt.Fprintf(w, "      missing %-16s %s\n", p.ID, p.Title)
+		}
+	}
+
+	if len(out.UnknownTopics) > 0 {
+		unknown := append([]string(nil), out.UnknownTopics...)
+		sort.Strings(unknown)
+		fmt.Fprintf(w, "\nexamples refer to topics that aren't in the Tour: %s\n", strings.Join(unknown, ", "))
+	}
+	if len(out.Stale) > 0 {
+		fmt.Fprintln(w, "\nregistered examples that no longer exist:")
+		for _, s := range out.Stale {
+			fmt.Fprintf(w, "  %s in %s\n", s.Name, s.Dir)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"tour/grader"
+)
+
+// --- tour grade ---
+
+// Checks the solutions to the Tour's exercises, in the exercises module, against the grader's hidden
+// cases (see tour/grader), and prints each case as PASS or FAIL - a failure with what was got and wanted,
+// and each difference between the two.
+
+func runGrade(args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ContinueOnError)
+	root := fs.String("root", "..", "repository root that the exercises module is in")
+	timeout := fs.Duration("timeout", time.Minute, "stop an exercise's checks still running after this long")
+	list := fs.Bool("list", false, "list the exercises, and what each needs the exercises module to declare")
+	output := fs.Bool("output", false, "also print what the solutions print while they're checked")
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "usage: tour grade [flags] [exercise ...]\n\n"+
+			"With no exercises, grades all of them.\n\n"+
+			"ex. tour grade sqrt\n    tour grade -list\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want text or json)", *format)
+	}
+	if *list {
+		for _, ex := range grader.Exercises {
+			fmt.Printf("%-10s %s - %s\n           %s\n", ex.Name, ex.Title, pageTitles(ex.Pages), ex.Wants)
+		}
+		return nil
+	}
+	exercises := grader.Exercises
+	if fs.NArg() > 0 {
+		exercises = nil
+		for _, name := range fs.Args() {
+			ex, ok := grader.Lookup(name)
+			if !ok {
+				return fmt.Errorf("no exercise %q (tour grade -list shows them)", name)
+			}
+			exercises = append(exercises, ex)
+		}
+	}
+	absRoot, err := filepath.Abs(*root)
+	if err != nil {
+		return err
+	}
+
+	type graded struct {
+		Exercise string          `json:"exercise"`
+		Results  []grader.Result `json:"results"`
+		Error    string          `json:"error,omitempty"`
+	}
+	var all []graded
+	failed := 0
+	for _, ex := range exercises {
+		if *format == "text" {
+			fmt.Printf("=== %s: %s - %s ===\n", ex.Name, ex.Title, pageTitles(ex.Pages))
+		}
+		cfg := grader.Config{Root: absRoot, Exercise: ex, Timeout: *timeout}
+		if *output {
+			cfg.Stdout = os.Stdout
+		}
+		results, err := grader.Grade(context.Background(), cfg)
+		g := graded{Exercise: ex.Name, Results: results}
+		if err != nil {
+			g.Error = err.Error()
+		}
+		passed := 0
+		for _, r := range results {
+			if r.Pass {
+				passed++
+			}
+		}
+		if err != nil || passed < len(results) {
+			failed++
+		}
+		all = append(all, g)
+		if *format == "text" {
+			writeGradeText(os.Stdout, results, err)
+			if len(results) > 0 {
+				fmt.Printf("--- %d of %d passed\n\n", passed, len(results))
+			} else {
+				fmt.Print("--- not graded\n\n")
+			}
+		}
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d exercises failed", failed, len(exercises))
+	}
+	return nil
+}
+
+func writeGradeText(w io.Writer, results []grader.Result, err error) {
+	for _, r := range results {
+		if r.Pass {
+			fmt.Fprintf(w, "PASS %s\n", r.Case)
+			continue
+		}
+		fmt.Fprintf(w, "FAIL %s\n", r.Case)
+		fmt.Fprintf(w, "     got:  %s\n", r.Got)
+		if r.Want != "" { // a panic or a timeout has nothing to compare
+			fmt.Fprintf(w, "     want: %s\n", r.Want)
+		}
+		for _, d := range r.Diff {
+			fmt.Fprintf(w, "     - %s\n", strings.ReplaceAll(d, "\n", "\n       "))
+		}
+	}
+	if err != nil {
+		msg := err.Error()
+		if len(results) == 0 && strings.HasPrefix(msg, "building") {
+			msg += "(does the exercises module declare it? tour grade -list shows what each exercise needs)"
+		}
+		fmt.Fprintf(w, "FAIL %s\n", strings.ReplaceAll(strings.TrimSpace(msg), "\n", "\n     "))
+	}
+}
@@ -0,0 +1,146 @@
+//go:build tourgrade
+
+package main
+
+import (
+	"cmp"
+	"exercises"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Exercise: Web Crawler - Crawl(url string, depth int, fetcher Fetcher)
+//
+// Crawl's result is its calls to Fetch, so the checks give it a site that records them:
+// how often each URL was fetched, and how many fetches were running at once
+
+// site is a Fetcher of made up pages, each fetch taking delay - or longer, for the URLs in slow
+type site struct {
+	pages map[string][]string // URL -> the URLs on it; any other URL is "not found"
+	delay time.Duration
+	slow  map[string]time.Duration
+
+	mu                    sync.Mutex
+	fetches               map[string]int
+	inFlight, maxInFlight int
+}
+
+func newSite(delay time.Duration, pages map[string][]string) *site {
+	return &site{pages: pages, delay: delay, fetches: map[string]int{}}
+}
+
+func (s *site) Fetch(url string) (string, []string, error) {
+	s.mu.Lock()
+	s.fetches[url]++
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	time.Sleep(cmp.Or(s.slow[url], s.delay))
+	if urls, ok := s.pages[url]; ok {
+		return "body of " + url, urls, nil
+	}
+	return "", nil, fmt.Errorf("not found: %s", url)
+}
+
+// crawl runs Crawl on s, returning the fetches made and the ones still running when it returned
+func (s *site) crawl(url string, depth int) (fetches map[string]int, running int) {
+	exercises.Crawl(url, depth, s)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.fetches), s.inFlight
+}
+
+// once is each url fetched once
+func once(urls ...string) map[string]int {
+	m := map[string]int{}
+	for _, u := range urls {
+		m[u] = 1
+	}
+	return m
+}
+
+func init() {
+	tour := map[string][]string{
+		"https://golang.org/":         {"https://golang.org/pkg/", "https://golang.org/cmd/"},
+		"https://golang.org/pkg/":     {"https://golang.org/", "https://golang.org/cmd/", "https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/"},
+		"https://golang.org/pkg/fmt/": {"https://golang.org/", "https://golang.org/pkg/"},
+		"https://golang.org/pkg/os/":  {"https://golang.org/", "https://golang.org/pkg/"},
+	}
+	chain := map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"d"}, "d": {"e"}, "e": nil}
+	wide := map[string][]string{"root": nil}
+	for i := range 10 {
+		page := fmt.Sprintf("page%d", i)
+		wide["root"] = append(wide["root"], page)
+		wide[page] = []string{"root", page} // back to the root, and to itself
+	}
+	all := func(pages map[string][]string) map[string]int { return once(slices.Collect(maps.Keys(pages))...) }
+
+	for _, tc := range []struct {
+		name  string
+		pages map[string][]string
+		url   string
+		depth int
+		want  map[string]int
+	}{
+		{"the Tour's site, depth 4: each URL once", tour, "https://golang.org/", 4, once(
+			"https://golang.org/", "https://golang.org/pkg/", "https://golang.org/cmd/",
+			"https://golang.org/pkg/fmt/", "https://golang.org/pkg/os/")},
+		{"depth 1 is the first page only", tour, "https://golang.org/", 1, once("https://golang.org/")},
+		{"depth 0 fetches nothing", tour, "https://golang.org/", 0, map[string]int{}},
+		{"a chain, depth 3", chain, "a", 3, once("a", "b", "c")},
+		{"a chain, deeper than it is long", chain, "a", 100, all(chain)},
+		{"a cycle, deeper than it is long", map[string][]string{"a": {"b"}, "b": {"a"}}, "a", 100, once("a", "b")},
+		{"a page linking to itself, twice", map[string][]string{"a": {"a", "a", "b"}, "b": nil}, "a", 5, once("a", "b")},
+		{"a missing page doesn't stop the rest", map[string][]string{"a": {"gone", "b"}, "b": {"c"}, "c": nil}, "a", 3, once("a", "gone", "b", "c")},
+		{"a missing first page", map[string][]string{}, "gone", 3, once("gone")},
+		{"pages linking back to the first and each other", wide, "root", 3, all(wide)},
+	} {
+		register(Case{
+			Name: "Crawl: " + tc.name,
+			Check: func() Outcome {
+				got, running := newSite(time.Millisecond, tc.pages).crawl(tc.url, tc.depth)
+				out := Outcome{Got: showMap(got), Want: showMap(tc.want), Diff: diffMaps(got, tc.want)}
+				if running > 0 {
+					out.Diff = append(out.Diff, fmt.Sprintf("returned with %d fetches still running", running))
+				}
+				return out
+			},
+		})
+	}
+
+	register(Case{
+		Name: "Crawl fetches in parallel",
+		Check: func() Outcome {
+			s := newSite(50*time.Millisecond, wide)
+			start := time.Now()
+			s.crawl("root", 2)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			return Outcome{
+				Got:  fmt.Sprintf("at most %d fetches at once, %v for 11 pages", s.maxInFlight, elapsed),
+				Want: "10 at once: the root, then its 10 links together",
+				Diff: fails(s.maxInFlight < 10, "one at a time the 11 fetches take 550ms, and all the links at once 100ms"),
+			}
+		},
+	})
+	register(Case{
+		Name: "Crawl waits for its slowest fetch",
+		Check: func() Outcome {
+			s := newSite(0, wide)
+			s.slow = map[string]time.Duration{"page9": 300 * time.Millisecond}
+			got, running := s.crawl("root", 2)
+			return Outcome{
+				Got: fmt.Sprintf("%d fetches running at return", running), Want: "0 fetches running at return",
+				Diff: fails(running > 0 || got["page9"] != 1, "Crawl returned before page9's fetch did - a sync.WaitGroup, or counting the fetches left, makes it wait"),
+			}
+		},
+	})
+}
@@ -0,0 +1,196 @@
+//go:build tourgrade
+
+// The program `tour grade` builds: this file and one exercise's checks (ex. sqrt.go), added to the
+// exercises module through go build's -overlay, as package main in a directory of their own.
+// The tourgrade build tag keeps them out of the tour module's build - they import the exercises
+// module, which tour can't - while gofmt and an editor still see them as the Go files they are.
+//
+// Each case's result is written to the file named by the first argument as it finishes, one JSON
+// object per line: if a case crashes the whole program (a fatal error recover can't catch, like a
+// concurrent map write), the ones before it are still reported. Stdout is the exercise's own output.
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A Case is one hidden test. Check returns an Outcome, which fails if it has any Diff lines
+type Case struct {
+	Name    string
+	Timeout time.Duration // 0 is defaultTimeout
+	Check   func() Outcome
+}
+
+const defaultTimeout = 2 * time.Second
+
+// Outcome is what a Case's check saw: Got and Want in short, and each difference between them
+type Outcome struct {
+	Got, Want string
+	Diff      []string
+}
+
+// result is the line written for each case; tour/grader reads it into a grader.Result
+type result struct {
+	Case string   `json:"case"`
+	Pass bool     `json:"pass"`
+	Got  string   `json:"got,omitempty"`
+	Want string   `json:"want,omitempty"`
+	Diff []string `json:"diff,omitempty"`
+}
+
+var cases []Case
+
+// register adds an exercise file's cases, from its init function
+func register(cs ...Case) { cases = append(cases, cs...) }
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: check <results file>")
+		os.Exit(2)
+	}
+	f, err := os.Create(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	enc := json.NewEncoder(f)
+	for _, c := range cases {
+		out := run(c)
+		r := result{Case: c.Name, Pass: len(out.Diff) == 0, Got: out.Got, Want: out.Want, Diff: out.Diff}
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run runs c's check in a goroutine of its own, so a panic fails the case rather than the program,
+// and a check still blocked after the timeout can be left behind (the program exits once all are run)
+func run(c Case) Outcome {
+	done := make(chan Outcome, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- Outcome{Got: "a panic", Diff: append([]string{fmt.Sprintf("panicked: %v", v)}, panicSite(debug.Stack())...)}
+			}
+		}()
+		done <- c.Check()
+	}()
+	timeout := cmp.Or(c.Timeout, defaultTimeout)
+	select {
+	case out := <-done:
+		return out
+	case <-time.After(timeout):
+		return Outcome{Got: "no result in time", Diff: []string{fmt.Sprintf("didn't return within %v - blocked on a channel or a lock?", timeout)}}
+	}
+}
+
+// panicSite is the frames of a stack trace between the panic and the check that called the solution:
+// where in the solution it panicked, without the harness and runtime frames around it
+func panicSite(stack []byte) []string {
+	lines := strings.Split(string(stack), "\n")
+	var site []string
+	for i, l := range lines {
+		if !strings.HasPrefix(l, "panic(") {
+			continue
+		}
+		// each frame is two lines: the function, then its file and line, indented
+		for j := i + 2; j+1 < len(lines) && !strings.Contains(lines[j+1], "/zz_tour_grade/"); j += 2 {
+			site = append(site, "at "+lines[j]+" "+strings.TrimSpace(lines[j+1]))
+		}
+		break
+	}
+	return site
+}
+
+// showMap is m with its keys sorted and Go-quoted, so a key of spaces or "" can be seen
+func showMap[K cmp.Ordered, V any](m map[K]V) string {
+	parts := []string{}
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		parts = append(parts, fmt.Sprintf("%#v: %v", k, m[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// diffStrings describes where got first differs from want, with a little of each around it
+func diffStrings(got, want string) []string {
+	if got == want {
+		return nil
+	}
+	i := 0
+	for i < len(got) && i < len(want) && got[i] == want[i] {
+		i++
+	}
+	around := func(s string) string {
+		return fmt.Sprintf("%q", s[max(0, i-10):min(len(s), i+10)])
+	}
+	return []string{
+		fmt.Sprintf("differs at byte %d: got %s, want %s", i, around(got), around(want)),
+		fmt.Sprintf("length: got %d, want %d", len(got), len(want)),
+	}
+}
+
+// diffMaps lists the keys got is missing, the ones it has extra, and those with another value - by key
+func diffMaps[K cmp.Ordered, V comparable](got, want map[K]V) []string {
+	var diff []string
+	for _, k := range slices.Sorted(maps.Keys(want)) {
+		g, ok := got[k]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("missing %#v (want %v)", k, want[k]))
+		case g != want[k]:
+			diff = append(diff, fmt.Sprintf("%#v: got %v, want %v", k, g, want[k]))
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(got)) {
+		if _, ok := want[k]; !ok {
+			diff = append(diff, fmt.Sprintf("extra %#v: %v", k, got[k]))
+		}
+	}
+	return diff
+}
+
+// diffSlices names the first index at which got and want differ, or the one that's longer
+func diffSlices[T comparable](got, want []T) []string {
+	for i := range min(len(got), len(want)) {
+		if got[i] != want[i] {
+			return []string{fmt.Sprintf("[%d]: got %v, want %v", i, got[i], want[i])}
+		}
+	}
+	switch {
+	case len(got) < len(want):
+		return []string{fmt.Sprintf("%d short: missing %v", len(want)-len(got), want[len(got):])}
+	case len(got) > len(want):
+		return []string{fmt.Sprintf("%d extra: %v", len(got)-len(want), got[len(want):])}
+	}
+	return nil
+}
+
+// fails is an Outcome's Diff of one line, if failed
+func fails(failed bool, format string, args ...any) []string {
+	if !failed {
+		return nil
+	}
+	return []string{fmt.Sprintf(format, args...)}
+}
+
+// short is s, cut to n bytes with an ellipsis, for Got and Want
+func short(s string, n int) string {
+	if len(s) <= n {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%q... (%d bytes)", s[:n], len(s))
+}
@@ -0,0 +1,180 @@
+//go:build tourgrade
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"exercises"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Exercise: rot13Reader - NewRot13Reader(r io.Reader) io.Reader
+
+// rot13 is the grader's own ROT13, to work out what's wanted for any input. Byte by byte, not with
+// strings.Map: that would turn bytes that aren't UTF-8 into U+FFFD
+func rot13(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case 'a' <= c && c <= 'z':
+			b[i] = 'a' + (c-'a'+13)%26
+		case 'A' <= c && c <= 'Z':
+			b[i] = 'A' + (c-'A'+13)%26
+		}
+	}
+	return string(b)
+}
+
+// Readers that read the way a real one may, each wrapping r: the rot13Reader has to cope with all of them
+
+// oneByteReader returns at most one byte per Read
+type oneByteReader struct{ r io.Reader }
+
+func (r oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return r.r.Read(p[:1])
+}
+
+// halfReader reads half of what's asked, rounded up
+type halfReader struct{ r io.Reader }
+
+func (r halfReader) Read(p []byte) (int, error) { return r.r.Read(p[:(len(p)+1)/2]) }
+
+// dataEOFReader returns io.EOF with the last bytes, rather than from a Read of its own after them
+type dataEOFReader struct {
+	r    io.Reader
+	next []byte // read ahead, so the last Read knows it's the last
+	err  error
+}
+
+func (r *dataEOFReader) Read(p []byte) (int, error) {
+	if r.next == nil && r.err == nil {
+		r.next, r.err = readSome(r.r)
+	}
+	n := copy(p, r.next)
+	r.next = r.next[n:]
+	if len(r.next) == 0 && r.err == nil {
+		r.next, r.err = readSome(r.r)
+	}
+	if len(r.next) == 0 {
+		return n, r.err
+	}
+	return n, nil
+}
+
+func readSome(r io.Reader) ([]byte, error) {
+	buf := make([]byte, 512)
+	n, err := r.Read(buf)
+	return buf[:n], err
+}
+
+// errReader fails every Read with err
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// checkReader reads a fresh reader from newReader to the end with buffers of several sizes: each time it
+// has to give want, never count more bytes than the buffer holds, and keep returning io.EOF once it has
+func checkReader(newReader func() io.Reader, want []byte) error {
+	for _, size := range []int{1, 3, 64, len(want) + 1} {
+		r, buf := newReader(), make([]byte, size)
+		var got []byte
+		for reads := 0; ; reads++ {
+			if reads > len(want)+100 {
+				return fmt.Errorf("%d-byte reads: no io.EOF after %d Reads", size, reads)
+			}
+			n, err := r.Read(buf)
+			if n < 0 || n > size {
+				return fmt.Errorf("%d-byte reads: Read returned %d bytes", size, n)
+			}
+			got = append(got, buf[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("%d-byte reads: %v", size, err)
+			}
+		}
+		if !bytes.Equal(got, want) {
+			return fmt.Errorf("%d-byte reads: got %q, want %q", size, got, want)
+		}
+		if n, err := r.Read(buf); n != 0 || err != io.EOF {
+			return fmt.Errorf("%d-byte reads: a Read after io.EOF returned %d, %v, want 0, io.EOF", size, n, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	all := func() string {
+		var b strings.Builder
+		for c := range 256 {
+			b.WriteByte(byte(c))
+		}
+		return b.String()
+	}()
+	for _, tc := range []struct {
+		name, in string
+		wrap     func(io.Reader) io.Reader // how the rot13Reader's own reader reads, ex. a byte at a time
+	}{
+		{"the Tour's message", "Lbh penpxrq gur pbqr!", nil},
+		{"both ends of the alphabet", "abcmnzABCMNZ", nil},
+		{"every byte value - only letters change", all, nil},
+		{"UTF-8 is left as it is", "Grüße, 世界 - ñ", nil},
+		{"the empty string", "", nil},
+		{"a reader giving a byte at a time", "Uryyb, Tbcuref!", func(r io.Reader) io.Reader { return oneByteReader{r} }},
+		{"a reader giving half what's asked", strings.Repeat("Gur Tb gbhe ", 300), func(r io.Reader) io.Reader { return halfReader{r} }},
+		{"data and io.EOF from the same Read", "Ynfg olgrf jvgu RBS", func(r io.Reader) io.Reader { return &dataEOFReader{r: r} }},
+	} {
+		register(Case{
+			Name: "rot13Reader: " + tc.name,
+			Check: func() Outcome {
+				var r io.Reader = strings.NewReader(tc.in)
+				if tc.wrap != nil {
+					r = tc.wrap(r)
+				}
+				got, err := io.ReadAll(exercises.NewRot13Reader(r))
+				want := rot13(tc.in)
+				out := Outcome{Got: short(string(got), 40), Want: short(want, 40), Diff: diffStrings(string(got), want)}
+				if err != nil {
+					out.Diff = append(out.Diff, "io.ReadAll failed: "+err.Error())
+				}
+				return out
+			},
+		})
+	}
+	register(Case{
+		Name: "rot13Reader passes on its reader's error",
+		Check: func() Outcome {
+			boom := errors.New("disk on fire")
+			_, err := io.ReadAll(exercises.NewRot13Reader(errReader{boom}))
+			return Outcome{Got: fmt.Sprint(err), Want: boom.Error(), Diff: fails(!errors.Is(err, boom), "the error isn't the reader's")}
+		},
+	})
+	register(Case{
+		Name: "rot13Reader behaves as an io.Reader should, whatever size its reads",
+		Check: func() Outcome {
+			in := "Gur dhvpx oebja sbk whzcf bire gur ynml qbt."
+			err := checkReader(func() io.Reader { return exercises.NewRot13Reader(strings.NewReader(in)) }, []byte(rot13(in)))
+			return Outcome{Got: fmt.Sprint(err), Want: "<nil>", Diff: fails(err != nil, "%v", err)}
+		},
+	})
+	register(Case{
+		Name: "rot13Reader applied twice is the original",
+		Check: func() Outcome {
+			in := "Round trip: The Go Programming Language"
+			var got bytes.Buffer
+			_, err := io.Copy(&got, exercises.NewRot13Reader(exercises.NewRot13Reader(strings.NewReader(in))))
+			out := Outcome{Got: short(got.String(), 40), Want: short(in, 40), Diff: diffStrings(got.String(), in)}
+			if err != nil {
+				out.Diff = append(out.Diff, "io.Copy failed: "+err.Error())
+			}
+			return out
+		},
+	})
+}
@@ -0,0 +1,53 @@
+//go:build tourgrade
+
+package main
+
+import (
+	"errors"
+	"exercises"
+	"fmt"
+	"math"
+)
+
+// Exercise: Loops and Functions, and Exercise: Errors - Sqrt(x float64) (float64, error)
+
+func init() {
+	for _, x := range []float64{2, 4, 9, 0.25, 1e-6, 3e-10, 1e10, 123456789, 1e300, 0, 1} {
+		register(Case{
+			Name: fmt.Sprintf("Sqrt(%v)", x),
+			Check: func() Outcome {
+				z, err := exercises.Sqrt(x)
+				want := math.Sqrt(x)
+				out := Outcome{Got: fmt.Sprintf("%v, %v", z, err), Want: fmt.Sprintf("%v, <nil>", want)}
+				// close, not equal: Newton's method can stop a float or two from math.Sqrt's answer.
+				// Relative to the root, except near 0 - where the Tour's fixed 10 steps end far off
+				if err != nil {
+					out.Diff = []string{"unexpected error: " + err.Error()}
+				} else if d := math.Abs(z - want); d > 1e-12*max(want, 1) {
+					out.Diff = []string{fmt.Sprintf("off by %g (allowed: %g)", z-want, 1e-12*max(want, 1))}
+				}
+				return out
+			},
+		})
+	}
+	for _, x := range []float64{-2, -0.5, math.Inf(-1)} {
+		register(Case{
+			Name: fmt.Sprintf("Sqrt(%v) is an ErrNegativeSqrt", x),
+			Check: func() Outcome {
+				_, err := exercises.Sqrt(x)
+				var neg exercises.ErrNegativeSqrt
+				wantMsg := fmt.Sprint("cannot Sqrt negative number: ", x)
+				out := Outcome{Got: fmt.Sprintf("%#v", err), Want: fmt.Sprintf("ErrNegativeSqrt(%v) %q", x, wantMsg)}
+				switch {
+				case !errors.As(err, &neg):
+					out.Diff = []string{fmt.Sprintf("the error is %T, not an ErrNegativeSqrt", err)}
+				case float64(neg) != x:
+					out.Diff = []string{fmt.Sprintf("it holds %v, not %v", float64(neg), x)}
+				default:
+					out.Diff = diffStrings(err.Error(), wantMsg)
+				}
+				return out
+			},
+		})
+	}
+}
@@ -0,0 +1,127 @@
+//go:build tourgrade
+
+package main
+
+import (
+	"exercises"
+	"exercises/tree"
+	"fmt"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// Exercise: Equivalent Binary Trees - Walk(t *tree.Tree, ch chan int), Same(t1, t2 *tree.Tree) bool
+
+// build inserts vs in their order - so sorted values make a tree that is one long right branch
+func build(vs ...int) *tree.Tree {
+	var t *tree.Tree
+	for _, v := range vs {
+		t = tree.Insert(t, v)
+	}
+	return t
+}
+
+// walk collects what Walk sends. The Tour leaves it open whether Walk closes ch, so either is fine:
+// if it did, closing it here again panics, and that's recovered
+func walk(t *tree.Tree) []int {
+	ch := make(chan int)
+	go func() {
+		defer func() { recover() }()
+		exercises.Walk(t, ch)
+		close(ch)
+	}()
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+	return got
+}
+
+func multiples(k, n int) []int {
+	vs := make([]int, n)
+	for i := range vs {
+		vs[i] = (i + 1) * k
+	}
+	return vs
+}
+
+func reversed(vs []int) []int {
+	vs = slices.Clone(vs)
+	slices.Reverse(vs)
+	return vs
+}
+
+func init() {
+	for _, tc := range []struct {
+		name string
+		t    *tree.Tree
+		want []int
+	}{
+		{"Walk(tree.New(1))", tree.New(1), multiples(1, 10)},
+		{"Walk(tree.New(7))", tree.New(7), multiples(7, 10)},
+		{"Walk of a tree that is all right branches", build(1, 2, 3, 4, 5), []int{1, 2, 3, 4, 5}},
+		{"Walk of a tree that is all left branches", build(5, 4, 3, 2, 1), []int{1, 2, 3, 4, 5}},
+		{"Walk of a balanced tree", build(4, 2, 6, 1, 3, 5, 7), []int{1, 2, 3, 4, 5, 6, 7}},
+		{"Walk of a tree with equal values", build(5, 3, 5, 3), []int{3, 3, 5, 5}},
+		{"Walk of a single node", build(42), []int{42}},
+		{"Walk(nil) sends nothing", nil, nil},
+	} {
+		register(Case{
+			Name: tc.name,
+			Check: func() Outcome {
+				got := walk(tc.t)
+				return Outcome{Got: fmt.Sprint(got), Want: fmt.Sprint(tc.want), Diff: diffSlices(got, tc.want)}
+			},
+		})
+	}
+
+	shuffled := build(6, 3, 9, 1, 4, 8, 10, 2, 5, 7)
+	for _, tc := range []struct {
+		name   string
+		t1, t2 *tree.Tree
+		want   bool
+	}{
+		{"Same(tree.New(1), tree.New(1))", tree.New(1), tree.New(1), true},
+		{"Same(tree.New(1), tree.New(2))", tree.New(1), tree.New(2), false},
+		{"Same: same values in different shapes", build(multiples(1, 10)...), shuffled, true},
+		{"Same: one tree twice", shuffled, shuffled, true},
+		{"Same: the first tree a prefix of the second", build(multiples(1, 9)...), tree.New(1), false},
+		{"Same: the second tree a prefix of the first", tree.New(1), build(multiples(1, 9)...), false},
+		{"Same: only the largest value differs", build(5, 2, 8, 1, 9), build(5, 2, 8, 1, 10), false},
+		{"Same: more than 10 values", build(multiples(3, 50)...), build(reversed(multiples(3, 50))...), true},
+		{"Same(nil, nil)", nil, nil, true},
+		{"Same(nil, tree.New(1))", nil, tree.New(1), false},
+	} {
+		register(Case{
+			Name: tc.name,
+			Check: func() Outcome {
+				got := exercises.Same(tc.t1, tc.t2)
+				return Outcome{Got: fmt.Sprint(got), Want: fmt.Sprint(tc.want), Diff: fails(got != tc.want, "%v and %v", tc.t1, tc.t2)}
+			},
+		})
+	}
+
+	register(Case{
+		Name:    "Same doesn't leave goroutines blocked when the trees differ",
+		Timeout: 5 * time.Second,
+		Check: func() Outcome {
+			before := runtime.NumGoroutine()
+			for range 100 {
+				exercises.Same(tree.New(1), tree.New(2))
+			}
+			// the walks may still be finishing - give them a moment
+			var after int
+			for range 100 {
+				if after = runtime.NumGoroutine(); after <= before {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			return Outcome{
+				Got: fmt.Sprintf("%d goroutines left", max(after-before, 0)), Want: "0 goroutines left",
+				Diff: fails(after > before, "returning at the first difference leaves the walks blocked on their next send"),
+			}
+		},
+	})
+}
@@ -0,0 +1,41 @@
+//go:build tourgrade
+
+package main
+
+import (
+	"exercises"
+	"strings"
+)
+
+// Exercise: Maps - WordCount(s string) map[string]int
+
+func init() {
+	for _, tc := range []struct {
+		name, s string
+		want    map[string]int
+	}{
+		{"a short sentence", "I am learning Go!", map[string]int{"I": 1, "am": 1, "learning": 1, "Go!": 1}},
+		{"the Tour's fox", "The quick brown fox jumped over the lazy dog.",
+			map[string]int{"The": 1, "quick": 1, "brown": 1, "fox": 1, "jumped": 1, "over": 1, "the": 1, "lazy": 1, "dog.": 1}},
+		{"repeated words", "I ate a donut. Then I ate another donut.",
+			map[string]int{"I": 2, "ate": 2, "a": 1, "donut.": 2, "Then": 1, "another": 1}},
+		{"case matters", "a A a A a", map[string]int{"a": 3, "A": 2}},
+		{"the empty string", "", map[string]int{}},
+		{"only spaces", "   \t\n ", map[string]int{}},
+		{"tabs, newlines and runs of spaces", "  one\ttwo\n\nthree   two\r\none ", map[string]int{"one": 2, "two": 2, "three": 1}},
+		{"non-ASCII words and spaces", "héllo wörld\u00a0héllo 世界", map[string]int{"héllo": 2, "wörld": 1, "世界": 1}},
+		{"one word many times", strings.Repeat("go ", 1000), map[string]int{"go": 1000}},
+	} {
+		register(Case{
+			Name: "WordCount: " + tc.name,
+			Check: func() Outcome {
+				got := exercises.WordCount(tc.s)
+				out := Outcome{Got: showMap(got), Want: showMap(tc.want), Diff: diffMaps(got, tc.want)}
+				if got == nil {
+					out.Diff = append(out.Diff, "returned a nil map - fine to read, but a caller adding to it would panic")
+				}
+				return out
+			},
+		})
+	}
+}
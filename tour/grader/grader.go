@@ -0,0 +1,185 @@
+package grader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// The grader checks the exercises module's solutions to the Tour's exercises against cases they
+// haven't been written against: the checks are here, in checks/, embedded into the tour binary.
+//
+// Grading an exercise is the runner's trick again (see tour/runner): `go build` of the exercises module
+// with extra files added through -overlay - here a whole package main, in a directory that doesn't exist
+// on disk, made of checks/harness.go and the exercise's own file. The checks import the exercises module
+// as any other code would, so they only see what it exports. They're built with the tourgrade tag,
+// which the checks' files need: without it the tour module's own build skips them.
+
+// Module is the module the solutions are in - the import path the checks use - relative to the root
+const Module = "exercises"
+
+// gradeDir is the directory the checks are built as, inside Module
+const gradeDir = "zz_tour_grade"
+
+//go:embed checks/*.go
+var checks embed.FS
+
+// Exercise is one of the Tour's exercises
+type Exercise struct {
+	Name  string   // ex. "sqrt", for tour grade
+	Title string   // the Tour's name for it
+	Pages []string // the Tour pages it is on
+	Wants string   // what the exercises module has to declare
+	file  string   // in checks/
+}
+
+var Exercises = []Exercise{
+	{"sqrt", "Sqrt", []string{"flowcontrol/8", "methods/20"},
+		"func Sqrt(x float64) (float64, error); type ErrNegativeSqrt float64", "sqrt.go"},
+	{"wordcount", "WordCount", []string{"moretypes/23"},
+		"func WordCount(s string) map[string]int", "wordcount.go"},
+	{"rot13", "rot13Reader", []string{"methods/23"},
+		"func NewRot13Reader(r io.Reader) io.Reader", "rot13.go"},
+	{"trees", "Equivalent Binary Trees", []string{"concurrency/7", "concurrency/8"},
+		"func Walk(t *tree.Tree, ch chan int); func Same(t1, t2 *tree.Tree) bool", "trees.go"},
+	{"crawl", "Web Crawler", []string{"concurrency/10"},
+		"func Crawl(url string, depth int, fetcher Fetcher); type Fetcher interface", "crawl.go"},
+}
+
+// Lookup returns the exercise with the name, or false
+func Lookup(name string) (Exercise, bool) {
+	for _, ex := range Exercises {
+		if ex.Name == name {
+			return ex, true
+		}
+	}
+	return Exercise{}, false
+}
+
+// Result is how one case went
+type Result struct {
+	Case string   `json:"case"`
+	Pass bool     `json:"pass"`
+	Got  string   `json:"got,omitempty"`
+	Want string   `json:"want,omitempty"`
+	Diff []string `json:"diff,omitempty"` // each difference between Got and Want, for a failed case
+}
+
+// Config says which exercise to grade, and where
+type Config struct {
+	Root     string // repository root; Module is relative to it
+	Exercise Exercise
+
+	// Timeout, if set, stops the checks still running after this long. Each case has a timeout of its
+	// own as well (2s, more for a few) - this is for a case that hangs the whole program
+	Timeout time.Duration
+
+	// Stdout gets what the solutions print, ex. Crawl's "found:" lines. Nil discards it
+	Stdout io.Writer
+}
+
+// Grade builds and runs the exercise's checks, and returns each case's result.
+// The error is the compiler's output for solutions that don't build (ex. a function missing, or with
+// another signature), or why the checks stopped early - with the results of the cases before that
+func Grade(ctx context.Context, cfg Config) ([]Result, error) {
+	modDir, err := filepath.Abs(filepath.Join(cfg.Root, Module))
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "tour-grade")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	replace := map[string]string{}
+	for _, name := range []string{"harness.go", cfg.Exercise.file} {
+		src, err := checks.ReadFile("checks/" + name)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(tmp, name)
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			return nil, err
+		}
+		replace[filepath.Join(modDir, gradeDir, name)] = path
+	}
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": replace})
+	if err != nil {
+		return nil, err
+	}
+	overlayPath := filepath.Join(tmp, "overlay.json")
+	if err := os.WriteFile(overlayPath, overlay, 0o644); err != nil {
+		return nil, err
+	}
+
+	bin := filepath.Join(tmp, "check")
+	build := exec.CommandContext(ctx, "go", "build", "-overlay="+overlayPath, "-tags=tourgrade", "-o", bin, "./"+gradeDir)
+	build.Dir = modDir
+	build.Env = append(os.Environ(), "CGO_ENABLED=0") // as the runner does, so a deadlock is reported rather than hanging
+	if out, err := build.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("building the checks: %w\n%s", err, out)
+	}
+
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if cfg.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+	}
+	defer cancel()
+
+	resultsPath := filepath.Join(tmp, "results.jsonl")
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, bin, resultsPath)
+	cmd.Dir = modDir
+	cmd.Stdout = cfg.Stdout
+	cmd.Stderr = &stderr
+	cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGQUIT) } // for the goroutines' stacks
+	cmd.WaitDelay = 5 * time.Second
+	runErr := cmd.Run()
+
+	results, err := readResults(resultsPath)
+	if err != nil {
+		return results, err
+	}
+	if runErr != nil {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			runErr = fmt.Errorf("timed out after %v", cfg.Timeout)
+		}
+		return results, fmt.Errorf("the checks stopped after %d cases: %w\n%s", len(results), runErr, stderr.Bytes())
+	}
+	return results, nil
+}
+
+// readResults reads the results file, one Result per line. A missing file is no results:
+// the checks didn't get as far as creating it
+func readResults(path string) ([]Result, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var results []Result
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20) // a panic's stack trace is in its Diff
+	for sc.Scan() {
+		var r Result
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return results, fmt.Errorf("reading the results: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, sc.Err()
+}
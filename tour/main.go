@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// === tour - a small command for working with the notes themselves ===
+
+// Usage: go run . <command> [flags] (from the tour directory)
+//
+// Each subcommand has its own flag.FlagSet, so flags are parsed after the command name:
+// go run . coverage -format json
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"coverage", "list Tour pages that have no runnable example yet", runCoverage},
+	{"contention", "run examples with mutex and block profiling, and show where goroutines waited", runContention},
+	{"run", "run examples under a timeout, explaining any that deadlock, panic or hang", runRun},
+	{"quiz", "answer questions about the Tour's pages", runQuiz},
+	{"serve", "serve the examples over HTTP: list them, and run one returning its output", runServe},
+	{"progress", "show which examples have been run and quiz questions answered, per lesson", runProgress},
+	{"grade", "check the solutions to the Tour's exercises against hidden cases, showing what differs", runGrade},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tour <command> [flags]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nrun 'tour <command> -h' for the command's flags")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, "tour "+c.name+":", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "tour: unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
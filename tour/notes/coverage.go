@@ -0,0 +1,66 @@
+package notes
+
+// Coverage cross-references the Tour's pages against the examples covering them.
+
+type LessonCoverage struct {
+	Chapter string `json:"chapter"`
+	Lesson  string `json:"lesson"`
+	Covered int    `json:"covered"`
+	Total   int    `json:"total"`
+	Missing []Page `json:"missing"`
+}
+
+type CoverageReport struct {
+	Covered int              `json:"covered"`
+	Total   int              `json:"total"`
+	Lessons []LessonCoverage `json:"lessons"`
+
+	// Topics named by an example that aren't pages of the Tour (typos in the registry)
+	UnknownTopics []string `json:"unknown_topics,omitempty"`
+}
+
+// Percent is the share of Tour pages with at least one example.
+func (r CoverageReport) Percent() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return 100 * float64(r.Covered) / float64(r.Total)
+}
+
+// Coverage reports which pages of lessons have no example in examples.
+func Coverage(lessons []Lesson, examples []Example) CoverageReport {
+	covered := make(map[string]bool)
+	for _, ex := range examples {
+		for _, id := range ex.Topics {
+			covered[id] = true
+		}
+	}
+
+	var report CoverageReport
+	known := make(map[string]bool)
+	for _, l := range lessons {
+		lc := LessonCoverage{Chapter: l.Chapter, Lesson: l.Title, Total: len(l.Pages), Missing: []Page{}}
+		for _, p := range l.Pages {
+			known[p.ID] = true
+			if covered[p.ID] {
+				lc.Covered++
+			} else {
+				lc.Missing = append(lc.Missing, p)
+			}
+		}
+		report.Covered += lc.Covered
+		report.Total += lc.Total
+		report.Lessons = append(report.Lessons, lc)
+	}
+
+	seen := make(map[string]bool)
+	for _, ex := range examples {
+		for _, id := range ex.Topics {
+			if !known[id] && !seen[id] {
+				seen[id] = true
+				report.UnknownTopics = append(report.UnknownTopics, id)
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,392 @@
+package notes
+
+// Example is a runnable function in one of the repo's modules.
+// The modules don't import each other, so examples are registered by name (as data),
+// not as func values - the tour command checks each entry against the source.
+type Example struct {
+	Name   string   `json:"name"`   // function name, ex. "mapExample"
+	Dir    string   `json:"dir"`    // package directory relative to the repo root, ex. "basics/main"
+	Topics []string `json:"topics"` // Tour pages it covers (Page IDs). Empty for notes beyond the Tour
+}
+
+// Examples lists every example function, grouped by module in the order of each module's main().
+// Add an entry here when adding an example.
+var Examples = []Example{
+	// basics
+	{"add", "basics/main", []string{"basics/4", "basics/5"}},
+	{"swap", "basics/main", []string{"basics/6", "basics/10"}},
+	{"split", "basics/main", []string{"basics/7"}},
+	{"whileExample", "basics/main", []string{"flowcontrol/1", "flowcontrol/3"}},
+	{"switchExample", "basics/main", []string{"flowcontrol/11"}},
+	{"arrExample", "basics/main", []string{"moretypes/6"}},
+	{"sliceExample", "basics/main", []string{"moretypes/7", "moretypes/9", "moretypes/10", "moretypes/11", "moretypes/12", "moretypes/13", "moretypes/14", "moretypes/15"}},
+	{"rangeForLoopEx", "basics/main", []string{"moretypes/16", "moretypes/17"}},
+	{"mapExample", "basics/main", []string{"moretypes/19", "moretypes/20", "moretypes/21", "moretypes/22"}},
+	{"FunctionValuesEx", "basics/main", []string{"moretypes/24"}},
+	{"fileStoreExample", "basics/main", nil},
+	{"semaphoreExample", "basics/main", []string{"concurrency/3"}},
+	{"snapshotExample", "basics/main", []string{"methods/9"}},
+	{"mapJSONExample", "basics/main", nil},
+	{"jsonStreamExample", "basics/main", nil},
+	{"reverseLookupExample", "basics/main", []string{"moretypes/19", "moretypes/22"}},
+	{"singleflightExample", "basics/main", nil},
+	{"ttlCacheExample", "basics/main", nil},
+	{"sliceutilExample", "basics/main", []string{"moretypes/16", "generics/1"}},
+	{"batchesExample", "basics/main", []string{"moretypes/7", "concurrency/2"}},
+	{"fileFormatsExample", "basics/main", []string{"moretypes/16"}},
+	{"filesExample", "basics/main", nil},
+	{"diskStoreExample", "basics/main", []string{"methods/9"}},
+	{"uploadLoggingExample", "basics/main", []string{"methods/9"}},
+	{"checksumUploadExample", "basics/main", []string{"methods/9"}},
+	{"uploadErrorsExample", "basics/main", []string{"methods/19"}},
+	{"uploadStatusExample", "basics/main", []string{"methods/17", "basics/16"}},
+	{"uploadHTTPExample", "basics/main", []string{"methods/9", "methods/21"}},
+	{"shardMapExample", "basics/main", []string{"moretypes/19", "concurrency/9"}},
+	{"lookupTableBenchmarkExample", "basics/main", []string{"concurrency/9"}},
+
+	// multiplepackages
+	{"main", "multiplepackages/main", []string{"basics/1", "basics/2", "basics/3"}},
+
+	// methodsinterfaces
+	{"methodExamples", "methodsinterfaces", []string{"methods/1", "methods/3", "methods/4", "methods/6", "methods/7", "methods/8"}},
+	{"interfaceExamples", "methodsinterfaces", []string{"methods/9", "methods/10", "methods/11", "methods/12", "methods/13", "methods/14", "methods/15", "methods/16", "methods/17", "methods/19", "methods/21"}},
+	{"sortingExamples", "methodsinterfaces", nil},
+	{"writerExamples", "methodsinterfaces", nil},
+	{"interfaceEmbeddingExamples", "methodsinterfaces", nil},
+	{"formatterExamples", "methodsinterfaces", []string{"methods/17"}},
+	{"methodSetExamples", "methodsinterfaces", []string{"methods/6", "methods/7", "methods/8"}},
+	{"typedNilExamples", "methodsinterfaces", []string{"methods/12"}},
+	{"shapeExamples", "methodsinterfaces", []string{"methods/9", "methods/16"}},
+	{"textMarshalerExamples", "methodsinterfaces", nil},
+	{"uploaderRegistryExamples", "methodsinterfaces", []string{"methods/10"}},
+	{"interfaceEqualityExamples", "methodsinterfaces", []string{"methods/11"}},
+	{"randomAccessExamples", "methodsinterfaces", []string{"methods/21"}},
+	{"nilReceiverExamples", "methodsinterfaces", []string{"methods/12"}},
+	{"loggingAllocExample", "methodsinterfaces", nil},
+	{"interfaceUpgradeExamples", "methodsinterfaces", []string{"methods/15"}},
+	{"receiverCostExamples", "methodsinterfaces", []string{"methods/4", "methods/8"}},
+	{"escapeAnalysisExamples", "methodsinterfaces", []string{"methods/8"}},
+
+	// errorsdeep
+	{"wrappingExample", "errorsdeep", []string{"methods/19"}},
+	{"customErrorTypeExample", "errorsdeep", []string{"methods/19"}},
+	{"customIsExample", "errorsdeep", nil},
+	{"joinExample", "errorsdeep", nil},
+	{"deferredCloseExamples", "errorsdeep", []string{"flowcontrol/12"}},
+	{"retryExample", "errorsdeep", []string{"generics/1"}},
+
+	// generics
+	{"main", "generics", []string{"generics/1"}},
+	{"compareByExample", "generics", []string{"generics/1"}},
+	{"unitSafetyExample", "generics", []string{"generics/2"}},
+	{"treePrintExample", "generics", []string{"generics/2"}},
+	{"constraintsExample", "generics", []string{"generics/1"}},
+	{"numbersExample", "generics", []string{"generics/1"}},
+	{"collectionsExample", "generics", []string{"generics/2"}},
+	{"setExample", "generics", []string{"generics/1", "generics/2"}},
+	{"llistExample", "generics", []string{"generics/2"}},
+	{"optionResultExample", "generics", []string{"generics/2"}},
+	{"lruExample", "generics", []string{"generics/2"}},
+	{"priorityQueueExample", "generics", []string{"generics/2"}},
+	{"paginateExample", "generics", []string{"generics/2"}},
+	{"eventBusExample", "generics", []string{"generics/1"}},
+	{"iteratorsExample", "generics", []string{"generics/2", "concurrency/4"}},
+	{"sortByExample", "generics", []string{"generics/2"}},
+	{"flyerExample", "generics", []string{"generics/2"}},
+	{"graphExample", "generics", []string{"generics/2"}},
+	{"genericsBenchmarkExample", "generics", []string{"generics/1"}},
+	{"genericMethodsExample", "generics", []string{"generics/2", "methods/17"}},
+
+	// concurrency
+	{"goroutineExample", "concurrency", []string{"concurrency/1"}},
+	{"channelExample", "concurrency", []string{"concurrency/2"}},
+	{"bufferedChannelsExample", "concurrency", []string{"concurrency/3"}},
+	// these stop the program on purpose - run them with tour run, which explains how they ended
+	{"deadlockExampleOverfilledBufferBlock", "concurrency", []string{"concurrency/3"}},
+	{"deadlockExampleEmptyBufferBlock", "concurrency", []string{"concurrency/3"}},
+	{"hiddenDeadlockExample", "concurrency", nil},
+	{"deadlockExampleUnbufferedChNoReciever", "concurrency", []string{"concurrency/3"}},
+	{"noDeadlockUnbufferedChannel", "concurrency", []string{"concurrency/3"}},
+	{"pingPongExample", "concurrency", []string{"concurrency/2", "concurrency/4"}},
+	{"mapReduceExample", "concurrency", []string{"concurrency/2"}},
+	{"testClosedChannelEx", "concurrency", []string{"concurrency/4"}},
+	{"sendingOnClosedChannelPanicEx", "concurrency", []string{"concurrency/4"}},
+	{"loopThroughValsUntilChannelClosedEx", "concurrency", []string{"concurrency/4"}},
+	{"selectEx", "concurrency", []string{"concurrency/5"}},
+	{"perOperationTimeoutExample", "concurrency", []string{"concurrency/5"}},
+	{"overallDeadlineExample", "concurrency", []string{"concurrency/5"}},
+	{"heartbeatExample", "concurrency", []string{"concurrency/5"}},
+	{"tickerInSelectExample", "concurrency", []string{"concurrency/5", "concurrency/6"}},
+	{"prioritySelectExample", "concurrency", []string{"concurrency/5", "concurrency/6"}},
+	{"generatorExample", "concurrency", []string{"concurrency/2", "concurrency/4"}},
+	{"safeIncrementMutuxExample", "concurrency", []string{"concurrency/9"}},
+	{"actorCounterExample", "concurrency", []string{"concurrency/9"}},
+	{"unsafeIncrementExample", "concurrency", []string{"concurrency/9"}},
+	{"unsafeMapWriteExample", "concurrency", []string{"concurrency/9"}},
+	{"onceExample", "concurrency", []string{"concurrency/9"}},
+	{"rwMutexBenchmarkExample", "concurrency", []string{"concurrency/9"}},
+	{"boundedQueueExample", "concurrency", []string{"concurrency/9"}},
+	{"backpressureExample", "concurrency", []string{"concurrency/3"}},
+	{"channelBenchmarkExample", "concurrency", []string{"concurrency/3"}},
+	{"goroutineLeakExample", "concurrency", nil},
+	{"scatterGatherExample", "concurrency", nil},
+	{"retryPoolExample", "concurrency", nil},
+	{"ringBufferExample", "concurrency", nil},
+	{"atomicIncrementExample", "concurrency", []string{"concurrency/9"}},
+	{"compareAndSwapExample", "concurrency", nil},
+	{"counterBenchmarkExample", "concurrency", nil},
+	{"writersBenchmarkExample", "concurrency", nil},
+	{"syncMapCacheExample", "concurrency", nil},
+	{"syncMapBenchmarkExample", "concurrency", nil},
+	{"syncPoolExample", "concurrency", nil},
+	{"pollFakeClockExample", "concurrency", nil},
+	{"configReloadExample", "concurrency", nil},
+	{"rateTrackerFakeClockExample", "concurrency", nil},
+	{"requestMetricsExample", "concurrency", nil},
+	{"leastLoadedExample", "concurrency", nil},
+	{"brokerExample", "concurrency", nil},
+	{"brokerCloseUnderLoadExample", "concurrency", nil},
+	{"brokerSlowSubscriberExample", "concurrency", []string{"concurrency/5"}},
+	{"crawlCauseExample", "concurrency", []string{"concurrency/10"}},
+	{"muxExamples", "concurrency", []string{"concurrency/6"}},
+	{"muxMainLoopExample", "concurrency", nil},
+	{"gracefulShutdownExample", "concurrency", nil},
+	{"webCrawlerExample", "concurrency", []string{"concurrency/10"}},
+	{"rateLimitExamples", "concurrency", nil},
+	{"keyedRateLimitExample", "concurrency", nil},
+	{"faultInjectionExample", "concurrency", nil},
+	{"errgroupExamples", "concurrency", nil},
+	{"workPoolExample", "concurrency", []string{"generics/2", "concurrency/2"}},
+	{"recoveredExample", "concurrency", []string{"flowcontrol/12"}},
+
+	// pipeline
+	{"handRolledPipelineExample", "pipeline/main", nil},
+	{"builderPipelineExample", "pipeline/main", nil},
+	{"errorPropagationExample", "pipeline/main", nil},
+	{"cancellationExample", "pipeline/main", nil},
+	{"shutdownCauseExample", "pipeline/main", nil},
+	{"fanOutFanInExample", "pipeline/main", nil},
+	{"demuxExample", "pipeline/main", nil},
+	{"orDoneTeeExample", "pipeline/main", nil},
+	{"pipelineTeardownExample", "pipeline/main", nil},
+	{"channelStagesExample", "pipeline/main", []string{"generics/1", "concurrency/2"}},
+
+	// hashing
+	{"hashBasicsExample", "hashing", nil},
+	{"distributionExample", "hashing", nil},
+	{"bloomFilterExample", "hashing", nil},
+	{"shardedMapExample", "hashing", nil},
+	{"shardedMapBenchmarkExample", "hashing", nil},
+	{"topKExample", "hashing", nil},
+
+	// search
+	{"searchExamples", "search", nil},
+	{"prefixSearchExamples", "search", []string{"generics/2"}},
+
+	// buildtags
+	{"buildTagExamples", "buildtags", nil},
+
+	// codegen
+	{"generatedTypesExample", "codegen/main", nil},
+
+	// jsonrpc
+	{"conformanceExample", "jsonrpc/main", nil},
+	{"pipeExample", "jsonrpc/main", nil},
+	{"tcpExample", "jsonrpc/main", nil},
+	{"clientExample", "jsonrpc/main", nil},
+
+	// httpserver
+	{"handlerFuncExample", "httpserver/main", []string{"methods/9"}},
+	{"apiExample", "httpserver/main", nil},
+	{"middlewareExample", "httpserver/main", nil},
+	{"serverExample", "httpserver/main", nil},
+	{"recoverMiddlewareExample", "httpserver/main", []string{"flowcontrol/12"}},
+	{"rateLimitMiddlewareExample", "httpserver/main", nil},
+	{"idempotencyExample", "httpserver/main", nil},
+	{"gracefulServerShutdownExample", "httpserver/main", nil},
+
+	// httpclient
+	{"timeoutExample", "httpclient/main", nil},
+	{"bodyExample", "httpclient/main", nil},
+	{"paginationExample", "httpclient/main", []string{"generics/2"}},
+	{"retryTransportExample", "httpclient/main", nil},
+
+	// jsonpkg
+	{"tagsExample", "jsonpkg/main", []string{"moretypes/2"}},
+	{"pointerExample", "jsonpkg/main", []string{"moretypes/1"}},
+	{"nestedExample", "jsonpkg/main", []string{"moretypes/2"}},
+	{"dynamicExample", "jsonpkg/main", []string{"methods/16"}},
+	{"streamExample", "jsonpkg/main", nil},
+
+	// bufiopkg
+	{"scannerExample", "bufiopkg/main", []string{"methods/21"}},
+	{"readStringExample", "bufiopkg/main", []string{"methods/21"}},
+	{"writerExample", "bufiopkg/main", nil},
+
+	// flagpkg
+	{"basicFlagsExample", "flagpkg/main", nil},
+	{"customValueExample", "flagpkg/main", []string{"methods/9"}},
+	{"subcommandExample", "flagpkg/main", nil},
+
+	// timepkg
+	{"formatExample", "timepkg/main", nil},
+	{"durationExample", "timepkg/main", []string{"basics/11"}},
+	{"timerTickerExample", "timepkg/main", []string{"concurrency/5"}},
+	{"afterFuncExample", "timepkg/main", nil},
+	{"monotonicExample", "timepkg/main", nil},
+
+	// regexppkg
+	{"matchExample", "regexppkg/main", nil},
+	{"namedGroupsExample", "regexppkg/main", []string{"moretypes/19"}},
+	{"replaceExample", "regexppkg/main", []string{"moretypes/25"}},
+	{"uuidExample", "regexppkg/main", nil},
+
+	// stringspkg
+	{"splitJoinExample", "stringspkg/main", []string{"moretypes/7"}},
+	{"replacerExample", "stringspkg/main", nil},
+	{"cutTrimExample", "stringspkg/main", []string{"basics/6"}},
+	{"builderBufferExample", "stringspkg/main", []string{"methods/21"}},
+	{"concatBenchmarkExample", "stringspkg/main", nil},
+
+	// sortpkg
+	{"sortUsersExample", "sortpkg/main", []string{"moretypes/25"}},
+	{"binarySearchExample", "sortpkg/main", []string{"generics/1"}},
+	{"stabilityExample", "sortpkg/main", nil},
+
+	// logging
+	{"handlersExample", "logging/main", nil},
+	{"levelsExample", "logging/main", nil},
+	{"groupsExample", "logging/main", nil},
+	{"contextExample", "logging/main", nil},
+	{"recorderExample", "logging/main", []string{"methods/9"}},
+
+	// embedpkg
+	{"embedFileExample", "embedpkg/main", nil},
+	{"embedFSExample", "embedpkg/main", []string{"methods/9"}},
+	{"serveEmbedExample", "embedpkg/main", nil},
+
+	// memlayout
+	{"alignmentExample", "memlayout/main", []string{"basics/11"}},
+	{"reorderExample", "memlayout/main", []string{"moretypes/2"}},
+	{"headersExample", "memlayout/main", []string{"moretypes/8", "moretypes/11", "moretypes/15"}},
+	{"paddingBenchmarkExample", "memlayout/main", nil},
+	{"unsafeWarningsExample", "memlayout/main", nil},
+
+	// tcp
+	{"echoExample", "tcp/main", []string{"concurrency/1"}},
+	{"deadlineExample", "tcp/main", nil},
+	{"shutdownExample", "tcp/main", []string{"concurrency/5"}},
+
+	// udp
+	{"timeEchoExample", "udp/main", nil},
+	{"readDeadlineExample", "udp/main", nil},
+	{"partialDatagramExample", "udp/main", nil},
+
+	// dbsql
+	{"queryExample", "dbsql/main", nil},
+	{"preparedExample", "dbsql/main", nil},
+	{"transactionExample", "dbsql/main", []string{"flowcontrol/12"}},
+	{"closeExample", "dbsql/main", []string{"flowcontrol/12"}},
+
+	// cryptopkg
+	{"checksumExample", "cryptopkg/main", nil},
+	{"hmacExample", "cryptopkg/main", nil},
+	{"newUUIDExample", "cryptopkg/main", nil},
+	{"constantTimeExample", "cryptopkg/main", nil},
+
+	// compress
+	{"roundTripExample", "compress/main", []string{"methods/21"}},
+	{"streamingExample", "compress/main", []string{"methods/21"}},
+	{"corruptInputExample", "compress/main", []string{"methods/19"}},
+	{"compressionLevelsExample", "compress/main", nil},
+	{"limitExample", "compress/main", nil},
+
+	// encodings
+	{"base64HexExample", "encodings/main", nil},
+	{"gobExample", "encodings/main", []string{"moretypes/2"}},
+	{"binaryExample", "encodings/main", []string{"basics/11"}},
+
+	// runtimepkg
+	{"goroutinesExample", "runtimepkg/main", []string{"concurrency/1"}},
+	{"gomaxprocsExample", "runtimepkg/main", []string{"concurrency/1"}},
+	{"memStatsExample", "runtimepkg/main", nil},
+	{"gcPercentExample", "runtimepkg/main", nil},
+
+	// mapsslices
+	{"mapsExample", "mapsslices/main", []string{"moretypes/19", "moretypes/22"}},
+	{"searchExample", "mapsslices/main", []string{"moretypes/16", "generics/1"}},
+	{"editExample", "mapsslices/main", []string{"moretypes/15"}},
+
+	// cmppkg
+	{"compareExample", "cmppkg/main", nil},
+	{"orderedExample", "cmppkg/main", []string{"generics/1"}},
+	{"orExample", "cmppkg/main", nil},
+	{"sortFuncCmpExample", "cmppkg/main", []string{"moretypes/24"}},
+
+	// randpkg
+	{"intNExample", "randpkg/main", nil},
+	{"shuffleExample", "randpkg/main", nil},
+	{"seededExample", "randpkg/main", nil},
+	{"workloadExample", "randpkg/main", nil},
+	{"cryptoRandExample", "randpkg/main", nil},
+
+	// rpc
+	{"counterServiceExample", "rpc/main", []string{"methods/10", "concurrency/9"}},
+	{"jsonCodecExample", "rpc/main", nil},
+	{"rpcErrorsExample", "rpc/main", []string{"methods/19"}},
+	{"tcpRPCExample", "rpc/main", nil},
+	// kvstore
+	{"kvBasicsExample", "kvstore/main", nil},
+	{"recoveryExample", "kvstore/main", nil},
+	{"compactionExample", "kvstore/main", nil},
+	{"concurrentKVExample", "kvstore/main", nil},
+	{"kvBenchmarkExample", "kvstore/main", nil},
+	// shorturl
+	{"shortenExample", "shorturl/main", nil},
+	{"listLinksExample", "shorturl/main", nil},
+	{"hitCountersExample", "shorturl/main", nil},
+	{"linkErrorsExample", "shorturl/main", nil},
+	{"codesExample", "shorturl/main", nil},
+	// scheduler
+	{"scheduleExample", "scheduler/main", nil},
+	{"jitterExample", "scheduler/main", nil},
+	{"overlapExample", "scheduler/main", nil},
+	{"panicRecoveryExample", "scheduler/main", nil},
+	{"stopExample", "scheduler/main", nil},
+	// pitfalls
+	{"goroutineCaptureExample", "pitfalls", []string{"concurrency/1"}},
+	{"closureCaptureExample", "pitfalls", []string{"moretypes/25"}},
+	{"deferArgsExample", "pitfalls", []string{"flowcontrol/12", "flowcontrol/13"}},
+	{"deferInLoopExample", "pitfalls", []string{"flowcontrol/12"}},
+	// exercises
+	{"sqrtExample", "exercises/main", []string{"flowcontrol/8", "methods/20"}},
+	{"wordCountExample", "exercises/main", []string{"moretypes/23"}},
+	{"rot13ReaderExample", "exercises/main", []string{"methods/23"}},
+	{"equivalentTreesExample", "exercises/main", []string{"concurrency/7", "concurrency/8"}},
+	{"crawlExerciseExample", "exercises/main", []string{"concurrency/10"}},
+	// strconvpkg
+	{"parseIntExample", "strconvpkg/main", nil},
+	{"parseFloatExample", "strconvpkg/main", nil},
+	{"parseBoolExample", "strconvpkg/main", nil},
+	{"formatNumbersExample", "strconvpkg/main", nil},
+	{"quoteExample", "strconvpkg/main", nil},
+	{"parseIntInRangeExample", "strconvpkg/main", []string{"methods/19"}},
+	// framing
+	{"frameBytesExample", "framing/main", []string{"methods/21"}},
+	{"streamSplitExample", "framing/main", []string{"methods/21"}},
+	{"pipeUsersExample", "framing/main", []string{"methods/9"}},
+	{"tcpUsersExample", "framing/main", []string{"concurrency/9"}},
+	{"badFrameExample", "framing/main", nil},
+}
+
+// ExamplesNamed returns the examples with the given function name.
+// Usually one - "main" is registered once per module that has notes in it
+func ExamplesNamed(name string) []Example {
+	var found []Example
+	for _, ex := range Examples {
+		if ex.Name == name {
+			found = append(found, ex)
+		}
+	}
+	return found
+}
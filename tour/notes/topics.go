@@ -0,0 +1,135 @@
+package notes
+
+// The Tour's table of contents (https://go.dev/tour/list), as data.
+// Page IDs are the paths of the Tour's URLs, ex. go.dev/tour/moretypes/19 is "moretypes/19".
+// The "Congratulations" page at the end of each lesson has no content, so it is left out.
+
+type Page struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type Lesson struct {
+	Chapter string `json:"chapter"`
+	Title   string `json:"title"`
+	Pages   []Page `json:"pages"`
+}
+
+var Lessons = []Lesson{
+	{"Basics", "Packages, variables, and functions", []Page{
+		{"basics/1", "Packages"},
+		{"basics/2", "Imports"},
+		{"basics/3", "Exported names"},
+		{"basics/4", "Functions"},
+		{"basics/5", "Functions continued"},
+		{"basics/6", "Multiple results"},
+		{"basics/7", "Named return values"},
+		{"basics/8", "Variables"},
+		{"basics/9", "Variables with initializers"},
+		{"basics/10", "Short variable declarations"},
+		{"basics/11", "Basic types"},
+		{"basics/12", "Zero values"},
+		{"basics/13", "Type conversions"},
+		{"basics/14", "Type inference"},
+		{"basics/15", "Constants"},
+		{"basics/16", "Numeric Constants"},
+	}},
+	{"Basics", "Flow control statements: for, if, else, switch and defer", []Page{
+		{"flowcontrol/1", "For"},
+		{"flowcontrol/2", "For continued"},
+		{"flowcontrol/3", "For is Go's \"while\""},
+		{"flowcontrol/4", "Forever"},
+		{"flowcontrol/5", "If"},
+		{"flowcontrol/6", "If with a short statement"},
+		{"flowcontrol/7", "If and else"},
+		{"flowcontrol/8", "Exercise: Loops and Functions"},
+		{"flowcontrol/9", "Switch"},
+		{"flowcontrol/10", "Switch evaluation order"},
+		{"flowcontrol/11", "Switch with no condition"},
+		{"flowcontrol/12", "Defer"},
+		{"flowcontrol/13", "Stacking defers"},
+	}},
+	{"Basics", "More types: structs, slices, and maps", []Page{
+		{"moretypes/1", "Pointers"},
+		{"moretypes/2", "Structs"},
+		{"moretypes/3", "Struct Fields"},
+		{"moretypes/4", "Pointers to structs"},
+		{"moretypes/5", "Struct Literals"},
+		{"moretypes/6", "Arrays"},
+		{"moretypes/7", "Slices"},
+		{"moretypes/8", "Slices are like references to arrays"},
+		{"moretypes/9", "Slice literals"},
+		{"moretypes/10", "Slice defaults"},
+		{"moretypes/11", "Slice length and capacity"},
+		{"moretypes/12", "Nil slices"},
+		{"moretypes/13", "Creating a slice with make"},
+		{"moretypes/14", "Slices of slices"},
+		{"moretypes/15", "Appending to a slice"},
+		{"moretypes/16", "Range"},
+		{"moretypes/17", "Range continued"},
+		{"moretypes/18", "Exercise: Slices"},
+		{"moretypes/19", "Maps"},
+		{"moretypes/20", "Map literals"},
+		{"moretypes/21", "Map literals continued"},
+		{"moretypes/22", "Mutating Maps"},
+		{"moretypes/23", "Exercise: Maps"},
+		{"moretypes/24", "Function values"},
+		{"moretypes/25", "Function closures"},
+		{"moretypes/26", "Exercise: Fibonacci closure"},
+	}},
+	{"Methods and interfaces", "Methods and interfaces", []Page{
+		{"methods/1", "Methods"},
+		{"methods/2", "Methods are functions"},
+		{"methods/3", "Methods continued"},
+		{"methods/4", "Pointer receivers"},
+		{"methods/5", "Pointers and functions"},
+		{"methods/6", "Methods and pointer indirection"},
+		{"methods/7", "Methods and pointer indirection (2)"},
+		{"methods/8", "Choosing a value or pointer receiver"},
+		{"methods/9", "Interfaces"},
+		{"methods/10", "Interfaces are implemented implicitly"},
+		{"methods/11", "Interface values"},
+		{"methods/12", "Interface values with nil underlying values"},
+		{"methods/13", "Nil interface values"},
+		{"methods/14", "The empty interface"},
+		{"methods/15", "Type assertions"},
+		{"methods/16", "Type switches"},
+		{"methods/17", "Stringers"},
+		{"methods/18", "Exercise: Stringers"},
+		{"methods/19", "Errors"},
+		{"methods/20", "Exercise: Errors"},
+		{"methods/21", "Readers"},
+		{"methods/22", "Exercise: Readers"},
+		{"methods/23", "Exercise: rot13Reader"},
+		{"methods/24", "Images"},
+		{"methods/25", "Exercise: Images"},
+	}},
+	{"Generics", "Generics", []Page{
+		{"generics/1", "Type parameters"},
+		{"generics/2", "Generic types"},
+	}},
+	{"Concurrency", "Concurrency", []Page{
+		{"concurrency/1", "Goroutines"},
+		{"concurrency/2", "Channels"},
+		{"concurrency/3", "Buffered Channels"},
+		{"concurrency/4", "Range and Close"},
+		{"concurrency/5", "Select"},
+		{"concurrency/6", "Default Selection"},
+		{"concurrency/7", "Exercise: Equivalent Binary Trees"},
+		{"concurrency/8", "Exercise: Equivalent Binary Trees (continued)"},
+		{"concurrency/9", "sync.Mutex"},
+		{"concurrency/10", "Exercise: Web Crawler"},
+	}},
+}
+
+// PageByID looks up a page of the Tour.
+func PageByID(id string) (Page, bool) {
+	for _, l := range Lessons {
+		for _, p := range l.Pages {
+			if p.ID == id {
+				return p, true
+			}
+		}
+	}
+	return Page{}, false
+}
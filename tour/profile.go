@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"tour/notes"
+)
+
+// --- tour run -profile ---
+
+// With -profile dir, tour run writes a CPU and a heap profile of each example to dir (runtime/pprof, see
+// runner.Config), and prints the top of each. They're pprof's binary format this time, not the text one
+// contention.go reads - the reading is left to go tool pprof, which comes with Go:
+//
+//	go tool pprof -top dir/counterBenchmarkExample.cpu.pprof
+//	go tool pprof -http localhost:8080 dir/counterBenchmarkExample.cpu.pprof
+//
+// The heap profile is taken when the example returns, after a GC: what's still in use then is usually little,
+// so the top printed here is of alloc_space - everything allocated while it ran, freed or not.
+
+// profileTop is how many functions printTopConsumers lists
+const profileTop = 10
+
+// profilePaths are where -profile dir puts ex's profiles
+func profilePaths(dir string, ex notes.Example) (cpu, mem string) {
+	base := filepath.Join(dir, ex.Name)
+	return base + ".cpu.pprof", base + ".mem.pprof"
+}
+
+// printTopConsumers prints the functions using the most of a profile's sample (ex. "cpu",
+// "alloc_space"), with go tool pprof -top. Its header - the file, the build ID, what was dropped - is left out
+func printTopConsumers(path, sample string) error {
+	out, err := exec.Command("go", "tool", "pprof", "-top", fmt.Sprintf("-nodecount=%d", profileTop),
+		"-sample_index="+sample, path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go tool pprof %s: %w\n%s", path, err, out)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "flat ") {
+			lines = lines[i:]
+			break
+		}
+	}
+	fmt.Printf("top %s (%s):\n", sample, path)
+	for _, line := range lines {
+		fmt.Println("  " + line)
+	}
+	return nil
+}
+
+// printProfiles prints the top of both of ex's profiles in dir. A profile with no samples - an example
+// too quick for the CPU profiler's 100 Hz - says so instead
+func printProfiles(dir string, ex notes.Example) error {
+	cpu, mem := profilePaths(dir, ex)
+	for _, p := range []struct{ path, sample string }{{cpu, "cpu"}, {mem, "alloc_space"}} {
+		if info, err := os.Stat(p.path); err != nil {
+			return err
+		} else if info.Size() == 0 {
+			fmt.Printf("top %s: no profile written (%s)\n", p.sample, p.path)
+			continue
+		}
+		if err := printTopConsumers(p.path, p.sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
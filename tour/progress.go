@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"tour/notes"
+	"tour/progress"
+	"tour/quiz"
+)
+
+// --- tour progress ---
+
+// Shows how much of each lesson is done: the examples run with tour run, and the quiz questions answered
+// right with tour quiz. Both commands record into the progress file (see tour/progress) as they go.
+
+func runProgress(args []string) error {
+	fs := flag.NewFlagSet("progress", flag.ContinueOnError)
+	format := fs.String("format", "text", "output format: text or json")
+	reset := fs.Bool("reset", false, "delete the progress file, starting over")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "usage: tour progress [flags]\n\n"+
+			"The progress file is $TOUR_PROGRESS, or a-tour-of-go-notes/progress.json in the user's config directory.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q (want text or json)", *format)
+	}
+	path, err := progress.Path()
+	if err != nil {
+		return err
+	}
+	if *reset {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		fmt.Println("progress reset:", path)
+		return nil
+	}
+	p, err := progress.Load(path)
+	if err != nil {
+		return err
+	}
+	bank, err := quiz.Bank()
+	if err != nil {
+		return err
+	}
+	report := p.Report(notes.Lessons, notes.Examples, bank)
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			progress.Report
+			Percent float64 `json:"percent"`
+			Path    string  `json:"path"`
+		}{report, report.Percent(), path})
+	}
+	writeProgressText(os.Stdout, report, path)
+	return nil
+}
+
+func writeProgressText(w io.Writer, r progress.Report, path string) {
+	fmt.Fprintf(w, "Progress: %.0f%% (%s)\n\n", r.Percent(), path)
+	fmt.Fprintf(w, "  %-60s %9s %9s %5s\n", "", "examples", "questions", "")
+	chapter := ""
+	for _, l := range append(r.Lessons, r.Beyond) {
+		if l.Chapter != chapter {
+			chapter = l.Chapter
+			fmt.Fprintf(w, "%s\n", chapter)
+		}
+		questions := "-"
+		if l.Questions > 0 {
+			questions = fmt.Sprintf("%d/%d", l.Right, l.Questions)
+		}
+		fmt.Fprintf(w, "  %-60s %9s %9s %4.0f%%\n", l.Lesson, fmt.Sprintf("%d/%d", l.Ran, l.Examples), questions, l.Percent())
+	}
+}
+
+// progressRecorder loads the progress file once, and saves it after each change. A file that can't be read
+// or written is a warning, not a failure - the command still does what it was asked
+type progressRecorder struct {
+	path string
+	p    *progress.Progress
+}
+
+func loadProgress() *progressRecorder {
+	path, err := progress.Path()
+	if err == nil {
+		var p *progress.Progress
+		if p, err = progress.Load(path); err == nil {
+			return &progressRecorder{path, p}
+		}
+	}
+	fmt.Fprintln(os.Stderr, "(progress won't be recorded:", err, ")")
+	return nil
+}
+
+func (r *progressRecorder) ranExample(ex notes.Example, result string) {
+	if r == nil {
+		return
+	}
+	r.p.RanExample(ex, result, time.Now())
+	r.save()
+}
+
+func (r *progressRecorder) answered(q quiz.Question, right bool) {
+	if r == nil {
+		return
+	}
+	r.p.Answered(q, right, time.Now())
+	r.save()
+}
+
+func (r *progressRecorder) save() {
+	if err := r.p.Save(r.path); err != nil {
+		fmt.Fprintln(os.Stderr, "(progress not saved:", err, ")")
+	}
+}
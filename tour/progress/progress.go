@@ -0,0 +1,107 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+	"tour/notes"
+	"tour/quiz"
+)
+
+// Progress is what someone has done with the notes so far: the examples they ran with tour run, and the
+// quiz questions they answered. It's kept as JSON in their config directory (Path), read at the start of
+// a command and saved after each example or answer - so an interrupted run keeps what it got through.
+
+// Progress is the whole file. Examples are keyed by ExampleKey, questions by their quiz ID
+type Progress struct {
+	Examples  map[string]ExampleRun `json:"examples"`
+	Questions map[string]Answer     `json:"questions"`
+}
+
+// ExampleRun is an example's runs. Result is the last one's: "ok", or the runner's failure kind
+// ("deadlocked", "timed out"...) - a run counts whatever its result, since some examples fail on purpose
+type ExampleRun struct {
+	Runs    int       `json:"runs"`
+	LastRun time.Time `json:"last_run"`
+	Result  string    `json:"result"`
+}
+
+// Answer is a question's answers. Right is whether the last one was
+type Answer struct {
+	Attempts int       `json:"attempts"`
+	Answered time.Time `json:"answered"`
+	Right    bool      `json:"right"`
+}
+
+// ExampleKey identifies ex - its name alone isn't enough, every module has a "main"
+func ExampleKey(ex notes.Example) string { return ex.Dir + ":" + ex.Name }
+
+// Path is where the progress file is: $TOUR_PROGRESS if it's set, otherwise
+// a-tour-of-go-notes/progress.json in os.UserConfigDir (ex. ~/.config on Linux)
+func Path() (string, error) {
+	if p := os.Getenv("TOUR_PROGRESS"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "a-tour-of-go-notes", "progress.json"), nil
+}
+
+// Load reads the progress file at path. A file that doesn't exist yet is no progress, not an error
+func Load(path string) (*Progress, error) {
+	p := &Progress{Examples: map[string]ExampleRun{}, Questions: map[string]Answer{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save writes p to path, creating its directory. It writes a temporary file and renames it over the old
+// one, so a crash mid-write leaves the previous progress rather than half a file
+func (p *Progress) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".progress-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // after the rename, there's nothing left to remove
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// RanExample records a run of ex that ended with result
+func (p *Progress) RanExample(ex notes.Example, result string, at time.Time) {
+	r := p.Examples[ExampleKey(ex)]
+	p.Examples[ExampleKey(ex)] = ExampleRun{Runs: r.Runs + 1, LastRun: at, Result: result}
+}
+
+// Answered records an answer to q
+func (p *Progress) Answered(q quiz.Question, right bool, at time.Time) {
+	a := p.Questions[q.ID]
+	p.Questions[q.ID] = Answer{Attempts: a.Attempts + 1, Answered: at, Right: right}
+}
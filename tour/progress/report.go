@@ -0,0 +1,93 @@
+package progress
+
+import (
+	"tour/notes"
+	"tour/quiz"
+)
+
+// LessonProgress is how much of one lesson is done: of the examples covering its pages, how many were
+// run, and of the questions about them, how many were last answered right
+type LessonProgress struct {
+	Chapter   string `json:"chapter"`
+	Lesson    string `json:"lesson"`
+	Ran       int    `json:"ran"`
+	Examples  int    `json:"examples"`
+	Right     int    `json:"right"`
+	Questions int    `json:"questions"`
+}
+
+// Percent is the share of the lesson's examples and questions done, together
+func (l LessonProgress) Percent() float64 {
+	return percent(l.Ran+l.Right, l.Examples+l.Questions)
+}
+
+// Report is the progress of every lesson, and of the examples that go beyond the Tour (no topics)
+type Report struct {
+	Lessons []LessonProgress `json:"lessons"`
+	Beyond  LessonProgress   `json:"beyond_the_tour"`
+}
+
+// Percent is the share of everything done
+func (r Report) Percent() float64 {
+	done, total := r.Beyond.Ran, r.Beyond.Examples
+	for _, l := range r.Lessons {
+		done += l.Ran + l.Right
+		total += l.Examples + l.Questions
+	}
+	return percent(done, total)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// Report is p against lessons, examples and questions - like notes.Coverage, a lesson's examples are those
+// naming one of its pages. An example covering two lessons counts in both
+func (p *Progress) Report(lessons []notes.Lesson, examples []notes.Example, questions []quiz.Question) Report {
+	lessonOf := make(map[string]int) // page ID -> index into lessons
+	for i, l := range lessons {
+		for _, pg := range l.Pages {
+			lessonOf[pg.ID] = i
+		}
+	}
+	r := Report{Beyond: LessonProgress{Chapter: "Beyond", Lesson: "Notes beyond the Tour"}}
+	for _, l := range lessons {
+		r.Lessons = append(r.Lessons, LessonProgress{Chapter: l.Chapter, Lesson: l.Title})
+	}
+
+	for _, ex := range examples {
+		ran := p.Examples[ExampleKey(ex)].Runs > 0
+		counted := make(map[int]bool)
+		for _, id := range ex.Topics {
+			i, ok := lessonOf[id]
+			if !ok || counted[i] {
+				continue
+			}
+			counted[i] = true
+			r.Lessons[i].Examples++
+			if ran {
+				r.Lessons[i].Ran++
+			}
+		}
+		if len(ex.Topics) == 0 {
+			r.Beyond.Examples++
+			if ran {
+				r.Beyond.Ran++
+			}
+		}
+	}
+	for _, q := range questions {
+		i, ok := lessonOf[q.Topic]
+		if !ok {
+			continue
+		}
+		r.Lessons[i].Questions++
+		if p.Questions[q.ID].Right {
+			r.Lessons[i].Right++
+		}
+	}
+	return r
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"tour/notes"
+	"tour/quiz"
+)
+
+// --- tour quiz ---
+
+// Asks the question bank's questions (tour/quiz) on stdin, one at a time, and explains each answer.
+// -topic picks the questions the same way it picks examples for tour run. -shuffle asks them in a random order,
+// before -n takes the first ones - so a short quiz is a different few questions each time.
+
+func runQuiz(args []string) error {
+	fs := flag.NewFlagSet("quiz", flag.ContinueOnError)
+	var topics topicsFlag
+	fs.Var(&topics, "topic", "only questions about this Tour `page` or lesson (repeatable, or comma-separated)")
+	n := fs.Int("n", 0, "ask at most this many questions (0: all of them)")
+	shuffle := fs.Bool("shuffle", false, "ask the questions, and list their choices, in a random order")
+	seed := fs.Uint64("seed", 0, "with -shuffle, the order for this seed - the same every time (0: a new one each run)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "usage: tour quiz [flags]\n\nex. tour quiz -topic concurrency -n 2\n    tour quiz -shuffle -n 5\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	bank, err := quiz.Bank()
+	if err != nil {
+		return err
+	}
+	var qs []quiz.Question
+	for _, q := range bank {
+		if len(topics) == 0 || topics.covers(notes.Example{Topics: []string{q.Topic}}) {
+			qs = append(qs, q)
+		}
+	}
+	if *shuffle {
+		qs = quiz.Shuffle(rand.New(rand.NewPCG(cmp.Or(*seed, rand.Uint64()), 0)), qs)
+	}
+	if *n > 0 && *n < len(qs) {
+		qs = qs[:*n]
+	}
+	if len(qs) == 0 {
+		return fmt.Errorf("no questions about %s", topics.String())
+	}
+	right, err := ask(os.Stdin, os.Stdout, qs, loadProgress().answered)
+	fmt.Printf("\n%d of %d right\n", right, len(qs))
+	return err
+}
+
+// ask puts each question to in and out, calling answered with each answer, and returns how many were right.
+// An answer that isn't one of the choice numbers is asked for again; the end of in stops the quiz
+func ask(in io.Reader, out io.Writer, qs []quiz.Question, answered func(q quiz.Question, right bool)) (right int, err error) {
+	sc := bufio.NewScanner(in)
+	for i, q := range qs {
+		page, _ := notes.PageByID(q.Topic)
+		fmt.Fprintf(out, "\n[%d/%d] %s (%s)\n%s\n", i+1, len(qs), page.Title, q.Topic, q.Prompt)
+		for j, c := range q.Choices {
+			fmt.Fprintf(out, "  %d) %s\n", j+1, c)
+		}
+		for {
+			fmt.Fprint(out, "> ")
+			if !sc.Scan() {
+				if err := sc.Err(); err != nil {
+					return right, err
+				}
+				return right, errors.New("quiz stopped before the last question")
+			}
+			choice, err := strconv.Atoi(strings.TrimSpace(sc.Text()))
+			if err != nil || choice < 1 || choice > len(q.Choices) {
+				fmt.Fprintf(out, "a number from 1 to %d\n", len(q.Choices))
+				continue
+			}
+			ok := q.Correct(choice - 1)
+			answered(q, ok)
+			if ok {
+				right++
+				fmt.Fprint(out, "right. ")
+			} else {
+				fmt.Fprintf(out, "no - it's %d. ", q.Answer+1)
+			}
+			fmt.Fprintln(out, q.Explain)
+			break
+		}
+	}
+	return right, nil
+}
@@ -0,0 +1,85 @@
+package quiz
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"tour/notes"
+)
+
+// The quiz's question bank is the JSON files in questions/, one per lesson, embedded into the tour binary
+// (see embedpkg for //go:embed) - so `tour quiz` works wherever the binary is, without the repository.
+// Adding a question is editing a JSON file; Load checks each one when the bank is read.
+
+//go:embed questions/*.json
+var questions embed.FS
+
+// Question is one multiple choice question about a page of the Tour
+type Question struct {
+	ID      string   `json:"id"`
+	Topic   string   `json:"topic"` // a Page ID, ex. "concurrency/2"
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices"`
+	Answer  int      `json:"answer"` // index into Choices
+	Explain string   `json:"explain"`
+}
+
+// Correct reports whether choice (an index into Choices) is the answer
+func (q Question) Correct(choice int) bool { return choice == q.Answer }
+
+// Bank is the embedded question bank
+func Bank() ([]Question, error) {
+	return Load(questions, "questions")
+}
+
+// Load reads every *.json file in dir of fsys, in file name order. A question with a duplicate ID,
+// a topic that isn't a page of the Tour, or an answer that isn't one of its choices is an error
+func Load(fsys fs.FS, dir string) ([]Question, error) {
+	paths, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var all []Question
+	seen := map[string]string{} // ID -> file
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		var qs []Question
+		if err := json.Unmarshal(data, &qs); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		for _, q := range qs {
+			if err := q.validate(); err != nil {
+				return nil, fmt.Errorf("%s: question %q: %w", p, q.ID, err)
+			}
+			if other, ok := seen[q.ID]; ok {
+				return nil, fmt.Errorf("%s: question %q: id already used in %s", p, q.ID, other)
+			}
+			seen[q.ID] = p
+			all = append(all, q)
+		}
+	}
+	return all, nil
+}
+
+func (q Question) validate() error {
+	var errs []error
+	if q.ID == "" || q.Prompt == "" {
+		errs = append(errs, errors.New("no id or prompt"))
+	}
+	if _, ok := notes.PageByID(q.Topic); !ok {
+		errs = append(errs, fmt.Errorf("topic %q is not a page of the Tour", q.Topic))
+	}
+	if len(q.Choices) < 2 {
+		errs = append(errs, fmt.Errorf("%d choices, need at least 2", len(q.Choices)))
+	}
+	if q.Answer < 0 || q.Answer >= len(q.Choices) {
+		errs = append(errs, fmt.Errorf("answer %d is not one of the %d choices", q.Answer, len(q.Choices)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,22 @@
+package quiz
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// Shuffle puts qs in a random order, and each question's choices too - so answers can't be learned by
+// position. The questions are copies (Choices included): the bank Load returned stays as it was.
+// r is a seeded generator for a repeatable order (tour quiz -seed), see the randpkg module
+func Shuffle(r *rand.Rand, qs []Question) []Question {
+	out := make([]Question, len(qs))
+	for i, j := range r.Perm(len(qs)) {
+		q := qs[j]
+		q.Choices = slices.Clone(q.Choices)
+		answer := q.Choices[q.Answer]
+		r.Shuffle(len(q.Choices), func(a, b int) { q.Choices[a], q.Choices[b] = q.Choices[b], q.Choices[a] })
+		q.Answer = slices.Index(q.Choices, answer)
+		out[i] = q
+	}
+	return out
+}
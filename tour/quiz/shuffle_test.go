@@ -0,0 +1,64 @@
+package quiz
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	bank, err := Bank()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := make([]Question, len(bank))
+	for i, q := range bank {
+		orig[i] = q
+		orig[i].Choices = slices.Clone(q.Choices)
+	}
+	seeded := func(seed uint64) *rand.Rand { return rand.New(rand.NewPCG(seed, 0)) }
+
+	got := Shuffle(seeded(1), bank)
+	if len(got) != len(bank) {
+		t.Fatalf("%d questions, want %d", len(got), len(bank))
+	}
+	byID := map[string]Question{}
+	for _, q := range orig {
+		byID[q.ID] = q
+	}
+	for _, q := range got {
+		want, ok := byID[q.ID]
+		if !ok {
+			t.Fatalf("question %q isn't in the bank, or came up twice", q.ID)
+		}
+		delete(byID, q.ID)
+		// The same choices, and Answer still points at the right one
+		if !slices.Equal(slices.Sorted(slices.Values(q.Choices)), slices.Sorted(slices.Values(want.Choices))) {
+			t.Errorf("%s: choices %q, want %q in some order", q.ID, q.Choices, want.Choices)
+		}
+		if q.Choices[q.Answer] != want.Choices[want.Answer] {
+			t.Errorf("%s: answer %q, want %q", q.ID, q.Choices[q.Answer], want.Choices[want.Answer])
+		}
+	}
+
+	// The bank is left as it was
+	for i := range bank {
+		if bank[i].ID != orig[i].ID || !slices.Equal(bank[i].Choices, orig[i].Choices) || bank[i].Answer != orig[i].Answer {
+			t.Fatalf("Shuffle changed question %d of the bank", i)
+		}
+	}
+
+	// The same seed, the same order
+	again := Shuffle(seeded(1), bank)
+	for i := range got {
+		if got[i].ID != again[i].ID || !slices.Equal(got[i].Choices, again[i].Choices) {
+			t.Fatalf("seed 1 gave two orders, from question %d", i)
+		}
+	}
+	if len(bank) > 2 {
+		other := Shuffle(seeded(2), bank)
+		if slices.EqualFunc(got, other, func(a, b Question) bool { return a.ID == b.ID }) {
+			t.Error("seeds 1 and 2 gave the same order")
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"tour/notes"
+	"tour/runner"
+)
+
+// --- tour run ---
+
+// Runs examples one at a time, each in its own process (see tour/runner), under a timeout.
+// Some examples deadlock or panic on purpose, and one that hangs would otherwise never return -
+// here an example that doesn't finish is explained, and the next one still runs.
+
+// explanations say why each kind of failure happens, in terms of the notes
+var explanations = map[runner.FailureKind]string{
+	runner.Deadlocked: "Every goroutine was blocked - on a channel send or receive, a lock, a WaitGroup - and none of them\n" +
+		"could ever be woken, so the runtime stopped the program. It only notices when ALL goroutines are stuck\n" +
+		"(see hiddenDeadlockExample for one it can't see).",
+	runner.TimedOut: "It was still running at the timeout, so it was stopped (with SIGQUIT, which dumps every goroutine's stack).\n" +
+		"Either it's just slow, or it's stuck where the runtime can't tell: blocked forever while some other goroutine\n" +
+		"could still be woken (a timer, a ticker, a network read), so it never counts as a deadlock.",
+	runner.Panicked: "An unrecovered panic unwinds its goroutine's stack running the deferred calls, and when nothing recovers it\n" +
+		"the whole program exits - not just that goroutine.",
+	runner.FatalError: "A fatal runtime error can't be recovered, unlike a panic (ex. concurrent map writes - the map noticed\n" +
+		"two goroutines using it at once).",
+	runner.DataRace: "Two goroutines used the same memory, at least one writing, with nothing ordering the two accesses\n" +
+		"(a mutex, a channel operation, an atomic). The output may well look right - the race detector reports\n" +
+		"the missing synchronization, whether or not the timing went wrong this run.",
+	runner.Exited: "It exited on its own with a non-zero status (os.Exit, log.Fatal).",
+}
+
+// topicsFlag is -topic: Tour pages ("concurrency/2") or whole lessons ("concurrency"), repeatable or comma-separated.
+// Set checks each against notes.Lessons, so a typo is a flag error rather than no examples
+type topicsFlag []string
+
+func (t *topicsFlag) String() string { return strings.Join(*t, ",") }
+
+func (t *topicsFlag) Set(s string) error {
+	for topic := range strings.SplitSeq(s, ",") {
+		topic = strings.TrimSpace(topic)
+		if _, ok := notes.PageByID(topic); !ok && !isLesson(topic) {
+			return fmt.Errorf("no Tour page or lesson %q (ex. concurrency/2, or concurrency)", topic)
+		}
+		*t = append(*t, topic)
+	}
+	return nil
+}
+
+// isLesson reports whether name is the first part of some page's ID - "moretypes" for "moretypes/19"
+func isLesson(name string) bool {
+	for _, l := range notes.Lessons {
+		for _, p := range l.Pages {
+			if lesson, _, _ := strings.Cut(p.ID, "/"); lesson == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// covers reports whether ex covers one of topics
+func (t topicsFlag) covers(ex notes.Example) bool {
+	for _, id := range ex.Topics {
+		lesson, _, _ := strings.Cut(id, "/")
+		if slices.Contains(t, id) || slices.Contains(t, lesson) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectExamples is the named examples, then every other one covering a topic, in the registry's order.
+// A function that takes arguments (add, swap) can't be run on its own, so a topic doesn't select it - it's in skipped
+func selectExamples(root string, names []string, topics topicsFlag) (selected, skipped []notes.Example, err error) {
+	for _, name := range names {
+		ex, err := lookupExample(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		selected = append(selected, ex)
+	}
+	funcsByDir := make(map[string]map[string]*ast.FuncType)
+	for _, ex := range notes.Examples {
+		named := slices.ContainsFunc(selected, func(s notes.Example) bool { return s.Name == ex.Name && s.Dir == ex.Dir })
+		if named || !topics.covers(ex) {
+			continue
+		}
+		funcs, ok := funcsByDir[ex.Dir]
+		if !ok {
+			if funcs, err = declaredFuncs(filepath.Join(root, ex.Dir)); err != nil {
+				return nil, nil, err
+			}
+			funcsByDir[ex.Dir] = funcs
+		}
+		if fn := funcs[ex.Name]; fn != nil && fn.Params.NumFields() > 0 {
+			skipped = append(skipped, ex)
+			continue
+		}
+		selected = append(selected, ex)
+	}
+	return selected, skipped, nil
+}
+
+func runRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	root := fs.String("root", "..", "repository root that example directories are relative to")
+	timeout := fs.Duration("timeout", 10*time.Second, "stop an example still running after this long")
+	race := fs.Bool("race", false, "build with the race detector (which turns off the runtime's deadlock detection)")
+	trace := fs.Bool("trace", false, "print the runtime's full report (every goroutine's stack) for failed examples")
+	verbose := fs.Bool("verbose", false, "also print each example's Tour pages, and how long it took (build included)")
+	profile := fs.String("profile", "", "write a CPU and a heap profile of each example into this `dir`, and print their top functions")
+	pprofAddr := fs.String("pprof", "", "serve net/http/pprof on this `addr` (ex. localhost:6060) while each example runs")
+	var topics topicsFlag
+	fs.Var(&topics, "topic", "also run every example covering this Tour `page` or lesson (repeatable, or comma-separated)")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "usage: tour run [flags] example ...\n       tour run [flags] -topic page ...\n\n"+
+			"ex. tour run -timeout 2s deadlockExampleOverfilledBufferBlock hiddenDeadlockExample\n"+
+			"    tour run -verbose -topic concurrency/3,concurrency/4\n"+
+			"    tour run -profile /tmp/prof counterBenchmarkExample\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 && len(topics) == 0 {
+		fs.Usage()
+		return errors.New("no examples given")
+	}
+	absRoot, err := filepath.Abs(*root)
+	if err != nil {
+		return err
+	}
+	examples, skipped, err := selectExamples(absRoot, fs.Args(), topics)
+	if err != nil {
+		return err
+	}
+	for _, ex := range skipped {
+		fmt.Printf("(skipping %s (%s): it takes arguments)\n", ex.Name, ex.Dir)
+	}
+	if len(examples) == 0 {
+		return fmt.Errorf("no runnable example covers %s", topics.String())
+	}
+	if *profile != "" {
+		if err := os.MkdirAll(*profile, 0o755); err != nil {
+			return err
+		}
+	}
+
+	record := loadProgress()
+	failed := 0
+	for _, ex := range examples {
+		fmt.Printf("=== %s (%s) ===\n", ex.Name, ex.Dir)
+		if *verbose {
+			fmt.Printf("pages: %s\n", pageTitles(ex.Topics))
+		}
+		if *pprofAddr != "" {
+			fmt.Printf("pprof: http://%s/debug/pprof/ while it runs\n", *pprofAddr)
+		}
+		start := time.Now()
+		// stderr is held back: on a failure, the runtime's report is summarized instead of dumped
+		var stderr bytes.Buffer
+		cfg := runner.Config{
+			Root:      absRoot,
+			Example:   ex,
+			Timeout:   *timeout,
+			Race:      *race,
+			PprofAddr: *pprofAddr,
+			Stdout:    os.Stdout,
+			Stderr:    &stderr,
+		}
+		if *profile != "" {
+			cfg.CPUProfile, cfg.MemProfile = profilePaths(*profile, ex)
+		}
+		err = runner.Run(context.Background(), cfg)
+		var f *runner.Failure
+		switch {
+		case err == nil:
+			record.ranExample(ex, "ok")
+			os.Stderr.Write(stderr.Bytes())
+			if *verbose {
+				fmt.Printf("--- ok after %v\n", time.Since(start).Round(time.Millisecond))
+			} else {
+				fmt.Println("--- ok")
+			}
+			if *profile != "" {
+				if err := printProfiles(*profile, ex); err != nil {
+					return fmt.Errorf("%s: %w", ex.Name, err)
+				}
+			}
+		case errors.As(err, &f):
+			record.ranExample(ex, f.Kind.String())
+			failed++
+			os.Stderr.Write(f.Output)
+			printFailure(f, absRoot, *trace)
+		default:
+			return fmt.Errorf("%s: %w", ex.Name, err) // couldn't build or start it - not the example's doing
+		}
+		fmt.Println()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d examples didn't finish normally", failed, len(examples))
+	}
+	return nil
+}
+
+// pageTitles is "Channels (concurrency/2), ..." - or a note that the example goes beyond the Tour
+func pageTitles(ids []string) string {
+	if len(ids) == 0 {
+		return "none (notes beyond the Tour)"
+	}
+	titles := make([]string, len(ids))
+	for i, id := range ids {
+		p, _ := notes.PageByID(id)
+		titles[i] = fmt.Sprintf("%s (%s)", p.Title, id)
+	}
+	return strings.Join(titles, ", ")
+}
+
+func printFailure(f *runner.Failure, root string, trace bool) {
+	fmt.Printf("--- %s after %v: %s\n", strings.ToUpper(f.Kind.String()), f.Elapsed.Round(time.Millisecond), f.Message)
+	fmt.Println(explanations[f.Kind])
+
+	if len(f.Goroutines) > 0 {
+		fmt.Println("goroutines:")
+		internal := 0
+		for _, g := range f.Goroutines {
+			if g.Func == "" {
+				internal++ // only runtime frames (the GC's workers and such)
+				continue
+			}
+			file, _ := strings.CutPrefix(g.File, root+string(filepath.Separator))
+			fmt.Printf("  %-4d %-22s %s  %s\n", g.ID, "["+g.State+"]", g.Func, file)
+		}
+		if internal > 0 {
+			fmt.Printf("  (and %d of the runtime's own)\n", internal)
+		}
+	}
+	for _, r := range f.Races {
+		fmt.Println("race:")
+		for _, a := range r.Accesses {
+			file, _ := strings.CutPrefix(a.File, root+string(filepath.Separator))
+			fmt.Printf("  %-16s by %-14s %s  %s\n", a.Op, a.Goroutine, a.Func, file)
+		}
+	}
+	if trace {
+		fmt.Printf("\nruntime report:\n%s", f.Report)
+	}
+}
@@ -0,0 +1,256 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// When a Go program dies from a panic or a fatal error (or gets SIGQUIT), the runtime writes a report
+// to stderr and exits with status 2. The report starts with what happened, then has a traceback
+// per goroutine:
+//
+//	fatal error: all goroutines are asleep - deadlock!
+//
+//	goroutine 1 [chan send]:
+//	main.deadlockExampleOverfilledBufferBlock(...)
+//		/root/module/concurrency/concurrency.go:103
+//	main.init.0()
+//		/root/module/concurrency/zz_tour_run.go:14 +0x4a
+//
+// The word in brackets is what the goroutine was doing (chan send, chan receive, select, sync.Mutex.Lock,
+// sleep, running, ...), and the frames are innermost first.
+
+// FailureKind says how an example ended when it didn't finish normally
+type FailureKind int
+
+const (
+	Exited     FailureKind = iota // a non-zero exit status without a runtime report, ex. os.Exit(1) or log.Fatal
+	Panicked                      // an unrecovered panic
+	Deadlocked                    // the runtime found every goroutine blocked
+	FatalError                    // another fatal runtime error, ex. concurrent map writes
+	TimedOut                      // still running at Config.Timeout
+	DataRace                      // the race detector found a race (Config.Race), and nothing else went wrong
+)
+
+func (k FailureKind) String() string {
+	switch k {
+	case Exited:
+		return "exited"
+	case Panicked:
+		return "panicked"
+	case Deadlocked:
+		return "deadlocked"
+	case FatalError:
+		return "fatal error"
+	case TimedOut:
+		return "timed out"
+	case DataRace:
+		return "data race"
+	}
+	return "FailureKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Goroutine is one goroutine from the runtime's report
+type Goroutine struct {
+	ID    int
+	State string // ex. "chan send", without the ", 5 minutes" the runtime adds for long waits
+	Func  string // the innermost frame outside the runtime - where the example's code was
+	File  string // file:line of Func
+}
+
+// Race is one report from the race detector: two accesses to the same memory, at least one a write,
+// from goroutines that didn't synchronize. Accesses[0] is the one that was caught, Accesses[1] the earlier one
+type Race struct {
+	Accesses []RaceAccess
+}
+
+type RaceAccess struct {
+	Op        string // "Read", "Write", "Previous write", ...
+	Goroutine string // "goroutine 8" or "main goroutine"
+	Func      string
+	File      string // file:line of Func
+}
+
+// Failure is the error Run returns for an example that didn't finish normally
+type Failure struct {
+	Kind       FailureKind
+	Message    string // ex. "send on closed channel", "all goroutines are asleep - deadlock!"
+	Goroutines []Goroutine
+	Races      []Race
+	ExitCode   int
+	Elapsed    time.Duration
+	Output     []byte // what the example itself wrote to stderr (the end of it, if long)
+	Report     []byte // the runtime's report - the message and the tracebacks - and any race reports
+	Err        *exec.ExitError
+}
+
+func (f *Failure) Error() string {
+	if f.Message == "" {
+		return f.Kind.String()
+	}
+	return f.Kind.String() + ": " + f.Message
+}
+
+func (f *Failure) Unwrap() error { return f.Err }
+
+// goroutineHeader matches "goroutine 1 [chan send]:" - with extra fields (gp=0x... m=...) when GOTRACEBACK is high
+var goroutineHeader = regexp.MustCompile(`^goroutine (\d+) (?:[^\[]* )?\[([^\]]+)\]:$`)
+
+// reportStart matches the first line of the runtime's report
+var reportStart = regexp.MustCompile(`^(panic: |fatal error: |SIG[A-Z]+: )`)
+
+// The race detector (-race) writes a report per race as soon as it sees it, between separator lines,
+// and once more at exit how many it found (then exits with status 66, even after os.Exit(0)):
+//
+//	==================
+//	WARNING: DATA RACE
+//	Read at 0x00c0000181b8 by goroutine 8:
+//	  main.Inc()
+//	      /root/module/concurrency/concurrency.go:296 +0x2e
+//
+//	Previous write at 0x00c0000181b8 by goroutine 7:
+//	  ...
+//	Goroutine 8 (running) created at:
+//	  ...
+//	==================
+//	Found 1 data race(s)
+const raceSeparator = "=================="
+
+var (
+	raceAccess  = regexp.MustCompile(`^(.+) at 0x[0-9a-f]+ by (.+):$`)
+	raceSummary = regexp.MustCompile(`^Found \d+ data race\(s\)$`)
+)
+
+// newFailure reads the runtime's report. timedOut is the timeout that stopped the example, or 0
+func newFailure(stderr []byte, exitErr *exec.ExitError, timedOut, elapsed time.Duration) *Failure {
+	f := &Failure{Kind: Exited, ExitCode: exitErr.ExitCode(), Elapsed: elapsed, Err: exitErr}
+
+	// Separate the reports from what the example itself wrote - race reports can come at any point
+	var output, report bytes.Buffer
+	var runtimeReport []string
+	var races [][]string
+	inRace, inRuntime := false, false
+	sc := bufio.NewScanner(bytes.NewReader(stderr))
+	sc.Buffer(nil, len(stderr)+1)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == raceSeparator:
+			if inRace = !inRace; inRace {
+				races = append(races, nil)
+			}
+		case inRace:
+			races[len(races)-1] = append(races[len(races)-1], line)
+		case inRuntime || reportStart.MatchString(line):
+			inRuntime = true
+			runtimeReport = append(runtimeReport, line)
+		case raceSummary.MatchString(line):
+		default:
+			output.WriteString(line + "\n")
+			continue
+		}
+		report.WriteString(line + "\n")
+	}
+	f.Output, f.Report = output.Bytes(), report.Bytes()
+
+	f.parseRuntimeReport(runtimeReport)
+	for _, r := range races {
+		f.Races = append(f.Races, parseRace(r))
+	}
+	switch {
+	case timedOut > 0:
+		// SIGQUIT makes a report too, but the message is just "SIGQUIT: quit"
+		f.Kind, f.Message = TimedOut, "no result within "+timedOut.String()
+	case f.Kind == Exited && len(f.Races) > 0:
+		f.Kind, f.Message = DataRace, fmt.Sprintf("%d data race(s)", len(f.Races))
+	case f.Kind == Exited:
+		f.Message = "exit status " + strconv.Itoa(f.ExitCode)
+	}
+	return f
+}
+
+func (f *Failure) parseRuntimeReport(lines []string) {
+	var cur *Goroutine
+	for _, line := range lines {
+		if m := goroutineHeader.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			state, _, _ := strings.Cut(m[2], ",")
+			f.Goroutines = append(f.Goroutines, Goroutine{ID: id, State: state})
+			cur = &f.Goroutines[len(f.Goroutines)-1]
+			continue
+		}
+		switch {
+		case f.Message == "" && strings.HasPrefix(line, "panic: "):
+			f.Kind, f.Message = Panicked, strings.TrimPrefix(line, "panic: ")
+		case f.Message == "" && strings.HasPrefix(line, "fatal error: "):
+			f.Kind, f.Message = FatalError, strings.TrimPrefix(line, "fatal error: ")
+			if strings.Contains(f.Message, "all goroutines are asleep") {
+				f.Kind = Deadlocked
+			}
+		case line == "":
+			cur = nil // the end of a traceback
+		case cur != nil && cur.Func == "" && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "created by "):
+			// a frame is a "pkg.Func(args)" line, then "\tfile:line +0xpc" - take the first one that isn't the runtime's
+			if fn, _, _ := strings.Cut(line, "("); !isRuntimeFrame(fn) {
+				cur.Func = fn
+			}
+		case cur != nil && cur.Func != "" && cur.File == "" && strings.HasPrefix(line, "\t"):
+			file, _, _ := strings.Cut(strings.TrimSpace(line), " +0x")
+			cur.File = file
+		}
+	}
+}
+
+// isRuntimeFrame reports whether fn is part of the runtime - ex. runtime.gopark, or the map code in internal/runtime/maps
+func isRuntimeFrame(fn string) bool {
+	return strings.HasPrefix(fn, "runtime.") || strings.HasPrefix(fn, "internal/runtime/")
+}
+
+// parseRace reads the accesses of one race report - the "created at" stacks after them are skipped.
+// Each access gets its innermost frame outside the runtime, or the innermost frame if every one is the
+// runtime's (the race detector doesn't always unwind past a map access)
+func parseRace(lines []string) Race {
+	var r Race
+	var cur *RaceAccess
+	var innermost [2]string // func, file:line
+	done := func() {
+		if cur != nil && cur.Func == "" {
+			cur.Func, cur.File = innermost[0], innermost[1]
+		}
+		cur, innermost = nil, [2]string{}
+	}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch m := raceAccess.FindStringSubmatch(line); {
+		case m != nil:
+			done()
+			r.Accesses = append(r.Accesses, RaceAccess{Op: m[1], Goroutine: m[2]})
+			cur = &r.Accesses[len(r.Accesses)-1]
+		case trimmed == "" || strings.HasPrefix(line, "Goroutine "):
+			done()
+		case cur == nil || cur.File != "":
+		case strings.HasPrefix(trimmed, "/"): // the file:line of the frame above
+			file, _, _ := strings.Cut(trimmed, " +0x")
+			if cur.Func != "" {
+				cur.File = file
+			} else if innermost[1] == "" {
+				innermost[1] = file
+			}
+		case cur.Func == "":
+			fn, _, _ := strings.Cut(trimmed, "(")
+			if !isRuntimeFrame(fn) && !strings.HasPrefix(fn, "sync/atomic.") {
+				cur.Func = fn
+			} else if innermost[0] == "" {
+				innermost[0] = fn
+			}
+		}
+	}
+	done()
+	return r
+}
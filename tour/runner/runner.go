@@ -0,0 +1,251 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"text/template"
+	"time"
+	"tour/notes"
+)
+
+// The examples are functions in package main of other modules, mostly commented out in their main().
+// Run executes one of them on its own, without editing the repository: `go build` of the example's
+// package with one extra file, added through go build's -overlay flag (a JSON file mapping paths
+// to replacement contents - here, a path that doesn't exist on disk to a file in a temp dir).
+//
+// The extra file has an init function that calls the example and then exits,
+// so the package's own main never runs. Package level variables are still initialized first, as usual.
+//
+// The binary is then run directly rather than through `go run`, so the timeout doesn't count
+// the compile, and a signal reaches the example itself instead of the go command.
+//
+// It is built with CGO_ENABLED=0. A binary that links cgo (on Linux, anything importing net does,
+// for the system DNS resolver) never reports "all goroutines are asleep" - the runtime can't tell
+// whether C code might still wake a goroutine - so a deadlock example would hang instead.
+// The modules with deadlock examples (concurrency) don't import net, so for them this is only a guard.
+
+// overlayFile is the name the extra file gets in the example's package
+const overlayFile = "zz_tour_run.go"
+
+// Config says which example to run and what to record while it runs
+type Config struct {
+	Root    string        // repository root; Example.Dir is relative to it
+	Example notes.Example // the function to run
+
+	// MutexProfile and BlockProfile, if set, are paths the runtime's mutex and block profiles are
+	// written to when the example returns, in the text format (pprof debug=1).
+	// Setting either turns on recording of every event (SetMutexProfileFraction(1), SetBlockProfileRate(1))
+	MutexProfile string
+	BlockProfile string
+
+	// CPUProfile and MemProfile, if set, are paths for a CPU profile of the whole example and a heap
+	// profile taken when it returns, in pprof's binary format - for go tool pprof (see tour run -profile)
+	CPUProfile string
+	MemProfile string
+
+	// PprofAddr, if set, is an address (ex. localhost:6060) the example serves net/http/pprof's pages
+	// on while it runs - profiles of a long running example taken from outside, at any moment
+	PprofAddr string
+
+	// Race builds the example with the race detector. It needs cgo, so Race turns cgo back on -
+	// and with it the runtime's deadlock detection off (see above)
+	Race bool
+
+	// Timeout, if set, stops an example still running after this long. It gets SIGQUIT, which makes
+	// the runtime print every goroutine's stack before exiting - so a hang shows where it was stuck
+	Timeout time.Duration
+
+	Env            []string // added to the environment, ex. GOMAXPROCS=4
+	Stdout, Stderr io.Writer
+}
+
+// The identifiers are prefixed so they can't collide with anything the example's package declares
+// (imports are file scoped, but still clash with package level names)
+var runTemplate = template.Must(template.New("run").Parse(`// Code generated by tour/runner. DO NOT EDIT.
+
+package main
+
+import (
+{{- if .PprofAddr}}
+	tourrunhttp "net/http"
+	_ "net/http/pprof"
+{{- end}}
+	tourrunos "os"
+	tourrunruntime "runtime"
+	tourrunpprof "runtime/pprof"
+)
+
+func init() {
+	tourrunruntime.SetMutexProfileFraction({{if .MutexProfile}}1{{else}}0{{end}})
+	tourrunruntime.SetBlockProfileRate({{if .BlockProfile}}1{{else}}0{{end}})
+{{- if .PprofAddr}}
+
+	// net/http/pprof registers its handlers on http.DefaultServeMux when imported
+	go func() {
+		err := tourrunhttp.ListenAndServe({{printf "%q" .PprofAddr}}, nil)
+		tourrunos.Stderr.WriteString("tour runner: pprof: " + err.Error() + "\n")
+	}()
+{{- end}}
+{{- if .MemProfile}}
+
+	// one sample per 4 KB allocated, rather than 512 KB: small examples allocate too little to show otherwise
+	tourrunruntime.MemProfileRate = 4096
+{{- end}}
+{{- if .CPUProfile}}
+
+	cpu := tourrunCreate({{printf "%q" .CPUProfile}})
+	if err := tourrunpprof.StartCPUProfile(cpu); err != nil {
+		tourrunFail(err)
+	}
+{{- end}}
+
+	{{.Example.Name}}()
+{{- if .CPUProfile}}
+
+	tourrunpprof.StopCPUProfile()
+	cpu.Close()
+{{- end}}
+
+	tourrunWriteProfile("mutex", {{printf "%q" .MutexProfile}}, 1)
+	tourrunWriteProfile("block", {{printf "%q" .BlockProfile}}, 1)
+{{- if .MemProfile}}
+	tourrunruntime.GC() // so the profile's "in use" numbers are what's still live, not garbage
+{{- end}}
+	tourrunWriteProfile("heap", {{printf "%q" .MemProfile}}, 0)
+	tourrunos.Exit(0)
+}
+
+func tourrunCreate(path string) *tourrunos.File {
+	f, err := tourrunos.Create(path)
+	if err != nil {
+		tourrunFail(err)
+	}
+	return f
+}
+
+func tourrunFail(err error) {
+	tourrunos.Stderr.WriteString("tour runner: " + err.Error() + "\n")
+	tourrunos.Exit(1)
+}
+
+// tourrunWriteProfile writes a named profile to path: debug 1 is the text format, 0 pprof's binary one
+func tourrunWriteProfile(name, path string, debug int) {
+	if path == "" {
+		return
+	}
+	f := tourrunCreate(path)
+	defer f.Close()
+	tourrunpprof.Lookup(name).WriteTo(f, debug)
+}
+`))
+
+// Run builds and runs the example and waits for it. If the example doesn't finish normally
+// (a panic, a deadlock, the timeout, any non-zero exit) the error is a *Failure.
+// A failed build is returned as the compiler's output
+func Run(ctx context.Context, cfg Config) error {
+	pkgDir, err := filepath.Abs(filepath.Join(cfg.Root, cfg.Example.Dir))
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp("", "tour-run")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, overlayFile)
+	f, err := os.Create(src)
+	if err != nil {
+		return err
+	}
+	err = runTemplate.Execute(f, cfg)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", overlayFile, err)
+	}
+
+	overlay, err := json.Marshal(map[string]map[string]string{
+		"Replace": {filepath.Join(pkgDir, overlayFile): src},
+	})
+	if err != nil {
+		return err
+	}
+	overlayPath := filepath.Join(tmp, "overlay.json")
+	if err := os.WriteFile(overlayPath, overlay, 0o644); err != nil {
+		return err
+	}
+
+	bin := filepath.Join(tmp, "example")
+	buildArgs, cgo := []string{"build", "-overlay=" + overlayPath, "-o", bin}, "CGO_ENABLED=0"
+	if cfg.Race {
+		// -race needs cgo, so under it the deadlock examples hang rather than being reported: only the
+		// timeout stops them
+		buildArgs, cgo = append(buildArgs, "-race"), "CGO_ENABLED=1"
+	}
+	build := exec.CommandContext(ctx, "go", append(buildArgs, ".")...)
+	build.Dir = pkgDir
+	build.Env = append(os.Environ(), cgo)
+	if out, err := build.CombinedOutput(); err != nil {
+		return fmt.Errorf("building %s: %w\n%s", cfg.Example.Dir, err, out)
+	}
+
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if cfg.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+	}
+	defer cancel()
+
+	// The runtime's report is kept for the Failure as well as passed on (it's at the end, so not all of it)
+	stderr := &tailBuffer{max: 64 << 10}
+	cmd := exec.CommandContext(runCtx, bin)
+	cmd.Dir = pkgDir
+	cmd.Env = append(os.Environ(), cfg.Env...)
+	cmd.Stdout = cfg.Stdout
+	cmd.Stderr = stderr
+	if cfg.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cfg.Stderr, stderr)
+	}
+	cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGQUIT) }
+	cmd.WaitDelay = 5 * time.Second // then it's killed, if the stack dump didn't end it
+
+	start := time.Now()
+	err = cmd.Run()
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err // it didn't start, or its output couldn't be written
+	}
+	var timedOut time.Duration
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		timedOut = cfg.Timeout
+	}
+	return newFailure(stderr.Bytes(), exitErr, timedOut, time.Since(start))
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf.Write(p)
+	if extra := t.buf.Len() - t.max; extra > 0 {
+		t.buf.Next(extra)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) Bytes() []byte { return t.buf.Bytes() }
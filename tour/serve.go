@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+	"tour/notes"
+	"tour/runner"
+)
+
+// --- tour serve ---
+
+// The example registry over HTTP - a small playground for the notes, on the machine that has the repository:
+//
+//	GET  /examples                  the registered examples, with their Tour pages
+//	POST /examples/{name}/run       build and run one (see tour run), returning its output as JSON
+//
+// ex. curl -X POST 'localhost:8080/examples/mapExample/run?timeout=2s'
+//
+// It runs the repository's code on request, so it listens on localhost unless -addr says otherwise.
+// Runs take a slot each (-parallel), and a request waits for one - the builds are what's expensive.
+
+// maxRunOutput is how much of an example's stdout and stderr a response carries
+const maxRunOutput = 1 << 20
+
+type exampleJSON struct {
+	Name   string   `json:"name"`
+	Dir    string   `json:"dir"`
+	Topics []string `json:"topics"`
+	Pages  string   `json:"pages"`
+}
+
+type runResponse struct {
+	Name      string       `json:"name"`
+	Dir       string       `json:"dir"`
+	OK        bool         `json:"ok"`
+	Stdout    string       `json:"stdout"`
+	Stderr    string       `json:"stderr"`
+	Truncated bool         `json:"truncated,omitempty"`
+	Elapsed   string       `json:"elapsed"` // build included
+	Failure   *failureJSON `json:"failure,omitempty"`
+}
+
+type failureJSON struct {
+	Kind        string `json:"kind"`
+	Message     string `json:"message"`
+	Explanation string `json:"explanation"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// cappedBuffer keeps the first max bytes written to it, and drops the rest - but always reports the whole
+// write as done, so the example isn't stopped by a write error
+type cappedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+type exampleServer struct {
+	root       string
+	maxTimeout time.Duration
+	slots      chan struct{} // a semaphore: one value per run in progress
+	log        *slog.Logger
+}
+
+func (s *exampleServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /examples", s.listExamples)
+	mux.HandleFunc("POST /examples/{name}/run", s.runExample)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func (s *exampleServer) listExamples(w http.ResponseWriter, r *http.Request) {
+	list := make([]exampleJSON, 0, len(notes.Examples))
+	for _, ex := range notes.Examples {
+		list = append(list, exampleJSON{ex.Name, ex.Dir, ex.Topics, pageTitles(ex.Topics)})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// runExample runs the example named in the path. ?timeout= asks for a shorter timeout than the server's
+// (never a longer one). A request that goes away - the client gave up - stops the run
+func (s *exampleServer) runExample(w http.ResponseWriter, r *http.Request) {
+	ex, err := lookupExample(r.PathValue("name"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorJSON{err.Error()})
+		return
+	}
+	timeout := s.maxTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil || d <= 0 {
+			writeJSON(w, http.StatusBadRequest, errorJSON{fmt.Sprintf("timeout %q: want a duration, ex. 2s", t)})
+			return
+		}
+		timeout = min(d, s.maxTimeout)
+	}
+	funcs, err := declaredFuncs(filepath.Join(s.root, ex.Dir))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorJSON{err.Error()})
+		return
+	}
+	if fn := funcs[ex.Name]; fn != nil && fn.Params.NumFields() > 0 {
+		writeJSON(w, http.StatusBadRequest, errorJSON{ex.Name + " takes arguments, so it can't be run on its own"})
+		return
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-r.Context().Done():
+		return
+	}
+
+	start := time.Now()
+	stdout, stderr := &cappedBuffer{max: maxRunOutput}, &cappedBuffer{max: maxRunOutput}
+	err = runner.Run(r.Context(), runner.Config{
+		Root:    s.root,
+		Example: ex,
+		Timeout: timeout,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	})
+	res := runResponse{Name: ex.Name, Dir: ex.Dir, OK: err == nil, Elapsed: time.Since(start).Round(time.Millisecond).String()}
+	var f *runner.Failure
+	switch {
+	case err == nil:
+	case errors.As(err, &f):
+		res.Failure = &failureJSON{Kind: f.Kind.String(), Message: f.Message, Explanation: explanations[f.Kind]}
+		// stderr has the runtime's report too - f.Output is the example's own part, as tour run prints it
+		stderr = &cappedBuffer{max: maxRunOutput}
+		stderr.Write(f.Output)
+	default:
+		s.log.Error("run", "example", ex.Name, "err", err)
+		writeJSON(w, http.StatusInternalServerError, errorJSON{err.Error()}) // couldn't build or start it
+		return
+	}
+	res.Stdout, res.Stderr = stdout.String(), stderr.String()
+	res.Truncated = stdout.truncated || stderr.truncated
+	s.log.Info("run", "example", ex.Name, "ok", res.OK, "elapsed", res.Elapsed)
+	// A failed example is still a successful request: the response says how it failed
+	writeJSON(w, http.StatusOK, res)
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	root := fs.String("root", "..", "repository root that example directories are relative to")
+	timeout := fs.Duration("timeout", 10*time.Second, "the longest an example may run (a request can ask for less)")
+	parallel := fs.Int("parallel", 2, "how many examples may run at once")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "usage: tour serve [flags]\n\n"+
+			"GET /examples lists the examples, POST /examples/{name}/run runs one and returns its output\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *parallel < 1 {
+		return errors.New("-parallel must be at least 1")
+	}
+	absRoot, err := filepath.Abs(*root)
+	if err != nil {
+		return err
+	}
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	s := &exampleServer{root: absRoot, maxTimeout: *timeout, slots: make(chan struct{}, *parallel), log: log}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+		// no WriteTimeout: a response is written once the run ends, up to -timeout plus the build later
+	}
+
+	// Ctrl-C stops accepting requests, and lets the runs in progress finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	log.Info("serving", "addr", "http://"+*addr, "examples", len(notes.Examples))
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	log.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *timeout+30*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"udp"
+)
+
+// === Ex. UDP: net.PacketConn, datagrams, read deadlines, messages that don't fit ===
+// (the checks are in udp_test.go)
+
+// All on 127.0.0.1, port 0. On loopback nothing is lost, so the retries never have to happen here -
+// over a real network they would.
+
+func main() {
+	timeEchoExample()
+	// readDeadlineExample()
+	// partialDatagramExample()
+}
+
+// start runs a time/echo server on a random local port
+func start() *udp.Server {
+	srv, err := udp.Listen("127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go srv.Serve()
+	return srv
+}
+
+func timeEchoExample() {
+	srv := start()
+	defer srv.Close()
+	addr := srv.Addr().String()
+	fmt.Println("listening on", srv.Addr(), "- a", srv.Addr().Network(), "address")
+
+	now, err := udp.Request(addr, "time", 100*time.Millisecond, 3)
+	fmt.Printf("time: %s, err %v\n", now, err)
+	echo, err := udp.Request(addr, "hello, gophers", 100*time.Millisecond, 3)
+	fmt.Printf("echo: %q, err %v\n", echo, err)
+
+	// One socket on the server, many clients: each Request is its own socket with its own port, and the
+	// server replies to whoever each datagram came from - no goroutine, no connection per client
+	// (udp_test.go checks 20 at once)
+	var wg sync.WaitGroup
+	replies := make([]string, 3)
+	for i := range replies {
+		wg.Go(func() {
+			replies[i], _ = udp.Request(addr, fmt.Sprint("client ", i), 100*time.Millisecond, 3)
+		})
+	}
+	wg.Wait()
+	fmt.Printf("3 clients at once: %q\n", replies)
+}
+
+func readDeadlineExample() {
+	// A socket that's bound but never answers: a server that's down, or a reply that got lost
+	silent, _ := net.ListenPacket("udp", "127.0.0.1:0")
+	defer silent.Close()
+	start := time.Now()
+	_, err := udp.Request(silent.LocalAddr().String(), "time", 50*time.Millisecond, 3)
+	fmt.Printf("a silent server: %v, after ~%v\n", err, time.Since(start).Round(50*time.Millisecond))
+	fmt.Println("is os.ErrDeadlineExceeded:", errors.Is(err, os.ErrDeadlineExceeded))
+
+	// There was no connection to fail - the datagrams did arrive, 3 of them: one per try
+	got := 0
+	buf := make([]byte, 64)
+	for {
+		silent.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+		if _, _, err := silent.ReadFrom(buf); err != nil {
+			break
+		}
+		got++
+	}
+	fmt.Println("datagrams the silent server got:", got)
+
+	// Nothing bound to the port at all: the host answers with an ICMP "port unreachable", which a
+	// Dial'ed UDP socket reports on its next Read - on loopback, at once
+	addr := silent.LocalAddr().String()
+	silent.Close()
+	_, err = udp.Request(addr, "time", time.Second, 3)
+	fmt.Println("a closed port:", err)
+}
+
+func partialDatagramExample() {
+	pc, _ := net.ListenPacket("udp", "127.0.0.1:0")
+	defer pc.Close()
+	conn, _ := net.Dial("udp", pc.LocalAddr().String())
+	defer conn.Close()
+
+	// Each Write is one datagram, and each ReadFrom gets one whole datagram - the boundaries stay,
+	// unlike TCP, where two writes can arrive as one read
+	conn.Write([]byte("first"))
+	conn.Write([]byte("second"))
+	buf := make([]byte, 64)
+	for range 2 {
+		n, from, _ := pc.ReadFrom(buf)
+		fmt.Printf("read %q from %v\n", buf[:n], from)
+	}
+
+	// A datagram bigger than the buffer: the start of it, no error, and the rest is gone - the next
+	// read is the next datagram, not the rest of this one
+	conn.Write([]byte("a datagram of 30 bytes, cut?.."))
+	conn.Write([]byte("next"))
+	small := make([]byte, 8)
+	n, _, err := pc.ReadFrom(small)
+	fmt.Printf("8-byte buffer: %q, err %v\n", small[:n], err)
+	n, _, _ = pc.ReadFrom(small)
+	fmt.Printf("the next read: %q\n", small[:n])
+
+	// So the server reads into MaxMessage+1 bytes: a message that fills it was too large
+	srv := start()
+	defer srv.Close()
+	reply, err := udp.Request(srv.Addr().String(), strings.Repeat("x", udp.MaxMessage+1), 100*time.Millisecond, 1)
+	fmt.Printf("%d bytes: %q, err %v\n", udp.MaxMessage+1, reply, err)
+	reply, _ = udp.Request(srv.Addr().String(), strings.Repeat("x", udp.MaxMessage), 100*time.Millisecond, 1)
+	fmt.Printf("%d bytes: %d back\n", udp.MaxMessage, len(reply))
+}
@@ -0,0 +1,103 @@
+package udp
+
+import (
+	"errors"
+	"net"
+	"os"
+	"time"
+)
+
+// A time/echo server over UDP. Unlike TCP (see the tcp notes) there are no connections:
+//
+// - net.ListenPacket returns a net.PacketConn - one socket, for every client. ReadFrom returns one whole
+//   datagram and the address it came from; the reply is WriteTo that address. There's no Accept, and no
+//   goroutine per client - nothing to keep open, nothing to shut down per client
+// - a datagram arrives whole or not at all, and may be lost, duplicated or reordered: nothing is resent.
+//   So a client sets a read deadline and tries again when no reply comes
+// - a datagram bigger than the read buffer is cut to fit, and the rest is thrown away - on Linux and
+//   macOS without any error. Reading into a buffer one byte bigger than the largest allowed message
+//   is how to tell a message that fits from one that was cut
+//
+// The protocol: "time" gets the server's time (RFC 3339), anything else is sent back
+
+// MaxMessage is the largest message the server accepts. Bigger ones get an error reply. 512 bytes fits
+// in one packet on any network, so it's never split into IP fragments
+const MaxMessage = 512
+
+// ReplyTooLarge is the reply to a message over MaxMessage
+const ReplyTooLarge = "error: message too large"
+
+// Server is a time/echo server
+type Server struct {
+	pc net.PacketConn
+}
+
+// Listen listens on addr (host:port; port 0 is any free one)
+func Listen(addr string) (*Server, error) {
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{pc: pc}, nil
+}
+
+// Addr is the address the server listens on
+func (s *Server) Addr() net.Addr { return s.pc.LocalAddr() }
+
+// Serve answers datagrams until Close. Each is answered before the next is read - a reply doesn't wait
+// on anything, so one goroutine is enough
+func (s *Server) Serve() error {
+	buf := make([]byte, MaxMessage+1)
+	for {
+		n, from, err := s.pc.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		// Other errors of a ReadFrom are per datagram; the socket's still good, so carry on
+		s.pc.WriteTo(s.reply(buf[:n]), from)
+	}
+}
+
+func (s *Server) reply(msg []byte) []byte {
+	switch {
+	case len(msg) > MaxMessage: // it filled the extra byte: it was cut
+		return []byte(ReplyTooLarge)
+	case string(msg) == "time":
+		return []byte(time.Now().UTC().Format(time.RFC3339Nano))
+	}
+	return msg
+}
+
+// Close stops the server: a blocked ReadFrom returns net.ErrClosed
+func (s *Server) Close() error { return s.pc.Close() }
+
+// Request sends msg to the server at addr and waits up to timeout for the reply, sending it again up to
+// tries times in all. A reply that doesn't come in time is an error matching os.ErrDeadlineExceeded
+func Request(addr, msg string, timeout time.Duration, tries int) (string, error) {
+	// Dial on "udp" sends no packets: it only fixes the address Write sends to, and makes Read drop
+	// datagrams from anyone else
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	buf := make([]byte, MaxMessage+1)
+	for range tries {
+		if _, err = conn.Write([]byte(msg)); err != nil {
+			return "", err
+		}
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		var n int
+		n, err = conn.Read(buf)
+		if err == nil {
+			return string(buf[:n]), nil
+		}
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			return "", err // ex. "connection refused": the server's host said no one listens on the port
+		}
+	}
+	return "", err
+}
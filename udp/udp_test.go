@@ -0,0 +1,205 @@
+package udp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// start runs a server on a random loopback port, closed and drained at the end of the test
+func start(t *testing.T) string {
+	t.Helper()
+	srv, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve() }()
+	t.Cleanup(func() {
+		srv.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve after Close = %v, want nil", err)
+		}
+	})
+	return srv.Addr().String()
+}
+
+func TestTime(t *testing.T) {
+	addr := start(t)
+	before := time.Now()
+	reply, err := Request(addr, "time", time.Second, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := time.Parse(time.RFC3339Nano, reply)
+	if err != nil {
+		t.Fatalf("reply %q: %v", reply, err)
+	}
+	if got.Before(before.Add(-time.Second)) || got.After(time.Now().Add(time.Second)) {
+		t.Errorf("time %v, want about %v", got, before)
+	}
+}
+
+func TestEcho(t *testing.T) {
+	addr := start(t)
+	for _, msg := range []string{"hello, gophers", "x", "Time", strings.Repeat("x", MaxMessage)} {
+		reply, err := Request(addr, msg, time.Second, 3)
+		if err != nil || reply != msg {
+			t.Errorf("Request(%.20q) = %.20q, %v, want it back", msg, reply, err)
+		}
+	}
+}
+
+func TestTooLarge(t *testing.T) {
+	addr := start(t)
+	for _, n := range []int{MaxMessage + 1, 4 * MaxMessage} {
+		reply, err := Request(addr, strings.Repeat("x", n), time.Second, 1)
+		if err != nil || reply != ReplyTooLarge {
+			t.Errorf("%d bytes: %.20q, %v, want %q", n, reply, err, ReplyTooLarge)
+		}
+	}
+	// The server is still answering afterwards
+	if reply, err := Request(addr, "after", time.Second, 1); err != nil || reply != "after" {
+		t.Errorf("after a large message: %q, %v", reply, err)
+	}
+}
+
+func TestManyClients(t *testing.T) {
+	addr := start(t)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			msg := fmt.Sprint("client ", i)
+			if reply, err := Request(addr, msg, time.Second, 3); err != nil || reply != msg {
+				t.Errorf("%s: %q, %v", msg, reply, err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestSilentServer(t *testing.T) {
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	const timeout, tries = 20 * time.Millisecond, 3
+	start := time.Now()
+	_, err = Request(silent.LocalAddr().String(), "time", timeout, tries)
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("err = %v, want os.ErrDeadlineExceeded", err)
+	}
+	if took := time.Since(start); took < tries*timeout {
+		t.Errorf("gave up after %v, want at least %v", took, tries*timeout)
+	}
+
+	// One datagram per try
+	got := 0
+	buf := make([]byte, 64)
+	for {
+		silent.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		n, _, err := silent.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		if string(buf[:n]) != "time" {
+			t.Errorf("datagram %q, want \"time\"", buf[:n])
+		}
+		got++
+	}
+	if got != tries {
+		t.Errorf("got %d datagrams, want %d", got, tries)
+	}
+}
+
+func TestClosedPort(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := pc.LocalAddr().String()
+	pc.Close()
+	// On Linux loopback the ICMP "port unreachable" comes back as an error on Read; elsewhere it may
+	// only time out. Either way Request must fail, and not hang past its tries
+	start := time.Now()
+	if _, err := Request(addr, "time", 50*time.Millisecond, 2); err == nil {
+		t.Error("Request to a closed port succeeded")
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("took %v", took)
+	}
+}
+
+func TestDatagramBoundaries(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	conn, err := net.Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	pc.SetReadDeadline(time.Now().Add(time.Second))
+
+	// Two writes are two reads
+	conn.Write([]byte("first"))
+	conn.Write([]byte("second"))
+	buf := make([]byte, 64)
+	for _, want := range []string{"first", "second"} {
+		n, from, err := pc.ReadFrom(buf)
+		if err != nil || string(buf[:n]) != want {
+			t.Errorf("ReadFrom = %q, %v, want %q", buf[:n], err, want)
+		}
+		if from.String() != conn.LocalAddr().String() {
+			t.Errorf("from %v, want %v", from, conn.LocalAddr())
+		}
+	}
+
+	// A datagram bigger than the buffer is cut, and the rest is gone
+	conn.Write([]byte("a datagram of 30 bytes, cut?.."))
+	conn.Write([]byte("next"))
+	small := make([]byte, 8)
+	n, _, err := pc.ReadFrom(small)
+	if err != nil || string(small[:n]) != "a datagr" {
+		t.Errorf("cut read = %q, %v, want \"a datagr\"", small[:n], err)
+	}
+	n, _, err = pc.ReadFrom(small)
+	if err != nil || string(small[:n]) != "next" {
+		t.Errorf("next read = %q, %v, want \"next\"", small[:n], err)
+	}
+}
+
+func TestCloseStopsServe(t *testing.T) {
+	srv, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve() }()
+	if _, err := Request(srv.Addr().String(), "up", time.Second, 3); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Serve still running after Close")
+	}
+}
+
+func TestListenBadAddr(t *testing.T) {
+	if _, err := Listen("not-an-address"); err == nil {
+		t.Error("Listen(\"not-an-address\") succeeded")
+	}
+}